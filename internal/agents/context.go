@@ -10,6 +10,8 @@ import (
 
 	"github.com/imyousuf/CodeEagle/internal/gitutil"
 	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+	"github.com/imyousuf/CodeEagle/internal/testresults"
 	"github.com/imyousuf/CodeEagle/internal/vectorstore"
 )
 
@@ -128,7 +130,13 @@ func (cb *ContextBuilder) BuildFileContext(ctx context.Context, filePath string)
 				if e.SourceID == n.ID {
 					target, err := cb.store.GetNode(ctx, e.TargetID)
 					if err == nil {
-						deps[target.QualifiedName] = struct{}{}
+						// Imported packages are shared Dependency
+						// nodes, named but not qualified.
+						name := target.QualifiedName
+						if name == "" {
+							name = target.Name
+						}
+						deps[name] = struct{}{}
 					}
 				}
 				if e.TargetID == n.ID {
@@ -183,6 +191,7 @@ func (cb *ContextBuilder) BuildServiceContext(ctx context.Context, serviceName s
 	files := make(map[string]struct{})
 	var endpoints []*graph.Node
 	depCount := 0
+	stores := make(map[string]string) // ExternalStore ID -> operations
 	for _, n := range nodes {
 		byType[n.Type] = append(byType[n.Type], n)
 		if n.FilePath != "" {
@@ -200,6 +209,15 @@ func (cb *ContextBuilder) BuildServiceContext(ctx context.Context, serviceName s
 				}
 			}
 		}
+		// Collect the caches and object stores it uses.
+		uses, err := cb.store.GetEdges(ctx, n.ID, graph.EdgeUses)
+		if err == nil {
+			for _, e := range uses {
+				if e.SourceID == n.ID {
+					stores[e.TargetID] = parser.MergeStoreOperations(stores[e.TargetID], e.Properties["operations"])
+				}
+			}
+		}
 	}
 
 	fmt.Fprintf(&b, "Files: %d\n", len(files))
@@ -219,6 +237,22 @@ func (cb *ContextBuilder) BuildServiceContext(ctx context.Context, serviceName s
 		}
 	}
 
+	if len(stores) > 0 {
+		b.WriteString("\n### External Stores\n")
+		ids := make([]string, 0, len(stores))
+		for id := range stores {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			st, err := cb.store.GetNode(ctx, id)
+			if err != nil || st == nil {
+				continue
+			}
+			fmt.Fprintf(&b, "- %s %s (%s)\n", st.Properties["kind"], st.Name, stores[id])
+		}
+	}
+
 	// Key types (structs and interfaces).
 	keyTypes := append(byType[graph.NodeStruct], byType[graph.NodeInterface]...)
 	if len(keyTypes) > 0 {
@@ -231,6 +265,58 @@ func (cb *ContextBuilder) BuildServiceContext(ctx context.Context, serviceName s
 	return b.String(), nil
 }
 
+// ImpactEdgeTypes are the edges followed backwards from a changed node to
+// find what it affects.
+var ImpactEdgeTypes = []graph.EdgeType{
+	graph.EdgeImports,
+	graph.EdgeDependsOn,
+	graph.EdgeCalls,
+	graph.EdgeImplements,
+	graph.EdgeExtends,
+	graph.EdgeTests,
+	graph.EdgeCovers,
+	graph.EdgeReads,
+	graph.EdgeRenders,
+	graph.EdgeThrows,
+}
+
+// ImpactLevels performs a BFS from the given node over incoming
+// ImpactEdgeTypes edges, up to depth levels, and returns the nodes that
+// would be affected if it changed: levels[0] depends on it directly,
+// levels[1] through one intermediate node, and so on.
+func (cb *ContextBuilder) ImpactLevels(ctx context.Context, nodeID string, depth int) ([][]*graph.Node, error) {
+	visited := map[string]struct{}{nodeID: {}}
+	frontier := []string{nodeID}
+	var levels [][]*graph.Node
+	for len(frontier) > 0 && len(levels) < depth {
+		var level []*graph.Node
+		var next []string
+		for _, id := range frontier {
+			for _, et := range ImpactEdgeTypes {
+				// Nodes that depend on the current node (incoming edges).
+				neighbors, err := cb.store.GetNeighbors(ctx, id, et, graph.Incoming)
+				if err != nil {
+					continue
+				}
+				for _, n := range neighbors {
+					if _, seen := visited[n.ID]; seen {
+						continue
+					}
+					visited[n.ID] = struct{}{}
+					level = append(level, n)
+					next = append(next, n.ID)
+				}
+			}
+		}
+		if len(level) == 0 {
+			break
+		}
+		levels = append(levels, level)
+		frontier = next
+	}
+	return levels, nil
+}
+
 // BuildImpactContext performs a BFS traversal from the given node, following
 // dependency-related edges up to 3 levels deep. It returns a formatted list
 // of affected nodes grouped by depth of impact.
@@ -243,50 +329,13 @@ func (cb *ContextBuilder) BuildImpactContext(ctx context.Context, nodeID string)
 	var b strings.Builder
 	fmt.Fprintf(&b, "## Impact Analysis: %s (%s)\n\n", root.Name, root.Type)
 
-	// BFS up to 3 levels. We follow incoming edges for Imports, DependsOn, Calls,
-	// Implements because we want to find "what depends on this node" (who would
-	// be affected if this node changes).
-	impactEdges := []graph.EdgeType{
-		graph.EdgeImports,
-		graph.EdgeDependsOn,
-		graph.EdgeCalls,
-		graph.EdgeImplements,
-		graph.EdgeTests,
-	}
-
-	type levelEntry struct {
-		node  *graph.Node
-		level int
+	impact, err := cb.ImpactLevels(ctx, nodeID, 3)
+	if err != nil {
+		return "", err
 	}
-
-	visited := map[string]struct{}{nodeID: {}}
-	queue := []levelEntry{{node: root, level: 0}}
 	levels := make(map[int][]*graph.Node) // level -> nodes at that level
-
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-
-		if current.level >= 3 {
-			continue
-		}
-
-		for _, et := range impactEdges {
-			// Get nodes that depend on the current node (incoming edges).
-			neighbors, err := cb.store.GetNeighbors(ctx, current.node.ID, et, graph.Incoming)
-			if err != nil {
-				continue
-			}
-			for _, n := range neighbors {
-				if _, seen := visited[n.ID]; seen {
-					continue
-				}
-				visited[n.ID] = struct{}{}
-				nextLevel := current.level + 1
-				levels[nextLevel] = append(levels[nextLevel], n)
-				queue = append(queue, levelEntry{node: n, level: nextLevel})
-			}
-		}
+	for i, nodes := range impact {
+		levels[i+1] = nodes
 	}
 
 	if len(levels) == 0 {
@@ -294,6 +343,7 @@ func (cb *ContextBuilder) BuildImpactContext(ctx context.Context, nodeID string)
 		return b.String(), nil
 	}
 
+	var tests []*graph.Node
 	levelLabels := map[int]string{
 		1: "Direct",
 		2: "Indirect",
@@ -311,6 +361,23 @@ func (cb *ContextBuilder) BuildImpactContext(ctx context.Context, nodeID string)
 				loc = fmt.Sprintf(" in %s", n.FilePath)
 			}
 			fmt.Fprintf(&b, "- [%s] %s%s\n", n.Type, n.Name, loc)
+			if n.Type == graph.NodeTestFunction {
+				tests = append(tests, n)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Order affected tests so the fastest, most reliable ones run first.
+	if len(tests) > 0 {
+		testresults.SortForPlan(tests)
+		b.WriteString("### Suggested test plan\n")
+		for _, n := range tests {
+			history := "no recorded runs"
+			if st, ok := testresults.StatsFromNode(n); ok {
+				history = st.String()
+			}
+			fmt.Fprintf(&b, "- %s in %s (%s)\n", n.Name, n.FilePath, history)
 		}
 		b.WriteString("\n")
 	}
@@ -373,7 +440,7 @@ func (cb *ContextBuilder) BuildDiffContext(ctx context.Context, changedFiles []s
 		// Find what depends on these symbols.
 		affected := make(map[string]string) // filePath -> node name for dedup
 		for _, n := range nodes {
-			for _, et := range []graph.EdgeType{graph.EdgeImports, graph.EdgeDependsOn, graph.EdgeCalls, graph.EdgeImplements, graph.EdgeTests} {
+			for _, et := range []graph.EdgeType{graph.EdgeImports, graph.EdgeDependsOn, graph.EdgeCalls, graph.EdgeImplements, graph.EdgeTests, graph.EdgeCovers} {
 				neighbors, err := cb.store.GetNeighbors(ctx, n.ID, et, graph.Incoming)
 				if err != nil {
 					continue
@@ -214,10 +214,23 @@ func TestBuildServiceContext(t *testing.T) {
 	cb := NewContextBuilder(store)
 	ctx := context.Background()
 
+	cacheID := graph.NewNodeID(string(graph.NodeExternalStore), "", "redis:redis")
+	if err := store.AddNode(ctx, &graph.Node{ID: cacheID, Type: graph.NodeExternalStore, Name: "redis", Properties: map[string]string{"kind": "redis"}}); err != nil {
+		t.Fatal(err)
+	}
+	handleReqID := graph.NewNodeID("Function", "src/handler.go", "HandleRequest")
+	if err := store.AddEdge(ctx, &graph.Edge{ID: "uses-cache", Type: graph.EdgeUses, SourceID: handleReqID, TargetID: cacheID,
+		Properties: map[string]string{"operations": "read,write"}}); err != nil {
+		t.Fatal(err)
+	}
+
 	result, err := cb.BuildServiceContext(ctx, "handler")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+	if !strings.Contains(result, "- redis redis (read,write)") {
+		t.Errorf("missing external store in service context:\n%s", result)
+	}
 	if !strings.Contains(result, "## Service: handler") {
 		t.Error("missing service header")
 	}
@@ -254,6 +267,46 @@ func TestBuildImpactContext(t *testing.T) {
 	}
 }
 
+func TestBuildImpactContextTestPlan(t *testing.T) {
+	store := setupTestGraph(t)
+	defer store.Close()
+
+	cb := NewContextBuilder(store)
+	ctx := context.Background()
+	handleReqID := graph.NewNodeID("Function", "src/handler.go", "HandleRequest")
+
+	tests := []*graph.Node{
+		{ID: "test-slow", Type: graph.NodeTestFunction, Name: "TestHandleSlow", FilePath: "src/handler_test.go",
+			Properties: map[string]string{"test_runs": "4", "test_passes": "4", "test_total_ms": "8000"}},
+		{ID: "test-fast", Type: graph.NodeTestFunction, Name: "TestHandleFast", FilePath: "src/handler_test.go",
+			Properties: map[string]string{"test_runs": "4", "test_passes": "4", "test_total_ms": "40"}},
+		{ID: "test-new", Type: graph.NodeTestFunction, Name: "TestHandleNew", FilePath: "src/handler_test.go"},
+	}
+	for _, n := range tests {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("add node: %v", err)
+		}
+		if err := store.AddEdge(ctx, &graph.Edge{
+			ID: graph.NewNodeID("Tests", n.ID, handleReqID), Type: graph.EdgeTests, SourceID: n.ID, TargetID: handleReqID,
+		}); err != nil {
+			t.Fatalf("add edge: %v", err)
+		}
+	}
+
+	result, err := cb.BuildImpactContext(ctx, handleReqID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan := result[strings.Index(result, "### Suggested test plan"):]
+	fast, slow, untested := strings.Index(plan, "TestHandleFast"), strings.Index(plan, "TestHandleSlow"), strings.Index(plan, "TestHandleNew")
+	if fast < 0 || !(fast < slow && slow < untested) {
+		t.Errorf("test plan not ordered fast, slow, untested:\n%s", plan)
+	}
+	if !strings.Contains(plan, "pass rate 100% over 4 run(s), avg 10ms") {
+		t.Errorf("missing run history in test plan:\n%s", plan)
+	}
+}
+
 func TestBuildDiffContext(t *testing.T) {
 	store := setupTestGraph(t)
 	defer store.Close()
@@ -196,6 +196,10 @@ func (t *queryNodeEdgesTool) Parameters() map[string]any {
 				"type":        "string",
 				"description": "Optional: filter by direction ('in', 'out', or 'both'). Default is 'both'.",
 			},
+			"min_confidence": map[string]any{
+				"type":        "number",
+				"description": "Optional: hide linker-inferred edges whose confidence score (0-1) is below this. Exact links score 1, heuristic matches 0.4-0.8, LLM inferences 0.3-0.7.",
+			},
 		},
 		"required": []string{"node"},
 	}
@@ -236,6 +240,8 @@ func (t *queryNodeEdgesTool) Execute(ctx context.Context, args map[string]any) (
 	if err != nil {
 		return fmt.Sprintf("Error getting edges: %v", err), false
 	}
+	minConfidence, _ := args["min_confidence"].(float64)
+	edges = graph.FilterEdgesByConfidence(edges, minConfidence)
 
 	if len(edges) == 0 {
 		b.WriteString("\nNo edges found.\n")
@@ -283,6 +283,45 @@ func TestQueryNodeEdgesToolDirectionFilter(t *testing.T) {
 	}
 }
 
+func TestQueryNodeEdgesToolMinConfidence(t *testing.T) {
+	store, cleanup := setupQueryToolTestStore(t)
+	defer cleanup()
+
+	// A weak heuristic link alongside the exact Implements edge.
+	if err := store.AddEdge(context.Background(), &graph.Edge{
+		ID:       "edge_doc1",
+		Type:     graph.EdgeDocuments,
+		SourceID: "struct1",
+		TargetID: "iface1",
+		Properties: map[string]string{
+			graph.PropConfidence:      graph.ConfidenceHeuristic,
+			graph.PropConfidenceScore: "0.40",
+		},
+	}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+
+	tool := &queryNodeEdgesTool{store: store}
+	result, ok := tool.Execute(context.Background(), map[string]any{"node": "EmbeddedStore"})
+	if !ok || !strings.Contains(result, "Documents") {
+		t.Fatalf("expected the Documents edge without a filter, got %q", result)
+	}
+
+	result, ok = tool.Execute(context.Background(), map[string]any{
+		"node":           "EmbeddedStore",
+		"min_confidence": 0.5,
+	})
+	if !ok {
+		t.Fatalf("expected success, got error: %s", result)
+	}
+	if strings.Contains(result, "Documents") {
+		t.Errorf("expected the low-confidence Documents edge to be filtered, got %q", result)
+	}
+	if !strings.Contains(result, "Implements") {
+		t.Errorf("expected the exact Implements edge to remain, got %q", result)
+	}
+}
+
 func TestQueryNodeEdgesToolEdgeTypeFilter(t *testing.T) {
 	store, cleanup := setupQueryToolTestStore(t)
 	defer cleanup()
@@ -0,0 +1,549 @@
+// Package backstage maps the knowledge graph onto Backstage software
+// catalog entities, so the catalog and catalog-graph plugins of a developer
+// portal can show CodeEagle's services, their APIs and the stores they use.
+//
+// Services become Components, the endpoints of each service an API, and
+// external stores Resources. Relations follow the Backstage well-known
+// relation types, in both directions.
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// APIVersion is the apiVersion of the entities.
+const APIVersion = "backstage.io/v1alpha1"
+
+// DefaultNamespace is the Backstage namespace of the entities.
+const DefaultNamespace = "default"
+
+// Entity kinds.
+const (
+	KindComponent = "Component"
+	KindAPI       = "API"
+	KindResource  = "Resource"
+)
+
+// Well-known relation types.
+const (
+	RelationOwnedBy       = "ownedBy"
+	RelationDependsOn     = "dependsOn"
+	RelationDependencyOf  = "dependencyOf"
+	RelationProvidesAPI   = "providesApi"
+	RelationAPIProvidedBy = "apiProvidedBy"
+	RelationConsumesAPI   = "consumesApi"
+	RelationAPIConsumedBy = "apiConsumedBy"
+)
+
+const (
+	annotationNodeID       = "codeeagle.io/node-id"
+	annotationServiceKind  = "codeeagle.io/service-kind"
+	annotationRepository   = "codeeagle.io/repo"
+	defaultLifecycle       = "production"
+	unknownOwner           = "group:default/unknown"
+	maxEntityNameLength    = 63
+	openAPIVersion         = "3.0.3"
+	openAPIDocumentVersion = "1.0.0"
+)
+
+// Entity is a catalog entity as the Backstage catalog API returns it.
+type Entity struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Metadata   Metadata       `json:"metadata"`
+	Spec       map[string]any `json:"spec"`
+	Relations  []Relation     `json:"relations"`
+}
+
+// Metadata is the metadata block of an entity.
+type Metadata struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Relation is a directed relation from an entity to the entity targetRef
+// names.
+type Relation struct {
+	Type      string `json:"type"`
+	TargetRef string `json:"targetRef"`
+}
+
+// Ref returns the entity reference of e, e.g. "component:default/orders".
+func (e *Entity) Ref() string {
+	return Ref(e.Kind, e.Metadata.Namespace, e.Metadata.Name)
+}
+
+// Ref returns the entity reference of an entity kind, namespace and name.
+func Ref(kind, namespace, name string) string {
+	return strings.ToLower(kind) + ":" + namespace + "/" + name
+}
+
+// Options configures how entities are built.
+type Options struct {
+	// Owners returns the CODEOWNERS owners of a path ("@org/team",
+	// "@user" or an email); nil leaves entities owned by
+	// group:default/unknown.
+	Owners func(path string) []string
+}
+
+// invalidNameChars are the characters Backstage does not allow in entity
+// names.
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// EntityName turns s into a valid entity name: runs of other characters
+// become "-", and the name is trimmed to 63 characters.
+func EntityName(s string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(s, "-"), "-_.")
+	if len(name) > maxEntityNameLength {
+		name = strings.TrimRight(name[:maxEntityNameLength], "-_.")
+	}
+	if name == "" {
+		name = "unnamed"
+	}
+	return name
+}
+
+// OwnerRef maps a CODEOWNERS owner to an entity reference: "@org/team"
+// to group:default/team, "@user" and "user@example.com" to
+// user:default/user.
+func OwnerRef(owner string) string {
+	owner = strings.TrimPrefix(owner, "@")
+	if _, team, ok := strings.Cut(owner, "/"); ok {
+		return Ref("group", DefaultNamespace, EntityName(team))
+	}
+	if local, _, ok := strings.Cut(owner, "@"); ok {
+		owner = local
+	}
+	return Ref("user", DefaultNamespace, EntityName(owner))
+}
+
+// builder accumulates entities and their relations.
+type builder struct {
+	entities map[string]*Entity // ref -> entity
+}
+
+func (b *builder) add(e *Entity) *Entity {
+	if existing, ok := b.entities[e.Ref()]; ok {
+		return existing
+	}
+	b.entities[e.Ref()] = e
+	return e
+}
+
+// relate adds the relation typ from the entity ref names to target, and
+// reverse back, once each.
+func (b *builder) relate(from, typ, reverse, to string) {
+	if e := b.entities[from]; e != nil {
+		e.Relations = appendRelation(e.Relations, Relation{Type: typ, TargetRef: to})
+	}
+	if e := b.entities[to]; e != nil && reverse != "" {
+		e.Relations = appendRelation(e.Relations, Relation{Type: reverse, TargetRef: from})
+	}
+}
+
+func appendRelation(list []Relation, r Relation) []Relation {
+	for _, existing := range list {
+		if existing == r {
+			return list
+		}
+	}
+	return append(list, r)
+}
+
+// Entities builds the catalog entities of the graph in store, sorted by
+// kind and name.
+func Entities(ctx context.Context, store graph.Store, opts Options) ([]Entity, error) {
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query services: %w", err)
+	}
+	b := &builder{entities: make(map[string]*Entity)}
+
+	componentOf := make(map[string]string) // service group -> component ref
+	serviceByID := make(map[string]string) // service node ID -> component ref
+	for _, svc := range services {
+		group := svc.Name
+		if svc.FilePath != "" {
+			group = linker.ServiceGroup(svc.FilePath)
+		}
+		if _, ok := componentOf[group]; ok {
+			continue
+		}
+		owner := ownerOf(opts, group)
+		component := b.add(&Entity{
+			APIVersion: APIVersion,
+			Kind:       KindComponent,
+			Metadata: Metadata{
+				Name:        EntityName(svc.Name),
+				Namespace:   DefaultNamespace,
+				Title:       svc.Name,
+				Description: svc.DocComment,
+				Annotations: annotations(svc),
+			},
+			Spec: map[string]any{
+				"type":      componentType(svc.Properties["kind"]),
+				"lifecycle": defaultLifecycle,
+				"owner":     owner,
+			},
+		})
+		componentOf[group] = component.Ref()
+		serviceByID[svc.ID] = component.Ref()
+		b.relate(component.Ref(), RelationOwnedBy, "", owner)
+	}
+
+	if err := b.addAPIs(ctx, store, componentOf); err != nil {
+		return nil, err
+	}
+
+	for _, svc := range services {
+		from, ok := serviceByID[svc.ID]
+		if !ok {
+			continue
+		}
+		deps, err := store.GetEdges(ctx, svc.ID, graph.EdgeDependsOn)
+		if err != nil {
+			return nil, fmt.Errorf("get dependencies of %s: %w", svc.Name, err)
+		}
+		for _, e := range deps {
+			if to, ok := serviceByID[e.TargetID]; ok && e.SourceID == svc.ID && to != from {
+				b.relate(from, RelationDependsOn, RelationDependencyOf, to)
+			}
+		}
+
+		uses, err := store.GetEdges(ctx, svc.ID, graph.EdgeUses)
+		if err != nil {
+			return nil, fmt.Errorf("get stores of %s: %w", svc.Name, err)
+		}
+		for _, e := range uses {
+			if e.SourceID != svc.ID {
+				continue
+			}
+			st, err := store.GetNode(ctx, e.TargetID)
+			if err != nil || st == nil || st.Type != graph.NodeExternalStore {
+				continue
+			}
+			resource := b.add(&Entity{
+				APIVersion: APIVersion,
+				Kind:       KindResource,
+				Metadata: Metadata{
+					Name:        EntityName(storeName(st)),
+					Namespace:   DefaultNamespace,
+					Title:       st.Name,
+					Annotations: annotations(st),
+				},
+				Spec: map[string]any{"type": st.Properties["kind"], "owner": unknownOwner},
+			})
+			b.relate(resource.Ref(), RelationOwnedBy, "", unknownOwner)
+			b.relate(from, RelationDependsOn, RelationDependencyOf, resource.Ref())
+		}
+	}
+
+	entities := make([]Entity, 0, len(b.entities))
+	for _, e := range b.entities {
+		fillSpecRelations(e)
+		entities = append(entities, *e)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		if entities[i].Kind != entities[j].Kind {
+			return entities[i].Kind < entities[j].Kind
+		}
+		return entities[i].Metadata.Name < entities[j].Metadata.Name
+	})
+	return entities, nil
+}
+
+// addAPIs adds an API for the endpoints of each service, provided by the
+// service's Component and consumed by the Components whose API calls the
+// linker resolved to those endpoints.
+func (b *builder) addAPIs(ctx context.Context, store graph.Store, componentOf map[string]string) error {
+	endpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return fmt.Errorf("query endpoints: %w", err)
+	}
+	byGroup := make(map[string][]*graph.Node)
+	for _, ep := range endpoints {
+		group := linker.ServiceGroup(ep.FilePath)
+		if _, ok := componentOf[group]; ok {
+			byGroup[group] = append(byGroup[group], ep)
+		}
+	}
+
+	for group, eps := range byGroup {
+		component := b.entities[componentOf[group]]
+		typ, definition, err := apiDefinition(component.Metadata.Title, eps)
+		if err != nil {
+			return err
+		}
+		api := b.add(&Entity{
+			APIVersion: APIVersion,
+			Kind:       KindAPI,
+			Metadata: Metadata{
+				Name:        component.Metadata.Name,
+				Namespace:   DefaultNamespace,
+				Title:       component.Metadata.Title + " API",
+				Description: fmt.Sprintf("%d endpoints of %s", len(eps), component.Metadata.Title),
+			},
+			Spec: map[string]any{
+				"type":       typ,
+				"lifecycle":  defaultLifecycle,
+				"owner":      component.Spec["owner"],
+				"definition": definition,
+			},
+		})
+		b.relate(api.Ref(), RelationOwnedBy, "", component.Spec["owner"].(string))
+		b.relate(component.Ref(), RelationProvidesAPI, RelationAPIProvidedBy, api.Ref())
+
+		for _, ep := range eps {
+			consumers, err := store.GetEdges(ctx, ep.ID, graph.EdgeConsumes)
+			if err != nil {
+				return fmt.Errorf("get consumers of %s: %w", ep.Name, err)
+			}
+			for _, e := range consumers {
+				if e.TargetID != ep.ID {
+					continue
+				}
+				call, err := store.GetNode(ctx, e.SourceID)
+				if err != nil || call == nil {
+					continue
+				}
+				consumer, ok := componentOf[linker.ServiceGroup(call.FilePath)]
+				if ok && consumer != component.Ref() {
+					b.relate(consumer, RelationConsumesAPI, RelationAPIConsumedBy, api.Ref())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pathParam matches :id and <id> path parameters, which OpenAPI writes
+// as {id}.
+var pathParam = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)|<(?:[^:>]+:)?([^>]+)>`)
+
+// apiDefinition returns the API type and definition of a service's
+// endpoints: an OpenAPI document of the HTTP endpoints, or for gRPC and
+// other endpoints without an HTTP method, their names one per line.
+func apiDefinition(title string, eps []*graph.Node) (string, string, error) {
+	paths := make(map[string]map[string]any)
+	var other []string
+	for _, ep := range eps {
+		method := strings.ToLower(ep.Properties["http_method"])
+		p := ep.Properties["full_path"]
+		if p == "" {
+			p = ep.Properties["path"]
+		}
+		if method == "" || method == "any" || p == "" {
+			other = append(other, ep.Name)
+			continue
+		}
+		p = pathParam.ReplaceAllString(p, "{$1$2}")
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		if paths[p] == nil {
+			paths[p] = make(map[string]any)
+		}
+		op := map[string]any{"summary": ep.Name, "responses": map[string]any{"default": map[string]any{"description": "response"}}}
+		if handler := ep.Properties["handler"]; handler != "" {
+			op["description"] = "Handled by " + handler
+		}
+		paths[p][method] = op
+	}
+	if len(paths) == 0 {
+		sort.Strings(other)
+		return "grpc", strings.Join(other, "\n"), nil
+	}
+	doc := map[string]any{
+		"openapi": openAPIVersion,
+		"info":    map[string]any{"title": title, "version": openAPIDocumentVersion},
+		"paths":   paths,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("encode OpenAPI definition of %s: %w", title, err)
+	}
+	return "openapi", string(data), nil
+}
+
+// fillSpecRelations mirrors an entity's relations into the spec fields
+// Backstage reads them from.
+func fillSpecRelations(e *Entity) {
+	fields := map[string]string{
+		RelationDependsOn:   "dependsOn",
+		RelationProvidesAPI: "providesApis",
+		RelationConsumesAPI: "consumesApis",
+	}
+	if e.Kind != KindComponent {
+		return
+	}
+	sort.Slice(e.Relations, func(i, j int) bool {
+		if e.Relations[i].Type != e.Relations[j].Type {
+			return e.Relations[i].Type < e.Relations[j].Type
+		}
+		return e.Relations[i].TargetRef < e.Relations[j].TargetRef
+	})
+	for _, r := range e.Relations {
+		if field, ok := fields[r.Type]; ok {
+			refs, _ := e.Spec[field].([]string)
+			e.Spec[field] = append(refs, r.TargetRef)
+		}
+	}
+}
+
+// ownerOf returns the owner reference of a service: the first CODEOWNERS
+// owner of its directory.
+func ownerOf(opts Options, group string) string {
+	if opts.Owners != nil {
+		if owners := opts.Owners(group + "/"); len(owners) > 0 {
+			return OwnerRef(owners[0])
+		}
+	}
+	return unknownOwner
+}
+
+// componentType maps a service kind to a Component spec.type.
+func componentType(kind string) string {
+	switch kind {
+	case "library":
+		return "library"
+	case "frontend", "website":
+		return "website"
+	default:
+		return "service"
+	}
+}
+
+// storeName names the Resource of an external store: the store kind,
+// with the bucket or index for stores named after one.
+func storeName(st *graph.Node) string {
+	kind := st.Properties["kind"]
+	if kind == "" || st.Name == kind {
+		return st.Name
+	}
+	return kind + "-" + st.Name
+}
+
+func annotations(n *graph.Node) map[string]string {
+	a := map[string]string{annotationNodeID: n.ID}
+	if n.Type == graph.NodeService && n.Properties["kind"] != "" {
+		a[annotationServiceKind] = n.Properties["kind"]
+	}
+	if repo := n.Properties["repo"]; repo != "" {
+		a[annotationRepository] = repo
+	}
+	return a
+}
+
+// Filter returns the entities matching any of filters, each a catalog API
+// filter such as "kind=component,spec.type=service" whose conditions must
+// all hold. Keys and values compare case-insensitively; a key without a
+// value only has to be present. No filters match every entity.
+func Filter(entities []Entity, filters []string) []Entity {
+	if len(filters) == 0 {
+		return entities
+	}
+	var out []Entity
+	for _, e := range entities {
+		for _, f := range filters {
+			if matches(&e, f) {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Find returns the entity of a kind, namespace and name, or nil.
+func Find(entities []Entity, kind, namespace, name string) *Entity {
+	ref := Ref(kind, namespace, name)
+	for i := range entities {
+		if strings.EqualFold(entities[i].Ref(), ref) {
+			return &entities[i]
+		}
+	}
+	return nil
+}
+
+func matches(e *Entity, filter string) bool {
+	for _, cond := range strings.Split(filter, ",") {
+		key, want, hasValue := strings.Cut(strings.TrimSpace(cond), "=")
+		if key == "" {
+			continue
+		}
+		values, ok := fieldValues(e, strings.ToLower(key))
+		if !ok {
+			return false
+		}
+		if !hasValue {
+			continue
+		}
+		found := false
+		for _, v := range values {
+			found = found || strings.EqualFold(v, want)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValues returns the values of a dotted entity field, and whether
+// the entity has it.
+func fieldValues(e *Entity, key string) ([]string, bool) {
+	switch key {
+	case "kind":
+		return []string{e.Kind}, true
+	case "apiversion":
+		return []string{e.APIVersion}, true
+	case "metadata.name":
+		return []string{e.Metadata.Name}, true
+	case "metadata.namespace":
+		return []string{e.Metadata.Namespace}, true
+	case "metadata.title":
+		return []string{e.Metadata.Title}, e.Metadata.Title != ""
+	}
+	if name, ok := strings.CutPrefix(key, "metadata.annotations."); ok {
+		for k, v := range e.Metadata.Annotations {
+			if strings.EqualFold(k, name) {
+				return []string{v}, true
+			}
+		}
+		return nil, false
+	}
+	if typ, ok := strings.CutPrefix(key, "relations."); ok {
+		var refs []string
+		for _, r := range e.Relations {
+			if strings.EqualFold(r.Type, typ) {
+				refs = append(refs, r.TargetRef)
+			}
+		}
+		return refs, len(refs) > 0
+	}
+	if name, ok := strings.CutPrefix(key, "spec."); ok {
+		for k, v := range e.Spec {
+			if !strings.EqualFold(k, name) {
+				continue
+			}
+			switch v := v.(type) {
+			case string:
+				return []string{v}, true
+			case []string:
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,171 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestEntities(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer store.Close()
+
+	nodes := []*graph.Node{
+		{ID: "svc-orders", Type: graph.NodeService, Name: "orders", FilePath: "orders/go.mod", Properties: map[string]string{"kind": "backend"}},
+		{ID: "svc-web", Type: graph.NodeService, Name: "web", FilePath: "web/package.json"},
+		{ID: "ep-get", Type: graph.NodeAPIEndpoint, Name: "GET /orders/:id", FilePath: "orders/api.go", Properties: map[string]string{"http_method": "GET", "path": "/orders/:id", "handler": "GetOrder"}},
+		{ID: "ep-post", Type: graph.NodeAPIEndpoint, Name: "POST /orders", FilePath: "orders/api.go", Properties: map[string]string{"http_method": "POST", "path": "/orders"}},
+		{ID: "call", Type: graph.NodeDependency, Name: "GET /orders/1", FilePath: "web/src/api.ts"},
+		{ID: "redis", Type: graph.NodeExternalStore, Name: "redis", Properties: map[string]string{"kind": "redis"}},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	edges := []*graph.Edge{
+		{ID: "e1", Type: graph.EdgeConsumes, SourceID: "call", TargetID: "ep-get"},
+		{ID: "e2", Type: graph.EdgeDependsOn, SourceID: "svc-web", TargetID: "svc-orders"},
+		{ID: "e3", Type: graph.EdgeUses, SourceID: "svc-orders", TargetID: "redis"},
+	}
+	for _, e := range edges {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+
+	owners := func(path string) []string {
+		if strings.HasPrefix(path, "orders/") {
+			return []string{"@acme/payments"}
+		}
+		return nil
+	}
+	entities, err := Entities(ctx, store, Options{Owners: owners})
+	if err != nil {
+		t.Fatalf("Entities: %v", err)
+	}
+	var refs []string
+	for _, e := range entities {
+		refs = append(refs, e.Ref())
+	}
+	wantRefs := []string{"api:default/orders", "component:default/orders", "component:default/web", "resource:default/redis"}
+	if !reflect.DeepEqual(refs, wantRefs) {
+		t.Fatalf("entities = %v, want %v", refs, wantRefs)
+	}
+
+	orders := Find(entities, "Component", "default", "orders")
+	if orders.Spec["owner"] != "group:default/payments" || orders.Spec["type"] != "service" {
+		t.Errorf("orders spec = %v", orders.Spec)
+	}
+	if got := orders.Spec["providesApis"]; !reflect.DeepEqual(got, []string{"api:default/orders"}) {
+		t.Errorf("orders providesApis = %v", got)
+	}
+	if got := orders.Spec["dependsOn"]; !reflect.DeepEqual(got, []string{"resource:default/redis"}) {
+		t.Errorf("orders dependsOn = %v", got)
+	}
+	wantRelations := []Relation{
+		{Type: RelationDependencyOf, TargetRef: "component:default/web"},
+		{Type: RelationDependsOn, TargetRef: "resource:default/redis"},
+		{Type: RelationOwnedBy, TargetRef: "group:default/payments"},
+		{Type: RelationProvidesAPI, TargetRef: "api:default/orders"},
+	}
+	if !reflect.DeepEqual(orders.Relations, wantRelations) {
+		t.Errorf("orders relations = %v, want %v", orders.Relations, wantRelations)
+	}
+
+	web := Find(entities, "component", "default", "web")
+	if web.Spec["owner"] != "group:default/unknown" {
+		t.Errorf("web owner = %v", web.Spec["owner"])
+	}
+	if got := web.Spec["consumesApis"]; !reflect.DeepEqual(got, []string{"api:default/orders"}) {
+		t.Errorf("web consumesApis = %v", got)
+	}
+
+	api := Find(entities, "API", "default", "orders")
+	if api.Spec["type"] != "openapi" {
+		t.Fatalf("api type = %v", api.Spec["type"])
+	}
+	var doc struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(api.Spec["definition"].(string)), &doc); err != nil {
+		t.Fatalf("definition: %v", err)
+	}
+	if _, ok := doc.Paths["/orders/{id}"]["get"]; !ok {
+		t.Errorf("definition paths = %v, want GET /orders/{id}", doc.Paths)
+	}
+	if !containsRelation(api.Relations, Relation{Type: RelationAPIConsumedBy, TargetRef: "component:default/web"}) {
+		t.Errorf("api relations = %v, want apiConsumedBy web", api.Relations)
+	}
+}
+
+func containsRelation(list []Relation, r Relation) bool {
+	for _, x := range list {
+		if x == r {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFilter(t *testing.T) {
+	entities := []Entity{
+		{Kind: KindComponent, Metadata: Metadata{Name: "orders", Namespace: DefaultNamespace}, Spec: map[string]any{"type": "service"},
+			Relations: []Relation{{Type: RelationOwnedBy, TargetRef: "group:default/payments"}}},
+		{Kind: KindComponent, Metadata: Metadata{Name: "shared", Namespace: DefaultNamespace}, Spec: map[string]any{"type": "library"}},
+		{Kind: KindAPI, Metadata: Metadata{Name: "orders", Namespace: DefaultNamespace}, Spec: map[string]any{"type": "openapi"}},
+	}
+	tests := []struct {
+		name    string
+		filters []string
+		want    []string
+	}{
+		{"none", nil, []string{"component:default/orders", "component:default/shared", "api:default/orders"}},
+		{"kind", []string{"kind=component"}, []string{"component:default/orders", "component:default/shared"}},
+		{"and", []string{"kind=Component,spec.type=library"}, []string{"component:default/shared"}},
+		{"or", []string{"spec.type=library", "kind=api"}, []string{"component:default/shared", "api:default/orders"}},
+		{"relation", []string{"relations.ownedBy=group:default/payments"}, []string{"component:default/orders"}},
+		{"exists", []string{"relations.ownedby"}, []string{"component:default/orders"}},
+		{"no match", []string{"metadata.name=billing"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Filter(entities, tt.filters) {
+				got = append(got, e.Ref())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter(%v) = %v, want %v", tt.filters, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{EntityName, "@scope/orders api", "scope-orders-api"},
+		{EntityName, "...", "unnamed"},
+		{EntityName, strings.Repeat("a", 70), strings.Repeat("a", 63)},
+		{OwnerRef, "@acme/payments", "group:default/payments"},
+		{OwnerRef, "@alice", "user:default/alice"},
+		{OwnerRef, "bob@example.com", "user:default/bob"},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.in); got != tt.want {
+			t.Errorf("%q -> %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package bookmark stores named references to graph nodes and saved queries,
+// and builds shareable codeeagle:// deep links to them.
+package bookmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Bookmark is a named reference to a node or a saved query.
+type Bookmark struct {
+	Name string `json:"name"`
+	// NodeID is set for node bookmarks.
+	NodeID string `json:"node_id,omitempty"`
+	// Query is set for saved-query bookmarks.
+	Query *Query `json:"query,omitempty"`
+	// Graph is the graph snapshot (branch) the bookmark refers to; empty
+	// means the current branch.
+	Graph   string    `json:"graph,omitempty"`
+	Note    string    `json:"note,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Link returns the deep link the bookmark points at.
+func (b *Bookmark) Link() Link {
+	if b.Query != nil {
+		return Link{Kind: LinkQuery, Query: b.Query, Graph: b.Graph}
+	}
+	return Link{Kind: LinkNode, NodeID: b.NodeID, Graph: b.Graph}
+}
+
+// Book is the persisted set of bookmarks.
+type Book struct {
+	Bookmarks map[string]*Bookmark `json:"bookmarks,omitempty"`
+}
+
+// Load reads bookmarks from the given file path.
+// Returns an empty book (no error) if the file does not exist.
+func Load(path string) (*Book, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Book{}, nil
+		}
+		return nil, fmt.Errorf("read bookmarks: %w", err)
+	}
+	var b Book
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("unmarshal bookmarks: %w", err)
+	}
+	return &b, nil
+}
+
+// Save writes the bookmarks to the given file path.
+func (b *Book) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal bookmarks: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write bookmarks: %w", err)
+	}
+	return nil
+}
+
+// Add stores a bookmark. It fails if the name is invalid, or already taken
+// and replace is false.
+func (b *Book) Add(bm *Bookmark, replace bool) error {
+	if bm.Name == "" || strings.ContainsAny(bm.Name, "/?#") {
+		return fmt.Errorf("invalid bookmark name %q", bm.Name)
+	}
+	if (bm.NodeID == "") == bm.Query.IsEmpty() {
+		return fmt.Errorf("bookmark %q must reference either a node or a query", bm.Name)
+	}
+	if _, exists := b.Bookmarks[bm.Name]; exists && !replace {
+		return fmt.Errorf("bookmark %q already exists", bm.Name)
+	}
+	if b.Bookmarks == nil {
+		b.Bookmarks = make(map[string]*Bookmark)
+	}
+	b.Bookmarks[bm.Name] = bm
+	return nil
+}
+
+// Get returns the bookmark with the given name.
+func (b *Book) Get(name string) (*Bookmark, error) {
+	bm, ok := b.Bookmarks[name]
+	if !ok {
+		return nil, fmt.Errorf("bookmark %q not found", name)
+	}
+	return bm, nil
+}
+
+// Remove deletes the bookmark with the given name.
+func (b *Book) Remove(name string) error {
+	if _, ok := b.Bookmarks[name]; !ok {
+		return fmt.Errorf("bookmark %q not found", name)
+	}
+	delete(b.Bookmarks, name)
+	return nil
+}
+
+// List returns all bookmarks sorted by name.
+func (b *Book) List() []*Bookmark {
+	list := make([]*Bookmark, 0, len(b.Bookmarks))
+	for _, bm := range b.Bookmarks {
+		list = append(list, bm)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
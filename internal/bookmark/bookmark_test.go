@@ -0,0 +1,113 @@
+package bookmark
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLinkRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		link Link
+		uri  string
+	}{
+		{
+			name: "node",
+			link: Link{Kind: LinkNode, NodeID: "f65bd298334c213eb3df8251", Graph: "main"},
+			uri:  "codeeagle://node/f65bd298334c213eb3df8251?graph=main",
+		},
+		{
+			name: "node without snapshot",
+			link: Link{Kind: LinkNode, NodeID: "abc"},
+			uri:  "codeeagle://node/abc",
+		},
+		{
+			name: "query",
+			link: Link{Kind: LinkQuery, Query: &Query{Type: "Function", Name: "Handle*"}, Graph: "feature/x"},
+			uri:  "codeeagle://query?graph=feature%2Fx&name=Handle%2A&type=Function",
+		},
+		{
+			name: "bookmark",
+			link: Link{Kind: LinkBookmark, Bookmark: "checkout flow"},
+			uri:  "codeeagle://bookmark/checkout%20flow",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.link.String(); got != tt.uri {
+				t.Errorf("String() = %q, want %q", got, tt.uri)
+			}
+			parsed, err := ParseURI(tt.uri)
+			if err != nil {
+				t.Fatalf("ParseURI: %v", err)
+			}
+			if parsed.Kind != tt.link.Kind || parsed.NodeID != tt.link.NodeID ||
+				parsed.Bookmark != tt.link.Bookmark || parsed.Graph != tt.link.Graph {
+				t.Errorf("ParseURI = %+v, want %+v", parsed, tt.link)
+			}
+			if tt.link.Query != nil && *parsed.Query != *tt.link.Query {
+				t.Errorf("query = %+v, want %+v", parsed.Query, tt.link.Query)
+			}
+		})
+	}
+
+	for _, bad := range []string{"https://node/abc", "codeeagle://node/", "codeeagle://query", "codeeagle://edge/abc"} {
+		if _, err := ParseURI(bad); err == nil {
+			t.Errorf("ParseURI(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestBookPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+
+	book, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load missing file: %v", err)
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := book.Add(&Bookmark{Name: "handler", NodeID: "abc", Graph: "main", Created: now}, false); err != nil {
+		t.Fatalf("Add node: %v", err)
+	}
+	if err := book.Add(&Bookmark{Name: "endpoints", Query: &Query{Type: "APIEndpoint"}, Created: now}, false); err != nil {
+		t.Fatalf("Add query: %v", err)
+	}
+	if err := book.Add(&Bookmark{Name: "handler", NodeID: "def"}, false); err == nil {
+		t.Error("expected error adding duplicate bookmark")
+	}
+	if err := book.Add(&Bookmark{Name: "empty"}, false); err == nil {
+		t.Error("expected error adding bookmark without node or query")
+	}
+	if err := book.Add(&Bookmark{Name: "a/b", NodeID: "abc"}, false); err == nil {
+		t.Error("expected error for invalid name")
+	}
+	if err := book.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	list := loaded.List()
+	if len(list) != 2 || list[0].Name != "endpoints" || list[1].Name != "handler" {
+		t.Fatalf("List = %+v", list)
+	}
+	if got := list[1].Link().String(); got != "codeeagle://node/abc?graph=main" {
+		t.Errorf("node bookmark link = %q", got)
+	}
+	if got := list[0].Link().String(); got != "codeeagle://query?type=APIEndpoint" {
+		t.Errorf("query bookmark link = %q", got)
+	}
+
+	if err := loaded.Remove("handler"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := loaded.Get("handler"); err == nil {
+		t.Error("expected removed bookmark to be gone")
+	}
+	if err := loaded.Remove("handler"); err == nil {
+		t.Error("expected error removing missing bookmark")
+	}
+}
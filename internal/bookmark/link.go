@@ -0,0 +1,151 @@
+package bookmark
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Scheme is the URI scheme of CodeEagle deep links.
+const Scheme = "codeeagle"
+
+// LinkKind identifies what a deep link points at.
+type LinkKind string
+
+const (
+	LinkNode     LinkKind = "node"
+	LinkQuery    LinkKind = "query"
+	LinkBookmark LinkKind = "bookmark"
+)
+
+// Link is a parsed deep link. Exactly one of NodeID, Query, or Bookmark is
+// set, according to Kind.
+//
+//	codeeagle://node/<id>?graph=<snapshot>
+//	codeeagle://query?type=Function&name=Handle*&graph=<snapshot>
+//	codeeagle://bookmark/<name>
+//
+// Node IDs are derived from a node's type, file, and name, so they stay
+// stable across re-indexing. Graph names the snapshot (the branch graph) the
+// link was created from; empty means the current branch.
+type Link struct {
+	Kind     LinkKind
+	NodeID   string
+	Query    *Query
+	Bookmark string
+	Graph    string
+}
+
+// NodeURI returns the deep link for a node in the given graph snapshot.
+func NodeURI(id, snapshot string) string {
+	return Link{Kind: LinkNode, NodeID: id, Graph: snapshot}.String()
+}
+
+// String formats the link as a codeeagle:// URI.
+func (l Link) String() string {
+	u := url.URL{Scheme: Scheme, Host: string(l.Kind)}
+	q := url.Values{}
+	switch l.Kind {
+	case LinkNode:
+		u.Path = "/" + l.NodeID
+	case LinkBookmark:
+		u.Path = "/" + l.Bookmark
+	case LinkQuery:
+		if l.Query != nil {
+			for _, kv := range l.Query.fields() {
+				if kv[1] != "" {
+					q.Set(kv[0], kv[1])
+				}
+			}
+		}
+	}
+	if l.Graph != "" {
+		q.Set("graph", l.Graph)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// ParseURI parses a codeeagle:// deep link.
+func ParseURI(s string) (Link, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Link{}, fmt.Errorf("parse link: %w", err)
+	}
+	if u.Scheme != Scheme {
+		return Link{}, fmt.Errorf("not a %s:// link: %q", Scheme, s)
+	}
+	params := u.Query()
+	target := strings.Trim(u.Path, "/")
+	l := Link{Kind: LinkKind(u.Host), Graph: params.Get("graph")}
+	switch l.Kind {
+	case LinkNode:
+		if target == "" {
+			return Link{}, fmt.Errorf("node link %q has no node ID", s)
+		}
+		l.NodeID = target
+	case LinkBookmark:
+		if target == "" {
+			return Link{}, fmt.Errorf("bookmark link %q has no name", s)
+		}
+		l.Bookmark = target
+	case LinkQuery:
+		q := &Query{
+			Type:     params.Get("type"),
+			Name:     params.Get("name"),
+			Package:  params.Get("package"),
+			File:     params.Get("file"),
+			Language: params.Get("language"),
+		}
+		if q.IsEmpty() {
+			return Link{}, fmt.Errorf("query link %q has no filters", s)
+		}
+		l.Query = q
+	default:
+		return Link{}, fmt.Errorf("unknown link kind %q in %q", u.Host, s)
+	}
+	return l, nil
+}
+
+// IsURI reports whether s looks like a deep link rather than a node ID or
+// bookmark name.
+func IsURI(s string) bool {
+	return strings.HasPrefix(s, Scheme+"://")
+}
+
+// Query is a saved node query.
+type Query struct {
+	Type     string `json:"type,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Package  string `json:"package,omitempty"`
+	File     string `json:"file,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+func (q *Query) fields() [][2]string {
+	return [][2]string{
+		{"type", q.Type},
+		{"name", q.Name},
+		{"package", q.Package},
+		{"file", q.File},
+		{"language", q.Language},
+	}
+}
+
+// IsEmpty reports whether the query has no filters.
+func (q *Query) IsEmpty() bool {
+	return q == nil || (q.Type == "" && q.Name == "" && q.Package == "" && q.File == "" && q.Language == "")
+}
+
+// Filter converts the query to a graph node filter.
+func (q *Query) Filter() graph.NodeFilter {
+	return graph.NodeFilter{
+		Type:        graph.NodeType(q.Type),
+		NamePattern: q.Name,
+		Package:     q.Package,
+		FilePath:    q.File,
+		Language:    q.Language,
+	}
+}
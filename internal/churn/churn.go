@@ -0,0 +1,254 @@
+// Package churn records the change history of files and functions from git
+// on their graph nodes, and ranks hotspots: code that changes often, is
+// complex, and is depended on by much of the codebase.
+package churn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Property keys recorded on File, TestFile, Function and Method nodes.
+const (
+	PropCommits = "churn_commits"
+	PropAuthors = "churn_authors"
+	// PropLines is the number of lines added and deleted; files only.
+	PropLines = "churn_lines"
+	// PropLastModified is the date of the latest change, as YYYY-MM-DD.
+	PropLastModified = "last_modified"
+)
+
+const dateLayout = "2006-01-02"
+
+// Stats is the change history of a file or function.
+type Stats struct {
+	Commits      int
+	Authors      int
+	Lines        int
+	LastModified time.Time
+}
+
+// StatsFromNode reads the recorded history from a node. The second return
+// value is false when no changes have been recorded.
+func StatsFromNode(n *graph.Node) (Stats, bool) {
+	if n == nil || n.Properties == nil {
+		return Stats{}, false
+	}
+	commits, _ := strconv.Atoi(n.Properties[PropCommits])
+	if commits <= 0 {
+		return Stats{}, false
+	}
+	authors, _ := strconv.Atoi(n.Properties[PropAuthors])
+	lines, _ := strconv.Atoi(n.Properties[PropLines])
+	last, _ := time.Parse(dateLayout, n.Properties[PropLastModified])
+	return Stats{Commits: commits, Authors: authors, Lines: lines, LastModified: last}, true
+}
+
+// apply writes the stats onto the node, or removes them when s is empty.
+// It reports whether the node changed.
+func (s Stats) apply(n *graph.Node) bool {
+	want := map[string]string{}
+	if s.Commits > 0 {
+		want[PropCommits] = strconv.Itoa(s.Commits)
+		want[PropAuthors] = strconv.Itoa(s.Authors)
+		if s.Lines > 0 {
+			want[PropLines] = strconv.Itoa(s.Lines)
+		}
+		if !s.LastModified.IsZero() {
+			want[PropLastModified] = s.LastModified.UTC().Format(dateLayout)
+		}
+	}
+	changed := false
+	for _, key := range []string{PropCommits, PropAuthors, PropLines, PropLastModified} {
+		if n.Properties[key] == want[key] {
+			continue
+		}
+		changed = true
+		if want[key] == "" {
+			delete(n.Properties, key)
+			continue
+		}
+		if n.Properties == nil {
+			n.Properties = make(map[string]string)
+		}
+		n.Properties[key] = want[key]
+	}
+	return changed
+}
+
+// Options configures an ingestion.
+type Options struct {
+	// Since limits the history to commits after a date git understands
+	// ("2024-01-01", "6.months.ago"); empty means all history.
+	Since string
+	// SkipFunctions records file history only, skipping the git blame
+	// needed to attribute changes to functions.
+	SkipFunctions bool
+}
+
+// Result summarizes an ingestion run.
+type Result struct {
+	// Commits is the number of commits in the history window.
+	Commits int `json:"commits"`
+	// Files is the number of file nodes with recorded changes.
+	Files int `json:"files"`
+	// Functions is the number of function and method nodes with recorded
+	// changes.
+	Functions int `json:"functions"`
+}
+
+// Ingest reads the git history of the repository at repoPath and records
+// it on the file, function and method nodes whose paths (relative to
+// repoPath) it touches. Nodes of other files in the repository have earlier
+// history removed, so each ingestion replaces the previous one; nodes of
+// files outside it, such as those of other indexed repositories, are left
+// alone.
+//
+// A function's history counts the commits in the window that last changed
+// at least one of its current lines, as reported by git blame, so code that
+// was later rewritten does not count.
+func Ingest(ctx context.Context, store graph.Store, repoPath string, opts Options) (*Result, error) {
+	commits, err := gitutil.GetChurnLog(repoPath, opts.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	type history struct {
+		commits map[string]bool
+		authors map[string]bool
+		lines   int
+		last    time.Time
+	}
+	files := make(map[string]*history)
+	inWindow := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		inWindow[c.Hash] = true
+		for _, f := range c.Files {
+			h := files[f.Path]
+			if h == nil {
+				h = &history{commits: map[string]bool{}, authors: map[string]bool{}}
+				files[f.Path] = h
+			}
+			h.commits[c.Hash] = true
+			h.authors[c.Author] = true
+			h.lines += f.Additions + f.Deletions
+			if c.Date.After(h.last) {
+				h.last = c.Date
+			}
+		}
+	}
+
+	// inRepo reports whether a node path belongs to this repository.
+	inRepo := func(path string) bool {
+		if files[path] != nil {
+			return true
+		}
+		_, err := os.Stat(filepath.Join(repoPath, path))
+		return err == nil
+	}
+
+	res := &Result{Commits: len(commits)}
+	var changed []*graph.Node
+	for _, typ := range []graph.NodeType{graph.NodeFile, graph.NodeTestFile} {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+		}
+		for _, n := range nodes {
+			if !inRepo(n.FilePath) {
+				continue
+			}
+			var s Stats
+			if h := files[n.FilePath]; h != nil {
+				s = Stats{Commits: len(h.commits), Authors: len(h.authors), Lines: h.lines, LastModified: h.last}
+				res.Files++
+			}
+			if s.apply(n) {
+				changed = append(changed, n)
+			}
+		}
+	}
+
+	if !opts.SkipFunctions {
+		byFile := make(map[string][]*graph.Node)
+		for _, typ := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+			nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ})
+			if err != nil {
+				return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+			}
+			for _, n := range nodes {
+				byFile[n.FilePath] = append(byFile[n.FilePath], n)
+			}
+		}
+		paths := make([]string, 0, len(byFile))
+		for p := range byFile {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if !inRepo(p) {
+				continue
+			}
+			var blame []gitutil.BlameLine
+			if files[p] != nil {
+				// Files deleted or ignored since the last index fail to
+				// blame; their functions are left without history.
+				blame, _ = gitutil.BlameFile(repoPath, p)
+			}
+			for _, n := range byFile[p] {
+				s := functionStats(blame, n.Line, n.EndLine, inWindow)
+				if s.Commits > 0 {
+					res.Functions++
+				}
+				if s.apply(n) {
+					changed = append(changed, n)
+				}
+			}
+		}
+	}
+
+	for _, n := range changed {
+		delete(n.Properties, graph.PropGraphSource)
+		if err := store.UpdateNode(ctx, n); err != nil {
+			// The node may come from a read-only branch; copy it to the write branch.
+			if err := store.AddNode(ctx, n); err != nil {
+				return nil, fmt.Errorf("update %s: %w", n.Name, err)
+			}
+		}
+	}
+	return res, nil
+}
+
+// functionStats summarizes the blame of lines start..end (1-based,
+// inclusive), counting only commits in the history window.
+func functionStats(blame []gitutil.BlameLine, start, end int, inWindow map[string]bool) Stats {
+	if start < 1 || end < start {
+		return Stats{}
+	}
+	end = min(end, len(blame))
+	commits := make(map[string]bool)
+	authors := make(map[string]bool)
+	var last time.Time
+	for _, line := range blame[min(start-1, end):end] {
+		if !inWindow[line.Commit] {
+			continue
+		}
+		commits[line.Commit] = true
+		authors[line.Author] = true
+		if line.Time.After(last) {
+			last = line.Time
+		}
+	}
+	return Stats{Commits: len(commits), Authors: len(authors), LastModified: last}
+}
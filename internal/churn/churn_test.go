@@ -0,0 +1,143 @@
+package churn
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestIngestAndHotspots(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	commit := func(date, author, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", "change " + file}} {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=" + author, "-c", "user.email=" + author + "@example.com"}, args...)...)
+			cmd.Dir = repo
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+	}
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	commit("2022-01-10T10:00:00Z", "alice", "a.go", "package a\n\nfunc Stable() int {\n\treturn 1\n}\n\nfunc Busy(x int) int {\n\treturn x\n}\n")
+	commit("2022-02-10T10:00:00Z", "bob", "a.go", "package a\n\nfunc Stable() int {\n\treturn 1\n}\n\nfunc Busy(x int) int {\n\treturn x + 1\n}\n")
+	commit("2022-03-10T10:00:00Z", "alice", "a.go", "package a\n\nfunc Stable() int {\n\treturn 1\n}\n\nfunc Busy(x int) int {\n\tif x < 0 {\n\t\tx = 0\n\t}\n\treturn x + 1\n}\n")
+	commit("2024-05-01T10:00:00Z", "carol", "b.go", "package a\n\nfunc Caller() int {\n\treturn Busy(1)\n}\n")
+
+	// An untracked file in the repository has no history.
+	if err := os.WriteFile(filepath.Join(repo, "gone_test.go"), []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+	for _, n := range []*graph.Node{
+		{ID: "file-a", Type: graph.NodeFile, Name: "a.go", FilePath: "a.go", Language: "go"},
+		{ID: "file-b", Type: graph.NodeFile, Name: "b.go", FilePath: "b.go", Language: "go"},
+		{ID: "file-gone", Type: graph.NodeTestFile, Name: "gone_test.go", FilePath: "gone_test.go",
+			Properties: map[string]string{PropCommits: "9", PropAuthors: "1"}},
+		{ID: "stable", Type: graph.NodeFunction, Name: "Stable", FilePath: "a.go", Line: 3, EndLine: 5, Language: "go"},
+		{ID: "busy", Type: graph.NodeFunction, Name: "Busy", FilePath: "a.go", Line: 7, EndLine: 12, Language: "go"},
+		{ID: "caller", Type: graph.NodeFunction, Name: "Caller", FilePath: "b.go", Line: 3, EndLine: 5, Language: "go"},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	if err := store.AddEdge(ctx, &graph.Edge{ID: graph.NewEdgeID(graph.EdgeCalls, "caller", "busy"),
+		Type: graph.EdgeCalls, SourceID: "caller", TargetID: "busy"}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+
+	// file-gone has stale history, so only the function level fails yet.
+	if _, err := Hotspots(ctx, store, HotspotOptions{Level: LevelFunction}); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("Hotspots before ingestion: err = %v, want ErrNoHistory", err)
+	}
+
+	res, err := Ingest(ctx, store, repo, Options{})
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if *res != (Result{Commits: 4, Files: 2, Functions: 3}) {
+		t.Errorf("result = %+v, want 4 commits, 2 files, 3 functions", *res)
+	}
+
+	stats := func(id string) Stats {
+		t.Helper()
+		n, err := store.GetNode(ctx, id)
+		if err != nil {
+			t.Fatalf("GetNode(%s): %v", id, err)
+		}
+		s, _ := StatsFromNode(n)
+		return s
+	}
+	if s := stats("file-a"); s.Commits != 3 || s.Authors != 2 || s.Lines == 0 || s.LastModified.Format("2006-01-02") != "2022-03-10" {
+		t.Errorf("a.go stats = %+v, want 3 commits by 2 authors, last on 2022-03-10", s)
+	}
+	if s := stats("busy"); s.Commits != 3 || s.Authors != 2 {
+		t.Errorf("Busy stats = %+v, want 3 commits by 2 authors", s)
+	}
+	if s := stats("stable"); s.Commits != 1 || s.Authors != 1 {
+		t.Errorf("Stable stats = %+v, want 1 commit by 1 author", s)
+	}
+	if s := stats("file-gone"); s.Commits != 0 {
+		t.Errorf("stale history not cleared: %+v", s)
+	}
+
+	spots, err := Hotspots(ctx, store, HotspotOptions{Level: LevelFunction, RepoRoots: []string{repo}, Limit: 2})
+	if err != nil {
+		t.Fatalf("Hotspots: %v", err)
+	}
+	if len(spots) != 2 || spots[0].ID != "busy" {
+		t.Fatalf("function hotspots = %+v, want Busy first of 2", spots)
+	}
+	if spots[0].Complexity != 2 || spots[0].FanIn != 1 {
+		t.Errorf("Busy complexity %v, fan-in %d; want 2 and 1", spots[0].Complexity, spots[0].FanIn)
+	}
+
+	spots, err = Hotspots(ctx, store, HotspotOptions{Level: LevelFile, RepoRoots: []string{repo}})
+	if err != nil {
+		t.Fatalf("Hotspots: %v", err)
+	}
+	if len(spots) != 2 || spots[0].ID != "file-a" || spots[0].FanIn != 1 || spots[1].FanIn != 0 {
+		t.Errorf("file hotspots = %+v, want a.go (fan-in 1) then b.go", spots)
+	}
+
+	// A narrower window replaces the recorded history, but only of files in
+	// the repository.
+	if err := store.AddNode(ctx, &graph.Node{ID: "file-elsewhere", Type: graph.NodeFile, Name: "main.go", FilePath: "other/main.go",
+		Properties: map[string]string{PropCommits: "4", PropAuthors: "2"}}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if res, err := Ingest(ctx, store, repo, Options{Since: "2023-01-01"}); err != nil || res.Commits != 1 {
+		t.Fatalf("Ingest since 2023: %+v, %v; want 1 commit", res, err)
+	}
+	if s := stats("busy"); s.Commits != 0 {
+		t.Errorf("Busy stats outside the window = %+v, want none", s)
+	}
+	if s := stats("caller"); s.Commits != 1 {
+		t.Errorf("Caller stats = %+v, want 1 commit", s)
+	}
+	if s := stats("file-elsewhere"); s.Commits != 4 {
+		t.Errorf("history of a file outside the repository = %+v, want it kept", s)
+	}
+}
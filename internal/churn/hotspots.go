@@ -0,0 +1,197 @@
+package churn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/metrics"
+)
+
+// Level selects what Hotspots ranks.
+type Level string
+
+const (
+	LevelFile     Level = "file"
+	LevelFunction Level = "function"
+)
+
+// ErrNoHistory is returned by Hotspots when no node has recorded churn.
+var ErrNoHistory = errors.New("no churn recorded; run 'codeeagle churn' first")
+
+// Hotspot is a file or function ranked by risk.
+type Hotspot struct {
+	ID           string         `json:"id"`
+	Type         graph.NodeType `json:"type"`
+	Name         string         `json:"name"`
+	FilePath     string         `json:"file_path"`
+	Line         int            `json:"line,omitempty"`
+	Commits      int            `json:"commits"`
+	Authors      int            `json:"authors"`
+	LastModified string         `json:"last_modified,omitempty"`
+	Complexity   float64        `json:"complexity"`
+	// FanIn is the number of distinct callers of a function, or of other
+	// files calling into a file.
+	FanIn int     `json:"fan_in"`
+	Score float64 `json:"score"`
+}
+
+// HotspotOptions configures Hotspots.
+type HotspotOptions struct {
+	Level Level
+	// RepoRoots are the repository roots that node file paths are relative
+	// to, used to read source for complexity.
+	RepoRoots []string
+	// Limit caps the number of hotspots returned; 0 means no limit.
+	Limit int
+}
+
+// Hotspots ranks the files or functions with recorded churn by
+// commits × cyclomatic complexity × log2(2 + fan-in), highest first, so
+// frequently changed code only ranks high when it is also complex, and
+// widely used code ranks higher still. Complexity is computed from the
+// current source; code that can no longer be read counts as 1.
+func Hotspots(ctx context.Context, store graph.Store, opts HotspotOptions) ([]Hotspot, error) {
+	types := []graph.NodeType{graph.NodeFile, graph.NodeTestFile}
+	if opts.Level == LevelFunction {
+		types = []graph.NodeType{graph.NodeFunction, graph.NodeMethod}
+	}
+
+	h := &hotspotter{store: store, roots: opts.RepoRoots, files: make(map[string][]string), nodeFile: make(map[string]string)}
+	var spots []Hotspot
+	for _, typ := range types {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+		}
+		for _, n := range nodes {
+			s, ok := StatsFromNode(n)
+			if !ok {
+				continue
+			}
+			spot := Hotspot{
+				ID: n.ID, Type: n.Type, Name: n.Name, FilePath: n.FilePath, Line: n.Line,
+				Commits: s.Commits, Authors: s.Authors, LastModified: n.Properties[PropLastModified],
+			}
+			var err error
+			if opts.Level == LevelFunction {
+				spot.Complexity = h.complexity(n, n.Line, n.EndLine)
+				spot.FanIn, err = h.functionFanIn(ctx, n.ID)
+			} else {
+				spot.Complexity = h.complexity(n, 0, 0)
+				spot.FanIn, err = h.fileFanIn(ctx, n.FilePath)
+			}
+			if err != nil {
+				return nil, err
+			}
+			spot.Score = float64(spot.Commits) * spot.Complexity * math.Log2(2+float64(spot.FanIn))
+			spots = append(spots, spot)
+		}
+	}
+	if len(spots) == 0 {
+		return nil, ErrNoHistory
+	}
+
+	sort.Slice(spots, func(i, j int) bool {
+		if spots[i].Score != spots[j].Score {
+			return spots[i].Score > spots[j].Score
+		}
+		return spots[i].ID < spots[j].ID
+	})
+	if opts.Limit > 0 && len(spots) > opts.Limit {
+		spots = spots[:opts.Limit]
+	}
+	return spots, nil
+}
+
+type hotspotter struct {
+	store graph.Store
+	roots []string
+	// files caches source lines by file path.
+	files map[string][]string
+	// nodeFile caches the file path of caller nodes.
+	nodeFile map[string]string
+}
+
+// complexity returns the cyclomatic complexity of lines start..end
+// (1-based, inclusive) of n's file, or of the whole file when start is 0.
+func (h *hotspotter) complexity(n *graph.Node, start, end int) float64 {
+	lines := h.source(n.FilePath)
+	if start > 0 {
+		if end < start || start > len(lines) {
+			return 1
+		}
+		lines = lines[start-1 : min(end, len(lines))]
+	}
+	calc := metrics.CyclomaticComplexityCalculator{}
+	m, err := calc.Calculate(n.FilePath, []byte(strings.Join(lines, "\n")), n.Language)
+	if err != nil {
+		return 1
+	}
+	return m[metrics.CyclomaticComplexity]
+}
+
+func (h *hotspotter) source(path string) []string {
+	if lines, ok := h.files[path]; ok {
+		return lines
+	}
+	var lines []string
+	for _, root := range h.roots {
+		if content, err := os.ReadFile(filepath.Join(root, path)); err == nil {
+			lines = strings.Split(string(content), "\n")
+			break
+		}
+	}
+	h.files[path] = lines
+	return lines
+}
+
+func (h *hotspotter) functionFanIn(ctx context.Context, id string) (int, error) {
+	edges, err := h.store.GetIncomingEdges(ctx, id, graph.EdgeCalls)
+	if err != nil {
+		return 0, fmt.Errorf("get callers of %s: %w", id, err)
+	}
+	callers := make(map[string]bool)
+	for _, e := range edges {
+		if e.SourceID != id {
+			callers[e.SourceID] = true
+		}
+	}
+	return len(callers), nil
+}
+
+func (h *hotspotter) fileFanIn(ctx context.Context, path string) (int, error) {
+	nodes, err := h.store.QueryNodes(ctx, graph.NodeFilter{FilePath: path})
+	if err != nil {
+		return 0, fmt.Errorf("query nodes in %s: %w", path, err)
+	}
+	callers := make(map[string]bool)
+	for _, n := range nodes {
+		if n.Type != graph.NodeFunction && n.Type != graph.NodeMethod {
+			continue
+		}
+		edges, err := h.store.GetIncomingEdges(ctx, n.ID, graph.EdgeCalls)
+		if err != nil {
+			return 0, fmt.Errorf("get callers of %s: %w", n.Name, err)
+		}
+		for _, e := range edges {
+			file, ok := h.nodeFile[e.SourceID]
+			if !ok {
+				if caller, err := h.store.GetNode(ctx, e.SourceID); err == nil {
+					file = caller.FilePath
+				}
+				h.nodeFile[e.SourceID] = file
+			}
+			if file != "" && file != path {
+				callers[file] = true
+			}
+		}
+	}
+	return len(callers), nil
+}
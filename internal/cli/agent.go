@@ -58,12 +58,11 @@ Available agents:
 
 // createLLMClient creates an LLM client from the config and environment.
 func createLLMClient(cfg *config.Config) (llm.Client, error) {
-	apiKey := os.Getenv("ANTHROPIC_API_KEY")
-
 	provider := cfg.Agents.LLMProvider
 	if provider == "" {
 		provider = "anthropic"
 	}
+	apiKey := llmAPIKey(provider)
 
 	// Auto-detect Claude CLI when Anthropic is configured but no API key is set.
 	if (provider == "" || provider == "anthropic") && apiKey == "" {
@@ -89,6 +88,7 @@ func createLLMClient(cfg *config.Config) (llm.Client, error) {
 		Project:         project,
 		Location:        location,
 		CredentialsFile: cfg.Agents.CredentialsFile,
+		APIVersion:      cfg.Agents.APIVersion,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create LLM client: %w", err)
@@ -97,6 +97,25 @@ func createLLMClient(cfg *config.Config) (llm.Client, error) {
 	return client, nil
 }
 
+// llmAPIKey returns the API key for provider from its environment variable.
+// Providers with other ways to authenticate read their own environment.
+func llmAPIKey(provider string) string {
+	switch provider {
+	case "anthropic":
+		return os.Getenv("ANTHROPIC_API_KEY")
+	case "azure-openai":
+		return os.Getenv("AZURE_OPENAI_API_KEY")
+	case "gemini":
+		if key := os.Getenv("GEMINI_API_KEY"); key != "" {
+			return key
+		}
+		return os.Getenv("GOOGLE_API_KEY")
+	case "bedrock":
+		return os.Getenv("AWS_BEARER_TOKEN_BEDROCK")
+	}
+	return ""
+}
+
 func newAgentPlanCmd() *cobra.Command {
 	var maxIterations int
 
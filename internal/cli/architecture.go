@@ -0,0 +1,343 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// archMemberTypes are the node types whose package or service they are
+// counted in. Test code is left out.
+var archMemberTypes = []graph.NodeType{
+	graph.NodeFunction, graph.NodeMethod, graph.NodeStruct, graph.NodeClass, graph.NodeInterface,
+	graph.NodeEnum, graph.NodeType_, graph.NodeDTO, graph.NodeDBModel, graph.NodeDomainModel, graph.NodeViewModel,
+}
+
+// archCouplingEdges are the edges making one package depend on another.
+var archCouplingEdges = []graph.EdgeType{
+	graph.EdgeCalls, graph.EdgeImplements, graph.EdgeExtends, graph.EdgeDependsOn, graph.EdgeProvides, graph.EdgeThrows,
+}
+
+// archPackage holds the coupling and abstraction metrics of a package (a
+// source directory) or service.
+type archPackage struct {
+	Name string `json:"name"`
+	// Types counts the package's types; Abstract those that are
+	// interfaces or abstract classes.
+	Types    int `json:"types"`
+	Abstract int `json:"abstract"`
+	// Afferent (Ca) counts the other packages depending on this one;
+	// Efferent (Ce) the packages this one depends on.
+	Afferent int `json:"afferent"`
+	Efferent int `json:"efferent"`
+	// Instability is Ce / (Ca + Ce), Abstractness Abstract / Types, and
+	// Distance |A + I - 1|, the distance from the main sequence.
+	Instability  float64     `json:"instability"`
+	Abstractness float64     `json:"abstractness"`
+	Distance     float64     `json:"distance"`
+	Change       *archChange `json:"change,omitempty"`
+}
+
+// archChange is how a package's metrics moved since the baseline snapshot.
+type archChange struct {
+	New          bool    `json:"new,omitempty"`
+	Afferent     int     `json:"afferent"`
+	Efferent     int     `json:"efferent"`
+	Instability  float64 `json:"instability"`
+	Abstractness float64 `json:"abstractness"`
+	Distance     float64 `json:"distance"`
+}
+
+// archInterface is the fan-in and fan-out of an interface: the other
+// packages depending on it and its implementations.
+type archInterface struct {
+	Name            string `json:"name"`
+	Package         string `json:"package"`
+	FilePath        string `json:"file_path"`
+	Line            int    `json:"line,omitempty"`
+	FanIn           int    `json:"fan_in"`
+	Implementations int    `json:"implementations"`
+}
+
+// archReport is the result of 'codeeagle metrics --architecture'.
+type archReport struct {
+	By         string          `json:"by"`
+	Baseline   string          `json:"baseline,omitempty"`
+	Packages   []archPackage   `json:"packages"`
+	Removed    []string        `json:"removed,omitempty"`
+	Interfaces []archInterface `json:"interfaces"`
+}
+
+// runArchitectureMetrics reports the architecture metrics of the current
+// graph, compared with the snapshot of commit compare when it is set.
+func runArchitectureMetrics(ctx context.Context, out io.Writer, by, language, compare string, limit int, jsonOut bool) error {
+	if by != "package" && by != "service" {
+		return fmt.Errorf("invalid --by %q: must be package or service", by)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	store, _, err := openBranchStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	report, err := buildArchReport(ctx, store, by, language)
+	if err != nil {
+		return err
+	}
+	if compare != "" {
+		branch, err := resolveSnapshot(store, compare)
+		if err != nil {
+			return err
+		}
+		baseline, err := buildArchReport(ctx, store.WithReadBranches([]string{branch}), by, language)
+		if err != nil {
+			return err
+		}
+		report.Baseline = strings.TrimPrefix(branch, snapshotBranchPrefix)
+		compareArch(report, baseline)
+	}
+	if limit > 0 {
+		report.Packages = report.Packages[:min(limit, len(report.Packages))]
+		report.Interfaces = report.Interfaces[:min(limit, len(report.Interfaces))]
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	writeArchReport(out, report)
+	return nil
+}
+
+// buildArchReport computes the metrics of each package (source directory)
+// or service from the coupling edges between their symbols, sorted by
+// distance from the main sequence, worst first.
+func buildArchReport(ctx context.Context, store graph.Store, by, language string) (*archReport, error) {
+	var serviceOf map[string]string
+	if by == "service" {
+		var err error
+		if serviceOf, err = serviceNamesByGroup(ctx, store); err != nil {
+			return nil, err
+		}
+	}
+	groupOf := func(n *graph.Node) string {
+		if by == "service" {
+			if name := serviceOf[linker.ServiceGroup(n.FilePath)]; name != "" {
+				return name
+			}
+			return "(none)"
+		}
+		return path.Dir(n.FilePath)
+	}
+
+	packages := make(map[string]*archPackage)
+	owner := make(map[string]string) // node ID -> package
+	var interfaces []*graph.Node
+	for _, typ := range archMemberTypes {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ, Language: language})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+		}
+		for _, n := range nodes {
+			lang := n.Language
+			if lang == "" {
+				lang = inferLangFromPath(n.FilePath)
+			}
+			if n.FilePath == "" || isTestFileByPath(n.FilePath, lang) {
+				continue
+			}
+			name := groupOf(n)
+			owner[n.ID] = name
+			p, ok := packages[name]
+			if !ok {
+				p = &archPackage{Name: name}
+				packages[name] = p
+			}
+			if typ == graph.NodeFunction || typ == graph.NodeMethod {
+				continue
+			}
+			p.Types++
+			if isAbstractType(n) {
+				p.Abstract++
+			}
+			if typ == graph.NodeInterface {
+				interfaces = append(interfaces, n)
+			}
+		}
+	}
+
+	efferent := make(map[string]map[string]bool)
+	afferent := make(map[string]map[string]bool)
+	dependents := make(map[string]map[string]bool) // interface ID -> depending packages
+	implementations := make(map[string]int)
+	for _, et := range archCouplingEdges {
+		edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: et})
+		if err != nil {
+			return nil, fmt.Errorf("query %s edges: %w", et, err)
+		}
+		for _, e := range edges {
+			from, ok := owner[e.SourceID]
+			to, ok2 := owner[e.TargetID]
+			if !ok || !ok2 {
+				continue
+			}
+			if et == graph.EdgeImplements {
+				implementations[e.TargetID]++
+			} else if from != to {
+				if dependents[e.TargetID] == nil {
+					dependents[e.TargetID] = make(map[string]bool)
+				}
+				dependents[e.TargetID][from] = true
+			}
+			if from == to {
+				continue
+			}
+			if efferent[from] == nil {
+				efferent[from] = make(map[string]bool)
+			}
+			efferent[from][to] = true
+			if afferent[to] == nil {
+				afferent[to] = make(map[string]bool)
+			}
+			afferent[to][from] = true
+		}
+	}
+
+	report := &archReport{By: by, Packages: []archPackage{}, Interfaces: []archInterface{}}
+	for name, p := range packages {
+		p.Afferent, p.Efferent = len(afferent[name]), len(efferent[name])
+		if p.Afferent+p.Efferent > 0 {
+			p.Instability = float64(p.Efferent) / float64(p.Afferent+p.Efferent)
+		}
+		if p.Types > 0 {
+			p.Abstractness = float64(p.Abstract) / float64(p.Types)
+		}
+		p.Distance = math.Abs(p.Abstractness + p.Instability - 1)
+		report.Packages = append(report.Packages, *p)
+	}
+	sort.Slice(report.Packages, func(i, j int) bool {
+		a, b := report.Packages[i], report.Packages[j]
+		if a.Distance != b.Distance {
+			return a.Distance > b.Distance
+		}
+		return a.Name < b.Name
+	})
+
+	for _, iface := range interfaces {
+		report.Interfaces = append(report.Interfaces, archInterface{
+			Name:            iface.Name,
+			Package:         owner[iface.ID],
+			FilePath:        iface.FilePath,
+			Line:            iface.Line,
+			FanIn:           len(dependents[iface.ID]),
+			Implementations: implementations[iface.ID],
+		})
+	}
+	sort.Slice(report.Interfaces, func(i, j int) bool {
+		a, b := report.Interfaces[i], report.Interfaces[j]
+		if a.FanIn != b.FanIn {
+			return a.FanIn > b.FanIn
+		}
+		if a.Implementations != b.Implementations {
+			return a.Implementations > b.Implementations
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+// isAbstractType reports whether a type is an interface or an abstract
+// class.
+func isAbstractType(n *graph.Node) bool {
+	if n.Type == graph.NodeInterface {
+		return true
+	}
+	for _, m := range strings.Fields(n.Properties["modifiers"]) {
+		if m == "abstract" {
+			return true
+		}
+	}
+	return false
+}
+
+// compareArch records on report how each package moved since baseline and
+// lists the packages that no longer exist.
+func compareArch(report, baseline *archReport) {
+	before := make(map[string]archPackage, len(baseline.Packages))
+	for _, p := range baseline.Packages {
+		before[p.Name] = p
+	}
+	for i := range report.Packages {
+		p := &report.Packages[i]
+		b, ok := before[p.Name]
+		if !ok {
+			p.Change = &archChange{New: true}
+			continue
+		}
+		delete(before, p.Name)
+		p.Change = &archChange{
+			Afferent:     p.Afferent - b.Afferent,
+			Efferent:     p.Efferent - b.Efferent,
+			Instability:  p.Instability - b.Instability,
+			Abstractness: p.Abstractness - b.Abstractness,
+			Distance:     p.Distance - b.Distance,
+		}
+	}
+	for name := range before {
+		report.Removed = append(report.Removed, name)
+	}
+	sort.Strings(report.Removed)
+}
+
+func writeArchReport(w io.Writer, r *archReport) {
+	if len(r.Packages) == 0 {
+		fmt.Fprintln(w, "No code in the graph. Run 'codeeagle sync' first.")
+		return
+	}
+
+	title := strings.ToUpper(r.By[:1]) + r.By[1:]
+	if r.Baseline != "" {
+		fmt.Fprintf(w, "Compared with snapshot %s\n\n", shortCommit(r.Baseline))
+	}
+	fmt.Fprintf(w, "  %-40s  %5s  %8s  %4s  %4s  %5s  %5s  %5s", title, "Types", "Abstract", "Ca", "Ce", "I", "A", "D")
+	if r.Baseline != "" {
+		fmt.Fprintf(w, "  %s", "Change")
+	}
+	fmt.Fprintln(w)
+	for _, p := range r.Packages {
+		fmt.Fprintf(w, "  %-40s  %5d  %8d  %4d  %4d  %5.2f  %5.2f  %5.2f", p.Name, p.Types, p.Abstract, p.Afferent, p.Efferent, p.Instability, p.Abstractness, p.Distance)
+		if c := p.Change; c != nil {
+			if c.New {
+				fmt.Fprint(w, "  new")
+			} else {
+				fmt.Fprintf(w, "  Ca %+d, Ce %+d, I %+.2f, D %+.2f", c.Afferent, c.Efferent, c.Instability, c.Distance)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	if len(r.Removed) > 0 {
+		fmt.Fprintf(w, "\nRemoved since %s: %s\n", shortCommit(r.Baseline), strings.Join(r.Removed, ", "))
+	}
+
+	if len(r.Interfaces) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nInterfaces (fan-in: depending %ss, fan-out: implementations):\n", r.By)
+	for _, i := range r.Interfaces {
+		fmt.Fprintf(w, "  %-40s  in %-3d  out %-3d  %s:%d\n", i.Name, i.FanIn, i.Implementations, i.FilePath, i.Line)
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestBuildArchReport(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	node := func(id string, typ graph.NodeType, file string) *graph.Node {
+		return &graph.Node{ID: id, Type: typ, Name: id, FilePath: file, Line: 1, Language: "java"}
+	}
+	edge := func(typ graph.EdgeType, from, to string) *graph.Edge {
+		return &graph.Edge{ID: graph.NewEdgeID(typ, from, to), Type: typ, SourceID: from, TargetID: to}
+	}
+	base := node("Base", graph.NodeClass, "core/Base.java")
+	base.Properties = map[string]string{"modifiers": "public abstract"}
+	addTestNodes(t, store,
+		node("Repo", graph.NodeInterface, "core/Repo.java"),
+		base,
+		node("SqlRepo", graph.NodeClass, "db/SqlRepo.java"),
+		node("query", graph.NodeMethod, "db/SqlRepo.java"),
+		node("Handler", graph.NodeClass, "web/Handler.java"),
+		node("handle", graph.NodeMethod, "web/Handler.java"),
+		node("HandlerTest", graph.NodeClass, "web/HandlerTest.java"),
+	)
+	addTestEdges(t, store,
+		edge(graph.EdgeImplements, "SqlRepo", "Repo"),
+		edge(graph.EdgeExtends, "SqlRepo", "Base"),
+		edge(graph.EdgeDependsOn, "Handler", "Repo"),
+		edge(graph.EdgeCalls, "handle", "query"),
+		// Tests and edges within a package don't couple packages.
+		edge(graph.EdgeDependsOn, "HandlerTest", "Handler"),
+		edge(graph.EdgeCalls, "query", "SqlRepo"),
+	)
+
+	report, err := buildArchReport(ctx, store, "package", "")
+	if err != nil {
+		t.Fatalf("buildArchReport: %v", err)
+	}
+	want := map[string]archPackage{
+		"core": {Types: 2, Abstract: 2, Afferent: 2, Efferent: 0, Instability: 0, Abstractness: 1, Distance: 0},
+		"db":   {Types: 1, Abstract: 0, Afferent: 1, Efferent: 1, Instability: 0.5, Abstractness: 0, Distance: 0.5},
+		"web":  {Types: 1, Abstract: 0, Afferent: 0, Efferent: 2, Instability: 1, Abstractness: 0, Distance: 0},
+	}
+	if len(report.Packages) != len(want) {
+		t.Fatalf("packages = %+v, want %d", report.Packages, len(want))
+	}
+	if report.Packages[0].Name != "db" {
+		t.Errorf("first package = %s, want db (furthest from the main sequence)", report.Packages[0].Name)
+	}
+	for _, p := range report.Packages {
+		w := want[p.Name]
+		w.Name = p.Name
+		if p != w {
+			t.Errorf("package %s = %+v, want %+v", p.Name, p, w)
+		}
+	}
+	if len(report.Interfaces) != 1 || report.Interfaces[0].FanIn != 1 || report.Interfaces[0].Implementations != 1 {
+		t.Errorf("interfaces = %+v, want Repo with fan-in 1 and 1 implementation", report.Interfaces)
+	}
+
+	baseline := &archReport{Packages: []archPackage{
+		{Name: "core", Afferent: 1, Abstractness: 1, Distance: 0},
+		{Name: "legacy"},
+	}}
+	compareArch(report, baseline)
+	for _, p := range report.Packages {
+		switch p.Name {
+		case "core":
+			if p.Change == nil || p.Change.New || p.Change.Afferent != 1 || math.Abs(p.Change.Distance) > 1e-9 {
+				t.Errorf("core change = %+v, want Ca +1", p.Change)
+			}
+		case "db", "web":
+			if p.Change == nil || !p.Change.New {
+				t.Errorf("%s change = %+v, want new", p.Name, p.Change)
+			}
+		}
+	}
+	if strings.Join(report.Removed, ",") != "legacy" {
+		t.Errorf("removed = %v, want legacy", report.Removed)
+	}
+
+	report.Baseline = "abc1234def"
+	var buf bytes.Buffer
+	writeArchReport(&buf, report)
+	for _, want := range []string{"Package", "Ca +1", "new", "Removed since", "legacy", "Repo", "core/Repo.java:1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
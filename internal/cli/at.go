@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func newAtCmd() *cobra.Command {
+	var (
+		nodeTypes []string
+		all       bool
+		jsonOut   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "at <file>:<line>",
+		Short: "Show the innermost symbol containing a file position",
+		Long: `Resolve a file position to the innermost node whose line span contains it,
+so editor integrations can anchor queries to the cursor. The file is the
+graph's repository-relative path; absolute paths under a configured
+repository are converted. --all lists every enclosing node, outermost first.
+
+Examples:
+  codeeagle at internal/indexer/indexer.go:240
+  codeeagle at "$PWD/main.go:12" --type Function,Method --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, lineStr, ok := cutLast(args[0], ":")
+			line, err := strconv.Atoi(lineStr)
+			if !ok || err != nil || line <= 0 {
+				return fmt.Errorf("position must be <file>:<line>, got %q", args[0])
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if filepath.IsAbs(file) {
+				var repoPaths []string
+				for _, repo := range cfg.Repositories {
+					if abs, err := filepath.Abs(repo.Path); err == nil {
+						repoPaths = append(repoPaths, abs)
+					}
+				}
+				file = relativePath(file, repoPaths)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var types []graph.NodeType
+			for _, t := range nodeTypes {
+				types = append(types, graph.NodeType(t))
+			}
+			nodes, err := graph.NodesAt(ctx(cmd), store, file, line, types...)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(nodes) == 0 {
+				if jsonOut {
+					fmt.Fprintln(out, "null")
+					return nil
+				}
+				return fmt.Errorf("no symbol at %s:%d", file, line)
+			}
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if all {
+					return enc.Encode(nodes)
+				}
+				return enc.Encode(nodes[0])
+			}
+			if !all {
+				nodes = nodes[:1]
+			}
+			writeEnclosing(out, nodes)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&nodeTypes, "type", nil, "only consider these node types (e.g. Function,Method)")
+	cmd.Flags().BoolVar(&all, "all", false, "list every enclosing node, outermost first")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// writeEnclosing prints nodes given innermost first as an indented chain,
+// outermost first.
+func writeEnclosing(out io.Writer, nodes []*graph.Node) {
+	for i := len(nodes) - 1; i >= 0; i-- {
+		n := nodes[i]
+		span := strconv.Itoa(n.Line)
+		if n.EndLine > n.Line {
+			span += "-" + strconv.Itoa(n.EndLine)
+		}
+		name := n.Name
+		if n.Signature != "" {
+			name = n.Signature
+		}
+		indent := strings.Repeat("  ", len(nodes)-1-i)
+		fmt.Fprintf(out, "%s%-12s  %s  %s:%s\n", indent, n.Type, name, n.FilePath, span)
+	}
+}
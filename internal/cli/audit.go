@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/osv"
+)
+
+func newAuditCmd() *cobra.Command {
+	var (
+		dumpPath      string
+		apiURL        string
+		jsonOut       bool
+		reachableOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Find known vulnerabilities in manifest dependencies (OSV)",
+		Long: `Look up every manifest dependency version (go.mod, package.json,
+pyproject.toml, requirements.txt, Cargo.toml, pom.xml, build.gradle) in the OSV database and record the
+results in the graph: a Vulnerability node per advisory, with Affects
+edges to the dependency and to the services that declare it.
+
+By default the public OSV API (api.osv.dev) is queried; only package
+names and versions are sent. For offline use, pass --osv-dump with an
+OSV export (a per-ecosystem all.zip, or a directory of OSV JSON records).
+
+Findings are ranked by reachability: advisories for packages that indexed
+code actually imports come first, then by severity. Run 'codeeagle sync'
+first so imports are linked to manifest dependencies. Re-running replaces
+the Vulnerability nodes of the previous audit.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var src osv.Source
+			if dumpPath != "" {
+				dump, err := osv.LoadDump(dumpPath)
+				if err != nil {
+					return err
+				}
+				src = dump
+			} else {
+				src = osv.NewClient(apiURL)
+			}
+
+			res, err := osv.Audit(ctx(cmd), store, src)
+			if err != nil {
+				return err
+			}
+			if reachableOnly {
+				var kept []osv.Finding
+				for _, f := range res.Findings {
+					if f.Reachable() {
+						kept = append(kept, f)
+					}
+				}
+				res.Findings = kept
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				if res.Findings == nil {
+					res.Findings = []osv.Finding{}
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(res)
+			}
+			writeAuditResult(out, res)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dumpPath, "osv-dump", "", "read advisories from a local OSV export (zip or directory) instead of the API")
+	cmd.Flags().StringVar(&apiURL, "api-url", osv.DefaultAPIURL, "OSV API base URL")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&reachableOnly, "reachable", false, "only report vulnerable packages that indexed code imports")
+
+	return cmd
+}
+
+// writeAuditResult renders the ranked findings as text.
+func writeAuditResult(w io.Writer, res *osv.Result) {
+	fmt.Fprintf(w, "Checked %d package version(s)", res.Packages)
+	if res.Skipped > 0 {
+		fmt.Fprintf(w, " (%d dependencies skipped: no concrete version or unsupported ecosystem)", res.Skipped)
+	}
+	fmt.Fprintln(w)
+
+	if len(res.Findings) == 0 {
+		fmt.Fprintln(w, "No known vulnerabilities.")
+		return
+	}
+
+	reachable := 0
+	for _, f := range res.Findings {
+		if f.Reachable() {
+			reachable++
+		}
+	}
+	fmt.Fprintf(w, "%d vulnerability finding(s), %d in imported packages\n\n", len(res.Findings), reachable)
+
+	for _, f := range res.Findings {
+		fmt.Fprintf(w, "[%s] %s  %s@%s (%s)\n", strings.ToUpper(f.Severity), f.ID, f.Package, f.Version, f.Ecosystem)
+		if f.Summary != "" {
+			fmt.Fprintf(w, "  %s\n", f.Summary)
+		}
+		fmt.Fprintf(w, "  Declared in: %s", f.Manifest)
+		if len(f.Services) > 0 {
+			fmt.Fprintf(w, " (service %s)", strings.Join(f.Services, ", "))
+		}
+		fmt.Fprintln(w)
+		if f.FixedIn != "" {
+			fmt.Fprintf(w, "  Fixed in: %s\n", f.FixedIn)
+		}
+		if f.Reachable() {
+			fmt.Fprintf(w, "  Imported by %d file(s): %s\n", len(f.ImportedBy), joinLimited(f.ImportedBy, 3))
+		} else {
+			fmt.Fprintln(w, "  Not imported by indexed code")
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// joinLimited joins up to n items, noting how many were left out.
+func joinLimited(items []string, n int) string {
+	if len(items) <= n {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s, ... and %d more", strings.Join(items[:n], ", "), len(items)-n)
+}
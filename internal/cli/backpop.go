@@ -80,6 +80,62 @@ Use --dry-run to preview the migration without writing changes.`,
 	return cmd
 }
 
+func newBackpopIDsCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "backpop-ids",
+		Short: "Migrate edge IDs in the graph DB to the current ID scheme",
+		Long: `Backpop-ids rewrites edges whose IDs were generated by the version 1 ID
+scheme (which reused node ID hashing for edges) to the current edge IDs, in
+all branches, so relinking and re-indexing don't leave duplicate edges.
+
+Nested symbols (e.g. Java inner classes) get scoped node IDs on the next
+full re-index: run 'codeeagle sync --full' afterwards.
+
+Use --dry-run to preview the migration without writing changes.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			resolvedDBPath := cfg.ResolveDBPath(dbPath)
+			if resolvedDBPath == "" {
+				return fmt.Errorf("no graph database path; run 'codeeagle init' or use --db-path")
+			}
+
+			store, err := embedded.NewBranchStore(resolvedDBPath, "default", []string{"default"})
+			if err != nil {
+				return fmt.Errorf("open graph store: %w", err)
+			}
+			defer store.Close()
+
+			out := cmd.OutOrStdout()
+
+			if dryRun {
+				fmt.Fprintln(out, "DRY RUN: no changes will be written")
+			}
+
+			result, err := store.MigrateEdgeIDs(context.Background(), dryRun)
+			if err != nil {
+				return fmt.Errorf("migration failed: %w", err)
+			}
+
+			fmt.Fprintf(out, "\nMigration %s:\n", statusLabel(dryRun))
+			fmt.Fprintf(out, "  Branches found:  %d (%v)\n", len(result.BranchesFound), result.BranchesFound)
+			fmt.Fprintf(out, "  Edges scanned:   %d\n", result.EdgesScanned)
+			fmt.Fprintf(out, "  Edges remapped:  %d\n", result.EdgesRemapped)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would change without writing")
+
+	return cmd
+}
+
 func statusLabel(dryRun bool) string {
 	if dryRun {
 		return "preview"
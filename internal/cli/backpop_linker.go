@@ -3,24 +3,54 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
 	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+	"github.com/imyousuf/CodeEagle/pkg/llm"
 )
 
 func newBackpopCmd() *cobra.Command {
-	var allPhases bool
+	var (
+		allPhases  bool
+		phaseNames []string
+		listPhases bool
+		logFormat  string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "backpop",
 		Short: "Run linker phases on an already-indexed graph",
 		Long: `Backpop runs linker phases on an existing graph database without re-indexing.
 
-By default only the new phases (cross-file implements + test coverage) are run.
-Use --all to run all linker phases.`,
+By default only the new phases (cross-file implements, dependency injection,
+test coverage, calls, and environment variable config) are run.
+Use --all to run all linker phases (as selected by the linker.phases and
+linker.disable config), or --phases to run specific ones in dependency order.
+--list prints the registered phases.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ro, err := redirectOutput(cmd, logFormat)
+			if err != nil {
+				return err
+			}
+			defer ro.Flush()
+
+			if listPhases {
+				for _, spec := range linker.RegisteredPhases() {
+					line := spec.Name
+					if len(spec.After) > 0 {
+						line += " (after " + strings.Join(spec.After, ", ") + ")"
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), line)
+				}
+				return nil
+			}
+
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("load config: %w", err)
@@ -38,15 +68,25 @@ Use --all to run all linker phases.`,
 				fmt.Fprintf(out, format+"\n", args...)
 			}
 
-			lnk := linker.NewLinker(store, nil, logFn, verbose)
+			lnk := newLinker(cfg, store, nil, logFn, verbose)
+			lnk.SetProgress(ro.LinkProgress)
 
 			var phases []linker.Phase
-			if allPhases {
-				phases = lnk.Phases()
+			switch {
+			case len(phaseNames) > 0:
+				lnk.SetPhaseSelection(phaseNames, nil)
+				if phases, err = lnk.SelectedPhases(); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Running linker phases %s...\n", strings.Join(phaseNames, ", "))
+			case allPhases:
+				if phases, err = lnk.SelectedPhases(); err != nil {
+					return err
+				}
 				fmt.Fprintln(out, "Running all linker phases...")
-			} else {
+			default:
 				phases = lnk.NewPhases()
-				fmt.Fprintln(out, "Running new linker phases (implements + tests)...")
+				fmt.Fprintln(out, "Running new linker phases (implements + injection + tests + calls + config)...")
 			}
 
 			results, err := lnk.RunPhases(context.Background(), phases)
@@ -57,17 +97,52 @@ Use --all to run all linker phases.`,
 			fmt.Fprintln(out, "\nResults:")
 			total := 0
 			for _, phase := range phases {
-				count := results[phase.Name]
-				fmt.Fprintf(out, "  %-15s %d linked\n", phase.Name+":", count)
-				total += count
+				r := results[phase.Name]
+				fmt.Fprintf(out, "  %-15s %d linked (%s)\n", phase.Name+":", r.Linked, r.Elapsed.Round(time.Millisecond))
+				total += r.Linked
 			}
 			fmt.Fprintf(out, "  %-15s %d\n", "total:", total)
+			ro.Event("link complete", "phases", len(phases), "linked", total)
 
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&allPhases, "all", false, "run all linker phases (not just new ones)")
+	cmd.Flags().StringSliceVar(&phaseNames, "phases", nil, "run only these linker phases (comma-separated; see --list)")
+	cmd.Flags().BoolVar(&listPhases, "list", false, "list the registered linker phases and exit")
+	addLogFormatFlag(cmd, &logFormat)
 
 	return cmd
 }
+
+// newLinker creates a linker honoring the declared service boundaries.
+func newLinker(cfg *config.Config, store graph.Store, llmClient llm.Client, logFn func(string, ...any), verbose bool) *linker.Linker {
+	lnk := linker.NewLinker(store, llmClient, logFn, verbose)
+	lnk.SetPhaseSelection(cfg.Linker.Phases, cfg.Linker.Disable)
+	lnk.SetParallelism(cfg.Linker.Parallelism)
+	lnk.SetLLMLimits(linker.LLMLimits{
+		Concurrency: cfg.Linker.LLM.Concurrency,
+		MaxRetries:  cfg.Linker.LLM.MaxRetries,
+		TokenBudget: cfg.Linker.LLM.TokenBudget,
+	})
+	if defs := cfg.Services.Definitions; len(defs) > 0 || cfg.Services.OnlyDeclared {
+		services := make([]linker.ServiceDefinition, len(defs))
+		for i, d := range defs {
+			services[i] = linker.ServiceDefinition{Name: d.Name, Roots: d.Roots, Kind: d.Kind, Hosts: d.Hosts}
+		}
+		lnk.SetServiceMap(linker.NewServiceMap(services, cfg.Services.OnlyDeclared))
+	}
+	if dc := cfg.DataClassification; len(dc.Fields) > 0 || dc.Depth > 0 {
+		rules := make([]linker.DataRule, len(dc.Fields))
+		for i, f := range dc.Fields {
+			class := f.Class
+			if class == "" {
+				class = parser.DataClassPII
+			}
+			rules[i] = linker.DataRule{Pattern: f.Pattern, Class: class}
+		}
+		lnk.SetDataClassification(rules, dc.Depth)
+	}
+	return lnk
+}
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+// Entries of a backup archive, a gzipped tar written in this order.
+const (
+	backupManifestEntry  = "manifest.json"
+	backupGraphEntry     = "graph.badger"
+	backupSyncStateEntry = "sync.state"
+)
+
+// backupFormat identifies a backup archive's manifest.
+const backupFormat = "codeeagle-backup/1"
+
+// backupManifest describes a backup archive.
+type backupManifest struct {
+	Format        string       `json:"format"`
+	CreatedAt     time.Time    `json:"created_at"`
+	Version       string       `json:"codeeagle_version"`
+	SchemaVersion int          `json:"schema_version"`
+	Repositories  []backupRepo `json:"repositories"`
+	Namespaces    []string     `json:"namespaces,omitempty"`
+	Branches      []string     `json:"branches"`
+}
+
+// backupRepo records the state of an indexed repository at backup time.
+type backupRepo struct {
+	Path   string `json:"path"`
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <file>",
+		Short: "Write a consistent snapshot of the graph database to an archive",
+		Long: `Write the whole graph database (every namespace, branch and snapshot) to a
+gzipped archive, as of one point in time, for moving a graph to another
+machine or archiving it. The archive records the schema version, the
+branch and commit of each configured repository, and the sync state, so
+'codeeagle restore' followed by 'codeeagle sync' only indexes what changed
+since.
+
+The backup can be taken while sync, watch or the daemon run.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			manifest, err := newBackupManifest(ctx(cmd), cfg, store)
+			if err != nil {
+				return err
+			}
+			syncState, err := os.ReadFile(filepath.Join(cfg.ConfigDir, "sync.state"))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("read sync state: %w", err)
+			}
+
+			// Write next to the target and rename, so a failed backup never
+			// leaves a truncated archive under the requested name.
+			path := args[0]
+			tmp, err := os.CreateTemp(filepath.Dir(path), ".codeeagle-backup-*")
+			if err != nil {
+				return fmt.Errorf("create backup: %w", err)
+			}
+			defer os.Remove(tmp.Name())
+			if err := writeBackup(tmp, store, manifest, syncState); err != nil {
+				tmp.Close()
+				return err
+			}
+			if err := tmp.Close(); err != nil {
+				return fmt.Errorf("write backup: %w", err)
+			}
+			if err := os.Rename(tmp.Name(), path); err != nil {
+				return fmt.Errorf("write backup: %w", err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("stat backup: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Backed up the graph (schema v%d, %d branches) to %s (%s)\n",
+				manifest.SchemaVersion, len(manifest.Branches), path, formatBytes(info.Size()))
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newRestoreCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Replace the graph database with a backup archive",
+		Long: `Replace the contents of the graph database with an archive written by
+'codeeagle backup', including every namespace and branch it holds, and the
+sync state. Archives of an older schema version are upgraded; archives of
+a newer one need a newer codeeagle.
+
+A database that already holds a graph is only replaced with --force. Stop
+'codeeagle watch' and the daemon first: the store must not be in use.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("open backup: %w", err)
+			}
+			defer f.Close()
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if !force {
+				stats, err := store.Stats(ctx(cmd))
+				if err != nil {
+					return fmt.Errorf("get stats: %w", err)
+				}
+				if stats.NodeCount > 0 {
+					return fmt.Errorf("graph database is not empty (%d nodes); use --force to replace it", stats.NodeCount)
+				}
+			}
+
+			manifest, syncState, err := readBackup(f, store)
+			if err != nil {
+				return err
+			}
+			if syncState != nil && cfg.ConfigDir != "" {
+				if err := os.WriteFile(filepath.Join(cfg.ConfigDir, "sync.state"), syncState, 0644); err != nil {
+					return fmt.Errorf("write sync state: %w", err)
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Restored the graph backed up %s (codeeagle %s, schema v%d, %d branches)\n",
+				manifest.CreatedAt.Local().Format(time.RFC3339), manifest.Version, manifest.SchemaVersion, len(manifest.Branches))
+			for _, r := range manifest.Repositories {
+				fmt.Fprintf(out, "  %s at %s %s\n", r.Path, r.Branch, shortCommit(r.Commit))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "replace a graph database that is not empty")
+
+	return cmd
+}
+
+// newBackupManifest describes a backup of store taken now.
+func newBackupManifest(ctx context.Context, cfg *config.Config, store *embedded.BranchStore) (*backupManifest, error) {
+	version, err := store.SchemaVersion()
+	if err != nil {
+		return nil, fmt.Errorf("read schema version: %w", err)
+	}
+	m := &backupManifest{
+		Format:        backupFormat,
+		CreatedAt:     time.Now().UTC(),
+		Version:       Version,
+		SchemaVersion: version,
+		Repositories:  []backupRepo{},
+	}
+	if m.Branches, err = store.ListBranches(); err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	if m.Namespaces, err = store.ListNamespaces(ctx); err != nil {
+		return nil, fmt.Errorf("list namespaces: %w", err)
+	}
+	for _, repo := range cfg.Repositories {
+		r := backupRepo{Path: repo.Path}
+		r.Branch, _ = gitutil.GetCurrentBranch(repo.Path)
+		r.Commit, _ = gitutil.GetCurrentHEAD(repo.Path)
+		m.Repositories = append(m.Repositories, r)
+	}
+	return m, nil
+}
+
+// writeBackup writes a backup archive of store to w: the manifest, the
+// DB and, when not nil, the sync state.
+func writeBackup(w io.Writer, store *embedded.BranchStore, manifest *backupManifest, syncState []byte) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal backup manifest: %w", err)
+	}
+	if err := writeTarEntry(tw, backupManifestEntry, data); err != nil {
+		return err
+	}
+
+	// The tar header needs the entry size up front, so the DB backup is
+	// staged in a temporary file.
+	staged, err := os.CreateTemp("", "codeeagle-graph-*")
+	if err != nil {
+		return fmt.Errorf("stage graph backup: %w", err)
+	}
+	defer os.Remove(staged.Name())
+	defer staged.Close()
+	if err := store.Backup(staged); err != nil {
+		return err
+	}
+	size, err := staged.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("stage graph backup: %w", err)
+	}
+	if _, err := staged.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("stage graph backup: %w", err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: backupGraphEntry, Mode: 0644, Size: size, ModTime: manifest.CreatedAt}); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	if _, err := io.Copy(tw, staged); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+
+	if syncState != nil {
+		if err := writeTarEntry(tw, backupSyncStateEntry, syncState); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	return nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	return nil
+}
+
+// readBackup restores the backup archive read from r into store. It
+// returns the archive's manifest and sync state (nil when it has none).
+// The manifest is checked before the DB is touched.
+func readBackup(r io.Reader, store *embedded.BranchStore) (*backupManifest, []byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read backup: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	var (
+		manifest  *backupManifest
+		syncState []byte
+		restored  bool
+	)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read backup: %w", err)
+		}
+		switch hdr.Name {
+		case backupManifestEntry:
+			var m backupManifest
+			if err := json.NewDecoder(tr).Decode(&m); err != nil {
+				return nil, nil, fmt.Errorf("read backup manifest: %w", err)
+			}
+			if m.Format != backupFormat {
+				return nil, nil, fmt.Errorf("not a codeeagle backup (format %q)", m.Format)
+			}
+			if m.SchemaVersion > embedded.LatestSchemaVersion {
+				return nil, nil, fmt.Errorf("backup schema version %d is newer than %d supported by this build; upgrade codeeagle", m.SchemaVersion, embedded.LatestSchemaVersion)
+			}
+			manifest = &m
+		case backupGraphEntry:
+			if manifest == nil {
+				return nil, nil, fmt.Errorf("read backup: graph before manifest")
+			}
+			if err := store.Restore(tr); err != nil {
+				return nil, nil, err
+			}
+			restored = true
+		case backupSyncStateEntry:
+			if syncState, err = io.ReadAll(tr); err != nil {
+				return nil, nil, fmt.Errorf("read backup sync state: %w", err)
+			}
+		}
+	}
+	if !restored {
+		return nil, nil, fmt.Errorf("read backup: no graph in archive")
+	}
+	return manifest, syncState, nil
+}
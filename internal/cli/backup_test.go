@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestBackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src, err := embedded.NewBranchStore(t.TempDir(), "main", []string{"main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if err := src.AddNode(ctx, &graph.Node{ID: "svc", Type: graph.NodeService, Name: "orders"}); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := &backupManifest{
+		Format:        backupFormat,
+		CreatedAt:     time.Now().UTC(),
+		Version:       "1.2.3",
+		SchemaVersion: embedded.LatestSchemaVersion,
+		Repositories:  []backupRepo{{Path: "/src/orders", Branch: "main", Commit: "abc123"}},
+		Branches:      []string{"main"},
+	}
+	var buf bytes.Buffer
+	if err := writeBackup(&buf, src, manifest, []byte(`{"commit":"abc123"}`)); err != nil {
+		t.Fatalf("writeBackup: %v", err)
+	}
+
+	dst, err := embedded.NewBranchStore(t.TempDir(), "main", []string{"main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	got, syncState, err := readBackup(bytes.NewReader(buf.Bytes()), dst)
+	if err != nil {
+		t.Fatalf("readBackup: %v", err)
+	}
+	if got.Version != "1.2.3" || len(got.Repositories) != 1 || got.Repositories[0].Commit != "abc123" {
+		t.Errorf("manifest = %+v", got)
+	}
+	if string(syncState) != `{"commit":"abc123"}` {
+		t.Errorf("sync state = %q", syncState)
+	}
+	if n, err := dst.GetNode(ctx, "svc"); err != nil || n.Name != "orders" {
+		t.Errorf("restored node = %v, %v", n, err)
+	}
+}
+
+func TestReadBackupRejects(t *testing.T) {
+	archive := func(m backupManifest) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		data, _ := json.Marshal(m)
+		if err := writeTarEntry(tw, backupManifestEntry, data); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeTarEntry(tw, backupGraphEntry, nil); err != nil {
+			t.Fatal(err)
+		}
+		tw.Close()
+		gz.Close()
+		return buf.Bytes()
+	}
+	tests := []struct {
+		name     string
+		manifest backupManifest
+		wantErr  string
+	}{
+		{"foreign format", backupManifest{Format: "other"}, "not a codeeagle backup"},
+		{"newer schema", backupManifest{Format: backupFormat, SchemaVersion: embedded.LatestSchemaVersion + 1}, "upgrade codeeagle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := embedded.NewBranchStore(t.TempDir(), "main", []string{"main"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer store.Close()
+			ctx := context.Background()
+			if err := store.AddNode(ctx, &graph.Node{ID: "keep", Type: graph.NodeService, Name: "keep"}); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err = readBackup(bytes.NewReader(archive(tt.manifest)), store)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("readBackup error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := store.GetNode(ctx, "keep"); err != nil {
+				t.Errorf("store modified after rejected backup: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/bookmark"
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// bookmarksFileName is the bookmark file inside the .CodeEagle dir.
+const bookmarksFileName = "bookmarks.json"
+
+// bookmarksPath returns the bookmark file path for the given config.
+func bookmarksPath(cfg *config.Config) (string, error) {
+	if cfg.ConfigDir == "" {
+		return "", fmt.Errorf("no config directory found; run 'codeeagle init' first")
+	}
+	return filepath.Join(cfg.ConfigDir, bookmarksFileName), nil
+}
+
+func newBookmarkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bookmark",
+		Short: "Manage named bookmarks of graph nodes and saved queries",
+		Long: `Manage named bookmarks stored in .CodeEagle/bookmarks.json.
+
+A bookmark references either a node (by ID or codeeagle:// link) or a saved
+query. Bookmarks can be opened with 'codeeagle open <name>' and shared as
+codeeagle://bookmark/<name> links within the project.`,
+	}
+	cmd.AddCommand(newBookmarkAddCmd())
+	cmd.AddCommand(newBookmarkListCmd())
+	cmd.AddCommand(newBookmarkRemoveCmd())
+	return cmd
+}
+
+func newBookmarkAddCmd() *cobra.Command {
+	var (
+		q       bookmark.Query
+		note    string
+		replace bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <name> [node-id|link]",
+		Short: "Bookmark a node, or save a query with --type/--name/--package/--file/--language",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			path, err := bookmarksPath(cfg)
+			if err != nil {
+				return err
+			}
+
+			bm := &bookmark.Bookmark{Name: args[0], Note: note, Created: time.Now().UTC()}
+			switch {
+			case len(args) == 2 && !q.IsEmpty():
+				return fmt.Errorf("give either a node or query filters, not both")
+			case len(args) == 2:
+				// Resolve the node now so typos are caught and the snapshot is recorded.
+				link, err := parseNodeRef(args[1])
+				if err != nil {
+					return err
+				}
+				store, _, err := openSnapshotStore(cfg, link.Graph)
+				if err != nil {
+					return err
+				}
+				n, err := store.GetNode(ctx(cmd), link.NodeID)
+				store.Close()
+				if err != nil {
+					return fmt.Errorf("node %s: %w", link.NodeID, err)
+				}
+				bm.NodeID = n.ID
+				bm.Graph = link.Graph
+			case q.IsEmpty():
+				return fmt.Errorf("give a node ID or link, or query filters")
+			default:
+				saved := q
+				bm.Query = &saved
+			}
+
+			book, err := bookmark.Load(path)
+			if err != nil {
+				return err
+			}
+			if err := book.Add(bm, replace); err != nil {
+				return err
+			}
+			if err := book.Save(path); err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Bookmarked %s\n", bm.Name)
+			fmt.Fprintf(out, "  %s\n", bm.Link())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&q.Type, "type", "", "saved query: node type")
+	cmd.Flags().StringVar(&q.Name, "name", "", "saved query: name pattern (glob)")
+	cmd.Flags().StringVar(&q.Package, "package", "", "saved query: package name")
+	cmd.Flags().StringVar(&q.File, "file", "", "saved query: file path")
+	cmd.Flags().StringVar(&q.Language, "language", "", "saved query: language")
+	cmd.Flags().StringVar(&note, "note", "", "note to store with the bookmark")
+	cmd.Flags().BoolVar(&replace, "replace", false, "replace an existing bookmark with the same name")
+
+	return cmd
+}
+
+func newBookmarkListCmd() *cobra.Command {
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List bookmarks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			path, err := bookmarksPath(cfg)
+			if err != nil {
+				return err
+			}
+			book, err := bookmark.Load(path)
+			if err != nil {
+				return err
+			}
+			list := book.List()
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(list)
+			}
+			if len(list) == 0 {
+				fmt.Fprintln(out, "No bookmarks.")
+				return nil
+			}
+			for _, bm := range list {
+				fmt.Fprintf(out, "%-24s  %s\n", bm.Name, bm.Link())
+				if bm.Note != "" {
+					fmt.Fprintf(out, "%-24s  %s\n", "", bm.Note)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	return cmd
+}
+
+func newBookmarkRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <name>",
+		Aliases: []string{"remove"},
+		Short:   "Remove a bookmark",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			path, err := bookmarksPath(cfg)
+			if err != nil {
+				return err
+			}
+			book, err := bookmark.Load(path)
+			if err != nil {
+				return err
+			}
+			if err := book.Remove(args[0]); err != nil {
+				return err
+			}
+			if err := book.Save(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed bookmark %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+func newLinkCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "link <node-id>",
+		Short: "Print a shareable codeeagle:// link for a node",
+		Long: `Print a stable codeeagle://node/<id>?graph=<snapshot> link for a node.
+
+The snapshot is the branch graph the node was read from. Paste the link into
+tickets or design docs; 'codeeagle open <link>' resolves it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, currentBranch, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			n, err := store.GetNode(ctx(cmd), args[0])
+			if err != nil {
+				return fmt.Errorf("node %s: %w", args[0], err)
+			}
+			snapshot := currentBranch
+			if src := n.Properties[graph.PropGraphSource]; src != "" {
+				snapshot = src
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), bookmark.NodeURI(n.ID, snapshot))
+			return nil
+		},
+	}
+}
+
+func newOpenCmd() *cobra.Command {
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "open <link|bookmark|node-id>",
+		Short: "Resolve a codeeagle:// link, bookmark, or node ID",
+		Long: `Resolve a deep link and show what it points at:
+
+  codeeagle open codeeagle://node/<id>?graph=main
+  codeeagle open codeeagle://bookmark/checkout-flow
+  codeeagle open checkout-flow
+
+Node links show the node and its location; saved-query links and bookmarks
+run the query against the referenced graph snapshot.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			link, err := resolveLink(cfg, args[0])
+			if err != nil {
+				return err
+			}
+
+			store, _, err := openSnapshotStore(cfg, link.Graph)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var nodes []*graph.Node
+			if link.Kind == bookmark.LinkQuery {
+				nodes, err = store.QueryNodes(ctx(cmd), link.Query.Filter())
+				if err != nil {
+					return fmt.Errorf("query nodes: %w", err)
+				}
+			} else {
+				n, err := store.GetNode(ctx(cmd), link.NodeID)
+				if err != nil {
+					return fmt.Errorf("node %s not found in graph %q: %w", link.NodeID, link.Graph, err)
+				}
+				nodes = []*graph.Node{n}
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if link.Kind == bookmark.LinkQuery {
+					return enc.Encode(nodes)
+				}
+				return enc.Encode(nodes[0])
+			}
+			if link.Kind == bookmark.LinkQuery {
+				if len(nodes) == 0 {
+					fmt.Fprintln(out, "No results found.")
+					return nil
+				}
+				writeNodeTable(out, nodes)
+				return nil
+			}
+			writeNodeDetails(out, nodes[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	return cmd
+}
+
+// resolveLink turns a deep link, bookmark name, or node ID into a node or
+// query link. Bookmark links and names are looked up in the bookmark file.
+func resolveLink(cfg *config.Config, ref string) (bookmark.Link, error) {
+	name := ref
+	if bookmark.IsURI(ref) {
+		link, err := bookmark.ParseURI(ref)
+		if err != nil || link.Kind != bookmark.LinkBookmark {
+			return link, err
+		}
+		name = link.Bookmark
+	}
+	if path, err := bookmarksPath(cfg); err == nil {
+		book, err := bookmark.Load(path)
+		if err != nil {
+			return bookmark.Link{}, err
+		}
+		if bm, err := book.Get(name); err == nil {
+			return bm.Link(), nil
+		}
+	}
+	if bookmark.IsURI(ref) {
+		return bookmark.Link{}, fmt.Errorf("bookmark %q not found", name)
+	}
+	// Not a bookmark: treat it as a node ID.
+	return bookmark.Link{Kind: bookmark.LinkNode, NodeID: ref}, nil
+}
+
+// parseNodeRef parses a node ID or codeeagle://node link.
+func parseNodeRef(ref string) (bookmark.Link, error) {
+	if !bookmark.IsURI(ref) {
+		return bookmark.Link{Kind: bookmark.LinkNode, NodeID: ref}, nil
+	}
+	link, err := bookmark.ParseURI(ref)
+	if err != nil {
+		return link, err
+	}
+	if link.Kind != bookmark.LinkNode {
+		return link, fmt.Errorf("%s is not a node link", ref)
+	}
+	return link, nil
+}
+
+// writeNodeDetails prints a single node's identity and location.
+func writeNodeDetails(out io.Writer, n *graph.Node) {
+	fmt.Fprintf(out, "%s %s\n", n.Type, n.Name)
+	fmt.Fprintf(out, "  ID:        %s\n", n.ID)
+	if loc := nodeLocation(n); loc != "" {
+		fmt.Fprintf(out, "  Location:  %s\n", loc)
+	}
+	if n.Package != "" {
+		fmt.Fprintf(out, "  Package:   %s\n", n.Package)
+	}
+	if n.Language != "" {
+		fmt.Fprintf(out, "  Language:  %s\n", n.Language)
+	}
+	if n.Signature != "" {
+		fmt.Fprintf(out, "  Signature: %s\n", n.Signature)
+	}
+	if n.DocComment != "" {
+		doc, _, _ := strings.Cut(strings.TrimSpace(n.DocComment), "\n")
+		fmt.Fprintf(out, "  Doc:       %s\n", doc)
+	}
+}
@@ -0,0 +1,293 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// callableTypes are the node types that take part in Calls edges.
+var callableTypes = []graph.NodeType{graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction}
+
+// callTree is one symbol in a callers or callees tree.
+type callTree struct {
+	ID            string         `json:"id"`
+	Type          graph.NodeType `json:"type"`
+	Name          string         `json:"name"`
+	QualifiedName string         `json:"qualified_name,omitempty"`
+	FilePath      string         `json:"file_path"`
+	Line          int            `json:"line,omitempty"`
+	// Repeat marks a symbol already expanded elsewhere in the tree
+	// (including recursion); its children are not listed again.
+	Repeat   bool        `json:"repeat,omitempty"`
+	Children []*callTree `json:"children,omitempty"`
+}
+
+func newCallersCmd() *cobra.Command {
+	return newCallTreeCmd(true)
+}
+
+func newCalleesCmd() *cobra.Command {
+	return newCallTreeCmd(false)
+}
+
+// newCallTreeCmd builds the callers command (callers=true) or the callees
+// command.
+func newCallTreeCmd(callers bool) *cobra.Command {
+	var (
+		depth   int
+		jsonOut bool
+	)
+
+	use, short, verb := "callees <symbol>", "Show the transitive call tree of what a function calls", "calls"
+	if callers {
+		use, short, verb = "callers <symbol>", "Show the transitive tree of functions calling a function", "is called by"
+	}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long: fmt.Sprintf(`Resolve a function or method and print, as a tree, what it %s,
+up to --depth levels (0 for no limit). The symbol is a node ID, a name, a
+qualified name (e.g. pkg.Func or Type.Method), or file:line for the
+innermost function containing that line. Symbols already expanded elsewhere
+in the tree (including recursive calls) are marked and not repeated.
+
+Calls edges come from the parsers and the linker; run 'codeeagle sync'
+first so cross-file calls are resolved.`, verb),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			// Call trees reach the same nodes along many paths.
+			cached := graph.NewCachedStore(store, graph.DefaultCacheSize)
+			root, err := resolveCallSymbol(ctx(cmd), cached, args[0])
+			if err != nil {
+				return err
+			}
+			tree, err := buildCallTree(ctx(cmd), cached, root, callers, depth)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(tree)
+			}
+			writeCallTree(out, tree, 0)
+			if len(tree.Children) == 0 {
+				if callers {
+					fmt.Fprintln(out, "\nNo callers found.")
+				} else {
+					fmt.Fprintln(out, "\nNo callees found.")
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&depth, "depth", 3, "maximum tree depth (0 for no limit)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// resolveCallSymbol finds the single function or method spec names: a node
+// ID, file:line, a qualified name (matched as a suffix of QualifiedName or
+// as Type.method), or a plain name.
+func resolveCallSymbol(ctx context.Context, store graph.Store, spec string) (*graph.Node, error) {
+	if n, err := store.GetNode(ctx, spec); err == nil && isCallable(n) {
+		return n, nil
+	}
+
+	if file, lineStr, ok := cutLast(spec, ":"); ok {
+		if line, err := strconv.Atoi(lineStr); err == nil {
+			return callableAtLine(ctx, store, file, line)
+		}
+	}
+
+	name := spec
+	if i := strings.LastIndex(spec, "."); i >= 0 {
+		name = spec[i+1:]
+	}
+	var matches []*graph.Node
+	for _, t := range callableTypes {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: t, NamePattern: name})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		for _, n := range nodes {
+			if n.Name != name {
+				continue
+			}
+			if name == spec || n.QualifiedName == spec || strings.HasSuffix(n.QualifiedName, "."+spec) || callLabel(n) == spec {
+				matches = append(matches, n)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no function or method named %q", spec)
+	case 1:
+		return matches[0], nil
+	default:
+		sort.Slice(matches, func(i, j int) bool { return nodeLocation(matches[i]) < nodeLocation(matches[j]) })
+		var candidates []string
+		for _, n := range matches {
+			candidates = append(candidates, fmt.Sprintf("%s (%s)", callLabel(n), nodeLocation(n)))
+		}
+		return nil, fmt.Errorf("%q is ambiguous; pass a qualified name, file:line, or node ID: %s", spec, strings.Join(candidates, ", "))
+	}
+}
+
+// callableAtLine returns the innermost function or method in file whose
+// line span contains line.
+func callableAtLine(ctx context.Context, store graph.Store, file string, line int) (*graph.Node, error) {
+	n, err := graph.NodeAt(ctx, store, file, line, callableTypes...)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, fmt.Errorf("no function or method at %s:%d", file, line)
+	}
+	return n, nil
+}
+
+// buildCallTree expands root's callers (or callees) breadth-first down to
+// depth levels (depth <= 0 for no limit). Each symbol is expanded once;
+// later occurrences are marked Repeat. Calls to unresolved targets are
+// skipped.
+func buildCallTree(ctx context.Context, store graph.Store, root *graph.Node, callers bool, depth int) (*callTree, error) {
+	tree := newCallTree(root)
+	expanded := map[string]bool{root.ID: true}
+	level := []*callTree{tree}
+	for d := 0; len(level) > 0 && (depth <= 0 || d < depth); d++ {
+		var next []*callTree
+		for _, t := range level {
+			nodes, err := callNeighbors(ctx, store, t.ID, callers)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nodes {
+				child := newCallTree(n)
+				if expanded[n.ID] {
+					child.Repeat = true
+				} else {
+					expanded[n.ID] = true
+					next = append(next, child)
+				}
+				t.Children = append(t.Children, child)
+			}
+		}
+		level = next
+	}
+	return tree, nil
+}
+
+// callNeighbors returns the resolved callers or callees of id, sorted by
+// label and location.
+func callNeighbors(ctx context.Context, store graph.Store, id string, callers bool) ([]*graph.Node, error) {
+	filter := graph.EdgeFilter{Type: graph.EdgeCalls, SourceID: id}
+	if callers {
+		filter = graph.EdgeFilter{Type: graph.EdgeCalls, TargetID: id}
+	}
+	edges, err := store.QueryEdges(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("get calls of %s: %w", id, err)
+	}
+	seen := make(map[string]bool)
+	var nodes []*graph.Node
+	for _, e := range edges {
+		other := e.TargetID
+		if callers {
+			other = e.SourceID
+		}
+		if seen[other] {
+			continue
+		}
+		seen[other] = true
+		n, err := store.GetNode(ctx, other)
+		if err != nil {
+			continue // unresolved call target
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if a, b := callLabel(nodes[i]), callLabel(nodes[j]); a != b {
+			return a < b
+		}
+		return nodeLocation(nodes[i]) < nodeLocation(nodes[j])
+	})
+	return nodes, nil
+}
+
+func newCallTree(n *graph.Node) *callTree {
+	return &callTree{
+		ID:            n.ID,
+		Type:          n.Type,
+		Name:          callLabel(n),
+		QualifiedName: n.QualifiedName,
+		FilePath:      n.FilePath,
+		Line:          n.Line,
+	}
+}
+
+// writeCallTree prints the tree indented two spaces per level.
+func writeCallTree(out io.Writer, t *callTree, level int) {
+	loc := t.FilePath
+	if t.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", t.FilePath, t.Line)
+	}
+	suffix := ""
+	if t.Repeat {
+		suffix = " (see above)"
+	}
+	fmt.Fprintf(out, "%s%s  %s%s\n", strings.Repeat("  ", level), t.Name, loc, suffix)
+	for _, c := range t.Children {
+		writeCallTree(out, c, level+1)
+	}
+}
+
+// callLabel names a function, or a method as Type.method.
+func callLabel(n *graph.Node) string {
+	owner := n.Properties["class"]
+	if owner == "" {
+		owner = n.Properties["receiver"]
+	}
+	if owner != "" {
+		return owner + "." + n.Name
+	}
+	return n.Name
+}
+
+func isCallable(n *graph.Node) bool {
+	return slices.Contains(callableTypes, n.Type)
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
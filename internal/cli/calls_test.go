@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func callGraphFixture(t *testing.T) graph.Store {
+	t.Helper()
+	store := newTestGraphStore(t)
+	fn := func(name, file string, line, end int, props map[string]string) *graph.Node {
+		typ := graph.NodeFunction
+		if props != nil {
+			typ = graph.NodeMethod
+		}
+		return &graph.Node{ID: file + "#" + name, Type: typ, Name: name, QualifiedName: "app." + name,
+			FilePath: file, Line: line, EndLine: end, Properties: props}
+	}
+	call := func(from, to string) *graph.Edge {
+		return &graph.Edge{ID: graph.NewEdgeID(graph.EdgeCalls, from, to), Type: graph.EdgeCalls, SourceID: from, TargetID: to}
+	}
+	addTestNodes(t, store,
+		fn("main", "main.go", 1, 5, nil),
+		fn("run", "main.go", 7, 12, nil),
+		fn("Save", "store.go", 3, 9, map[string]string{"receiver": "Repo"}),
+		fn("Save", "cache.go", 3, 9, map[string]string{"receiver": "Cache"}),
+		fn("retry", "main.go", 14, 20, nil),
+	)
+	addTestEdges(t, store,
+		call("main.go#main", "main.go#run"),
+		call("main.go#run", "store.go#Save"),
+		call("main.go#run", "main.go#retry"),
+		call("main.go#retry", "main.go#retry"),
+		call("main.go#retry", "store.go#Save"),
+		call("main.go#run", "missing"),
+	)
+	return store
+}
+
+func TestResolveCallSymbol(t *testing.T) {
+	store := callGraphFixture(t)
+	tests := []struct {
+		spec    string
+		wantID  string
+		wantErr string
+	}{
+		{"run", "main.go#run", ""},
+		{"app.run", "main.go#run", ""},
+		{"Repo.Save", "store.go#Save", ""},
+		{"main.go:9", "main.go#run", ""},
+		{"cache.go#Save", "cache.go#Save", ""},
+		{"Save", "", "ambiguous"},
+		{"main.go:6", "", "no function or method at"},
+		{"nope", "", "no function or method named"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			n, err := resolveCallSymbol(context.Background(), store, tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.ID != tt.wantID {
+				t.Errorf("resolved %s, want %s", n.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestBuildCallTree(t *testing.T) {
+	ctx := context.Background()
+	store := callGraphFixture(t)
+	root, err := store.GetNode(ctx, "main.go#main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree, err := buildCallTree(ctx, store, root, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	writeCallTree(&buf, tree, 0)
+	want := `main  main.go:1
+  run  main.go:7
+    Repo.Save  store.go:3
+    retry  main.go:14
+      Repo.Save  store.go:3 (see above)
+      retry  main.go:14 (see above)
+`
+	if buf.String() != want {
+		t.Errorf("callees tree:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	tree, err = buildCallTree(ctx, store, root, false, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Children) != 1 || len(tree.Children[0].Children) != 0 {
+		t.Errorf("depth 1 tree = %+v, want main -> run only", tree)
+	}
+
+	save, err := store.GetNode(ctx, "store.go#Save")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err = buildCallTree(ctx, store, save, true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range tree.Children {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "retry,run" {
+		t.Errorf("callers of Repo.Save = %s, want retry,run", got)
+	}
+}
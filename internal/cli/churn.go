@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/churn"
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func newChurnCmd() *cobra.Command {
+	var (
+		since       string
+		noFunctions bool
+		jsonOut     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "churn",
+		Short: "Record git change history on files and functions",
+		Long: `Read the git history of each configured repository and record it on the
+graph as node properties:
+
+  churn_commits   number of commits that changed the file or function
+  churn_authors   number of distinct authors of those commits
+  churn_lines     lines added and deleted (files only)
+  last_modified   date of the latest of those commits (YYYY-MM-DD)
+
+Function and method history comes from git blame: a commit counts when it
+last changed at least one of the function's current lines. Use
+--no-functions to skip blame on large repositories.
+
+Each run replaces the previous history, so --since sets the window:
+
+  codeeagle churn --since 6.months.ago
+
+Then rank risky code with 'codeeagle hotspots'. Re-run after re-indexing,
+which resets node properties.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if len(cfg.Repositories) == 0 {
+				return fmt.Errorf("no repositories configured")
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			out := cmd.OutOrStdout()
+			results := make(map[string]*churn.Result, len(cfg.Repositories))
+			for _, repo := range cfg.Repositories {
+				res, err := churn.Ingest(ctx(cmd), store, repo.Path, churn.Options{Since: since, SkipFunctions: noFunctions})
+				if err != nil {
+					return fmt.Errorf("%s: %w", repo.Path, err)
+				}
+				results[repo.Path] = res
+				if !jsonOut {
+					fmt.Fprintf(out, "%s: %d commit(s), %d file(s) and %d function(s) changed\n",
+						repo.Path, res.Commits, res.Files, res.Functions)
+				}
+			}
+
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(results)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", `only count commits after this date ("2024-01-01", "6.months.ago")`)
+	cmd.Flags().BoolVar(&noFunctions, "no-functions", false, "record file history only, without git blame")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func newHotspotsCmd() *cobra.Command {
+	var (
+		level   string
+		limit   int
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "hotspots",
+		Short: "Rank risky code by churn, complexity and fan-in",
+		Long: `List the files or functions most likely to harbour bugs: code that changes
+often, is complex, and is called from many places. Each is scored
+
+  commits × cyclomatic complexity × log2(2 + fan-in)
+
+where commits come from 'codeeagle churn' (run it first), complexity is
+computed from the current source, and fan-in counts distinct callers (for
+files, distinct calling files).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if level != string(churn.LevelFile) && level != string(churn.LevelFunction) {
+				return fmt.Errorf("invalid --level %q: must be file or function", level)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			roots := make([]string, 0, len(cfg.Repositories))
+			for _, repo := range cfg.Repositories {
+				roots = append(roots, repo.Path)
+			}
+			spots, err := churn.Hotspots(ctx(cmd), store, churn.HotspotOptions{
+				Level:     churn.Level(level),
+				RepoRoots: roots,
+				Limit:     limit,
+			})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(spots)
+			}
+			writeHotspots(out, spots)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&level, "level", "file", "rank files or functions: file, function")
+	cmd.Flags().IntVar(&limit, "limit", 20, "max hotspots to list (0 for all)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func writeHotspots(out io.Writer, spots []churn.Hotspot) {
+	fmt.Fprintf(out, "%-8s %7s %7s %10s %6s  %-10s  %s\n", "SCORE", "COMMITS", "AUTHORS", "COMPLEXITY", "FAN-IN", "MODIFIED", "LOCATION")
+	for _, s := range spots {
+		loc := s.FilePath
+		if s.Type != graph.NodeFile && s.Type != graph.NodeTestFile {
+			loc = fmt.Sprintf("%s (%s:%d)", s.Name, s.FilePath, s.Line)
+		}
+		fmt.Fprintf(out, "%-8.1f %7d %7d %10.0f %6d  %-10s  %s\n",
+			s.Score, s.Commits, s.Authors, s.Complexity, s.FanIn, s.LastModified, loc)
+	}
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/compact"
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+// compactReport is the outcome of 'codeeagle compact'.
+type compactReport struct {
+	DryRun           bool           `json:"dry_run"`
+	Dependencies     compact.Result `json:"dependencies"`
+	OrphanedDeps     int            `json:"orphaned_dependencies"`
+	PrunedSnapshots  []string       `json:"pruned_snapshots"`
+	SizeBeforeBytes  int64          `json:"size_before_bytes"`
+	SizeAfterBytes   int64          `json:"size_after_bytes,omitempty"`
+	CompactedStorage bool           `json:"compacted_storage"`
+}
+
+func newCompactCmd() *cobra.Command {
+	var (
+		dryRun        bool
+		keepSnapshots int
+		jsonOut       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compact",
+		Short: "Shrink the graph store: merge duplicate dependencies, prune old snapshots, reclaim disk space",
+		Long: `Reduce the size of the graph database in three steps:
+
+  1. Delete all but the newest --keep-snapshots graph snapshots
+     ('codeeagle snapshot'), ordered by the date of their commit in the first
+     repository. Snapshots of commits the repository no longer has count as
+     the oldest.
+  2. Merge Dependency nodes (imports and other references) of the same file
+     that are identical apart from their ID and line, as left behind by
+     earlier ID schemes, moving their edges onto the node kept, and delete
+     the shared dependency nodes of packages no file imports any more and
+     the placeholder nodes of external types nothing refers to. Every
+     branch of the namespace is compacted.
+  3. Compact the on-disk representation, reclaiming the space of deleted
+     and overwritten data.
+
+Stop 'codeeagle watch' and the daemon first: the store must not be in use.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			// Measure with the store closed: an open store preallocates its
+			// value log and memtable files.
+			path := cfg.ResolveDBPath(dbPath)
+			report := compactReport{DryRun: dryRun}
+			if report.SizeBeforeBytes, err = dirSize(path); err != nil {
+				return fmt.Errorf("measure store: %w", err)
+			}
+			if err := runCompact(cmd, cfg, keepSnapshots, &report); err != nil {
+				return err
+			}
+			if !dryRun {
+				if report.SizeAfterBytes, err = dirSize(path); err != nil {
+					return fmt.Errorf("measure store: %w", err)
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			verb := "Removed"
+			if dryRun {
+				verb = "Would remove"
+			}
+			fmt.Fprintf(out, "%s %d duplicate dependency nodes in %d groups (%d edges moved)\n",
+				verb, report.Dependencies.NodesRemoved, report.Dependencies.Groups, report.Dependencies.EdgesMoved)
+			fmt.Fprintf(out, "%s %d dependencies and external types nothing refers to\n", verb, report.OrphanedDeps)
+			fmt.Fprintf(out, "%s %d superseded snapshots", verb, len(report.PrunedSnapshots))
+			if len(report.PrunedSnapshots) > 0 {
+				short := make([]string, len(report.PrunedSnapshots))
+				for i, s := range report.PrunedSnapshots {
+					short[i] = shortCommit(s)
+				}
+				fmt.Fprintf(out, ": %s", strings.Join(short, ", "))
+			}
+			fmt.Fprintln(out)
+			if dryRun {
+				fmt.Fprintf(out, "Store size: %s\n", formatBytes(report.SizeBeforeBytes))
+			} else {
+				fmt.Fprintf(out, "Store size: %s -> %s\n", formatBytes(report.SizeBeforeBytes), formatBytes(report.SizeAfterBytes))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would be removed without changing the store")
+	cmd.Flags().IntVar(&keepSnapshots, "keep-snapshots", 20, "number of newest snapshots to keep (negative keeps all)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// runCompact prunes snapshots, merges duplicate dependencies, deletes
+// orphaned ones and, unless report.DryRun is set, compacts the store,
+// recording the outcome in report.
+func runCompact(cmd *cobra.Command, cfg *config.Config, keepSnapshots int, report *compactReport) error {
+	store, _, err := openBranchStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var pruned []string
+	if keepSnapshots >= 0 && len(cfg.Repositories) > 0 {
+		if pruned, err = supersededSnapshots(store, cfg.Repositories[0].Path, keepSnapshots); err != nil {
+			return err
+		}
+		for _, s := range pruned {
+			if !report.DryRun {
+				if err := store.DeleteByBranch(s); err != nil {
+					return fmt.Errorf("delete snapshot %s: %w", s, err)
+				}
+			}
+			report.PrunedSnapshots = append(report.PrunedSnapshots, strings.TrimPrefix(s, snapshotBranchPrefix))
+		}
+	}
+
+	branches, err := store.ListBranches()
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	sort.Strings(branches)
+	for _, b := range branches {
+		if report.DryRun && slices.Contains(pruned, b) {
+			continue
+		}
+		view := store.WithBranch(b)
+		res, err := compact.DedupeDependencies(ctx(cmd), view, report.DryRun)
+		if err != nil {
+			return fmt.Errorf("branch %s: %w", b, err)
+		}
+		report.Dependencies.Groups += res.Groups
+		report.Dependencies.NodesRemoved += res.NodesRemoved
+		report.Dependencies.EdgesMoved += res.EdgesMoved
+		orphaned, err := compact.PruneDependencies(ctx(cmd), view, report.DryRun)
+		if err != nil {
+			return fmt.Errorf("branch %s: %w", b, err)
+		}
+		report.OrphanedDeps += orphaned
+	}
+
+	if report.DryRun {
+		return nil
+	}
+	if err := store.Compact(); err != nil {
+		return fmt.Errorf("compact store: %w", err)
+	}
+	report.CompactedStorage = true
+	return nil
+}
+
+// supersededSnapshots returns the snapshot branches beyond the keep newest,
+// ordered by commit date in repoPath; unknown commits count as oldest.
+func supersededSnapshots(store *embedded.BranchStore, repoPath string, keep int) ([]string, error) {
+	snapshots, err := listSnapshots(store)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) <= keep {
+		return nil, nil
+	}
+	dates := make(map[string]time.Time, len(snapshots))
+	for _, s := range snapshots {
+		if t, err := gitutil.GetCommitTime(repoPath, strings.TrimPrefix(s, snapshotBranchPrefix)); err == nil {
+			dates[s] = t
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return dates[snapshots[i]].After(dates[snapshots[j]])
+	})
+	prune := snapshots[keep:]
+	sort.Strings(prune)
+	return prune, nil
+}
+
+// dirSize returns the total size of the files under dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// formatBytes renders a byte count with a binary unit.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -99,9 +99,15 @@ func runConfigView(cmd *cobra.Command, args []string) error {
 	printSection(out, "LLM Configuration")
 	printKV(out, "Provider", cfg.Agents.LLMProvider)
 	printKV(out, "Model", cfg.Agents.Model)
-	if cfg.Agents.LLMProvider == "vertex-ai" {
+	switch cfg.Agents.LLMProvider {
+	case "vertex-ai":
 		printKV(out, "GCP Project", cfg.Agents.Project)
 		printKV(out, "GCP Region", cfg.Agents.Location)
+	case "bedrock":
+		printKV(out, "AWS Region", cfg.Agents.Location)
+	case "azure-openai":
+		printKV(out, "Endpoint", cfg.Agents.BaseURL)
+		printKV(out, "API Version", cfg.Agents.APIVersion)
 	}
 	printKV(out, "Auto-summarize", boolYesNo(cfg.Agents.AutoSummarize))
 	printKV(out, "Auto-link", boolYesNo(cfg.Agents.AutoLink))
@@ -192,12 +198,6 @@ func runConfigEdit(cmd *cobra.Command) error {
 		langOptions[i] = opt
 	}
 
-	providerOptions := []huh.Option[string]{
-		huh.NewOption("Claude Code CLI", "claude-cli"),
-		huh.NewOption("Anthropic API", "anthropic"),
-		huh.NewOption("Vertex AI (GCP)", "vertex-ai"),
-	}
-
 	repoTypeOptions := []huh.Option[string]{
 		huh.NewOption("Single repository", "single"),
 		huh.NewOption("Monorepo", "monorepo"),
@@ -235,7 +235,7 @@ func runConfigEdit(cmd *cobra.Command) error {
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("LLM provider").
-				Options(providerOptions...).
+				Options(llmProviderOptions()...).
 				Value(&llmProvider),
 		).Title("LLM Configuration"),
 
@@ -288,15 +288,7 @@ func runConfigEdit(cmd *cobra.Command) error {
 					if langStr == "" {
 						langStr = "(none)"
 					}
-					providerLabel := llmProvider
-					switch llmProvider {
-					case "claude-cli":
-						providerLabel = "Claude Code CLI"
-					case "anthropic":
-						providerLabel = "Anthropic API"
-					case "vertex-ai":
-						providerLabel = fmt.Sprintf("Vertex AI (%s / %s)", gcpProject, gcpRegion)
-					}
+					providerLabel := llmProviderLabel(llmProvider, gcpProject, gcpRegion)
 					return fmt.Sprintf(
 						"Project:     %s\n"+
 							"Repo type:   %s\n"+
@@ -344,7 +336,10 @@ func runConfigEdit(cmd *cobra.Command) error {
 		cfg.Agents.Location = gcpRegion
 	} else {
 		cfg.Agents.Project = ""
-		cfg.Agents.Location = ""
+		// Bedrock keeps its AWS region in Location.
+		if llmProvider != "bedrock" {
+			cfg.Agents.Location = ""
+		}
 	}
 
 	// Write updated config
@@ -0,0 +1,383 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/vectorstore"
+)
+
+const (
+	// contextCharsPerToken approximates how many characters of source code
+	// make up one LLM token.
+	contextCharsPerToken = 4
+	// contextHopDecay scales the relevance of each hop away from a seed.
+	contextHopDecay = 0.5
+	// contextFallbackLines is how much code to take for a symbol without an
+	// end line.
+	contextFallbackLines = 15
+	// contextMinSnippetTokens is the smallest truncated snippet worth
+	// including when the budget runs low.
+	contextMinSnippetTokens = 64
+)
+
+// contextExpandEdges are the edges followed, both ways, from the seeds.
+var contextExpandEdges = []graph.EdgeType{
+	graph.EdgeCalls, graph.EdgeImplements, graph.EdgeExtends, graph.EdgeProvides,
+	graph.EdgeInjectedInto, graph.EdgeConsumes, graph.EdgeThrows,
+}
+
+// contextItem is one symbol of a context pack with its code.
+type contextItem struct {
+	ID       string         `json:"id"`
+	Type     graph.NodeType `json:"type"`
+	Name     string         `json:"name"`
+	FilePath string         `json:"file_path"`
+	Line     int            `json:"line"`
+	EndLine  int            `json:"end_line"`
+	Score    float64        `json:"score"`
+	// Reason is why the symbol was selected: "search", "file", or the
+	// edge that led to it, e.g. "called by Handle".
+	Reason    string `json:"reason"`
+	Signature string `json:"signature,omitempty"`
+	Code      string `json:"code"`
+	Tokens    int    `json:"tokens"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// contextPack is the result of 'codeeagle context'.
+type contextPack struct {
+	Task   string        `json:"task"`
+	Budget int           `json:"budget"`
+	Tokens int           `json:"tokens"`
+	Items  []contextItem `json:"items"`
+	// Omitted lists the selected symbols left out for lack of budget.
+	Omitted []string `json:"omitted,omitempty"`
+}
+
+// contextCandidate is a symbol considered for a pack.
+type contextCandidate struct {
+	node   *graph.Node
+	score  float64
+	reason string
+}
+
+func newContextCmd() *cobra.Command {
+	var (
+		budget  int
+		limit   int
+		depth   int
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "context <task description|file>",
+		Short: "Build a token-budgeted context bundle for a coding agent",
+		Long: `Select the code most relevant to a task and emit it as a context bundle for
+a coding agent, instead of whole files.
+
+For a task description, the seeds are the best matches of a hybrid search:
+semantic similarity from the vector index when it is built, keyword matches
+from the graph, and graph centrality, as 'codeeagle rag' ranks them. For a
+file, the seeds are the symbols it declares. The graph then expands the
+seeds --depth hops along calls, implementations, DI wiring, API consumption
+and thrown errors, each hop halving the relevance.
+
+Symbols are added best first with their source until --budget tokens
+(estimated at 4 characters a token) are used; the last one is truncated to
+fit. Output is markdown, or JSON with --json.
+
+Examples:
+  codeeagle context "add rate limiting to the login endpoint"
+  codeeagle context internal/store/store.go --budget 4000 --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, currentBranch, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var repoPaths []string
+			for _, repo := range cfg.Repositories {
+				repoPaths = append(repoPaths, repo.Path)
+			}
+
+			task := strings.Join(args, " ")
+			var seeds []contextCandidate
+			if len(args) == 1 {
+				if seeds, err = contextFileSeeds(ctx(cmd), store, args[0], repoPaths); err != nil {
+					return err
+				}
+			}
+			if seeds == nil {
+				vs := openAgentVectorStore(cfg, store, currentBranch)
+				if vs == nil {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Warning: no vector index, selecting by keywords only; run 'codeeagle vectorindex' for semantic search")
+				} else {
+					defer vs.Close()
+				}
+				if seeds, err = contextSearchSeeds(ctx(cmd), store, vs, task, limit); err != nil {
+					return err
+				}
+			}
+			if len(seeds) == 0 {
+				return fmt.Errorf("nothing in the graph matches %q", task)
+			}
+
+			candidates, err := expandContext(ctx(cmd), store, seeds, depth)
+			if err != nil {
+				return err
+			}
+			pack := packContext(ctx(cmd), store, candidates, budget)
+			pack.Task = task
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(pack)
+			}
+			writeContextPack(out, pack)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&budget, "budget", 8000, "token budget of the bundle's code")
+	cmd.Flags().IntVar(&limit, "limit", 10, "number of search results to expand from")
+	cmd.Flags().IntVar(&depth, "depth", 1, "graph hops to expand from the seeds")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	return cmd
+}
+
+// contextFileSeeds returns the symbols declared in arg when it names a
+// file in the graph, or nil when it does not.
+func contextFileSeeds(ctx context.Context, store graph.Store, arg string, repoPaths []string) ([]contextCandidate, error) {
+	info, err := os.Stat(arg)
+	if err != nil || info.IsDir() {
+		return nil, nil
+	}
+	rel := filepath.ToSlash(arg)
+	if abs, err := filepath.Abs(arg); err == nil {
+		rel = filepath.ToSlash(relativePath(abs, repoPaths))
+	}
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{FilePath: rel})
+	if err != nil {
+		return nil, fmt.Errorf("query symbols of %s: %w", rel, err)
+	}
+	var seeds []contextCandidate
+	for _, n := range nodes {
+		if codeNodeTypes[n.Type] && n.Line > 0 {
+			seeds = append(seeds, contextCandidate{node: n, score: 1, reason: "file"})
+		}
+	}
+	return seeds, nil
+}
+
+// contextSearchSeeds returns the best limit code symbols for task by the
+// hybrid ranking of 'codeeagle rag'; vs may be nil for keywords only.
+func contextSearchSeeds(ctx context.Context, store graph.Store, vs *vectorstore.VectorStore, task string, limit int) ([]contextCandidate, error) {
+	var results []vectorstore.SearchResult
+	if vs != nil {
+		found, err := vs.Search(ctx, task, min(limit*10, 200))
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		results = deduplicateResults(found)
+	}
+	keywordNodes, totalKeywords := keywordSearch(ctx, store, task)
+	results, keywordCounts := injectKeywordResults(results, keywordNodes, codeNodeTypes, true, "", "")
+	results = rerankResults(ctx, store, results, keywordCounts, totalKeywords)
+
+	var seeds []contextCandidate
+	for _, r := range results {
+		if len(seeds) >= limit {
+			break
+		}
+		if r.Node != nil && codeNodeTypes[r.Node.Type] && r.Node.Line > 0 {
+			seeds = append(seeds, contextCandidate{node: r.Node, score: r.Score, reason: "search"})
+		}
+	}
+	return seeds, nil
+}
+
+// expandContext adds the code symbols up to depth hops from the seeds,
+// each scored contextHopDecay of the symbol it was reached from, and
+// returns all candidates best first.
+func expandContext(ctx context.Context, store graph.Store, seeds []contextCandidate, depth int) ([]contextCandidate, error) {
+	best := make(map[string]*contextCandidate)
+	var frontier []*contextCandidate
+	for i := range seeds {
+		s := seeds[i]
+		if cur, ok := best[s.node.ID]; !ok || s.score > cur.score {
+			best[s.node.ID] = &s
+			frontier = append(frontier, &s)
+		}
+	}
+
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []*contextCandidate
+		for _, c := range frontier {
+			for _, et := range contextExpandEdges {
+				edges, err := store.GetEdges(ctx, c.node.ID, et)
+				if err != nil {
+					return nil, fmt.Errorf("get %s edges of %s: %w", et, c.node.Name, err)
+				}
+				for _, e := range edges {
+					otherID, reason := e.TargetID, contextEdgeReason(et, true, c.node.Name)
+					if otherID == c.node.ID {
+						otherID, reason = e.SourceID, contextEdgeReason(et, false, c.node.Name)
+					}
+					score := c.score * contextHopDecay
+					if cur, ok := best[otherID]; ok && cur.score >= score {
+						continue
+					}
+					n, err := store.GetNode(ctx, otherID)
+					if err != nil || n == nil || !codeNodeTypes[n.Type] || n.Line <= 0 {
+						continue
+					}
+					cand := &contextCandidate{node: n, score: score, reason: reason}
+					best[otherID] = cand
+					next = append(next, cand)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	candidates := make([]contextCandidate, 0, len(best))
+	for _, c := range best {
+		candidates = append(candidates, *c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].node.ID < candidates[j].node.ID
+	})
+	return candidates, nil
+}
+
+// contextEdgeReason describes how an edge of type et led from the symbol
+// named from to a neighbour: outgoing when the neighbour is its target.
+func contextEdgeReason(et graph.EdgeType, outgoing bool, from string) string {
+	verbs := map[graph.EdgeType][2]string{
+		graph.EdgeCalls:        {"called by", "calls"},
+		graph.EdgeImplements:   {"implemented by", "implements"},
+		graph.EdgeExtends:      {"extended by", "extends"},
+		graph.EdgeProvides:     {"provided by", "provides"},
+		graph.EdgeInjectedInto: {"injected into", "takes injected"},
+		graph.EdgeConsumes:     {"consumed by", "consumes"},
+		graph.EdgeThrows:       {"thrown by", "throws"},
+	}
+	v := verbs[et]
+	if outgoing {
+		return v[0] + " " + from
+	}
+	return v[1] + " " + from
+}
+
+// packContext adds the candidates' code best first until budget tokens
+// are used. A symbol whose lines an included one already holds is
+// skipped; the first that does not fit is truncated when enough budget
+// is left, and the rest are omitted. Code is read through src.
+func packContext(ctx context.Context, src graph.SourceStore, candidates []contextCandidate, budget int) *contextPack {
+	pack := &contextPack{Budget: budget, Items: []contextItem{}}
+	type span struct{ start, end int }
+	included := make(map[string][]span) // file -> included line ranges
+	full := false
+	for _, c := range candidates {
+		n := c.node
+		end := n.EndLine
+		if end < n.Line {
+			end = n.Line + contextFallbackLines - 1
+		}
+		covered := false
+		for _, s := range included[n.FilePath] {
+			covered = covered || (s.start <= n.Line && end <= s.end)
+		}
+		if covered {
+			continue
+		}
+		if full {
+			pack.Omitted = append(pack.Omitted, n.Name)
+			continue
+		}
+
+		var code string
+		var err error
+		if n.EndLine >= n.Line {
+			code, err = src.GetSource(ctx, n.ID)
+		} else {
+			code, err = src.GetSourceLines(ctx, n.FilePath, n.Line, end)
+		}
+		if err != nil || code == "" {
+			continue
+		}
+		item := contextItem{
+			ID: n.ID, Type: n.Type, Name: n.Name, FilePath: n.FilePath, Line: n.Line, EndLine: end,
+			Score: c.score, Reason: c.reason, Signature: n.Signature, Code: code,
+			Tokens: contextTokens(code),
+		}
+		if remaining := budget - pack.Tokens; item.Tokens > remaining {
+			full = true
+			if remaining < contextMinSnippetTokens {
+				pack.Omitted = append(pack.Omitted, n.Name)
+				continue
+			}
+			item.Code = truncateLines(code, remaining*contextCharsPerToken)
+			item.Tokens = contextTokens(item.Code)
+			item.EndLine = n.Line + strings.Count(item.Code, "\n") - 1
+			item.Truncated = true
+		}
+		pack.Items = append(pack.Items, item)
+		pack.Tokens += item.Tokens
+		included[n.FilePath] = append(included[n.FilePath], span{n.Line, end})
+	}
+	return pack
+}
+
+func contextTokens(s string) int {
+	return (len(s) + contextCharsPerToken - 1) / contextCharsPerToken
+}
+
+// truncateLines cuts s to whole lines of at most maxChars characters.
+func truncateLines(s string, maxChars int) string {
+	if len(s) <= maxChars {
+		return s
+	}
+	if i := strings.LastIndexByte(s[:maxChars], '\n'); i >= 0 {
+		return s[:i+1]
+	}
+	return ""
+}
+
+func writeContextPack(w io.Writer, p *contextPack) {
+	fmt.Fprintf(w, "# Context: %s\n\n", p.Task)
+	fmt.Fprintf(w, "%d symbols, ~%d of %d tokens.\n", len(p.Items), p.Tokens, p.Budget)
+	for _, item := range p.Items {
+		fmt.Fprintf(w, "\n## %s %s (%s:%d-%d)\n\n", item.Type, item.Name, item.FilePath, item.Line, item.EndLine)
+		fmt.Fprintf(w, "Selected: %s, relevance %.2f", item.Reason, item.Score)
+		if item.Truncated {
+			fmt.Fprint(w, ", truncated")
+		}
+		fmt.Fprintf(w, "\n\n```%s\n%s```\n", inferLangFromPath(item.FilePath), item.Code)
+	}
+	if len(p.Omitted) > 0 {
+		fmt.Fprintf(w, "\nOmitted for budget: %s\n", strings.Join(p.Omitted, ", "))
+	}
+}
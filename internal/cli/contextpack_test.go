@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestContextPack(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	repo := t.TempDir()
+	src := "package auth\n\n" +
+		"func Login(u string) error {\n\treturn checkRate(u)\n}\n\n" + // lines 3-5
+		"func checkRate(u string) error {\n\treturn nil\n}\n\n" + // lines 7-9
+		"func Unrelated() {}\n" // line 11
+	handler := "package web\n\nfunc HandleLogin() {\n\tauth.Login(\"x\")\n}\n" // lines 3-5
+	for file, content := range map[string]string{"auth/login.go": src, "web/handler.go": handler} {
+		path := filepath.Join(repo, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.SetSourceRoots(repo)
+
+	fn := func(id, file string, line, end int) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeFunction, Name: id, FilePath: file, Line: line, EndLine: end, Language: "go"}
+	}
+	calls := func(from, to string) *graph.Edge {
+		return &graph.Edge{ID: graph.NewEdgeID(graph.EdgeCalls, from, to), Type: graph.EdgeCalls, SourceID: from, TargetID: to}
+	}
+	addTestNodes(t, store,
+		fn("Login", "auth/login.go", 3, 5),
+		fn("checkRate", "auth/login.go", 7, 9),
+		fn("Unrelated", "auth/login.go", 11, 11),
+		fn("HandleLogin", "web/handler.go", 3, 5),
+	)
+	addTestEdges(t, store, calls("Login", "checkRate"), calls("HandleLogin", "Login"))
+
+	seeds, err := contextSearchSeeds(ctx, store, nil, "login flow", 1)
+	if err != nil {
+		t.Fatalf("contextSearchSeeds: %v", err)
+	}
+	if len(seeds) != 1 || seeds[0].node.ID != "Login" {
+		t.Fatalf("seeds = %+v, want Login", seeds)
+	}
+	seeds[0].score = 1
+
+	candidates, err := expandContext(ctx, store, seeds, 1)
+	if err != nil {
+		t.Fatalf("expandContext: %v", err)
+	}
+	var got []string
+	for _, c := range candidates {
+		got = append(got, c.node.ID+": "+c.reason)
+	}
+	want := "Login: search, HandleLogin: calls Login, checkRate: called by Login"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("candidates = %s, want %s", strings.Join(got, ", "), want)
+	}
+
+	tests := []struct {
+		name        string
+		budget      int
+		wantItems   []string
+		wantOmitted []string
+	}{
+		{"everything fits", 1000, []string{"Login", "HandleLogin", "checkRate"}, nil},
+		// HandleLogin does not fit in what Login leaves, too little to
+		// truncate it to, so everything after it is omitted.
+		{"budget runs out", 20, []string{"Login"}, []string{"HandleLogin", "checkRate"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pack := packContext(ctx, store, candidates, tt.budget)
+			var items []string
+			for _, item := range pack.Items {
+				items = append(items, item.Name)
+			}
+			if strings.Join(items, ",") != strings.Join(tt.wantItems, ",") || strings.Join(pack.Omitted, ",") != strings.Join(tt.wantOmitted, ",") {
+				t.Errorf("items = %v, omitted = %v; want %v, %v", items, pack.Omitted, tt.wantItems, tt.wantOmitted)
+			}
+			if pack.Tokens > tt.budget {
+				t.Errorf("tokens = %d, over budget %d", pack.Tokens, tt.budget)
+			}
+		})
+	}
+
+	pack := packContext(ctx, store, candidates, 1000)
+	if pack.Items[0].Code != "func Login(u string) error {\n\treturn checkRate(u)\n}\n" {
+		t.Errorf("Login code = %q", pack.Items[0].Code)
+	}
+	pack.Task = "login flow"
+	var out bytes.Buffer
+	writeContextPack(&out, pack)
+	if !strings.Contains(out.String(), "## Function HandleLogin (web/handler.go:3-5)") || !strings.Contains(out.String(), "```go\nfunc HandleLogin() {") {
+		t.Errorf("markdown =\n%s", out.String())
+	}
+}
+
+func TestTruncateLines(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{"a\nb\n", 10, "a\nb\n"},
+		{"aaa\nbbb\nccc\n", 9, "aaa\nbbb\n"},
+		{"aaaa\n", 3, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateLines(tt.in, tt.max); got != tt.want {
+			t.Errorf("truncateLines(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/agents"
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/daemon"
+)
+
+func newDaemonCmd() *cobra.Command {
+	var (
+		socket      string
+		idleTimeout time.Duration
+		cacheSize   int
+		logFile     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Serve graph queries from a long-running process over a unix socket",
+		Long: `Keep the graph store open and recent results cached, and answer queries
+over a unix socket (default .CodeEagle/daemon.sock) with newline-delimited
+JSON-RPC 2.0, so editor integrations get millisecond responses instead of
+opening the store per query.
+
+Methods:
+  graph/node {id}                       graph/query {NodeFilter fields}
+  graph/edges {id, type}                graph/neighbors {id, type, direction: in|out|both}
+  graph/at {file, line, types}          graph/stats
+  tools/list, tools/call {name, arguments}   (the MCP server's tools)
+  ping, cache/clear, shutdown
+
+The daemon holds the graph store, so sync and watch cannot open it while it
+runs; it exits after --idle-timeout without requests, on 'codeeagle daemon
+stop', or on SIGINT/SIGTERM. Clear the cache after the graph changes.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			path, err := daemonSocket(cfg, socket)
+			if err != nil {
+				return err
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var repoPaths []string
+			for _, repo := range cfg.Repositories {
+				repoPaths = append(repoPaths, repo.Path)
+			}
+			registry := agents.NewRegistry()
+			for _, tool := range agents.NewPlannerTools(agents.NewContextBuilder(store, repoPaths...)) {
+				registry.Register(tool)
+			}
+
+			var logFn func(format string, args ...any)
+			if logFile != "" {
+				f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+				if err != nil {
+					return fmt.Errorf("open log file %s: %w", logFile, err)
+				}
+				defer f.Close()
+				logFn = func(format string, args ...any) {
+					fmt.Fprintf(f, format+"\n", args...)
+				}
+			} else if verbose {
+				logFn = func(format string, args ...any) {
+					fmt.Fprintf(os.Stderr, format+"\n", args...)
+				}
+			}
+
+			server := daemon.NewServer(daemon.Config{
+				Store:       store,
+				Registry:    registry,
+				CacheSize:   cacheSize,
+				IdleTimeout: idleTimeout,
+				Logger:      logFn,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-sigCh
+				cancel()
+			}()
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "codeeagle daemon listening on %s\n", path)
+			return server.ListenAndServe(ctx, path)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&socket, "socket", "", "unix socket path (default .CodeEagle/daemon.sock)")
+	cmd.Flags().DurationVar(&idleTimeout, "idle-timeout", 30*time.Minute, "exit after this long without requests (0 = never)")
+	cmd.Flags().IntVar(&cacheSize, "cache-size", daemon.DefaultCacheSize, "number of query results to cache (negative disables)")
+	cmd.Flags().StringVar(&logFile, "log", "", "path to write a log of handled requests")
+
+	cmd.AddCommand(newDaemonStatusCmd(&socket))
+	cmd.AddCommand(newDaemonStopCmd(&socket))
+
+	return cmd
+}
+
+func newDaemonStatusCmd(socket *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether a daemon is running and its cache statistics",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := callDaemon(*socket, "ping")
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(st)
+		},
+	}
+}
+
+func newDaemonStopCmd(socket *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running daemon",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := callDaemon(*socket, "shutdown"); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Daemon stopped.")
+			return nil
+		},
+	}
+}
+
+// callDaemon sends one request to the configured daemon.
+func callDaemon(socket, method string) (*daemon.Status, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	path, err := daemonSocket(cfg, socket)
+	if err != nil {
+		return nil, err
+	}
+	c, err := daemon.Dial(path)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+	var st daemon.Status
+	if err := c.Call(method, nil, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// daemonSocket returns the --socket flag or the socket in the config
+// directory.
+func daemonSocket(cfg *config.Config, flag string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if cfg.ConfigDir == "" {
+		return "", fmt.Errorf("no .CodeEagle directory; run 'codeeagle init' or pass --socket")
+	}
+	return filepath.Join(cfg.ConfigDir, daemon.SocketFile), nil
+}
@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// dataFlowReport lists the types holding sensitive data and the endpoints
+// and services exposing them.
+type dataFlowReport struct {
+	Types     []sensitiveType `json:"types"`
+	Endpoints []dataEndpoint  `json:"endpoints"`
+	Services  []dataService   `json:"services"`
+}
+
+// sensitiveType is a model, DTO or class with fields tagged with a data class.
+type sensitiveType struct {
+	surfaceEntry
+	Service string   `json:"service"`
+	Classes []string `json:"classes"`
+	// Fields are "field:class" entries.
+	Fields []string `json:"fields"`
+}
+
+// dataEndpoint is an endpoint whose handlers take or return sensitive types.
+type dataEndpoint struct {
+	surfaceEntry
+	Service string   `json:"service"`
+	Classes []string `json:"classes"`
+	Types   []string `json:"types"`
+	// Auth is the endpoint's auth requirement: protected, public or none.
+	Auth string `json:"auth"`
+}
+
+// dataService sums up the sensitive data a service exposes.
+type dataService struct {
+	Service   string   `json:"service"`
+	Classes   []string `json:"classes"`
+	Endpoints int      `json:"endpoints"`
+	// Unauthenticated counts the endpoints exposing data without auth.
+	Unauthenticated int `json:"unauthenticated"`
+}
+
+// dataTypeKinds are the node types the linker tags with data classes.
+var dataTypeKinds = []graph.NodeType{
+	graph.NodeClass, graph.NodeStruct, graph.NodeDTO, graph.NodeDBModel, graph.NodeDomainModel, graph.NodeViewModel,
+}
+
+func newDataFlowCmd() *cobra.Command {
+	var (
+		class   string
+		service string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "data-flow",
+		Short: "Show which endpoints and services expose sensitive data",
+		Long: `List the types holding sensitive data and the endpoints and services that
+expose them. Fields are tagged in code, with annotations (@PII,
+[PersonalData], @Sensitive, @DataClassification("phi")) or Go struct tags
+(pii:"true", classification:"pci"), or by patterns in the config file:
+
+  data_classification:
+    fields:
+      - pattern: "*.ssn"
+      - pattern: "*card_number*"
+        class: pci
+    depth: 3
+
+An endpoint exposes a type when its handler, or a function it calls within
+'depth' calls, takes or returns the type. Endpoints exposing data without
+auth are flagged. Run 'codeeagle sync' first.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			report, err := buildDataFlowReport(ctx(cmd), store, class, service)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			writeDataFlowReport(out, report)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&class, "class", "", "only show this data class (e.g. pii, pci, phi)")
+	cmd.Flags().StringVar(&service, "service", "", "only show this service")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// buildDataFlowReport collects the data classes the linker recorded on
+// types and endpoints, optionally limited to one class and service.
+func buildDataFlowReport(ctx context.Context, store graph.Store, class, service string) (*dataFlowReport, error) {
+	report := &dataFlowReport{Types: []sensitiveType{}, Endpoints: []dataEndpoint{}, Services: []dataService{}}
+	keep := func(n *graph.Node) ([]string, string, bool) {
+		classes := splitList(n.Properties[parser.PropDataClasses])
+		svc := linker.ServiceGroup(n.FilePath)
+		if len(classes) == 0 || (class != "" && !slices.Contains(classes, class)) || (service != "" && svc != service) {
+			return nil, "", false
+		}
+		return classes, svc, true
+	}
+
+	for _, kind := range dataTypeKinds {
+		types, err := store.QueryNodes(ctx, graph.NodeFilter{Type: kind})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", kind, err)
+		}
+		for _, t := range types {
+			classes, svc, ok := keep(t)
+			if !ok {
+				continue
+			}
+			report.Types = append(report.Types, sensitiveType{
+				surfaceEntry: newSurfaceEntry(t, ""),
+				Service:      svc,
+				Classes:      classes,
+				Fields:       splitList(t.Properties[parser.PropSensitiveFields]),
+			})
+		}
+	}
+
+	endpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return nil, fmt.Errorf("query endpoints: %w", err)
+	}
+	services := make(map[string]*dataService)
+	for _, ep := range endpoints {
+		classes, svc, ok := keep(ep)
+		if !ok {
+			continue
+		}
+		auth := ep.Properties[parser.PropAuth]
+		if auth == "" {
+			auth = authNone
+		}
+		report.Endpoints = append(report.Endpoints, dataEndpoint{
+			surfaceEntry: newSurfaceEntry(ep, ""),
+			Service:      svc,
+			Classes:      classes,
+			Types:        splitList(ep.Properties[parser.PropDataTypes]),
+			Auth:         auth,
+		})
+		s, ok := services[svc]
+		if !ok {
+			s = &dataService{Service: svc}
+			services[svc] = s
+		}
+		s.Endpoints++
+		if auth == authNone {
+			s.Unauthenticated++
+		}
+		for _, c := range classes {
+			if !slices.Contains(s.Classes, c) {
+				s.Classes = append(s.Classes, c)
+			}
+		}
+	}
+	for _, s := range services {
+		sort.Strings(s.Classes)
+		report.Services = append(report.Services, *s)
+	}
+
+	sort.Slice(report.Types, func(i, j int) bool {
+		a, b := report.Types[i], report.Types[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Name < b.Name
+	})
+	sort.Slice(report.Endpoints, func(i, j int) bool {
+		a, b := report.Endpoints[i], report.Endpoints[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+	sort.Slice(report.Services, func(i, j int) bool {
+		return report.Services[i].Service < report.Services[j].Service
+	})
+	return report, nil
+}
+
+func writeDataFlowReport(out io.Writer, r *dataFlowReport) {
+	if len(r.Types) == 0 {
+		fmt.Fprintln(out, "No sensitive fields tagged. Annotate fields (@PII, [PersonalData]) or configure data_classification.fields, then run 'codeeagle sync'.")
+		return
+	}
+	fmt.Fprintf(out, "Sensitive types (%d):\n", len(r.Types))
+	for _, t := range r.Types {
+		fmt.Fprintf(out, "  %-30s  %-12s  %-30s  %s\n", t.Name, strings.Join(t.Classes, ","), strings.Join(t.Fields, ","), entryLocation(t.surfaceEntry))
+	}
+
+	fmt.Fprintf(out, "\nEndpoints exposing sensitive data (%d):\n", len(r.Endpoints))
+	for _, e := range r.Endpoints {
+		auth := e.Auth
+		if auth == authNone {
+			auth = "NO AUTH"
+		}
+		fmt.Fprintf(out, "  %-40s  %-12s  %-9s  via %-24s  %s\n", e.Name, strings.Join(e.Classes, ","), auth, strings.Join(e.Types, ","), entryLocation(e.surfaceEntry))
+	}
+
+	if len(r.Services) > 0 {
+		fmt.Fprintln(out, "\nServices:")
+		for _, s := range r.Services {
+			fmt.Fprintf(out, "  %-20s  %-12s  %d endpoints, %d without auth\n", s.Service, strings.Join(s.Classes, ","), s.Endpoints, s.Unauthenticated)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestBuildDataFlowReport(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	addTestNodes(t, store,
+		&graph.Node{ID: "dto", Type: graph.NodeDTO, Name: "UserDto", FilePath: "users/UserDto.java", Line: 3,
+			Properties: map[string]string{parser.PropSensitiveFields: "email:pii,ssn:phi", parser.PropDataClasses: "phi,pii"}},
+		&graph.Node{ID: "card", Type: graph.NodeStruct, Name: "Card", FilePath: "billing/card.go", Line: 5,
+			Properties: map[string]string{parser.PropSensitiveFields: "Number:pci", parser.PropDataClasses: "pci"}},
+		&graph.Node{ID: "plain", Type: graph.NodeClass, Name: "Health", FilePath: "users/Health.java"},
+		&graph.Node{ID: "ep-user", Type: graph.NodeAPIEndpoint, Name: "GET /users/{id}", FilePath: "users/UserController.java", Line: 20,
+			Properties: map[string]string{parser.PropDataClasses: "phi,pii", parser.PropDataTypes: "UserDto", parser.PropAuth: parser.AuthProtected}},
+		&graph.Node{ID: "ep-export", Type: graph.NodeAPIEndpoint, Name: "GET /users/export", FilePath: "users/UserController.java", Line: 30,
+			Properties: map[string]string{parser.PropDataClasses: "pii", parser.PropDataTypes: "UserDto"}},
+		&graph.Node{ID: "ep-charge", Type: graph.NodeAPIEndpoint, Name: "POST /charge", FilePath: "billing/handlers.go", Line: 8,
+			Properties: map[string]string{parser.PropDataClasses: "pci", parser.PropDataTypes: "Card", parser.PropAuth: parser.AuthProtected}},
+		&graph.Node{ID: "ep-ping", Type: graph.NodeAPIEndpoint, Name: "GET /ping", FilePath: "users/UserController.java", Line: 40},
+	)
+
+	report, err := buildDataFlowReport(ctx, store, "", "")
+	if err != nil {
+		t.Fatalf("buildDataFlowReport: %v", err)
+	}
+	var types, endpoints []string
+	for _, ty := range report.Types {
+		types = append(types, ty.Service+":"+ty.Name)
+	}
+	for _, ep := range report.Endpoints {
+		endpoints = append(endpoints, ep.Name+"="+ep.Auth)
+	}
+	if got := strings.Join(types, ","); got != "billing:Card,users:UserDto" {
+		t.Errorf("types = %s", got)
+	}
+	if got := strings.Join(endpoints, ","); got != "POST /charge=protected,GET /users/{id}=protected,GET /users/export=none" {
+		t.Errorf("endpoints = %s", got)
+	}
+	if len(report.Services) != 2 || report.Services[1].Service != "users" ||
+		strings.Join(report.Services[1].Classes, ",") != "phi,pii" || report.Services[1].Endpoints != 2 || report.Services[1].Unauthenticated != 1 {
+		t.Errorf("services = %+v", report.Services)
+	}
+
+	pci, err := buildDataFlowReport(ctx, store, "pci", "")
+	if err != nil {
+		t.Fatalf("buildDataFlowReport --class pci: %v", err)
+	}
+	if len(pci.Types) != 1 || len(pci.Endpoints) != 1 || pci.Endpoints[0].ID != "ep-charge" {
+		t.Errorf("pci report = %+v", pci)
+	}
+
+	var out bytes.Buffer
+	writeDataFlowReport(&out, report)
+	for _, s := range []string{"Sensitive types (2):", "email:pii,ssn:phi", "NO AUTH", "users                 phi,pii       2 endpoints, 1 without auth"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output missing %q:\n%s", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// debtAgeBuckets are the age groups of 'codeeagle debt --by age', oldest
+// first. Annotations older than a bucket's minimum fall into it.
+var debtAgeBuckets = []struct {
+	name    string
+	minDays int
+}{
+	{"over 1 year", 365},
+	{"6-12 months", 182},
+	{"1-6 months", 30},
+	{"under 1 month", 0},
+}
+
+// debtItem is a TODO, FIXME, HACK or XXX annotation.
+type debtItem struct {
+	ID       string   `json:"id"`
+	Kind     string   `json:"kind"`
+	Text     string   `json:"text"`
+	FilePath string   `json:"file_path"`
+	Line     int      `json:"line"`
+	Function string   `json:"function,omitempty"`
+	Owner    string   `json:"owner"`
+	Service  string   `json:"service,omitempty"`
+	Issues   []string `json:"issues,omitempty"`
+	// Added is the date git blame attributes the line to (YYYY-MM-DD).
+	Added   string `json:"added,omitempty"`
+	AgeDays int    `json:"age_days,omitempty"`
+}
+
+// debtGroup is the annotations of one owner, service, age bucket or kind.
+type debtGroup struct {
+	Name  string     `json:"name"`
+	Items []debtItem `json:"items"`
+}
+
+// debtReport is the result of 'codeeagle debt'.
+type debtReport struct {
+	By     string      `json:"by"`
+	Total  int         `json:"total"`
+	Groups []debtGroup `json:"groups"`
+}
+
+// debtSources resolve the owner and age of annotations; nil functions are
+// skipped.
+type debtSources struct {
+	// owners returns the CODEOWNERS owners of a file path.
+	owners func(path string) []string
+	// blame returns the git blame of a file, line n at index n-1.
+	blame func(path string) []gitutil.BlameLine
+	now   time.Time
+}
+
+func newDebtCmd() *cobra.Command {
+	var (
+		by      string
+		kind    string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "debt",
+		Short: "List TODO, FIXME, HACK and XXX comments by owner, service or age",
+		Long: `List the tech-debt annotations found while indexing: comments starting with
+TODO, FIXME, HACK or XXX, with the function they appear in and the issues
+they reference (#123, org/repo#123, PROJ-123, tracker URLs).
+
+Annotations are grouped by --by:
+
+  owner    the author named in the comment (TODO(alice), TODO @alice),
+           else the CODEOWNERS owner of the file, else the git blame author
+  service  the service owning the file
+  age      when the line was last changed, from git blame
+  kind     todo, fixme, hack, xxx`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if by != "owner" && by != "service" && by != "age" && by != "kind" {
+				return fmt.Errorf("invalid --by %q: must be owner, service, age or kind", by)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			owners, err := loadCodeOwners(cfg)
+			if err != nil {
+				return err
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			blames := make(map[string][]gitutil.BlameLine)
+			blame := func(path string) []gitutil.BlameLine {
+				if lines, ok := blames[path]; ok {
+					return lines
+				}
+				var lines []gitutil.BlameLine
+				for _, repo := range cfg.Repositories {
+					if _, err := os.Stat(filepath.Join(repo.Path, path)); err == nil {
+						lines, _ = gitutil.BlameFile(repo.Path, path)
+						break
+					}
+				}
+				blames[path] = lines
+				return lines
+			}
+
+			report, err := buildDebtReport(ctx(cmd), store, by, kind, debtSources{owners: owners, blame: blame, now: time.Now()})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			writeDebtReport(out, report)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&by, "by", "owner", "group by owner, service, age or kind")
+	cmd.Flags().StringVar(&kind, "kind", "", "only list one kind: todo, fixme, hack, xxx")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// buildDebtReport collects the Annotation nodes of the given kind (empty
+// for all) and groups them. Owner and service groups are ordered by size,
+// age groups oldest first, and items by file and line.
+func buildDebtReport(ctx context.Context, store graph.Store, by, kind string, src debtSources) (*debtReport, error) {
+	filter := graph.NodeFilter{Type: graph.NodeAnnotation}
+	if kind != "" {
+		filter.Properties = map[string]string{"kind": strings.ToLower(kind)}
+	}
+	annotations, err := store.QueryNodes(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query annotations: %w", err)
+	}
+	serviceOf, err := serviceNamesByGroup(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]debtItem)
+	for _, n := range annotations {
+		item := debtItem{
+			ID:       n.ID,
+			Kind:     n.Properties["kind"],
+			Text:     n.Name,
+			FilePath: n.FilePath,
+			Line:     n.Line,
+			Owner:    n.Properties["author"],
+			Service:  serviceOf[linker.ServiceGroup(n.FilePath)],
+		}
+		if issues := n.Properties["issues"]; issues != "" {
+			item.Issues = strings.Split(issues, ",")
+		}
+
+		edges, err := store.GetEdges(ctx, n.ID, graph.EdgeAnnotates)
+		if err != nil {
+			return nil, fmt.Errorf("get annotated code of %s: %w", n.ID, err)
+		}
+		for _, e := range edges {
+			if e.SourceID != n.ID {
+				continue
+			}
+			if target, err := store.GetNode(ctx, e.TargetID); err == nil && target.Type != graph.NodeFile && target.Type != graph.NodeTestFile {
+				item.Function = target.Name
+			}
+		}
+
+		var blamed gitutil.BlameLine
+		if src.blame != nil {
+			if lines := src.blame(n.FilePath); n.Line >= 1 && n.Line <= len(lines) {
+				blamed = lines[n.Line-1]
+			}
+		}
+		if !blamed.Time.IsZero() {
+			item.Added = blamed.Time.Format("2006-01-02")
+			item.AgeDays = int(src.now.Sub(blamed.Time).Hours() / 24)
+		}
+		if item.Owner == "" && src.owners != nil {
+			item.Owner = strings.Join(src.owners(n.FilePath), " ")
+		}
+		if item.Owner == "" {
+			item.Owner = blamed.Author
+		}
+
+		var key string
+		switch by {
+		case "owner":
+			key = orDefault(item.Owner, "(unowned)")
+		case "service":
+			key = orDefault(item.Service, "(none)")
+		case "kind":
+			key = item.Kind
+		case "age":
+			key = "unknown"
+			if item.Added != "" {
+				for _, b := range debtAgeBuckets {
+					if item.AgeDays >= b.minDays {
+						key = b.name
+						break
+					}
+				}
+			}
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	report := &debtReport{By: by, Total: len(annotations)}
+	for name, items := range groups {
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].FilePath != items[j].FilePath {
+				return items[i].FilePath < items[j].FilePath
+			}
+			return items[i].Line < items[j].Line
+		})
+		report.Groups = append(report.Groups, debtGroup{Name: name, Items: items})
+	}
+	rank := func(name string) int {
+		for i, b := range debtAgeBuckets {
+			if b.name == name {
+				return i
+			}
+		}
+		return len(debtAgeBuckets)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		gi, gj := report.Groups[i], report.Groups[j]
+		if by == "age" {
+			return rank(gi.Name) < rank(gj.Name)
+		}
+		if len(gi.Items) != len(gj.Items) {
+			return len(gi.Items) > len(gj.Items)
+		}
+		return gi.Name < gj.Name
+	})
+	return report, nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// writeDebtReport renders the report as text, one section per group.
+func writeDebtReport(w io.Writer, report *debtReport) {
+	if report.Total == 0 {
+		fmt.Fprintln(w, "No TODO, FIXME, HACK or XXX comments in the graph.")
+		return
+	}
+	for _, g := range report.Groups {
+		fmt.Fprintf(w, "%s (%d)\n", g.Name, len(g.Items))
+		for _, it := range g.Items {
+			loc := fmt.Sprintf("%s:%d", it.FilePath, it.Line)
+			if it.Function != "" {
+				loc += " in " + it.Function
+			}
+			var extra []string
+			if len(it.Issues) > 0 {
+				extra = append(extra, strings.Join(it.Issues, ", "))
+			}
+			if it.Added != "" {
+				extra = append(extra, "since "+it.Added)
+			}
+			if report.By != "owner" && it.Owner != "" {
+				extra = append(extra, it.Owner)
+			}
+			line := fmt.Sprintf("  %-5s %s  %s", strings.ToUpper(it.Kind), it.Text, loc)
+			if len(extra) > 0 {
+				line += "  (" + strings.Join(extra, "; ") + ")"
+			}
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d annotation(s)\n", report.Total)
+}
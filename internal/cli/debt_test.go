@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestBuildDebtReport(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	note := func(id, kind, file string, line int, props map[string]string) *graph.Node {
+		p := map[string]string{"kind": kind}
+		for k, v := range props {
+			p[k] = v
+		}
+		return &graph.Node{ID: id, Type: graph.NodeAnnotation, Name: id + " text", FilePath: file, Line: line, Properties: p}
+	}
+	addTestNodes(t, store,
+		&graph.Node{ID: "svc", Type: graph.NodeService, Name: "billing", FilePath: "billing/go.mod"},
+		&graph.Node{ID: "refund", Type: graph.NodeFunction, Name: "Refund", FilePath: "billing/refund.go", Line: 1, EndLine: 20},
+		note("a", "todo", "billing/refund.go", 2, map[string]string{"author": "alice", "issues": "#42,PAY-1"}),
+		note("b", "fixme", "billing/refund.go", 3, nil),
+		note("c", "hack", "tools/gen.go", 1, nil),
+	)
+	addTestEdges(t, store, &graph.Edge{ID: graph.NewEdgeID(graph.EdgeAnnotates, "a", "refund"),
+		Type: graph.EdgeAnnotates, SourceID: "a", TargetID: "refund"})
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	src := debtSources{
+		owners: func(path string) []string {
+			if strings.HasPrefix(path, "billing/") {
+				return []string{"@payments-team"}
+			}
+			return nil
+		},
+		blame: func(path string) []gitutil.BlameLine {
+			if path != "billing/refund.go" {
+				return nil
+			}
+			return []gitutil.BlameLine{
+				{},
+				{Author: "x@example.com", Time: now.AddDate(-2, 0, 0)},
+				{Author: "y@example.com", Time: now.AddDate(0, 0, -3)},
+			}
+		},
+		now: now,
+	}
+
+	groupNames := func(r *debtReport) string {
+		var names []string
+		for _, g := range r.Groups {
+			names = append(names, g.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	report, err := buildDebtReport(ctx, store, "owner", "", src)
+	if err != nil {
+		t.Fatalf("buildDebtReport: %v", err)
+	}
+	if got := groupNames(report); got != "(unowned),@payments-team,alice" {
+		t.Errorf("owner groups = %s", got)
+	}
+	a := report.Groups[2].Items[0]
+	if a.Function != "Refund" || a.Service != "billing" || len(a.Issues) != 2 || a.Added != "2023-06-01" {
+		t.Errorf("item a = %+v", a)
+	}
+
+	report, err = buildDebtReport(ctx, store, "age", "", src)
+	if err != nil {
+		t.Fatalf("buildDebtReport: %v", err)
+	}
+	if got := groupNames(report); got != "over 1 year,under 1 month,unknown" {
+		t.Errorf("age groups = %s", got)
+	}
+
+	report, err = buildDebtReport(ctx, store, "service", "fixme", src)
+	if err != nil {
+		t.Fatalf("buildDebtReport: %v", err)
+	}
+	if report.Total != 1 || groupNames(report) != "billing" {
+		t.Errorf("fixme by service = %+v", report)
+	}
+
+	var buf bytes.Buffer
+	writeDebtReport(&buf, report)
+	if want := "FIXME b text  billing/refund.go:3  (since 2025-05-29; @payments-team)"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// docCoverageTypes are the symbol types whose exported declarations are
+// expected to carry a doc comment.
+var docCoverageTypes = []graph.NodeType{
+	graph.NodeFunction, graph.NodeMethod, graph.NodeStruct, graph.NodeClass,
+	graph.NodeInterface, graph.NodeEnum, graph.NodeType_,
+}
+
+// docSymbol is an exported symbol without a doc comment.
+type docSymbol struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Type     graph.NodeType `json:"type"`
+	FilePath string         `json:"file_path"`
+	Line     int            `json:"line,omitempty"`
+}
+
+// docGroupCoverage is the documentation coverage of a package or service.
+type docGroupCoverage struct {
+	Name         string      `json:"name"`
+	Total        int         `json:"total"`
+	Documented   int         `json:"documented"`
+	Undocumented int         `json:"undocumented"`
+	Percent      float64     `json:"percent"`
+	Missing      []docSymbol `json:"missing,omitempty"`
+}
+
+// docCoverageReport is the result of 'codeeagle doc-coverage'.
+type docCoverageReport struct {
+	By           string             `json:"by"`
+	Total        int                `json:"total"`
+	Documented   int                `json:"documented"`
+	Undocumented int                `json:"undocumented"`
+	Percent      float64            `json:"percent"`
+	Groups       []docGroupCoverage `json:"groups"`
+}
+
+func newDocCoverageCmd() *cobra.Command {
+	var (
+		by        string
+		pkg       string
+		language  string
+		list      bool
+		failUnder float64
+		jsonOut   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doc-coverage",
+		Short: "Report exported symbols lacking documentation",
+		Long: `Report the share of exported functions, methods, and types (structs,
+classes, interfaces, enums, type aliases) that have a doc comment, per
+package (default) or per service. Test code is excluded.
+
+Use --list to name the undocumented symbols, and --fail-under in CI to
+exit non-zero when overall coverage is below a percentage:
+
+  codeeagle doc-coverage --by service --fail-under 80`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if by != "package" && by != "service" {
+				return fmt.Errorf("invalid --by %q: must be package or service", by)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			report, err := buildDocCoverage(ctx(cmd), store, by, pkg, language)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				writeDocCoverage(out, report, list)
+			}
+
+			if failUnder > 0 && report.Total > 0 && report.Percent < failUnder {
+				return fmt.Errorf("documentation coverage %.1f%% is below %.1f%%", report.Percent, failUnder)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&by, "by", "package", "group by package or service")
+	cmd.Flags().StringVar(&pkg, "package", "", "filter by package name")
+	cmd.Flags().StringVar(&language, "language", "", "filter by language")
+	cmd.Flags().BoolVar(&list, "list", false, "list the undocumented symbols")
+	cmd.Flags().Float64Var(&failUnder, "fail-under", 0, "exit non-zero when overall coverage is below this percentage (for CI)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// buildDocCoverage counts the exported symbols with and without a doc
+// comment, grouped by package or by service. Services own the files under
+// their top-level directory; other files are grouped under "(none)".
+func buildDocCoverage(ctx context.Context, store graph.Store, by, pkg, language string) (*docCoverageReport, error) {
+	var serviceOf map[string]string
+	if by == "service" {
+		var err error
+		if serviceOf, err = serviceNamesByGroup(ctx, store); err != nil {
+			return nil, err
+		}
+	}
+
+	exported := true
+	groups := make(map[string]*docGroupCoverage)
+	report := &docCoverageReport{By: by}
+	for _, typ := range docCoverageTypes {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ, Package: pkg, Language: language, Exported: &exported})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+		}
+		for _, n := range nodes {
+			lang := n.Language
+			if lang == "" {
+				lang = inferLangFromPath(n.FilePath)
+			}
+			if isTestFileByPath(n.FilePath, lang) || isTestFuncByName(n.Name, lang, n.FilePath) {
+				continue
+			}
+
+			name := n.Package
+			if by == "service" {
+				name = serviceOf[linker.ServiceGroup(n.FilePath)]
+			}
+			if name == "" {
+				name = "(none)"
+			}
+			g := groups[name]
+			if g == nil {
+				g = &docGroupCoverage{Name: name}
+				groups[name] = g
+			}
+			g.Total++
+			if strings.TrimSpace(n.DocComment) != "" {
+				g.Documented++
+				continue
+			}
+			g.Undocumented++
+			g.Missing = append(g.Missing, docSymbol{ID: n.ID, Name: n.Name, Type: n.Type, FilePath: n.FilePath, Line: n.Line})
+		}
+	}
+
+	for _, g := range groups {
+		g.Percent = percentOf(g.Documented, g.Total)
+		sort.Slice(g.Missing, func(i, j int) bool {
+			if g.Missing[i].FilePath != g.Missing[j].FilePath {
+				return g.Missing[i].FilePath < g.Missing[j].FilePath
+			}
+			return g.Missing[i].Line < g.Missing[j].Line
+		})
+		report.Total += g.Total
+		report.Documented += g.Documented
+		report.Undocumented += g.Undocumented
+		report.Groups = append(report.Groups, *g)
+	}
+	report.Percent = percentOf(report.Documented, report.Total)
+	sort.Slice(report.Groups, func(i, j int) bool {
+		return report.Groups[i].Name < report.Groups[j].Name
+	})
+	return report, nil
+}
+
+// serviceNamesByGroup maps service groups (top-level directories, see
+// linker.ServiceGroup) to the name of the service they hold. Several
+// manifests in one group resolve to the alphabetically first name.
+func serviceNamesByGroup(ctx context.Context, store graph.Store) (map[string]string, error) {
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query services: %w", err)
+	}
+	names := make(map[string]string)
+	for _, svc := range services {
+		group := svc.Name
+		if svc.FilePath != "" {
+			group = linker.ServiceGroup(svc.FilePath)
+		}
+		if name, ok := names[group]; !ok || svc.Name < name {
+			names[group] = svc.Name
+		}
+	}
+	return names, nil
+}
+
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(n) / float64(total) * 100
+}
+
+// writeDocCoverage renders the report as a table, optionally followed by
+// the undocumented symbols of each group.
+func writeDocCoverage(w io.Writer, report *docCoverageReport, list bool) {
+	if report.Total == 0 {
+		fmt.Fprintln(w, "No exported symbols in the graph.")
+		return
+	}
+
+	title := strings.ToUpper(report.By[:1]) + report.By[1:]
+	fmt.Fprintf(w, "  %-40s  %8s  %10s  %12s  %8s\n", title, "Symbols", "Documented", "Undocumented", "Percent")
+	fmt.Fprintf(w, "  %-40s  %8s  %10s  %12s  %8s\n", strings.Repeat("-", 40), "--------", "----------", "------------", "--------")
+	for _, g := range report.Groups {
+		fmt.Fprintf(w, "  %-40s  %8d  %10d  %12d  %7.1f%%\n", g.Name, g.Total, g.Documented, g.Undocumented, g.Percent)
+	}
+	fmt.Fprintf(w, "\n%d of %d exported symbol(s) documented (%.1f%%)\n", report.Documented, report.Total, report.Percent)
+
+	if !list {
+		return
+	}
+	for _, g := range report.Groups {
+		if len(g.Missing) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s (%d undocumented):\n", g.Name, len(g.Missing))
+		for _, s := range g.Missing {
+			fmt.Fprintf(w, "  %-10s  %-40s  %s:%d\n", s.Type, s.Name, s.FilePath, s.Line)
+		}
+	}
+}
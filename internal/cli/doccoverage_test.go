@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestBuildDocCoverage(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	sym := func(id string, typ graph.NodeType, file, pkg string, exported bool, doc string) *graph.Node {
+		return &graph.Node{ID: id, Type: typ, Name: id, FilePath: file, Package: pkg, Line: 3,
+			Language: "go", Exported: exported, DocComment: doc}
+	}
+	addTestNodes(t, store,
+		&graph.Node{ID: "svc-billing", Type: graph.NodeService, Name: "billing", FilePath: "billing/go.mod"},
+		sym("Charge", graph.NodeFunction, "billing/charge.go", "billing", true, "Charge bills a card."),
+		sym("Refund", graph.NodeFunction, "billing/charge.go", "billing", true, " "),
+		sym("Invoice", graph.NodeStruct, "billing/invoice.go", "invoice", true, "Invoice is a bill."),
+		sym("helper", graph.NodeFunction, "billing/charge.go", "billing", false, ""),
+		sym("TestCharge", graph.NodeFunction, "billing/charge_test.go", "billing", true, ""),
+		sym("Run", graph.NodeFunction, "tools/run.go", "tools", true, ""),
+	)
+
+	report, err := buildDocCoverage(ctx, store, "package", "", "")
+	if err != nil {
+		t.Fatalf("buildDocCoverage: %v", err)
+	}
+	if report.Total != 4 || report.Documented != 2 || report.Percent != 50 {
+		t.Errorf("report = %d/%d (%.1f%%), want 2/4 (50%%)", report.Documented, report.Total, report.Percent)
+	}
+	var got []string
+	for _, g := range report.Groups {
+		got = append(got, g.Name)
+	}
+	if strings.Join(got, ",") != "billing,invoice,tools" {
+		t.Errorf("package groups = %v", got)
+	}
+	if m := report.Groups[0].Missing; len(m) != 1 || m[0].Name != "Refund" {
+		t.Errorf("billing missing = %+v, want Refund (blank doc comment)", m)
+	}
+
+	report, err = buildDocCoverage(ctx, store, "service", "", "")
+	if err != nil {
+		t.Fatalf("buildDocCoverage: %v", err)
+	}
+	if len(report.Groups) != 2 || report.Groups[0].Name != "(none)" || report.Groups[1].Name != "billing" || report.Groups[1].Total != 3 {
+		t.Errorf("service groups = %+v, want (none) and billing with 3 symbols", report.Groups)
+	}
+
+	var buf bytes.Buffer
+	writeDocCoverage(&buf, report, true)
+	for _, want := range []string{"Service", "2 of 4 exported symbol(s) documented (50.0%)", "tools/run.go:3"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
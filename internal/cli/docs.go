@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/sitegen"
+)
+
+func newDocsCmd() *cobra.Command {
+	var (
+		format   string
+		output   string
+		services []string
+		title    string
+		maxTypes int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate a static documentation site with a page per service",
+		Long: `Generate a static site (markdown or HTML) from the knowledge graph: an
+index page plus one page per service with its description, exposed and
+consumed endpoints, key types with their doc comments, and its service and
+package dependencies.
+
+Descriptions come from the service summaries generated during indexing
+(requires an LLM provider); run 'codeeagle sync' first so endpoint
+consumers and service dependencies are linked.
+
+Examples:
+  codeeagle docs
+  codeeagle docs --format html -o site
+  codeeagle docs --service orders --service billing`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if title == "" {
+				title = cfg.Project.Name
+			}
+			site, err := sitegen.Build(ctx(cmd), store, sitegen.Options{
+				Title:    title,
+				MaxTypes: maxTypes,
+				Services: services,
+			})
+			if err != nil {
+				return err
+			}
+			if len(site.Services) == 0 {
+				return fmt.Errorf("no services in the graph; run 'codeeagle sync' first")
+			}
+			written, err := sitegen.Write(site, output, sitegen.Format(format))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d pages (%d services) to %s\n", len(written), len(site.Services), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", string(sitegen.FormatMarkdown), "output format: markdown or html")
+	cmd.Flags().StringVarP(&output, "output", "o", "docs/services", "output directory")
+	cmd.Flags().StringSliceVar(&services, "service", nil, "only document these services (repeatable)")
+	cmd.Flags().StringVar(&title, "title", "", "site title (default: project name)")
+	cmd.Flags().IntVar(&maxTypes, "max-types", 15, "maximum key types listed per service")
+
+	return cmd
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// driftSections orders and titles the text report's sections.
+var driftSections = []struct {
+	kind  linker.DriftKind
+	title string
+}{
+	{linker.DriftMissingEndpoint, "Calls to endpoints that don't exist"},
+	{linker.DriftMethodMismatch, "Calls with a method the endpoint doesn't accept"},
+	{linker.DriftUnusedEndpoint, "Endpoints with no consumers"},
+}
+
+func newDriftCmd() *cobra.Command {
+	var (
+		jsonOut     bool
+		kind        string
+		service     string
+		failOnDrift bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Report contract drift between API consumers and providers",
+		Long: `Compare the paths and methods API consumers call (api_call nodes) with the
+endpoints providers define, and report drift:
+
+  missing_endpoint  a call whose path matches no endpoint
+  method_mismatch   a call whose path exists but not with the call's method
+  unused_endpoint   an endpoint no call (or HTML form) consumes
+
+Calls marked external in the unresolved backlog ('codeeagle unresolved
+ignore') are not reported.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			var backlog *linker.Backlog
+			if path := backlogPath(cfg); path != "" {
+				if backlog, err = linker.LoadBacklog(path); err != nil {
+					return err
+				}
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			report, err := newLinker(cfg, store, nil, nil, false).DetectDrift(ctx(cmd), backlog)
+			if err != nil {
+				return fmt.Errorf("detect drift: %w", err)
+			}
+			filterDrift(report, linker.DriftKind(kind), service)
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(report); err != nil {
+					return err
+				}
+			} else {
+				writeDriftReport(out, report)
+			}
+
+			if failOnDrift && len(report.Findings) > 0 {
+				return fmt.Errorf("%d contract drift finding(s)", len(report.Findings))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	cmd.Flags().StringVar(&kind, "kind", "", "only report one kind: missing_endpoint, method_mismatch, unused_endpoint")
+	cmd.Flags().StringVar(&service, "service", "", "only report findings in this service")
+	cmd.Flags().BoolVar(&failOnDrift, "fail-on-drift", false, "exit non-zero when any drift is found (for CI)")
+
+	return cmd
+}
+
+// filterDrift keeps only findings of the given kind and service (empty
+// matches all).
+func filterDrift(report *linker.DriftReport, kind linker.DriftKind, service string) {
+	kept := report.Findings[:0]
+	for _, f := range report.Findings {
+		if (kind == "" || f.Kind == kind) && (service == "" || f.Service == service) {
+			kept = append(kept, f)
+		}
+	}
+	report.Findings = kept
+}
+
+// writeDriftReport renders the drift report as text, one section per kind.
+func writeDriftReport(w io.Writer, report *linker.DriftReport) {
+	if report.Calls == 0 && report.Endpoints == 0 {
+		fmt.Fprintln(w, "No API calls or endpoints in the graph. Run 'codeeagle sync' first.")
+		return
+	}
+
+	for _, s := range driftSections {
+		var rows []linker.DriftFinding
+		for _, f := range report.Findings {
+			if f.Kind == s.kind {
+				rows = append(rows, f)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%d)\n", s.title, len(rows))
+		for _, f := range rows {
+			method := f.Method
+			if method == "" {
+				method = "?"
+			}
+			line := fmt.Sprintf("  %-7s %s  %s:%d", method, f.Path, f.FilePath, f.Line)
+			if len(f.Provided) > 0 {
+				line += fmt.Sprintf("  (accepts %s in %s)", strings.Join(f.Provided, ", "), f.Provider)
+			}
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d calls, %d endpoints: %d missing, %d method mismatches, %d unused\n",
+		report.Calls, report.Endpoints,
+		report.Count(linker.DriftMissingEndpoint),
+		report.Count(linker.DriftMethodMismatch),
+		report.Count(linker.DriftUnusedEndpoint))
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func newFindingsCmd() *cobra.Command {
+	var (
+		format   string
+		output   string
+		rules    []string
+		severity string
+		file     string
+		category string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "findings",
+		Short: "List hard-coded secrets and parse errors found during indexing",
+		Long: `List the Finding nodes recorded while indexing: syntax errors parsers
+recovered from (category parse_error; the graph lacks whatever the broken
+code declared) and hard-coded secrets (category secret). Secret scanning is
+opt-in; enable it in .CodeEagle/config.yaml and re-sync:
+
+  secrets:
+    scan: true
+    exclude: ["**/testdata/**"]
+
+Secret values are never stored; findings carry a redacted prefix only.
+Add "codeeagle:allow-secret" to a line to suppress a finding.
+
+Output formats match "codeeagle problems": text, json, checkstyle.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			findings, err := collectFindings(ctx(cmd), store, category, rules, severity, file)
+			if err != nil {
+				return err
+			}
+			if len(findings) == 0 && !cfg.Secrets.Scan && category == "secret" && format == "text" {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Secret scanning is disabled; set secrets.scan: true in the config and re-sync.")
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" {
+				if dir := filepath.Dir(output); dir != "." {
+					if err := os.MkdirAll(dir, 0o755); err != nil {
+						return fmt.Errorf("create output dir: %w", err)
+					}
+				}
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			if err := writeProblems(out, format, findings); err != nil {
+				return err
+			}
+			if output != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d finding(s) to %s\n", len(findings), output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, or checkstyle")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write to file instead of stdout")
+	cmd.Flags().StringSliceVar(&rules, "rules", nil, "only report these rule IDs (e.g., aws-access-key-id,private-key)")
+	cmd.Flags().StringVar(&severity, "severity", "", "only report this severity: error or warning")
+	cmd.Flags().StringVar(&category, "category", "", "only report this category: secret or parse_error")
+	cmd.Flags().StringVar(&file, "file", "", "only report findings in files under this path prefix")
+
+	return cmd
+}
+
+// collectFindings converts Finding nodes to problems, filtered by category,
+// rule ID, severity, and file path prefix, sorted by location.
+func collectFindings(ctx context.Context, store graph.Store, category string, rules []string, severity, file string) ([]problem, error) {
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFinding, FilePathPrefix: file})
+	if err != nil {
+		return nil, fmt.Errorf("query findings: %w", err)
+	}
+
+	wantRule := make(map[string]bool)
+	for _, r := range rules {
+		wantRule[r] = true
+	}
+
+	var problems []problem
+	for _, n := range nodes {
+		if category != "" && n.Properties["category"] != category {
+			continue
+		}
+		rule := n.Properties["rule"]
+		if len(wantRule) > 0 && !wantRule[rule] {
+			continue
+		}
+		if severity != "" && n.Properties["severity"] != severity {
+			continue
+		}
+		col, _ := strconv.Atoi(n.Properties["column"])
+		message := n.Properties["message"]
+		if n.Properties["category"] == "secret" {
+			message = fmt.Sprintf("hard-coded %s (%s)", message, n.Properties["redacted"])
+		}
+		problems = append(problems, problem{
+			File:     n.FilePath,
+			Line:     n.Line,
+			Column:   col,
+			Severity: n.Properties["severity"],
+			Rule:     rule,
+			Message:  message,
+		})
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems, nil
+}
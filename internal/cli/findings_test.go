@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestCollectFindings(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	finding := func(id, file string, line int, rule, severity string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeFinding, Name: rule, FilePath: file, Line: line,
+			Properties: map[string]string{"category": "secret", "rule": rule, "severity": severity, "column": "7",
+				"message": "secret", "redacted": "abcd********"}}
+	}
+	addTestNodes(t, store,
+		finding("f1", "web/app.ts", 3, "generic-secret", "warning"),
+		finding("f2", "api/main.go", 10, "aws-access-key-id", "error"),
+		finding("f3", "api/main.go", 2, "private-key", "error"),
+		&graph.Node{ID: "p1", Type: graph.NodeFinding, Name: "syntax-error", FilePath: "web/broken.ts", Line: 4,
+			Properties: map[string]string{"category": "parse_error", "rule": "syntax-error", "severity": "warning",
+				"column": "7", "message": "missing }"}},
+	)
+
+	tests := []struct {
+		name     string
+		category string
+		rules    []string
+		severity string
+		file     string
+		want     []string // file:rule in order
+	}{
+		{"all sorted", "", nil, "", "", []string{"api/main.go:private-key", "api/main.go:aws-access-key-id", "web/app.ts:generic-secret", "web/broken.ts:syntax-error"}},
+		{"by category", "secret", nil, "", "", []string{"api/main.go:private-key", "api/main.go:aws-access-key-id", "web/app.ts:generic-secret"}},
+		{"parse errors", "parse_error", nil, "", "", []string{"web/broken.ts:syntax-error"}},
+		{"by rule", "", []string{"generic-secret"}, "", "", []string{"web/app.ts:generic-secret"}},
+		{"by severity", "", nil, "error", "", []string{"api/main.go:private-key", "api/main.go:aws-access-key-id"}},
+		{"by file", "", nil, "", "web/app", []string{"web/app.ts:generic-secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collectFindings(ctx, store, tt.category, tt.rules, tt.severity, tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d findings, want %d", len(got), len(tt.want))
+			}
+			for i, p := range got {
+				if p.File+":"+p.Rule != tt.want[i] {
+					t.Errorf("finding %d = %s:%s, want %s", i, p.File, p.Rule, tt.want[i])
+				}
+				want := "hard-coded secret (abcd********)"
+				if p.Rule == "syntax-error" {
+					want = "missing }"
+				}
+				if p.Column != 7 || p.Message != want {
+					t.Errorf("finding %d = %+v", i, p)
+				}
+			}
+		})
+	}
+}
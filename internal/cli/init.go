@@ -116,17 +116,41 @@ func detectLLMProvider() (provider, hint string) {
 	if os.Getenv("ANTHROPIC_API_KEY") != "" {
 		return "anthropic", "ANTHROPIC_API_KEY set"
 	}
+	if os.Getenv("AZURE_OPENAI_API_KEY") != "" {
+		return "azure-openai", "AZURE_OPENAI_API_KEY set"
+	}
+	if os.Getenv("GEMINI_API_KEY") != "" {
+		return "gemini", "GEMINI_API_KEY set"
+	}
 	if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" || os.Getenv("GOOGLE_CLOUD_PROJECT") != "" {
 		return "vertex-ai", "Google Cloud credentials detected"
 	}
+	if os.Getenv("AWS_BEARER_TOKEN_BEDROCK") != "" {
+		return "bedrock", "AWS_BEARER_TOKEN_BEDROCK set"
+	}
 	if internalllm.FindClaudeCLI() != "" {
 		return "claude-cli", "Claude Code CLI detected"
 	}
 	return "anthropic", ""
 }
 
+// defaultLLMModel returns the model written to a new config for provider.
+func defaultLLMModel(provider string) string {
+	switch provider {
+	case "gemini":
+		return "gemini-2.0-flash"
+	case "ollama":
+		return "qwen3:14b"
+	case "azure-openai":
+		return "gpt-4o" // the deployment name
+	case "bedrock":
+		return "anthropic.claude-3-5-sonnet-20240620-v1:0"
+	}
+	return "claude-sonnet-4-5-20250929"
+}
+
 func generateConfigYAML(projectName, projectRoot, provider string) string {
-	model := "claude-sonnet-4-5-20250929"
+	model := defaultLLMModel(provider)
 
 	return fmt.Sprintf(`project:
   name: %q
@@ -172,5 +196,18 @@ func generateEnvTemplate() string {
 # Google Cloud / Vertex AI
 # GOOGLE_APPLICATION_CREDENTIALS=/path/to/service-account.json
 # GOOGLE_CLOUD_PROJECT=my-gcp-project
+
+# Gemini (Developer API)
+# GEMINI_API_KEY=...
+
+# Azure OpenAI
+# AZURE_OPENAI_ENDPOINT=https://my-resource.openai.azure.com
+# AZURE_OPENAI_API_KEY=...
+
+# AWS Bedrock (access keys, or a Bedrock API key)
+# AWS_REGION=us-east-1
+# AWS_ACCESS_KEY_ID=...
+# AWS_SECRET_ACCESS_KEY=...
+# AWS_BEARER_TOKEN_BEDROCK=...
 `
 }
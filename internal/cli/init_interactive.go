@@ -19,7 +19,7 @@ import (
 var allLanguages = []string{
 	"go", "python", "typescript", "javascript", "java",
 	"rust", "csharp", "ruby",
-	"html", "markdown", "makefile", "shell", "terraform", "yaml",
+	"html", "markdown", "makefile", "shell", "terraform", "yaml", "nginx",
 }
 
 // filenameToLanguage maps well-known filenames to their language for auto-detection.
@@ -34,6 +34,7 @@ var filenameToLanguage = map[string]string{
 	"setup.py":         "python",
 	"Cargo.toml":       "rust",
 	"Gemfile":          "ruby",
+	"nginx.conf":       "nginx",
 }
 
 // detectLanguages walks rootDir (depth-limited to 2 levels) and returns
@@ -128,13 +129,6 @@ func runInteractiveInit(cmd *cobra.Command, cwd string) error {
 		langOptions[i] = opt
 	}
 
-	// Provider options
-	providerOptions := []huh.Option[string]{
-		huh.NewOption("Claude Code CLI", "claude-cli"),
-		huh.NewOption("Anthropic API", "anthropic"),
-		huh.NewOption("Vertex AI (GCP)", "vertex-ai"),
-	}
-
 	// Repo type options
 	repoTypeOptions := []huh.Option[string]{
 		huh.NewOption("Single repository", "single"),
@@ -179,7 +173,7 @@ func runInteractiveInit(cmd *cobra.Command, cwd string) error {
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("LLM provider").
-				Options(providerOptions...).
+				Options(llmProviderOptions()...).
 				Value(&llmProvider),
 		).Title("LLM Configuration"),
 
@@ -246,15 +240,7 @@ func runInteractiveInit(cmd *cobra.Command, cwd string) error {
 					if langStr == "" {
 						langStr = "(none)"
 					}
-					providerLabel := llmProvider
-					switch llmProvider {
-					case "claude-cli":
-						providerLabel = "Claude Code CLI"
-					case "anthropic":
-						providerLabel = "Anthropic API"
-					case "vertex-ai":
-						providerLabel = fmt.Sprintf("Vertex AI (%s / %s)", gcpProject, gcpRegion)
-					}
+					providerLabel := llmProviderLabel(llmProvider, gcpProject, gcpRegion)
 					return fmt.Sprintf(
 						"Project:     %s\n"+
 							"Repo type:   %s\n"+
@@ -307,7 +293,7 @@ func runInteractiveInit(cmd *cobra.Command, cwd string) error {
 		Graph:     config.GraphConfig{Storage: "embedded"},
 		Agents: config.AgentsConfig{
 			LLMProvider:   llmProvider,
-			Model:         "claude-sonnet-4-5-20250929",
+			Model:         defaultLLMModel(llmProvider),
 			AutoLink:      autoLink,
 			AutoSummarize: autoSummarize,
 		},
@@ -370,3 +356,38 @@ func runInteractiveInit(cmd *cobra.Command, cwd string) error {
 
 	return nil
 }
+
+// llmProviderOptions are the LLM providers offered by the interactive
+// init and config edit wizards.
+func llmProviderOptions() []huh.Option[string] {
+	return []huh.Option[string]{
+		huh.NewOption("Claude Code CLI", "claude-cli"),
+		huh.NewOption("Anthropic API", "anthropic"),
+		huh.NewOption("Vertex AI (GCP)", "vertex-ai"),
+		huh.NewOption("Gemini API", "gemini"),
+		huh.NewOption("Ollama", "ollama"),
+		huh.NewOption("Azure OpenAI", "azure-openai"),
+		huh.NewOption("AWS Bedrock", "bedrock"),
+	}
+}
+
+// llmProviderLabel returns the wizard summary label for provider.
+func llmProviderLabel(provider, gcpProject, gcpRegion string) string {
+	switch provider {
+	case "claude-cli":
+		return "Claude Code CLI"
+	case "anthropic":
+		return "Anthropic API"
+	case "vertex-ai":
+		return fmt.Sprintf("Vertex AI (%s / %s)", gcpProject, gcpRegion)
+	case "gemini":
+		return "Gemini API"
+	case "ollama":
+		return "Ollama"
+	case "azure-openai":
+		return "Azure OpenAI"
+	case "bedrock":
+		return "AWS Bedrock"
+	}
+	return provider
+}
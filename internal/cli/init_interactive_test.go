@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+func TestLLMProviderOptions(t *testing.T) {
+	tests := []struct {
+		provider string
+		label    string
+	}{
+		{"claude-cli", "Claude Code CLI"},
+		{"anthropic", "Anthropic API"},
+		{"vertex-ai", "Vertex AI (proj / us-central1)"},
+		{"gemini", "Gemini API"},
+		{"ollama", "Ollama"},
+		{"azure-openai", "Azure OpenAI"},
+		{"bedrock", "AWS Bedrock"},
+	}
+
+	offered := make(map[string]bool)
+	for _, opt := range llmProviderOptions() {
+		offered[opt.Value] = true
+	}
+	if len(offered) != len(tests) {
+		t.Errorf("picker offers %d providers, want %d", len(offered), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			if !offered[tt.provider] {
+				t.Errorf("picker does not offer %s", tt.provider)
+			}
+			if !llm.IsProviderRegistered(tt.provider) {
+				t.Errorf("%s is not a registered LLM provider", tt.provider)
+			}
+			if got := llmProviderLabel(tt.provider, "proj", "us-central1"); got != tt.label {
+				t.Errorf("label = %q, want %q", got, tt.label)
+			}
+			if defaultLLMModel(tt.provider) == "" {
+				t.Error("expected a default model")
+			}
+		})
+	}
+	if got := defaultLLMModel("ollama"); got == defaultLLMModel("anthropic") {
+		t.Errorf("ollama default model = %q, want an Ollama model", got)
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/issues"
+)
+
+func newIssuesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "issues",
+		Short: "Link issue tracker references to code",
+	}
+	cmd.AddCommand(newIssuesSyncCmd())
+	return cmd
+}
+
+func newIssuesSyncCmd() *cobra.Command {
+	var (
+		since   string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Link issues named in commit messages to the code the commits changed",
+		Long: `Read the commit messages of each configured repository and link the issues
+they name (Jira keys such as PROJ-123, GitHub references such as #456 or
+owner/repo#456, and issue URLs) to Issue nodes, with References edges
+(source=commit) from:
+
+  files      changed by a commit naming the issue
+  functions  whose current lines were last changed by such a commit,
+             according to git blame
+
+Issues named in code comments are linked when indexing (source=comment).
+Each run replaces the previous commit links, so --since sets the window.
+Then see the code behind a ticket with 'codeeagle query issue PROJ-123'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if len(cfg.Repositories) == 0 {
+				return fmt.Errorf("no repositories configured")
+			}
+
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			out := cmd.OutOrStdout()
+			results := make(map[string]*issues.Result, len(cfg.Repositories))
+			for _, repo := range cfg.Repositories {
+				res, err := issues.Ingest(ctx(cmd), store, repo.Path, issues.Options{Since: since})
+				if err != nil {
+					return fmt.Errorf("%s: %w", repo.Path, err)
+				}
+				results[repo.Path] = res
+				if !jsonOut {
+					fmt.Fprintf(out, "%s: %d of %d commit(s) name %d issue(s); linked %d file and %d function reference(s)\n",
+						repo.Path, res.Referencing, res.Commits, res.Issues, res.Files, res.Functions)
+				}
+			}
+
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(results)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", `only read commits after this date ("2024-01-01", "6.months.ago")`)
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
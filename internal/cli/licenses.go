@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/licenses"
+)
+
+// licenseInventory is the per-service dependency license report.
+type licenseInventory struct {
+	Services   []serviceLicenses `json:"services"`
+	Licenses   map[string]int    `json:"licenses"` // license -> dependency count; "" is unknown
+	Violations int               `json:"violations"`
+}
+
+type serviceLicenses struct {
+	Service      string       `json:"service"`
+	Manifest     string       `json:"manifest"`
+	Ecosystem    string       `json:"ecosystem"`
+	Dependencies []depLicense `json:"dependencies"`
+}
+
+type depLicense struct {
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	Indirect  bool   `json:"indirect,omitempty"`
+	License   string `json:"license,omitempty"`
+	Source    string `json:"source,omitempty"`
+	Violation string `json:"violation,omitempty"`
+}
+
+func newLicensesCmd() *cobra.Command {
+	var (
+		jsonOut         bool
+		service         string
+		violationsOnly  bool
+		failOnViolation bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "licenses",
+		Short: "Per-service inventory of dependency licenses with policy violations",
+		Long: `Resolve the license of every manifest dependency (go.mod, package.json,
+pyproject.toml, requirements.txt) from metadata already on disk:
+
+  go      LICENSE/COPYING in the Go module cache (GOMODCACHE)
+  nodejs  package-lock.json, then node_modules/<pkg>/package.json
+  python  METADATA in a .venv or venv next to the manifest
+
+Nothing is fetched from the network; dependencies that aren't installed
+locally are reported as unknown. Declared licenses are normalized to SPDX
+IDs. The policy comes from the config file:
+
+  licenses:
+    allow: [MIT, Apache-2.0, BSD-3-Clause, ISC]
+    deny: [AGPL-3.0, GPL-3.0]
+    overrides:
+      - package: github.com/acme/internal-sdk
+        license: Proprietary`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var roots []string
+			for _, repo := range cfg.Repositories {
+				roots = append(roots, repo.Path)
+			}
+			overrides := make(map[string]string)
+			for _, o := range cfg.Licenses.Overrides {
+				overrides[o.Package] = o.License
+			}
+			resolver := licenses.NewResolver(roots, overrides)
+			policy := licenses.Policy{Allow: cfg.Licenses.Allow, Deny: cfg.Licenses.Deny}
+
+			inv, err := buildLicenseInventory(ctx(cmd), store, resolver.Resolve, policy, service)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(inv); err != nil {
+					return err
+				}
+			} else {
+				writeLicenseInventory(out, inv, violationsOnly)
+			}
+
+			if failOnViolation && inv.Violations > 0 {
+				return fmt.Errorf("%d license policy violation(s)", inv.Violations)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	cmd.Flags().StringVar(&service, "service", "", "only report this service")
+	cmd.Flags().BoolVar(&violationsOnly, "violations", false, "only list dependencies that violate the policy")
+	cmd.Flags().BoolVar(&failOnViolation, "fail-on-violation", false, "exit non-zero when any dependency violates the policy (for CI)")
+
+	return cmd
+}
+
+// buildLicenseInventory groups manifest dependencies by the manifest
+// declaring them and resolves each one's license.
+func buildLicenseInventory(ctx context.Context, store graph.Store, resolve func(licenses.Dependency) licenses.Resolution, policy licenses.Policy, service string) (*licenseInventory, error) {
+	deps, err := store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Properties: map[string]string{"kind": "manifest_dep"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query dependencies: %w", err)
+	}
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query services: %w", err)
+	}
+	serviceByManifest := make(map[string]string)
+	for _, svc := range services {
+		serviceByManifest[svc.FilePath] = svc.Name
+	}
+
+	byManifest := make(map[string]*serviceLicenses)
+	inv := &licenseInventory{Licenses: make(map[string]int)}
+	for _, dep := range deps {
+		if dep.Properties["internal"] == "true" {
+			continue // workspace package, not third-party
+		}
+		sl := byManifest[dep.FilePath]
+		if sl == nil {
+			name := serviceByManifest[dep.FilePath]
+			if name == "" {
+				name = filepath.Base(filepath.Dir(dep.FilePath))
+			}
+			sl = &serviceLicenses{Service: name, Manifest: dep.FilePath, Ecosystem: dep.Properties["ecosystem"]}
+			byManifest[dep.FilePath] = sl
+		}
+		if service != "" && sl.Service != service {
+			continue
+		}
+
+		res := resolve(licenses.Dependency{
+			Name:      dep.Name,
+			Version:   dep.Properties["version"],
+			Ecosystem: dep.Properties["ecosystem"],
+			Manifest:  dep.FilePath,
+		})
+		dl := depLicense{
+			Name:      dep.Name,
+			Version:   dep.Properties["version"],
+			Indirect:  dep.Properties["scope"] == "indirect",
+			License:   res.License,
+			Source:    res.Source,
+			Violation: policy.Check(res.License),
+		}
+		if dl.Violation != "" {
+			inv.Violations++
+		}
+		inv.Licenses[dl.License]++
+		sl.Dependencies = append(sl.Dependencies, dl)
+	}
+
+	for _, sl := range byManifest {
+		if len(sl.Dependencies) == 0 {
+			continue
+		}
+		sort.Slice(sl.Dependencies, func(i, j int) bool { return sl.Dependencies[i].Name < sl.Dependencies[j].Name })
+		inv.Services = append(inv.Services, *sl)
+	}
+	sort.Slice(inv.Services, func(i, j int) bool {
+		if inv.Services[i].Service != inv.Services[j].Service {
+			return inv.Services[i].Service < inv.Services[j].Service
+		}
+		return inv.Services[i].Manifest < inv.Services[j].Manifest
+	})
+	return inv, nil
+}
+
+// writeLicenseInventory renders the inventory as text, one block per service.
+func writeLicenseInventory(w io.Writer, inv *licenseInventory, violationsOnly bool) {
+	if len(inv.Services) == 0 {
+		fmt.Fprintln(w, "No manifest dependencies in the graph. Run 'codeeagle sync' first.")
+		return
+	}
+
+	for _, sl := range inv.Services {
+		var rows []depLicense
+		for _, d := range sl.Dependencies {
+			if !violationsOnly || d.Violation != "" {
+				rows = append(rows, d)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s (%s, %s)\n", sl.Service, sl.Manifest, sl.Ecosystem)
+		width := 0
+		for _, d := range rows {
+			width = max(width, len(d.Name))
+		}
+		for _, d := range rows {
+			license := d.License
+			if license == "" {
+				license = "unknown"
+			}
+			line := fmt.Sprintf("  %-*s  %-10s  %s", width, d.Name, d.Version, license)
+			if d.Indirect {
+				line += " (indirect)"
+			}
+			if d.Violation != "" {
+				line += "  ! " + d.Violation
+			}
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w)
+	}
+
+	var names []string
+	total := 0
+	for l, n := range inv.Licenses {
+		names = append(names, l)
+		total += n
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if inv.Licenses[names[i]] != inv.Licenses[names[j]] {
+			return inv.Licenses[names[i]] > inv.Licenses[names[j]]
+		}
+		if names[i] == "" || names[j] == "" {
+			return names[j] == "" // unknown last among equals
+		}
+		return names[i] < names[j]
+	})
+	var parts []string
+	for _, l := range names {
+		label := l
+		if label == "" {
+			label = "unknown"
+		}
+		parts = append(parts, fmt.Sprintf("%s %d", label, inv.Licenses[l]))
+	}
+	fmt.Fprintf(w, "%d dependencies: %s\n", total, strings.Join(parts, ", "))
+	fmt.Fprintf(w, "Policy violations: %d\n", inv.Violations)
+}
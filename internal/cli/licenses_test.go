@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/licenses"
+)
+
+func TestBuildLicenseInventory(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	dep := func(id, manifest, name, ecosystem string, props ...string) *graph.Node {
+		n := &graph.Node{ID: id, Type: graph.NodeDependency, Name: name, FilePath: manifest,
+			Properties: map[string]string{"kind": "manifest_dep", "ecosystem": ecosystem, "version": "1.0.0"}}
+		for i := 0; i+1 < len(props); i += 2 {
+			n.Properties[props[i]] = props[i+1]
+		}
+		return n
+	}
+	addTestNodes(t, store,
+		&graph.Node{ID: "svc-api", Type: graph.NodeService, Name: "github.com/acme/api", FilePath: "api/go.mod"},
+		&graph.Node{ID: "svc-web", Type: graph.NodeService, Name: "web", FilePath: "web/package.json"},
+		dep("d1", "api/go.mod", "github.com/spf13/cobra", "go"),
+		dep("d2", "api/go.mod", "github.com/gpl/thing", "go", "scope", "indirect"),
+		dep("d3", "web/package.json", "react", "nodejs"),
+		dep("d4", "web/package.json", "mystery", "nodejs"),
+		&graph.Node{ID: "d5", Type: graph.NodeDependency, Name: "os", Properties: map[string]string{"kind": "import"}},
+	)
+
+	known := map[string]string{"github.com/spf13/cobra": "Apache-2.0", "github.com/gpl/thing": "GPL-3.0-only", "react": "MIT"}
+	resolve := func(d licenses.Dependency) licenses.Resolution {
+		if l := known[d.Name]; l != "" {
+			return licenses.Resolution{License: l, Source: "test"}
+		}
+		return licenses.Resolution{}
+	}
+	policy := licenses.Policy{Deny: []string{"GPL-3.0"}}
+
+	inv, err := buildLicenseInventory(ctx, store, resolve, policy, "")
+	if err != nil {
+		t.Fatalf("buildLicenseInventory: %v", err)
+	}
+	if len(inv.Services) != 2 || inv.Services[0].Service != "github.com/acme/api" || inv.Services[1].Service != "web" {
+		t.Fatalf("services = %+v", inv.Services)
+	}
+	api := inv.Services[0].Dependencies
+	if len(api) != 2 || api[0].Name != "github.com/gpl/thing" || !api[0].Indirect || api[0].Violation != "denied license GPL-3.0-only" {
+		t.Errorf("api dependencies = %+v", api)
+	}
+	if inv.Violations != 1 || inv.Licenses[""] != 1 || inv.Licenses["MIT"] != 1 {
+		t.Errorf("violations/licenses = %d/%v", inv.Violations, inv.Licenses)
+	}
+
+	var buf bytes.Buffer
+	writeLicenseInventory(&buf, inv, true)
+	out := buf.String()
+	if !strings.Contains(out, "github.com/gpl/thing") || strings.Contains(out, "react") {
+		t.Errorf("violations-only output:\n%s", out)
+	}
+	if !strings.Contains(out, "4 dependencies: Apache-2.0 1, GPL-3.0-only 1, MIT 1, unknown 1") {
+		t.Errorf("summary missing:\n%s", out)
+	}
+
+	inv, err = buildLicenseInventory(ctx, store, resolve, policy, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inv.Services) != 1 || inv.Violations != 0 {
+		t.Errorf("--service web = %+v", inv)
+	}
+}
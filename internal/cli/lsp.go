@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/lsp"
+)
+
+func newLSPCmd() *cobra.Command {
+	var logFile string
+
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "Start a Language Server Protocol server over stdio",
+		Long: `Start a Language Server Protocol server over stdin/stdout, backed by the
+knowledge graph, to run alongside an editor's native language servers:
+
+  workspace/symbol         symbols from every indexed language
+  textDocument/references  callers of the function at the cursor, from Calls
+                           edges (including cross-file and cross-service
+                           calls resolved by the linker) and Consumes edges
+  codeeagle/impact         custom request: {textDocument, position} ->
+                           {symbol, impacted: [{name, type, level, location}]}
+
+Configure the editor to start 'codeeagle lsp' in a directory with a
+.CodeEagle config. Keep the graph current with 'codeeagle watch' or the
+post-commit hook.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var repoPaths []string
+			for _, repo := range cfg.Repositories {
+				if abs, err := filepath.Abs(repo.Path); err == nil {
+					repoPaths = append(repoPaths, abs)
+				}
+			}
+			server := lsp.NewServer(store, repoPaths)
+
+			if logFile != "" {
+				f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+				if err != nil {
+					return fmt.Errorf("open log file %s: %w", logFile, err)
+				}
+				defer f.Close()
+				server.SetLogger(func(format string, args ...any) {
+					fmt.Fprintf(f, format+"\n", args...)
+				})
+			} else if verbose {
+				server.SetLogger(func(format string, args ...any) {
+					fmt.Fprintf(os.Stderr, format+"\n", args...)
+				})
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			go func() {
+				<-sigCh
+				cancel()
+			}()
+
+			// stdout carries the protocol; status goes to stderr.
+			fmt.Fprintln(os.Stderr, "codeeagle LSP server started")
+
+			if err := server.Run(ctx); err != nil && ctx.Err() == nil {
+				return fmt.Errorf("LSP server error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&logFile, "log", "", "path to write a log of handled requests")
+
+	return cmd
+}
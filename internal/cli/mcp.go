@@ -1,16 +1,28 @@
 package cli
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/imyousuf/CodeEagle/internal/agents"
+	"github.com/imyousuf/CodeEagle/internal/backstage"
 	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
 	"github.com/imyousuf/CodeEagle/internal/mcp"
 )
 
@@ -26,11 +38,14 @@ func newMCPCmd() *cobra.Command {
 }
 
 func newMCPServeCmd() *cobra.Command {
-	var logFile string
+	var (
+		logFile  string
+		httpAddr string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "serve",
-		Short: "Start the MCP server over stdio",
+		Short: "Start the MCP server over stdio or HTTP",
 		Long: `Start a JSON-RPC 2.0 MCP server over stdin/stdout.
 
 The server exposes CodeEagle knowledge graph tools to MCP clients
@@ -38,7 +53,16 @@ The server exposes CodeEagle knowledge graph tools to MCP clients
 to stdout, one JSON object per line.
 
 This command is typically invoked automatically by the Claude CLI via
---mcp-config, not run directly by users.`,
+--mcp-config, not run directly by users.
+
+With --http the server instead listens on the given address so the graph
+can be shared. JSON-RPC requests are POSTed to /mcp, and graph branches are
+exported and imported with GET and PUT /graph/<branch> (JSON lines, as
+written by 'codeeagle sync --export'). The X-CodeEagle-Namespace header selects
+the graph namespace. Requests must carry a bearer token from serve.tokens;
+each token is limited to its namespaces and to read or write scope, and
+every request is recorded in serve.audit_log. Without tokens the server
+only listens on loopback addresses.`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load()
@@ -56,32 +80,25 @@ This command is typically invoked automatically by the Claude CLI via
 			for _, repo := range cfg.Repositories {
 				repoPaths = append(repoPaths, repo.Path)
 			}
-			ctxBuilder := agents.NewContextBuilder(store, repoPaths...)
-
-			registry := agents.NewRegistry()
-			for _, tool := range agents.NewPlannerTools(ctxBuilder) {
-				registry.Register(tool)
-			}
 
 			// Set up tool call logging: to log file if --log is set, else to stderr if -v.
+			var logger func(format string, args ...any)
 			if logFile != "" {
 				f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 				if err != nil {
 					return fmt.Errorf("open log file %s: %w", logFile, err)
 				}
 				defer f.Close()
-				registry.SetLogger(func(format string, args ...any) {
+				logger = func(format string, args ...any) {
 					fmt.Fprintf(f, format+"\n", args...)
 					_ = f.Sync()
-				})
+				}
 			} else if verbose {
-				registry.SetLogger(func(format string, args ...any) {
+				logger = func(format string, args ...any) {
 					fmt.Fprintf(os.Stderr, format+"\n", args...)
-				})
+				}
 			}
 
-			server := mcp.NewServer(registry)
-
 			// Handle signals for graceful shutdown.
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
@@ -93,6 +110,13 @@ This command is typically invoked automatically by the Claude CLI via
 				cancel()
 			}()
 
+			if httpAddr != "" {
+				tenants := &tenantRegistries{store: store, repoPaths: repoPaths, logger: logger}
+				return serveMCPHTTP(ctx, cfg, httpAddr, tenants)
+			}
+
+			server := mcp.NewServer(newToolRegistry(store, repoPaths, logger))
+
 			// Redirect any fmt.Fprintf to stderr so stdout is clean for JSON-RPC.
 			fmt.Fprintln(os.Stderr, "codeeagle MCP server started")
 
@@ -104,6 +128,213 @@ This command is typically invoked automatically by the Claude CLI via
 	}
 
 	cmd.Flags().StringVar(&logFile, "log", "", "path to write tool call logs (used by Claude CLI verbose mode)")
+	cmd.Flags().StringVar(&httpAddr, "http", "", "serve over HTTP on this address (e.g. :8080) instead of stdio")
 
 	return cmd
 }
+
+// newToolRegistry returns the MCP tools answering queries on store. Source
+// files are read from repoPaths.
+func newToolRegistry(store graph.Store, repoPaths []string, logger func(format string, args ...any)) *agents.Registry {
+	registry := agents.NewRegistry()
+	for _, tool := range agents.NewPlannerTools(agents.NewContextBuilder(store, repoPaths...)) {
+		registry.Register(tool)
+	}
+	if logger != nil {
+		registry.SetLogger(logger)
+	}
+	return registry
+}
+
+// tenantRegistryLimit bounds the tool registries kept by tenantRegistries;
+// the least recently used one is dropped beyond it.
+var tenantRegistryLimit = 64
+
+// tenantRegistries holds the tool registry of each namespace served over
+// HTTP, built on first use for namespaces that hold data.
+type tenantRegistries struct {
+	store     *embedded.BranchStore
+	repoPaths []string
+	logger    func(format string, args ...any)
+	// owners resolves the CODEOWNERS of this project's files; nil when
+	// it has none.
+	owners func(string) []string
+
+	mu         sync.Mutex
+	registries map[string]*list.Element // of *tenantRegistry
+	order      *list.List               // front = most recently used
+}
+
+type tenantRegistry struct {
+	namespace string
+	registry  *agents.Registry
+}
+
+// view returns the store of a namespace. Other namespaces read all of
+// their branches, as their graphs are not tied to the server's checkout.
+func (t *tenantRegistries) view(namespace string) (*embedded.BranchStore, error) {
+	if namespace == "" {
+		// A view, so imports never swap the shared store's write branch.
+		return t.store.WithReadBranches(t.store.ReadBranches()), nil
+	}
+	view, err := t.store.WithNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := view.ListBranches()
+	if err != nil {
+		return nil, fmt.Errorf("list branches of %s: %w", namespace, err)
+	}
+	sort.Strings(branches)
+	return view.WithReadBranches(branches), nil
+}
+
+func (t *tenantRegistries) registry(namespace string) (*agents.Registry, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if el, ok := t.registries[namespace]; ok {
+		t.order.MoveToFront(el)
+		return el.Value.(*tenantRegistry).registry, nil
+	}
+	// A token granted every namespace must not build registries for
+	// namespaces that don't exist.
+	if namespace != "" {
+		namespaces, err := t.store.ListNamespaces(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("list namespaces: %w", err)
+		}
+		if !slices.Contains(namespaces, namespace) {
+			return nil, fmt.Errorf("namespace %q: %w", namespace, mcp.ErrNotFound)
+		}
+	}
+	view, err := t.view(namespace)
+	if err != nil {
+		return nil, err
+	}
+	// Only the default namespace is this project's graph; the server's
+	// source files are not exposed to other tenants.
+	var repoPaths []string
+	if namespace == "" {
+		repoPaths = t.repoPaths
+	}
+	if t.registries == nil {
+		t.registries = make(map[string]*list.Element)
+		t.order = list.New()
+	}
+	r := newToolRegistry(view, repoPaths, t.logger)
+	t.registries[namespace] = t.order.PushFront(&tenantRegistry{namespace: namespace, registry: r})
+	for t.order.Len() > tenantRegistryLimit {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.registries, oldest.Value.(*tenantRegistry).namespace)
+	}
+	return r, nil
+}
+
+func (t *tenantRegistries) export(ctx context.Context, namespace, branch string, w io.Writer) error {
+	view, err := t.view(namespace)
+	if err != nil {
+		return err
+	}
+	branches, err := view.ListBranches()
+	if err != nil {
+		return fmt.Errorf("list branches: %w", err)
+	}
+	if !containsAny(branches, []string{branch}) {
+		return fmt.Errorf("branch %q: %w", branch, mcp.ErrNotFound)
+	}
+	return view.ExportBranch(ctx, w, branch)
+}
+
+func (t *tenantRegistries) importBranch(ctx context.Context, namespace, branch string, r io.Reader) error {
+	view, err := t.view(namespace)
+	if err != nil {
+		return err
+	}
+	if _, err := view.ImportIntoBranch(ctx, r, branch); err != nil {
+		return fmt.Errorf("import %s: %w", branch, err)
+	}
+	// The namespace may have gained a branch its registry does not read.
+	t.mu.Lock()
+	if el, ok := t.registries[namespace]; ok {
+		t.order.Remove(el)
+		delete(t.registries, namespace)
+	}
+	t.mu.Unlock()
+	return nil
+}
+
+// catalog returns the Backstage catalog entities of a namespace. Only the
+// default namespace is owned through this project's CODEOWNERS.
+func (t *tenantRegistries) catalog(ctx context.Context, namespace string) ([]backstage.Entity, error) {
+	view, err := t.view(namespace)
+	if err != nil {
+		return nil, err
+	}
+	var opts backstage.Options
+	if namespace == "" {
+		opts.Owners = t.owners
+	}
+	return backstage.Entities(ctx, view, opts)
+}
+
+// serveMCPHTTP serves the tenants' graphs over HTTP until ctx is done.
+func serveMCPHTTP(ctx context.Context, cfg *config.Config, addr string, tenants *tenantRegistries) error {
+	auth, err := mcp.NewAuth(cfg.Serve.Tokens, os.Getenv)
+	if err != nil {
+		return err
+	}
+	if auth == nil && !isLoopbackAddr(addr) {
+		return fmt.Errorf("refusing to serve %s without authentication; configure serve.tokens or listen on a loopback address", addr)
+	}
+
+	if tenants.owners, err = loadCodeOwners(cfg); err != nil {
+		return err
+	}
+
+	opts := mcp.HTTPOptions{
+		Registry: tenants.registry,
+		Export:   tenants.export,
+		Import:   tenants.importBranch,
+		Catalog:  tenants.catalog,
+		Auth:     auth,
+	}
+	if path := cfg.Serve.AuditLog; path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cfg.ConfigDir, path)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("open audit log: %w", err)
+		}
+		defer f.Close()
+		opts.Audit = f
+	}
+
+	srv := &http.Server{Addr: addr, Handler: mcp.NewHTTPHandler(opts), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	fmt.Fprintf(os.Stderr, "codeeagle MCP server listening on %s\n", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("MCP server error: %w", err)
+	}
+	return nil
+}
+
+// isLoopbackAddr reports whether a listen address only accepts local
+// connections.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/mcp"
+)
+
+func TestTenantRegistries(t *testing.T) {
+	store := newTestGraphStore(t)
+	for i := 0; i < 3; i++ {
+		view, err := store.WithNamespace(fmt.Sprintf("team-%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		addTestNodes(t, view, &graph.Node{ID: "n1", Type: graph.NodeFunction, Name: "Run"})
+	}
+	limit := tenantRegistryLimit
+	tenantRegistryLimit = 2
+	t.Cleanup(func() { tenantRegistryLimit = limit })
+	tenants := &tenantRegistries{store: store}
+
+	for _, tt := range []struct {
+		namespace string
+		notFound  bool
+	}{
+		{"", false},
+		{"team-0", false},
+		{"team-1", false},
+		{"team-2", false},
+		{"team-9", true},
+	} {
+		_, err := tenants.registry(tt.namespace)
+		if got := errors.Is(err, mcp.ErrNotFound); got != tt.notFound || (err != nil && !got) {
+			t.Errorf("registry(%q) = %v, want not found %v", tt.namespace, err, tt.notFound)
+		}
+	}
+	if _, ok := tenants.registries["team-9"]; ok {
+		t.Error("registry built for a namespace without data")
+	}
+	if len(tenants.registries) != 2 || tenants.order.Len() != 2 {
+		t.Errorf("cache holds %d registries (%d in order), want 2", len(tenants.registries), tenants.order.Len())
+	}
+	for _, ns := range []string{"team-1", "team-2"} {
+		if _, ok := tenants.registries[ns]; !ok {
+			t.Errorf("recently used %s evicted", ns)
+		}
+	}
+}
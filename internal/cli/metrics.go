@@ -32,12 +32,43 @@ var extToLanguage = map[string]string{
 }
 
 func newMetricsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "metrics <file>",
+	var (
+		architecture bool
+		by           string
+		language     string
+		compare      string
+		limit        int
+		jsonOut      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "metrics <file> | --architecture",
 		Short: "Show code quality metrics",
-		Long:  `Show code quality metrics for a source file.`,
-		Args:  cobra.ExactArgs(1),
+		Long: `Show code quality metrics for a source file.
+
+With --architecture, report the coupling and abstraction metrics of each
+package (source directory) or service from the graph's call, implements,
+extends, dependency and error edges:
+
+  Ca  afferent coupling: packages depending on this one
+  Ce  efferent coupling: packages this one depends on
+  I   instability, Ce / (Ca + Ce)
+  A   abstractness, the share of interfaces and abstract classes
+  D   distance from the main sequence, |A + I - 1|
+
+followed by the fan-in (depending packages) and fan-out (implementations)
+of each interface. --compare <sha> shows how the metrics moved since a
+snapshot taken with 'codeeagle snapshot'.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if architecture {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if architecture {
+				return runArchitectureMetrics(ctx(cmd), cmd.OutOrStdout(), by, language, compare, limit, jsonOut)
+			}
 			filePath := args[0]
 
 			content, err := os.ReadFile(filePath)
@@ -78,6 +109,15 @@ func newMetricsCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&architecture, "architecture", false, "report package coupling, instability and abstractness from the graph")
+	cmd.Flags().StringVar(&by, "by", "package", "with --architecture: group by package or service")
+	cmd.Flags().StringVar(&language, "language", "", "with --architecture: filter by language")
+	cmd.Flags().StringVar(&compare, "compare", "", "with --architecture: compare with the snapshot of this commit")
+	cmd.Flags().IntVar(&limit, "limit", 0, "with --architecture: show at most this many packages and interfaces")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "with --architecture: output as JSON")
+
+	return cmd
 }
 
 // detectLanguage maps a file extension to a language name.
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+)
+
+func newNamespaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "namespace",
+		Short: "Manage graph namespaces in a shared graph database",
+		Long: `Manage the namespaces (tenants) of a graph database.
+
+A namespace isolates one team's or repository's graph from the others in a
+shared database, so one server can host many graphs. Select it with the
+--namespace flag or graph.namespace in the config; without one, commands use
+the default namespace.`,
+	}
+	cmd.AddCommand(newNamespaceListCmd())
+	cmd.AddCommand(newNamespaceDeleteCmd())
+	return cmd
+}
+
+// namespaceInfo summarizes a namespace for 'namespace list'.
+type namespaceInfo struct {
+	Namespace string   `json:"namespace"`
+	Branches  []string `json:"branches"`
+	Nodes     int64    `json:"nodes"`
+	Edges     int64    `json:"edges"`
+}
+
+func newNamespaceListCmd() *cobra.Command {
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the namespaces with their branches and graph sizes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			namespaces, err := store.ListNamespaces(ctx(cmd))
+			if err != nil {
+				return fmt.Errorf("list namespaces: %w", err)
+			}
+			list := make([]namespaceInfo, 0, len(namespaces))
+			for _, ns := range namespaces {
+				view, err := store.WithNamespace(ns)
+				if err != nil {
+					return err
+				}
+				branches, err := view.ListBranches()
+				if err != nil {
+					return fmt.Errorf("list branches of %s: %w", ns, err)
+				}
+				stats, err := view.WithReadBranches(branches).Stats(ctx(cmd))
+				if err != nil {
+					return fmt.Errorf("stats of %s: %w", ns, err)
+				}
+				list = append(list, namespaceInfo{Namespace: ns, Branches: branches, Nodes: stats.NodeCount, Edges: stats.EdgeCount})
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(list)
+			}
+			if len(list) == 0 {
+				fmt.Fprintln(out, "No namespaces.")
+				return nil
+			}
+			for _, ns := range list {
+				fmt.Fprintf(out, "%-24s  %6d nodes  %6d edges  %s\n", ns.Namespace, ns.Nodes, ns.Edges, strings.Join(ns.Branches, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	return cmd
+}
+
+func newNamespaceDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <namespace>",
+		Short: "Delete a namespace and all of its graph branches",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			namespaces, err := store.ListNamespaces(ctx(cmd))
+			if err != nil {
+				return fmt.Errorf("list namespaces: %w", err)
+			}
+			if !containsAny(namespaces, args[:1]) {
+				return fmt.Errorf("namespace %q not found", args[0])
+			}
+			if err := store.DeleteNamespace(ctx(cmd), args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted namespace %s\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/notify"
+)
+
+// changeNotifier reports graph changes made by indexing to the notify
+// subscriptions, comparing each state of the graph with the previous one.
+type changeNotifier struct {
+	notifier *notify.Notifier
+	branch   string
+	prev     *notify.Snapshot
+	logFn    func(string, ...any)
+}
+
+// newChangeNotifier captures the current graph state. It returns nil when
+// no subscriptions are configured.
+func newChangeNotifier(ctx context.Context, cfg *config.Config, store graph.Store, branch string, logFn func(string, ...any)) (*changeNotifier, error) {
+	n, err := notify.New(cfg.Notify, cfg.ConfigDir)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	prev, err := notify.Capture(ctx, store, endpointTested)
+	if err != nil {
+		return nil, err
+	}
+	return &changeNotifier{notifier: n, branch: branch, prev: prev, logFn: logFn}, nil
+}
+
+// check notifies the subscriptions of the changes since the last check.
+// Failures are logged rather than returned, so they never fail indexing.
+func (c *changeNotifier) check(ctx context.Context, store graph.Store) {
+	if c == nil {
+		return
+	}
+	cur, err := notify.Capture(ctx, store, endpointTested)
+	if err != nil {
+		c.logFn("Warning: capture graph changes: %v", err)
+		return
+	}
+	events := notify.Diff(c.prev, cur, time.Now().UTC())
+	c.prev = cur
+	if len(events) == 0 {
+		return
+	}
+	for i := range events {
+		events[i].Branch = c.branch
+	}
+	c.logFn("[notify] %d graph change event(s)", len(events))
+	if err := c.notifier.Notify(ctx, events); err != nil {
+		c.logFn("Warning: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/indexer"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+	"github.com/imyousuf/CodeEagle/internal/parser/external"
+)
+
+// registerExternalParsers adds the parser processes configured under
+// parsers.external. They are registered after the built-in parsers, so an
+// external parser for an existing language or extension replaces it.
+func registerExternalParsers(registry *parser.Registry, cfg *config.Config) error {
+	dir := projectDir(cfg)
+	for _, pc := range cfg.Parsers.External {
+		command := append([]string(nil), pc.Command...)
+		if len(command) > 0 && strings.Contains(command[0], "/") && !filepath.IsAbs(command[0]) && dir != "" {
+			command[0] = filepath.Join(dir, command[0])
+		}
+		p, err := external.NewParser(external.Config{
+			Language:   pc.Language,
+			Extensions: pc.Extensions,
+			Filenames:  pc.Filenames,
+			Command:    command,
+			Dir:        dir,
+			Timeout:    time.Duration(pc.TimeoutSeconds) * time.Second,
+		})
+		if err != nil {
+			return fmt.Errorf("register parser: %w", err)
+		}
+		registry.Register(p)
+	}
+	return nil
+}
+
+// projectDir returns the directory external parser commands run in: the
+// directory holding .CodeEagle.conf, else the parent of the config directory.
+func projectDir(cfg *config.Config) string {
+	if cfg.ProjectConfDir != "" {
+		return cfg.ProjectConfDir
+	}
+	if cfg.ConfigDir != "" {
+		return filepath.Dir(cfg.ConfigDir)
+	}
+	return ""
+}
+
+// newFileFilter builds the indexer's file filter from the index config.
+func newFileFilter(cfg *config.Config) *indexer.FileFilter {
+	filter := &indexer.FileFilter{
+		MaxFileSize:      cfg.Index.MaxFileSize,
+		SkipVendored:     cfg.Index.SkipVendored,
+		Generated:        indexer.GeneratedMode(cfg.Index.Generated),
+		GeneratedMarkers: cfg.Index.GeneratedMarkers,
+	}
+	if len(cfg.Index.Languages) > 0 {
+		filter.Languages = make(map[parser.Language]indexer.LanguageFilter, len(cfg.Index.Languages))
+		for _, l := range cfg.Index.Languages {
+			filter.Languages[parser.Language(l.Language)] = indexer.LanguageFilter{
+				Include:     l.Include,
+				Exclude:     l.Exclude,
+				MaxFileSize: l.MaxFileSize,
+			}
+		}
+	}
+	return filter
+}
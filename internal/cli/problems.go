@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// Problem rule identifiers.
+const (
+	ruleDeadCode      = "dead-code"
+	ruleMissingAuth   = "missing-auth"
+	ruleContractDrift = "contract-drift"
+)
+
+// allProblemRules lists every rule in the order they are evaluated.
+var allProblemRules = []string{ruleDeadCode, ruleMissingAuth, ruleContractDrift}
+
+// problem is a single graph-derived finding anchored to a source location.
+type problem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+func newProblemsCmd() *cobra.Command {
+	var (
+		format          string
+		output          string
+		rules           []string
+		includeExported bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "problems",
+		Short: "Export graph-derived findings as editor problem markers",
+		Long: `Export findings from the knowledge graph in formats editors can show
+inline as problem markers:
+
+  dead-code       functions and methods with no incoming calls
+  missing-auth    endpoints without an auth annotation/decorator in a service
+                  where other endpoints have one
+  contract-drift  API calls that match no known endpoint (calls marked
+                  external in the unresolved backlog are skipped)
+
+Formats:
+  text        file:line:col: severity: message [rule] (VS Code "$gcc" matcher)
+  json        array of {file, line, column, severity, rule, message}
+  checkstyle  Checkstyle XML (JetBrains, SonarQube, CI reporters)
+
+File paths are relative to the repository root.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			var backlog *linker.Backlog
+			if path := backlogPath(cfg); path != "" {
+				backlog, err = linker.LoadBacklog(path)
+				if err != nil {
+					return fmt.Errorf("load unresolved backlog: %w", err)
+				}
+			}
+
+			problems, err := collectProblems(ctx(cmd), store, backlog, rules, includeExported)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if output != "" {
+				if dir := filepath.Dir(output); dir != "." {
+					if err := os.MkdirAll(dir, 0o755); err != nil {
+						return fmt.Errorf("create output dir: %w", err)
+					}
+				}
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("create output file: %w", err)
+				}
+				defer f.Close()
+				out = f
+			}
+
+			if err := writeProblems(out, format, problems); err != nil {
+				return err
+			}
+			if output != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d problem(s) to %s\n", len(problems), output)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, json, or checkstyle")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write to file instead of stdout")
+	cmd.Flags().StringSliceVar(&rules, "rules", nil, "rules to run (default: all): "+strings.Join(allProblemRules, ", "))
+	cmd.Flags().BoolVar(&includeExported, "include-exported", false, "include exported functions in dead-code findings")
+
+	return cmd
+}
+
+// collectProblems runs the requested rules (all when empty) against the graph
+// and returns the findings sorted by file, line, and rule.
+func collectProblems(ctx context.Context, store graph.Store, backlog *linker.Backlog, rules []string, includeExported bool) ([]problem, error) {
+	if len(rules) == 0 {
+		rules = allProblemRules
+	}
+
+	var problems []problem
+	for _, rule := range rules {
+		var (
+			found []problem
+			err   error
+		)
+		switch rule {
+		case ruleDeadCode:
+			found, err = deadCodeProblems(ctx, store, includeExported)
+		case ruleMissingAuth:
+			found, err = missingAuthProblems(ctx, store)
+		case ruleContractDrift:
+			found, err = contractDriftProblems(ctx, store, backlog)
+		default:
+			return nil, fmt.Errorf("unknown rule %q (valid: %s)", rule, strings.Join(allProblemRules, ", "))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rule, err)
+		}
+		problems = append(problems, found...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Rule < problems[j].Rule
+	})
+	return problems, nil
+}
+
+func deadCodeProblems(ctx context.Context, store graph.Store, includeExported bool) ([]problem, error) {
+	unused, err := findUnused(ctx, store, []graph.NodeType{graph.NodeFunction, graph.NodeMethod}, "", "", includeExported)
+	if err != nil {
+		return nil, err
+	}
+	problems := make([]problem, 0, len(unused))
+	for _, u := range unused {
+		problems = append(problems, problem{
+			File:     u.FilePath,
+			Line:     max(u.Line, 1),
+			Column:   1,
+			Severity: "info",
+			Rule:     ruleDeadCode,
+			Message:  fmt.Sprintf("%s %s has no callers in the indexed code", strings.ToLower(string(u.Type)), u.Name),
+		})
+	}
+	return problems, nil
+}
+
+// missingAuthProblems flags endpoints with no auth annotation or
+// middleware, but only in services where at least one other endpoint has
+// one. Services without any are assumed to handle auth elsewhere (a
+// gateway) and are not reported.
+func missingAuthProblems(ctx context.Context, store graph.Store) ([]problem, error) {
+	endpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return nil, fmt.Errorf("query endpoints: %w", err)
+	}
+
+	type candidate struct {
+		ep   *graph.Node
+		auth string // "protected", "public", or "" (no marker)
+	}
+	byService := make(map[string][]candidate)
+	for _, ep := range endpoints {
+		auth, err := endpointAuth(ctx, store, ep)
+		if err != nil {
+			return nil, err
+		}
+		svc := linker.ServiceGroup(ep.FilePath)
+		byService[svc] = append(byService[svc], candidate{ep: ep, auth: auth})
+	}
+
+	var problems []problem
+	for svc, cands := range byService {
+		protected := 0
+		for _, c := range cands {
+			if c.auth == "protected" {
+				protected++
+			}
+		}
+		if protected == 0 {
+			continue
+		}
+		for _, c := range cands {
+			if c.auth != "" {
+				continue
+			}
+			problems = append(problems, problem{
+				File:     c.ep.FilePath,
+				Line:     max(c.ep.Line, 1),
+				Column:   1,
+				Severity: "warning",
+				Rule:     ruleMissingAuth,
+				Message: fmt.Sprintf("endpoint %s has no auth annotation or middleware, but %d other endpoint(s) in %s do",
+					c.ep.Name, protected, svc),
+			})
+		}
+	}
+	return problems, nil
+}
+
+// endpointAuth returns an endpoint's auth requirement: "protected",
+// "public" (explicitly anonymous), or "" when nothing marks it. Graphs
+// linked before auth requirements were recorded are classified from the
+// handler's annotations.
+func endpointAuth(ctx context.Context, store graph.Store, ep *graph.Node) (string, error) {
+	if auth := ep.Properties[parser.PropAuth]; auth != "" {
+		return auth, nil
+	}
+	req, err := linker.EndpointAuth(ctx, store, ep)
+	if err != nil {
+		return "", fmt.Errorf("classify auth of %s: %w", ep.Name, err)
+	}
+	return req.Status, nil
+}
+
+func contractDriftProblems(ctx context.Context, store graph.Store, backlog *linker.Backlog) ([]problem, error) {
+	lnk := linker.NewLinker(store, nil, nil, false)
+	calls, err := lnk.UnresolvedAPICalls(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query api calls: %w", err)
+	}
+
+	var problems []problem
+	for _, call := range calls {
+		if call.Properties["path"] == "" || backlog.IsExternal(call) {
+			continue
+		}
+		method := call.Properties["http_method"]
+		if method == "" {
+			method = "UNKNOWN"
+		}
+		problems = append(problems, problem{
+			File:     call.FilePath,
+			Line:     max(call.Line, 1),
+			Column:   1,
+			Severity: "warning",
+			Rule:     ruleContractDrift,
+			Message:  fmt.Sprintf("API call %s %s matches no known endpoint", method, call.Properties["path"]),
+		})
+	}
+	return problems, nil
+}
+
+// checkstyle XML document types.
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeProblems renders problems in the given format.
+func writeProblems(w io.Writer, format string, problems []problem) error {
+	switch format {
+	case "text", "":
+		for _, p := range problems {
+			fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s]\n", p.File, p.Line, p.Column, p.Severity, p.Message, p.Rule)
+		}
+		return nil
+	case "json":
+		if problems == nil {
+			problems = []problem{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(problems)
+	case "checkstyle":
+		report := checkstyleReport{Version: "4.3"}
+		index := make(map[string]int)
+		for _, p := range problems {
+			i, ok := index[p.File]
+			if !ok {
+				i = len(report.Files)
+				index[p.File] = i
+				report.Files = append(report.Files, checkstyleFile{Name: p.File})
+			}
+			report.Files[i].Errors = append(report.Files[i].Errors, checkstyleError{
+				Line:     p.Line,
+				Column:   p.Column,
+				Severity: p.Severity,
+				Message:  p.Message,
+				Source:   "codeeagle." + p.Rule,
+			})
+		}
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("encode checkstyle: %w", err)
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	default:
+		return fmt.Errorf("unknown format %q (valid: text, json, checkstyle)", format)
+	}
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+func TestCollectProblems(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	addTestNodes(t, store,
+		// dead code
+		&graph.Node{ID: "fn-dead", Type: graph.NodeFunction, Name: "helper", FilePath: "api/util.go", Line: 12, Language: "go"},
+		&graph.Node{ID: "fn-live", Type: graph.NodeFunction, Name: "caller", FilePath: "api/util.go", Line: 20, Language: "go"},
+		&graph.Node{ID: "fn-callee", Type: graph.NodeFunction, Name: "callee", FilePath: "api/util.go", Line: 30, Language: "go"},
+		// missing auth: controller with one protected and one unprotected action
+		&graph.Node{ID: "cls", Type: graph.NodeClass, Name: "UsersController", FilePath: "api/UsersController.cs",
+			Properties: map[string]string{"annotations": "ApiController"}},
+		&graph.Node{ID: "m-get", Type: graph.NodeMethod, Name: "Get", FilePath: "api/UsersController.cs", Exported: true,
+			Properties: map[string]string{"annotations": "HttpGet,Authorize"}},
+		&graph.Node{ID: "m-del", Type: graph.NodeMethod, Name: "Delete", FilePath: "api/UsersController.cs", Exported: true,
+			Properties: map[string]string{"annotations": "HttpDelete"}},
+		&graph.Node{ID: "m-login", Type: graph.NodeMethod, Name: "Login", FilePath: "api/UsersController.cs", Exported: true,
+			Properties: map[string]string{"annotations": "HttpPost,AllowAnonymous"}},
+		&graph.Node{ID: "ep-get", Type: graph.NodeAPIEndpoint, Name: "GET /users", FilePath: "api/UsersController.cs", Line: 10},
+		&graph.Node{ID: "ep-del", Type: graph.NodeAPIEndpoint, Name: "DELETE /users/{id}", FilePath: "api/UsersController.cs", Line: 20},
+		&graph.Node{ID: "ep-login", Type: graph.NodeAPIEndpoint, Name: "POST /login", FilePath: "api/UsersController.cs", Line: 30},
+		// service with no auth annotations at all is not reported
+		&graph.Node{ID: "ep-other", Type: graph.NodeAPIEndpoint, Name: "GET /health", FilePath: "ops/routes.py", Line: 3},
+		// contract drift
+		&graph.Node{ID: "call-1", Type: graph.NodeDependency, Name: "GET /api/orders", FilePath: "web/src/api.ts", Line: 7,
+			Properties: map[string]string{"kind": "api_call", "http_method": "GET", "path": "/api/orders"}},
+		&graph.Node{ID: "call-ext", Type: graph.NodeDependency, Name: "POST https://stripe.com/v1/charges", FilePath: "web/src/pay.ts", Line: 4,
+			Properties: map[string]string{"kind": "api_call", "http_method": "POST", "path": "/v1/charges"}},
+	)
+	addTestEdges(t, store,
+		&graph.Edge{ID: "c1", Type: graph.EdgeCalls, SourceID: "fn-live", TargetID: "fn-callee"},
+		&graph.Edge{ID: "k1", Type: graph.EdgeContains, SourceID: "cls", TargetID: "m-get"},
+		&graph.Edge{ID: "k2", Type: graph.EdgeContains, SourceID: "cls", TargetID: "m-del"},
+		&graph.Edge{ID: "k3", Type: graph.EdgeContains, SourceID: "cls", TargetID: "m-login"},
+		&graph.Edge{ID: "x1", Type: graph.EdgeExposes, SourceID: "m-get", TargetID: "ep-get"},
+		&graph.Edge{ID: "x2", Type: graph.EdgeExposes, SourceID: "m-del", TargetID: "ep-del"},
+		&graph.Edge{ID: "x3", Type: graph.EdgeExposes, SourceID: "m-login", TargetID: "ep-login"},
+	)
+
+	// Mark the Stripe call as intentionally external.
+	backlog := &linker.Backlog{}
+	lnk := linker.NewLinker(store, nil, nil, false)
+	if _, err := lnk.UpdateBacklog(ctx, backlog, time.Now()); err != nil {
+		t.Fatalf("UpdateBacklog: %v", err)
+	}
+	if err := backlog.SetStatus("web/src/pay.ts|POST /v1/charges", linker.BacklogExternal, "", time.Now()); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+
+	problems, err := collectProblems(ctx, store, backlog, nil, false)
+	if err != nil {
+		t.Fatalf("collectProblems: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, p := range problems {
+		got[p.Rule+" "+p.File] += p.Message + ";"
+	}
+	if !strings.Contains(got["dead-code api/util.go"], "function caller") {
+		t.Errorf("expected dead-code finding for caller, got %v", got)
+	}
+	if strings.Contains(got["dead-code api/util.go"], "callee") {
+		t.Errorf("callee has a caller and should not be reported: %v", got)
+	}
+	auth := got["missing-auth api/UsersController.cs"]
+	if !strings.Contains(auth, "DELETE /users/{id}") || strings.Contains(auth, "GET /users") || strings.Contains(auth, "POST /login") {
+		t.Errorf("missing-auth findings = %q, want only DELETE /users/{id}", auth)
+	}
+	if _, ok := got["missing-auth ops/routes.py"]; ok {
+		t.Error("service without auth annotations should not be reported")
+	}
+	if !strings.Contains(got["contract-drift web/src/api.ts"], "GET /api/orders") {
+		t.Errorf("expected contract-drift finding, got %v", got)
+	}
+	if _, ok := got["contract-drift web/src/pay.ts"]; ok {
+		t.Error("external call should not be reported")
+	}
+
+	if _, err := collectProblems(ctx, store, nil, []string{"bogus"}, false); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+}
+
+func TestWriteProblems(t *testing.T) {
+	problems := []problem{
+		{File: "a/b.go", Line: 3, Column: 1, Severity: "info", Rule: ruleDeadCode, Message: "function f has no callers"},
+		{File: "a/b.go", Line: 9, Column: 1, Severity: "warning", Rule: ruleContractDrift, Message: `call "x" & <y>`},
+	}
+
+	var text bytes.Buffer
+	if err := writeProblems(&text, "text", problems); err != nil {
+		t.Fatalf("text: %v", err)
+	}
+	if !strings.HasPrefix(text.String(), "a/b.go:3:1: info: function f has no callers [dead-code]\n") {
+		t.Errorf("text output = %q", text.String())
+	}
+
+	var js bytes.Buffer
+	if err := writeProblems(&js, "json", problems); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var decoded []problem
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil || len(decoded) != 2 {
+		t.Fatalf("json decode: %v (%d problems)", err, len(decoded))
+	}
+
+	var empty bytes.Buffer
+	if err := writeProblems(&empty, "json", nil); err != nil || strings.TrimSpace(empty.String()) != "[]" {
+		t.Errorf("empty json = %q, err = %v", empty.String(), err)
+	}
+
+	var cs bytes.Buffer
+	if err := writeProblems(&cs, "checkstyle", problems); err != nil {
+		t.Fatalf("checkstyle: %v", err)
+	}
+	out := cs.String()
+	if strings.Count(out, "<file ") != 1 || strings.Count(out, "<error ") != 2 {
+		t.Errorf("checkstyle should group errors by file:\n%s", out)
+	}
+	if !strings.Contains(out, `source="codeeagle.contract-drift"`) || !strings.Contains(out, "&amp; &lt;y&gt;") {
+		t.Errorf("checkstyle output missing source or escaping:\n%s", out)
+	}
+
+	if err := writeProblems(&bytes.Buffer{}, "sarif-ish", problems); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/indexer"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// runOutput is the output of a long-running indexing or linking command.
+// With --log-format=json every line the command prints becomes a JSON log
+// record (stdout lines at INFO, stderr lines at WARN) and progress events
+// carry structured fields, so CI jobs get parseable logs. In text mode
+// progress is a bar on a terminal and a periodic line otherwise.
+type runOutput struct {
+	mu     sync.Mutex
+	out    io.Writer
+	errOut io.Writer
+	json   *slog.Logger
+	tty    bool
+	bar    bool // a progress bar is drawn on the current line
+}
+
+// addLogFormatFlag registers --log-format on a command.
+func addLogFormatFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVar(format, "log-format", "text", "log format: text or json (one JSON record per line)")
+}
+
+// newRunOutput wraps a command's stdout and stderr for the given log format.
+func newRunOutput(cmd *cobra.Command, format string) (*runOutput, error) {
+	stdout, stderr := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	o := &runOutput{}
+	switch format {
+	case "", "text":
+		o.tty = isTerminal(stdout) && !verbose
+		o.out = &barClearingWriter{o: o, w: stdout}
+		o.errOut = &barClearingWriter{o: o, w: stderr}
+	case "json":
+		o.json = slog.New(slog.NewJSONHandler(stdout, nil))
+		o.out = &logLineWriter{logger: o.json, level: slog.LevelInfo}
+		o.errOut = &logLineWriter{logger: slog.New(slog.NewJSONHandler(stderr, nil)), level: slog.LevelWarn}
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want text or json)", format)
+	}
+	return o, nil
+}
+
+// redirectOutput switches the command's stdout and stderr to a runOutput
+// for the format, so everything the command prints follows it.
+func redirectOutput(cmd *cobra.Command, format string) (*runOutput, error) {
+	o, err := newRunOutput(cmd, format)
+	if err != nil {
+		return nil, err
+	}
+	cmd.SetOut(o.out)
+	cmd.SetErr(o.errOut)
+	return o, nil
+}
+
+// Out returns the writer for regular command output.
+func (o *runOutput) Out() io.Writer { return o.out }
+
+// Err returns the writer for warnings.
+func (o *runOutput) Err() io.Writer { return o.errOut }
+
+// Logf is a logger for indexers and linkers.
+func (o *runOutput) Logf(format string, args ...any) {
+	fmt.Fprintf(o.out, format+"\n", args...)
+}
+
+// IndexProgress reports indexer progress.
+func (o *runOutput) IndexProgress(p indexer.Progress) {
+	switch {
+	case o.json != nil:
+		o.json.Info("index progress",
+			"dir", p.Dir, "files", p.Files, "total", p.Total, "nodes", p.Nodes, "edges", p.Edges,
+			"elapsed_ms", p.Elapsed.Milliseconds(), "eta_ms", p.ETA.Milliseconds(), "done", p.Done)
+	case o.tty:
+		if p.Done {
+			o.clearBar()
+			return
+		}
+		o.drawBar(fmt.Sprintf("Indexing %s %d/%d files, %d nodes, ETA %s",
+			bar(p.Files, p.Total), p.Files, p.Total, p.Nodes, formatETA(p.ETA)))
+	case !p.Done:
+		pct := 0
+		if p.Total > 0 {
+			pct = p.Files * 100 / p.Total
+		}
+		fmt.Fprintf(o.out, "  Progress: %d/%d files (%d%%), %d nodes, %d edges, ETA %s\n",
+			p.Files, p.Total, pct, p.Nodes, p.Edges, formatETA(p.ETA))
+	}
+}
+
+// LinkProgress reports a finished linker phase.
+func (o *runOutput) LinkProgress(p linker.PhaseProgress) {
+	switch {
+	case o.json != nil:
+		o.json.Info("link phase",
+			"phase", p.Phase, "index", p.Index, "total", p.Total, "linked", p.Linked,
+			"elapsed_ms", p.Elapsed.Milliseconds())
+	case o.tty:
+		if p.Index == p.Total {
+			o.clearBar()
+			return
+		}
+		o.drawBar(fmt.Sprintf("Linking %s %d/%d %s", bar(p.Index, p.Total), p.Index, p.Total, p.Phase))
+	}
+}
+
+// Event records a structured event in JSON mode; text mode prints nothing
+// because commands print their own summaries.
+func (o *runOutput) Event(msg string, attrs ...any) {
+	if o.json != nil {
+		o.json.Info(msg, attrs...)
+	}
+}
+
+// Flush writes any partial line buffered by a JSON writer.
+func (o *runOutput) Flush() {
+	for _, w := range []io.Writer{o.out, o.errOut} {
+		if lw, ok := w.(*logLineWriter); ok {
+			lw.flush()
+		}
+	}
+	o.clearBar()
+}
+
+func (o *runOutput) drawBar(line string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	w := o.out.(*barClearingWriter).w
+	fmt.Fprintf(w, "\r\033[K%s", line)
+	o.bar = true
+}
+
+func (o *runOutput) clearBar() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.clearBarLocked()
+}
+
+func (o *runOutput) clearBarLocked() {
+	if !o.bar {
+		return
+	}
+	if bw, ok := o.out.(*barClearingWriter); ok {
+		fmt.Fprint(bw.w, "\r\033[K")
+	}
+	o.bar = false
+}
+
+// barClearingWriter erases the progress bar before writing a line over it.
+type barClearingWriter struct {
+	o *runOutput
+	w io.Writer
+}
+
+func (b *barClearingWriter) Write(p []byte) (int, error) {
+	b.o.mu.Lock()
+	defer b.o.mu.Unlock()
+	b.o.clearBarLocked()
+	return b.w.Write(p)
+}
+
+// logLineWriter turns each line written to it into a log record.
+type logLineWriter struct {
+	mu     sync.Mutex
+	logger *slog.Logger
+	level  slog.Level
+	buf    []byte
+}
+
+func (l *logLineWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+		l.emit(string(l.buf[:i]))
+		l.buf = l.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+func (l *logLineWriter) flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.buf) > 0 {
+		l.emit(string(l.buf))
+		l.buf = nil
+	}
+}
+
+func (l *logLineWriter) emit(line string) {
+	if line = strings.TrimSpace(line); line != "" {
+		l.logger.Log(context.Background(), l.level, line)
+	}
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// bar renders a 20-cell progress bar.
+func bar(done, total int) string {
+	const width = 20
+	filled := 0
+	if total > 0 {
+		filled = min(width, done*width/total)
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// formatETA rounds an estimate to seconds; zero means unknown.
+func formatETA(d time.Duration) string {
+	if d <= 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
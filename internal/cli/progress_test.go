@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/indexer"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+func TestRunOutputJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	ro, err := redirectOutput(cmd, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Indexing %s...\n", "repo")
+	fmt.Fprint(cmd.ErrOrStderr(), "Warning: linker failed")
+	ro.IndexProgress(indexer.Progress{Dir: "/src", Files: 5, Total: 10, Nodes: 40, ETA: 3 * time.Second})
+	ro.LinkProgress(linker.PhaseProgress{Phase: "calls", Index: 2, Total: 14, Linked: 7})
+	ro.Event("index complete", "files", 10)
+	ro.Flush()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var r map[string]any
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("stdout line %q is not JSON: %v", line, err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d stdout records, want 4: %s", len(records), stdout.String())
+	}
+	if records[0]["msg"] != "Indexing repo..." {
+		t.Errorf("record 0 = %v", records[0])
+	}
+	if records[1]["msg"] != "index progress" || records[1]["files"] != 5.0 || records[1]["eta_ms"] != 3000.0 {
+		t.Errorf("progress record = %v", records[1])
+	}
+	if records[2]["msg"] != "link phase" || records[2]["phase"] != "calls" || records[2]["linked"] != 7.0 {
+		t.Errorf("link record = %v", records[2])
+	}
+	if records[3]["msg"] != "index complete" || records[3]["files"] != 10.0 {
+		t.Errorf("event record = %v", records[3])
+	}
+
+	var warn map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &warn); err != nil {
+		t.Fatalf("stderr %q is not JSON: %v", stderr.String(), err)
+	}
+	if warn["level"] != "WARN" || warn["msg"] != "Warning: linker failed" {
+		t.Errorf("stderr record = %v", warn)
+	}
+}
+
+func TestRunOutputText(t *testing.T) {
+	var stdout bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&stdout)
+	ro, err := redirectOutput(cmd, "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ro.IndexProgress(indexer.Progress{Files: 25, Total: 100, Nodes: 300, Edges: 500, ETA: 90 * time.Second})
+	ro.IndexProgress(indexer.Progress{Files: 100, Total: 100, Done: true})
+	ro.LinkProgress(linker.PhaseProgress{Phase: "calls", Index: 2, Total: 14})
+	ro.Event("index complete")
+
+	want := "  Progress: 25/100 files (25%), 300 nodes, 500 edges, ETA 1m30s\n"
+	if stdout.String() != want {
+		t.Errorf("text output = %q, want %q", stdout.String(), want)
+	}
+
+	if _, err := newRunOutput(cmd, "xml"); err == nil {
+		t.Error("newRunOutput(xml): want error")
+	}
+}
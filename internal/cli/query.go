@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -21,6 +22,7 @@ func newQueryCmd() *cobra.Command {
 		pkg         string
 		filePath    string
 		language    string
+		platform    string
 	)
 
 	cmd := &cobra.Command{
@@ -32,7 +34,7 @@ func newQueryCmd() *cobra.Command {
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			store, _, err := openBranchStore(cfg)
+			store, _, err := openReadOnlyBranchStore(cfg)
 			if err != nil {
 				return err
 			}
@@ -44,6 +46,7 @@ func newQueryCmd() *cobra.Command {
 				Package:     pkg,
 				FilePath:    filePath,
 				Language:    language,
+				Platform:    platform,
 			}
 
 			nodes, err := store.QueryNodes(context.Background(), filter)
@@ -57,20 +60,7 @@ func newQueryCmd() *cobra.Command {
 				return nil
 			}
 
-			fmt.Fprintf(out, "%-24s  %-14s  %-30s  %s\n", "ID", "Type", "Name", "Location")
-			fmt.Fprintf(out, "%-24s  %-14s  %-30s  %s\n", "------------------------", "--------------", "------------------------------", "--------")
-			for _, n := range nodes {
-				loc := ""
-				if n.FilePath != "" {
-					loc = n.FilePath
-					if n.Line > 0 {
-						loc = fmt.Sprintf("%s:%d", n.FilePath, n.Line)
-					}
-				}
-				fmt.Fprintf(out, "%-24s  %-14s  %-30s  %s\n", n.ID, n.Type, n.Name, loc)
-			}
-			fmt.Fprintf(out, "\n%d result(s)\n", len(nodes))
-
+			writeNodeTable(out, nodes)
 			return nil
 		},
 	}
@@ -80,16 +70,41 @@ func newQueryCmd() *cobra.Command {
 	cmd.Flags().StringVar(&pkg, "package", "", "filter by package name")
 	cmd.Flags().StringVar(&filePath, "file", "", "filter by file path")
 	cmd.Flags().StringVar(&language, "language", "", "filter by language")
+	cmd.Flags().StringVar(&platform, "platform", "", "keep symbols built for GOOS or GOOS/GOARCH (e.g. linux/amd64), per Go build constraints")
 
 	cmd.AddCommand(newQuerySymbolsCmd())
 	cmd.AddCommand(newQueryInterfaceCmd())
 	cmd.AddCommand(newQueryEdgesCmd())
 	cmd.AddCommand(newQueryUnusedCmd())
 	cmd.AddCommand(newQueryCoverageCmd())
+	cmd.AddCommand(newQueryErrorsCmd())
+	cmd.AddCommand(newQueryUnresolvedCmd())
+	cmd.AddCommand(newQueryTelemetryCmd())
+	cmd.AddCommand(newQueryIssueCmd())
+	cmd.AddCommand(newQueryPipelinesCmd())
+	cmd.AddCommand(newQueryAuthCmd())
 
 	return cmd
 }
 
+// writeNodeTable prints nodes as an ID/Type/Name/Location table.
+func writeNodeTable(out io.Writer, nodes []*graph.Node) {
+	fmt.Fprintf(out, "%-24s  %-14s  %-30s  %s\n", "ID", "Type", "Name", "Location")
+	fmt.Fprintf(out, "%-24s  %-14s  %-30s  %s\n", "------------------------", "--------------", "------------------------------", "--------")
+	for _, n := range nodes {
+		fmt.Fprintf(out, "%-24s  %-14s  %-30s  %s\n", n.ID, n.Type, n.Name, nodeLocation(n))
+	}
+	fmt.Fprintf(out, "\n%d result(s)\n", len(nodes))
+}
+
+// nodeLocation formats a node's file and line as "path:line".
+func nodeLocation(n *graph.Node) string {
+	if n.FilePath == "" || n.Line <= 0 {
+		return n.FilePath
+	}
+	return fmt.Sprintf("%s:%d", n.FilePath, n.Line)
+}
+
 func newQuerySymbolsCmd() *cobra.Command {
 	var (
 		filePath string
@@ -109,7 +124,7 @@ func newQuerySymbolsCmd() *cobra.Command {
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			store, _, err := openBranchStore(cfg)
+			store, _, err := openReadOnlyBranchStore(cfg)
 			if err != nil {
 				return err
 			}
@@ -257,7 +272,7 @@ func newQueryInterfaceCmd() *cobra.Command {
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			store, _, err := openBranchStore(cfg)
+			store, _, err := openReadOnlyBranchStore(cfg)
 			if err != nil {
 				return err
 			}
@@ -377,6 +392,7 @@ func newQueryEdgesCmd() *cobra.Command {
 		edgeType      string
 		direction     string
 		packageFilter string
+		minConfidence float64
 		jsonOut       bool
 	)
 
@@ -393,23 +409,24 @@ func newQueryEdgesCmd() *cobra.Command {
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			store, _, err := openBranchStore(cfg)
+			store, _, err := openReadOnlyBranchStore(cfg)
 			if err != nil {
 				return err
 			}
 			defer store.Close()
+			cached := graph.NewCachedStore(store, graph.DefaultCacheSize)
 
 			ctx := context.Background()
 
 			// Try to find the node by ID first, then by name.
-			node, err := store.GetNode(ctx, nodeArg)
+			node, err := cached.GetNode(ctx, nodeArg)
 			if err != nil || node == nil {
 				// Try name search, optionally filtered by package.
 				filter := graph.NodeFilter{NamePattern: nodeArg}
 				if packageFilter != "" {
 					filter.Package = packageFilter
 				}
-				candidates, qErr := store.QueryNodes(ctx, filter)
+				candidates, qErr := cached.QueryNodes(ctx, filter)
 				if qErr != nil {
 					return fmt.Errorf("query nodes: %w", qErr)
 				}
@@ -434,10 +451,11 @@ func newQueryEdgesCmd() *cobra.Command {
 			}
 
 			// Fetch all edges for this node.
-			edges, err := store.GetEdges(ctx, node.ID, graph.EdgeType(edgeType))
+			edges, err := cached.GetEdges(ctx, node.ID, graph.EdgeType(edgeType))
 			if err != nil {
 				return fmt.Errorf("get edges: %w", err)
 			}
+			edges = graph.FilterEdgesByConfidence(edges, minConfidence)
 
 			// Resolve other nodes and split into outgoing/incoming.
 			var outgoing, incoming []edgeEntry
@@ -452,7 +470,7 @@ func newQueryEdgesCmd() *cobra.Command {
 					isOutgoing = false
 				}
 
-				other, _ := store.GetNode(ctx, otherID)
+				other, _ := cached.GetNode(ctx, otherID)
 				entry := edgeEntry{
 					EdgeType:   e.Type,
 					NodeID:     otherID,
@@ -537,6 +555,7 @@ func newQueryEdgesCmd() *cobra.Command {
 	cmd.Flags().StringVar(&edgeType, "type", "", "filter by edge type (e.g. Calls, Implements)")
 	cmd.Flags().StringVar(&direction, "direction", "both", "edge direction: in, out, or both")
 	cmd.Flags().StringVar(&packageFilter, "package", "", "filter by package name (disambiguate common names)")
+	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0, "hide linker-inferred edges scoring below this confidence (0-1)")
 	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
 
 	return cmd
@@ -49,7 +49,7 @@ Test functions, init(), and main() are always excluded.`,
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			store, _, err := openBranchStore(cfg)
+			store, _, err := openReadOnlyBranchStore(cfg)
 			if err != nil {
 				return err
 			}
@@ -67,61 +67,11 @@ Test functions, init(), and main() are always excluded.`,
 				types = []graph.NodeType{nt}
 			}
 
-			var candidates []*graph.Node
-			for _, t := range types {
-				filter := graph.NodeFilter{
-					Type:     t,
-					Package:  pkg,
-					Language: language,
-				}
-				nodes, err := store.QueryNodes(ctx, filter)
-				if err != nil {
-					return fmt.Errorf("query %s nodes: %w", t, err)
-				}
-				candidates = append(candidates, nodes...)
-			}
-
-			// Filter and check for incoming Calls edges.
-			var unused []unusedEntry
-			for _, n := range candidates {
-				if shouldSkipForUnused(n, includeExported) {
-					continue
-				}
-
-				edges, err := store.GetEdges(ctx, n.ID, graph.EdgeCalls)
-				if err != nil {
-					return fmt.Errorf("get edges for %s: %w", n.Name, err)
-				}
-
-				hasIncoming := false
-				for _, e := range edges {
-					if e.TargetID == n.ID {
-						hasIncoming = true
-						break
-					}
-				}
-
-				if !hasIncoming {
-					unused = append(unused, unusedEntry{
-						ID:       n.ID,
-						Name:     n.Name,
-						Type:     n.Type,
-						FilePath: n.FilePath,
-						Line:     n.Line,
-						Package:  n.Package,
-						Language: n.Language,
-					})
-				}
+			unused, err := findUnused(ctx, store, types, pkg, language, includeExported)
+			if err != nil {
+				return err
 			}
 
-			// Sort by file path then line.
-			sort.Slice(unused, func(i, j int) bool {
-				if unused[i].FilePath != unused[j].FilePath {
-					return unused[i].FilePath < unused[j].FilePath
-				}
-				return unused[i].Line < unused[j].Line
-			})
-
 			out := cmd.OutOrStdout()
 
 			if jsonOut {
@@ -159,6 +109,58 @@ Test functions, init(), and main() are always excluded.`,
 	return cmd
 }
 
+// findUnused returns functions and methods of the given types that have no
+// incoming Calls edges, sorted by file path then line.
+func findUnused(ctx context.Context, store graph.Store, types []graph.NodeType, pkg, language string, includeExported bool) ([]unusedEntry, error) {
+	var candidates []*graph.Node
+	for _, t := range types {
+		filter := graph.NodeFilter{
+			Type:     t,
+			Package:  pkg,
+			Language: language,
+		}
+		nodes, err := store.QueryNodes(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		candidates = append(candidates, nodes...)
+	}
+
+	// Filter and check for incoming Calls edges.
+	var unused []unusedEntry
+	for _, n := range candidates {
+		if shouldSkipForUnused(n, includeExported) {
+			continue
+		}
+
+		callers, err := store.GetIncomingEdges(ctx, n.ID, graph.EdgeCalls)
+		if err != nil {
+			return nil, fmt.Errorf("get edges for %s: %w", n.Name, err)
+		}
+
+		if len(callers) == 0 {
+			unused = append(unused, unusedEntry{
+				ID:       n.ID,
+				Name:     n.Name,
+				Type:     n.Type,
+				FilePath: n.FilePath,
+				Line:     n.Line,
+				Package:  n.Package,
+				Language: n.Language,
+			})
+		}
+	}
+
+	// Sort by file path then line.
+	sort.Slice(unused, func(i, j int) bool {
+		if unused[i].FilePath != unused[j].FilePath {
+			return unused[i].FilePath < unused[j].FilePath
+		}
+		return unused[i].Line < unused[j].Line
+	})
+	return unused, nil
+}
+
 // shouldSkipForUnused returns true if the node should be excluded from unused analysis.
 func shouldSkipForUnused(n *graph.Node, includeExported bool) bool {
 	// Skip test functions.
@@ -220,7 +222,8 @@ func newQueryCoverageCmd() *cobra.Command {
 		Use:   "coverage",
 		Short: "Show test coverage gaps for files and functions",
 		Long: `Analyze which files or functions have test coverage by checking for
-EdgeTests relationships in the knowledge graph. Shows uncovered items
+EdgeTests relationships (or EdgeCovers from 'codeeagle coverage') in the
+knowledge graph. Shows uncovered items
 and per-package coverage percentages.
 
 Levels:
@@ -236,7 +239,7 @@ Levels:
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			store, _, err := openBranchStore(cfg)
+			store, _, err := openReadOnlyBranchStore(cfg)
 			if err != nil {
 				return err
 			}
@@ -305,19 +308,11 @@ func runFileCoverage(ctx context.Context, cmd *cobra.Command, store graph.Store,
 	// Check coverage for each source file.
 	var entries []coverageEntry
 	for _, f := range sourceFiles {
-		edges, err := store.GetEdges(ctx, f.ID, graph.EdgeTests)
+		covered, err := hasIncomingTestEdge(ctx, store, f.ID)
 		if err != nil {
 			return fmt.Errorf("get edges for %s: %w", f.FilePath, err)
 		}
 
-		covered := false
-		for _, e := range edges {
-			if e.TargetID == f.ID {
-				covered = true
-				break
-			}
-		}
-
 		entries = append(entries, coverageEntry{
 			ID:       f.ID,
 			Name:     f.Name,
@@ -423,19 +418,11 @@ func runFunctionCoverage(ctx context.Context, cmd *cobra.Command, store graph.St
 	// Check coverage for each function.
 	var entries []coverageEntry
 	for _, f := range sourceFuncs {
-		edges, err := store.GetEdges(ctx, f.ID, graph.EdgeTests)
+		covered, err := hasIncomingTestEdge(ctx, store, f.ID)
 		if err != nil {
 			return fmt.Errorf("get edges for %s: %w", f.Name, err)
 		}
 
-		covered := false
-		for _, e := range edges {
-			if e.TargetID == f.ID {
-				covered = true
-				break
-			}
-		}
-
 		entries = append(entries, coverageEntry{
 			ID:       f.ID,
 			Name:     f.Name,
@@ -561,6 +548,21 @@ func computePackageCoverage(entries []coverageEntry) []packageCoverage {
 // These duplicate the heuristics from internal/linker/tests.go to avoid
 // pulling in the linker package (which depends on pkg/llm).
 
+// hasIncomingTestEdge reports whether a node is the target of a Tests edge
+// (name heuristic) or a Covers edge (ingested coverage report).
+func hasIncomingTestEdge(ctx context.Context, store graph.Store, nodeID string) (bool, error) {
+	for _, et := range []graph.EdgeType{graph.EdgeTests, graph.EdgeCovers} {
+		edges, err := store.GetIncomingEdges(ctx, nodeID, et)
+		if err != nil {
+			return false, err
+		}
+		if len(edges) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // isTestFileByPath returns true if the file path matches test file naming conventions.
 func isTestFileByPath(filePath, language string) bool {
 	base := filepath.Base(filePath)
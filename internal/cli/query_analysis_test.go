@@ -12,7 +12,7 @@ import (
 	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
 )
 
-func newTestGraphStore(t *testing.T) graph.Store {
+func newTestGraphStore(t *testing.T) *embedded.BranchStore {
 	t.Helper()
 	store, err := embedded.NewStore(t.TempDir())
 	if err != nil {
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// authNone is the auth status of endpoints no annotation or middleware marks.
+const authNone = "none"
+
+// endpointAuthEntry is an API endpoint and its auth requirement.
+type endpointAuthEntry struct {
+	surfaceEntry
+	Service string `json:"service"`
+	// Auth is "protected", "public" or "none".
+	Auth  string   `json:"auth"`
+	Via   []string `json:"via,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func newQueryAuthCmd() *cobra.Command {
+	var (
+		service     string
+		missingOnly bool
+		jsonOut     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Map the auth requirements of API endpoints",
+		Long: `List API endpoints by service with their auth requirement, read from
+handler annotations ([Authorize], @PreAuthorize, @RolesAllowed,
+login_required), route middleware (requireAuth, passport.authenticate, Gin
+and Echo group middleware) and Rails before_action filters:
+
+  protected  an annotation or middleware requires an authenticated caller
+  public     explicitly anonymous ([AllowAnonymous], permitAll)
+  none       nothing marks the endpoint either way
+
+Use --missing to list only the endpoints without auth, for security
+review. Run 'codeeagle sync' first.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			entries, err := findEndpointAuth(ctx(cmd), store, service, missingOnly)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+			if len(entries) == 0 {
+				if missingOnly {
+					fmt.Fprintln(out, "No endpoints without auth.")
+				} else {
+					fmt.Fprintln(out, "No API endpoints found.")
+				}
+				return nil
+			}
+			writeEndpointAuth(out, entries)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&service, "service", "", "only list endpoints of this service")
+	cmd.Flags().BoolVar(&missingOnly, "missing", false, "only list endpoints without auth")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// findEndpointAuth returns the API endpoints of service (all services when
+// empty) with their auth requirements, sorted by service and location. With
+// missingOnly, only endpoints without auth are returned.
+func findEndpointAuth(ctx context.Context, store graph.Store, service string, missingOnly bool) ([]endpointAuthEntry, error) {
+	endpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return nil, fmt.Errorf("query endpoints: %w", err)
+	}
+
+	entries := []endpointAuthEntry{}
+	for _, ep := range endpoints {
+		svc := linker.ServiceGroup(ep.FilePath)
+		if service != "" && svc != service {
+			continue
+		}
+		req := parser.AuthRequirement{Status: ep.Properties[parser.PropAuth]}
+		if req.Status != "" {
+			req.Via = splitList(ep.Properties[parser.PropAuthVia])
+			req.Roles = splitList(ep.Properties[parser.PropAuthRoles])
+		} else if req, err = linker.EndpointAuth(ctx, store, ep); err != nil {
+			return nil, fmt.Errorf("classify auth of %s: %w", ep.Name, err)
+		}
+		if req.Status == "" {
+			req.Status = authNone
+		}
+		if missingOnly && req.Status != authNone {
+			continue
+		}
+		entries = append(entries, endpointAuthEntry{
+			surfaceEntry: newSurfaceEntry(ep, ""),
+			Service:      svc,
+			Auth:         req.Status,
+			Via:          req.Via,
+			Roles:        req.Roles,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+	return entries, nil
+}
+
+// writeEndpointAuth prints entries grouped by service, with each service's
+// count of endpoints without auth.
+func writeEndpointAuth(out io.Writer, entries []endpointAuthEntry) {
+	for i := 0; i < len(entries); {
+		svc := entries[i].Service
+		j, missing := i, 0
+		for ; j < len(entries) && entries[j].Service == svc; j++ {
+			if entries[j].Auth == authNone {
+				missing++
+			}
+		}
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%s (%d endpoints, %d without auth)\n", svc, j-i, missing)
+		for _, e := range entries[i:j] {
+			detail := strings.Join(e.Via, ",")
+			if len(e.Roles) > 0 {
+				detail += " [" + strings.Join(e.Roles, ",") + "]"
+			}
+			fmt.Fprintf(out, "  %-9s  %-40s  %-30s  %s\n", e.Auth, e.Name, detail, entryLocation(e.surfaceEntry))
+		}
+		i = j
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestFindEndpointAuth(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	addTestNodes(t, store,
+		&graph.Node{ID: "ep-users", Type: graph.NodeAPIEndpoint, Name: "GET /users", FilePath: "api/routes.go", Line: 10,
+			Properties: map[string]string{parser.PropAuth: parser.AuthProtected, parser.PropAuthVia: "Authorize", parser.PropAuthRoles: "Admin"}},
+		&graph.Node{ID: "ep-login", Type: graph.NodeAPIEndpoint, Name: "POST /login", FilePath: "api/routes.go", Line: 12,
+			Properties: map[string]string{parser.PropAuth: parser.AuthPublic, parser.PropAuthVia: "AllowAnonymous"}},
+		&graph.Node{ID: "ep-delete", Type: graph.NodeAPIEndpoint, Name: "DELETE /users/{id}", FilePath: "api/routes.go", Line: 14},
+		// Not yet linked: classified from its handler's decorators.
+		&graph.Node{ID: "ep-report", Type: graph.NodeAPIEndpoint, Name: "GET /report", FilePath: "ops/app.py", Line: 3},
+		&graph.Node{ID: "report", Type: graph.NodeFunction, Name: "report", FilePath: "ops/app.py",
+			Properties: map[string]string{"decorators": "app.get('/report'),login_required"}},
+		&graph.Node{ID: "ep-ping", Type: graph.NodeAPIEndpoint, Name: "GET /ping", FilePath: "ops/app.py", Line: 8},
+	)
+	addTestEdges(t, store,
+		&graph.Edge{ID: "x1", Type: graph.EdgeExposes, SourceID: "report", TargetID: "ep-report"},
+	)
+
+	entries, err := findEndpointAuth(ctx, store, "", false)
+	if err != nil {
+		t.Fatalf("findEndpointAuth: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Service+" "+e.Name+" "+e.Auth+" "+strings.Join(e.Via, ",")+" "+strings.Join(e.Roles, ","))
+	}
+	want := []string{
+		"api GET /users protected Authorize Admin",
+		"api POST /login public AllowAnonymous ",
+		"api DELETE /users/{id} none  ",
+		"ops GET /report protected login_required ",
+		"ops GET /ping none  ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("entries =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	missing, err := findEndpointAuth(ctx, store, "api", true)
+	if err != nil {
+		t.Fatalf("findEndpointAuth --missing: %v", err)
+	}
+	if len(missing) != 1 || missing[0].ID != "ep-delete" {
+		t.Errorf("missing = %+v, want only ep-delete", missing)
+	}
+
+	var out bytes.Buffer
+	writeEndpointAuth(&out, entries)
+	for _, s := range []string{"api (3 endpoints, 1 without auth)", "ops (2 endpoints, 1 without auth)", "Authorize [Admin]"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("output missing %q:\n%s", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// errorTypeNodeTypes are the node types that define error types.
+var errorTypeNodeTypes = []graph.NodeType{graph.NodeClass, graph.NodeStruct, graph.NodeType_, graph.NodeVariable}
+
+// surfaceEntry is a symbol on an error's surface.
+type surfaceEntry struct {
+	ID       string         `json:"id"`
+	Type     graph.NodeType `json:"type"`
+	Name     string         `json:"name"`
+	FilePath string         `json:"file_path"`
+	Line     int            `json:"line,omitempty"`
+	// Via is the throwing function an endpoint or job reaches.
+	Via string `json:"via,omitempty"`
+}
+
+// errorSurface lists where an error type can surface: the functions that
+// throw it and the endpoints and scheduled jobs whose calls reach them.
+type errorSurface struct {
+	Error     surfaceEntry   `json:"error"`
+	ThrownBy  []surfaceEntry `json:"thrown_by"`
+	Endpoints []surfaceEntry `json:"endpoints"`
+	Jobs      []surfaceEntry `json:"jobs"`
+}
+
+func newQueryErrorsCmd() *cobra.Command {
+	var (
+		depth   int
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "errors <ErrorType>",
+		Short: "Show which functions, endpoints and jobs can surface an error type",
+		Long: `Find the error or exception types (classes, Go error structs and sentinel
+errors) with the given name and list the functions that throw, raise,
+panic with or return them, and the API endpoints and scheduled jobs whose
+call trees reach those functions within --depth calls (0 for no limit).
+
+Throws edges come from the parsers and the linker's errors phase; run
+'codeeagle sync' first.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			cached := graph.NewCachedStore(store, graph.DefaultCacheSize)
+			surfaces, err := findErrorSurfaces(ctx(cmd), cached, args[0], depth)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(surfaces)
+			}
+			if len(surfaces) == 0 {
+				fmt.Fprintf(out, "No error type named %q found.\n", args[0])
+				return nil
+			}
+			for i, s := range surfaces {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				writeErrorSurface(out, s)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&depth, "depth", 10, "maximum number of calls between an endpoint and the throwing function (0 for no limit)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// findErrorSurfaces returns the surface of each error type named name: a
+// node marked as an error type or the target of a Throws edge.
+func findErrorSurfaces(ctx context.Context, store graph.Store, name string, depth int) ([]errorSurface, error) {
+	var surfaces []errorSurface
+	for _, t := range errorTypeNodeTypes {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: t, NamePattern: name})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		for _, n := range nodes {
+			throws, err := store.GetIncomingEdges(ctx, n.ID, graph.EdgeThrows)
+			if err != nil {
+				return nil, fmt.Errorf("get throws of %s: %w", n.Name, err)
+			}
+			if len(throws) == 0 && n.Properties[parser.PropErrorType] != "true" {
+				continue
+			}
+			s, err := errorSurfaceOf(ctx, store, n, throws, depth)
+			if err != nil {
+				return nil, err
+			}
+			surfaces = append(surfaces, s)
+		}
+	}
+	return surfaces, nil
+}
+
+// errorSurfaceOf walks callers back from the functions throwing an error
+// type, breadth first, collecting the endpoints and jobs reached.
+func errorSurfaceOf(ctx context.Context, store graph.Store, errNode *graph.Node, throws []*graph.Edge, depth int) (errorSurface, error) {
+	s := errorSurface{
+		Error:     newSurfaceEntry(errNode, ""),
+		ThrownBy:  []surfaceEntry{},
+		Endpoints: []surfaceEntry{},
+		Jobs:      []surfaceEntry{},
+	}
+
+	type step struct{ id, via string }
+	visited := make(map[string]bool)
+	var level []step
+	for _, e := range throws {
+		if visited[e.SourceID] {
+			continue
+		}
+		visited[e.SourceID] = true
+		fn, err := store.GetNode(ctx, e.SourceID)
+		if err != nil {
+			continue
+		}
+		s.ThrownBy = append(s.ThrownBy, newSurfaceEntry(fn, ""))
+		level = append(level, step{fn.ID, callLabel(fn)})
+	}
+
+	for d := 0; len(level) > 0 && (depth <= 0 || d < depth); d++ {
+		var next []step
+		for _, st := range level {
+			callers, err := callNeighbors(ctx, store, st.id, true)
+			if err != nil {
+				return s, err
+			}
+			for _, n := range callers {
+				if visited[n.ID] {
+					continue
+				}
+				visited[n.ID] = true
+				switch n.Type {
+				case graph.NodeAPIEndpoint:
+					s.Endpoints = append(s.Endpoints, newSurfaceEntry(n, st.via))
+				case graph.NodeJob:
+					s.Jobs = append(s.Jobs, newSurfaceEntry(n, st.via))
+				default:
+					next = append(next, step{n.ID, st.via})
+				}
+			}
+		}
+		level = next
+	}
+
+	for _, list := range [][]surfaceEntry{s.ThrownBy, s.Endpoints, s.Jobs} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].FilePath != list[j].FilePath {
+				return list[i].FilePath < list[j].FilePath
+			}
+			return list[i].Line < list[j].Line
+		})
+	}
+	return s, nil
+}
+
+func newSurfaceEntry(n *graph.Node, via string) surfaceEntry {
+	return surfaceEntry{
+		ID:       n.ID,
+		Type:     n.Type,
+		Name:     callLabel(n),
+		FilePath: n.FilePath,
+		Line:     n.Line,
+		Via:      via,
+	}
+}
+
+func writeErrorSurface(out io.Writer, s errorSurface) {
+	fmt.Fprintf(out, "%s (%s)  %s\n", s.Error.Name, s.Error.Type, entryLocation(s.Error))
+	sections := []struct {
+		title   string
+		entries []surfaceEntry
+	}{
+		{"Thrown by", s.ThrownBy},
+		{"Endpoints", s.Endpoints},
+		{"Jobs", s.Jobs},
+	}
+	for _, sec := range sections {
+		if len(sec.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s (%d):\n", sec.title, len(sec.entries))
+		for _, e := range sec.entries {
+			via := ""
+			if e.Via != "" {
+				via = "  via " + e.Via
+			}
+			fmt.Fprintf(out, "  %-40s  %s%s\n", e.Name, entryLocation(e), via)
+		}
+	}
+	if len(s.ThrownBy) == 0 {
+		fmt.Fprintln(out, "\nNo throw sites found.")
+	}
+}
+
+func entryLocation(e surfaceEntry) string {
+	if e.Line <= 0 {
+		return e.FilePath
+	}
+	return fmt.Sprintf("%s:%d", e.FilePath, e.Line)
+}
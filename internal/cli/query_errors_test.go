@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestFindErrorSurfaces(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	node := func(id string, typ graph.NodeType, name, file string, line int) *graph.Node {
+		return &graph.Node{ID: id, Type: typ, Name: name, FilePath: file, Line: line}
+	}
+	edge := func(typ graph.EdgeType, src, tgt string) *graph.Edge {
+		return &graph.Edge{ID: graph.NewEdgeID(typ, src, tgt), Type: typ, SourceID: src, TargetID: tgt}
+	}
+
+	declined := node("declined", graph.NodeClass, "PaymentDeclinedError", "pay/errors.py", 3)
+	unused := node("unused", graph.NodeClass, "PaymentDeclinedError", "legacy/errors.py", 1)
+	unused.Properties = map[string]string{parser.PropErrorType: "true"}
+	addTestNodes(t, store,
+		declined, unused,
+		node("model", graph.NodeClass, "PaymentDeclinedError", "pay/models.py", 9), // not an error type
+		node("charge", graph.NodeFunction, "charge", "pay/gateway.py", 10),
+		node("checkout", graph.NodeFunction, "checkout", "pay/service.py", 20),
+		node("retry", graph.NodeFunction, "retry", "pay/service.py", 40),
+		node("ep", graph.NodeAPIEndpoint, "POST /checkout", "pay/routes.py", 5),
+		node("job", graph.NodeJob, "nightly-retry", "pay/schedule.yml", 2),
+	)
+	addTestEdges(t, store,
+		edge(graph.EdgeThrows, "charge", "declined"),
+		edge(graph.EdgeCalls, "checkout", "charge"),
+		edge(graph.EdgeCalls, "retry", "checkout"),
+		edge(graph.EdgeCalls, "ep", "checkout"),
+		edge(graph.EdgeCalls, "job", "retry"),
+	)
+
+	surfaces, err := findErrorSurfaces(ctx, store, "PaymentDeclinedError", 10)
+	if err != nil {
+		t.Fatalf("findErrorSurfaces: %v", err)
+	}
+	if len(surfaces) != 2 {
+		t.Fatalf("got %d surfaces, want 2 (the thrown class and the marked one)", len(surfaces))
+	}
+	var s errorSurface
+	for _, cand := range surfaces {
+		if cand.Error.ID == "declined" {
+			s = cand
+		}
+	}
+	if len(s.ThrownBy) != 1 || s.ThrownBy[0].ID != "charge" {
+		t.Errorf("ThrownBy = %+v, want [charge]", s.ThrownBy)
+	}
+	if len(s.Endpoints) != 1 || s.Endpoints[0].ID != "ep" || s.Endpoints[0].Via != "charge" {
+		t.Errorf("Endpoints = %+v, want [ep via charge]", s.Endpoints)
+	}
+	if len(s.Jobs) != 1 || s.Jobs[0].ID != "job" {
+		t.Errorf("Jobs = %+v, want [job]", s.Jobs)
+	}
+
+	// The job is three calls away from the throw site.
+	shallow, err := findErrorSurfaces(ctx, store, "PaymentDeclinedError", 2)
+	if err != nil {
+		t.Fatalf("findErrorSurfaces: %v", err)
+	}
+	for _, cand := range shallow {
+		if cand.Error.ID == "declined" && (len(cand.Endpoints) != 1 || len(cand.Jobs) != 0) {
+			t.Errorf("depth 2: endpoints %+v, jobs %+v; want the endpoint only", cand.Endpoints, cand.Jobs)
+		}
+	}
+
+	var buf bytes.Buffer
+	writeErrorSurface(&buf, s)
+	for _, want := range []string{"PaymentDeclinedError (Class)  pay/errors.py:3", "Endpoints (1):", "POST /checkout", "via charge"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/issues"
+)
+
+// issueReference is code referencing an issue.
+type issueReference struct {
+	surfaceEntry
+	// Commits is the number of commits naming the issue that changed the
+	// code.
+	Commits int `json:"commits,omitempty"`
+	// Annotation is the kind of TODO, FIXME, HACK or XXX comment naming the
+	// issue.
+	Annotation string `json:"annotation,omitempty"`
+}
+
+// issueReport lists the code referencing an issue.
+type issueReport struct {
+	Issue   string `json:"issue"`
+	Tracker string `json:"tracker"`
+	URL     string `json:"url,omitempty"`
+	// ImplementedBy is the code changed by commits naming the issue.
+	ImplementedBy []issueReference `json:"implemented_by"`
+	// Blocked is the code with a TODO, FIXME, HACK or XXX comment naming
+	// the issue.
+	Blocked []issueReference `json:"blocked"`
+	// Mentioned is the code naming the issue in other comments.
+	Mentioned []issueReference `json:"mentioned"`
+}
+
+func newQueryIssueCmd() *cobra.Command {
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "issue <KEY>",
+		Short: "Show the code implementing or blocked by an issue",
+		Long: `Show the files and functions that reference an issue tracker ticket,
+given as a Jira key (PROJ-123), a GitHub number (#456, owner/repo#456) or
+an issue URL:
+
+  implemented by  code changed by commits whose message names the issue
+                  (recorded by 'codeeagle issues sync')
+  blocked         code with a TODO, FIXME, HACK or XXX comment naming it
+  mentioned       code naming it in other comments
+
+"#456" also matches owner/repo#456.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			reports, err := findIssueReports(ctx(cmd), store, args[0])
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(reports)
+			}
+			if len(reports) == 0 {
+				fmt.Fprintf(out, "No code references %q.\n", args[0])
+				return nil
+			}
+			for i, r := range reports {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				writeIssueReport(out, r)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// findIssueReports returns a report per Issue node matching ref.
+func findIssueReports(ctx context.Context, store graph.Store, ref string) ([]issueReport, error) {
+	key := issues.Normalize(ref)
+	if key == "" {
+		key = strings.TrimSpace(ref)
+	}
+	filter := graph.NodeFilter{Type: graph.NodeIssue, NamePattern: key}
+	if strings.HasPrefix(key, "#") {
+		// Glob patterns cannot match across the "/" of owner/repo#N.
+		filter.NamePattern = ""
+	}
+	all, err := store.QueryNodes(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query issues: %w", err)
+	}
+	var nodes []*graph.Node
+	for _, n := range all {
+		if strings.HasSuffix(n.Name, key) {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	var reports []issueReport
+	for _, n := range nodes {
+		edges, err := store.GetIncomingEdges(ctx, n.ID, graph.EdgeReferences)
+		if err != nil {
+			return nil, fmt.Errorf("get references to %s: %w", n.Name, err)
+		}
+		if len(edges) == 0 {
+			continue
+		}
+		r := issueReport{
+			Issue:         n.Name,
+			Tracker:       n.Properties["tracker"],
+			URL:           n.Properties["url"],
+			ImplementedBy: []issueReference{},
+			Blocked:       []issueReference{},
+			Mentioned:     []issueReference{},
+		}
+		for _, e := range edges {
+			src, err := store.GetNode(ctx, e.SourceID)
+			if err != nil {
+				continue
+			}
+			ref := issueReference{surfaceEntry: surfaceEntry{
+				ID: src.ID, Type: src.Type, Name: src.Name, FilePath: src.FilePath, Line: src.Line,
+			}}
+			switch e.Properties["source"] {
+			case issues.SourceCommit:
+				ref.Commits, _ = strconv.Atoi(e.Properties["commits"])
+				r.ImplementedBy = append(r.ImplementedBy, ref)
+			default:
+				if line, err := strconv.Atoi(e.Properties["line"]); err == nil {
+					ref.Line = line
+				}
+				if ref.Annotation = e.Properties["annotation"]; ref.Annotation != "" {
+					r.Blocked = append(r.Blocked, ref)
+				} else {
+					r.Mentioned = append(r.Mentioned, ref)
+				}
+			}
+		}
+		sort.Slice(r.ImplementedBy, func(i, j int) bool {
+			a, b := r.ImplementedBy[i], r.ImplementedBy[j]
+			if a.Commits != b.Commits {
+				return a.Commits > b.Commits
+			}
+			return a.FilePath+":"+a.Name < b.FilePath+":"+b.Name
+		})
+		for _, refs := range [][]issueReference{r.Blocked, r.Mentioned} {
+			sort.Slice(refs, func(i, j int) bool {
+				if refs[i].FilePath != refs[j].FilePath {
+					return refs[i].FilePath < refs[j].FilePath
+				}
+				return refs[i].Line < refs[j].Line
+			})
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// writeIssueReport renders an issue report as text.
+func writeIssueReport(w io.Writer, r issueReport) {
+	title := r.Issue
+	if r.URL != "" {
+		title += "  " + r.URL
+	}
+	fmt.Fprintln(w, title)
+
+	location := func(ref issueReference) string {
+		loc := ref.FilePath
+		if ref.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", loc, ref.Line)
+		}
+		if ref.Type == graph.NodeFile || ref.Type == graph.NodeTestFile {
+			return loc
+		}
+		return fmt.Sprintf("%s %s  %s", ref.Type, ref.Name, loc)
+	}
+	if len(r.ImplementedBy) > 0 {
+		fmt.Fprintf(w, "\nImplemented by (%d):\n", len(r.ImplementedBy))
+		for _, ref := range r.ImplementedBy {
+			fmt.Fprintf(w, "  %s  (%d commit(s))\n", location(ref), ref.Commits)
+		}
+	}
+	if len(r.Blocked) > 0 {
+		fmt.Fprintf(w, "\nBlocked (%d):\n", len(r.Blocked))
+		for _, ref := range r.Blocked {
+			fmt.Fprintf(w, "  %s  (%s)\n", location(ref), strings.ToUpper(ref.Annotation))
+		}
+	}
+	if len(r.Mentioned) > 0 {
+		fmt.Fprintf(w, "\nMentioned in comments (%d):\n", len(r.Mentioned))
+		for _, ref := range r.Mentioned {
+			fmt.Fprintf(w, "  %s\n", location(ref))
+		}
+	}
+}
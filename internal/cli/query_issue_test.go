@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/issues"
+)
+
+func TestFindIssueReports(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	ref := func(src, key, source string, props map[string]string) *graph.Edge {
+		p := map[string]string{"source": source}
+		for k, v := range props {
+			p[k] = v
+		}
+		return &graph.Edge{ID: issues.EdgeID(src, key, source), Type: graph.EdgeReferences,
+			SourceID: src, TargetID: issues.NodeID(key), Properties: p}
+	}
+	addTestNodes(t, store,
+		issues.NewNode("acme/pay#4"),
+		issues.NewNode("#40"),
+		&graph.Node{ID: "file", Type: graph.NodeFile, Name: "refund.go", FilePath: "pay/refund.go"},
+		&graph.Node{ID: "refund", Type: graph.NodeFunction, Name: "Refund", FilePath: "pay/refund.go", Line: 4},
+		&graph.Node{ID: "charge", Type: graph.NodeFunction, Name: "Charge", FilePath: "pay/charge.go", Line: 9},
+	)
+	addTestEdges(t, store,
+		ref("file", "acme/pay#4", issues.SourceCommit, map[string]string{"commits": "1"}),
+		ref("refund", "acme/pay#4", issues.SourceCommit, map[string]string{"commits": "3"}),
+		ref("refund", "acme/pay#4", issues.SourceComment, map[string]string{"annotation": "todo", "line": "6"}),
+		ref("charge", "acme/pay#4", issues.SourceComment, map[string]string{"line": "12"}),
+		ref("charge", "#40", issues.SourceComment, nil),
+	)
+
+	reports, err := findIssueReports(ctx, store, "https://github.com/acme/pay/issues/4")
+	if err != nil {
+		t.Fatalf("findIssueReports: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1", len(reports))
+	}
+	r := reports[0]
+	if len(r.ImplementedBy) != 2 || r.ImplementedBy[0].ID != "refund" || r.ImplementedBy[0].Commits != 3 {
+		t.Errorf("implemented by = %+v, want Refund (3 commits) first", r.ImplementedBy)
+	}
+	if len(r.Blocked) != 1 || r.Blocked[0].Line != 6 || len(r.Mentioned) != 1 || r.Mentioned[0].ID != "charge" {
+		t.Errorf("blocked = %+v, mentioned = %+v", r.Blocked, r.Mentioned)
+	}
+
+	var buf bytes.Buffer
+	writeIssueReport(&buf, r)
+	for _, want := range []string{"acme/pay#4  https://github.com/acme/pay/issues/4", "Function Refund  pay/refund.go:4  (3 commit(s))", "pay/refund.go:6  (TODO)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	// A bare number matches the issue in any repository.
+	if reports, err = findIssueReports(ctx, store, "#4"); err != nil || len(reports) != 1 || reports[0].Issue != "acme/pay#4" {
+		t.Errorf("#4 reports = %+v, %v", reports, err)
+	}
+}
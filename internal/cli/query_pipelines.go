@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// affectedPipeline is a CI pipeline with the jobs a change affects.
+type affectedPipeline struct {
+	Name     string        `json:"name"`
+	CI       string        `json:"ci"`
+	FilePath string        `json:"file_path"`
+	Jobs     []affectedJob `json:"jobs"`
+}
+
+// affectedJob is a pipeline job affected by a change, with why.
+type affectedJob struct {
+	Name    string   `json:"name"`
+	Line    int      `json:"line,omitempty"`
+	Actions []string `json:"actions,omitempty"`
+	Reasons []string `json:"reasons"`
+}
+
+func newQueryPipelinesCmd() *cobra.Command {
+	var (
+		base    string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "pipelines [file...]",
+		Short: "List the CI pipeline jobs affected by a change",
+		Long: `List the GitHub Actions and GitLab CI jobs a change affects: jobs that
+work in the directory of a changed file, build, test or deploy the service
+containing it (Targets edges from the linker), or belong to a pipeline
+whose configuration changed. A pipeline whose trigger path filters
+(on.push.paths, rules:changes) match a changed file runs as a whole, so
+all its jobs are listed; one whose filters match no changed file is left
+out.
+
+The change is the given files (paths as in the graph) or, without
+arguments, the files changed between the merge base of --base (default:
+the repository's default branch) and HEAD. Run 'codeeagle sync' first.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			files := args
+			if len(files) == 0 {
+				if files, err = changedGraphPaths(cfg, base); err != nil {
+					return err
+				}
+			}
+
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			pipelines, err := findAffectedPipelines(ctx(cmd), store, files)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(pipelines)
+			}
+			writeAffectedPipelines(out, pipelines, len(files))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&base, "base", "", "base ref of the change when no files are given (default: the repository's default branch)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// changedGraphPaths returns the graph paths of the files changed between
+// the merge base of base and HEAD in the current repository.
+func changedGraphPaths(cfg *config.Config, base string) ([]string, error) {
+	gitRoot, err := gitutil.GetRepoRoot(".")
+	if err != nil {
+		return nil, fmt.Errorf("find repository root: %w", err)
+	}
+	if base == "" {
+		info, err := gitutil.GetBranchInfo(gitRoot)
+		if err != nil {
+			return nil, fmt.Errorf("detect base branch (use --base): %w", err)
+		}
+		base = info.DefaultBranch
+	}
+	mergeBase, err := gitutil.GetMergeBase(gitRoot, base, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("merge base of %s and HEAD: %w", base, err)
+	}
+	added, modified, deleted, err := gitutil.GetChangedFilesBetween(gitRoot, mergeBase, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	toGraphPath := graphPathMapper(gitRoot, cfg)
+	var files []string
+	for _, p := range append(append(added, modified...), deleted...) {
+		if gp, ok := toGraphPath(p); ok {
+			files = append(files, gp)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// findAffectedPipelines returns the pipelines with jobs affected by a
+// change to files, sorted by file path.
+func findAffectedPipelines(ctx context.Context, store graph.Store, files []string) ([]affectedPipeline, error) {
+	changedServices, err := servicesOfFiles(ctx, store, files)
+	if err != nil {
+		return nil, err
+	}
+	pipelines, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodePipeline})
+	if err != nil {
+		return nil, fmt.Errorf("query pipelines: %w", err)
+	}
+
+	var affected []affectedPipeline
+	for _, p := range pipelines {
+		triggered, filtered := pipelineTriggered(p, files)
+		if !triggered {
+			continue
+		}
+		jobs, err := pipelineJobs(ctx, store, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		ap := affectedPipeline{Name: p.Name, CI: p.Properties[parser.PipelinePropCI], FilePath: p.FilePath, Jobs: []affectedJob{}}
+		for _, job := range jobs {
+			var reasons []string
+			if filtered {
+				reasons = append(reasons, "pipeline trigger paths match")
+			}
+			r, ok, err := jobReasons(ctx, store, job, p.FilePath, files, changedServices)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			reasons = append(reasons, r...)
+			if len(reasons) == 0 {
+				continue
+			}
+			aj := affectedJob{Name: job.Name, Line: job.Line, Reasons: reasons}
+			if actions := job.Properties[parser.PipelinePropActions]; actions != "" {
+				aj.Actions = strings.Split(actions, ",")
+			}
+			ap.Jobs = append(ap.Jobs, aj)
+		}
+		if len(ap.Jobs) > 0 {
+			affected = append(affected, ap)
+		}
+	}
+	sort.Slice(affected, func(i, j int) bool {
+		if affected[i].FilePath != affected[j].FilePath {
+			return affected[i].FilePath < affected[j].FilePath
+		}
+		return affected[i].Name < affected[j].Name
+	})
+	return affected, nil
+}
+
+// pipelineTriggered reports whether a change to files triggers the
+// pipeline p, and whether it was selected by trigger path filters.
+// Pipelines without filters run on any change.
+func pipelineTriggered(p *graph.Node, files []string) (triggered, filtered bool) {
+	include := splitList(p.Properties[parser.PipelinePropTriggerPaths])
+	ignore := splitList(p.Properties[parser.PipelinePropIgnorePaths])
+	if len(include) == 0 && len(ignore) == 0 {
+		return true, false
+	}
+	for _, f := range files {
+		if f == p.FilePath {
+			return true, false
+		}
+		if len(include) > 0 && matchesAnyPath(include, f) && !matchesAnyPath(ignore, f) {
+			return true, true
+		}
+		if len(include) == 0 && !matchesAnyPath(ignore, f) {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// jobReasons returns why a change to files affects job. ok is false when
+// the job's own trigger paths (GitLab rules:changes) match no file.
+func jobReasons(ctx context.Context, store graph.Store, job *graph.Node, pipelineFile string, files []string, changedServices map[string]string) (reasons []string, ok bool, err error) {
+	if globs := splitList(job.Properties[parser.PipelinePropTriggerPaths]); len(globs) > 0 {
+		matched := false
+		for _, f := range files {
+			matched = matched || matchesAnyPath(globs, f)
+		}
+		if !matched {
+			return nil, false, nil
+		}
+		reasons = append(reasons, "job trigger paths match")
+	}
+	for _, f := range files {
+		if f == pipelineFile {
+			reasons = append(reasons, "pipeline configuration changed")
+			break
+		}
+	}
+	for _, dir := range splitList(job.Properties[parser.PipelinePropPaths]) {
+		for _, f := range files {
+			if strings.HasPrefix(f, dir+"/") {
+				reasons = append(reasons, "works in "+dir)
+				break
+			}
+		}
+	}
+	edges, err := store.GetEdges(ctx, job.ID, graph.EdgeTargets)
+	if err != nil {
+		return nil, false, fmt.Errorf("get targets of %s: %w", job.Name, err)
+	}
+	for _, e := range edges {
+		if name, ok := changedServices[e.TargetID]; ok && e.SourceID == job.ID {
+			reasons = append(reasons, "targets service "+name)
+		}
+	}
+	return reasons, true, nil
+}
+
+// pipelineJobs returns the PipelineJob nodes a pipeline contains, in file
+// order.
+func pipelineJobs(ctx context.Context, store graph.Store, pipelineID string) ([]*graph.Node, error) {
+	edges, err := store.GetEdges(ctx, pipelineID, graph.EdgeContains)
+	if err != nil {
+		return nil, fmt.Errorf("get jobs of pipeline: %w", err)
+	}
+	var jobs []*graph.Node
+	for _, e := range edges {
+		if e.SourceID != pipelineID {
+			continue
+		}
+		if n, err := store.GetNode(ctx, e.TargetID); err == nil && n.Type == graph.NodePipelineJob {
+			jobs = append(jobs, n)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Line < jobs[j].Line })
+	return jobs, nil
+}
+
+// servicesOfFiles returns the IDs and names of the services containing
+// any of files.
+func servicesOfFiles(ctx context.Context, store graph.Store, files []string) (map[string]string, error) {
+	services := make(map[string]string)
+	for _, f := range files {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{FilePath: f})
+		if err != nil {
+			return nil, fmt.Errorf("query %s: %w", f, err)
+		}
+		for _, n := range nodes {
+			if n.Type != graph.NodeFile && n.Type != graph.NodeTestFile {
+				continue
+			}
+			edges, err := store.GetIncomingEdges(ctx, n.ID, graph.EdgeContains)
+			if err != nil {
+				return nil, fmt.Errorf("get service of %s: %w", f, err)
+			}
+			for _, e := range edges {
+				if svc, err := store.GetNode(ctx, e.SourceID); err == nil && svc.Type == graph.NodeService {
+					services[svc.ID] = svc.Name
+				}
+			}
+		}
+	}
+	return services, nil
+}
+
+func matchesAnyPath(globs []string, file string) bool {
+	for _, g := range globs {
+		if parser.MatchPipelinePath(g, file) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// writeAffectedPipelines prints the affected pipelines and their jobs.
+func writeAffectedPipelines(out io.Writer, pipelines []affectedPipeline, changed int) {
+	if len(pipelines) == 0 {
+		fmt.Fprintf(out, "No pipeline jobs affected by %d changed files.\n", changed)
+		return
+	}
+	fmt.Fprintf(out, "%d pipelines affected by %d changed files:\n", len(pipelines), changed)
+	for _, p := range pipelines {
+		fmt.Fprintf(out, "\n%s (%s)  %s\n", p.Name, p.CI, p.FilePath)
+		for _, j := range p.Jobs {
+			actions := ""
+			if len(j.Actions) > 0 {
+				actions = " [" + strings.Join(j.Actions, ",") + "]"
+			}
+			fmt.Fprintf(out, "  %-30s%s  %s\n", j.Name, actions, strings.Join(j.Reasons, "; "))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestFindAffectedPipelines(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	pipeline := func(id, file string, props map[string]string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodePipeline, Name: id, FilePath: file, Properties: props}
+	}
+	job := func(id, file string, line int, props map[string]string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodePipelineJob, Name: id, FilePath: file, Line: line, Properties: props}
+	}
+	const ci, gl = ".github/workflows/ci.yml", ".gitlab-ci.yml"
+	addTestNodes(t, store,
+		&graph.Node{ID: "svc-orders", Type: graph.NodeService, Name: "orders"},
+		&graph.Node{ID: "f-handler", Type: graph.NodeFile, Name: "orders/api/handler.go", FilePath: "orders/api/handler.go"},
+		pipeline("ci", ci, map[string]string{parser.PipelinePropCI: "github_actions"}),
+		job("test-orders", ci, 5, map[string]string{parser.PipelinePropActions: "test"}),
+		job("test-web", ci, 12, map[string]string{parser.PipelinePropPaths: "web"}),
+		job("lint-api", ci, 20, map[string]string{parser.PipelinePropPaths: "orders/api"}),
+		pipeline("docs", ".github/workflows/docs.yml", map[string]string{parser.PipelinePropTriggerPaths: "docs/**"}),
+		job("publish-docs", ".github/workflows/docs.yml", 8, map[string]string{}),
+		pipeline("gitlab", gl, map[string]string{parser.PipelinePropCI: "gitlab_ci"}),
+		job("deploy-orders", gl, 3, map[string]string{parser.PipelinePropTriggerPaths: "k8s/**", parser.PipelinePropActions: "deploy"}),
+	)
+	addTestEdges(t, store,
+		&graph.Edge{ID: "c1", Type: graph.EdgeContains, SourceID: "svc-orders", TargetID: "f-handler"},
+		&graph.Edge{ID: "p1", Type: graph.EdgeContains, SourceID: "ci", TargetID: "test-orders"},
+		&graph.Edge{ID: "p2", Type: graph.EdgeContains, SourceID: "ci", TargetID: "test-web"},
+		&graph.Edge{ID: "p3", Type: graph.EdgeContains, SourceID: "ci", TargetID: "lint-api"},
+		&graph.Edge{ID: "p4", Type: graph.EdgeContains, SourceID: "docs", TargetID: "publish-docs"},
+		&graph.Edge{ID: "p5", Type: graph.EdgeContains, SourceID: "gitlab", TargetID: "deploy-orders"},
+		&graph.Edge{ID: "t1", Type: graph.EdgeTargets, SourceID: "test-orders", TargetID: "svc-orders"},
+		&graph.Edge{ID: "t2", Type: graph.EdgeTargets, SourceID: "deploy-orders", TargetID: "svc-orders"},
+	)
+
+	tests := []struct {
+		name  string
+		files []string
+		want  map[string][]string // job -> reasons
+	}{
+		{
+			name:  "service change",
+			files: []string{"orders/api/handler.go"},
+			want: map[string][]string{
+				"test-orders": {"targets service orders"},
+				"lint-api":    {"works in orders/api"},
+			},
+		},
+		{
+			name:  "trigger paths",
+			files: []string{"docs/guide.md", "k8s/orders.yaml"},
+			want: map[string][]string{
+				"publish-docs":  {"pipeline trigger paths match"},
+				"deploy-orders": {"job trigger paths match"},
+			},
+		},
+		{
+			name:  "configuration change",
+			files: []string{ci},
+			want: map[string][]string{
+				"test-orders": {"pipeline configuration changed"},
+				"test-web":    {"pipeline configuration changed"},
+				"lint-api":    {"pipeline configuration changed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipelines, err := findAffectedPipelines(ctx, store, tt.files)
+			if err != nil {
+				t.Fatalf("findAffectedPipelines: %v", err)
+			}
+			got := make(map[string][]string)
+			for _, p := range pipelines {
+				for _, j := range p.Jobs {
+					got[j.Name] = j.Reasons
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("affected jobs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// telemetryEmitter is a function, method or file that defines or emits a
+// metric, span or log event.
+type telemetryEmitter struct {
+	surfaceEntry
+	Definition bool `json:"definition,omitempty"`
+}
+
+// telemetrySignal is a metric, span or log event and the code emitting it,
+// across all files.
+type telemetrySignal struct {
+	Name       string             `json:"name"`
+	Kind       string             `json:"kind"`
+	Library    string             `json:"library,omitempty"`
+	Instrument string             `json:"instrument,omitempty"`
+	Level      string             `json:"level,omitempty"`
+	Emitters   []telemetryEmitter `json:"emitters"`
+}
+
+func newQueryTelemetryCmd() *cobra.Command {
+	var (
+		kind    string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "telemetry <name>",
+		Short: "Show the code emitting a metric, tracing span or log event",
+		Long: `Find the metrics (Prometheus, StatsD, Micrometer, OpenTelemetry), tracing
+spans and structured log events with the given name (a glob pattern, as
+shown on dashboards or in log search) and list the functions, methods and
+files that define or emit them.
+
+Use --kind to restrict to metric, span or log_event. Telemetry nodes are
+extracted during indexing; run 'codeeagle sync' first.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			signals, err := findTelemetry(ctx(cmd), store, args[0], kind)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(signals)
+			}
+			if len(signals) == 0 {
+				fmt.Fprintf(out, "No metric, span or log event named %q found.\n", args[0])
+				return nil
+			}
+			for i, s := range signals {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				writeTelemetrySignal(out, s)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&kind, "kind", "", "only show this kind: metric, span or log_event")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// findTelemetry returns the telemetry named like pattern, optionally of one
+// kind, merging the per-file nodes of each kind and name.
+func findTelemetry(ctx context.Context, store graph.Store, pattern, kind string) ([]telemetrySignal, error) {
+	filter := graph.NodeFilter{Type: graph.NodeTelemetry, NamePattern: pattern}
+	if kind != "" {
+		filter.Properties = map[string]string{"kind": kind}
+	}
+	nodes, err := store.QueryNodes(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query telemetry nodes: %w", err)
+	}
+
+	byKey := make(map[string]*telemetrySignal)
+	var keys []string
+	for _, n := range nodes {
+		key := n.Properties["kind"] + ":" + n.Name
+		s, ok := byKey[key]
+		if !ok {
+			s = &telemetrySignal{
+				Name:       n.Name,
+				Kind:       n.Properties["kind"],
+				Library:    n.Properties["library"],
+				Instrument: n.Properties["instrument"],
+				Level:      n.Properties["level"],
+				Emitters:   []telemetryEmitter{},
+			}
+			byKey[key] = s
+			keys = append(keys, key)
+		}
+		edges, err := store.GetIncomingEdges(ctx, n.ID, graph.EdgeEmits)
+		if err != nil {
+			return nil, fmt.Errorf("get emitters of %s: %w", n.Name, err)
+		}
+		for _, e := range edges {
+			src, err := store.GetNode(ctx, e.SourceID)
+			if err != nil {
+				continue
+			}
+			s.Emitters = append(s.Emitters, telemetryEmitter{
+				surfaceEntry: newSurfaceEntry(src, ""),
+				Definition:   e.Properties["definition"] == "true",
+			})
+		}
+	}
+
+	sort.Strings(keys)
+	signals := make([]telemetrySignal, 0, len(keys))
+	for _, key := range keys {
+		s := byKey[key]
+		sort.Slice(s.Emitters, func(i, j int) bool {
+			a, b := s.Emitters[i], s.Emitters[j]
+			if a.FilePath != b.FilePath {
+				return a.FilePath < b.FilePath
+			}
+			return a.Line < b.Line
+		})
+		signals = append(signals, *s)
+	}
+	return signals, nil
+}
+
+func writeTelemetrySignal(out io.Writer, s telemetrySignal) {
+	details := []string{s.Kind}
+	for _, d := range []string{s.Instrument, s.Level, s.Library} {
+		if d != "" {
+			details = append(details, d)
+		}
+	}
+	fmt.Fprintf(out, "%s (%s)\n", s.Name, strings.Join(details, ", "))
+	if len(s.Emitters) == 0 {
+		fmt.Fprintln(out, "  No emitters found.")
+		return
+	}
+	for _, e := range s.Emitters {
+		suffix := ""
+		if e.Definition {
+			suffix = "  (definition)"
+		}
+		fmt.Fprintf(out, "  %-40s  %s%s\n", e.Name, entryLocation(e.surfaceEntry), suffix)
+	}
+}
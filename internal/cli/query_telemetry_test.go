@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestFindTelemetry(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	metric := func(id, file string, kind string) *graph.Node {
+		return &graph.Node{
+			ID: id, Type: graph.NodeTelemetry, Name: "charges_total", FilePath: file,
+			Properties: map[string]string{"kind": kind, "library": "prometheus", "instrument": "counter"},
+		}
+	}
+	fn := func(id, name, file string, line int) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeFunction, Name: name, FilePath: file, Line: line}
+	}
+	emits := func(src, tgt string, definition bool) *graph.Edge {
+		e := &graph.Edge{ID: graph.NewEdgeID(graph.EdgeEmits, src, tgt), Type: graph.EdgeEmits, SourceID: src, TargetID: tgt}
+		if definition {
+			e.Properties = map[string]string{"definition": "true"}
+		}
+		return e
+	}
+
+	addTestNodes(t, store,
+		metric("m1", "pay/metrics.go", "metric"),
+		metric("m2", "pay/charge.go", "metric"),
+		metric("log", "pay/charge.go", "log_event"),
+		&graph.Node{ID: "file", Type: graph.NodeFile, Name: "metrics.go", FilePath: "pay/metrics.go"},
+		fn("charge", "Charge", "pay/charge.go", 12),
+		fn("refund", "Refund", "pay/charge.go", 40),
+	)
+	addTestEdges(t, store,
+		emits("file", "m1", true),
+		emits("refund", "m2", false),
+		emits("charge", "m2", false),
+		emits("charge", "log", false),
+	)
+
+	signals, err := findTelemetry(ctx, store, "charges_*", "metric")
+	if err != nil {
+		t.Fatalf("findTelemetry: %v", err)
+	}
+	if len(signals) != 1 {
+		t.Fatalf("got %d signals, want the per-file metric nodes merged into 1", len(signals))
+	}
+	s := signals[0]
+	var got []string
+	for _, e := range s.Emitters {
+		got = append(got, e.ID)
+	}
+	if strings.Join(got, ",") != "charge,refund,file" {
+		t.Errorf("emitters = %v, want [charge refund file]", got)
+	}
+	if !s.Emitters[2].Definition {
+		t.Errorf("file emitter should be the definition")
+	}
+
+	all, err := findTelemetry(ctx, store, "charges_total", "")
+	if err != nil {
+		t.Fatalf("findTelemetry: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("got %d signals without --kind, want metric and log event", len(all))
+	}
+
+	var buf bytes.Buffer
+	writeTelemetrySignal(&buf, s)
+	for _, want := range []string{"charges_total (metric, counter, prometheus)", "pay/charge.go:12", "(definition)"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// unresolvedRef is a reference the linker could not bind, with the symbols
+// that make it.
+type unresolvedRef struct {
+	TargetType string         `json:"target_type"`
+	Name       string         `json:"name"`
+	Language   string         `json:"language"`
+	FilePath   string         `json:"file_path"`
+	Line       int            `json:"line,omitempty"`
+	Referrers  []surfaceEntry `json:"referrers"`
+}
+
+func newQueryUnresolvedCmd() *cobra.Command {
+	var (
+		language string
+		jsonOut  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "unresolved",
+		Short: "List references the linker could not bind to a node",
+		Long: `List the Unresolved nodes left in the graph: references a parser could
+name but not locate (such as the interface a class implements) that no
+linker phase bound to a declaration. Most name types of external
+libraries; the rest point at gaps in the indexed code.
+
+Each reference is listed with the symbols that make it. Run
+'codeeagle sync' first.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openReadOnlyBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			refs, err := findUnresolvedRefs(ctx(cmd), store, language)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(refs)
+			}
+			if len(refs) == 0 {
+				fmt.Fprintln(out, "No unresolved references.")
+				return nil
+			}
+			fmt.Fprintf(out, "%d unresolved references:\n", len(refs))
+			for _, r := range refs {
+				fmt.Fprintf(out, "\n%s %s (%s)  %s\n", r.TargetType, r.Name, r.Language, entryLocation(surfaceEntry{FilePath: r.FilePath, Line: r.Line}))
+				for _, e := range r.Referrers {
+					fmt.Fprintf(out, "  %-40s  %s\n", e.Name, entryLocation(e))
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&language, "language", "", "only list references in this language")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// findUnresolvedRefs returns the Unresolved nodes in language (all
+// languages when empty), sorted by target type, name and file.
+func findUnresolvedRefs(ctx context.Context, store graph.Store, language string) ([]unresolvedRef, error) {
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeUnresolved, Language: language})
+	if err != nil {
+		return nil, fmt.Errorf("query unresolved nodes: %w", err)
+	}
+	refs := make([]unresolvedRef, 0, len(nodes))
+	for _, n := range nodes {
+		edges, err := store.GetIncomingEdges(ctx, n.ID, "")
+		if err != nil {
+			return nil, fmt.Errorf("get referrers of %s: %w", n.Name, err)
+		}
+		r := unresolvedRef{
+			TargetType: n.Properties["target_type"],
+			Name:       n.Name,
+			Language:   n.Language,
+			FilePath:   n.FilePath,
+			Line:       n.Line,
+			Referrers:  []surfaceEntry{},
+		}
+		for _, e := range edges {
+			src, err := store.GetNode(ctx, e.SourceID)
+			if err != nil {
+				continue
+			}
+			r.Referrers = append(r.Referrers, newSurfaceEntry(src, ""))
+		}
+		refs = append(refs, r)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		if a.TargetType != b.TargetType {
+			return a.TargetType < b.TargetType
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.FilePath < b.FilePath
+	})
+	return refs, nil
+}
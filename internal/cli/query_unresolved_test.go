@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestFindUnresolvedRefs(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	unresolved := func(id, name, lang, file string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeUnresolved, Name: name, Language: lang, FilePath: file,
+			Properties: map[string]string{"target_type": string(graph.NodeInterface)}}
+	}
+	addTestNodes(t, store,
+		unresolved("u-ser", "Serializable", "java", "src/Order.java"),
+		unresolved("u-run", "Runnable", "java", "src/Job.java"),
+		unresolved("u-init", "OnModuleInit", "typescript", "src/app.ts"),
+		&graph.Node{ID: "order", Type: graph.NodeClass, Name: "Order", FilePath: "src/Order.java", Line: 4},
+		&graph.Node{ID: "job", Type: graph.NodeClass, Name: "Job", FilePath: "src/Job.java", Line: 7},
+	)
+	addTestEdges(t, store,
+		&graph.Edge{ID: "e1", Type: graph.EdgeImplements, SourceID: "order", TargetID: "u-ser"},
+		&graph.Edge{ID: "e2", Type: graph.EdgeImplements, SourceID: "job", TargetID: "u-run"},
+	)
+
+	refs, err := findUnresolvedRefs(ctx, store, "java")
+	if err != nil {
+		t.Fatalf("findUnresolvedRefs: %v", err)
+	}
+	want := []struct{ name, referrer string }{
+		{"Runnable", "job"},
+		{"Serializable", "order"},
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("got %d refs, want %d: %+v", len(refs), len(want), refs)
+	}
+	for i, w := range want {
+		r := refs[i]
+		if r.Name != w.name || len(r.Referrers) != 1 || r.Referrers[0].ID != w.referrer {
+			t.Errorf("refs[%d] = %+v, want %s referenced by %s", i, r, w.name, w.referrer)
+		}
+	}
+}
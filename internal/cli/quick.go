@@ -0,0 +1,374 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+	csharpparser "github.com/imyousuf/CodeEagle/internal/parser/csharp"
+	"github.com/imyousuf/CodeEagle/internal/parser/golang"
+	"github.com/imyousuf/CodeEagle/internal/parser/java"
+	"github.com/imyousuf/CodeEagle/internal/parser/javascript"
+	"github.com/imyousuf/CodeEagle/internal/parser/python"
+	rubyparser "github.com/imyousuf/CodeEagle/internal/parser/ruby"
+	rustparser "github.com/imyousuf/CodeEagle/internal/parser/rust"
+	"github.com/imyousuf/CodeEagle/internal/parser/typescript"
+)
+
+// Quick-mode rule identifiers.
+const (
+	ruleUntestedEndpoint     = "untested-endpoint"
+	ruleNewServiceDependency = "new-service-dependency"
+)
+
+// stagedFile holds the parse results of a staged file and its HEAD version.
+// Head is nil for newly added files.
+type stagedFile struct {
+	Path   string
+	Staged *parser.ParseResult
+	Head   *parser.ParseResult
+}
+
+func newQuickCmd() *cobra.Command {
+	var (
+		staged  bool
+		budget  time.Duration
+		strict  bool
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "quick --staged",
+		Short: "Fast pre-commit checks on staged files",
+		Long: `Analyze only the staged files within a strict time budget and warn on
+obvious issues. No LLM calls are made and the linker is not run, so it is
+fast enough for a pre-commit hook:
+
+  untested-endpoint       a staged file adds an API endpoint but no test
+                          file in the same service is staged
+  new-service-dependency  a staged file adds an API call that makes its
+                          service depend on another service for the first time
+
+The staged (index) version of each file is compared with its HEAD version.
+The knowledge graph is read, if available, to resolve endpoints in other
+services. Files not analyzed before the budget expires are skipped.
+
+Warnings do not fail the command unless --strict is set. Example hook:
+
+  codeeagle quick --staged --strict || exit 1`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !staged {
+				return fmt.Errorf("quick currently supports only --staged")
+			}
+			start := time.Now()
+			deadline := start.Add(budget)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			gitRoot, err := gitutil.GetRepoRoot(".")
+			if err != nil {
+				return fmt.Errorf("find repository root: %w", err)
+			}
+			added, modified, _, err := gitutil.GetStagedFiles(gitRoot)
+			if err != nil {
+				return err
+			}
+			toGraphPath := graphPathMapper(gitRoot, cfg)
+
+			registry := newQuickRegistry(cfg)
+			paths := append(added, modified...)
+			sort.Strings(paths)
+
+			var (
+				files   []stagedFile
+				skipped int
+			)
+			for i, p := range paths {
+				if time.Now().After(deadline) {
+					skipped = len(paths) - i
+					break
+				}
+				gp, ok := toGraphPath(p)
+				if !ok {
+					continue
+				}
+				sp, ok := registry.ParserForFile(gp)
+				if !ok {
+					continue
+				}
+				sf, err := parseStagedFile(gitRoot, p, gp, sp)
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+					continue
+				}
+				files = append(files, sf)
+			}
+
+			// The graph is optional: it may be missing or not yet built.
+			var store graph.Store
+			if time.Now().Before(deadline) {
+				s, _, err := openReadOnlyBranchStore(cfg)
+				if err != nil {
+					if verbose {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Warning: graph unavailable, skipping cross-service checks: %v\n", err)
+					}
+				} else {
+					defer s.Close()
+					store = s
+				}
+			}
+
+			problems, err := quickCheck(ctx(cmd), store, files)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				if problems == nil {
+					problems = []problem{}
+				}
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(problems); err != nil {
+					return err
+				}
+			} else {
+				if err := writeProblems(out, "text", problems); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "quick: %d staged file(s) checked in %s, %d warning(s)",
+					len(files), time.Since(start).Round(time.Millisecond), len(problems))
+				if skipped > 0 {
+					fmt.Fprintf(out, ", %d skipped (time budget)", skipped)
+				}
+				fmt.Fprintln(out)
+			}
+
+			if strict && len(problems) > 0 {
+				return fmt.Errorf("%d warning(s) in staged changes", len(problems))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&staged, "staged", false, "analyze files staged in the git index")
+	cmd.Flags().DurationVar(&budget, "budget", 3*time.Second, "time budget for analysis")
+	cmd.Flags().BoolVar(&strict, "strict", false, "exit with an error when warnings are found")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// newQuickRegistry builds a registry with only the source-code parsers; no
+// fallback parser is registered so docs are never sent to an LLM.
+func newQuickRegistry(cfg *config.Config) *parser.Registry {
+	registry := parser.NewRegistry()
+	registry.Register(golang.NewParser())
+	registry.Register(python.NewParser())
+	registry.Register(typescript.NewParser())
+	registry.Register(javascript.NewParser())
+	registry.Register(java.NewParser())
+	registry.Register(rustparser.NewParser())
+	registry.Register(rubyparser.NewParser())
+	registry.Register(csharpparser.NewParser())
+	registry.SetTestPatterns(&parser.TestPatterns{
+		FileGlobs:        cfg.Tests.FilePatterns,
+		FunctionPatterns: cfg.Tests.FunctionPatterns,
+		Annotations:      cfg.Tests.Annotations,
+	})
+	return registry
+}
+
+// graphPathMapper converts git-root-relative paths to the repo-relative paths
+// used in the graph. The second return value is false for files outside the
+// configured repository.
+func graphPathMapper(gitRoot string, cfg *config.Config) func(string) (string, bool) {
+	prefix := ""
+	for _, repo := range cfg.Repositories {
+		abs, err := filepath.Abs(repo.Path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(gitRoot, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if rel != "." {
+			prefix = filepath.ToSlash(rel) + "/"
+		}
+		break
+	}
+	return func(p string) (string, bool) {
+		if prefix == "" {
+			return p, true
+		}
+		if !strings.HasPrefix(p, prefix) {
+			return "", false
+		}
+		return strings.TrimPrefix(p, prefix), true
+	}
+}
+
+// parseStagedFile parses the staged and HEAD versions of a file.
+func parseStagedFile(gitRoot, gitPath, graphPath string, p parser.Parser) (stagedFile, error) {
+	staged, err := parseAtRef(gitRoot, "", gitPath, graphPath, p)
+	if err != nil {
+		return stagedFile{}, err
+	}
+	sf := stagedFile{Path: graphPath, Staged: staged}
+	if head, err := parseAtRef(gitRoot, "HEAD", gitPath, graphPath, p); err == nil {
+		sf.Head = head
+	}
+	return sf, nil
+}
+
+// parseAtRef parses a file's content at the given ref ("" for the index).
+func parseAtRef(gitRoot, ref, gitPath, graphPath string, p parser.Parser) (*parser.ParseResult, error) {
+	content, err := gitutil.ReadFileAtRef(gitRoot, ref, gitPath)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.ParseFile(graphPath, content)
+	if err != nil {
+		at := ref
+		if at == "" {
+			at = "staged"
+		}
+		return nil, fmt.Errorf("parse %s at %s: %w", gitPath, at, err)
+	}
+	return res, nil
+}
+
+// quickCheck runs the quick-mode rules over parsed staged files. store may be
+// nil, in which case cross-service checks only consider staged endpoints.
+func quickCheck(ctx context.Context, store graph.Store, files []stagedFile) ([]problem, error) {
+	testServices := make(map[string]bool)
+	var stagedEndpoints []*graph.Node
+	for _, f := range files {
+		for _, n := range f.Staged.Nodes {
+			switch n.Type {
+			case graph.NodeTestFile:
+				testServices[linker.ServiceGroup(f.Path)] = true
+			case graph.NodeAPIEndpoint:
+				stagedEndpoints = append(stagedEndpoints, n)
+			}
+		}
+	}
+
+	var problems []problem
+
+	// New endpoints without accompanying tests.
+	for _, f := range files {
+		before := nodeNames(f.Head, graph.NodeAPIEndpoint, nil)
+		svc := linker.ServiceGroup(f.Path)
+		for _, n := range f.Staged.Nodes {
+			if n.Type != graph.NodeAPIEndpoint || before[n.Name] || testServices[svc] {
+				continue
+			}
+			problems = append(problems, problem{
+				File:     f.Path,
+				Line:     max(n.Line, 1),
+				Column:   1,
+				Severity: "warning",
+				Rule:     ruleUntestedEndpoint,
+				Message:  fmt.Sprintf("new endpoint %s has no staged tests in %s", n.Name, svc),
+			})
+		}
+	}
+
+	// New cross-service dependencies via API calls.
+	endpoints := stagedEndpoints
+	existing := make(map[string]bool) // "caller→callee" service pairs already in the graph
+	if store != nil {
+		graphEndpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+		if err != nil {
+			return nil, fmt.Errorf("query endpoints: %w", err)
+		}
+		endpoints = append(graphEndpoints, stagedEndpoints...)
+		index := linker.NewEndpointIndex(endpoints)
+
+		calls, err := store.QueryNodes(ctx, graph.NodeFilter{
+			Type:       graph.NodeDependency,
+			Properties: map[string]string{"kind": "api_call"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("query api calls: %w", err)
+		}
+		for _, call := range calls {
+			if ep := index.Match(call); ep != nil {
+				existing[linker.ServiceGroup(call.FilePath)+"→"+linker.ServiceGroup(ep.FilePath)] = true
+			}
+		}
+	}
+	index := linker.NewEndpointIndex(endpoints)
+
+	for _, f := range files {
+		before := nodeNames(f.Head, graph.NodeDependency, isAPICall)
+		caller := linker.ServiceGroup(f.Path)
+		for _, n := range f.Staged.Nodes {
+			if !isAPICall(n) || before[n.Name] {
+				continue
+			}
+			ep := index.Match(n)
+			if ep == nil {
+				continue
+			}
+			callee := linker.ServiceGroup(ep.FilePath)
+			key := caller + "→" + callee
+			if callee == caller || existing[key] {
+				continue
+			}
+			existing[key] = true
+			problems = append(problems, problem{
+				File:     f.Path,
+				Line:     max(n.Line, 1),
+				Column:   1,
+				Severity: "warning",
+				Rule:     ruleNewServiceDependency,
+				Message:  fmt.Sprintf("new dependency from %s on %s via %s (endpoint %s in %s)", caller, callee, n.Name, ep.Name, ep.FilePath),
+			})
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Line < problems[j].Line
+	})
+	return problems, nil
+}
+
+// nodeNames returns the names of nodes of the given type in a parse result,
+// optionally restricted by keep. A nil result yields an empty set.
+func nodeNames(res *parser.ParseResult, t graph.NodeType, keep func(*graph.Node) bool) map[string]bool {
+	names := make(map[string]bool)
+	if res == nil {
+		return names
+	}
+	for _, n := range res.Nodes {
+		if n.Type == t && (keep == nil || keep(n)) {
+			names[n.Name] = true
+		}
+	}
+	return names
+}
+
+func isAPICall(n *graph.Node) bool {
+	return n.Type == graph.NodeDependency && n.Properties["kind"] == "api_call"
+}
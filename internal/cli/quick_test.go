@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestQuickCheck(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	addTestNodes(t, store,
+		&graph.Node{ID: "ep-orders", Type: graph.NodeAPIEndpoint, Name: "GET /orders", FilePath: "orders/handler.go",
+			Properties: map[string]string{"http_method": "GET", "path": "/orders"}},
+		&graph.Node{ID: "ep-users", Type: graph.NodeAPIEndpoint, Name: "GET /users", FilePath: "users/handler.go",
+			Properties: map[string]string{"http_method": "GET", "path": "/users"}},
+		// web already depends on users.
+		&graph.Node{ID: "call-users", Type: graph.NodeDependency, Name: "GET /users", FilePath: "web/src/old.ts",
+			Properties: map[string]string{"kind": "api_call", "http_method": "GET", "path": "/users"}},
+	)
+
+	endpoint := func(name, file string, line int) *graph.Node {
+		return &graph.Node{Type: graph.NodeAPIEndpoint, Name: name, FilePath: file, Line: line}
+	}
+	apiCall := func(method, path, file string, line int) *graph.Node {
+		return &graph.Node{Type: graph.NodeDependency, Name: method + " " + path, FilePath: file, Line: line,
+			Properties: map[string]string{"kind": "api_call", "http_method": method, "path": path}}
+	}
+
+	files := []stagedFile{
+		{
+			Path: "api/routes.go",
+			Staged: &parser.ParseResult{Nodes: []*graph.Node{
+				endpoint("GET /health", "api/routes.go", 5),
+				endpoint("POST /items", "api/routes.go", 9),
+			}},
+			Head: &parser.ParseResult{Nodes: []*graph.Node{endpoint("GET /health", "api/routes.go", 5)}},
+		},
+		{
+			Path: "billing/routes.go",
+			Staged: &parser.ParseResult{Nodes: []*graph.Node{
+				endpoint("POST /invoices", "billing/routes.go", 3),
+			}},
+		},
+		{
+			Path:   "billing/routes_test.go",
+			Staged: &parser.ParseResult{Nodes: []*graph.Node{{Type: graph.NodeTestFile, Name: "billing/routes_test.go"}}},
+		},
+		{
+			Path: "web/src/client.ts",
+			Staged: &parser.ParseResult{Nodes: []*graph.Node{
+				apiCall("GET", "/orders/", "web/src/client.ts", 12),
+				apiCall("GET", "/users", "web/src/client.ts", 20),
+				apiCall("GET", "/orders", "web/src/client.ts", 30),
+			}},
+		},
+	}
+
+	problems, err := quickCheck(ctx, store, files)
+	if err != nil {
+		t.Fatalf("quickCheck: %v", err)
+	}
+
+	want := []struct {
+		file string
+		line int
+		rule string
+	}{
+		{"api/routes.go", 9, ruleUntestedEndpoint},
+		{"web/src/client.ts", 12, ruleNewServiceDependency},
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %+v", len(problems), len(want), problems)
+	}
+	for i, w := range want {
+		p := problems[i]
+		if p.File != w.file || p.Line != w.line || p.Rule != w.rule {
+			t.Errorf("problem[%d] = %+v, want %s:%d %s", i, p, w.file, w.line, w.rule)
+		}
+	}
+
+	// Without a graph, only staged endpoints are considered for dependencies.
+	problems, err = quickCheck(ctx, nil, files)
+	if err != nil {
+		t.Fatalf("quickCheck without store: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Rule != ruleUntestedEndpoint {
+		t.Errorf("problems without store = %+v, want only untested-endpoint", problems)
+	}
+}
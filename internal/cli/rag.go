@@ -123,7 +123,7 @@ Examples:
 				return err
 			}
 			if vs == nil {
-				return fmt.Errorf("no embedding provider available; install Ollama with nomic-embed-text-v2-moe or configure Vertex AI")
+				return fmt.Errorf("no embedding provider available; install Ollama with nomic-embed-text-v2-moe, run llama.cpp with --embeddings, or configure Vertex AI")
 			}
 			defer vs.Close()
 
@@ -0,0 +1,536 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/codeowners"
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/internal/savedquery"
+)
+
+// reportsDirName is the report snapshot directory inside the .CodeEagle dir.
+const reportsDirName = "reports"
+
+// orgReport is the monorepo executive summary produced by 'report org'.
+type orgReport struct {
+	Generated time.Time `json:"generated"`
+	// Month (YYYY-MM) keys the saved snapshot used for month-over-month deltas.
+	Month              string              `json:"month"`
+	Graph              string              `json:"graph,omitempty"`
+	Services           int                 `json:"services"`
+	ServicesByLanguage map[string]int      `json:"services_by_language"`
+	DependencyEdges    int                 `json:"dependency_edges"`
+	DependencyDensity  float64             `json:"dependency_density"`
+	TopDependedOn      []serviceDependents `json:"top_depended_on"`
+	Endpoints          int                 `json:"endpoints"`
+	// OwnersKnown is false when no CODEOWNERS file was found; Unowned is
+	// then empty rather than listing every endpoint.
+	OwnersKnown     bool          `json:"owners_known"`
+	Unowned         []endpointGap `json:"unowned"`
+	Untested        []endpointGap `json:"untested"`
+	Unauthenticated []endpointGap `json:"unauthenticated"`
+	Delta           *orgDelta     `json:"delta,omitempty"`
+}
+
+// serviceDependents is a service and the number of services depending on it.
+type serviceDependents struct {
+	Service    string `json:"service"`
+	Dependents int    `json:"dependents"`
+}
+
+// endpointGap is an endpoint missing an owner, tests, or auth.
+type endpointGap struct {
+	Endpoint string `json:"endpoint"`
+	Service  string `json:"service"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// orgDelta is the change in headline numbers since an earlier month's snapshot.
+type orgDelta struct {
+	Since             string  `json:"since"`
+	Services          int     `json:"services"`
+	DependencyEdges   int     `json:"dependency_edges"`
+	DependencyDensity float64 `json:"dependency_density"`
+	Endpoints         int     `json:"endpoints"`
+	Unowned           int     `json:"unowned"`
+	Untested          int     `json:"untested"`
+	Unauthenticated   int     `json:"unauthenticated"`
+}
+
+func newReportCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "report [name]",
+		Short: "Generate summary reports from the knowledge graph",
+		Long: `Generate summary reports from the knowledge graph.
+
+With a name, run the saved query .CodeEagle/queries/<name>.yaml; without
+one, list the saved queries. A saved query matches nodes, optionally walks
+edges from them, and picks the output columns and format:
+
+  description: Endpoints without tests
+  match:
+    type: APIEndpoint          # also name (glob), package, file, language,
+                               # exported, properties
+  traverse:                    # optional, applied in order
+    - edge: Tests              # any edge type when empty
+      direction: in            # out (default), in or both
+      without: true            # keep nodes with no such neighbour
+  output:
+    columns: [name, file, line]  # id, type, name, qualified_name, package,
+                                 # file, line, language, exported, from,
+                                 # from_type, from_file, prop.<key>
+    group_by: ""               # count results per value of a column
+    sort: file                 # "-" prefix sorts descending
+    limit: 0
+    format: table              # table, json or csv
+
+Built-in reports (such as 'org') take precedence over saved queries of the
+same name.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			if cfg.ConfigDir == "" {
+				return fmt.Errorf("no config directory found; run 'codeeagle init' first")
+			}
+			dir := filepath.Join(cfg.ConfigDir, savedquery.DirName)
+
+			out := cmd.OutOrStdout()
+			if len(args) == 0 {
+				queries, err := savedquery.Load(dir)
+				if err != nil {
+					return err
+				}
+				writeSavedQueries(out, queries, dir)
+				return nil
+			}
+
+			q, err := savedquery.Find(dir, args[0])
+			if err != nil {
+				return err
+			}
+			if format != "" {
+				q.Output.Format = format
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			res, err := savedquery.Run(ctx(cmd), store, q)
+			if err != nil {
+				return err
+			}
+			return writeSavedQueryResult(out, res, q.Output.Format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "override the saved query's output format: table, json or csv")
+	cmd.AddCommand(newReportOrgCmd())
+	return cmd
+}
+
+func newReportOrgCmd() *cobra.Command {
+	var (
+		jsonOut bool
+		limit   int
+		noSave  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "org",
+		Short: "Executive summary of the monorepo: services, dependencies, endpoint gaps",
+		Long: `Produce a one-page overview of the indexed monorepo:
+
+  - number of services, by dominant language
+  - cross-service dependency density (dependency edges / possible pairs)
+  - the most depended-on services
+  - endpoints without an owner (CODEOWNERS), tests, or auth annotations
+
+Each run saves a snapshot to .CodeEagle/reports/org-<YYYY-MM>.json (the
+current month's snapshot is overwritten). When a snapshot from an earlier
+month exists, month-over-month deltas are included.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, currentBranch, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			owners, err := loadCodeOwners(cfg)
+			if err != nil {
+				return err
+			}
+
+			report, err := buildOrgReport(ctx(cmd), store, owners, time.Now().UTC())
+			if err != nil {
+				return err
+			}
+			report.Graph = currentBranch
+
+			if cfg.ConfigDir != "" {
+				dir := filepath.Join(cfg.ConfigDir, reportsDirName)
+				prev, err := loadPreviousOrgReport(dir, report.Month)
+				if err != nil {
+					return err
+				}
+				report.Delta = orgReportDelta(report, prev)
+				if !noSave {
+					if err := saveOrgReport(dir, report); err != nil {
+						return err
+					}
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			writeOrgReport(out, report, limit)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	cmd.Flags().IntVar(&limit, "limit", 10, "max services and endpoints to list per section")
+	cmd.Flags().BoolVar(&noSave, "no-save", false, "do not save this month's snapshot")
+	return cmd
+}
+
+// loadCodeOwners loads CODEOWNERS from every configured repository and
+// returns a lookup that uses the first repository with an owner for a path.
+// Returns nil if no repository has a CODEOWNERS file.
+func loadCodeOwners(cfg *config.Config) (func(string) []string, error) {
+	var files []*codeowners.File
+	for _, repo := range cfg.Repositories {
+		f, err := codeowners.Load(repo.Path)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	return func(path string) []string {
+		for _, f := range files {
+			if owners := f.Owners(path); len(owners) > 0 {
+				return owners
+			}
+		}
+		return nil
+	}, nil
+}
+
+// buildOrgReport computes the executive summary from the graph. owners
+// resolves CODEOWNERS for a file path; nil means ownership is unknown.
+func buildOrgReport(ctx context.Context, store graph.Store, owners func(string) []string, now time.Time) (*orgReport, error) {
+	report := &orgReport{
+		Generated:          now,
+		Month:              now.Format("2006-01"),
+		ServicesByLanguage: make(map[string]int),
+		OwnersKnown:        owners != nil,
+	}
+
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query services: %w", err)
+	}
+	// Several manifests in one top-level directory describe the same
+	// service, so services are counted by group.
+	groupOf := make(map[string]string) // service node ID -> group
+	groups := make(map[string]bool)
+	for _, svc := range services {
+		group := svc.Name
+		if svc.FilePath != "" {
+			group = linker.ServiceGroup(svc.FilePath)
+		}
+		groupOf[svc.ID] = group
+		groups[group] = true
+	}
+	report.Services = len(groups)
+
+	// Dominant language per service group, by file count.
+	files, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFile})
+	if err != nil {
+		return nil, fmt.Errorf("query files: %w", err)
+	}
+	langCounts := make(map[string]map[string]int)
+	for _, f := range files {
+		group := linker.ServiceGroup(f.FilePath)
+		if !groups[group] || f.Language == "" {
+			continue
+		}
+		if langCounts[group] == nil {
+			langCounts[group] = make(map[string]int)
+		}
+		langCounts[group][f.Language]++
+	}
+	for group := range groups {
+		lang, best := "unknown", 0
+		for l, n := range langCounts[group] {
+			if n > best || (n == best && l < lang) {
+				lang, best = l, n
+			}
+		}
+		report.ServicesByLanguage[lang]++
+	}
+
+	// Cross-service dependencies, deduplicated by group pair.
+	pairs := make(map[[2]string]bool)
+	dependents := make(map[string]int)
+	for _, svc := range services {
+		edges, err := store.GetEdges(ctx, svc.ID, graph.EdgeDependsOn)
+		if err != nil {
+			return nil, fmt.Errorf("get dependencies of %s: %w", svc.Name, err)
+		}
+		for _, e := range edges {
+			from, to := groupOf[e.SourceID], groupOf[e.TargetID]
+			if e.SourceID != svc.ID || to == "" || from == to {
+				continue
+			}
+			if pair := [2]string{from, to}; !pairs[pair] {
+				pairs[pair] = true
+				dependents[to]++
+			}
+		}
+	}
+	report.DependencyEdges = len(pairs)
+	if n := report.Services; n > 1 {
+		report.DependencyDensity = float64(len(pairs)) / float64(n*(n-1))
+	}
+	for group, n := range dependents {
+		report.TopDependedOn = append(report.TopDependedOn, serviceDependents{Service: group, Dependents: n})
+	}
+	sort.Slice(report.TopDependedOn, func(i, j int) bool {
+		a, b := report.TopDependedOn[i], report.TopDependedOn[j]
+		if a.Dependents != b.Dependents {
+			return a.Dependents > b.Dependents
+		}
+		return a.Service < b.Service
+	})
+	if len(report.TopDependedOn) > 10 {
+		report.TopDependedOn = report.TopDependedOn[:10]
+	}
+
+	endpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return nil, fmt.Errorf("query endpoints: %w", err)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].FilePath != endpoints[j].FilePath {
+			return endpoints[i].FilePath < endpoints[j].FilePath
+		}
+		return endpoints[i].Line < endpoints[j].Line
+	})
+	report.Endpoints = len(endpoints)
+	for _, ep := range endpoints {
+		gap := endpointGap{Endpoint: ep.Name, Service: linker.ServiceGroup(ep.FilePath), File: ep.FilePath, Line: ep.Line}
+
+		if owners != nil && len(owners(ep.FilePath)) == 0 {
+			report.Unowned = append(report.Unowned, gap)
+		}
+
+		tested, err := endpointTested(ctx, store, ep)
+		if err != nil {
+			return nil, err
+		}
+		if !tested {
+			report.Untested = append(report.Untested, gap)
+		}
+
+		auth, err := endpointAuth(ctx, store, ep)
+		if err != nil {
+			return nil, err
+		}
+		if auth == "" {
+			report.Unauthenticated = append(report.Unauthenticated, gap)
+		}
+	}
+	return report, nil
+}
+
+// endpointTested reports whether the endpoint or its handler is the target
+// of a Tests or Covers edge.
+func endpointTested(ctx context.Context, store graph.Store, ep *graph.Node) (bool, error) {
+	if ok, err := hasIncomingTestEdge(ctx, store, ep.ID); err != nil || ok {
+		return ok, err
+	}
+	edges, err := store.GetEdges(ctx, ep.ID, graph.EdgeExposes)
+	if err != nil {
+		return false, fmt.Errorf("get exposes edges for %s: %w", ep.Name, err)
+	}
+	for _, e := range edges {
+		if e.TargetID != ep.ID {
+			continue
+		}
+		if ok, err := hasIncomingTestEdge(ctx, store, e.SourceID); err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// orgReportPath returns the snapshot file for a month.
+func orgReportPath(dir, month string) string {
+	return filepath.Join(dir, "org-"+month+".json")
+}
+
+// saveOrgReport writes the report as the snapshot for its month.
+func saveOrgReport(dir string, report *orgReport) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create reports dir: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	if err := os.WriteFile(orgReportPath(dir, report.Month), data, 0o644); err != nil {
+		return fmt.Errorf("write report snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadPreviousOrgReport returns the most recent snapshot from a month before
+// the given one, or nil if there is none.
+func loadPreviousOrgReport(dir, month string) (*orgReport, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "org-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	latest := ""
+	for _, m := range matches {
+		prev := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), "org-"), ".json")
+		if prev < month && prev > latest {
+			latest = prev
+		}
+	}
+	if latest == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(orgReportPath(dir, latest))
+	if err != nil {
+		return nil, fmt.Errorf("read report snapshot: %w", err)
+	}
+	var prev orgReport
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return nil, fmt.Errorf("unmarshal report snapshot %s: %w", latest, err)
+	}
+	return &prev, nil
+}
+
+// orgReportDelta compares a report with an earlier snapshot. Returns nil
+// if there is no earlier snapshot.
+func orgReportDelta(cur, prev *orgReport) *orgDelta {
+	if prev == nil {
+		return nil
+	}
+	return &orgDelta{
+		Since:             prev.Month,
+		Services:          cur.Services - prev.Services,
+		DependencyEdges:   cur.DependencyEdges - prev.DependencyEdges,
+		DependencyDensity: cur.DependencyDensity - prev.DependencyDensity,
+		Endpoints:         cur.Endpoints - prev.Endpoints,
+		Unowned:           len(cur.Unowned) - len(prev.Unowned),
+		Untested:          len(cur.Untested) - len(prev.Untested),
+		Unauthenticated:   len(cur.Unauthenticated) - len(prev.Unauthenticated),
+	}
+}
+
+// writeOrgReport prints the report as a Markdown one-pager.
+func writeOrgReport(out io.Writer, r *orgReport, limit int) {
+	d := r.Delta
+	delta := func(get func(*orgDelta) int) string {
+		if d == nil {
+			return ""
+		}
+		return fmt.Sprintf(" (%+d since %s)", get(d), d.Since)
+	}
+
+	fmt.Fprintf(out, "# Organization Report (%s)\n\n", r.Month)
+	if r.Graph != "" {
+		fmt.Fprintf(out, "Graph: %s, generated %s\n\n", r.Graph, r.Generated.Format(time.RFC3339))
+	}
+
+	fmt.Fprintf(out, "## Services: %d%s\n\n", r.Services, delta(func(d *orgDelta) int { return d.Services }))
+	langs := make([]string, 0, len(r.ServicesByLanguage))
+	for l := range r.ServicesByLanguage {
+		langs = append(langs, l)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		a, b := r.ServicesByLanguage[langs[i]], r.ServicesByLanguage[langs[j]]
+		if a != b {
+			return a > b
+		}
+		return langs[i] < langs[j]
+	})
+	for _, l := range langs {
+		fmt.Fprintf(out, "  %-14s %d\n", l, r.ServicesByLanguage[l])
+	}
+
+	fmt.Fprintf(out, "\n## Cross-service dependencies\n\n")
+	fmt.Fprintf(out, "  Edges:   %d%s\n", r.DependencyEdges, delta(func(d *orgDelta) int { return d.DependencyEdges }))
+	density := fmt.Sprintf("%.1f%%", r.DependencyDensity*100)
+	if d != nil {
+		density += fmt.Sprintf(" (%+.1f pts since %s)", d.DependencyDensity*100, d.Since)
+	}
+	fmt.Fprintf(out, "  Density: %s\n", density)
+	if len(r.TopDependedOn) > 0 {
+		fmt.Fprintf(out, "\n  Most depended-on:\n")
+		for i, s := range r.TopDependedOn {
+			if i == limit {
+				break
+			}
+			fmt.Fprintf(out, "  %2d. %-30s %d dependent(s)\n", i+1, s.Service, s.Dependents)
+		}
+	}
+
+	fmt.Fprintf(out, "\n## Endpoints: %d%s\n\n", r.Endpoints, delta(func(d *orgDelta) int { return d.Endpoints }))
+	if r.OwnersKnown {
+		writeEndpointGaps(out, "Without owner", r.Unowned, delta(func(d *orgDelta) int { return d.Unowned }), limit)
+	} else {
+		fmt.Fprintf(out, "  Without owner: unknown (no CODEOWNERS file)\n")
+	}
+	writeEndpointGaps(out, "Without tests", r.Untested, delta(func(d *orgDelta) int { return d.Untested }), limit)
+	writeEndpointGaps(out, "Without auth", r.Unauthenticated, delta(func(d *orgDelta) int { return d.Unauthenticated }), limit)
+}
+
+func writeEndpointGaps(out io.Writer, title string, gaps []endpointGap, delta string, limit int) {
+	fmt.Fprintf(out, "  %s: %d%s\n", title, len(gaps), delta)
+	for i, g := range gaps {
+		if i == limit {
+			fmt.Fprintf(out, "    ... and %d more\n", len(gaps)-limit)
+			break
+		}
+		fmt.Fprintf(out, "    %-36s %s:%d\n", g.Endpoint, g.File, g.Line)
+	}
+}
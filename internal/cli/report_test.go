@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestBuildOrgReport(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	addTestNodes(t, store,
+		&graph.Node{ID: "svc-api", Type: graph.NodeService, Name: "api", FilePath: "api/go.mod"},
+		&graph.Node{ID: "svc-api-npm", Type: graph.NodeService, Name: "api-tools", FilePath: "api/package.json"},
+		&graph.Node{ID: "svc-auth", Type: graph.NodeService, Name: "auth"},
+		&graph.Node{ID: "svc-web", Type: graph.NodeService, Name: "web"},
+		&graph.Node{ID: "f-api-1", Type: graph.NodeFile, Name: "api/main.go", FilePath: "api/main.go", Language: "go"},
+		&graph.Node{ID: "f-api-2", Type: graph.NodeFile, Name: "api/users.go", FilePath: "api/users.go", Language: "go"},
+		&graph.Node{ID: "f-api-3", Type: graph.NodeFile, Name: "api/tools.ts", FilePath: "api/tools.ts", Language: "typescript"},
+		&graph.Node{ID: "f-auth", Type: graph.NodeFile, Name: "auth/app.py", FilePath: "auth/app.py", Language: "python"},
+		&graph.Node{ID: "f-web", Type: graph.NodeFile, Name: "web/index.ts", FilePath: "web/index.ts", Language: "typescript"},
+
+		&graph.Node{ID: "h-users", Type: graph.NodeFunction, Name: "ListUsers", FilePath: "api/users.go",
+			Properties: map[string]string{"annotations": "RequireAuth"}},
+		&graph.Node{ID: "h-health", Type: graph.NodeFunction, Name: "Health", FilePath: "api/main.go"},
+		&graph.Node{ID: "t-users", Type: graph.NodeTestFunction, Name: "TestListUsers", FilePath: "api/users_test.go"},
+		&graph.Node{ID: "ep-users", Type: graph.NodeAPIEndpoint, Name: "GET /users", FilePath: "api/users.go", Line: 12},
+		&graph.Node{ID: "ep-health", Type: graph.NodeAPIEndpoint, Name: "GET /health", FilePath: "api/main.go", Line: 5},
+		&graph.Node{ID: "ep-login", Type: graph.NodeAPIEndpoint, Name: "POST /login", FilePath: "auth/app.py", Line: 9},
+	)
+	addTestEdges(t, store,
+		// api's two manifests count as one service; web and api both depend on auth.
+		&graph.Edge{ID: "d1", Type: graph.EdgeDependsOn, SourceID: "svc-api", TargetID: "svc-auth"},
+		&graph.Edge{ID: "d2", Type: graph.EdgeDependsOn, SourceID: "svc-api-npm", TargetID: "svc-auth"},
+		&graph.Edge{ID: "d3", Type: graph.EdgeDependsOn, SourceID: "svc-web", TargetID: "svc-auth"},
+		&graph.Edge{ID: "d4", Type: graph.EdgeDependsOn, SourceID: "svc-web", TargetID: "svc-api"},
+		&graph.Edge{ID: "d5", Type: graph.EdgeDependsOn, SourceID: "svc-api", TargetID: "svc-api-npm"},
+		&graph.Edge{ID: "x1", Type: graph.EdgeExposes, SourceID: "h-users", TargetID: "ep-users"},
+		&graph.Edge{ID: "x2", Type: graph.EdgeExposes, SourceID: "h-health", TargetID: "ep-health"},
+		&graph.Edge{ID: "t1", Type: graph.EdgeTests, SourceID: "t-users", TargetID: "h-users"},
+	)
+
+	owners := func(path string) []string {
+		if strings.HasPrefix(path, "api/") {
+			return []string{"@acme/api"}
+		}
+		return nil
+	}
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	report, err := buildOrgReport(ctx, store, owners, now)
+	if err != nil {
+		t.Fatalf("buildOrgReport: %v", err)
+	}
+
+	if report.Month != "2026-10" || report.Services != 3 {
+		t.Errorf("month/services = %s/%d, want 2026-10/3", report.Month, report.Services)
+	}
+	wantLangs := map[string]int{"go": 1, "python": 1, "typescript": 1}
+	for l, n := range wantLangs {
+		if report.ServicesByLanguage[l] != n {
+			t.Errorf("services by language = %v, want %v", report.ServicesByLanguage, wantLangs)
+			break
+		}
+	}
+	if report.DependencyEdges != 3 {
+		t.Errorf("dependency edges = %d, want 3", report.DependencyEdges)
+	}
+	if report.DependencyDensity != 0.5 {
+		t.Errorf("density = %v, want 0.5", report.DependencyDensity)
+	}
+	if len(report.TopDependedOn) != 2 || report.TopDependedOn[0] != (serviceDependents{"auth", 2}) {
+		t.Errorf("top depended-on = %+v", report.TopDependedOn)
+	}
+
+	names := func(gaps []endpointGap) string {
+		var out []string
+		for _, g := range gaps {
+			out = append(out, g.Endpoint)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := names(report.Unowned); got != "POST /login" {
+		t.Errorf("unowned = %q", got)
+	}
+	if got := names(report.Untested); got != "GET /health,POST /login" {
+		t.Errorf("untested = %q", got)
+	}
+	if got := names(report.Unauthenticated); got != "GET /health,POST /login" {
+		t.Errorf("unauthenticated = %q", got)
+	}
+
+	// Month-over-month delta against an earlier snapshot.
+	dir := filepath.Join(t.TempDir(), "reports")
+	prev := &orgReport{Month: "2026-09", Services: 2, Endpoints: 1, DependencyEdges: 1,
+		Untested: []endpointGap{{Endpoint: "GET /health"}}}
+	for _, r := range []*orgReport{prev, {Month: "2026-08"}, report} {
+		if err := saveOrgReport(dir, r); err != nil {
+			t.Fatalf("saveOrgReport: %v", err)
+		}
+	}
+	loaded, err := loadPreviousOrgReport(dir, report.Month)
+	if err != nil {
+		t.Fatalf("loadPreviousOrgReport: %v", err)
+	}
+	report.Delta = orgReportDelta(report, loaded)
+	if d := report.Delta; d == nil || d.Since != "2026-09" || d.Services != 1 || d.Endpoints != 2 || d.Untested != 1 {
+		t.Errorf("delta = %+v", d)
+	}
+
+	var buf bytes.Buffer
+	writeOrgReport(&buf, report, 1)
+	out := buf.String()
+	for _, want := range []string{"## Services: 3 (+1 since 2026-09)", "Density: 50.0%", "1. auth", "Without tests: 2 (+1 since 2026-09)", "... and 1 more"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// fileChange holds the parse results of a changed file at the base and head
+// revisions. Before is nil for added files and After is nil for deleted ones.
+type fileChange struct {
+	Path   string
+	Before *parser.ParseResult
+	After  *parser.ParseResult
+}
+
+// reviewReport is the PR review summary.
+type reviewReport struct {
+	Base              string           `json:"base"`
+	MergeBase         string           `json:"merge_base"`
+	Head              string           `json:"head"`
+	FilesChanged      int              `json:"files_changed"`
+	NewEndpoints      []reviewEndpoint `json:"new_endpoints"`
+	ConsumedEndpoints []reviewEndpoint `json:"consumed_endpoints"`
+	UntestedFunctions []reviewNode     `json:"untested_functions"`
+	Skipped           []string         `json:"skipped,omitempty"`
+}
+
+type reviewEndpoint struct {
+	id        string
+	Name      string           `json:"name"`
+	FilePath  string           `json:"file_path"`
+	Line      int              `json:"line"`
+	Service   string           `json:"service"`
+	Change    string           `json:"change"` // added, removed, or modified (endpoint in a changed file)
+	Consumers []reviewConsumer `json:"consumers,omitempty"`
+}
+
+type reviewConsumer struct {
+	Service  string `json:"service"`
+	Call     string `json:"call"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+}
+
+type reviewNode struct {
+	Name     string `json:"name"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+}
+
+func newReviewCmd() *cobra.Command {
+	var (
+		base    string
+		head    string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "review --base <ref>",
+		Short: "Markdown architecture review of a branch, for posting as a PR comment",
+		Long: `Compare the files changed between the merge base of --base and --head
+(default HEAD) and report, as markdown:
+
+  - API endpoints introduced by the change
+  - endpoints added, removed, or in changed files that are consumed by other
+    services (from the linker's API call matching in the graph)
+  - new functions and methods without a test, by the naming conventions used
+    for Tests edges or coverage data in the graph
+
+Both revisions of each changed file are parsed directly; the graph supplies
+consumers and existing tests and should be indexed before running. No LLM
+calls are made. Example CI step:
+
+  codeeagle review --base origin/main > review.md
+  gh pr comment "$PR" --body-file review.md`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			gitRoot, err := gitutil.GetRepoRoot(".")
+			if err != nil {
+				return fmt.Errorf("find repository root: %w", err)
+			}
+			if base == "" {
+				info, err := gitutil.GetBranchInfo(gitRoot)
+				if err != nil {
+					return fmt.Errorf("detect base branch (use --base): %w", err)
+				}
+				base = info.DefaultBranch
+			}
+			mergeBase, err := gitutil.GetMergeBase(gitRoot, base, head)
+			if err != nil {
+				return fmt.Errorf("merge base of %s and %s: %w", base, head, err)
+			}
+
+			changes, skipped, err := collectFileChanges(gitRoot, cfg, mergeBase, head)
+			if err != nil {
+				return err
+			}
+
+			var store graph.Store
+			if s, _, err := openBranchStore(cfg); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: graph unavailable, consumers and existing tests are not checked: %v\n", err)
+			} else {
+				defer s.Close()
+				store = s
+			}
+
+			report, err := buildReview(ctx(cmd), store, changes)
+			if err != nil {
+				return err
+			}
+			report.Base, report.MergeBase, report.Head = base, mergeBase, head
+			report.Skipped = skipped
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			writeReviewMarkdown(out, report)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&base, "base", "", "base ref the change is merged into (default: the repository's default branch)")
+	cmd.Flags().StringVar(&head, "head", "HEAD", "head ref of the change")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON instead of markdown")
+
+	return cmd
+}
+
+// collectFileChanges parses the files changed between mergeBase and head.
+// Files that fail to parse are returned as skipped.
+func collectFileChanges(gitRoot string, cfg *config.Config, mergeBase, head string) ([]fileChange, []string, error) {
+	added, modified, deleted, err := gitutil.GetChangedFilesBetween(gitRoot, mergeBase, head)
+	if err != nil {
+		return nil, nil, err
+	}
+	toGraphPath := graphPathMapper(gitRoot, cfg)
+	registry := newQuickRegistry(cfg)
+
+	var paths []string
+	paths = append(paths, added...)
+	paths = append(paths, modified...)
+	paths = append(paths, deleted...)
+	sort.Strings(paths)
+
+	var (
+		changes []fileChange
+		skipped []string
+	)
+	for _, p := range paths {
+		gp, ok := toGraphPath(p)
+		if !ok {
+			continue
+		}
+		fp, ok := registry.ParserForFile(gp)
+		if !ok {
+			continue
+		}
+		fc := fileChange{Path: gp}
+		var before, after error
+		fc.Before, before = parseAtRef(gitRoot, mergeBase, p, gp, fp)
+		fc.After, after = parseAtRef(gitRoot, head, p, gp, fp)
+		if fc.Before == nil && fc.After == nil {
+			if after == nil {
+				after = before
+			}
+			skipped = append(skipped, fmt.Sprintf("%s: %v", gp, after))
+			continue
+		}
+		changes = append(changes, fc)
+	}
+	return changes, skipped, nil
+}
+
+// buildReview computes the review sections for the changed files. store may
+// be nil, in which case only the changed files themselves are considered.
+func buildReview(ctx context.Context, store graph.Store, changes []fileChange) (*reviewReport, error) {
+	report := &reviewReport{FilesChanged: len(changes)}
+
+	// Endpoints defined by the changed files, keyed by ID.
+	changed := make(map[string]bool)
+	var endpoints []reviewEndpoint
+	epNodes := make(map[string]*graph.Node)
+	for _, fc := range changes {
+		changed[fc.Path] = true
+		before := nodesOfType(fc.Before, graph.NodeAPIEndpoint)
+		after := nodesOfType(fc.After, graph.NodeAPIEndpoint)
+		for name, n := range after {
+			change := "modified"
+			if before[name] == nil {
+				change = "added"
+			}
+			epNodes[n.ID] = n
+			endpoints = append(endpoints, reviewEndpoint{id: n.ID, Name: n.Name, FilePath: n.FilePath, Line: n.Line, Service: linker.ServiceGroup(n.FilePath), Change: change})
+		}
+		for name, n := range before {
+			if after[name] == nil {
+				epNodes[n.ID] = n
+				endpoints = append(endpoints, reviewEndpoint{id: n.ID, Name: n.Name, FilePath: n.FilePath, Line: n.Line, Service: linker.ServiceGroup(n.FilePath), Change: "removed"})
+			}
+		}
+	}
+
+	consumers := make(map[string][]reviewConsumer) // endpoint ID -> consumers
+	if store != nil && len(epNodes) > 0 {
+		graphEndpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+		if err != nil {
+			return nil, fmt.Errorf("query endpoints: %w", err)
+		}
+		// The changed files' endpoints replace their indexed versions.
+		var all []*graph.Node
+		indexed := make(map[string]*graph.Node)
+		for _, ep := range graphEndpoints {
+			indexed[ep.ID] = ep
+			if !changed[ep.FilePath] {
+				all = append(all, ep)
+			}
+		}
+		// Keep the route prefixes the linker resolved for known endpoints.
+		for id, ep := range epNodes {
+			if g := indexed[id]; g != nil && g.Properties["full_path"] != "" && ep.Properties["full_path"] == "" {
+				props := make(map[string]string, len(ep.Properties)+1)
+				for k, v := range ep.Properties {
+					props[k] = v
+				}
+				props["full_path"] = g.Properties["full_path"]
+				cp := *ep
+				cp.Properties = props
+				epNodes[id] = &cp
+			}
+		}
+		for _, ep := range epNodes {
+			all = append(all, ep)
+		}
+		index := linker.NewEndpointIndex(all)
+
+		calls, err := store.QueryNodes(ctx, graph.NodeFilter{
+			Type:       graph.NodeDependency,
+			Properties: map[string]string{"kind": "api_call"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("query api calls: %w", err)
+		}
+		for _, call := range calls {
+			ep := index.Match(call)
+			if ep == nil {
+				continue
+			}
+			if _, ok := epNodes[ep.ID]; !ok {
+				continue
+			}
+			caller := linker.ServiceGroup(call.FilePath)
+			if caller == linker.ServiceGroup(ep.FilePath) {
+				continue
+			}
+			consumers[ep.ID] = append(consumers[ep.ID], reviewConsumer{Service: caller, Call: call.Name, FilePath: call.FilePath, Line: call.Line})
+		}
+	}
+
+	for _, ep := range endpoints {
+		if ep.Change == "added" {
+			report.NewEndpoints = append(report.NewEndpoints, ep)
+		}
+	}
+	for _, ep := range endpoints {
+		if cs := consumers[ep.id]; len(cs) > 0 {
+			sort.Slice(cs, func(i, j int) bool {
+				if cs[i].Service != cs[j].Service {
+					return cs[i].Service < cs[j].Service
+				}
+				return cs[i].FilePath < cs[j].FilePath
+			})
+			ep.Consumers = cs
+			report.ConsumedEndpoints = append(report.ConsumedEndpoints, ep)
+		}
+	}
+
+	untested, err := untestedNewFunctions(ctx, store, changes)
+	if err != nil {
+		return nil, err
+	}
+	report.UntestedFunctions = untested
+
+	sortReviewEndpoints(report.NewEndpoints)
+	sortReviewEndpoints(report.ConsumedEndpoints)
+	return report, nil
+}
+
+// untestedNewFunctions returns functions and methods added in non-test files
+// that no test exercises: neither a test function named after them (in the
+// graph or the changed files, within the same service) nor a Tests or Covers
+// edge in the graph.
+func untestedNewFunctions(ctx context.Context, store graph.Store, changes []fileChange) ([]reviewNode, error) {
+	tested := make(map[string]bool) // service + "\x00" + lower-case name
+	addTests := func(tests []*graph.Node) {
+		for _, tf := range tests {
+			svc := linker.ServiceGroup(tf.FilePath)
+			for _, name := range linker.TestedNames(tf.Name, tf.Language, tf.FilePath) {
+				tested[svc+"\x00"+strings.ToLower(name)] = true
+			}
+		}
+	}
+	if store != nil {
+		tests, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeTestFunction})
+		if err != nil {
+			return nil, fmt.Errorf("query tests: %w", err)
+		}
+		addTests(tests)
+	}
+	for _, fc := range changes {
+		if fc.After == nil {
+			continue
+		}
+		var tests []*graph.Node
+		for _, n := range fc.After.Nodes {
+			if n.Type == graph.NodeTestFunction {
+				tests = append(tests, n)
+			}
+		}
+		addTests(tests)
+	}
+
+	var out []reviewNode
+	for _, fc := range changes {
+		if fc.After == nil || len(nodesOfType(fc.After, graph.NodeTestFile)) > 0 {
+			continue
+		}
+		svc := linker.ServiceGroup(fc.Path)
+		for _, typ := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+			before := nodesOfType(fc.Before, typ)
+			for name, n := range nodesOfType(fc.After, typ) {
+				// Anonymous functions are named by position, so they look new
+				// whenever the code above them moves.
+				if before[name] != nil || isEntrypointFunc(n) || n.Properties["anonymous"] == "true" {
+					continue
+				}
+				if tested[svc+"\x00"+strings.ToLower(n.Name)] {
+					continue
+				}
+				if store != nil {
+					if ok, err := hasIncomingTestEdge(ctx, store, n.ID); err != nil {
+						return nil, fmt.Errorf("get tests of %s: %w", n.Name, err)
+					} else if ok {
+						continue
+					}
+				}
+				out = append(out, reviewNode{Name: n.Name, FilePath: n.FilePath, Line: n.Line})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].FilePath != out[j].FilePath {
+			return out[i].FilePath < out[j].FilePath
+		}
+		return out[i].Line < out[j].Line
+	})
+	return out, nil
+}
+
+// isEntrypointFunc reports whether n is a program entry point that tests
+// don't call directly.
+func isEntrypointFunc(n *graph.Node) bool {
+	return n.Language == string(parser.LangGo) && n.Type == graph.NodeFunction && (n.Name == "main" || n.Name == "init")
+}
+
+// nodesOfType returns the nodes of the given type in a parse result, keyed
+// by name. A nil result yields an empty map.
+func nodesOfType(res *parser.ParseResult, t graph.NodeType) map[string]*graph.Node {
+	nodes := make(map[string]*graph.Node)
+	if res == nil {
+		return nodes
+	}
+	for _, n := range res.Nodes {
+		if n.Type == t {
+			nodes[n.Name] = n
+		}
+	}
+	return nodes
+}
+
+func sortReviewEndpoints(eps []reviewEndpoint) {
+	sort.Slice(eps, func(i, j int) bool {
+		if eps[i].FilePath != eps[j].FilePath {
+			return eps[i].FilePath < eps[j].FilePath
+		}
+		return eps[i].Line < eps[j].Line
+	})
+}
+
+// writeReviewMarkdown renders the report as a PR comment.
+func writeReviewMarkdown(w io.Writer, r *reviewReport) {
+	fmt.Fprintln(w, "## CodeEagle architecture review")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Compared `%s` with `%s` (merge base `%s`): %d analyzed file(s) changed.\n",
+		r.Head, r.Base, shortCommit(r.MergeBase), r.FilesChanged)
+
+	if len(r.NewEndpoints)+len(r.ConsumedEndpoints)+len(r.UntestedFunctions) == 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No new endpoints, cross-service consumers, or untested new functions.")
+	}
+
+	if len(r.NewEndpoints) > 0 {
+		fmt.Fprintf(w, "\n### New endpoints (%d)\n\n", len(r.NewEndpoints))
+		fmt.Fprintln(w, "| Endpoint | Service | Location |")
+		fmt.Fprintln(w, "|---|---|---|")
+		for _, ep := range r.NewEndpoints {
+			fmt.Fprintf(w, "| `%s` | %s | `%s:%d` |\n", ep.Name, ep.Service, ep.FilePath, ep.Line)
+		}
+	}
+
+	if len(r.ConsumedEndpoints) > 0 {
+		fmt.Fprintf(w, "\n### Endpoints consumed by other services (%d)\n\n", len(r.ConsumedEndpoints))
+		fmt.Fprintln(w, "Check these changes for compatibility with their consumers.")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Endpoint | Change | Consumers |")
+		fmt.Fprintln(w, "|---|---|---|")
+		for _, ep := range r.ConsumedEndpoints {
+			var cs []string
+			for _, c := range ep.Consumers {
+				cs = append(cs, fmt.Sprintf("%s (`%s:%d`)", c.Service, c.FilePath, c.Line))
+			}
+			change := ep.Change
+			if change == "removed" {
+				change = "**removed**"
+			}
+			fmt.Fprintf(w, "| `%s` | %s | %s |\n", ep.Name, change, strings.Join(cs, "<br>"))
+		}
+	}
+
+	if len(r.UntestedFunctions) > 0 {
+		fmt.Fprintf(w, "\n### Untested new functions (%d)\n\n", len(r.UntestedFunctions))
+		for _, n := range r.UntestedFunctions {
+			fmt.Fprintf(w, "- `%s` (`%s:%d`)\n", n.Name, n.FilePath, n.Line)
+		}
+	}
+
+	if len(r.Skipped) > 0 {
+		fmt.Fprintf(w, "\n<details><summary>%d file(s) could not be parsed</summary>\n\n", len(r.Skipped))
+		for _, s := range r.Skipped {
+			fmt.Fprintf(w, "- %s\n", s)
+		}
+		fmt.Fprintln(w, "\n</details>")
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestBuildReview(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	endpoint := func(id, name, path, file string, line int) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeAPIEndpoint, Name: name, FilePath: file, Line: line,
+			Properties: map[string]string{"path": path}}
+	}
+	fn := func(typ graph.NodeType, name, file string, line int) *graph.Node {
+		return &graph.Node{ID: file + "#" + name, Type: typ, Name: name, FilePath: file, Line: line, Language: "go"}
+	}
+
+	addTestNodes(t, store,
+		endpoint("ep-list", "GET /orders", "/orders", "orders/api.go", 10),
+		endpoint("ep-legacy", "GET /legacy", "/legacy", "orders/api.go", 20),
+		// web consumes both orders endpoints; orders calls itself, which isn't cross-service.
+		&graph.Node{ID: "call-list", Type: graph.NodeDependency, Name: "GET /orders", FilePath: "web/client.go", Line: 7,
+			Properties: map[string]string{"kind": "api_call", "path": "/orders"}},
+		&graph.Node{ID: "call-legacy", Type: graph.NodeDependency, Name: "GET /legacy", FilePath: "web/client.go", Line: 9,
+			Properties: map[string]string{"kind": "api_call", "path": "/legacy"}},
+		&graph.Node{ID: "call-self", Type: graph.NodeDependency, Name: "GET /orders", FilePath: "orders/worker.go", Line: 3,
+			Properties: map[string]string{"kind": "api_call", "path": "/orders"}},
+		// An existing test covering validate by name.
+		fn(graph.NodeTestFunction, "TestValidate", "orders/validate_test.go", 5),
+		fn(graph.NodeFunction, "Covered", "orders/api.go", 60),
+	)
+	if err := store.AddEdge(ctx, &graph.Edge{ID: "cov", Type: graph.EdgeCovers, SourceID: "orders/validate_test.go#TestValidate", TargetID: "orders/api.go#Covered"}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := []fileChange{
+		{
+			Path: "orders/api.go",
+			Before: &parser.ParseResult{Nodes: []*graph.Node{
+				endpoint("ep-list", "GET /orders", "/orders", "orders/api.go", 10),
+				endpoint("ep-legacy", "GET /legacy", "/legacy", "orders/api.go", 20),
+				fn(graph.NodeFunction, "listOrders", "orders/api.go", 30),
+			}},
+			After: &parser.ParseResult{Nodes: []*graph.Node{
+				endpoint("ep-list", "GET /orders", "/orders", "orders/api.go", 10),
+				endpoint("ep-create", "POST /orders/new", "/orders/new", "orders/api.go", 12),
+				fn(graph.NodeFunction, "listOrders", "orders/api.go", 30),
+				fn(graph.NodeFunction, "createOrder", "orders/api.go", 40),
+				fn(graph.NodeFunction, "validate", "orders/api.go", 50),
+				fn(graph.NodeFunction, "Covered", "orders/api.go", 60),
+				fn(graph.NodeFunction, "main", "orders/api.go", 70),
+			}},
+		},
+		{
+			Path: "orders/api_test.go",
+			After: &parser.ParseResult{Nodes: []*graph.Node{
+				{Type: graph.NodeTestFile, Name: "orders/api_test.go", FilePath: "orders/api_test.go"},
+				fn(graph.NodeTestFunction, "TestCreateOrder", "orders/api_test.go", 5),
+				fn(graph.NodeFunction, "newFixture", "orders/api_test.go", 20),
+			}},
+		},
+	}
+
+	report, err := buildReview(ctx, store, changes)
+	if err != nil {
+		t.Fatalf("buildReview: %v", err)
+	}
+
+	if len(report.NewEndpoints) != 1 || report.NewEndpoints[0].Name != "POST /orders/new" || report.NewEndpoints[0].Service != "orders" {
+		t.Errorf("new endpoints = %+v", report.NewEndpoints)
+	}
+
+	consumed := make(map[string]reviewEndpoint)
+	for _, ep := range report.ConsumedEndpoints {
+		consumed[ep.Name] = ep
+	}
+	if len(consumed) != 2 {
+		t.Fatalf("consumed endpoints = %+v", report.ConsumedEndpoints)
+	}
+	if ep := consumed["GET /orders"]; ep.Change != "modified" || len(ep.Consumers) != 1 || ep.Consumers[0].Service != "web" {
+		t.Errorf("GET /orders = %+v", ep)
+	}
+	if ep := consumed["GET /legacy"]; ep.Change != "removed" || len(ep.Consumers) != 1 {
+		t.Errorf("GET /legacy = %+v", ep)
+	}
+
+	if len(report.UntestedFunctions) != 0 {
+		var names []string
+		for _, n := range report.UntestedFunctions {
+			names = append(names, n.Name)
+		}
+		t.Errorf("untested functions = %v, want none", names)
+	}
+
+	// Without the graph, only tests in the change count.
+	report, err = buildReview(ctx, nil, changes)
+	if err != nil {
+		t.Fatalf("buildReview without graph: %v", err)
+	}
+	var untested []string
+	for _, n := range report.UntestedFunctions {
+		untested = append(untested, n.Name)
+	}
+	if strings.Join(untested, ",") != "validate,Covered" {
+		t.Errorf("untested without graph = %v", untested)
+	}
+	if len(report.ConsumedEndpoints) != 0 {
+		t.Errorf("consumed without graph = %+v", report.ConsumedEndpoints)
+	}
+
+	var out bytes.Buffer
+	report.Base, report.Head, report.MergeBase = "main", "HEAD", "0123456789abcdef"
+	writeReviewMarkdown(&out, report)
+	for _, want := range []string{"## CodeEagle architecture review", "merge base `0123456789ab`", "| `POST /orders/new` | orders | `orders/api.go:12` |", "### Untested new functions (2)", "- `validate` (`orders/api.go:50`)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, out.String())
+		}
+	}
+}
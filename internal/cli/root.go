@@ -12,6 +12,7 @@ var (
 	cfgFile     string
 	verbose     bool
 	dbPath      string
+	namespace   string
 	projectName string
 )
 
@@ -55,6 +56,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: .CodeEagle/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().StringVar(&dbPath, "db-path", "", "path for the graph database")
+	rootCmd.PersistentFlags().StringVar(&namespace, "namespace", "", "graph namespace (tenant) within a shared graph database")
 	rootCmd.PersistentFlags().StringVarP(&projectName, "project-name", "p", "", "project name (looks up in ~/.codeeagle.conf registry)")
 
 	// Bind flags to viper
@@ -72,19 +74,57 @@ func init() {
 	rootCmd.AddCommand(newStatusCmd())
 	rootCmd.AddCommand(newAgentCmd())
 	rootCmd.AddCommand(newQueryCmd())
+	rootCmd.AddCommand(newCallersCmd())
+	rootCmd.AddCommand(newCalleesCmd())
+	rootCmd.AddCommand(newAtCmd())
 	rootCmd.AddCommand(newMetricsCmd())
 	rootCmd.AddCommand(newLLMTestCmd())
 	rootCmd.AddCommand(newCompletionCmd())
 	rootCmd.AddCommand(newSyncCmd())
 	rootCmd.AddCommand(newHookCmd())
 	rootCmd.AddCommand(newBackpopPathsCmd())
+	rootCmd.AddCommand(newBackpopIDsCmd())
 	rootCmd.AddCommand(newBackpopCmd())
 	rootCmd.AddCommand(newMCPCmd())
+	rootCmd.AddCommand(newLSPCmd())
+	rootCmd.AddCommand(newDaemonCmd())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newUpdateCmd())
 	rootCmd.AddCommand(newConfigCmd())
 	rootCmd.AddCommand(newVectorIndexCmd())
 	rootCmd.AddCommand(newRagCmd())
+	rootCmd.AddCommand(newUnresolvedCmd())
+	rootCmd.AddCommand(newProblemsCmd())
+	rootCmd.AddCommand(newFindingsCmd())
+	rootCmd.AddCommand(newCoverageCmd())
+	rootCmd.AddCommand(newTestResultsCmd())
+	rootCmd.AddCommand(newTracesCmd())
+	rootCmd.AddCommand(newChurnCmd())
+	rootCmd.AddCommand(newHotspotsCmd())
+	rootCmd.AddCommand(newDocCoverageCmd())
+	rootCmd.AddCommand(newDebtCmd())
+	rootCmd.AddCommand(newIssuesCmd())
+	rootCmd.AddCommand(newBookmarkCmd())
+	rootCmd.AddCommand(newLinkCmd())
+	rootCmd.AddCommand(newOpenCmd())
+	rootCmd.AddCommand(newQuickCmd())
+	rootCmd.AddCommand(newIndexCmd())
+	rootCmd.AddCommand(newReportCmd())
+	rootCmd.AddCommand(newNamespaceCmd())
+	rootCmd.AddCommand(newCompactCmd())
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.AddCommand(newRestoreCmd())
+	rootCmd.AddCommand(newLicensesCmd())
+	rootCmd.AddCommand(newDriftCmd())
+	rootCmd.AddCommand(newAuditCmd())
+	rootCmd.AddCommand(newDataFlowCmd())
+	rootCmd.AddCommand(newSnapshotCmd())
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newReviewCmd())
+	rootCmd.AddCommand(newDiagramCmd())
+	rootCmd.AddCommand(newDocsCmd())
+	rootCmd.AddCommand(newSuggestCmd())
+	rootCmd.AddCommand(newContextCmd())
 
 	// Conditionally register faces commands (requires -tags faces build).
 	if registerFacesCmd != nil {
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/savedquery"
+)
+
+// writeSavedQueries lists the saved queries in dir.
+func writeSavedQueries(out io.Writer, queries []*savedquery.Query, dir string) {
+	if len(queries) == 0 {
+		fmt.Fprintf(out, "No saved queries in %s.\n", dir)
+		fmt.Fprintln(out, "Add <name>.yaml files there, then run 'codeeagle report <name>'.")
+		return
+	}
+	width := 0
+	for _, q := range queries {
+		width = max(width, len(q.Name))
+	}
+	fmt.Fprintf(out, "Saved queries (%s):\n", dir)
+	for _, q := range queries {
+		fmt.Fprintf(out, "  %-*s  %s\n", width, q.Name, q.Description)
+	}
+}
+
+// writeSavedQueryResult renders a saved query result in the given format.
+func writeSavedQueryResult(out io.Writer, res *savedquery.Result, format string) error {
+	switch format {
+	case savedquery.FormatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Name        string              `json:"name"`
+			Description string              `json:"description,omitempty"`
+			Columns     []string            `json:"columns"`
+			Rows        []map[string]string `json:"rows"`
+		}{res.Name, res.Description, res.Columns, res.Records()})
+	case savedquery.FormatCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(res.Columns); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+		if err := w.WriteAll(res.Rows); err != nil {
+			return fmt.Errorf("write csv: %w", err)
+		}
+		return nil
+	case "", savedquery.FormatTable:
+	default:
+		return fmt.Errorf("unknown format %q (want table, json or csv)", format)
+	}
+
+	if res.Description != "" {
+		fmt.Fprintf(out, "%s\n\n", res.Description)
+	}
+	widths := make([]int, len(res.Columns))
+	for i, c := range res.Columns {
+		widths[i] = len(c)
+		for _, row := range res.Rows {
+			widths[i] = max(widths[i], len(row[i]))
+		}
+	}
+	writeRow := func(cells []string) {
+		parts := make([]string, len(cells))
+		for i, c := range cells {
+			parts[i] = fmt.Sprintf("%-*s", widths[i], c)
+		}
+		fmt.Fprintln(out, strings.TrimRight(strings.Join(parts, "  "), " "))
+	}
+	header := make([]string, len(res.Columns))
+	rule := make([]string, len(res.Columns))
+	for i, c := range res.Columns {
+		header[i] = strings.ToUpper(c)
+		rule[i] = strings.Repeat("-", widths[i])
+	}
+	writeRow(header)
+	writeRow(rule)
+	for _, row := range res.Rows {
+		writeRow(row)
+	}
+	fmt.Fprintf(out, "\n%d result(s)\n", len(res.Rows))
+	return nil
+}
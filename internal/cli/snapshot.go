@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+// snapshotBranchPrefix prefixes the graph branches holding commit snapshots.
+const snapshotBranchPrefix = "snapshot/"
+
+func newSnapshotCmd() *cobra.Command {
+	var (
+		list   bool
+		remove bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "snapshot [sha]",
+		Short: "Tag the current graph with a commit SHA for later diffing",
+		Long: `Copy the current graph (the current branch overlaid on the default
+branch) into the snapshot/<sha> graph branch. The SHA defaults to HEAD of the
+first configured repository.
+
+Snapshots can be compared with 'codeeagle diff <shaA> <shaB>' and opened in
+links and bookmarks as the snapshot/<sha> graph.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			out := cmd.OutOrStdout()
+			if list {
+				snapshots, err := listSnapshots(store)
+				if err != nil {
+					return err
+				}
+				if len(snapshots) == 0 {
+					fmt.Fprintln(out, "No snapshots.")
+				}
+				for _, s := range snapshots {
+					fmt.Fprintln(out, strings.TrimPrefix(s, snapshotBranchPrefix))
+				}
+				return nil
+			}
+
+			if remove {
+				if len(args) == 0 {
+					return fmt.Errorf("--delete requires a snapshot SHA")
+				}
+				branch, err := resolveSnapshot(store, args[0])
+				if err != nil {
+					return err
+				}
+				if err := store.DeleteByBranch(branch); err != nil {
+					return fmt.Errorf("delete snapshot %s: %w", branch, err)
+				}
+				fmt.Fprintf(out, "Deleted snapshot %s\n", strings.TrimPrefix(branch, snapshotBranchPrefix))
+				return nil
+			}
+
+			var sha string
+			if len(args) == 1 {
+				sha = strings.TrimSpace(args[0])
+			} else {
+				if len(cfg.Repositories) == 0 {
+					return fmt.Errorf("no repositories configured; give the commit SHA explicitly")
+				}
+				sha, err = gitutil.GetCurrentHEAD(cfg.Repositories[0].Path)
+				if err != nil {
+					return fmt.Errorf("resolve HEAD: %w", err)
+				}
+			}
+			if sha == "" || strings.ContainsAny(sha, ": \t/") {
+				return fmt.Errorf("invalid snapshot SHA %q", sha)
+			}
+
+			nodes, edges, err := store.CopyReadBranches(ctx(cmd), snapshotBranchPrefix+sha)
+			if err != nil {
+				return fmt.Errorf("snapshot %s: %w", sha, err)
+			}
+			fmt.Fprintf(out, "Snapshot %s: %d nodes, %d edges\n", shortCommit(sha), nodes, edges)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "list existing snapshots")
+	cmd.Flags().BoolVar(&remove, "delete", false, "delete the given snapshot")
+
+	return cmd
+}
+
+// listSnapshots returns the snapshot branches in the store, sorted.
+func listSnapshots(store *embedded.BranchStore) ([]string, error) {
+	branches, err := store.ListBranches()
+	if err != nil {
+		return nil, fmt.Errorf("list graph snapshots: %w", err)
+	}
+	var snapshots []string
+	for _, b := range branches {
+		if strings.HasPrefix(b, snapshotBranchPrefix) {
+			snapshots = append(snapshots, b)
+		}
+	}
+	sort.Strings(snapshots)
+	return snapshots, nil
+}
+
+// resolveSnapshot maps a (possibly abbreviated) SHA to its snapshot branch.
+func resolveSnapshot(store *embedded.BranchStore, sha string) (string, error) {
+	snapshots, err := listSnapshots(store)
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, s := range snapshots {
+		if s == snapshotBranchPrefix+sha {
+			return s, nil
+		}
+		if strings.HasPrefix(s, snapshotBranchPrefix+sha) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no snapshot for %q; create one with 'codeeagle snapshot'", sha)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("snapshot SHA %q is ambiguous (%d matches)", sha, len(matches))
+	}
+}
+
+// graphDiff is the architecture-level difference between two snapshots.
+type graphDiff struct {
+	From                string           `json:"from"`
+	To                  string           `json:"to"`
+	AddedEndpoints      []diffNode       `json:"added_endpoints"`
+	RemovedEndpoints    []diffNode       `json:"removed_endpoints"`
+	AddedDependencies   []diffDependency `json:"added_dependencies"`
+	RemovedDependencies []diffDependency `json:"removed_dependencies"`
+	RemovedTests        []diffNode       `json:"removed_tests"`
+}
+
+type diffNode struct {
+	Name     string `json:"name"`
+	FilePath string `json:"file_path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+type diffDependency struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// empty reports whether the snapshots have no architectural differences.
+func (d *graphDiff) empty() bool {
+	return len(d.AddedEndpoints)+len(d.RemovedEndpoints)+len(d.AddedDependencies)+
+		len(d.RemovedDependencies)+len(d.RemovedTests) == 0
+}
+
+func newDiffCmd() *cobra.Command {
+	var (
+		jsonOut    bool
+		failOnDiff bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <shaA> <shaB>",
+		Short: "Compare two graph snapshots: endpoints, service dependencies, tests",
+		Long: `Report the architecture changes between two snapshots taken with
+'codeeagle snapshot': API endpoints added and removed, service-to-service
+dependencies added and removed, and test functions removed. SHAs may be
+abbreviated.
+
+Useful in CI for reviewing the architectural impact of a pull request:
+
+  codeeagle snapshot $BASE_SHA   # after indexing the base
+  codeeagle snapshot $HEAD_SHA   # after indexing the PR head
+  codeeagle diff $BASE_SHA $HEAD_SHA`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			from, err := resolveSnapshot(store, args[0])
+			if err != nil {
+				return err
+			}
+			to, err := resolveSnapshot(store, args[1])
+			if err != nil {
+				return err
+			}
+			a := store.WithReadBranches([]string{from})
+			b := store.WithReadBranches([]string{to})
+
+			diff, err := diffGraphs(ctx(cmd), a, b)
+			if err != nil {
+				return err
+			}
+			diff.From = strings.TrimPrefix(from, snapshotBranchPrefix)
+			diff.To = strings.TrimPrefix(to, snapshotBranchPrefix)
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(diff); err != nil {
+					return err
+				}
+			} else {
+				writeGraphDiff(out, diff)
+			}
+			if failOnDiff && !diff.empty() {
+				return fmt.Errorf("architecture changed between %s and %s", shortCommit(diff.From), shortCommit(diff.To))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&failOnDiff, "fail-on-diff", false, "exit non-zero when the snapshots differ (for CI)")
+
+	return cmd
+}
+
+// diffGraphs compares graph a (before) with graph b (after).
+func diffGraphs(ctx context.Context, a, b graph.Store) (*graphDiff, error) {
+	diff := &graphDiff{}
+
+	epA, err := nodesByID(ctx, a, graph.NodeAPIEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	epB, err := nodesByID(ctx, b, graph.NodeAPIEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	diff.AddedEndpoints = missingNodes(epB, epA)
+	diff.RemovedEndpoints = missingNodes(epA, epB)
+
+	testsA, err := nodesByID(ctx, a, graph.NodeTestFunction)
+	if err != nil {
+		return nil, err
+	}
+	testsB, err := nodesByID(ctx, b, graph.NodeTestFunction)
+	if err != nil {
+		return nil, err
+	}
+	diff.RemovedTests = missingNodes(testsA, testsB)
+
+	depsA, err := serviceDependencies(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	depsB, err := serviceDependencies(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	diff.AddedDependencies = missingDependencies(depsB, depsA)
+	diff.RemovedDependencies = missingDependencies(depsA, depsB)
+	return diff, nil
+}
+
+func nodesByID(ctx context.Context, store graph.Store, typ graph.NodeType) (map[string]*graph.Node, error) {
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ})
+	if err != nil {
+		return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+	}
+	byID := make(map[string]*graph.Node, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+	return byID, nil
+}
+
+// missingNodes returns the nodes of have that are absent from other, sorted
+// by file and line.
+func missingNodes(have, other map[string]*graph.Node) []diffNode {
+	var out []diffNode
+	for id, n := range have {
+		if _, ok := other[id]; !ok {
+			out = append(out, diffNode{Name: n.Name, FilePath: n.FilePath, Line: n.Line})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].FilePath != out[j].FilePath {
+			return out[i].FilePath < out[j].FilePath
+		}
+		if out[i].Line != out[j].Line {
+			return out[i].Line < out[j].Line
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// serviceDependencies returns the DependsOn edges between Service nodes,
+// keyed by service name so that snapshots with differently derived service
+// IDs still compare.
+func serviceDependencies(ctx context.Context, store graph.Store) (map[diffDependency]bool, error) {
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query services: %w", err)
+	}
+	names := make(map[string]string, len(services))
+	for _, svc := range services {
+		names[svc.ID] = svc.Name
+	}
+	deps := make(map[diffDependency]bool)
+	for _, svc := range services {
+		edges, err := store.GetEdges(ctx, svc.ID, graph.EdgeDependsOn)
+		if err != nil {
+			return nil, fmt.Errorf("get dependencies of %s: %w", svc.Name, err)
+		}
+		for _, e := range edges {
+			if e.SourceID != svc.ID {
+				continue
+			}
+			if target, ok := names[e.TargetID]; ok && target != svc.Name {
+				deps[diffDependency{From: svc.Name, To: target}] = true
+			}
+		}
+	}
+	return deps, nil
+}
+
+func missingDependencies(have, other map[diffDependency]bool) []diffDependency {
+	var out []diffDependency
+	for d := range have {
+		if !other[d] {
+			out = append(out, d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].From != out[j].From {
+			return out[i].From < out[j].From
+		}
+		return out[i].To < out[j].To
+	})
+	return out
+}
+
+// writeGraphDiff renders the diff as text.
+func writeGraphDiff(w io.Writer, d *graphDiff) {
+	fmt.Fprintf(w, "Graph diff %s..%s\n", shortCommit(d.From), shortCommit(d.To))
+	if d.empty() {
+		fmt.Fprintln(w, "\nNo architecture changes.")
+		return
+	}
+
+	writeNodes := func(sign string, nodes []diffNode) {
+		for _, n := range nodes {
+			if n.FilePath != "" {
+				fmt.Fprintf(w, "  %s %s  (%s:%d)\n", sign, n.Name, n.FilePath, n.Line)
+			} else {
+				fmt.Fprintf(w, "  %s %s\n", sign, n.Name)
+			}
+		}
+	}
+	if len(d.AddedEndpoints)+len(d.RemovedEndpoints) > 0 {
+		fmt.Fprintf(w, "\nEndpoints (+%d -%d):\n", len(d.AddedEndpoints), len(d.RemovedEndpoints))
+		writeNodes("+", d.AddedEndpoints)
+		writeNodes("-", d.RemovedEndpoints)
+	}
+	if len(d.AddedDependencies)+len(d.RemovedDependencies) > 0 {
+		fmt.Fprintf(w, "\nService dependencies (+%d -%d):\n", len(d.AddedDependencies), len(d.RemovedDependencies))
+		for _, dep := range d.AddedDependencies {
+			fmt.Fprintf(w, "  + %s -> %s\n", dep.From, dep.To)
+		}
+		for _, dep := range d.RemovedDependencies {
+			fmt.Fprintf(w, "  - %s -> %s\n", dep.From, dep.To)
+		}
+	}
+	if len(d.RemovedTests) > 0 {
+		fmt.Fprintf(w, "\nRemoved tests (%d):\n", len(d.RemovedTests))
+		writeNodes("-", d.RemovedTests)
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestDiffGraphs(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewBranchStore(t.TempDir(), "main", []string{"main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	before := []*graph.Node{
+		{ID: "svc-web", Type: graph.NodeService, Name: "web"},
+		{ID: "svc-orders", Type: graph.NodeService, Name: "orders"},
+		{ID: "svc-users", Type: graph.NodeService, Name: "users"},
+		{ID: "ep-list", Type: graph.NodeAPIEndpoint, Name: "GET /orders", FilePath: "orders/api.go", Line: 10},
+		{ID: "ep-old", Type: graph.NodeAPIEndpoint, Name: "GET /legacy", FilePath: "orders/api.go", Line: 20},
+		{ID: "t-list", Type: graph.NodeTestFunction, Name: "TestList", FilePath: "orders/api_test.go", Line: 5},
+		{ID: "t-legacy", Type: graph.NodeTestFunction, Name: "TestLegacy", FilePath: "orders/api_test.go", Line: 15},
+	}
+	after := []*graph.Node{
+		before[0], before[1], before[2], before[3], before[5],
+		{ID: "ep-create", Type: graph.NodeAPIEndpoint, Name: "POST /orders", FilePath: "orders/api.go", Line: 30},
+	}
+	// Build each snapshot by indexing into main and snapshotting it.
+	write := func(sha string, nodes []*graph.Node, deps [][2]string) {
+		t.Helper()
+		if err := store.DeleteByBranch("main"); err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range nodes {
+			if err := store.AddNode(ctx, n); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, d := range deps {
+			if err := store.AddEdge(ctx, &graph.Edge{ID: d[0] + "->" + d[1], Type: graph.EdgeDependsOn, SourceID: d[0], TargetID: d[1]}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, _, err := store.CopyReadBranches(ctx, snapshotBranchPrefix+sha); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("aaa111", before, [][2]string{{"svc-web", "svc-users"}})
+	write("bbb222", after, [][2]string{{"svc-web", "svc-orders"}})
+
+	from, err := resolveSnapshot(store, "aaa")
+	if err != nil || from != snapshotBranchPrefix+"aaa111" {
+		t.Fatalf("resolveSnapshot(aaa) = %q, %v", from, err)
+	}
+	if _, err := resolveSnapshot(store, "ccc"); err == nil {
+		t.Error("expected error for unknown snapshot")
+	}
+
+	diff, err := diffGraphs(ctx, store.WithReadBranches([]string{from}), store.WithReadBranches([]string{snapshotBranchPrefix + "bbb222"}))
+	if err != nil {
+		t.Fatalf("diffGraphs: %v", err)
+	}
+	names := func(nodes []diffNode) []string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, n.Name)
+		}
+		return out
+	}
+	if got := names(diff.AddedEndpoints); len(got) != 1 || got[0] != "POST /orders" {
+		t.Errorf("added endpoints = %v", got)
+	}
+	if got := names(diff.RemovedEndpoints); len(got) != 1 || got[0] != "GET /legacy" {
+		t.Errorf("removed endpoints = %v", got)
+	}
+	if got := names(diff.RemovedTests); len(got) != 1 || got[0] != "TestLegacy" {
+		t.Errorf("removed tests = %v", got)
+	}
+	if len(diff.AddedDependencies) != 1 || diff.AddedDependencies[0] != (diffDependency{"web", "orders"}) {
+		t.Errorf("added dependencies = %v", diff.AddedDependencies)
+	}
+	if len(diff.RemovedDependencies) != 1 || diff.RemovedDependencies[0] != (diffDependency{"web", "users"}) {
+		t.Errorf("removed dependencies = %v", diff.RemovedDependencies)
+	}
+
+	var out bytes.Buffer
+	writeGraphDiff(&out, diff)
+	for _, want := range []string{"Endpoints (+1 -1)", "+ web -> orders", "- TestLegacy"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -26,7 +26,7 @@ func newStatusCmd() *cobra.Command {
 				return fmt.Errorf("load config: %w", err)
 			}
 
-			store, currentBranch, err := openBranchStore(cfg)
+			store, currentBranch, err := openReadOnlyBranchStore(cfg)
 			if err != nil {
 				return err
 			}
@@ -42,6 +42,9 @@ func newStatusCmd() *cobra.Command {
 			fmt.Fprintf(out, "Knowledge Graph Status\n")
 			fmt.Fprintf(out, "======================\n\n")
 			fmt.Fprintf(out, "  Active branch: %s\n", currentBranch)
+			if version, err := store.SchemaVersion(); err == nil {
+				fmt.Fprintf(out, "  Schema:        v%d\n", version)
+			}
 			fmt.Fprintf(out, "  Total nodes:   %d\n", stats.NodeCount)
 			fmt.Fprintf(out, "  Total edges:   %d\n\n", stats.EdgeCount)
 
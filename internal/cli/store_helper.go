@@ -2,17 +2,52 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/imyousuf/CodeEagle/internal/config"
 	"github.com/imyousuf/CodeEagle/internal/gitutil"
 	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
 )
 
+// newBranchStore opens the graph store at path in the namespace chosen by
+// the --namespace flag or graph.namespace config, reading node source from
+// the configured repositories.
+func newBranchStore(cfg *config.Config, path, writeBranch string, readBranches []string) (*embedded.BranchStore, error) {
+	store, err := embedded.NewNamespacedStore(path, cfg.ResolveNamespace(namespace), writeBranch, readBranches)
+	if err != nil {
+		return nil, fmt.Errorf("open graph store: %w", err)
+	}
+	store.SetSourceRoots(repositoryPaths(cfg)...)
+	return store, nil
+}
+
+// newReadOnlyStore opens the graph store at path read-only in the
+// namespace chosen by the --namespace flag or graph.namespace config.
+func newReadOnlyStore(cfg *config.Config, path string, readBranches []string) (*embedded.BranchStore, error) {
+	store, err := embedded.OpenReadOnly(path, cfg.ResolveNamespace(namespace), readBranches)
+	if err != nil {
+		return nil, fmt.Errorf("open graph store: %w", err)
+	}
+	store.SetSourceRoots(repositoryPaths(cfg)...)
+	return store, nil
+}
+
 // openBranchStore opens a BranchStore using the config and CLI flags.
 // It resolves the DB path, detects the current git branch from the first
 // repository, and builds the readBranches list.
 // Returns the store, the current branch name, and any error.
 func openBranchStore(cfg *config.Config) (*embedded.BranchStore, string, error) {
+	return openStore(cfg, false)
+}
+
+// openReadOnlyBranchStore is openBranchStore for commands that only read
+// the graph. It works while sync, watch or the daemon write to the DB,
+// reading the graph as of when it opens.
+func openReadOnlyBranchStore(cfg *config.Config) (*embedded.BranchStore, string, error) {
+	return openStore(cfg, true)
+}
+
+func openStore(cfg *config.Config, readOnly bool) (*embedded.BranchStore, string, error) {
 	resolvedDBPath := cfg.ResolveDBPath(dbPath)
 	if resolvedDBPath == "" {
 		return nil, "", fmt.Errorf("no graph database path; run 'codeeagle init' or use --db-path")
@@ -39,10 +74,76 @@ func openBranchStore(cfg *config.Config) (*embedded.BranchStore, string, error)
 		readBranches = append(readBranches, defaultBranch)
 	}
 
-	store, err := embedded.NewBranchStore(resolvedDBPath, currentBranch, readBranches)
+	open := func(readBranches []string) (*embedded.BranchStore, error) {
+		if readOnly {
+			return newReadOnlyStore(cfg, resolvedDBPath, readBranches)
+		}
+		return newBranchStore(cfg, resolvedDBPath, currentBranch, readBranches)
+	}
+	store, err := open(readBranches)
 	if err != nil {
-		return nil, "", fmt.Errorf("open graph store: %w", err)
+		return nil, "", err
+	}
+
+	// Graphs built with 'codeeagle index' are keyed by the fetched ref rather
+	// than the local branch. When an explicit --db-path holds none of the
+	// expected branches, read all of its branches instead.
+	if dbPath != "" {
+		if branches, err := store.ListBranches(); err == nil && len(branches) > 0 && !containsAny(branches, readBranches) {
+			store.Close()
+			store, err = open(branches)
+			if err != nil {
+				return nil, "", err
+			}
+		}
 	}
 
 	return store, currentBranch, nil
 }
+
+// repositoryPaths returns the paths of the configured repositories, which
+// the graph's relative file paths are resolved against.
+func repositoryPaths(cfg *config.Config) []string {
+	roots := make([]string, 0, len(cfg.Repositories))
+	for _, repo := range cfg.Repositories {
+		roots = append(roots, repo.Path)
+	}
+	return roots
+}
+
+// containsAny reports whether any of want appears in have.
+func containsAny(have, want []string) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// openSnapshotStore opens the graph store reading only the given graph
+// snapshot (branch). An empty snapshot, or the current branch, behaves like
+// openBranchStore.
+func openSnapshotStore(cfg *config.Config, snapshot string) (*embedded.BranchStore, string, error) {
+	store, currentBranch, err := openBranchStore(cfg)
+	if err != nil || snapshot == "" || snapshot == currentBranch {
+		return store, currentBranch, err
+	}
+	branches, err := store.ListBranches()
+	if err != nil {
+		store.Close()
+		return nil, "", fmt.Errorf("list graph snapshots: %w", err)
+	}
+	if !containsAny(branches, []string{snapshot}) {
+		store.Close()
+		return nil, "", fmt.Errorf("graph snapshot %q not found (available: %s)", snapshot, strings.Join(branches, ", "))
+	}
+	store.Close()
+	store, err = newBranchStore(cfg, cfg.ResolveDBPath(dbPath), currentBranch, []string{snapshot})
+	if err != nil {
+		return nil, "", err
+	}
+	return store, currentBranch, nil
+}
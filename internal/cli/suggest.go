@@ -0,0 +1,698 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+// Thresholds for the facts 'codeeagle suggest' collects.
+const (
+	// suggestMinFanIn and suggestMinMethods make a type a god class
+	// candidate: called from that many other files, or that large.
+	suggestMinFanIn   = 5
+	suggestMinMethods = 15
+	// suggestMinCallers is how many files must make the same API call for
+	// it to count as a duplicated client.
+	suggestMinCallers = 2
+	// suggestSnippetLines bounds each code snippet sent to the LLM.
+	suggestSnippetLines = 12
+)
+
+// Suggestion priorities, most urgent first.
+var suggestPriorities = []string{"high", "medium", "low"}
+
+// suggestFact is a graph-derived finding the suggestions must cite.
+type suggestFact struct {
+	ID        string            `json:"id"`
+	Kind      string            `json:"kind"` // cycle, god_class, duplicated_call or duplicated_client
+	Summary   string            `json:"summary"`
+	Locations []suggestLocation `json:"locations"`
+	// snippets holds the code at the first locations, for the prompt.
+	snippets []string
+}
+
+type suggestLocation struct {
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line,omitempty"`
+	Note     string `json:"note,omitempty"`
+}
+
+// suggestion is one refactoring the LLM proposes.
+type suggestion struct {
+	Title     string   `json:"title"`
+	Priority  string   `json:"priority"`
+	Rationale string   `json:"rationale"`
+	Steps     []string `json:"steps,omitempty"`
+	Facts     []string `json:"facts"`
+}
+
+// suggestReport is the result of 'codeeagle suggest'.
+type suggestReport struct {
+	Scope       string        `json:"scope"`
+	Kind        string        `json:"kind"` // package or service
+	Facts       []suggestFact `json:"facts"`
+	Suggestions []suggestion  `json:"suggestions,omitempty"`
+}
+
+// suggestScope selects the nodes of a package (a directory and the ones
+// below it) or of a service.
+type suggestScope struct {
+	name    string
+	kind    string
+	service string // service group, for services
+}
+
+func (s suggestScope) contains(filePath string) bool {
+	if filePath == "" {
+		return false
+	}
+	if s.kind == "service" {
+		return linker.ServiceGroup(filePath) == s.service
+	}
+	return s.name == "." || path.Dir(filePath) == s.name || strings.HasPrefix(filePath, s.name+"/")
+}
+
+func newSuggestCmd() *cobra.Command {
+	var (
+		limit     int
+		factsOnly bool
+		jsonOut   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "suggest <package|service>",
+		Short: "Prioritized, cited refactoring suggestions for a package or service",
+		Long: `Collect refactoring candidates from the graph and ask the LLM to turn them
+into prioritized refactoring suggestions.
+
+The argument is a service name or a package directory (e.g. internal/store);
+a directory includes the packages below it. The graph supplies the facts:
+
+  - dependency cycles between the scope's packages and others
+  - god classes: types called from many other files, or with many methods
+  - duplicated clients: the same API call made from several files, and
+    client types declared in several packages
+
+Each fact is sent with its code, and every suggestion cites the facts it
+addresses. Use --facts to list the facts without calling the LLM.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			report, err := buildSuggestFacts(ctx(cmd), store, args[0], limit)
+			if err != nil {
+				return err
+			}
+
+			if !factsOnly && len(report.Facts) > 0 {
+				client, err := createLLMClient(cfg)
+				if err != nil {
+					return err
+				}
+				defer client.Close()
+				if report.Suggestions, err = requestSuggestions(ctx(cmd), client, report); err != nil {
+					return err
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			writeSuggestReport(out, report, factsOnly)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 10, "max facts of each kind")
+	cmd.Flags().BoolVar(&factsOnly, "facts", false, "list the graph facts without asking the LLM")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+	return cmd
+}
+
+// resolveSuggestScope matches arg to a service name, else to a package
+// directory with code in the graph.
+func resolveSuggestScope(ctx context.Context, store graph.Store, arg string) (suggestScope, error) {
+	services, err := serviceNamesByGroup(ctx, store)
+	if err != nil {
+		return suggestScope{}, err
+	}
+	for group, name := range services {
+		if name == arg {
+			return suggestScope{name: name, kind: "service", service: group}, nil
+		}
+	}
+	dir := path.Clean(strings.TrimSuffix(filepath.ToSlash(arg), "/"))
+	return suggestScope{name: dir, kind: "package"}, nil
+}
+
+// buildSuggestFacts collects the refactoring facts of the package or
+// service arg, at most limit of each kind, with the code of each read
+// through the store when it is a graph.SourceStore.
+func buildSuggestFacts(ctx context.Context, store graph.Store, arg string, limit int) (*suggestReport, error) {
+	scope, err := resolveSuggestScope(ctx, store, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*graph.Node) // member ID -> node, test code left out
+	inScope := 0
+	for _, typ := range archMemberTypes {
+		found, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+		}
+		for _, n := range found {
+			lang := n.Language
+			if lang == "" {
+				lang = inferLangFromPath(n.FilePath)
+			}
+			if n.FilePath == "" || isTestFileByPath(n.FilePath, lang) {
+				continue
+			}
+			nodes[n.ID] = n
+			if scope.contains(n.FilePath) {
+				inScope++
+			}
+		}
+	}
+	if inScope == 0 {
+		return nil, fmt.Errorf("no service or package named %q with code in the graph", arg)
+	}
+
+	var coupling []*graph.Edge
+	for _, et := range archCouplingEdges {
+		edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: et})
+		if err != nil {
+			return nil, fmt.Errorf("query %s edges: %w", et, err)
+		}
+		coupling = append(coupling, edges...)
+	}
+
+	report := &suggestReport{Scope: scope.name, Kind: scope.kind, Facts: []suggestFact{}}
+	report.Facts = append(report.Facts, suggestCycles(scope, nodes, coupling, limit)...)
+	report.Facts = append(report.Facts, suggestGodClasses(scope, nodes, coupling, limit)...)
+	dups, err := suggestDuplicatedClients(ctx, store, scope, nodes, limit)
+	if err != nil {
+		return nil, err
+	}
+	report.Facts = append(report.Facts, dups...)
+
+	for i := range report.Facts {
+		f := &report.Facts[i]
+		f.ID = fmt.Sprintf("F%d", i+1)
+		for _, loc := range f.Locations[:min(2, len(f.Locations))] {
+			if s := readSnippet(ctx, store, loc.FilePath, loc.Line); s != "" {
+				f.snippets = append(f.snippets, fmt.Sprintf("%s:%d\n%s", loc.FilePath, loc.Line, s))
+			}
+		}
+	}
+	return report, nil
+}
+
+// suggestCycles finds the package dependency cycles through the scope's
+// packages, each with the edge behind every hop.
+func suggestCycles(scope suggestScope, nodes map[string]*graph.Node, edges []*graph.Edge, limit int) []suggestFact {
+	deps := make(map[string]map[string]*graph.Edge) // package -> package -> first edge
+	for _, e := range edges {
+		from, to := nodes[e.SourceID], nodes[e.TargetID]
+		if from == nil || to == nil {
+			continue
+		}
+		a, b := path.Dir(from.FilePath), path.Dir(to.FilePath)
+		if a == b {
+			continue
+		}
+		if deps[a] == nil {
+			deps[a] = make(map[string]*graph.Edge)
+		}
+		if _, ok := deps[a][b]; !ok {
+			deps[a][b] = e
+		}
+	}
+
+	var facts []suggestFact
+	seen := make(map[string]bool) // packages already on a reported cycle
+	for _, component := range stronglyConnected(deps) {
+		if len(component) < 2 || len(facts) >= limit {
+			continue
+		}
+		start := ""
+		for _, p := range component {
+			if scope.contains(p+"/") && (start == "" || p < start) {
+				start = p
+			}
+		}
+		if start == "" || seen[start] {
+			continue
+		}
+		cycle := shortestCycle(deps, component, start)
+		if cycle == nil {
+			continue
+		}
+		fact := suggestFact{Kind: "cycle", Summary: "Dependency cycle: " + strings.Join(append(cycle, cycle[0]), " -> ")}
+		for i, p := range cycle {
+			seen[p] = true
+			next := cycle[(i+1)%len(cycle)]
+			e := deps[p][next]
+			src := nodes[e.SourceID]
+			fact.Locations = append(fact.Locations, suggestLocation{
+				FilePath: src.FilePath,
+				Line:     src.Line,
+				Note:     fmt.Sprintf("%s %s %s", src.Name, e.Type, nodes[e.TargetID].Name),
+			})
+		}
+		facts = append(facts, fact)
+	}
+	return facts
+}
+
+// stronglyConnected returns the strongly connected components of a
+// package graph (Tarjan's algorithm), each sorted, in a stable order.
+func stronglyConnected(deps map[string]map[string]*graph.Edge) [][]string {
+	var names []string
+	for p := range deps {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+	var visit func(p string)
+	visit = func(p string) {
+		index[p] = len(index)
+		low[p] = index[p]
+		stack = append(stack, p)
+		onStack[p] = true
+		var next []string
+		for q := range deps[p] {
+			next = append(next, q)
+		}
+		sort.Strings(next)
+		for _, q := range next {
+			if _, ok := index[q]; !ok {
+				visit(q)
+				low[p] = min(low[p], low[q])
+			} else if onStack[q] {
+				low[p] = min(low[p], index[q])
+			}
+		}
+		if low[p] != index[p] {
+			return
+		}
+		var component []string
+		for {
+			q := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[q] = false
+			component = append(component, q)
+			if q == p {
+				break
+			}
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+	for _, p := range names {
+		if _, ok := index[p]; !ok {
+			visit(p)
+		}
+	}
+	return components
+}
+
+// shortestCycle returns the shortest cycle from start back to it through
+// the packages of component, without repeating start.
+func shortestCycle(deps map[string]map[string]*graph.Edge, component []string, start string) []string {
+	member := make(map[string]bool, len(component))
+	for _, p := range component {
+		member[p] = true
+	}
+	prev := map[string]string{start: ""}
+	queue := []string{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		var next []string
+		for q := range deps[p] {
+			next = append(next, q)
+		}
+		sort.Strings(next)
+		for _, q := range next {
+			if q == start {
+				var cycle []string
+				for at := p; at != ""; at = prev[at] {
+					cycle = append([]string{at}, cycle...)
+				}
+				return cycle
+			}
+			if _, ok := prev[q]; !ok && member[q] {
+				prev[q] = p
+				queue = append(queue, q)
+			}
+		}
+	}
+	return nil
+}
+
+// suggestGodClasses finds the scope's classes and structs called from many
+// other files or carrying many methods, by fan-in.
+func suggestGodClasses(scope suggestScope, nodes map[string]*graph.Node, edges []*graph.Edge, limit int) []suggestFact {
+	types := make(map[string]*graph.Node) // package + "\x00" + name -> type
+	for _, n := range nodes {
+		if (n.Type == graph.NodeStruct || n.Type == graph.NodeClass) && scope.contains(n.FilePath) {
+			types[path.Dir(n.FilePath)+"\x00"+n.Name] = n
+		}
+	}
+	owner := make(map[string]*graph.Node) // method ID -> type
+	methods := make(map[string]int)
+	for _, n := range nodes {
+		if n.Type != graph.NodeMethod {
+			continue
+		}
+		recv := n.Properties["receiver"]
+		if recv == "" {
+			recv = n.Properties["class"]
+		}
+		if t := types[path.Dir(n.FilePath)+"\x00"+strings.TrimPrefix(recv, "*")]; t != nil {
+			owner[n.ID] = t
+			methods[t.ID]++
+		}
+	}
+	callers := make(map[string]map[string]bool) // type ID -> calling files
+	for _, e := range edges {
+		t, caller := owner[e.TargetID], nodes[e.SourceID]
+		if e.Type != graph.EdgeCalls || t == nil || caller == nil || caller.FilePath == t.FilePath {
+			continue
+		}
+		if callers[t.ID] == nil {
+			callers[t.ID] = make(map[string]bool)
+		}
+		callers[t.ID][caller.FilePath] = true
+	}
+
+	var candidates []*graph.Node
+	for _, t := range types {
+		if len(callers[t.ID]) >= suggestMinFanIn || methods[t.ID] >= suggestMinMethods {
+			candidates = append(candidates, t)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if len(callers[a.ID]) != len(callers[b.ID]) {
+			return len(callers[a.ID]) > len(callers[b.ID])
+		}
+		if methods[a.ID] != methods[b.ID] {
+			return methods[a.ID] > methods[b.ID]
+		}
+		return a.ID < b.ID
+	})
+
+	var facts []suggestFact
+	for _, t := range candidates[:min(limit, len(candidates))] {
+		fact := suggestFact{
+			Kind:      "god_class",
+			Summary:   fmt.Sprintf("God class candidate %s: %d methods, called from %d other files", t.Name, methods[t.ID], len(callers[t.ID])),
+			Locations: []suggestLocation{{FilePath: t.FilePath, Line: t.Line, Note: "declaration"}},
+		}
+		files := sortedKeys(callers[t.ID])
+		for _, f := range files[:min(3, len(files))] {
+			fact.Locations = append(fact.Locations, suggestLocation{FilePath: f, Note: "caller"})
+		}
+		facts = append(facts, fact)
+	}
+	return facts
+}
+
+// suggestDuplicatedClients finds the API calls the scope makes from
+// several files, and client types declared in several packages one of
+// which is in scope.
+func suggestDuplicatedClients(ctx context.Context, store graph.Store, scope suggestScope, nodes map[string]*graph.Node, limit int) ([]suggestFact, error) {
+	calls, err := store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Properties: map[string]string{"kind": "api_call"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query API calls: %w", err)
+	}
+	byCall := make(map[string][]*graph.Node) // call name -> call sites
+	for _, c := range calls {
+		if scope.contains(c.FilePath) && !isTestFileByPath(c.FilePath, inferLangFromPath(c.FilePath)) {
+			byCall[c.Name] = append(byCall[c.Name], c)
+		}
+	}
+	var facts []suggestFact
+	for _, name := range sortedKeys(byCall) {
+		sites := byCall[name]
+		files := make(map[string]bool)
+		for _, s := range sites {
+			files[s.FilePath] = true
+		}
+		if len(files) < suggestMinCallers {
+			continue
+		}
+		sort.Slice(sites, func(i, j int) bool {
+			if sites[i].FilePath != sites[j].FilePath {
+				return sites[i].FilePath < sites[j].FilePath
+			}
+			return sites[i].Line < sites[j].Line
+		})
+		fact := suggestFact{Kind: "duplicated_call", Summary: fmt.Sprintf("API call %s is made from %d files", name, len(files))}
+		for _, s := range sites {
+			fact.Locations = append(fact.Locations, suggestLocation{FilePath: s.FilePath, Line: s.Line})
+		}
+		facts = append(facts, fact)
+	}
+
+	clients := make(map[string][]*graph.Node) // type name -> declarations
+	for _, n := range nodes {
+		if (n.Type == graph.NodeStruct || n.Type == graph.NodeClass) && strings.HasSuffix(n.Name, "Client") {
+			clients[n.Name] = append(clients[n.Name], n)
+		}
+	}
+	for _, name := range sortedKeys(clients) {
+		decls := clients[name]
+		packages := make(map[string]bool)
+		touches := false
+		for _, d := range decls {
+			packages[path.Dir(d.FilePath)] = true
+			touches = touches || scope.contains(d.FilePath)
+		}
+		if len(packages) < 2 || !touches {
+			continue
+		}
+		sort.Slice(decls, func(i, j int) bool { return decls[i].FilePath < decls[j].FilePath })
+		fact := suggestFact{Kind: "duplicated_client", Summary: fmt.Sprintf("Client type %s is declared in %d packages", name, len(packages))}
+		for _, d := range decls {
+			fact.Locations = append(fact.Locations, suggestLocation{FilePath: d.FilePath, Line: d.Line})
+		}
+		facts = append(facts, fact)
+	}
+	return facts[:min(limit, len(facts))], nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readSnippet returns up to suggestSnippetLines numbered lines of a file
+// from just before line. It returns "" without a line or when the store
+// cannot read the file.
+func readSnippet(ctx context.Context, store graph.Store, filePath string, line int) string {
+	src, ok := store.(graph.SourceStore)
+	if !ok || line <= 0 {
+		return ""
+	}
+	start := max(line-2, 1)
+	code, err := src.GetSourceLines(ctx, filePath, start, start+suggestSnippetLines-1)
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for i, text := range strings.Split(strings.TrimSuffix(code, "\n"), "\n") {
+		fmt.Fprintf(&b, "%5d  %s\n", start+i, text)
+	}
+	return b.String()
+}
+
+const suggestSystemPrompt = `You are a senior software engineer reviewing a codebase for refactoring opportunities.
+You are given facts derived from the codebase's knowledge graph, each with an ID and code.
+Propose concrete refactorings that address these facts, most valuable first.
+Every suggestion must cite the IDs of the facts it addresses; do not invent facts, files or symbols.
+Priority is high for changes that remove dependency cycles or reduce wide coupling, medium for
+consolidating duplicated code, low for local cleanups.`
+
+// suggestSchema is the structured output of the suggestion request.
+var suggestSchema = llm.Schema{
+	Name:        "refactoring_suggestions",
+	Description: "Prioritized refactoring suggestions citing the facts they address.",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"suggestions": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"title":     map[string]any{"type": "string"},
+						"priority":  map[string]any{"type": "string", "enum": suggestPriorities},
+						"rationale": map[string]any{"type": "string"},
+						"steps":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+						"facts":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					},
+					"required": []string{"title", "priority", "rationale", "facts"},
+				},
+			},
+		},
+		"required": []string{"suggestions"},
+	},
+}
+
+// requestSuggestions asks the LLM for suggestions addressing the report's
+// facts. Citations of unknown facts are dropped, and so are suggestions
+// left citing none; the rest are sorted by priority.
+func requestSuggestions(ctx context.Context, client llm.Client, report *suggestReport) ([]suggestion, error) {
+	resp, err := llm.ChatJSON(ctx, client, suggestSystemPrompt,
+		[]llm.Message{{Role: llm.RoleUser, Content: suggestPrompt(report)}},
+		suggestSchema, llm.DefaultJSONRetries)
+	if err != nil {
+		return nil, fmt.Errorf("request suggestions: %w", err)
+	}
+	var out struct {
+		Suggestions []suggestion `json:"suggestions"`
+	}
+	if err := json.Unmarshal([]byte(resp.Content), &out); err != nil {
+		return nil, fmt.Errorf("parse suggestions: %w", err)
+	}
+
+	known := make(map[string]bool, len(report.Facts))
+	for _, f := range report.Facts {
+		known[f.ID] = true
+	}
+	rank := make(map[string]int, len(suggestPriorities))
+	for i, p := range suggestPriorities {
+		rank[p] = i
+	}
+	var suggestions []suggestion
+	for _, s := range out.Suggestions {
+		var facts []string
+		for _, id := range s.Facts {
+			if id = strings.TrimSpace(id); known[id] && !slices.Contains(facts, id) {
+				facts = append(facts, id)
+			}
+		}
+		if len(facts) == 0 {
+			continue
+		}
+		s.Facts = facts
+		if _, ok := rank[s.Priority]; !ok {
+			s.Priority = "low"
+		}
+		suggestions = append(suggestions, s)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return rank[suggestions[i].Priority] < rank[suggestions[j].Priority]
+	})
+	return suggestions, nil
+}
+
+// suggestPrompt lists the report's facts with their locations and code.
+func suggestPrompt(report *suggestReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Suggest refactorings for the %s %s.\n\nFacts:\n", report.Kind, report.Scope)
+	for _, f := range report.Facts {
+		fmt.Fprintf(&b, "\n[%s] %s (%s)\n", f.ID, f.Summary, f.Kind)
+		for _, loc := range f.Locations {
+			fmt.Fprintf(&b, "  - %s", formatSuggestLocation(loc))
+			if loc.Note != "" {
+				fmt.Fprintf(&b, " (%s)", loc.Note)
+			}
+			b.WriteString("\n")
+		}
+		for _, s := range f.snippets {
+			fmt.Fprintf(&b, "```\n%s```\n", s)
+		}
+	}
+	return b.String()
+}
+
+func formatSuggestLocation(loc suggestLocation) string {
+	if loc.Line > 0 {
+		return fmt.Sprintf("%s:%d", loc.FilePath, loc.Line)
+	}
+	return loc.FilePath
+}
+
+func writeSuggestReport(w io.Writer, r *suggestReport, factsOnly bool) {
+	if len(r.Facts) == 0 {
+		fmt.Fprintf(w, "No cycles, god classes or duplicated clients found in %s %s.\n", r.Kind, r.Scope)
+		return
+	}
+
+	facts := make(map[string]suggestFact, len(r.Facts))
+	for _, f := range r.Facts {
+		facts[f.ID] = f
+	}
+	if !factsOnly {
+		fmt.Fprintf(w, "Refactoring suggestions for %s %s:\n", r.Kind, r.Scope)
+		if len(r.Suggestions) == 0 {
+			fmt.Fprintln(w, "  The LLM made no suggestions citing the facts below.")
+		}
+		for i, s := range r.Suggestions {
+			fmt.Fprintf(w, "\n%d. [%s] %s\n", i+1, s.Priority, s.Title)
+			fmt.Fprintf(w, "   %s\n", s.Rationale)
+			for _, step := range s.Steps {
+				fmt.Fprintf(w, "   - %s\n", step)
+			}
+			for _, id := range s.Facts {
+				f := facts[id]
+				fmt.Fprintf(w, "   [%s] %s, %s\n", id, f.Summary, formatSuggestLocation(f.Locations[0]))
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Facts for %s %s:\n", r.Kind, r.Scope)
+	for _, f := range r.Facts {
+		fmt.Fprintf(w, "  [%s] %s\n", f.ID, f.Summary)
+		for _, loc := range f.Locations {
+			fmt.Fprintf(w, "       %s", formatSuggestLocation(loc))
+			if loc.Note != "" {
+				fmt.Fprintf(w, "  %s", loc.Note)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+type stubSuggestClient struct {
+	prompt string
+	reply  string
+}
+
+func (c *stubSuggestClient) Chat(_ context.Context, _ string, msgs []llm.Message) (*llm.Response, error) {
+	c.prompt = msgs[0].Content
+	return &llm.Response{Content: c.reply}, nil
+}
+func (c *stubSuggestClient) Model() string    { return "stub" }
+func (c *stubSuggestClient) Provider() string { return "stub" }
+func (c *stubSuggestClient) Close() error     { return nil }
+
+func TestBuildSuggestFacts(t *testing.T) {
+	store := newTestGraphStore(t)
+	ctx := context.Background()
+
+	node := func(id string, typ graph.NodeType, file string, props ...string) *graph.Node {
+		n := &graph.Node{ID: id, Type: typ, Name: id, FilePath: file, Line: 1, Language: "go", Properties: map[string]string{}}
+		for i := 0; i+1 < len(props); i += 2 {
+			n.Properties[props[i]] = props[i+1]
+		}
+		return n
+	}
+	calls := func(from, to string) *graph.Edge {
+		return &graph.Edge{ID: graph.NewEdgeID(graph.EdgeCalls, from, to), Type: graph.EdgeCalls, SourceID: from, TargetID: to}
+	}
+	// A second PaymentsClient, in another package.
+	billingClient := node("billing.PaymentsClient", graph.NodeStruct, "app/billing/client.go")
+	billingClient.Name = "PaymentsClient"
+	addTestNodes(t, store,
+		node("Store", graph.NodeStruct, "app/store/store.go"),
+		node("Get", graph.NodeMethod, "app/store/store.go", "receiver", "*Store"),
+		node("Notify", graph.NodeFunction, "app/store/notify.go"),
+		node("Send", graph.NodeFunction, "app/mail/send.go"),
+		node("PaymentsClient", graph.NodeStruct, "app/store/client.go"),
+		billingClient,
+		&graph.Node{ID: "call1", Type: graph.NodeDependency, Name: "GET /payments", FilePath: "app/store/a.go", Line: 3, Properties: map[string]string{"kind": "api_call"}},
+		&graph.Node{ID: "call2", Type: graph.NodeDependency, Name: "GET /payments", FilePath: "app/store/b.go", Line: 7, Properties: map[string]string{"kind": "api_call"}},
+		&graph.Node{ID: "call3", Type: graph.NodeDependency, Name: "POST /payments", FilePath: "app/store/b.go", Line: 9, Properties: map[string]string{"kind": "api_call"}},
+	)
+
+	var edges []*graph.Edge
+	// app/store -> app/mail -> app/store.
+	edges = append(edges, calls("Notify", "Send"), calls("Send", "Get"))
+	for i := range suggestMinFanIn {
+		caller := fmt.Sprintf("h%d", i)
+		addTestNodes(t, store, node(caller, graph.NodeFunction, fmt.Sprintf("app/web/h%d.go", i)))
+		edges = append(edges, calls(caller, "Get"))
+	}
+	addTestEdges(t, store, edges...)
+
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "app/store"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "app/store/store.go"), []byte("package store\n\ntype Store struct{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetSourceRoots(repo)
+
+	report, err := buildSuggestFacts(ctx, store, "app/store/", 10)
+	if err != nil {
+		t.Fatalf("buildSuggestFacts: %v", err)
+	}
+	var got []string
+	for _, f := range report.Facts {
+		got = append(got, f.ID+" "+f.Summary)
+	}
+	want := []string{
+		"F1 Dependency cycle: app/store -> app/mail -> app/store",
+		fmt.Sprintf("F2 God class candidate Store: 1 methods, called from %d other files", suggestMinFanIn+1),
+		"F3 API call GET /payments is made from 2 files",
+		"F4 Client type PaymentsClient is declared in 2 packages",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("facts =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if loc := report.Facts[0].Locations[0]; loc.FilePath != "app/store/notify.go" || loc.Note != "Notify Calls Send" {
+		t.Errorf("cycle hop = %+v", loc)
+	}
+	if s := report.Facts[1].snippets; len(s) != 1 || !strings.Contains(s[0], "type Store struct{}") {
+		t.Errorf("god class snippets = %q", s)
+	}
+
+	if _, err := buildSuggestFacts(ctx, store, "nowhere", 10); err == nil {
+		t.Error("buildSuggestFacts(nowhere) succeeded, want an error")
+	}
+
+	client := &stubSuggestClient{reply: `{"suggestions": [
+		{"title": "Merge payment clients", "priority": "medium", "rationale": "two copies", "facts": ["F3", "F4", "F9"]},
+		{"title": "Invented", "priority": "high", "rationale": "no evidence", "facts": ["F7"]},
+		{"title": "Break the cycle", "priority": "high", "rationale": "mail calls back into store", "facts": ["F1", "F1"]}
+	]}`}
+	suggestions, err := requestSuggestions(ctx, client, report)
+	if err != nil {
+		t.Fatalf("requestSuggestions: %v", err)
+	}
+	if !strings.Contains(client.prompt, "[F2] God class candidate Store") || !strings.Contains(client.prompt, "type Store struct{}") {
+		t.Errorf("prompt lacks facts or code:\n%s", client.prompt)
+	}
+	wantSuggestions := []suggestion{
+		{Title: "Break the cycle", Priority: "high", Rationale: "mail calls back into store", Facts: []string{"F1"}},
+		{Title: "Merge payment clients", Priority: "medium", Rationale: "two copies", Facts: []string{"F3", "F4"}},
+	}
+	if !reflect.DeepEqual(suggestions, wantSuggestions) {
+		t.Errorf("suggestions = %+v, want %+v", suggestions, wantSuggestions)
+	}
+
+	report.Suggestions = suggestions
+	var out bytes.Buffer
+	writeSuggestReport(&out, report, false)
+	if !strings.Contains(out.String(), "1. [high] Break the cycle") || !strings.Contains(out.String(), "[F1] Dependency cycle: app/store -> app/mail -> app/store, app/store/notify.go:1") {
+		t.Errorf("report =\n%s", out.String())
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/imyousuf/CodeEagle/internal/config"
 	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
 	"github.com/imyousuf/CodeEagle/internal/indexer"
-	"github.com/imyousuf/CodeEagle/internal/linker"
 	"github.com/imyousuf/CodeEagle/pkg/llm"
 
 	// Register LLM and embedding providers so their init() functions run.
@@ -28,6 +27,7 @@ import (
 	makefileparser "github.com/imyousuf/CodeEagle/internal/parser/makefile"
 	"github.com/imyousuf/CodeEagle/internal/parser/manifest"
 	"github.com/imyousuf/CodeEagle/internal/parser/markdown"
+	nginxparser "github.com/imyousuf/CodeEagle/internal/parser/nginx"
 	"github.com/imyousuf/CodeEagle/internal/parser/python"
 	rubyparser "github.com/imyousuf/CodeEagle/internal/parser/ruby"
 	rustparser "github.com/imyousuf/CodeEagle/internal/parser/rust"
@@ -43,6 +43,7 @@ func newSyncCmd() *cobra.Command {
 	var exportGraph bool
 	var importGraph bool
 	var branch string
+	var logFormat string
 
 	cmd := &cobra.Command{
 		Use:   "sync",
@@ -64,7 +65,13 @@ target branch for import.`,
 				return fmt.Errorf("invalid config: %w", err)
 			}
 
-			out := cmd.OutOrStdout()
+			ro, err := newRunOutput(cmd, logFormat)
+			if err != nil {
+				return err
+			}
+			defer ro.Flush()
+			out := ro.Out()
+			errOut := ro.Err()
 
 			if exportGraph && importGraph {
 				return fmt.Errorf("cannot use --export and --import together")
@@ -72,7 +79,7 @@ target branch for import.`,
 
 			// Handle export/import.
 			if exportGraph || importGraph {
-				return handleExportImport(cfg, exportGraph, branch, cmd.OutOrStdout())
+				return handleExportImport(cfg, exportGraph, branch, out)
 			}
 
 			// Normal sync.
@@ -82,9 +89,7 @@ target branch for import.`,
 			}
 			defer store.Close()
 
-			logFn := func(format string, args ...any) {
-				fmt.Fprintf(out, format+"\n", args...)
-			}
+			logFn := ro.Logf
 
 			// Auto-import if .CodeEagle.conf is available.
 			if cfg.ProjectConf != nil && cfg.ProjectConfDir != "" {
@@ -93,7 +98,7 @@ target branch for import.`,
 				state, err := indexer.LoadSyncState(statePath)
 				if err == nil {
 					if err := indexer.AutoImportIfNeeded(ctx(cmd), store, exportFilePath, state, logFn); err != nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "Warning: auto-import failed: %v\n", err)
+						fmt.Fprintf(errOut, "Warning: auto-import failed: %v\n", err)
 					} else {
 						// Save state if auto-import updated LastImportTime.
 						_ = state.Save(statePath)
@@ -114,17 +119,21 @@ target branch for import.`,
 			registry.Register(shell.NewParser())
 			registry.Register(terraform.NewParser())
 			registry.Register(yamlparser.NewParser())
+			registry.Register(nginxparser.NewParser())
 			registry.Register(rustparser.NewParser())
 			registry.Register(rubyparser.NewParser())
 			registry.Register(manifest.NewParser())
 			registry.Register(csharpparser.NewParser())
+			if err := registerExternalParsers(registry, cfg); err != nil {
+				return err
+			}
 
 			// Detect docs LLM provider for topic extraction.
 			var docsProvider docs.Provider
 			var docsCache *docs.Cache
 			dp, dpErr := docs.DetectProvider(cfg)
 			if dpErr != nil {
-				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: docs provider: %v\n", dpErr)
+				fmt.Fprintf(errOut, "Warning: docs provider: %v\n", dpErr)
 			}
 			if dp != nil {
 				docsProvider = dp
@@ -133,7 +142,7 @@ target branch for import.`,
 				cachePath := cfg.ConfigDir + "/docs.db"
 				dc, dcErr := docs.OpenCache(cachePath)
 				if dcErr != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: docs cache: %v\n", dcErr)
+					fmt.Fprintf(errOut, "Warning: docs cache: %v\n", dcErr)
 				} else {
 					docsCache = dc
 					defer docsCache.Close()
@@ -143,6 +152,11 @@ target branch for import.`,
 			// Register generic fallback parser for non-code files.
 			registry.SetFallback(genericparser.NewGenericParser(cfg.Docs.ExcludeExtensions, docsProvider, docsCache, cfg.Docs.MaxImageRes))
 			registry.SetExcludeExtensions(cfg.Docs.ExcludeExtensions)
+			registry.SetTestPatterns(&parser.TestPatterns{
+				FileGlobs:        cfg.Tests.FilePatterns,
+				FunctionPatterns: cfg.Tests.FunctionPatterns,
+				Annotations:      cfg.Tests.Annotations,
+			})
 
 			// Build watcher config for the matcher.
 			var paths []string
@@ -159,7 +173,7 @@ target branch for import.`,
 			if cfg.Agents.AutoSummarize || cfg.Agents.AutoLink {
 				c, err := createLLMClient(cfg)
 				if err != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: LLM client creation failed: %v\n", err)
+					fmt.Fprintf(errOut, "Warning: LLM client creation failed: %v\n", err)
 				} else {
 					llmClient = c
 					defer llmClient.Close()
@@ -176,8 +190,20 @@ target branch for import.`,
 				Logger:         logFn,
 				LLMClient:      llmClient,
 				AutoSummarize:  cfg.Agents.AutoSummarize,
+				ScanSecrets:    cfg.Secrets.Scan,
+				FileFilter:     newFileFilter(cfg),
+				BatchSize:      cfg.Index.BatchSize,
+				TrackRenames:   cfg.Index.TrackRenames,
+				SecretsExclude: cfg.Secrets.Exclude,
+				Progress:       ro.IndexProgress,
 			})
 
+			// Capture the graph before indexing to notify subscribers of changes.
+			changes, err := newChangeNotifier(ctx(cmd), cfg, store, currentBranch, logFn)
+			if err != nil {
+				return err
+			}
+
 			mode := "incremental"
 			if full {
 				mode = "full"
@@ -201,10 +227,16 @@ target branch for import.`,
 				if cfg.Agents.AutoLink {
 					linkerLLM = llmClient
 				}
-				lnk := linker.NewLinker(store, linkerLLM, logFn, verbose)
+				lnk := newLinker(cfg, store, linkerLLM, logFn, verbose)
+				lnk.SetProgress(ro.LinkProgress)
 				if err := lnk.RunAll(ctx(cmd)); err != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: linker failed: %v\n", err)
+					fmt.Fprintf(errOut, "Warning: linker failed: %v\n", err)
 				}
+
+				// Track unresolved API calls across runs.
+				updateUnresolvedBacklog(cmd, cfg, store, logFn)
+
+				changes.check(ctx(cmd), store)
 			}
 
 			// Run vector indexing if an embedding provider is available.
@@ -213,12 +245,12 @@ target branch for import.`,
 			}
 			vs, vecErr := openVectorStore(cfg, store, currentBranch, logFn)
 			if vecErr != nil {
-				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: vector store: %v\n", vecErr)
+				fmt.Fprintf(errOut, "Warning: vector store: %v\n", vecErr)
 			}
 			if vs != nil {
 				defer vs.Close()
 				if err := syncVectorIndex(vs, cfg, full, logFn); err != nil {
-					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: vector indexing failed: %v\n", err)
+					fmt.Fprintf(errOut, "Warning: vector indexing failed: %v\n", err)
 				}
 			}
 
@@ -228,7 +260,7 @@ target branch for import.`,
 				state, err := indexer.LoadSyncState(statePath)
 				if err == nil {
 					if err := indexer.CleanupStaleBranches(ctx(cmd), store, cfg.Repositories[0].Path, state, logFn); err != nil {
-						fmt.Fprintf(cmd.ErrOrStderr(), "Warning: branch cleanup failed: %v\n", err)
+						fmt.Fprintf(errOut, "Warning: branch cleanup failed: %v\n", err)
 					}
 					_ = state.Save(statePath)
 				}
@@ -238,15 +270,25 @@ target branch for import.`,
 			stats := idx.Stats()
 			fmt.Fprintf(out, "Sync complete: %d files indexed, %d nodes, %d edges\n",
 				stats.FilesIndexed, stats.NodesTotal, stats.EdgesTotal)
+			if stats.FilesPartial > 0 {
+				fmt.Fprintf(out, "  Partially parsed: %d files with syntax errors (see 'codeeagle findings --category parse_error')\n", stats.FilesPartial)
+			}
+			if stats.FilesSkipped > 0 {
+				fmt.Fprintf(out, "  Skipped: %d (index size, vendored, generated, or language rules)\n", stats.FilesSkipped)
+			}
 			if len(stats.Errors) > 0 {
 				fmt.Fprintf(out, "  Errors: %d\n", len(stats.Errors))
 			}
+			ro.Event("sync complete",
+				"files", stats.FilesIndexed, "skipped", stats.FilesSkipped, "partial", stats.FilesPartial,
+				"nodes", stats.NodesTotal, "edges", stats.EdgesTotal, "errors", len(stats.Errors))
 
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&full, "full", false, "full re-index of all files")
+	addLogFormatFlag(cmd, &logFormat)
 	cmd.Flags().BoolVar(&exportGraph, "export", false, "export current branch graph to a file")
 	cmd.Flags().BoolVar(&importGraph, "import", false, "import a graph export file")
 	cmd.Flags().StringVar(&branch, "branch", "", "target branch for import (auto-detected if empty)")
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/testresults"
+)
+
+func newTestResultsCmd() *cobra.Command {
+	var (
+		format  string
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test-results <report>...",
+		Short: "Ingest test results to track test pass rates and durations",
+		Long: `Ingest test result reports and record run history on TestFunction nodes.
+
+Supported formats (auto-detected by default):
+  junit   JUnit XML (Maven Surefire, Gradle, pytest --junitxml, jest-junit, ...)
+  gotest  go test -json output
+
+Each ingestion adds to the history of the matching tests, stored as node
+properties: test_runs, test_passes, test_failures, test_pass_rate,
+test_avg_ms, and test_flaky (set when a test has both passed and failed).
+Skipped tests are ignored. Impact analysis uses this history to order the
+suggested test plan, fastest and most reliable tests first. For example:
+
+  go test -json ./... > results.json
+  codeeagle test-results results.json
+
+Re-indexing a test file resets the history of its tests.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			out := cmd.OutOrStdout()
+			results := make(map[string]*testresults.Result, len(args))
+			for _, path := range args {
+				rep, err := testresults.ParseFile(path, testresults.Format(format))
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				res, err := testresults.Ingest(ctx(cmd), store, rep)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				results[path] = res
+
+				if jsonOut {
+					continue
+				}
+				fmt.Fprintf(out, "%s (%s): %d run(s), %d test(s) updated\n",
+					path, rep.Format, res.Runs, res.Tests)
+				if len(res.UnresolvedTests) > 0 {
+					fmt.Fprintf(out, "  %d test(s) not in the graph\n", len(res.UnresolvedTests))
+					if verbose {
+						for _, t := range res.UnresolvedTests {
+							fmt.Fprintf(out, "    %s\n", t)
+						}
+					}
+				}
+			}
+
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(results)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "auto", "report format: auto, junit, gotest")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/traces"
+)
+
+func newTracesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "traces",
+		Short: "Verify the graph against runtime traces",
+	}
+	cmd.AddCommand(newTracesIngestCmd())
+	return cmd
+}
+
+func newTracesIngestCmd() *cobra.Command {
+	var (
+		serviceMap map[string]string
+		jsonOut    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ingest <export>...",
+		Short: "Ingest OTLP trace exports and compare observed with inferred dependencies",
+		Long: `Read OpenTelemetry trace exports in OTLP JSON (a TracesData document, or
+one per line as written by the collector's file exporter) and record what
+ran in the graph, marking edges observed=true with an observed_count:
+
+  Calls      between functions mapped through the code.function,
+             code.namespace and code.filepath span attributes, and from
+             endpoints to their handlers
+  Consumes   from the calling function (or service) to the endpoint a
+             server span handled, matched by http.route and method
+  DependsOn  between services, from cross-service parent/child spans and
+             peer.service on client spans
+
+Edges already in the graph are marked observed rather than duplicated. The
+report then lists service dependencies that were observed but not inferred
+statically (dynamic URLs, service discovery), and static dependencies of
+traced services that were never observed.
+
+Spans name their service through the service.name resource attribute,
+matched to Service nodes ignoring case, punctuation and suffixes such as
+-service or -api. Use --service-map for other names:
+
+  codeeagle traces ingest traces.json --service-map checkout-prod=checkout
+
+Re-run after 'codeeagle sync', which rebuilds linker edges.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+
+			var spans []*traces.Span
+			for _, path := range args {
+				s, err := traces.ParseFile(path)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				spans = append(spans, s...)
+			}
+
+			store, _, err := openBranchStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			res, err := traces.Ingest(ctx(cmd), store, spans, traces.Options{ServiceMap: serviceMap})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(res)
+			}
+			writeTraceResult(out, res)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringToStringVar(&serviceMap, "service-map", nil, "map a trace service.name to a graph service name (name=service, repeatable)")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func writeTraceResult(out io.Writer, res *traces.Result) {
+	fmt.Fprintf(out, "Ingested %d span(s) from %d trace(s): %d observed call(s), %d observed endpoint request(s)\n",
+		res.Spans, res.Traces, res.Calls, res.Consumes)
+
+	sections := []struct {
+		title string
+		deps  []traces.Dependency
+	}{
+		{"Confirmed service dependencies", res.Confirmed},
+		{"Observed but not inferred statically", res.ObservedOnly},
+		{"Inferred statically but not observed", res.StaticOnly},
+	}
+	for _, sec := range sections {
+		if len(sec.deps) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s (%d):\n", sec.title, len(sec.deps))
+		for _, d := range sec.deps {
+			spans := ""
+			if d.Spans > 0 {
+				spans = fmt.Sprintf("  (%d span(s))", d.Spans)
+			}
+			fmt.Fprintf(out, "  %s -> %s%s\n", d.From, d.To, spans)
+		}
+	}
+	if len(res.UnresolvedServices) > 0 {
+		fmt.Fprintf(out, "\nServices not in the graph (use --service-map): %s\n", strings.Join(res.UnresolvedServices, ", "))
+	}
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// backlogFileName is the unresolved-call backlog file inside the .CodeEagle dir.
+const backlogFileName = "unresolved.json"
+
+// backlogPath returns the backlog file path for the given config.
+func backlogPath(cfg *config.Config) string {
+	if cfg.ConfigDir == "" {
+		return ""
+	}
+	return filepath.Join(cfg.ConfigDir, backlogFileName)
+}
+
+// updateUnresolvedBacklog reconciles the persisted backlog with the graph and saves it.
+func updateUnresolvedBacklog(cmd *cobra.Command, cfg *config.Config, store graph.Store, logFn func(string, ...any)) {
+	path := backlogPath(cfg)
+	if path == "" {
+		return
+	}
+	backlog, err := linker.LoadBacklog(path)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: unresolved backlog: %v\n", err)
+		return
+	}
+	lnk := newLinker(cfg, store, nil, logFn, verbose)
+	if _, err := lnk.UpdateBacklog(ctx(cmd), backlog, time.Now().UTC()); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: unresolved backlog: %v\n", err)
+		return
+	}
+	if err := backlog.Save(path); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: unresolved backlog: %v\n", err)
+	}
+}
+
+func newUnresolvedCmd() *cobra.Command {
+	var (
+		status  string
+		refresh bool
+		jsonOut bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "unresolved",
+		Short: "Show the backlog of API calls that could not be linked to endpoints",
+		Long: `Show the cross-run backlog of unresolved API calls.
+
+Each sync records unresolved api_call nodes in .CodeEagle/unresolved.json with
+the date they were first seen and their resolution status (open, resolved,
+external). The history section shows whether the open count is trending down.
+
+Use --refresh to reconcile the backlog with the current graph before printing.
+Use 'unresolved ignore <key>' to mark a call as intentionally external.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			path := backlogPath(cfg)
+			if path == "" {
+				return fmt.Errorf("no config directory found; run 'codeeagle init' first")
+			}
+
+			if refresh {
+				store, _, err := openBranchStore(cfg)
+				if err != nil {
+					return err
+				}
+				updateUnresolvedBacklog(cmd, cfg, store, nil)
+				store.Close()
+			}
+
+			backlog, err := linker.LoadBacklog(path)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			entries := backlog.Sorted(linker.BacklogStatus(status))
+
+			if jsonOut {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					Entries []*linker.BacklogEntry   `json:"entries"`
+					History []linker.BacklogSnapshot `json:"history,omitempty"`
+				}{entries, backlog.History})
+			}
+
+			printBacklog(out, entries, backlog.History)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&status, "status", string(linker.BacklogOpen), "filter by status: open, resolved, external (empty for all)")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "reconcile the backlog with the current graph first")
+	cmd.Flags().BoolVar(&jsonOut, "json", false, "output as JSON")
+
+	cmd.AddCommand(newUnresolvedSetStatusCmd("ignore", "Mark an unresolved call as intentionally external", linker.BacklogExternal))
+	cmd.AddCommand(newUnresolvedSetStatusCmd("reopen", "Reopen a backlog entry previously marked external", linker.BacklogOpen))
+
+	return cmd
+}
+
+// newUnresolvedSetStatusCmd builds a subcommand that sets the status of one backlog entry.
+func newUnresolvedSetStatusCmd(use, short string, status linker.BacklogStatus) *cobra.Command {
+	var note string
+
+	cmd := &cobra.Command{
+		Use:   use + " <key>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("load config: %w", err)
+			}
+			path := backlogPath(cfg)
+			if path == "" {
+				return fmt.Errorf("no config directory found; run 'codeeagle init' first")
+			}
+
+			backlog, err := linker.LoadBacklog(path)
+			if err != nil {
+				return err
+			}
+			if err := backlog.SetStatus(args[0], status, note, time.Now().UTC()); err != nil {
+				return err
+			}
+			if err := backlog.Save(path); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Marked %s as %s\n", args[0], status)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&note, "note", "", "reason recorded with the status change")
+
+	return cmd
+}
+
+// printBacklog writes the backlog entries and trend history as text.
+func printBacklog(out io.Writer, entries []*linker.BacklogEntry, history []linker.BacklogSnapshot) {
+	if len(entries) == 0 {
+		fmt.Fprintln(out, "No backlog entries.")
+	} else {
+		fmt.Fprintf(out, "%-10s  %-10s  %-8s  %s\n", "First seen", "Status", "Method", "Key")
+		fmt.Fprintf(out, "%-10s  %-10s  %-8s  %s\n", "----------", "----------", "--------", "---")
+		for _, e := range entries {
+			fmt.Fprintf(out, "%-10s  %-10s  %-8s  %s\n",
+				e.FirstSeen.Format("2006-01-02"), e.Status, e.Method, e.Key)
+			if e.Note != "" {
+				fmt.Fprintf(out, "%34s  note: %s\n", "", e.Note)
+			}
+		}
+		fmt.Fprintf(out, "\n%d entries\n", len(entries))
+	}
+
+	if len(history) == 0 {
+		return
+	}
+
+	// Show the most recent runs so the trend is visible.
+	const maxShown = 10
+	start := 0
+	if len(history) > maxShown {
+		start = len(history) - maxShown
+	}
+	fmt.Fprintln(out, "\nTrend (most recent runs):")
+	for _, h := range history[start:] {
+		fmt.Fprintf(out, "  %s  open=%d new=%d resolved=%d external=%d\n",
+			h.Time.Format("2006-01-02 15:04"), h.Open, h.New, h.Resolved, h.External)
+	}
+	if len(history) > 1 {
+		first := history[start]
+		last := history[len(history)-1]
+		fmt.Fprintf(out, "  Open delta over shown runs: %+d\n", last.Open-first.Open)
+	}
+}
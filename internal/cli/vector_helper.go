@@ -40,7 +40,11 @@ func openVectorStore(cfg *config.Config, store *embedded.BranchStore, branch str
 	}
 
 	if logFn != nil {
-		logFn("[vector] Embedding provider: %s/%s (%d-dim)", embedder.Name(), embedder.ModelName(), embedder.Dimensions())
+		if dims := embedder.Dimensions(); dims > 0 {
+			logFn("[vector] Embedding provider: %s/%s (%d-dim)", embedder.Name(), embedder.ModelName(), dims)
+		} else {
+			logFn("[vector] Embedding provider: %s/%s", embedder.Name(), embedder.ModelName())
+		}
 	}
 
 	vs, err := vectorstore.New(
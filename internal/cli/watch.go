@@ -13,7 +13,6 @@ import (
 	"github.com/imyousuf/CodeEagle/internal/docs"
 	"github.com/imyousuf/CodeEagle/internal/gitutil"
 	"github.com/imyousuf/CodeEagle/internal/indexer"
-	"github.com/imyousuf/CodeEagle/internal/linker"
 	"github.com/imyousuf/CodeEagle/internal/parser"
 	csharpparser "github.com/imyousuf/CodeEagle/internal/parser/csharp"
 	genericparser "github.com/imyousuf/CodeEagle/internal/parser/generic"
@@ -24,6 +23,7 @@ import (
 	makefileparser "github.com/imyousuf/CodeEagle/internal/parser/makefile"
 	"github.com/imyousuf/CodeEagle/internal/parser/manifest"
 	"github.com/imyousuf/CodeEagle/internal/parser/markdown"
+	nginxparser "github.com/imyousuf/CodeEagle/internal/parser/nginx"
 	"github.com/imyousuf/CodeEagle/internal/parser/python"
 	rubyparser "github.com/imyousuf/CodeEagle/internal/parser/ruby"
 	rustparser "github.com/imyousuf/CodeEagle/internal/parser/rust"
@@ -101,10 +101,14 @@ func newWatchCmd() *cobra.Command {
 			registry.Register(shell.NewParser())
 			registry.Register(terraform.NewParser())
 			registry.Register(yamlparser.NewParser())
+			registry.Register(nginxparser.NewParser())
 			registry.Register(rustparser.NewParser())
 			registry.Register(rubyparser.NewParser())
 			registry.Register(manifest.NewParser())
 			registry.Register(csharpparser.NewParser())
+			if err := registerExternalParsers(registry, cfg); err != nil {
+				return err
+			}
 
 			// Detect docs LLM provider for topic extraction.
 			var docsProvider docs.Provider
@@ -129,6 +133,11 @@ func newWatchCmd() *cobra.Command {
 			// Register generic fallback parser for non-code files.
 			registry.SetFallback(genericparser.NewGenericParser(cfg.Docs.ExcludeExtensions, docsProvider, docsCache, cfg.Docs.MaxImageRes))
 			registry.SetExcludeExtensions(cfg.Docs.ExcludeExtensions)
+			registry.SetTestPatterns(&parser.TestPatterns{
+				FileGlobs:        cfg.Tests.FilePatterns,
+				FunctionPatterns: cfg.Tests.FunctionPatterns,
+				Annotations:      cfg.Tests.Annotations,
+			})
 
 			// Build watcher config from project config.
 			var paths []string
@@ -157,7 +166,7 @@ func newWatchCmd() *cobra.Command {
 			if cfg.Agents.AutoLink {
 				linkerLLM = llmClient
 			}
-			lnk := linker.NewLinker(store, linkerLLM, logFn, verbose)
+			lnk := newLinker(cfg, store, linkerLLM, logFn, verbose)
 
 			// Open vector store if embedding provider is available.
 			vs, vecErr := openVectorStore(cfg, store, currentBranch, logFn)
@@ -172,11 +181,18 @@ func newWatchCmd() *cobra.Command {
 				}
 			}
 
-			// Build post-index hook: linker + vector update.
+			// Capture the graph before indexing to notify subscribers of changes.
+			changes, err := newChangeNotifier(ctx(cmd), cfg, store, currentBranch, logFn)
+			if err != nil {
+				return err
+			}
+
+			// Build post-index hook: linker + vector update + change notifications.
 			postIndexHook := func(hookCtx context.Context) error {
 				if err := lnk.RunAll(hookCtx); err != nil {
 					return err
 				}
+				changes.check(hookCtx, store)
 				if vs != nil && vs.Available() {
 					// Save updated vectors after each index round.
 					if err := vs.Save(); err != nil {
@@ -186,6 +202,19 @@ func newWatchCmd() *cobra.Command {
 				return nil
 			}
 
+			// With notify subscriptions, re-link and report changes once
+			// watched edits settle, so new service dependencies are seen.
+			var changeHook func(context.Context) error
+			if changes != nil {
+				changeHook = func(hookCtx context.Context) error {
+					if err := lnk.RunAll(hookCtx); err != nil {
+						return err
+					}
+					changes.check(hookCtx, store)
+					return nil
+				}
+			}
+
 			// Create indexer.
 			idx := indexer.NewIndexer(indexer.IndexerConfig{
 				GraphStore:     store,
@@ -196,7 +225,13 @@ func newWatchCmd() *cobra.Command {
 				Logger:         logFn,
 				LLMClient:      llmClient,
 				AutoSummarize:  cfg.Agents.AutoSummarize,
+				ScanSecrets:    cfg.Secrets.Scan,
+				FileFilter:     newFileFilter(cfg),
+				BatchSize:      cfg.Index.BatchSize,
+				TrackRenames:   cfg.Index.TrackRenames,
+				SecretsExclude: cfg.Secrets.Exclude,
 				PostIndexHook:  postIndexHook,
+				ChangeHook:     changeHook,
 			})
 
 			// Set up signal handling.
@@ -0,0 +1,157 @@
+// Package codeowners parses GitHub/GitLab-style CODEOWNERS files and
+// resolves the owners of repository paths.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Locations are the CODEOWNERS paths checked by Load, in GitHub's order.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	// Path is the file the rules were read from, if loaded from disk.
+	Path  string
+	rules []rule
+}
+
+type rule struct {
+	segments []string // pattern split on "/", "**" for any depth
+	dirOnly  bool
+	fileOnly bool // trailing wildcard: "docs/*" does not own docs/api/x.md
+	owners   []string
+}
+
+// Load reads the first CODEOWNERS file found in the repository root.
+// Returns nil (no error) if the repository has none.
+func Load(repoRoot string) (*File, error) {
+	for _, loc := range Locations {
+		p := filepath.Join(repoRoot, loc)
+		f, err := os.Open(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("open %s: %w", p, err)
+		}
+		defer f.Close()
+		co, err := Parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", p, err)
+		}
+		co.Path = p
+		return co, nil
+	}
+	return nil, nil
+}
+
+// Parse reads CODEOWNERS rules. Each line is a gitignore-style pattern
+// followed by zero or more owners; a pattern without owners clears ownership.
+// GitLab section headers ("[Section]") are skipped.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[") ||
+			strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		f.rules = append(f.rules, parseRule(fields[0], fields[1:]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseRule(pattern string, owners []string) rule {
+	r := rule{owners: owners}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	// Patterns containing a slash are anchored to the repository root;
+	// bare names match at any depth.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !anchored {
+		r.segments = append(r.segments, "**")
+	}
+	for _, s := range strings.Split(pattern, "/") {
+		if s != "" {
+			r.segments = append(r.segments, s)
+		}
+	}
+	if len(r.segments) == 0 {
+		r.segments = []string{"**"}
+	}
+	last := r.segments[len(r.segments)-1]
+	r.fileOnly = !r.dirOnly && last != "**" && strings.ContainsAny(last, "*?[")
+	return r
+}
+
+// Owners returns the owners of a repository-relative path. The last matching
+// rule wins. Returns nil if no rule matches or the matching rule has no owners.
+func (f *File) Owners(filePath string) []string {
+	if f == nil {
+		return nil
+	}
+	parts := strings.Split(strings.Trim(filepath.ToSlash(filePath), "/"), "/")
+	var owners []string
+	for _, r := range f.rules {
+		if r.matches(parts) {
+			owners = r.owners
+		}
+	}
+	return owners
+}
+
+// matches reports whether the rule matches the path itself or one of its
+// parent directories (a matched directory owns everything below it).
+func (r rule) matches(parts []string) bool {
+	if r.fileOnly {
+		return matchSegments(r.segments, parts)
+	}
+	for n := len(parts); n >= 1; n-- {
+		if r.dirOnly && n == len(parts) {
+			continue
+		}
+		if matchSegments(r.segments, parts[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
@@ -0,0 +1,73 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sample = `# Default owners
+*                   @acme/platform
+
+[Payments]
+/payments/          @acme/payments
+*.sql               @acme/dba # schema changes
+docs/*              @acme/docs
+**/generated/**
+apps/web/**/api/    @acme/web-api alice@example.com
+`
+
+func TestOwners(t *testing.T) {
+	f, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"README.md", "@acme/platform"},
+		{"payments/src/charge.go", "@acme/payments"},
+		{"payments", "@acme/platform"}, // directory rule needs something below it
+		{"payments/db/001_init.sql", "@acme/dba"},
+		{"docs/guide.md", "@acme/docs"},
+		{"docs/api/guide.md", "@acme/platform"}, // docs/* is one level only
+		{"orders/generated/client.go", ""},
+		{"apps/web/src/api/users.ts", "@acme/web-api alice@example.com"},
+		{"/payments/src/refund.go", "@acme/payments"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(f.Owners(tt.path), " "); got != tt.want {
+			t.Errorf("Owners(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	f, err := Load(root)
+	if err != nil || f != nil {
+		t.Fatalf("Load without CODEOWNERS = (%v, %v), want (nil, nil)", f, err)
+	}
+	if got := f.Owners("any.go"); got != nil {
+		t.Errorf("nil File Owners = %v, want nil", got)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @github\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err = Load(root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := f.Owners("main.go"); len(got) != 1 || got[0] != "@github" {
+		t.Errorf("Owners = %v, want [@github] from .github/CODEOWNERS", got)
+	}
+}
@@ -0,0 +1,200 @@
+// Package compact shrinks the knowledge graph by merging duplicate nodes
+// left behind by earlier ID schemes and parser versions and by pruning
+// shared nodes nothing refers to any more.
+package compact
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Result counts what DedupeDependencies merged (or would merge).
+type Result struct {
+	// Groups is the number of sets of identical dependency nodes.
+	Groups int `json:"groups"`
+	// NodesRemoved is the number of duplicate nodes deleted.
+	NodesRemoved int `json:"nodes_removed"`
+	// EdgesMoved is the number of edges re-pointed at the kept node.
+	EdgesMoved int `json:"edges_moved"`
+}
+
+// DedupeDependencies merges Dependency nodes of the same file that are
+// identical apart from their ID and line: each group keeps one node, the
+// edges of the others are moved onto it, and the others are deleted.
+// API call nodes are left alone, as each records a distinct call site.
+// With dryRun the store is not modified.
+func DedupeDependencies(ctx context.Context, store graph.Store, dryRun bool) (*Result, error) {
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeDependency})
+	if err != nil {
+		return nil, fmt.Errorf("query dependencies: %w", err)
+	}
+	groups := make(map[string][]*graph.Node)
+	for _, n := range nodes {
+		if n.Properties["kind"] == "api_call" {
+			continue
+		}
+		k := dedupeKey(n)
+		groups[k] = append(groups[k], n)
+	}
+
+	res := &Result{}
+	keys := make([]string, 0, len(groups))
+	for k, g := range groups {
+		if len(g) > 1 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		group := groups[k]
+		keep := pickKeeper(group)
+		res.Groups++
+		added := make(map[string]bool)
+		for _, dup := range group {
+			if dup.ID == keep.ID {
+				continue
+			}
+			moved, err := moveEdges(ctx, store, dup.ID, keep.ID, added, dryRun)
+			if err != nil {
+				return res, err
+			}
+			res.EdgesMoved += moved
+			if !dryRun {
+				if err := store.DeleteNode(ctx, dup.ID); err != nil {
+					return res, fmt.Errorf("delete duplicate %s: %w", dup.ID, err)
+				}
+			}
+			res.NodesRemoved++
+		}
+	}
+	return res, nil
+}
+
+// PruneDependencies deletes the shared Dependency nodes of package imports
+// (those without a file path) that no file imports any more: re-indexing or
+// deleting a file removes its Imports edges but leaves the shared node. The
+// placeholder nodes of external types (external=true) nothing links to any
+// more are deleted too. It returns the number of nodes deleted, or with
+// dryRun that would be.
+func PruneDependencies(ctx context.Context, store graph.Store, dryRun bool) (int, error) {
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeDependency})
+	if err != nil {
+		return 0, fmt.Errorf("query dependencies: %w", err)
+	}
+	external, err := store.QueryNodes(ctx, graph.NodeFilter{Properties: map[string]string{"external": "true"}})
+	if err != nil {
+		return 0, fmt.Errorf("query external types: %w", err)
+	}
+	pruned := 0
+	for _, n := range append(nodes, external...) {
+		if n.FilePath != "" {
+			continue
+		}
+		edgeType := graph.EdgeImports
+		if n.Type != graph.NodeDependency {
+			edgeType = ""
+		}
+		edges, err := store.GetEdges(ctx, n.ID, edgeType)
+		if err != nil {
+			return pruned, fmt.Errorf("get edges of %s: %w", n.ID, err)
+		}
+		referenced := false
+		for _, e := range edges {
+			if e.TargetID == n.ID {
+				referenced = true
+				break
+			}
+		}
+		if referenced {
+			continue
+		}
+		if !dryRun {
+			if err := store.DeleteNode(ctx, n.ID); err != nil {
+				return pruned, fmt.Errorf("delete orphaned %s %s: %w", n.Type, n.ID, err)
+			}
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// dedupeKey identifies a dependency by everything but its ID and position.
+func dedupeKey(n *graph.Node) string {
+	parts := []string{n.FilePath, n.Name, n.Language, n.Package}
+	props := make([]string, 0, len(n.Properties))
+	for k, v := range n.Properties {
+		if k == graph.PropGraphSource {
+			continue
+		}
+		props = append(props, k+"="+v)
+	}
+	sort.Strings(props)
+	return strings.Join(append(parts, props...), "\x00")
+}
+
+// pickKeeper prefers the node with the ID the parsers assign today, then
+// the first occurrence in the file.
+func pickKeeper(group []*graph.Node) *graph.Node {
+	canonical := graph.NewNodeID(string(graph.NodeDependency), group[0].FilePath, group[0].Name)
+	keep := group[0]
+	for _, n := range group {
+		switch {
+		case n.ID == canonical:
+			return n
+		case n.Line < keep.Line || (n.Line == keep.Line && n.ID < keep.ID):
+			keep = n
+		}
+	}
+	return keep
+}
+
+// moveEdges re-creates the edges of node from on node to, skipping those
+// the kept node already has or was given (added, keyed by edge ID). It
+// returns the number of edges moved.
+func moveEdges(ctx context.Context, store graph.Store, from, to string, added map[string]bool, dryRun bool) (int, error) {
+	edges, err := store.GetEdges(ctx, from, "")
+	if err != nil {
+		return 0, fmt.Errorf("get edges of %s: %w", from, err)
+	}
+	moved := 0
+	for _, e := range edges {
+		src, tgt := e.SourceID, e.TargetID
+		if src == from {
+			src = to
+		}
+		if tgt == from {
+			tgt = to
+		}
+		if src == tgt {
+			continue
+		}
+		id := graph.NewEdgeID(e.Type, src, tgt)
+		if added[id] {
+			continue
+		}
+		if existing, err := store.QueryEdges(ctx, graph.EdgeFilter{SourceID: src, TargetID: tgt, Type: e.Type}); err != nil {
+			return moved, fmt.Errorf("query edges of %s: %w", to, err)
+		} else if len(existing) > 0 {
+			continue
+		}
+		added[id] = true
+		moved++
+		if dryRun {
+			continue
+		}
+		props := make(map[string]string, len(e.Properties))
+		for k, v := range e.Properties {
+			if k != graph.PropGraphSource {
+				props[k] = v
+			}
+		}
+		if err := store.AddEdge(ctx, &graph.Edge{ID: id, Type: e.Type, SourceID: src, TargetID: tgt, Properties: props}); err != nil {
+			return moved, fmt.Errorf("move edge %s: %w", e.ID, err)
+		}
+	}
+	return moved, nil
+}
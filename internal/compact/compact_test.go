@@ -0,0 +1,141 @@
+package compact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestDedupeDependencies(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	canonical := graph.NewNodeID(string(graph.NodeDependency), "app.py", "os")
+	imp := map[string]string{"kind": "import"}
+	for _, n := range []*graph.Node{
+		{ID: "mod", Type: graph.NodeModule, Name: "app", FilePath: "app.py"},
+		{ID: "fn", Type: graph.NodeFunction, Name: "run", FilePath: "app.py"},
+		{ID: "os-old-1", Type: graph.NodeDependency, Name: "os", FilePath: "app.py", Line: 1, Properties: imp},
+		{ID: "os-old-2", Type: graph.NodeDependency, Name: "os", FilePath: "app.py", Line: 9, Properties: imp},
+		{ID: canonical, Type: graph.NodeDependency, Name: "os", FilePath: "app.py", Line: 5, Properties: imp},
+		{ID: "os-other-file", Type: graph.NodeDependency, Name: "os", FilePath: "cli.py", Line: 1, Properties: imp},
+		{ID: "call-1", Type: graph.NodeDependency, Name: "GET /users", FilePath: "app.py", Line: 3, Properties: map[string]string{"kind": "api_call"}},
+		{ID: "call-2", Type: graph.NodeDependency, Name: "GET /users", FilePath: "app.py", Line: 7, Properties: map[string]string{"kind": "api_call"}},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	for _, e := range []*graph.Edge{
+		{ID: "i1", Type: graph.EdgeImports, SourceID: "mod", TargetID: "os-old-1"},
+		{ID: "i2", Type: graph.EdgeImports, SourceID: "mod", TargetID: "os-old-2"},
+		{ID: "i3", Type: graph.EdgeImports, SourceID: "mod", TargetID: canonical},
+		{ID: "u1", Type: graph.EdgeDependsOn, SourceID: "fn", TargetID: "os-old-2", Properties: map[string]string{"line": "9"}},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+
+	want := Result{Groups: 1, NodesRemoved: 2, EdgesMoved: 1}
+	res, err := DedupeDependencies(ctx, store, true)
+	if err != nil || *res != want {
+		t.Fatalf("dry run = %+v, %v; want %+v", res, err, want)
+	}
+	if _, err := store.GetNode(ctx, "os-old-1"); err != nil {
+		t.Fatal("dry run deleted a node")
+	}
+
+	res, err = DedupeDependencies(ctx, store, false)
+	if err != nil || *res != want {
+		t.Fatalf("DedupeDependencies = %+v, %v; want %+v", res, err, want)
+	}
+	for _, id := range []string{"os-old-1", "os-old-2"} {
+		if _, err := store.GetNode(ctx, id); err == nil {
+			t.Errorf("duplicate %s kept", id)
+		}
+	}
+	for _, id := range []string{canonical, "os-other-file", "call-1", "call-2"} {
+		if _, err := store.GetNode(ctx, id); err != nil {
+			t.Errorf("node %s removed: %v", id, err)
+		}
+	}
+	uses, err := store.QueryEdges(ctx, graph.EdgeFilter{SourceID: "fn", TargetID: canonical, Type: graph.EdgeDependsOn})
+	if err != nil || len(uses) != 1 || uses[0].Properties["line"] != "9" {
+		t.Errorf("moved Uses edge = %v, %v", uses, err)
+	}
+	if imports, err := store.GetEdges(ctx, "mod", graph.EdgeImports); err != nil || len(imports) != 1 {
+		t.Errorf("module imports = %v, %v; want one", imports, err)
+	}
+
+	if res, err := DedupeDependencies(ctx, store, false); err != nil || *res != (Result{}) {
+		t.Errorf("second run = %+v, %v; want nothing to do", res, err)
+	}
+}
+
+func TestPruneDependencies(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	imp := map[string]string{"kind": "import"}
+	used := graph.NewDependencyID("go", "fmt")
+	orphan := graph.NewDependencyID("go", "os")
+	ext := map[string]string{"external": "true"}
+	usedType := graph.NewExternalTypeID("typescript", "@nestjs/common", "OnModuleInit")
+	orphanType := graph.NewExternalTypeID("typescript", "@nestjs/common", "OnModuleDestroy")
+	for _, n := range []*graph.Node{
+		{ID: "pkg", Type: graph.NodePackage, Name: "main", FilePath: "main.go"},
+		{ID: "fn", Type: graph.NodeFunction, Name: "run", FilePath: "main.go"},
+		{ID: used, Type: graph.NodeDependency, Name: "fmt", Properties: imp},
+		{ID: orphan, Type: graph.NodeDependency, Name: "os", Properties: imp},
+		{ID: "local", Type: graph.NodeDependency, Name: "./util", FilePath: "main.go", Properties: imp},
+		{ID: "cls", Type: graph.NodeClass, Name: "App", FilePath: "app.ts"},
+		{ID: usedType, Type: graph.NodeInterface, Name: "OnModuleInit", Properties: ext},
+		{ID: orphanType, Type: graph.NodeInterface, Name: "OnModuleDestroy", Properties: ext},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	for _, e := range []*graph.Edge{
+		{ID: "i1", Type: graph.EdgeImports, SourceID: "pkg", TargetID: used},
+		// A call alone doesn't keep a dependency no file imports.
+		{ID: "c1", Type: graph.EdgeCalls, SourceID: "fn", TargetID: orphan},
+		{ID: "impl", Type: graph.EdgeImplements, SourceID: "cls", TargetID: usedType},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+
+	n, err := PruneDependencies(ctx, store, true)
+	if err != nil {
+		t.Fatalf("PruneDependencies dry run: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("dry run pruned %d, want 2", n)
+	}
+	if node, err := store.GetNode(ctx, orphan); err != nil || node == nil {
+		t.Fatalf("dry run deleted the orphan: %v", err)
+	}
+
+	if n, err = PruneDependencies(ctx, store, false); err != nil || n != 2 {
+		t.Fatalf("PruneDependencies = %d, %v, want 2", n, err)
+	}
+	for id, want := range map[string]bool{used: true, orphan: false, "local": true, usedType: true, orphanType: false} {
+		node, _ := store.GetNode(ctx, id)
+		if got := node != nil; got != want {
+			t.Errorf("%s present = %v, want %v", id, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -91,6 +92,26 @@ type Config struct {
 	Agents AgentsConfig `mapstructure:"agents" yaml:"agents"`
 	// Docs contains non-code file indexing configuration.
 	Docs DocsConfig `mapstructure:"docs" yaml:"docs"`
+	// Tests contains project-specific test detection overrides.
+	Tests TestsConfig `mapstructure:"tests" yaml:"tests,omitempty"`
+	// Secrets contains hard-coded credential scanning configuration.
+	Secrets SecretsConfig `mapstructure:"secrets" yaml:"secrets,omitempty"`
+	// Licenses contains the dependency license policy.
+	Licenses LicensesConfig `mapstructure:"licenses" yaml:"licenses,omitempty"`
+	// Services declares service boundaries for the linker.
+	Services ServicesConfig `mapstructure:"services" yaml:"services,omitempty"`
+	// DataClassification tags sensitive model and DTO fields.
+	DataClassification DataClassificationConfig `mapstructure:"data_classification" yaml:"data_classification,omitempty"`
+	// Index controls which files the indexer parses.
+	Index IndexConfig `mapstructure:"index" yaml:"index,omitempty"`
+	// Parsers registers external parser processes.
+	Parsers ParsersConfig `mapstructure:"parsers" yaml:"parsers,omitempty"`
+	// Linker selects and orders the linker phases.
+	Linker LinkerConfig `mapstructure:"linker" yaml:"linker,omitempty"`
+	// Notify sends graph change events from sync and watch to subscribers.
+	Notify NotifyConfig `mapstructure:"notify" yaml:"notify,omitempty"`
+	// Serve controls access to the graph served over HTTP.
+	Serve ServeConfig `mapstructure:"serve" yaml:"serve,omitempty"`
 	// ConfigDir is the resolved .CodeEagle directory path (not persisted in YAML).
 	ConfigDir string `mapstructure:"-" yaml:"-"`
 	// ProjectConf is the parsed .CodeEagle.conf if found (not persisted).
@@ -119,6 +140,237 @@ type WatchConfig struct {
 	Exclude []string `mapstructure:"exclude" yaml:"exclude"`
 }
 
+// TestsConfig holds project-specific test detection overrides. Patterns
+// extend the built-in per-language conventions rather than replacing them.
+type TestsConfig struct {
+	// FilePatterns lists globs (e.g., "*_it.go", "**/__tests__/**") that mark files as test files.
+	FilePatterns []string `mapstructure:"file_patterns" yaml:"file_patterns,omitempty"`
+	// FunctionPatterns lists globs (e.g., "Should*") that mark functions in test files as tests.
+	FunctionPatterns []string `mapstructure:"function_patterns" yaml:"function_patterns,omitempty"`
+	// Annotations lists extra annotation/attribute names (e.g., "IntegrationTest") that mark test methods.
+	Annotations []string `mapstructure:"annotations" yaml:"annotations,omitempty"`
+}
+
+// SecretsConfig controls the opt-in scan for hard-coded credentials during
+// indexing. Matches are stored as Finding nodes with the value redacted.
+type SecretsConfig struct {
+	// Scan enables the scan.
+	Scan bool `mapstructure:"scan" yaml:"scan,omitempty"`
+	// Exclude lists globs (e.g., "**/testdata/**", "*.lock") of files not to scan.
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+}
+
+// LicensesConfig holds the dependency license policy used by
+// `codeeagle licenses`.
+type LicensesConfig struct {
+	// Allow lists SPDX IDs dependencies may use; when set, anything else
+	// (including unresolved licenses) is a violation.
+	Allow []string `mapstructure:"allow" yaml:"allow,omitempty"`
+	// Deny lists SPDX IDs (e.g., "AGPL-3.0") that are always a violation.
+	Deny []string `mapstructure:"deny" yaml:"deny,omitempty"`
+	// Overrides sets the license of packages whose metadata is missing or
+	// wrong. A list rather than a map because package names contain dots,
+	// which viper treats as key separators.
+	Overrides []LicenseOverride `mapstructure:"overrides" yaml:"overrides,omitempty"`
+}
+
+// LicenseOverride pins the license of one dependency.
+type LicenseOverride struct {
+	// Package is the dependency name as declared in the manifest.
+	Package string `mapstructure:"package" yaml:"package"`
+	// License is the SPDX ID to use.
+	License string `mapstructure:"license" yaml:"license"`
+}
+
+// ServicesConfig declares service boundaries. By default the linker treats
+// each top-level directory as a service, which is wrong for layouts like
+// apps/foo/backend; declared services take precedence for files under their
+// roots.
+type ServicesConfig struct {
+	// OnlyDeclared disables top-level directory auto-detection: files outside
+	// every declared root belong to no service.
+	OnlyDeclared bool `mapstructure:"only_declared" yaml:"only_declared,omitempty"`
+	// Definitions lists the declared services.
+	Definitions []ServiceDefinition `mapstructure:"definitions" yaml:"definitions,omitempty"`
+}
+
+// ServiceDefinition declares one service.
+type ServiceDefinition struct {
+	// Name is the service name.
+	Name string `mapstructure:"name" yaml:"name"`
+	// Roots lists directory globs (e.g., "apps/foo/backend", "services/*/api",
+	// "libs/**/billing") whose files belong to the service.
+	Roots []string `mapstructure:"roots" yaml:"roots"`
+	// Kind is the service kind (e.g., "backend", "frontend", "library");
+	// defaults to "service".
+	Kind string `mapstructure:"kind" yaml:"kind,omitempty"`
+	// Hosts lists hostnames or base URLs (e.g., "payments.internal",
+	// "https://api.example.com/payments") the service is reachable at.
+	// API calls to these hosts link to the service's endpoints.
+	Hosts []string `mapstructure:"hosts" yaml:"hosts,omitempty"`
+}
+
+// DataClassificationConfig tags model and DTO fields as sensitive, on top
+// of those annotated in code (@PII, [PersonalData], `pii:"true"` struct
+// tags). The linker propagates the data classes to the endpoints and
+// services exposing the types, reported by `codeeagle data-flow`.
+type DataClassificationConfig struct {
+	// Fields lists the field patterns to tag.
+	Fields []SensitiveFieldRule `mapstructure:"fields" yaml:"fields,omitempty"`
+	// Depth bounds how many calls from an endpoint's handler propagation
+	// follows; defaults to 3.
+	Depth int `mapstructure:"depth" yaml:"depth,omitempty"`
+}
+
+// SensitiveFieldRule tags the fields matching a pattern with a data class.
+type SensitiveFieldRule struct {
+	// Pattern is a case-insensitive glob over "Type.field" or the bare
+	// field name (e.g., "*.ssn", "User.email", "*card_number*").
+	Pattern string `mapstructure:"pattern" yaml:"pattern"`
+	// Class is the data class (e.g., "pii", "pci", "phi"); defaults to
+	// "pii".
+	Class string `mapstructure:"class" yaml:"class,omitempty"`
+}
+
+// IndexConfig holds the rules applied to each file before it is parsed, on
+// top of watch.exclude.
+type IndexConfig struct {
+	// MaxFileSize skips files larger than this many bytes (0 = no limit).
+	MaxFileSize int64 `mapstructure:"max_file_size" yaml:"max_file_size,omitempty"`
+	// SkipVendored skips vendor/, node_modules/, bower_components/, dist/ and
+	// .venv/ directories and minified bundles.
+	SkipVendored bool `mapstructure:"skip_vendored" yaml:"skip_vendored,omitempty"`
+	// Generated is what to do with files whose header marks them generated
+	// ("Code generated ... DO NOT EDIT", "@generated", "<auto-generated>"):
+	// "annotate" (parse and mark nodes generated=true), "skip", or "index".
+	Generated string `mapstructure:"generated" yaml:"generated,omitempty"`
+	// GeneratedMarkers adds header fragments identifying generated files.
+	GeneratedMarkers []string `mapstructure:"generated_markers" yaml:"generated_markers,omitempty"`
+	// Languages holds per-language include/exclude globs and size limits.
+	Languages []LanguageIndexConfig `mapstructure:"languages" yaml:"languages,omitempty"`
+	// BatchSize is how many nodes and edges are buffered per store write
+	// (0 = the indexer default); it bounds the memory a huge file needs.
+	BatchSize int `mapstructure:"batch_size" yaml:"batch_size,omitempty"`
+	// TrackRenames links symbols that were renamed or moved during
+	// incremental indexing to their old IDs with RenamedFrom edges.
+	TrackRenames bool `mapstructure:"track_renames" yaml:"track_renames,omitempty"`
+}
+
+// LanguageIndexConfig narrows which files of one language are parsed.
+type LanguageIndexConfig struct {
+	// Language is the parser language (e.g., "typescript").
+	Language string `mapstructure:"language" yaml:"language"`
+	// Include, when set, restricts the language to matching files.
+	Include []string `mapstructure:"include" yaml:"include,omitempty"`
+	// Exclude skips matching files (e.g., "**/*.generated.ts").
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+	// MaxFileSize overrides index.max_file_size for the language.
+	MaxFileSize int64 `mapstructure:"max_file_size" yaml:"max_file_size,omitempty"`
+}
+
+// ParsersConfig adds parsers for languages CodeEagle doesn't support.
+type ParsersConfig struct {
+	// External lists parsers run as separate processes that read a file as
+	// JSON on stdin and write its nodes and edges as JSON on stdout.
+	External []ExternalParserConfig `mapstructure:"external" yaml:"external,omitempty"`
+}
+
+// ExternalParserConfig registers one external parser process.
+type ExternalParserConfig struct {
+	// Language names the language (e.g., "cobol"); it overrides a built-in
+	// parser of the same name.
+	Language string `mapstructure:"language" yaml:"language"`
+	// Extensions lists the file extensions (e.g., ".cbl") it parses.
+	Extensions []string `mapstructure:"extensions" yaml:"extensions,omitempty"`
+	// Filenames lists exact filenames (e.g., "BUILD.dsl") it parses.
+	Filenames []string `mapstructure:"filenames" yaml:"filenames,omitempty"`
+	// Command is the executable and its arguments. A relative executable
+	// path containing a slash is resolved against the project directory.
+	Command []string `mapstructure:"command" yaml:"command"`
+	// TimeoutSeconds bounds each file's parse; defaults to 30.
+	TimeoutSeconds int `mapstructure:"timeout_seconds" yaml:"timeout_seconds,omitempty"`
+}
+
+// LinkerConfig selects the linker phases run after indexing. Phases run in
+// dependency order; names are those listed by `codeeagle backpop --list`.
+type LinkerConfig struct {
+	// Phases lists the phases to run, in preferred order (a phase still runs
+	// after the phases it depends on). Empty runs every registered phase.
+	Phases []string `mapstructure:"phases" yaml:"phases,omitempty"`
+	// Disable lists phases to skip.
+	Disable []string `mapstructure:"disable" yaml:"disable,omitempty"`
+	// Parallelism bounds how many independent phases run at once. Zero
+	// uses GOMAXPROCS; 1 runs the phases one at a time.
+	Parallelism int `mapstructure:"parallelism" yaml:"parallelism,omitempty"`
+	// LLM bounds the LLM-assisted phases run by sync and watch.
+	LLM LinkerLLMConfig `mapstructure:"llm" yaml:"llm,omitempty"`
+}
+
+// LinkerLLMConfig limits the linker's LLM requests. Responses are cached in
+// the graph by prompt hash, so unchanged prompts cost nothing on later runs.
+type LinkerLLMConfig struct {
+	// Concurrency is how many requests run at once; defaults to 4.
+	Concurrency int `mapstructure:"concurrency" yaml:"concurrency,omitempty"`
+	// MaxRetries is how many times a failed request is retried with
+	// exponential backoff; defaults to 3, negative disables retries.
+	MaxRetries int `mapstructure:"max_retries" yaml:"max_retries,omitempty"`
+	// TokenBudget caps the input plus output tokens spent per linker run;
+	// zero means no limit.
+	TokenBudget int `mapstructure:"token_budget" yaml:"token_budget,omitempty"`
+}
+
+// NotifyConfig lists the subscribers to graph change events (new service
+// dependencies, new or untested endpoints) detected by sync and watch.
+type NotifyConfig struct {
+	Subscriptions []NotifySubscription `mapstructure:"subscriptions" yaml:"subscriptions,omitempty"`
+}
+
+// NotifySubscription delivers matching events to a webhook, an NDJSON
+// file, or both.
+type NotifySubscription struct {
+	// Name identifies the subscription in logs.
+	Name string `mapstructure:"name" yaml:"name,omitempty"`
+	// Events lists the event types to deliver; empty delivers all.
+	Events []string `mapstructure:"events" yaml:"events,omitempty"`
+	// Services lists globs of the service names whose events to deliver;
+	// empty delivers events of every service.
+	Services []string `mapstructure:"services" yaml:"services,omitempty"`
+	// Webhook is the URL events are POSTed to.
+	Webhook string `mapstructure:"webhook" yaml:"webhook,omitempty"`
+	// Format is the webhook payload: "json" (the default, {"events": [...]})
+	// or "slack" (an incoming-webhook {"text": ...} message).
+	Format string `mapstructure:"format" yaml:"format,omitempty"`
+	// File is an NDJSON file events are appended to, one per line;
+	// relative paths are resolved against the .CodeEagle directory.
+	File string `mapstructure:"file" yaml:"file,omitempty"`
+}
+
+// ServeConfig controls access to the graph when it is served over HTTP
+// ('codeeagle mcp serve --http').
+type ServeConfig struct {
+	// Tokens lists the bearer tokens accepted by the server. Without
+	// tokens the server only listens on loopback addresses.
+	Tokens []ServeToken `mapstructure:"tokens" yaml:"tokens,omitempty"`
+	// AuditLog is an NDJSON file every request is appended to; relative
+	// paths are resolved against the .CodeEagle directory.
+	AuditLog string `mapstructure:"audit_log" yaml:"audit_log,omitempty"`
+}
+
+// ServeToken grants a bearer token access to graph namespaces.
+type ServeToken struct {
+	// Name identifies the token in the audit log.
+	Name string `mapstructure:"name" yaml:"name"`
+	// TokenEnv names the environment variable holding the token, keeping
+	// the secret out of the config file.
+	TokenEnv string `mapstructure:"token_env" yaml:"token_env"`
+	// Namespaces lists globs of the namespaces the token may access; an
+	// empty list allows only the default namespace.
+	Namespaces []string `mapstructure:"namespaces" yaml:"namespaces,omitempty"`
+	// Scope is "read" (the default: queries and exports) or "write"
+	// (also graph imports).
+	Scope string `mapstructure:"scope" yaml:"scope,omitempty"`
+}
+
 // GraphConfig holds knowledge graph storage configuration.
 type GraphConfig struct {
 	// Storage is the storage backend (embedded or neo4j).
@@ -127,17 +379,23 @@ type GraphConfig struct {
 	Neo4jURI string `mapstructure:"neo4j_uri" yaml:"neo4j_uri,omitempty"`
 	// DBPath is the path to the graph database directory.
 	DBPath string `mapstructure:"db_path" yaml:"db_path,omitempty"`
+	// Namespace isolates this project's graph from the other tenants of a
+	// shared graph database (empty for the default namespace).
+	Namespace string `mapstructure:"namespace" yaml:"namespace,omitempty"`
 }
 
 // AgentsConfig holds AI agent configuration.
 type AgentsConfig struct {
-	// LLMProvider is the LLM provider (anthropic, vertex-ai, openai, ollama).
+	// LLMProvider is the LLM provider (anthropic, claude-cli, vertex-ai, gemini,
+	// ollama, azure-openai, bedrock).
 	LLMProvider string `mapstructure:"llm_provider" yaml:"llm_provider"`
-	// Model is the model identifier.
+	// Model is the model identifier (the deployment name for azure-openai,
+	// the model ID for bedrock).
 	Model string `mapstructure:"model" yaml:"model"`
 	// Project is the GCP project ID (used when LLMProvider is "vertex-ai").
 	Project string `mapstructure:"project" yaml:"project,omitempty"`
-	// Location is the GCP region (used when LLMProvider is "vertex-ai", e.g. "us-central1").
+	// Location is the GCP region (used when LLMProvider is "vertex-ai", e.g. "us-central1")
+	// or the AWS region (used when LLMProvider is "bedrock", e.g. "us-east-1").
 	Location string `mapstructure:"location" yaml:"location,omitempty"`
 	// AutoSummarize enables LLM-based summarization after indexing.
 	AutoSummarize bool `mapstructure:"auto_summarize" yaml:"auto_summarize"`
@@ -145,12 +403,22 @@ type AgentsConfig struct {
 	AutoLink bool `mapstructure:"auto_link" yaml:"auto_link"`
 	// CredentialsFile is the path to a GCP service account credentials JSON file (for Vertex AI).
 	CredentialsFile string `mapstructure:"credentials_file" yaml:"credentials_file,omitempty"`
-	// BaseURL is the base URL for the LLM provider API (e.g. Ollama endpoint).
+	// BaseURL is the base URL for the LLM provider API (e.g. Ollama endpoint,
+	// Azure OpenAI resource endpoint).
 	BaseURL string `mapstructure:"base_url" yaml:"base_url,omitempty"`
-	// EmbeddingProvider is the embedding provider ("ollama", "vertex-ai"). Empty means auto-detect.
+	// APIVersion is the API version requested from Azure OpenAI. Empty means
+	// the provider default.
+	APIVersion string `mapstructure:"api_version" yaml:"api_version,omitempty"`
+	// EmbeddingProvider is the embedding provider ("ollama", "llamacpp", "vertex-ai"). Empty means auto-detect.
 	EmbeddingProvider string `mapstructure:"embedding_provider" yaml:"embedding_provider,omitempty"`
 	// EmbeddingModel is the embedding model name. Empty means use provider default.
 	EmbeddingModel string `mapstructure:"embedding_model" yaml:"embedding_model,omitempty"`
+	// EmbeddingBaseURL is the base URL of a local embedding server (Ollama or
+	// llama.cpp). Empty means the provider's default local port.
+	EmbeddingBaseURL string `mapstructure:"embedding_base_url" yaml:"embedding_base_url,omitempty"`
+	// EmbeddingDimensions is the embedding vector size. Empty means the
+	// model's native size (learned from the server for local providers).
+	EmbeddingDimensions int `mapstructure:"embedding_dimensions" yaml:"embedding_dimensions,omitempty"`
 }
 
 // HomeDir returns the path to the user-level CodeEagle directory (~/.CodeEagle/).
@@ -207,6 +475,15 @@ func (c *Config) ResolveDBPath(flagValue string) string {
 	return ""
 }
 
+// ResolveNamespace returns the graph namespace: flagValue (CLI --namespace
+// flag) if non-empty, else graph.namespace from config YAML.
+func (c *Config) ResolveNamespace(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return c.Graph.Namespace
+}
+
 // DiscoverProjectConf walks up from startDir looking for a .CodeEagle.conf file.
 // Returns the conf file path, parsed conf, and any error.
 func DiscoverProjectConf(startDir string) (confPath string, conf *ProjectConf, err error) {
@@ -375,6 +652,91 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("neo4j_uri is required when graph storage is 'neo4j'")
 	}
 
+	seen := make(map[string]bool)
+	for i, svc := range c.Services.Definitions {
+		if svc.Name == "" {
+			return fmt.Errorf("service %d: name is required", i)
+		}
+		if seen[svc.Name] {
+			return fmt.Errorf("service %q: declared more than once", svc.Name)
+		}
+		seen[svc.Name] = true
+		if len(svc.Roots) == 0 {
+			return fmt.Errorf("service %q: at least one root is required", svc.Name)
+		}
+		for _, root := range svc.Roots {
+			if _, err := filepath.Match(root, ""); err != nil {
+				return fmt.Errorf("service %q: invalid root %q: %w", svc.Name, root, err)
+			}
+		}
+	}
+
+	switch c.Index.Generated {
+	case "", "annotate", "skip", "index":
+	default:
+		return fmt.Errorf("index generated must be 'annotate', 'skip', or 'index', got %q", c.Index.Generated)
+	}
+	if c.Index.BatchSize < 0 {
+		return fmt.Errorf("index batch_size must be >= 0, got %d", c.Index.BatchSize)
+	}
+	for i, l := range c.Index.Languages {
+		if l.Language == "" {
+			return fmt.Errorf("index language %d: language is required", i)
+		}
+		for _, g := range append(append([]string(nil), l.Include...), l.Exclude...) {
+			if _, err := filepath.Match(g, ""); err != nil {
+				return fmt.Errorf("index language %q: invalid glob %q: %w", l.Language, g, err)
+			}
+		}
+	}
+
+	for i, p := range c.Parsers.External {
+		if p.Language == "" {
+			return fmt.Errorf("external parser %d: language is required", i)
+		}
+		if len(p.Command) == 0 {
+			return fmt.Errorf("external parser %q: command is required", p.Language)
+		}
+		if len(p.Extensions) == 0 && len(p.Filenames) == 0 {
+			return fmt.Errorf("external parser %q: at least one extension or filename is required", p.Language)
+		}
+	}
+
+	for i, sub := range c.Notify.Subscriptions {
+		name := sub.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+		if sub.Webhook == "" && sub.File == "" {
+			return fmt.Errorf("notify subscription %s: webhook or file is required", name)
+		}
+		if sub.Format != "" && sub.Format != "json" && sub.Format != "slack" {
+			return fmt.Errorf("notify subscription %s: format must be 'json' or 'slack', got %q", name, sub.Format)
+		}
+		for _, g := range sub.Services {
+			if _, err := filepath.Match(g, ""); err != nil {
+				return fmt.Errorf("notify subscription %s: invalid service glob %q: %w", name, g, err)
+			}
+		}
+	}
+
+	for i, tok := range c.Serve.Tokens {
+		if tok.Name == "" {
+			return fmt.Errorf("serve token %d: name is required", i)
+		}
+		if tok.TokenEnv == "" {
+			return fmt.Errorf("serve token %s: token_env is required", tok.Name)
+		}
+		if tok.Scope != "" && tok.Scope != "read" && tok.Scope != "write" {
+			return fmt.Errorf("serve token %s: scope must be 'read' or 'write', got %q", tok.Name, tok.Scope)
+		}
+		for _, g := range tok.Namespaces {
+			if _, err := path.Match(g, ""); err != nil {
+				return fmt.Errorf("serve token %s: invalid namespace glob %q: %w", tok.Name, g, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -409,6 +771,10 @@ func setDefaults(v *viper.Viper) {
 
 	v.SetDefault("docs.max_image_resolution", 1024)
 	v.SetDefault("docs.context_window", 49152)
+	v.SetDefault("index.max_file_size", 1<<20)
+	v.SetDefault("index.skip_vendored", true)
+	v.SetDefault("index.generated", "annotate")
+	v.SetDefault("index.track_renames", true)
 	v.SetDefault("docs.exclude_extensions", []string{".lock", ".min.js", ".min.css", ".map", ".wasm", ".pb.go"})
 	v.SetDefault("docs.faces.enabled", false)
 	v.SetDefault("docs.faces.model_dir", "~/.codeeagle/models/")
@@ -200,6 +200,108 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "service without roots",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Services:     ServicesConfig{Definitions: []ServiceDefinition{{Name: "foo"}}},
+			},
+			wantErr: true,
+			errMsg:  "at least one root is required",
+		},
+		{
+			name: "duplicate service",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Services: ServicesConfig{Definitions: []ServiceDefinition{
+					{Name: "foo", Roots: []string{"apps/foo"}},
+					{Name: "foo", Roots: []string{"apps/bar"}},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "declared more than once",
+		},
+		{
+			name: "invalid service root",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Services:     ServicesConfig{Definitions: []ServiceDefinition{{Name: "foo", Roots: []string{"apps/[foo"}}}},
+			},
+			wantErr: true,
+			errMsg:  "invalid root",
+		},
+		{
+			name: "invalid generated mode",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Index:        IndexConfig{Generated: "drop"},
+			},
+			wantErr: true,
+			errMsg:  "index generated must be",
+		},
+		{
+			name: "negative batch size",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Index:        IndexConfig{BatchSize: -1},
+			},
+			wantErr: true,
+			errMsg:  "index batch_size must be",
+		},
+		{
+			name: "external parser without command",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Parsers:      ParsersConfig{External: []ExternalParserConfig{{Language: "cobol", Extensions: []string{".cbl"}}}},
+			},
+			wantErr: true,
+			errMsg:  "command is required",
+		},
+		{
+			name: "external parser without files",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Parsers:      ParsersConfig{External: []ExternalParserConfig{{Language: "cobol", Command: []string{"cobol-parse"}}}},
+			},
+			wantErr: true,
+			errMsg:  "at least one extension or filename",
+		},
+		{
+			name: "notify subscription without target",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Notify:       NotifyConfig{Subscriptions: []NotifySubscription{{Name: "drift"}}},
+			},
+			wantErr: true,
+			errMsg:  "notify subscription drift: webhook or file is required",
+		},
+		{
+			name: "notify subscription with unknown format",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Notify:       NotifyConfig{Subscriptions: []NotifySubscription{{Webhook: "https://example.com/hook", Format: "teams"}}},
+			},
+			wantErr: true,
+			errMsg:  "format must be 'json' or 'slack'",
+		},
+		{
+			name: "serve token without env var",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Serve:        ServeConfig{Tokens: []ServeToken{{Name: "ci"}}},
+			},
+			wantErr: true,
+			errMsg:  "serve token ci: token_env is required",
+		},
+		{
+			name: "serve token with unknown scope",
+			cfg: Config{
+				Repositories: []RepositoryConfig{{Path: "/tmp/repo"}},
+				Serve:        ServeConfig{Tokens: []ServeToken{{Name: "ci", TokenEnv: "CI_TOKEN", Scope: "admin"}}},
+			},
+			wantErr: true,
+			errMsg:  "scope must be 'read' or 'write'",
+		},
 		{
 			name: "valid neo4j config",
 			cfg: Config{
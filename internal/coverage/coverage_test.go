@@ -0,0 +1,221 @@
+package coverage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+const goProfile = `mode: set
+github.com/acme/app/store/store.go:10.30,12.2 1 1
+github.com/acme/app/store/store.go:14.30,18.2 2 0
+`
+
+const lcovReport = `TN:creates a user
+SF:web/src/users.ts
+DA:3,1
+DA:4,0
+end_of_record
+TN:
+SF:web/src/util.ts
+DA:1,2
+end_of_record
+`
+
+const jacocoReport = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="app">
+  <sessioninfo id="s1" start="1" dump="2"/>
+  <package name="com/acme">
+    <sourcefile name="UserService.java">
+      <line nr="5" mi="0" ci="3" mb="0" cb="0"/>
+      <line nr="6" mi="2" ci="0" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>
+`
+
+const coberturaXML = `<?xml version="1.0" ?>
+<coverage version="7.4">
+  <packages>
+    <package name="app">
+      <classes>
+        <class name="api.py" filename="app/api.py">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="2" hits="0"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>
+`
+
+const coveragePyReport = `{
+  "meta": {"show_contexts": true},
+  "files": {
+    "app/api.py": {
+      "executed_lines": [1, 5, 6],
+      "missing_lines": [9],
+      "contexts": {"1": [""], "5": ["tests/test_api.py::test_create|run"], "6": ["tests/test_api.py::test_create|run"]}
+    }
+  }
+}`
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantFormat Format
+		file       string
+		covered    []int
+		uncovered  []int
+		testLines  map[string]int
+	}{
+		{"go", goProfile, FormatGo, "github.com/acme/app/store/store.go", []int{10, 11, 12}, []int{14, 18}, nil},
+		{"lcov", lcovReport, FormatLCOV, "web/src/users.ts", []int{3}, []int{4}, map[string]int{"creates a user": 1}},
+		{"jacoco", jacocoReport, FormatJaCoCo, "com/acme/UserService.java", []int{5}, []int{6}, nil},
+		{"cobertura", coberturaXML, FormatCoveragePy, "app/api.py", []int{1}, []int{2}, nil},
+		{"coveragepy json", coveragePyReport, FormatCoveragePy, "app/api.py", []int{1, 5, 6}, []int{9}, map[string]int{"tests/test_api.py::test_create": 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep, err := Parse(strings.NewReader(tt.input), FormatAuto)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if rep.Format != tt.wantFormat {
+				t.Errorf("format = %s, want %s", rep.Format, tt.wantFormat)
+			}
+			fc := rep.Files[tt.file]
+			if fc == nil {
+				t.Fatalf("file %s missing; got %v", tt.file, rep.Files)
+			}
+			for _, l := range tt.covered {
+				if fc.Lines[l] == 0 {
+					t.Errorf("line %d should be covered", l)
+				}
+			}
+			for _, l := range tt.uncovered {
+				if hits, ok := fc.Lines[l]; !ok || hits != 0 {
+					t.Errorf("line %d should be present and uncovered, got %d (present=%v)", l, hits, ok)
+				}
+			}
+			if len(fc.TestLines) != len(tt.testLines) {
+				t.Errorf("TestLines = %v, want %v", fc.TestLines, tt.testLines)
+			}
+			for name, n := range tt.testLines {
+				if len(fc.TestLines[name]) != n {
+					t.Errorf("TestLines[%q] has %d lines, want %d", name, len(fc.TestLines[name]), n)
+				}
+			}
+		})
+	}
+
+	if _, err := Parse(strings.NewReader("hello"), FormatAuto); err == nil {
+		t.Error("expected error for unrecognized format")
+	}
+	if _, err := Parse(strings.NewReader("mode: set\nbad line\n"), FormatGo); err == nil {
+		t.Error("expected error for malformed go profile")
+	}
+}
+
+func newTestStore(t *testing.T) graph.Store {
+	t.Helper()
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestIngest(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	nodes := []*graph.Node{
+		{ID: "file-store", Type: graph.NodeFile, Name: "store/store.go", FilePath: "store/store.go"},
+		{ID: "fn-get", Type: graph.NodeFunction, Name: "Get", FilePath: "store/store.go", Line: 10, EndLine: 12},
+		{ID: "fn-put", Type: graph.NodeFunction, Name: "Put", FilePath: "store/store.go", Line: 14, EndLine: 18},
+		{ID: "tf-store", Type: graph.NodeTestFile, Name: "store/store_test.go", FilePath: "store/store_test.go"},
+		{ID: "test-get", Type: graph.NodeTestFunction, Name: "TestGet", FilePath: "store/store_test.go", Line: 5},
+		{ID: "file-api", Type: graph.NodeFile, Name: "app/api.py", FilePath: "app/api.py"},
+		{ID: "fn-create", Type: graph.NodeFunction, Name: "create", FilePath: "app/api.py", Line: 4, EndLine: 7},
+		{ID: "test-create", Type: graph.NodeTestFunction, Name: "test_create", FilePath: "tests/test_api.py", Line: 3},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+
+	// Go profile without attribution requires a test name.
+	rep, err := Parse(strings.NewReader(goProfile), FormatAuto)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := Ingest(ctx, store, rep, Options{}); err == nil {
+		t.Fatal("expected error for report without per-test attribution")
+	}
+
+	res, err := Ingest(ctx, store, rep, Options{Test: "TestGet"})
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if res.Files != 1 || res.Tests != 1 || res.Edges != 2 {
+		t.Errorf("result = %+v, want files=1 tests=1 edges=2", res)
+	}
+	assertCovers(t, store, "test-get", map[string]string{"fn-get": "3", "file-store": "3"})
+
+	// Re-ingesting replaces previous edges from the same test.
+	rep2, _ := Parse(strings.NewReader("mode: set\ngithub.com/acme/app/store/store.go:15.1,15.20 1 1\n"), FormatAuto)
+	if _, err := Ingest(ctx, store, rep2, Options{Test: "TestGet"}); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	assertCovers(t, store, "test-get", map[string]string{"fn-put": "1", "file-store": "1"})
+
+	// Test file attribution.
+	if _, err := Ingest(ctx, store, rep, Options{Test: "store/store_test.go"}); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	assertCovers(t, store, "tf-store", map[string]string{"fn-get": "3", "file-store": "3"})
+
+	// Per-test attribution from coverage.py contexts.
+	pyRep, _ := Parse(strings.NewReader(coveragePyReport), FormatAuto)
+	res, err = Ingest(ctx, store, pyRep, Options{})
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if len(res.UnresolvedTests) != 0 {
+		t.Errorf("unresolved tests = %v", res.UnresolvedTests)
+	}
+	assertCovers(t, store, "test-create", map[string]string{"fn-create": "2", "file-api": "2"})
+}
+
+func assertCovers(t *testing.T, store graph.Store, srcID string, want map[string]string) {
+	t.Helper()
+	edges, err := store.GetEdges(context.Background(), srcID, graph.EdgeCovers)
+	if err != nil {
+		t.Fatalf("GetEdges: %v", err)
+	}
+	got := make(map[string]string)
+	for _, e := range edges {
+		if e.SourceID == srcID {
+			got[e.TargetID] = e.Properties["lines"]
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("covers from %s = %v, want %v", srcID, got, want)
+		return
+	}
+	for id, lines := range want {
+		if got[id] != lines {
+			t.Errorf("covers %s -> %s lines = %q, want %q", srcID, id, got[id], lines)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Options controls how a report is attributed to tests.
+type Options struct {
+	// Test attributes every covered line in the report to the named test
+	// function or test file path, ignoring any per-test data in the report.
+	// Required for formats without per-test attribution (Go, JaCoCo, Cobertura).
+	Test string
+}
+
+// Result summarizes an ingestion run.
+type Result struct {
+	// Files is the number of report files matched to indexed files.
+	Files int `json:"files"`
+	// UnmatchedFiles lists report paths with no corresponding indexed file.
+	UnmatchedFiles []string `json:"unmatched_files,omitempty"`
+	// Tests is the number of test nodes that received Covers edges.
+	Tests int `json:"tests"`
+	// UnresolvedTests lists test names that matched no TestFunction or TestFile node.
+	UnresolvedTests []string `json:"unresolved_tests,omitempty"`
+	// Edges is the number of Covers edges written.
+	Edges int `json:"edges"`
+}
+
+// Ingest links the covered lines in rep to the functions, methods, and files
+// that contain them, writing Covers edges from the covering test nodes. Any
+// existing Covers edges from those test nodes are replaced.
+func Ingest(ctx context.Context, store graph.Store, rep *Report, opts Options) (*Result, error) {
+	if opts.Test == "" && !rep.HasTestAttribution() {
+		return nil, fmt.Errorf("%s report has no per-test attribution; specify the test that produced it", rep.Format)
+	}
+
+	res := &Result{}
+	ix, err := newIndex(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	// testName -> graph file path -> covered lines
+	attributed := make(map[string]map[string][]int)
+	addLines := func(test, gp string, lines []int) {
+		if len(lines) == 0 {
+			return
+		}
+		byFile, ok := attributed[test]
+		if !ok {
+			byFile = make(map[string][]int)
+			attributed[test] = byFile
+		}
+		byFile[gp] = append(byFile[gp], lines...)
+	}
+
+	reportPaths := make([]string, 0, len(rep.Files))
+	for p := range rep.Files {
+		reportPaths = append(reportPaths, p)
+	}
+	sort.Strings(reportPaths)
+	for _, rp := range reportPaths {
+		fc := rep.Files[rp]
+		gp := ix.resolvePath(rp)
+		if gp == "" {
+			res.UnmatchedFiles = append(res.UnmatchedFiles, rp)
+			continue
+		}
+		res.Files++
+		if opts.Test != "" {
+			addLines(opts.Test, gp, fc.CoveredLines())
+			continue
+		}
+		for test, lines := range fc.TestLines {
+			covered := make([]int, 0, len(lines))
+			for l := range lines {
+				covered = append(covered, l)
+			}
+			addLines(test, gp, covered)
+		}
+	}
+
+	tests := make([]string, 0, len(attributed))
+	for t := range attributed {
+		tests = append(tests, t)
+	}
+	sort.Strings(tests)
+
+	for _, test := range tests {
+		sources := ix.resolveTest(test)
+		if len(sources) == 0 {
+			res.UnresolvedTests = append(res.UnresolvedTests, test)
+			continue
+		}
+		for _, src := range sources {
+			n, err := ix.writeCovers(ctx, src, attributed[test], rep.Format)
+			if err != nil {
+				return nil, err
+			}
+			res.Tests++
+			res.Edges += n
+		}
+	}
+	return res, nil
+}
+
+// index caches the graph lookups needed during ingestion.
+type index struct {
+	store     graph.Store
+	filePaths []string                 // indexed source and test file paths
+	fileNodes map[string]*graph.Node   // source file path -> File node
+	testFiles map[string]*graph.Node   // test file path -> TestFile node
+	testFuncs map[string][]*graph.Node // test function name -> nodes
+	funcs     map[string][]*graph.Node // file path -> Function/Method nodes (lazy)
+}
+
+func newIndex(ctx context.Context, store graph.Store) (*index, error) {
+	ix := &index{
+		store:     store,
+		fileNodes: make(map[string]*graph.Node),
+		testFiles: make(map[string]*graph.Node),
+		testFuncs: make(map[string][]*graph.Node),
+		funcs:     make(map[string][]*graph.Node),
+	}
+
+	files, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFile})
+	if err != nil {
+		return nil, fmt.Errorf("query file nodes: %w", err)
+	}
+	for _, f := range files {
+		ix.fileNodes[f.FilePath] = f
+		ix.filePaths = append(ix.filePaths, f.FilePath)
+	}
+
+	testFiles, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeTestFile})
+	if err != nil {
+		return nil, fmt.Errorf("query test file nodes: %w", err)
+	}
+	for _, f := range testFiles {
+		ix.testFiles[f.FilePath] = f
+		ix.filePaths = append(ix.filePaths, f.FilePath)
+	}
+
+	testFuncs, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeTestFunction})
+	if err != nil {
+		return nil, fmt.Errorf("query test function nodes: %w", err)
+	}
+	for _, f := range testFuncs {
+		ix.testFuncs[f.Name] = append(ix.testFuncs[f.Name], f)
+	}
+	return ix, nil
+}
+
+// resolvePath maps a report path (absolute, module-qualified, or package
+// relative) to an indexed file path. Exact matches win; otherwise the longest
+// indexed path that is a suffix of the report path, or failing that the
+// shortest indexed path ending with the report path, is used.
+func (ix *index) resolvePath(reportPath string) string {
+	rp := strings.TrimPrefix(filepath.ToSlash(reportPath), "./")
+	best := ""
+	for _, p := range ix.filePaths {
+		if p == rp {
+			return p
+		}
+		if strings.HasSuffix(rp, "/"+p) && len(p) > len(best) {
+			best = p
+		}
+	}
+	if best != "" {
+		return best
+	}
+	for _, p := range ix.filePaths {
+		if strings.HasSuffix(p, "/"+rp) && (best == "" || len(p) < len(best)) {
+			best = p
+		}
+	}
+	return best
+}
+
+// resolveTest finds the test nodes for a test name. Names may be a test file
+// path, a bare function name, or qualified forms such as
+// "tests/test_api.py::test_create" or "com.example.FooTest.testBar".
+func (ix *index) resolveTest(name string) []*graph.Node {
+	if gp := ix.resolvePath(name); gp != "" {
+		if tf, ok := ix.testFiles[gp]; ok {
+			return []*graph.Node{tf}
+		}
+	}
+
+	filePart, funcName := "", name
+	if idx := strings.LastIndex(name, "::"); idx >= 0 {
+		filePart, funcName = name[:idx], name[idx+2:]
+	}
+	candidates := []string{funcName}
+	if idx := strings.LastIndexAny(funcName, "./#"); idx >= 0 {
+		candidates = append(candidates, funcName[idx+1:])
+	}
+	for _, c := range candidates {
+		nodes := ix.testFuncs[c]
+		if len(nodes) == 0 {
+			continue
+		}
+		if filePart != "" {
+			var filtered []*graph.Node
+			for _, n := range nodes {
+				if strings.HasSuffix(filePart, n.FilePath) || strings.HasSuffix(n.FilePath, filePart) {
+					filtered = append(filtered, n)
+				}
+			}
+			if len(filtered) > 0 {
+				return filtered
+			}
+		}
+		return nodes
+	}
+	return nil
+}
+
+// functionsIn returns the Function and Method nodes declared in a file.
+func (ix *index) functionsIn(ctx context.Context, filePath string) ([]*graph.Node, error) {
+	if fns, ok := ix.funcs[filePath]; ok {
+		return fns, nil
+	}
+	var fns []*graph.Node
+	for _, t := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+		nodes, err := ix.store.QueryNodes(ctx, graph.NodeFilter{Type: t, FilePath: filePath})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes in %s: %w", t, filePath, err)
+		}
+		fns = append(fns, nodes...)
+	}
+	ix.funcs[filePath] = fns
+	return fns, nil
+}
+
+// enclosing returns the innermost function containing line, or nil.
+func enclosing(fns []*graph.Node, line int) *graph.Node {
+	var best *graph.Node
+	for _, fn := range fns {
+		end := fn.EndLine
+		if end == 0 {
+			end = fn.Line
+		}
+		if line < fn.Line || line > end {
+			continue
+		}
+		if best == nil || fn.Line > best.Line {
+			best = fn
+		}
+	}
+	return best
+}
+
+// writeCovers replaces the Covers edges from src with edges to the files and
+// functions containing the given lines. It returns the number of edges written.
+func (ix *index) writeCovers(ctx context.Context, src *graph.Node, byFile map[string][]int, format Format) (int, error) {
+	existing, err := ix.store.GetEdges(ctx, src.ID, graph.EdgeCovers)
+	if err != nil {
+		return 0, fmt.Errorf("get covers edges for %s: %w", src.Name, err)
+	}
+	for _, e := range existing {
+		if e.SourceID != src.ID {
+			continue
+		}
+		if err := ix.store.DeleteEdge(ctx, e.ID); err != nil {
+			return 0, fmt.Errorf("delete covers edge %s: %w", e.ID, err)
+		}
+	}
+
+	counts := make(map[string]int) // target node ID -> covered line count
+	for gp, lines := range byFile {
+		fileNode, ok := ix.fileNodes[gp]
+		if !ok {
+			// Test files and unindexed paths are not coverage targets.
+			continue
+		}
+		fns, err := ix.functionsIn(ctx, gp)
+		if err != nil {
+			return 0, err
+		}
+		seen := make(map[int]struct{}, len(lines))
+		for _, l := range lines {
+			if _, dup := seen[l]; dup {
+				continue
+			}
+			seen[l] = struct{}{}
+			counts[fileNode.ID]++
+			if fn := enclosing(fns, l); fn != nil {
+				counts[fn.ID]++
+			}
+		}
+	}
+
+	for targetID, n := range counts {
+		edge := &graph.Edge{
+			ID:       graph.NewEdgeID(graph.EdgeCovers, src.ID, targetID),
+			Type:     graph.EdgeCovers,
+			SourceID: src.ID,
+			TargetID: targetID,
+			Properties: map[string]string{
+				"lines":  strconv.Itoa(n),
+				"source": string(format),
+			},
+		}
+		if err := ix.store.AddEdge(ctx, edge); err != nil {
+			return 0, fmt.Errorf("add covers edge: %w", err)
+		}
+	}
+	return len(counts), nil
+}
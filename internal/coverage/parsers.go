@@ -0,0 +1,244 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseGoProfile parses a `go test -coverprofile` file. Each block line has
+// the form "file.go:startLine.startCol,endLine.endCol numStmts count".
+func parseGoProfile(data []byte) (*Report, error) {
+	rep := &Report{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: missing file separator", lineNo)
+		}
+		file := line[:colon]
+		fields := strings.Fields(line[colon+1:])
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields, got %d", lineNo, len(fields))
+		}
+		start, end, ok := parseGoBlockRange(fields[0])
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid block range %q", lineNo, fields[0])
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid count %q", lineNo, fields[2])
+		}
+		fc := rep.file(file)
+		for l := start; l <= end; l++ {
+			fc.hit(l, count)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// parseGoBlockRange parses "startLine.startCol,endLine.endCol".
+func parseGoBlockRange(s string) (start, end int, ok bool) {
+	from, to, found := strings.Cut(s, ",")
+	if !found {
+		return 0, 0, false
+	}
+	startStr, _, _ := strings.Cut(from, ".")
+	endStr, _, _ := strings.Cut(to, ".")
+	start, err1 := strconv.Atoi(startStr)
+	end, err2 := strconv.Atoi(endStr)
+	if err1 != nil || err2 != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// parseLCOV parses an lcov tracefile. Records introduced by a non-empty TN
+// (test name) line are attributed to that test.
+func parseLCOV(data []byte) (*Report, error) {
+	rep := &Report{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var (
+		test string
+		fc   *FileCoverage
+	)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case strings.HasPrefix(line, "TN:"):
+			test = strings.TrimSpace(strings.TrimPrefix(line, "TN:"))
+		case strings.HasPrefix(line, "SF:"):
+			fc = rep.file(strings.TrimSpace(strings.TrimPrefix(line, "SF:")))
+		case strings.HasPrefix(line, "DA:"):
+			if fc == nil {
+				return nil, fmt.Errorf("line %d: DA record outside SF section", lineNo)
+			}
+			parts := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(parts) < 2 {
+				return nil, fmt.Errorf("line %d: invalid DA record", lineNo)
+			}
+			ln, err1 := strconv.Atoi(parts[0])
+			hits, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: invalid DA record", lineNo)
+			}
+			fc.hit(ln, hits)
+			if hits > 0 {
+				fc.hitByTest(test, ln)
+			}
+		case line == "end_of_record":
+			fc = nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// JaCoCo XML document types.
+type jacocoGroup struct {
+	Groups   []jacocoGroup   `xml:"group"`
+	Packages []jacocoPackage `xml:"package"`
+}
+
+type jacocoPackage struct {
+	Name        string             `xml:"name,attr"`
+	SourceFiles []jacocoSourceFile `xml:"sourcefile"`
+}
+
+type jacocoSourceFile struct {
+	Name  string       `xml:"name,attr"`
+	Lines []jacocoLine `xml:"line"`
+}
+
+type jacocoLine struct {
+	Number        int `xml:"nr,attr"`
+	CoveredInstrs int `xml:"ci,attr"`
+}
+
+// parseJaCoCo parses a JaCoCo XML report. Source paths are built from the
+// package name and source file name (e.g. "com/example/Foo.java").
+func parseJaCoCo(data []byte) (*Report, error) {
+	var root jacocoGroup
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+	rep := &Report{}
+	var walk func(g jacocoGroup)
+	walk = func(g jacocoGroup) {
+		for _, pkg := range g.Packages {
+			for _, sf := range pkg.SourceFiles {
+				path := sf.Name
+				if pkg.Name != "" {
+					path = pkg.Name + "/" + sf.Name
+				}
+				fc := rep.file(path)
+				for _, l := range sf.Lines {
+					fc.hit(l.Number, l.CoveredInstrs)
+				}
+			}
+		}
+		for _, sub := range g.Groups {
+			walk(sub)
+		}
+	}
+	walk(root)
+	return rep, nil
+}
+
+// Cobertura XML document types (coverage.py `coverage xml`).
+type coberturaReport struct {
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// coveragePyJSON is the `coverage json` document. Contexts are present when
+// the report was produced with --show-contexts.
+type coveragePyJSON struct {
+	Files map[string]struct {
+		ExecutedLines []int               `json:"executed_lines"`
+		MissingLines  []int               `json:"missing_lines"`
+		Contexts      map[string][]string `json:"contexts"`
+	} `json:"files"`
+}
+
+// parseCoveragePy parses coverage.py output in either JSON or Cobertura XML form.
+func parseCoveragePy(data []byte) (*Report, error) {
+	rep := &Report{}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var doc coveragePyJSON
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		for path, f := range doc.Files {
+			fc := rep.file(path)
+			for _, l := range f.MissingLines {
+				fc.hit(l, 0)
+			}
+			for _, l := range f.ExecutedLines {
+				fc.hit(l, 1)
+			}
+			for lineStr, contexts := range f.Contexts {
+				ln, err := strconv.Atoi(lineStr)
+				if err != nil {
+					continue
+				}
+				for _, c := range contexts {
+					fc.hitByTest(contextTestName(c), ln)
+				}
+			}
+		}
+		return rep, nil
+	}
+
+	var doc coberturaReport
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for _, pkg := range doc.Packages {
+		for _, cls := range pkg.Classes {
+			fc := rep.file(cls.Filename)
+			for _, l := range cls.Lines {
+				fc.hit(l.Number, l.Hits)
+			}
+		}
+	}
+	return rep, nil
+}
+
+// contextTestName converts a coverage.py dynamic context such as
+// "tests/test_api.py::test_create|run" into a test name. The empty
+// (global) context yields "".
+func contextTestName(ctx string) string {
+	name, _, _ := strings.Cut(ctx, "|")
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,167 @@
+// Package coverage parses test coverage reports (Go coverprofile, lcov,
+// JaCoCo XML, coverage.py) and links covered code to the tests that
+// exercised it via Covers edges in the knowledge graph.
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Format identifies a coverage report format.
+type Format string
+
+const (
+	FormatAuto       Format = "auto"
+	FormatGo         Format = "go"
+	FormatLCOV       Format = "lcov"
+	FormatJaCoCo     Format = "jacoco"
+	FormatCoveragePy Format = "coveragepy"
+)
+
+// Report is a parsed coverage report.
+type Report struct {
+	Format Format
+	// Files maps the file path as written in the report to its coverage.
+	Files map[string]*FileCoverage
+}
+
+// FileCoverage holds the line coverage for a single source file.
+type FileCoverage struct {
+	Path string
+	// Lines maps a 1-based line number to its hit count.
+	Lines map[int]int
+	// TestLines maps a test name to the lines it covered. It is only populated
+	// for reports that carry per-test attribution (lcov TN records,
+	// coverage.py contexts).
+	TestLines map[string]map[int]struct{}
+}
+
+// file returns the coverage entry for path, creating it if needed.
+func (r *Report) file(path string) *FileCoverage {
+	if r.Files == nil {
+		r.Files = make(map[string]*FileCoverage)
+	}
+	fc, ok := r.Files[path]
+	if !ok {
+		fc = &FileCoverage{Path: path, Lines: make(map[int]int)}
+		r.Files[path] = fc
+	}
+	return fc
+}
+
+// hit records hits for a line, keeping the highest count seen.
+func (fc *FileCoverage) hit(line, count int) {
+	if line <= 0 {
+		return
+	}
+	if cur, ok := fc.Lines[line]; !ok || count > cur {
+		fc.Lines[line] = count
+	}
+}
+
+// hitByTest records that the named test covered a line.
+func (fc *FileCoverage) hitByTest(test string, line int) {
+	if test == "" || line <= 0 {
+		return
+	}
+	if fc.TestLines == nil {
+		fc.TestLines = make(map[string]map[int]struct{})
+	}
+	lines, ok := fc.TestLines[test]
+	if !ok {
+		lines = make(map[int]struct{})
+		fc.TestLines[test] = lines
+	}
+	lines[line] = struct{}{}
+}
+
+// CoveredLines returns the lines with at least one hit.
+func (fc *FileCoverage) CoveredLines() []int {
+	var lines []int
+	for line, hits := range fc.Lines {
+		if hits > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// HasTestAttribution reports whether any file in the report carries per-test data.
+func (r *Report) HasTestAttribution() bool {
+	for _, fc := range r.Files {
+		if len(fc.TestLines) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFile reads and parses a coverage report from disk.
+func ParseFile(path string, format Format) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read coverage report: %w", err)
+	}
+	return Parse(bytes.NewReader(data), format)
+}
+
+// Parse parses a coverage report. When format is empty or FormatAuto the
+// format is detected from the content.
+func Parse(r io.Reader, format Format) (*Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read coverage report: %w", err)
+	}
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(data)
+		if format == "" {
+			return nil, fmt.Errorf("unrecognized coverage report format")
+		}
+	}
+
+	var rep *Report
+	switch format {
+	case FormatGo:
+		rep, err = parseGoProfile(data)
+	case FormatLCOV:
+		rep, err = parseLCOV(data)
+	case FormatJaCoCo:
+		rep, err = parseJaCoCo(data)
+	case FormatCoveragePy:
+		rep, err = parseCoveragePy(data)
+	default:
+		return nil, fmt.Errorf("unsupported coverage format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s report: %w", format, err)
+	}
+	rep.Format = format
+	return rep, nil
+}
+
+// DetectFormat guesses the report format from its content. It returns an
+// empty Format when the content is not recognized.
+func DetectFormat(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return FormatGo
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatCoveragePy
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		head := string(trimmed[:min(len(trimmed), 2048)])
+		if strings.Contains(head, "<report") || strings.Contains(head, "JACOCO") {
+			return FormatJaCoCo
+		}
+		if strings.Contains(head, "<coverage") {
+			return FormatCoveragePy
+		}
+	case bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		return FormatLCOV
+	}
+	return ""
+}
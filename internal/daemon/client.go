@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Client sends requests to a daemon over its socket, one at a time.
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	nextID  int
+}
+
+// Dial connects to the daemon listening on the unix socket at path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connect to daemon at %s: %w", path, err)
+	}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	return &Client{conn: conn, scanner: scanner}, nil
+}
+
+// Call sends method with params and decodes the result into result (which
+// may be nil). JSON-RPC errors are returned as *Error.
+func (c *Client) Call(method string, params, result any) error {
+	c.nextID++
+	req := Request{JSONRPC: "2.0", ID: json.RawMessage(strconv.Itoa(c.nextID)), Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("marshal params: %w", err)
+		}
+		req.Params = data
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	if _, err := c.conn.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("send %s: %w", method, err)
+	}
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return fmt.Errorf("read %s response: %w", method, err)
+		}
+		return fmt.Errorf("read %s response: connection closed", method)
+	}
+	var resp Response
+	if err := json.Unmarshal(c.scanner.Bytes(), &resp); err != nil {
+		return fmt.Errorf("decode %s response: %w", method, err)
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("decode %s result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,478 @@
+// Package daemon implements a long-running query server that keeps the graph
+// store open and caches results, serving newline-delimited JSON-RPC 2.0 over
+// a unix socket. Editor integrations use it to avoid paying the store open
+// cost on every query.
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/agents"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// SocketFile is the socket's file name in the .CodeEagle directory.
+const SocketFile = "daemon.sock"
+
+// DefaultCacheSize is how many results the daemon caches by default.
+const DefaultCacheSize = 1000
+
+// JSON-RPC error codes used by the daemon.
+const (
+	codeParseError     = -32700
+	codeInvalidParams  = -32602
+	codeMethodNotFound = -32601
+	codeInternalError  = -32603
+)
+
+// Request is a JSON-RPC 2.0 request.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // nil for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("daemon error %d: %s", e.Code, e.Message)
+}
+
+// Status is the result of a ping request.
+type Status struct {
+	PID       int   `json:"pid"`
+	UptimeMS  int64 `json:"uptime_ms"`
+	Requests  int64 `json:"requests"`
+	CacheHits int64 `json:"cache_hits"`
+	Cached    int   `json:"cached"`
+}
+
+// Config configures a Server.
+type Config struct {
+	Store       graph.Store
+	Registry    *agents.Registry // tools served by tools/list and tools/call; optional
+	CacheSize   int              // results kept (default DefaultCacheSize; negative disables caching)
+	IdleTimeout time.Duration    // exit after this long without requests (0 = never)
+	Logger      func(format string, args ...any)
+}
+
+// Server answers queries from one open graph store.
+type Server struct {
+	store    graph.Store
+	registry *agents.Registry
+	cache    *resultCache
+	idle     time.Duration
+	log      func(format string, args ...any)
+	started  time.Time
+
+	requests  atomic.Int64
+	cacheHits atomic.Int64
+	activity  chan struct{}
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewServer creates a Server from cfg.
+func NewServer(cfg Config) *Server {
+	size := cfg.CacheSize
+	if size == 0 {
+		size = DefaultCacheSize
+	}
+	logFn := cfg.Logger
+	if logFn == nil {
+		logFn = func(string, ...any) {}
+	}
+	registry := cfg.Registry
+	if registry == nil {
+		registry = agents.NewRegistry()
+	}
+	return &Server{
+		store:    cfg.Store,
+		registry: registry,
+		cache:    newResultCache(size),
+		idle:     cfg.IdleTimeout,
+		log:      logFn,
+		started:  time.Now(),
+		activity: make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// ListenAndServe serves on a unix socket at path until ctx is done, a
+// shutdown request arrives, or the idle timeout passes. A stale socket
+// file left by a crashed daemon is replaced; a live one is an error.
+func (s *Server) ListenAndServe(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return fmt.Errorf("a daemon is already listening on %s", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("remove stale socket %s: %w", path, err)
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", path, err)
+	}
+	defer os.Remove(path)
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return fmt.Errorf("restrict socket %s: %w", path, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is done, a shutdown request
+// arrives, or the idle timeout passes. It closes ln.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		var idle <-chan time.Time
+		var timer *time.Timer
+		if s.idle > 0 {
+			timer = time.NewTimer(s.idle)
+			defer timer.Stop()
+			idle = timer.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+			case <-s.stop:
+			case <-idle:
+				s.log("daemon: idle for %s, exiting", s.idle)
+			case <-s.activity:
+				if timer != nil {
+					if !timer.Stop() {
+						<-timer.C
+					}
+					timer.Reset(s.idle)
+				}
+				continue
+			}
+			cancel()
+			ln.Close()
+			return
+		}
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// Shutdown stops Serve.
+func (s *Server) Shutdown() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// serveConn answers requests on one connection, one JSON object per line,
+// in order.
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	w := bufio.NewWriter(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var resp *Response
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			resp = errorResponse(nil, codeParseError, "Parse error: "+err.Error())
+		} else {
+			resp = s.handle(ctx, &req)
+		}
+		if resp != nil {
+			data, _ := json.Marshal(resp)
+			w.Write(data)
+			w.WriteByte('\n')
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+		// Stop only once the shutdown request has been answered.
+		if req.Method == "shutdown" {
+			s.Shutdown()
+		}
+	}
+}
+
+// handle answers one request, from the cache when possible. It returns nil
+// for notifications.
+func (s *Server) handle(ctx context.Context, req *Request) *Response {
+	s.requests.Add(1)
+	select {
+	case s.activity <- struct{}{}:
+	default:
+	}
+	s.log("daemon: %s", req.Method)
+
+	var (
+		result any
+		err    error
+	)
+	cacheable := false
+	switch req.Method {
+	case "ping":
+		result = s.status()
+	case "shutdown":
+		// Acted on by serveConn after the response is sent.
+	case "cache/clear":
+		s.cache.clear()
+	default:
+		cacheable = true
+	}
+
+	key := req.Method + "\x00" + compact(req.Params)
+	if cacheable {
+		if req.ID == nil {
+			return nil // a query nobody waits for
+		}
+		if data, ok := s.cache.get(key); ok {
+			s.cacheHits.Add(1)
+			return resultResponse(req.ID, data)
+		}
+		result, err = s.query(ctx, req)
+	}
+	if req.ID == nil {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			return errorResponse(req.ID, rpcErr.Code, rpcErr.Message)
+		}
+		return errorResponse(req.ID, codeInternalError, err.Error())
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, codeInternalError, "marshal result: "+err.Error())
+	}
+	if cacheable {
+		s.cache.put(key, data)
+	}
+	return resultResponse(req.ID, data)
+}
+
+func (s *Server) status() Status {
+	return Status{
+		PID:       os.Getpid(),
+		UptimeMS:  time.Since(s.started).Milliseconds(),
+		Requests:  s.requests.Load(),
+		CacheHits: s.cacheHits.Load(),
+		Cached:    s.cache.len(),
+	}
+}
+
+// query runs a cacheable read request.
+func (s *Server) query(ctx context.Context, req *Request) (any, error) {
+	switch req.Method {
+	case "graph/node":
+		var p struct {
+			ID string `json:"id"`
+		}
+		if err := decodeParams(req, &p); err != nil {
+			return nil, err
+		}
+		return s.store.GetNode(ctx, p.ID)
+	case "graph/query":
+		var filter graph.NodeFilter
+		if err := decodeParams(req, &filter); err != nil {
+			return nil, err
+		}
+		return s.store.QueryNodes(ctx, filter)
+	case "graph/edges":
+		var p struct {
+			ID   string         `json:"id"`
+			Type graph.EdgeType `json:"type"`
+		}
+		if err := decodeParams(req, &p); err != nil {
+			return nil, err
+		}
+		return s.store.GetEdges(ctx, p.ID, p.Type)
+	case "graph/neighbors":
+		var p struct {
+			ID        string         `json:"id"`
+			Type      graph.EdgeType `json:"type"`
+			Direction string         `json:"direction"` // "out" (default), "in", or "both"
+		}
+		if err := decodeParams(req, &p); err != nil {
+			return nil, err
+		}
+		dir := graph.Outgoing
+		switch p.Direction {
+		case "", "out":
+		case "in":
+			dir = graph.Incoming
+		case "both":
+			dir = graph.Both
+		default:
+			return nil, &Error{Code: codeInvalidParams, Message: fmt.Sprintf("direction must be in, out, or both, got %q", p.Direction)}
+		}
+		return s.store.GetNeighbors(ctx, p.ID, p.Type, dir)
+	case "graph/at":
+		var p struct {
+			File  string           `json:"file"`
+			Line  int              `json:"line"`
+			Types []graph.NodeType `json:"types,omitempty"`
+		}
+		if err := decodeParams(req, &p); err != nil {
+			return nil, err
+		}
+		return graph.NodesAt(ctx, s.store, p.File, p.Line, p.Types...)
+	case "graph/stats":
+		return s.store.Stats(ctx)
+	case "tools/list":
+		return map[string]any{"tools": s.registry.Definitions()}, nil
+	case "tools/call":
+		var p struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := decodeParams(req, &p); err != nil {
+			return nil, err
+		}
+		text, success, err := s.registry.Execute(ctx, p.Name, p.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"text": text, "isError": !success}, nil
+	}
+	return nil, &Error{Code: codeMethodNotFound, Message: "Method not found: " + req.Method}
+}
+
+func decodeParams(req *Request, v any) error {
+	if len(req.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(req.Params, v); err != nil {
+		return &Error{Code: codeInvalidParams, Message: "Invalid params: " + err.Error()}
+	}
+	return nil
+}
+
+func compact(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+func resultResponse(id, data json.RawMessage) *Response {
+	return &Response{JSONRPC: "2.0", ID: id, Result: data}
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *Response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
+
+// resultCache is a size-bounded LRU of marshaled results.
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front = most recently used
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key  string
+	data json.RawMessage
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *resultCache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).data, true
+}
+
+func (c *resultCache) put(key string, data json.RawMessage) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheEntry).data = data
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, data: data})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.entries)
+}
+
+func (c *resultCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
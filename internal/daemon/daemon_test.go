@@ -0,0 +1,175 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+// startDaemon serves a store with two nodes on a fresh socket and returns
+// its path and a channel receiving ListenAndServe's result.
+func startDaemon(t *testing.T, cfg Config) (string, <-chan error) {
+	t.Helper()
+	ctx := context.Background()
+	store, err := embedded.NewStore(filepath.Join(t.TempDir(), "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	for _, n := range []*graph.Node{
+		{ID: "a", Type: graph.NodeFunction, Name: "Alpha", FilePath: "a.go", Line: 1, EndLine: 10},
+		{ID: "b", Type: graph.NodeFunction, Name: "Beta", FilePath: "a.go", Line: 12, EndLine: 20},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddEdge(ctx, &graph.Edge{ID: "e", Type: graph.EdgeCalls, SourceID: "a", TargetID: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unix socket paths are length-limited, so avoid the long test temp dir.
+	dir, err := os.MkdirTemp("", "ced")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, SocketFile)
+
+	cfg.Store = store
+	srv := NewServer(cfg)
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe(ctx, path) }()
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Cleanup(srv.Shutdown)
+	return path, done
+}
+
+func dial(t *testing.T, path string) *Client {
+	t.Helper()
+	c, err := Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestDaemonQueriesAndCache(t *testing.T) {
+	path, _ := startDaemon(t, Config{})
+	c := dial(t, path)
+
+	var node graph.Node
+	if err := c.Call("graph/node", map[string]string{"id": "a"}, &node); err != nil {
+		t.Fatal(err)
+	}
+	if node.Name != "Alpha" {
+		t.Errorf("graph/node = %+v, want Alpha", node)
+	}
+
+	for i := 0; i < 2; i++ {
+		var nodes []*graph.Node
+		if err := c.Call("graph/query", graph.NodeFilter{FilePath: "a.go"}, &nodes); err != nil {
+			t.Fatal(err)
+		}
+		if len(nodes) != 2 {
+			t.Fatalf("graph/query returned %d nodes, want 2", len(nodes))
+		}
+	}
+	var neighbors []*graph.Node
+	if err := c.Call("graph/neighbors", map[string]string{"id": "b", "type": "Calls", "direction": "in"}, &neighbors); err != nil {
+		t.Fatal(err)
+	}
+	if len(neighbors) != 1 || neighbors[0].ID != "a" {
+		t.Errorf("graph/neighbors = %+v, want a", neighbors)
+	}
+	var at []*graph.Node
+	if err := c.Call("graph/at", map[string]any{"file": "a.go", "line": 15}, &at); err != nil {
+		t.Fatal(err)
+	}
+	if len(at) != 1 || at[0].ID != "b" {
+		t.Errorf("graph/at = %+v, want b", at)
+	}
+
+	var st Status
+	if err := c.Call("ping", nil, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.CacheHits != 1 || st.Cached != 4 || st.PID != os.Getpid() {
+		t.Errorf("status = %+v, want 1 cache hit and 4 cached results", st)
+	}
+	if err := c.Call("cache/clear", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Call("ping", nil, &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Cached != 0 {
+		t.Errorf("cached = %d after cache/clear, want 0", st.Cached)
+	}
+
+	var rpcErr *Error
+	if err := c.Call("graph/frobnicate", nil, nil); !errors.As(err, &rpcErr) || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("unknown method err = %v, want method not found", err)
+	}
+	if err := c.Call("graph/neighbors", map[string]string{"id": "b", "direction": "up"}, nil); !errors.As(err, &rpcErr) || rpcErr.Code != codeInvalidParams {
+		t.Errorf("bad direction err = %v, want invalid params", err)
+	}
+}
+
+func TestDaemonShutdown(t *testing.T) {
+	path, done := startDaemon(t, Config{})
+	if err := dial(t, path).Call("shutdown", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ListenAndServe = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon still running after shutdown")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket left behind after shutdown: %v", err)
+	}
+}
+
+func TestDaemonIdleTimeout(t *testing.T) {
+	_, done := startDaemon(t, Config{IdleTimeout: 100 * time.Millisecond})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ListenAndServe = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("daemon did not exit when idle")
+	}
+}
+
+func TestResultCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newResultCache(2)
+	c.put("a", []byte("1"))
+	c.put("b", []byte("2"))
+	c.get("a")
+	c.put("c", []byte("3"))
+	if _, ok := c.get("b"); ok {
+		t.Error("b still cached, want it evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.get(k); !ok {
+			t.Errorf("%s evicted, want it cached", k)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Package diagram renders views of the knowledge graph as Mermaid or
+// PlantUML diagram source for embedding in documentation.
+package diagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Format is a diagram source language.
+type Format string
+
+const (
+	FormatMermaid  Format = "mermaid"
+	FormatPlantUML Format = "plantuml"
+)
+
+// Node kinds, which select the shape a node is drawn with.
+const (
+	KindService  = "service"
+	KindEndpoint = "endpoint"
+	KindFunction = "function"
+)
+
+// Diagram is a directed graph of labeled nodes, optionally grouped into
+// boxes (services, classes).
+type Diagram struct {
+	Title string
+	Nodes []Node
+	Edges []Edge
+}
+
+// Node is a diagram node. Key identifies it within the diagram; nodes with
+// the same Group are drawn together in a box labeled with the group.
+type Node struct {
+	Key   string
+	Label string
+	Kind  string
+	Group string
+}
+
+// Edge is a directed edge between node keys.
+type Edge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// builder accumulates nodes and edges, dropping duplicates.
+type builder struct {
+	d     *Diagram
+	nodes map[string]bool
+	edges map[Edge]bool
+}
+
+func newBuilder(title string) *builder {
+	return &builder{d: &Diagram{Title: title}, nodes: make(map[string]bool), edges: make(map[Edge]bool)}
+}
+
+func (b *builder) node(n Node) {
+	if !b.nodes[n.Key] {
+		b.nodes[n.Key] = true
+		b.d.Nodes = append(b.d.Nodes, n)
+	}
+}
+
+func (b *builder) edge(e Edge) {
+	if !b.edges[e] {
+		b.edges[e] = true
+		b.d.Edges = append(b.d.Edges, e)
+	}
+}
+
+// diagram returns the result with nodes and edges in a stable order, so the
+// generated source diffs cleanly when committed to docs.
+func (b *builder) diagram() *Diagram {
+	sort.SliceStable(b.d.Nodes, func(i, j int) bool {
+		a, c := b.d.Nodes[i], b.d.Nodes[j]
+		if a.Group != c.Group {
+			return a.Group < c.Group
+		}
+		return a.Label < c.Label
+	})
+	pos := make(map[string]int, len(b.d.Nodes))
+	for i, n := range b.d.Nodes {
+		pos[n.Key] = i
+	}
+	sort.SliceStable(b.d.Edges, func(i, j int) bool {
+		a, c := b.d.Edges[i], b.d.Edges[j]
+		if pos[a.From] != pos[c.From] {
+			return pos[a.From] < pos[c.From]
+		}
+		return pos[a.To] < pos[c.To]
+	})
+	return b.d
+}
+
+// Render returns the diagram source in the given format.
+func Render(d *Diagram, format Format) (string, error) {
+	switch format {
+	case FormatMermaid:
+		return Mermaid(d), nil
+	case FormatPlantUML:
+		return PlantUML(d), nil
+	default:
+		return "", fmt.Errorf("unknown diagram format %q (want mermaid or plantuml)", format)
+	}
+}
+
+// aliases assigns short identifiers (n0, n1, ...) to node keys, which may
+// contain characters neither language accepts in identifiers.
+func aliases(d *Diagram) map[string]string {
+	ids := make(map[string]string, len(d.Nodes))
+	for i, n := range d.Nodes {
+		ids[n.Key] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+// groups returns the distinct non-empty groups in node order.
+func groups(d *Diagram) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, n := range d.Nodes {
+		if n.Group != "" && !seen[n.Group] {
+			seen[n.Group] = true
+			out = append(out, n.Group)
+		}
+	}
+	return out
+}
+
+// Mermaid renders the diagram as a Mermaid flowchart.
+func Mermaid(d *Diagram) string {
+	var sb strings.Builder
+	if d.Title != "" {
+		fmt.Fprintf(&sb, "---\ntitle: %s\n---\n", d.Title)
+	}
+	sb.WriteString("flowchart LR\n")
+	ids := aliases(d)
+
+	writeNode := func(indent string, n Node) {
+		label := mermaidEscape(n.Label)
+		switch n.Kind {
+		case KindService:
+			fmt.Fprintf(&sb, "%s%s[[\"%s\"]]\n", indent, ids[n.Key], label)
+		case KindEndpoint:
+			fmt.Fprintf(&sb, "%s%s([\"%s\"])\n", indent, ids[n.Key], label)
+		default:
+			fmt.Fprintf(&sb, "%s%s[\"%s\"]\n", indent, ids[n.Key], label)
+		}
+	}
+	for _, n := range d.Nodes {
+		if n.Group == "" {
+			writeNode("  ", n)
+		}
+	}
+	for i, g := range groups(d) {
+		fmt.Fprintf(&sb, "  subgraph g%d[\"%s\"]\n", i, mermaidEscape(g))
+		for _, n := range d.Nodes {
+			if n.Group == g {
+				writeNode("    ", n)
+			}
+		}
+		sb.WriteString("  end\n")
+	}
+	for _, e := range d.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&sb, "  %s -->|\"%s\"| %s\n", ids[e.From], mermaidEscape(e.Label), ids[e.To])
+		} else {
+			fmt.Fprintf(&sb, "  %s --> %s\n", ids[e.From], ids[e.To])
+		}
+	}
+	return sb.String()
+}
+
+// mermaidEscape makes a label safe inside double quotes.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
+
+// PlantUML renders the diagram as a PlantUML component diagram.
+func PlantUML(d *Diagram) string {
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	if d.Title != "" {
+		fmt.Fprintf(&sb, "title %s\n", d.Title)
+	}
+	sb.WriteString("left to right direction\n")
+	ids := aliases(d)
+
+	writeNode := func(indent string, n Node) {
+		element := "rectangle"
+		switch n.Kind {
+		case KindService:
+			element = "component"
+		case KindEndpoint:
+			element = "interface"
+		}
+		fmt.Fprintf(&sb, "%s%s \"%s\" as %s\n", indent, element, plantUMLEscape(n.Label), ids[n.Key])
+	}
+	for _, n := range d.Nodes {
+		if n.Group == "" {
+			writeNode("", n)
+		}
+	}
+	for _, g := range groups(d) {
+		fmt.Fprintf(&sb, "package \"%s\" {\n", plantUMLEscape(g))
+		for _, n := range d.Nodes {
+			if n.Group == g {
+				writeNode("  ", n)
+			}
+		}
+		sb.WriteString("}\n")
+	}
+	for _, e := range d.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&sb, "%s --> %s : %s\n", ids[e.From], ids[e.To], plantUMLEscape(e.Label))
+		} else {
+			fmt.Fprintf(&sb, "%s --> %s\n", ids[e.From], ids[e.To])
+		}
+	}
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+// plantUMLEscape makes a label safe inside double quotes.
+func plantUMLEscape(s string) string {
+	return strings.NewReplacer(`"`, "'", "\n", " ").Replace(s)
+}
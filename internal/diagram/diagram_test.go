@@ -0,0 +1,159 @@
+package diagram
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func newTestStore(t *testing.T) graph.Store {
+	t.Helper()
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	nodes := []*graph.Node{
+		{ID: "svc-web", Type: graph.NodeService, Name: "web"},
+		{ID: "svc-orders", Type: graph.NodeService, Name: "orders"},
+		{ID: "svc-users", Type: graph.NodeService, Name: "users"},
+		{ID: "ep-list", Type: graph.NodeAPIEndpoint, Name: "GET /orders", FilePath: "orders/api.go"},
+		{ID: "ep-get", Type: graph.NodeAPIEndpoint, Name: `GET /orders/{id} "v2"`, FilePath: "orders/api.go"},
+		{ID: "call-list", Type: graph.NodeDependency, Name: "GET /orders", FilePath: "web/client.go"},
+		{ID: "call-self", Type: graph.NodeDependency, Name: "GET /orders", FilePath: "orders/worker.go"},
+		{ID: "type-svc", Type: graph.NodeStruct, Name: "OrderService", FilePath: "orders/service.go", Package: "orders"},
+		{ID: "m-create", Type: graph.NodeMethod, Name: "Create", FilePath: "orders/service.go", Package: "orders", Properties: map[string]string{"receiver": "OrderService"}},
+		{ID: "m-validate", Type: graph.NodeMethod, Name: "validate", FilePath: "orders/validate.go", Package: "orders", Properties: map[string]string{"receiver": "OrderService"}},
+		{ID: "m-other", Type: graph.NodeMethod, Name: "Create", FilePath: "users/service.go", Package: "orders", Properties: map[string]string{"receiver": "OrderService"}},
+		{ID: "f-handler", Type: graph.NodeFunction, Name: "createHandler", FilePath: "orders/api.go", Package: "orders"},
+		{ID: "f-save", Type: graph.NodeFunction, Name: "save", FilePath: "orders/db.go", Package: "orders"},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	edges := []*graph.Edge{
+		{ID: "d1", Type: graph.EdgeDependsOn, SourceID: "svc-web", TargetID: "svc-orders", Properties: map[string]string{"kind": "api_dependency"}},
+		{ID: "d2", Type: graph.EdgeDependsOn, SourceID: "svc-orders", TargetID: "svc-users", Properties: map[string]string{"kind": "package"}},
+		{ID: "c1", Type: graph.EdgeConsumes, SourceID: "call-list", TargetID: "ep-list"},
+		{ID: "c2", Type: graph.EdgeConsumes, SourceID: "call-self", TargetID: "ep-list"},
+		{ID: "k1", Type: graph.EdgeCalls, SourceID: "f-handler", TargetID: "m-create"},
+		{ID: "k2", Type: graph.EdgeCalls, SourceID: "m-create", TargetID: "m-validate"},
+		{ID: "k3", Type: graph.EdgeCalls, SourceID: "m-create", TargetID: "f-save"},
+		{ID: "k4", Type: graph.EdgeCalls, SourceID: "m-create", TargetID: "unresolved"},
+	}
+	for _, e := range edges {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func edgeLabels(d *Diagram) []string {
+	label := make(map[string]string)
+	for _, n := range d.Nodes {
+		label[n.Key] = n.Label
+	}
+	var out []string
+	for _, e := range d.Edges {
+		out = append(out, label[e.From]+"->"+label[e.To])
+	}
+	return out
+}
+
+func TestViews(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		build func() (*Diagram, error)
+		nodes int
+		edges string
+	}{
+		{"services all", func() (*Diagram, error) { return Services(ctx, store, AllScope) }, 3, "orders->users,web->orders"},
+		{"services scoped", func() (*Diagram, error) { return Services(ctx, store, "web") }, 2, "web->orders"},
+		{"endpoints", func() (*Diagram, error) { return Endpoints(ctx, store, "orders") }, 3, "web->GET /orders"},
+		{"class calls", func() (*Diagram, error) { return Calls(ctx, store, "OrderService") }, 4, "createHandler->Create,Create->save,Create->validate"},
+		{"method calls", func() (*Diagram, error) { return Calls(ctx, store, "f-save") }, 2, "OrderService.Create->save"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(d.Nodes) != tt.nodes {
+				t.Errorf("nodes = %+v, want %d", d.Nodes, tt.nodes)
+			}
+			if got := strings.Join(edgeLabels(d), ","); got != tt.edges {
+				t.Errorf("edges = %s, want %s", got, tt.edges)
+			}
+		})
+	}
+
+	for _, bad := range []func() (*Diagram, error){
+		func() (*Diagram, error) { return Services(ctx, store, "billing") },
+		func() (*Diagram, error) { return Endpoints(ctx, store, "billing") },
+		func() (*Diagram, error) { return Calls(ctx, store, "Missing") },
+		func() (*Diagram, error) { return Calls(ctx, store, "Create") }, // ambiguous
+	} {
+		if _, err := bad(); err == nil {
+			t.Error("expected error")
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	store := newTestStore(t)
+	d, err := Endpoints(context.Background(), store, AllScope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mermaid, err := Render(d, FormatMermaid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"title: Endpoint consumers",
+		"flowchart LR",
+		`n0[["web"]]`,
+		`subgraph g0["orders"]`,
+		`    n1(["GET /orders"])`,
+		`    n2(["GET /orders/{id} #quot;v2#quot;"])`,
+		"n0 --> n1",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	plantuml, err := Render(d, FormatPlantUML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"@startuml",
+		`component "web" as n0`,
+		`package "orders" {`,
+		`  interface "GET /orders/{id} 'v2'" as n2`,
+		"n0 --> n1",
+		"@enduml",
+	} {
+		if !strings.Contains(plantuml, want) {
+			t.Errorf("plantuml missing %q:\n%s", want, plantuml)
+		}
+	}
+
+	if _, err := Render(d, "graphviz"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
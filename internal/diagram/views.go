@@ -0,0 +1,259 @@
+package diagram
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// AllScope selects every service.
+const AllScope = "all"
+
+// Services builds the service dependency diagram from the service-level
+// DependsOn edges. A scope other than "" or "all" restricts the diagram to
+// that service and its direct dependencies and dependents.
+func Services(ctx context.Context, store graph.Store, scope string) (*Diagram, error) {
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query services: %w", err)
+	}
+	byID := make(map[string]*graph.Node, len(services))
+	found := false
+	for _, svc := range services {
+		byID[svc.ID] = svc
+		found = found || svc.Name == scope
+	}
+	all := scope == "" || scope == AllScope
+	if !all && !found {
+		return nil, fmt.Errorf("service %q not found", scope)
+	}
+
+	title := "Service dependencies"
+	if !all {
+		title += ": " + scope
+	}
+	b := newBuilder(title)
+	for _, svc := range services {
+		if all || svc.Name == scope {
+			b.node(serviceNode(svc.Name))
+		}
+		edges, err := store.GetEdges(ctx, svc.ID, graph.EdgeDependsOn)
+		if err != nil {
+			return nil, fmt.Errorf("get dependencies of %s: %w", svc.Name, err)
+		}
+		for _, e := range edges {
+			target := byID[e.TargetID]
+			if e.SourceID != svc.ID || target == nil || target.Name == svc.Name {
+				continue
+			}
+			if !all && svc.Name != scope && target.Name != scope {
+				continue
+			}
+			b.node(serviceNode(svc.Name))
+			b.node(serviceNode(target.Name))
+			b.edge(Edge{From: serviceKey(svc.Name), To: serviceKey(target.Name), Label: strings.TrimSuffix(e.Properties["kind"], "_dependency")})
+		}
+	}
+	return b.diagram(), nil
+}
+
+// Endpoints builds the diagram of API endpoints, grouped by service, and the
+// other services consuming them (from the linker's Consumes edges). A scope
+// other than "" or "all" restricts it to the endpoints of that service.
+func Endpoints(ctx context.Context, store graph.Store, scope string) (*Diagram, error) {
+	endpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return nil, fmt.Errorf("query endpoints: %w", err)
+	}
+	all := scope == "" || scope == AllScope
+
+	title := "Endpoint consumers"
+	if !all {
+		title += ": " + scope
+	}
+	b := newBuilder(title)
+	for _, ep := range endpoints {
+		owner := linker.ServiceGroup(ep.FilePath)
+		if !all && owner != scope {
+			continue
+		}
+		b.node(Node{Key: ep.ID, Label: ep.Name, Kind: KindEndpoint, Group: owner})
+
+		edges, err := store.GetEdges(ctx, ep.ID, graph.EdgeConsumes)
+		if err != nil {
+			return nil, fmt.Errorf("get consumers of %s: %w", ep.Name, err)
+		}
+		for _, e := range edges {
+			if e.TargetID != ep.ID {
+				continue
+			}
+			call, err := store.GetNode(ctx, e.SourceID)
+			if err != nil {
+				continue
+			}
+			consumer := linker.ServiceGroup(call.FilePath)
+			if consumer == owner {
+				continue // internal call
+			}
+			b.node(serviceNode(consumer))
+			b.edge(Edge{From: serviceKey(consumer), To: ep.ID})
+		}
+	}
+	if !all && len(b.d.Nodes) == 0 {
+		return nil, fmt.Errorf("no endpoints found for service %q", scope)
+	}
+	return b.diagram(), nil
+}
+
+// Calls builds the call neighborhood of a class (its methods with their
+// callers and callees) or of a single function or method. name is a node ID,
+// a type name, or a function name; methods may be given as Type.method.
+func Calls(ctx context.Context, store graph.Store, name string) (*Diagram, error) {
+	focus, group, err := resolveFocus(ctx, store, name)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newBuilder("Call neighborhood: " + name)
+	for _, f := range focus {
+		b.node(Node{Key: f.ID, Label: f.Name, Kind: KindFunction, Group: group})
+	}
+	for _, f := range focus {
+		edges, err := store.GetEdges(ctx, f.ID, graph.EdgeCalls)
+		if err != nil {
+			return nil, fmt.Errorf("get calls of %s: %w", f.Name, err)
+		}
+		for _, e := range edges {
+			other := e.TargetID
+			if e.TargetID == f.ID {
+				other = e.SourceID
+			}
+			n, err := store.GetNode(ctx, other)
+			if err != nil {
+				continue // unresolved call target
+			}
+			if n.ID != f.ID {
+				b.node(Node{Key: n.ID, Label: qualifiedLabel(n), Kind: KindFunction})
+			}
+			b.edge(Edge{From: e.SourceID, To: e.TargetID})
+		}
+	}
+	return b.diagram(), nil
+}
+
+// resolveFocus returns the functions a call diagram centers on, and the
+// class grouping them ("" for a single function).
+func resolveFocus(ctx context.Context, store graph.Store, name string) ([]*graph.Node, string, error) {
+	if n, err := store.GetNode(ctx, name); err == nil {
+		switch n.Type {
+		case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
+			return []*graph.Node{n}, "", nil
+		case graph.NodeClass, graph.NodeStruct, graph.NodeInterface:
+			methods, err := methodsOf(ctx, store, n)
+			return methods, n.Name, err
+		}
+	}
+
+	var types []*graph.Node
+	for _, t := range []graph.NodeType{graph.NodeClass, graph.NodeStruct, graph.NodeInterface} {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: t, NamePattern: name})
+		if err != nil {
+			return nil, "", fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		types = append(types, nodes...)
+	}
+	switch len(types) {
+	case 0:
+	case 1:
+		methods, err := methodsOf(ctx, store, types[0])
+		if err != nil {
+			return nil, "", err
+		}
+		if len(methods) == 0 {
+			return nil, "", fmt.Errorf("type %s has no methods", name)
+		}
+		return methods, types[0].Name, nil
+	default:
+		return nil, "", ambiguous(name, types)
+	}
+
+	owner, fn, isMethod := strings.Cut(name, ".")
+	if !isMethod {
+		fn = name
+	}
+	var funcs []*graph.Node
+	for _, t := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: t, NamePattern: fn})
+		if err != nil {
+			return nil, "", fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		for _, n := range nodes {
+			if !isMethod || methodOwner(n) == owner {
+				funcs = append(funcs, n)
+			}
+		}
+	}
+	switch len(funcs) {
+	case 0:
+		return nil, "", fmt.Errorf("no class or function named %q", name)
+	case 1:
+		return funcs, "", nil
+	default:
+		return nil, "", ambiguous(name, funcs)
+	}
+}
+
+// methodsOf returns the methods declared on a type: those in its directory
+// (Go methods may live in other files of the package) whose class or
+// receiver is the type.
+func methodsOf(ctx context.Context, store graph.Store, typ *graph.Node) ([]*graph.Node, error) {
+	filter := graph.NodeFilter{Type: graph.NodeMethod, FilePath: typ.FilePath}
+	if typ.Package != "" {
+		filter = graph.NodeFilter{Type: graph.NodeMethod, Package: typ.Package}
+	}
+	nodes, err := store.QueryNodes(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("query methods of %s: %w", typ.Name, err)
+	}
+	var methods []*graph.Node
+	dir := filepath.Dir(typ.FilePath)
+	for _, n := range nodes {
+		if methodOwner(n) == typ.Name && filepath.Dir(n.FilePath) == dir {
+			methods = append(methods, n)
+		}
+	}
+	return methods, nil
+}
+
+// methodOwner returns the class or receiver type of a method.
+func methodOwner(n *graph.Node) string {
+	if c := n.Properties["class"]; c != "" {
+		return c
+	}
+	return n.Properties["receiver"]
+}
+
+func qualifiedLabel(n *graph.Node) string {
+	if owner := methodOwner(n); owner != "" {
+		return owner + "." + n.Name
+	}
+	return n.Name
+}
+
+func ambiguous(name string, nodes []*graph.Node) error {
+	var candidates []string
+	for _, n := range nodes {
+		candidates = append(candidates, fmt.Sprintf("%s (%s)", n.ID, n.FilePath))
+	}
+	return fmt.Errorf("%q is ambiguous; pass a node ID: %s", name, strings.Join(candidates, ", "))
+}
+
+func serviceKey(name string) string { return "service:" + name }
+
+func serviceNode(name string) Node {
+	return Node{Key: serviceKey(name), Label: name, Kind: KindService}
+}
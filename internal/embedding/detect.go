@@ -18,7 +18,8 @@ type ollamaTagsResponse struct {
 }
 
 // DetectProvider checks available embedding providers in priority order.
-// Priority: 1. Ollama (local) 2. Vertex AI (cloud) 3. nil (disabled)
+// Priority: 1. Ollama (local) 2. llama.cpp (local) 3. Vertex AI (cloud)
+// 4. nil (disabled)
 // Returns nil with no error if no provider is available.
 func DetectProvider(cfg *appconfig.Config) (Provider, error) {
 	embCfg := embeddingConfigFromApp(cfg)
@@ -37,6 +38,11 @@ func DetectProvider(cfg *appconfig.Config) (Provider, error) {
 		return p, nil
 	}
 
+	// Then a llama.cpp (or other OpenAI-compatible) embedding server.
+	if p, err := tryLlamaCpp(embCfg); err == nil && p != nil {
+		return p, nil
+	}
+
 	// Try Vertex AI.
 	if cfg.Agents.Project != "" && cfg.Agents.Location != "" {
 		embCfg.Provider = "vertex-ai"
@@ -100,6 +106,23 @@ func tryOllama(cfg Config) (Provider, error) {
 	return nil, fmt.Errorf("ollama model %q not found", model)
 }
 
+// tryLlamaCpp checks if a llama.cpp server with embeddings enabled is
+// running by embedding a short probe text.
+func tryLlamaCpp(cfg Config) (Provider, error) {
+	cfg.Provider = "llamacpp"
+	p, err := NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := p.EmbedQuery(ctx, "probe"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 // matchesModelName checks if a pulled model name matches the target.
 // Ollama model names may include tags like ":latest", so "nomic-embed-text-v2-moe"
 // matches "nomic-embed-text-v2-moe:latest".
@@ -121,7 +144,9 @@ func embeddingConfigFromApp(cfg *appconfig.Config) Config {
 	return Config{
 		Provider:        cfg.Agents.EmbeddingProvider,
 		Model:           cfg.Agents.EmbeddingModel,
-		OllamaBaseURL:   "", // use default
+		Dimensions:      cfg.Agents.EmbeddingDimensions,
+		OllamaBaseURL:   cfg.Agents.EmbeddingBaseURL,
+		LlamaCppBaseURL: cfg.Agents.EmbeddingBaseURL,
 		Project:         cfg.Agents.Project,
 		Location:        cfg.Agents.Location,
 		CredentialsFile: cfg.Agents.CredentialsFile,
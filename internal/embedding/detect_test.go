@@ -65,7 +65,7 @@ func TestDetectProviderAutoDetect(t *testing.T) {
 	}
 	// If Ollama is running locally, it will be detected. Otherwise nil.
 	if p != nil {
-		if p.Name() != "ollama" && p.Name() != "vertex-ai" {
+		if p.Name() != "ollama" && p.Name() != "llamacpp" && p.Name() != "vertex-ai" {
 			t.Errorf("unexpected provider: %q", p.Name())
 		}
 		t.Logf("auto-detected provider: %s/%s", p.Name(), p.ModelName())
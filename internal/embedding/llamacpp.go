@@ -0,0 +1,201 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultLlamaCppBaseURL = "http://localhost:8080"
+
+// llamaCppProvider embeds text with a llama.cpp server started with
+// --embeddings, through its OpenAI-compatible /v1/embeddings endpoint. Any
+// server speaking that API (LocalAI, vLLM, LM Studio) works the same way.
+type llamaCppProvider struct {
+	baseURL string
+	dims    dimsTracker
+	client  *http.Client
+
+	mu          sync.Mutex
+	model       string
+	docPrefix   string
+	queryPrefix string
+}
+
+func init() {
+	RegisterProvider("llamacpp", newLlamaCppProvider)
+}
+
+func newLlamaCppProvider(cfg Config) (Provider, error) {
+	baseURL := cfg.LlamaCppBaseURL
+	if baseURL == "" {
+		baseURL = defaultLlamaCppBaseURL
+	}
+	p := &llamaCppProvider{
+		baseURL: baseURL,
+		dims:    dimsTracker{dims: cfg.Dimensions},
+		client:  &http.Client{},
+	}
+	model := cfg.Model
+	if model == "" {
+		// Name the index after the model the server loaded, so switching
+		// models triggers a reindex.
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		model, _ = p.servedModel(ctx)
+		cancel()
+	}
+	if model != "" {
+		p.setModel(model)
+	}
+	return p, nil
+}
+
+// openAIEmbedRequest is the request body for /v1/embeddings.
+type openAIEmbedRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// openAIEmbedResponse is the response body from /v1/embeddings.
+type openAIEmbedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (l *llamaCppProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	docPrefix, _ := l.prefixes(ctx)
+	return l.embed(ctx, withPrefix(docPrefix, texts))
+}
+
+func (l *llamaCppProvider) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	_, queryPrefix := l.prefixes(ctx)
+	results, err := l.embed(ctx, []string{queryPrefix + text})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("llama.cpp embed returned no results")
+	}
+	return results[0], nil
+}
+
+func (l *llamaCppProvider) embed(ctx context.Context, texts []string) ([][]float32, error) {
+	l.mu.Lock()
+	model := l.model
+	l.mu.Unlock()
+
+	data, err := json.Marshal(openAIEmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshal embed request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.baseURL+"/v1/embeddings", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("create embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embed request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("llama.cpp embed: status %d: %s", resp.StatusCode, body)
+	}
+
+	var embedResp openAIEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("decode embed response: %w", err)
+	}
+	if len(embedResp.Data) != len(texts) {
+		return nil, fmt.Errorf("llama.cpp embed: got %d embeddings for %d inputs", len(embedResp.Data), len(texts))
+	}
+
+	// The API does not promise response order; place each vector by index.
+	sort.Slice(embedResp.Data, func(i, j int) bool { return embedResp.Data[i].Index < embedResp.Data[j].Index })
+	vecs := make([][]float32, len(embedResp.Data))
+	for i, d := range embedResp.Data {
+		vecs[i] = d.Embedding
+	}
+	l.dims.observe(vecs)
+	return vecs, nil
+}
+
+// prefixes returns the task prefixes for the served model, asking the
+// server which model it loaded when none was configured.
+func (l *llamaCppProvider) prefixes(ctx context.Context) (doc, query string) {
+	l.mu.Lock()
+	known := l.model != ""
+	l.mu.Unlock()
+	if !known {
+		if model, err := l.servedModel(ctx); err == nil && model != "" {
+			l.setModel(model)
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.docPrefix, l.queryPrefix
+}
+
+// servedModel returns the first model listed by /v1/models.
+func (l *llamaCppProvider) servedModel(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+"/v1/models", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("llama.cpp models: status %d", resp.StatusCode)
+	}
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return "", fmt.Errorf("decode llama.cpp models: %w", err)
+	}
+	if len(models.Data) == 0 {
+		return "", fmt.Errorf("llama.cpp server lists no models")
+	}
+	return models.Data[0].ID, nil
+}
+
+func (l *llamaCppProvider) setModel(model string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.model = model
+	l.docPrefix, l.queryPrefix = modelPrefixes(model)
+}
+
+func (l *llamaCppProvider) Dimensions() int { return l.dims.get() }
+func (l *llamaCppProvider) Name() string    { return "llamacpp" }
+
+// ModelName returns the configured or served model, or "default" before the
+// server has been asked.
+func (l *llamaCppProvider) ModelName() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.model == "" {
+		return "default"
+	}
+	return l.model
+}
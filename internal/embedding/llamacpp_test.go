@@ -0,0 +1,107 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newLlamaCppServer returns a mock llama.cpp server serving model and
+// embeddings of size dims. Embeddings are returned in reverse order to
+// exercise index-based placement.
+func newLlamaCppServer(t *testing.T, model string, dims int, inputs *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			json.NewEncoder(w).Encode(map[string]any{"data": []map[string]string{{"id": model}}})
+		case "/v1/embeddings":
+			var req openAIEmbedRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode request: %v", err)
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			if inputs != nil {
+				*inputs = append(*inputs, req.Input...)
+			}
+			data := make([]map[string]any, 0, len(req.Input))
+			for i := len(req.Input) - 1; i >= 0; i-- {
+				vec := make([]float32, dims)
+				vec[0] = float32(i + 1)
+				data = append(data, map[string]any{"index": i, "embedding": vec})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": data})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLlamaCppEmbed(t *testing.T) {
+	var inputs []string
+	server := newLlamaCppServer(t, "nomic-embed-text-v1.5.Q8_0.gguf", 512, &inputs)
+
+	provider, err := NewProvider(Config{Provider: "llamacpp", LlamaCppBaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	if provider.Name() != "llamacpp" {
+		t.Errorf("Name = %q, want llamacpp", provider.Name())
+	}
+	if provider.ModelName() != "nomic-embed-text-v1.5.Q8_0.gguf" {
+		t.Errorf("ModelName = %q, want the served model", provider.ModelName())
+	}
+
+	ctx := context.Background()
+	results, err := provider.Embed(ctx, []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(results) != 2 || results[0][0] != 1 || results[1][0] != 2 {
+		t.Fatalf("embeddings not placed by index: %v", [][]float32{results[0][:1], results[1][:1]})
+	}
+	if provider.Dimensions() != 512 {
+		t.Errorf("Dimensions = %d, want 512", provider.Dimensions())
+	}
+
+	if _, err := provider.EmbedQuery(ctx, "auth flow"); err != nil {
+		t.Fatalf("EmbedQuery: %v", err)
+	}
+	want := []string{"search_document: first", "search_document: second", "search_query: auth flow"}
+	if strings.Join(inputs, "|") != strings.Join(want, "|") {
+		t.Errorf("inputs = %q, want %q", inputs, want)
+	}
+}
+
+func TestLlamaCppEmbedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "embeddings not enabled", http.StatusNotImplemented)
+	}))
+	defer server.Close()
+
+	provider, _ := newLlamaCppProvider(Config{LlamaCppBaseURL: server.URL, Model: "bge-m3"})
+	if _, err := provider.Embed(context.Background(), []string{"test"}); err == nil {
+		t.Error("expected error for 501 response")
+	}
+	if _, err := tryLlamaCpp(Config{LlamaCppBaseURL: server.URL}); err == nil {
+		t.Error("tryLlamaCpp should fail when the server cannot embed")
+	}
+}
+
+func TestTryLlamaCpp(t *testing.T) {
+	server := newLlamaCppServer(t, "bge-m3", 1024, nil)
+
+	p, err := tryLlamaCpp(Config{LlamaCppBaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("tryLlamaCpp: %v", err)
+	}
+	if p.Name() != "llamacpp" || p.ModelName() != "bge-m3" || p.Dimensions() != 1024 {
+		t.Errorf("got %s/%s/%d, want llamacpp/bge-m3/1024", p.Name(), p.ModelName(), p.Dimensions())
+	}
+}
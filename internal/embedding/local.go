@@ -0,0 +1,59 @@
+package embedding
+
+import (
+	"strings"
+	"sync"
+)
+
+// modelPrefixes returns the prefixes a model expects on indexed documents
+// and on search queries. Retrieval models trained with task prefixes embed
+// noticeably worse without them; other models get none.
+func modelPrefixes(model string) (doc, query string) {
+	m := strings.ToLower(model)
+	switch {
+	case strings.Contains(m, "nomic-embed"):
+		return "search_document: ", "search_query: "
+	case strings.Contains(m, "mxbai-embed"), strings.Contains(m, "arctic-embed"):
+		return "", "Represent this sentence for searching relevant passages: "
+	case strings.HasPrefix(m, "e5-"), strings.Contains(m, "/e5-"), strings.Contains(m, "multilingual-e5"):
+		return "passage: ", "query: "
+	}
+	return "", ""
+}
+
+// withPrefix returns texts with prefix prepended, or texts itself when the
+// prefix is empty.
+func withPrefix(prefix string, texts []string) []string {
+	if prefix == "" {
+		return texts
+	}
+	out := make([]string, len(texts))
+	for i, t := range texts {
+		out[i] = prefix + t
+	}
+	return out
+}
+
+// dimsTracker holds the dimensionality of a local model, either configured
+// or learned from the first vectors the server returns, since local servers
+// can load any model.
+type dimsTracker struct {
+	mu   sync.Mutex
+	dims int
+}
+
+// observe records the length of the first vector when no size is known yet.
+func (d *dimsTracker) observe(vecs [][]float32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dims == 0 && len(vecs) > 0 {
+		d.dims = len(vecs[0])
+	}
+}
+
+// get returns the known dimensionality, or 0 before the first embedding.
+func (d *dimsTracker) get() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dims
+}
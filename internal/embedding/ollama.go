@@ -15,10 +15,12 @@ const (
 )
 
 type ollamaProvider struct {
-	baseURL string
-	model   string
-	dims    int
-	client  *http.Client
+	baseURL     string
+	model       string
+	docPrefix   string
+	queryPrefix string
+	dims        dimsTracker
+	client      *http.Client
 }
 
 func init() {
@@ -34,15 +36,14 @@ func newOllamaProvider(cfg Config) (Provider, error) {
 	if model == "" {
 		model = defaultOllamaModel
 	}
-	dims := cfg.Dimensions
-	if dims == 0 {
-		dims = 768
-	}
+	docPrefix, queryPrefix := modelPrefixes(model)
 	return &ollamaProvider{
-		baseURL: baseURL,
-		model:   model,
-		dims:    dims,
-		client:  &http.Client{},
+		baseURL:     baseURL,
+		model:       model,
+		docPrefix:   docPrefix,
+		queryPrefix: queryPrefix,
+		dims:        dimsTracker{dims: cfg.Dimensions},
+		client:      &http.Client{},
 	}, nil
 }
 
@@ -62,18 +63,11 @@ func (o *ollamaProvider) Embed(ctx context.Context, texts []string) ([][]float32
 		return nil, nil
 	}
 
-	// Add search_document: prefix for indexing (v2-moe convention).
-	prefixed := make([]string, len(texts))
-	for i, t := range texts {
-		prefixed[i] = "search_document: " + t
-	}
-
-	return o.embed(ctx, prefixed)
+	return o.embed(ctx, withPrefix(o.docPrefix, texts))
 }
 
 func (o *ollamaProvider) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
-	// Add search_query: prefix for queries (v2-moe convention).
-	results, err := o.embed(ctx, []string{"search_query: " + text})
+	results, err := o.embed(ctx, []string{o.queryPrefix + text})
 	if err != nil {
 		return nil, err
 	}
@@ -116,9 +110,10 @@ func (o *ollamaProvider) embed(ctx context.Context, texts []string) ([][]float32
 		return nil, fmt.Errorf("decode embed response: %w", err)
 	}
 
+	o.dims.observe(embedResp.Embeddings)
 	return embedResp.Embeddings, nil
 }
 
-func (o *ollamaProvider) Dimensions() int   { return o.dims }
+func (o *ollamaProvider) Dimensions() int   { return o.dims.get() }
 func (o *ollamaProvider) Name() string      { return "ollama" }
 func (o *ollamaProvider) ModelName() string { return o.model }
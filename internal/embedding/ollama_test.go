@@ -47,7 +47,7 @@ func TestOllamaEmbed(t *testing.T) {
 
 	provider, err := newOllamaProvider(Config{
 		OllamaBaseURL: server.URL,
-		Model:         "test-model",
+		Model:         "nomic-embed-text-v2-moe",
 		Dimensions:    768,
 	})
 	if err != nil {
@@ -102,7 +102,7 @@ func TestOllamaEmbed(t *testing.T) {
 
 		qProvider, _ := newOllamaProvider(Config{
 			OllamaBaseURL: queryServer.URL,
-			Model:         "test-model",
+			Model:         "nomic-embed-text-v2-moe",
 		})
 		vec, err := qProvider.EmbedQuery(ctx, "what is auth")
 		if err != nil {
@@ -117,8 +117,8 @@ func TestOllamaEmbed(t *testing.T) {
 		if provider.Name() != "ollama" {
 			t.Errorf("Name = %q, want ollama", provider.Name())
 		}
-		if provider.ModelName() != "test-model" {
-			t.Errorf("ModelName = %q, want test-model", provider.ModelName())
+		if provider.ModelName() != "nomic-embed-text-v2-moe" {
+			t.Errorf("ModelName = %q, want nomic-embed-text-v2-moe", provider.ModelName())
 		}
 		if provider.Dimensions() != 768 {
 			t.Errorf("Dimensions = %d, want 768", provider.Dimensions())
@@ -138,3 +138,49 @@ func TestOllamaEmbedError(t *testing.T) {
 		t.Error("expected error for 404 response")
 	}
 }
+
+func TestOllamaLearnsDimensions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaEmbedRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Input[0] != "plain text" {
+			t.Errorf("input = %q, want no prefix for an unknown model", req.Input[0])
+		}
+		json.NewEncoder(w).Encode(ollamaEmbedResponse{Embeddings: [][]float32{make([]float32, 384)}})
+	}))
+	defer server.Close()
+
+	provider, _ := newOllamaProvider(Config{OllamaBaseURL: server.URL, Model: "all-minilm"})
+	if got := provider.Dimensions(); got != 0 {
+		t.Errorf("Dimensions before embedding = %d, want 0", got)
+	}
+	if _, err := provider.Embed(context.Background(), []string{"plain text"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if got := provider.Dimensions(); got != 384 {
+		t.Errorf("Dimensions = %d, want 384", got)
+	}
+}
+
+func TestModelPrefixes(t *testing.T) {
+	tests := []struct {
+		model     string
+		wantDoc   string
+		wantQuery string
+	}{
+		{"nomic-embed-text-v2-moe", "search_document: ", "search_query: "},
+		{"nomic-embed-text-v1.5.Q8_0.gguf", "search_document: ", "search_query: "},
+		{"mxbai-embed-large", "", "Represent this sentence for searching relevant passages: "},
+		{"snowflake-arctic-embed:m", "", "Represent this sentence for searching relevant passages: "},
+		{"intfloat/multilingual-e5-large", "passage: ", "query: "},
+		{"e5-small-v2", "passage: ", "query: "},
+		{"all-minilm", "", ""},
+		{"bge-m3", "", ""},
+	}
+	for _, tc := range tests {
+		doc, query := modelPrefixes(tc.model)
+		if doc != tc.wantDoc || query != tc.wantQuery {
+			t.Errorf("modelPrefixes(%q) = (%q, %q), want (%q, %q)", tc.model, doc, query, tc.wantDoc, tc.wantQuery)
+		}
+	}
+}
@@ -18,10 +18,11 @@ type Provider interface {
 	// EmbedQuery embeds a query text with appropriate prefix for search.
 	EmbedQuery(ctx context.Context, text string) ([]float32, error)
 
-	// Dimensions returns the dimensionality of the embedding vectors.
+	// Dimensions returns the dimensionality of the embedding vectors, or 0
+	// while a local provider has not yet learned it from its first response.
 	Dimensions() int
 
-	// Name returns the provider name (e.g., "ollama", "vertex-ai").
+	// Name returns the provider name (e.g., "ollama", "llamacpp", "vertex-ai").
 	Name() string
 
 	// ModelName returns the embedding model name.
@@ -30,14 +31,17 @@ type Provider interface {
 
 // Config holds configuration for creating an embedding provider.
 type Config struct {
-	// Provider specifies which embedding provider to use ("ollama", "vertex-ai").
+	// Provider specifies which embedding provider to use ("ollama", "llamacpp", "vertex-ai").
 	Provider string
 	// Model is the embedding model name.
 	Model string
-	// Dimensions is the target dimensionality (for MRL-capable models).
+	// Dimensions is the target dimensionality (for MRL-capable models). Local
+	// providers learn it from the server when zero.
 	Dimensions int
 	// OllamaBaseURL is the Ollama API base URL (default "http://localhost:11434").
 	OllamaBaseURL string
+	// LlamaCppBaseURL is the llama.cpp server base URL (default "http://localhost:8080").
+	LlamaCppBaseURL string
 	// Project is the GCP project ID (for Vertex AI).
 	Project string
 	// Location is the GCP region (for Vertex AI).
@@ -0,0 +1,139 @@
+package fetch
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxExtractSize caps the total uncompressed size extracted from an archive.
+const maxExtractSize = 4 << 30 // 4 GiB
+
+// extractArchive extracts a .zip, .tar.gz, or .tgz archive into dest and
+// returns the directory to index.
+func extractArchive(path, dest string) (string, error) {
+	lower := strings.ToLower(path)
+	var err error
+	if strings.HasSuffix(lower, ".zip") {
+		err = extractZip(path, dest)
+	} else {
+		err = extractTarGz(path, dest)
+	}
+	if err != nil {
+		return "", err
+	}
+	return singleTopDir(dest), nil
+}
+
+// safeJoin joins name onto dest, rejecting entries that would escape dest.
+func safeJoin(dest, name string) (string, error) {
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dest, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(name) {
+		return "", fmt.Errorf("archive entry %q escapes destination", name)
+	}
+	return target, nil
+}
+
+func extractZip(path, dest string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("open zip: %w", err)
+	}
+	defer r.Close()
+
+	var written int64
+	for _, f := range r.File {
+		target, err := safeJoin(dest, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("create dir: %w", err)
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			continue // skip symlinks and special files
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("open %s: %w", f.Name, err)
+		}
+		n, err := writeFile(target, rc, maxExtractSize-written)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("extract %s: %w", f.Name, err)
+		}
+		written += n
+	}
+	return nil
+}
+
+func extractTarGz(path, dest string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("open gzip: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	var written int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read tar: %w", err)
+		}
+		target, err := safeJoin(dest, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("create dir: %w", err)
+			}
+		case tar.TypeReg:
+			n, err := writeFile(target, tr, maxExtractSize-written)
+			if err != nil {
+				return fmt.Errorf("extract %s: %w", hdr.Name, err)
+			}
+			written += n
+		}
+	}
+}
+
+// writeFile copies at most limit bytes from r into a new file at target.
+func writeFile(target string, r io.Reader, limit int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+	n, err := io.Copy(out, io.LimitReader(r, limit+1))
+	if err != nil {
+		return n, err
+	}
+	if n > limit {
+		return n, fmt.Errorf("archive exceeds %d bytes uncompressed", int64(maxExtractSize))
+	}
+	return n, nil
+}
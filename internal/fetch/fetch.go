@@ -0,0 +1,287 @@
+// Package fetch materializes remote git repositories and source archives
+// into temporary directories so they can be indexed without a manual checkout.
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Kind identifies the type of a source.
+type Kind string
+
+const (
+	KindGit     Kind = "git"
+	KindArchive Kind = "archive"
+	// KindDirectory is a local directory indexed in place; it is never fetched.
+	KindDirectory Kind = "directory"
+)
+
+// Source describes where code should be fetched from.
+type Source struct {
+	Kind Kind
+	// Location is the git URL or archive file path.
+	Location string
+	// Ref is the git branch, tag, or commit to fetch (empty for the default branch).
+	Ref string
+}
+
+// Options controls fetching.
+type Options struct {
+	// Depth is the git history depth; 0 fetches the full history.
+	Depth int
+	// CacheDir, when set, keeps git clones in a per-repository directory under
+	// it and reuses them on later fetches instead of cloning into a temporary
+	// directory. Cached checkouts are not removed by Cleanup.
+	CacheDir string
+}
+
+// Checkout is a materialized source tree.
+type Checkout struct {
+	Source Source
+	// Dir is the root of the source tree to index. For archives with a single
+	// top-level directory (e.g. GitHub downloads) it is that directory.
+	Dir string
+	// Commit is the checked-out commit SHA (git sources only).
+	Commit string
+	// tempDir is the directory to remove on Cleanup.
+	tempDir string
+}
+
+// Cleanup removes the temporary directory backing the checkout.
+func (c *Checkout) Cleanup() error {
+	if c.tempDir == "" {
+		return nil
+	}
+	return os.RemoveAll(c.tempDir)
+}
+
+// scpLikeRepo matches "user@host:path" git remotes.
+var scpLikeRepo = regexp.MustCompile(`^[^@/]+@[^:/]+:.+`)
+
+// urlWithPath matches "scheme://host/path" URLs.
+var urlWithPath = regexp.MustCompile(`^[a-z][a-z0-9+.-]*://[^/]+/.+`)
+
+// ParseSource interprets a user-supplied source string. Git URLs may carry a
+// ref suffix after the last "@" (e.g. "https://github.com/org/repo.git@v1.2").
+// Files ending in .zip, .tar.gz, or .tgz are treated as archives.
+func ParseSource(s string) (Source, error) {
+	if s == "" {
+		return Source{}, fmt.Errorf("empty source")
+	}
+	if isArchivePath(s) && !isGitURL(s) {
+		return Source{Kind: KindArchive, Location: s}, nil
+	}
+	if !isGitURL(s) {
+		return Source{}, fmt.Errorf("unsupported source %q: expected a git URL or a .zip/.tar.gz archive", s)
+	}
+
+	src := Source{Kind: KindGit, Location: s}
+	if at := strings.LastIndex(s, "@"); at > 0 {
+		repo, ref := s[:at], s[at+1:]
+		if ref != "" && (urlWithPath.MatchString(repo) || scpLikeRepo.MatchString(repo)) {
+			if err := checkRef(ref); err != nil {
+				return Source{}, err
+			}
+			src.Location, src.Ref = repo, ref
+		}
+	}
+	return src, nil
+}
+
+// checkRef rejects refs that git would parse as options, such as
+// "--upload-pack=...".
+func checkRef(ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid git ref %q: must not start with \"-\"", ref)
+	}
+	return nil
+}
+
+func isGitURL(s string) bool {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "file://"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return scpLikeRepo.MatchString(s)
+}
+
+func isArchivePath(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasSuffix(lower, ".zip") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// Name returns a short, filesystem-safe name for the source, such as
+// "repo@v1.2" or "archive".
+func (s Source) Name() string {
+	base := strings.TrimSuffix(s.Location, "/")
+	if idx := strings.LastIndexAny(base, "/:"); idx >= 0 {
+		base = base[idx+1:]
+	}
+	lower := strings.ToLower(base)
+	for _, ext := range []string{".git", ".zip", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, ext) {
+			base = base[:len(base)-len(ext)]
+			break
+		}
+	}
+	if s.Ref != "" {
+		base += "@" + s.Ref
+	}
+	return unsafeNameChars.ReplaceAllString(base, "_")
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._@-]`)
+
+// Fetch materializes the source into a new temporary directory, or for git
+// sources with opts.CacheDir set, into the repository's cache directory. The
+// caller must call Cleanup on the returned checkout.
+func Fetch(ctx context.Context, src Source, opts Options) (*Checkout, error) {
+	if src.Kind == KindGit {
+		if err := checkRef(src.Ref); err != nil {
+			return nil, err
+		}
+	}
+	if src.Kind == KindGit && opts.CacheDir != "" {
+		return fetchCached(ctx, src, opts)
+	}
+
+	tmp, err := os.MkdirTemp("", "codeeagle-"+src.Name()+"-")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	co := &Checkout{Source: src, Dir: tmp, tempDir: tmp}
+
+	switch src.Kind {
+	case KindGit:
+		err = cloneGit(ctx, src, tmp, opts.Depth)
+	case KindArchive:
+		co.Dir, err = extractArchive(src.Location, tmp)
+	default:
+		err = fmt.Errorf("unknown source kind %q", src.Kind)
+	}
+	if err != nil {
+		_ = co.Cleanup()
+		return nil, err
+	}
+	if src.Kind == KindGit {
+		if co.Commit, err = HeadCommit(ctx, co.Dir); err != nil {
+			_ = co.Cleanup()
+			return nil, err
+		}
+	}
+	return co, nil
+}
+
+// CacheKey returns the cache directory name for a git source: the repository
+// name plus a hash of its location, so equally named repositories from
+// different hosts or organizations don't collide. The ref is not part of the
+// key; one clone serves every ref.
+func (s Source) CacheKey() string {
+	name := Source{Kind: s.Kind, Location: s.Location}.Name()
+	sum := sha256.Sum256([]byte(s.Location))
+	return name + "-" + hex.EncodeToString(sum[:4])
+}
+
+// fetchCached updates (or creates) the cached clone of src and checks out
+// the requested ref.
+func fetchCached(ctx context.Context, src Source, opts Options) (*Checkout, error) {
+	dir := filepath.Join(opts.CacheDir, src.CacheKey())
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create cache dir: %w", err)
+		}
+		if err := cloneGit(ctx, src, dir, opts.Depth); err != nil {
+			_ = os.RemoveAll(dir) // don't leave a half-initialized clone behind
+			return nil, err
+		}
+	} else {
+		ref := src.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		fetchArgs := []string{"fetch", "--quiet", "--force"}
+		if opts.Depth > 0 {
+			fetchArgs = append(fetchArgs, fmt.Sprintf("--depth=%d", opts.Depth))
+		}
+		fetchArgs = append(fetchArgs, "--", "origin", ref)
+		err := runGit(ctx, dir,
+			[]string{"remote", "set-url", "origin", src.Location},
+			fetchArgs,
+			[]string{"checkout", "--quiet", "--force", "FETCH_HEAD"},
+			[]string{"clean", "--quiet", "-fdx"},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commit, err := HeadCommit(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Checkout{Source: src, Dir: dir, Commit: commit}, nil
+}
+
+// HeadCommit returns the SHA of HEAD in the git work tree dir.
+func HeadCommit(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// cloneGit fetches a single ref into dir. Fetching by ref (rather than
+// `git clone --branch`) also works for commit SHAs on hosts that allow it.
+func cloneGit(ctx context.Context, src Source, dir string, depth int) error {
+	ref := src.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	fetchArgs := []string{"fetch", "--quiet"}
+	if depth > 0 {
+		fetchArgs = append(fetchArgs, fmt.Sprintf("--depth=%d", depth))
+	}
+	fetchArgs = append(fetchArgs, "--", "origin", ref)
+
+	return runGit(ctx, dir,
+		[]string{"init", "--quiet"},
+		[]string{"remote", "add", "origin", src.Location},
+		fetchArgs,
+		[]string{"checkout", "--quiet", "FETCH_HEAD"},
+	)
+}
+
+// runGit runs git commands in dir, stopping at the first failure.
+func runGit(ctx context.Context, dir string, steps ...[]string) error {
+	for _, args := range steps {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// singleTopDir returns the only child directory of dir when dir contains
+// exactly one entry and it is a directory; otherwise it returns dir.
+func singleTopDir(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
@@ -0,0 +1,250 @@
+package fetch
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		in       string
+		kind     Kind
+		location string
+		ref      string
+		name     string
+		wantErr  bool
+	}{
+		{"https://github.com/org/repo.git", KindGit, "https://github.com/org/repo.git", "", "repo", false},
+		{"https://github.com/org/repo.git@v1.2.0", KindGit, "https://github.com/org/repo.git", "v1.2.0", "repo@v1.2.0", false},
+		{"https://github.com/org/repo@release/1.0", KindGit, "https://github.com/org/repo", "release/1.0", "repo@release_1.0", false},
+		{"https://user@host.example/org/repo.git", KindGit, "https://user@host.example/org/repo.git", "", "repo", false},
+		{"git@github.com:org/repo.git", KindGit, "git@github.com:org/repo.git", "", "repo", false},
+		{"git@github.com:org/repo.git@abc123", KindGit, "git@github.com:org/repo.git", "abc123", "repo@abc123", false},
+		{"downloads/repo-main.zip", KindArchive, "downloads/repo-main.zip", "", "repo-main", false},
+		{"old.tar.gz", KindArchive, "old.tar.gz", "", "old", false},
+		{"https://github.com/org/repo.git@--upload-pack=touch /tmp/x", "", "", "", "", true},
+		{"some/local/dir", "", "", "", "", true},
+		{"", "", "", "", "", true},
+	}
+	for _, tt := range tests {
+		src, err := ParseSource(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSource(%q): expected error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSource(%q): %v", tt.in, err)
+			continue
+		}
+		if src.Kind != tt.kind || src.Location != tt.location || src.Ref != tt.ref {
+			t.Errorf("ParseSource(%q) = %+v, want kind=%s location=%s ref=%s", tt.in, src, tt.kind, tt.location, tt.ref)
+		}
+		if got := src.Name(); got != tt.name {
+			t.Errorf("ParseSource(%q).Name() = %q, want %q", tt.in, got, tt.name)
+		}
+	}
+}
+
+func TestFetchZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo-main.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range map[string]string{
+		"repo-main/main.go":        "package main\n",
+		"repo-main/pkg/util/u.go":  "package util\n",
+		"repo-main/docs/README.md": "# docs\n",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	zw.Close()
+	f.Close()
+
+	co, err := Fetch(context.Background(), Source{Kind: KindArchive, Location: path}, Options{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	defer co.Cleanup()
+
+	if filepath.Base(co.Dir) != "repo-main" {
+		t.Errorf("Dir = %s, want single top-level dir repo-main", co.Dir)
+	}
+	if data, err := os.ReadFile(filepath.Join(co.Dir, "pkg/util/u.go")); err != nil || string(data) != "package util\n" {
+		t.Errorf("extracted file = %q, %v", data, err)
+	}
+
+	tmp := co.tempDir
+	if err := co.Cleanup(); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if _, err := os.Stat(tmp); !os.IsNotExist(err) {
+		t.Errorf("temp dir not removed: %v", err)
+	}
+}
+
+func TestFetchTarGzRejectsTraversal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evil.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	body := []byte("pwned")
+	tw.WriteHeader(&tar.Header{Name: "../escape.txt", Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg})
+	tw.Write(body)
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	_, err = Fetch(context.Background(), Source{Kind: KindArchive, Location: path}, Options{})
+	if err == nil || !strings.Contains(err.Error(), "escapes destination") {
+		t.Fatalf("expected traversal error, got %v", err)
+	}
+}
+
+func TestFetchGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("package main // v1\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "v1")
+	run("tag", "v1")
+	write("package main // v2\n")
+	run("commit", "-q", "-am", "v2")
+
+	for _, tt := range []struct {
+		ref  string
+		want string
+	}{
+		{"", "package main // v2\n"},
+		{"v1", "package main // v1\n"},
+	} {
+		src := Source{Kind: KindGit, Location: "file://" + repo, Ref: tt.ref}
+		co, err := Fetch(context.Background(), src, Options{Depth: 1})
+		if err != nil {
+			t.Fatalf("Fetch ref %q: %v", tt.ref, err)
+		}
+		data, err := os.ReadFile(filepath.Join(co.Dir, "main.go"))
+		co.Cleanup()
+		if err != nil || string(data) != tt.want {
+			t.Errorf("ref %q: main.go = %q, %v; want %q", tt.ref, data, err, tt.want)
+		}
+	}
+
+	if _, err := Fetch(context.Background(), Source{Kind: KindGit, Location: "file://" + repo, Ref: "nope"}, Options{Depth: 1}); err == nil {
+		t.Error("expected error for missing ref")
+	}
+}
+
+func TestFetchGitRejectsOptionRef(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "pwned")
+	src := Source{Kind: KindGit, Location: "file://" + t.TempDir(), Ref: "--upload-pack=touch " + marker}
+	for _, opts := range []Options{{Depth: 1}, {CacheDir: t.TempDir()}} {
+		if co, err := Fetch(context.Background(), src, opts); err == nil {
+			co.Cleanup()
+			t.Errorf("Fetch with cache %q: expected error for ref %q", opts.CacheDir, src.Ref)
+		}
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("ref was run as a git option: %v", err)
+	}
+}
+
+func TestFetchGitCached(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("package main // v1\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "v1")
+	run("tag", "v1")
+	v1 := run("rev-parse", "HEAD")
+
+	cache := t.TempDir()
+	opts := Options{Depth: 1, CacheDir: cache}
+	fetch := func(ref, want, wantCommit string) string {
+		t.Helper()
+		co, err := Fetch(context.Background(), Source{Kind: KindGit, Location: "file://" + repo, Ref: ref}, opts)
+		if err != nil {
+			t.Fatalf("Fetch ref %q: %v", ref, err)
+		}
+		if err := co.Cleanup(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(filepath.Join(co.Dir, "main.go"))
+		if err != nil || string(data) != want {
+			t.Errorf("ref %q: main.go = %q, %v; want %q", ref, data, err, want)
+		}
+		if co.Commit != wantCommit {
+			t.Errorf("ref %q: commit = %s, want %s", ref, co.Commit, wantCommit)
+		}
+		return co.Dir
+	}
+
+	dir := fetch("", "package main // v1\n", v1)
+	if filepath.Dir(dir) != cache {
+		t.Errorf("checkout %s not in cache dir %s", dir, cache)
+	}
+
+	// New upstream commits are picked up by the cached clone.
+	write("package main // v2\n")
+	run("commit", "-q", "-am", "v2")
+	v2 := run("rev-parse", "HEAD")
+	if got := fetch("", "package main // v2\n", v2); got != dir {
+		t.Errorf("cached clone not reused: %s != %s", got, dir)
+	}
+	fetch("v1", "package main // v1\n", v1)
+}
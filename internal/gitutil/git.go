@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // BranchInfo holds information about the current git branch state.
@@ -269,10 +270,29 @@ func GetCurrentHEAD(repoPath string) (string, error) {
 	return runGit(repoPath, "rev-parse", "HEAD")
 }
 
+// GetCommitTime returns the committer date of a commit.
+func GetCommitTime(repoPath, ref string) (time.Time, error) {
+	out, err := runGit(repoPath, "show", "-s", "--format=%ct", ref+"^{commit}")
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, err := strconv.ParseInt(out, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse commit time %q: %w", out, err)
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
+
 // GetChangedFilesSince returns the files that changed between sinceCommit and HEAD.
 // Files are categorized as added, modified, or deleted.
 func GetChangedFilesSince(repoPath, sinceCommit string) (added, modified, deleted []string, err error) {
-	output, err := runGit(repoPath, "diff", "--name-status", sinceCommit+"..HEAD")
+	return GetChangedFilesBetween(repoPath, sinceCommit, "HEAD")
+}
+
+// GetChangedFilesBetween returns the files that changed between the from and
+// to refs, categorized as added, modified, or deleted.
+func GetChangedFilesBetween(repoPath, from, to string) (added, modified, deleted []string, err error) {
+	output, err := runGit(repoPath, "diff", "--name-status", from+".."+to)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("git diff --name-status: %w", err)
 	}
@@ -292,6 +312,11 @@ func GetChangedFilesSince(repoPath, sinceCommit string) (added, modified, delete
 	return added, modified, deleted, nil
 }
 
+// GetMergeBase returns the best common ancestor of refs a and b.
+func GetMergeBase(repoPath, a, b string) (string, error) {
+	return runGit(repoPath, "merge-base", a, b)
+}
+
 // runGit executes a git command in the given repository path and returns trimmed stdout.
 func runGit(repoPath string, args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
@@ -302,3 +327,178 @@ func runGit(repoPath string, args ...string) (string, error) {
 	}
 	return strings.TrimSpace(string(output)), nil
 }
+
+// GetRepoRoot returns the absolute path of the top-level directory of the
+// repository containing repoPath.
+func GetRepoRoot(repoPath string) (string, error) {
+	return runGit(repoPath, "rev-parse", "--show-toplevel")
+}
+
+// GetStagedFiles returns files staged in the index, categorized as added,
+// modified, or deleted. Paths are relative to the repository root; renamed
+// files are reported as modified under their new path.
+func GetStagedFiles(repoPath string) (added, modified, deleted []string, err error) {
+	output, err := runGit(repoPath, "diff", "--cached", "--name-status")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("git diff --cached --name-status: %w", err)
+	}
+
+	for path, status := range parseNameStatus(output) {
+		switch status {
+		case "added":
+			added = append(added, path)
+		case "deleted":
+			deleted = append(deleted, path)
+		default:
+			modified = append(modified, path)
+		}
+	}
+	return added, modified, deleted, nil
+}
+
+// ReadFileAtRef returns the content of path (relative to the repository root)
+// at the given ref. An empty ref reads the staged version from the index.
+// Unlike runGit, the output is returned untrimmed so line numbers are preserved.
+func ReadFileAtRef(repoPath, ref, path string) ([]byte, error) {
+	spec := ref + ":" + path
+	cmd := exec.Command("git", "show", spec)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show %s: %w", spec, err)
+	}
+	return output, nil
+}
+
+// ChurnCommit is a commit with the lines it added and deleted per file.
+type ChurnCommit struct {
+	Hash string
+	// Author is the author's email, or name when the email is empty.
+	Author  string
+	Date    time.Time
+	Message string
+	Files   []ChangedFile
+}
+
+// GetChurnLog returns the non-merge commits reachable from HEAD, newest
+// first, with their messages and the lines each changed per file. Paths are relative to
+// repoPath; renames count as a deletion and an addition. since limits the
+// log to commits after a date git understands ("2024-01-01",
+// "1.year.ago"); empty means all history.
+func GetChurnLog(repoPath, since string) ([]ChurnCommit, error) {
+	args := []string{"log", "--no-merges", "--no-renames", "--relative", "--numstat",
+		"--format=%x1e%H%x1f%ae%x1f%an%x1f%aI%x1f%B%x1d"}
+	if since != "" {
+		args = append(args, "--since="+since)
+	}
+	output, err := runGit(repoPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get churn log: %w", err)
+	}
+	return parseChurnLog(output), nil
+}
+
+// parseChurnLog parses GetChurnLog's output: records separated by \x1e,
+// a "hash\x1femail\x1fname\x1fdate\x1fmessage" header ending with \x1d,
+// followed by numstat lines. Binary files ("-" counts) are recorded with
+// zero lines.
+func parseChurnLog(output string) []ChurnCommit {
+	var commits []ChurnCommit
+	for _, record := range strings.Split(output, "\x1e") {
+		head, stats, _ := strings.Cut(record, "\x1d")
+		header := strings.Split(head, "\x1f")
+		if len(header) < 5 {
+			continue
+		}
+		c := ChurnCommit{Hash: header[0], Author: strings.ToLower(header[1]), Message: strings.TrimSpace(header[4])}
+		if c.Author == "" {
+			c.Author = header[2]
+		}
+		c.Date, _ = time.Parse(time.RFC3339, header[3])
+		for _, line := range strings.Split(strings.TrimSpace(stats), "\n") {
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			added, _ := strconv.Atoi(parts[0])
+			deleted, _ := strconv.Atoi(parts[1])
+			c.Files = append(c.Files, ChangedFile{Path: parts[2], Status: "modified", Additions: added, Deletions: deleted})
+		}
+		commits = append(commits, c)
+	}
+	return commits
+}
+
+// BlameLine is the commit that last changed a line.
+type BlameLine struct {
+	// Commit is the commit hash; all zeros for uncommitted changes.
+	Commit string
+	// Author is the author's email, or name when the email is empty.
+	Author string
+	Time   time.Time
+}
+
+// BlameFile returns the commit that last changed each line of the file at
+// path (relative to repoPath) in the working tree; line n is at index n-1.
+func BlameFile(repoPath, path string) ([]BlameLine, error) {
+	output, err := runGit(repoPath, "blame", "--incremental", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("blame %s: %w", path, err)
+	}
+	return parseBlameIncremental(output), nil
+}
+
+// parseBlameIncremental parses git blame --incremental output: groups of
+// "<hash> <orig-line> <final-line> <count>" followed by header lines, of
+// which the commit's author fields only appear the first time it is seen,
+// ending with a "filename" line.
+func parseBlameIncremental(output string) []BlameLine {
+	commits := make(map[string]*BlameLine)
+	var lines []BlameLine
+	var cur *BlameLine
+	var final, count int
+	var name string
+	for _, line := range strings.Split(output, "\n") {
+		if cur == nil {
+			fields := strings.Fields(line)
+			if len(fields) != 4 || len(fields[0]) < 40 {
+				continue
+			}
+			final, _ = strconv.Atoi(fields[2])
+			count, _ = strconv.Atoi(fields[3])
+			if final < 1 {
+				continue
+			}
+			cur = commits[fields[0]]
+			if cur == nil {
+				cur = &BlameLine{Commit: fields[0]}
+				commits[fields[0]] = cur
+			}
+			name = ""
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			name = value
+		case "author-mail":
+			cur.Author = strings.ToLower(strings.Trim(value, "<>"))
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				cur.Time = time.Unix(sec, 0).UTC()
+			}
+		case "filename":
+			if cur.Author == "" {
+				cur.Author = name
+			}
+			for len(lines) < final-1+count {
+				lines = append(lines, BlameLine{})
+			}
+			for i := 0; i < count; i++ {
+				lines[final-1+i] = *cur
+			}
+			cur = nil
+		}
+	}
+	return lines
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 )
 
 // These tests use the CodeEagle repository itself as the test subject.
@@ -41,6 +42,19 @@ func TestRunGitInvalidRepo(t *testing.T) {
 	}
 }
 
+func TestGetCommitTime(t *testing.T) {
+	got, err := GetCommitTime(repoPath, "HEAD")
+	if err != nil {
+		t.Fatalf("GetCommitTime: %v", err)
+	}
+	if got.IsZero() || got.After(time.Now().Add(time.Hour)) {
+		t.Errorf("commit time = %v", got)
+	}
+	if _, err := GetCommitTime(repoPath, "0000000000000000000000000000000000000000"); err == nil {
+		t.Error("expected error for unknown commit")
+	}
+}
+
 func TestGetBranchInfo(t *testing.T) {
 	info, err := GetBranchInfo(repoPath)
 	if err != nil {
@@ -341,3 +355,132 @@ func TestRunGitOutputTrimmed(t *testing.T) {
 		t.Errorf("expected trimmed output, got %q", output)
 	}
 }
+
+func TestGetStagedFilesAndReadFileAtRef(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(dir+"/"+name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("keep.go", "package a\n")
+	write("gone.go", "package a\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	write("keep.go", "\npackage a // staged\n")
+	write("new.go", "package a\n\nfunc New() {}\n")
+	git("add", "keep.go", "new.go")
+	git("rm", "-q", "gone.go")
+	write("keep.go", "package a // unstaged\n")
+
+	added, modified, deleted, err := GetStagedFiles(dir)
+	if err != nil {
+		t.Fatalf("GetStagedFiles: %v", err)
+	}
+	if len(added) != 1 || added[0] != "new.go" {
+		t.Errorf("added = %v, want [new.go]", added)
+	}
+	if len(modified) != 1 || modified[0] != "keep.go" {
+		t.Errorf("modified = %v, want [keep.go]", modified)
+	}
+	if len(deleted) != 1 || deleted[0] != "gone.go" {
+		t.Errorf("deleted = %v, want [gone.go]", deleted)
+	}
+
+	staged, err := ReadFileAtRef(dir, "", "keep.go")
+	if err != nil {
+		t.Fatalf("ReadFileAtRef index: %v", err)
+	}
+	if string(staged) != "\npackage a // staged\n" {
+		t.Errorf("staged content = %q, want untrimmed staged version", staged)
+	}
+	head, err := ReadFileAtRef(dir, "HEAD", "keep.go")
+	if err != nil {
+		t.Fatalf("ReadFileAtRef HEAD: %v", err)
+	}
+	if string(head) != "package a\n" {
+		t.Errorf("HEAD content = %q", head)
+	}
+	if _, err := ReadFileAtRef(dir, "HEAD", "new.go"); err == nil {
+		t.Error("expected error reading file absent at HEAD")
+	}
+
+	root, err := GetRepoRoot(dir)
+	if err != nil || root == "" {
+		t.Errorf("GetRepoRoot = %q, %v", root, err)
+	}
+}
+
+func TestParseChurnLog(t *testing.T) {
+	output := "\x1eaaa\x1fAda@Example.com\x1fAda\x1f2024-03-01T10:00:00+01:00\x1fFix retries (PAY-12)\n\n1\t2\tnot/a/file\n\x1d\n\n" +
+		"3\t1\tsrc/app.go\n-\t-\tlogo.png\n" +
+		"\x1ebbb\x1f\x1fBob\x1f2024-02-01T09:00:00Z\x1fAdd app\n\x1d\n\n10\t0\tsrc/app.go\n" +
+		"\x1eccc\x1fc@example.com\x1fCy\x1f2024-01-01T09:00:00Z\x1fEmpty\n\x1d\n"
+	commits := parseChurnLog(output)
+	if len(commits) != 3 {
+		t.Fatalf("expected 3 commits, got %d", len(commits))
+	}
+	c := commits[0]
+	if c.Hash != "aaa" || c.Author != "ada@example.com" || c.Date.UTC().Hour() != 9 || c.Message != "Fix retries (PAY-12)\n\n1\t2\tnot/a/file" {
+		t.Errorf("unexpected commit header: %+v", c)
+	}
+	if len(c.Files) != 2 || c.Files[0] != (ChangedFile{Path: "src/app.go", Status: "modified", Additions: 3, Deletions: 1}) ||
+		c.Files[1].Path != "logo.png" || c.Files[1].Additions != 0 {
+		t.Errorf("unexpected files: %+v", c.Files)
+	}
+	if commits[1].Author != "Bob" {
+		t.Errorf("expected the name when the email is empty, got %q", commits[1].Author)
+	}
+	if len(commits[2].Files) != 0 {
+		t.Errorf("expected no files for an empty commit, got %+v", commits[2].Files)
+	}
+}
+
+func TestParseBlameIncremental(t *testing.T) {
+	a := strings.Repeat("a", 40)
+	b := strings.Repeat("b", 40)
+	output := a + " 1 1 2\nauthor Ada\nauthor-mail <Ada@example.com>\nauthor-time 1700000000\nsummary x\nfilename f.go\n" +
+		b + " 5 4 1\nauthor Bob\nauthor-mail <>\nauthor-time 1710000000\nprevious " + a + " f.go\nfilename f.go\n" +
+		a + " 3 3 1\nfilename f.go\n"
+	lines := parseBlameIncremental(output)
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(lines))
+	}
+	for i, want := range []string{"ada@example.com", "ada@example.com", "ada@example.com", "Bob"} {
+		if lines[i].Author != want {
+			t.Errorf("line %d author = %q, want %q", i+1, lines[i].Author, want)
+		}
+	}
+	if lines[2].Commit != a || lines[3].Commit != b || lines[3].Time.Unix() != 1710000000 {
+		t.Errorf("unexpected blame: %+v", lines)
+	}
+}
+
+func TestGetChurnLogAndBlame(t *testing.T) {
+	commits, err := GetChurnLog(repoPath, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(commits) == 0 || commits[0].Hash == "" || commits[0].Date.IsZero() {
+		t.Fatalf("expected commits with hashes and dates, got %+v", commits)
+	}
+	lines, err := BlameFile(repoPath, "go.mod")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(lines) == 0 || lines[0].Commit == "" {
+		t.Errorf("expected blamed lines for go.mod, got %+v", lines)
+	}
+}
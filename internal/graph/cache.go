@@ -0,0 +1,349 @@
+package graph
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"maps"
+	"sync"
+)
+
+// DefaultCacheSize is the number of entries a CachedStore keeps when no size
+// is given.
+const DefaultCacheSize = 4096
+
+// CachedStore wraps a Store with an in-memory LRU cache of GetNode, GetEdges
+// and QueryNodes results. Writes made through it invalidate the affected
+// entries: node writes drop cached queries, edge writes drop the cached
+// edges of both endpoints. Writes made to the underlying store directly are
+// not seen; call Invalidate after them.
+//
+// Callers receive copies of cached nodes and edges, so mutating a result
+// does not corrupt the cache.
+type CachedStore struct {
+	Store
+
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+	hits    int64
+	misses  int64
+	// gen counts invalidations, so a result read from the store while a
+	// write invalidated its entry is not cached.
+	gen uint64
+}
+
+// CacheStats reports how a CachedStore has been used.
+type CacheStats struct {
+	Entries int   `json:"entries"`
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+}
+
+type cacheEntry struct {
+	key   string
+	nodes []*Node              // GetNode (one node) and QueryNodes results
+	edges map[EdgeType][]*Edge // GetEdges results for one node, by type
+}
+
+// NewCachedStore wraps store with a cache holding up to size entries. A
+// size of zero or less uses DefaultCacheSize. Each cached query counts as
+// one entry however many nodes it returned.
+func NewCachedStore(store Store, size int) *CachedStore {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &CachedStore{
+		Store:   store,
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// CacheStats returns the cache statistics.
+func (c *CachedStore) CacheStats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Entries: c.ll.Len(), Hits: c.hits, Misses: c.misses}
+}
+
+// Invalidate drops every cached entry.
+func (c *CachedStore) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	clear(c.entries)
+	c.gen++
+}
+
+// GetNode returns the node from the cache or the underlying store.
+func (c *CachedStore) GetNode(ctx context.Context, id string) (*Node, error) {
+	key := nodeKey(id)
+	e, gen := c.get(key)
+	if e != nil {
+		return cloneNode(e.nodes[0]), nil
+	}
+	n, err := c.Store.GetNode(ctx, id)
+	if err != nil || n == nil {
+		return n, err
+	}
+	c.put(&cacheEntry{key: key, nodes: []*Node{cloneNode(n)}}, gen)
+	return n, nil
+}
+
+// QueryNodes returns the query results from the cache or the underlying
+// store.
+func (c *CachedStore) QueryNodes(ctx context.Context, filter NodeFilter) ([]*Node, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return c.Store.QueryNodes(ctx, filter)
+	}
+	key := "q\x00" + string(data)
+	e, gen := c.get(key)
+	if e != nil {
+		return cloneNodes(e.nodes), nil
+	}
+	nodes, err := c.Store.QueryNodes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	c.put(&cacheEntry{key: key, nodes: cloneNodes(nodes)}, gen)
+	return nodes, nil
+}
+
+// GetEdges returns the node's edges from the cache or the underlying store.
+func (c *CachedStore) GetEdges(ctx context.Context, nodeID string, edgeType EdgeType) ([]*Edge, error) {
+	key := edgesKey(nodeID)
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		if edges, ok := el.Value.(*cacheEntry).edges[edgeType]; ok {
+			c.hits++
+			c.ll.MoveToFront(el)
+			c.mu.Unlock()
+			return cloneEdges(edges), nil
+		}
+	}
+	c.misses++
+	gen := c.gen
+	c.mu.Unlock()
+
+	edges, err := c.Store.GetEdges(ctx, nodeID, edgeType)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return edges, nil
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheEntry).edges[edgeType] = cloneEdges(edges)
+	} else {
+		c.putLocked(&cacheEntry{key: key, edges: map[EdgeType][]*Edge{edgeType: cloneEdges(edges)}})
+	}
+	return edges, nil
+}
+
+// AddNode adds the node and drops cached queries.
+func (c *CachedStore) AddNode(ctx context.Context, node *Node) error {
+	defer c.invalidateNodes(node.ID)
+	return c.Store.AddNode(ctx, node)
+}
+
+// UpdateNode updates the node and drops cached queries.
+func (c *CachedStore) UpdateNode(ctx context.Context, node *Node) error {
+	defer c.invalidateNodes(node.ID)
+	return c.Store.UpdateNode(ctx, node)
+}
+
+// DeleteNode deletes the node. Its edges go with it, so every cached entry
+// is dropped.
+func (c *CachedStore) DeleteNode(ctx context.Context, id string) error {
+	defer c.Invalidate()
+	return c.Store.DeleteNode(ctx, id)
+}
+
+// DeleteByFile deletes the file's nodes and drops every cached entry.
+func (c *CachedStore) DeleteByFile(ctx context.Context, filePath string) error {
+	defer c.Invalidate()
+	return c.Store.DeleteByFile(ctx, filePath)
+}
+
+// AddEdge adds the edge and drops the cached edges of its endpoints.
+func (c *CachedStore) AddEdge(ctx context.Context, edge *Edge) error {
+	defer c.remove(edgesKey(edge.SourceID), edgesKey(edge.TargetID))
+	return c.Store.AddEdge(ctx, edge)
+}
+
+// DeleteEdge deletes the edge. Its endpoints are unknown without a lookup,
+// so all cached edges are dropped.
+func (c *CachedStore) DeleteEdge(ctx context.Context, id string) error {
+	defer c.invalidateEdges()
+	return c.Store.DeleteEdge(ctx, id)
+}
+
+// AddBatch adds the nodes and edges, in one batch when the underlying store
+// supports it, and invalidates as the single writes would.
+func (c *CachedStore) AddBatch(ctx context.Context, nodes []*Node, edges []*Edge) error {
+	defer func() {
+		ids := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			ids = append(ids, n.ID)
+		}
+		c.invalidateNodes(ids...)
+		keys := make([]string, 0, 2*len(edges))
+		for _, e := range edges {
+			keys = append(keys, edgesKey(e.SourceID), edgesKey(e.TargetID))
+		}
+		c.remove(keys...)
+	}()
+	if bs, ok := c.Store.(BatchStore); ok {
+		return bs.AddBatch(ctx, nodes, edges)
+	}
+	for _, n := range nodes {
+		if err := c.Store.AddNode(ctx, n); err != nil {
+			return err
+		}
+	}
+	for _, e := range edges {
+		if err := c.Store.AddEdge(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSource passes through to the underlying store when it is a
+// SourceStore; source text is not cached.
+func (c *CachedStore) GetSource(ctx context.Context, nodeID string) (string, error) {
+	return GetSource(ctx, c.Store, nodeID)
+}
+
+// GetSourceLines passes through to the underlying store when it is a
+// SourceStore.
+func (c *CachedStore) GetSourceLines(ctx context.Context, filePath string, start, end int) (string, error) {
+	if ss, ok := c.Store.(SourceStore); ok {
+		return ss.GetSourceLines(ctx, filePath, start, end)
+	}
+	return "", ErrNoSource
+}
+
+func nodeKey(id string) string  { return "n\x00" + id }
+func edgesKey(id string) string { return "e\x00" + id }
+
+// invalidateNodes drops the given nodes and every cached query.
+func (c *CachedStore) invalidateNodes(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		c.removeLocked(nodeKey(id))
+	}
+	c.removeMatchingLocked(func(e *cacheEntry) bool { return e.key[0] == 'q' })
+	c.gen++
+}
+
+// invalidateEdges drops every cached edge list.
+func (c *CachedStore) invalidateEdges() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeMatchingLocked(func(e *cacheEntry) bool { return e.edges != nil })
+	c.gen++
+}
+
+// get returns the cached entry for key, or nil and the current generation
+// to pass to put.
+func (c *CachedStore) get(key string) (*cacheEntry, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, c.gen
+	}
+	c.hits++
+	c.ll.MoveToFront(el)
+	return el.Value.(*cacheEntry), c.gen
+}
+
+// put caches e unless an invalidation happened since generation gen.
+func (c *CachedStore) put(e *cacheEntry, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.putLocked(e)
+	}
+}
+
+func (c *CachedStore) putLocked(e *cacheEntry) {
+	if el, ok := c.entries[e.key]; ok {
+		el.Value = e
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.entries[e.key] = c.ll.PushFront(e)
+	for c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *CachedStore) remove(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, k := range keys {
+		c.removeLocked(k)
+	}
+	c.gen++
+}
+
+func (c *CachedStore) removeLocked(key string) {
+	if el, ok := c.entries[key]; ok {
+		c.ll.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+func (c *CachedStore) removeMatchingLocked(match func(*cacheEntry) bool) {
+	for el := c.ll.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*cacheEntry); match(e) {
+			c.ll.Remove(el)
+			delete(c.entries, e.key)
+		}
+		el = next
+	}
+}
+
+func cloneNode(n *Node) *Node {
+	cp := *n
+	cp.Properties = maps.Clone(n.Properties)
+	cp.Metrics = maps.Clone(n.Metrics)
+	return &cp
+}
+
+func cloneNodes(nodes []*Node) []*Node {
+	if nodes == nil {
+		return nil
+	}
+	out := make([]*Node, len(nodes))
+	for i, n := range nodes {
+		out[i] = cloneNode(n)
+	}
+	return out
+}
+
+func cloneEdges(edges []*Edge) []*Edge {
+	if edges == nil {
+		return nil
+	}
+	out := make([]*Edge, len(edges))
+	for i, e := range edges {
+		cp := *e
+		cp.Properties = maps.Clone(e.Properties)
+		out[i] = &cp
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package graph_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestCachedStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewStore(filepath.Join(t.TempDir(), "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	c := graph.NewCachedStore(store, 0)
+
+	if err := c.AddNode(ctx, &graph.Node{ID: "a", Type: graph.NodeFunction, Name: "A", FilePath: "a.go"}); err != nil {
+		t.Fatal(err)
+	}
+	filter := graph.NodeFilter{FilePath: "a.go"}
+	queryIDs := func() []string {
+		t.Helper()
+		nodes, err := c.QueryNodes(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, n := range nodes {
+			ids = append(ids, n.ID)
+		}
+		return ids
+	}
+
+	if got := queryIDs(); len(got) != 1 {
+		t.Fatalf("QueryNodes = %v, want [a]", got)
+	}
+	// A write through the cache drops cached queries.
+	if err := c.AddNode(ctx, &graph.Node{ID: "b", Type: graph.NodeFunction, Name: "B", FilePath: "a.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := queryIDs(); len(got) != 2 {
+		t.Fatalf("QueryNodes after AddNode = %v, want [a b]", got)
+	}
+	queryIDs()
+	if st := c.CacheStats(); st.Hits != 1 || st.Misses != 2 {
+		t.Errorf("stats = %+v, want 1 hit and 2 misses", st)
+	}
+
+	// Mutating a returned node does not change the cached copy.
+	n, err := c.GetNode(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Name = "changed"
+	if n, _ = c.GetNode(ctx, "a"); n.Name != "A" {
+		t.Errorf("cached node name = %q after mutating a result, want A", n.Name)
+	}
+	n.Name = "A2"
+	if err := c.UpdateNode(ctx, n); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ = c.GetNode(ctx, "a"); n.Name != "A2" {
+		t.Errorf("node name = %q after UpdateNode, want A2", n.Name)
+	}
+
+	edges := func() int {
+		t.Helper()
+		es, err := c.GetEdges(ctx, "b", graph.EdgeCalls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(es)
+	}
+	if got := edges(); got != 0 {
+		t.Fatalf("GetEdges = %d edges, want 0", got)
+	}
+	if err := c.AddEdge(ctx, &graph.Edge{ID: "e", Type: graph.EdgeCalls, SourceID: "a", TargetID: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := edges(); got != 1 {
+		t.Errorf("GetEdges after AddEdge = %d edges, want 1", got)
+	}
+	if err := c.DeleteEdge(ctx, "e"); err != nil {
+		t.Fatal(err)
+	}
+	if got := edges(); got != 0 {
+		t.Errorf("GetEdges after DeleteEdge = %d edges, want 0", got)
+	}
+
+	if err := c.DeleteByFile(ctx, "a.go"); err != nil {
+		t.Fatal(err)
+	}
+	if got := queryIDs(); len(got) != 0 {
+		t.Errorf("QueryNodes after DeleteByFile = %v, want none", got)
+	}
+}
+
+func TestCachedStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewStore(filepath.Join(t.TempDir(), "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.AddNode(ctx, &graph.Node{ID: id, Type: graph.NodeFunction, Name: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := graph.NewCachedStore(store, 2)
+	for _, id := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := c.GetNode(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a was used most recently before c was added, so b was evicted.
+	if st := c.CacheStats(); st.Entries != 2 || st.Hits != 2 || st.Misses != 4 {
+		t.Errorf("stats = %+v, want 2 entries, 2 hits and 4 misses", st)
+	}
+}
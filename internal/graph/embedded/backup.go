@@ -0,0 +1,32 @@
+package embedded
+
+import (
+	"fmt"
+	"io"
+)
+
+// Backup writes a consistent copy of the whole DB to w: every namespace
+// and branch as of one point in time, with the schema version. It works on
+// read-only stores, so a backup can be taken while a writer runs.
+func (s *BranchStore) Backup(w io.Writer) error {
+	if _, err := s.db.Backup(w, 0); err != nil {
+		return fmt.Errorf("backup graph DB: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the contents of the DB with a backup read from r, then
+// migrates it to the current schema version. Every namespace and branch of
+// the DB is replaced, not only the store's own.
+func (s *BranchStore) Restore(r io.Reader) error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	if err := s.db.DropAll(); err != nil {
+		return fmt.Errorf("clear graph DB: %w", err)
+	}
+	if err := s.db.Load(r, 256); err != nil {
+		return fmt.Errorf("load graph DB backup: %w", err)
+	}
+	return s.migrate()
+}
@@ -0,0 +1,76 @@
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+
+	src := newTestStore(t)
+	if err := src.AddNode(ctx, &graph.Node{ID: "n1", Type: graph.NodeFunction, Name: "Run", Language: "go"}); err != nil {
+		t.Fatal(err)
+	}
+	team, err := src.WithNamespace("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := team.AddNode(ctx, &graph.Node{ID: "n2", Type: graph.NodeFunction, Name: "Stop"}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := src.Backup(&buf); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	dst := newTestStore(t)
+	if err := dst.AddNode(ctx, &graph.Node{ID: "stale", Type: graph.NodeFunction, Name: "Old"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Restore(&buf); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if _, err := dst.GetNode(ctx, "stale"); err == nil {
+		t.Error("Restore kept a node of the replaced graph")
+	}
+	if nodes, err := dst.QueryNodes(ctx, graph.NodeFilter{Language: "go"}); err != nil || len(nodes) != 1 {
+		t.Errorf("restored go nodes = %v, %v; want n1", nodes, err)
+	}
+	if namespaces, err := dst.ListNamespaces(ctx); err != nil || len(namespaces) != 1 || namespaces[0] != "team" {
+		t.Errorf("restored namespaces = %v, %v; want [team]", namespaces, err)
+	}
+	if version, err := dst.SchemaVersion(); err != nil || version != LatestSchemaVersion {
+		t.Errorf("restored schema version = %d, %v", version, err)
+	}
+
+	// The restored DB takes new writes.
+	if err := dst.AddNode(ctx, &graph.Node{ID: "n3", Type: graph.NodeFunction, Name: "Next"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := dst.GetNode(ctx, "n3"); err != nil || n.Name != "Next" {
+		t.Errorf("GetNode after restore = %v, %v", n, err)
+	}
+}
+
+func TestRestoreReadOnly(t *testing.T) {
+	dbPath := t.TempDir()
+	s, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	r, err := OpenReadOnly(dbPath, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := r.Restore(bytes.NewReader(nil)); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Restore on a read-only store = %v, want ErrReadOnly", err)
+	}
+}
@@ -20,15 +20,18 @@ type exportRecord struct {
 
 // Export writes all nodes and edges from the write branch to w in JSON-lines format.
 func (s *BranchStore) Export(_ context.Context, w io.Writer) error {
-	return s.ExportBranch(context.Background(), w, s.writeBranch)
+	return s.ExportBranch(context.Background(), w, s.WriteBranch())
 }
 
 // ExportBranch writes all nodes and edges for the given branch to w in JSON-lines format.
+// Records carry the branch name without the namespace, so an export can be
+// imported into another namespace.
 func (s *BranchStore) ExportBranch(_ context.Context, w io.Writer, branch string) error {
 	enc := json.NewEncoder(w)
+	keyBranch := s.qualify(branch)
 	return s.db.View(func(txn *badger.Txn) error {
 		// Export nodes.
-		if err := scanBranchNodes(txn, branch, func(node *graph.Node) bool {
+		if err := scanBranchNodes(txn, keyBranch, func(node *graph.Node) bool {
 			data, err := json.Marshal(node)
 			if err != nil {
 				return true // skip bad nodes
@@ -40,28 +43,22 @@ func (s *BranchStore) ExportBranch(_ context.Context, w io.Writer, branch string
 		}
 
 		// Export edges.
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchValues = true
-		edgePrefix := []byte(prefixEdge + branch + ":")
-		opts.Prefix = edgePrefix
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Seek(edgePrefix); it.Valid(); it.Next() {
-			item := it.Item()
-			var edge graph.Edge
-			err := item.Value(func(val []byte) error {
-				return json.Unmarshal(val, &edge)
-			})
+		var encErr error
+		if err := scanBranchEdges(txn, keyBranch, func(edge *graph.Edge) bool {
+			data, err := json.Marshal(edge)
 			if err != nil {
-				continue
-			}
-			data, err := json.Marshal(&edge)
-			if err != nil {
-				continue
+				return true
 			}
 			if err := enc.Encode(exportRecord{Kind: "edge", Branch: branch, Data: data}); err != nil {
-				return fmt.Errorf("encode edge: %w", err)
+				encErr = fmt.Errorf("encode edge: %w", err)
+				return false
 			}
+			return true
+		}); err != nil {
+			return fmt.Errorf("export edges: %w", err)
+		}
+		if encErr != nil {
+			return encErr
 		}
 		return nil
 	})
@@ -69,7 +66,8 @@ func (s *BranchStore) ExportBranch(_ context.Context, w io.Writer, branch string
 
 // Import reads JSON-lines from r and imports into the store.
 // If records have a Branch field (new format), imports into that branch via ImportIntoBranch.
-// If no Branch field (legacy format), clears the entire DB and imports into writeBranch.
+// If no Branch field (legacy format), clears every branch of the store's
+// namespace and imports into writeBranch.
 func (s *BranchStore) Import(ctx context.Context, r io.Reader) error {
 	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024)
@@ -94,7 +92,7 @@ func (s *BranchStore) Import(ctx context.Context, r io.Reader) error {
 			return fmt.Errorf("clear branch %s: %w", firstRec.Branch, err)
 		}
 		// Process first record.
-		if err := s.importRecord(ctx, firstRec, firstRec.Branch); err != nil {
+		if err := s.importRecord(ctx, firstRec, s.qualify(firstRec.Branch)); err != nil {
 			return err
 		}
 		// Process remaining records.
@@ -111,13 +109,13 @@ func (s *BranchStore) Import(ctx context.Context, r io.Reader) error {
 			if targetBranch == "" {
 				targetBranch = firstRec.Branch
 			}
-			if err := s.importRecord(ctx, rec, targetBranch); err != nil {
+			if err := s.importRecord(ctx, rec, s.qualify(targetBranch)); err != nil {
 				return err
 			}
 		}
 	} else {
-		// Legacy format: clear entire DB and import into writeBranch.
-		if err := s.db.DropAll(); err != nil {
+		// Legacy format: clear the namespace and import into writeBranch.
+		if err := s.clearNamespace(); err != nil {
 			return fmt.Errorf("clear store: %w", err)
 		}
 		if err := s.importRecord(ctx, firstRec, s.writeBranch); err != nil {
@@ -150,7 +148,7 @@ func (s *BranchStore) ImportIntoBranch(ctx context.Context, r io.Reader, targetB
 
 	// Temporarily set writeBranch to targetBranch for import.
 	origBranch := s.writeBranch
-	s.writeBranch = targetBranch
+	s.writeBranch = s.qualify(targetBranch)
 	defer func() { s.writeBranch = origBranch }()
 
 	scanner := bufio.NewScanner(r)
@@ -168,7 +166,7 @@ func (s *BranchStore) ImportIntoBranch(ctx context.Context, r io.Reader, targetB
 		if rec.Branch != "" && sourceBranch == "" {
 			sourceBranch = rec.Branch
 		}
-		if err := s.importRecord(ctx, rec, targetBranch); err != nil {
+		if err := s.importRecord(ctx, rec, s.writeBranch); err != nil {
 			return sourceBranch, err
 		}
 	}
@@ -176,6 +174,66 @@ func (s *BranchStore) ImportIntoBranch(ctx context.Context, r io.Reader, targetB
 	return sourceBranch, scanner.Err()
 }
 
+// CopyReadBranches replaces targetBranch with the merged view of the read
+// branches: for IDs present in several branches, the first read branch wins,
+// as with GetNode and QueryNodes. Returns the number of nodes and edges
+// copied.
+func (s *BranchStore) CopyReadBranches(ctx context.Context, targetBranch string) (nodes, edges int, err error) {
+	var (
+		nodeList []*graph.Node
+		edgeList []*graph.Edge
+	)
+	seenNodes := make(map[string]struct{})
+	seenEdges := make(map[string]struct{})
+	err = s.db.View(func(txn *badger.Txn) error {
+		for _, branch := range s.readBranches {
+			if err := scanBranchNodes(txn, branch, func(node *graph.Node) bool {
+				if _, ok := seenNodes[node.ID]; !ok {
+					seenNodes[node.ID] = struct{}{}
+					nodeList = append(nodeList, node)
+				}
+				return true
+			}); err != nil {
+				return err
+			}
+			if err := scanBranchEdges(txn, branch, func(edge *graph.Edge) bool {
+				if _, ok := seenEdges[edge.ID]; !ok {
+					seenEdges[edge.ID] = struct{}{}
+					edgeList = append(edgeList, edge)
+				}
+				return true
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("read branches: %w", err)
+	}
+
+	if err := s.DeleteByBranch(targetBranch); err != nil {
+		return 0, 0, fmt.Errorf("clear target branch %s: %w", targetBranch, err)
+	}
+	origBranch := s.writeBranch
+	s.writeBranch = s.qualify(targetBranch)
+	defer func() { s.writeBranch = origBranch }()
+
+	for _, n := range nodeList {
+		if err := s.AddNode(ctx, n); err != nil {
+			return nodes, edges, fmt.Errorf("copy node %s: %w", n.ID, err)
+		}
+		nodes++
+	}
+	for _, e := range edgeList {
+		if err := s.AddEdge(ctx, e); err != nil {
+			return nodes, edges, fmt.Errorf("copy edge %s: %w", e.ID, err)
+		}
+		edges++
+	}
+	return nodes, edges, nil
+}
+
 // ReadExportBranch reads the first record from an export file to extract the branch name.
 func ReadExportBranch(r io.Reader) (string, error) {
 	scanner := bufio.NewScanner(r)
@@ -198,7 +256,22 @@ func ReadExportBranch(r io.Reader) (string, error) {
 	return "", nil // empty file
 }
 
-// importRecord adds a single export record to the store under the given branch.
+// clearNamespace removes every branch of the store's namespace.
+func (s *BranchStore) clearNamespace() error {
+	branches, err := s.ListBranches()
+	if err != nil {
+		return err
+	}
+	for _, b := range branches {
+		if err := s.DeleteByBranch(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importRecord adds a single export record to the store under the given
+// key branch (qualified with the namespace).
 func (s *BranchStore) importRecord(ctx context.Context, rec exportRecord, branch string) error {
 	origBranch := s.writeBranch
 	s.writeBranch = branch
@@ -218,6 +291,8 @@ func (s *BranchStore) importRecord(ctx context.Context, rec exportRecord, branch
 		if err := json.Unmarshal(rec.Data, &edge); err != nil {
 			return fmt.Errorf("unmarshal edge: %w", err)
 		}
+		// Exports made before the current ID scheme merge without duplicates.
+		edge.ID = graph.MigrateEdgeID(&edge)
 		if err := s.AddEdge(ctx, &edge); err != nil {
 			return fmt.Errorf("import edge %s: %w", edge.ID, err)
 		}
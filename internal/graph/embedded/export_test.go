@@ -346,3 +346,61 @@ func TestReadExportBranch(t *testing.T) {
 		t.Errorf("empty file branch = %q, want empty", branch)
 	}
 }
+
+func TestCopyReadBranches(t *testing.T) {
+	ctx := context.Background()
+	dbPath := t.TempDir()
+
+	mainStore, err := NewBranchStore(dbPath, "main", []string{"main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []*graph.Node{
+		{ID: "a", Type: graph.NodeFunction, Name: "mainA", FilePath: "a.go"},
+		{ID: "b", Type: graph.NodeFunction, Name: "mainB", FilePath: "b.go"},
+	} {
+		if err := mainStore.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mainStore.AddEdge(ctx, &graph.Edge{ID: "e1", Type: graph.EdgeCalls, SourceID: "a", TargetID: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	mainStore.Close()
+
+	store, err := NewBranchStore(dbPath, "feature", []string{"feature", "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddNode(ctx, &graph.Node{ID: "a", Type: graph.NodeFunction, Name: "featureA", FilePath: "a.go"}); err != nil {
+		t.Fatal(err)
+	}
+	nodes, edges, err := store.CopyReadBranches(ctx, "snap")
+	if err != nil {
+		t.Fatalf("CopyReadBranches: %v", err)
+	}
+	if nodes != 2 || edges != 1 {
+		t.Errorf("copied %d nodes, %d edges; want 2, 1", nodes, edges)
+	}
+	if store.WriteBranch() != "feature" {
+		t.Errorf("write branch = %q after copy", store.WriteBranch())
+	}
+	store.Close()
+
+	snap, err := NewBranchStore(dbPath, "snap", []string{"snap"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Close()
+	n, err := snap.GetNode(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Name != "featureA" {
+		t.Errorf("node a = %q, want the feature branch version", n.Name)
+	}
+	got, err := snap.GetEdges(ctx, "b", graph.EdgeCalls)
+	if err != nil || len(got) != 1 {
+		t.Errorf("edges of b = %v, %v", got, err)
+	}
+}
@@ -29,7 +29,7 @@ type MigrateResult struct {
 func (s *BranchStore) MigrateAbsToRelPaths(ctx context.Context, repoRoots []string, dryRun bool) (*MigrateResult, error) {
 	result := &MigrateResult{}
 
-	branches, err := s.ListBranches()
+	branches, err := s.listKeyBranches()
 	if err != nil {
 		return nil, fmt.Errorf("list branches: %w", err)
 	}
@@ -144,7 +144,7 @@ func (s *BranchStore) migrateBranch(_ context.Context, branch string, repoRoots
 
 			if changed {
 				// Recompute edge ID from its components.
-				newEdgeID := fmt.Sprintf("%s-%s-%s", edgeCopy.SourceID, edgeCopy.Type, edgeCopy.TargetID)
+				newEdgeID := graph.NewEdgeID(edgeCopy.Type, edgeCopy.SourceID, edgeCopy.TargetID)
 				edgeCopy.ID = newEdgeID
 
 				edgesToMigrate = append(edgesToMigrate, edgeEntry{
@@ -172,7 +172,7 @@ func (s *BranchStore) migrateBranch(_ context.Context, branch string, repoRoots
 	defer func() { s.writeBranch = origBranch }()
 
 	for _, ne := range nodesToMigrate {
-		err := s.db.Update(func(txn *badger.Txn) error {
+		err := s.update(func(txn *badger.Txn) error {
 			// Delete old node and its indexes.
 			if err := deleteNodeInTxn(txn, branch, ne.oldID); err != nil {
 				// Node may have already been deleted if it shared an ID; skip.
@@ -190,29 +190,8 @@ func (s *BranchStore) migrateBranch(_ context.Context, branch string, repoRoots
 		if err != nil {
 			return fmt.Errorf("marshal node %s: %w", ne.newID, err)
 		}
-		err = s.db.Update(func(txn *badger.Txn) error {
-			if err := txn.Set(nodeKey(branch, ne.newID), data); err != nil {
-				return err
-			}
-			if err := txn.Set(indexTypeKey(branch, ne.node.Type, ne.newID), nil); err != nil {
-				return err
-			}
-			if ne.node.FilePath != "" {
-				if err := txn.Set(indexFileKey(branch, ne.node.FilePath, ne.newID), nil); err != nil {
-					return err
-				}
-			}
-			if ne.node.Package != "" {
-				if err := txn.Set(indexPkgKey(branch, ne.node.Package, ne.newID), nil); err != nil {
-					return err
-				}
-			}
-			if role := nodeArchRole(ne.node); role != "" {
-				if err := txn.Set(indexRoleKey(branch, role, ne.newID), nil); err != nil {
-					return err
-				}
-			}
-			return nil
+		err = s.update(func(txn *badger.Txn) error {
+			return setNode(txn.Set, branch, ne.node, data)
 		})
 		if err != nil {
 			return fmt.Errorf("write new node %s: %w", ne.newID, err)
@@ -221,7 +200,7 @@ func (s *BranchStore) migrateBranch(_ context.Context, branch string, repoRoots
 
 	// Pass 4: Delete old edges and write new ones.
 	for _, ee := range edgesToMigrate {
-		err := s.db.Update(func(txn *badger.Txn) error {
+		err := s.update(func(txn *badger.Txn) error {
 			// Try to delete old edge (may fail if node deletion already cascaded).
 			_ = deleteEdgeInTxn(txn, branch, ee.oldID)
 			return nil
@@ -234,14 +213,8 @@ func (s *BranchStore) migrateBranch(_ context.Context, branch string, repoRoots
 		if err != nil {
 			return fmt.Errorf("marshal edge %s: %w", ee.newID, err)
 		}
-		err = s.db.Update(func(txn *badger.Txn) error {
-			if err := txn.Set(edgeKey(branch, ee.newID), data); err != nil {
-				return err
-			}
-			if err := txn.Set(indexEdgeKey(branch, ee.edge.SourceID, ee.edge.Type, ee.newID), nil); err != nil {
-				return err
-			}
-			return txn.Set(indexReverseEdgeKey(branch, ee.edge.TargetID, ee.edge.Type, ee.newID), nil)
+		err = s.update(func(txn *badger.Txn) error {
+			return setEdge(txn.Set, branch, ee.edge, data)
 		})
 		if err != nil {
 			return fmt.Errorf("write new edge %s: %w", ee.newID, err)
@@ -261,3 +234,61 @@ func toRelPath(absPath string, roots []string) string {
 	}
 	return absPath
 }
+
+// MigrateEdgeIDs rewrites edges whose IDs follow the version 1 scheme
+// (NewNodeID applied to the edge) to graph.NewEdgeID IDs in all branches,
+// so graphs built before graph.IDScheme 2 don't collect duplicates when
+// the linker or a re-index writes the same edges again. An old edge whose
+// new ID is already present is dropped. Nested symbols get their scoped IDs
+// from a full re-index.
+//
+// If dryRun is true, no writes are performed and only the result stats are returned.
+func (s *BranchStore) MigrateEdgeIDs(_ context.Context, dryRun bool) (*MigrateResult, error) {
+	result := &MigrateResult{}
+
+	branches, err := s.listKeyBranches()
+	if err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	result.BranchesFound = branches
+
+	for _, branch := range branches {
+		var legacy []*graph.Edge
+		err := s.db.View(func(txn *badger.Txn) error {
+			return scanBranchEdges(txn, branch, func(edge *graph.Edge) bool {
+				result.EdgesScanned++
+				if graph.MigrateEdgeID(edge) != edge.ID {
+					legacy = append(legacy, edge)
+				}
+				return true
+			})
+		})
+		if err != nil {
+			return result, fmt.Errorf("scan edges of branch %s: %w", branch, err)
+		}
+		result.EdgesRemapped += len(legacy)
+		if dryRun {
+			continue
+		}
+
+		for _, edge := range legacy {
+			oldID := edge.ID
+			edge.ID = graph.MigrateEdgeID(edge)
+			data, err := json.Marshal(edge)
+			if err != nil {
+				return result, fmt.Errorf("marshal edge %s: %w", edge.ID, err)
+			}
+			err = s.update(func(txn *badger.Txn) error {
+				if err := deleteEdgeInTxn(txn, branch, oldID); err != nil {
+					return err
+				}
+				return setEdge(txn.Set, branch, edge, data)
+			})
+			if err != nil {
+				return result, fmt.Errorf("migrate edge %s: %w", oldID, err)
+			}
+		}
+	}
+
+	return result, nil
+}
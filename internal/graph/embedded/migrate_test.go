@@ -200,3 +200,53 @@ func TestMigrateAlreadyRelative(t *testing.T) {
 		t.Errorf("expected 0 migrated nodes for already-relative paths, got %d", result.NodesMigrated)
 	}
 }
+
+func TestMigrateEdgeIDs(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	for _, n := range []*graph.Node{
+		{ID: "a", Type: graph.NodeFunction, Name: "a", FilePath: "a.go"},
+		{ID: "b", Type: graph.NodeFunction, Name: "b", FilePath: "b.go"},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	legacy := &graph.Edge{ID: graph.NewNodeID(string(graph.EdgeCalls), "a", "b"), Type: graph.EdgeCalls, SourceID: "a", TargetID: "b"}
+	custom := &graph.Edge{ID: "a->b", Type: graph.EdgeContains, SourceID: "a", TargetID: "b"}
+	for _, e := range []*graph.Edge{legacy, custom} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dry, err := store.MigrateEdgeIDs(ctx, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.EdgesScanned != 2 || dry.EdgesRemapped != 1 {
+		t.Errorf("dry run scanned %d, remapped %d; want 2 and 1", dry.EdgesScanned, dry.EdgesRemapped)
+	}
+	if edges, _ := store.GetEdges(ctx, "a", graph.EdgeCalls); len(edges) != 1 || edges[0].ID != legacy.ID {
+		t.Fatalf("dry run changed edges: %v", edges)
+	}
+
+	if _, err := store.MigrateEdgeIDs(ctx, false); err != nil {
+		t.Fatalf("MigrateEdgeIDs: %v", err)
+	}
+	calls, err := store.GetEdges(ctx, "a", graph.EdgeCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := graph.NewEdgeID(graph.EdgeCalls, "a", "b"); len(calls) != 1 || calls[0].ID != want {
+		t.Errorf("calls edges = %v, want one with ID %s", calls, want)
+	}
+	if in, _ := store.GetNeighbors(ctx, "b", graph.EdgeCalls, graph.Incoming); len(in) != 1 {
+		t.Errorf("reverse index not migrated: %d incoming callers", len(in))
+	}
+	contains, _ := store.GetEdges(ctx, "a", graph.EdgeContains)
+	if len(contains) != 1 || contains[0].ID != "a->b" {
+		t.Errorf("custom edge ID changed: %v", contains)
+	}
+}
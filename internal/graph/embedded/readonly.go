@@ -0,0 +1,361 @@
+package embedded
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// ErrReadOnly is returned by the write methods of a store opened with
+// OpenReadOnly.
+var ErrReadOnly = errors.New("graph store is read-only")
+
+// update runs fn in a read-write transaction, unless the store is
+// read-only.
+func (s *BranchStore) update(fn func(txn *badger.Txn) error) error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	return s.db.Update(fn)
+}
+
+// ReadOnly reports whether the store was opened with OpenReadOnly.
+func (s *BranchStore) ReadOnly() bool { return s.readOnly }
+
+// snapshotAttempts bounds the retries of a snapshot copy racing a
+// compaction that deletes the tables being linked.
+const snapshotAttempts = 3
+
+// snapshotRoot holds the snapshots of locked DBs, one directory per DB
+// path, shared by the read-only stores of all processes.
+var snapshotRoot = filepath.Join(os.TempDir(), "codeeagle-snapshots")
+
+// snapshotMaxAge bounds how long a snapshot is reused while the writer's
+// MANIFEST is unchanged, i.e. how long writes still in its memtable may
+// stay invisible to readers.
+var snapshotMaxAge = 30 * time.Second
+
+// snapshotTempAge is the age after which an unfinished snapshot left by a
+// crashed reader is pruned.
+const snapshotTempAge = time.Hour
+
+// OpenReadOnly opens the graph store at dbPath for reading, scoped to the
+// given namespace and read branches. Any number of read-only stores may be
+// open at once: Badger shares the directory lock between them. When a
+// writer (sync, watch, the daemon) holds the lock instead, the store reads
+// a snapshot of the DB, so queries work during long index runs; they don't
+// see writes made after the snapshot was taken, which is at most
+// snapshotMaxAge earlier unless the writer flushed since. A DB at an older
+// schema version is also read through a snapshot, migrated there.
+func OpenReadOnly(dbPath, namespace string, readBranches []string) (*BranchStore, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if len(readBranches) == 0 {
+		readBranches = []string{"default"}
+	}
+	s := &BranchStore{namespace: namespace, readOnly: true}
+	s.readBranches = s.qualifyAll(readBranches)
+	s.writeBranch = s.readBranches[0]
+
+	opts := badger.DefaultOptions(dbPath).WithReadOnly(true)
+	opts.Logger = nil
+	db, err := badger.Open(opts)
+	if err == nil {
+		s.db = db
+		version, verr := s.SchemaVersion()
+		if verr == nil && version == LatestSchemaVersion {
+			return s, nil
+		}
+		db.Close()
+	}
+	// Locked by a writer, left with a memtable to replay, or not migrated.
+	if serr := s.openSnapshot(dbPath); serr != nil {
+		if err != nil {
+			return nil, fmt.Errorf("open badger db: %w (snapshot: %v)", err, serr)
+		}
+		return nil, serr
+	}
+	return s, nil
+}
+
+// openSnapshot opens a snapshot of the DB at dbPath, brought to the
+// current schema version. Snapshots are shared: the newest one taken at the
+// DB's current MANIFEST and younger than snapshotMaxAge is reused, so only
+// the first of a run of commands pays for the copy.
+func (s *BranchStore) openSnapshot(dbPath string) error {
+	cache, err := snapshotCacheDir(dbPath)
+	if err != nil {
+		return err
+	}
+	stamp, err := manifestStamp(dbPath)
+	if err != nil {
+		return err
+	}
+	if dir := latestSnapshot(cache, stamp); dir != "" {
+		if err := s.openSnapshotDir(dir); err == nil {
+			return nil
+		}
+	}
+	dir, err := buildSnapshot(dbPath, cache, stamp)
+	if err != nil {
+		return err
+	}
+	return s.openSnapshotDir(dir)
+}
+
+// openSnapshotDir opens the finished snapshot in dir with Badger's shared
+// lock, like any other read-only store.
+func (s *BranchStore) openSnapshotDir(dir string) error {
+	opts := badger.DefaultOptions(dir).WithReadOnly(true)
+	opts.Logger = nil
+	db, err := badger.Open(opts)
+	if err != nil {
+		return fmt.Errorf("open snapshot: %w", err)
+	}
+	s.db, s.snapshotDir = db, dir
+	return nil
+}
+
+// snapshotCacheDir returns the directory holding the snapshots of the DB
+// at dbPath, creating it if needed.
+func snapshotCacheDir(dbPath string) (string, error) {
+	abs, err := filepath.Abs(dbPath)
+	if err != nil {
+		return "", fmt.Errorf("resolve DB path: %w", err)
+	}
+	sum := sha256.Sum256([]byte(abs))
+	dir := filepath.Join(snapshotRoot, hex.EncodeToString(sum[:8]))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create snapshot dir: %w", err)
+	}
+	return dir, nil
+}
+
+// manifestStamp identifies the generation of the DB at dbPath: Badger
+// rewrites its MANIFEST whenever a memtable is flushed or tables are
+// compacted. The schema version is part of it, since snapshots are
+// migrated.
+func manifestStamp(dbPath string) (string, error) {
+	info, err := os.Stat(filepath.Join(dbPath, "MANIFEST"))
+	if err != nil {
+		return "", fmt.Errorf("stat MANIFEST: %w", err)
+	}
+	return fmt.Sprintf("v%d-%x-%x", LatestSchemaVersion, info.Size(), info.ModTime().UnixNano()), nil
+}
+
+// latestSnapshot returns the newest snapshot in cache taken at the given
+// stamp within snapshotMaxAge, or "".
+func latestSnapshot(cache, stamp string) string {
+	entries, err := os.ReadDir(cache)
+	if err != nil {
+		return ""
+	}
+	var best string
+	var bestTaken int64
+	for _, e := range entries {
+		rest, ok := strings.CutPrefix(e.Name(), stamp+"-")
+		if !ok {
+			continue
+		}
+		taken, err := strconv.ParseInt(rest, 16, 64)
+		if err != nil || time.Since(time.Unix(0, taken)) >= snapshotMaxAge {
+			continue
+		}
+		if taken > bestTaken {
+			best, bestTaken = e.Name(), taken
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return filepath.Join(cache, best)
+}
+
+// buildSnapshot copies the DB at dbPath into a new snapshot in cache,
+// migrates it and closes it so it can be opened read-only. Older snapshots
+// are pruned; readers that still have one open keep their files.
+func buildSnapshot(dbPath, cache, stamp string) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt < snapshotAttempts; attempt++ {
+		tmp, err := os.MkdirTemp(cache, "tmp-")
+		if err != nil {
+			return "", fmt.Errorf("create snapshot dir: %w", err)
+		}
+		if err := snapshotDB(dbPath, tmp); err != nil {
+			os.RemoveAll(tmp)
+			lastErr = err
+			continue
+		}
+		opts := badger.DefaultOptions(tmp)
+		opts.Logger = nil
+		db, err := badger.Open(opts)
+		if err != nil {
+			os.RemoveAll(tmp)
+			lastErr = err
+			continue
+		}
+		err = (&BranchStore{db: db}).migrate()
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.RemoveAll(tmp)
+			return "", err
+		}
+		dir := filepath.Join(cache, fmt.Sprintf("%s-%x", stamp, time.Now().UnixNano()))
+		if err := os.Rename(tmp, dir); err != nil {
+			os.RemoveAll(tmp)
+			return "", fmt.Errorf("store snapshot: %w", err)
+		}
+		pruneSnapshots(cache, dir)
+		return dir, nil
+	}
+	return "", fmt.Errorf("snapshot graph DB: %w", lastErr)
+}
+
+// pruneSnapshots removes the snapshots in cache other than keep, and the
+// unfinished ones of crashed readers. Errors are ignored: a snapshot still
+// open elsewhere is removed by the next reader that can.
+func pruneSnapshots(cache, keep string) {
+	entries, err := os.ReadDir(cache)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		path := filepath.Join(cache, e.Name())
+		if path == keep {
+			continue
+		}
+		if strings.HasPrefix(e.Name(), "tmp-") {
+			info, err := e.Info()
+			if err != nil || time.Since(info.ModTime()) < snapshotTempAge {
+				continue
+			}
+		}
+		_ = os.RemoveAll(path)
+	}
+}
+
+// snapshotDB copies the Badger DB in src to dst while a writer may still
+// be using it, in an order that keeps the copy consistent:
+//
+//   - memtable logs (.mem) first: a log flushed meanwhile is gone, but its
+//     table is already listed in the MANIFEST copied next;
+//   - the MANIFEST, then the value logs, which hold every value the copied
+//     memtables and tables point to;
+//   - tables last, hard-linked: they are immutable. A table the MANIFEST
+//     lists but a compaction removed meanwhile fails the copy, to retry;
+//     tables newer than the MANIFEST are dropped by Badger on open.
+//
+// The log being appended to may end in a torn entry; Badger checks the
+// entry checksums on replay and truncates the copy after the last whole
+// one.
+func snapshotDB(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("read DB dir: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".mem") {
+			continue
+		}
+		err := copyFile(filepath.Join(src, name), filepath.Join(dst, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if err := copyFile(filepath.Join(src, "MANIFEST"), filepath.Join(dst, "MANIFEST")); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || name == "MANIFEST" || name == "LOCK" ||
+			strings.HasSuffix(name, ".mem") || strings.HasSuffix(name, ".sst") {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, name), filepath.Join(dst, name)); err != nil {
+			return err
+		}
+	}
+
+	entries, err = os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("read DB dir: %w", err)
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".sst") {
+			continue
+		}
+		from, to := filepath.Join(src, name), filepath.Join(dst, name)
+		if err := os.Link(from, to); err == nil {
+			continue
+		}
+		if err := copyFile(from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyBlock is the unit copyFile reads.
+const copyBlock = 1 << 20
+
+// copyFile copies src to dst in full. Badger preallocates its value log and
+// memtable files, so all-zero blocks are skipped rather than written,
+// leaving the copy sparse.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", src, err)
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", src, err)
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("create %s: %w", dst, err)
+	}
+
+	buf := make([]byte, copyBlock)
+	zero := make([]byte, copyBlock)
+	var off int64
+	for {
+		n, err := io.ReadFull(in, buf)
+		if n > 0 && !bytes.Equal(buf[:n], zero[:n]) {
+			if _, werr := out.WriteAt(buf[:n], off); werr != nil {
+				out.Close()
+				return fmt.Errorf("write %s: %w", dst, werr)
+			}
+		}
+		off += int64(n)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			out.Close()
+			return fmt.Errorf("read %s: %w", src, err)
+		}
+	}
+	// The file may have grown while it was read; keep what was read.
+	if err := out.Truncate(off); err != nil {
+		out.Close()
+		return fmt.Errorf("extend %s: %w", dst, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,200 @@
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestOpenReadOnlyWhileWriterOpen(t *testing.T) {
+	useSnapshotRoot(t)
+	dbPath := t.TempDir()
+	ctx := context.Background()
+
+	w, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.AddNode(ctx, &graph.Node{ID: "n1", Type: graph.NodeFunction, Name: "Run", Language: "go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := OpenReadOnly(dbPath, "", []string{"default"})
+	if err != nil {
+		t.Fatalf("OpenReadOnly with a writer open: %v", err)
+	}
+	if r.snapshotDir == "" {
+		t.Error("reader of a locked DB should read a snapshot")
+	}
+	nodes, err := r.QueryNodes(ctx, graph.NodeFilter{Language: "go"})
+	if err != nil || len(nodes) != 1 {
+		t.Errorf("QueryNodes = %v, %v; want n1", nodes, err)
+	}
+	if err := r.AddNode(ctx, &graph.Node{ID: "n2", Type: graph.NodeFunction}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddNode on a read-only store = %v, want ErrReadOnly", err)
+	}
+
+	// The writer keeps writing; the snapshot doesn't change.
+	if err := w.AddNode(ctx, &graph.Node{ID: "n3", Type: graph.NodeFunction, Name: "Stop", Language: "go"}); err != nil {
+		t.Fatal(err)
+	}
+	if nodes, _ := r.QueryNodes(ctx, graph.NodeFilter{Language: "go"}); len(nodes) != 1 {
+		t.Errorf("snapshot sees %d nodes, want 1", len(nodes))
+	}
+
+	// The next reader reuses the snapshot rather than copying the DB again.
+	dir := r.snapshotDir
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r2, err := OpenReadOnly(dbPath, "", []string{"default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r2.snapshotDir != dir {
+		t.Errorf("second reader snapshot = %s, want reused %s", r2.snapshotDir, dir)
+	}
+	r2.Close()
+
+	// Once the snapshot is too old, a new one is taken and the old pruned.
+	snapshotMaxAge = 0
+	r3, err := OpenReadOnly(dbPath, "", []string{"default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r3.Close()
+	if r3.snapshotDir == dir {
+		t.Error("stale snapshot reused")
+	}
+	if nodes, _ := r3.QueryNodes(ctx, graph.NodeFilter{Language: "go"}); len(nodes) != 2 {
+		t.Errorf("new snapshot sees %d nodes, want 2", len(nodes))
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("stale snapshot left behind: %v", err)
+	}
+}
+
+// TestSnapshotWhileWriting takes snapshots while a writer keeps adding
+// nodes: every snapshot opens and holds a prefix of the writes.
+func TestSnapshotWhileWriting(t *testing.T) {
+	useSnapshotRoot(t)
+	snapshotMaxAge = 0
+	dbPath := t.TempDir()
+	ctx := context.Background()
+
+	w, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if err := w.AddNode(ctx, &graph.Node{ID: "n0", Type: graph.NodeFunction, Name: "f0", Language: "go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const writes = 2000
+	done := make(chan error, 1)
+	go func() {
+		for i := 1; i < writes; i++ {
+			n := &graph.Node{ID: fmt.Sprintf("n%d", i), Type: graph.NodeFunction, Name: fmt.Sprintf("f%d", i), Language: "go",
+				Properties: map[string]string{"body": strings.Repeat("x", 512)}}
+			if err := w.AddNode(ctx, n); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	for i := 0; i < 5; i++ {
+		r, err := OpenReadOnly(dbPath, "", []string{"default"})
+		if err != nil {
+			t.Fatalf("snapshot %d: %v", i, err)
+		}
+		nodes, err := r.QueryNodes(ctx, graph.NodeFilter{Language: "go"})
+		r.Close()
+		if err != nil {
+			t.Fatalf("snapshot %d: QueryNodes: %v", i, err)
+		}
+		seen := make(map[string]bool, len(nodes))
+		for _, n := range nodes {
+			seen[n.ID] = true
+		}
+		for j := 0; j < len(nodes); j++ {
+			if id := fmt.Sprintf("n%d", j); !seen[id] {
+				t.Errorf("snapshot %d has %d nodes but misses %s", i, len(nodes), id)
+				break
+			}
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyFileKeepsZeroBlocks(t *testing.T) {
+	dir := t.TempDir()
+	src, dst := filepath.Join(dir, "000001.vlog"), filepath.Join(dir, "copy.vlog")
+	data := append([]byte("head"), make([]byte, 2*copyBlock)...)
+	data = append(data, "tail"...)
+	if err := os.WriteFile(src, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := copyFile(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("copy has %d bytes, want %d ending in %q", len(got), len(data), "tail")
+	}
+}
+
+// useSnapshotRoot keeps the snapshots a test takes in its temp dir.
+func useSnapshotRoot(t *testing.T) {
+	t.Helper()
+	root, maxAge := snapshotRoot, snapshotMaxAge
+	snapshotRoot = t.TempDir()
+	t.Cleanup(func() { snapshotRoot, snapshotMaxAge = root, maxAge })
+}
+
+func TestOpenReadOnlyConcurrentReaders(t *testing.T) {
+	dbPath := t.TempDir()
+	ctx := context.Background()
+
+	w, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddNode(ctx, &graph.Node{ID: "n1", Type: graph.NodeFunction, Name: "Run"}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	var readers []*BranchStore
+	for i := 0; i < 2; i++ {
+		r, err := OpenReadOnly(dbPath, "", []string{"default"})
+		if err != nil {
+			t.Fatalf("reader %d: %v", i, err)
+		}
+		defer r.Close()
+		if r.snapshotDir != "" {
+			t.Errorf("reader %d read a snapshot of an unlocked DB", i)
+		}
+		readers = append(readers, r)
+	}
+	for i, r := range readers {
+		if n, err := r.GetNode(ctx, "n1"); err != nil || n.Name != "Run" {
+			t.Errorf("reader %d GetNode = %v, %v", i, n, err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// migration upgrades a DB from the previous schema version to version.
+// Migrations run in order when a store is opened, each at most once per
+// DB, so a change to the key scheme or to node and edge shapes upgrades
+// existing graphs instead of requiring a full re-index. Append new
+// migrations to the list; never reorder or remove one.
+type migration struct {
+	version int
+	name    string
+	run     func(s *BranchStore) error
+}
+
+var migrations = []migration{
+	{1, "index edge types", (*BranchStore).indexEdgeTypes},
+	{2, "index node language, name and kind", (*BranchStore).indexNodeFields},
+	{3, "rewrite version 1 edge IDs", func(s *BranchStore) error {
+		_, err := s.MigrateEdgeIDs(context.Background(), false)
+		return err
+	}},
+}
+
+// LatestSchemaVersion is the schema version of DBs written by this build.
+var LatestSchemaVersion = migrations[len(migrations)-1].version
+
+// SchemaVersion returns the schema version recorded in the DB.
+func (s *BranchStore) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.View(func(txn *badger.Txn) error {
+		v, err := readSchemaVersion(txn)
+		version = v
+		return err
+	})
+	return version, err
+}
+
+// migrate brings the DB to LatestSchemaVersion. A new DB is stamped with it
+// directly; a DB written before schema versions starts at version 0, or 1
+// when its edge type index was already backfilled. A DB of a newer
+// version than this build knows is refused, as its keys may not be read
+// correctly.
+func (s *BranchStore) migrate() error {
+	var version int
+	err := s.db.View(func(txn *badger.Txn) error {
+		v, err := readSchemaVersion(txn)
+		if err != badger.ErrKeyNotFound {
+			version = v
+			return err
+		}
+		switch {
+		case isEmptyDB(txn):
+			version = LatestSchemaVersion
+		case hasKey(txn, keyEdgeTypeIndexed):
+			version = 1
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if version > LatestSchemaVersion {
+		return fmt.Errorf("graph DB schema version %d is newer than %d supported by this build; upgrade codeeagle", version, LatestSchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		if err := m.run(s); err != nil {
+			return fmt.Errorf("migrate graph DB to schema version %d (%s): %w", m.version, m.name, err)
+		}
+		version = m.version
+		if err := s.writeSchemaVersion(version); err != nil {
+			return err
+		}
+	}
+	return s.writeSchemaVersion(version)
+}
+
+func readSchemaVersion(txn *badger.Txn) (int, error) {
+	item, err := txn.Get([]byte(keySchemaVersion))
+	if err != nil {
+		return 0, err
+	}
+	var version int
+	err = item.Value(func(val []byte) error {
+		v, err := strconv.Atoi(string(val))
+		version = v
+		return err
+	})
+	return version, err
+}
+
+func (s *BranchStore) writeSchemaVersion(version int) error {
+	err := s.update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(keySchemaVersion), []byte(strconv.Itoa(version)))
+	})
+	if err != nil {
+		return fmt.Errorf("write schema version: %w", err)
+	}
+	return nil
+}
+
+// isEmptyDB reports whether the DB holds no node or edge.
+func isEmptyDB(txn *badger.Txn) bool {
+	return !hasPrefix(txn, []byte(prefixNode)) && !hasPrefix(txn, []byte(prefixEdge))
+}
+
+func hasKey(txn *badger.Txn, key string) bool {
+	_, err := txn.Get([]byte(key))
+	return err == nil
+}
+
+func hasPrefix(txn *badger.Txn, prefix []byte) bool {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	opts.Prefix = prefix
+	it := txn.NewIterator(opts)
+	defer it.Close()
+	it.Seek(prefix)
+	return it.Valid()
+}
+
+// indexEdgeTypes backfills the edge type index.
+func (s *BranchStore) indexEdgeTypes() error {
+	return s.backfillIndex(prefixEdge, func(branch string, val []byte) ([][]byte, error) {
+		var edge graph.Edge
+		if err := json.Unmarshal(val, &edge); err != nil {
+			return nil, err
+		}
+		return [][]byte{indexEdgeTypeKey(branch, edge.Type, edge.ID)}, nil
+	})
+}
+
+// indexNodeFields backfills the language, name and property indexes.
+func (s *BranchStore) indexNodeFields() error {
+	return s.backfillIndex(prefixNode, func(branch string, val []byte) ([][]byte, error) {
+		var node graph.Node
+		if err := json.Unmarshal(val, &node); err != nil {
+			return nil, err
+		}
+		return nodeFieldIndexKeys(branch, &node), nil
+	})
+}
+
+// backfillIndex writes the index keys keysOf returns for every record
+// under prefix (nodes or edges, keyed <prefix><branch>:<id>). Records
+// that don't decode are skipped.
+func (s *BranchStore) backfillIndex(prefix string, keysOf func(branch string, val []byte) ([][]byte, error)) error {
+	var keys [][]byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(prefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Seek(opts.Prefix); it.Valid(); it.Next() {
+			rest := string(it.Item().Key()[len(prefix):])
+			idx := strings.Index(rest, ":")
+			if idx <= 0 {
+				continue
+			}
+			var recordKeys [][]byte
+			if err := it.Item().Value(func(val []byte) error {
+				k, err := keysOf(rest[:idx], val)
+				recordKeys = k
+				return err
+			}); err != nil {
+				continue
+			}
+			keys = append(keys, recordKeys...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, k := range keys {
+		if err := wb.Set(k, nil); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
@@ -0,0 +1,97 @@
+package embedded
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/badger/v4"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestSchemaVersionNewDB(t *testing.T) {
+	s := newTestStore(t)
+	version, err := s.SchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != LatestSchemaVersion {
+		t.Errorf("SchemaVersion() = %d, want %d", version, LatestSchemaVersion)
+	}
+}
+
+func TestMigrationsOrdered(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %q has version %d, want %d", m.name, m.version, i+1)
+		}
+	}
+}
+
+func TestMigrateLegacyDB(t *testing.T) {
+	dbPath := t.TempDir()
+	ctx := context.Background()
+
+	s, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := s.AddNode(ctx, &graph.Node{ID: id, Type: graph.NodeFunction, Name: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	legacy := &graph.Edge{ID: graph.NewNodeID(string(graph.EdgeCalls), "a", "b"), Type: graph.EdgeCalls, SourceID: "a", TargetID: "b"}
+	if err := s.AddEdge(ctx, legacy); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a DB written before schema versions, with its edge type
+	// index backfilled.
+	if err := s.deleteKeysByPrefix([]byte(keySchemaVersion)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.db.Update(func(txn *badger.Txn) error { return txn.Set([]byte(keyEdgeTypeIndexed), nil) }); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewStore(dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.Close()
+	version, err := s.SchemaVersion()
+	if err != nil || version != LatestSchemaVersion {
+		t.Errorf("SchemaVersion() = %d, %v; want %d", version, err, LatestSchemaVersion)
+	}
+	edges, err := s.GetEdges(ctx, "a", graph.EdgeCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := graph.NewEdgeID(graph.EdgeCalls, "a", "b")
+	if len(edges) != 1 || edges[0].ID != want {
+		t.Errorf("edges after migration = %v, want one with ID %s", edges, want)
+	}
+}
+
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+	dbPath := t.TempDir()
+
+	s, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.writeSchemaVersion(LatestSchemaVersion + 1); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewStore(dbPath)
+	if err == nil {
+		s.Close()
+		t.Fatal("opening a DB of a newer schema version succeeded")
+	}
+	if !strings.Contains(err.Error(), "newer") {
+		t.Errorf("error = %v, want a newer schema version error", err)
+	}
+}
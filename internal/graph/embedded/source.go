@@ -0,0 +1,36 @@
+package embedded
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// SetSourceRoots sets the repository roots that GetSource resolves the
+// graph's relative file paths against. Views made afterwards share them.
+func (s *BranchStore) SetSourceRoots(roots ...string) {
+	s.source = graph.NewSourceReader(roots...)
+}
+
+// GetSource returns the source text of a node's span, read lazily from its
+// file. It returns graph.ErrNoSource when no roots are set, the node has
+// no span, or its file cannot be read.
+func (s *BranchStore) GetSource(ctx context.Context, nodeID string) (string, error) {
+	if s.source == nil {
+		return "", graph.ErrNoSource
+	}
+	n, err := s.GetNode(ctx, nodeID)
+	if err != nil {
+		return "", fmt.Errorf("get source of %s: %w", nodeID, err)
+	}
+	return s.source.Read(n)
+}
+
+// GetSourceLines returns lines start to end of a file by its graph path.
+func (s *BranchStore) GetSourceLines(_ context.Context, filePath string, start, end int) (string, error) {
+	if s.source == nil {
+		return "", graph.ErrNoSource
+	}
+	return s.source.Lines(filePath, start, end)
+}
@@ -3,8 +3,10 @@ package embedded
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/dgraph-io/badger/v4"
@@ -21,26 +23,70 @@ const (
 	prefixIdxEdge        = "idx:edge:"
 	prefixIdxReverseEdge = "idx:redge:"
 	prefixIdxRole        = "idx:role:"
+	prefixIdxEdgeType    = "idx:etype:"
+	prefixIdxLang        = "idx:lang:"
+	prefixIdxName        = "idx:name:"
+	prefixIdxProp        = "idx:prop:"
+
+	// keySchemaVersion holds the DB's schema version (see SchemaVersion).
+	keySchemaVersion = "meta:schema-version"
+	// keyEdgeTypeIndexed marks a DB written before schema versions whose
+	// edges all have type index keys: schema version 1.
+	keyEdgeTypeIndexed = "meta:edge-type-index"
 )
 
+// indexedProperties are the node properties with a secondary index, so
+// that queries filtering on them scan only the matching nodes.
+var indexedProperties = []string{"kind"}
+
 // BranchStore implements graph.Store using BadgerDB with branch-aware key prefixes.
 // All keys are prefixed with the branch name, enabling N-branch support in a single DB.
+//
+// A store may also be scoped to a namespace, isolating the graphs of one
+// tenant (a team or repository) from the others in the same DB. The
+// namespace is folded into the branch segment of the keys as
+// <namespace>~<branch>; git refs cannot contain "~", so the default
+// namespace keeps the plain branch keys of DBs written before namespaces.
 type BranchStore struct {
 	db           *badger.DB
-	writeBranch  string
-	readBranches []string // ordered by priority; first branch wins for duplicate IDs
+	readOnly     bool   // writes fail with ErrReadOnly
+	snapshotDir  string // shared snapshot read instead of a locked DB
+	namespace    string
+	writeBranch  string              // qualified with the namespace
+	readBranches []string            // qualified; ordered by priority, first branch wins for duplicate IDs
+	source       *graph.SourceReader // nil until SetSourceRoots
 }
 
+// namespaceSep separates the namespace from the branch in keys.
+const namespaceSep = "~"
+
 // NewBranchStore opens (or creates) a BadgerDB-backed graph store at dbPath with
 // the given write branch and read branches (ordered by priority).
 func NewBranchStore(dbPath, writeBranch string, readBranches []string) (*BranchStore, error) {
+	return NewNamespacedStore(dbPath, "", writeBranch, readBranches)
+}
+
+// NewNamespacedStore opens (or creates) a graph store at dbPath scoped to the
+// given namespace. An empty namespace is the default one used by
+// NewBranchStore.
+func NewNamespacedStore(dbPath, namespace, writeBranch string, readBranches []string) (*BranchStore, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
 	opts := badger.DefaultOptions(dbPath)
 	opts.Logger = nil // suppress badger logs
 	db, err := badger.Open(opts)
 	if err != nil {
 		return nil, fmt.Errorf("open badger db: %w", err)
 	}
-	return &BranchStore{db: db, writeBranch: writeBranch, readBranches: readBranches}, nil
+	s := &BranchStore{db: db, namespace: namespace}
+	s.writeBranch = s.qualify(writeBranch)
+	s.readBranches = s.qualifyAll(readBranches)
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
 }
 
 // NewStore opens (or creates) a BadgerDB-backed graph store at dbPath.
@@ -50,10 +96,96 @@ func NewStore(dbPath string) (*BranchStore, error) {
 }
 
 // WriteBranch returns the branch used for write operations.
-func (s *BranchStore) WriteBranch() string { return s.writeBranch }
+func (s *BranchStore) WriteBranch() string { return s.unqualify(s.writeBranch) }
 
 // ReadBranches returns the ordered list of branches used for read operations.
-func (s *BranchStore) ReadBranches() []string { return s.readBranches }
+func (s *BranchStore) ReadBranches() []string {
+	branches := make([]string, len(s.readBranches))
+	for i, b := range s.readBranches {
+		branches[i] = s.unqualify(b)
+	}
+	return branches
+}
+
+// WithReadBranches returns a view of the same DB reading the given branches.
+// The view shares the underlying DB handle: close the original store, not
+// the view.
+func (s *BranchStore) WithReadBranches(readBranches []string) *BranchStore {
+	return &BranchStore{db: s.db, readOnly: s.readOnly, namespace: s.namespace, writeBranch: s.writeBranch, readBranches: s.qualifyAll(readBranches), source: s.source}
+}
+
+// WithBranch returns a view of the same DB that reads and writes only the
+// given branch. Close the original store, not the view.
+func (s *BranchStore) WithBranch(branch string) *BranchStore {
+	b := s.qualify(branch)
+	return &BranchStore{db: s.db, readOnly: s.readOnly, namespace: s.namespace, writeBranch: b, readBranches: []string{b}, source: s.source}
+}
+
+// Namespace returns the namespace the store is scoped to; empty for the
+// default namespace.
+func (s *BranchStore) Namespace() string { return s.namespace }
+
+// WithNamespace returns a view of the same DB with the same write and read
+// branches in another namespace, so a server can serve many tenants from
+// one DB. Close the original store, not the view.
+func (s *BranchStore) WithNamespace(namespace string) (*BranchStore, error) {
+	if err := ValidateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	v := &BranchStore{db: s.db, readOnly: s.readOnly, namespace: namespace, source: s.source}
+	v.writeBranch = v.qualify(s.WriteBranch())
+	v.readBranches = v.qualifyAll(s.ReadBranches())
+	return v, nil
+}
+
+// ValidateNamespace checks that a namespace name can be used in keys: it
+// must start with a letter or digit and contain only letters, digits and
+// "._-/". The empty (default) namespace is valid.
+func ValidateNamespace(namespace string) error {
+	for i, r := range namespace {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case i > 0 && strings.ContainsRune("._-/", r):
+		default:
+			return fmt.Errorf("invalid namespace %q: use letters, digits and ._-/, starting with a letter or digit", namespace)
+		}
+	}
+	return nil
+}
+
+// qualify returns the key branch segment of a branch in the store's namespace.
+func (s *BranchStore) qualify(branch string) string {
+	if s.namespace == "" {
+		return branch
+	}
+	return s.namespace + namespaceSep + branch
+}
+
+func (s *BranchStore) qualifyAll(branches []string) []string {
+	out := make([]string, len(branches))
+	for i, b := range branches {
+		out[i] = s.qualify(b)
+	}
+	return out
+}
+
+// unqualify strips the store's namespace from a key branch segment.
+func (s *BranchStore) unqualify(branch string) string {
+	if s.namespace == "" {
+		return branch
+	}
+	return strings.TrimPrefix(branch, s.namespace+namespaceSep)
+}
+
+// inNamespace reports whether a key branch segment belongs to the store's
+// namespace.
+func (s *BranchStore) inNamespace(branch string) bool {
+	ns, _, found := strings.Cut(branch, namespaceSep)
+	if !found {
+		return s.namespace == ""
+	}
+	return ns == s.namespace
+}
 
 // --- branch-aware key functions ---
 
@@ -88,11 +220,71 @@ func indexReverseEdgeKey(branch, targetID string, edgeType graph.EdgeType, edgeI
 	return []byte(fmt.Sprintf("%s%s:%s:%s:%s", prefixIdxReverseEdge, branch, targetID, edgeType, edgeID))
 }
 
+// indexEdgeTypeKey returns the key indexing an edge by its type.
+func indexEdgeTypeKey(branch string, edgeType graph.EdgeType, edgeID string) []byte {
+	return []byte(fmt.Sprintf("%s%s:%s:%s", prefixIdxEdgeType, branch, edgeType, edgeID))
+}
+
 // indexRoleKey returns a secondary index key for architectural role lookup.
 func indexRoleKey(branch, role, id string) []byte {
 	return []byte(fmt.Sprintf("%s%s:%s:%s", prefixIdxRole, branch, role, id))
 }
 
+// indexLangKey returns a secondary index key for language lookup.
+func indexLangKey(branch, language, id string) []byte {
+	return []byte(fmt.Sprintf("%s%s:%s:%s", prefixIdxLang, branch, language, id))
+}
+
+// indexNameKey returns a secondary index key for name lookup. Names are
+// escaped so that a name's key prefix never matches a longer name's
+// ":"-separated tail.
+func indexNameKey(branch, name, id string) []byte {
+	return []byte(fmt.Sprintf("%s%s:%s:%s", prefixIdxName, branch, escapeIndexValue(name), id))
+}
+
+// indexPropKey returns a secondary index key for property lookup.
+func indexPropKey(branch, key, value, id string) []byte {
+	return []byte(fmt.Sprintf("%s%s:%s=%s:%s", prefixIdxProp, branch, key, escapeIndexValue(value), id))
+}
+
+// indexValueEscaper escapes ":" in index values.
+var indexValueEscaper = strings.NewReplacer("%", "%25", ":", "%3A")
+
+func escapeIndexValue(v string) string { return indexValueEscaper.Replace(v) }
+
+// nodeIndexKeys returns every secondary index key of a node.
+func nodeIndexKeys(branch string, node *graph.Node) [][]byte {
+	keys := [][]byte{indexTypeKey(branch, node.Type, node.ID)}
+	if node.FilePath != "" {
+		keys = append(keys, indexFileKey(branch, node.FilePath, node.ID))
+	}
+	if node.Package != "" {
+		keys = append(keys, indexPkgKey(branch, node.Package, node.ID))
+	}
+	if role := nodeArchRole(node); role != "" {
+		keys = append(keys, indexRoleKey(branch, role, node.ID))
+	}
+	return append(keys, nodeFieldIndexKeys(branch, node)...)
+}
+
+// nodeFieldIndexKeys returns a node's language, name and property index
+// keys.
+func nodeFieldIndexKeys(branch string, node *graph.Node) [][]byte {
+	var keys [][]byte
+	if node.Language != "" {
+		keys = append(keys, indexLangKey(branch, node.Language, node.ID))
+	}
+	if node.Name != "" {
+		keys = append(keys, indexNameKey(branch, node.Name, node.ID))
+	}
+	for _, k := range indexedProperties {
+		if v := node.Properties[k]; v != "" {
+			keys = append(keys, indexPropKey(branch, k, v, node.ID))
+		}
+	}
+	return keys
+}
+
 // nodeArchRole extracts the architectural role from a node's properties.
 func nodeArchRole(n *graph.Node) string {
 	if n.Properties == nil {
@@ -102,35 +294,56 @@ func nodeArchRole(n *graph.Node) string {
 }
 
 func (s *BranchStore) AddNode(_ context.Context, node *graph.Node) error {
-	b := s.writeBranch
 	data, err := json.Marshal(node)
 	if err != nil {
 		return fmt.Errorf("marshal node: %w", err)
 	}
-	return s.db.Update(func(txn *badger.Txn) error {
-		if err := txn.Set(nodeKey(b, node.ID), data); err != nil {
+	return s.update(func(txn *badger.Txn) error {
+		return setNode(txn.Set, s.writeBranch, node, data)
+	})
+}
+
+// setNode writes a node and its index keys with set.
+func setNode(set func(key, value []byte) error, b string, node *graph.Node, data []byte) error {
+	if err := set(nodeKey(b, node.ID), data); err != nil {
+		return err
+	}
+	for _, k := range nodeIndexKeys(b, node) {
+		if err := set(k, nil); err != nil {
 			return err
 		}
-		if err := txn.Set(indexTypeKey(b, node.Type, node.ID), nil); err != nil {
-			return err
+	}
+	return nil
+}
+
+// AddBatch implements graph.BatchStore with a single Badger write batch,
+// which splits into as many transactions as the batch needs.
+func (s *BranchStore) AddBatch(_ context.Context, nodes []*graph.Node, edges []*graph.Edge) error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	b := s.writeBranch
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, node := range nodes {
+		data, err := json.Marshal(node)
+		if err != nil {
+			return fmt.Errorf("marshal node: %w", err)
 		}
-		if node.FilePath != "" {
-			if err := txn.Set(indexFileKey(b, node.FilePath, node.ID), nil); err != nil {
-				return err
-			}
+		if err := setNode(wb.Set, b, node, data); err != nil {
+			return err
 		}
-		if node.Package != "" {
-			if err := txn.Set(indexPkgKey(b, node.Package, node.ID), nil); err != nil {
-				return err
-			}
+	}
+	for _, edge := range edges {
+		data, err := json.Marshal(edge)
+		if err != nil {
+			return fmt.Errorf("marshal edge: %w", err)
 		}
-		if role := nodeArchRole(node); role != "" {
-			if err := txn.Set(indexRoleKey(b, role, node.ID), nil); err != nil {
-				return err
-			}
+		if err := setEdge(wb.Set, b, edge, data); err != nil {
+			return err
 		}
-		return nil
-	})
+	}
+	return wb.Flush()
 }
 
 func (s *BranchStore) UpdateNode(_ context.Context, node *graph.Node) error {
@@ -139,54 +352,30 @@ func (s *BranchStore) UpdateNode(_ context.Context, node *graph.Node) error {
 	if err != nil {
 		return fmt.Errorf("marshal node: %w", err)
 	}
-	return s.db.Update(func(txn *badger.Txn) error {
+	return s.update(func(txn *badger.Txn) error {
 		// Read existing node to clean up old indexes if fields changed.
 		old, err := getNodeInTxn(txn, b, node.ID)
 		if err != nil {
 			return fmt.Errorf("get existing node for update: %w", err)
 		}
 		// Remove stale indexes.
-		if old.Type != node.Type {
-			_ = txn.Delete(indexTypeKey(b, old.Type, old.ID))
-		}
-		if old.FilePath != node.FilePath && old.FilePath != "" {
-			_ = txn.Delete(indexFileKey(b, old.FilePath, old.ID))
-		}
-		if old.Package != node.Package && old.Package != "" {
-			_ = txn.Delete(indexPkgKey(b, old.Package, old.ID))
-		}
-		if oldRole := nodeArchRole(old); oldRole != "" && oldRole != nodeArchRole(node) {
-			_ = txn.Delete(indexRoleKey(b, oldRole, old.ID))
-		}
-		// Write new data and indexes.
-		if err := txn.Set(nodeKey(b, node.ID), data); err != nil {
-			return err
-		}
-		if err := txn.Set(indexTypeKey(b, node.Type, node.ID), nil); err != nil {
-			return err
-		}
-		if node.FilePath != "" {
-			if err := txn.Set(indexFileKey(b, node.FilePath, node.ID), nil); err != nil {
-				return err
-			}
+		current := make(map[string]bool)
+		for _, k := range nodeIndexKeys(b, node) {
+			current[string(k)] = true
 		}
-		if node.Package != "" {
-			if err := txn.Set(indexPkgKey(b, node.Package, node.ID), nil); err != nil {
-				return err
-			}
-		}
-		if role := nodeArchRole(node); role != "" {
-			if err := txn.Set(indexRoleKey(b, role, node.ID), nil); err != nil {
-				return err
+		for _, k := range nodeIndexKeys(b, old) {
+			if !current[string(k)] {
+				_ = txn.Delete(k)
 			}
 		}
-		return nil
+		// Write new data and indexes.
+		return setNode(txn.Set, b, node, data)
 	})
 }
 
 func (s *BranchStore) DeleteNode(_ context.Context, id string) error {
 	b := s.writeBranch
-	return s.db.Update(func(txn *badger.Txn) error {
+	return s.update(func(txn *badger.Txn) error {
 		return deleteNodeInTxn(txn, b, id)
 	})
 }
@@ -218,15 +407,8 @@ func deleteNodeInTxn(txn *badger.Txn, branch, id string) error {
 		}
 	}
 	// Delete indexes.
-	_ = txn.Delete(indexTypeKey(branch, node.Type, id))
-	if node.FilePath != "" {
-		_ = txn.Delete(indexFileKey(branch, node.FilePath, id))
-	}
-	if node.Package != "" {
-		_ = txn.Delete(indexPkgKey(branch, node.Package, id))
-	}
-	if role := nodeArchRole(node); role != "" {
-		_ = txn.Delete(indexRoleKey(branch, role, id))
+	for _, k := range nodeIndexKeys(branch, node) {
+		_ = txn.Delete(k)
 	}
 	// Delete the node itself.
 	return txn.Delete(nodeKey(branch, id))
@@ -264,55 +446,83 @@ func getNodeInTxn(txn *badger.Txn, branch, id string) (*graph.Node, error) {
 }
 
 func (s *BranchStore) QueryNodes(_ context.Context, filter graph.NodeFilter) ([]*graph.Node, error) {
-	seen := make(map[string]struct{})
 	var results []*graph.Node
+	err := s.scanNodes(filter, func(node *graph.Node) error {
+		results = append(results, node)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *BranchStore) ScanNodes(_ context.Context, filter graph.NodeFilter, fn func(*graph.Node) error) error {
+	return s.scanNodes(filter, fn)
+}
+
+// errScanLimit ends a scan that reached the filter's limit.
+var errScanLimit = errors.New("scan limit reached")
+
+// scanNodes calls fn for each node matching filter: by branch in read
+// order, then by key, skipping IDs an earlier branch already had. Nodes
+// are read in one transaction per branch, so fn may write to the store.
+func (s *BranchStore) scanNodes(filter graph.NodeFilter, fn func(*graph.Node) error) error {
+	seen := make(map[string]struct{})
+	skip, remaining := filter.Offset, filter.Limit
+	emit := func(node *graph.Node, branch string) error {
+		seen[node.ID] = struct{}{}
+		if skip > 0 {
+			skip--
+			return nil
+		}
+		tagNodeSource(node, branch)
+		if err := fn(node); err != nil {
+			return err
+		}
+		if remaining--; filter.Limit > 0 && remaining == 0 {
+			return errScanLimit
+		}
+		return nil
+	}
 
 	for _, branch := range s.readBranches {
 		var nodeIDs []string
 		var useFullScan bool
 
 		err := s.db.View(func(txn *badger.Txn) error {
-			switch {
-			case filter.FilePath != "":
-				ids, err := scanIndexPrefix(txn, []byte(fmt.Sprintf("%s%s:%s:", prefixIdxFile, branch, filter.FilePath)))
-				if err != nil {
-					return err
-				}
-				nodeIDs = ids
-			case filter.Type != "":
-				ids, err := scanIndexPrefix(txn, []byte(fmt.Sprintf("%s%s:%s:", prefixIdxType, branch, filter.Type)))
-				if err != nil {
-					return err
-				}
-				nodeIDs = ids
-			case filter.Package != "":
-				ids, err := scanIndexPrefix(txn, []byte(fmt.Sprintf("%s%s:%s:", prefixIdxPkg, branch, filter.Package)))
-				if err != nil {
-					return err
-				}
-				nodeIDs = ids
-			default:
+			prefix := nodeIndexPrefix(branch, filter)
+			if prefix == nil {
 				useFullScan = true
+				return nil
 			}
+			ids, err := scanIndexPrefix(txn, prefix)
+			if err != nil {
+				return err
+			}
+			nodeIDs = ids
 			return nil
 		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		err = s.db.View(func(txn *badger.Txn) error {
 			if useFullScan {
-				return scanBranchNodes(txn, branch, func(node *graph.Node) bool {
+				var fnErr error
+				err := scanBranchNodes(txn, branch, func(node *graph.Node) bool {
 					if _, ok := seen[node.ID]; ok {
 						return true // skip, earlier branch already has this ID
 					}
 					if matchesFilter(node, filter) {
-						seen[node.ID] = struct{}{}
-						tagNodeSource(node, branch)
-						results = append(results, node)
+						fnErr = emit(node, branch)
 					}
-					return true
+					return fnErr == nil
 				})
+				if err != nil {
+					return err
+				}
+				return fnErr
 			}
 			for _, id := range nodeIDs {
 				if _, ok := seen[id]; ok {
@@ -323,40 +533,50 @@ func (s *BranchStore) QueryNodes(_ context.Context, filter graph.NodeFilter) ([]
 					continue // index entry for deleted node; skip
 				}
 				if matchesFilter(node, filter) {
-					seen[id] = struct{}{}
-					tagNodeSource(node, branch)
-					results = append(results, node)
+					if err := emit(node, branch); err != nil {
+						return err
+					}
 				}
 			}
 			return nil
 		})
+		if errors.Is(err, errScanLimit) || errors.Is(err, graph.ErrStopScan) {
+			return nil
+		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-	return results, nil
+	return nil
 }
 
 func (s *BranchStore) AddEdge(_ context.Context, edge *graph.Edge) error {
-	b := s.writeBranch
 	data, err := json.Marshal(edge)
 	if err != nil {
 		return fmt.Errorf("marshal edge: %w", err)
 	}
-	return s.db.Update(func(txn *badger.Txn) error {
-		if err := txn.Set(edgeKey(b, edge.ID), data); err != nil {
-			return err
-		}
-		if err := txn.Set(indexEdgeKey(b, edge.SourceID, edge.Type, edge.ID), nil); err != nil {
-			return err
-		}
-		return txn.Set(indexReverseEdgeKey(b, edge.TargetID, edge.Type, edge.ID), nil)
+	return s.update(func(txn *badger.Txn) error {
+		return setEdge(txn.Set, s.writeBranch, edge, data)
 	})
 }
 
+// setEdge writes an edge and its index keys with set.
+func setEdge(set func(key, value []byte) error, b string, edge *graph.Edge, data []byte) error {
+	if err := set(edgeKey(b, edge.ID), data); err != nil {
+		return err
+	}
+	if err := set(indexEdgeKey(b, edge.SourceID, edge.Type, edge.ID), nil); err != nil {
+		return err
+	}
+	if err := set(indexEdgeTypeKey(b, edge.Type, edge.ID), nil); err != nil {
+		return err
+	}
+	return set(indexReverseEdgeKey(b, edge.TargetID, edge.Type, edge.ID), nil)
+}
+
 func (s *BranchStore) DeleteEdge(_ context.Context, id string) error {
 	b := s.writeBranch
-	return s.db.Update(func(txn *badger.Txn) error {
+	return s.update(func(txn *badger.Txn) error {
 		return deleteEdgeInTxn(txn, b, id)
 	})
 }
@@ -375,6 +595,7 @@ func deleteEdgeInTxn(txn *badger.Txn, branch, id string) error {
 	}
 	_ = txn.Delete(indexEdgeKey(branch, edge.SourceID, edge.Type, edge.ID))
 	_ = txn.Delete(indexReverseEdgeKey(branch, edge.TargetID, edge.Type, edge.ID))
+	_ = txn.Delete(indexEdgeTypeKey(branch, edge.Type, edge.ID))
 	return txn.Delete(edgeKey(branch, id))
 }
 
@@ -412,6 +633,71 @@ func (s *BranchStore) GetEdges(_ context.Context, nodeID string, edgeType graph.
 	return results, err
 }
 
+func (s *BranchStore) GetIncomingEdges(_ context.Context, nodeID string, edgeType graph.EdgeType) ([]*graph.Edge, error) {
+	return s.queryEdges(graph.EdgeFilter{Type: edgeType, TargetID: nodeID})
+}
+
+func (s *BranchStore) QueryEdges(_ context.Context, filter graph.EdgeFilter) ([]*graph.Edge, error) {
+	return s.queryEdges(filter)
+}
+
+// queryEdges scans the narrowest index the filter allows: the forward index
+// when the source is known, the reverse index when the target is, the type
+// index when only the type is, and every edge otherwise.
+func (s *BranchStore) queryEdges(filter graph.EdgeFilter) ([]*graph.Edge, error) {
+	seen := make(map[string]struct{})
+	var results []*graph.Edge
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		for _, branch := range s.readBranches {
+			var candidates []*graph.Edge
+			if filter.SourceID == "" && filter.TargetID == "" && filter.Type == "" {
+				err := scanBranchEdges(txn, branch, func(e *graph.Edge) bool {
+					candidates = append(candidates, e)
+					return true
+				})
+				if err != nil {
+					return err
+				}
+			} else {
+				var prefix []byte
+				switch {
+				case filter.SourceID != "":
+					prefix = buildEdgeIndexPrefix(prefixIdxEdge, branch, filter.SourceID, filter.Type)
+				case filter.TargetID != "":
+					prefix = buildEdgeIndexPrefix(prefixIdxReverseEdge, branch, filter.TargetID, filter.Type)
+				default:
+					prefix = []byte(fmt.Sprintf("%s%s:%s:", prefixIdxEdgeType, branch, filter.Type))
+				}
+				ids, err := scanIndexPrefix(txn, prefix)
+				if err != nil {
+					return err
+				}
+				for _, eid := range ids {
+					if _, ok := seen[eid]; ok {
+						continue
+					}
+					e, err := getEdgeInTxn(txn, branch, eid)
+					if err != nil {
+						continue
+					}
+					candidates = append(candidates, e)
+				}
+			}
+			for _, e := range candidates {
+				if _, ok := seen[e.ID]; ok || !matchesEdgeFilter(e, filter) {
+					continue
+				}
+				seen[e.ID] = struct{}{}
+				tagEdgeSource(e, branch)
+				results = append(results, e)
+			}
+		}
+		return nil
+	})
+	return results, err
+}
+
 func (s *BranchStore) GetNeighbors(_ context.Context, nodeID string, edgeType graph.EdgeType, direction graph.Direction) ([]*graph.Node, error) {
 	var results []*graph.Node
 	err := s.db.View(func(txn *badger.Txn) error {
@@ -500,7 +786,7 @@ func (s *BranchStore) DeleteByFile(_ context.Context, filePath string) error {
 		return err
 	}
 	for _, id := range nodeIDs {
-		err := s.db.Update(func(txn *badger.Txn) error {
+		err := s.update(func(txn *badger.Txn) error {
 			return deleteNodeInTxn(txn, b, id)
 		})
 		if err != nil {
@@ -565,20 +851,53 @@ func (s *BranchStore) Close() error {
 	return s.db.Close()
 }
 
-// DeleteByBranch removes all keys belonging to the given branch from the DB.
+// branchPrefixes lists all key prefixes that contain branch data.
+var branchPrefixes = []string{
+	prefixNode,
+	prefixEdge,
+	prefixIdxType,
+	prefixIdxFile,
+	prefixIdxPkg,
+	prefixIdxEdge,
+	prefixIdxReverseEdge,
+	prefixIdxRole,
+	prefixIdxEdgeType,
+	prefixIdxLang,
+	prefixIdxName,
+	prefixIdxProp,
+}
+
+// Compact reclaims disk space left by deleted and overwritten keys: it
+// merges the LSM tree into one level and rewrites value log files that are
+// mostly garbage. No other process may use the DB meanwhile.
+func (s *BranchStore) Compact() error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	if err := s.db.Flatten(2); err != nil {
+		return fmt.Errorf("flatten: %w", err)
+	}
+	for {
+		err := s.db.RunValueLogGC(0.5)
+		if err == badger.ErrNoRewrite || err == badger.ErrGCInMemoryMode {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("value log gc: %w", err)
+		}
+	}
+}
+
+// DeleteByBranch removes all keys belonging to the given branch of the
+// store's namespace from the DB.
 func (s *BranchStore) DeleteByBranch(branch string) error {
-	// All key prefixes that contain branch data.
-	prefixes := []string{
-		prefixNode + branch + ":",
-		prefixEdge + branch + ":",
-		prefixIdxType + branch + ":",
-		prefixIdxFile + branch + ":",
-		prefixIdxPkg + branch + ":",
-		prefixIdxEdge + branch + ":",
-		prefixIdxReverseEdge + branch + ":",
-		prefixIdxRole + branch + ":",
-	}
-	for _, prefix := range prefixes {
+	return s.deleteKeyBranch(s.qualify(branch))
+}
+
+// deleteKeyBranch removes all keys with the given key branch segment.
+func (s *BranchStore) deleteKeyBranch(branch string) error {
+	for _, p := range branchPrefixes {
+		prefix := p + branch + ":"
 		if err := s.deleteKeysByPrefix([]byte(prefix)); err != nil {
 			return fmt.Errorf("delete branch %s prefix %s: %w", branch, prefix, err)
 		}
@@ -586,6 +905,45 @@ func (s *BranchStore) DeleteByBranch(branch string) error {
 	return nil
 }
 
+// DeleteNamespace removes every branch of a namespace from the DB. The
+// default namespace cannot be deleted this way; use DeleteByBranch.
+func (s *BranchStore) DeleteNamespace(_ context.Context, namespace string) error {
+	if namespace == "" {
+		return fmt.Errorf("cannot delete the default namespace")
+	}
+	if err := ValidateNamespace(namespace); err != nil {
+		return err
+	}
+	for _, p := range branchPrefixes {
+		prefix := p + namespace + namespaceSep
+		if err := s.deleteKeysByPrefix([]byte(prefix)); err != nil {
+			return fmt.Errorf("delete namespace %s prefix %s: %w", namespace, prefix, err)
+		}
+	}
+	return nil
+}
+
+// ListNamespaces returns the sorted names of the non-default namespaces
+// holding nodes.
+func (s *BranchStore) ListNamespaces(_ context.Context) ([]string, error) {
+	branches, err := s.listKeyBranches()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{})
+	var namespaces []string
+	for _, b := range branches {
+		ns, _, found := strings.Cut(b, namespaceSep)
+		if _, dup := seen[ns]; !found || dup {
+			continue
+		}
+		seen[ns] = struct{}{}
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
 // deleteKeysByPrefix removes all keys with the given prefix.
 func (s *BranchStore) deleteKeysByPrefix(prefix []byte) error {
 	// Collect keys first, then delete in batches.
@@ -614,7 +972,7 @@ func (s *BranchStore) deleteKeysByPrefix(prefix []byte) error {
 			end = len(keys)
 		}
 		batch := keys[i:end]
-		err := s.db.Update(func(txn *badger.Txn) error {
+		err := s.update(func(txn *badger.Txn) error {
 			for _, key := range batch {
 				if err := txn.Delete(key); err != nil {
 					return err
@@ -629,8 +987,24 @@ func (s *BranchStore) deleteKeysByPrefix(prefix []byte) error {
 	return nil
 }
 
-// ListBranches discovers unique branch names present in the DB by scanning node key prefixes.
+// ListBranches discovers the unique branch names of the store's namespace
+// present in the DB by scanning node key prefixes.
 func (s *BranchStore) ListBranches() ([]string, error) {
+	keyBranches, err := s.listKeyBranches()
+	if err != nil {
+		return nil, err
+	}
+	var branches []string
+	for _, b := range keyBranches {
+		if s.inNamespace(b) {
+			branches = append(branches, s.unqualify(b))
+		}
+	}
+	return branches, nil
+}
+
+// listKeyBranches returns the key branch segments of all namespaces.
+func (s *BranchStore) listKeyBranches() ([]string, error) {
 	branchSet := make(map[string]struct{})
 	err := s.db.View(func(txn *badger.Txn) error {
 		opts := badger.DefaultIteratorOptions
@@ -669,6 +1043,57 @@ func buildEdgeIndexPrefix(prefix, branch, nodeID string, edgeType graph.EdgeType
 	return []byte(fmt.Sprintf("%s%s:%s:%s:", prefix, branch, nodeID, edgeType))
 }
 
+// nodeIndexPrefix returns the prefix of the index keys listing the
+// candidate nodes of filter in branch, or nil when no index applies and
+// the branch must be scanned in full. Indexes are tried from the most to
+// the least selective; the candidates are then checked against the whole
+// filter.
+func nodeIndexPrefix(branch string, filter graph.NodeFilter) []byte {
+	if filter.FilePath != "" {
+		return []byte(fmt.Sprintf("%s%s:%s:", prefixIdxFile, branch, filter.FilePath))
+	}
+	if filter.NamePattern != "" && !hasGlobMeta(filter.NamePattern) {
+		return []byte(fmt.Sprintf("%s%s:%s:", prefixIdxName, branch, escapeIndexValue(filter.NamePattern)))
+	}
+	for _, k := range indexedProperties {
+		if v, ok := filter.Properties[k]; ok && v != "" {
+			return []byte(fmt.Sprintf("%s%s:%s=%s:", prefixIdxProp, branch, k, escapeIndexValue(v)))
+		}
+	}
+	if filter.Package != "" {
+		return []byte(fmt.Sprintf("%s%s:%s:", prefixIdxPkg, branch, filter.Package))
+	}
+	if filter.Type != "" {
+		return []byte(fmt.Sprintf("%s%s:%s:", prefixIdxType, branch, filter.Type))
+	}
+	if lit := globLiteralPrefix(filter.NamePattern); lit != "" {
+		// Keys of names starting with the literal; escaping keeps the
+		// order of the prefix's names intact.
+		return []byte(fmt.Sprintf("%s%s:%s", prefixIdxName, branch, escapeIndexValue(lit)))
+	}
+	if filter.FilePathPrefix != "" {
+		return []byte(fmt.Sprintf("%s%s:%s", prefixIdxFile, branch, filter.FilePathPrefix))
+	}
+	if filter.Language != "" {
+		return []byte(fmt.Sprintf("%s%s:%s:", prefixIdxLang, branch, filter.Language))
+	}
+	return nil
+}
+
+// hasGlobMeta reports whether a filepath.Match pattern has metacharacters.
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// globLiteralPrefix returns the literal text a filepath.Match pattern
+// starts with.
+func globLiteralPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+	return pattern
+}
+
 // scanIndexPrefix scans all keys with the given prefix and extracts the trailing
 // ID segment (the last colon-separated part).
 func scanIndexPrefix(txn *badger.Txn, prefix []byte) ([]string, error) {
@@ -713,6 +1138,31 @@ func scanBranchNodes(txn *badger.Txn, branch string, fn func(*graph.Node) bool)
 	return nil
 }
 
+// scanBranchEdges iterates all edges in a branch, calling fn for each.
+// Iteration stops if fn returns false.
+func scanBranchEdges(txn *badger.Txn, branch string, fn func(*graph.Edge) bool) error {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = true
+	branchPrefix := []byte(prefixEdge + branch + ":")
+	opts.Prefix = branchPrefix
+	it := txn.NewIterator(opts)
+	defer it.Close()
+	for it.Seek(branchPrefix); it.Valid(); it.Next() {
+		item := it.Item()
+		var edge graph.Edge
+		err := item.Value(func(val []byte) error {
+			return json.Unmarshal(val, &edge)
+		})
+		if err != nil {
+			continue
+		}
+		if !fn(&edge) {
+			break
+		}
+	}
+	return nil
+}
+
 func getEdgeInTxn(txn *badger.Txn, branch, id string) (*graph.Edge, error) {
 	item, err := txn.Get(edgeKey(branch, id))
 	if err != nil {
@@ -736,6 +1186,9 @@ func matchesFilter(node *graph.Node, filter graph.NodeFilter) bool {
 	if filter.FilePath != "" && node.FilePath != filter.FilePath {
 		return false
 	}
+	if filter.FilePathPrefix != "" && !strings.HasPrefix(node.FilePath, filter.FilePathPrefix) {
+		return false
+	}
 	if filter.Package != "" && node.Package != filter.Package {
 		return false
 	}
@@ -751,6 +1204,9 @@ func matchesFilter(node *graph.Node, filter graph.NodeFilter) bool {
 	if filter.Exported != nil && node.Exported != *filter.Exported {
 		return false
 	}
+	if filter.Platform != "" && !graph.BuildsOn(node, filter.Platform) {
+		return false
+	}
 	// Property-based filtering: all specified key-value pairs must match.
 	for key, val := range filter.Properties {
 		if node.Properties == nil {
@@ -768,13 +1224,33 @@ func matchesFilter(node *graph.Node, filter graph.NodeFilter) bool {
 	return true
 }
 
+// matchesEdgeFilter checks whether an edge matches all non-zero fields in
+// the filter.
+func matchesEdgeFilter(e *graph.Edge, filter graph.EdgeFilter) bool {
+	if filter.Type != "" && e.Type != filter.Type {
+		return false
+	}
+	if filter.SourceID != "" && e.SourceID != filter.SourceID {
+		return false
+	}
+	if filter.TargetID != "" && e.TargetID != filter.TargetID {
+		return false
+	}
+	for key, val := range filter.Properties {
+		if e.Properties[key] != val {
+			return false
+		}
+	}
+	return true
+}
+
 // tagNodeSource sets the PropGraphSource property on a node to indicate
 // which branch it came from. Set on reads only, never persisted.
 func tagNodeSource(n *graph.Node, source string) {
 	if n.Properties == nil {
 		n.Properties = make(map[string]string)
 	}
-	n.Properties[graph.PropGraphSource] = source
+	n.Properties[graph.PropGraphSource] = branchName(source)
 }
 
 // tagEdgeSource sets the PropGraphSource property on an edge to indicate
@@ -783,5 +1259,13 @@ func tagEdgeSource(e *graph.Edge, source string) {
 	if e.Properties == nil {
 		e.Properties = make(map[string]string)
 	}
-	e.Properties[graph.PropGraphSource] = source
+	e.Properties[graph.PropGraphSource] = branchName(source)
+}
+
+// branchName strips the namespace from a key branch segment.
+func branchName(keyBranch string) string {
+	if _, branch, found := strings.Cut(keyBranch, namespaceSep); found {
+		return branch
+	}
+	return keyBranch
 }
@@ -1,7 +1,11 @@
 package embedded
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
@@ -310,6 +314,90 @@ func TestQueryNodesExportedFilter(t *testing.T) {
 	}
 }
 
+func TestQueryNodesPagination(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"n1", "n2", "n3", "n4", "n5"} {
+		if err := s.AddNode(ctx, &graph.Node{ID: id, Type: graph.NodeFunction, Name: id, FilePath: "a.go"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddNode(ctx, &graph.Node{ID: "s1", Type: graph.NodeStruct, Name: "s1", FilePath: "a.go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		filter graph.NodeFilter
+		want   []string
+	}{
+		{graph.NodeFilter{Type: graph.NodeFunction, Limit: 2}, []string{"n1", "n2"}},
+		{graph.NodeFilter{Type: graph.NodeFunction, Offset: 2, Limit: 2}, []string{"n3", "n4"}},
+		{graph.NodeFilter{Type: graph.NodeFunction, Offset: 4, Limit: 2}, []string{"n5"}},
+		{graph.NodeFilter{Type: graph.NodeFunction, Offset: 5}, nil},
+		{graph.NodeFilter{NamePattern: "n*", Offset: 3}, []string{"n4", "n5"}},
+	}
+	for _, tt := range tests {
+		results, err := s.QueryNodes(ctx, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, n := range results {
+			got = append(got, n.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("QueryNodes(offset=%d, limit=%d) = %v, want %v", tt.filter.Offset, tt.filter.Limit, got, tt.want)
+		}
+	}
+}
+
+func TestScanNodes(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"n1", "n2", "n3"} {
+		if err := s.AddNode(ctx, &graph.Node{ID: id, Type: graph.NodeFunction, Name: id, FilePath: "a.go"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The callback may write to the store while the scan runs.
+	var seen []string
+	err := s.ScanNodes(ctx, graph.NodeFilter{Type: graph.NodeFunction}, func(n *graph.Node) error {
+		seen = append(seen, n.ID)
+		return s.AddEdge(ctx, &graph.Edge{ID: "e-" + n.ID, Type: graph.EdgeCalls, SourceID: n.ID, TargetID: "n1"})
+	})
+	if err != nil {
+		t.Fatalf("ScanNodes: %v", err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("scanned %v, want 3 nodes", seen)
+	}
+	edges, err := s.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeCalls})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 3 {
+		t.Errorf("got %d edges added during the scan, want 3", len(edges))
+	}
+
+	seen = nil
+	err = s.ScanNodes(ctx, graph.NodeFilter{}, func(n *graph.Node) error {
+		seen = append(seen, n.ID)
+		return graph.ErrStopScan
+	})
+	if err != nil || len(seen) != 1 {
+		t.Errorf("stopped scan: err=%v, scanned %v, want one node", err, seen)
+	}
+
+	boom := errors.New("boom")
+	err = s.ScanNodes(ctx, graph.NodeFilter{Type: graph.NodeFunction}, func(*graph.Node) error { return boom })
+	if !errors.Is(err, boom) {
+		t.Errorf("ScanNodes error = %v, want %v", err, boom)
+	}
+}
+
 func TestAddGetEdge(t *testing.T) {
 	s := newTestStore(t)
 	ctx := context.Background()
@@ -482,6 +570,44 @@ func TestGetNeighborsBoth(t *testing.T) {
 	}
 }
 
+func TestAddBatch(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	nodes := []*graph.Node{
+		{ID: "n1", Type: graph.NodeFunction, Name: "foo", FilePath: "big.go", Package: "main"},
+		{ID: "n2", Type: graph.NodeStruct, Name: "Bar", FilePath: "big.go", Package: "main",
+			Properties: map[string]string{graph.PropArchRole: "service"}},
+	}
+	edges := []*graph.Edge{{ID: "e1", Type: graph.EdgeContains, SourceID: "n1", TargetID: "n2"}}
+	if err := s.AddBatch(ctx, nodes, edges); err != nil {
+		t.Fatalf("AddBatch: %v", err)
+	}
+
+	// Batched writes are indexed like single writes.
+	byFile, err := s.QueryNodes(ctx, graph.NodeFilter{FilePath: "big.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byFile) != 2 {
+		t.Errorf("QueryNodes by file = %d nodes, want 2", len(byFile))
+	}
+	in, err := s.GetNeighbors(ctx, "n2", graph.EdgeContains, graph.Incoming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(in) != 1 || in[0].ID != "n1" {
+		t.Errorf("incoming neighbors of n2 = %v, want n1", in)
+	}
+
+	if err := s.DeleteByFile(ctx, "big.go"); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := s.GetEdges(ctx, "n1", ""); len(out) != 0 {
+		t.Errorf("edges after DeleteByFile = %d, want 0", len(out))
+	}
+}
+
 func TestDeleteByFile(t *testing.T) {
 	s := newTestStore(t)
 	ctx := context.Background()
@@ -608,6 +734,219 @@ func TestDeleteEdge(t *testing.T) {
 	}
 }
 
+func TestQueryEdges(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	for _, id := range []string{"n1", "n2", "n3"} {
+		if err := s.AddNode(ctx, &graph.Node{ID: id, Type: graph.NodeFunction, Name: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range []*graph.Edge{
+		{ID: "e1", Type: graph.EdgeCalls, SourceID: "n1", TargetID: "n2"},
+		{ID: "e2", Type: graph.EdgeCalls, SourceID: "n3", TargetID: "n2", Properties: map[string]string{"kind": "async"}},
+		{ID: "e3", Type: graph.EdgeImports, SourceID: "n2", TargetID: "n3"},
+		{ID: "e4", Type: graph.EdgeCalls, SourceID: "n2", TargetID: "n3"},
+	} {
+		if err := s.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.DeleteEdge(ctx, "e4"); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(edges []*graph.Edge) map[string]bool {
+		m := make(map[string]bool)
+		for _, e := range edges {
+			m[e.ID] = true
+		}
+		return m
+	}
+
+	incoming, err := s.GetIncomingEdges(ctx, "n2", graph.EdgeCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(incoming); len(got) != 2 || !got["e1"] || !got["e2"] {
+		t.Errorf("GetIncomingEdges(n2) = %v, want e1 and e2", got)
+	}
+
+	tests := []struct {
+		name   string
+		filter graph.EdgeFilter
+		want   []string
+	}{
+		{"type", graph.EdgeFilter{Type: graph.EdgeCalls}, []string{"e1", "e2"}},
+		{"source", graph.EdgeFilter{SourceID: "n2"}, []string{"e3"}},
+		{"target and type", graph.EdgeFilter{Type: graph.EdgeImports, TargetID: "n3"}, []string{"e3"}},
+		{"source and target", graph.EdgeFilter{SourceID: "n3", TargetID: "n2"}, []string{"e2"}},
+		{"properties", graph.EdgeFilter{Type: graph.EdgeCalls, Properties: map[string]string{"kind": "async"}}, []string{"e2"}},
+		{"all", graph.EdgeFilter{}, []string{"e1", "e2", "e3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edges, err := s.QueryEdges(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ids(edges)
+			if len(got) != len(tt.want) {
+				t.Fatalf("QueryEdges = %v, want %v", got, tt.want)
+			}
+			for _, id := range tt.want {
+				if !got[id] {
+					t.Errorf("QueryEdges = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEdgeTypeIndexBackfill(t *testing.T) {
+	dbPath := t.TempDir()
+	ctx := context.Background()
+
+	s, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddEdge(ctx, &graph.Edge{ID: "e1", Type: graph.EdgeCalls, SourceID: "n1", TargetID: "n2"}); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a DB written before the type index existed.
+	if err := s.deleteKeysByPrefix([]byte(prefixIdxEdgeType)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.deleteKeysByPrefix([]byte(keySchemaVersion)); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	edges, err := s.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeCalls})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 {
+		t.Errorf("QueryEdges after reopening = %d edges, want 1", len(edges))
+	}
+}
+
+func TestQueryNodesFieldIndexes(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	nodes := []*graph.Node{
+		{ID: "n1", Type: graph.NodeDependency, Name: "fetch users", FilePath: "web/src/api.ts", Language: "typescript",
+			Properties: map[string]string{"kind": "api_call"}},
+		{ID: "n2", Type: graph.NodeDependency, Name: "react", FilePath: "web/src/app.ts", Language: "typescript",
+			Properties: map[string]string{"kind": "import"}},
+		{ID: "n3", Type: graph.NodeDependency, Name: "requests", FilePath: "api/main.py", Language: "python",
+			Properties: map[string]string{"kind": "import"}},
+		{ID: "n4", Type: graph.NodeFunction, Name: "fetchUser", FilePath: "web/src/user.ts", Language: "typescript"},
+		{ID: "n5", Type: graph.NodeFunction, Name: "a:b", FilePath: "web/src/user.ts", Language: "typescript"},
+		{ID: "n6", Type: graph.NodeFunction, Name: "a", FilePath: "web/src/user.ts", Language: "typescript"},
+	}
+	for _, n := range nodes {
+		if err := s.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter graph.NodeFilter
+		want   []string
+	}{
+		{"language", graph.NodeFilter{Language: "python"}, []string{"n3"}},
+		{"property", graph.NodeFilter{Properties: map[string]string{"kind": "import"}}, []string{"n2", "n3"}},
+		{"property and language", graph.NodeFilter{Language: "typescript", Properties: map[string]string{"kind": "import"}}, []string{"n2"}},
+		{"exact name", graph.NodeFilter{NamePattern: "a"}, []string{"n6"}},
+		{"name with colon", graph.NodeFilter{NamePattern: "a:b"}, []string{"n5"}},
+		{"name prefix", graph.NodeFilter{NamePattern: "fetch*"}, []string{"n1", "n4"}},
+		{"file prefix", graph.NodeFilter{FilePathPrefix: "web/"}, []string{"n1", "n2", "n4", "n5", "n6"}},
+		{"file prefix and type", graph.NodeFilter{Type: graph.NodeDependency, FilePathPrefix: "web/src/a"}, []string{"n1", "n2"}},
+	}
+	for _, tt := range tests {
+		results, err := s.QueryNodes(ctx, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, n := range results {
+			got = append(got, n.ID)
+		}
+		sort.Strings(got)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Updating a node moves its index entries.
+	updated := *nodes[1]
+	updated.Properties = map[string]string{"kind": "manifest_dep"}
+	if err := s.UpdateNode(ctx, &updated); err != nil {
+		t.Fatal(err)
+	}
+	results, err := s.QueryNodes(ctx, graph.NodeFilter{Properties: map[string]string{"kind": "import"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ID != "n3" {
+		t.Errorf("kind=import after update = %v, want only n3", results)
+	}
+}
+
+func TestNodeFieldIndexBackfill(t *testing.T) {
+	dbPath := t.TempDir()
+	ctx := context.Background()
+
+	s, err := NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &graph.Node{ID: "n1", Type: graph.NodeDependency, Name: "react", Language: "typescript",
+		Properties: map[string]string{"kind": "import"}}
+	if err := s.AddNode(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a DB written before the field indexes existed.
+	for _, p := range []string{prefixIdxLang, prefixIdxName, prefixIdxProp} {
+		if err := s.deleteKeysByPrefix([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.writeSchemaVersion(1); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for _, filter := range []graph.NodeFilter{
+		{Language: "typescript"},
+		{NamePattern: "react"},
+		{Properties: map[string]string{"kind": "import"}},
+	} {
+		nodes, err := s.QueryNodes(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nodes) != 1 {
+			t.Errorf("QueryNodes(%+v) after reopening = %d nodes, want 1", filter, len(nodes))
+		}
+	}
+}
+
 func TestBranchStoreWriteReadIsolation(t *testing.T) {
 	dbPath := t.TempDir()
 
@@ -879,6 +1218,105 @@ func TestBranchStoreListBranches(t *testing.T) {
 	}
 }
 
+func TestNamespaceIsolation(t *testing.T) {
+	ctx := context.Background()
+	base := newTestStore(t)
+	teamA, err := base.WithNamespace("team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	teamB, err := base.WithNamespace("team-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ns, s := range map[string]*BranchStore{"": base, "team-a": teamA, "team-b": teamB} {
+		if err := s.AddNode(ctx, &graph.Node{ID: "shared", Type: graph.NodeFunction, Name: "fn-" + ns, FilePath: "a.go"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddNode(ctx, &graph.Node{ID: "only-" + ns, Type: graph.NodeFunction, Name: "only", FilePath: "a.go"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.AddEdge(ctx, &graph.Edge{ID: "e", Type: graph.EdgeCalls, SourceID: "shared", TargetID: "only-" + ns}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := teamA.GetNode(ctx, "shared"); err != nil || n.Name != "fn-team-a" || n.Properties[graph.PropGraphSource] != "default" {
+		t.Errorf("team-a GetNode = %v, %v", n, err)
+	}
+	if _, err := teamA.GetNode(ctx, "only-team-b"); err == nil {
+		t.Error("team-a sees team-b's node")
+	}
+	if nodes, err := teamB.GetNeighbors(ctx, "shared", graph.EdgeCalls, graph.Outgoing); err != nil || len(nodes) != 1 || nodes[0].ID != "only-team-b" {
+		t.Errorf("team-b neighbors = %v, %v", nodes, err)
+	}
+	if stats, err := base.Stats(ctx); err != nil || stats.NodeCount != 2 || stats.EdgeCount != 1 {
+		t.Errorf("default namespace stats = %+v, %v", stats, err)
+	}
+	if teamA.WriteBranch() != "default" || teamA.Namespace() != "team-a" {
+		t.Errorf("team-a branch %q namespace %q", teamA.WriteBranch(), teamA.Namespace())
+	}
+
+	if branches, err := teamA.ListBranches(); err != nil || len(branches) != 1 || branches[0] != "default" {
+		t.Errorf("team-a ListBranches = %v, %v", branches, err)
+	}
+	if branches, err := base.ListBranches(); err != nil || len(branches) != 1 || branches[0] != "default" {
+		t.Errorf("default ListBranches = %v, %v", branches, err)
+	}
+	if namespaces, err := base.ListNamespaces(ctx); err != nil || len(namespaces) != 2 || namespaces[0] != "team-a" || namespaces[1] != "team-b" {
+		t.Errorf("ListNamespaces = %v, %v", namespaces, err)
+	}
+
+	if err := base.DeleteNamespace(ctx, "team-a"); err != nil {
+		t.Fatal(err)
+	}
+	if stats, err := teamA.Stats(ctx); err != nil || stats.NodeCount != 0 || stats.EdgeCount != 0 {
+		t.Errorf("deleted namespace stats = %+v, %v", stats, err)
+	}
+	if stats, err := teamB.Stats(ctx); err != nil || stats.NodeCount != 2 {
+		t.Errorf("team-b stats after deleting team-a = %+v, %v", stats, err)
+	}
+	if err := base.DeleteNamespace(ctx, ""); err == nil {
+		t.Error("DeleteNamespace removed the default namespace")
+	}
+}
+
+func TestNamespaceExportImport(t *testing.T) {
+	ctx := context.Background()
+	dbPath := t.TempDir()
+	src, err := NewNamespacedStore(dbPath, "org/payments", "main", []string{"main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if err := src.AddNode(ctx, &graph.Node{ID: "n1", Type: graph.NodeFunction, Name: "Pay", FilePath: "pay.go"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if branch, err := ReadExportBranch(bytes.NewReader(buf.Bytes())); err != nil || branch != "main" {
+		t.Errorf("export branch = %q, %v; want the branch without namespace", branch, err)
+	}
+
+	dst, err := src.WithNamespace("org/billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Import(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := dst.GetNode(ctx, "n1"); err != nil || n.Name != "Pay" {
+		t.Errorf("imported node = %v, %v", n, err)
+	}
+	if _, err := src.WithNamespace("team~a"); err == nil {
+		t.Error("WithNamespace accepted a name with the separator")
+	}
+}
+
 func TestNewNodeIDDeterministic(t *testing.T) {
 	id1 := graph.NewNodeID("Function", "main.go", "main")
 	id2 := graph.NewNodeID("Function", "main.go", "main")
@@ -1,6 +1,9 @@
 package graph
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // Direction specifies the traversal direction for edge queries.
 type Direction int
@@ -13,15 +16,41 @@ const (
 
 // NodeFilter specifies criteria for querying nodes.
 type NodeFilter struct {
-	Type        NodeType
-	FilePath    string
-	Package     string
-	Language    string
-	NamePattern string // glob pattern matched against Name
-	Exported    *bool
+	Type     NodeType
+	FilePath string
+	// FilePathPrefix keeps the nodes whose file path starts with it, such
+	// as the nodes of a directory ("services/orders/").
+	FilePathPrefix string
+	Package        string
+	Language       string
+	NamePattern    string // glob pattern matched against Name
+	Exported       *bool
 	// Properties filters nodes by property key-value pairs.
 	// All specified entries must match (AND logic).
 	Properties map[string]string
+	// Platform, as GOOS or GOOS/GOARCH, keeps the nodes that are compiled
+	// for it (see BuildsOn): nodes of files built only for other platforms
+	// are left out.
+	Platform string
+	// Offset skips that many matching nodes and Limit, when positive, caps
+	// the number returned, for paging through large result sets. Matching
+	// nodes come in a stable order between writes.
+	Offset int
+	Limit  int
+}
+
+// ErrStopScan is returned by a ScanNodes callback to end the scan early.
+// ScanNodes then returns nil.
+var ErrStopScan = errors.New("stop scan")
+
+// EdgeFilter specifies criteria for querying edges. All non-zero fields
+// must match.
+type EdgeFilter struct {
+	Type     EdgeType
+	SourceID string
+	TargetID string
+	// Properties filters edges by exact property key-value pairs.
+	Properties map[string]string
 }
 
 // Store is the interface for knowledge graph persistence.
@@ -41,6 +70,11 @@ type Store interface {
 	// QueryNodes returns all nodes matching the given filter.
 	QueryNodes(ctx context.Context, filter NodeFilter) ([]*Node, error)
 
+	// ScanNodes calls fn for each node matching the given filter, in
+	// QueryNodes order, without collecting them in memory first. An error
+	// from fn ends the scan and is returned, except ErrStopScan.
+	ScanNodes(ctx context.Context, filter NodeFilter, fn func(*Node) error) error
+
 	// AddEdge inserts a new edge into the graph.
 	AddEdge(ctx context.Context, edge *Edge) error
 
@@ -51,6 +85,14 @@ type Store interface {
 	// If edgeType is empty, all edge types are returned.
 	GetEdges(ctx context.Context, nodeID string, edgeType EdgeType) ([]*Edge, error)
 
+	// GetIncomingEdges returns edges whose target is nodeID with the given type.
+	// If edgeType is empty, all edge types are returned.
+	GetIncomingEdges(ctx context.Context, nodeID string, edgeType EdgeType) ([]*Edge, error)
+
+	// QueryEdges returns all edges matching the given filter. An empty
+	// filter returns every edge.
+	QueryEdges(ctx context.Context, filter EdgeFilter) ([]*Edge, error)
+
 	// GetNeighbors returns nodes connected to nodeID via edges of the given type
 	// in the specified direction. If edgeType is empty, all edge types are traversed.
 	GetNeighbors(ctx context.Context, nodeID string, edgeType EdgeType, direction Direction) ([]*Node, error)
@@ -65,3 +107,24 @@ type Store interface {
 	// Close releases resources held by the store.
 	Close() error
 }
+
+// BatchStore is implemented by stores that can write many nodes and edges
+// at once, more cheaply than one AddNode or AddEdge call each. The indexer
+// flushes bounded batches through it when available.
+type BatchStore interface {
+	// AddBatch inserts the nodes and then the edges.
+	AddBatch(ctx context.Context, nodes []*Node, edges []*Edge) error
+}
+
+// NamespacedStore is implemented by stores that keep the graphs of several
+// tenants (teams or repositories) isolated in one database. All Store
+// methods act on the store's own namespace only.
+type NamespacedStore interface {
+	Store
+	// Namespace returns the store's namespace; empty for the default one.
+	Namespace() string
+	// ListNamespaces returns the non-default namespaces holding data.
+	ListNamespaces(ctx context.Context) ([]string, error)
+	// DeleteNamespace removes all data of a non-default namespace.
+	DeleteNamespace(ctx context.Context, namespace string) error
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// IDScheme is the version of the node and edge ID scheme. Version 1 hashed
+// type, file path, and name for nodes and reused NewNodeID for edges, so
+// same-named symbols in different scopes collided. Version 2 adds scoped
+// SymbolKeys and a separate edge namespace (NewEdgeID); unscoped symbols
+// keep their version 1 IDs, so only nested symbols and edges move.
+const IDScheme = 2
+
+// SymbolKey identifies a symbol structurally. Its canonical form is
+//
+//	language:type:file:scope.chain:name:disambiguator
+//
+// where Scope lists the enclosing declarations outermost first (e.g. the
+// outer classes of a nested class) and Disambiguator separates symbols that
+// share everything else (e.g. an overload's parameter types).
+type SymbolKey struct {
+	Language      string
+	Type          NodeType
+	FilePath      string
+	Scope         []string
+	Name          string
+	Disambiguator string
+}
+
+// String returns the canonical form, with ':' (and '.' within scope
+// elements) escaped so distinct keys never share a form.
+func (k SymbolKey) String() string {
+	scope := make([]string, len(k.Scope))
+	for i, s := range k.Scope {
+		scope[i] = strings.ReplaceAll(escapeIDPart(s), ".", `\.`)
+	}
+	return strings.Join([]string{
+		escapeIDPart(k.Language),
+		escapeIDPart(string(k.Type)),
+		escapeIDPart(k.FilePath),
+		strings.Join(scope, "."),
+		escapeIDPart(k.Name),
+		escapeIDPart(k.Disambiguator),
+	}, ":")
+}
+
+// ID returns the node ID for the key. A key without scope or disambiguator
+// gets the same ID as NewNodeID(Type, FilePath, Name), keeping top-level
+// symbols stable across the scheme change.
+func (k SymbolKey) ID() string {
+	if len(k.Scope) == 0 && k.Disambiguator == "" {
+		return NewNodeID(string(k.Type), k.FilePath, k.Name)
+	}
+	return hashID("v2:" + k.String())
+}
+
+// NewDependencyID returns the ID of the Dependency node shared by every file
+// that imports package pkg in ecosystem (the importing language, such as
+// "go" or "python"). Unlike NewNodeID it involves no file path, so a package
+// imported by many files is one node.
+func NewDependencyID(ecosystem, pkg string) string {
+	return hashID("dependency:" + escapeIDPart(ecosystem) + ":" + pkg)
+}
+
+// NewExternalTypeID returns the ID of the placeholder node standing for
+// type name exported by package pkg of ecosystem, declared outside the
+// indexed repositories. Like NewDependencyID it involves no file path, so
+// every file referring to the type shares the node.
+func NewExternalTypeID(ecosystem, pkg, name string) string {
+	return hashID("external:" + escapeIDPart(ecosystem) + ":" + escapeIDPart(pkg) + ":" + name)
+}
+
+// NewEdgeID generates a deterministic edge ID from the edge type and its
+// endpoints. Edge IDs are hashed in their own namespace so they can never
+// equal a node ID.
+func NewEdgeID(edgeType EdgeType, sourceID, targetID string) string {
+	return hashID(fmt.Sprintf("edge:%s:%s:%s", edgeType, sourceID, targetID))
+}
+
+// legacyEdgeID is the version 1 edge ID, NewNodeID applied to the edge.
+func legacyEdgeID(e *Edge) string {
+	return NewNodeID(string(e.Type), e.SourceID, e.TargetID)
+}
+
+// MigrateEdgeID returns the current ID for an edge whose ID follows the
+// version 1 scheme, and the edge's own ID otherwise (IDs built some other
+// way, such as by external parsers, are left alone).
+func MigrateEdgeID(e *Edge) string {
+	if e.ID == legacyEdgeID(e) {
+		return NewEdgeID(e.Type, e.SourceID, e.TargetID)
+	}
+	return e.ID
+}
+
+func escapeIDPart(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, ":", `\:`)
+}
+
+func hashID(raw string) string {
+	h := sha256.Sum256([]byte(raw))
+	return fmt.Sprintf("%x", h[:12])
+}
@@ -0,0 +1,53 @@
+package graph
+
+import "testing"
+
+func TestSymbolKeyID(t *testing.T) {
+	top := SymbolKey{Language: "java", Type: NodeClass, FilePath: "A.java", Name: "Builder"}
+	if got, want := top.ID(), NewNodeID(string(NodeClass), "A.java", "Builder"); got != want {
+		t.Errorf("unscoped key ID = %s, want the NewNodeID %s", got, want)
+	}
+
+	keys := []SymbolKey{
+		top,
+		{Language: "java", Type: NodeClass, FilePath: "A.java", Scope: []string{"Order"}, Name: "Builder"},
+		{Language: "java", Type: NodeClass, FilePath: "A.java", Scope: []string{"Invoice"}, Name: "Builder"},
+		{Language: "java", Type: NodeClass, FilePath: "A.java", Scope: []string{"Order", "Line"}, Name: "Builder"},
+		{Language: "java", Type: NodeClass, FilePath: "A.java", Scope: []string{"Order.Line"}, Name: "Builder"},
+		{Language: "java", Type: NodeMethod, FilePath: "A.java", Scope: []string{"Order"}, Name: "m", Disambiguator: "int"},
+		{Language: "java", Type: NodeMethod, FilePath: "A.java", Scope: []string{"Order"}, Name: "m", Disambiguator: "long"},
+	}
+	seen := make(map[string]int)
+	for i, k := range keys {
+		id := k.ID()
+		if j, dup := seen[id]; dup {
+			t.Errorf("keys %d (%s) and %d (%s) share ID %s", j, keys[j], i, k, id)
+		}
+		seen[id] = i
+		if k.ID() != id {
+			t.Errorf("key %s: ID not deterministic", k)
+		}
+	}
+}
+
+func TestSymbolKeyString(t *testing.T) {
+	k := SymbolKey{Language: "java", Type: NodeClass, FilePath: "src/A.java", Scope: []string{"Outer", "a.b"}, Name: "X:Y"}
+	if got, want := k.String(), `java:Class:src/A.java:Outer.a\.b:X\:Y:`; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
+
+func TestMigrateEdgeID(t *testing.T) {
+	e := &Edge{Type: EdgeCalls, SourceID: "a", TargetID: "b"}
+	e.ID = NewNodeID(string(e.Type), e.SourceID, e.TargetID)
+	if got, want := MigrateEdgeID(e), NewEdgeID(EdgeCalls, "a", "b"); got != want {
+		t.Errorf("legacy edge migrated to %s, want %s", got, want)
+	}
+	e.ID = "custom"
+	if got := MigrateEdgeID(e); got != "custom" {
+		t.Errorf("custom edge ID migrated to %s", got)
+	}
+	if NewEdgeID(EdgeCalls, "a", "b") == NewNodeID(string(EdgeCalls), "a", "b") {
+		t.Error("edge IDs share the node ID namespace")
+	}
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// NodesAt returns the nodes in filePath whose Line..EndLine span contains
+// line, innermost (smallest span) first. A node without an EndLine covers
+// its start line only; nodes without a line (such as File nodes) never
+// match. If types is non-empty, only nodes of those types are returned.
+func NodesAt(ctx context.Context, store Store, filePath string, line int, types ...NodeType) ([]*Node, error) {
+	nodes, err := store.QueryNodes(ctx, NodeFilter{FilePath: filePath})
+	if err != nil {
+		return nil, fmt.Errorf("query nodes in %s: %w", filePath, err)
+	}
+	var matches []*Node
+	for _, n := range nodes {
+		if n.Line <= 0 || line < n.Line || line > max(n.EndLine, n.Line) {
+			continue
+		}
+		if len(types) > 0 && !hasType(types, n.Type) {
+			continue
+		}
+		matches = append(matches, n)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if sa, sb := max(a.EndLine, a.Line)-a.Line, max(b.EndLine, b.Line)-b.Line; sa != sb {
+			return sa < sb
+		}
+		if a.Line != b.Line {
+			return a.Line > b.Line
+		}
+		return a.ID < b.ID
+	})
+	return matches, nil
+}
+
+// NodeAt returns the innermost node in filePath containing line, as
+// NodesAt, or nil if there is none.
+func NodeAt(ctx context.Context, store Store, filePath string, line int, types ...NodeType) (*Node, error) {
+	nodes, err := NodesAt(ctx, store, filePath, line, types...)
+	if err != nil || len(nodes) == 0 {
+		return nil, err
+	}
+	return nodes[0], nil
+}
+
+func hasType(types []NodeType, t NodeType) bool {
+	for _, want := range types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
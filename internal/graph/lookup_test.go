@@ -0,0 +1,69 @@
+package graph_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestNodesAt(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewStore(filepath.Join(t.TempDir(), "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	for _, n := range []*graph.Node{
+		{ID: "file", Type: graph.NodeFile, Name: "a.java", FilePath: "a.java"},
+		{ID: "class", Type: graph.NodeClass, Name: "Order", FilePath: "a.java", Line: 1, EndLine: 40},
+		{ID: "inner", Type: graph.NodeClass, Name: "Builder", FilePath: "a.java", Line: 20, EndLine: 35},
+		{ID: "build", Type: graph.NodeMethod, Name: "build", FilePath: "a.java", Line: 25, EndLine: 30},
+		{ID: "field", Type: graph.NodeVariable, Name: "total", FilePath: "a.java", Line: 3},
+		{ID: "other", Type: graph.NodeFunction, Name: "f", FilePath: "b.java", Line: 1, EndLine: 99},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		line  int
+		types []graph.NodeType
+		want  []string
+	}{
+		{27, nil, []string{"build", "inner", "class"}},
+		{22, nil, []string{"inner", "class"}},
+		{3, nil, []string{"field", "class"}},
+		{27, []graph.NodeType{graph.NodeClass}, []string{"inner", "class"}},
+		{50, nil, nil},
+	}
+	for _, tt := range tests {
+		nodes, err := graph.NodesAt(ctx, store, "a.java", tt.line, tt.types...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, n := range nodes {
+			got = append(got, n.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("line %d types %v: got %v, want %v", tt.line, tt.types, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("line %d types %v: got %v, want %v", tt.line, tt.types, got, tt.want)
+				break
+			}
+		}
+	}
+
+	n, err := graph.NodeAt(ctx, store, "a.java", 50)
+	if err != nil || n != nil {
+		t.Errorf("NodeAt outside any span = %v, %v; want nil, nil", n, err)
+	}
+}
@@ -0,0 +1,136 @@
+package graph
+
+import (
+	"go/build/constraint"
+	"slices"
+	"strings"
+)
+
+// knownOS and knownArch are the GOOS and GOARCH values a build constraint
+// or file name suffix can name.
+var (
+	knownOS = []string{
+		"aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+		"linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+	}
+	knownArch = []string{
+		"386", "amd64", "amd64p32", "arm", "armbe", "arm64", "arm64be", "loong64", "mips",
+		"mipsle", "mips64", "mips64le", "mips64p32", "mips64p32le", "ppc", "ppc64", "ppc64le",
+		"riscv", "riscv64", "s390", "s390x", "sparc", "sparc64", "wasm",
+	}
+	unixOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "linux": true, "netbsd": true,
+		"openbsd": true, "solaris": true,
+	}
+)
+
+// maxFreeTags caps the tags other than GOOS and GOARCH whose combinations
+// are tried; constraints naming more are assumed satisfiable.
+const maxFreeTags = 8
+
+// IsGOOS reports whether s is a GOOS value, as in a foo_GOOS.go file name.
+func IsGOOS(s string) bool { return slices.Contains(knownOS, s) }
+
+// IsGOARCH reports whether s is a GOARCH value.
+func IsGOARCH(s string) bool { return slices.Contains(knownArch, s) }
+
+// BuildsOn reports whether a node is compiled for platform, given as GOOS
+// or GOOS/GOARCH, according to its PropBuildConstraint. Other tags, such
+// as cgo, Go versions and custom tags, may be set either way. Nodes
+// without a constraint, or with one that does not parse, build everywhere.
+func BuildsOn(n *Node, platform string) bool {
+	expr := buildConstraint(n)
+	if expr == nil || platform == "" {
+		return true
+	}
+	goos, goarch, _ := strings.Cut(platform, "/")
+	return satisfiable([]constraint.Expr{expr}, goos, goarch)
+}
+
+// BuildTogether reports whether two nodes can be compiled into the same
+// program: some platform and set of tags satisfies both their build
+// constraints. Platform variants of a symbol, such as a function declared
+// in both foo_linux.go and foo_windows.go, never build together.
+func BuildTogether(a, b *Node) bool {
+	x, y := buildConstraint(a), buildConstraint(b)
+	if x == nil || y == nil {
+		return true
+	}
+	return satisfiable([]constraint.Expr{x, y}, "", "")
+}
+
+// buildConstraint parses a node's PropBuildConstraint.
+func buildConstraint(n *Node) constraint.Expr {
+	s := n.Properties[PropBuildConstraint]
+	if s == "" {
+		return nil
+	}
+	expr, err := constraint.Parse("//go:build " + s)
+	if err != nil {
+		return nil
+	}
+	return expr
+}
+
+// satisfiable reports whether some GOOS, GOARCH and set of other tags
+// satisfies all of exprs. An empty goos or goarch tries every known value.
+func satisfiable(exprs []constraint.Expr, goos, goarch string) bool {
+	oses, arches := knownOS, knownArch
+	if goos != "" {
+		oses = []string{goos}
+	}
+	if goarch != "" {
+		arches = []string{goarch}
+	}
+	var free []string
+	for _, x := range exprs {
+		x.Eval(func(tag string) bool {
+			if tag != "unix" && !IsGOOS(tag) && !IsGOARCH(tag) && !slices.Contains(free, tag) {
+				free = append(free, tag)
+			}
+			return false
+		})
+	}
+	if len(free) > maxFreeTags {
+		return true
+	}
+
+	for set := 0; set < 1<<len(free); set++ {
+		for _, os := range oses {
+			for _, arch := range arches {
+				// As in go/build, android implies linux, ios implies
+				// darwin and illumos implies solaris.
+				ok := func(tag string) bool {
+					switch tag {
+					case os, arch:
+						return true
+					case "unix":
+						return unixOS[os]
+					case "linux":
+						return os == "android"
+					case "darwin":
+						return os == "ios"
+					case "solaris":
+						return os == "illumos"
+					}
+					if i := slices.Index(free, tag); i >= 0 {
+						return set&(1<<i) != 0
+					}
+					return false
+				}
+				all := true
+				for _, x := range exprs {
+					if !x.Eval(ok) {
+						all = false
+						break
+					}
+				}
+				if all {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package graph
+
+import "testing"
+
+func TestBuildsOn(t *testing.T) {
+	tests := []struct {
+		constraint string
+		platform   string
+		want       bool
+	}{
+		{"", "windows", true},
+		{"linux", "linux", true},
+		{"linux", "linux/arm64", true},
+		{"linux", "windows/amd64", false},
+		{"linux", "android", true}, // android implies linux
+		{"unix", "darwin", true},
+		{"unix", "windows", false},
+		{"linux && amd64", "linux/arm64", false},
+		{"linux && amd64", "linux", true},
+		{"!windows", "windows", false},
+		{"linux && cgo", "linux", true}, // cgo may be set
+		{"integration && !linux", "linux", false},
+		{"!integration", "windows", true},
+		{"not a ( constraint", "windows", true}, // unparsable: built everywhere
+	}
+	for _, tt := range tests {
+		n := &Node{Properties: map[string]string{PropBuildConstraint: tt.constraint}}
+		if got := BuildsOn(n, tt.platform); got != tt.want {
+			t.Errorf("BuildsOn(%q, %q) = %v, want %v", tt.constraint, tt.platform, got, tt.want)
+		}
+	}
+}
+
+func TestBuildTogether(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"", "windows", true},
+		{"linux", "windows", false},
+		{"linux", "!windows", true},
+		{"linux", "unix", true},
+		{"windows", "unix", false},
+		{"amd64", "linux", true},
+		{"linux && amd64", "linux && arm64", false},
+		{"debug", "!debug", false},
+		{"debug", "!release", true},
+	}
+	for _, tt := range tests {
+		a := &Node{Properties: map[string]string{PropBuildConstraint: tt.a}}
+		b := &Node{Properties: map[string]string{PropBuildConstraint: tt.b}}
+		if got := BuildTogether(a, b); got != tt.want {
+			t.Errorf("BuildTogether(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
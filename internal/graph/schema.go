@@ -1,42 +1,78 @@
 package graph
 
 import (
-	"crypto/sha256"
 	"fmt"
+	"strconv"
 )
 
 // NodeType represents the kind of entity in the knowledge graph.
 type NodeType string
 
 const (
-	NodeRepository   NodeType = "Repository"
-	NodeService      NodeType = "Service"
-	NodeModule       NodeType = "Module"
-	NodePackage      NodeType = "Package"
-	NodeFile         NodeType = "File"
-	NodeFunction     NodeType = "Function"
-	NodeMethod       NodeType = "Method"
-	NodeClass        NodeType = "Class"
-	NodeStruct       NodeType = "Struct"
-	NodeInterface    NodeType = "Interface"
-	NodeEnum         NodeType = "Enum"
-	NodeType_        NodeType = "Type"
-	NodeConstant     NodeType = "Constant"
-	NodeVariable     NodeType = "Variable"
-	NodeAPIEndpoint  NodeType = "APIEndpoint"
-	NodeDBModel      NodeType = "DBModel"
-	NodeDomainModel  NodeType = "DomainModel"
-	NodeViewModel    NodeType = "ViewModel"
-	NodeDTO          NodeType = "DTO"
-	NodeMigration    NodeType = "Migration"
-	NodeDependency   NodeType = "Dependency"
-	NodeDocument     NodeType = "Document"
-	NodeAIGuideline  NodeType = "AIGuideline"
-	NodeTestFunction NodeType = "TestFunction"
-	NodeTestFile     NodeType = "TestFile"
-	NodeDirectory    NodeType = "Directory"
-	NodeTopic        NodeType = "Topic"
-	NodePerson       NodeType = "Person"
+	NodeRepository    NodeType = "Repository"
+	NodeService       NodeType = "Service"
+	NodeModule        NodeType = "Module"
+	NodePackage       NodeType = "Package"
+	NodeFile          NodeType = "File"
+	NodeFunction      NodeType = "Function"
+	NodeMethod        NodeType = "Method"
+	NodeClass         NodeType = "Class"
+	NodeStruct        NodeType = "Struct"
+	NodeInterface     NodeType = "Interface"
+	NodeEnum          NodeType = "Enum"
+	NodeType_         NodeType = "Type"
+	NodeConstant      NodeType = "Constant"
+	NodeVariable      NodeType = "Variable"
+	NodeAPIEndpoint   NodeType = "APIEndpoint"
+	NodeDBModel       NodeType = "DBModel"
+	NodeDomainModel   NodeType = "DomainModel"
+	NodeViewModel     NodeType = "ViewModel"
+	NodeDTO           NodeType = "DTO"
+	NodeMigration     NodeType = "Migration"
+	NodeDependency    NodeType = "Dependency"
+	NodeDocument      NodeType = "Document"
+	NodeAIGuideline   NodeType = "AIGuideline"
+	NodeTestFunction  NodeType = "TestFunction"
+	NodeTestFile      NodeType = "TestFile"
+	NodeDirectory     NodeType = "Directory"
+	NodeTopic         NodeType = "Topic"
+	NodePerson        NodeType = "Person"
+	NodeConfig        NodeType = "Config"
+	NodeFinding       NodeType = "Finding"
+	NodeVulnerability NodeType = "Vulnerability"
+	// NodeJob is a scheduled job (Kubernetes CronJob, Spring @Scheduled,
+	// node-cron, sidekiq-cron, Go cron libraries). It Calls the function or
+	// class it runs.
+	NodeJob NodeType = "Job"
+	// NodeTelemetry is a metric, tracing span or log event emitted by code,
+	// named as it appears on dashboards and in log search. It is per file;
+	// functions Emit it.
+	NodeTelemetry NodeType = "Telemetry"
+	// NodeAnnotation is a TODO, FIXME, HACK or XXX comment. It Annotates
+	// the function, method or file it appears in.
+	NodeAnnotation NodeType = "Annotation"
+	// NodeIssue is an issue tracker ticket (Jira "PROJ-123", GitHub "#456"
+	// or "owner/repo#456") referenced from commit messages or comments.
+	// Code References it.
+	NodeIssue NodeType = "Issue"
+	// NodeLLMCache holds a cached LLM response, keyed by prompt hash.
+	NodeLLMCache NodeType = "LLMCache"
+	// NodeUnresolved stands for a node a parser can name but not locate,
+	// such as the interface of a class when it is declared in another file.
+	// Properties["target_type"] is the type of node named. The linker binds
+	// it to the node declaring the name, when it finds one.
+	NodeUnresolved NodeType = "Unresolved"
+	// NodePipeline is a CI pipeline: a GitHub Actions workflow or a GitLab
+	// CI configuration. It Contains its PipelineJobs.
+	NodePipeline NodeType = "Pipeline"
+	// NodePipelineJob is a job of a CI pipeline. It Targets the services it
+	// builds, tests or deploys.
+	NodePipelineJob NodeType = "PipelineJob"
+	// NodeExternalStore is a cache, search engine or object store the code
+	// talks to (Redis, Memcached, Elasticsearch, S3), named by its bucket or
+	// index when the code names one. It is shared by every file using it;
+	// functions and services Use it.
+	NodeExternalStore NodeType = "ExternalStore"
 )
 
 // Well-known property keys used for architectural classification.
@@ -56,25 +92,128 @@ const (
 	// PropGraphSource indicates which branch a node or edge came from
 	// when using BranchStore. Set to the branch name on reads, never persisted.
 	PropGraphSource = "graph_source"
+
+	// PropConfidence is how a linker-created edge was resolved: "exact"
+	// (unambiguous rule), "heuristic" (name, path-shape, or proximity
+	// matching), or "llm" (inferred by an LLM phase).
+	PropConfidence = "confidence"
+
+	// PropConfidenceScore is the edge's confidence in [0, 1], formatted with
+	// two decimals. Edges without one (parser-extracted) count as 1.
+	PropConfidenceScore = "confidence_score"
+
+	// PropObserved is "true" on edges seen at runtime in ingested traces,
+	// whether or not they were also inferred statically.
+	PropObserved = "observed"
+
+	// PropObservedCount is the number of trace spans that observed an edge,
+	// accumulated across ingestions.
+	PropObservedCount = "observed_count"
+
+	// PropNotebookCell is the index, in a notebook's cell list, of the cell
+	// a node was declared in. The node's lines count from the cell's start.
+	PropNotebookCell = "cell"
+
+	// PropBuildConstraint is the build constraint of a node's file, in
+	// //go:build syntax without the prefix (e.g. "linux && amd64"),
+	// combining the file's //go:build line and its _GOOS/_GOARCH name
+	// suffix. See BuildsOn and BuildTogether.
+	PropBuildConstraint = "build_constraint"
 )
 
+// Confidence levels stored in PropConfidence.
+const (
+	ConfidenceExact     = "exact"
+	ConfidenceHeuristic = "heuristic"
+	ConfidenceLLM       = "llm"
+)
+
+// EdgeConfidence returns an edge's confidence score. Edges without a score
+// are parser-extracted and score 1, except LLM-inferred edges from older
+// graphs, which score 0.5.
+func EdgeConfidence(e *Edge) float64 {
+	if s, ok := e.Properties[PropConfidenceScore]; ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	if e.Properties["inferred"] == "true" {
+		return 0.5
+	}
+	return 1
+}
+
+// FilterEdgesByConfidence returns the edges whose confidence score is at
+// least min. A min of 0 or less returns edges unchanged.
+func FilterEdgesByConfidence(edges []*Edge, min float64) []*Edge {
+	if min <= 0 {
+		return edges
+	}
+	var kept []*Edge
+	for _, e := range edges {
+		if EdgeConfidence(e) >= min {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
 // EdgeType represents a relationship between two nodes.
 type EdgeType string
 
 const (
-	EdgeContains   EdgeType = "Contains"
-	EdgeImports    EdgeType = "Imports"
-	EdgeDependsOn  EdgeType = "DependsOn"
-	EdgeCalls      EdgeType = "Calls"
-	EdgeImplements EdgeType = "Implements"
-	EdgeExposes    EdgeType = "Exposes"
-	EdgeConsumes   EdgeType = "Consumes"
-	EdgeDocuments  EdgeType = "Documents"
-	EdgeTests      EdgeType = "Tests"
-	EdgeMigrates   EdgeType = "Migrates"
-	EdgeConfigures EdgeType = "Configures"
-	EdgeHasTopic   EdgeType = "HasTopic"
-	EdgeAppearsIn  EdgeType = "AppearsIn"
+	EdgeContains    EdgeType = "Contains"
+	EdgeImports     EdgeType = "Imports"
+	EdgeDependsOn   EdgeType = "DependsOn"
+	EdgeCalls       EdgeType = "Calls"
+	EdgeImplements  EdgeType = "Implements"
+	EdgeExposes     EdgeType = "Exposes"
+	EdgeConsumes    EdgeType = "Consumes"
+	EdgeDocuments   EdgeType = "Documents"
+	EdgeTests       EdgeType = "Tests"
+	EdgeMigrates    EdgeType = "Migrates"
+	EdgeConfigures  EdgeType = "Configures"
+	EdgeHasTopic    EdgeType = "HasTopic"
+	EdgeAppearsIn   EdgeType = "AppearsIn"
+	EdgeCovers      EdgeType = "Covers"
+	EdgeReads       EdgeType = "Reads"
+	EdgeAffects     EdgeType = "Affects"
+	EdgeRenders     EdgeType = "Renders"
+	EdgeRenamedFrom EdgeType = "RenamedFrom"
+	// EdgeThrows links a function or method to an error or exception type
+	// (or Go sentinel error) it throws, raises, panics with or returns.
+	EdgeThrows EdgeType = "Throws"
+	// EdgeEmits links a function, method or file to a metric, span or log
+	// event (Telemetry node) it defines or emits.
+	EdgeEmits EdgeType = "Emits"
+	// EdgeAnnotates links an Annotation to the function, method or file
+	// containing it.
+	EdgeAnnotates EdgeType = "Annotates"
+	// EdgeReferences links a file, function or method to an Issue named in
+	// the messages of commits that changed it (source=commit) or in its
+	// comments (source=comment).
+	EdgeReferences EdgeType = "References"
+	// EdgeExtends links a class to the superclass it extends.
+	EdgeExtends EdgeType = "Extends"
+	// EdgeTargets links a PipelineJob to a service it builds, tests or
+	// deploys. Properties["actions"] lists which.
+	EdgeTargets EdgeType = "Targets"
+	// EdgeRelatesTo links a DBModel to a model its ORM declares an
+	// association with. Properties["cardinality"] is one_to_one,
+	// one_to_many, many_to_one or many_to_many, seen from the source.
+	EdgeRelatesTo EdgeType = "RelatesTo"
+	// EdgeUses links a function, method, file or service to an
+	// ExternalStore it reads or writes. Properties["operations"] lists
+	// which of read, write, delete, search, publish and subscribe.
+	EdgeUses EdgeType = "Uses"
+	// EdgeProvides links a constructor registered with a DI container
+	// (google/wire, uber fx, dig) to the type it provides.
+	// Properties["container"] names the container.
+	EdgeProvides EdgeType = "Provides"
+	// EdgeInjectedInto links a DI provider to a provider or invoked
+	// function taking the provided type as a parameter.
+	// Properties["type"] names the injected type.
+	EdgeInjectedInto EdgeType = "InjectedInto"
 )
 
 // Node represents a source code or documentation entity in the knowledge graph.
@@ -114,8 +253,7 @@ type GraphStats struct {
 
 // NewNodeID generates a deterministic node ID from the type, file path, and name.
 // The ID is a hex-encoded SHA-256 hash prefix to keep keys compact and collision-resistant.
+// Symbols nested in other declarations should use SymbolKey instead.
 func NewNodeID(nodeType, filePath, name string) string {
-	raw := fmt.Sprintf("%s:%s:%s", nodeType, filePath, name)
-	h := sha256.Sum256([]byte(raw))
-	return fmt.Sprintf("%x", h[:12])
+	return hashID(fmt.Sprintf("%s:%s:%s", nodeType, filePath, name))
 }
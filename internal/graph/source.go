@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrNoSource is returned when a node has no source span or its file
+// cannot be read.
+var ErrNoSource = errors.New("no source")
+
+// SourceStore is implemented by stores that can return the source text of
+// the nodes they hold, so callers need not resolve and slice files
+// themselves.
+type SourceStore interface {
+	// GetSource returns the text of the node's Line to EndLine span, or
+	// just its Line when it has no EndLine, ending in a newline.
+	GetSource(ctx context.Context, nodeID string) (string, error)
+	// GetSourceLines returns lines start to end of a file by its graph
+	// path. An end past the end of the file is clamped to it.
+	GetSourceLines(ctx context.Context, filePath string, start, end int) (string, error)
+}
+
+// GetSource returns the source text of a node when store is a
+// SourceStore, and ErrNoSource otherwise.
+func GetSource(ctx context.Context, store Store, nodeID string) (string, error) {
+	ss, ok := store.(SourceStore)
+	if !ok {
+		return "", ErrNoSource
+	}
+	return ss.GetSource(ctx, nodeID)
+}
+
+// SourceReader reads node spans from the files they were indexed from. It
+// resolves graph paths against repository roots and remembers the byte
+// offset of each line of the files it reads, so a span is read with one
+// ReadAt. A file is re-scanned when its size or modification time changes.
+// It is safe for concurrent use.
+type SourceReader struct {
+	roots []string
+
+	mu    sync.Mutex
+	files map[string]*lineIndex // by resolved path
+}
+
+// lineIndex holds the byte offsets of the lines of one file version.
+type lineIndex struct {
+	size    int64
+	modTime time.Time
+	// offsets[i] is where line i+1 starts; the last entry is the file size.
+	offsets []int64
+}
+
+// NewSourceReader creates a SourceReader resolving relative graph paths
+// against roots, first match wins. Absolute paths are read as they are.
+func NewSourceReader(roots ...string) *SourceReader {
+	return &SourceReader{roots: roots, files: make(map[string]*lineIndex)}
+}
+
+// Read returns the source text of node's span.
+func (r *SourceReader) Read(node *Node) (string, error) {
+	end := node.EndLine
+	if end < node.Line {
+		end = node.Line
+	}
+	return r.Lines(node.FilePath, node.Line, end)
+}
+
+// Lines returns lines start to end of the file at filePath. It returns
+// ErrNoSource when the file cannot be found or start is not in it, which
+// happens when the file changed since it was indexed.
+func (r *SourceReader) Lines(filePath string, start, end int) (string, error) {
+	if filePath == "" || start <= 0 || end < start {
+		return "", ErrNoSource
+	}
+	f, err := r.open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrNoSource, filePath, err)
+	}
+	defer f.Close()
+	idx, err := r.index(f)
+	if err != nil {
+		return "", fmt.Errorf("index %s: %w", filePath, err)
+	}
+	lines := len(idx.offsets) - 1
+	if start > lines {
+		return "", fmt.Errorf("%w: %s has %d lines, want line %d", ErrNoSource, filePath, lines, start)
+	}
+	end = min(end, lines)
+	from, to := idx.offsets[start-1], idx.offsets[end]
+	buf := make([]byte, to-from)
+	if _, err := f.ReadAt(buf, from); err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read %s: %w", filePath, err)
+	}
+	if len(buf) > 0 && buf[len(buf)-1] != '\n' {
+		buf = append(buf, '\n')
+	}
+	return string(buf), nil
+}
+
+// open opens a file by its graph path.
+func (r *SourceReader) open(filePath string) (*os.File, error) {
+	if filepath.IsAbs(filePath) {
+		return os.Open(filePath)
+	}
+	for _, root := range r.roots {
+		if f, err := os.Open(filepath.Join(root, filePath)); err == nil {
+			return f, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+// index returns the line offsets of f, scanning it unless the cached ones
+// are for the same size and modification time.
+func (r *SourceReader) index(f *os.File) (*lineIndex, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	idx, ok := r.files[f.Name()]
+	r.mu.Unlock()
+	if ok && idx.size == info.Size() && idx.modTime.Equal(info.ModTime()) {
+		return idx, nil
+	}
+
+	idx = &lineIndex{size: info.Size(), modTime: info.ModTime(), offsets: []int64{0}}
+	br := bufio.NewReader(io.NewSectionReader(f, 0, info.Size()))
+	var pos int64
+	for {
+		line, err := br.ReadSlice('\n')
+		pos += int64(len(line))
+		if err == nil {
+			idx.offsets = append(idx.offsets, pos)
+			continue
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		break
+	}
+	if last := idx.offsets[len(idx.offsets)-1]; pos > last {
+		idx.offsets = append(idx.offsets, pos) // final line without a newline
+	}
+
+	r.mu.Lock()
+	r.files[f.Name()] = idx
+	r.mu.Unlock()
+	return idx, nil
+}
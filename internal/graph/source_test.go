@@ -0,0 +1,88 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestGetSource(t *testing.T) {
+	ctx := context.Background()
+	store, err := embedded.NewStore(filepath.Join(t.TempDir(), "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	repo := t.TempDir()
+	src := "package auth\n\nfunc Login() {\n\tcheck()\n}\n\nfunc check() {}"
+	path := filepath.Join(repo, "auth/login.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []*graph.Node{
+		{ID: "login", Type: graph.NodeFunction, Name: "Login", FilePath: "auth/login.go", Line: 3, EndLine: 5},
+		{ID: "check", Type: graph.NodeFunction, Name: "check", FilePath: "auth/login.go", Line: 7},
+		{ID: "abs", Type: graph.NodeFunction, Name: "Login", FilePath: path, Line: 3, EndLine: 3},
+		{ID: "stale", Type: graph.NodeFunction, Name: "gone", FilePath: "auth/login.go", Line: 40, EndLine: 42},
+		{ID: "missing", Type: graph.NodeFunction, Name: "f", FilePath: "auth/missing.go", Line: 1, EndLine: 2},
+		{ID: "pkg", Type: graph.NodePackage, Name: "auth"},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := store.GetSource(ctx, "login"); !errors.Is(err, graph.ErrNoSource) {
+		t.Fatalf("GetSource without roots: err = %v, want ErrNoSource", err)
+	}
+	store.SetSourceRoots(t.TempDir(), repo)
+	cached := graph.NewCachedStore(store.WithReadBranches(store.ReadBranches()), 0)
+
+	tests := []struct {
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{"login", "func Login() {\n\tcheck()\n}\n", false},
+		{"check", "func check() {}\n", false}, // last line has no newline
+		{"abs", "func Login() {\n", false},
+		{"stale", "", true},
+		{"missing", "", true},
+		{"pkg", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := graph.GetSource(ctx, cached, tt.id)
+			if tt.wantErr {
+				if !errors.Is(err, graph.ErrNoSource) {
+					t.Errorf("err = %v, want ErrNoSource", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("GetSource = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if got, err := store.GetSourceLines(ctx, "auth/login.go", 6, 100); err != nil || got != "\nfunc check() {}\n" {
+		t.Errorf("GetSourceLines(6, 100) = %q, %v", got, err)
+	}
+
+	// Edits since indexing are picked up rather than served from stale offsets.
+	if err := os.WriteFile(path, []byte("package auth\n\n// Login logs in.\nfunc Login() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := store.GetSource(ctx, "login"); err != nil || got != "// Login logs in.\nfunc Login() {}\n" {
+		t.Errorf("GetSource after edit = %q, %v", got, err)
+	}
+}
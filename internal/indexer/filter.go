@@ -0,0 +1,182 @@
+package indexer
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/parser"
+	"github.com/imyousuf/CodeEagle/internal/secrets"
+)
+
+// GeneratedMode says what the indexer does with generated files.
+type GeneratedMode string
+
+const (
+	// GeneratedIndex parses generated files like any other.
+	GeneratedIndex GeneratedMode = "index"
+	// GeneratedAnnotate parses generated files and marks their nodes
+	// generated=true.
+	GeneratedAnnotate GeneratedMode = "annotate"
+	// GeneratedSkip doesn't parse generated files.
+	GeneratedSkip GeneratedMode = "skip"
+)
+
+// vendoredDirs are directory names holding third-party or build output that
+// SkipVendored leaves out.
+var vendoredDirs = map[string]bool{
+	"vendor":           true,
+	"node_modules":     true,
+	"bower_components": true,
+	"dist":             true,
+	".venv":            true,
+}
+
+// generatedMarkers are lower-cased header fragments identifying generated
+// code: Go's "Code generated ... DO NOT EDIT.", protoc, Facebook's @generated,
+// .NET's <auto-generated>, and common generator banners.
+var generatedMarkers = []string{
+	"code generated",
+	"@generated",
+	"<auto-generated",
+	"auto-generated",
+	"autogenerated",
+	"automatically generated",
+	"generated by the protocol buffer compiler",
+}
+
+// generatedHeaderLines is how many leading lines are searched for markers.
+const generatedHeaderLines = 10
+
+// FileFilter decides which files the indexer parses. The zero value parses
+// every file a parser is registered for.
+type FileFilter struct {
+	// MaxFileSize skips files larger than this many bytes (0 = no limit).
+	MaxFileSize int64
+	// SkipVendored skips files under vendored directories (vendor/,
+	// node_modules/, dist/, ...) and minified bundles.
+	SkipVendored bool
+	// Generated is the handling of files whose header marks them generated;
+	// "" behaves as GeneratedIndex.
+	Generated GeneratedMode
+	// GeneratedMarkers adds header fragments (matched case-insensitively)
+	// identifying generated files.
+	GeneratedMarkers []string
+	// Languages holds per-language rules keyed by parser language.
+	Languages map[parser.Language]LanguageFilter
+}
+
+// LanguageFilter holds the rules for one language's files. Globs are matched
+// against repository-relative paths; a pattern without "/" matches the base
+// name.
+type LanguageFilter struct {
+	// Include, when set, restricts the language to matching files.
+	Include []string
+	// Exclude skips matching files.
+	Exclude []string
+	// MaxFileSize overrides FileFilter.MaxFileSize when non-zero.
+	MaxFileSize int64
+}
+
+// skipPath returns why a file should not be parsed based on its path,
+// language, and size, or "" to parse it.
+func (f *FileFilter) skipPath(relPath string, lang parser.Language, size int64) string {
+	if f == nil {
+		return ""
+	}
+	relPath = path.Clean(strings.ReplaceAll(relPath, "\\", "/"))
+	if f.SkipVendored {
+		for _, dir := range strings.Split(path.Dir(relPath), "/") {
+			if vendoredDirs[dir] {
+				return "vendored"
+			}
+		}
+		if strings.Contains(path.Base(relPath), ".min.") {
+			return "minified"
+		}
+	}
+
+	limit := f.MaxFileSize
+	if lf, ok := f.Languages[lang]; ok {
+		if len(lf.Include) > 0 && !secrets.Excluded(lf.Include, relPath) {
+			return "not included for " + string(lang)
+		}
+		if secrets.Excluded(lf.Exclude, relPath) {
+			return "excluded for " + string(lang)
+		}
+		if lf.MaxFileSize > 0 {
+			limit = lf.MaxFileSize
+		}
+	}
+	if limit > 0 && size > limit {
+		return fmt.Sprintf("larger than %d bytes", limit)
+	}
+	return ""
+}
+
+// skipContent returns why a file should not be parsed based on its content,
+// or "" to parse it. generated reports whether the header marks it generated.
+func (f *FileFilter) skipContent(content []byte) (reason string, generated bool) {
+	if f == nil {
+		return "", false
+	}
+	if f.SkipVendored && isMinified(content) {
+		return "minified", false
+	}
+	if f.Generated == "" || f.Generated == GeneratedIndex {
+		return "", false
+	}
+	if !isGenerated(content, f.GeneratedMarkers) {
+		return "", false
+	}
+	if f.Generated == GeneratedSkip {
+		return "generated", true
+	}
+	return "", true
+}
+
+// skipDir reports whether a directory walk should not descend into name.
+func (f *FileFilter) skipDir(name string) bool {
+	return f != nil && f.SkipVendored && vendoredDirs[name]
+}
+
+// isGenerated reports whether the first lines of content carry a generated
+// code marker.
+func isGenerated(content []byte, extra []string) bool {
+	header := content
+	for i, n := 0, 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			if n++; n == generatedHeaderLines {
+				header = content[:i]
+				break
+			}
+		}
+	}
+	header = bytes.ToLower(header)
+	for _, m := range generatedMarkers {
+		if bytes.Contains(header, []byte(m)) {
+			return true
+		}
+	}
+	for _, m := range extra {
+		if m != "" && bytes.Contains(header, bytes.ToLower([]byte(m))) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMinified reports whether content looks like a minified bundle: a sizable
+// sample whose lines average several hundred characters.
+func isMinified(content []byte) bool {
+	const sample, minSize, avgLine = 32 * 1024, 4 * 1024, 500
+	if len(content) < minSize {
+		return false
+	}
+	if len(content) > sample {
+		content = content[:sample]
+	}
+	lines := bytes.Count(content, []byte("\n")) + 1
+	return len(content)/lines > avgLine
+}
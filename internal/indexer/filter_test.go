@@ -0,0 +1,155 @@
+package indexer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestFileFilterSkipPath(t *testing.T) {
+	f := &FileFilter{
+		MaxFileSize:  1000,
+		SkipVendored: true,
+		Languages: map[parser.Language]LanguageFilter{
+			parser.LangTypeScript: {Include: []string{"web/**"}, Exclude: []string{"*.d.ts"}, MaxFileSize: 50},
+		},
+	}
+	tests := []struct {
+		path string
+		lang parser.Language
+		size int64
+		want string
+	}{
+		{"pkg/api/handler.go", parser.LangGo, 500, ""},
+		{"pkg/api/handler.go", parser.LangGo, 5000, "larger than 1000 bytes"},
+		{"vendor/github.com/x/y.go", parser.LangGo, 10, "vendored"},
+		{"web/node_modules/react/index.js", parser.LangJavaScript, 10, "vendored"},
+		{"web/dist/app.js", parser.LangJavaScript, 10, "vendored"},
+		{"web/static/jquery.min.js", parser.LangJavaScript, 10, "minified"},
+		{"web/src/app.ts", parser.LangTypeScript, 40, ""},
+		{"web/src/app.ts", parser.LangTypeScript, 60, "larger than 50 bytes"},
+		{"web/src/types.d.ts", parser.LangTypeScript, 10, "excluded for typescript"},
+		{"scripts/build.ts", parser.LangTypeScript, 10, "not included for typescript"},
+	}
+	for _, tt := range tests {
+		if got := f.skipPath(tt.path, tt.lang, tt.size); got != tt.want {
+			t.Errorf("skipPath(%s, %s, %d) = %q, want %q", tt.path, tt.lang, tt.size, got, tt.want)
+		}
+	}
+
+	var none *FileFilter
+	if got := none.skipPath("vendor/a.go", parser.LangGo, 1<<30); got != "" {
+		t.Errorf("nil filter skipPath = %q, want \"\"", got)
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		extra   []string
+		want    bool
+	}{
+		{"go", "// Code generated by protoc-gen-go. DO NOT EDIT.\npackage pb\n", nil, true},
+		{"facebook", "/**\n * @generated SignedSource<<abc>>\n */\n", nil, true},
+		{"dotnet", "//------\n// <auto-generated>\n//     This code was generated by a tool.\n", nil, true},
+		{"handwritten", "package main\n\nfunc main() {}\n", nil, false},
+		{"marker past header", strings.Repeat("x\n", 20) + "// Code generated\n", nil, false},
+		{"custom marker", "# Built by gen-tool v2\n", []string{"BUILT BY GEN-TOOL"}, true},
+	}
+	for _, tt := range tests {
+		if got := isGenerated([]byte(tt.content), tt.extra); got != tt.want {
+			t.Errorf("%s: isGenerated = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsMinified(t *testing.T) {
+	bundle := strings.Repeat("var a=function(b){return b+1};", 300)
+	source := strings.Repeat("function add(a, b) {\n  return a + b;\n}\n", 300)
+	if !isMinified([]byte(bundle)) {
+		t.Error("bundle not detected as minified")
+	}
+	if isMinified([]byte(source)) {
+		t.Error("source detected as minified")
+	}
+	if isMinified([]byte("var a=1;")) {
+		t.Error("tiny file detected as minified")
+	}
+}
+
+func TestIndexFileGenerated(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "api.pb.go")
+	src := "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n\nfunc Decode() {}\n"
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []GeneratedMode{GeneratedAnnotate, GeneratedSkip} {
+		t.Run(string(mode), func(t *testing.T) {
+			idx, store := setupTestIndexer(t)
+			idx.repoRoots = []string{dir}
+			idx.filter = &FileFilter{Generated: mode}
+			if err := idx.IndexFile(ctx, file); err != nil {
+				t.Fatalf("IndexFile: %v", err)
+			}
+			nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFunction})
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch mode {
+			case GeneratedSkip:
+				if len(nodes) != 0 || idx.Stats().FilesSkipped != 1 {
+					t.Errorf("got %d nodes, %d skipped; want 0 nodes, 1 skipped", len(nodes), idx.Stats().FilesSkipped)
+				}
+			case GeneratedAnnotate:
+				if len(nodes) != 1 || nodes[0].Properties["generated"] != "true" {
+					t.Errorf("got %d nodes (%v), want Decode marked generated", len(nodes), nodes)
+				}
+			}
+		})
+	}
+}
+
+func TestIndexFileParseErrors(t *testing.T) {
+	ctx := context.Background()
+	idx, store := setupTestIndexer(t)
+	dir := t.TempDir()
+	idx.repoRoots = []string{dir}
+	file := filepath.Join(dir, "bad.go")
+	if err := os.WriteFile(file, []byte("package bad\n\nfunc Good() {}\n\nfunc broken( {\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatalf("IndexFile: %v", err)
+	}
+
+	findings, err := store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeFinding,
+		Properties: map[string]string{"category": "parse_error"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) == 0 || findings[0].Line != 5 || findings[0].Properties["message"] == "" {
+		t.Fatalf("parse_error findings = %v, want one at line 5", findings)
+	}
+	files, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Properties["parse_errors"] != strconv.Itoa(len(findings)) {
+		t.Errorf("file parse_errors = %v, want %d", files, len(findings))
+	}
+	if got := idx.Stats().FilesPartial; got != 1 {
+		t.Errorf("FilesPartial = %d, want 1", got)
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
 	"github.com/imyousuf/CodeEagle/internal/parser"
+	"github.com/imyousuf/CodeEagle/internal/secrets"
 	"github.com/imyousuf/CodeEagle/internal/watcher"
 	"github.com/imyousuf/CodeEagle/pkg/llm"
 )
@@ -20,17 +22,43 @@ type IndexerConfig struct {
 	GraphStore     graph.Store
 	ParserRegistry *parser.Registry
 	WatcherConfig  *watcher.WatcherConfig
-	RepoRoots      []string // repository root paths for abs→rel path conversion
+	RepoRoots      []string          // repository root paths for abs→rel path conversion
+	RepoName       string            // multi-repo graphs: prefix for relative paths and "repo" node property
+	NodeProperties map[string]string // properties set on every indexed node (e.g., repo_url, commit)
 	Verbose        bool
 	Logger         func(format string, args ...any) // optional logger, defaults to fmt.Fprintf(os.Stderr, ...)
 	LLMClient      llm.Client                       // optional LLM client for auto-summarization
 	AutoSummarize  bool                             // enable post-index LLM summarization
 	PostIndexHook  func(ctx context.Context) error  // optional hook called after initial full index (e.g., linker)
+	ChangeHook     func(ctx context.Context) error  // optional hook called once watched file changes settle (e.g., change notifications)
+	ChangeDelay    time.Duration                    // quiet period after the last change before ChangeHook (default 2s)
+	ScanSecrets    bool                             // record hard-coded credentials as Finding nodes
+	SecretsExclude []string                         // globs of files skipped by the secrets scan
+	FileFilter     *FileFilter                      // optional size, vendored, generated, and per-language rules
+	Progress       func(Progress)                   // optional callback for periodic IndexDirectory progress
+	ProgressEvery  time.Duration                    // interval between progress callbacks (default 2s)
+	BatchSize      int                              // nodes and edges buffered per store write (default DefaultBatchSize)
+	TrackRenames   bool                             // link renamed and moved symbols to their old IDs with RenamedFrom edges
+}
+
+// Progress reports how far IndexDirectory has got. Total counts the files a
+// parser is registered for; ETA is extrapolated from the rate so far.
+type Progress struct {
+	Dir     string        `json:"dir"`
+	Files   int           `json:"files"`
+	Total   int           `json:"total"`
+	Nodes   int           `json:"nodes"`
+	Edges   int           `json:"edges"`
+	Elapsed time.Duration `json:"elapsed"`
+	ETA     time.Duration `json:"eta"`
+	Done    bool          `json:"done"`
 }
 
 // IndexStats holds statistics about the indexing state.
 type IndexStats struct {
 	FilesIndexed  int       `json:"files_indexed"`
+	FilesSkipped  int       `json:"files_skipped,omitempty"`
+	FilesPartial  int       `json:"files_partial,omitempty"` // parsed with syntax errors
 	NodesTotal    int64     `json:"nodes_total"`
 	EdgesTotal    int64     `json:"edges_total"`
 	LastIndexTime time.Time `json:"last_index_time"`
@@ -39,22 +67,38 @@ type IndexStats struct {
 
 // Indexer orchestrates file parsing and knowledge graph updates.
 type Indexer struct {
-	store         graph.Store
-	registry      *parser.Registry
-	wcfg          *watcher.WatcherConfig
-	matcher       *watcher.GitIgnoreMatcher
-	repoRoots     []string
-	verbose       bool
-	log           func(format string, args ...any)
-	llmClient     llm.Client
-	autoSummarize bool
-	postIndexHook func(ctx context.Context) error
+	store          graph.Store
+	registry       *parser.Registry
+	wcfg           *watcher.WatcherConfig
+	matcher        *watcher.GitIgnoreMatcher
+	repoRoots      []string
+	repoName       string
+	nodeProps      map[string]string
+	verbose        bool
+	log            func(format string, args ...any)
+	llmClient      llm.Client
+	autoSummarize  bool
+	postIndexHook  func(ctx context.Context) error
+	changeHook     func(ctx context.Context) error
+	changeDelay    time.Duration
+	scanSecrets    bool
+	secretsExclude []string
+	filter         *FileFilter
+	progress       func(Progress)
+	progressEvery  time.Duration
+	batchSize      int
+	trackRenames   bool
 
 	mu           sync.Mutex
 	filesIndexed int
+	nodesAdded   int
+	edgesAdded   int
+	filesSkipped int
+	filesPartial int
 	errors       []string
 	lastIndex    time.Time
 	changedFiles map[string]struct{} // tracks relative paths of files changed since last reset
+	retired      []retiredSymbol     // rename candidates, oldest first
 }
 
 // NewIndexer creates a new Indexer with the given configuration.
@@ -73,6 +117,20 @@ func NewIndexer(cfg IndexerConfig) *Indexer {
 	matcher := watcher.NewGitIgnoreMatcher(paths, allPatterns)
 	_ = matcher.LoadPatterns()
 
+	progressEvery := cfg.ProgressEvery
+	if progressEvery <= 0 {
+		progressEvery = 2 * time.Second
+	}
+	changeDelay := cfg.ChangeDelay
+	if changeDelay <= 0 {
+		changeDelay = 2 * time.Second
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
 	logFn := cfg.Logger
 	if logFn == nil {
 		logFn = func(format string, args ...any) {
@@ -81,17 +139,28 @@ func NewIndexer(cfg IndexerConfig) *Indexer {
 	}
 
 	return &Indexer{
-		store:         cfg.GraphStore,
-		registry:      cfg.ParserRegistry,
-		wcfg:          cfg.WatcherConfig,
-		matcher:       matcher,
-		repoRoots:     cfg.RepoRoots,
-		verbose:       cfg.Verbose,
-		log:           logFn,
-		llmClient:     cfg.LLMClient,
-		autoSummarize: cfg.AutoSummarize,
-		postIndexHook: cfg.PostIndexHook,
-		changedFiles:  make(map[string]struct{}),
+		store:          cfg.GraphStore,
+		registry:       cfg.ParserRegistry,
+		wcfg:           cfg.WatcherConfig,
+		matcher:        matcher,
+		repoRoots:      cfg.RepoRoots,
+		repoName:       cfg.RepoName,
+		nodeProps:      cfg.NodeProperties,
+		verbose:        cfg.Verbose,
+		log:            logFn,
+		llmClient:      cfg.LLMClient,
+		autoSummarize:  cfg.AutoSummarize,
+		postIndexHook:  cfg.PostIndexHook,
+		changeHook:     cfg.ChangeHook,
+		changeDelay:    changeDelay,
+		scanSecrets:    cfg.ScanSecrets,
+		secretsExclude: cfg.SecretsExclude,
+		filter:         cfg.FileFilter,
+		progress:       cfg.Progress,
+		progressEvery:  progressEvery,
+		batchSize:      batchSize,
+		trackRenames:   cfg.TrackRenames,
+		changedFiles:   make(map[string]struct{}),
 	}
 }
 
@@ -119,11 +188,15 @@ func (idx *Indexer) ChangedFiles() []string {
 }
 
 // toRelativePath converts an absolute file path to a path relative to the
-// first matching repo root. If no repo root matches, the path is returned as-is.
+// first matching repo root, prefixed with the repo name when set. If no repo
+// root matches, the path is returned as-is.
 func (idx *Indexer) toRelativePath(absPath string) string {
 	for _, root := range idx.repoRoots {
 		rel, err := filepath.Rel(root, absPath)
 		if err == nil && !strings.HasPrefix(rel, "..") {
+			if idx.repoName != "" {
+				return filepath.Join(idx.repoName, rel)
+			}
 			return rel
 		}
 	}
@@ -141,59 +214,154 @@ func (idx *Indexer) IndexFile(ctx context.Context, filePath string) error {
 		return nil // no parser for this file
 	}
 
+	relPath := idx.toRelativePath(filePath)
+	repoRelPath := relPath
+	if idx.repoName != "" {
+		repoRelPath = strings.TrimPrefix(relPath, idx.repoName+string(filepath.Separator))
+	}
+
+	if idx.filter != nil {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("stat file %s: %w", filePath, err)
+		}
+		if reason := idx.filter.skipPath(repoRelPath, p.Language(), info.Size()); reason != "" {
+			return idx.skipFile(ctx, relPath, reason)
+		}
+	}
+
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("read file %s: %w", filePath, err)
 	}
 
-	relPath := idx.toRelativePath(filePath)
+	reason, generated := idx.filter.skipContent(content)
+	if reason != "" {
+		return idx.skipFile(ctx, relPath, reason)
+	}
 
 	if idx.verbose {
 		idx.log("Parsing %s (%s)...", relPath, p.Language())
 	}
 
-	result, err := p.ParseFile(relPath, content)
+	// Nodes and edges are classified, decorated, and written in bounded
+	// batches as the parser emits them.
+	sink := &fileSink{
+		idx:        idx,
+		relPath:    relPath,
+		generated:  generated,
+		classifier: parser.NewClassifier(),
+		w:          &batchWriter{ctx: ctx, store: idx.store, size: idx.batchSize},
+	}
+	diags, err := parser.Stream(p, relPath, content, sink)
 	if err != nil {
+		if sink.err != nil {
+			return sink.err
+		}
+		if sink.started {
+			// Don't leave half a file behind.
+			_ = idx.store.DeleteByFile(ctx, relPath)
+		}
 		return fmt.Errorf("parse file %s: %w", relPath, err)
 	}
+	// A file that now yields nothing still loses its old nodes.
+	if err := sink.start(); err != nil {
+		return err
+	}
 
-	// Classify nodes with architectural roles, design patterns, and layer tags.
-	classifier := parser.NewClassifier()
-	result = classifier.Classify(result)
+	// Whole-file passes run on the file's outline and add their own nodes.
+	tail := &parser.ParseResult{
+		Nodes:       sink.outline,
+		FilePath:    relPath,
+		Language:    p.Language(),
+		Diagnostics: diags,
+	}
+	outlineLen := len(tail.Nodes)
 
-	// Delete old nodes for this file to support incremental updates.
-	if err := idx.store.DeleteByFile(ctx, relPath); err != nil {
-		return fmt.Errorf("delete old nodes for %s: %w", relPath, err)
+	// Record environment variable and config key reads.
+	tail = parser.ExtractConfigUsage(tail, content)
+
+	// Record metrics, tracing spans and log events the code emits.
+	tail = parser.ExtractTelemetry(tail, content)
+
+	// Record Redis, Memcached, Elasticsearch and S3 client calls.
+	tail = parser.ExtractExternalStores(tail, content)
+
+	// Record TODO, FIXME, HACK and XXX comments.
+	tail = parser.ExtractAnnotations(tail, content)
+
+	// Link issues referenced in comments.
+	tail = parser.ExtractIssueReferences(tail, content)
+
+	// Record syntax errors the parser recovered from.
+	if len(diags) > 0 {
+		addParseFindings(tail)
+		idx.mu.Lock()
+		idx.filesPartial++
+		idx.mu.Unlock()
 	}
 
-	// Add new nodes.
-	for _, node := range result.Nodes {
-		if err := idx.store.AddNode(ctx, node); err != nil {
-			return fmt.Errorf("add node %s: %w", node.ID, err)
-		}
+	if idx.scanSecrets && !secrets.Excluded(idx.secretsExclude, filepath.ToSlash(relPath)) {
+		addSecretFindings(tail, content)
 	}
 
-	// Add new edges.
-	for _, edge := range result.Edges {
-		if err := idx.store.AddEdge(ctx, edge); err != nil {
-			return fmt.Errorf("add edge %s: %w", edge.ID, err)
+	for _, node := range tail.Nodes[outlineLen:] {
+		idx.decorate(node, generated)
+		if err := sink.write(node, nil); err != nil {
+			return err
+		}
+	}
+	for _, edge := range tail.Edges {
+		if err := sink.write(nil, edge); err != nil {
+			return err
+		}
+	}
+	if err := sink.w.flush(); err != nil {
+		return err
+	}
+	if err := sink.finishRenames(ctx); err != nil {
+		return err
+	}
+	// The file node was written before its parse error count was known.
+	if len(diags) > 0 && sink.file != nil {
+		if err := idx.store.UpdateNode(ctx, sink.file); err != nil {
+			return fmt.Errorf("update file node %s: %w", sink.file.ID, err)
 		}
 	}
 
 	idx.mu.Lock()
 	idx.filesIndexed++
+	idx.nodesAdded += sink.nodes
+	idx.edgesAdded += sink.edges
 	idx.lastIndex = time.Now()
 	idx.changedFiles[relPath] = struct{}{}
 	idx.mu.Unlock()
 
 	if idx.verbose {
-		idx.log("  -> %d nodes, %d edges", len(result.Nodes), len(result.Edges))
+		idx.log("  -> %d nodes, %d edges", sink.nodes, sink.edges)
 	}
 
 	return nil
 }
 
+// skipFile records a file the filter leaves out, removing anything indexed
+// for it before the filter applied.
+func (idx *Indexer) skipFile(ctx context.Context, relPath, reason string) error {
+	if idx.verbose {
+		idx.log("Skipping %s (%s)", relPath, reason)
+	}
+	if err := idx.store.DeleteByFile(ctx, relPath); err != nil {
+		return fmt.Errorf("delete old nodes for %s: %w", relPath, err)
+	}
+	idx.mu.Lock()
+	idx.filesSkipped++
+	idx.mu.Unlock()
+	return nil
+}
+
 // IndexDirectory walks a directory tree and indexes all supported files.
+// With a Progress callback configured, the tree is counted first and
+// progress is reported every ProgressEvery and once more when done.
 func (idx *Indexer) IndexDirectory(ctx context.Context, dirPath string) error {
 	if idx.verbose {
 		idx.log("Scanning directory: %s", dirPath)
@@ -203,7 +371,76 @@ func (idx *Indexer) IndexDirectory(ctx context.Context, dirPath string) error {
 	startFiles := idx.filesIndexed
 	fileCount := 0
 
-	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+	total := 0
+	if idx.progress != nil {
+		err := idx.walkFiles(ctx, dirPath, false, func(path string) {
+			if _, ok := idx.registry.ParserForFile(path); ok {
+				total++
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	idx.mu.Lock()
+	startNodes, startEdges := idx.nodesAdded, idx.edgesAdded
+	idx.mu.Unlock()
+	report := func(done bool) {
+		idx.mu.Lock()
+		p := Progress{
+			Dir:     dirPath,
+			Files:   fileCount,
+			Total:   total,
+			Nodes:   idx.nodesAdded - startNodes,
+			Edges:   idx.edgesAdded - startEdges,
+			Elapsed: time.Since(dirStart),
+			Done:    done,
+		}
+		idx.mu.Unlock()
+		if p.Files > 0 && p.Total > p.Files && !done {
+			p.ETA = time.Duration(float64(p.Elapsed) / float64(p.Files) * float64(p.Total-p.Files))
+		}
+		idx.progress(p)
+	}
+	lastReport := dirStart
+
+	err := idx.walkFiles(ctx, dirPath, idx.verbose, func(path string) {
+		if _, ok := idx.registry.ParserForFile(path); !ok {
+			return
+		}
+		if err := idx.IndexFile(ctx, path); err != nil {
+			idx.mu.Lock()
+			idx.errors = append(idx.errors, fmt.Sprintf("%s: %v", path, err))
+			idx.mu.Unlock()
+			// Continue indexing other files.
+		}
+
+		fileCount++
+		if idx.verbose && fileCount%100 == 0 {
+			idx.log("  Progress: %d files indexed...", fileCount)
+		}
+		if idx.progress != nil && time.Since(lastReport) >= idx.progressEvery {
+			lastReport = time.Now()
+			report(false)
+		}
+	})
+	if idx.progress != nil {
+		report(true)
+	}
+
+	if idx.verbose {
+		elapsed := time.Since(dirStart)
+		newFiles := idx.filesIndexed - startFiles
+		idx.log("  Directory complete: %s (%d files indexed in %s)", dirPath, newFiles, elapsed)
+	}
+
+	return err
+}
+
+// walkFiles calls fn for every file under dirPath that the ignore rules and
+// file filter don't exclude, skipping excluded directories entirely.
+func (idx *Indexer) walkFiles(ctx context.Context, dirPath string, logSkips bool, fn func(path string)) error {
+	return filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil // skip inaccessible entries
 		}
@@ -217,8 +454,8 @@ func (idx *Indexer) IndexDirectory(ctx context.Context, dirPath string) error {
 
 		// Skip ignored directories.
 		if info.IsDir() {
-			if idx.matcher.Match(path) {
-				if idx.verbose {
+			if idx.matcher.Match(path) || (path != dirPath && idx.filter.skipDir(info.Name())) {
+				if logSkips {
 					idx.log("  Skipping directory: %s (excluded)", path)
 				}
 				return filepath.SkipDir
@@ -231,28 +468,9 @@ func (idx *Indexer) IndexDirectory(ctx context.Context, dirPath string) error {
 			return nil
 		}
 
-		if err := idx.IndexFile(ctx, path); err != nil {
-			idx.mu.Lock()
-			idx.errors = append(idx.errors, fmt.Sprintf("%s: %v", path, err))
-			idx.mu.Unlock()
-			// Continue indexing other files.
-		}
-
-		fileCount++
-		if idx.verbose && fileCount%100 == 0 {
-			idx.log("  Progress: %d files indexed...", fileCount)
-		}
-
+		fn(path)
 		return nil
 	})
-
-	if idx.verbose {
-		elapsed := time.Since(dirStart)
-		newFiles := idx.filesIndexed - startFiles
-		idx.log("  Directory complete: %s (%d files indexed in %s)", dirPath, newFiles, elapsed)
-	}
-
-	return err
 }
 
 // Start performs an initial full index of all configured paths, then starts
@@ -302,7 +520,9 @@ func (idx *Indexer) Start(ctx context.Context) error {
 		return fmt.Errorf("start watcher: %w", err)
 	}
 
-	// Process events until context is cancelled.
+	// Process events until context is cancelled. The change hook runs once
+	// no event has arrived for changeDelay, not after every file.
+	var settled <-chan time.Time
 	for {
 		select {
 		case <-ctx.Done():
@@ -312,6 +532,14 @@ func (idx *Indexer) Start(ctx context.Context) error {
 				return nil
 			}
 			idx.handleEvent(ctx, evt)
+			if idx.changeHook != nil {
+				settled = time.After(idx.changeDelay)
+			}
+		case <-settled:
+			settled = nil
+			if err := idx.changeHook(ctx); err != nil {
+				idx.log("Warning: change hook failed: %v", err)
+			}
 		}
 	}
 }
@@ -326,7 +554,7 @@ func (idx *Indexer) handleEvent(ctx context.Context, evt watcher.Event) {
 		}
 	case watcher.Remove, watcher.Rename:
 		relPath := idx.toRelativePath(evt.Path)
-		if err := idx.store.DeleteByFile(ctx, relPath); err != nil {
+		if err := idx.RemoveFile(ctx, relPath); err != nil {
 			idx.mu.Lock()
 			idx.errors = append(idx.errors, fmt.Sprintf("delete %s: %v", relPath, err))
 			idx.mu.Unlock()
@@ -339,6 +567,8 @@ func (idx *Indexer) Stats() IndexStats {
 	idx.mu.Lock()
 	stats := IndexStats{
 		FilesIndexed:  idx.filesIndexed,
+		FilesSkipped:  idx.filesSkipped,
+		FilesPartial:  idx.filesPartial,
 		LastIndexTime: idx.lastIndex,
 		Errors:        make([]string, len(idx.errors)),
 	}
@@ -439,3 +669,90 @@ func (idx *Indexer) runSummarization(ctx context.Context, changedFiles []string)
 		idx.log("Summarization complete.")
 	}
 }
+
+// addSecretFindings adds a Finding node per hard-coded credential in content,
+// attached to the file node with a Contains edge. Only the redacted value is
+// stored.
+func addSecretFindings(result *parser.ParseResult, content []byte) {
+	file := fileNode(result)
+	if file == nil {
+		return
+	}
+	fileID := file.ID
+
+	for _, m := range secrets.Scan(content) {
+		finding := &graph.Node{
+			ID:       graph.NewNodeID(string(graph.NodeFinding), result.FilePath, fmt.Sprintf("%s:%d:%d", m.Rule.ID, m.Line, m.Column)),
+			Type:     graph.NodeFinding,
+			Name:     m.Rule.ID,
+			FilePath: result.FilePath,
+			Line:     m.Line,
+			Language: string(result.Language),
+			Properties: map[string]string{
+				"category": "secret",
+				"rule":     m.Rule.ID,
+				"severity": m.Rule.Severity,
+				"message":  m.Rule.Description,
+				"redacted": m.Redacted,
+				"column":   fmt.Sprintf("%d", m.Column),
+			},
+		}
+		result.Nodes = append(result.Nodes, finding)
+		result.Edges = append(result.Edges, &graph.Edge{
+			ID:       graph.NewEdgeID(graph.EdgeContains, fileID, finding.ID),
+			Type:     graph.EdgeContains,
+			SourceID: fileID,
+			TargetID: finding.ID,
+		})
+	}
+}
+
+// addParseFindings records the parser's diagnostics as Finding nodes
+// (category=parse_error) under the file node, and their count on the file
+// node as Properties["parse_errors"], so users can see what the graph is
+// missing.
+func addParseFindings(result *parser.ParseResult) {
+	file := fileNode(result)
+	if file == nil {
+		return
+	}
+	if file.Properties == nil {
+		file.Properties = make(map[string]string)
+	}
+	file.Properties["parse_errors"] = strconv.Itoa(len(result.Diagnostics))
+
+	for _, d := range result.Diagnostics {
+		finding := &graph.Node{
+			ID:       graph.NewNodeID(string(graph.NodeFinding), result.FilePath, fmt.Sprintf("syntax-error:%d:%d", d.Line, d.Column)),
+			Type:     graph.NodeFinding,
+			Name:     "syntax-error",
+			FilePath: result.FilePath,
+			Line:     d.Line,
+			Language: string(result.Language),
+			Properties: map[string]string{
+				"category": "parse_error",
+				"rule":     "syntax-error",
+				"severity": "warning",
+				"message":  d.Message,
+				"column":   strconv.Itoa(d.Column),
+			},
+		}
+		result.Nodes = append(result.Nodes, finding)
+		result.Edges = append(result.Edges, &graph.Edge{
+			ID:       graph.NewEdgeID(graph.EdgeContains, file.ID, finding.ID),
+			Type:     graph.EdgeContains,
+			SourceID: file.ID,
+			TargetID: finding.ID,
+		})
+	}
+}
+
+// fileNode returns the parse result's file-level node, or nil.
+func fileNode(result *parser.ParseResult) *graph.Node {
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeFile || n.Type == graph.NodeTestFile || n.Type == graph.NodeDocument {
+			return n
+		}
+	}
+	return nil
+}
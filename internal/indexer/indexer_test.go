@@ -2,9 +2,11 @@ package indexer
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
 	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
@@ -129,6 +131,68 @@ func TestIndexFileUnsupportedExtension(t *testing.T) {
 	}
 }
 
+func TestIndexFileScanSecrets(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "testdb")
+	store, err := embedded.NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	registry := parser.NewRegistry()
+	registry.Register(golang.NewParser())
+	tmpDir := t.TempDir()
+	idx := NewIndexer(IndexerConfig{
+		GraphStore:     store,
+		ParserRegistry: registry,
+		RepoRoots:      []string{tmpDir},
+		ScanSecrets:    true,
+		SecretsExclude: []string{"**/fixtures/**"},
+	})
+	ctx := context.Background()
+
+	// The key is split so this test file itself doesn't look like a secret.
+	content := "package main\n\nconst awsKey = \"" + "AKIA" + "IOSFODNN7QWERTY1" + "\"\n"
+	for _, rel := range []string{"main.go", "fixtures/keys.go"} {
+		path := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := idx.IndexFile(ctx, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	findings, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFinding})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding (fixtures excluded), got %d", len(findings))
+	}
+	f := findings[0]
+	if f.FilePath != "main.go" || f.Line != 3 || f.Properties["rule"] != "aws-access-key-id" {
+		t.Errorf("finding = %s:%d %s", f.FilePath, f.Line, f.Properties["rule"])
+	}
+	if f.Properties["redacted"] != "AKIA********" {
+		t.Errorf("redacted = %q", f.Properties["redacted"])
+	}
+
+	edges, err := store.GetEdges(ctx, f.ID, graph.EdgeContains)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 {
+		t.Fatalf("expected 1 Contains edge to the finding, got %d", len(edges))
+	}
+	if file, err := store.GetNode(ctx, edges[0].SourceID); err != nil || file.Type != graph.NodeFile {
+		t.Errorf("finding not attached to a File node: %v", err)
+	}
+}
+
 func TestIndexDirectory(t *testing.T) {
 	idx, store := setupTestIndexer(t)
 	ctx := context.Background()
@@ -497,3 +561,81 @@ func TestChangedFilesUnsupportedExtensionNotTracked(t *testing.T) {
 		t.Error("expected HasChanges=false after indexing unsupported file type")
 	}
 }
+
+func TestIndexFileRepoName(t *testing.T) {
+	store, err := embedded.NewStore(filepath.Join(t.TempDir(), "testdb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	registry := parser.NewRegistry()
+	registry.Register(golang.NewParser())
+	tmpDir := t.TempDir()
+	idx := NewIndexer(IndexerConfig{
+		GraphStore:     store,
+		ParserRegistry: registry,
+		RepoRoots:      []string{tmpDir},
+		RepoName:       "orders",
+		NodeProperties: map[string]string{"commit": "abc123"},
+	})
+	ctx := context.Background()
+
+	path := filepath.Join(tmpDir, "cmd", "main.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.IndexFile(ctx, path); err != nil {
+		t.Fatal(err)
+	}
+
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{FilePath: filepath.Join("orders", "cmd", "main.go")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) == 0 {
+		t.Fatal("expected nodes under the repo-prefixed path")
+	}
+	for _, n := range nodes {
+		if n.Properties["repo"] != "orders" || n.Properties["commit"] != "abc123" {
+			t.Errorf("%s %s: repo = %q, commit = %q", n.Type, n.Name, n.Properties["repo"], n.Properties["commit"])
+		}
+	}
+}
+
+func TestIndexDirectoryProgress(t *testing.T) {
+	idx, _ := setupTestIndexer(t)
+	var events []Progress
+	idx.progress = func(p Progress) { events = append(events, p) }
+	idx.progressEvery = time.Nanosecond
+
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		src := fmt.Sprintf("package p\n\nfunc F%d() {}\n", i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", i)), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.bin"), []byte{0, 1}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := idx.IndexDirectory(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) < 2 {
+		t.Fatalf("got %d progress events, want periodic events plus a final one", len(events))
+	}
+	last := events[len(events)-1]
+	if !last.Done || last.Files != 3 || last.Total != 3 || last.Nodes == 0 {
+		t.Errorf("final progress = %+v, want done with 3/3 files and nodes", last)
+	}
+	for _, p := range events[:len(events)-1] {
+		if p.Done || p.Total != 3 {
+			t.Errorf("intermediate progress = %+v", p)
+		}
+	}
+}
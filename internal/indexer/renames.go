@@ -0,0 +1,299 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Rename detection: when a file is re-indexed or removed, its symbols that
+// are gone are retired into a short-lived pool. Symbols that show up with a
+// new ID are matched against the pool by type, name, signature, doc comment,
+// and location, and the best match above renameThreshold is recorded as a
+// RenamedFrom edge from the new symbol to the old ID.
+const (
+	renameThreshold = 0.6
+	renamePoolSize  = 10000
+	renameWindow    = 5 * time.Minute
+)
+
+// retiredSymbol is a symbol removed from the graph that a later symbol may
+// have been renamed or moved from.
+type retiredSymbol struct {
+	node *graph.Node
+	at   time.Time
+}
+
+// renameTracked reports whether nodes of type t take part in rename detection.
+func renameTracked(t graph.NodeType) bool {
+	switch t {
+	case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction,
+		graph.NodeClass, graph.NodeStruct, graph.NodeInterface, graph.NodeEnum, graph.NodeType_:
+		return true
+	}
+	return false
+}
+
+// symbolSnapshot is the part of a symbol rename detection compares.
+func symbolSnapshot(n *graph.Node) *graph.Node {
+	return &graph.Node{
+		ID:            n.ID,
+		Type:          n.Type,
+		Name:          n.Name,
+		QualifiedName: n.QualifiedName,
+		FilePath:      n.FilePath,
+		Line:          n.Line,
+		EndLine:       n.EndLine,
+		Signature:     n.Signature,
+		DocComment:    n.DocComment,
+	}
+}
+
+// fileSymbols is a file's tracked symbols before re-indexing, with the
+// RenamedFrom edges they carry, which DeleteByFile would otherwise drop.
+type fileSymbols struct {
+	nodes   map[string]*graph.Node
+	renames map[string][]*graph.Edge
+}
+
+// snapshotFile records the tracked symbols stored for relPath.
+func (idx *Indexer) snapshotFile(ctx context.Context, relPath string) (*fileSymbols, error) {
+	nodes, err := idx.store.QueryNodes(ctx, graph.NodeFilter{FilePath: relPath})
+	if err != nil {
+		return nil, fmt.Errorf("query old symbols for %s: %w", relPath, err)
+	}
+	snap := &fileSymbols{nodes: make(map[string]*graph.Node), renames: make(map[string][]*graph.Edge)}
+	for _, n := range nodes {
+		if !renameTracked(n.Type) {
+			continue
+		}
+		snap.nodes[n.ID] = symbolSnapshot(n)
+		if n.Properties["renamed_from"] == "" {
+			continue
+		}
+		edges, err := idx.store.GetEdges(ctx, n.ID, graph.EdgeRenamedFrom)
+		if err != nil {
+			return nil, fmt.Errorf("get rename edges for %s: %w", n.ID, err)
+		}
+		for _, e := range edges {
+			if e.SourceID == n.ID {
+				snap.renames[n.ID] = append(snap.renames[n.ID], e)
+			}
+		}
+		snap.nodes[n.ID].Properties = map[string]string{"renamed_from": n.Properties["renamed_from"]}
+	}
+	return snap, nil
+}
+
+// RemoveFile deletes a file's nodes from the graph. With rename tracking on,
+// its symbols are kept as rename candidates for files indexed shortly after,
+// so a moved symbol is linked to its old ID.
+func (idx *Indexer) RemoveFile(ctx context.Context, relPath string) error {
+	if idx.trackRenames {
+		snap, err := idx.snapshotFile(ctx, relPath)
+		if err != nil {
+			return err
+		}
+		retired := make([]*graph.Node, 0, len(snap.nodes))
+		for _, n := range snap.nodes {
+			retired = append(retired, n)
+		}
+		idx.retire(retired)
+	}
+	return idx.store.DeleteByFile(ctx, relPath)
+}
+
+// retire adds symbols to the rename pool, dropping expired entries and the
+// oldest ones beyond renamePoolSize.
+func (idx *Indexer) retire(nodes []*graph.Node) {
+	if len(nodes) == 0 {
+		return
+	}
+	now := time.Now()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, n := range nodes {
+		idx.retired = append(idx.retired, retiredSymbol{node: n, at: now})
+	}
+	drop := 0
+	for drop < len(idx.retired) && now.Sub(idx.retired[drop].at) > renameWindow {
+		drop++
+	}
+	if over := len(idx.retired) - drop - renamePoolSize; over > 0 {
+		drop += over
+	}
+	if drop > 0 {
+		idx.retired = append(idx.retired[:0], idx.retired[drop:]...)
+	}
+}
+
+// claimRename removes and returns the retired symbol node most likely
+// renamed or moved to, with its score and the reason for the match.
+func (idx *Indexer) claimRename(node *graph.Node) (*graph.Node, float64, string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	best, bestScore, bestReason := -1, 0.0, ""
+	for i, r := range idx.retired {
+		if time.Since(r.at) > renameWindow {
+			continue
+		}
+		if score, reason := renameScore(r.node, node); score > bestScore {
+			best, bestScore, bestReason = i, score, reason
+		}
+	}
+	if best < 0 || bestScore < renameThreshold {
+		return nil, 0, ""
+	}
+	old := idx.retired[best].node
+	idx.retired = append(idx.retired[:best], idx.retired[best+1:]...)
+	return old, bestScore, bestReason
+}
+
+// resetRenames empties the rename pool, e.g. once a sync has finished.
+func (idx *Indexer) resetRenames() {
+	idx.mu.Lock()
+	idx.retired = nil
+	idx.mu.Unlock()
+}
+
+// renameScore rates how likely cur is old renamed or moved, in [0, 1], and
+// names the evidence: "moved" (same name elsewhere), "signature", "doc", or
+// "name" (similar name at the same place).
+func renameScore(old, cur *graph.Node) (float64, string) {
+	if old.Type != cur.Type || old.ID == cur.ID {
+		return 0, ""
+	}
+	sameFile := old.FilePath == cur.FilePath
+	sigMatch := old.Signature != "" && renameShape(old.Signature, old.Name) == renameShape(cur.Signature, cur.Name)
+	if old.Name == cur.Name {
+		if sigMatch {
+			return 0.9, "moved"
+		}
+		return 0.7, "moved"
+	}
+
+	similar := nameSimilarity(old.Name, cur.Name) >= 0.6
+	sameSpan := sameFile && old.EndLine-old.Line == cur.EndLine-cur.Line
+	switch {
+	case sigMatch && similar:
+		return 0.9, "signature"
+	case len(old.DocComment) >= 20 && renameShape(old.DocComment, old.Name) == renameShape(cur.DocComment, cur.Name):
+		return 0.8, "doc"
+	case sigMatch && sameSpan:
+		return 0.7, "signature"
+	case similar && sameSpan:
+		return 0.6, "name"
+	}
+	return 0, ""
+}
+
+// renameShape blanks out a symbol's own name so text naming it compares
+// equal across a rename.
+func renameShape(s, name string) string {
+	if name == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, name, "\x00")
+}
+
+// nameSimilarity is 1 minus the case-insensitive edit distance between a and
+// b over the longer length; a name containing the other scores at least 0.6.
+func nameSimilarity(a, b string) float64 {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	sim := 1 - float64(levenshtein(ra, rb))/float64(longest)
+	if min(len(ra), len(rb)) >= 3 && (strings.Contains(a, b) || strings.Contains(b, a)) {
+		sim = max(sim, 0.6)
+	}
+	return sim
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// renameEdge links cur to the retired symbol old.
+func renameEdge(cur, old *graph.Node, score float64, reason string) *graph.Edge {
+	return &graph.Edge{
+		ID:       graph.NewEdgeID(graph.EdgeRenamedFrom, cur.ID, old.ID),
+		Type:     graph.EdgeRenamedFrom,
+		SourceID: cur.ID,
+		TargetID: old.ID,
+		Properties: map[string]string{
+			"old_name":                old.Name,
+			"old_qualified_name":      old.QualifiedName,
+			"old_file":                old.FilePath,
+			"reason":                  reason,
+			graph.PropConfidence:      graph.ConfidenceHeuristic,
+			graph.PropConfidenceScore: fmt.Sprintf("%.2f", score),
+		},
+	}
+}
+
+// finishRenames runs after a file's new nodes are written: it restores the
+// rename edges of symbols that kept their ID, retires the symbols that are
+// gone, and links symbols with new IDs to what they were renamed from.
+func (s *fileSink) finishRenames(ctx context.Context) error {
+	if s.old == nil {
+		return nil
+	}
+	var gone []*graph.Node
+	for id, n := range s.old.nodes {
+		if _, ok := s.seen[id]; ok {
+			for _, e := range s.old.renames[id] {
+				if err := s.idx.store.AddEdge(ctx, e); err != nil {
+					return fmt.Errorf("restore rename edge %s: %w", e.ID, err)
+				}
+			}
+			continue
+		}
+		gone = append(gone, n)
+	}
+	s.idx.retire(gone)
+
+	for _, cur := range s.added {
+		old, score, reason := s.idx.claimRename(cur)
+		if old == nil {
+			continue
+		}
+		if err := s.idx.store.AddEdge(ctx, renameEdge(cur, old, score, reason)); err != nil {
+			return fmt.Errorf("add rename edge for %s: %w", cur.ID, err)
+		}
+		node, err := s.idx.store.GetNode(ctx, cur.ID)
+		if err != nil {
+			return fmt.Errorf("get renamed node %s: %w", cur.ID, err)
+		}
+		if node.Properties == nil {
+			node.Properties = make(map[string]string)
+		}
+		node.Properties["renamed_from"] = old.ID
+		if err := s.idx.store.UpdateNode(ctx, node); err != nil {
+			return fmt.Errorf("update renamed node %s: %w", cur.ID, err)
+		}
+		s.edges++
+	}
+	return nil
+}
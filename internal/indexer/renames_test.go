@@ -0,0 +1,188 @@
+package indexer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+const renameBefore = `package shop
+
+// Total sums the prices of the items in the cart.
+func Total(prices []float64) float64 {
+	var sum float64
+	for _, p := range prices {
+		sum += p
+	}
+	return sum
+}
+
+func Helper() int { return 1 }
+`
+
+const renameAfter = `package shop
+
+// CartTotal sums the prices of the items in the cart.
+func CartTotal(prices []float64) float64 {
+	var sum float64
+	for _, p := range prices {
+		sum += p
+	}
+	return sum
+}
+
+func Helper() int { return 1 }
+`
+
+func renameEdges(t *testing.T, store graph.Store, file, name string) []*graph.Edge {
+	t.Helper()
+	nodes, err := store.QueryNodes(context.Background(), graph.NodeFilter{Type: graph.NodeFunction, FilePath: file, NamePattern: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 {
+		t.Fatalf("found %d %s functions in %s, want 1", len(nodes), name, file)
+	}
+	edges, err := store.GetEdges(context.Background(), nodes[0].ID, graph.EdgeRenamedFrom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) > 0 && nodes[0].Properties["renamed_from"] != edges[0].TargetID {
+		t.Errorf("renamed_from = %q, want %q", nodes[0].Properties["renamed_from"], edges[0].TargetID)
+	}
+	return edges
+}
+
+func writeGo(t *testing.T, path, src string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenameInSameFile(t *testing.T) {
+	ctx := context.Background()
+	idx, store := setupTestIndexer(t)
+	idx.trackRenames = true
+	dir := t.TempDir()
+	idx.repoRoots = []string{dir}
+	file := filepath.Join(dir, "cart.go")
+
+	writeGo(t, file, renameBefore)
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatal(err)
+	}
+	oldID := graph.NewNodeID(string(graph.NodeFunction), "cart.go", "Total")
+
+	writeGo(t, file, renameAfter)
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatal(err)
+	}
+	edges := renameEdges(t, store, "cart.go", "CartTotal")
+	if len(edges) != 1 {
+		t.Fatalf("got %d RenamedFrom edges, want 1", len(edges))
+	}
+	e := edges[0]
+	if e.TargetID != oldID || e.Properties["old_name"] != "Total" {
+		t.Errorf("edge = %+v, want target %s named Total", e, oldID)
+	}
+	if e.Properties[graph.PropConfidence] != graph.ConfidenceHeuristic {
+		t.Errorf("confidence = %q, want heuristic", e.Properties[graph.PropConfidence])
+	}
+	if got := renameEdges(t, store, "cart.go", "Helper"); len(got) != 0 {
+		t.Errorf("unchanged Helper got %d RenamedFrom edges", len(got))
+	}
+
+	// Re-indexing unchanged content keeps the edge.
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatal(err)
+	}
+	if got := renameEdges(t, store, "cart.go", "CartTotal"); len(got) != 1 {
+		t.Errorf("after re-index got %d RenamedFrom edges, want 1", len(got))
+	}
+}
+
+func TestRenameMovedFile(t *testing.T) {
+	ctx := context.Background()
+	idx, store := setupTestIndexer(t)
+	idx.trackRenames = true
+	dir := t.TempDir()
+	idx.repoRoots = []string{dir}
+
+	writeGo(t, filepath.Join(dir, "cart.go"), renameBefore)
+	if err := idx.IndexFile(ctx, filepath.Join(dir, "cart.go")); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.RemoveFile(ctx, "cart.go"); err != nil {
+		t.Fatal(err)
+	}
+	writeGo(t, filepath.Join(dir, "basket.go"), renameBefore)
+	if err := idx.IndexFile(ctx, filepath.Join(dir, "basket.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Total", "Helper"} {
+		edges := renameEdges(t, store, "basket.go", name)
+		if len(edges) != 1 {
+			t.Fatalf("%s: got %d RenamedFrom edges, want 1", name, len(edges))
+		}
+		if got := edges[0].Properties["old_file"]; got != "cart.go" {
+			t.Errorf("%s: old_file = %q, want cart.go", name, got)
+		}
+		if got := edges[0].Properties["reason"]; got != "moved" {
+			t.Errorf("%s: reason = %q, want moved", name, got)
+		}
+	}
+}
+
+func TestRenameTrackingOff(t *testing.T) {
+	ctx := context.Background()
+	idx, store := setupTestIndexer(t)
+	dir := t.TempDir()
+	idx.repoRoots = []string{dir}
+	file := filepath.Join(dir, "cart.go")
+
+	writeGo(t, file, renameBefore)
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatal(err)
+	}
+	writeGo(t, file, renameAfter)
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatal(err)
+	}
+	if got := renameEdges(t, store, "cart.go", "CartTotal"); len(got) != 0 {
+		t.Errorf("got %d RenamedFrom edges with tracking off, want 0", len(got))
+	}
+}
+
+func TestRenameScore(t *testing.T) {
+	fn := func(name, file, sig, doc string, line, end int) *graph.Node {
+		return &graph.Node{ID: file + ":" + name, Type: graph.NodeFunction, Name: name, FilePath: file,
+			Signature: sig, DocComment: doc, Line: line, EndLine: end}
+	}
+	tests := []struct {
+		name       string
+		old, cur   *graph.Node
+		wantReason string
+	}{
+		{"moved", fn("Parse", "a.go", "func Parse(s string) error", "", 1, 5), fn("Parse", "b.go", "func Parse(s string) error", "", 9, 13), "moved"},
+		{"similar name and signature", fn("Load", "a.go", "func Load(p string) error", "", 1, 5), fn("LoadAll", "a.go", "func LoadAll(p string) error", "", 1, 5), "signature"},
+		{"doc", fn("Sum", "a.go", "func Sum(x []int) int", "Sum adds up the numbers in x.", 1, 5), fn("Add", "b.go", "func Add(x []int64) int64", "Add adds up the numbers in x.", 1, 5), "doc"},
+		{"unrelated", fn("Open", "a.go", "func Open() error", "", 1, 5), fn("Close", "a.go", "func Close(f int)", "", 8, 9), ""},
+		{"different type", fn("Open", "a.go", "", "", 1, 5), &graph.Node{ID: "x", Type: graph.NodeStruct, Name: "Open", FilePath: "b.go"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason := renameScore(tt.old, tt.cur)
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q (score %.2f), want %q", reason, score, tt.wantReason)
+			}
+			if (reason != "") != (score >= renameThreshold) {
+				t.Errorf("score %.2f inconsistent with reason %q", score, reason)
+			}
+		})
+	}
+}
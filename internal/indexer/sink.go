@@ -0,0 +1,194 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// DefaultBatchSize is how many nodes and edges the indexer buffers before
+// writing them to the store.
+const DefaultBatchSize = 500
+
+// batchWriter buffers nodes and edges and writes them once size are
+// pending, so the memory a file's extraction holds stays bounded. Stores
+// implementing graph.BatchStore get each batch in one call.
+type batchWriter struct {
+	ctx   context.Context
+	store graph.Store
+	size  int
+	nodes []*graph.Node
+	edges []*graph.Edge
+}
+
+func (w *batchWriter) addNode(node *graph.Node) error {
+	w.nodes = append(w.nodes, node)
+	return w.maybeFlush()
+}
+
+func (w *batchWriter) addEdge(edge *graph.Edge) error {
+	w.edges = append(w.edges, edge)
+	return w.maybeFlush()
+}
+
+func (w *batchWriter) maybeFlush() error {
+	if len(w.nodes)+len(w.edges) < w.size {
+		return nil
+	}
+	return w.flush()
+}
+
+// flush writes the pending nodes, then the pending edges.
+func (w *batchWriter) flush() error {
+	if len(w.nodes)+len(w.edges) == 0 {
+		return nil
+	}
+	if bs, ok := w.store.(graph.BatchStore); ok {
+		if err := bs.AddBatch(w.ctx, w.nodes, w.edges); err != nil {
+			return fmt.Errorf("write batch: %w", err)
+		}
+	} else {
+		for _, node := range w.nodes {
+			if err := w.store.AddNode(w.ctx, node); err != nil {
+				return fmt.Errorf("add node %s: %w", node.ID, err)
+			}
+		}
+		for _, edge := range w.edges {
+			if err := w.store.AddEdge(w.ctx, edge); err != nil {
+				return fmt.Errorf("add edge %s: %w", edge.ID, err)
+			}
+		}
+	}
+	clear(w.nodes)
+	clear(w.edges)
+	w.nodes, w.edges = w.nodes[:0], w.edges[:0]
+	return nil
+}
+
+// fileSink receives one file's nodes and edges from the parser, classifies
+// and decorates each node as it arrives, and hands them to a batchWriter.
+// The file's old nodes are deleted just before the first write, so a parser
+// that fails before emitting anything leaves the previous index intact.
+// Of the nodes themselves it keeps only what the whole-file passes run
+// afterwards need: the file node and the line spans of callables.
+type fileSink struct {
+	idx        *Indexer
+	relPath    string
+	generated  bool
+	classifier *parser.Classifier
+	w          *batchWriter
+
+	started bool
+	err     error         // first store error; the parse is abandoned
+	file    *graph.Node   // first File, TestFile, or Document node
+	outline []*graph.Node // file node and callable spans, for the whole-file passes
+	nodes   int
+	edges   int
+
+	// Rename tracking (nil old when off): the file's symbols before the
+	// delete, which of them were emitted again, and symbols with new IDs.
+	old   *fileSymbols
+	seen  map[string]struct{}
+	added []*graph.Node
+}
+
+// start deletes the file's old nodes once, before anything is written.
+func (s *fileSink) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+	if s.idx.trackRenames {
+		old, err := s.idx.snapshotFile(s.w.ctx, s.relPath)
+		if err != nil {
+			return err
+		}
+		s.old, s.seen = old, make(map[string]struct{})
+	}
+	if err := s.idx.store.DeleteByFile(s.w.ctx, s.relPath); err != nil {
+		return fmt.Errorf("delete old nodes for %s: %w", s.relPath, err)
+	}
+	return nil
+}
+
+func (s *fileSink) AddNode(node *graph.Node) error {
+	s.classifier.ClassifyNode(node)
+	// Shared nodes, such as imported packages, belong to no one file.
+	s.idx.decorate(node, s.generated && node.FilePath != "")
+	switch node.Type {
+	case graph.NodeFile, graph.NodeTestFile, graph.NodeDocument:
+		if s.file == nil {
+			s.file = node
+			s.outline = append(s.outline, node)
+		}
+	case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
+		s.outline = append(s.outline, &graph.Node{ID: node.ID, Type: node.Type, Line: node.Line, EndLine: node.EndLine})
+	}
+	if err := s.start(); err != nil {
+		s.err = err
+		return err
+	}
+	if s.old != nil && renameTracked(node.Type) {
+		if prev, ok := s.old.nodes[node.ID]; ok {
+			s.seen[node.ID] = struct{}{}
+			if from := prev.Properties["renamed_from"]; from != "" {
+				if node.Properties == nil {
+					node.Properties = make(map[string]string)
+				}
+				node.Properties["renamed_from"] = from
+			}
+		} else {
+			s.added = append(s.added, symbolSnapshot(node))
+		}
+	}
+	return s.write(node, nil)
+}
+
+func (s *fileSink) AddEdge(edge *graph.Edge) error {
+	return s.write(nil, edge)
+}
+
+// write passes a node or edge to the batch writer, remembering the first
+// failure so the caller can tell store errors from parse errors.
+func (s *fileSink) write(node *graph.Node, edge *graph.Edge) error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.start(); err != nil {
+		s.err = err
+		return err
+	}
+	var err error
+	if node != nil {
+		s.nodes++
+		err = s.w.addNode(node)
+	} else {
+		s.edges++
+		err = s.w.addEdge(edge)
+	}
+	if err != nil {
+		s.err = err
+	}
+	return err
+}
+
+// decorate marks a node generated and sets the configured node properties.
+func (idx *Indexer) decorate(node *graph.Node, generated bool) {
+	if !generated && idx.repoName == "" && len(idx.nodeProps) == 0 {
+		return
+	}
+	if node.Properties == nil {
+		node.Properties = make(map[string]string)
+	}
+	if generated {
+		node.Properties["generated"] = "true"
+	}
+	for k, v := range idx.nodeProps {
+		node.Properties[k] = v
+	}
+	if idx.repoName != "" {
+		node.Properties["repo"] = idx.repoName
+	}
+}
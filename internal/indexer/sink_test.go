@@ -0,0 +1,129 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// streamParser emits a file node and n functions, failing after failAfter
+// nodes when failAfter > 0.
+type streamParser struct {
+	n         int
+	failAfter int
+}
+
+func (p *streamParser) Language() parser.Language { return "stream" }
+func (p *streamParser) Extensions() []string      { return []string{".big"} }
+
+func (p *streamParser) ParseFile(filePath string, content []byte) (*parser.ParseResult, error) {
+	return parser.Collect(p, filePath, content)
+}
+
+func (p *streamParser) StreamFile(filePath string, _ []byte, sink parser.Sink) ([]parser.Diagnostic, error) {
+	fileID := graph.NewNodeID(string(graph.NodeFile), filePath, filePath)
+	if err := sink.AddNode(&graph.Node{ID: fileID, Type: graph.NodeFile, Name: filePath, FilePath: filePath}); err != nil {
+		return nil, err
+	}
+	for i := 0; i < p.n; i++ {
+		if p.failAfter > 0 && i == p.failAfter {
+			return nil, errors.New("truncated input")
+		}
+		name := fmt.Sprintf("f%d", i)
+		id := graph.NewNodeID(string(graph.NodeFunction), filePath, name)
+		if err := sink.AddNode(&graph.Node{ID: id, Type: graph.NodeFunction, Name: name, FilePath: filePath, Line: i + 1, EndLine: i + 1}); err != nil {
+			return nil, err
+		}
+		if err := sink.AddEdge(&graph.Edge{ID: fileID + "->" + id, Type: graph.EdgeContains, SourceID: fileID, TargetID: id}); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// batchRecorder records the size of every batch written through it.
+type batchRecorder struct {
+	graph.Store
+	sizes []int
+}
+
+func (r *batchRecorder) AddBatch(ctx context.Context, nodes []*graph.Node, edges []*graph.Edge) error {
+	r.sizes = append(r.sizes, len(nodes)+len(edges))
+	return r.Store.(graph.BatchStore).AddBatch(ctx, nodes, edges)
+}
+
+func TestIndexFileStreamsInBatches(t *testing.T) {
+	ctx := context.Background()
+	idx, store := setupTestIndexer(t)
+	rec := &batchRecorder{Store: store}
+	idx.store = rec
+	idx.batchSize = 50
+	idx.registry.Register(&streamParser{n: 120})
+
+	dir := t.TempDir()
+	idx.repoRoots = []string{dir}
+	file := filepath.Join(dir, "huge.big")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatalf("IndexFile: %v", err)
+	}
+
+	total := 0
+	for _, n := range rec.sizes {
+		if n > idx.batchSize {
+			t.Errorf("batch of %d exceeds batch size %d", n, idx.batchSize)
+		}
+		total += n
+	}
+	if want := 1 + 120*2; total != want {
+		t.Errorf("wrote %d nodes and edges, want %d", total, want)
+	}
+	fns, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFunction, FilePath: "huge.big"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fns) != 120 {
+		t.Errorf("stored %d functions, want 120", len(fns))
+	}
+	if stats := idx.Stats(); stats.NodesTotal == 0 {
+		t.Errorf("Stats().NodesTotal = 0 after indexing")
+	}
+}
+
+func TestIndexFileStreamFailureRemovesPartialFile(t *testing.T) {
+	ctx := context.Background()
+	idx, store := setupTestIndexer(t)
+	idx.batchSize = 10
+	p := &streamParser{n: 30}
+	idx.registry.Register(p)
+
+	dir := t.TempDir()
+	idx.repoRoots = []string{dir}
+	file := filepath.Join(dir, "huge.big")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.IndexFile(ctx, file); err != nil {
+		t.Fatalf("IndexFile: %v", err)
+	}
+
+	p.failAfter = 20
+	if err := idx.IndexFile(ctx, file); err == nil {
+		t.Fatal("IndexFile succeeded, want the parser's error")
+	}
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{FilePath: "huge.big"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("%d nodes left after a failed stream, want 0", len(nodes))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/imyousuf/CodeEagle/internal/compact"
 	"github.com/imyousuf/CodeEagle/internal/gitutil"
 	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
 )
@@ -28,6 +29,9 @@ func SyncFiles(ctx context.Context, idx *Indexer, paths []string, configDir stri
 	// Migrate legacy flat state to branch-aware on first load.
 	state.MigrateLegacy(branch)
 
+	// Rename candidates don't outlive the sync that retired them.
+	defer idx.resetRenames()
+
 	for _, repoPath := range paths {
 		if isGitRepo(repoPath) {
 			if err := syncGitRepo(ctx, idx, repoPath, state, full, branch); err != nil {
@@ -40,6 +44,16 @@ func SyncFiles(ctx context.Context, idx *Indexer, paths []string, configDir stri
 		}
 	}
 
+	// Package imports share one Dependency node across files, which
+	// re-indexing the files no longer importing it leaves behind.
+	pruned, err := compact.PruneDependencies(ctx, idx.store, false)
+	if err != nil {
+		return fmt.Errorf("prune dependencies: %w", err)
+	}
+	if idx.verbose && pruned > 0 {
+		idx.log("Pruned %d dependencies and external types nothing refers to", pruned)
+	}
+
 	if err := state.Save(statePath); err != nil {
 		return fmt.Errorf("save sync state: %w", err)
 	}
@@ -96,13 +110,15 @@ func syncGitRepo(ctx context.Context, idx *Indexer, repoPath string, state *Sync
 			// Git diff returns relative paths — use them directly since the graph
 			// now stores relative paths.
 			for _, relPath := range deleted {
-				if err := idx.Store().DeleteByFile(ctx, relPath); err != nil {
+				if err := idx.RemoveFile(ctx, relPath); err != nil {
 					idx.log("Warning: delete by file %s: %v", relPath, err)
 				}
 			}
 
-			// Re-index added and modified files.
-			for _, relPath := range append(added, modified...) {
+			// Re-index modified, then added files, so symbols moved out of
+			// deleted or modified files are known when their new home is
+			// indexed.
+			for _, relPath := range append(modified, added...) {
 				absPath := filepath.Join(repoPath, relPath)
 				if err := idx.IndexFile(ctx, absPath); err != nil {
 					idx.log("Warning: index file %s: %v", absPath, err)
@@ -139,8 +155,9 @@ func syncDirectory(ctx context.Context, idx *Indexer, dirPath string, state *Syn
 		state.FileTimes = make(map[string]time.Time)
 	}
 
-	// Track which relative paths still exist.
+	// Track which relative paths still exist, and which changed.
 	existing := make(map[string]struct{})
+	var changed []string
 
 	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -162,9 +179,7 @@ func syncDirectory(ctx context.Context, idx *Indexer, dirPath string, state *Syn
 
 		prevTime, hasPrev := state.FileTimes[relPath]
 		if !hasPrev || modTime.After(prevTime) {
-			if err := idx.IndexFile(ctx, path); err != nil {
-				idx.log("Warning: index file %s: %v", path, err)
-			}
+			changed = append(changed, path)
 			state.FileTimes[relPath] = modTime
 		}
 
@@ -174,16 +189,28 @@ func syncDirectory(ctx context.Context, idx *Indexer, dirPath string, state *Syn
 		return err
 	}
 
-	// Delete nodes for files that no longer exist.
+	// Delete nodes for files that no longer exist, before re-indexing, so
+	// symbols moved out of them can be linked to their new home.
 	for relPath := range state.FileTimes {
 		if _, ok := existing[relPath]; !ok {
-			if err := idx.Store().DeleteByFile(ctx, relPath); err != nil {
+			if err := idx.RemoveFile(ctx, relPath); err != nil {
 				idx.log("Warning: delete by file %s: %v", relPath, err)
 			}
 			delete(state.FileTimes, relPath)
 		}
 	}
 
+	for _, path := range changed {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if err := idx.IndexFile(ctx, path); err != nil {
+			idx.log("Warning: index file %s: %v", path, err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,191 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/imyousuf/CodeEagle/internal/gitutil"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Options configures an ingestion.
+type Options struct {
+	// Since limits the history to commits after a date git understands
+	// ("2024-01-01", "6.months.ago"); empty means all history.
+	Since string
+}
+
+// Result summarizes an ingestion run.
+type Result struct {
+	// Commits is the number of commits in the history window.
+	Commits int `json:"commits"`
+	// Referencing is the number of those commits naming at least one issue.
+	Referencing int `json:"referencing"`
+	// Issues is the number of distinct issues referenced.
+	Issues int `json:"issues"`
+	// Files and Functions count the References edges created.
+	Files     int `json:"files"`
+	Functions int `json:"functions"`
+}
+
+// Ingest reads the commit messages of the repository at repoPath and links
+// the code changed by commits naming an issue to its Issue node, with
+// References edges (source=commit) carrying the number of such commits:
+//
+//   - files, from every referencing commit that changed them
+//   - functions and methods, from the referencing commits that last changed
+//     at least one of their current lines, as reported by git blame
+//
+// Commit edges from earlier ingestions of the repository are replaced;
+// comment edges and the code of other repositories are left alone.
+func Ingest(ctx context.Context, store graph.Store, repoPath string, opts Options) (*Result, error) {
+	commits, err := gitutil.GetChurnLog(repoPath, opts.Since)
+	if err != nil {
+		return nil, err
+	}
+	if err := deleteCommitEdges(ctx, store, repoPath); err != nil {
+		return nil, err
+	}
+
+	res := &Result{Commits: len(commits)}
+	commitRefs := make(map[string][]string)     // commit -> issue keys
+	fileRefs := make(map[string]map[string]int) // path -> issue key -> commits
+	for _, c := range commits {
+		refs := Refs(c.Message)
+		if len(refs) == 0 {
+			continue
+		}
+		res.Referencing++
+		commitRefs[c.Hash] = refs
+		for _, f := range c.Files {
+			if fileRefs[f.Path] == nil {
+				fileRefs[f.Path] = make(map[string]int)
+			}
+			for _, key := range refs {
+				fileRefs[f.Path][key]++
+			}
+		}
+	}
+
+	issues := make(map[string]bool)
+	link := func(n *graph.Node, counts map[string]int) error {
+		for key, count := range counts {
+			if !issues[key] {
+				issues[key] = true
+				if _, err := store.GetNode(ctx, NodeID(key)); err != nil {
+					if err := store.AddNode(ctx, NewNode(key)); err != nil {
+						return fmt.Errorf("add issue %s: %w", key, err)
+					}
+				}
+			}
+			edge := &graph.Edge{
+				ID:       EdgeID(n.ID, key, SourceCommit),
+				Type:     graph.EdgeReferences,
+				SourceID: n.ID,
+				TargetID: NodeID(key),
+				Properties: map[string]string{
+					"source":  SourceCommit,
+					"commits": strconv.Itoa(count),
+				},
+			}
+			if err := store.AddEdge(ctx, edge); err != nil {
+				return fmt.Errorf("link %s to %s: %w", n.Name, key, err)
+			}
+		}
+		return nil
+	}
+
+	for _, typ := range []graph.NodeType{graph.NodeFile, graph.NodeTestFile} {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+		}
+		for _, n := range nodes {
+			if counts := fileRefs[n.FilePath]; len(counts) > 0 {
+				if err := link(n, counts); err != nil {
+					return nil, err
+				}
+				res.Files += len(counts)
+			}
+		}
+	}
+
+	byFile := make(map[string][]*graph.Node)
+	for _, typ := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: typ})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", typ, err)
+		}
+		for _, n := range nodes {
+			if fileRefs[n.FilePath] != nil && n.Line > 0 && n.EndLine >= n.Line {
+				byFile[n.FilePath] = append(byFile[n.FilePath], n)
+			}
+		}
+	}
+	paths := make([]string, 0, len(byFile))
+	for p := range byFile {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// Files deleted or ignored since the last index fail to blame.
+		blame, _ := gitutil.BlameFile(repoPath, p)
+		for _, n := range byFile[p] {
+			seen := make(map[string]bool)
+			counts := make(map[string]int)
+			for _, line := range blame[min(n.Line-1, len(blame)):min(n.EndLine, len(blame))] {
+				if seen[line.Commit] {
+					continue
+				}
+				seen[line.Commit] = true
+				for _, key := range commitRefs[line.Commit] {
+					counts[key]++
+				}
+			}
+			if err := link(n, counts); err != nil {
+				return nil, err
+			}
+			res.Functions += len(counts)
+		}
+	}
+
+	res.Issues = len(issues)
+	return res, nil
+}
+
+// deleteCommitEdges removes the References edges of earlier commit
+// ingestions from code in files under repoPath.
+func deleteCommitEdges(ctx context.Context, store graph.Store, repoPath string) error {
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeIssue})
+	if err != nil {
+		return fmt.Errorf("query issues: %w", err)
+	}
+	for _, n := range nodes {
+		edges, err := store.GetIncomingEdges(ctx, n.ID, graph.EdgeReferences)
+		if err != nil {
+			return fmt.Errorf("get references to %s: %w", n.Name, err)
+		}
+		for _, e := range edges {
+			if e.Properties["source"] != SourceCommit {
+				continue
+			}
+			src, err := store.GetNode(ctx, e.SourceID)
+			if err == nil {
+				if _, err := os.Stat(filepath.Join(repoPath, src.FilePath)); err != nil {
+					continue
+				}
+			}
+			if err := store.DeleteEdge(ctx, e.ID); err != nil {
+				return fmt.Errorf("delete reference %s: %w", e.ID, err)
+			}
+		}
+	}
+	return nil
+}
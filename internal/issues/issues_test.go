@@ -0,0 +1,125 @@
+package issues
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestRefs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"PAY-12: fix refunds (#34)", []string{"PAY-12", "#34"}},
+		{"Closes acme/billing#7 and https://github.com/acme/web/issues/9", []string{"acme/billing#7", "acme/web#9"}},
+		{"see https://acme.atlassian.net/browse/OPS-4 and OPS-4 again", []string{"OPS-4"}},
+		{"https://github.com/acme/web/pull/15", []string{"acme/web#15"}},
+		{"UTF-8, SHA-256, ISO-8601, CVE-2024-1234", nil},
+		{"item#3, &#39; and pr-12", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := Refs(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Refs(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+
+	if got := Normalize(" https://github.com/acme/web/issues/9 "); got != "acme/web#9" {
+		t.Errorf("Normalize(url) = %q", got)
+	}
+	if got := Normalize("PAY-1 PAY-2"); got != "" {
+		t.Errorf("Normalize(two refs) = %q, want empty", got)
+	}
+	if n := NewNode("acme/web#9"); n.Properties["tracker"] != TrackerGitHub || n.Properties["url"] != "https://github.com/acme/web/issues/9" {
+		t.Errorf("NewNode properties = %v", n.Properties)
+	}
+}
+
+func TestIngest(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	commit := func(msg, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", msg}} {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+			cmd.Dir = repo
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+	}
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	commit("Add refunds", "pay.go", "package pay\n\nfunc Refund() {\n}\n\nfunc Charge() {\n}\n")
+	commit("PAY-7: retry charges", "pay.go", "package pay\n\nfunc Refund() {\n}\n\nfunc Charge() {\n\tretry()\n}\n")
+	commit("Tidy\n\nPart of PAY-7, see #3", "other.go", "package pay\n")
+
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+	for _, n := range []*graph.Node{
+		{ID: "file-pay", Type: graph.NodeFile, Name: "pay.go", FilePath: "pay.go"},
+		{ID: "file-other", Type: graph.NodeFile, Name: "other.go", FilePath: "other.go"},
+		{ID: "refund", Type: graph.NodeFunction, Name: "Refund", FilePath: "pay.go", Line: 3, EndLine: 4},
+		{ID: "charge", Type: graph.NodeFunction, Name: "Charge", FilePath: "pay.go", Line: 6, EndLine: 8},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	// A comment reference to the same issue keeps its own edge.
+	if err := store.AddNode(ctx, NewNode("PAY-7")); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	comment := &graph.Edge{ID: EdgeID("charge", "PAY-7", SourceComment), Type: graph.EdgeReferences,
+		SourceID: "charge", TargetID: NodeID("PAY-7"), Properties: map[string]string{"source": SourceComment}}
+	if err := store.AddEdge(ctx, comment); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+
+	for i := 0; i < 2; i++ { // re-ingesting replaces the commit edges
+		res, err := Ingest(ctx, store, repo, Options{})
+		if err != nil {
+			t.Fatalf("Ingest: %v", err)
+		}
+		if want := (Result{Commits: 3, Referencing: 2, Issues: 2, Files: 3, Functions: 1}); *res != want {
+			t.Errorf("result = %+v, want %+v", *res, want)
+		}
+	}
+
+	refs := func(issue string) map[string]string {
+		t.Helper()
+		edges, err := store.GetIncomingEdges(ctx, NodeID(issue), graph.EdgeReferences)
+		if err != nil {
+			t.Fatalf("GetIncomingEdges: %v", err)
+		}
+		got := make(map[string]string)
+		for _, e := range edges {
+			got[e.SourceID+"/"+e.Properties["source"]] = e.Properties["commits"]
+		}
+		return got
+	}
+	want := map[string]string{"file-pay/commit": "1", "file-other/commit": "1", "charge/commit": "1", "charge/comment": ""}
+	if got := refs("PAY-7"); !reflect.DeepEqual(got, want) {
+		t.Errorf("PAY-7 references = %v, want %v", got, want)
+	}
+	if got := refs("#3"); !reflect.DeepEqual(got, map[string]string{"file-other/commit": "1"}) {
+		t.Errorf("#3 references = %v", got)
+	}
+}
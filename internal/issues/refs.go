@@ -0,0 +1,128 @@
+// Package issues finds issue tracker references (Jira keys, GitHub issue
+// numbers and URLs) in commit messages and comments, and links the code
+// they touch to Issue nodes.
+package issues
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Trackers recorded in the tracker property of Issue nodes.
+const (
+	TrackerJira   = "jira"
+	TrackerGitHub = "github"
+)
+
+// Sources recorded in the source property of References edges.
+const (
+	SourceCommit  = "commit"
+	SourceComment = "comment"
+)
+
+// refRe matches, in order: GitHub issue and pull request URLs, Jira browse
+// URLs, "owner/repo#12" and "#12", and Jira keys.
+var refRe = regexp.MustCompile(`https?://github\.com/(?P<ghrepo>[\w.-]+/[\w.-]+)/(?:issues|pull)/(?P<ghurl>\d+)` +
+	`|https?://[\w.-]+(?:/[\w.-]+)*/browse/(?P<jiraurl>[A-Z][A-Z0-9]+-\d+)` +
+	`|(?P<repo>[\w.-]+/[\w.-]+)?#(?P<num>\d+)\b` +
+	`|\b(?P<key>[A-Z][A-Z0-9]+-\d+)\b`)
+
+// notJiraProjects are uppercase prefixes of common "NAME-123" identifiers
+// that are not Jira keys.
+var notJiraProjects = map[string]bool{
+	"UTF": true, "SHA": true, "ISO": true, "RFC": true, "CVE": true, "GHSA": true,
+	"HTTP": true, "TLS": true, "SSL": true, "AES": true, "RSA": true, "MD": true,
+	"CRC": true, "GPL": true, "LGPL": true, "AGPL": true, "BSD": true, "CC": true,
+	"ES": true, "ECMA": true, "PEP": true, "JSR": true, "IEEE": true, "X": true,
+}
+
+// Refs returns the issue keys referenced in text, in order of first
+// appearance and without duplicates. Keys are normalized: Jira keys as
+// "PROJ-123" and GitHub references as "#12" or "owner/repo#12" (URLs
+// included). Numbers directly after a word character or "&" (HTML
+// entities) are not references.
+func Refs(text string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	names := refRe.SubexpNames()
+	for _, m := range refRe.FindAllStringSubmatchIndex(text, -1) {
+		group := make(map[string]string)
+		for i, name := range names {
+			if name != "" && m[2*i] >= 0 {
+				group[name] = text[m[2*i]:m[2*i+1]]
+			}
+		}
+		var key string
+		switch {
+		case group["ghurl"] != "":
+			key = group["ghrepo"] + "#" + group["ghurl"]
+		case group["jiraurl"] != "":
+			key = group["jiraurl"]
+		case group["num"] != "":
+			if group["repo"] == "" && m[0] > 0 {
+				if c := text[m[0]-1]; c == '&' || c == '_' || isAlnum(c) {
+					continue
+				}
+			}
+			key = group["repo"] + "#" + group["num"]
+		case group["key"] != "":
+			project, _, _ := strings.Cut(group["key"], "-")
+			if notJiraProjects[project] {
+				continue
+			}
+			key = group["key"]
+		}
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// Normalize returns the key of a single issue reference as Refs would
+// record it, or "" when s is not one.
+func Normalize(s string) string {
+	s = strings.TrimSpace(s)
+	if refs := Refs(s); len(refs) == 1 {
+		return refs[0]
+	}
+	return ""
+}
+
+// NodeID returns the ID of the Issue node for a key.
+func NodeID(key string) string {
+	return graph.NewNodeID(string(graph.NodeIssue), "", key)
+}
+
+// EdgeID returns the ID of the References edge from a node to an issue
+// for a source (SourceCommit or SourceComment), so code both changed by a
+// referencing commit and mentioning the issue has one edge of each.
+func EdgeID(sourceID, key, source string) string {
+	return graph.NewEdgeID(graph.EdgeReferences, sourceID, NodeID(key)+":"+source)
+}
+
+// NewNode returns the Issue node for a key. GitHub references to a
+// repository carry the issue URL.
+func NewNode(key string) *graph.Node {
+	props := map[string]string{"tracker": TrackerJira}
+	if repo, num, ok := strings.Cut(key, "#"); ok {
+		props["tracker"] = TrackerGitHub
+		if repo != "" {
+			props["url"] = "https://github.com/" + repo + "/issues/" + num
+		}
+	}
+	return &graph.Node{
+		ID:            NodeID(key),
+		Type:          graph.NodeIssue,
+		Name:          key,
+		QualifiedName: key,
+		Properties:    props,
+	}
+}
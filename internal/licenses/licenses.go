@@ -0,0 +1,281 @@
+// Package licenses resolves the licenses of manifest dependencies from
+// metadata already on disk (Go module cache, package-lock.json, node_modules,
+// Python dist-info) and checks them against an allow/deny policy. It never
+// touches the network.
+package licenses
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Dependency identifies a manifest dependency to resolve.
+type Dependency struct {
+	Name      string
+	Version   string
+	Ecosystem string // "go", "nodejs", or "python" (as set by the manifest parser)
+	Manifest  string // manifest path relative to a repository root
+}
+
+// Resolution is a resolved license. License is an SPDX identifier (or the
+// declared string when it isn't a known one) and empty when unknown. Source
+// says where it came from.
+type Resolution struct {
+	License string `json:"license,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+// Resolver looks up dependency licenses. It caches parsed lock files.
+type Resolver struct {
+	roots     []string
+	modCache  string
+	overrides map[string]string
+	locks     map[string]map[string]string // package-lock.json path -> package -> license
+}
+
+// NewResolver creates a Resolver that reads metadata under the given
+// repository roots. overrides maps dependency names to licenses and takes
+// precedence over anything found on disk.
+func NewResolver(roots []string, overrides map[string]string) *Resolver {
+	return &Resolver{
+		roots:     roots,
+		modCache:  goModCache(),
+		overrides: overrides,
+		locks:     make(map[string]map[string]string),
+	}
+}
+
+// Resolve returns the license of dep.
+func (r *Resolver) Resolve(dep Dependency) Resolution {
+	if lic, ok := r.overrides[dep.Name]; ok {
+		return Resolution{License: Normalize(lic), Source: "override"}
+	}
+	var res Resolution
+	switch dep.Ecosystem {
+	case "go":
+		res = r.resolveGo(dep)
+	case "nodejs":
+		res = r.resolveNode(dep)
+	case "python":
+		res = r.resolvePython(dep)
+	}
+	res.License = Normalize(res.License)
+	return res
+}
+
+// resolveGo detects the license file of the module in the Go module cache
+// (the version pinned in go.mod, which go.sum verifies).
+func (r *Resolver) resolveGo(dep Dependency) Resolution {
+	if r.modCache == "" || dep.Version == "" {
+		return Resolution{}
+	}
+	dir := filepath.Join(r.modCache, escapeModulePath(dep.Name)+"@"+dep.Version)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Resolution{}
+	}
+	for _, e := range entries {
+		upper := strings.ToUpper(e.Name())
+		if e.IsDir() || !(strings.HasPrefix(upper, "LICENSE") || strings.HasPrefix(upper, "LICENCE") || strings.HasPrefix(upper, "COPYING")) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		if lic := Detect(string(content)); lic != "" {
+			return Resolution{License: lic, Source: "module cache"}
+		}
+	}
+	return Resolution{}
+}
+
+// resolveNode reads the license from package-lock.json next to the manifest,
+// falling back to the installed package's package.json.
+func (r *Resolver) resolveNode(dep Dependency) Resolution {
+	for _, root := range r.roots {
+		dir := filepath.Join(root, filepath.Dir(dep.Manifest))
+		if lic := r.lockLicenses(filepath.Join(dir, "package-lock.json"))[dep.Name]; lic != "" {
+			return Resolution{License: lic, Source: "package-lock.json"}
+		}
+		content, err := os.ReadFile(filepath.Join(dir, "node_modules", dep.Name, "package.json"))
+		if err != nil {
+			continue
+		}
+		var pkg struct {
+			License  json.RawMessage `json:"license"`
+			Licenses []struct {
+				Type string `json:"type"`
+			} `json:"licenses"`
+		}
+		if json.Unmarshal(content, &pkg) != nil {
+			continue
+		}
+		if lic := npmLicense(pkg.License); lic != "" {
+			return Resolution{License: lic, Source: "node_modules"}
+		}
+		if len(pkg.Licenses) > 0 {
+			var ids []string
+			for _, l := range pkg.Licenses {
+				ids = append(ids, l.Type)
+			}
+			return Resolution{License: strings.Join(ids, " OR "), Source: "node_modules"}
+		}
+	}
+	return Resolution{}
+}
+
+// lockLicenses returns the package licenses recorded in a lockfileVersion 2+
+// package-lock.json, or nil if it is missing or unreadable.
+func (r *Resolver) lockLicenses(path string) map[string]string {
+	if m, ok := r.locks[path]; ok {
+		return m
+	}
+	var m map[string]string
+	if content, err := os.ReadFile(path); err == nil {
+		var lock struct {
+			Packages map[string]struct {
+				License json.RawMessage `json:"license"`
+			} `json:"packages"`
+		}
+		if json.Unmarshal(content, &lock) == nil {
+			m = make(map[string]string)
+			for key, pkg := range lock.Packages {
+				// Keys are install paths; nested copies ("node_modules/a/node_modules/b")
+				// must not shadow the top-level one.
+				name, ok := strings.CutPrefix(key, "node_modules/")
+				if !ok || strings.Contains(name, "/node_modules/") {
+					continue
+				}
+				if lic := npmLicense(pkg.License); lic != "" {
+					m[name] = lic
+				}
+			}
+		}
+	}
+	r.locks[path] = m
+	return m
+}
+
+// npmLicense decodes a package.json "license" field, which is a string or a
+// legacy {"type": ...} object.
+func npmLicense(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &obj) == nil {
+		return obj.Type
+	}
+	return ""
+}
+
+// resolvePython reads the METADATA of the installed distribution in a
+// virtualenv (.venv or venv) next to the manifest.
+func (r *Resolver) resolvePython(dep Dependency) Resolution {
+	want := normalizePythonName(dep.Name)
+	for _, root := range r.roots {
+		dir := filepath.Join(root, filepath.Dir(dep.Manifest))
+		for _, venv := range []string{".venv", "venv"} {
+			infos, _ := filepath.Glob(filepath.Join(dir, venv, "lib", "python*", "site-packages", "*.dist-info"))
+			for _, info := range infos {
+				name, _, _ := strings.Cut(strings.TrimSuffix(filepath.Base(info), ".dist-info"), "-")
+				if normalizePythonName(name) != want {
+					continue
+				}
+				if lic := pythonMetadataLicense(filepath.Join(info, "METADATA")); lic != "" {
+					return Resolution{License: lic, Source: "dist-info"}
+				}
+			}
+		}
+	}
+	return Resolution{}
+}
+
+// pythonMetadataLicense returns License-Expression, a short License field, or
+// the first license trove classifier from a core metadata file.
+func pythonMetadataLicense(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var license, classifier string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break // end of headers
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "License-Expression":
+			return value
+		case "License":
+			// Some packages paste the whole license text here.
+			if len(value) <= 64 && value != "UNKNOWN" {
+				license = value
+			}
+		case "Classifier":
+			if c, ok := strings.CutPrefix(value, "License :: OSI Approved :: "); ok && classifier == "" {
+				classifier = c
+			}
+		}
+	}
+	if license != "" {
+		return license
+	}
+	return classifier
+}
+
+func normalizePythonName(name string) string {
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToLower(name))
+}
+
+// goModCache returns GOMODCACHE, defaulting to $GOPATH/pkg/mod.
+func goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// escapeModulePath applies the module cache's case encoding: each upper-case
+// letter becomes "!" followed by its lower-case form.
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
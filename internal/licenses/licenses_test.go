@@ -0,0 +1,110 @@
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"mit", "MIT"},
+		{"Apache 2.0", "Apache-2.0"},
+		{"Apache Software License", "Apache-2.0"},
+		{"BSD License", "BSD-3-Clause"},
+		{"(MIT OR apache-2.0)", "MIT OR Apache-2.0"},
+		{"Custom EULA", "Custom EULA"},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct{ name, text, want string }{
+		{"mit", "MIT License\n\nPermission is hereby granted, free of\ncharge, to any person", "MIT"},
+		{"apache", "                                 Apache License\n                           Version 2.0, January 2004", "Apache-2.0"},
+		{"bsd3", "Redistribution and use in source and binary forms ... Neither the name of the copyright holder nor the names of its contributors may be used to endorse or promote products", "BSD-3-Clause"},
+		{"bsd2", "Redistribution and use in source and binary forms, with or without modification", "BSD-2-Clause"},
+		{"lgpl", "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007 ... GNU GENERAL PUBLIC LICENSE", "LGPL-3.0"},
+		{"gpl2", "GNU GENERAL PUBLIC LICENSE\nVersion 2, June 1991", "GPL-2.0"},
+		{"unknown", "All rights reserved.", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.text); got != tt.want {
+			t.Errorf("%s: Detect = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	deny := Policy{Deny: []string{"GPL-3.0", "AGPL-3.0"}}
+	allow := Policy{Allow: []string{"MIT", "Apache-2.0", "BSD-3-Clause"}}
+	tests := []struct {
+		name    string
+		policy  Policy
+		license string
+		want    string
+	}{
+		{"no policy", Policy{}, "GPL-3.0", ""},
+		{"denied", deny, "GPL-3.0-or-later", "denied license GPL-3.0-or-later"},
+		{"not denied", deny, "MIT", ""},
+		{"unknown without allow list", deny, "", ""},
+		{"unknown with allow list", allow, "", "unknown license"},
+		{"not allowed", allow, "MPL-2.0", "license MPL-2.0 not in allow list"},
+		{"or passes if any passes", allow, "GPL-2.0 OR MIT", ""},
+		{"and fails if any fails", allow, "MIT AND Zlib", "license Zlib not in allow list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Check(tt.license); got != tt.want {
+				t.Errorf("Check(%q) = %q, want %q", tt.license, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(modCache, "github.com/!burnt!sushi/toml@v1.3.2/COPYING"), "The MIT License (MIT)\n\nPermission is hereby granted, free of charge, to any person")
+	write(filepath.Join(root, "web/package-lock.json"), `{"lockfileVersion": 3, "packages": {
+		"": {"name": "web"},
+		"node_modules/react": {"version": "18.2.0", "license": "MIT"},
+		"node_modules/left-pad/node_modules/react": {"license": "GPL-3.0"}}}`)
+	write(filepath.Join(root, "web/node_modules/legacy/package.json"), `{"name": "legacy", "license": {"type": "BSD"}}`)
+	write(filepath.Join(root, "ml/.venv/lib/python3.12/site-packages/Flask_Login-0.6.3.dist-info/METADATA"),
+		"Metadata-Version: 2.1\nName: Flask-Login\nLicense: UNKNOWN\nClassifier: License :: OSI Approved :: MIT License\n\nDescription")
+
+	r := NewResolver([]string{root}, map[string]string{"internal-sdk": "proprietary"})
+	tests := []struct {
+		dep  Dependency
+		want Resolution
+	}{
+		{Dependency{Name: "github.com/BurntSushi/toml", Version: "v1.3.2", Ecosystem: "go", Manifest: "go.mod"}, Resolution{"MIT", "module cache"}},
+		{Dependency{Name: "github.com/missing/mod", Version: "v1.0.0", Ecosystem: "go", Manifest: "go.mod"}, Resolution{}},
+		{Dependency{Name: "react", Ecosystem: "nodejs", Manifest: "web/package.json"}, Resolution{"MIT", "package-lock.json"}},
+		{Dependency{Name: "legacy", Ecosystem: "nodejs", Manifest: "web/package.json"}, Resolution{"BSD-3-Clause", "node_modules"}},
+		{Dependency{Name: "flask-login[extra]", Ecosystem: "python", Manifest: "ml/requirements.txt"}, Resolution{"MIT", "dist-info"}},
+		{Dependency{Name: "internal-sdk", Ecosystem: "python", Manifest: "ml/requirements.txt"}, Resolution{"proprietary", "override"}},
+	}
+	for _, tt := range tests {
+		if got := r.Resolve(tt.dep); got != tt.want {
+			t.Errorf("Resolve(%s) = %+v, want %+v", tt.dep.Name, got, tt.want)
+		}
+	}
+}
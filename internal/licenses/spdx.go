@@ -0,0 +1,198 @@
+package licenses
+
+import "strings"
+
+// spdxIDs lists the SPDX identifiers recognized by Normalize, keyed by lower
+// case.
+var spdxIDs = func() map[string]string {
+	ids := []string{
+		"0BSD", "AFL-3.0", "AGPL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later",
+		"Apache-1.1", "Apache-2.0", "Artistic-2.0", "BlueOak-1.0.0",
+		"BSD-2-Clause", "BSD-3-Clause", "BSD-4-Clause", "BSL-1.0",
+		"CC-BY-4.0", "CC-BY-SA-4.0", "CC0-1.0", "CDDL-1.0", "CDDL-1.1",
+		"EPL-1.0", "EPL-2.0", "EUPL-1.2",
+		"GPL-2.0", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0", "GPL-3.0-only", "GPL-3.0-or-later",
+		"ISC", "LGPL-2.1", "LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0", "LGPL-3.0-only", "LGPL-3.0-or-later",
+		"MIT", "MIT-0", "MPL-1.1", "MPL-2.0", "MS-PL", "NCSA", "OFL-1.1", "OpenSSL",
+		"PSF-2.0", "Python-2.0", "SSPL-1.0", "Unlicense", "UPL-1.0", "WTFPL", "Zlib",
+	}
+	m := make(map[string]string, len(ids))
+	for _, id := range ids {
+		m[strings.ToLower(id)] = id
+	}
+	return m
+}()
+
+// licenseAliases maps common non-SPDX spellings (as found in package
+// metadata and trove classifiers) to SPDX identifiers.
+var licenseAliases = map[string]string{
+	"mit license":                           "MIT",
+	"the mit license":                       "MIT",
+	"apache":                                "Apache-2.0",
+	"apache 2":                              "Apache-2.0",
+	"apache 2.0":                            "Apache-2.0",
+	"apache-2":                              "Apache-2.0",
+	"apache license 2.0":                    "Apache-2.0",
+	"apache license, version 2.0":           "Apache-2.0",
+	"apache software license":               "Apache-2.0",
+	"apache software license 2.0":           "Apache-2.0",
+	"bsd":                                   "BSD-3-Clause",
+	"bsd license":                           "BSD-3-Clause",
+	"new bsd license":                       "BSD-3-Clause",
+	"3-clause bsd license":                  "BSD-3-Clause",
+	"simplified bsd license":                "BSD-2-Clause",
+	"2-clause bsd license":                  "BSD-2-Clause",
+	"isc license":                           "ISC",
+	"isc license (iscl)":                    "ISC",
+	"mozilla public license 2.0 (mpl 2.0)":  "MPL-2.0",
+	"mpl 2.0":                               "MPL-2.0",
+	"gpl":                                   "GPL-3.0",
+	"gplv2":                                 "GPL-2.0",
+	"gplv3":                                 "GPL-3.0",
+	"gnu general public license v2 (gplv2)": "GPL-2.0",
+	"gnu general public license v3 (gplv3)": "GPL-3.0",
+	"gnu lesser general public license v3 (lgplv3)": "LGPL-3.0",
+	"gnu affero general public license v3":          "AGPL-3.0",
+	"lgpl":                                          "LGPL-3.0",
+	"python software foundation license":            "PSF-2.0",
+	"the unlicense":                                 "Unlicense",
+	"the unlicense (unlicense)":                     "Unlicense",
+	"public domain":                                 "Unlicense",
+}
+
+// Normalize maps a declared license to its SPDX identifier. Expressions
+// ("MIT OR Apache-2.0", "(MIT AND Zlib)") are normalized term by term;
+// unrecognized values are returned trimmed.
+func Normalize(license string) string {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return ""
+	}
+	if id := normalizeTerm(license); id != "" {
+		return id
+	}
+	expr := strings.Trim(license, "()")
+	for _, op := range []string{" OR ", " AND "} {
+		if strings.Contains(expr, op) {
+			terms := strings.Split(expr, op)
+			for i, t := range terms {
+				terms[i] = Normalize(t)
+			}
+			return strings.Join(terms, op)
+		}
+	}
+	return license
+}
+
+func normalizeTerm(term string) string {
+	lower := strings.ToLower(strings.TrimSpace(term))
+	if id, ok := spdxIDs[lower]; ok {
+		return id
+	}
+	return licenseAliases[lower]
+}
+
+// Detect identifies the license from the text of a LICENSE file, returning
+// its SPDX identifier or "" when the text isn't recognized.
+func Detect(text string) string {
+	// Collapse whitespace so phrases wrapped across lines still match.
+	t := strings.Join(strings.Fields(text), " ")
+	has := func(s string) bool { return strings.Contains(t, s) }
+
+	switch {
+	case has("GNU AFFERO GENERAL PUBLIC LICENSE"):
+		return "AGPL-3.0"
+	case has("GNU LESSER GENERAL PUBLIC LICENSE"):
+		if has("Version 2.1") {
+			return "LGPL-2.1"
+		}
+		return "LGPL-3.0"
+	case has("GNU GENERAL PUBLIC LICENSE"):
+		if has("Version 2,") || has("Version 2 ") {
+			return "GPL-2.0"
+		}
+		return "GPL-3.0"
+	case has("Mozilla Public License Version 2.0"), has("Mozilla Public License, version 2.0"):
+		return "MPL-2.0"
+	case has("Apache License") && (has("Version 2.0") || has("LICENSE-2.0")):
+		return "Apache-2.0"
+	case has("Permission is hereby granted, free of charge"):
+		return "MIT"
+	case has("Permission to use, copy, modify, and/or distribute this software for any purpose"),
+		has("Permission to use, copy, modify, and distribute this software for any purpose"):
+		return "ISC"
+	case has("Redistribution and use in source and binary forms"):
+		if has("endorse or promote products") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case has("This is free and unencumbered software released into the public domain"):
+		return "Unlicense"
+	case has("Boost Software License"):
+		return "BSL-1.0"
+	case has("Eclipse Public License - v 2.0"):
+		return "EPL-2.0"
+	}
+	return ""
+}
+
+// Policy flags licenses a project may not ship. Denied licenses are always
+// flagged; when Allow is non-empty, anything not in it (including unresolved
+// licenses) is flagged too. Entries match SPDX IDs ignoring "-only" and
+// "-or-later".
+type Policy struct {
+	Allow []string
+	Deny  []string
+}
+
+// Check returns why license violates the policy, or "" if it doesn't. For an
+// expression, an OR passes if any alternative passes and an AND only if all
+// terms pass.
+func (p Policy) Check(license string) string {
+	if license == "" {
+		if len(p.Allow) > 0 {
+			return "unknown license"
+		}
+		return ""
+	}
+	var reason string
+	for _, alt := range strings.Split(strings.Trim(license, "()"), " OR ") {
+		reason = ""
+		for _, term := range strings.Split(alt, " AND ") {
+			if r := p.checkTerm(strings.Trim(term, "() ")); r != "" {
+				reason = r
+				break
+			}
+		}
+		if reason == "" {
+			return ""
+		}
+	}
+	return reason
+}
+
+func (p Policy) checkTerm(id string) string {
+	if listed(p.Deny, id) {
+		return "denied license " + id
+	}
+	if len(p.Allow) > 0 && !listed(p.Allow, id) {
+		return "license " + id + " not in allow list"
+	}
+	return ""
+}
+
+func listed(list []string, id string) bool {
+	base := licenseBase(id)
+	for _, l := range list {
+		if strings.EqualFold(licenseBase(l), base) {
+			return true
+		}
+	}
+	return false
+}
+
+func licenseBase(id string) string {
+	id = strings.TrimSuffix(id, "+")
+	id = strings.TrimSuffix(id, "-only")
+	return strings.TrimSuffix(id, "-or-later")
+}
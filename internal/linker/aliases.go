@@ -0,0 +1,248 @@
+package linker
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// jsModuleExts extends tsModuleExts with the JavaScript files a bare or
+// aliased specifier may also name.
+var jsModuleExts = append(append([]string{}, tsModuleExts...), ".js", ".jsx", "/index.js", "/index.jsx")
+
+// tsconfigPaths holds one tsconfig.json's resolution settings.
+type tsconfigPaths struct {
+	dir     string
+	baseURL string
+	paths   []tsconfigAlias
+}
+
+// tsconfigAlias is a "paths" entry: a pattern with at most one "*" and the
+// repo-relative targets it maps to.
+type tsconfigAlias struct {
+	pattern string
+	targets []string
+}
+
+// linkModuleAliases resolves non-relative TypeScript and JavaScript imports
+// that name files in the repository: tsconfig/jsconfig "paths" aliases
+// (@app/shared/utils), baseUrl-relative imports, and imports of workspace
+// packages by their package.json name. Each importing module gets an
+// Imports edge (kind=alias or workspace, with the specifier) to the target
+// module, which later phases such as reexports follow, and the import's
+// Dependency node is marked internal=true with Properties["resolved_path"]
+// set to the file.
+func (l *Linker) linkModuleAliases(ctx context.Context) (int, error) {
+	configs, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeModule,
+		Properties: map[string]string{"kind": "tsconfig"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeService,
+		Properties: map[string]string{"ecosystem": "nodejs"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(configs) == 0 && len(services) == 0 {
+		return 0, nil
+	}
+
+	var tsconfigs []tsconfigPaths
+	for _, c := range configs {
+		tc := tsconfigPaths{dir: path.Dir(c.FilePath), baseURL: c.Properties["base_url"]}
+		for _, entry := range strings.Split(c.Properties["paths"], ",") {
+			pattern, targets, ok := strings.Cut(entry, "=")
+			if ok && pattern != "" {
+				tc.paths = append(tc.paths, tsconfigAlias{pattern: pattern, targets: strings.Split(targets, "|")})
+			}
+		}
+		tsconfigs = append(tsconfigs, tc)
+	}
+	packages := make(map[string]string) // package name -> directory
+	for _, s := range services {
+		packages[s.Name] = path.Dir(s.FilePath)
+	}
+
+	modules := make(map[string]*graph.Node) // file path -> module node
+	for _, lang := range []string{"typescript", "javascript"} {
+		nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeModule, Language: lang})
+		if err != nil {
+			return 0, err
+		}
+		for _, m := range nodes {
+			modules[m.FilePath] = m
+		}
+	}
+
+	linked := 0
+	for _, lang := range []string{"typescript", "javascript"} {
+		imports, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+			Type:       graph.NodeDependency,
+			Language:   lang,
+			Properties: map[string]string{"kind": "import"},
+		})
+		if err != nil {
+			return linked, err
+		}
+		for _, imp := range imports {
+			spec := imp.Name
+			if spec == "" || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") {
+				continue
+			}
+			importers, err := l.importingFiles(ctx, imp)
+			if err != nil {
+				return linked, err
+			}
+			// Each importer resolves the specifier against its own nearest
+			// tsconfig; the node records the first resolution.
+			resolved := ""
+			for _, importer := range importers {
+				kind := "alias"
+				file := resolveTsconfigImport(tsconfigs, modules, importer, spec)
+				if file == "" {
+					kind = "workspace"
+					file = resolveWorkspaceImport(packages, modules, spec)
+				}
+				if file == "" || file == importer {
+					continue
+				}
+				if resolved == "" {
+					resolved = file
+				}
+				source := modules[importer]
+				if source == nil {
+					continue
+				}
+				target := modules[file]
+				edge := &graph.Edge{
+					ID:         graph.NewEdgeID(graph.EdgeImports, source.ID, target.ID),
+					Type:       graph.EdgeImports,
+					SourceID:   source.ID,
+					TargetID:   target.ID,
+					Properties: withConfidence(map[string]string{"kind": kind, "specifier": spec}, graph.ConfidenceExact, scoreExact),
+				}
+				if err := l.store.AddEdge(ctx, edge); err != nil {
+					continue
+				}
+				linked++
+
+				if l.verbose {
+					l.log("    Import alias: %s in %s resolved to %s", spec, importer, file)
+				}
+			}
+			if resolved == "" {
+				continue
+			}
+			imp.Properties["internal"] = "true"
+			imp.Properties["resolved_path"] = resolved
+			if err := l.store.UpdateNode(ctx, imp); err != nil {
+				return linked, err
+			}
+		}
+	}
+	return linked, nil
+}
+
+// resolveTsconfigImport resolves spec using the nearest tsconfig enclosing
+// the importing file: the longest matching "paths" pattern first, then
+// baseUrl.
+func resolveTsconfigImport(configs []tsconfigPaths, modules map[string]*graph.Node, importer, spec string) string {
+	var tc *tsconfigPaths
+	for i := range configs {
+		c := &configs[i]
+		if !inDir(importer, c.dir) {
+			continue
+		}
+		if tc == nil || len(c.dir) > len(tc.dir) {
+			tc = c
+		}
+	}
+	if tc == nil {
+		return ""
+	}
+
+	var best *tsconfigAlias
+	var bestWildcard string
+	bestLen := -1
+	for i := range tc.paths {
+		a := &tc.paths[i]
+		prefix, suffix, wildcard := strings.Cut(a.pattern, "*")
+		if !wildcard {
+			if spec == a.pattern {
+				best, bestWildcard = a, ""
+				break // exact matches win outright
+			}
+			continue
+		}
+		if len(prefix) > bestLen && len(spec) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(spec, prefix) && strings.HasSuffix(spec, suffix) {
+			best, bestWildcard, bestLen = a, spec[len(prefix):len(spec)-len(suffix)], len(prefix)
+		}
+	}
+	if best != nil {
+		for _, target := range best.targets {
+			if file := resolveModuleFile(modules, strings.Replace(target, "*", bestWildcard, 1)); file != "" {
+				return file
+			}
+		}
+	}
+	if tc.baseURL != "" {
+		return resolveModuleFile(modules, path.Join(tc.baseURL, spec))
+	}
+	return ""
+}
+
+// resolveWorkspaceImport resolves spec against the repository's own npm
+// packages: "@acme/ui" names the package entry point and "@acme/ui/button" a
+// file inside it, looked up in the package directory and its src directory.
+func resolveWorkspaceImport(packages map[string]string, modules map[string]*graph.Node, spec string) string {
+	name, dir := "", ""
+	for pkg, pkgDir := range packages {
+		if (spec == pkg || strings.HasPrefix(spec, pkg+"/")) && len(pkg) > len(name) {
+			name, dir = pkg, pkgDir
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	rest := strings.TrimPrefix(strings.TrimPrefix(spec, name), "/")
+	if rest == "" {
+		rest = "index"
+	}
+	for _, base := range []string{path.Join(dir, "src", rest), path.Join(dir, rest)} {
+		if file := resolveModuleFile(modules, base); file != "" {
+			return file
+		}
+	}
+	return ""
+}
+
+// resolveModuleFile maps a module path without extension (or naming the
+// compiled .js file) to an indexed file, or "" if none matches.
+func resolveModuleFile(modules map[string]*graph.Node, base string) string {
+	base = path.Clean(base)
+	if _, ok := modules[base]; ok {
+		return base
+	}
+	for _, ext := range []string{".js", ".jsx", ".mjs"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+	for _, ext := range jsModuleExts {
+		if _, ok := modules[base+ext]; ok {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// inDir reports whether the repo-relative file lies under dir ("." is the
+// repository root).
+func inDir(file, dir string) bool {
+	return dir == "." || dir == "" || strings.HasPrefix(file, dir+"/")
+}
@@ -0,0 +1,97 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkModuleAliases(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+	module := func(file string) *graph.Node {
+		return ts(&graph.Node{ID: "mod-" + file, Type: graph.NodeModule, Name: file, FilePath: file})
+	}
+	imp := func(id, spec, file string) *graph.Node {
+		return ts(&graph.Node{ID: id, Type: graph.NodeDependency, Name: spec, FilePath: file, Properties: map[string]string{"kind": "import"}})
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "tsconfig-root", Type: graph.NodeModule, Name: ".", FilePath: "tsconfig.base.json", Language: "manifest",
+			Properties: map[string]string{"kind": "tsconfig", "paths": "@app/*=apps/web/src/app/*,@app/shared/*=libs/shared/src/*|libs/legacy/*"}},
+		&graph.Node{ID: "tsconfig-web", Type: graph.NodeModule, Name: "apps/web", FilePath: "apps/web/tsconfig.json", Language: "manifest",
+			Properties: map[string]string{"kind": "tsconfig", "base_url": "apps/web/src", "paths": "@env=apps/web/src/env/prod.ts"}},
+		&graph.Node{ID: "svc-ui", Type: graph.NodeService, Name: "@acme/ui", FilePath: "packages/ui/package.json", Language: "manifest",
+			Properties: map[string]string{"kind": "service", "ecosystem": "nodejs"}},
+		module("apps/web/src/main.ts"),
+		module("apps/web/src/env/prod.ts"),
+		module("apps/web/src/components/button.tsx"),
+		module("apps/api/src/server.ts"),
+		module("libs/shared/src/utils/index.ts"),
+		module("libs/legacy/dates.ts"),
+		module("packages/ui/src/index.ts"),
+		module("packages/ui/theme.ts"),
+		imp("dep-env", "@env", "apps/web/src/main.ts"),
+		imp("dep-button", "components/button", "apps/web/src/main.ts"),
+		imp("dep-utils", "@app/shared/utils", "apps/api/src/server.ts"),
+		imp("dep-dates", "@app/shared/dates", "apps/api/src/server.ts"),
+		imp("dep-ui", "@acme/ui", "apps/api/src/server.ts"),
+		imp("dep-theme", "@acme/ui/theme", "apps/api/src/server.ts"),
+		imp("dep-express", "express", "apps/api/src/server.ts"),
+		imp("dep-missing", "@app/shared/missing", "apps/api/src/server.ts"),
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkModuleAliases(ctx)
+	if err != nil {
+		t.Fatalf("linkModuleAliases: %v", err)
+	}
+
+	want := map[string]struct{ file, kind string }{
+		"dep-env":    {"apps/web/src/env/prod.ts", "alias"},
+		"dep-button": {"apps/web/src/components/button.tsx", "alias"},
+		"dep-utils":  {"libs/shared/src/utils/index.ts", "alias"},
+		"dep-dates":  {"libs/legacy/dates.ts", "alias"},
+		"dep-ui":     {"packages/ui/src/index.ts", "workspace"},
+		"dep-theme":  {"packages/ui/theme.ts", "workspace"},
+	}
+	if count != len(want) {
+		t.Errorf("linked %d imports, want %d", count, len(want))
+	}
+	for id, w := range want {
+		n, err := store.GetNode(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.Properties["resolved_path"] != w.file || n.Properties["internal"] != "true" {
+			t.Errorf("%s resolved to %q (internal=%q), want %q", id, n.Properties["resolved_path"], n.Properties["internal"], w.file)
+		}
+	}
+	for _, id := range []string{"dep-express", "dep-missing"} {
+		n, err := store.GetNode(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.Properties["resolved_path"] != "" {
+			t.Errorf("%s unexpectedly resolved to %q", id, n.Properties["resolved_path"])
+		}
+	}
+
+	edges, err := store.GetEdges(ctx, "mod-apps/api/src/server.ts", graph.EdgeImports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string) // target -> kind
+	for _, e := range edges {
+		if e.SourceID == "mod-apps/api/src/server.ts" {
+			got[e.TargetID] = e.Properties["kind"]
+		}
+	}
+	for _, id := range []string{"dep-utils", "dep-dates", "dep-ui", "dep-theme"} {
+		w := want[id]
+		if got["mod-"+w.file] != w.kind {
+			t.Errorf("Imports edge to %s kind = %q, want %q", w.file, got["mod-"+w.file], w.kind)
+		}
+	}
+}
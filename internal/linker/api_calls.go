@@ -10,7 +10,9 @@ import (
 
 // linkAPICalls matches NodeDependency nodes with kind=api_call to
 // NodeAPIEndpoint nodes, creating EdgeConsumes edges and service-level
-// EdgeDependsOn edges.
+// EdgeDependsOn edges. Calls under a reverse proxy route are matched at the
+// path the proxy forwards them to, and calls to absolute or
+// environment-based URLs are matched against the service serving the host.
 func (l *Linker) linkAPICalls(ctx context.Context) (int, error) {
 	// Query all API call dependency nodes.
 	apiCalls, err := l.store.QueryNodes(ctx, graph.NodeFilter{
@@ -33,19 +35,15 @@ func (l *Linker) linkAPICalls(ctx context.Context) (int, error) {
 		return 0, nil
 	}
 
-	// Build endpoint index: normalized path → endpoint node.
-	// An endpoint might have a full_path (resolved with prefix) or just path.
-	endpointIndex := make(map[string]*graph.Node)
-	for _, ep := range endpoints {
-		fullPath := ep.Properties["full_path"]
-		if fullPath == "" {
-			fullPath = ep.Properties["path"]
-		}
-		if fullPath == "" {
-			continue
-		}
-		normalized := normalizeURLPath(fullPath)
-		endpointIndex[normalized] = ep
+	endpointIndex := NewEndpointIndex(endpoints)
+	byGroup := l.endpointsByGroup(endpoints)
+	routes, err := l.loadRoutes(ctx, byGroup)
+	if err != nil {
+		return 0, err
+	}
+	hosts, err := l.loadHosts(ctx, byGroup)
+	if err != nil {
+		return 0, err
 	}
 
 	// Query services for service-level edge creation.
@@ -53,14 +51,7 @@ func (l *Linker) linkAPICalls(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	serviceByGroup := make(map[string]*graph.Node)
-	for _, svc := range services {
-		group := topDir(svc.FilePath)
-		if group == "" {
-			group = svc.Name
-		}
-		serviceByGroup[group] = svc
-	}
+	serviceByGroup := l.servicesByGroup(services)
 
 	// Track service-level edges to avoid duplicates.
 	serviceDeps := make(map[string]bool)
@@ -72,43 +63,93 @@ func (l *Linker) linkAPICalls(ctx context.Context) (int, error) {
 			continue
 		}
 
-		normalized := normalizeURLPath(callPath)
-		ep := matchEndpoint(normalized, endpointIndex)
+		// Calls to an absolute or environment-based URL are matched against
+		// the endpoints of the service serving that host, or through the
+		// proxy routes when the host is not a known service.
+		var ep *graph.Node
+		var score float64
+		var route *proxyRoute
+		base := hosts.resolve(l, call)
+		if base != nil {
+			if base.group != "" {
+				ep, score = matchEndpoint(normalizeURLPath(base.path), byGroup[base.group])
+			} else {
+				ep, score, route = routes.match(normalizeURLPath(base.path), endpointIndex)
+			}
+			if ep != nil && base.guessed {
+				score = min(score, scoreStrong)
+			}
+		}
+		if ep == nil {
+			base = nil
+			// Calls through a reverse proxy reach the endpoint at the
+			// rewritten path on the route's upstream. A stronger direct
+			// match wins, so a catch-all route cannot shadow an exact
+			// endpoint.
+			ep, score, route = routes.match(normalizeURLPath(callPath), endpointIndex)
+			if direct, directScore := endpointIndex.match(call); direct != nil && directScore > score {
+				ep, score, route = direct, directScore, nil
+			}
+		}
 		if ep == nil {
 			continue
 		}
+		level := graph.ConfidenceExact
+		if score < scoreExact {
+			level = graph.ConfidenceHeuristic
+		}
 
 		// Create EdgeConsumes from the calling dependency → endpoint.
 		consumeEdge := &graph.Edge{
-			ID:       graph.NewNodeID(string(graph.EdgeConsumes), call.ID, ep.ID),
+			ID:       graph.NewEdgeID(graph.EdgeConsumes, call.ID, ep.ID),
 			Type:     graph.EdgeConsumes,
 			SourceID: call.ID,
 			TargetID: ep.ID,
-			Properties: map[string]string{
+			Properties: withConfidence(map[string]string{
 				"resolved": "true",
-			},
+			}, level, score),
+		}
+		if base != nil {
+			consumeEdge.Properties["base_host"] = base.host
+			if base.env != "" {
+				consumeEdge.Properties["base_env"] = base.env
+			}
+		}
+		if route != nil {
+			consumeEdge.Properties["route"] = route.prefix
+			consumeEdge.Properties["route_upstream"] = route.upstream
+			consumeEdge.Properties["route_file"] = route.node.FilePath
+		}
+		// In multi-repo graphs, flag calls that cross repository boundaries.
+		crossRepo := call.Properties["repo"] != "" && ep.Properties["repo"] != "" &&
+			call.Properties["repo"] != ep.Properties["repo"]
+		if crossRepo {
+			consumeEdge.Properties["cross_repo"] = "true"
 		}
 		if err := l.store.AddEdge(ctx, consumeEdge); err != nil {
 			continue
 		}
 
 		// Create service-level EdgeDependsOn if both sides have services.
-		callerGroup := topDir(call.FilePath)
+		callerGroup := l.group(call.FilePath)
 		callerSvc := serviceByGroup[callerGroup]
-		endpointGroup := topDir(ep.FilePath)
+		endpointGroup := l.group(ep.FilePath)
 		endpointSvc := serviceByGroup[endpointGroup]
 
 		if callerSvc != nil && endpointSvc != nil && callerSvc.ID != endpointSvc.ID {
 			depKey := callerSvc.ID + "→" + endpointSvc.ID
 			if !serviceDeps[depKey] {
 				depEdge := &graph.Edge{
-					ID:       graph.NewNodeID(string(graph.EdgeDependsOn), callerSvc.ID, endpointSvc.ID),
+					ID:       graph.NewEdgeID(graph.EdgeDependsOn, callerSvc.ID, endpointSvc.ID),
 					Type:     graph.EdgeDependsOn,
 					SourceID: callerSvc.ID,
 					TargetID: endpointSvc.ID,
-					Properties: map[string]string{
+					Properties: withConfidence(map[string]string{
 						"kind": "api_dependency",
-					},
+					}, level, score),
+				}
+				if crossRepo {
+					depEdge.Properties["cross_repo"] = "true"
 				}
 				if err := l.store.AddEdge(ctx, depEdge); err == nil {
 					serviceDeps[depKey] = true
@@ -122,13 +163,55 @@ func (l *Linker) linkAPICalls(ctx context.Context) (int, error) {
 	return resolved, nil
 }
 
-// paramPattern matches URL path parameters like {id}, :id, <id>.
-var paramPattern = regexp.MustCompile(`\{[^}]+\}|:[a-zA-Z_][a-zA-Z0-9_]*|<[^>]+>`)
+// EndpointIndex maps normalized endpoint paths to endpoint nodes so API call
+// nodes can be matched to the endpoints they consume.
+type EndpointIndex map[string]*graph.Node
+
+// NewEndpointIndex indexes endpoints by normalized path. An endpoint might
+// have a full_path (resolved with prefix) or just path.
+func NewEndpointIndex(endpoints []*graph.Node) EndpointIndex {
+	index := make(EndpointIndex)
+	for _, ep := range endpoints {
+		fullPath := endpointPath(ep)
+		if fullPath == "" {
+			continue
+		}
+		index[normalizeURLPath(fullPath)] = ep
+	}
+	return index
+}
+
+// Match returns the endpoint an api_call node resolves to, or nil.
+func (ix EndpointIndex) Match(call *graph.Node) *graph.Node {
+	ep, _ := ix.match(call)
+	return ep
+}
+
+// match is Match, also returning the match's confidence score.
+func (ix EndpointIndex) match(call *graph.Node) (*graph.Node, float64) {
+	callPath := call.Properties["path"]
+	if callPath == "" {
+		return nil, 0
+	}
+	return matchEndpoint(normalizeURLPath(callPath), ix)
+}
+
+// ServiceGroup returns the service grouping key (top-level directory) for a
+// file path, as used when creating service-level edges.
+func ServiceGroup(filePath string) string {
+	return topDir(filePath)
+}
+
+// paramPattern matches URL path parameters like {id}, {id:int}, :id,
+// :id(\d+), <id> and <int:id>.
+var paramPattern = regexp.MustCompile(`\{[^}]+\}|:[a-zA-Z_][a-zA-Z0-9_]*(?:\([^)]*\))?|<[^>]+>`)
 
 // normalizeURLPath normalizes a URL path for matching:
 // - Lowercase
 // - Strip trailing slash
 // - Replace path parameters ({id}, :id, <id>) with *
+//
+// A parameter of a known type keeps it after the *: {id:int} becomes *int.
 func normalizeURLPath(p string) string {
 	p = strings.ToLower(p)
 	p = strings.TrimRight(p, "/")
@@ -136,54 +219,82 @@ func normalizeURLPath(p string) string {
 		p = "/" + p
 	}
 	// Replace path parameters with wildcard.
-	p = paramPattern.ReplaceAllString(p, "*")
+	p = paramPattern.ReplaceAllStringFunc(p, func(raw string) string {
+		return "*" + parsePathParam(raw).Type
+	})
 	return p
 }
 
 // matchEndpoint tries to match a normalized call path to an endpoint.
-// First tries exact match, then suffix matching (for API gateway prefixes).
-func matchEndpoint(callPath string, index map[string]*graph.Node) *graph.Node {
+// First tries exact match, then suffix matching (for API gateway prefixes),
+// then segment-wise wildcard matching. It also returns the match's
+// confidence score: exact matches score 1, wildcard matches are strong
+// heuristics, and suffix matches are weaker ones.
+func matchEndpoint(callPath string, index map[string]*graph.Node) (*graph.Node, float64) {
 	// Exact match.
 	if ep, ok := index[callPath]; ok {
-		return ep
+		return ep, scoreExact
 	}
 
 	// Suffix match: the call path might have an extra prefix (e.g., /backend/api/v1/...)
 	// while the endpoint is /api/v1/...
 	for epPath, ep := range index {
 		if strings.HasSuffix(callPath, epPath) {
-			return ep
+			return ep, scoreMedium
 		}
 		if strings.HasSuffix(epPath, callPath) {
-			return ep
+			return ep, scoreMedium
 		}
 	}
 
-	// Wildcard-aware match: compare segments, treating * as wildcard.
+	// Wildcard-aware match: compare segments, treating parameters as
+	// wildcards their values must fit. Of several matches, the one whose
+	// typed parameters confirm the most segments wins, then the first path.
 	callSegments := strings.Split(callPath, "/")
+	var (
+		best      *graph.Node
+		bestPath  string
+		bestTyped = -1
+	)
 	for epPath, ep := range index {
-		epSegments := strings.Split(epPath, "/")
-		if matchSegments(callSegments, epSegments) {
-			return ep
+		typed, ok := matchTypedSegments(callSegments, strings.Split(epPath, "/"))
+		if !ok {
+			continue
 		}
+		if typed > bestTyped || typed == bestTyped && epPath < bestPath {
+			best, bestPath, bestTyped = ep, epPath, typed
+		}
+	}
+	if best != nil {
+		return best, scoreStrong
 	}
 
-	return nil
+	return nil, 0
 }
 
-// matchSegments checks whether two URL segment slices match, treating *
-// in either side as a wildcard that matches any single segment.
+// matchSegments checks whether two URL segment slices match, treating a
+// parameter (* or *type) in either side as a wildcard that matches any
+// single segment of a compatible type.
 func matchSegments(a, b []string) bool {
+	_, ok := matchTypedSegments(a, b)
+	return ok
+}
+
+// matchTypedSegments is matchSegments, also returning the number of
+// literal segments typed parameters confirmed.
+func matchTypedSegments(a, b []string) (int, bool) {
 	if len(a) != len(b) {
-		return false
+		return 0, false
 	}
+	typed := 0
 	for i := range a {
-		if a[i] == "*" || b[i] == "*" {
-			continue
+		ok, confirmed := segmentsCompatible(a[i], b[i])
+		if !ok {
+			return 0, false
 		}
-		if a[i] != b[i] {
-			return false
+		if confirmed {
+			typed++
 		}
 	}
-	return true
+	return typed, true
 }
@@ -0,0 +1,260 @@
+package linker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// BacklogStatus is the resolution status of an unresolved API call backlog entry.
+type BacklogStatus string
+
+const (
+	// BacklogOpen means the call is still unresolved as of the last link run.
+	BacklogOpen BacklogStatus = "open"
+	// BacklogResolved means the call was matched to an endpoint in a later run
+	// (or the calling code was removed).
+	BacklogResolved BacklogStatus = "resolved"
+	// BacklogExternal means the call was marked as intentionally targeting an
+	// API outside the indexed codebase. External entries are never reopened.
+	BacklogExternal BacklogStatus = "external"
+)
+
+// BacklogEntry tracks a single unresolved API call across link runs.
+type BacklogEntry struct {
+	Key        string        `json:"key"`
+	Method     string        `json:"method,omitempty"`
+	Path       string        `json:"path"`
+	FilePath   string        `json:"file_path"`
+	Service    string        `json:"service,omitempty"`
+	Status     BacklogStatus `json:"status"`
+	FirstSeen  time.Time     `json:"first_seen"`
+	LastSeen   time.Time     `json:"last_seen"`
+	ResolvedAt *time.Time    `json:"resolved_at,omitempty"` // nil unless resolved
+	Note       string        `json:"note,omitempty"`
+}
+
+// BacklogSnapshot records backlog counts at the end of a single link run,
+// so the unresolved count can be tracked over time.
+type BacklogSnapshot struct {
+	Time     time.Time `json:"time"`
+	Open     int       `json:"open"`
+	Resolved int       `json:"resolved"`
+	External int       `json:"external"`
+	New      int       `json:"new"`
+}
+
+// maxBacklogHistory caps the number of snapshots kept in the backlog file.
+const maxBacklogHistory = 100
+
+// Backlog is the persisted set of unresolved API calls, keyed by a stable
+// call key (file path + method + normalized path). Node IDs are not used as
+// keys because they include line numbers and change on unrelated edits.
+type Backlog struct {
+	Entries map[string]*BacklogEntry `json:"entries,omitempty"`
+	History []BacklogSnapshot        `json:"history,omitempty"`
+}
+
+// LoadBacklog reads the backlog from the given file path.
+// Returns an empty backlog (no error) if the file does not exist.
+func LoadBacklog(path string) (*Backlog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Backlog{}, nil
+		}
+		return nil, fmt.Errorf("read backlog: %w", err)
+	}
+	var b Backlog
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("unmarshal backlog: %w", err)
+	}
+	// Backlogs saved before ResolvedAt was a pointer hold the zero time
+	// for unresolved entries.
+	for _, e := range b.Entries {
+		if e.ResolvedAt != nil && e.ResolvedAt.IsZero() {
+			e.ResolvedAt = nil
+		}
+	}
+	return &b, nil
+}
+
+// Save writes the backlog to the given file path.
+func (b *Backlog) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal backlog: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write backlog: %w", err)
+	}
+	return nil
+}
+
+// SetStatus changes the status of the entry with the given key and records
+// an optional note. Returns an error if no entry has that key.
+func (b *Backlog) SetStatus(key string, status BacklogStatus, note string, now time.Time) error {
+	e, ok := b.Entries[key]
+	if !ok {
+		return fmt.Errorf("backlog entry %q not found", key)
+	}
+	e.Status = status
+	if note != "" {
+		e.Note = note
+	}
+	if status == BacklogResolved {
+		e.ResolvedAt = &now
+	} else {
+		e.ResolvedAt = nil
+	}
+	return nil
+}
+
+// Sorted returns backlog entries with the given status (all if empty),
+// ordered by first-seen date then key.
+func (b *Backlog) Sorted(status BacklogStatus) []*BacklogEntry {
+	var entries []*BacklogEntry
+	for _, e := range b.Entries {
+		if status != "" && e.Status != status {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].FirstSeen.Equal(entries[j].FirstSeen) {
+			return entries[i].FirstSeen.Before(entries[j].FirstSeen)
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// counts returns the number of entries in each status.
+func (b *Backlog) counts() (open, resolved, external int) {
+	for _, e := range b.Entries {
+		switch e.Status {
+		case BacklogOpen:
+			open++
+		case BacklogResolved:
+			resolved++
+		case BacklogExternal:
+			external++
+		}
+	}
+	return open, resolved, external
+}
+
+// backlogKey builds the stable backlog key for an API call node.
+func backlogKey(call *graph.Node) string {
+	method := call.Properties["http_method"]
+	if method == "" {
+		method = "UNKNOWN"
+	}
+	return call.FilePath + "|" + method + " " + normalizeURLPath(call.Properties["path"])
+}
+
+// IsExternal reports whether the given API call node has been marked as an
+// intentionally external call in the backlog.
+func (b *Backlog) IsExternal(call *graph.Node) bool {
+	if b == nil {
+		return false
+	}
+	e, ok := b.Entries[backlogKey(call)]
+	return ok && e.Status == BacklogExternal
+}
+
+// UnresolvedAPICalls returns the api_call dependency nodes that have no
+// Consumes edge to an endpoint.
+func (l *Linker) UnresolvedAPICalls(ctx context.Context) ([]*graph.Node, error) {
+	apiCalls, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Properties: map[string]string{"kind": "api_call"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l.filterUnresolvedCalls(ctx, apiCalls), nil
+}
+
+// UpdateBacklog reconciles the backlog with the API calls currently in the
+// graph. Unresolved calls not yet tracked are added as open; previously open
+// entries that are no longer unresolved are marked resolved; resolved entries
+// that reappear are reopened. External entries keep their status. A snapshot
+// of the resulting counts is appended to the backlog history and returned.
+func (l *Linker) UpdateBacklog(ctx context.Context, b *Backlog, now time.Time) (BacklogSnapshot, error) {
+	unresolved, err := l.UnresolvedAPICalls(ctx)
+	if err != nil {
+		return BacklogSnapshot{}, err
+	}
+
+	if b.Entries == nil {
+		b.Entries = make(map[string]*BacklogEntry)
+	}
+
+	current := make(map[string]struct{})
+	newCount := 0
+	for _, call := range unresolved {
+		if call.Properties["path"] == "" {
+			continue
+		}
+		key := backlogKey(call)
+		current[key] = struct{}{}
+
+		e, ok := b.Entries[key]
+		if !ok {
+			b.Entries[key] = &BacklogEntry{
+				Key:       key,
+				Method:    call.Properties["http_method"],
+				Path:      call.Properties["path"],
+				FilePath:  call.FilePath,
+				Service:   l.group(call.FilePath),
+				Status:    BacklogOpen,
+				FirstSeen: now,
+				LastSeen:  now,
+			}
+			newCount++
+			continue
+		}
+		e.LastSeen = now
+		if e.Status == BacklogResolved {
+			e.Status = BacklogOpen
+			e.ResolvedAt = nil
+		}
+	}
+
+	for key, e := range b.Entries {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		if e.Status == BacklogOpen {
+			e.Status = BacklogResolved
+			resolved := now
+			e.ResolvedAt = &resolved
+		}
+	}
+
+	open, resolved, external := b.counts()
+	snap := BacklogSnapshot{
+		Time:     now,
+		Open:     open,
+		Resolved: resolved,
+		External: external,
+		New:      newCount,
+	}
+	b.History = append(b.History, snap)
+	if len(b.History) > maxBacklogHistory {
+		b.History = b.History[len(b.History)-maxBacklogHistory:]
+	}
+
+	if l.verbose {
+		l.log("  Unresolved call backlog: %d open (%d new), %d resolved, %d external",
+			open, newCount, resolved, external)
+	}
+
+	return snap, nil
+}
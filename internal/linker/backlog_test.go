@@ -0,0 +1,139 @@
+package linker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestUpdateBacklogLifecycle(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	callA := &graph.Node{
+		ID: "call-a", Type: graph.NodeDependency, Name: "GET /api/v1/users",
+		FilePath: "frontend/src/api.ts",
+		Properties: map[string]string{
+			"kind": "api_call", "http_method": "GET", "path": "/api/v1/users",
+		},
+	}
+	callB := &graph.Node{
+		ID: "call-b", Type: graph.NodeDependency, Name: "POST https://stripe.com/v1/charges",
+		FilePath: "billing/pay.py",
+		Properties: map[string]string{
+			"kind": "api_call", "http_method": "POST", "path": "/v1/charges",
+		},
+	}
+	addNodes(t, store, callA, callB)
+
+	l := NewLinker(store, nil, nil, false)
+	backlog := &Backlog{}
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	snap, err := l.UpdateBacklog(ctx, backlog, day1)
+	if err != nil {
+		t.Fatalf("UpdateBacklog: %v", err)
+	}
+	if snap.Open != 2 || snap.New != 2 {
+		t.Fatalf("snapshot = %+v, want open=2 new=2", snap)
+	}
+
+	keyA := backlogKey(callA)
+	keyB := backlogKey(callB)
+	if err := backlog.SetStatus(keyB, BacklogExternal, "stripe API", day1); err != nil {
+		t.Fatalf("SetStatus: %v", err)
+	}
+
+	// Resolve callA by adding a matching endpoint and a Consumes edge.
+	addNodes(t, store, &graph.Node{
+		ID: "ep-users", Type: graph.NodeAPIEndpoint, Name: "GET /api/v1/users",
+		FilePath:   "backend/routes.py",
+		Properties: map[string]string{"path": "/api/v1/users", "http_method": "GET"},
+	})
+	if err := store.AddEdge(ctx, &graph.Edge{
+		ID: "consume-a", Type: graph.EdgeConsumes, SourceID: callA.ID, TargetID: "ep-users",
+	}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+
+	day2 := day1.Add(24 * time.Hour)
+	snap, err = l.UpdateBacklog(ctx, backlog, day2)
+	if err != nil {
+		t.Fatalf("UpdateBacklog: %v", err)
+	}
+	if snap.Open != 0 || snap.Resolved != 1 || snap.External != 1 || snap.New != 0 {
+		t.Errorf("snapshot = %+v, want open=0 resolved=1 external=1 new=0", snap)
+	}
+
+	a := backlog.Entries[keyA]
+	if a.Status != BacklogResolved || a.ResolvedAt == nil || !a.ResolvedAt.Equal(day2) {
+		t.Errorf("entry A = %+v, want resolved at day2", a)
+	}
+	if !a.FirstSeen.Equal(day1) {
+		t.Errorf("entry A FirstSeen = %v, want %v", a.FirstSeen, day1)
+	}
+	b := backlog.Entries[keyB]
+	if b.Status != BacklogExternal || b.Note != "stripe API" || !b.LastSeen.Equal(day2) {
+		t.Errorf("entry B = %+v, want external with note and LastSeen day2", b)
+	}
+	if len(backlog.History) != 2 {
+		t.Errorf("len(History) = %d, want 2", len(backlog.History))
+	}
+}
+
+func TestBacklogSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unresolved.json")
+
+	missing, err := LoadBacklog(path)
+	if err != nil {
+		t.Fatalf("LoadBacklog missing file: %v", err)
+	}
+	if len(missing.Entries) != 0 {
+		t.Errorf("expected empty backlog, got %d entries", len(missing.Entries))
+	}
+
+	now := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	original := &Backlog{
+		Entries: map[string]*BacklogEntry{
+			"a|GET /x": {Key: "a|GET /x", Path: "/x", Status: BacklogOpen, FirstSeen: now, LastSeen: now},
+			"b|GET /y": {Key: "b|GET /y", Path: "/y", Status: BacklogResolved, FirstSeen: now, LastSeen: now, ResolvedAt: &now},
+		},
+		History: []BacklogSnapshot{{Time: now, Open: 1, New: 1}},
+	}
+	if err := original.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), `"resolved_at"`); n != 1 {
+		t.Errorf("saved backlog has %d resolved_at fields, want 1 (open entries omit it)", n)
+	}
+	loaded, err := LoadBacklog(path)
+	if err != nil {
+		t.Fatalf("LoadBacklog: %v", err)
+	}
+	e := loaded.Entries["a|GET /x"]
+	if e == nil || e.Status != BacklogOpen || !e.FirstSeen.Equal(now) || e.ResolvedAt != nil {
+		t.Errorf("loaded entry = %+v", e)
+	}
+	if r := loaded.Entries["b|GET /y"]; r == nil || r.ResolvedAt == nil || !r.ResolvedAt.Equal(now) {
+		t.Errorf("loaded resolved entry = %+v", r)
+	}
+	old := `{"entries":{"c|GET /z":{"key":"c|GET /z","path":"/z","status":"open","resolved_at":"0001-01-01T00:00:00Z"}}}`
+	if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if old, err := LoadBacklog(path); err != nil || old.Entries["c|GET /z"].ResolvedAt != nil {
+		t.Errorf("zero resolved_at of an older backlog not cleared: %v", err)
+	}
+	if err := loaded.SetStatus("missing", BacklogExternal, "", now); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
@@ -0,0 +1,247 @@
+package linker
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// hostBase is a hostname, optionally with a base path, that a service group
+// is reachable at.
+type hostBase struct {
+	host  string
+	path  string
+	group string
+}
+
+// hostResolver maps the hosts of absolute and environment-based API call
+// URLs to service groups.
+type hostResolver struct {
+	// declared holds the hosts of declared services, longest base path
+	// first.
+	declared []hostBase
+	// hosts maps network hostnames (docker-compose service names) to
+	// groups.
+	hosts map[string]string
+	// envURLs holds env var producers whose value is an http(s) URL, by
+	// variable name.
+	envURLs map[string][]*graph.Node
+	byGroup map[string]EndpointIndex
+}
+
+// callBase is where an API call's URL was resolved to.
+type callBase struct {
+	host string
+	// path is the call path below the host.
+	path string
+	// group is the service group serving host, or "" when the host is not
+	// a known service (it may be a proxy in the route table).
+	group string
+	// env is the environment variable the host came from, if any.
+	env string
+	// guessed is set when the host was derived from the variable's name
+	// rather than a configured value.
+	guessed bool
+}
+
+// loadHosts builds the resolver from declared service hosts, host Config
+// nodes (see parser.ConfigKindHost) and env var producers with URL values.
+func (l *Linker) loadHosts(ctx context.Context, byGroup map[string]EndpointIndex) (*hostResolver, error) {
+	r := &hostResolver{
+		hosts:   make(map[string]string),
+		envURLs: make(map[string][]*graph.Node),
+		byGroup: byGroup,
+	}
+	if l.services != nil {
+		for _, def := range l.services.defs {
+			for _, h := range def.Hosts {
+				hb := hostBase{group: def.Name}
+				if host, p, ok := parser.SplitURL(h); ok {
+					hb.host, hb.path = host, normalizeURLPath(p)
+				} else {
+					hb.host, hb.path = parser.UpstreamHost(h), "/"
+				}
+				if hb.host != "" {
+					r.declared = append(r.declared, hb)
+				}
+			}
+		}
+		sort.SliceStable(r.declared, func(i, j int) bool {
+			return len(r.declared[i].path) > len(r.declared[j].path)
+		})
+	}
+
+	hosts, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeConfig,
+		Properties: map[string]string{"kind": parser.ConfigKindHost},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range hosts {
+		if ctxDir := n.Properties["build_context"]; ctxDir != "" {
+			if g := l.group(ctxDir + "/"); g != "" {
+				r.hosts[strings.ToLower(n.Name)] = g
+			}
+		}
+	}
+
+	producers, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type: graph.NodeConfig,
+		Properties: map[string]string{
+			"kind": parser.ConfigKindEnvVar,
+			"role": parser.ConfigRoleProducer,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range producers {
+		if n.Properties[parser.EnvPropURLHost] != "" {
+			r.envURLs[n.Name] = append(r.envURLs[n.Name], n)
+		}
+	}
+	return r, nil
+}
+
+// resolve returns the host an API call is sent to and its path there, or
+// nil when the call uses a relative URL or its base cannot be resolved.
+// Hosts that are not a known service are kept only when they come from a
+// configured env var URL, since they may be a gateway in the route table.
+func (r *hostResolver) resolve(l *Linker, call *graph.Node) *callBase {
+	if r == nil {
+		return nil
+	}
+	callPath := call.Properties["path"]
+	var b *callBase
+	if host, p, ok := parser.SplitURL(callPath); ok {
+		b = &callBase{host: host, path: p}
+	} else if expr := call.Properties[parser.APICallPropBase]; expr != "" && strings.HasPrefix(callPath, "*") {
+		b = r.resolveEnv(l, call, expr, callPath[1:])
+	}
+	if b == nil {
+		return nil
+	}
+	b.group, b.path = r.hostGroup(b.host, b.path)
+	if b.group == "" && (b.env == "" || b.guessed) {
+		// Only a configured URL may point at a proxy; other unknown hosts
+		// are external services.
+		return nil
+	}
+	return b
+}
+
+// resolveEnv resolves a URL base expression through the environment
+// variables it names: their configured URL values first, then service names
+// derived from the variable names (PAYMENTS_URL → payments).
+func (r *hostResolver) resolveEnv(l *Linker, call *graph.Node, expr, rest string) *callBase {
+	names := envNameCandidates(expr)
+	for _, name := range names {
+		producers := r.envURLs[name]
+		if len(producers) == 0 {
+			continue
+		}
+		selected, _ := l.selectProducers(call, producers)
+		p := selected[0]
+		return &callBase{
+			host: p.Properties[parser.EnvPropURLHost],
+			path: strings.TrimRight(p.Properties[parser.EnvPropURLPath], "/") + rest,
+			env:  name,
+		}
+	}
+	for _, name := range names {
+		if host := hostFromEnvName(name); host != "" {
+			return &callBase{host: host, path: rest, env: name, guessed: true}
+		}
+	}
+	return nil
+}
+
+// hostGroup returns the service group serving host and the path below the
+// group's base path. Declared service hosts are tried first, then compose
+// hostnames, then a group named like the host.
+func (r *hostResolver) hostGroup(host, p string) (string, string) {
+	norm := normalizeURLPath(p)
+	for _, hb := range r.declared {
+		if hb.host != host {
+			continue
+		}
+		if rest, ok := routeRemainder(norm, hb.path); ok {
+			if hb.path == "/" {
+				return hb.group, p
+			}
+			return hb.group, rest
+		}
+	}
+	if g, ok := r.hosts[host]; ok {
+		return g, p
+	}
+	for _, name := range upstreamGroupNames(host) {
+		if _, ok := r.byGroup[name]; ok {
+			return name, p
+		}
+	}
+	return "", p
+}
+
+// identPattern matches identifiers and the contents of string literals in a
+// base expression.
+var identPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// envNameCandidates returns the names in a base expression that may be
+// environment variables, most specific (rightmost) first, e.g. API_URL for
+// process.env.API_URL or os.Getenv("API_URL"). camelCase names are also
+// tried in SCREAMING_SNAKE form, so paymentsBaseUrl yields
+// PAYMENTS_BASE_URL.
+func envNameCandidates(expr string) []string {
+	idents := identPattern.FindAllString(expr, -1)
+	var names []string
+	seen := make(map[string]bool)
+	for i := len(idents) - 1; i >= 0; i-- {
+		for _, name := range []string{idents[i], screamingSnake(idents[i])} {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// screamingSnake converts a camelCase identifier to SCREAMING_SNAKE_CASE.
+func screamingSnake(s string) string {
+	var b strings.Builder
+	for i, c := range s {
+		if unicode.IsUpper(c) && i > 0 && unicode.IsLower(rune(s[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
+}
+
+// envURLSuffixes are the suffixes that mark a variable as holding a
+// service's address, longest first.
+var envURLSuffixes = []string{
+	"_SERVICE_URL", "_BASE_URL", "_API_URL", "_ENDPOINT", "_ADDRESS",
+	"_ADDR", "_HOST", "_URL", "_URI",
+}
+
+// genericEnvPrefixes name no particular service (BASE_URL, SERVER_HOST).
+var genericEnvPrefixes = map[string]bool{"BASE": true, "SERVER": true, "APP": true}
+
+// hostFromEnvName derives the service host a variable like PAYMENTS_URL
+// names ("payments"), or "" for names without an address suffix.
+func hostFromEnvName(name string) string {
+	for _, suffix := range envURLSuffixes {
+		if svc, ok := strings.CutSuffix(name, suffix); ok && svc != "" && svc == strings.ToUpper(svc) && !genericEnvPrefixes[svc] {
+			return strings.ToLower(strings.ReplaceAll(svc, "_", "-"))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package linker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkAPICallsByBaseURL(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	billingEp := graph.NewNodeID("APIEndpoint", "billing/routes.go", "GET /entries/{id}")
+	ledgerEp := graph.NewNodeID("APIEndpoint", "ledger/routes.go", "GET /entries/{id}")
+	chargesEp := graph.NewNodeID("APIEndpoint", "payments-api/app.py", "POST /v1/charges")
+	billingCall := graph.NewNodeID("Dependency", "frontend/src/api.ts", "fetch billing")
+	chargeCall := graph.NewNodeID("Dependency", "frontend/src/api.ts", "fetch charges")
+	ledgerCall := graph.NewNodeID("Dependency", "frontend/src/api.ts", "fetch ledger")
+
+	endpoint := func(id, file, path string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeAPIEndpoint, Name: path, FilePath: file,
+			Properties: map[string]string{"path": path}}
+	}
+	call := func(id, path, base string) *graph.Node {
+		props := map[string]string{"kind": "api_call", "path": path}
+		if base != "" {
+			props[parser.APICallPropBase] = base
+		}
+		return &graph.Node{ID: id, Type: graph.NodeDependency, Name: path, FilePath: "frontend/src/api.ts",
+			Properties: props}
+	}
+
+	addNodes(t, store,
+		endpoint(billingEp, "billing/routes.go", "/entries/{id}"),
+		endpoint(ledgerEp, "ledger/routes.go", "/entries/{id}"),
+		endpoint(chargesEp, "payments-api/app.py", "/v1/charges"),
+		call(billingCall, "https://billing.internal/v1/entries/*", ""),
+		call(chargeCall, "*/charges", "process.env.PAYMENTS_URL"),
+		call(ledgerCall, "*/entries/*", "LEDGER_URL"),
+		&graph.Node{ID: "env", Type: graph.NodeConfig, Name: "PAYMENTS_URL", FilePath: "docker-compose.yml",
+			Properties: map[string]string{
+				"kind": parser.ConfigKindEnvVar, "role": parser.ConfigRoleProducer, "service": "frontend",
+				parser.EnvPropURLHost: "payments", parser.EnvPropURLPath: "/v1",
+			}},
+		&graph.Node{ID: "host", Type: graph.NodeConfig, Name: "payments", FilePath: "docker-compose.yml",
+			Properties: map[string]string{"kind": parser.ConfigKindHost, "build_context": "payments-api"}},
+	)
+
+	l := NewLinker(store, nil, nil, false)
+	l.SetServiceMap(NewServiceMap([]ServiceDefinition{
+		{Name: "billing", Roots: []string{"billing"}, Hosts: []string{"https://billing.internal/v1"}},
+	}, false))
+	count, err := l.linkAPICalls(ctx)
+	if err != nil {
+		t.Fatalf("linkAPICalls: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkAPICalls returned %d, want 3", count)
+	}
+
+	tests := []struct {
+		call, endpoint, host, env string
+		confidence                string
+	}{
+		{billingCall, billingEp, "billing.internal", "", graph.ConfidenceExact},
+		{chargeCall, chargesEp, "payments", "PAYMENTS_URL", graph.ConfidenceExact},
+		{ledgerCall, ledgerEp, "ledger", "LEDGER_URL", graph.ConfidenceHeuristic},
+	}
+	for _, tt := range tests {
+		edges, err := store.GetEdges(ctx, tt.call, graph.EdgeConsumes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(edges) != 1 {
+			t.Fatalf("call %s: got %d Consumes edges, want 1", tt.call, len(edges))
+		}
+		e := edges[0]
+		if e.TargetID != tt.endpoint {
+			t.Errorf("call %s consumes %s, want %s", tt.call, e.TargetID, tt.endpoint)
+		}
+		if e.Properties["base_host"] != tt.host || e.Properties["base_env"] != tt.env {
+			t.Errorf("call %s base = %q/%q, want %q/%q", tt.call,
+				e.Properties["base_host"], e.Properties["base_env"], tt.host, tt.env)
+		}
+		if e.Properties["confidence"] != tt.confidence {
+			t.Errorf("call %s confidence = %q, want %q", tt.call, e.Properties["confidence"], tt.confidence)
+		}
+	}
+}
+
+func TestEnvNameCandidates(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+		host string
+	}{
+		{"process.env.PAYMENTS_URL", []string{"PAYMENTS_URL", "env", "ENV", "process", "PROCESS"}, "payments"},
+		{`os.Getenv("ORDER_SERVICE_URL")`, []string{"ORDER_SERVICE_URL", "Getenv", "GETENV", "os", "OS"}, "order"},
+		{"inventoryBaseUrl", []string{"inventoryBaseUrl", "INVENTORY_BASE_URL"}, "inventory"},
+		{"baseURL", []string{"baseURL", "BASE_URL"}, ""},
+	}
+	for _, tt := range tests {
+		got := envNameCandidates(tt.expr)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("envNameCandidates(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+		host := ""
+		for _, name := range got {
+			if host = hostFromEnvName(name); host != "" {
+				break
+			}
+		}
+		if host != tt.host {
+			t.Errorf("host for %q = %q, want %q", tt.expr, host, tt.host)
+		}
+	}
+}
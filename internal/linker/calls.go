@@ -13,7 +13,9 @@ import (
 // The Go parser stores unresolved direct calls (functions not found in the same
 // file) as Properties["unresolved_calls"] on function/method nodes. This phase
 // looks up those names across all functions in the same package and creates
-// Calls edges for matches.
+// Calls edges for matches. Endpoints whose handler is declared in another file
+// of the package, and scheduled jobs whose handler is, carry the handler name
+// the same way.
 func (l *Linker) linkCalls(ctx context.Context) (int, error) {
 	// Query all Go callable nodes (functions, test functions, methods).
 	funcs, err := l.store.QueryNodes(ctx, graph.NodeFilter{
@@ -38,6 +40,21 @@ func (l *Linker) linkCalls(ctx context.Context) (int, error) {
 		return 0, err
 	}
 
+	endpoints, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:     graph.NodeAPIEndpoint,
+		Language: "go",
+	})
+	if err != nil {
+		return 0, err
+	}
+	jobs, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:     graph.NodeJob,
+		Language: "go",
+	})
+	if err != nil {
+		return 0, err
+	}
+
 	// Merge all callable nodes.
 	allCallable := make([]*graph.Node, 0, len(funcs)+len(testFuncs)+len(methods))
 	allCallable = append(allCallable, funcs...)
@@ -54,15 +71,17 @@ func (l *Linker) linkCalls(ctx context.Context) (int, error) {
 		if pkgFuncMap[n.Package] == nil {
 			pkgFuncMap[n.Package] = make(map[string][]*graph.Node)
 		}
-		// Index functions/test functions by Name.
-		if n.Type == graph.NodeFunction || n.Type == graph.NodeTestFunction {
+		// Index functions/test functions by Name. Closures are local to
+		// the function declaring them.
+		if (n.Type == graph.NodeFunction || n.Type == graph.NodeTestFunction) && n.Properties["anonymous"] != "true" {
 			pkgFuncMap[n.Package][n.Name] = append(pkgFuncMap[n.Package][n.Name], n)
 		}
 	}
 
 	// Find nodes with unresolved calls and resolve them.
 	linked := 0
-	for _, caller := range allCallable {
+	callers := append(append(allCallable, endpoints...), jobs...)
+	for _, caller := range callers {
 		unresolvedStr, ok := caller.Properties["unresolved_calls"]
 		if !ok || unresolvedStr == "" {
 			continue
@@ -76,32 +95,46 @@ func (l *Linker) linkCalls(ctx context.Context) (int, error) {
 
 		resolved := false
 		for _, name := range names {
-			candidates := funcMap[name]
+			// Platform variants the caller is never built with are not
+			// candidates.
+			var candidates []*graph.Node
+			for _, c := range funcMap[name] {
+				if graph.BuildTogether(caller, c) {
+					candidates = append(candidates, c)
+				}
+			}
 			if len(candidates) == 0 {
 				continue
 			}
 
 			// Pick the best match: prefer different file (that's the whole point),
-			// then prefer same directory.
-			target := pickCallTarget(caller, candidates)
-			if target == nil || target.ID == caller.ID {
-				continue
+			// then prefer same directory. Platform variants of one function
+			// (foo_linux.go, foo_windows.go) are all called.
+			targets := platformVariants(caller, candidates)
+			level, score := graph.ConfidenceExact, scoreExact
+			if targets == nil {
+				targets = []*graph.Node{pickCallTarget(caller, candidates)}
+				level, score = nameMatchConfidence(len(candidates))
 			}
-
-			edge := &graph.Edge{
-				ID:       graph.NewNodeID(string(graph.EdgeCalls), caller.ID, target.ID),
-				Type:     graph.EdgeCalls,
-				SourceID: caller.ID,
-				TargetID: target.ID,
-				Properties: map[string]string{
-					"kind": "cross_file",
-				},
+			for _, target := range targets {
+				if target == nil || target.ID == caller.ID {
+					continue
+				}
+				edge := &graph.Edge{
+					ID:       graph.NewEdgeID(graph.EdgeCalls, caller.ID, target.ID),
+					Type:     graph.EdgeCalls,
+					SourceID: caller.ID,
+					TargetID: target.ID,
+					Properties: withConfidence(map[string]string{
+						"kind": "cross_file",
+					}, level, score),
+				}
+				if err := l.store.AddEdge(ctx, edge); err != nil {
+					continue
+				}
+				linked++
+				resolved = true
 			}
-			if err := l.store.AddEdge(ctx, edge); err != nil {
-				continue
-			}
-			linked++
-			resolved = true
 		}
 
 		// Clear the unresolved_calls property after resolution.
@@ -142,3 +175,26 @@ func pickCallTarget(caller *graph.Node, candidates []*graph.Node) *graph.Node {
 	}
 	return diffFile[0]
 }
+
+// platformVariants returns the candidates in other files when there are
+// several and no two of them are built together: they are variants of one
+// function for different platforms or build tags. It returns nil otherwise.
+func platformVariants(caller *graph.Node, candidates []*graph.Node) []*graph.Node {
+	var variants []*graph.Node
+	for _, c := range candidates {
+		if c.FilePath != caller.FilePath {
+			variants = append(variants, c)
+		}
+	}
+	if len(variants) < 2 {
+		return nil
+	}
+	for i, a := range variants {
+		for _, b := range variants[i+1:] {
+			if graph.BuildTogether(a, b) {
+				return nil
+			}
+		}
+	}
+	return variants
+}
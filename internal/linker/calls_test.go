@@ -2,6 +2,8 @@ package linker
 
 import (
 	"context"
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
@@ -254,3 +256,163 @@ func TestPickCallTarget(t *testing.T) {
 		}
 	})
 }
+
+func TestLinkCalls_EndpointHandler(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// A route registered in routes.go whose handler lives in handlers.go.
+	endpoint := &graph.Node{
+		ID:       graph.NewNodeID(string(graph.NodeAPIEndpoint), "api/routes.go", "GET:/users"),
+		Type:     graph.NodeAPIEndpoint,
+		Name:     "GET /users",
+		FilePath: "api/routes.go",
+		Package:  "api",
+		Language: "go",
+		Properties: map[string]string{
+			"handler":          "listUsers",
+			"unresolved_calls": "listUsers",
+		},
+	}
+	handler := &graph.Node{
+		ID:       graph.NewNodeID(string(graph.NodeFunction), "api/handlers.go", "listUsers"),
+		Type:     graph.NodeFunction,
+		Name:     "listUsers",
+		FilePath: "api/handlers.go",
+		Package:  "api",
+		Language: "go",
+	}
+	addNodes(t, store, endpoint, handler)
+
+	linker := NewLinker(store, nil, nil, false)
+	count, err := linker.linkCalls(ctx)
+	if err != nil {
+		t.Fatalf("linkCalls: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 linked call, got %d", count)
+	}
+
+	edges, err := store.GetEdges(ctx, endpoint.ID, graph.EdgeCalls)
+	if err != nil {
+		t.Fatalf("GetEdges: %v", err)
+	}
+	if len(edges) != 1 || edges[0].SourceID != endpoint.ID || edges[0].TargetID != handler.ID {
+		t.Errorf("expected Calls edge from endpoint to handler, got %+v", edges)
+	}
+}
+
+func TestLinkCalls_JobHandler(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// A cron job registered in scheduler.go whose handler lives in cleanup.go.
+	job := &graph.Node{
+		ID:       graph.NewNodeID(string(graph.NodeJob), "jobs/scheduler.go", "cleanup:12"),
+		Type:     graph.NodeJob,
+		Name:     "cleanup",
+		FilePath: "jobs/scheduler.go",
+		Package:  "jobs",
+		Language: "go",
+		Properties: map[string]string{
+			"handler":          "cleanup",
+			"unresolved_calls": "cleanup",
+		},
+	}
+	handler := &graph.Node{
+		ID:       graph.NewNodeID(string(graph.NodeFunction), "jobs/cleanup.go", "cleanup"),
+		Type:     graph.NodeFunction,
+		Name:     "cleanup",
+		FilePath: "jobs/cleanup.go",
+		Package:  "jobs",
+		Language: "go",
+	}
+	addNodes(t, store, job, handler)
+
+	linker := NewLinker(store, nil, nil, false)
+	count, err := linker.linkCalls(ctx)
+	if err != nil {
+		t.Fatalf("linkCalls: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 linked call, got %d", count)
+	}
+
+	edges, err := store.GetEdges(ctx, job.ID, graph.EdgeCalls)
+	if err != nil {
+		t.Fatalf("GetEdges: %v", err)
+	}
+	if len(edges) != 1 || edges[0].SourceID != job.ID || edges[0].TargetID != handler.ID {
+		t.Errorf("expected Calls edge from job to handler, got %+v", edges)
+	}
+}
+
+func TestLinkCalls_PlatformVariants(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	fn := func(file, name, buildConstraint, calls string) *graph.Node {
+		n := &graph.Node{
+			ID:         graph.NewNodeID(string(graph.NodeFunction), file, name),
+			Type:       graph.NodeFunction,
+			Name:       name,
+			FilePath:   file,
+			Package:    "fs",
+			Language:   "go",
+			Properties: map[string]string{},
+		}
+		if buildConstraint != "" {
+			n.Properties[graph.PropBuildConstraint] = buildConstraint
+		}
+		if calls != "" {
+			n.Properties["unresolved_calls"] = calls
+		}
+		return n
+	}
+	openLinux := fn("fs/open_linux.go", "openFile", "linux", "")
+	openWindows := fn("fs/open_windows.go", "openFile", "windows", "")
+	// Portable caller: both variants are called, neither is a guess.
+	open := fn("fs/fs.go", "Open", "", "openFile")
+	// Linux-only caller: the Windows variant is never built with it.
+	watch := fn("fs/watch_linux.go", "Watch", "linux", "openFile")
+	addNodes(t, store, openLinux, openWindows, open, watch)
+
+	linker := NewLinker(store, nil, nil, false)
+	count, err := linker.linkCalls(ctx)
+	if err != nil {
+		t.Fatalf("linkCalls: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 linked calls, got %d", count)
+	}
+
+	tests := []struct {
+		caller *graph.Node
+		want   []string
+	}{
+		{open, []string{openLinux.ID, openWindows.ID}},
+		{watch, []string{openLinux.ID}},
+	}
+	for _, tt := range tests {
+		edges, err := store.GetEdges(ctx, tt.caller.ID, graph.EdgeCalls)
+		if err != nil {
+			t.Fatalf("GetEdges: %v", err)
+		}
+		var got []string
+		for _, e := range edges {
+			if e.SourceID != tt.caller.ID {
+				continue
+			}
+			got = append(got, e.TargetID)
+			if e.Properties[graph.PropConfidence] != graph.ConfidenceExact {
+				t.Errorf("%s -> %s confidence = %q, want exact", tt.caller.Name, e.TargetID, e.Properties[graph.PropConfidence])
+			}
+		}
+		sort.Strings(got)
+		want := append([]string(nil), tt.want...)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s calls %v, want %v", tt.caller.Name, got, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package linker
+
+import (
+	"strconv"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Confidence scores for linker-created edges. Every edge a phase creates
+// records how it was resolved (graph.PropConfidence) and a score
+// (graph.PropConfidenceScore) so consumers can drop weak links.
+const (
+	scoreExact  = 1.0 // unambiguous rule: path resolution, declared name, single candidate
+	scoreStrong = 0.8 // heuristic with corroborating evidence
+	scoreMedium = 0.6 // heuristic choice among several candidates, naming conventions
+	scoreWeak   = 0.4 // bare name or text mention
+)
+
+// withConfidence records a confidence level and score in props, creating
+// the map if needed, and returns it.
+func withConfidence(props map[string]string, level string, score float64) map[string]string {
+	if props == nil {
+		props = make(map[string]string)
+	}
+	props[graph.PropConfidence] = level
+	props[graph.PropConfidenceScore] = strconv.FormatFloat(score, 'f', 2, 64)
+	return props
+}
+
+// nameMatchConfidence rates a match made by name: exact when the name
+// identified a single candidate, ambiguous otherwise.
+func nameMatchConfidence(candidates int) (string, float64) {
+	if candidates <= 1 {
+		return graph.ConfidenceExact, scoreExact
+	}
+	return graph.ConfidenceHeuristic, scoreMedium
+}
+
+// llmConfidenceScore maps an LLM's self-reported confidence to a score.
+func llmConfidenceScore(reported string) float64 {
+	switch reported {
+	case "high":
+		return 0.7
+	case "medium":
+		return 0.5
+	default:
+		return 0.3
+	}
+}
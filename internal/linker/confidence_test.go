@@ -0,0 +1,91 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestAPICallEdgeConfidence(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	call := func(id, path string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeDependency, Name: "GET " + path, FilePath: "web/api.ts",
+			Properties: map[string]string{"kind": "api_call", "http_method": "GET", "path": path}}
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "ep-users", Type: graph.NodeAPIEndpoint, Name: "GET /api/users/{id}", FilePath: "api/routes.go",
+			Properties: map[string]string{"http_method": "GET", "path": "/api/users/{id}"}},
+		&graph.Node{ID: "ep-health", Type: graph.NodeAPIEndpoint, Name: "GET /healthz", FilePath: "api/routes.go",
+			Properties: map[string]string{"http_method": "GET", "path": "/healthz"}},
+		call("call-exact", "/api/users/:id"),
+		call("call-wildcard", "/api/users/42"),
+		call("call-suffix", "/gateway/healthz"),
+	)
+
+	if _, err := NewLinker(store, nil, nil, false).linkAPICalls(ctx); err != nil {
+		t.Fatalf("linkAPICalls: %v", err)
+	}
+
+	tests := []struct {
+		call  string
+		level string
+		score float64
+	}{
+		{"call-exact", graph.ConfidenceExact, 1},
+		{"call-wildcard", graph.ConfidenceHeuristic, 0.8},
+		{"call-suffix", graph.ConfidenceHeuristic, 0.6},
+	}
+	for _, tt := range tests {
+		edges, err := store.GetEdges(ctx, tt.call, graph.EdgeConsumes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(edges) != 1 {
+			t.Errorf("%s: got %d Consumes edges, want 1", tt.call, len(edges))
+			continue
+		}
+		e := edges[0]
+		if e.Properties[graph.PropConfidence] != tt.level || graph.EdgeConfidence(e) != tt.score {
+			t.Errorf("%s: confidence = %s %v, want %s %v", tt.call,
+				e.Properties[graph.PropConfidence], graph.EdgeConfidence(e), tt.level, tt.score)
+		}
+	}
+}
+
+func TestFilterEdgesByConfidence(t *testing.T) {
+	edges := []*graph.Edge{
+		{ID: "parser"},
+		{ID: "exact", Properties: withConfidence(nil, graph.ConfidenceExact, scoreExact)},
+		{ID: "weak", Properties: withConfidence(nil, graph.ConfidenceHeuristic, scoreWeak)},
+		{ID: "llm", Properties: withConfidence(nil, graph.ConfidenceLLM, llmConfidenceScore("medium"))},
+		{ID: "legacy-llm", Properties: map[string]string{"inferred": "true", "confidence": "high"}},
+	}
+	tests := []struct {
+		min  float64
+		want []string
+	}{
+		{0, []string{"parser", "exact", "weak", "llm", "legacy-llm"}},
+		{0.5, []string{"parser", "exact", "llm", "legacy-llm"}},
+		{0.9, []string{"parser", "exact"}},
+	}
+	for _, tt := range tests {
+		got := graph.FilterEdgesByConfidence(edges, tt.min)
+		var ids []string
+		for _, e := range got {
+			ids = append(ids, e.ID)
+		}
+		if len(ids) != len(tt.want) {
+			t.Errorf("min %v: got %v, want %v", tt.min, ids, tt.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("min %v: got %v, want %v", tt.min, ids, tt.want)
+				break
+			}
+		}
+	}
+}
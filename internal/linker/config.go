@@ -0,0 +1,119 @@
+package linker
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkConfig matches environment variables set by deployment config
+// (docker-compose, Kubernetes; role=producer) to the code reading them
+// (role=consumer), creating Configures edges from producer to consumer
+// Config nodes. A producer whose compose build context or container name
+// matches the consumer's service is preferred; otherwise every producer of
+// the variable is linked.
+//
+// Variables read by more than one service are implicit coupling: changing
+// one deployment's value affects the others. Each such consumer gets a
+// "shared_with" property listing the other services reading the variable.
+func (l *Linker) linkConfig(ctx context.Context) (int, error) {
+	configs, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeConfig,
+		Properties: map[string]string{"kind": parser.ConfigKindEnvVar},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	producers := make(map[string][]*graph.Node)
+	consumers := make(map[string][]*graph.Node)
+	for _, c := range configs {
+		switch c.Properties["role"] {
+		case parser.ConfigRoleProducer:
+			producers[c.Name] = append(producers[c.Name], c)
+		case parser.ConfigRoleConsumer:
+			consumers[c.Name] = append(consumers[c.Name], c)
+		}
+	}
+
+	linked := 0
+	for name, readers := range consumers {
+		for _, consumer := range readers {
+			selected, sameService := l.selectProducers(consumer, producers[name])
+			level, score := graph.ConfidenceExact, scoreExact
+			if !sameService {
+				level, score = graph.ConfidenceHeuristic, scoreStrong
+				if len(selected) > 1 {
+					score = scoreMedium
+				}
+			}
+			for _, producer := range selected {
+				edge := &graph.Edge{
+					ID:       graph.NewEdgeID(graph.EdgeConfigures, producer.ID, consumer.ID),
+					Type:     graph.EdgeConfigures,
+					SourceID: producer.ID,
+					TargetID: consumer.ID,
+					Properties: withConfidence(map[string]string{
+						"kind":    parser.ConfigKindEnvVar,
+						"service": producer.Properties["service"],
+					}, level, score),
+				}
+				if err := l.store.AddEdge(ctx, edge); err != nil {
+					continue
+				}
+				linked++
+
+				if l.verbose {
+					l.log("    Env: %s (%s) -> %s", name, producer.FilePath, consumer.FilePath)
+				}
+			}
+		}
+
+		groups := make(map[string]bool)
+		for _, consumer := range readers {
+			if g := l.group(consumer.FilePath); g != "" {
+				groups[g] = true
+			}
+		}
+		if len(groups) < 2 {
+			continue
+		}
+		for _, consumer := range readers {
+			own := l.group(consumer.FilePath)
+			var others []string
+			for g := range groups {
+				if g != own {
+					others = append(others, g)
+				}
+			}
+			sort.Strings(others)
+			consumer.Properties["shared_with"] = strings.Join(others, ",")
+			if err := l.store.UpdateNode(ctx, consumer); err != nil && l.verbose {
+				l.log("  Warning: update %s: %v", consumer.ID, err)
+			}
+		}
+	}
+	return linked, nil
+}
+
+// selectProducers returns the producers that configure the consumer's
+// service, or all producers when none can be tied to it; the bool reports
+// whether they were tied to the service.
+func (l *Linker) selectProducers(consumer *graph.Node, producers []*graph.Node) ([]*graph.Node, bool) {
+	group := l.group(consumer.FilePath)
+	var matched []*graph.Node
+	for _, p := range producers {
+		if ctxDir := p.Properties["build_context"]; ctxDir != "" && l.group(ctxDir+"/") == group {
+			matched = append(matched, p)
+		} else if p.Properties["service"] == group {
+			matched = append(matched, p)
+		}
+	}
+	if len(matched) > 0 {
+		return matched, true
+	}
+	return producers, false
+}
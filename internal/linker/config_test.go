@@ -0,0 +1,71 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkConfig(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	env := func(id, name, file, role string, props map[string]string) *graph.Node {
+		p := map[string]string{"kind": "env_var", "role": role}
+		for k, v := range props {
+			p[k] = v
+		}
+		return &graph.Node{ID: id, Type: graph.NodeConfig, Name: name, FilePath: file, Properties: p}
+	}
+	addNodes(t, store,
+		// docker-compose sets DATABASE_URL for api and worker; k8s sets it for billing.
+		env("p-api", "DATABASE_URL", "docker-compose.yml", "producer", map[string]string{"service": "api", "build_context": "api"}),
+		env("p-worker", "DATABASE_URL", "docker-compose.yml", "producer", map[string]string{"service": "jobs", "build_context": "worker"}),
+		env("p-billing", "DATABASE_URL", "k8s/billing.yaml", "producer", map[string]string{"service": "billing"}),
+		env("p-log", "LOG_LEVEL", "docker-compose.yml", "producer", map[string]string{"service": "api", "build_context": "api"}),
+		env("c-api", "DATABASE_URL", "api/db.go", "consumer", nil),
+		env("c-worker", "DATABASE_URL", "worker/db.py", "consumer", nil),
+		env("c-billing", "DATABASE_URL", "billing/src/db.ts", "consumer", nil),
+		// No producer tied to the reports service: linked to every producer.
+		env("c-log", "LOG_LEVEL", "reports/main.go", "consumer", nil),
+		env("c-unset", "FEATURE_X", "api/flags.go", "consumer", nil),
+	)
+
+	linker := NewLinker(store, nil, nil, false)
+	count, err := linker.linkConfig(ctx)
+	if err != nil {
+		t.Fatalf("linkConfig: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("linkConfig returned %d, want 4", count)
+	}
+
+	want := map[string]string{"c-api": "p-api", "c-worker": "p-worker", "c-billing": "p-billing", "c-log": "p-log", "c-unset": ""}
+	for consumer, producer := range want {
+		edges, err := store.GetEdges(ctx, consumer, graph.EdgeConfigures)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range edges {
+			if e.TargetID == consumer {
+				got = append(got, e.SourceID)
+			}
+		}
+		if (producer == "" && len(got) != 0) || (producer != "" && (len(got) != 1 || got[0] != producer)) {
+			t.Errorf("%s producers = %v, want %q", consumer, got, producer)
+		}
+	}
+
+	n, err := store.GetNode(ctx, "c-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Properties["shared_with"]; got != "billing,worker" {
+		t.Errorf("c-api shared_with = %q, want billing,worker", got)
+	}
+	if n, _ := store.GetNode(ctx, "c-log"); n.Properties["shared_with"] != "" {
+		t.Errorf("c-log shared_with = %q, want empty", n.Properties["shared_with"])
+	}
+}
@@ -0,0 +1,129 @@
+package linker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkCSharpImplements resolves the interfaces C# classes and structs
+// implement by qualified name. The parser only sees one file: it guesses
+// interfaces by their "I" prefix and points Implements edges at an
+// Unresolved node unless the interface is declared in the same file. Here
+// each base type (Properties["implements"], and Properties["extends"] in
+// case the guess took an interface for the base class) is looked up the way
+// the compiler does: in the enclosing namespaces, innermost first, then in
+// the namespaces the file imports with using directives. A resolved
+// interface gets an Implements edge (kind=namespace) replacing the parser's
+// edge to the Unresolved node. Generic arguments and global:: are ignored;
+// names imported from several namespaces are ambiguous and resolved by
+// bestMatch with heuristic confidence.
+func (l *Linker) linkCSharpImplements(ctx context.Context) (int, error) {
+	interfaces, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeInterface, Language: "csharp"})
+	if err != nil {
+		return 0, err
+	}
+	if len(interfaces) == 0 {
+		return 0, nil
+	}
+	byQualified := make(map[string][]*graph.Node)
+	for _, iface := range interfaces {
+		byQualified[iface.QualifiedName] = append(byQualified[iface.QualifiedName], iface)
+	}
+
+	usings, err := l.fileImports(ctx, "csharp")
+	if err != nil {
+		return 0, err
+	}
+
+	var types []*graph.Node
+	for _, t := range []graph.NodeType{graph.NodeClass, graph.NodeStruct} {
+		nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: t, Language: "csharp"})
+		if err != nil {
+			return 0, err
+		}
+		types = append(types, nodes...)
+	}
+
+	linked := 0
+	for _, typ := range types {
+		var bases []string
+		for _, b := range strings.Split(typ.Properties["implements"], ",") {
+			if b = strings.TrimSpace(b); b != "" {
+				bases = append(bases, b)
+			}
+		}
+		if ext := typ.Properties["extends"]; ext != "" {
+			bases = append(bases, ext)
+		}
+
+		for _, base := range bases {
+			candidates := resolveCSharpType(byQualified, typ.Package, usings[typ.FilePath], base)
+			target := l.bestMatch(typ, candidates)
+			if target == nil || target.ID == typ.ID {
+				continue
+			}
+			level, score := nameMatchConfidence(len(candidates))
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeImplements, typ.ID, target.ID),
+				Type:       graph.EdgeImplements,
+				SourceID:   typ.ID,
+				TargetID:   target.ID,
+				Properties: withConfidence(map[string]string{"kind": "namespace"}, level, score),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+
+			l.unbind(ctx, graph.EdgeImplements, typ.ID, parser.Unresolved{TargetType: graph.NodeInterface, Name: base, FilePath: typ.FilePath})
+
+			if l.verbose {
+				l.log("    C# implements: %s -> %s", typ.QualifiedName, target.QualifiedName)
+			}
+		}
+	}
+	return linked, nil
+}
+
+// resolveCSharpType returns the interfaces a type name written in namespace
+// ns, in a file with the given using directives, can refer to: those found
+// in the innermost enclosing namespace declaring the name or, failing that,
+// every one imported by a using directive. Qualified names are only looked
+// up relative to the enclosing namespaces, as using directives import types,
+// not nested namespaces.
+func resolveCSharpType(byQualified map[string][]*graph.Node, ns string, usings []string, name string) []*graph.Node {
+	name = strings.TrimPrefix(name, "global::")
+	if i := strings.IndexByte(name, '<'); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+
+	for scope := ns; ; {
+		qualified := name
+		if scope != "" {
+			qualified = scope + "." + name
+		}
+		if found := byQualified[qualified]; len(found) > 0 {
+			return found
+		}
+		if scope == "" {
+			break
+		}
+		scope = scope[:max(strings.LastIndexByte(scope, '.'), 0)]
+	}
+
+	if strings.Contains(name, ".") {
+		return nil
+	}
+	var found []*graph.Node
+	for _, u := range usings {
+		found = append(found, byQualified[u+"."+name]...)
+	}
+	return found
+}
@@ -0,0 +1,104 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkCSharpImplements(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	const file = "shop/orders/OrderRepository.cs"
+	cs := func(n *graph.Node) *graph.Node { n.Language = "csharp"; return n }
+	using := graph.NewDependencyID("csharp", "Contracts")
+	addNodes(t, store,
+		cs(&graph.Node{ID: "file", Type: graph.NodeFile, Name: "OrderRepository.cs", FilePath: file}),
+		cs(&graph.Node{ID: using, Type: graph.NodeDependency, Name: "Contracts", Properties: map[string]string{"kind": "import"}}),
+		cs(&graph.Node{ID: "repo", Type: graph.NodeInterface, Name: "IRepository", QualifiedName: "Contracts.IRepository", Package: "Contracts", FilePath: "contracts/IRepository.cs"}),
+		cs(&graph.Node{ID: "other-repo", Type: graph.NodeInterface, Name: "IRepository", QualifiedName: "Legacy.IRepository", Package: "Legacy", FilePath: "legacy/IRepository.cs"}),
+		cs(&graph.Node{ID: "auditable", Type: graph.NodeInterface, Name: "IAuditable", QualifiedName: "Shop.IAuditable", Package: "Shop", FilePath: "shop/IAuditable.cs"}),
+		cs(&graph.Node{ID: "cls", Type: graph.NodeClass, Name: "OrderRepository", QualifiedName: "Shop.Orders.OrderRepository", Package: "Shop.Orders", FilePath: file,
+			Properties: map[string]string{"implements": "IRepository<Order>,IAuditable,IUnknown", "extends": "RepositoryBase"}}),
+	)
+	ref, placeholder := parser.Unresolved{Language: parser.LangCSharp, TargetType: graph.NodeInterface, Name: "IRepository<Order>", FilePath: file}.Link(graph.EdgeImplements, "cls")
+	addNodes(t, store, ref)
+	for _, e := range []*graph.Edge{
+		{ID: "imp", Type: graph.EdgeImports, SourceID: "file", TargetID: using},
+		placeholder,
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkCSharpImplements(ctx)
+	if err != nil {
+		t.Fatalf("linkCSharpImplements: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linkCSharpImplements returned %d, want 2", count)
+	}
+
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeImplements, SourceID: "cls"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, e := range edges {
+		got[e.TargetID] = e.Properties["kind"]
+	}
+	want := map[string]string{"repo": "namespace", "auditable": "namespace"}
+	if len(got) != len(want) {
+		t.Errorf("implements edges = %v, want %v", got, want)
+	}
+	for target, kind := range want {
+		if got[target] != kind {
+			t.Errorf("implements %s kind = %q, want %q", target, got[target], kind)
+		}
+	}
+}
+
+func TestResolveCSharpType(t *testing.T) {
+	byQualified := map[string][]*graph.Node{
+		"A.B.IService": {{ID: "ab"}},
+		"A.IService":   {{ID: "a"}},
+		"A.IStore":     {{ID: "a-store"}},
+		"X.IStore":     {{ID: "x-store"}},
+		"Y.IStore":     {{ID: "y-store"}},
+		"Y.Inner.IRun": {{ID: "y-run"}},
+	}
+	tests := []struct {
+		ns     string
+		usings []string
+		name   string
+		want   []string
+	}{
+		{"A.B.C", nil, "IService", []string{"ab"}},
+		{"A.C", nil, "IService<int>", []string{"a"}},
+		{"A", []string{"X"}, "IStore", []string{"a-store"}},
+		{"B", []string{"X", "Y"}, "IStore", []string{"x-store", "y-store"}},
+		{"B", []string{"Y"}, "global::Y.Inner.IRun", []string{"y-run"}},
+		{"B", []string{"Y"}, "Inner.IRun", nil},
+		{"", nil, "IMissing", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, n := range resolveCSharpType(byQualified, tt.ns, tt.usings, tt.name) {
+			got = append(got, n.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("resolveCSharpType(%q, %v, %q) = %v, want %v", tt.ns, tt.usings, tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("resolveCSharpType(%q, %v, %q) = %v, want %v", tt.ns, tt.usings, tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package linker
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// DataRule tags the model and DTO fields matching a pattern with a data
+// class.
+type DataRule struct {
+	// Pattern is a case-insensitive glob over "Type.field" or the bare
+	// field name: "*.ssn", "User.email", "*card*".
+	Pattern string
+	// Class is the data class, such as pii, pci or phi.
+	Class string
+}
+
+// defaultDataDepth is how many calls from an endpoint's handler data
+// propagation follows by default.
+const defaultDataDepth = 3
+
+// dataTypeKinds are the node types whose fields may hold sensitive data.
+var dataTypeKinds = []graph.NodeType{
+	graph.NodeClass, graph.NodeStruct, graph.NodeDTO, graph.NodeDBModel, graph.NodeDomainModel, graph.NodeViewModel,
+}
+
+// SetDataClassification sets the configured sensitive field rules and how
+// many calls from an endpoint's handler the data_classification phase
+// follows (0 for the default).
+func (l *Linker) SetDataClassification(rules []DataRule, depth int) {
+	l.dataRules = rules
+	l.dataDepth = depth
+}
+
+// linkDataClassification tags the types holding sensitive data and the
+// endpoints and services exposing them. A type's sensitive fields are those
+// its source tags (parser tagged_fields), those with a data class annotation
+// (@PII, [PersonalData], @Sensitive) and those matching a configured rule.
+// An endpoint exposes a type when its handler, or a function the handler
+// reaches within the configured call depth, takes or returns the type; a
+// service exposes the data classes of its endpoints.
+func (l *Linker) linkDataClassification(ctx context.Context) (int, error) {
+	tagged := make(map[string]map[string]bool) // language:type name -> data classes
+	for _, kind := range dataTypeKinds {
+		types, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: kind})
+		if err != nil {
+			return 0, err
+		}
+		for _, t := range types {
+			fields, err := l.sensitiveFields(ctx, t)
+			if err != nil {
+				return 0, err
+			}
+			classes := make(map[string]bool)
+			for _, class := range fields {
+				classes[class] = true
+			}
+			props := map[string]string{
+				parser.PropSensitiveFields: "",
+				parser.PropDataClasses:     strings.Join(sortedKeys(classes), ","),
+			}
+			if len(fields) > 0 {
+				props[parser.PropSensitiveFields] = parser.FormatSensitiveFields(fields)
+				key := t.Language + ":" + t.Name
+				if tagged[key] == nil {
+					tagged[key] = make(map[string]bool)
+				}
+				for class := range classes {
+					tagged[key][class] = true
+				}
+			}
+			if _, err := l.setProperties(ctx, t, props); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	endpoints, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return 0, err
+	}
+	byGroup := make(map[string]map[string]bool) // service group -> data classes
+	marked := 0
+	for _, ep := range endpoints {
+		exposed, err := l.exposedTypes(ctx, ep, tagged)
+		if err != nil {
+			return marked, err
+		}
+		classes := make(map[string]bool)
+		for _, cs := range exposed {
+			for class := range cs {
+				classes[class] = true
+			}
+		}
+		props := map[string]string{
+			parser.PropDataClasses: strings.Join(sortedKeys(classes), ","),
+			parser.PropDataTypes:   strings.Join(sortedKeys(exposed), ","),
+		}
+		if len(classes) > 0 {
+			marked++
+			group := l.group(ep.FilePath)
+			if byGroup[group] == nil {
+				byGroup[group] = make(map[string]bool)
+			}
+			for class := range classes {
+				byGroup[group][class] = true
+			}
+		}
+		changed, err := l.setProperties(ctx, ep, props)
+		if err != nil {
+			return marked, err
+		}
+		if changed && l.verbose && len(classes) > 0 {
+			l.log("    Endpoint %s exposes %s (%s)", ep.Name, props[parser.PropDataClasses], props[parser.PropDataTypes])
+		}
+	}
+
+	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return marked, err
+	}
+	for _, svc := range services {
+		classes := byGroup[l.serviceGroup(svc)]
+		if _, err := l.setProperties(ctx, svc, map[string]string{parser.PropDataClasses: strings.Join(sortedKeys(classes), ",")}); err != nil {
+			return marked, err
+		}
+	}
+	return marked, nil
+}
+
+// sensitiveFields returns the sensitive fields of a type and their data
+// classes. Source tags win over annotations, which win over configured
+// rules.
+func (l *Linker) sensitiveFields(ctx context.Context, t *graph.Node) (map[string]string, error) {
+	fields := parser.ParseSensitiveFields(t.Properties[parser.PropTaggedFields])
+	var names []string
+	if v := t.Properties["fields"]; v != "" {
+		names = strings.Split(v, ",")
+	}
+
+	// Java and C# fields and properties are Variable nodes in the type.
+	edges, err := l.store.GetEdges(ctx, t.ID, graph.EdgeContains)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range edges {
+		if e.SourceID != t.ID {
+			continue
+		}
+		member, err := l.store.GetNode(ctx, e.TargetID)
+		if err != nil || member == nil || member.Type != graph.NodeVariable {
+			continue
+		}
+		names = append(names, member.Name)
+		if _, ok := fields[member.Name]; ok {
+			continue
+		}
+		for _, ann := range strings.Split(member.Properties["annotations"], ",") {
+			if class := parser.AnnotationDataClass(ann); class != "" {
+				fields[member.Name] = class
+				break
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := fields[name]; ok || name == "" {
+			continue
+		}
+		if class := l.ruleDataClass(t.Name, name); class != "" {
+			fields[name] = class
+		}
+	}
+	return fields, nil
+}
+
+// ruleDataClass returns the class of the first configured rule matching
+// typeName.field, or "".
+func (l *Linker) ruleDataClass(typeName, field string) string {
+	qualified := strings.ToLower(typeName + "." + field)
+	bare := strings.ToLower(field)
+	for _, r := range l.dataRules {
+		pattern := strings.ToLower(r.Pattern)
+		if ok, _ := path.Match(pattern, qualified); ok {
+			return r.Class
+		}
+		if ok, _ := path.Match(pattern, bare); ok {
+			return r.Class
+		}
+	}
+	return ""
+}
+
+// exposedTypes returns the tagged types, with their data classes, named in
+// the signatures of the handlers of ep and the functions they call within
+// the configured depth.
+func (l *Linker) exposedTypes(ctx context.Context, ep *graph.Node, tagged map[string]map[string]bool) (map[string]map[string]bool, error) {
+	exposed := make(map[string]map[string]bool)
+	if len(tagged) == 0 {
+		return exposed, nil
+	}
+	depth := l.dataDepth
+	if depth <= 0 {
+		depth = defaultDataDepth
+	}
+
+	// Handlers expose the endpoint, or are called by it when imported.
+	var frontier []string
+	for _, et := range []graph.EdgeType{graph.EdgeExposes, graph.EdgeCalls} {
+		edges, err := l.store.GetEdges(ctx, ep.ID, et)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range edges {
+			switch {
+			case et == graph.EdgeExposes && e.TargetID == ep.ID:
+				frontier = append(frontier, e.SourceID)
+			case et == graph.EdgeCalls && e.SourceID == ep.ID:
+				frontier = append(frontier, e.TargetID)
+			}
+		}
+	}
+
+	visited := map[string]bool{ep.ID: true}
+	for level := 0; level <= depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, id := range frontier {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			fn, err := l.store.GetNode(ctx, id)
+			if err != nil || fn == nil {
+				continue
+			}
+			for _, name := range signatureTypes(fn) {
+				if classes, ok := tagged[fn.Language+":"+name]; ok {
+					if exposed[name] == nil {
+						exposed[name] = make(map[string]bool)
+					}
+					for class := range classes {
+						exposed[name][class] = true
+					}
+				}
+			}
+			calls, err := l.store.GetEdges(ctx, id, graph.EdgeCalls)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range calls {
+				if e.SourceID == id && !visited[e.TargetID] {
+					next = append(next, e.TargetID)
+				}
+			}
+		}
+		frontier = next
+	}
+	return exposed, nil
+}
+
+// signatureTypes returns the identifiers in a function's signature and
+// return type: the candidate names of the types it takes and returns.
+func signatureTypes(fn *graph.Node) []string {
+	text := fn.Signature + " " + fn.Properties["return_type"]
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,145 @@
+package linker
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkDataClassification(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	java := func(n *graph.Node) *graph.Node { n.Language = "java"; return n }
+	goNode := func(n *graph.Node) *graph.Node { n.Language = "go"; return n }
+	addNodes(t, store,
+		&graph.Node{ID: "svc-users", Type: graph.NodeService, Name: "users"},
+		&graph.Node{ID: "svc-billing", Type: graph.NodeService, Name: "billing"},
+		// Java DTO with an annotated field and one matched by config.
+		java(&graph.Node{ID: "dto", Type: graph.NodeDTO, Name: "UserDto", FilePath: "users/UserDto.java"}),
+		java(&graph.Node{ID: "f-email", Type: graph.NodeVariable, Name: "email", FilePath: "users/UserDto.java",
+			Properties: map[string]string{"annotations": "PII,JsonProperty"}}),
+		java(&graph.Node{ID: "f-ssn", Type: graph.NodeVariable, Name: "ssn", FilePath: "users/UserDto.java"}),
+		java(&graph.Node{ID: "f-id", Type: graph.NodeVariable, Name: "id", FilePath: "users/UserDto.java"}),
+		java(&graph.Node{ID: "ctrl", Type: graph.NodeMethod, Name: "getUser", FilePath: "users/UserController.java",
+			Signature: "public ResponseEntity<UserService> getUser(Long id)"}),
+		java(&graph.Node{ID: "svc", Type: graph.NodeMethod, Name: "find", FilePath: "users/UserService.java",
+			Signature: "public UserDto find(Long id)"}),
+		java(&graph.Node{ID: "ping", Type: graph.NodeMethod, Name: "ping", FilePath: "users/UserController.java",
+			Signature: "public String ping()"}),
+		&graph.Node{ID: "ep-user", Type: graph.NodeAPIEndpoint, Name: "GET /users/{id}", FilePath: "users/UserController.java"},
+		&graph.Node{ID: "ep-ping", Type: graph.NodeAPIEndpoint, Name: "GET /ping", FilePath: "users/UserController.java",
+			Properties: map[string]string{parser.PropDataClasses: "pii", parser.PropDataTypes: "Stale"}},
+		// Go struct tagged in source, reached by the handler directly.
+		goNode(&graph.Node{ID: "card", Type: graph.NodeStruct, Name: "Card", FilePath: "billing/card.go",
+			Properties: map[string]string{"fields": "Number,Expiry", parser.PropTaggedFields: "Number:pci"}}),
+		goNode(&graph.Node{ID: "charge", Type: graph.NodeFunction, Name: "charge", FilePath: "billing/handlers.go",
+			Signature: "func(ctx context.Context, c *Card) error"}),
+		&graph.Node{ID: "ep-charge", Type: graph.NodeAPIEndpoint, Name: "POST /charge", FilePath: "billing/handlers.go"},
+	)
+	for _, e := range []*graph.Edge{
+		{ID: "c1", Type: graph.EdgeContains, SourceID: "dto", TargetID: "f-email"},
+		{ID: "c2", Type: graph.EdgeContains, SourceID: "dto", TargetID: "f-ssn"},
+		{ID: "c3", Type: graph.EdgeContains, SourceID: "dto", TargetID: "f-id"},
+		{ID: "x1", Type: graph.EdgeExposes, SourceID: "ctrl", TargetID: "ep-user"},
+		{ID: "x2", Type: graph.EdgeExposes, SourceID: "ping", TargetID: "ep-ping"},
+		{ID: "x3", Type: graph.EdgeExposes, SourceID: "charge", TargetID: "ep-charge"},
+		{ID: "k1", Type: graph.EdgeCalls, SourceID: "ctrl", TargetID: "svc"},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := NewLinker(store, nil, nil, false)
+	l.SetDataClassification([]DataRule{{Pattern: "*.SSN", Class: "phi"}, {Pattern: "number", Class: "pii"}}, 0)
+	count, err := l.linkDataClassification(ctx)
+	if err != nil {
+		t.Fatalf("linkDataClassification: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linkDataClassification returned %d, want 2", count)
+	}
+
+	tests := []struct {
+		id    string
+		props map[string]string
+	}{
+		{"dto", map[string]string{parser.PropSensitiveFields: "email:pii,ssn:phi", parser.PropDataClasses: "phi,pii"}},
+		{"card", map[string]string{parser.PropSensitiveFields: "Number:pci", parser.PropDataClasses: "pci"}},
+		{"ep-user", map[string]string{parser.PropDataClasses: "phi,pii", parser.PropDataTypes: "UserDto"}},
+		{"ep-charge", map[string]string{parser.PropDataClasses: "pci", parser.PropDataTypes: "Card"}},
+		{"ep-ping", map[string]string{parser.PropDataClasses: "", parser.PropDataTypes: ""}},
+		{"svc-users", map[string]string{parser.PropDataClasses: "phi,pii"}},
+		{"svc-billing", map[string]string{parser.PropDataClasses: "pci"}},
+	}
+	for _, tt := range tests {
+		n, err := store.GetNode(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, want := range tt.props {
+			if got := n.Properties[k]; got != want {
+				t.Errorf("%s %s = %q, want %q", tt.id, k, got, want)
+			}
+		}
+	}
+}
+
+func TestDataClassificationWithDependencies(t *testing.T) {
+	// Both phases rewrite Service nodes; run together they must not
+	// overwrite each other's properties, so they may not run concurrently.
+	for _, p := range defaultRegistry.all() {
+		if p.Name == "data_classification" && !slices.Contains(p.After, "dependencies") {
+			t.Fatalf("data_classification After = %v, want it to include dependencies", p.After)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		store := newTestStore(t)
+		ctx := context.Background()
+
+		goNode := func(n *graph.Node) *graph.Node { n.Language = "go"; return n }
+		addNodes(t, store,
+			&graph.Node{ID: "ws", Type: graph.NodeModule, Name: ".", FilePath: "pnpm-workspace.yaml",
+				Properties: map[string]string{"kind": "workspace", "tool": "pnpm", "members": "packages/*"}},
+			&graph.Node{ID: "web", Type: graph.NodeService, Name: "@acme/web", FilePath: "packages/web/package.json",
+				Properties: map[string]string{"kind": "service", "ecosystem": "nodejs"}},
+			&graph.Node{ID: "ui", Type: graph.NodeService, Name: "@acme/ui", FilePath: "packages/ui/package.json",
+				Properties: map[string]string{"kind": "service", "ecosystem": "nodejs"}},
+			&graph.Node{ID: "d-ui", Type: graph.NodeDependency, Name: "@acme/ui", FilePath: "packages/web/package.json",
+				Properties: map[string]string{"kind": "manifest_dep", "ecosystem": "nodejs", "internal": "true"}},
+			goNode(&graph.Node{ID: "card", Type: graph.NodeStruct, Name: "Card", FilePath: "packages/ui/card.go",
+				Properties: map[string]string{"fields": "Number", parser.PropTaggedFields: "Number:pci"}}),
+			goNode(&graph.Node{ID: "charge", Type: graph.NodeFunction, Name: "charge", FilePath: "packages/ui/handlers.go",
+				Signature: "func(c *Card) error"}),
+			&graph.Node{ID: "ep-charge", Type: graph.NodeAPIEndpoint, Name: "POST /charge", FilePath: "packages/ui/handlers.go"},
+		)
+		if err := store.AddEdge(ctx, &graph.Edge{ID: "x1", Type: graph.EdgeExposes, SourceID: "charge", TargetID: "ep-charge"}); err != nil {
+			t.Fatal(err)
+		}
+
+		l := NewLinker(store, nil, nil, false)
+		l.SetParallelism(4)
+		l.SetPhaseSelection([]string{"data_classification", "dependencies"}, nil)
+		if err := l.RunAll(ctx); err != nil {
+			t.Fatalf("RunAll: %v", err)
+		}
+
+		n, err := store.GetNode(ctx, "ui")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, want := range map[string]string{
+			"kind":                 "library",
+			"workspace":            "pnpm-workspace.yaml",
+			parser.PropDataClasses: "pci",
+		} {
+			if got := n.Properties[k]; got != want {
+				t.Fatalf("run %d: ui %s = %q, want %q", i, k, got, want)
+			}
+		}
+	}
+}
@@ -2,6 +2,10 @@ package linker
 
 import (
 	"context"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
 )
@@ -9,6 +13,11 @@ import (
 // linkDependencies resolves manifest dependencies between services.
 // When service A depends on package "llm-framework" and service B declares
 // its package name as "llm-framework", we create EdgeDependsOn from A → B.
+//
+// Workspace-local dependencies (Cargo/npm path dependencies, Gradle
+// project() dependencies) are resolved by directory instead of name. Every
+// dependency resolved to a local service is marked internal=true, and
+// services depended on by a sibling in the same workspace become kind=library.
 func (l *Linker) linkDependencies(ctx context.Context) (int, error) {
 	// Query all manifest dependency nodes.
 	deps, err := l.store.QueryNodes(ctx, graph.NodeFilter{
@@ -18,9 +27,6 @@ func (l *Linker) linkDependencies(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	if len(deps) == 0 {
-		return 0, nil
-	}
 
 	// Query all services and build a map: package_name → service.
 	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
@@ -28,20 +34,33 @@ func (l *Linker) linkDependencies(ctx context.Context) (int, error) {
 		return 0, err
 	}
 
+	workspaces, err := l.applyWorkspaces(ctx, services)
+	if err != nil {
+		return 0, err
+	}
+	if len(deps) == 0 {
+		return 0, nil
+	}
+
 	// Map service names/package names to service nodes.
 	serviceByName := make(map[string]*graph.Node)
-	serviceByGroup := make(map[string]*graph.Node)
+	serviceByGroup := l.servicesByGroup(services)
+	serviceByManifest := make(map[string]*graph.Node)
+	serviceByDir := make(map[string][]*graph.Node)
 	for _, svc := range services {
 		serviceByName[svc.Name] = svc
-		group := topDir(svc.FilePath)
-		if group == "" {
-			group = svc.Name
-		}
-		serviceByGroup[group] = svc
 		// Also index by properties like go_module.
 		if mod, ok := svc.Properties["go_module"]; ok {
 			serviceByName[mod] = svc
 		}
+		if coords, ok := svc.Properties["maven_coordinates"]; ok {
+			serviceByName[coords] = svc
+		}
+		if svc.FilePath != "" {
+			serviceByManifest[svc.FilePath] = svc
+			dir := manifestDir(svc.FilePath)
+			serviceByDir[dir] = append(serviceByDir[dir], svc)
+		}
 	}
 
 	// Track service-level edges to avoid duplicates.
@@ -54,15 +73,28 @@ func (l *Linker) linkDependencies(ctx context.Context) (int, error) {
 			continue
 		}
 
-		// Find the consuming service (where this dependency is declared).
-		consumerGroup := topDir(dep.FilePath)
-		consumerSvc := serviceByGroup[consumerGroup]
+		// Find the consuming service: the one declared by the same manifest,
+		// or failing that the service of the same top-level directory.
+		consumerSvc := serviceByManifest[dep.FilePath]
+		if consumerSvc == nil {
+			consumerSvc = serviceByGroup[l.group(dep.FilePath)]
+		}
 		if consumerSvc == nil {
 			continue
 		}
 
-		// Check if the dependency name matches any local service.
-		providerSvc := serviceByName[depName]
+		// Check if the dependency resolves to a local service.
+		var providerSvc *graph.Node
+		switch {
+		case dep.Properties["path"] != "":
+			dir := path.Join(manifestDir(dep.FilePath), filepath.ToSlash(dep.Properties["path"]))
+			providerSvc = pickService(serviceByDir[dir], dep.Properties["ecosystem"])
+		case dep.Properties["gradle_project"] != "":
+			dir := gradleProjectDir(dep, workspaces)
+			providerSvc = pickService(serviceByDir[dir], dep.Properties["ecosystem"])
+		default:
+			providerSvc = serviceByName[depName]
+		}
 		if providerSvc == nil {
 			continue
 		}
@@ -72,21 +104,39 @@ func (l *Linker) linkDependencies(ctx context.Context) (int, error) {
 			continue
 		}
 
+		if dep.Properties["internal"] != "true" {
+			dep.Properties["internal"] = "true"
+			if err := l.store.UpdateNode(ctx, dep); err != nil && l.verbose {
+				l.log("  Warning: update %s: %v", dep.ID, err)
+			}
+		}
+
 		depKey := consumerSvc.ID + "→" + providerSvc.ID
 		if serviceDeps[depKey] {
 			continue
 		}
 
+		kind := "library_dependency"
+		if ws := consumerSvc.Properties["workspace"]; ws != "" && ws == providerSvc.Properties["workspace"] {
+			kind = "workspace_dependency"
+			if providerSvc.Properties["kind"] != "library" {
+				providerSvc.Properties["kind"] = "library"
+				if err := l.store.UpdateNode(ctx, providerSvc); err != nil && l.verbose {
+					l.log("  Warning: update %s: %v", providerSvc.ID, err)
+				}
+			}
+		}
+
 		edge := &graph.Edge{
-			ID:       graph.NewNodeID(string(graph.EdgeDependsOn), consumerSvc.ID, providerSvc.ID),
+			ID:       graph.NewEdgeID(graph.EdgeDependsOn, consumerSvc.ID, providerSvc.ID),
 			Type:     graph.EdgeDependsOn,
 			SourceID: consumerSvc.ID,
 			TargetID: providerSvc.ID,
-			Properties: map[string]string{
-				"kind":    "library_dependency",
+			Properties: withConfidence(map[string]string{
+				"kind":    kind,
 				"dep":     depName,
 				"version": dep.Properties["version"],
-			},
+			}, graph.ConfidenceExact, scoreExact),
 		}
 		if err := l.store.AddEdge(ctx, edge); err != nil {
 			continue
@@ -102,6 +152,132 @@ func (l *Linker) linkDependencies(ctx context.Context) (int, error) {
 	return resolved, nil
 }
 
+// applyWorkspaces expands the member globs of workspace definitions (go.work,
+// npm/pnpm/yarn workspaces, Cargo, Maven modules, Gradle settings) against
+// service manifest directories and sets each member's "workspace" property to
+// the defining file. It returns the workspace Module nodes.
+func (l *Linker) applyWorkspaces(ctx context.Context, services []*graph.Node) ([]*graph.Node, error) {
+	workspaces, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeModule,
+		Properties: map[string]string{"kind": "workspace"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Nested workspaces (a Maven module that is itself an aggregator) are
+	// applied outermost first so the innermost definition wins.
+	sort.Slice(workspaces, func(i, j int) bool {
+		return strings.Count(workspaces[i].FilePath, "/") < strings.Count(workspaces[j].FilePath, "/")
+	})
+
+	members := make(map[string]string) // service ID -> workspace file
+	for _, ws := range workspaces {
+		wsDir := manifestDir(ws.FilePath)
+		globs := strings.Split(ws.Properties["members"], ",")
+		for _, svc := range services {
+			if svc.FilePath == "" || svc.FilePath == ws.FilePath {
+				continue
+			}
+			rel := manifestDir(svc.FilePath)
+			if wsDir != "." {
+				var ok bool
+				if rel, ok = strings.CutPrefix(rel, wsDir+"/"); !ok {
+					continue
+				}
+			}
+			for _, g := range globs {
+				if matchWorkspaceMember(g, rel) {
+					members[svc.ID] = ws.FilePath
+					break
+				}
+			}
+		}
+	}
+
+	for _, svc := range services {
+		ws, ok := members[svc.ID]
+		if !ok || svc.Properties["workspace"] == ws {
+			continue
+		}
+		svc.Properties["workspace"] = ws
+		if err := l.store.UpdateNode(ctx, svc); err != nil && l.verbose {
+			l.log("  Warning: update %s: %v", svc.ID, err)
+		}
+		if l.verbose {
+			l.log("    Workspace: %s (%s) in %s", svc.Name, svc.FilePath, ws)
+		}
+	}
+	return workspaces, nil
+}
+
+// matchWorkspaceMember matches a directory against a member glob, where "**"
+// matches any number of directories.
+func matchWorkspaceMember(glob, dir string) bool {
+	return matchDirSegments(strings.Split(glob, "/"), strings.Split(dir, "/"))
+}
+
+func matchDirSegments(glob, parts []string) bool {
+	if len(glob) == 0 {
+		return len(parts) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchDirSegments(glob[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(glob[0], parts[0]); !matched {
+		return false
+	}
+	return matchDirSegments(glob[1:], parts[1:])
+}
+
+// gradleProjectDir maps a Gradle project path (":libs:core") to its directory
+// under the innermost Gradle workspace containing the dependency.
+func gradleProjectDir(dep *graph.Node, workspaces []*graph.Node) string {
+	depDir := manifestDir(dep.FilePath)
+	root := ""
+	for _, ws := range workspaces {
+		if ws.Properties["tool"] != "gradle" {
+			continue
+		}
+		dir := manifestDir(ws.FilePath)
+		if (dir == "." || depDir == dir || strings.HasPrefix(depDir, dir+"/")) && len(dir) >= len(root) {
+			root = dir
+		}
+	}
+	if root == "" {
+		root = "."
+	}
+	project := strings.Trim(dep.Properties["gradle_project"], ":")
+	return path.Join(root, strings.ReplaceAll(project, ":", "/"))
+}
+
+// pickService prefers the candidate of the given ecosystem when a directory
+// has several manifests (e.g. package.json next to go.mod).
+func pickService(candidates []*graph.Node, ecosystem string) *graph.Node {
+	for _, c := range candidates {
+		if c.Properties["ecosystem"] == ecosystem {
+			return c
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// manifestDir returns the slash-separated directory of a manifest path ("."
+// for the repository root).
+func manifestDir(filePath string) string {
+	return path.Dir(filepath.ToSlash(filePath))
+}
+
 // detectVersionConflicts checks for the same dependency used by
 // different services with different versions and logs warnings.
 func (l *Linker) detectVersionConflicts(deps []*graph.Node) {
@@ -120,7 +296,7 @@ func (l *Linker) detectVersionConflicts(deps []*graph.Node) {
 		if version == "" {
 			continue
 		}
-		group := topDir(dep.FilePath)
+		group := l.group(dep.FilePath)
 		byName[dep.Name] = append(byName[dep.Name], depVersion{group, version})
 	}
 
@@ -27,17 +27,13 @@ func (l *Linker) linkDocuments(ctx context.Context) (int, error) {
 
 	nameToNodes := make(map[string][]string) // lowercase name → node IDs
 	for _, nt := range codeTypes {
-		nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: nt})
-		if err != nil {
-			continue
-		}
-		for _, n := range nodes {
+		_ = l.store.ScanNodes(ctx, graph.NodeFilter{Type: nt}, func(n *graph.Node) error {
 			lower := strings.ToLower(n.Name)
-			if len(lower) < 3 {
-				continue // skip very short names to avoid false positives
+			if len(lower) >= 3 { // skip very short names to avoid false positives
+				nameToNodes[lower] = append(nameToNodes[lower], n.ID)
 			}
-			nameToNodes[lower] = append(nameToNodes[lower], n.ID)
-		}
+			return nil
+		})
 	}
 
 	linked := 0
@@ -62,10 +58,11 @@ func (l *Linker) linkDocuments(ctx context.Context) (int, error) {
 
 				edgeID := graph.NewNodeID("edge", doc.ID, targetID+":Documents")
 				edge := &graph.Edge{
-					ID:       edgeID,
-					Type:     graph.EdgeDocuments,
-					SourceID: doc.ID,
-					TargetID: targetID,
+					ID:         edgeID,
+					Type:       graph.EdgeDocuments,
+					SourceID:   doc.ID,
+					TargetID:   targetID,
+					Properties: withConfidence(nil, graph.ConfidenceHeuristic, scoreWeak),
 				}
 				if err := l.store.AddEdge(ctx, edge); err != nil {
 					if l.verbose {
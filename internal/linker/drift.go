@@ -0,0 +1,207 @@
+package linker
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// DriftKind classifies a contract drift finding.
+type DriftKind string
+
+const (
+	// DriftMissingEndpoint is an API call whose path matches no endpoint.
+	DriftMissingEndpoint DriftKind = "missing_endpoint"
+	// DriftMethodMismatch is an API call whose path matches endpoints that
+	// don't accept its HTTP method.
+	DriftMethodMismatch DriftKind = "method_mismatch"
+	// DriftUnusedEndpoint is an endpoint no API call consumes.
+	DriftUnusedEndpoint DriftKind = "unused_endpoint"
+)
+
+// DriftFinding is one mismatch between consumers and providers. For call
+// findings FilePath/Line locate the call; for unused endpoints they locate
+// the endpoint.
+type DriftFinding struct {
+	Kind     DriftKind `json:"kind"`
+	Method   string    `json:"method,omitempty"`
+	Path     string    `json:"path"`
+	FilePath string    `json:"file_path"`
+	Line     int       `json:"line,omitempty"`
+	Service  string    `json:"service,omitempty"`
+	// Provided lists the methods the matched path accepts (method_mismatch),
+	// and Provider the file defining it.
+	Provided []string `json:"provided,omitempty"`
+	Provider string   `json:"provider,omitempty"`
+}
+
+// DriftReport is the result of comparing API calls against endpoints.
+type DriftReport struct {
+	Calls     int            `json:"calls"`
+	Endpoints int            `json:"endpoints"`
+	Findings  []DriftFinding `json:"findings"`
+}
+
+// Count returns the number of findings of the given kind.
+func (r *DriftReport) Count(kind DriftKind) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// anyMethods are endpoint methods that accept every HTTP method.
+var anyMethods = map[string]bool{"": true, "ALL": true, "ANY": true, "*": true, "UNKNOWN": true}
+
+// DetectDrift compares the paths and methods consumers call (api_call nodes)
+// with the endpoints providers define. Calls matching no endpoint path are
+// missing_endpoint findings and calls whose method no endpoint on the matched
+// path accepts are method_mismatch findings; endpoints neither matched by a
+// call nor the target of another Consumes edge (from an HTML form or LLM
+// analysis) are unused_endpoint findings. Calls the backlog marks as external
+// are skipped; backlog may be nil.
+func (l *Linker) DetectDrift(ctx context.Context, backlog *Backlog) (*DriftReport, error) {
+	calls, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Properties: map[string]string{"kind": "api_call"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return nil, err
+	}
+
+	// Group endpoints by normalized path; the index keeps one per path for
+	// matching and byPath holds every method defined on it.
+	index := make(map[string]*graph.Node)
+	byPath := make(map[string][]*graph.Node)
+	report := &DriftReport{}
+	for _, ep := range endpoints {
+		p := endpointPath(ep)
+		if p == "" {
+			continue
+		}
+		report.Endpoints++
+		key := normalizeURLPath(p)
+		if index[key] == nil {
+			index[key] = ep
+		}
+		byPath[key] = append(byPath[key], ep)
+	}
+
+	consumed := make(map[string]bool)
+	for _, call := range calls {
+		callPath := call.Properties["path"]
+		if callPath == "" || backlog.IsExternal(call) {
+			continue
+		}
+		report.Calls++
+		method := strings.ToUpper(call.Properties["http_method"])
+
+		match, _ := matchEndpoint(normalizeURLPath(callPath), index)
+		if match == nil {
+			report.Findings = append(report.Findings, DriftFinding{
+				Kind:     DriftMissingEndpoint,
+				Method:   method,
+				Path:     callPath,
+				FilePath: call.FilePath,
+				Line:     call.Line,
+				Service:  l.group(call.FilePath),
+			})
+			continue
+		}
+
+		candidates := byPath[normalizeURLPath(endpointPath(match))]
+		var accepting []*graph.Node
+		var provided []string
+		for _, ep := range candidates {
+			epMethod := strings.ToUpper(ep.Properties["http_method"])
+			if anyMethods[method] || anyMethods[epMethod] || epMethod == method {
+				accepting = append(accepting, ep)
+			}
+			if !anyMethods[epMethod] {
+				provided = append(provided, epMethod)
+			}
+		}
+		for _, ep := range accepting {
+			consumed[ep.ID] = true
+		}
+		if len(accepting) == 0 {
+			sort.Strings(provided)
+			report.Findings = append(report.Findings, DriftFinding{
+				Kind:     DriftMethodMismatch,
+				Method:   method,
+				Path:     callPath,
+				FilePath: call.FilePath,
+				Line:     call.Line,
+				Service:  l.group(call.FilePath),
+				Provided: provided,
+				Provider: match.FilePath,
+			})
+		}
+	}
+
+	for _, eps := range byPath {
+		for _, ep := range eps {
+			if consumed[ep.ID] {
+				continue
+			}
+			edges, err := l.store.GetIncomingEdges(ctx, ep.ID, graph.EdgeConsumes)
+			if err != nil {
+				return nil, err
+			}
+			if consumedOutsideAPICalls(edges, ep.ID) {
+				continue
+			}
+			report.Findings = append(report.Findings, DriftFinding{
+				Kind:     DriftUnusedEndpoint,
+				Method:   strings.ToUpper(ep.Properties["http_method"]),
+				Path:     endpointPath(ep),
+				FilePath: ep.FilePath,
+				Line:     ep.Line,
+				Service:  l.group(ep.FilePath),
+			})
+		}
+	}
+
+	sort.Slice(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Method < b.Method
+	})
+	return report, nil
+}
+
+// endpointPath returns an endpoint's path including any router prefix.
+func endpointPath(ep *graph.Node) string {
+	if p := ep.Properties["full_path"]; p != "" {
+		return p
+	}
+	return ep.Properties["path"]
+}
+
+// consumedOutsideAPICalls reports whether a Consumes edge not created by
+// linkAPICalls (which ignores methods, and is re-checked here) targets id.
+func consumedOutsideAPICalls(edges []*graph.Edge, id string) bool {
+	for _, e := range edges {
+		if e.TargetID == id && e.Properties["resolved"] != "true" {
+			return true
+		}
+	}
+	return false
+}
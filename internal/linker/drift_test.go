@@ -0,0 +1,81 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestDetectDrift(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	call := func(id, method, path, file string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeDependency, Name: method + " " + path, FilePath: file, Line: 10,
+			Properties: map[string]string{"kind": "api_call", "http_method": method, "path": path}}
+	}
+	endpoint := func(id, method, path string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeAPIEndpoint, Name: method + " " + path, FilePath: "api/routes.go", Line: 5,
+			Properties: map[string]string{"http_method": method, "path": path}}
+	}
+	addNodes(t, store,
+		endpoint("ep-list", "GET", "/api/users"),
+		endpoint("ep-get", "GET", "/api/users/{id}"),
+		endpoint("ep-delete", "DELETE", "/api/users/{id}"),
+		endpoint("ep-health", "GET", "/healthz"),
+		endpoint("ep-form", "POST", "/signup"),
+		call("call-list", "GET", "/api/users", "web/src/users.ts"),
+		call("call-get", "get", "/api/users/42", "web/src/users.ts"),
+		call("call-put", "PUT", "/api/users/42", "web/src/edit.ts"),
+		call("call-orders", "GET", "/api/orders", "web/src/orders.ts"),
+		call("call-stripe", "POST", "/v1/charges", "web/src/pay.ts"),
+		&graph.Node{ID: "form", Type: graph.NodeDependency, Name: "signup form", FilePath: "web/signup.html"},
+	)
+	if err := store.AddEdge(ctx, &graph.Edge{ID: "form-consumes", Type: graph.EdgeConsumes, SourceID: "form", TargetID: "ep-form"}); err != nil {
+		t.Fatal(err)
+	}
+	// A method-blind Consumes edge from linkAPICalls does not count as a consumer.
+	if err := store.AddEdge(ctx, &graph.Edge{ID: "put-consumes", Type: graph.EdgeConsumes, SourceID: "call-put", TargetID: "ep-delete",
+		Properties: map[string]string{"resolved": "true"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	backlog := &Backlog{Entries: map[string]*BacklogEntry{}}
+	stripe := call("call-stripe", "POST", "/v1/charges", "web/src/pay.ts")
+	backlog.Entries[backlogKey(stripe)] = &BacklogEntry{Key: backlogKey(stripe), Status: BacklogExternal}
+
+	report, err := NewLinker(store, nil, nil, false).DetectDrift(ctx, backlog)
+	if err != nil {
+		t.Fatalf("DetectDrift: %v", err)
+	}
+	if report.Calls != 4 || report.Endpoints != 5 {
+		t.Errorf("compared %d calls and %d endpoints, want 4 and 5", report.Calls, report.Endpoints)
+	}
+
+	want := []struct {
+		kind   DriftKind
+		method string
+		path   string
+	}{
+		{DriftMethodMismatch, "PUT", "/api/users/42"},
+		{DriftMissingEndpoint, "GET", "/api/orders"},
+		{DriftUnusedEndpoint, "DELETE", "/api/users/{id}"},
+		{DriftUnusedEndpoint, "GET", "/healthz"},
+	}
+	if len(report.Findings) != len(want) {
+		t.Fatalf("findings = %+v, want %d", report.Findings, len(want))
+	}
+	for i, w := range want {
+		f := report.Findings[i]
+		if f.Kind != w.kind || f.Method != w.method || f.Path != w.path {
+			t.Errorf("finding %d = %s %s %s, want %s %s %s", i, f.Kind, f.Method, f.Path, w.kind, w.method, w.path)
+		}
+	}
+	if got := report.Findings[0].Provided; len(got) != 2 || got[0] != "DELETE" || got[1] != "GET" {
+		t.Errorf("method_mismatch provided = %v, want [DELETE GET]", got)
+	}
+	if report.Count(DriftUnusedEndpoint) != 2 {
+		t.Errorf("Count(unused_endpoint) = %d, want 2", report.Count(DriftUnusedEndpoint))
+	}
+}
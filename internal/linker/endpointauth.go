@@ -0,0 +1,126 @@
+package linker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// authMarkerProps are the node properties listing the annotations,
+// decorators and controller filters auth requirements are read from.
+var authMarkerProps = []string{"annotations", "decorators", "before_actions"}
+
+// linkEndpointAuth records the auth requirement of each API endpoint: the
+// middleware its route passes through ([Authorize], @PreAuthorize,
+// requireAuth, Gin group middleware), the annotations and decorators of the
+// handler exposing it, and those of the handler's class, classified by
+// parser.ClassifyAuth. Endpoints nothing marks are left without an auth
+// property; stale properties from an earlier run are cleared.
+func (l *Linker) linkEndpointAuth(ctx context.Context) (int, error) {
+	endpoints, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return 0, err
+	}
+
+	marked := 0
+	for _, ep := range endpoints {
+		req, err := EndpointAuth(ctx, l.store, ep)
+		if err != nil {
+			return marked, err
+		}
+		props := map[string]string{
+			parser.PropAuth:      req.Status,
+			parser.PropAuthVia:   strings.Join(req.Via, ","),
+			parser.PropAuthRoles: strings.Join(req.Roles, ","),
+		}
+		if req.Status != "" {
+			marked++
+		}
+		changed, err := l.setProperties(ctx, ep, props)
+		if err != nil {
+			return marked, err
+		}
+		if changed && l.verbose && req.Status != "" {
+			l.log("    Endpoint %s is %s via %s", ep.Name, req.Status, props[parser.PropAuthVia])
+		}
+	}
+	return marked, nil
+}
+
+// EndpointAuth classifies an endpoint from its route middleware and the
+// annotations on the node exposing it and that node's container (e.g. the
+// controller class).
+func EndpointAuth(ctx context.Context, store graph.Store, ep *graph.Node) (parser.AuthRequirement, error) {
+	var markers []string
+	if mw := ep.Properties[parser.PropMiddleware]; mw != "" {
+		markers = strings.Split(mw, ",")
+	}
+
+	edges, err := store.GetEdges(ctx, ep.ID, graph.EdgeExposes)
+	if err != nil {
+		return parser.AuthRequirement{}, err
+	}
+	for _, e := range edges {
+		if e.TargetID != ep.ID {
+			continue
+		}
+		handler, err := store.GetNode(ctx, e.SourceID)
+		if err != nil || handler == nil {
+			continue
+		}
+		markers = append(markers, authMarkersOf(handler)...)
+
+		parents, err := store.GetEdges(ctx, handler.ID, graph.EdgeContains)
+		if err != nil {
+			return parser.AuthRequirement{}, err
+		}
+		for _, pe := range parents {
+			if pe.TargetID != handler.ID {
+				continue
+			}
+			if parent, err := store.GetNode(ctx, pe.SourceID); err == nil && parent != nil {
+				markers = append(markers, authMarkersOf(parent)...)
+			}
+		}
+	}
+	return parser.ClassifyAuth(markers), nil
+}
+
+// authMarkersOf splits the annotation, decorator and filter properties of
+// a node.
+func authMarkersOf(n *graph.Node) []string {
+	var out []string
+	for _, key := range authMarkerProps {
+		if v := n.Properties[key]; v != "" {
+			out = append(out, strings.Split(v, ",")...)
+		}
+	}
+	return out
+}
+
+// setProperties sets props on n, deleting those with empty values, and
+// stores n when that changed it.
+func (l *Linker) setProperties(ctx context.Context, n *graph.Node, props map[string]string) (bool, error) {
+	changed := false
+	for k, v := range props {
+		if n.Properties[k] != v {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	if n.Properties == nil {
+		n.Properties = make(map[string]string)
+	}
+	for k, v := range props {
+		if v == "" {
+			delete(n.Properties, k)
+		} else {
+			n.Properties[k] = v
+		}
+	}
+	return true, l.store.UpdateNode(ctx, n)
+}
@@ -0,0 +1,69 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkEndpointAuth(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	endpoint := func(id, name string, props map[string]string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeAPIEndpoint, Name: name, FilePath: "api/routes", Properties: props}
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "ctrl", Type: graph.NodeClass, Name: "UsersController", FilePath: "api/UsersController.cs",
+			Properties: map[string]string{"annotations": "ApiController,Authorize"}},
+		&graph.Node{ID: "list", Type: graph.NodeMethod, Name: "List", FilePath: "api/UsersController.cs",
+			Properties: map[string]string{"annotations": `HttpGet,Authorize(Roles = "Admin,Ops")`}},
+		&graph.Node{ID: "login", Type: graph.NodeMethod, Name: "Login", FilePath: "api/UsersController.cs",
+			Properties: map[string]string{"annotations": "HttpPost,AllowAnonymous"}},
+		endpoint("ep-list", "GET /users", nil),
+		endpoint("ep-login", "POST /login", nil),
+		endpoint("ep-me", "GET /me", map[string]string{parser.PropMiddleware: "gin.Logger,AuthRequired"}),
+		endpoint("ep-health", "GET /health", map[string]string{parser.PropMiddleware: "gin.Logger",
+			parser.PropAuth: parser.AuthProtected, parser.PropAuthVia: "stale"}),
+	)
+	for _, e := range []*graph.Edge{
+		{ID: "c1", Type: graph.EdgeContains, SourceID: "ctrl", TargetID: "list"},
+		{ID: "c2", Type: graph.EdgeContains, SourceID: "ctrl", TargetID: "login"},
+		{ID: "x1", Type: graph.EdgeExposes, SourceID: "list", TargetID: "ep-list"},
+		{ID: "x2", Type: graph.EdgeExposes, SourceID: "login", TargetID: "ep-login"},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkEndpointAuth(ctx)
+	if err != nil {
+		t.Fatalf("linkEndpointAuth: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkEndpointAuth returned %d, want 3", count)
+	}
+
+	tests := []struct {
+		id, auth, via, roles string
+	}{
+		{"ep-list", parser.AuthProtected, "Authorize", "Admin,Ops"},
+		{"ep-login", parser.AuthPublic, "AllowAnonymous", ""},
+		{"ep-me", parser.AuthProtected, "AuthRequired", ""},
+		{"ep-health", "", "", ""},
+	}
+	for _, tt := range tests {
+		n, err := store.GetNode(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := n.Properties
+		if p[parser.PropAuth] != tt.auth || p[parser.PropAuthVia] != tt.via || p[parser.PropAuthRoles] != tt.roles {
+			t.Errorf("%s auth = %q via %q roles %q, want %q %q %q", tt.id,
+				p[parser.PropAuth], p[parser.PropAuthVia], p[parser.PropAuthRoles], tt.auth, tt.via, tt.roles)
+		}
+	}
+}
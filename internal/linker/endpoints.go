@@ -9,7 +9,10 @@ import (
 )
 
 // linkEndpoints links API endpoint nodes to their containing services
-// and resolves full paths using router mount prefixes.
+// and records each endpoint's full_path: the path parsers compose from
+// class, group, namespace and router prefixes declared with the route,
+// under the prefix of any router mount in its directory tree. The
+// parameters of the full path are recorded as path_params ("id:int,slug").
 func (l *Linker) linkEndpoints(ctx context.Context) (int, error) {
 	// Query all APIEndpoint nodes.
 	endpoints, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
@@ -39,20 +42,12 @@ func (l *Linker) linkEndpoints(ctx context.Context) (int, error) {
 		}
 	}
 
-	// Query all services for lookup by top-level directory.
+	// Query all services for lookup by service group.
 	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
 	if err != nil {
 		return 0, err
 	}
-	serviceByGroup := make(map[string]*graph.Node)
-	for _, svc := range services {
-		group := topDir(svc.FilePath)
-		if group == "" {
-			// Auto-detected services have no FilePath; use Name as group.
-			group = svc.Name
-		}
-		serviceByGroup[group] = svc
-	}
+	serviceByGroup := l.servicesByGroup(services)
 
 	linked := 0
 	for _, ep := range endpoints {
@@ -60,17 +55,23 @@ func (l *Linker) linkEndpoints(ctx context.Context) (int, error) {
 		path := ep.Properties["path"]
 		if path != "" {
 			fullPath := resolveFullPath(ep.FilePath, path, prefixByDir)
-			if fullPath != path {
+			params := formatPathParams(pathParams(fullPath))
+			if fullPath != ep.Properties["full_path"] || params != ep.Properties["path_params"] {
 				if ep.Properties == nil {
 					ep.Properties = make(map[string]string)
 				}
 				ep.Properties["full_path"] = fullPath
+				if params != "" {
+					ep.Properties["path_params"] = params
+				} else {
+					delete(ep.Properties, "path_params")
+				}
 				_ = l.store.UpdateNode(ctx, ep)
 			}
 		}
 
 		// Find the containing service based on file path.
-		group := topDir(ep.FilePath)
+		group := l.group(ep.FilePath)
 		svc, ok := serviceByGroup[group]
 		if !ok {
 			continue
@@ -78,10 +79,11 @@ func (l *Linker) linkEndpoints(ctx context.Context) (int, error) {
 
 		// Create EdgeExposes from service → endpoint.
 		edge := &graph.Edge{
-			ID:       graph.NewNodeID(string(graph.EdgeExposes), svc.ID, ep.ID),
-			Type:     graph.EdgeExposes,
-			SourceID: svc.ID,
-			TargetID: ep.ID,
+			ID:         graph.NewEdgeID(graph.EdgeExposes, svc.ID, ep.ID),
+			Type:       graph.EdgeExposes,
+			SourceID:   svc.ID,
+			TargetID:   ep.ID,
+			Properties: withConfidence(nil, graph.ConfidenceExact, scoreExact),
 		}
 		if err := l.store.AddEdge(ctx, edge); err != nil {
 			// Ignore duplicate edge errors.
@@ -0,0 +1,100 @@
+package linker
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkErrors resolves the error types functions and methods surface (their
+// throws property, see parser.PropThrows) to the classes, structs, types
+// and Go sentinel variables defining them, creating Throws edges. Together
+// with Calls edges these answer which endpoints can surface an error.
+// Only definitions in the function's language are considered; those marked
+// as error types are preferred, then those in the same file and service
+// group. Unqualified Go names only match within the function's package.
+// Names without a definition in the graph (built-in exceptions) stay on the
+// throws property only.
+func (l *Linker) linkErrors(ctx context.Context) (int, error) {
+	byName := make(map[string][]*graph.Node)
+	for _, t := range []graph.NodeType{graph.NodeClass, graph.NodeStruct, graph.NodeType_, graph.NodeVariable} {
+		filter := graph.NodeFilter{Type: t}
+		if t == graph.NodeVariable {
+			filter.Properties = map[string]string{parser.PropErrorType: "true"}
+		}
+		err := l.store.ScanNodes(ctx, filter, func(n *graph.Node) error {
+			byName[n.Name] = append(byName[n.Name], n)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(byName) == 0 {
+		return 0, nil
+	}
+
+	linked := 0
+	for _, t := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+		err := l.store.ScanNodes(ctx, graph.NodeFilter{Type: t}, func(fn *graph.Node) error {
+			throws := fn.Properties[parser.PropThrows]
+			if throws == "" {
+				return nil
+			}
+			for _, name := range strings.Split(throws, ",") {
+				candidates := l.errorTypeCandidates(fn, name, byName[lastNameSegment(name)])
+				if len(candidates) == 0 {
+					continue
+				}
+				level, score := nameMatchConfidence(len(candidates))
+				edge := &graph.Edge{
+					ID:       graph.NewEdgeID(graph.EdgeThrows, fn.ID, candidates[0].ID),
+					Type:     graph.EdgeThrows,
+					SourceID: fn.ID,
+					TargetID: candidates[0].ID,
+					Properties: withConfidence(map[string]string{
+						"error": name,
+					}, level, score),
+				}
+				if err := l.store.AddEdge(ctx, edge); err != nil {
+					continue
+				}
+				linked++
+			}
+			return nil
+		})
+		if err != nil {
+			return linked, err
+		}
+	}
+	return linked, nil
+}
+
+// errorTypeCandidates narrows the nodes named like an error type a function
+// surfaces to those in its language, and for Go to its package unless the
+// name is package-qualified.
+func (l *Linker) errorTypeCandidates(fn *graph.Node, name string, nodes []*graph.Node) []*graph.Node {
+	nodes = sameLanguage(fn, nodes)
+	if fn.Language == "go" {
+		pkg, _, qualified := strings.Cut(name, ".")
+		var kept []*graph.Node
+		for _, n := range nodes {
+			if qualified && n.Package == pkg ||
+				!qualified && n.Package == fn.Package && path.Dir(n.FilePath) == path.Dir(fn.FilePath) {
+				kept = append(kept, n)
+			}
+		}
+		nodes = kept
+	} else if qualified := strings.ReplaceAll(name, "::", "."); strings.Contains(qualified, ".") {
+		nodes = narrow(nodes, func(n *graph.Node) bool {
+			return strings.HasSuffix(strings.ReplaceAll(n.QualifiedName, "::", "."), qualified)
+		})
+	}
+	nodes = narrow(nodes, func(n *graph.Node) bool { return n.Properties[parser.PropErrorType] == "true" })
+	nodes = narrow(nodes, func(n *graph.Node) bool { return n.FilePath == fn.FilePath })
+	group := l.group(fn.FilePath)
+	return narrow(nodes, func(n *graph.Node) bool { return l.group(n.FilePath) == group })
+}
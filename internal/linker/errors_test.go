@@ -0,0 +1,81 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkErrors(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	fn := func(id, file, lang, pkg, throws string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeFunction, Name: id, FilePath: file, Language: lang, Package: pkg,
+			Properties: map[string]string{parser.PropThrows: throws}}
+	}
+	errType := func(id string, typ graph.NodeType, name, qualified, file, lang, pkg string, marked bool) *graph.Node {
+		n := &graph.Node{ID: id, Type: typ, Name: name, QualifiedName: qualified, FilePath: file, Language: lang,
+			Package: pkg, Properties: map[string]string{}}
+		if marked {
+			n.Properties[parser.PropErrorType] = "true"
+		}
+		return n
+	}
+
+	addNodes(t, store,
+		fn("charge", "payments/app/service.py", "python", "", "DeclinedError,ValueError"),
+		fn("refund", "payments/app/refunds.rb", "ruby", "", "Payments::DeclinedError"),
+		fn("load", "orders/store/load.go", "go", "store", "ErrNotFound,sql.ErrNoRows,DeclinedError"),
+		errType("py-declined", graph.NodeClass, "DeclinedError", "app.errors.DeclinedError", "payments/app/errors.py", "python", "", true),
+		errType("py-declined-model", graph.NodeClass, "DeclinedError", "app.models.DeclinedError", "payments/app/models.py", "python", "", false),
+		errType("rb-declined", graph.NodeClass, "DeclinedError", "Payments::DeclinedError", "payments/app/errors.rb", "ruby", "", true),
+		errType("rb-other", graph.NodeClass, "DeclinedError", "Cards::DeclinedError", "payments/app/cards.rb", "ruby", "", true),
+		errType("go-notfound", graph.NodeVariable, "ErrNotFound", "store.ErrNotFound", "orders/store/errors.go", "go", "store", true),
+		errType("go-notfound-other", graph.NodeVariable, "ErrNotFound", "cache.ErrNotFound", "orders/cache/errors.go", "go", "cache", true),
+		errType("go-declined", graph.NodeStruct, "DeclinedError", "billing.DeclinedError", "billing/errors.go", "go", "billing", true),
+	)
+
+	l := NewLinker(store, nil, nil, false)
+	count, err := l.linkErrors(ctx)
+	if err != nil {
+		t.Fatalf("linkErrors: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkErrors returned %d, want 3", count)
+	}
+
+	tests := []struct {
+		fn      string
+		targets []string
+	}{
+		// The marked error class wins over a model of the same name;
+		// ValueError has no definition.
+		{"charge", []string{"py-declined"}},
+		// Qualified Ruby names pick the matching namespace.
+		{"refund", []string{"rb-declined"}},
+		// Unqualified Go names stay in the package; DeclinedError lives in
+		// another package and sql is not indexed.
+		{"load", []string{"go-notfound"}},
+	}
+	for _, tt := range tests {
+		edges, err := store.GetEdges(ctx, tt.fn, graph.EdgeThrows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var targets []string
+		for _, e := range edges {
+			if e.SourceID == tt.fn {
+				targets = append(targets, e.TargetID)
+				if e.Properties[graph.PropConfidence] != graph.ConfidenceExact {
+					t.Errorf("%s -> %s confidence = %q, want exact", tt.fn, e.TargetID, e.Properties[graph.PropConfidence])
+				}
+			}
+		}
+		if len(targets) != len(tt.targets) || (len(targets) > 0 && targets[0] != tt.targets[0]) {
+			t.Errorf("%s throws %v, want %v", tt.fn, targets, tt.targets)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package linker
+
+import (
+	"context"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkExternalStores adds the caches, search engines and object stores
+// services talk to to the service graph. The parsers link the functions
+// calling a Redis, Memcached, Elasticsearch or S3 client to an
+// ExternalStore node with Uses edges; this phase creates a Uses edge from
+// the service of each such function to the store, listing the operations
+// of all its functions.
+func (l *Linker) linkExternalStores(ctx context.Context) (int, error) {
+	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return 0, err
+	}
+	byGroup := l.servicesByGroup(services)
+	if len(byGroup) == 0 {
+		return 0, nil
+	}
+	stores, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeExternalStore})
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, st := range stores {
+		edges, err := l.store.GetIncomingEdges(ctx, st.ID, graph.EdgeUses)
+		if err != nil {
+			return linked, err
+		}
+		ops := make(map[string]string) // service ID -> operations
+		var order []string
+		for _, e := range edges {
+			user, err := l.store.GetNode(ctx, e.SourceID)
+			if err != nil || user == nil || user.Type == graph.NodeService {
+				continue
+			}
+			svc, ok := byGroup[l.group(user.FilePath)]
+			if !ok {
+				continue
+			}
+			if _, seen := ops[svc.ID]; !seen {
+				order = append(order, svc.ID)
+			}
+			ops[svc.ID] = parser.MergeStoreOperations(ops[svc.ID], e.Properties["operations"])
+		}
+		for _, svcID := range order {
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeUses, svcID, st.ID),
+				Type:       graph.EdgeUses,
+				SourceID:   svcID,
+				TargetID:   st.ID,
+				Properties: withConfidence(map[string]string{"operations": ops[svcID]}, graph.ConfidenceExact, scoreExact),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+			if l.verbose {
+				l.log("    Service %s uses %s %s (%s)", svcID, st.Properties["kind"], st.Name, ops[svcID])
+			}
+		}
+	}
+	return linked, nil
+}
@@ -0,0 +1,69 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkExternalStores(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	redis := graph.NewNodeID(string(graph.NodeExternalStore), "", "redis:redis")
+	bucket := graph.NewNodeID(string(graph.NodeExternalStore), "", "s3:invoices")
+	addNodes(t, store,
+		&graph.Node{ID: "svc-orders", Type: graph.NodeService, Name: "orders"},
+		&graph.Node{ID: "svc-billing", Type: graph.NodeService, Name: "billing"},
+		&graph.Node{ID: redis, Type: graph.NodeExternalStore, Name: "redis", Properties: map[string]string{"kind": "redis"}},
+		&graph.Node{ID: bucket, Type: graph.NodeExternalStore, Name: "invoices", Properties: map[string]string{"kind": "s3"}},
+		&graph.Node{ID: "load", Type: graph.NodeFunction, Name: "Load", FilePath: "orders/cache.go"},
+		&graph.Node{ID: "warm", Type: graph.NodeFunction, Name: "Warm", FilePath: "orders/warm.go"},
+		&graph.Node{ID: "invoice", Type: graph.NodeFunction, Name: "Invoice", FilePath: "billing/invoice.py"},
+		&graph.Node{ID: "script", Type: graph.NodeFile, Name: "seed.rb", FilePath: "scripts/seed.rb"},
+	)
+	for _, e := range []*graph.Edge{
+		{ID: "u1", Type: graph.EdgeUses, SourceID: "load", TargetID: redis, Properties: map[string]string{"operations": "read"}},
+		{ID: "u2", Type: graph.EdgeUses, SourceID: "warm", TargetID: redis, Properties: map[string]string{"operations": "delete,write"}},
+		{ID: "u3", Type: graph.EdgeUses, SourceID: "invoice", TargetID: redis, Properties: map[string]string{"operations": "read"}},
+		{ID: "u4", Type: graph.EdgeUses, SourceID: "invoice", TargetID: bucket, Properties: map[string]string{"operations": "write"}},
+		{ID: "u5", Type: graph.EdgeUses, SourceID: "script", TargetID: bucket, Properties: map[string]string{"operations": "read"}},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkExternalStores(ctx)
+	if err != nil {
+		t.Fatalf("linkExternalStores: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkExternalStores returned %d, want 3", count)
+	}
+
+	got := make(map[[2]string]string)
+	for _, svc := range []string{"svc-orders", "svc-billing"} {
+		edges, err := store.GetEdges(ctx, svc, graph.EdgeUses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range edges {
+			got[[2]string{e.SourceID, e.TargetID}] = e.Properties["operations"]
+		}
+	}
+	want := map[[2]string]string{
+		{"svc-orders", redis}:   "delete,read,write",
+		{"svc-billing", redis}:  "read",
+		{"svc-billing", bucket}: "write",
+	}
+	if len(got) != len(want) {
+		t.Errorf("service Uses edges = %v, want %v", got, want)
+	}
+	for k, ops := range want {
+		if got[k] != ops {
+			t.Errorf("%s -> %s operations = %q, want %q", k[0], k[1], got[k], ops)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package linker
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// linkGoMethods adds a Contains edge from each Go struct or named type to
+// the methods declared on it in other files of its package, so methods
+// hang off their type wherever they are declared. The parser links the
+// methods declared in the type's own file. A method's receiver is matched
+// by name to a type in the same directory and package clause; a type
+// declared once per platform (foo_linux.go, foo_windows.go) gets the
+// methods built with its variant.
+func (l *Linker) linkGoMethods(ctx context.Context) (int, error) {
+	methods, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeMethod, Language: "go"})
+	if err != nil || len(methods) == 0 {
+		return 0, err
+	}
+
+	typeByKey := make(map[string][]*graph.Node) // dir + package + name
+	for _, kind := range []graph.NodeType{graph.NodeStruct, graph.NodeType_} {
+		nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: kind, Language: "go"})
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range nodes {
+			key := goTypeKey(n.FilePath, n.Package, n.Name)
+			typeByKey[key] = append(typeByKey[key], n)
+		}
+	}
+
+	linked := 0
+	for _, m := range methods {
+		recv, _, _ := strings.Cut(m.Properties["receiver"], "[") // generic receivers
+		for _, t := range typeByKey[goTypeKey(m.FilePath, m.Package, recv)] {
+			if t.FilePath == m.FilePath || !graph.BuildTogether(t, m) {
+				continue
+			}
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeContains, t.ID, m.ID),
+				Type:       graph.EdgeContains,
+				SourceID:   t.ID,
+				TargetID:   m.ID,
+				Properties: withConfidence(nil, graph.ConfidenceExact, scoreExact),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+			if l.verbose {
+				l.log("    Go method: %s contains %s", t.Name, m.Name)
+			}
+		}
+	}
+	return linked, nil
+}
+
+// goTypeKey identifies a Go type by the directory and package clause of
+// its file and its name.
+func goTypeKey(filePath, pkg, name string) string {
+	return path.Dir(filePath) + "\x00" + pkg + "\x00" + name
+}
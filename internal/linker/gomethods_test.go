@@ -0,0 +1,72 @@
+package linker
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkGoMethods(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	method := func(id, recv, pkg, file string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeMethod, Name: id, Package: pkg, Language: "go", FilePath: file, Properties: map[string]string{"receiver": recv}}
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "store", Type: graph.NodeStruct, Name: "Store", Package: "store", Language: "go", FilePath: "api/store/store.go"},
+		&graph.Node{ID: "set", Type: graph.NodeType_, Name: "Set", Package: "store", Language: "go", FilePath: "api/store/set.go"},
+		&graph.Node{ID: "other-store", Type: graph.NodeStruct, Name: "Store", Package: "cache", Language: "go", FilePath: "api/cache/cache.go"},
+		method("Get", "Store", "store", "api/store/get.go"),
+		method("Add", "Set[T]", "store", "api/store/add.go"),
+		// Same file: left to the parser.
+		method("Close", "Store", "store", "api/store/store.go"),
+		// Same directory, external test package.
+		method("Fake", "Store", "store_test", "api/store/store_test.go"),
+		// Another directory.
+		method("Put", "Store", "store", "api/other/put.go"),
+	)
+	// A type declared per platform gets the methods built with each variant.
+	platform := func(n *graph.Node, buildConstraint string) *graph.Node {
+		if n.Properties == nil {
+			n.Properties = make(map[string]string)
+		}
+		n.Properties[graph.PropBuildConstraint] = buildConstraint
+		return n
+	}
+	addNodes(t, store,
+		platform(&graph.Node{ID: "conn-unix", Type: graph.NodeStruct, Name: "conn", Package: "store", Language: "go", FilePath: "api/store/conn_unix.go"}, "unix"),
+		platform(&graph.Node{ID: "conn-windows", Type: graph.NodeStruct, Name: "conn", Package: "store", Language: "go", FilePath: "api/store/conn_windows.go"}, "windows"),
+		method("Dial", "conn", "store", "api/store/dial.go"),
+		platform(method("Fd", "conn", "store", "api/store/fd_linux.go"), "linux"),
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkGoMethods(ctx)
+	if err != nil {
+		t.Fatalf("linkGoMethods: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("linked = %d, want 5", count)
+	}
+
+	var got []string
+	for _, typ := range []string{"store", "set", "other-store", "conn-unix", "conn-windows"} {
+		edges, err := store.GetEdges(ctx, typ, graph.EdgeContains)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range edges {
+			if e.SourceID == typ {
+				got = append(got, typ+" -> "+e.TargetID)
+			}
+		}
+	}
+	sort.Strings(got)
+	want := []string{"conn-unix -> Dial", "conn-unix -> Fd", "conn-windows -> Dial", "set -> Add", "store -> Get"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Contains edges = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package linker
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// goModule is a go.mod in the repository: its module path and directory.
+type goModule struct {
+	path string
+	dir  string
+}
+
+// linkGoModuleImports classifies Go imports against the repository's go.mod
+// module paths. An import of a package inside one of the repo's modules (or
+// go.work members) is marked internal=true, with Properties["module"] naming
+// the module and, when the package's directory is indexed,
+// Properties["resolved_path"] naming that directory. The Package node of
+// every importing file then gets an Imports edge (kind=internal) to the
+// imported package's Package node, the one declared by the lowest-sorting
+// non-test file in the directory, so package dependencies can be followed
+// inside the repository.
+func (l *Linker) linkGoModuleImports(ctx context.Context) (int, error) {
+	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeService,
+		Properties: map[string]string{"ecosystem": "go"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var modules []goModule
+	for _, s := range services {
+		if path.Base(s.FilePath) == "go.mod" {
+			modules = append(modules, goModule{path: s.Name, dir: path.Dir(s.FilePath)})
+		}
+	}
+	if len(modules) == 0 {
+		return 0, nil
+	}
+	// Longest module path first so nested modules win.
+	sort.Slice(modules, func(i, j int) bool { return len(modules[i].path) > len(modules[j].path) })
+
+	pkgs, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodePackage, Language: "go"})
+	if err != nil {
+		return 0, err
+	}
+	byFile := make(map[string]*graph.Node) // file path -> package node
+	byDir := make(map[string]*graph.Node)  // directory -> canonical package node
+	for _, p := range pkgs {
+		byFile[p.FilePath] = p
+		if strings.HasSuffix(p.FilePath, "_test.go") {
+			continue
+		}
+		dir := path.Dir(p.FilePath)
+		if cur := byDir[dir]; cur == nil || p.FilePath < cur.FilePath {
+			byDir[dir] = p
+		}
+	}
+
+	imports, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Language:   "go",
+		Properties: map[string]string{"kind": "import"},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, imp := range imports {
+		mod, dir, ok := goImportDir(modules, imp.Name)
+		if !ok {
+			continue
+		}
+		imp.Properties["internal"] = "true"
+		imp.Properties["module"] = mod.path
+		target := byDir[dir]
+		if target != nil {
+			imp.Properties["resolved_path"] = dir
+		}
+		if err := l.store.UpdateNode(ctx, imp); err != nil {
+			return linked, err
+		}
+
+		if target == nil {
+			continue
+		}
+		files, err := l.importingFiles(ctx, imp)
+		if err != nil {
+			return linked, err
+		}
+		for _, file := range files {
+			source := byFile[file]
+			if source == nil || path.Dir(source.FilePath) == dir {
+				continue
+			}
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeImports, source.ID, target.ID),
+				Type:       graph.EdgeImports,
+				SourceID:   source.ID,
+				TargetID:   target.ID,
+				Properties: withConfidence(map[string]string{"kind": "internal", "import_path": imp.Name}, graph.ConfidenceExact, scoreExact),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+
+			if l.verbose {
+				l.log("    Go import: %s in %s resolved to %s", imp.Name, file, dir)
+			}
+		}
+	}
+	return linked, nil
+}
+
+// goImportDir maps an import path to the module containing it and the
+// package's repo-relative directory. modules must be sorted longest path
+// first.
+func goImportDir(modules []goModule, importPath string) (goModule, string, bool) {
+	for _, m := range modules {
+		if importPath == m.path {
+			return m, m.dir, true
+		}
+		if rest, ok := strings.CutPrefix(importPath, m.path+"/"); ok {
+			return m, path.Join(m.dir, rest), true
+		}
+	}
+	return goModule{}, "", false
+}
@@ -0,0 +1,137 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkGoModuleImports(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	goNode := func(n *graph.Node) *graph.Node { n.Language = "go"; return n }
+	pkg := func(file, name string) *graph.Node {
+		return goNode(&graph.Node{ID: "pkg-" + file, Type: graph.NodePackage, Name: name, FilePath: file})
+	}
+	imp := func(id, importPath, file string) *graph.Node {
+		return goNode(&graph.Node{ID: id, Type: graph.NodeDependency, Name: importPath, FilePath: file, Properties: map[string]string{"kind": "import"}})
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "mod-root", Type: graph.NodeService, Name: "github.com/acme/shop", FilePath: "go.mod", Language: "manifest",
+			Properties: map[string]string{"kind": "service", "ecosystem": "go"}},
+		&graph.Node{ID: "mod-tools", Type: graph.NodeService, Name: "github.com/acme/shop/tools", FilePath: "tools/go.mod", Language: "manifest",
+			Properties: map[string]string{"kind": "service", "ecosystem": "go"}},
+		pkg("cmd/shop/main.go", "main"),
+		pkg("internal/store/store.go", "store"),
+		pkg("internal/store/cache.go", "store"),
+		pkg("internal/store/a_test.go", "store_test"),
+		pkg("tools/gen/gen.go", "gen"),
+		imp("dep-store", "github.com/acme/shop/internal/store", "cmd/shop/main.go"),
+		imp("dep-gen", "github.com/acme/shop/tools/gen", "cmd/shop/main.go"),
+		imp("dep-api", "github.com/acme/shop/api", "cmd/shop/main.go"),
+		imp("dep-chi", "github.com/go-chi/chi/v5", "cmd/shop/main.go"),
+		imp("dep-other", "github.com/acme/shopping", "cmd/shop/main.go"),
+		imp("dep-self", "github.com/acme/shop/internal/store", "internal/store/a_test.go"),
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkGoModuleImports(ctx)
+	if err != nil {
+		t.Fatalf("linkGoModuleImports: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linked %d package imports, want 2", count)
+	}
+
+	tests := []struct {
+		id, internal, module, resolved string
+	}{
+		{"dep-store", "true", "github.com/acme/shop", "internal/store"},
+		{"dep-gen", "true", "github.com/acme/shop/tools", "tools/gen"},
+		{"dep-api", "true", "github.com/acme/shop", ""},
+		{"dep-chi", "", "", ""},
+		{"dep-other", "", "", ""},
+		{"dep-self", "true", "github.com/acme/shop", "internal/store"},
+	}
+	for _, tt := range tests {
+		n, err := store.GetNode(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.Properties["internal"] != tt.internal || n.Properties["module"] != tt.module || n.Properties["resolved_path"] != tt.resolved {
+			t.Errorf("%s: internal=%q module=%q resolved_path=%q, want %q %q %q", tt.id,
+				n.Properties["internal"], n.Properties["module"], n.Properties["resolved_path"], tt.internal, tt.module, tt.resolved)
+		}
+	}
+
+	edges, err := store.GetEdges(ctx, "pkg-cmd/shop/main.go", graph.EdgeImports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := make(map[string]bool)
+	for _, e := range edges {
+		if e.Properties["kind"] == "internal" {
+			targets[e.TargetID] = true
+		}
+	}
+	// The package is represented by its lowest-sorting non-test file.
+	for _, want := range []string{"pkg-internal/store/cache.go", "pkg-tools/gen/gen.go"} {
+		if !targets[want] {
+			t.Errorf("missing Imports edge to %s (got %v)", want, targets)
+		}
+	}
+}
+
+func TestLinkGoModuleImportsSharedDependency(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// One shared node for the package, imported by two files of different
+	// packages and by a file of the package itself.
+	depID := graph.NewDependencyID("go", "github.com/acme/shop/internal/store")
+	pkg := func(file, name string) *graph.Node {
+		return &graph.Node{ID: "pkg-" + file, Type: graph.NodePackage, Name: name, FilePath: file, Language: "go"}
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "mod-root", Type: graph.NodeService, Name: "github.com/acme/shop", FilePath: "go.mod", Language: "manifest",
+			Properties: map[string]string{"kind": "service", "ecosystem": "go"}},
+		pkg("cmd/shop/main.go", "main"),
+		pkg("cmd/admin/main.go", "main"),
+		pkg("internal/store/store.go", "store"),
+		pkg("internal/store/store_test.go", "store"),
+		&graph.Node{ID: depID, Type: graph.NodeDependency, Name: "github.com/acme/shop/internal/store", Language: "go",
+			Properties: map[string]string{"kind": "import"}},
+	)
+	for _, file := range []string{"cmd/shop/main.go", "cmd/admin/main.go", "internal/store/store_test.go"} {
+		src := "pkg-" + file
+		if err := store.AddEdge(ctx, &graph.Edge{ID: graph.NewEdgeID(graph.EdgeImports, src, depID), Type: graph.EdgeImports,
+			SourceID: src, TargetID: depID, Properties: map[string]string{"line": "3"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkGoModuleImports(ctx)
+	if err != nil {
+		t.Fatalf("linkGoModuleImports: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linked %d package imports, want 2", count)
+	}
+	for _, file := range []string{"cmd/shop/main.go", "cmd/admin/main.go"} {
+		edges, err := store.QueryEdges(ctx, graph.EdgeFilter{SourceID: "pkg-" + file, TargetID: "pkg-internal/store/store.go", Type: graph.EdgeImports})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(edges) != 1 || edges[0].Properties["kind"] != "internal" {
+			t.Errorf("%s: got %d internal Imports edges to the store package, want 1", file, len(edges))
+		}
+	}
+	n, err := store.GetNode(ctx, depID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Properties["resolved_path"] != "internal/store" {
+		t.Errorf("resolved_path = %q, want internal/store", n.Properties["resolved_path"])
+	}
+}
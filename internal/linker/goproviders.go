@@ -0,0 +1,286 @@
+package linker
+
+import (
+	"context"
+	"go/ast"
+	goparser "go/parser"
+	"go/types"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// goProvider is a constructor or invoked function registered with a Go DI
+// container, with the parameter and result types of its signature.
+type goProvider struct {
+	node       *graph.Node // the Function, or the registration for literals
+	reg        *graph.Node
+	params     []string
+	results    []string
+	candidates int
+}
+
+// linkGoProviders resolves the google/wire, uber fx and dig registrations
+// the Go parser recorded as Dependency nodes (kind=di_provider). Each
+// provided constructor gets a Provides edge to the type it returns; each
+// provider whose type another provider or invoked function takes as a
+// parameter gets an InjectedInto edge to it. wire.Bind(new(I), new(*T))
+// adds a DependsOn edge (kind=di_registration) from I to T, as ASP.NET Core
+// registrations do, and lets providers of T satisfy parameters of type I.
+// Providers only satisfy parameters within their service.
+func (l *Linker) linkGoProviders(ctx context.Context) (int, error) {
+	regs, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Language:   "go",
+		Properties: map[string]string{"kind": "di_provider"},
+	})
+	if err != nil || len(regs) == 0 {
+		return 0, err
+	}
+
+	funcs, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFunction, Language: "go"})
+	if err != nil {
+		return 0, err
+	}
+	funcByName := make(map[string][]*graph.Node)
+	for _, fn := range funcs {
+		funcByName[fn.Name] = append(funcByName[fn.Name], fn)
+	}
+	typeByName := make(map[string][]*graph.Node)
+	for _, kind := range []graph.NodeType{graph.NodeStruct, graph.NodeInterface, graph.NodeType_} {
+		nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: kind, Language: "go"})
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range nodes {
+			typeByName[n.Name] = append(typeByName[n.Name], n)
+		}
+	}
+
+	linked := 0
+	addEdge := func(edge *graph.Edge) {
+		if err := l.store.AddEdge(ctx, edge); err == nil {
+			linked++
+		}
+	}
+
+	var providers, consumers []goProvider
+	bindings := make(map[string][]string) // group + "\x00" + interface -> implementations
+	for _, reg := range regs {
+		if reg.Properties["role"] == "bind" {
+			iface := goQualify(reg.Properties["interface"], reg.Package)
+			impl := goQualify(reg.Properties["implementation"], reg.Package)
+			key := l.group(reg.FilePath) + "\x00" + iface
+			bindings[key] = appendString(bindings[key], impl)
+
+			from, _ := goQualifiedMatch(reg, iface, typeByName)
+			to, candidates := goQualifiedMatch(reg, impl, typeByName)
+			if from == nil || to == nil || from.ID == to.ID {
+				continue
+			}
+			level, score := nameMatchConfidence(candidates)
+			addEdge(&graph.Edge{
+				ID:       graph.NewEdgeID(graph.EdgeDependsOn, from.ID, to.ID),
+				Type:     graph.EdgeDependsOn,
+				SourceID: from.ID,
+				TargetID: to.ID,
+				Properties: withConfidence(map[string]string{
+					"kind":          "di_registration",
+					"container":     reg.Properties["container"],
+					"registered_in": reg.FilePath,
+				}, level, score),
+			})
+			if l.verbose {
+				l.log("    DI binding: %s -> %s (%s)", from.Name, to.Name, reg.Properties["container"])
+			}
+			continue
+		}
+
+		p, ok := resolveGoProvider(reg, funcByName)
+		if !ok {
+			continue
+		}
+		consumers = append(consumers, p)
+		if reg.Properties["role"] == "provide" {
+			providers = append(providers, p)
+		}
+	}
+
+	byType := make(map[string][]goProvider) // group + "\x00" + type -> providers
+	for _, p := range providers {
+		group := l.group(p.reg.FilePath)
+		for _, result := range p.results {
+			key := group + "\x00" + result
+			if !goProviderListed(byType[key], p) {
+				byType[key] = append(byType[key], p)
+			}
+			target, candidates := goQualifiedMatch(p.node, result, typeByName)
+			if target == nil {
+				continue
+			}
+			props := map[string]string{
+				"container":     p.reg.Properties["container"],
+				"registered_in": p.reg.FilePath,
+			}
+			if set := p.reg.Properties["set"]; set != "" {
+				props["set"] = set
+			}
+			level, score := nameMatchConfidence(max(p.candidates, candidates))
+			addEdge(&graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeProvides, p.node.ID, target.ID),
+				Type:       graph.EdgeProvides,
+				SourceID:   p.node.ID,
+				TargetID:   target.ID,
+				Properties: withConfidence(props, level, score),
+			})
+			if l.verbose {
+				l.log("    DI provider: %s provides %s (%s)", p.node.Name, target.Name, p.reg.Properties["container"])
+			}
+		}
+	}
+
+	for _, c := range consumers {
+		group := l.group(c.reg.FilePath)
+		for _, param := range c.params {
+			suppliers := byType[group+"\x00"+param]
+			for _, impl := range bindings[group+"\x00"+param] {
+				suppliers = append(suppliers, byType[group+"\x00"+impl]...)
+			}
+			for _, p := range suppliers {
+				if p.node.ID == c.node.ID {
+					continue
+				}
+				level, score := nameMatchConfidence(max(p.candidates, c.candidates))
+				addEdge(&graph.Edge{
+					ID:       graph.NewEdgeID(graph.EdgeInjectedInto, p.node.ID, c.node.ID),
+					Type:     graph.EdgeInjectedInto,
+					SourceID: p.node.ID,
+					TargetID: c.node.ID,
+					Properties: withConfidence(map[string]string{
+						"type":      param,
+						"container": c.reg.Properties["container"],
+					}, level, score),
+				})
+				if l.verbose {
+					l.log("    DI injection: %s -> %s (%s)", p.node.Name, c.node.Name, param)
+				}
+			}
+		}
+	}
+	return linked, nil
+}
+
+// resolveGoProvider finds the function a registration names, in the
+// package its qualifier names or else the registration's own. Function
+// literals are their own provider.
+func resolveGoProvider(reg *graph.Node, funcByName map[string][]*graph.Node) (goProvider, bool) {
+	if sig := reg.Properties["signature"]; sig != "" {
+		params, results, ok := goSignatureTypes(sig, reg.Package)
+		return goProvider{node: reg, reg: reg, params: params, results: results, candidates: 1}, ok
+	}
+	name := reg.Properties["provider"]
+	if name == "" {
+		return goProvider{}, false
+	}
+	fn, candidates := goQualifiedMatch(reg, goQualify(name, reg.Package), funcByName)
+	if fn == nil {
+		return goProvider{}, false
+	}
+	params, results, ok := goSignatureTypes(fn.Signature, fn.Package)
+	return goProvider{node: fn, reg: reg, params: params, results: results, candidates: candidates}, ok
+}
+
+// goQualifiedMatch picks the node a package-qualified name ("store.Store")
+// names, preferring one in ref's directory among same-named packages. It
+// also returns the number of candidates.
+func goQualifiedMatch(ref *graph.Node, name string, byName map[string][]*graph.Node) (*graph.Node, int) {
+	pkg, simple, _ := strings.Cut(name, ".")
+	var candidates []*graph.Node
+	for _, c := range byName[simple] {
+		if c.Package == pkg {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, 0
+	}
+	dir := path.Dir(ref.FilePath)
+	for _, c := range candidates {
+		if path.Dir(c.FilePath) == dir {
+			return c, len(candidates)
+		}
+	}
+	return candidates[0], len(candidates)
+}
+
+// goQualify qualifies an unqualified name with pkg.
+func goQualify(name, pkg string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return pkg + "." + name
+}
+
+// goSignatureTypes parses a Go function signature such as
+// "func NewStore(db *sql.DB, log Logger) (*Store, error)" declared in pkg
+// into the package-qualified names of its parameter and result types
+// ("sql.DB", "pkg.Logger"), dropping pointers, builtins and error.
+func goSignatureTypes(sig, pkg string) (params, results []string, ok bool) {
+	open := strings.Index(sig, "(")
+	if open < 0 {
+		return nil, nil, false
+	}
+	expr, err := goparser.ParseExpr("func" + sig[open:])
+	if err != nil {
+		return nil, nil, false
+	}
+	ft, isFunc := expr.(*ast.FuncType)
+	if !isFunc {
+		return nil, nil, false
+	}
+	return goFieldTypes(ft.Params, pkg), goFieldTypes(ft.Results, pkg), true
+}
+
+// goFieldTypes returns the distinct injectable type names of a parameter
+// or result list.
+func goFieldTypes(fl *ast.FieldList, pkg string) []string {
+	if fl == nil {
+		return nil
+	}
+	var out []string
+	for _, f := range fl.List {
+		x := f.Type
+		if star, ok := x.(*ast.StarExpr); ok {
+			x = star.X
+		}
+		var name string
+		switch t := x.(type) {
+		case *ast.Ident:
+			if types.Universe.Lookup(t.Name) != nil {
+				continue
+			}
+			name = pkg + "." + t.Name
+		case *ast.SelectorExpr:
+			id, ok := t.X.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			name = id.Name + "." + t.Sel.Name
+		default:
+			continue
+		}
+		out = appendString(out, name)
+	}
+	return out
+}
+
+// goProviderListed reports whether p is already among list.
+func goProviderListed(list []goProvider, p goProvider) bool {
+	for _, q := range list {
+		if q.node.ID == p.node.ID {
+			return true
+		}
+	}
+	return false
+}
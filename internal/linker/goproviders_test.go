@@ -0,0 +1,82 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkGoProviders(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	fn := func(id, name, pkg, file, sig string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeFunction, Name: name, Package: pkg, Language: "go", FilePath: file, Signature: sig}
+	}
+	reg := func(id, pkg, file string, props map[string]string) *graph.Node {
+		props["kind"] = "di_provider"
+		return &graph.Node{ID: id, Type: graph.NodeDependency, Name: id, Package: pkg, Language: "go", FilePath: file, Properties: props}
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "store-type", Type: graph.NodeStruct, Name: "Store", Package: "store", Language: "go", FilePath: "api/store/store.go"},
+		&graph.Node{ID: "repo-type", Type: graph.NodeInterface, Name: "Repo", Package: "users", Language: "go", FilePath: "api/users/repo.go"},
+		&graph.Node{ID: "service-type", Type: graph.NodeStruct, Name: "Service", Package: "users", Language: "go", FilePath: "api/users/service.go"},
+		// A same-named struct in another package must not be matched.
+		&graph.Node{ID: "other-service", Type: graph.NodeStruct, Name: "Service", Package: "billing", Language: "go", FilePath: "api/billing/service.go"},
+		fn("new-store", "NewStore", "store", "api/store/store.go", "func NewStore(db *sql.DB) (*Store, error)"),
+		fn("new-service", "NewService", "users", "api/users/service.go", "func NewService(r Repo, log *zap.Logger) *Service"),
+		fn("register", "Register", "users", "api/users/routes.go", "func Register(s *Service)"),
+		reg("r-store", "app", "api/app/wire.go", map[string]string{"container": "wire", "role": "provide", "provider": "store.NewStore", "set": "AppSet"}),
+		reg("r-bind", "users", "api/users/wire.go", map[string]string{"container": "wire", "role": "bind", "interface": "Repo", "implementation": "store.Store"}),
+		reg("r-service", "users", "api/users/module.go", map[string]string{"container": "fx", "role": "provide", "provider": "NewService"}),
+		reg("r-register", "users", "api/users/module.go", map[string]string{"container": "fx", "role": "invoke", "provider": "Register"}),
+		reg("r-missing", "users", "api/users/module.go", map[string]string{"container": "fx", "role": "provide", "provider": "NewGhost"}),
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkGoProviders(ctx)
+	if err != nil {
+		t.Fatalf("linkGoProviders: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("linkGoProviders returned %d, want 5", count)
+	}
+
+	want := map[graph.EdgeType][]string{
+		graph.EdgeProvides:     {"new-store->store-type", "new-service->service-type"},
+		graph.EdgeInjectedInto: {"new-store->new-service", "new-service->register"},
+		graph.EdgeDependsOn:    {"repo-type->store-type"},
+	}
+	for et, pairs := range want {
+		edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: et})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]*graph.Edge)
+		for _, e := range edges {
+			got[e.SourceID+"->"+e.TargetID] = e
+		}
+		for _, pair := range pairs {
+			if got[pair] == nil {
+				t.Errorf("missing %s edge %s; got %v", et, pair, got)
+			}
+		}
+		if len(got) != len(pairs) {
+			t.Errorf("got %d %s edges, want %d", len(got), et, len(pairs))
+		}
+	}
+
+	edges, err := store.GetEdges(ctx, "new-store", graph.EdgeInjectedInto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range edges {
+		if e.SourceID == "new-store" && e.Properties["type"] != "users.Repo" {
+			t.Errorf("injected type = %q, want users.Repo", e.Properties["type"])
+		}
+	}
+	provides, err := store.GetEdges(ctx, "new-store", graph.EdgeProvides)
+	if err != nil || len(provides) != 1 || provides[0].Properties["set"] != "AppSet" || provides[0].Properties["container"] != "wire" {
+		t.Errorf("Provides edge properties = %+v, %v", provides, err)
+	}
+}
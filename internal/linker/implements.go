@@ -8,7 +8,7 @@ import (
 )
 
 // linkImplements resolves cross-file implements relationships.
-// It handles three language families:
+// It handles three language families, after merging C# partial classes:
 //   - Go (structural typing): checks if struct method sets satisfy interface method sets
 //   - Java/TypeScript (nominal typing): resolves Properties["implements"] to Interface nodes
 //   - Python Protocol: resolves classes that inherit from Protocol interfaces
@@ -18,6 +18,13 @@ func (l *Linker) linkImplements(ctx context.Context) (int, error) {
 
 	linked := 0
 
+	// --- C# partial classes (merged first so their parts share interfaces) ---
+	partialLinked, err := l.linkPartialClasses(ctx)
+	if err != nil {
+		return linked, err
+	}
+	linked += partialLinked
+
 	// --- Go structural typing ---
 	goLinked, err := l.linkGoImplements(ctx, existing)
 	if err != nil {
@@ -103,9 +110,13 @@ func (l *Linker) linkGoImplements(ctx context.Context, existing map[string]bool)
 			continue
 		}
 
+		// Methods of platform variants of the struct (same name, other
+		// build constraints) are not part of its method set.
 		structMethodNames := make(map[string]bool)
 		for _, m := range methods {
-			structMethodNames[m.Name] = true
+			if graph.BuildTogether(s, m) {
+				structMethodNames[m.Name] = true
+			}
 		}
 
 		// Check against each interface.
@@ -133,13 +144,13 @@ func (l *Linker) linkGoImplements(ctx context.Context, existing map[string]bool)
 			}
 
 			edge := &graph.Edge{
-				ID:       graph.NewNodeID(string(graph.EdgeImplements), s.ID, iface.node.ID),
+				ID:       graph.NewEdgeID(graph.EdgeImplements, s.ID, iface.node.ID),
 				Type:     graph.EdgeImplements,
 				SourceID: s.ID,
 				TargetID: iface.node.ID,
-				Properties: map[string]string{
+				Properties: withConfidence(map[string]string{
 					"kind": "structural",
-				},
+				}, graph.ConfidenceHeuristic, scoreStrong),
 			}
 			if err := l.store.AddEdge(ctx, edge); err != nil {
 				continue
@@ -156,7 +167,14 @@ func (l *Linker) linkGoImplements(ctx context.Context, existing map[string]bool)
 	return linked, nil
 }
 
-// linkNominalImplements resolves nominal implements relationships for Java and TypeScript.
+// nominalSkip lists the languages whose classes have a dedicated implements
+// resolution phase.
+var nominalSkip = map[string]bool{"java": true, "csharp": true, "typescript": true}
+
+// linkNominalImplements resolves nominal implements relationships by name,
+// for languages without a resolution phase of their own (such as those of
+// external parsers): Java classes are left to linkJavaHierarchy, C# classes
+// to linkCSharpImplements and TypeScript classes to linkTSImplements.
 func (l *Linker) linkNominalImplements(ctx context.Context, existing map[string]bool) (int, error) {
 	// Query all classes with "implements" property.
 	classes, err := l.store.QueryNodes(ctx, graph.NodeFilter{
@@ -181,7 +199,7 @@ func (l *Linker) linkNominalImplements(ctx context.Context, existing map[string]
 
 	linked := 0
 	for _, cls := range classes {
-		if cls.Properties == nil {
+		if cls.Properties == nil || nominalSkip[cls.Language] {
 			continue
 		}
 		implStr := cls.Properties["implements"]
@@ -201,7 +219,7 @@ func (l *Linker) linkNominalImplements(ctx context.Context, existing map[string]
 			}
 
 			// Prefer same-package match.
-			target := bestMatch(cls, candidates)
+			target := l.bestMatch(cls, candidates)
 			if target == nil {
 				continue
 			}
@@ -210,15 +228,16 @@ func (l *Linker) linkNominalImplements(ctx context.Context, existing map[string]
 			if existing[edgeKey] {
 				continue
 			}
+			level, score := nameMatchConfidence(len(candidates))
 
 			edge := &graph.Edge{
-				ID:       graph.NewNodeID(string(graph.EdgeImplements), cls.ID, target.ID),
+				ID:       graph.NewEdgeID(graph.EdgeImplements, cls.ID, target.ID),
 				Type:     graph.EdgeImplements,
 				SourceID: cls.ID,
 				TargetID: target.ID,
-				Properties: map[string]string{
+				Properties: withConfidence(map[string]string{
 					"kind": "nominal",
-				},
+				}, level, score),
 			}
 			if err := l.store.AddEdge(ctx, edge); err != nil {
 				continue
@@ -277,7 +296,7 @@ func (l *Linker) linkPythonProtocol(ctx context.Context, existing map[string]boo
 				continue
 			}
 
-			target := bestMatch(cls, candidates)
+			target := l.bestMatch(cls, candidates)
 			if target == nil {
 				continue
 			}
@@ -286,15 +305,16 @@ func (l *Linker) linkPythonProtocol(ctx context.Context, existing map[string]boo
 			if existing[edgeKey] {
 				continue
 			}
+			level, score := nameMatchConfidence(len(candidates))
 
 			edge := &graph.Edge{
-				ID:       graph.NewNodeID(string(graph.EdgeImplements), cls.ID, target.ID),
+				ID:       graph.NewEdgeID(graph.EdgeImplements, cls.ID, target.ID),
 				Type:     graph.EdgeImplements,
 				SourceID: cls.ID,
 				TargetID: target.ID,
-				Properties: map[string]string{
+				Properties: withConfidence(map[string]string{
 					"kind": "protocol",
-				},
+				}, level, score),
 			}
 			if err := l.store.AddEdge(ctx, edge); err != nil {
 				continue
@@ -313,7 +333,7 @@ func (l *Linker) linkPythonProtocol(ctx context.Context, existing map[string]boo
 
 // bestMatch returns the best matching interface node for a class,
 // preferring same-directory, then same-package matches.
-func bestMatch(cls *graph.Node, candidates []*graph.Node) *graph.Node {
+func (l *Linker) bestMatch(cls *graph.Node, candidates []*graph.Node) *graph.Node {
 	if len(candidates) == 0 {
 		return nil
 	}
@@ -321,10 +341,10 @@ func bestMatch(cls *graph.Node, candidates []*graph.Node) *graph.Node {
 		return candidates[0]
 	}
 
-	// Prefer same top-level directory (same service).
-	clsDir := topDir(cls.FilePath)
+	// Prefer same service.
+	clsDir := l.group(cls.FilePath)
 	for _, c := range candidates {
-		if topDir(c.FilePath) == clsDir {
+		if l.group(c.FilePath) == clsDir {
 			return c
 		}
 	}
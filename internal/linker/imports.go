@@ -2,6 +2,8 @@ package linker
 
 import (
 	"context"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
@@ -10,6 +12,8 @@ import (
 // linkImports connects import NodeDependency nodes to their corresponding
 // manifest NodeDependency nodes via EdgeDependsOn. This bridges the gap
 // between `import foo` statements and `foo==1.2.3` in a manifest file.
+// A package import is one node shared by its importing files (see
+// importingFiles), so manifests are preferred in the services of any of them.
 func (l *Linker) linkImports(ctx context.Context) (int, error) {
 	// Query all import dependency nodes.
 	imports, err := l.store.QueryNodes(ctx, graph.NodeFilter{
@@ -38,7 +42,11 @@ func (l *Linker) linkImports(ctx context.Context) (int, error) {
 	// Build index: manifest dep name → list of manifest nodes.
 	// Also build a normalized name index for Python (hyphens ↔ underscores).
 	manifestByName := make(map[string][]*graph.Node)
+	var mavenDeps []*graph.Node
 	for _, m := range manifests {
+		if m.Properties["artifact"] != "" {
+			mavenDeps = append(mavenDeps, m)
+		}
 		manifestByName[m.Name] = append(manifestByName[m.Name], m)
 		// Python packages often use hyphens in PyPI but underscores in import.
 		normalized := normalizePythonPkg(m.Name)
@@ -51,21 +59,37 @@ func (l *Linker) linkImports(ctx context.Context) (int, error) {
 	seen := make(map[string]bool) // avoid duplicate edges
 
 	for _, imp := range imports {
-		matches := l.findManifestMatches(imp, manifestByName)
+		files, err := l.importingFiles(ctx, imp)
+		if err != nil {
+			return linked, err
+		}
+		var matches []*graph.Node
+		if importedFromJVM(files) {
+			matches = l.matchMavenImport(imp, files, mavenDeps)
+		}
+		if len(matches) == 0 {
+			matches = l.findManifestMatches(imp, files, manifestByName)
+		}
 		for _, manifest := range matches {
 			edgeKey := imp.ID + "→" + manifest.ID
 			if seen[edgeKey] {
 				continue
 			}
 
+			// Anything but an exact name match (subpackage, normalized
+			// Python name, Maven group) is a heuristic match.
+			level, score := graph.ConfidenceExact, scoreExact
+			if imp.Name != manifest.Name {
+				level, score = graph.ConfidenceHeuristic, scoreStrong
+			}
 			edge := &graph.Edge{
-				ID:       graph.NewNodeID(string(graph.EdgeDependsOn), imp.ID, manifest.ID),
+				ID:       graph.NewEdgeID(graph.EdgeDependsOn, imp.ID, manifest.ID),
 				Type:     graph.EdgeDependsOn,
 				SourceID: imp.ID,
 				TargetID: manifest.ID,
-				Properties: map[string]string{
+				Properties: withConfidence(map[string]string{
 					"kind": "import_to_manifest",
-				},
+				}, level, score),
 			}
 			if err := l.store.AddEdge(ctx, edge); err != nil {
 				continue
@@ -78,13 +102,78 @@ func (l *Linker) linkImports(ctx context.Context) (int, error) {
 	return linked, nil
 }
 
-// findManifestMatches returns manifest nodes that match the given import node.
-func (l *Linker) findManifestMatches(imp *graph.Node, manifestByName map[string][]*graph.Node) []*graph.Node {
+// importingFiles returns the files importing the import dependency dep: the
+// files of the sources of its Imports edges, sorted. A dependency scoped to
+// a file (a relative import, or one indexed before package imports were
+// shared) is imported by that file.
+func (l *Linker) importingFiles(ctx context.Context, dep *graph.Node) ([]string, error) {
+	seen := make(map[string]bool)
+	if dep.FilePath != "" {
+		seen[dep.FilePath] = true
+	}
+	edges, err := l.store.GetEdges(ctx, dep.ID, graph.EdgeImports)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range edges {
+		if e.TargetID != dep.ID {
+			continue
+		}
+		if src, err := l.store.GetNode(ctx, e.SourceID); err == nil && src != nil && src.FilePath != "" {
+			seen[src.FilePath] = true
+		}
+	}
+	files := make([]string, 0, len(seen))
+	for f := range seen {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// fileImports maps each file of language to the names it imports (Go and
+// Java import paths, C# using directives, ...).
+func (l *Linker) fileImports(ctx context.Context, language string) (map[string][]string, error) {
+	deps, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Language:   language,
+		Properties: map[string]string{"kind": "import"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	imports := make(map[string][]string)
+	for _, dep := range deps {
+		files, err := l.importingFiles(ctx, dep)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			imports[f] = append(imports[f], dep.Name)
+		}
+	}
+	return imports, nil
+}
+
+// importedFromJVM reports whether any of the importing files is a JVM
+// source file.
+func importedFromJVM(files []string) bool {
+	for _, f := range files {
+		if jvmSourceExts[filepath.Ext(f)] {
+			return true
+		}
+	}
+	return false
+}
+
+// findManifestMatches returns manifest nodes that match the given import
+// node, imported by files.
+func (l *Linker) findManifestMatches(imp *graph.Node, files []string, manifestByName map[string][]*graph.Node) []*graph.Node {
 	name := imp.Name
 
 	// 1. Exact match: import name == manifest dep name (e.g., "axios" == "axios").
 	if matches, ok := manifestByName[name]; ok {
-		return sameServiceFilter(imp, matches)
+		return l.sameServiceFilter(files, matches)
 	}
 
 	// 2. Go subpackage match: import "github.com/foo/bar/pkg/util" matches
@@ -101,7 +190,7 @@ func (l *Linker) findManifestMatches(imp *graph.Node, manifestByName map[string]
 			}
 		}
 		if len(bestMatch) > 0 {
-			return sameServiceFilter(imp, bestMatch)
+			return l.sameServiceFilter(files, bestMatch)
 		}
 	}
 
@@ -111,12 +200,12 @@ func (l *Linker) findManifestMatches(imp *graph.Node, manifestByName map[string]
 		firstComponent := strings.SplitN(name, ".", 2)[0]
 		normalized := normalizePythonPkg(firstComponent)
 		if matches, ok := manifestByName[normalized]; ok {
-			return sameServiceFilter(imp, matches)
+			return l.sameServiceFilter(files, matches)
 		}
 		// Also try the unnormalized first component.
 		if normalized != firstComponent {
 			if matches, ok := manifestByName[firstComponent]; ok {
-				return sameServiceFilter(imp, matches)
+				return l.sameServiceFilter(files, matches)
 			}
 		}
 	}
@@ -131,7 +220,7 @@ func (l *Linker) findManifestMatches(imp *graph.Node, manifestByName map[string]
 			mNorm := strings.ToLower(strings.ReplaceAll(mName, "-", "."))
 			nameNorm := strings.ToLower(name)
 			if strings.Contains(nameNorm, mNorm) {
-				return sameServiceFilter(imp, manifestByName[mName])
+				return l.sameServiceFilter(files, manifestByName[mName])
 			}
 		}
 		// Try second segment (group ID-like) for shorter matches.
@@ -141,13 +230,16 @@ func (l *Linker) findManifestMatches(imp *graph.Node, manifestByName map[string]
 	return nil
 }
 
-// sameServiceFilter returns only manifest nodes that are in the same service
-// group as the import node (same top-level directory).
-func sameServiceFilter(imp *graph.Node, manifests []*graph.Node) []*graph.Node {
-	impGroup := topDir(imp.FilePath)
+// sameServiceFilter returns only manifest nodes that are in the service
+// group of one of the importing files.
+func (l *Linker) sameServiceFilter(files []string, manifests []*graph.Node) []*graph.Node {
+	groups := make(map[string]bool, len(files))
+	for _, f := range files {
+		groups[l.group(f)] = true
+	}
 	var filtered []*graph.Node
 	for _, m := range manifests {
-		if topDir(m.FilePath) == impGroup {
+		if groups[l.group(m.FilePath)] {
 			filtered = append(filtered, m)
 		}
 	}
@@ -158,6 +250,71 @@ func sameServiceFilter(imp *graph.Node, manifests []*graph.Node) []*graph.Node {
 	return filtered
 }
 
+// jvmSourceExts are the source files whose imports are package-qualified
+// class names resolved against Maven/Gradle coordinates.
+var jvmSourceExts = map[string]bool{".java": true, ".kt": true, ".kts": true, ".scala": true, ".groovy": true}
+
+// matchMavenImport matches a qualified JVM import such as
+// "org.springframework.web.client.RestTemplate" against group:artifact
+// manifest deps. Packages usually start with the group ID, and artifact
+// names usually repeat package segments ("spring-web" -> ...web...), so a
+// dep scores by the group segments shared with the import and by how many
+// artifact tokens appear as package segments. Only the best-scoring deps
+// are returned.
+func (l *Linker) matchMavenImport(imp *graph.Node, files []string, deps []*graph.Node) []*graph.Node {
+	segments := strings.Split(imp.Name, ".")
+	if len(segments) < 2 {
+		return nil
+	}
+
+	var best []*graph.Node
+	bestScore := 0
+	for _, dep := range deps {
+		group := strings.Split(dep.Properties["group"], ".")
+		common := 0
+		for common < len(group) && common < len(segments)-1 && group[common] == segments[common] {
+			common++
+		}
+		fullGroup := group[0] != "" && common == len(group)
+
+		tokens := strings.FieldsFunc(strings.ToLower(dep.Properties["artifact"]), func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+		matched := 0
+		for _, tok := range tokens {
+			for _, seg := range segments[:len(segments)-1] {
+				seg = strings.ToLower(seg)
+				if seg == tok || (len(tok) >= 3 && strings.HasPrefix(seg, tok)) {
+					matched++
+					break
+				}
+			}
+		}
+
+		// Require the full group as package prefix, every artifact token as
+		// a package segment ("junit:junit" -> org.junit), or a shared
+		// organization prefix plus some artifact token.
+		if !fullGroup && (matched == 0 || matched < len(tokens) && common < 2) {
+			continue
+		}
+		score := common*10 + matched
+		if fullGroup {
+			score += 100
+		}
+		switch {
+		case score > bestScore:
+			bestScore = score
+			best = []*graph.Node{dep}
+		case score == bestScore:
+			best = append(best, dep)
+		}
+	}
+	if len(best) == 0 {
+		return nil
+	}
+	return l.sameServiceFilter(files, best)
+}
+
 // normalizePythonPkg normalizes a Python package name by converting
 // hyphens to underscores and lowercasing (PEP 503 normalization).
 func normalizePythonPkg(name string) string {
@@ -0,0 +1,516 @@
+package linker
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// springComponentAnnotations mark Java classes whose constructors Spring (or
+// a JSR-330 container) uses for injection without an explicit @Autowired.
+var springComponentAnnotations = map[string]bool{
+	"Component":         true,
+	"Service":           true,
+	"Repository":        true,
+	"Controller":        true,
+	"RestController":    true,
+	"Configuration":     true,
+	"Named":             true,
+	"Singleton":         true,
+	"ApplicationScoped": true,
+	"RequestScoped":     true,
+}
+
+// javaInjectAnnotations mark injected Java fields and constructors.
+var javaInjectAnnotations = map[string]bool{
+	"Autowired": true,
+	"Inject":    true,
+	"Resource":  true,
+}
+
+// nestInjectableDecorators mark NestJS classes whose constructor parameters
+// are resolved by the DI container.
+var nestInjectableDecorators = map[string]bool{
+	"Injectable":       true,
+	"Controller":       true,
+	"Resolver":         true,
+	"WebSocketGateway": true,
+	"Catch":            true,
+}
+
+// injectionWrappers are generic types whose type argument is the injected type
+// (e.g. Optional<Foo>, ObjectProvider<Foo>).
+var injectionWrappers = map[string]bool{
+	"Optional":       true,
+	"Provider":       true,
+	"ObjectProvider": true,
+	"ObjectFactory":  true,
+	"Lazy":           true,
+}
+
+// injectionPoint is a dependency a class receives from the DI container.
+type injectionPoint struct {
+	consumer  *graph.Node
+	typeName  string
+	member    string // parameter or field name
+	via       string // "constructor" or "field"
+	qualifier string // @Qualifier/@Named/@Inject token, if any
+}
+
+// linkInjections resolves constructor and field injection for Spring (Java)
+// and NestJS (TypeScript). For each injected type it creates DependsOn edges
+// (kind=injection) from the consuming class to the injected interface and to
+// the implementation class the container would wire in. ASP.NET Core
+// container registrations are resolved by linkDIRegistrations, and Go
+// wire/fx/dig providers by linkGoProviders.
+func (l *Linker) linkInjections(ctx context.Context) (int, error) {
+	classes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeClass})
+	if err != nil {
+		return 0, err
+	}
+	interfaces, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeInterface})
+	if err != nil {
+		return 0, err
+	}
+
+	classByName := make(map[string][]*graph.Node)
+	classByFile := make(map[string]*graph.Node) // "filePath\x00name" -> class
+	implementers := make(map[string][]*graph.Node)
+	for _, cls := range classes {
+		classByName[cls.Name] = append(classByName[cls.Name], cls)
+		classByFile[cls.FilePath+"\x00"+cls.Name] = cls
+		if cls.Properties == nil {
+			continue
+		}
+		for _, iface := range strings.Split(cls.Properties["implements"], ",") {
+			if iface = strings.TrimSpace(iface); iface != "" {
+				implementers[iface] = append(implementers[iface], cls)
+			}
+		}
+	}
+	ifaceByName := make(map[string][]*graph.Node)
+	for _, iface := range interfaces {
+		ifaceByName[iface.Name] = append(ifaceByName[iface.Name], iface)
+	}
+
+	points, err := l.javaInjectionPoints(ctx, classByFile)
+	if err != nil {
+		return 0, err
+	}
+	tsPoints, err := l.nestInjectionPoints(ctx, classByFile)
+	if err != nil {
+		return 0, err
+	}
+	points = append(points, tsPoints...)
+
+	linked := 0
+	for _, p := range points {
+		var targets []*graph.Node
+		var candidates []int // candidates each target was chosen from
+		resolved := ""
+		ifaces := sameLanguage(p.consumer, ifaceByName[p.typeName])
+		classes := sameLanguage(p.consumer, classByName[p.typeName])
+		if iface := l.bestMatch(p.consumer, ifaces); iface != nil {
+			targets = append(targets, iface)
+			candidates = append(candidates, len(ifaces))
+			impls := sameLanguage(p.consumer, implementers[p.typeName])
+			if impl := selectImplementation(p, impls); impl != nil {
+				targets = append(targets, impl)
+				candidates = append(candidates, len(impls))
+			}
+			resolved = "interface"
+		} else if cls := l.bestMatch(p.consumer, classes); cls != nil {
+			targets = append(targets, cls)
+			candidates = append(candidates, len(classes))
+			resolved = "class"
+		}
+
+		for i, target := range targets {
+			if target.ID == p.consumer.ID {
+				continue
+			}
+			role := resolved
+			if i > 0 {
+				role = "implementation"
+			}
+			level, score := nameMatchConfidence(candidates[i])
+			edge := &graph.Edge{
+				ID:       graph.NewEdgeID(graph.EdgeDependsOn, p.consumer.ID, target.ID),
+				Type:     graph.EdgeDependsOn,
+				SourceID: p.consumer.ID,
+				TargetID: target.ID,
+				Properties: withConfidence(map[string]string{
+					"kind":   "injection",
+					"via":    p.via,
+					"member": p.member,
+					"type":   p.typeName,
+					"target": role,
+				}, level, score),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+
+			if l.verbose {
+				l.log("    Injection: %s.%s -> %s (%s)", p.consumer.Name, p.member, target.Name, role)
+			}
+		}
+	}
+
+	regCount, err := l.linkDIRegistrations(ctx)
+	if err != nil {
+		return linked, err
+	}
+	goCount, err := l.linkGoProviders(ctx)
+	if err != nil {
+		return linked + regCount, err
+	}
+	return linked + regCount + goCount, nil
+}
+
+// javaInjectionPoints collects injected constructor parameters and fields of
+// Java classes.
+func (l *Linker) javaInjectionPoints(ctx context.Context, classByFile map[string]*graph.Node) ([]injectionPoint, error) {
+	var points []injectionPoint
+
+	ctors, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeMethod,
+		Language:   "java",
+		Properties: map[string]string{"constructor": "true"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, ctor := range ctors {
+		cls := classByFile[ctor.FilePath+"\x00"+ctor.Properties["class"]]
+		if cls == nil {
+			continue
+		}
+		if !hasAnnotation(ctor.Properties["annotations"], javaInjectAnnotations) &&
+			!hasAnnotation(cls.Properties["annotations"], springComponentAnnotations) {
+			continue
+		}
+		for _, param := range splitParams(ctor.Signature) {
+			typeName, name, qualifier := parseJavaParam(param)
+			if typeName == "" {
+				continue
+			}
+			points = append(points, injectionPoint{consumer: cls, typeName: typeName, member: name, via: "constructor", qualifier: qualifier})
+		}
+	}
+
+	fields, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeVariable, Language: "java"})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if f.Properties == nil || !hasAnnotation(f.Properties["annotations"], javaInjectAnnotations) {
+			continue
+		}
+		cls := classByFile[f.FilePath+"\x00"+f.Properties["class"]]
+		typeName := injectedTypeName(f.Properties["type"])
+		if cls == nil || typeName == "" {
+			continue
+		}
+		points = append(points, injectionPoint{
+			consumer:  cls,
+			typeName:  typeName,
+			member:    f.Name,
+			via:       "field",
+			qualifier: annotationValue(f.Properties["annotations"], "Qualifier", "Named"),
+		})
+	}
+	return points, nil
+}
+
+// nestInjectionPoints collects constructor parameters of NestJS providers and
+// controllers.
+func (l *Linker) nestInjectionPoints(ctx context.Context, classByFile map[string]*graph.Node) ([]injectionPoint, error) {
+	ctors, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:        graph.NodeMethod,
+		Language:    "typescript",
+		NamePattern: "constructor",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var points []injectionPoint
+	for _, ctor := range ctors {
+		if ctor.Properties == nil {
+			continue
+		}
+		cls := classByFile[ctor.FilePath+"\x00"+ctor.Properties["receiver"]]
+		if cls == nil {
+			continue
+		}
+		params := splitParams(ctor.Signature)
+		explicit := false
+		for _, p := range params {
+			if strings.HasPrefix(strings.TrimSpace(p), "@Inject(") {
+				explicit = true
+			}
+		}
+		if !explicit && !hasDecorator(cls.Properties["decorators"], nestInjectableDecorators) {
+			continue
+		}
+		for _, param := range params {
+			typeName, name, token := parseTSParam(param)
+			if typeName == "" {
+				continue
+			}
+			points = append(points, injectionPoint{consumer: cls, typeName: typeName, member: name, via: "constructor", qualifier: token})
+		}
+	}
+	return points, nil
+}
+
+// selectImplementation picks the implementation the container would inject
+// for an interface: the one matching the qualifier, else the only one, else
+// the one marked @Primary. It returns nil when the choice is ambiguous.
+func selectImplementation(p injectionPoint, impls []*graph.Node) *graph.Node {
+	if len(impls) == 0 {
+		return nil
+	}
+	if p.qualifier != "" {
+		for _, impl := range impls {
+			if beanName(impl) == p.qualifier {
+				return impl
+			}
+		}
+	}
+	if len(impls) == 1 {
+		return impls[0]
+	}
+	var primary *graph.Node
+	for _, impl := range impls {
+		if impl.Properties != nil && hasAnnotation(impl.Properties["annotations"], map[string]bool{"Primary": true}) {
+			if primary != nil {
+				return nil
+			}
+			primary = impl
+		}
+	}
+	return primary
+}
+
+// beanName returns the bean name of a class: the value of its stereotype or
+// @Named annotation, or its name with a lower-case first letter.
+func beanName(cls *graph.Node) string {
+	if cls.Properties != nil {
+		if v := annotationValue(cls.Properties["annotations"], "Component", "Service", "Repository", "Controller", "RestController", "Named"); v != "" {
+			return v
+		}
+	}
+	r := []rune(cls.Name)
+	if len(r) == 0 {
+		return ""
+	}
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// sameLanguage filters nodes to those in the consumer's language.
+func sameLanguage(consumer *graph.Node, nodes []*graph.Node) []*graph.Node {
+	var out []*graph.Node
+	for _, n := range nodes {
+		if n.Language == consumer.Language {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// hasAnnotation reports whether a comma-separated annotation list (as stored
+// by the Java parser, without "@") contains any of the given names.
+func hasAnnotation(list string, names map[string]bool) bool {
+	for _, ann := range splitTopLevel(list, ',') {
+		if names[annotationName(ann)] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDecorator reports whether a comma-separated decorator list (as stored
+// by the TypeScript parser, e.g. "@Injectable()") contains any of the names.
+func hasDecorator(list string, names map[string]bool) bool {
+	for _, dec := range splitTopLevel(list, ',') {
+		if names[annotationName(strings.TrimPrefix(strings.TrimSpace(dec), "@"))] {
+			return true
+		}
+	}
+	return false
+}
+
+// annotationName returns the simple name of an annotation such as
+// `org.springframework.beans.factory.annotation.Qualifier("x")`.
+func annotationName(ann string) string {
+	ann = strings.TrimSpace(ann)
+	if idx := strings.Index(ann, "("); idx >= 0 {
+		ann = ann[:idx]
+	}
+	if idx := strings.LastIndex(ann, "."); idx >= 0 {
+		ann = ann[idx+1:]
+	}
+	return strings.TrimSpace(ann)
+}
+
+// annotationValue returns the string value of the first annotation in list
+// whose name is one of names, e.g. "x" for `Qualifier("x")`.
+func annotationValue(list string, names ...string) string {
+	for _, ann := range splitTopLevel(list, ',') {
+		name := annotationName(ann)
+		for _, want := range names {
+			if name == want {
+				if v := quotedValue(ann); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// quotedValue returns the first double- or single-quoted string in s.
+func quotedValue(s string) string {
+	start := strings.IndexAny(s, `"'`)
+	if start < 0 {
+		return ""
+	}
+	end := strings.IndexByte(s[start+1:], s[start])
+	if end < 0 {
+		return ""
+	}
+	return s[start+1 : start+1+end]
+}
+
+// splitParams splits the parameter list of a signature like
+// "Name(A a, Map<K, V> b)" on top-level commas.
+func splitParams(sig string) []string {
+	open := strings.Index(sig, "(")
+	close := strings.LastIndex(sig, ")")
+	if open < 0 || close <= open {
+		return nil
+	}
+	var params []string
+	for _, p := range splitTopLevel(sig[open+1:close], ',') {
+		if p = strings.TrimSpace(p); p != "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// splitTopLevel splits s on sep, ignoring separators nested in (), <>, [],
+// {}, or quotes.
+func splitTopLevel(s string, sep rune) []string {
+	var (
+		parts []string
+		depth int
+		quote rune
+		start int
+	)
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '(' || r == '<' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == '>' || r == ']' || r == '}':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+// parseJavaParam parses a Java parameter such as
+// `@Qualifier("fast") final PaymentGateway gateway` into its injected type
+// name, parameter name, and qualifier.
+func parseJavaParam(param string) (typeName, name, qualifier string) {
+	var tokens []string
+	for _, tok := range splitTopLevel(param, ' ') {
+		tok = strings.TrimSpace(tok)
+		switch {
+		case tok == "" || tok == "final":
+		case strings.HasPrefix(tok, "@"):
+			switch annotationName(tok[1:]) {
+			case "Qualifier", "Named":
+				qualifier = quotedValue(tok)
+			}
+		default:
+			tokens = append(tokens, tok)
+		}
+	}
+	if len(tokens) < 2 {
+		return "", "", ""
+	}
+	name = tokens[len(tokens)-1]
+	return injectedTypeName(strings.Join(tokens[:len(tokens)-1], "")), name, qualifier
+}
+
+// parseTSParam parses a TypeScript constructor parameter such as
+// `@Inject(CACHE) private readonly cache: Cache<string>` into its injected
+// type name, parameter name, and @Inject token.
+func parseTSParam(param string) (typeName, name, token string) {
+	parts := splitTopLevel(param, ':')
+	if len(parts) < 2 {
+		return "", "", ""
+	}
+	head, typ := parts[0], strings.TrimSpace(strings.Join(parts[1:], ":"))
+	for _, tok := range splitTopLevel(head, ' ') {
+		tok = strings.TrimSpace(tok)
+		switch {
+		case tok == "":
+		case strings.HasPrefix(tok, "@"):
+			if annotationName(tok[1:]) == "Inject" {
+				token = strings.Trim(strings.TrimSuffix(strings.TrimPrefix(tok[1:], "Inject("), ")"), `"'`)
+			}
+		case tok == "private" || tok == "protected" || tok == "public" || tok == "readonly":
+		default:
+			name = strings.TrimSuffix(tok, "?")
+		}
+	}
+	if idx := strings.Index(typ, "="); idx >= 0 {
+		typ = strings.TrimSpace(typ[:idx])
+	}
+	switch typ {
+	case "string", "number", "boolean", "any", "unknown", "object", "bigint", "symbol":
+		return "", "", ""
+	}
+	return injectedTypeName(typ), name, token
+}
+
+// injectedTypeName reduces a declared type to the simple name of the injected
+// type: generics and package qualifiers are dropped, and wrapper types such as
+// Optional<Foo> or ObjectProvider<Foo> yield Foo.
+func injectedTypeName(typ string) string {
+	typ = strings.TrimSpace(typ)
+	base, arg := typ, ""
+	if idx := strings.Index(typ, "<"); idx > 0 && strings.HasSuffix(typ, ">") {
+		base, arg = typ[:idx], typ[idx+1:len(typ)-1]
+	}
+	if idx := strings.LastIndex(base, "."); idx >= 0 {
+		base = base[idx+1:]
+	}
+	if injectionWrappers[base] && arg != "" && !strings.Contains(arg, ",") {
+		return injectedTypeName(arg)
+	}
+	if base == "" || strings.ContainsAny(base, "[]|&(){} ") || !unicode.IsUpper([]rune(base)[0]) {
+		return ""
+	}
+	return base
+}
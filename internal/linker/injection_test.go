@@ -0,0 +1,126 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestParseJavaParam(t *testing.T) {
+	tests := []struct {
+		param, wantType, wantName, wantQualifier string
+	}{
+		{"UserRepository repo", "UserRepository", "repo", ""},
+		{`@Qualifier("fast") final PaymentGateway gateway`, "PaymentGateway", "gateway", "fast"},
+		{"ObjectProvider<com.acme.Clock> clock", "Clock", "clock", ""},
+		{"Map<String, Handler> handlers", "Map", "handlers", ""},
+		{"int retries", "", "", ""},
+	}
+	for _, tt := range tests {
+		typ, name, q := parseJavaParam(tt.param)
+		if typ != tt.wantType || (typ != "" && (name != tt.wantName || q != tt.wantQualifier)) {
+			t.Errorf("parseJavaParam(%q) = (%q, %q, %q), want (%q, %q, %q)",
+				tt.param, typ, name, q, tt.wantType, tt.wantName, tt.wantQualifier)
+		}
+	}
+}
+
+func TestParseTSParam(t *testing.T) {
+	tests := []struct {
+		param, wantType, wantName, wantToken string
+	}{
+		{"private readonly users: UsersService", "UsersService", "users", ""},
+		{"@Inject(CACHE_MANAGER) private cache: Cache<string>", "Cache", "cache", "CACHE_MANAGER"},
+		{"logger?: Logger", "Logger", "logger", ""},
+		{"private name: string", "", "", ""},
+	}
+	for _, tt := range tests {
+		typ, name, token := parseTSParam(tt.param)
+		if typ != tt.wantType || (typ != "" && (name != tt.wantName || token != tt.wantToken)) {
+			t.Errorf("parseTSParam(%q) = (%q, %q, %q), want (%q, %q, %q)",
+				tt.param, typ, name, token, tt.wantType, tt.wantName, tt.wantToken)
+		}
+	}
+}
+
+func TestLinkInjections(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	java := func(n *graph.Node) *graph.Node { n.Language = "java"; return n }
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+
+	addNodes(t, store,
+		// Spring: constructor injection of an interface with two implementations,
+		// disambiguated by @Primary; field injection with @Qualifier.
+		java(&graph.Node{ID: "order-svc", Type: graph.NodeClass, Name: "OrderService", FilePath: "orders/src/OrderService.java",
+			Properties: map[string]string{"annotations": "Service"}}),
+		java(&graph.Node{ID: "order-ctor", Type: graph.NodeMethod, Name: "OrderService", FilePath: "orders/src/OrderService.java",
+			Signature:  "OrderService(PaymentGateway gateway, int retries)",
+			Properties: map[string]string{"constructor": "true", "class": "OrderService"}}),
+		java(&graph.Node{ID: "order-field", Type: graph.NodeVariable, Name: "audit", FilePath: "orders/src/OrderService.java",
+			Properties: map[string]string{"annotations": `Autowired,Qualifier("dbAudit")`, "type": "AuditLog", "class": "OrderService"}}),
+		java(&graph.Node{ID: "gateway", Type: graph.NodeInterface, Name: "PaymentGateway", FilePath: "orders/src/PaymentGateway.java"}),
+		java(&graph.Node{ID: "stripe", Type: graph.NodeClass, Name: "StripeGateway", FilePath: "orders/src/StripeGateway.java",
+			Properties: map[string]string{"implements": "PaymentGateway", "annotations": "Component,Primary"}}),
+		java(&graph.Node{ID: "fake", Type: graph.NodeClass, Name: "FakeGateway", FilePath: "orders/src/FakeGateway.java",
+			Properties: map[string]string{"implements": "PaymentGateway", "annotations": "Component"}}),
+		java(&graph.Node{ID: "audit", Type: graph.NodeInterface, Name: "AuditLog", FilePath: "orders/src/AuditLog.java"}),
+		java(&graph.Node{ID: "db-audit", Type: graph.NodeClass, Name: "DbAuditLog", FilePath: "orders/src/DbAuditLog.java",
+			Properties: map[string]string{"implements": "AuditLog", "annotations": `Component("dbAudit")`}}),
+		java(&graph.Node{ID: "file-audit", Type: graph.NodeClass, Name: "FileAuditLog", FilePath: "orders/src/FileAuditLog.java",
+			Properties: map[string]string{"implements": "AuditLog"}}),
+		// Plain Java class: constructor is not injected.
+		java(&graph.Node{ID: "plain", Type: graph.NodeClass, Name: "Plain", FilePath: "orders/src/Plain.java"}),
+		java(&graph.Node{ID: "plain-ctor", Type: graph.NodeMethod, Name: "Plain", FilePath: "orders/src/Plain.java",
+			Signature:  "Plain(PaymentGateway gateway)",
+			Properties: map[string]string{"constructor": "true", "class": "Plain"}}),
+
+		// NestJS: controller injecting a concrete provider class.
+		ts(&graph.Node{ID: "users-ctl", Type: graph.NodeClass, Name: "UsersController", FilePath: "api/src/users.controller.ts",
+			Properties: map[string]string{"decorators": "@Controller('users')"}}),
+		ts(&graph.Node{ID: "users-ctl-ctor", Type: graph.NodeMethod, Name: "constructor", FilePath: "api/src/users.controller.ts",
+			Signature:  "constructor(private readonly users: UsersService, private name: string)",
+			Properties: map[string]string{"receiver": "UsersController"}}),
+		ts(&graph.Node{ID: "users-svc", Type: graph.NodeClass, Name: "UsersService", FilePath: "api/src/users.service.ts",
+			Properties: map[string]string{"decorators": "@Injectable()"}}),
+	)
+
+	linker := NewLinker(store, nil, nil, false)
+	count, err := linker.linkInjections(ctx)
+	if err != nil {
+		t.Fatalf("linkInjections: %v", err)
+	}
+
+	want := map[string]map[string]string{ // source -> target -> target role
+		"order-svc": {"gateway": "interface", "stripe": "implementation", "audit": "interface", "db-audit": "implementation"},
+		"users-ctl": {"users-svc": "class"},
+		"plain":     {},
+	}
+	total := 0
+	for src, targets := range want {
+		edges, err := store.GetEdges(ctx, src, graph.EdgeDependsOn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]string)
+		for _, e := range edges {
+			if e.SourceID == src && e.Properties["kind"] == "injection" {
+				got[e.TargetID] = e.Properties["target"]
+			}
+		}
+		if len(got) != len(targets) {
+			t.Errorf("%s injection edges = %v, want %v", src, got, targets)
+		}
+		for target, role := range targets {
+			if got[target] != role {
+				t.Errorf("%s -> %s role = %q, want %q", src, target, got[target], role)
+			}
+		}
+		total += len(targets)
+	}
+	if count != total {
+		t.Errorf("linkInjections returned %d, want %d", count, total)
+	}
+}
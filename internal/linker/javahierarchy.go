@@ -0,0 +1,162 @@
+package linker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkJavaHierarchy resolves the supertypes of Java classes by qualified
+// name. The parser points Implements edges at an Unresolved node unless the
+// interface is declared in the same file, and records the superclass only
+// as Properties["extends"]. Each name in Properties["implements"] and
+// Properties["extends"] is looked up the way javac does: among the types
+// nested in the enclosing classes, then the single-type imports, the
+// class's own package and finally the on-demand (wildcard) imports. A
+// resolved interface gets an Implements edge (kind=qualified) replacing the
+// parser's edge to the Unresolved node, and a resolved superclass an Extends
+// edge. Supertypes declared outside the graph (JDK or library types) are
+// left unresolved.
+func (l *Linker) linkJavaHierarchy(ctx context.Context) (int, error) {
+	byQualified := make(map[string][]*graph.Node)
+	var classes []*graph.Node
+	for _, t := range []graph.NodeType{graph.NodeClass, graph.NodeInterface} {
+		nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: t, Language: "java"})
+		if err != nil {
+			return 0, err
+		}
+		for _, n := range nodes {
+			byQualified[n.QualifiedName] = append(byQualified[n.QualifiedName], n)
+		}
+		if t == graph.NodeClass {
+			classes = nodes
+		}
+	}
+	if len(classes) == 0 {
+		return 0, nil
+	}
+
+	imports, err := l.fileImports(ctx, "java")
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, cls := range classes {
+		var supertypes []javaSupertype
+		for _, name := range strings.Split(cls.Properties["implements"], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				supertypes = append(supertypes, javaSupertype{name, graph.NodeInterface, graph.EdgeImplements})
+			}
+		}
+		if ext := cls.Properties["extends"]; ext != "" {
+			supertypes = append(supertypes, javaSupertype{ext, graph.NodeClass, graph.EdgeExtends})
+		}
+
+		for _, st := range supertypes {
+			var candidates []*graph.Node
+			for _, n := range resolveJavaType(byQualified, cls, imports[cls.FilePath], st.name) {
+				if n.Type == st.nodeType && n.ID != cls.ID {
+					candidates = append(candidates, n)
+				}
+			}
+			target := l.bestMatch(cls, candidates)
+			if target == nil {
+				continue
+			}
+			level, score := nameMatchConfidence(len(candidates))
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(st.edgeType, cls.ID, target.ID),
+				Type:       st.edgeType,
+				SourceID:   cls.ID,
+				TargetID:   target.ID,
+				Properties: withConfidence(map[string]string{"kind": "qualified"}, level, score),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+
+			if st.edgeType == graph.EdgeImplements {
+				l.unbind(ctx, graph.EdgeImplements, cls.ID, parser.Unresolved{TargetType: graph.NodeInterface, Name: st.name, FilePath: cls.FilePath})
+			}
+
+			if l.verbose {
+				l.log("    Java %s: %s -> %s", strings.ToLower(string(st.edgeType)), cls.QualifiedName, target.QualifiedName)
+			}
+		}
+	}
+	return linked, nil
+}
+
+// javaSupertype is a supertype named in a class declaration, to be linked
+// to a node of nodeType with an edge of edgeType.
+type javaSupertype struct {
+	name     string
+	nodeType graph.NodeType
+	edgeType graph.EdgeType
+}
+
+// resolveJavaType returns the types a name written in class cls, in a file
+// with the given imports, can refer to. Type arguments are ignored. A
+// qualified name is either fully qualified or starts with a type name,
+// resolved like a simple name, that the rest is nested in.
+func resolveJavaType(byQualified map[string][]*graph.Node, cls *graph.Node, imports []string, name string) []*graph.Node {
+	if i := strings.IndexByte(name, '<'); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+	if first, rest, ok := strings.Cut(name, "."); ok {
+		if found := byQualified[name]; len(found) > 0 {
+			return found
+		}
+		var found []*graph.Node
+		for _, outer := range resolveJavaType(byQualified, cls, imports, first) {
+			found = append(found, byQualified[outer.QualifiedName+"."+rest]...)
+		}
+		return found
+	}
+
+	// Types nested in the enclosing classes, innermost first.
+	if scope := cls.Properties["scope"]; scope != "" {
+		outer := strings.Split(scope, ".")
+		for i := len(outer); i > 0; i-- {
+			qualified := strings.Join(outer[:i], ".") + "." + name
+			if cls.Package != "" {
+				qualified = cls.Package + "." + qualified
+			}
+			if found := byQualified[qualified]; len(found) > 0 {
+				return found
+			}
+		}
+	}
+
+	// Single-type imports.
+	for _, imp := range imports {
+		if imp == name || strings.HasSuffix(imp, "."+name) {
+			if found := byQualified[imp]; len(found) > 0 {
+				return found
+			}
+		}
+	}
+
+	qualified := name
+	if cls.Package != "" {
+		qualified = cls.Package + "." + name
+	}
+	if found := byQualified[qualified]; len(found) > 0 {
+		return found
+	}
+
+	// On-demand imports: the parser records "import a.b.*;" as a.b.
+	var found []*graph.Node
+	for _, imp := range imports {
+		found = append(found, byQualified[imp+"."+name]...)
+	}
+	return found
+}
@@ -0,0 +1,75 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkJavaHierarchy(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	const file = "orders/src/main/java/com/acme/orders/OrderService.java"
+	java := func(n *graph.Node) *graph.Node { n.Language = "java"; return n }
+	wildcard := graph.NewDependencyID("java", "com.acme.api")
+	single := graph.NewDependencyID("java", "com.acme.base.BaseService")
+	addNodes(t, store,
+		java(&graph.Node{ID: "file", Type: graph.NodeFile, Name: "OrderService.java", FilePath: file}),
+		java(&graph.Node{ID: wildcard, Type: graph.NodeDependency, Name: "com.acme.api", Properties: map[string]string{"kind": "import"}}),
+		java(&graph.Node{ID: single, Type: graph.NodeDependency, Name: "com.acme.base.BaseService", Properties: map[string]string{"kind": "import"}}),
+		java(&graph.Node{ID: "api", Type: graph.NodeInterface, Name: "OrderApi", QualifiedName: "com.acme.api.OrderApi", Package: "com.acme.api", FilePath: "api/OrderApi.java"}),
+		java(&graph.Node{ID: "other-api", Type: graph.NodeInterface, Name: "OrderApi", QualifiedName: "com.legacy.OrderApi", Package: "com.legacy", FilePath: "legacy/OrderApi.java"}),
+		java(&graph.Node{ID: "listener", Type: graph.NodeInterface, Name: "Listener", QualifiedName: "com.acme.orders.Events.Listener", Package: "com.acme.orders", FilePath: "orders/Events.java",
+			Properties: map[string]string{"scope": "Events"}}),
+		java(&graph.Node{ID: "events", Type: graph.NodeClass, Name: "Events", QualifiedName: "com.acme.orders.Events", Package: "com.acme.orders", FilePath: "orders/Events.java"}),
+		java(&graph.Node{ID: "base", Type: graph.NodeClass, Name: "BaseService", QualifiedName: "com.acme.base.BaseService", Package: "com.acme.base", FilePath: "base/BaseService.java"}),
+		java(&graph.Node{ID: "other-base", Type: graph.NodeClass, Name: "BaseService", QualifiedName: "com.acme.orders.BaseService", Package: "com.acme.orders", FilePath: "orders/BaseService.java"}),
+		java(&graph.Node{ID: "cls", Type: graph.NodeClass, Name: "OrderService", QualifiedName: "com.acme.orders.OrderService", Package: "com.acme.orders", FilePath: file,
+			Properties: map[string]string{"implements": "OrderApi,Events.Listener,Runnable", "extends": "BaseService<Order>"}}),
+	)
+	ref, placeholder := parser.Unresolved{Language: parser.LangJava, TargetType: graph.NodeInterface, Name: "OrderApi", FilePath: file}.Link(graph.EdgeImplements, "cls")
+	addNodes(t, store, ref)
+	for _, e := range []*graph.Edge{
+		{ID: "imp1", Type: graph.EdgeImports, SourceID: "file", TargetID: wildcard},
+		{ID: "imp2", Type: graph.EdgeImports, SourceID: "file", TargetID: single},
+		placeholder,
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkJavaHierarchy(ctx)
+	if err != nil {
+		t.Fatalf("linkJavaHierarchy: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkJavaHierarchy returned %d, want 3", count)
+	}
+
+	implements, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeImplements, SourceID: "cls"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]bool)
+	for _, e := range implements {
+		got[e.TargetID] = true
+	}
+	if len(got) != 2 || !got["api"] || !got["listener"] {
+		t.Errorf("implements targets = %v, want api and listener", got)
+	}
+
+	extends, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeExtends, SourceID: "cls"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extends) != 1 || extends[0].TargetID != "base" {
+		t.Fatalf("extends edges = %v, want one to base", extends)
+	}
+	if extends[0].Properties[graph.PropConfidence] != graph.ConfidenceExact {
+		t.Errorf("extends confidence = %q, want exact", extends[0].Properties[graph.PropConfidence])
+	}
+}
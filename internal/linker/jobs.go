@@ -0,0 +1,119 @@
+package linker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkJobs resolves the handlers of scheduled jobs that the parsers could
+// not link within the job's file, such as the worker class of a sidekiq-cron
+// schedule or an imported node-cron handler, creating Calls edges from the
+// Job to the class, function or method of that name. Candidates in the
+// job's service group are preferred, then those in the job's language.
+// Go jobs are resolved by the calls phase.
+func (l *Linker) linkJobs(ctx context.Context) (int, error) {
+	jobs, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeJob})
+	if err != nil {
+		return 0, err
+	}
+
+	var pending []*graph.Node
+	for _, job := range jobs {
+		if job.Properties[parser.JobPropHandler] == "" || job.Language == "go" {
+			continue
+		}
+		edges, err := l.store.GetEdges(ctx, job.ID, graph.EdgeCalls)
+		if err != nil {
+			return 0, err
+		}
+		linked := false
+		for _, e := range edges {
+			if e.SourceID == job.ID {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			pending = append(pending, job)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	byName := make(map[string][]*graph.Node)
+	for _, t := range []graph.NodeType{graph.NodeClass, graph.NodeFunction, graph.NodeMethod} {
+		err := l.store.ScanNodes(ctx, graph.NodeFilter{Type: t}, func(n *graph.Node) error {
+			byName[n.Name] = append(byName[n.Name], n)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	linked := 0
+	for _, job := range pending {
+		handler := job.Properties[parser.JobPropHandler]
+		candidates := l.jobHandlerCandidates(job, handler, byName[lastNameSegment(handler)])
+		if len(candidates) == 0 {
+			continue
+		}
+		level, score := nameMatchConfidence(len(candidates))
+		edge := &graph.Edge{
+			ID:       graph.NewEdgeID(graph.EdgeCalls, job.ID, candidates[0].ID),
+			Type:     graph.EdgeCalls,
+			SourceID: job.ID,
+			TargetID: candidates[0].ID,
+			Properties: withConfidence(map[string]string{
+				"kind": "job_handler",
+			}, level, score),
+		}
+		if err := l.store.AddEdge(ctx, edge); err != nil {
+			continue
+		}
+		linked++
+	}
+	return linked, nil
+}
+
+// jobHandlerCandidates narrows the nodes named like a job's handler: to
+// those whose qualified name matches a namespaced handler
+// (Reports::DailyWorker), then to the job's service group, then to its
+// language.
+func (l *Linker) jobHandlerCandidates(job *graph.Node, handler string, nodes []*graph.Node) []*graph.Node {
+	if qualified := strings.ReplaceAll(handler, "::", "."); strings.Contains(qualified, ".") {
+		nodes = narrow(nodes, func(n *graph.Node) bool {
+			return strings.HasSuffix(strings.ReplaceAll(n.QualifiedName, "::", "."), qualified)
+		})
+	}
+	group := l.group(job.FilePath)
+	nodes = narrow(nodes, func(n *graph.Node) bool { return l.group(n.FilePath) == group })
+	return narrow(nodes, func(n *graph.Node) bool { return n.Language == job.Language })
+}
+
+// narrow returns the nodes matching keep, or all nodes when none do.
+func narrow(nodes []*graph.Node, keep func(*graph.Node) bool) []*graph.Node {
+	var kept []*graph.Node
+	for _, n := range nodes {
+		if keep(n) {
+			kept = append(kept, n)
+		}
+	}
+	if len(kept) == 0 {
+		return nodes
+	}
+	return kept
+}
+
+// lastNameSegment returns the last part of a handler name qualified with
+// "::" or ".".
+func lastNameSegment(name string) string {
+	if i := strings.LastIndexAny(name, ":."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
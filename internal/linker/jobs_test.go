@@ -0,0 +1,80 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkJobs(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	job := func(id, file, lang, handler string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeJob, Name: id, FilePath: file, Language: lang,
+			Properties: map[string]string{parser.JobPropHandler: handler}}
+	}
+	class := func(id, name, qualified, file, lang string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeClass, Name: name, QualifiedName: qualified,
+			FilePath: file, Language: lang}
+	}
+
+	addNodes(t, store,
+		job("nightly", "billing/config/schedule.yml", "yaml", "Reports::NightlyWorker"),
+		job("cleanup", "billing/config/schedule.yml", "yaml", "CleanupWorker"),
+		job("linked", "web/src/jobs.ts", "typescript", "sync"),
+		job("missing", "billing/config/schedule.yml", "yaml", "GoneWorker"),
+		job("go", "billing/jobs.go", "go", "poll"),
+		class("reports-nightly", "NightlyWorker", "Reports::NightlyWorker", "billing/app/workers/reports/nightly_worker.rb", "ruby"),
+		class("admin-nightly", "NightlyWorker", "Admin::NightlyWorker", "billing/app/workers/admin/nightly_worker.rb", "ruby"),
+		class("billing-cleanup", "CleanupWorker", "CleanupWorker", "billing/app/workers/cleanup_worker.rb", "ruby"),
+		class("web-cleanup", "CleanupWorker", "CleanupWorker", "web/app/workers/cleanup_worker.rb", "ruby"),
+		&graph.Node{ID: "sync", Type: graph.NodeFunction, Name: "sync", FilePath: "web/src/jobs.ts", Language: "typescript"},
+		&graph.Node{ID: "poll", Type: graph.NodeFunction, Name: "poll", FilePath: "billing/jobs.go", Language: "go"},
+	)
+	if err := store.AddEdge(ctx, &graph.Edge{ID: "linked-sync", Type: graph.EdgeCalls, SourceID: "linked", TargetID: "sync"}); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLinker(store, nil, nil, false)
+	count, err := l.linkJobs(ctx)
+	if err != nil {
+		t.Fatalf("linkJobs: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linkJobs returned %d, want 2", count)
+	}
+
+	tests := []struct {
+		job, target string
+	}{
+		{"nightly", "reports-nightly"},
+		{"cleanup", "billing-cleanup"},
+		{"linked", "sync"},
+		{"missing", ""},
+		{"go", ""},
+	}
+	for _, tt := range tests {
+		edges, err := store.GetEdges(ctx, tt.job, graph.EdgeCalls)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var targets []string
+		for _, e := range edges {
+			if e.SourceID == tt.job {
+				targets = append(targets, e.TargetID)
+			}
+		}
+		if tt.target == "" {
+			if len(targets) != 0 {
+				t.Errorf("job %s calls %v, want nothing", tt.job, targets)
+			}
+			continue
+		}
+		if len(targets) != 1 || targets[0] != tt.target {
+			t.Errorf("job %s calls %v, want [%s]", tt.job, targets, tt.target)
+		}
+	}
+}
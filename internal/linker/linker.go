@@ -6,6 +6,9 @@ package linker
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
 	"github.com/imyousuf/CodeEagle/pkg/llm"
@@ -17,64 +20,192 @@ type Linker struct {
 	llmClient llm.Client
 	log       func(format string, args ...any)
 	verbose   bool
+	services  *ServiceMap
+	phaseOnly []string
+	phaseSkip []string
+	progress  func(PhaseProgress)
+	// parallel bounds how many independent phases run at once.
+	parallel  int
+	llmLimits LLMLimits
+	// dataRules and dataDepth configure the data_classification phase.
+	dataRules []DataRule
+	dataDepth int
+}
+
+// PhaseProgress reports a finished linker phase: its position among the
+// phases being run, how many edges or nodes it linked, and how long it took.
+type PhaseProgress struct {
+	Phase   string        `json:"phase"`
+	Index   int           `json:"index"`
+	Total   int           `json:"total"`
+	Linked  int           `json:"linked"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// SetProgress sets a callback invoked after each phase RunAll or RunPhases
+// runs.
+func (l *Linker) SetProgress(fn func(PhaseProgress)) {
+	l.progress = fn
+}
+
+// PhaseResult is the outcome of one phase run by RunPhases.
+type PhaseResult struct {
+	Linked  int           `json:"linked"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// SetParallelism sets how many independent phases RunAll and RunPhases run
+// at once. Below 1 (the default) means runtime.GOMAXPROCS; 1 runs the phases
+// one at a time, in order.
+func (l *Linker) SetParallelism(n int) {
+	l.parallel = n
+}
+
+// runScheduled runs phases concurrently, up to the parallelism limit,
+// starting each once every phase it runs After (among those given) has
+// finished; phases without such a relation may run in any order. done is
+// called for each finished phase, one call at a time, after the progress
+// callback. After a phase fails no more phases start, the context of those
+// running is canceled, and its name and error are returned.
+func (l *Linker) runScheduled(ctx context.Context, phases []Phase, done func(Phase, PhaseResult)) (string, error) {
+	workers := l.parallel
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	index := make(map[string]int, len(phases))
+	for i, p := range phases {
+		index[p.Name] = i
+	}
+	finished := make([]bool, len(phases))
+	started := make([]bool, len(phases))
+	ready := func(i int) bool {
+		for _, dep := range phases[i].After {
+			if j, ok := index[dep]; ok && j != i && !finished[j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	type outcome struct {
+		i      int
+		result PhaseResult
+		err    error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	outcomes := make(chan outcome)
+
+	running, completed := 0, 0
+	var failed string
+	var firstErr error
+	for {
+		for i := 0; firstErr == nil && running < workers && i < len(phases); i++ {
+			if started[i] || !ready(i) {
+				continue
+			}
+			started[i] = true
+			running++
+			go func(i int) {
+				start := time.Now()
+				count, err := phases[i].Fn(ctx)
+				outcomes <- outcome{i: i, result: PhaseResult{Linked: count, Elapsed: time.Since(start)}, err: err}
+			}(i)
+		}
+		if running == 0 {
+			return failed, firstErr
+		}
+
+		o := <-outcomes
+		running--
+		finished[o.i] = true
+		phase := phases[o.i]
+		if o.err != nil {
+			if firstErr == nil {
+				failed, firstErr = phase.Name, o.err
+				cancel()
+			}
+			continue
+		}
+		completed++
+		if l.progress != nil {
+			l.progress(PhaseProgress{Phase: phase.Name, Index: completed, Total: len(phases), Linked: o.result.Linked, Elapsed: o.result.Elapsed})
+		}
+		done(phase, o.result)
+	}
 }
 
 // NewLinker creates a new Linker.
 // The llmClient is optional; if nil, LLM-assisted analysis is skipped.
 func NewLinker(store graph.Store, llmClient llm.Client, logFn func(format string, args ...any), verbose bool) *Linker {
-	if logFn == nil {
-		logFn = func(string, ...any) {}
+	log := func(string, ...any) {}
+	if logFn != nil {
+		// Concurrent phases log through one lock.
+		var mu sync.Mutex
+		log = func(format string, args ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			logFn(format, args...)
+		}
 	}
 	return &Linker{
 		store:     store,
 		llmClient: llmClient,
-		log:       logFn,
+		log:       log,
 		verbose:   verbose,
 	}
 }
 
+// cacheReads routes the linker's store calls through an LRU cache until the
+// returned function is called. Phases repeat the same QueryNodes filters
+// many times over, and their writes go through the cache and invalidate it;
+// the cache lives for one run so writes made between runs are seen.
+func (l *Linker) cacheReads() func() {
+	store := l.store
+	l.store = graph.NewCachedStore(store, graph.DefaultCacheSize)
+	return func() { l.store = store }
+}
+
 // Phase represents a named linker phase.
 type Phase struct {
-	Name string
-	Fn   func(ctx context.Context) (int, error)
+	Name    string
+	Summary string
+	// After lists phases that must finish before this one starts when they
+	// run together.
+	After []string
+	Fn    func(ctx context.Context) (int, error)
 }
 
-// Phases returns all linker phases in execution order (excluding LLM phases).
+// Phases returns all registered linker phases in execution order (excluding
+// LLM phases), ignoring any phase selection.
 func (l *Linker) Phases() []Phase {
-	return []Phase{
-		{Name: "services", Fn: l.linkServices},
-		{Name: "endpoints", Fn: l.linkEndpoints},
-		{Name: "api_calls", Fn: l.linkAPICalls},
-		{Name: "dependencies", Fn: l.linkDependencies},
-		{Name: "imports", Fn: l.linkImports},
-		{Name: "implements", Fn: l.linkImplements},
-		{Name: "tests", Fn: l.linkTests},
-		{Name: "calls", Fn: l.linkCalls},
-		{Name: "documents", Fn: l.linkDocuments},
-	}
+	return l.bind(defaultRegistry.all())
 }
 
-// NewPhases returns only the newly added phases (implements + tests + calls).
+// NewPhases returns only the newly added phases (implements + injection + tests + calls + config).
 func (l *Linker) NewPhases() []Phase {
 	return []Phase{
 		{Name: "implements", Fn: l.linkImplements},
+		{Name: "injection", After: []string{"implements"}, Fn: l.linkInjections},
 		{Name: "tests", Fn: l.linkTests},
 		{Name: "calls", Fn: l.linkCalls},
+		{Name: "config", Fn: l.linkConfig},
 	}
 }
 
-// RunPhases executes the given phases in order and returns per-phase counts.
-func (l *Linker) RunPhases(ctx context.Context, phases []Phase) (map[string]int, error) {
-	results := make(map[string]int, len(phases))
-	for _, phase := range phases {
-		count, err := phase.Fn(ctx)
-		if err != nil {
-			return results, fmt.Errorf("phase %s: %w", phase.Name, err)
-		}
-		results[phase.Name] = count
+// RunPhases executes the given phases, independent ones concurrently (see
+// SetParallelism), and returns each finished phase's count and duration.
+func (l *Linker) RunPhases(ctx context.Context, phases []Phase) (map[string]PhaseResult, error) {
+	defer l.cacheReads()()
+	results := make(map[string]PhaseResult, len(phases))
+	failed, err := l.runScheduled(ctx, phases, func(phase Phase, r PhaseResult) {
+		results[phase.Name] = r
 		if l.verbose {
-			l.log("  Phase %s: linked %d", phase.Name, count)
+			l.log("  Phase %s: linked %d (%s)", phase.Name, r.Linked, r.Elapsed.Round(time.Millisecond))
 		}
+	})
+	if err != nil {
+		return results, fmt.Errorf("phase %s: %w", failed, err)
 	}
 	return results, nil
 }
@@ -84,91 +215,32 @@ func (l *Linker) RunAll(ctx context.Context) error {
 	if l.verbose {
 		l.log("Running cross-service linker...")
 	}
+	defer l.cacheReads()()
 
-	// 1. Detect services and create service → file edges.
-	serviceCount, err := l.linkServices(ctx)
-	if err != nil {
-		return fmt.Errorf("link services: %w", err)
-	}
-	if l.verbose {
-		l.log("  Linked %d services", serviceCount)
-	}
-
-	// 2. Link endpoints to their containing services.
-	endpointCount, err := l.linkEndpoints(ctx)
-	if err != nil {
-		return fmt.Errorf("link endpoints: %w", err)
-	}
-	if l.verbose {
-		l.log("  Linked %d endpoints to services", endpointCount)
-	}
-
-	// 3. Resolve API calls to endpoints.
-	callCount, err := l.linkAPICalls(ctx)
-	if err != nil {
-		return fmt.Errorf("link API calls: %w", err)
-	}
-	if l.verbose {
-		l.log("  Resolved %d API calls to endpoints", callCount)
-	}
-
-	// 4. Resolve library dependencies between services.
-	depCount, err := l.linkDependencies(ctx)
-	if err != nil {
-		return fmt.Errorf("link dependencies: %w", err)
-	}
-	if l.verbose {
-		l.log("  Resolved %d cross-service dependencies", depCount)
-	}
-
-	// 4.5. Link import statements to manifest dependencies.
-	importCount, err := l.linkImports(ctx)
+	// 1-4. Run the registered phases (services, endpoints, API calls,
+	// dependencies, imports, implements, tests, calls, ...) in dependency
+	// order, independent ones concurrently, honoring any phase selection.
+	phases, err := l.SelectedPhases()
 	if err != nil {
-		return fmt.Errorf("link imports: %w", err)
+		return err
 	}
-	if l.verbose {
-		l.log("  Linked %d imports to manifest dependencies", importCount)
-	}
-
-	// 4.6. Resolve cross-file implements relationships.
-	implCount, err := l.linkImplements(ctx)
-	if err != nil {
-		return fmt.Errorf("link implements: %w", err)
-	}
-	if l.verbose {
-		l.log("  Linked %d cross-file implements", implCount)
-	}
-
-	// 4.7. Link test files/functions to source entities.
-	testCount, err := l.linkTests(ctx)
-	if err != nil {
-		return fmt.Errorf("link tests: %w", err)
-	}
-	if l.verbose {
-		l.log("  Linked %d test coverage edges", testCount)
-	}
-
-	// 4.8. Resolve cross-file intra-package function calls.
-	callsLinked, err := l.linkCalls(ctx)
-	if err != nil {
-		return fmt.Errorf("link calls: %w", err)
-	}
-	if l.verbose {
-		l.log("  Linked %d cross-file call edges", callsLinked)
-	}
-
-	// 4.9. Link documents to code entities they reference.
-	docCount, err := l.linkDocuments(ctx)
+	failed, err := l.runScheduled(ctx, phases, func(phase Phase, r PhaseResult) {
+		if l.verbose {
+			if phase.Summary != "" {
+				l.log("  "+phase.Summary, r.Linked)
+			} else {
+				l.log("  Phase %s: linked %d", phase.Name, r.Linked)
+			}
+		}
+	})
 	if err != nil {
-		return fmt.Errorf("link documents: %w", err)
-	}
-	if l.verbose {
-		l.log("  Linked %d document-to-code edges", docCount)
+		return fmt.Errorf("link %s: %w", failed, err)
 	}
 
 	// 5. LLM-assisted analysis for unresolved calls (optional).
 	if l.llmClient != nil {
-		llmCount, err := l.llmAnalyzeUnresolvedCalls(ctx)
+		caller := l.newLLMCaller()
+		llmCount, err := l.llmAnalyzeUnresolvedCalls(ctx, caller)
 		if err != nil {
 			if l.verbose {
 				l.log("  Warning: LLM call analysis: %v", err)
@@ -177,7 +249,16 @@ func (l *Linker) RunAll(ctx context.Context) error {
 			l.log("  LLM resolved %d additional API calls", llmCount)
 		}
 
-		eventCount, err := l.llmAnalyzeEventDriven(ctx)
+		fnCount, err := l.llmAnalyzeUnresolvedFunctionCalls(ctx, caller)
+		if err != nil {
+			if l.verbose {
+				l.log("  Warning: LLM function call analysis: %v", err)
+			}
+		} else if l.verbose {
+			l.log("  LLM resolved %d additional function calls", fnCount)
+		}
+
+		eventCount, err := l.llmAnalyzeEventDriven(ctx, caller)
 		if err != nil {
 			if l.verbose {
 				l.log("  Warning: LLM event analysis: %v", err)
@@ -185,6 +266,7 @@ func (l *Linker) RunAll(ctx context.Context) error {
 		} else if l.verbose {
 			l.log("  LLM resolved %d event-driven dependencies", eventCount)
 		}
+		l.logUsage(caller)
 	}
 
 	if l.verbose {
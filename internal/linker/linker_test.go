@@ -2,7 +2,9 @@ package linker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
@@ -49,6 +51,111 @@ func TestTopDir(t *testing.T) {
 	}
 }
 
+func TestServiceMapGroup(t *testing.T) {
+	m := NewServiceMap([]ServiceDefinition{
+		{Name: "foo-backend", Roots: []string{"apps/foo/backend"}},
+		{Name: "frontends", Roots: []string{"apps/*/web"}},
+		{Name: "apps", Roots: []string{"apps"}},
+		{Name: "billing", Roots: []string{"libs/**/billing/"}},
+	}, false)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"apps/foo/backend/src/main.go", "foo-backend"},
+		{"apps/bar/web/index.ts", "frontends"},
+		{"apps/bar/README.md", "apps"},
+		{"libs/core/billing/invoice.py", "billing"},
+		{"libs/billing/invoice.py", "billing"},
+		{"tools/gen.go", "tools"},
+		{"main.go", "(root)"},
+	}
+	for _, tt := range tests {
+		if got := m.Group(tt.path); got != tt.want {
+			t.Errorf("Group(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	only := NewServiceMap([]ServiceDefinition{{Name: "api", Roots: []string{"apps/api"}}}, true)
+	if got := only.Group("tools/gen.go"); got != "" {
+		t.Errorf("only declared: Group = %q, want empty", got)
+	}
+	var none *ServiceMap
+	if got := none.Group("backend/api/routes.go"); got != "backend" {
+		t.Errorf("nil map: Group = %q, want backend", got)
+	}
+}
+
+func TestLinkServicesDeclared(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	addNodes(t, store,
+		&graph.Node{ID: "f1", Type: graph.NodeFile, Name: "apps/foo/backend/main.go", FilePath: "apps/foo/backend/main.go"},
+		&graph.Node{ID: "f2", Type: graph.NodeFile, Name: "apps/foo/web/app.ts", FilePath: "apps/foo/web/app.ts"},
+		&graph.Node{ID: "f3", Type: graph.NodeFile, Name: "tools/gen.go", FilePath: "tools/gen.go"},
+		&graph.Node{ID: "ep", Type: graph.NodeAPIEndpoint, Name: "GET /orders", FilePath: "apps/foo/backend/main.go",
+			Properties: map[string]string{"path": "/orders"}},
+		// A manifest service inside the declared root.
+		&graph.Node{ID: "svc-mod", Type: graph.NodeService, Name: "foo", FilePath: "apps/foo/backend/go.mod",
+			Properties: map[string]string{"kind": "service"}},
+	)
+
+	l := NewLinker(store, nil, nil, false)
+	l.SetServiceMap(NewServiceMap([]ServiceDefinition{
+		{Name: "foo-backend", Roots: []string{"apps/foo/backend"}, Kind: "backend"},
+		{Name: "foo-web", Roots: []string{"apps/foo/web"}},
+	}, false))
+	if _, err := l.linkServices(ctx); err != nil {
+		t.Fatalf("linkServices: %v", err)
+	}
+	if _, err := l.linkEndpoints(ctx); err != nil {
+		t.Fatalf("linkEndpoints: %v", err)
+	}
+
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]*graph.Node)
+	for _, s := range services {
+		byName[s.Name] = s
+	}
+	backend := byName["foo-backend"]
+	if backend == nil || backend.Properties["kind"] != "backend" || backend.Properties["source"] != "config" {
+		t.Fatalf("declared service = %+v", backend)
+	}
+	if web := byName["foo-web"]; web == nil || web.Properties["kind"] != "service" {
+		t.Errorf("default kind: %+v", web)
+	}
+	if byName["tools"] == nil || byName["apps"] != nil {
+		t.Errorf("expected auto-detection for undeclared files only, got %v", byName)
+	}
+
+	contains := func(svcID, fileID string) bool {
+		edges, err := store.GetEdges(ctx, svcID, graph.EdgeContains)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range edges {
+			if e.SourceID == svcID && e.TargetID == fileID {
+				return true
+			}
+		}
+		return false
+	}
+	if !contains(backend.ID, "f1") || contains(backend.ID, "f2") || !contains(byName["foo-web"].ID, "f2") {
+		t.Error("declared services should contain the files under their roots")
+	}
+	exposes, err := store.GetEdges(ctx, backend.ID, graph.EdgeExposes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exposes) != 1 || exposes[0].TargetID != "ep" {
+		t.Errorf("expected the declared service to expose the endpoint, got %+v", exposes)
+	}
+}
+
 func TestNormalizeURLPath(t *testing.T) {
 	tests := []struct {
 		input string
@@ -60,6 +167,12 @@ func TestNormalizeURLPath(t *testing.T) {
 		{"/API/V1/Users/", "/api/v1/users"},
 		{"api/v1/data", "/api/v1/data"},
 		{"/simple", "/simple"},
+		{"/users/{id:int}/orders/{orderId:guid}", "/users/*int/orders/*uuid"},
+		{"/users/{id:min(1)}", "/users/*"},
+		{"/items/<int:item_id>/<path:rest>", "/items/*int/*"},
+		{"/posts/:id(\\d+)", "/posts/*int"},
+		{"/orders/{order_uuid}", "/orders/*uuid"},
+		{"/files/{id:[0-9]+}", "/files/*int"},
 	}
 	for _, tt := range tests {
 		got := normalizeURLPath(tt.input)
@@ -96,6 +209,11 @@ func TestMatchSegments(t *testing.T) {
 		{[]string{"", "api", "v1", "*"}, []string{"", "api", "v1", "data"}, true},
 		{[]string{"", "api", "v1"}, []string{"", "api", "v2"}, false},
 		{[]string{"", "api"}, []string{"", "api", "v1"}, false},
+		{[]string{"", "users", "42"}, []string{"", "users", "*int"}, true},
+		{[]string{"", "users", "me"}, []string{"", "users", "*int"}, false},
+		{[]string{"", "users", "*int"}, []string{"", "users", "*uuid"}, false},
+		{[]string{"", "users", "*"}, []string{"", "users", "*uuid"}, true},
+		{[]string{"", "flags", "true"}, []string{"", "flags", "*bool"}, true},
 	}
 	for _, tt := range tests {
 		got := matchSegments(tt.a, tt.b)
@@ -239,6 +357,15 @@ func TestLinkEndpoints(t *testing.T) {
 	if edges[0].TargetID != epID {
 		t.Errorf("EdgeExposes target = %s, want %s", edges[0].TargetID, epID)
 	}
+
+	// Endpoints without a router mount get their own path as full_path.
+	ep, err := store.GetNode(ctx, epID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Properties["full_path"] != "/api/v1/users" {
+		t.Errorf("full_path = %q, want /api/v1/users", ep.Properties["full_path"])
+	}
 }
 
 func TestLinkEndpointsWithRouterPrefix(t *testing.T) {
@@ -291,6 +418,9 @@ func TestLinkEndpointsWithRouterPrefix(t *testing.T) {
 	if ep.Properties["full_path"] != want {
 		t.Errorf("full_path = %q, want %q", ep.Properties["full_path"], want)
 	}
+	if ep.Properties["path_params"] != "id" {
+		t.Errorf("path_params = %q, want id", ep.Properties["path_params"])
+	}
 }
 
 func TestLinkAPICalls(t *testing.T) {
@@ -402,6 +532,62 @@ func TestLinkAPICallsWithPathParams(t *testing.T) {
 	}
 }
 
+func TestLinkAPICallsTypedPathParams(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	endpoint := func(id, path string) *graph.Node {
+		return &graph.Node{
+			ID: id, Type: graph.NodeAPIEndpoint, Name: "GET " + path,
+			FilePath:   "backend/UsersController.cs",
+			Properties: map[string]string{"http_method": "GET", "path": path},
+		}
+	}
+	call := func(id, path string) *graph.Node {
+		return &graph.Node{
+			ID: id, Type: graph.NodeDependency, Name: "fetch " + path,
+			FilePath:   "frontend/src/api.ts",
+			Properties: map[string]string{"kind": "api_call", "http_method": "GET", "path": path},
+		}
+	}
+	addNodes(t, store,
+		endpoint("by-id", "/users/{id:int}"),
+		endpoint("by-slug", "/users/{slug:alpha}"),
+		call("call-id", "/users/123"),
+		call("call-slug", "/users/alice"),
+		call("call-other", "/users/a-1"),
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkAPICalls(ctx)
+	if err != nil {
+		t.Fatalf("linkAPICalls: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linkAPICalls returned %d, want 2", count)
+	}
+
+	tests := []struct {
+		call, want string
+	}{
+		{"call-id", "by-id"},
+		{"call-slug", "by-slug"},
+		{"call-other", ""},
+	}
+	for _, tt := range tests {
+		edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeConsumes, SourceID: tt.call})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got string
+		if len(edges) > 0 {
+			got = edges[0].TargetID
+		}
+		if len(edges) > 1 || got != tt.want {
+			t.Errorf("%s consumes %v, want %q", tt.call, edges, tt.want)
+		}
+	}
+}
+
 func TestLinkDependencies(t *testing.T) {
 	store := newTestStore(t)
 	ctx := context.Background()
@@ -487,6 +673,91 @@ func TestLinkDependenciesNoSelfRef(t *testing.T) {
 	}
 }
 
+func TestLinkDependenciesWorkspace(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	svc := func(id, name, manifest, ecosystem string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeService, Name: name, FilePath: manifest,
+			Properties: map[string]string{"kind": "service", "ecosystem": ecosystem}}
+	}
+	dep := func(id, name, manifest, ecosystem string, props ...string) *graph.Node {
+		n := &graph.Node{ID: id, Type: graph.NodeDependency, Name: name, FilePath: manifest,
+			Properties: map[string]string{"kind": "manifest_dep", "ecosystem": ecosystem}}
+		for i := 0; i+1 < len(props); i += 2 {
+			n.Properties[props[i]] = props[i+1]
+		}
+		return n
+	}
+	addNodes(t, store,
+		// pnpm workspace: packages/web and packages/ui share the top-level
+		// directory, so only the same-manifest rule finds the right consumer.
+		&graph.Node{ID: "ws-pnpm", Type: graph.NodeModule, Name: ".", FilePath: "pnpm-workspace.yaml",
+			Properties: map[string]string{"kind": "workspace", "tool": "pnpm", "members": "packages/*"}},
+		svc("web", "@acme/web", "packages/web/package.json", "nodejs"),
+		svc("ui", "@acme/ui", "packages/ui/package.json", "nodejs"),
+		dep("d-ui", "@acme/ui", "packages/web/package.json", "nodejs", "version", "workspace:^", "internal", "true"),
+		dep("d-react", "react", "packages/web/package.json", "nodejs", "version", "^18.2.0"),
+
+		// Gradle multi-project build under java/.
+		&graph.Node{ID: "ws-gradle", Type: graph.NodeModule, Name: "java", FilePath: "java/settings.gradle",
+			Properties: map[string]string{"kind": "workspace", "tool": "gradle", "members": "app,libs/util"}},
+		svc("app", "app", "java/app/build.gradle", "maven"),
+		svc("util", "util", "java/libs/util/build.gradle", "maven"),
+		dep("d-util", ":libs:util", "java/app/build.gradle", "maven", "gradle_project", ":libs:util", "internal", "true"),
+
+		// Cargo path dependency outside any workspace.
+		svc("cli", "cli", "tools/cli/Cargo.toml", "rust"),
+		svc("core", "core-lib", "tools/core/Cargo.toml", "rust"),
+		dep("d-core", "core-lib", "tools/cli/Cargo.toml", "rust", "path", "../core", "internal", "true"),
+	)
+
+	linker := NewLinker(store, nil, nil, false)
+	count, err := linker.linkDependencies(ctx)
+	if err != nil {
+		t.Fatalf("linkDependencies: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkDependencies returned %d, want 3", count)
+	}
+
+	wantEdges := map[string]string{"web": "ui", "app": "util", "cli": "core"}
+	for from, to := range wantEdges {
+		edges, err := store.GetEdges(ctx, from, graph.EdgeDependsOn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, e := range edges {
+			if e.SourceID == from && e.TargetID == to {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected DependsOn %s -> %s", from, to)
+		}
+	}
+
+	for id, wantWS := range map[string]string{"web": "pnpm-workspace.yaml", "ui": "pnpm-workspace.yaml", "util": "java/settings.gradle", "cli": ""} {
+		n, err := store.GetNode(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n.Properties["workspace"] != wantWS {
+			t.Errorf("%s workspace = %q, want %q", id, n.Properties["workspace"], wantWS)
+		}
+	}
+	for id, wantKind := range map[string]string{"ui": "library", "util": "library", "web": "service", "core": "service"} {
+		n, _ := store.GetNode(ctx, id)
+		if n.Properties["kind"] != wantKind {
+			t.Errorf("%s kind = %q, want %q", id, n.Properties["kind"], wantKind)
+		}
+	}
+	if n, _ := store.GetNode(ctx, "d-react"); n.Properties["internal"] != "" {
+		t.Error("third-party dependency marked internal")
+	}
+}
+
 func TestVersionConflictDetection(t *testing.T) {
 	var logs []string
 	logFn := func(format string, args ...any) {
@@ -651,11 +922,11 @@ func TestLLMAnalyzeUnresolvedCalls(t *testing.T) {
 	)
 
 	mockClient := &mockLLMClient{
-		response: `[{"endpoint_path": "/api/v1/execute", "confidence": "high", "reason": "Function name suggests agent execution"}]`,
+		response: `{"matches": [{"endpoint_path": "/api/v1/execute", "confidence": "high", "reason": "Function name suggests agent execution"}]}`,
 	}
 
 	linker := NewLinker(store, mockClient, nil, false)
-	count, err := linker.llmAnalyzeUnresolvedCalls(ctx)
+	count, err := linker.llmAnalyzeUnresolvedCalls(ctx, linker.newLLMCaller())
 	if err != nil {
 		t.Fatalf("llmAnalyzeUnresolvedCalls: %v", err)
 	}
@@ -681,7 +952,7 @@ func TestLLMAnalyzeNoClient(t *testing.T) {
 	ctx := context.Background()
 
 	linker := NewLinker(store, nil, nil, false)
-	count, err := linker.llmAnalyzeUnresolvedCalls(ctx)
+	count, err := linker.llmAnalyzeUnresolvedCalls(ctx, linker.newLLMCaller())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -690,30 +961,105 @@ func TestLLMAnalyzeNoClient(t *testing.T) {
 	}
 }
 
-func TestExtractJSON(t *testing.T) {
-	tests := []struct {
-		input string
-		want  string
-	}{
-		{
-			`Here are the matches: [{"endpoint_path": "/api/v1/users", "confidence": "high"}]`,
-			`[{"endpoint_path": "/api/v1/users", "confidence": "high"}]`,
-		},
-		{
-			`No matches found.`,
-			"",
-		},
-		{
-			`[{"a": 1}, {"b": 2}] and more text`,
-			`[{"a": 1}, {"b": 2}]`,
-		},
+func TestLLMAnalyzeUnresolvedFunctionCalls(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+	dep := graph.NewDependencyID("typescript", "@acme/utils")
+	addNodes(t, store,
+		ts(&graph.Node{ID: dep, Type: graph.NodeDependency, Name: "@acme/utils", Properties: map[string]string{"kind": "import"}}),
+		ts(&graph.Node{ID: "render", Type: graph.NodeFunction, Name: "render", FilePath: "web/src/view.ts"}),
+		ts(&graph.Node{ID: "load", Type: graph.NodeFunction, Name: "load", FilePath: "web/src/load.ts"}),
+		ts(&graph.Node{ID: "web-format", Type: graph.NodeFunction, Name: "formatDate", FilePath: "web/src/utils/date.ts"}),
+		ts(&graph.Node{ID: "api-format", Type: graph.NodeFunction, Name: "formatDate", FilePath: "api/src/date.ts"}),
+		ts(&graph.Node{ID: "parse", Type: graph.NodeFunction, Name: "parseDate", FilePath: "web/src/utils/date.ts"}),
+	)
+	addEdges := []*graph.Edge{
+		{ID: "c1", Type: graph.EdgeCalls, SourceID: "render", TargetID: dep, Properties: map[string]string{"callee": "fmt", "export": "formatDate"}},
+		{ID: "c2", Type: graph.EdgeCalls, SourceID: "render", TargetID: dep, Properties: map[string]string{"callee": "chunk"}}, // library call
+		{ID: "c3", Type: graph.EdgeCalls, SourceID: "load", TargetID: dep, Properties: map[string]string{"callee": "parseDate"}},
+		{ID: "c4", Type: graph.EdgeCalls, SourceID: "load", TargetID: "parse", Properties: map[string]string{"callee": "parseDate"}}, // already resolved
+	}
+	for _, e := range addEdges {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
 	}
-	for _, tt := range tests {
-		got := extractJSON(tt.input)
-		if got != tt.want {
-			t.Errorf("extractJSON(%q) = %q, want %q", tt.input, got, tt.want)
+
+	linker := NewLinker(store, nil, nil, false)
+	calls, err := linker.unresolvedFunctionCalls(ctx)
+	if err != nil {
+		t.Fatalf("unresolvedFunctionCalls: %v", err)
+	}
+	if len(calls) != 1 || calls[0].callee != "formatDate" || len(calls[0].candidates) != 2 {
+		t.Fatalf("unresolved calls = %+v, want formatDate with 2 candidates", calls)
+	}
+	if calls[0].candidates[0].ID != "web-format" {
+		t.Errorf("first candidate = %s, want the caller's service's web-format", calls[0].candidates[0].ID)
+	}
+
+	linker = NewLinker(store, &mockLLMClient{
+		response: `{"matches": [{"call": "c1", "target": "f1", "confidence": "high", "reason": "same service"}, {"call": "c2", "target": "f1", "confidence": "high", "reason": "out of range"}]}`,
+	}, nil, false)
+	count, err := linker.llmAnalyzeUnresolvedFunctionCalls(ctx, linker.newLLMCaller())
+	if err != nil {
+		t.Fatalf("llmAnalyzeUnresolvedFunctionCalls: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("llmAnalyzeUnresolvedFunctionCalls returned %d, want 1", count)
+	}
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeCalls, SourceID: "render", TargetID: "web-format"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 || edges[0].Properties[graph.PropConfidence] != graph.ConfidenceLLM || edges[0].Properties["inferred"] != "true" {
+		t.Fatalf("inferred call edges = %v, want one with confidence=llm", edges)
+	}
+	if want := graph.NewEdgeID(graph.EdgeCalls, "render", "web-format"); edges[0].ID != want || edges[0].Properties["method"] != "llm_analysis" {
+		t.Errorf("inferred call edge ID = %s method = %q, want %s from llm_analysis", edges[0].ID, edges[0].Properties["method"], want)
+	}
+}
+
+func TestLLMInferredCallKeepsStaticEdge(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+	dep := graph.NewDependencyID("typescript", "@acme/utils")
+	addNodes(t, store,
+		ts(&graph.Node{ID: dep, Type: graph.NodeDependency, Name: "@acme/utils", Properties: map[string]string{"kind": "import"}}),
+		ts(&graph.Node{ID: "render", Type: graph.NodeFunction, Name: "render", FilePath: "web/src/view.ts"}),
+		ts(&graph.Node{ID: "format", Type: graph.NodeFunction, Name: "formatDate", FilePath: "web/src/utils/date.ts"}),
+	)
+	static := &graph.Edge{ID: graph.NewEdgeID(graph.EdgeCalls, "render", "format"), Type: graph.EdgeCalls, SourceID: "render", TargetID: "format",
+		Properties: withConfidence(map[string]string{}, graph.ConfidenceExact, scoreExact)}
+	for _, e := range []*graph.Edge{
+		{ID: "c1", Type: graph.EdgeCalls, SourceID: "render", TargetID: dep, Properties: map[string]string{"callee": "formatDate"}},
+		static,
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
 		}
 	}
+
+	linker := NewLinker(store, &mockLLMClient{
+		response: `{"matches": [{"call": "c1", "target": "f1", "confidence": "high", "reason": "same name"}]}`,
+	}, nil, false)
+	count, err := linker.llmAnalyzeUnresolvedFunctionCalls(ctx, linker.newLLMCaller())
+	if err != nil {
+		t.Fatalf("llmAnalyzeUnresolvedFunctionCalls: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("llmAnalyzeUnresolvedFunctionCalls returned %d, want 0", count)
+	}
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeCalls, SourceID: "render", TargetID: "format"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 || edges[0].Properties[graph.PropConfidence] != graph.ConfidenceExact {
+		t.Errorf("call edges = %v, want only the static one", edges)
+	}
 }
 
 func TestContainsAny(t *testing.T) {
@@ -771,6 +1117,52 @@ func TestLinkImportsExactMatch(t *testing.T) {
 	}
 }
 
+func TestLinkImportsSharedDependency(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// A shared "axios" import node used by two services links to the
+	// manifests of both, not to that of a third service.
+	impID := graph.NewDependencyID("typescript", "axios")
+	addNodes(t, store,
+		&graph.Node{ID: impID, Type: graph.NodeDependency, Name: "axios", Language: "typescript",
+			Properties: map[string]string{"kind": "import"}},
+		&graph.Node{ID: "mod-web", Type: graph.NodeModule, Name: "api", FilePath: "web/src/api.ts", Language: "typescript"},
+		&graph.Node{ID: "mod-admin", Type: graph.NodeModule, Name: "app", FilePath: "admin/src/app.ts", Language: "typescript"},
+		&graph.Node{ID: "man-web", Type: graph.NodeDependency, Name: "axios", FilePath: "web/package.json",
+			Properties: map[string]string{"kind": "manifest_dep"}},
+		&graph.Node{ID: "man-admin", Type: graph.NodeDependency, Name: "axios", FilePath: "admin/package.json",
+			Properties: map[string]string{"kind": "manifest_dep"}},
+		&graph.Node{ID: "man-docs", Type: graph.NodeDependency, Name: "axios", FilePath: "docs/package.json",
+			Properties: map[string]string{"kind": "manifest_dep"}},
+	)
+	for _, src := range []string{"mod-web", "mod-admin"} {
+		if err := store.AddEdge(ctx, &graph.Edge{ID: graph.NewEdgeID(graph.EdgeImports, src, impID), Type: graph.EdgeImports,
+			SourceID: src, TargetID: impID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkImports(ctx)
+	if err != nil {
+		t.Fatalf("linkImports: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linkImports returned %d, want 2", count)
+	}
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{SourceID: impID, Type: graph.EdgeDependsOn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := make(map[string]bool)
+	for _, e := range edges {
+		targets[e.TargetID] = true
+	}
+	if !targets["man-web"] || !targets["man-admin"] || targets["man-docs"] {
+		t.Errorf("DependsOn targets = %v, want man-web and man-admin", targets)
+	}
+}
+
 func TestLinkImportsGoPrefixMatch(t *testing.T) {
 	store := newTestStore(t)
 	ctx := context.Background()
@@ -1238,18 +1630,71 @@ func TestRunPhases(t *testing.T) {
 	}
 }
 
+func TestRunPhasesSchedule(t *testing.T) {
+	l := NewLinker(newTestStore(t), nil, nil, false)
+	l.SetParallelism(2)
+	ctx := context.Background()
+
+	// a and b only finish once both have started, so they must run
+	// concurrently; c must wait for both.
+	var started sync.WaitGroup
+	started.Add(2)
+	var mu sync.Mutex
+	var order []string
+	phase := func(name string, wait bool, after ...string) Phase {
+		return Phase{Name: name, After: after, Fn: func(context.Context) (int, error) {
+			if wait {
+				started.Done()
+				started.Wait()
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return len(name), nil
+		}}
+	}
+	phases := []Phase{phase("a", true), phase("b", true), phase("c", false, "a", "b")}
+
+	results, err := l.RunPhases(ctx, phases)
+	if err != nil {
+		t.Fatalf("RunPhases: %v", err)
+	}
+	if len(order) != 3 || order[2] != "c" {
+		t.Errorf("order = %v, want c last", order)
+	}
+	if r := results["c"]; r.Linked != 1 {
+		t.Errorf("results[c] = %+v, want 1 linked", r)
+	}
+
+	// With parallelism 1 phases run in order, and a failure stops the rest.
+	l.SetParallelism(1)
+	order = nil
+	boom := errors.New("boom")
+	failing := Phase{Name: "fail", Fn: func(context.Context) (int, error) { return 0, boom }}
+	results, err = l.RunPhases(ctx, []Phase{phase("x", false), failing, phase("y", false)})
+	if !errors.Is(err, boom) {
+		t.Fatalf("RunPhases err = %v, want boom", err)
+	}
+	if len(order) != 1 || order[0] != "x" {
+		t.Errorf("order = %v, want only x to run", order)
+	}
+	if _, ok := results["x"]; !ok {
+		t.Error("missing result for x, which finished before the failure")
+	}
+}
+
 func TestPhasesCount(t *testing.T) {
 	store := newTestStore(t)
 	linker := NewLinker(store, nil, nil, false)
 
 	allPhases := linker.Phases()
-	if len(allPhases) != 9 {
-		t.Errorf("Phases() returned %d, want 9", len(allPhases))
+	if len(allPhases) != 26 {
+		t.Errorf("Phases() returned %d, want 26", len(allPhases))
 	}
 
 	newPhases := linker.NewPhases()
-	if len(newPhases) != 3 {
-		t.Errorf("NewPhases() returned %d, want 3", len(newPhases))
+	if len(newPhases) != 5 {
+		t.Errorf("NewPhases() returned %d, want 5", len(newPhases))
 	}
 }
 
@@ -1462,3 +1907,68 @@ func containsSubstr(s, sub string) bool {
 	}
 	return false
 }
+
+func TestLinkImportsMavenCoordinates(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	maven := func(group, artifact string) *graph.Node {
+		name := group + ":" + artifact
+		return &graph.Node{
+			ID: graph.NewNodeID("Dependency", "orders/pom.xml", name), Type: graph.NodeDependency, Name: name,
+			FilePath: "orders/pom.xml",
+			Properties: map[string]string{
+				"kind": "manifest_dep", "ecosystem": "maven", "group": group, "artifact": artifact,
+			},
+		}
+	}
+	deps := []*graph.Node{
+		maven("org.springframework", "spring-web"),
+		maven("org.springframework", "spring-webmvc"),
+		maven("com.fasterxml.jackson.core", "jackson-databind"),
+		maven("junit", "junit"),
+		maven("org.projectlombok", "lombok"),
+	}
+	addNodes(t, store, deps...)
+
+	tests := []struct {
+		importName string
+		want       *graph.Node // nil means no link
+	}{
+		{"org.springframework.web.client.RestTemplate", deps[0]},
+		{"com.fasterxml.jackson.databind.ObjectMapper", deps[2]},
+		{"org.junit.Test", deps[3]},
+		{"lombok.Data", deps[4]},
+		{"com.acme.orders.Order", nil},
+	}
+	for _, tt := range tests {
+		addNodes(t, store, &graph.Node{
+			ID:   graph.NewNodeID("Dependency", "orders/src/main/java/App.java", tt.importName),
+			Type: graph.NodeDependency, Name: tt.importName,
+			FilePath:   "orders/src/main/java/App.java",
+			Properties: map[string]string{"kind": "import"},
+		})
+	}
+
+	linker := NewLinker(store, nil, nil, false)
+	if _, err := linker.linkImports(ctx); err != nil {
+		t.Fatalf("linkImports: %v", err)
+	}
+
+	for _, tt := range tests {
+		impID := graph.NewNodeID("Dependency", "orders/src/main/java/App.java", tt.importName)
+		edges, err := store.GetEdges(ctx, impID, graph.EdgeDependsOn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want == nil {
+			if len(edges) != 0 {
+				t.Errorf("%s: expected no link, got %d", tt.importName, len(edges))
+			}
+			continue
+		}
+		if len(edges) != 1 || edges[0].TargetID != tt.want.ID {
+			t.Errorf("%s: edges = %+v, want one to %s", tt.importName, edges, tt.want.Name)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
@@ -18,12 +19,26 @@ You will be given:
 
 Your task: determine which endpoints the function is likely calling based on code context (variable names, comments, surrounding code patterns).
 
-Respond with a JSON array of matches. Each match should have:
+Respond with a JSON object whose "matches" array lists the matches. Each match should have:
 - "endpoint_path": the path of the matched endpoint
 - "confidence": "high", "medium", or "low"
 - "reason": brief explanation
 
-Only include matches with medium or high confidence. If no matches are likely, return an empty array [].`
+Only include matches with medium or high confidence. If no matches are likely, return {"matches": []}.`
+
+const llmCallPrompt = `You are a code dependency analyzer. You resolve function calls that static analysis could not bind to a definition.
+
+You will be given a numbered list of calls. Each names the calling function, the called name, the import it was called through, and numbered candidate definitions from the codebase with their files and signatures.
+
+Your task: for each call, pick the candidate it most likely invokes based on the import path, file layout, signatures and names.
+
+Respond with a JSON object whose "matches" array lists the matches. Each match should have:
+- "call": the call's number (e.g. "c1")
+- "target": the chosen candidate's number (e.g. "f3")
+- "confidence": "high", "medium", or "low"
+- "reason": brief explanation
+
+Only include matches with medium or high confidence. If no matches are likely, return {"matches": []}.`
 
 const eventBusPrompt = `You are a code dependency analyzer specializing in event-driven architectures.
 
@@ -34,13 +49,57 @@ You will be given:
 
 Your task: match producers to consumers based on event names, topic patterns, and code context.
 
-Respond with a JSON array of matches. Each match should have:
+Respond with a JSON object whose "matches" array lists the matches. Each match should have:
 - "producer": the producer function identifier
 - "consumer": the consumer function identifier
 - "event": the event name/topic
 - "confidence": "high", "medium", or "low"
 
-Only include matches with medium or high confidence. If no matches are likely, return an empty array [].`
+Only include matches with medium or high confidence. If no matches are likely, return {"matches": []}.`
+
+// confidenceSchema is the JSON Schema of a match's confidence.
+var confidenceSchema = map[string]any{"type": "string", "enum": []string{"high", "medium", "low"}}
+
+// matchesSchema returns the schema of a {"matches": [...]} reply whose
+// items have the given string properties, all required.
+func matchesSchema(name, description string, props ...string) llm.Schema {
+	properties := make(map[string]any, len(props))
+	for _, p := range props {
+		properties[p] = map[string]any{"type": "string"}
+	}
+	properties["confidence"] = confidenceSchema
+	return llm.Schema{
+		Name:        name,
+		Description: description,
+		Schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"matches": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type":       "object",
+						"properties": properties,
+						"required":   append(props, "confidence"),
+					},
+				},
+			},
+			"required": []string{"matches"},
+		},
+	}
+}
+
+var (
+	// llmMatchSchema is the structured output of the unresolved-calls analysis.
+	llmMatchSchema = matchesSchema("endpoint_matches", "API endpoints targeted by the unresolved HTTP calls.",
+		"endpoint_path", "reason")
+	// callMatchSchema is the structured output of the unresolved function
+	// calls analysis.
+	callMatchSchema = matchesSchema("call_matches", "Candidate definitions targeted by the unresolved function calls.",
+		"call", "target", "reason")
+	// eventMatchSchema is the structured output of the event-driven analysis.
+	eventMatchSchema = matchesSchema("event_matches", "Event producers matched to their consumers.",
+		"producer", "consumer", "event")
+)
 
 // llmMatch represents a single LLM-inferred endpoint match.
 type llmMatch struct {
@@ -49,6 +108,14 @@ type llmMatch struct {
 	Reason       string `json:"reason"`
 }
 
+// callMatch represents a single LLM-inferred function call target.
+type callMatch struct {
+	Call       string `json:"call"`
+	Target     string `json:"target"`
+	Confidence string `json:"confidence"`
+	Reason     string `json:"reason"`
+}
+
 // eventMatch represents a single LLM-inferred event bus match.
 type eventMatch struct {
 	Producer   string `json:"producer"`
@@ -59,8 +126,8 @@ type eventMatch struct {
 
 // llmAnalyzeUnresolvedCalls uses the LLM to resolve API calls that couldn't
 // be matched by static analysis. It batches calls per service and sends
-// them to the LLM with available endpoint context.
-func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context) (int, error) {
+// them to the LLM with available endpoint context, through c.
+func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context, c *llmCaller) (int, error) {
 	if l.llmClient == nil {
 		return 0, nil
 	}
@@ -98,14 +165,14 @@ func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context) (int, error) {
 			path = ep.Properties["path"]
 		}
 		framework := ep.Properties["framework"]
-		svc := topDir(ep.FilePath)
+		svc := l.group(ep.FilePath)
 		fmt.Fprintf(&epList, "- %s %s (service: %s, framework: %s)\n", method, path, svc, framework)
 	}
 
 	// Group unresolved calls by service for batched LLM requests.
 	byService := make(map[string][]*graph.Node)
 	for _, call := range unresolved {
-		svc := topDir(call.FilePath)
+		svc := l.group(call.FilePath)
 		byService[svc] = append(byService[svc], call)
 	}
 
@@ -114,14 +181,7 @@ func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	serviceByGroup := make(map[string]*graph.Node)
-	for _, svc := range services {
-		group := topDir(svc.FilePath)
-		if group == "" {
-			group = svc.Name
-		}
-		serviceByGroup[group] = svc
-	}
+	serviceByGroup := l.servicesByGroup(services)
 
 	// Build endpoint index for creating edges.
 	endpointByPath := make(map[string]*graph.Node)
@@ -135,9 +195,16 @@ func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context) (int, error) {
 		}
 	}
 
-	resolved := 0
-	for svc, calls := range byService {
-		// Build the call descriptions for this service batch.
+	// Build one prompt per service batch, in a stable order so repeat runs
+	// hit the response cache.
+	svcs := make([]string, 0, len(byService))
+	for svc := range byService {
+		svcs = append(svcs, svc)
+	}
+	sort.Strings(svcs)
+	prompts := make([]string, len(svcs))
+	for i, svc := range svcs {
+		calls := byService[svc]
 		var callDesc strings.Builder
 		for _, call := range calls {
 			method := call.Properties["http_method"]
@@ -149,23 +216,33 @@ func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context) (int, error) {
 				method, path, call.FilePath, call.Name)
 		}
 
-		userMsg := fmt.Sprintf(
+		prompts[i] = fmt.Sprintf(
 			"Service: %s\n\nUnresolved HTTP calls:\n%s\nAvailable API endpoints:\n%s\nWhich endpoints are these calls targeting?",
 			svc, callDesc.String(), epList.String(),
 		)
+	}
 
-		resp, err := l.llmClient.Chat(ctx, llmAnalyzerPrompt, []llm.Message{
-			{Role: llm.RoleUser, Content: userMsg},
-		})
-		if err != nil {
+	responses, errs := c.chatAll(ctx, llmAnalyzerPrompt, &llmMatchSchema, prompts)
+	resolved := 0
+	for i, svc := range svcs {
+		calls := byService[svc]
+		if errs[i] != nil {
 			if l.verbose {
-				l.log("  LLM analyzer error for service %s: %v", svc, err)
+				l.log("  LLM analyzer error for service %s: %v", svc, errs[i])
 			}
 			continue
 		}
 
-		// Parse LLM response.
-		matches := parseLLMMatches(resp.Content)
+		var reply struct {
+			Matches []llmMatch `json:"matches"`
+		}
+		if err := json.Unmarshal([]byte(responses[i]), &reply); err != nil {
+			if l.verbose {
+				l.log("  LLM analyzer returned unusable output for service %s: %v", svc, err)
+			}
+			continue
+		}
+		matches := reply.Matches
 		for _, m := range matches {
 			if m.Confidence == "low" {
 				continue
@@ -199,32 +276,32 @@ func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context) (int, error) {
 				Type:     graph.EdgeConsumes,
 				SourceID: caller.ID,
 				TargetID: ep.ID,
-				Properties: map[string]string{
-					"inferred":   "true",
-					"confidence": m.Confidence,
-					"method":     "llm_analysis",
-					"reason":     m.Reason,
-				},
+				Properties: withConfidence(map[string]string{
+					"inferred":       "true",
+					"llm_confidence": m.Confidence,
+					"method":         "llm_analysis",
+					"reason":         m.Reason,
+				}, graph.ConfidenceLLM, llmConfidenceScore(m.Confidence)),
 			}
 			if err := l.store.AddEdge(ctx, edge); err != nil {
 				continue
 			}
 
 			// Create service-level edge.
-			callerSvc := serviceByGroup[topDir(caller.FilePath)]
-			epSvc := serviceByGroup[topDir(ep.FilePath)]
+			callerSvc := serviceByGroup[l.group(caller.FilePath)]
+			epSvc := serviceByGroup[l.group(ep.FilePath)]
 			if callerSvc != nil && epSvc != nil && callerSvc.ID != epSvc.ID {
 				svcEdge := &graph.Edge{
 					ID:       graph.NewNodeID("llm_"+string(graph.EdgeDependsOn), callerSvc.ID, epSvc.ID),
 					Type:     graph.EdgeDependsOn,
 					SourceID: callerSvc.ID,
 					TargetID: epSvc.ID,
-					Properties: map[string]string{
-						"kind":       "api_dependency",
-						"inferred":   "true",
-						"confidence": m.Confidence,
-						"method":     "llm_analysis",
-					},
+					Properties: withConfidence(map[string]string{
+						"kind":           "api_dependency",
+						"inferred":       "true",
+						"llm_confidence": m.Confidence,
+						"method":         "llm_analysis",
+					}, graph.ConfidenceLLM, llmConfidenceScore(m.Confidence)),
 				}
 				_ = l.store.AddEdge(ctx, svcEdge)
 			}
@@ -236,9 +313,277 @@ func (l *Linker) llmAnalyzeUnresolvedCalls(ctx context.Context) (int, error) {
 	return resolved, nil
 }
 
+const (
+	// maxCallCandidates caps the candidate definitions listed per call.
+	maxCallCandidates = 8
+	// callsPerPrompt caps the unresolved function calls sent per LLM request.
+	callsPerPrompt = 25
+)
+
+// unresolvedCall is a function call the parser linked to the Dependency
+// node of the import it was made through, with the definitions its name
+// may refer to.
+type unresolvedCall struct {
+	edge       *graph.Edge
+	dep        *graph.Node
+	caller     *graph.Node
+	callee     string
+	candidates []*graph.Node
+}
+
+// llmAnalyzeUnresolvedFunctionCalls uses the LLM to resolve function calls
+// left on Dependency nodes: parsers link a call made through an import to
+// the import's node, recording the called name ("callee", and "export" for
+// a renamed import), and only some languages have a phase following the
+// import to the definition. Calls no phase resolved are batched per
+// service, each with the functions and methods of the import's language
+// sharing the called name, and the LLM picks the likely target. Each pick
+// becomes a Calls edge (inferred=true, confidence=llm). Calls no definition
+// in the graph is named after (library calls) are not sent.
+func (l *Linker) llmAnalyzeUnresolvedFunctionCalls(ctx context.Context, c *llmCaller) (int, error) {
+	if l.llmClient == nil {
+		return 0, nil
+	}
+	calls, err := l.unresolvedFunctionCalls(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(calls) == 0 {
+		return 0, nil
+	}
+
+	byService := make(map[string][]unresolvedCall)
+	for _, call := range calls {
+		svc := l.group(call.caller.FilePath)
+		byService[svc] = append(byService[svc], call)
+	}
+
+	// One prompt per service batch, in a stable order so repeat runs hit
+	// the response cache.
+	svcs := make([]string, 0, len(byService))
+	for svc := range byService {
+		svcs = append(svcs, svc)
+	}
+	sort.Strings(svcs)
+	var (
+		batches [][]unresolvedCall
+		prompts []string
+	)
+	for _, svc := range svcs {
+		list := byService[svc]
+		for start := 0; start < len(list); start += callsPerPrompt {
+			batch := list[start:min(start+callsPerPrompt, len(list))]
+			batches = append(batches, batch)
+			prompts = append(prompts, fmt.Sprintf(
+				"Service: %s\n\nUnresolved function calls:\n%s\nWhich candidate does each call invoke?",
+				svc, describeCalls(batch)))
+		}
+	}
+
+	responses, errs := c.chatAll(ctx, llmCallPrompt, &callMatchSchema, prompts)
+	resolved := 0
+	for i, batch := range batches {
+		if errs[i] != nil {
+			if l.verbose {
+				l.log("  LLM call analysis error: %v", errs[i])
+			}
+			continue
+		}
+		var reply struct {
+			Matches []callMatch `json:"matches"`
+		}
+		if err := json.Unmarshal([]byte(responses[i]), &reply); err != nil {
+			if l.verbose {
+				l.log("  LLM call analysis returned unusable output: %v", err)
+			}
+			continue
+		}
+		for _, m := range reply.Matches {
+			if m.Confidence == "low" {
+				continue
+			}
+			var ci, fi int
+			if _, err := fmt.Sscanf(m.Call, "c%d", &ci); err != nil || ci < 1 || ci > len(batch) {
+				continue
+			}
+			call := batch[ci-1]
+			if _, err := fmt.Sscanf(m.Target, "f%d", &fi); err != nil || fi < 1 || fi > len(call.candidates) {
+				continue
+			}
+			target := call.candidates[fi-1]
+
+			// Inferred edges share the ID of a static Calls edge between the
+			// same nodes; never replace one found by analysis.
+			existing, err := l.store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeCalls, SourceID: call.caller.ID, TargetID: target.ID})
+			if err != nil {
+				return resolved, err
+			}
+			if len(existing) > 0 {
+				continue
+			}
+			edge := &graph.Edge{
+				ID:       graph.NewEdgeID(graph.EdgeCalls, call.caller.ID, target.ID),
+				Type:     graph.EdgeCalls,
+				SourceID: call.caller.ID,
+				TargetID: target.ID,
+				Properties: withConfidence(map[string]string{
+					"callee":         call.callee,
+					"inferred":       "true",
+					"llm_confidence": m.Confidence,
+					"method":         "llm_analysis",
+					"reason":         m.Reason,
+				}, graph.ConfidenceLLM, llmConfidenceScore(m.Confidence)),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			resolved++
+
+			if l.verbose {
+				l.log("    LLM call: %s -> %s (%s)", symbolName(call.caller), symbolName(target), m.Confidence)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// unresolvedFunctionCalls returns the Calls edges to import Dependency
+// nodes whose caller has no Calls edge to a definition of the called name,
+// with the candidate definitions of each. Calls without candidates are
+// left out.
+func (l *Linker) unresolvedFunctionCalls(ctx context.Context) ([]unresolvedCall, error) {
+	deps, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Properties: map[string]string{"kind": "import"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]map[string][]*graph.Node) // language -> name -> definitions
+	definitions := func(language, name string) ([]*graph.Node, error) {
+		if byName[language] == nil {
+			byName[language] = make(map[string][]*graph.Node)
+			for _, t := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+				nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: t, Language: language})
+				if err != nil {
+					return nil, err
+				}
+				for _, n := range nodes {
+					byName[language][n.Name] = append(byName[language][n.Name], n)
+				}
+			}
+		}
+		return byName[language][name], nil
+	}
+
+	var calls []unresolvedCall
+	for _, dep := range deps {
+		edges, err := l.store.GetIncomingEdges(ctx, dep.ID, graph.EdgeCalls)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range edges {
+			callee := e.Properties["export"]
+			if callee == "" || callee == "default" {
+				callee = e.Properties["callee"]
+			}
+			if callee == "" {
+				continue
+			}
+			defs, err := definitions(dep.Language, callee)
+			if err != nil {
+				return nil, err
+			}
+			var candidates []*graph.Node
+			for _, d := range defs {
+				if d.ID != e.SourceID {
+					candidates = append(candidates, d)
+				}
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+			resolved, err := l.callResolved(ctx, e, callee)
+			if err != nil {
+				return nil, err
+			}
+			if resolved {
+				continue
+			}
+			caller, err := l.store.GetNode(ctx, e.SourceID)
+			if err != nil || caller == nil {
+				continue
+			}
+			sort.Slice(candidates, func(i, j int) bool {
+				// Definitions in the caller's service first.
+				si := l.group(candidates[i].FilePath) == l.group(caller.FilePath)
+				sj := l.group(candidates[j].FilePath) == l.group(caller.FilePath)
+				if si != sj {
+					return si
+				}
+				return candidates[i].FilePath < candidates[j].FilePath
+			})
+			if len(candidates) > maxCallCandidates {
+				candidates = candidates[:maxCallCandidates]
+			}
+			calls = append(calls, unresolvedCall{edge: e, dep: dep, caller: caller, callee: callee, candidates: candidates})
+		}
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i].edge.ID < calls[j].edge.ID })
+	return calls, nil
+}
+
+// callResolved reports whether the caller of the Calls edge e, made through
+// an import, also calls a definition named callee: a phase resolved it.
+func (l *Linker) callResolved(ctx context.Context, e *graph.Edge, callee string) (bool, error) {
+	out, err := l.store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeCalls, SourceID: e.SourceID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range out {
+		if o.TargetID == e.TargetID {
+			continue
+		}
+		if o.Properties["callee"] == callee || o.Properties["callee"] == e.Properties["callee"] {
+			if n, err := l.store.GetNode(ctx, o.TargetID); err == nil && n != nil && n.Type != graph.NodeDependency {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// describeCalls lists a batch of unresolved calls for a prompt, numbering
+// calls c1, c2, ... and each call's candidates f1, f2, ...
+func describeCalls(batch []unresolvedCall) string {
+	var b strings.Builder
+	for i, call := range batch {
+		fmt.Fprintf(&b, "c%d. %s in %s calls %q imported from %q\n",
+			i+1, symbolName(call.caller), call.caller.FilePath, call.callee, call.dep.Name)
+		for j, cand := range call.candidates {
+			fmt.Fprintf(&b, "    f%d. %s in %s", j+1, symbolName(cand), cand.FilePath)
+			if cand.Signature != "" {
+				fmt.Fprintf(&b, ": %s", cand.Signature)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// symbolName returns n's qualified name, or its name when it has none.
+func symbolName(n *graph.Node) string {
+	if n.QualifiedName != "" {
+		return n.QualifiedName
+	}
+	return n.Name
+}
+
 // llmAnalyzeEventDriven uses the LLM to detect publish/subscribe patterns
-// and create dependency edges between event producers and consumers.
-func (l *Linker) llmAnalyzeEventDriven(ctx context.Context) (int, error) {
+// and create dependency edges between event producers and consumers,
+// through c.
+func (l *Linker) llmAnalyzeEventDriven(ctx context.Context, c *llmCaller) (int, error) {
 	if l.llmClient == nil {
 		return 0, nil
 	}
@@ -246,24 +591,27 @@ func (l *Linker) llmAnalyzeEventDriven(ctx context.Context) (int, error) {
 	// Look for event-bus-related nodes by searching for known patterns.
 	// Event producers: functions that call publish/emit/send methods.
 	// Event consumers: functions with subscribe/on/handle patterns.
-	allFuncs, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeFunction})
-	if err != nil {
-		return 0, err
-	}
-
+	// Index the candidates by qualified name to look up the LLM's matches.
 	var producers, consumers []string
-	for _, fn := range allFuncs {
+	funcByQName := make(map[string]*graph.Node)
+	err := l.store.ScanNodes(ctx, graph.NodeFilter{Type: graph.NodeFunction}, func(fn *graph.Node) error {
 		name := strings.ToLower(fn.Name)
 		sig := strings.ToLower(fn.Signature)
 		// Detect event-related patterns from function names and signatures.
 		if containsAny(name, "publish", "emit", "send_event", "dispatch", "fire") ||
 			containsAny(sig, "publish", "emit", "send_event", "dispatch") {
-			producers = append(producers, fmt.Sprintf("- %s in %s (service: %s)", fn.QualifiedName, fn.FilePath, topDir(fn.FilePath)))
+			producers = append(producers, fmt.Sprintf("- %s in %s (service: %s)", fn.QualifiedName, fn.FilePath, l.group(fn.FilePath)))
+			funcByQName[fn.QualifiedName] = fn
 		}
 		if containsAny(name, "subscribe", "on_event", "handle_event", "consume", "listener") ||
 			containsAny(sig, "subscribe", "on_event", "handle_event", "consumer") {
-			consumers = append(consumers, fmt.Sprintf("- %s in %s (service: %s)", fn.QualifiedName, fn.FilePath, topDir(fn.FilePath)))
+			consumers = append(consumers, fmt.Sprintf("- %s in %s (service: %s)", fn.QualifiedName, fn.FilePath, l.group(fn.FilePath)))
+			funcByQName[fn.QualifiedName] = fn
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	if len(producers) == 0 || len(consumers) == 0 {
@@ -275,9 +623,7 @@ func (l *Linker) llmAnalyzeEventDriven(ctx context.Context) (int, error) {
 		strings.Join(producers, "\n"), strings.Join(consumers, "\n"),
 	)
 
-	resp, err := l.llmClient.Chat(ctx, eventBusPrompt, []llm.Message{
-		{Role: llm.RoleUser, Content: userMsg},
-	})
+	content, err := c.chatJSON(ctx, eventBusPrompt, userMsg, eventMatchSchema)
 	if err != nil {
 		if l.verbose {
 			l.log("  LLM event analysis error: %v", err)
@@ -285,27 +631,23 @@ func (l *Linker) llmAnalyzeEventDriven(ctx context.Context) (int, error) {
 		return 0, nil
 	}
 
-	matches := parseEventMatches(resp.Content)
-	resolved := 0
-
-	// Build function index for looking up by qualified name.
-	funcByQName := make(map[string]*graph.Node)
-	for _, fn := range allFuncs {
-		funcByQName[fn.QualifiedName] = fn
+	var reply struct {
+		Matches []eventMatch `json:"matches"`
+	}
+	if err := json.Unmarshal([]byte(content), &reply); err != nil {
+		if l.verbose {
+			l.log("  LLM event analysis returned unusable output: %v", err)
+		}
+		return 0, nil
 	}
+	matches := reply.Matches
+	resolved := 0
 
 	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
 	if err != nil {
 		return 0, err
 	}
-	serviceByGroup := make(map[string]*graph.Node)
-	for _, svc := range services {
-		group := topDir(svc.FilePath)
-		if group == "" {
-			group = svc.Name
-		}
-		serviceByGroup[group] = svc
-	}
+	serviceByGroup := l.servicesByGroup(services)
 
 	for _, m := range matches {
 		if m.Confidence == "low" {
@@ -324,34 +666,34 @@ func (l *Linker) llmAnalyzeEventDriven(ctx context.Context) (int, error) {
 			Type:     graph.EdgeCalls,
 			SourceID: producerNode.ID,
 			TargetID: consumerNode.ID,
-			Properties: map[string]string{
-				"kind":       "event_driven",
-				"event":      m.Event,
-				"inferred":   "true",
-				"confidence": m.Confidence,
-				"method":     "llm_analysis",
-			},
+			Properties: withConfidence(map[string]string{
+				"kind":           "event_driven",
+				"event":          m.Event,
+				"inferred":       "true",
+				"llm_confidence": m.Confidence,
+				"method":         "llm_analysis",
+			}, graph.ConfidenceLLM, llmConfidenceScore(m.Confidence)),
 		}
 		if err := l.store.AddEdge(ctx, edge); err != nil {
 			continue
 		}
 
 		// Create service-level edge.
-		prodSvc := serviceByGroup[topDir(producerNode.FilePath)]
-		consSvc := serviceByGroup[topDir(consumerNode.FilePath)]
+		prodSvc := serviceByGroup[l.group(producerNode.FilePath)]
+		consSvc := serviceByGroup[l.group(consumerNode.FilePath)]
 		if prodSvc != nil && consSvc != nil && prodSvc.ID != consSvc.ID {
 			svcEdge := &graph.Edge{
 				ID:       graph.NewNodeID("event_"+string(graph.EdgeDependsOn), consSvc.ID, prodSvc.ID),
 				Type:     graph.EdgeDependsOn,
 				SourceID: consSvc.ID,
 				TargetID: prodSvc.ID,
-				Properties: map[string]string{
-					"kind":       "event_dependency",
-					"event":      m.Event,
-					"inferred":   "true",
-					"confidence": m.Confidence,
-					"method":     "llm_analysis",
-				},
+				Properties: withConfidence(map[string]string{
+					"kind":           "event_dependency",
+					"event":          m.Event,
+					"inferred":       "true",
+					"llm_confidence": m.Confidence,
+					"method":         "llm_analysis",
+				}, graph.ConfidenceLLM, llmConfidenceScore(m.Confidence)),
 			}
 			_ = l.store.AddEdge(ctx, svcEdge)
 		}
@@ -375,55 +717,6 @@ func (l *Linker) filterUnresolvedCalls(ctx context.Context, calls []*graph.Node)
 	return unresolved
 }
 
-// parseLLMMatches extracts the JSON array of matches from the LLM response.
-func parseLLMMatches(content string) []llmMatch {
-	// Try to find JSON array in the response.
-	jsonStr := extractJSON(content)
-	if jsonStr == "" {
-		return nil
-	}
-	var matches []llmMatch
-	if err := json.Unmarshal([]byte(jsonStr), &matches); err != nil {
-		return nil
-	}
-	return matches
-}
-
-// parseEventMatches extracts event match results from LLM response.
-func parseEventMatches(content string) []eventMatch {
-	jsonStr := extractJSON(content)
-	if jsonStr == "" {
-		return nil
-	}
-	var matches []eventMatch
-	if err := json.Unmarshal([]byte(jsonStr), &matches); err != nil {
-		return nil
-	}
-	return matches
-}
-
-// extractJSON finds the first JSON array in a string.
-func extractJSON(s string) string {
-	start := strings.Index(s, "[")
-	if start == -1 {
-		return ""
-	}
-	// Find matching closing bracket.
-	depth := 0
-	for i := start; i < len(s); i++ {
-		switch s[i] {
-		case '[':
-			depth++
-		case ']':
-			depth--
-			if depth == 0 {
-				return s[start : i+1]
-			}
-		}
-	}
-	return ""
-}
-
 // containsAny checks if s contains any of the given substrings.
 func containsAny(s string, subs ...string) bool {
 	for _, sub := range subs {
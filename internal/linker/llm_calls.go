@@ -0,0 +1,250 @@
+package linker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+// Defaults for LLMLimits fields left at zero.
+const (
+	defaultLLMConcurrency = 4
+	defaultLLMRetries     = 3
+	defaultLLMBackoff     = time.Second
+	maxLLMBackoff         = 30 * time.Second
+)
+
+// errLLMBudget is returned for LLM calls made after the token budget is
+// spent.
+var errLLMBudget = errors.New("LLM token budget exhausted")
+
+// LLMLimits bounds the linker's LLM-assisted phases.
+type LLMLimits struct {
+	// Concurrency is how many LLM requests run at once (default 4).
+	Concurrency int
+	// MaxRetries is how many times a failed request is retried, with
+	// exponential backoff (default 3; negative disables retries).
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled for each
+	// further one up to 30s (default 1s).
+	Backoff time.Duration
+	// TokenBudget caps the input plus output tokens spent per run; requests
+	// past it are skipped. Zero means no limit. Cached responses are free.
+	TokenBudget int
+}
+
+// SetLLMLimits sets the concurrency, retry and token budget limits of the
+// LLM-assisted phases.
+func (l *Linker) SetLLMLimits(limits LLMLimits) {
+	l.llmLimits = limits
+}
+
+// llmUsage counts what the LLM-assisted phases of a run spent.
+type llmUsage struct {
+	Requests     int
+	CacheHits    int
+	Retries      int
+	Failures     int
+	Invalid      int
+	InputTokens  int
+	OutputTokens int
+}
+
+// llmCaller sends the linker's LLM requests with a response cache persisted
+// in the graph store (LLMCache nodes keyed by prompt hash), bounded
+// concurrency, retries with exponential backoff, and token accounting.
+type llmCaller struct {
+	client llm.Client
+	store  graph.Store
+	limits LLMLimits
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	sem   chan struct{}
+	mu    sync.Mutex
+	usage llmUsage
+}
+
+func (l *Linker) newLLMCaller() *llmCaller {
+	limits := l.llmLimits
+	if limits.Concurrency <= 0 {
+		limits.Concurrency = defaultLLMConcurrency
+	}
+	if limits.MaxRetries == 0 {
+		limits.MaxRetries = defaultLLMRetries
+	}
+	if limits.Backoff <= 0 {
+		limits.Backoff = defaultLLMBackoff
+	}
+	return &llmCaller{
+		client: l.llmClient,
+		store:  l.store,
+		limits: limits,
+		sleep:  sleepContext,
+		sem:    make(chan struct{}, limits.Concurrency),
+	}
+}
+
+// llmCacheKey hashes everything that determines a response.
+func llmCacheKey(provider, model, systemPrompt, userMsg string, schema *llm.Schema) string {
+	h := sha256.New()
+	parts := []string{provider, model, systemPrompt, userMsg}
+	if schema != nil {
+		data, _ := json.Marshal(schema.Schema)
+		parts = append(parts, schema.Name, string(data))
+	}
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// chat returns the response to userMsg, from the cache when an identical
+// prompt was answered before.
+func (c *llmCaller) chat(ctx context.Context, systemPrompt, userMsg string) (string, error) {
+	return c.call(ctx, systemPrompt, userMsg, nil)
+}
+
+// chatJSON returns the response to userMsg as JSON validated against
+// schema. Replies that stay invalid after llm.ChatJSON's re-prompts fail
+// with an error wrapping llm.ErrInvalidOutput and are not cached.
+func (c *llmCaller) chatJSON(ctx context.Context, systemPrompt, userMsg string, schema llm.Schema) (string, error) {
+	return c.call(ctx, systemPrompt, userMsg, &schema)
+}
+
+// call answers userMsg from the cache or the LLM, as text or, when schema
+// is set, as structured output.
+func (c *llmCaller) call(ctx context.Context, systemPrompt, userMsg string, schema *llm.Schema) (string, error) {
+	key := llmCacheKey(c.client.Provider(), c.client.Model(), systemPrompt, userMsg, schema)
+	id := graph.NewNodeID(string(graph.NodeLLMCache), "", key)
+	if n, err := c.store.GetNode(ctx, id); err == nil && n != nil {
+		c.mu.Lock()
+		c.usage.CacheHits++
+		c.mu.Unlock()
+		return n.Properties["content"], nil
+	}
+
+	c.sem <- struct{}{}
+	defer func() { <-c.sem }()
+
+	var resp *llm.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		c.mu.Lock()
+		spent := c.usage.InputTokens + c.usage.OutputTokens
+		c.mu.Unlock()
+		if c.limits.TokenBudget > 0 && spent >= c.limits.TokenBudget {
+			return "", errLLMBudget
+		}
+
+		msgs := []llm.Message{{Role: llm.RoleUser, Content: userMsg}}
+		if schema != nil {
+			resp, err = llm.ChatJSON(ctx, c.client, systemPrompt, msgs, *schema, llm.DefaultJSONRetries)
+		} else {
+			resp, err = c.client.Chat(ctx, systemPrompt, msgs)
+		}
+		c.mu.Lock()
+		c.usage.Requests++
+		// An invalid structured reply still reports what it spent.
+		if resp != nil {
+			c.usage.InputTokens += resp.Usage.InputTokens
+			c.usage.OutputTokens += resp.Usage.OutputTokens
+		}
+		if errors.Is(err, llm.ErrInvalidOutput) {
+			c.usage.Invalid++
+		}
+		c.mu.Unlock()
+		// Invalid output was already re-prompted; retrying would repeat it.
+		if err == nil || errors.Is(err, llm.ErrInvalidOutput) || attempt >= c.limits.MaxRetries || ctx.Err() != nil {
+			break
+		}
+
+		delay := c.limits.Backoff << attempt
+		if delay > maxLLMBackoff || delay <= 0 {
+			delay = maxLLMBackoff
+		}
+		c.mu.Lock()
+		c.usage.Retries++
+		c.mu.Unlock()
+		if err := c.sleep(ctx, delay); err != nil {
+			return "", err
+		}
+	}
+	if err != nil {
+		c.mu.Lock()
+		c.usage.Failures++
+		c.mu.Unlock()
+		return "", err
+	}
+
+	// A failed cache write only costs a repeat request next run.
+	_ = c.store.AddNode(ctx, &graph.Node{
+		ID:   id,
+		Type: graph.NodeLLMCache,
+		Name: key,
+		Properties: map[string]string{
+			"content":       resp.Content,
+			"provider":      c.client.Provider(),
+			"model":         c.client.Model(),
+			"input_tokens":  strconv.Itoa(resp.Usage.InputTokens),
+			"output_tokens": strconv.Itoa(resp.Usage.OutputTokens),
+			"created_at":    time.Now().UTC().Format(time.RFC3339),
+		},
+	})
+	return resp.Content, nil
+}
+
+// chatAll sends every prompt, up to the concurrency limit at once, and
+// returns the responses and errors by index. With a schema, each response
+// is structured output as from chatJSON.
+func (c *llmCaller) chatAll(ctx context.Context, systemPrompt string, schema *llm.Schema, userMsgs []string) ([]string, []error) {
+	out := make([]string, len(userMsgs))
+	errs := make([]error, len(userMsgs))
+	var wg sync.WaitGroup
+	for i, msg := range userMsgs {
+		wg.Add(1)
+		go func(i int, msg string) {
+			defer wg.Done()
+			out[i], errs[i] = c.call(ctx, systemPrompt, msg, schema)
+		}(i, msg)
+	}
+	wg.Wait()
+	return out, errs
+}
+
+// logUsage logs the run's LLM usage when the linker is verbose.
+func (l *Linker) logUsage(c *llmCaller) {
+	if !l.verbose {
+		return
+	}
+	c.mu.Lock()
+	u := c.usage
+	c.mu.Unlock()
+	budget := "no budget"
+	if c.limits.TokenBudget > 0 {
+		budget = fmt.Sprintf("budget %d", c.limits.TokenBudget)
+	}
+	l.log("  LLM usage: %d requests (%d retries, %d failed, %d invalid), %d cached, %d input + %d output tokens (%s)",
+		u.Requests, u.Retries, u.Failures, u.Invalid, u.CacheHits, u.InputTokens, u.OutputTokens, budget)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,135 @@
+package linker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+// flakyLLMClient fails its first failures requests, then answers with the
+// prompt, spending 10 input and 5 output tokens per answer.
+type flakyLLMClient struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (c *flakyLLMClient) Chat(_ context.Context, _ string, msgs []llm.Message) (*llm.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, errors.New("rate limited")
+	}
+	return &llm.Response{Content: "re: " + msgs[0].Content, Usage: llm.TokenUsage{InputTokens: 10, OutputTokens: 5}}, nil
+}
+
+func (c *flakyLLMClient) Model() string    { return "flaky" }
+func (c *flakyLLMClient) Provider() string { return "test" }
+func (c *flakyLLMClient) Close() error     { return nil }
+
+func TestLLMCallerRetriesAndCaches(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	client := &flakyLLMClient{failures: 2}
+	l := NewLinker(store, client, nil, false)
+
+	c := l.newLLMCaller()
+	var delays []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	got, err := c.chat(ctx, "system", "hello")
+	if err != nil {
+		t.Fatalf("chat: %v", err)
+	}
+	if got != "re: hello" {
+		t.Errorf("chat = %q, want %q", got, "re: hello")
+	}
+	if len(delays) != 2 || delays[0] != time.Second || delays[1] != 2*time.Second {
+		t.Errorf("backoff delays = %v, want [1s 2s]", delays)
+	}
+
+	// A new run answers the same prompt from the store.
+	c = l.newLLMCaller()
+	if got, err := c.chat(ctx, "system", "hello"); err != nil || got != "re: hello" {
+		t.Fatalf("cached chat = %q, %v", got, err)
+	}
+	if client.calls != 3 {
+		t.Errorf("client calls = %d, want 3 (no request for the cached prompt)", client.calls)
+	}
+	if c.usage.CacheHits != 1 || c.usage.Requests != 0 {
+		t.Errorf("usage = %+v, want 1 cache hit and no requests", c.usage)
+	}
+}
+
+func TestLLMCallerTokenBudget(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	client := &flakyLLMClient{}
+	l := NewLinker(store, client, nil, false)
+	l.SetLLMLimits(LLMLimits{Concurrency: 1, TokenBudget: 20})
+
+	c := l.newLLMCaller()
+	_, errs := c.chatAll(ctx, "system", nil, []string{"a", "b", "c"})
+	var exhausted int
+	for _, err := range errs {
+		if errors.Is(err, errLLMBudget) {
+			exhausted++
+		} else if err != nil {
+			t.Errorf("chatAll error = %v", err)
+		}
+	}
+	// Each answer spends 15 tokens, so the budget of 20 allows two.
+	if exhausted != 1 || client.calls != 2 {
+		t.Errorf("exhausted = %d, client calls = %d; want 1 and 2", exhausted, client.calls)
+	}
+	if c.usage.InputTokens != 20 || c.usage.OutputTokens != 10 {
+		t.Errorf("usage = %+v, want 20 input and 10 output tokens", c.usage)
+	}
+}
+
+// replyLLMClient answers every request with the same content.
+type replyLLMClient struct {
+	content string
+	calls   int
+}
+
+func (c *replyLLMClient) Chat(_ context.Context, _ string, _ []llm.Message) (*llm.Response, error) {
+	c.calls++
+	return &llm.Response{Content: c.content}, nil
+}
+
+func (c *replyLLMClient) Model() string    { return "reply" }
+func (c *replyLLMClient) Provider() string { return "test" }
+func (c *replyLLMClient) Close() error     { return nil }
+
+func TestLLMCallerInvalidStructuredOutput(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	client := &replyLLMClient{content: "I could not find any matches."}
+	l := NewLinker(store, client, nil, false)
+
+	c := l.newLLMCaller()
+	_, err := c.chatJSON(ctx, "system", "hello", llmMatchSchema)
+	if !errors.Is(err, llm.ErrInvalidOutput) {
+		t.Fatalf("chatJSON error = %v, want ErrInvalidOutput", err)
+	}
+	// One request, re-prompted after each invalid reply, never retried as a
+	// transport failure.
+	if client.calls != 1+llm.DefaultJSONRetries || c.usage.Invalid != 1 || c.usage.Retries != 0 {
+		t.Errorf("client calls = %d, usage = %+v", client.calls, c.usage)
+	}
+
+	// Invalid output is not cached.
+	client.content = `{"matches": []}`
+	got, err := l.newLLMCaller().chatJSON(ctx, "system", "hello", llmMatchSchema)
+	if err != nil || got != `{"matches":[]}` {
+		t.Fatalf("chatJSON = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,163 @@
+package linker
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkORMRelations builds the data-model layer of the graph: a RelatesTo
+// edge from each DBModel to the models its ORM associations name
+// (ActiveRecord, SQLAlchemy, Django, GORM and Entity Framework), as the
+// parsers recorded them in the model's "relations" property. Targets are
+// DBModels of the same language, preferring those in the model's service.
+// Associations from one model to another share an edge listing their
+// fields and cardinalities.
+//
+// Entity Framework entities need not carry attributes, so C# classes named
+// by a DbSet<T> property of a class in the same service are promoted to
+// DBModel first.
+func (l *Linker) linkORMRelations(ctx context.Context) (int, error) {
+	if err := l.promoteEFEntities(ctx); err != nil {
+		return 0, err
+	}
+	models, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeDBModel})
+	if err != nil {
+		return 0, err
+	}
+	byName := make(map[string][]*graph.Node)
+	for _, m := range models {
+		byName[m.Language+":"+m.Name] = append(byName[m.Language+":"+m.Name], m)
+	}
+
+	linked := 0
+	for _, m := range models {
+		relations := parser.ParseModelRelations(m.Properties[parser.PropModelRelations])
+		if len(relations) == 0 {
+			continue
+		}
+		group := l.group(m.FilePath)
+		type link struct {
+			target        *graph.Node
+			candidates    int
+			fields        []string
+			cardinalities []string
+			through       []string
+		}
+		links := make(map[string]*link)
+		var order []string
+		for _, r := range relations {
+			candidates := byName[m.Language+":"+r.Target]
+			var local []*graph.Node
+			for _, c := range candidates {
+				if l.group(c.FilePath) == group {
+					local = append(local, c)
+				}
+			}
+			if len(local) > 0 {
+				candidates = local
+			}
+			for _, target := range candidates {
+				lk, ok := links[target.ID]
+				if !ok {
+					lk = &link{target: target, candidates: len(candidates)}
+					links[target.ID] = lk
+					order = append(order, target.ID)
+				}
+				lk.fields = appendString(lk.fields, r.Field)
+				lk.cardinalities = appendString(lk.cardinalities, r.Cardinality)
+				if r.Through != "" {
+					lk.through = appendString(lk.through, r.Through)
+				}
+				lk.candidates = max(lk.candidates, len(candidates))
+			}
+		}
+
+		for _, id := range order {
+			lk := links[id]
+			props := map[string]string{
+				"fields":      strings.Join(lk.fields, ","),
+				"cardinality": strings.Join(lk.cardinalities, ","),
+			}
+			if orm := m.Properties[parser.PropORM]; orm != "" {
+				props[parser.PropORM] = orm
+			}
+			if len(lk.through) > 0 {
+				props["through"] = strings.Join(lk.through, ",")
+			}
+			level, score := nameMatchConfidence(lk.candidates)
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeRelatesTo, m.ID, id),
+				Type:       graph.EdgeRelatesTo,
+				SourceID:   m.ID,
+				TargetID:   id,
+				Properties: withConfidence(props, level, score),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+			if l.verbose {
+				l.log("    Model %s relates to %s (%s)", m.Name, lk.target.Name, props["cardinality"])
+			}
+		}
+	}
+	return linked, nil
+}
+
+// promoteEFEntities retypes as DBModel the C# classes registered as Entity
+// Framework entities by a DbSet<T> property in their service.
+func (l *Linker) promoteEFEntities(ctx context.Context) error {
+	props, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeVariable,
+		Language:   "csharp",
+		Properties: map[string]string{"property": "true"},
+	})
+	if err != nil {
+		return err
+	}
+	entities := make(map[string]bool)
+	for _, p := range props {
+		typ := strings.TrimSuffix(p.Properties["type"], "?")
+		if !strings.HasPrefix(typ, "DbSet<") || !strings.HasSuffix(typ, ">") {
+			continue
+		}
+		name := strings.TrimSpace(typ[len("DbSet<") : len(typ)-1])
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		entities[l.group(p.FilePath)+":"+name] = true
+	}
+	if len(entities) == 0 {
+		return nil
+	}
+
+	classes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeClass, Language: "csharp"})
+	if err != nil {
+		return err
+	}
+	for _, c := range classes {
+		if !entities[l.group(c.FilePath)+":"+c.Name] {
+			continue
+		}
+		c.Type = graph.NodeDBModel
+		if err := l.store.UpdateNode(ctx, c); err != nil {
+			return err
+		}
+		if l.verbose {
+			l.log("    EF entity: %s", c.QualifiedName)
+		}
+	}
+	return nil
+}
+
+// appendString appends s to list unless it is already there.
+func appendString(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
@@ -0,0 +1,90 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkORMRelations(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	model := func(id, lang, name, file, relations string) *graph.Node {
+		n := &graph.Node{ID: id, Type: graph.NodeDBModel, Name: name, Language: lang, FilePath: file}
+		if relations != "" {
+			n.Properties = map[string]string{parser.PropORM: "active_record", parser.PropModelRelations: relations}
+		}
+		return n
+	}
+	addNodes(t, store,
+		model("order", "ruby", "Order", "shop/app/models/order.rb",
+			"customer:Customer:many_to_one,billing_customer:Customer:many_to_one,line_items:LineItem:one_to_many,tags:Tag:many_to_many:Tagging,ghost:Ghost:one_to_one"),
+		model("customer", "ruby", "Customer", "shop/app/models/customer.rb", ""),
+		model("other-customer", "ruby", "Customer", "billing/app/models/customer.rb", ""),
+		model("line-item", "ruby", "LineItem", "shop/app/models/line_item.rb", ""),
+		model("tag-a", "ruby", "Tag", "tags/a/tag.rb", ""),
+		model("tag-b", "ruby", "Tag", "labels/b/tag.rb", ""),
+		model("py-line-item", "python", "LineItem", "shop/models.py", ""),
+		&graph.Node{ID: "ghost", Type: graph.NodeClass, Name: "Ghost", Language: "ruby", FilePath: "shop/app/ghost.rb"},
+		// EF: OrderEntity is registered by the DbContext, Audit is not.
+		&graph.Node{ID: "cs-order", Type: graph.NodeClass, Name: "OrderEntity", Language: "csharp", FilePath: "api/Data/OrderEntity.cs",
+			Properties: map[string]string{parser.PropORM: "ef", parser.PropModelRelations: "Audit:Audit:one_to_one,Self:OrderEntity:one_to_one"}},
+		&graph.Node{ID: "cs-audit", Type: graph.NodeClass, Name: "Audit", Language: "csharp", FilePath: "api/Data/Audit.cs"},
+		&graph.Node{ID: "cs-set", Type: graph.NodeVariable, Name: "Orders", Language: "csharp", FilePath: "api/Data/ShopContext.cs",
+			Properties: map[string]string{"property": "true", "type": "DbSet<Shop.OrderEntity>"}},
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkORMRelations(ctx)
+	if err != nil {
+		t.Fatalf("linkORMRelations: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("linkORMRelations returned %d, want 5", count)
+	}
+
+	promoted, err := store.GetNode(ctx, "cs-order")
+	if err != nil || promoted.Type != graph.NodeDBModel {
+		t.Errorf("EF entity not promoted to DBModel: %+v, %v", promoted, err)
+	}
+	if audit, _ := store.GetNode(ctx, "cs-audit"); audit == nil || audit.Type != graph.NodeClass {
+		t.Errorf("unregistered class retyped: %+v", audit)
+	}
+
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeRelatesTo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]map[string]string)
+	for _, e := range edges {
+		got[e.SourceID+">"+e.TargetID] = e.Properties
+	}
+	tests := []struct {
+		edge, fields, cardinality, through, confidence string
+	}{
+		{"order>customer", "customer,billing_customer", "many_to_one", "", graph.ConfidenceExact},
+		{"order>line-item", "line_items", "one_to_many", "", graph.ConfidenceExact},
+		{"order>tag-a", "tags", "many_to_many", "Tagging", graph.ConfidenceHeuristic},
+		{"order>tag-b", "tags", "many_to_many", "Tagging", graph.ConfidenceHeuristic},
+		{"cs-order>cs-order", "Self", "one_to_one", "", graph.ConfidenceExact},
+	}
+	if len(got) != len(tests) {
+		t.Errorf("RelatesTo edges = %v, want %d", got, len(tests))
+	}
+	for _, tt := range tests {
+		props, ok := got[tt.edge]
+		if !ok {
+			t.Errorf("missing RelatesTo edge %s", tt.edge)
+			continue
+		}
+		if props["fields"] != tt.fields || props["cardinality"] != tt.cardinality || props["through"] != tt.through {
+			t.Errorf("edge %s: fields=%q cardinality=%q through=%q, want %q %q %q",
+				tt.edge, props["fields"], props["cardinality"], props["through"], tt.fields, tt.cardinality, tt.through)
+		}
+		if props[graph.PropConfidence] != tt.confidence {
+			t.Errorf("edge %s confidence = %q, want %q", tt.edge, props[graph.PropConfidence], tt.confidence)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package linker
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// linkPartialClasses merges C# partial classes declared across files. The
+// parser already merges the parts within one file; here the parts sharing a
+// qualified name are folded into the part with the lowest file path, which
+// gains Contains edges (kind=partial) to the members of every other part and
+// the union of their implemented interfaces. The other parts record the
+// canonical node in Properties["partial_of"], and every part lists all files
+// in Properties["partial_files"].
+func (l *Linker) linkPartialClasses(ctx context.Context) (int, error) {
+	classes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeClass, Language: "csharp"})
+	if err != nil {
+		return 0, err
+	}
+	groups := make(map[string][]*graph.Node) // qualified name -> parts
+	for _, c := range classes {
+		if c.Properties["partial"] == "true" {
+			groups[c.QualifiedName] = append(groups[c.QualifiedName], c)
+		}
+	}
+
+	linked := 0
+	for _, parts := range groups {
+		if len(parts) < 2 {
+			continue
+		}
+		sort.Slice(parts, func(i, j int) bool { return parts[i].FilePath < parts[j].FilePath })
+		canonical := parts[0]
+
+		var files, implements []string
+		for _, part := range parts {
+			files = append(files, part.FilePath)
+			for _, iface := range strings.Split(part.Properties["implements"], ",") {
+				if iface != "" && !slices.Contains(implements, iface) {
+					implements = append(implements, iface)
+				}
+			}
+		}
+
+		for _, part := range parts[1:] {
+			edges, err := l.store.GetEdges(ctx, part.ID, graph.EdgeContains)
+			if err != nil {
+				return linked, err
+			}
+			for _, e := range edges {
+				if e.SourceID != part.ID {
+					continue
+				}
+				edge := &graph.Edge{
+					ID:         graph.NewEdgeID(graph.EdgeContains, canonical.ID, e.TargetID),
+					Type:       graph.EdgeContains,
+					SourceID:   canonical.ID,
+					TargetID:   e.TargetID,
+					Properties: withConfidence(map[string]string{"kind": "partial", "part": part.FilePath}, graph.ConfidenceExact, scoreExact),
+				}
+				if err := l.store.AddEdge(ctx, edge); err != nil {
+					continue
+				}
+				linked++
+			}
+			part.Properties["partial_of"] = canonical.ID
+		}
+
+		if len(implements) > 0 {
+			canonical.Properties["implements"] = strings.Join(implements, ",")
+		}
+		for _, part := range parts {
+			part.Properties["partial_files"] = strings.Join(files, ",")
+			if err := l.store.UpdateNode(ctx, part); err != nil {
+				return linked, err
+			}
+		}
+
+		if l.verbose {
+			l.log("    Partial class: %s (%d parts)", canonical.QualifiedName, len(parts))
+		}
+	}
+	return linked, nil
+}
@@ -0,0 +1,76 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkPartialClasses(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	cs := func(n *graph.Node) *graph.Node { n.Language = "csharp"; return n }
+	addNodes(t, store,
+		cs(&graph.Node{ID: "part-b", Type: graph.NodeClass, Name: "OrderService", QualifiedName: "Shop.OrderService", FilePath: "api/OrderService.Persistence.cs",
+			Properties: map[string]string{"partial": "true", "implements": "IDisposable"}}),
+		cs(&graph.Node{ID: "part-a", Type: graph.NodeClass, Name: "OrderService", QualifiedName: "Shop.OrderService", FilePath: "api/OrderService.Api.cs",
+			Properties: map[string]string{"partial": "true", "implements": "IOrderService"}}),
+		cs(&graph.Node{ID: "other", Type: graph.NodeClass, Name: "OrderService", QualifiedName: "Admin.OrderService", FilePath: "admin/OrderService.cs",
+			Properties: map[string]string{"partial": "true"}}),
+		cs(&graph.Node{ID: "m-save", Type: graph.NodeMethod, Name: "Save", FilePath: "api/OrderService.Persistence.cs"}),
+		cs(&graph.Node{ID: "m-place", Type: graph.NodeMethod, Name: "Place", FilePath: "api/OrderService.Api.cs"}),
+	)
+	for _, e := range []*graph.Edge{
+		{ID: "c1", Type: graph.EdgeContains, SourceID: "part-b", TargetID: "m-save"},
+		{ID: "c2", Type: graph.EdgeContains, SourceID: "part-a", TargetID: "m-place"},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkPartialClasses(ctx)
+	if err != nil {
+		t.Fatalf("linkPartialClasses: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("linkPartialClasses returned %d, want 1", count)
+	}
+
+	edges, err := store.GetEdges(ctx, "part-a", graph.EdgeContains)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contains := make(map[string]string)
+	for _, e := range edges {
+		if e.SourceID == "part-a" {
+			contains[e.TargetID] = e.Properties["kind"]
+		}
+	}
+	if _, ok := contains["m-place"]; !ok || contains["m-save"] != "partial" {
+		t.Errorf("canonical Contains edges = %v, want own member and partial m-save", contains)
+	}
+
+	canonical, err := store.GetNode(ctx, "part-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := canonical.Properties["implements"]; got != "IOrderService,IDisposable" {
+		t.Errorf("canonical implements = %q", got)
+	}
+	if got := canonical.Properties["partial_files"]; got != "api/OrderService.Api.cs,api/OrderService.Persistence.cs" {
+		t.Errorf("partial_files = %q", got)
+	}
+	part, err := store.GetNode(ctx, "part-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if part.Properties["partial_of"] != "part-a" {
+		t.Errorf("partial_of = %q, want part-a", part.Properties["partial_of"])
+	}
+	if other, _ := store.GetNode(ctx, "other"); other.Properties["partial_files"] != "" {
+		t.Error("single-part partial class should be left alone")
+	}
+}
@@ -0,0 +1,159 @@
+package linker
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Path parameter types inferred from route templates.
+const (
+	paramInt   = "int"
+	paramFloat = "float"
+	paramUUID  = "uuid"
+	paramBool  = "bool"
+	paramAlpha = "alpha"
+)
+
+// paramTypeNames maps route constraint and converter names (ASP.NET
+// {id:int}, Flask <int:id>) to parameter types.
+var paramTypeNames = map[string]string{
+	"int": paramInt, "long": paramInt, "integer": paramInt,
+	"float": paramFloat, "double": paramFloat, "decimal": paramFloat, "number": paramFloat,
+	"guid": paramUUID, "uuid": paramUUID,
+	"bool": paramBool, "boolean": paramBool,
+	"alpha": paramAlpha,
+}
+
+var (
+	// intRegexPattern matches a parameter regex only digits satisfy
+	// ({id:\d+}, {id:[0-9]+}, :id(\d+)).
+	intRegexPattern = regexp.MustCompile(`^(\\d|\[0-9\])[+*]?$|^(\\d|\[0-9\])\{\d+(,\d*)?\}$`)
+	// uuidRegexPattern matches a parameter regex for UUIDs.
+	uuidRegexPattern = regexp.MustCompile(`\{8\}-`)
+
+	// paramLiterals are the literal path segments each type accepts.
+	paramLiterals = map[string]*regexp.Regexp{
+		paramInt:   regexp.MustCompile(`^-?\d+$`),
+		paramFloat: regexp.MustCompile(`^-?\d+(\.\d+)?$`),
+		paramUUID:  regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`),
+		paramBool:  regexp.MustCompile(`^(true|false)$`),
+		paramAlpha: regexp.MustCompile(`^[a-z]+$`),
+	}
+)
+
+// pathParam is a parameter of a route template.
+type pathParam struct {
+	Name string
+	// Type is the inferred type, or "" when any value fits.
+	Type string
+}
+
+// parsePathParam parses a route parameter as matched by paramPattern:
+// {id}, {id:int}, {id:\d+}, {id?}, {*slug}, :id, :id(\d+), <id> or
+// <int:id>. A parameter without a constraint is typed by its name only
+// when the name says it holds a UUID (:uuid, {order_uuid}).
+func parsePathParam(raw string) pathParam {
+	var name, constraint string
+	switch raw[0] {
+	case '{':
+		name, constraint, _ = strings.Cut(raw[1:len(raw)-1], ":")
+	case '<':
+		inner := raw[1 : len(raw)-1]
+		if conv, n, ok := strings.Cut(inner, ":"); ok {
+			name, constraint = n, conv
+		} else {
+			name = inner
+		}
+	default: // :name or :name(regex)
+		name = raw[1:]
+		if i := strings.IndexByte(name, '('); i >= 0 {
+			name, constraint = name[:i], strings.TrimSuffix(name[i+1:], ")")
+		}
+	}
+	name = strings.Trim(name, "*?")
+	return pathParam{Name: name, Type: paramType(name, constraint)}
+}
+
+// paramType infers a parameter's type from its constraint (a type name,
+// possibly followed by more constraints, or a regex) or, failing that, its
+// name.
+func paramType(name, constraint string) string {
+	if constraint != "" {
+		first, _, _ := strings.Cut(constraint, ":")
+		if i := strings.IndexByte(first, '('); i > 0 {
+			first = first[:i] // min(1), length(3,10)
+		}
+		if t, ok := paramTypeNames[strings.ToLower(first)]; ok {
+			return t
+		}
+		if intRegexPattern.MatchString(constraint) {
+			return paramInt
+		}
+		if uuidRegexPattern.MatchString(constraint) {
+			return paramUUID
+		}
+		return ""
+	}
+	lower := strings.ToLower(name)
+	if lower == "uuid" || lower == "guid" || strings.HasSuffix(lower, "_uuid") || strings.HasSuffix(lower, "uuid") && len(lower) > 4 {
+		return paramUUID
+	}
+	return ""
+}
+
+// pathParams returns the parameters of a route template in order.
+func pathParams(path string) []pathParam {
+	var params []pathParam
+	for _, raw := range paramPattern.FindAllString(path, -1) {
+		params = append(params, parsePathParam(raw))
+	}
+	return params
+}
+
+// formatPathParams renders parameters as an endpoint's path_params
+// property: "name:type" for typed parameters and "name" for the rest,
+// comma separated.
+func formatPathParams(params []pathParam) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.Name
+		if p.Type != "" {
+			parts[i] += ":" + p.Type
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// wildcardType returns the parameter type of a normalized path segment and
+// whether the segment is a parameter ("*" or "*int").
+func wildcardType(seg string) (string, bool) {
+	if !strings.HasPrefix(seg, "*") {
+		return "", false
+	}
+	return seg[1:], true
+}
+
+// segmentsCompatible reports whether a segment of one normalized path can
+// match the same segment of another, and whether the match was confirmed
+// by a typed parameter: two parameters match unless both are typed
+// differently, and a literal fits a parameter of its type.
+func segmentsCompatible(a, b string) (ok, typed bool) {
+	ta, wa := wildcardType(a)
+	tb, wb := wildcardType(b)
+	switch {
+	case wa && wb:
+		return ta == "" || tb == "" || ta == tb, false
+	case wa:
+		return literalFits(ta, b), ta != ""
+	case wb:
+		return literalFits(tb, a), tb != ""
+	}
+	return a == b, false
+}
+
+// literalFits reports whether a literal path segment is a valid value of
+// a parameter of type t.
+func literalFits(t, literal string) bool {
+	re, ok := paramLiterals[t]
+	return !ok || re.MatchString(literal)
+}
@@ -0,0 +1,67 @@
+package linker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkPipelines links CI pipeline jobs to the services they build, test or
+// deploy: the services of the directories the job works in, as the parser
+// derived them from working directories and commands. Each Targets edge
+// lists the job's actions and the directories that matched the service.
+func (l *Linker) linkPipelines(ctx context.Context) (int, error) {
+	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return 0, err
+	}
+	byGroup := l.servicesByGroup(services)
+	if len(byGroup) == 0 {
+		return 0, nil
+	}
+
+	linked := 0
+	err = l.store.ScanNodes(ctx, graph.NodeFilter{Type: graph.NodePipelineJob}, func(job *graph.Node) error {
+		paths := job.Properties[parser.PipelinePropPaths]
+		if paths == "" {
+			return nil
+		}
+		// Group takes a file path; any file in the directory will do.
+		dirs := make(map[string][]string)
+		var order []string
+		for _, dir := range strings.Split(paths, ",") {
+			svc, ok := byGroup[l.group(dir+"/_")]
+			if !ok {
+				continue
+			}
+			if _, seen := dirs[svc.ID]; !seen {
+				order = append(order, svc.ID)
+			}
+			dirs[svc.ID] = append(dirs[svc.ID], dir)
+		}
+		for _, svcID := range order {
+			props := map[string]string{"paths": strings.Join(dirs[svcID], ",")}
+			if actions := job.Properties[parser.PipelinePropActions]; actions != "" {
+				props[parser.PipelinePropActions] = actions
+			}
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeTargets, job.ID, svcID),
+				Type:       graph.EdgeTargets,
+				SourceID:   job.ID,
+				TargetID:   svcID,
+				Properties: withConfidence(props, graph.ConfidenceHeuristic, scoreStrong),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+			if l.verbose {
+				l.log("    Pipeline job %s targets %s", job.Name, svcID)
+			}
+		}
+		return nil
+	})
+	return linked, err
+}
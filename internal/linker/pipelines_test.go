@@ -0,0 +1,63 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkPipelines(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	job := func(id, paths, actions string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodePipelineJob, Name: id, FilePath: ".github/workflows/ci.yml",
+			Properties: map[string]string{parser.PipelinePropPaths: paths, parser.PipelinePropActions: actions}}
+	}
+	addNodes(t, store,
+		&graph.Node{ID: "svc-orders", Type: graph.NodeService, Name: "orders"},
+		&graph.Node{ID: "svc-web", Type: graph.NodeService, Name: "web"},
+		job("test-orders", "orders/internal,orders/cmd/server", "test"),
+		job("deploy-all", "orders,web/dist,docs", "build,deploy"),
+		job("lint", "", ""),
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkPipelines(ctx)
+	if err != nil {
+		t.Fatalf("linkPipelines: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkPipelines returned %d, want 3", count)
+	}
+
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeTargets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[[2]string]map[string]string)
+	for _, e := range edges {
+		got[[2]string{e.SourceID, e.TargetID}] = e.Properties
+	}
+	tests := []struct {
+		job, svc, paths, actions string
+	}{
+		{"test-orders", "svc-orders", "orders/internal,orders/cmd/server", "test"},
+		{"deploy-all", "svc-orders", "orders", "build,deploy"},
+		{"deploy-all", "svc-web", "web/dist", "build,deploy"},
+	}
+	if len(got) != len(tests) {
+		t.Errorf("Targets edges = %v, want %d", got, len(tests))
+	}
+	for _, tt := range tests {
+		props, ok := got[[2]string{tt.job, tt.svc}]
+		if !ok {
+			t.Errorf("missing Targets edge %s -> %s", tt.job, tt.svc)
+			continue
+		}
+		if props["paths"] != tt.paths || props[parser.PipelinePropActions] != tt.actions {
+			t.Errorf("%s -> %s: paths=%q actions=%q, want %q %q", tt.job, tt.svc, props["paths"], props[parser.PipelinePropActions], tt.paths, tt.actions)
+		}
+	}
+}
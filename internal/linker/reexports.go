@@ -0,0 +1,261 @@
+package linker
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// tsModuleExts are the file name suffixes tried, in order, when resolving a
+// relative TypeScript module specifier to a file.
+var tsModuleExts = []string{".ts", ".tsx", ".d.ts", "/index.ts", "/index.tsx"}
+
+// tsModules indexes TypeScript modules for export resolution.
+type tsModules struct {
+	exports map[string][]string               // file path -> export bindings
+	decls   map[string]map[string]*graph.Node // file path -> declaration name -> node
+}
+
+// tsModuleIndex indexes the TypeScript modules and their declarations of
+// declType (functions for calls, interfaces for implements).
+func (l *Linker) tsModuleIndex(ctx context.Context, declType graph.NodeType) (*tsModules, error) {
+	modules, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeModule, Language: "typescript"})
+	if err != nil {
+		return nil, err
+	}
+	decls, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: declType, Language: "typescript"})
+	if err != nil {
+		return nil, err
+	}
+	idx := &tsModules{
+		exports: make(map[string][]string),
+		decls:   make(map[string]map[string]*graph.Node),
+	}
+	for _, m := range modules {
+		idx.exports[m.FilePath] = nil
+		if list := m.Properties["exports"]; list != "" {
+			idx.exports[m.FilePath] = strings.Split(list, ",")
+		}
+	}
+	for _, d := range decls {
+		if idx.decls[d.FilePath] == nil {
+			idx.decls[d.FilePath] = make(map[string]*graph.Node)
+		}
+		idx.decls[d.FilePath][d.Name] = d
+	}
+	return idx, nil
+}
+
+// linkReexports resolves TypeScript calls made through imports to the
+// function defining the called export. The parser links such calls to the
+// import's Dependency node, recording the export name in the edge's "export"
+// property; this phase resolves the module path to a file for the caller's
+// file (package imports are shared by their importers) and follows its
+// re-export chain (export * from, export { a as b } from, barrel index.ts
+// files) to the defining module. It creates Calls edges (kind=cross_module)
+// from the caller to the function, with "via" listing the re-exporting files
+// passed through. Renders edges from JSX elements naming
+// imported components are resolved the same way.
+func (l *Linker) linkReexports(ctx context.Context) (int, error) {
+	idx, err := l.tsModuleIndex(ctx, graph.NodeFunction)
+	if err != nil {
+		return 0, err
+	}
+
+	imports, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Language:   "typescript",
+		Properties: map[string]string{"kind": "import"},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	aliased, err := l.aliasedImports(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, imp := range imports {
+		for _, edgeType := range []graph.EdgeType{graph.EdgeCalls, graph.EdgeRenders} {
+			edges, err := l.store.GetEdges(ctx, imp.ID, edgeType)
+			if err != nil {
+				return linked, err
+			}
+			for _, e := range edges {
+				export := e.Properties["export"]
+				if e.TargetID != imp.ID || export == "" {
+					continue
+				}
+				file := l.resolveImportFor(ctx, idx, aliased, imp, e.SourceID)
+				if file == "" {
+					continue
+				}
+				target, via := idx.findExport(file, export, make(map[string]bool))
+				if target == nil || target.ID == e.SourceID {
+					continue
+				}
+				props := map[string]string{"kind": "cross_module", "callee": export}
+				if edgeType == graph.EdgeRenders {
+					props = map[string]string{"kind": "cross_module", "component": export}
+				}
+				if len(via) > 0 {
+					props["via"] = strings.Join(via, ",")
+				}
+				withConfidence(props, graph.ConfidenceExact, scoreExact)
+				edge := &graph.Edge{
+					ID:         graph.NewEdgeID(edgeType, e.SourceID, target.ID),
+					Type:       edgeType,
+					SourceID:   e.SourceID,
+					TargetID:   target.ID,
+					Properties: props,
+				}
+				if err := l.store.AddEdge(ctx, edge); err != nil {
+					continue
+				}
+				linked++
+
+				if l.verbose && len(via) > 0 {
+					l.log("    Re-export: %s resolved to %s via %s", export, target.FilePath, props["via"])
+				}
+			}
+		}
+	}
+	return linked, nil
+}
+
+// aliasedImports maps an importing file and specifier ("file\x00spec") to
+// the file the aliases phase resolved it to, from its Imports edges.
+func (l *Linker) aliasedImports(ctx context.Context) (map[string]string, error) {
+	aliased := make(map[string]string)
+	for _, kind := range []string{"alias", "workspace"} {
+		edges, err := l.store.QueryEdges(ctx, graph.EdgeFilter{
+			Type:       graph.EdgeImports,
+			Properties: map[string]string{"kind": kind},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range edges {
+			src, err := l.store.GetNode(ctx, e.SourceID)
+			if err != nil || src == nil {
+				continue
+			}
+			if tgt, err := l.store.GetNode(ctx, e.TargetID); err == nil && tgt != nil {
+				aliased[src.FilePath+"\x00"+e.Properties["specifier"]] = tgt.FilePath
+			}
+		}
+	}
+	return aliased, nil
+}
+
+// resolveImportFor returns the indexed file the import imp names for the
+// caller node callerID: a relative specifier is resolved against the
+// importing file, other specifiers as the aliases phase resolved them for
+// that file. A package import is shared by its importers, so the caller's
+// own file decides. It returns "" for imports of external packages.
+func (l *Linker) resolveImportFor(ctx context.Context, idx *tsModules, aliased map[string]string, imp *graph.Node, callerID string) string {
+	importer := imp.FilePath
+	if importer == "" {
+		if caller, err := l.store.GetNode(ctx, callerID); err == nil && caller != nil {
+			importer = caller.FilePath
+		}
+	}
+	if file := idx.resolve(importer, imp.Name); file != "" {
+		return file
+	}
+	if file := aliased[importer+"\x00"+imp.Name]; file != "" {
+		if _, ok := idx.exports[file]; ok {
+			return file
+		}
+	}
+	// Fall back to the resolution recorded on the node.
+	if _, ok := idx.exports[imp.Properties["resolved_path"]]; ok {
+		return imp.Properties["resolved_path"]
+	}
+	return ""
+}
+
+// resolve maps a relative module specifier imported by a file to the indexed
+// file it names, or "" for package imports and unknown files.
+func (m *tsModules) resolve(importer, spec string) string {
+	if !strings.HasPrefix(spec, ".") {
+		return ""
+	}
+	base := path.Join(path.Dir(importer), spec)
+	if _, ok := m.exports[base]; ok {
+		return base
+	}
+	// ESM imports name the compiled file: './util.js' is './util.ts'.
+	for _, ext := range []string{".js", ".jsx", ".mjs"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+	for _, ext := range tsModuleExts {
+		if _, ok := m.exports[base+ext]; ok {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// findExport finds the declaration a module exports under name, following
+// its export bindings. It returns the declaration and the re-exporting files
+// passed through on the way.
+func (m *tsModules) findExport(file, name string, visited map[string]bool) (*graph.Node, []string) {
+	key := file + "\x00" + name
+	if visited[key] {
+		return nil, nil
+	}
+	visited[key] = true
+
+	bindings := m.exports[file]
+	// Named bindings first: export { a as name } [from './x'].
+	for _, b := range bindings {
+		exported, original, source := parseExportBinding(b)
+		if exported != name || original == "*" {
+			continue
+		}
+		if source == "" {
+			if fn := m.decls[file][original]; fn != nil {
+				return fn, nil
+			}
+			continue
+		}
+		if next := m.resolve(file, source); next != "" {
+			if fn, via := m.findExport(next, original, visited); fn != nil {
+				return fn, append([]string{file}, via...)
+			}
+		}
+	}
+	// Default exports are only ever named bindings.
+	if name == "default" {
+		return nil, nil
+	}
+	// Exported declarations: export function name() {}.
+	if fn := m.decls[file][name]; fn != nil && fn.Exported {
+		return fn, nil
+	}
+	// export * from './x' forwards every named export except default.
+	for _, b := range bindings {
+		exported, _, source := parseExportBinding(b)
+		if exported != "*" {
+			continue
+		}
+		if next := m.resolve(file, source); next != "" {
+			if fn, via := m.findExport(next, name, visited); fn != nil {
+				return fn, append([]string{file}, via...)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// parseExportBinding splits an "exported=original@source" export binding.
+func parseExportBinding(b string) (exported, original, source string) {
+	exported, rest, _ := strings.Cut(b, "=")
+	original, source, _ = strings.Cut(rest, "@")
+	return exported, original, source
+}
@@ -0,0 +1,180 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkReexports(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+	module := func(file, exports string) *graph.Node {
+		n := ts(&graph.Node{ID: "mod-" + file, Type: graph.NodeModule, Name: file, FilePath: file})
+		if exports != "" {
+			n.Properties = map[string]string{"exports": exports}
+		}
+		return n
+	}
+	addNodes(t, store,
+		module("src/app.ts", ""),
+		module("src/lib/format.ts", ""),
+		module("src/lib/strings.ts", "slugify=slug@,default=pad@"),
+		module("src/lib/index.ts", "*=*@./format,slugify=slugify@./strings,pad=default@./strings"),
+		ts(&graph.Node{ID: "main", Type: graph.NodeFunction, Name: "main", FilePath: "src/app.ts", Exported: true}),
+		ts(&graph.Node{ID: "render", Type: graph.NodeFunction, Name: "render", FilePath: "src/app.ts"}),
+		ts(&graph.Node{ID: "formatDate", Type: graph.NodeFunction, Name: "formatDate", FilePath: "src/lib/format.ts", Exported: true}),
+		ts(&graph.Node{ID: "internal", Type: graph.NodeFunction, Name: "internal", FilePath: "src/lib/format.ts"}),
+		ts(&graph.Node{ID: "slug", Type: graph.NodeFunction, Name: "slug", FilePath: "src/lib/strings.ts"}),
+		ts(&graph.Node{ID: "pad", Type: graph.NodeFunction, Name: "pad", FilePath: "src/lib/strings.ts"}),
+		ts(&graph.Node{ID: "dep-lib", Type: graph.NodeDependency, Name: "./lib", FilePath: "src/app.ts", Properties: map[string]string{"kind": "import"}}),
+		ts(&graph.Node{ID: "dep-strings", Type: graph.NodeDependency, Name: "./lib/strings.js", FilePath: "src/app.ts", Properties: map[string]string{"kind": "import"}}),
+		ts(&graph.Node{ID: "dep-react", Type: graph.NodeDependency, Name: "react", FilePath: "src/app.ts", Properties: map[string]string{"kind": "import"}}),
+	)
+	for _, e := range []*graph.Edge{
+		{ID: "c1", Type: graph.EdgeCalls, SourceID: "main", TargetID: "dep-lib", Properties: map[string]string{"callee": "formatDate", "export": "formatDate"}},
+		{ID: "c2", Type: graph.EdgeCalls, SourceID: "main", TargetID: "dep-lib", Properties: map[string]string{"callee": "s", "export": "slugify"}},
+		{ID: "c3", Type: graph.EdgeCalls, SourceID: "main", TargetID: "dep-lib", Properties: map[string]string{"callee": "pad", "export": "pad"}},
+		{ID: "c4", Type: graph.EdgeCalls, SourceID: "render", TargetID: "dep-strings", Properties: map[string]string{"callee": "p", "export": "default"}},
+		{ID: "c5", Type: graph.EdgeCalls, SourceID: "main", TargetID: "dep-lib", Properties: map[string]string{"callee": "internal", "export": "internal"}},
+		{ID: "c6", Type: graph.EdgeCalls, SourceID: "main", TargetID: "dep-react", Properties: map[string]string{"callee": "useState", "export": "useState"}},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := NewLinker(store, nil, nil, false).linkReexports(ctx); err != nil {
+		t.Fatalf("linkReexports: %v", err)
+	}
+
+	edges, err := store.GetEdges(ctx, "main", graph.EdgeCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string) // target -> via
+	for _, e := range edges {
+		if e.Properties["kind"] == "cross_module" {
+			got[e.TargetID] = e.Properties["via"]
+		}
+	}
+	want := map[string]string{
+		"formatDate": "src/lib/index.ts",
+		"slug":       "src/lib/index.ts",
+		"pad":        "src/lib/index.ts",
+	}
+	if len(got) != len(want) {
+		t.Errorf("cross-module calls = %v, want %v", got, want)
+	}
+	for target, via := range want {
+		if v, ok := got[target]; !ok || v != via {
+			t.Errorf("call to %s via %q, want via %q (got %v)", target, v, via, got)
+		}
+	}
+
+	// A default import of the defining module resolves directly.
+	direct, err := store.GetEdges(ctx, "render", graph.EdgeCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, e := range direct {
+		if e.TargetID == "pad" && e.Properties["kind"] == "cross_module" && e.Properties["via"] == "" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected direct call render -> pad, got %+v", direct)
+	}
+}
+
+func TestLinkReexportsRenders(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+	addNodes(t, store,
+		ts(&graph.Node{ID: "mod-app", Type: graph.NodeModule, Name: "src/App.tsx", FilePath: "src/App.tsx"}),
+		ts(&graph.Node{ID: "mod-ui", Type: graph.NodeModule, Name: "src/ui/index.ts", FilePath: "src/ui/index.ts",
+			Properties: map[string]string{"exports": "Button=Button@./Button"}}),
+		ts(&graph.Node{ID: "mod-button", Type: graph.NodeModule, Name: "src/ui/Button.tsx", FilePath: "src/ui/Button.tsx"}),
+		ts(&graph.Node{ID: "App", Type: graph.NodeFunction, Name: "App", FilePath: "src/App.tsx", Exported: true}),
+		ts(&graph.Node{ID: "Button", Type: graph.NodeFunction, Name: "Button", FilePath: "src/ui/Button.tsx", Exported: true}),
+		ts(&graph.Node{ID: "dep-ui", Type: graph.NodeDependency, Name: "./ui", FilePath: "src/App.tsx", Properties: map[string]string{"kind": "import"}}),
+	)
+	if err := store.AddEdge(ctx, &graph.Edge{ID: "r1", Type: graph.EdgeRenders, SourceID: "App", TargetID: "dep-ui",
+		Properties: map[string]string{"component": "Button", "export": "Button"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewLinker(store, nil, nil, false).linkReexports(ctx); err != nil {
+		t.Fatalf("linkReexports: %v", err)
+	}
+	edges, err := store.GetEdges(ctx, "App", graph.EdgeRenders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range edges {
+		if e.TargetID == "Button" && e.Properties["kind"] == "cross_module" {
+			if e.Properties["via"] != "src/ui/index.ts" {
+				t.Errorf("via = %q, want src/ui/index.ts", e.Properties["via"])
+			}
+			return
+		}
+	}
+	t.Error("expected a cross-module Renders edge from App to Button")
+}
+
+func TestLinkReexportsSharedImport(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	// Two apps import "@lib" through one shared node, each aliased to its
+	// own library; every caller resolves through its own file's alias.
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+	depID := graph.NewDependencyID("typescript", "@lib")
+	addNodes(t, store,
+		ts(&graph.Node{ID: "mod-web", Type: graph.NodeModule, Name: "web/app.ts", FilePath: "web/app.ts"}),
+		ts(&graph.Node{ID: "mod-web-lib", Type: graph.NodeModule, Name: "web/lib.ts", FilePath: "web/lib.ts"}),
+		ts(&graph.Node{ID: "mod-admin", Type: graph.NodeModule, Name: "admin/app.ts", FilePath: "admin/app.ts"}),
+		ts(&graph.Node{ID: "mod-admin-lib", Type: graph.NodeModule, Name: "admin/lib.ts", FilePath: "admin/lib.ts"}),
+		ts(&graph.Node{ID: "web-main", Type: graph.NodeFunction, Name: "main", FilePath: "web/app.ts"}),
+		ts(&graph.Node{ID: "admin-main", Type: graph.NodeFunction, Name: "main", FilePath: "admin/app.ts"}),
+		ts(&graph.Node{ID: "web-format", Type: graph.NodeFunction, Name: "format", FilePath: "web/lib.ts", Exported: true}),
+		ts(&graph.Node{ID: "admin-format", Type: graph.NodeFunction, Name: "format", FilePath: "admin/lib.ts", Exported: true}),
+		ts(&graph.Node{ID: depID, Type: graph.NodeDependency, Name: "@lib", Properties: map[string]string{"kind": "import"}}),
+	)
+	alias := map[string]string{"kind": "alias", "specifier": "@lib"}
+	for _, e := range []*graph.Edge{
+		{ID: "i1", Type: graph.EdgeImports, SourceID: "mod-web", TargetID: depID},
+		{ID: "i2", Type: graph.EdgeImports, SourceID: "mod-admin", TargetID: depID},
+		{ID: "a1", Type: graph.EdgeImports, SourceID: "mod-web", TargetID: "mod-web-lib", Properties: alias},
+		{ID: "a2", Type: graph.EdgeImports, SourceID: "mod-admin", TargetID: "mod-admin-lib", Properties: alias},
+		{ID: "c1", Type: graph.EdgeCalls, SourceID: "web-main", TargetID: depID, Properties: map[string]string{"callee": "format", "export": "format"}},
+		{ID: "c2", Type: graph.EdgeCalls, SourceID: "admin-main", TargetID: depID, Properties: map[string]string{"callee": "format", "export": "format"}},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkReexports(ctx)
+	if err != nil {
+		t.Fatalf("linkReexports: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linked %d calls, want 2", count)
+	}
+	for caller, want := range map[string]string{"web-main": "web-format", "admin-main": "admin-format"} {
+		edges, err := store.QueryEdges(ctx, graph.EdgeFilter{SourceID: caller, TargetID: want, Type: graph.EdgeCalls})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(edges) != 1 {
+			t.Errorf("%s: got %d calls to %s, want 1", caller, len(edges), want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package linker
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// linkDIRegistrations resolves ASP.NET Core container registrations such as
+// services.AddScoped<IFoo, Foo>(), recorded by the C# parser as Dependency
+// nodes (kind=di_registration). It creates DependsOn edges (kind=di_registration)
+// from each registered interface to its implementation class, then uses them
+// to resolve calls made through interface-typed fields and properties
+// (Properties["member_calls"]) to the interface method and to the matching
+// method of every registered implementation, choosing among overloads by the
+// recorded argument count.
+func (l *Linker) linkDIRegistrations(ctx context.Context) (int, error) {
+	regs, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Language:   "csharp",
+		Properties: map[string]string{"kind": "di_registration"},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	classes, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeClass, Language: "csharp"})
+	if err != nil {
+		return 0, err
+	}
+	interfaces, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeInterface, Language: "csharp"})
+	if err != nil {
+		return 0, err
+	}
+	classByName := make(map[string][]*graph.Node)
+	for _, cls := range classes {
+		classByName[cls.Name] = append(classByName[cls.Name], cls)
+	}
+	ifaceByName := make(map[string][]*graph.Node)
+	for _, iface := range interfaces {
+		ifaceByName[iface.Name] = append(ifaceByName[iface.Name], iface)
+	}
+
+	// Services may be registered against an abstract base class as well as
+	// an interface.
+	lookupService := func(ref *graph.Node, name string) *graph.Node {
+		if iface := l.bestMatch(ref, ifaceByName[name]); iface != nil {
+			return iface
+		}
+		return l.bestMatch(ref, classByName[name])
+	}
+
+	linked := 0
+	registered := make(map[string][]*graph.Node) // service node ID -> implementations
+	for _, reg := range regs {
+		service := lookupService(reg, reg.Properties["service"])
+		impl := l.bestMatch(reg, classByName[reg.Properties["implementation"]])
+		if service == nil || impl == nil || service.ID == impl.ID {
+			continue
+		}
+		registered[service.ID] = appendUnique(registered[service.ID], impl)
+		level, score := nameMatchConfidence(len(classByName[reg.Properties["implementation"]]))
+
+		edge := &graph.Edge{
+			ID:       graph.NewEdgeID(graph.EdgeDependsOn, service.ID, impl.ID),
+			Type:     graph.EdgeDependsOn,
+			SourceID: service.ID,
+			TargetID: impl.ID,
+			Properties: withConfidence(map[string]string{
+				"kind":          "di_registration",
+				"lifetime":      reg.Properties["lifetime"],
+				"registered_in": reg.FilePath,
+			}, level, score),
+		}
+		if err := l.store.AddEdge(ctx, edge); err != nil {
+			continue
+		}
+		linked++
+
+		if l.verbose {
+			l.log("    DI registration: %s -> %s (%s)", service.Name, impl.Name, reg.Properties["lifetime"])
+		}
+	}
+
+	methods, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeMethod, Language: "csharp"})
+	if err != nil {
+		return 0, err
+	}
+	methodByOwner := make(map[string][]*graph.Node) // "filePath\x00class\x00method" -> overloads
+	for _, m := range methods {
+		key := m.FilePath + "\x00" + m.Properties["class"] + "\x00" + m.Name
+		methodByOwner[key] = append(methodByOwner[key], m)
+	}
+	ownerMethod := func(owner *graph.Node, name string, args int) *graph.Node {
+		return parser.PickOverload(methodByOwner[owner.FilePath+"\x00"+owner.Name+"\x00"+name], args)
+	}
+
+	for _, caller := range methods {
+		calls := caller.Properties["member_calls"]
+		if calls == "" {
+			continue
+		}
+		for _, call := range strings.Split(calls, ",") {
+			typeName, method, ok := strings.Cut(call, ".")
+			if !ok {
+				continue
+			}
+			args := -1 // unknown for entries recorded without "/argc"
+			if name, argc, ok := strings.Cut(method, "/"); ok {
+				method = name
+				if n, err := strconv.Atoi(argc); err == nil {
+					args = n
+				}
+			}
+			service := lookupService(caller, typeName)
+			if service == nil {
+				continue
+			}
+
+			type callTarget struct {
+				method *graph.Node
+				kind   string
+			}
+			var targets []callTarget
+			if m := ownerMethod(service, method, args); m != nil {
+				kind := "member"
+				if service.Type == graph.NodeInterface {
+					kind = "interface"
+				}
+				targets = append(targets, callTarget{m, kind})
+			}
+			for _, impl := range registered[service.ID] {
+				if m := ownerMethod(impl, method, args); m != nil {
+					targets = append(targets, callTarget{m, "di_registration"})
+				}
+			}
+
+			for _, t := range targets {
+				if t.method.ID == caller.ID {
+					continue
+				}
+				// Calls reaching an implementation through a container
+				// registration assume that registration is the one used.
+				level, score := graph.ConfidenceExact, scoreExact
+				if t.kind == "di_registration" {
+					level, score = graph.ConfidenceHeuristic, scoreStrong
+				}
+				edge := &graph.Edge{
+					ID:       graph.NewEdgeID(graph.EdgeCalls, caller.ID, t.method.ID),
+					Type:     graph.EdgeCalls,
+					SourceID: caller.ID,
+					TargetID: t.method.ID,
+					Properties: withConfidence(map[string]string{
+						"kind":   t.kind,
+						"callee": method,
+						"via":    typeName,
+					}, level, score),
+				}
+				if err := l.store.AddEdge(ctx, edge); err != nil {
+					continue
+				}
+				linked++
+			}
+		}
+	}
+	return linked, nil
+}
+
+// appendUnique appends n to nodes unless a node with the same ID is present.
+func appendUnique(nodes []*graph.Node, n *graph.Node) []*graph.Node {
+	for _, existing := range nodes {
+		if existing.ID == n.ID {
+			return nodes
+		}
+	}
+	return append(nodes, n)
+}
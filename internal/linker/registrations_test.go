@@ -0,0 +1,67 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkDIRegistrations(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	cs := func(n *graph.Node) *graph.Node { n.Language = "csharp"; return n }
+
+	addNodes(t, store,
+		cs(&graph.Node{ID: "reg", Type: graph.NodeDependency, Name: "IOrderService => OrderService", FilePath: "api/Program.cs",
+			Properties: map[string]string{"kind": "di_registration", "service": "IOrderService", "implementation": "OrderService", "lifetime": "scoped"}}),
+		cs(&graph.Node{ID: "reg-missing", Type: graph.NodeDependency, Name: "IClock => SystemClock", FilePath: "api/Program.cs",
+			Properties: map[string]string{"kind": "di_registration", "service": "IClock", "implementation": "SystemClock", "lifetime": "singleton"}}),
+		cs(&graph.Node{ID: "iface", Type: graph.NodeInterface, Name: "IOrderService", FilePath: "api/Services/IOrderService.cs"}),
+		cs(&graph.Node{ID: "iface-place", Type: graph.NodeMethod, Name: "PlaceOrder", FilePath: "api/Services/IOrderService.cs",
+			Properties: map[string]string{"class": "IOrderService"}}),
+		cs(&graph.Node{ID: "impl", Type: graph.NodeClass, Name: "OrderService", FilePath: "api/Services/OrderService.cs"}),
+		cs(&graph.Node{ID: "impl-place", Type: graph.NodeMethod, Name: "PlaceOrder", FilePath: "api/Services/OrderService.cs",
+			Properties: map[string]string{"class": "OrderService", "arity": "0"}}),
+		cs(&graph.Node{ID: "impl-place-qty", Type: graph.NodeMethod, Name: "PlaceOrder", FilePath: "api/Services/OrderService.cs",
+			Properties: map[string]string{"class": "OrderService", "arity": "1-2"}}),
+		cs(&graph.Node{ID: "caller", Type: graph.NodeMethod, Name: "Checkout", FilePath: "api/Controllers/CheckoutController.cs",
+			Properties: map[string]string{"class": "CheckoutController", "member_calls": "IOrderService.PlaceOrder/1,IOrderService.Cancel/0,IClock.Now"}}),
+	)
+
+	linker := NewLinker(store, nil, nil, false)
+	count, err := linker.linkDIRegistrations(ctx)
+	if err != nil {
+		t.Fatalf("linkDIRegistrations: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("linkDIRegistrations returned %d, want 3", count)
+	}
+
+	deps, err := store.GetEdges(ctx, "iface", graph.EdgeDependsOn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 1 || deps[0].TargetID != "impl" || deps[0].Properties["lifetime"] != "scoped" {
+		t.Errorf("interface DependsOn edges = %+v, want one scoped edge to impl", deps)
+	}
+
+	calls, err := store.GetEdges(ctx, "caller", graph.EdgeCalls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, e := range calls {
+		got[e.TargetID] = e.Properties["kind"]
+	}
+	want := map[string]string{"iface-place": "interface", "impl-place-qty": "di_registration"}
+	if len(got) != len(want) {
+		t.Errorf("call edges = %v, want %v", got, want)
+	}
+	for target, kind := range want {
+		if got[target] != kind {
+			t.Errorf("call to %s kind = %q, want %q", target, got[target], kind)
+		}
+	}
+}
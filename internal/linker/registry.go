@@ -0,0 +1,230 @@
+package linker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// PhaseSpec describes a linker phase in the phase registry. Programs
+// embedding the linker add their own phases with RegisterPhase, typically
+// from an init function.
+type PhaseSpec struct {
+	// Name identifies the phase in config and on the command line.
+	Name string
+	// After lists phases that must run before this one when both are
+	// selected. They must already be registered. Phases not ordered by
+	// After, directly or through other phases, may run concurrently, so a
+	// phase reading what another writes must name it here.
+	After []string
+	// Summary is the verbose log line, formatted with the phase's count.
+	Summary string
+	// Run links the graph and returns the number of edges or nodes linked.
+	Run func(l *Linker, ctx context.Context) (int, error)
+}
+
+// phaseRegistry holds registered phases in registration order. Because a
+// phase may only depend on phases registered before it, registration order
+// is always a valid execution order.
+type phaseRegistry struct {
+	mu     sync.RWMutex
+	specs  []PhaseSpec
+	byName map[string]int
+}
+
+// builtinPhases are the linker's own phases in their default order.
+var builtinPhases = []PhaseSpec{
+	{Name: "services", Summary: "Linked %d services", Run: (*Linker).linkServices},
+	{Name: "endpoints", After: []string{"services"}, Summary: "Linked %d endpoints to services", Run: (*Linker).linkEndpoints},
+	{Name: "api_calls", After: []string{"endpoints"}, Summary: "Resolved %d API calls to endpoints", Run: (*Linker).linkAPICalls},
+	{Name: "dependencies", After: []string{"services"}, Summary: "Resolved %d cross-service dependencies", Run: (*Linker).linkDependencies},
+	{Name: "aliases", After: []string{"services"}, Summary: "Resolved %d aliased imports to repository files", Run: (*Linker).linkModuleAliases},
+	{Name: "go_modules", After: []string{"services"}, Summary: "Linked %d internal Go package imports", Run: (*Linker).linkGoModuleImports},
+	{Name: "imports", After: []string{"aliases", "go_modules"}, Summary: "Linked %d imports to manifest dependencies", Run: (*Linker).linkImports},
+	{Name: "go_methods", Summary: "Linked %d Go methods to types declared in other files", Run: (*Linker).linkGoMethods},
+	{Name: "implements", Summary: "Linked %d cross-file implements", Run: (*Linker).linkImplements},
+	{Name: "ts_implements", After: []string{"aliases"}, Summary: "Resolved %d TypeScript implements across modules", Run: (*Linker).linkTSImplements},
+	{Name: "java_hierarchy", After: []string{"implements"}, Summary: "Resolved %d Java implements and extends", Run: (*Linker).linkJavaHierarchy},
+	{Name: "csharp_implements", After: []string{"implements"}, Summary: "Resolved %d C# implements by namespace", Run: (*Linker).linkCSharpImplements},
+	{Name: "unresolved", After: []string{"ts_implements", "java_hierarchy", "csharp_implements"}, Summary: "Bound %d unresolved references", Run: (*Linker).linkUnresolved},
+	{Name: "injection", After: []string{"implements"}, Summary: "Linked %d dependency injection edges", Run: (*Linker).linkInjections},
+	{Name: "tests", Summary: "Linked %d test coverage edges", Run: (*Linker).linkTests},
+	{Name: "calls", Summary: "Linked %d cross-file call edges", Run: (*Linker).linkCalls},
+	{Name: "reexports", After: []string{"aliases"}, Summary: "Linked %d cross-module call edges", Run: (*Linker).linkReexports},
+	{Name: "documents", Summary: "Linked %d document-to-code edges", Run: (*Linker).linkDocuments},
+	{Name: "config", After: []string{"services"}, Summary: "Linked %d environment variable edges", Run: (*Linker).linkConfig},
+	{Name: "jobs", After: []string{"services", "calls"}, Summary: "Linked %d scheduled jobs to their handlers", Run: (*Linker).linkJobs},
+	{Name: "errors", After: []string{"services"}, Summary: "Linked %d functions to the error types they surface", Run: (*Linker).linkErrors},
+	{Name: "pipelines", After: []string{"services"}, Summary: "Linked %d CI pipeline jobs to the services they target", Run: (*Linker).linkPipelines},
+	{Name: "orm_relations", After: []string{"unresolved", "injection"}, Summary: "Linked %d ORM model relations", Run: (*Linker).linkORMRelations},
+	{Name: "external_stores", After: []string{"services"}, Summary: "Linked %d services to the external stores they use", Run: (*Linker).linkExternalStores},
+	{Name: "endpoint_auth", After: []string{"endpoints"}, Summary: "Recorded the auth requirements of %d endpoints", Run: (*Linker).linkEndpointAuth},
+	{Name: "data_classification", After: []string{"services", "dependencies", "calls", "endpoint_auth", "orm_relations"}, Summary: "Tagged %d endpoints exposing sensitive data", Run: (*Linker).linkDataClassification},
+}
+
+var defaultRegistry = newPhaseRegistry(builtinPhases)
+
+func newPhaseRegistry(specs []PhaseSpec) *phaseRegistry {
+	r := &phaseRegistry{byName: make(map[string]int)}
+	for _, spec := range specs {
+		if err := r.register(spec); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// RegisterPhase adds a phase to the registry. It runs after the built-in
+// phases unless config orders it otherwise, and after every phase in
+// spec.After. It panics if the name is empty or taken, Run is nil, or a
+// dependency is not registered.
+func RegisterPhase(spec PhaseSpec) {
+	if err := defaultRegistry.register(spec); err != nil {
+		panic(err)
+	}
+}
+
+// RegisteredPhases returns every registered phase in default execution order.
+func RegisteredPhases() []PhaseSpec {
+	return defaultRegistry.all()
+}
+
+func (r *phaseRegistry) register(spec PhaseSpec) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if spec.Name == "" {
+		return fmt.Errorf("linker: phase name is empty")
+	}
+	if spec.Run == nil {
+		return fmt.Errorf("linker: phase %s has no Run function", spec.Name)
+	}
+	if _, dup := r.byName[spec.Name]; dup {
+		return fmt.Errorf("linker: phase %s registered twice", spec.Name)
+	}
+	for _, dep := range spec.After {
+		if _, ok := r.byName[dep]; !ok {
+			return fmt.Errorf("linker: phase %s depends on unregistered phase %s", spec.Name, dep)
+		}
+	}
+	r.byName[spec.Name] = len(r.specs)
+	r.specs = append(r.specs, spec)
+	return nil
+}
+
+func (r *phaseRegistry) all() []PhaseSpec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]PhaseSpec(nil), r.specs...)
+}
+
+// selectPhases returns the phases to run. With only empty every phase is
+// selected in registration order; otherwise only the named phases, in the
+// given order except where a phase must wait for a selected dependency.
+// Phases named in skip are dropped; a dependency that is not selected is
+// not run.
+func (r *phaseRegistry) selectPhases(only, skip []string) ([]PhaseSpec, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, name := range append(append([]string(nil), only...), skip...) {
+		if _, ok := r.byName[name]; !ok {
+			return nil, fmt.Errorf("unknown linker phase %q", name)
+		}
+	}
+
+	// priority orders phases whose dependencies are satisfied.
+	priority := make(map[string]int)
+	if len(only) == 0 {
+		for i, spec := range r.specs {
+			priority[spec.Name] = i
+		}
+	} else {
+		for i, name := range only {
+			if _, seen := priority[name]; !seen {
+				priority[name] = i
+			}
+		}
+	}
+	for _, name := range skip {
+		delete(priority, name)
+	}
+
+	done := make(map[string]bool, len(priority))
+	selected := make([]PhaseSpec, 0, len(priority))
+	for len(selected) < len(priority) {
+		next := -1
+		for _, spec := range r.specs {
+			p, ok := priority[spec.Name]
+			if !ok || done[spec.Name] || !depsDone(spec, priority, done) {
+				continue
+			}
+			if next < 0 || p < priority[r.specs[next].Name] {
+				next = r.byName[spec.Name]
+			}
+		}
+		// Dependencies always point at earlier registrations, so some
+		// selected phase is always ready.
+		spec := r.specs[next]
+		done[spec.Name] = true
+		selected = append(selected, spec)
+	}
+	return selected, nil
+}
+
+// depsDone reports whether every selected dependency of spec has run.
+func depsDone(spec PhaseSpec, selected map[string]int, done map[string]bool) bool {
+	for _, dep := range spec.After {
+		if _, ok := selected[dep]; ok && !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetPhaseSelection restricts RunAll and SelectedPhases to the phases named
+// in only (all when empty), in that order subject to dependencies, minus
+// those in skip.
+func (l *Linker) SetPhaseSelection(only, skip []string) {
+	l.phaseOnly = only
+	l.phaseSkip = skip
+}
+
+// SelectedPhases returns the phases RunAll will run, bound to this linker.
+// It fails if the selection names an unregistered phase.
+func (l *Linker) SelectedPhases() ([]Phase, error) {
+	specs, err := defaultRegistry.selectPhases(l.phaseOnly, l.phaseSkip)
+	if err != nil {
+		return nil, err
+	}
+	return l.bind(specs), nil
+}
+
+// bind turns registry specs into phases run by l.
+func (l *Linker) bind(specs []PhaseSpec) []Phase {
+	phases := make([]Phase, len(specs))
+	for i, spec := range specs {
+		run := spec.Run
+		phases[i] = Phase{
+			Name:    spec.Name,
+			Summary: spec.Summary,
+			After:   spec.After,
+			Fn:      func(ctx context.Context) (int, error) { return run(l, ctx) },
+		}
+	}
+	return phases
+}
+
+// Store returns the graph store the linker reads and writes, for phases
+// registered outside this package.
+func (l *Linker) Store() graph.Store {
+	return l.store
+}
+
+// Logf logs a message when the linker is verbose.
+func (l *Linker) Logf(format string, args ...any) {
+	if l.verbose {
+		l.log(format, args...)
+	}
+}
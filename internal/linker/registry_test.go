@@ -0,0 +1,102 @@
+package linker
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func testRegistry(t *testing.T) *phaseRegistry {
+	t.Helper()
+	run := func(*Linker, context.Context) (int, error) { return 0, nil }
+	return newPhaseRegistry([]PhaseSpec{
+		{Name: "a", Run: run},
+		{Name: "b", After: []string{"a"}, Run: run},
+		{Name: "c", Run: run},
+		{Name: "d", After: []string{"b", "c"}, Run: run},
+	})
+}
+
+func TestSelectPhases(t *testing.T) {
+	r := testRegistry(t)
+	tests := []struct {
+		name    string
+		only    []string
+		skip    []string
+		want    string
+		wantErr string
+	}{
+		{name: "all", want: "a,b,c,d"},
+		{name: "skip", skip: []string{"b"}, want: "a,c,d"},
+		{name: "subset", only: []string{"c", "a"}, want: "c,a"},
+		{name: "dependency first", only: []string{"d", "b", "a"}, want: "a,b,d"},
+		{name: "unselected dependency", only: []string{"d", "c"}, want: "c,d"},
+		{name: "only and skip", only: []string{"a", "b"}, skip: []string{"a"}, want: "b"},
+		{name: "unknown", only: []string{"a", "nope"}, wantErr: `unknown linker phase "nope"`},
+		{name: "unknown skip", skip: []string{"nope"}, wantErr: `unknown linker phase "nope"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := r.selectPhases(tt.only, tt.skip)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectPhases: %v", err)
+			}
+			var names []string
+			for _, s := range specs {
+				names = append(names, s.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterPhaseErrors(t *testing.T) {
+	run := func(*Linker, context.Context) (int, error) { return 0, nil }
+	tests := []struct {
+		name string
+		spec PhaseSpec
+		want string
+	}{
+		{"duplicate", PhaseSpec{Name: "a", Run: run}, "registered twice"},
+		{"unregistered dependency", PhaseSpec{Name: "e", After: []string{"z"}, Run: run}, "unregistered phase z"},
+		{"no run", PhaseSpec{Name: "e"}, "no Run function"},
+		{"no name", PhaseSpec{Run: run}, "name is empty"},
+	}
+	for _, tt := range tests {
+		r := testRegistry(t)
+		err := r.register(tt.spec)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRunAllPhaseSelection(t *testing.T) {
+	store := newTestStore(t)
+	l := NewLinker(store, nil, nil, false)
+
+	l.SetPhaseSelection([]string{"calls", "services"}, nil)
+	phases, err := l.SelectedPhases()
+	if err != nil {
+		t.Fatalf("SelectedPhases: %v", err)
+	}
+	if len(phases) != 2 || phases[0].Name != "calls" || phases[1].Name != "services" {
+		t.Errorf("SelectedPhases = %v, want [calls services]", phases)
+	}
+	if err := l.RunAll(context.Background()); err != nil {
+		t.Fatalf("RunAll: %v", err)
+	}
+
+	l.SetPhaseSelection(nil, []string{"bogus"})
+	if err := l.RunAll(context.Background()); err == nil {
+		t.Error("RunAll with unknown phase: want error")
+	}
+}
@@ -0,0 +1,153 @@
+package linker
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// proxyRoute is an ingress route from reverse proxy configuration: calls
+// under prefix are forwarded to the upstream with prefix replaced by
+// rewrite.
+type proxyRoute struct {
+	node     *graph.Node
+	prefix   string
+	rewrite  string
+	upstream string
+	// group is the service group serving the upstream, or "" when it could
+	// not be resolved.
+	group string
+}
+
+// routeTable resolves API call paths through reverse proxy routes.
+type routeTable struct {
+	// routes is ordered by descending prefix length so the first match is
+	// the longest.
+	routes []proxyRoute
+	// byGroup indexes endpoints per service group.
+	byGroup map[string]EndpointIndex
+}
+
+// loadRoutes builds the route table from route Config nodes (see
+// parser.ConfigKindRoute). Upstreams resolve to the service group of a
+// docker-compose build context, or to the group named like the upstream
+// host, ignoring a "-svc" or "-service" suffix. byGroup indexes endpoints
+// per service group.
+func (l *Linker) loadRoutes(ctx context.Context, byGroup map[string]EndpointIndex) (*routeTable, error) {
+	nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeConfig,
+		Properties: map[string]string{"kind": parser.ConfigKindRoute},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	table := &routeTable{byGroup: byGroup}
+	for _, n := range nodes {
+		r := proxyRoute{
+			node:     n,
+			prefix:   normalizeURLPath(n.Properties[parser.RoutePropPrefix]),
+			rewrite:  normalizeURLPath(n.Properties[parser.RoutePropRewrite]),
+			upstream: n.Properties[parser.RoutePropUpstream],
+		}
+		if ctxDir := n.Properties["build_context"]; ctxDir != "" {
+			r.group = l.group(ctxDir + "/")
+		}
+		if _, ok := table.byGroup[r.group]; !ok {
+			r.group = ""
+			for _, name := range upstreamGroupNames(r.upstream) {
+				if _, ok := table.byGroup[name]; ok {
+					r.group = name
+					break
+				}
+			}
+		}
+		table.routes = append(table.routes, r)
+	}
+	sort.SliceStable(table.routes, func(i, j int) bool {
+		return len(table.routes[i].prefix) > len(table.routes[j].prefix)
+	})
+	return table, nil
+}
+
+// endpointsByGroup indexes endpoints per service group.
+func (l *Linker) endpointsByGroup(endpoints []*graph.Node) map[string]EndpointIndex {
+	grouped := make(map[string][]*graph.Node)
+	for _, ep := range endpoints {
+		if g := l.group(ep.FilePath); g != "" {
+			grouped[g] = append(grouped[g], ep)
+		}
+	}
+	byGroup := make(map[string]EndpointIndex, len(grouped))
+	for g, eps := range grouped {
+		byGroup[g] = NewEndpointIndex(eps)
+	}
+	return byGroup
+}
+
+// upstreamGroupNames returns the service group names an upstream host may
+// stand for.
+func upstreamGroupNames(host string) []string {
+	names := []string{host}
+	if label, _, ok := strings.Cut(host, "."); ok {
+		names = append(names, label)
+	}
+	for _, suffix := range []string{"-svc", "-service"} {
+		if trimmed := strings.TrimSuffix(names[len(names)-1], suffix); trimmed != names[len(names)-1] {
+			names = append(names, trimmed)
+		}
+	}
+	return names
+}
+
+// match resolves a normalized call path through the matching routes,
+// longest prefix first, and returns the first endpoint reached on a route's
+// upstream, the match score, and the route. Routes from several proxies
+// may share a prefix, so a route whose rewritten path matches nothing does
+// not end the search. It returns a nil endpoint when no route leads to an
+// endpoint.
+func (t *routeTable) match(callPath string, all EndpointIndex) (*graph.Node, float64, *proxyRoute) {
+	if t == nil {
+		return nil, 0, nil
+	}
+	for i := range t.routes {
+		r := &t.routes[i]
+		rest, ok := routeRemainder(callPath, r.prefix)
+		if !ok {
+			continue
+		}
+		index := all
+		if r.group != "" {
+			index = t.byGroup[r.group]
+		}
+		target := strings.TrimRight(r.rewrite, "/") + rest
+		if target == "" {
+			target = "/"
+		}
+		if ep, score := matchEndpoint(target, index); ep != nil {
+			return ep, score, r
+		}
+	}
+	return nil, 0, nil
+}
+
+// routeRemainder returns the part of path after prefix when prefix matches
+// whole path segments.
+func routeRemainder(path, prefix string) (string, bool) {
+	if prefix == "/" {
+		return path, true
+	}
+	if path == prefix {
+		return "", true
+	}
+	if strings.HasPrefix(path, prefix+"/") {
+		return path[len(prefix):], true
+	}
+	return "", false
+}
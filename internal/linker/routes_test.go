@@ -0,0 +1,104 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkAPICallsThroughProxyRoute(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	backendEp := graph.NewNodeID("APIEndpoint", "backend/routes.go", "GET /users/{id}")
+	adminEp := graph.NewNodeID("APIEndpoint", "admin/routes.go", "GET /users/{id}")
+	ordersEp := graph.NewNodeID("APIEndpoint", "orders/app.py", "POST /v2/orders")
+	userCall := graph.NewNodeID("Dependency", "frontend/src/api.ts", "fetch /api/users/:id")
+	orderCall := graph.NewNodeID("Dependency", "frontend/src/api.ts", "fetch /shop/orders")
+
+	endpoint := func(id, file, path string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeAPIEndpoint, Name: path, FilePath: file,
+			Properties: map[string]string{"path": path}}
+	}
+	call := func(id, path string) *graph.Node {
+		return &graph.Node{ID: id, Type: graph.NodeDependency, Name: path, FilePath: "frontend/src/api.ts",
+			Properties: map[string]string{"kind": "api_call", "path": path}}
+	}
+	route := func(file, prefix, rewrite, upstream, buildContext string) *graph.Node {
+		props := map[string]string{
+			"kind":                   parser.ConfigKindRoute,
+			parser.RoutePropPrefix:   prefix,
+			parser.RoutePropRewrite:  rewrite,
+			parser.RoutePropUpstream: upstream,
+		}
+		if buildContext != "" {
+			props["build_context"] = buildContext
+		}
+		return &graph.Node{ID: graph.NewNodeID("Config", file, "route:"+prefix), Type: graph.NodeConfig,
+			Name: prefix, FilePath: file, Properties: props}
+	}
+
+	addNodes(t, store,
+		endpoint(backendEp, "backend/routes.go", "/users/{id}"),
+		endpoint(adminEp, "admin/routes.go", "/users/{id}"),
+		endpoint(ordersEp, "orders/app.py", "/v2/orders"),
+		call(userCall, "/api/users/:id"),
+		call(orderCall, "/shop/orders"),
+		route("frontend/nginx.conf", "/api", "/", "backend-svc", ""),
+		route("docker-compose.yml", "/shop/orders", "/v2/orders", "shop", "orders"),
+	)
+
+	count, err := NewLinker(store, nil, nil, false).linkAPICalls(ctx)
+	if err != nil {
+		t.Fatalf("linkAPICalls: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linkAPICalls returned %d, want 2", count)
+	}
+
+	tests := []struct {
+		call, endpoint, route string
+	}{
+		{userCall, backendEp, "/api"},
+		{orderCall, ordersEp, "/shop/orders"},
+	}
+	for _, tt := range tests {
+		edges, err := store.GetEdges(ctx, tt.call, graph.EdgeConsumes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(edges) != 1 {
+			t.Fatalf("call %s: got %d Consumes edges, want 1", tt.call, len(edges))
+		}
+		if edges[0].TargetID != tt.endpoint {
+			t.Errorf("call %s consumes %s, want %s", tt.call, edges[0].TargetID, tt.endpoint)
+		}
+		if got := edges[0].Properties["route"]; got != tt.route {
+			t.Errorf("call %s route = %q, want %q", tt.call, got, tt.route)
+		}
+		if edges[0].Properties["confidence"] != string(graph.ConfidenceExact) {
+			t.Errorf("call %s confidence = %q, want exact", tt.call, edges[0].Properties["confidence"])
+		}
+	}
+}
+
+func TestRouteRemainder(t *testing.T) {
+	tests := []struct {
+		path, prefix string
+		want         string
+		ok           bool
+	}{
+		{"/api/users", "/api", "/users", true},
+		{"/api", "/api", "", true},
+		{"/apiary/x", "/api", "", false},
+		{"/users", "/", "/users", true},
+	}
+	for _, tt := range tests {
+		got, ok := routeRemainder(tt.path, tt.prefix)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("routeRemainder(%q, %q) = %q, %v; want %q, %v", tt.path, tt.prefix, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -2,41 +2,148 @@ package linker
 
 import (
 	"context"
+	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
 )
 
-// linkServices ensures each top-level directory group has a NodeService node
-// and creates EdgeContains edges from services to their file nodes.
-func (l *Linker) linkServices(ctx context.Context) (int, error) {
-	// Query all nodes and group by top-level directory.
-	allNodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{})
-	if err != nil {
-		return 0, err
+// ServiceDefinition declares a service boundary: files under any of the
+// root directory globs belong to the service.
+type ServiceDefinition struct {
+	Name  string
+	Roots []string
+	Kind  string
+	// Hosts lists hostnames or base URLs (e.g. "payments.internal",
+	// "https://api.example.com/payments") the service is reachable at, so
+	// absolute and environment-based API call URLs resolve to it.
+	Hosts []string
+}
+
+// ServiceMap assigns file paths to service groups. Declared services take
+// precedence; other files fall back to their top-level directory unless
+// onlyDeclared is set. A nil ServiceMap uses top-level directories only.
+type ServiceMap struct {
+	defs         []ServiceDefinition
+	onlyDeclared bool
+}
+
+// NewServiceMap creates a ServiceMap from declared services.
+func NewServiceMap(defs []ServiceDefinition, onlyDeclared bool) *ServiceMap {
+	return &ServiceMap{defs: defs, onlyDeclared: onlyDeclared}
+}
+
+// Group returns the service group of a file path: the name of the declared
+// service whose root matches the deepest directory of the path, else the
+// top-level directory. It returns "" for paths that belong to no service.
+func (m *ServiceMap) Group(filePath string) string {
+	if def := m.Declared(filePath); def != nil {
+		return def.Name
+	}
+	if m != nil && m.onlyDeclared {
+		return ""
+	}
+	return topDir(filePath)
+}
+
+// Declared returns the declared service containing the file path, or nil.
+func (m *ServiceMap) Declared(filePath string) *ServiceDefinition {
+	if m == nil || filePath == "" {
+		return nil
+	}
+	dir := path.Dir(filepath.ToSlash(filePath))
+	if dir == "." {
+		return nil
 	}
+	parts := strings.Split(dir, "/")
 
-	// Build service index: which top-level groups already have a NodeService?
-	existingServices := make(map[string]*graph.Node)
-	for _, n := range allNodes {
-		if n.Type == graph.NodeService {
-			group := topDir(n.FilePath)
-			existingServices[group] = n
+	var best *ServiceDefinition
+	bestDepth := 0
+	for i := range m.defs {
+		for _, root := range m.defs[i].Roots {
+			glob := strings.Split(strings.Trim(path.Clean(filepath.ToSlash(root)), "/"), "/")
+			for depth := len(parts); depth > bestDepth; depth-- {
+				if matchDirSegments(glob, parts[:depth]) {
+					best, bestDepth = &m.defs[i], depth
+					break
+				}
+			}
 		}
 	}
+	return best
+}
 
-	// Group file nodes by top-level directory.
-	fileGroups := make(map[string][]*graph.Node)
-	for _, n := range allNodes {
-		if n.Type != graph.NodeFile {
+// SetServiceMap configures declared service boundaries. Without one, each
+// top-level directory is a service.
+func (l *Linker) SetServiceMap(m *ServiceMap) {
+	l.services = m
+}
+
+// group returns the service group of a file path.
+func (l *Linker) group(filePath string) string {
+	return l.services.Group(filePath)
+}
+
+// serviceGroup returns the group a Service node stands for. Manifest services
+// are grouped by their file path; declared and auto-detected services have
+// no file path and are grouped by name.
+func (l *Linker) serviceGroup(svc *graph.Node) string {
+	if svc.FilePath == "" {
+		return svc.Name
+	}
+	return l.group(svc.FilePath)
+}
+
+// servicesByGroup indexes Service nodes by group. A declared service wins
+// over manifest services inside its roots.
+func (l *Linker) servicesByGroup(services []*graph.Node) map[string]*graph.Node {
+	byGroup := make(map[string]*graph.Node)
+	for _, svc := range services {
+		group := l.serviceGroup(svc)
+		if group == "" {
 			continue
 		}
-		group := topDir(n.FilePath)
-		if group == "" {
+		if cur, ok := byGroup[group]; ok && cur.Properties["source"] == "config" {
 			continue
 		}
-		fileGroups[group] = append(fileGroups[group], n)
+		byGroup[group] = svc
+	}
+	return byGroup
+}
+
+// linkServices ensures each service group has a NodeService node and creates
+// EdgeContains edges from services to their file nodes. Declared services get
+// a node (source=config) even when a manifest service lives in their roots.
+func (l *Linker) linkServices(ctx context.Context) (int, error) {
+	services, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return 0, err
+	}
+	if l.services != nil {
+		for _, def := range l.services.defs {
+			svc, err := l.ensureDeclaredService(ctx, def)
+			if err != nil {
+				return 0, err
+			}
+			services = append(services, svc)
+		}
+	}
+
+	// Build service index: which groups already have a NodeService?
+	existingServices := l.servicesByGroup(services)
+
+	// Group file nodes by service.
+	fileGroups := make(map[string][]*graph.Node)
+	err = l.store.ScanNodes(ctx, graph.NodeFilter{Type: graph.NodeFile}, func(n *graph.Node) error {
+		if group := l.group(n.FilePath); group != "" {
+			fileGroups[group] = append(fileGroups[group], n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	linked := 0
@@ -53,6 +160,9 @@ func (l *Linker) linkServices(ctx context.Context) (int, error) {
 					"kind": "auto_detected",
 				},
 			}
+			if repo := files[0].Properties["repo"]; repo != "" {
+				svc.Properties["repo"] = repo
+			}
 			if err := l.store.AddNode(ctx, svc); err != nil {
 				if l.verbose {
 					l.log("  Warning: add auto-detected service %s: %v", group, err)
@@ -64,10 +174,11 @@ func (l *Linker) linkServices(ctx context.Context) (int, error) {
 		// Create EdgeContains from service → each file.
 		for _, fileNode := range files {
 			edge := &graph.Edge{
-				ID:       graph.NewNodeID(string(graph.EdgeContains), svc.ID, fileNode.ID),
-				Type:     graph.EdgeContains,
-				SourceID: svc.ID,
-				TargetID: fileNode.ID,
+				ID:         graph.NewEdgeID(graph.EdgeContains, svc.ID, fileNode.ID),
+				Type:       graph.EdgeContains,
+				SourceID:   svc.ID,
+				TargetID:   fileNode.ID,
+				Properties: withConfidence(nil, graph.ConfidenceExact, scoreExact),
 			}
 			if err := l.store.AddEdge(ctx, edge); err != nil {
 				// Ignore duplicate edge errors.
@@ -80,6 +191,35 @@ func (l *Linker) linkServices(ctx context.Context) (int, error) {
 	return linked, nil
 }
 
+// ensureDeclaredService adds or updates the Service node of a declared
+// service.
+func (l *Linker) ensureDeclaredService(ctx context.Context, def ServiceDefinition) (*graph.Node, error) {
+	kind := def.Kind
+	if kind == "" {
+		kind = "service"
+	}
+	svc := &graph.Node{
+		ID:   graph.NewNodeID(string(graph.NodeService), "config", def.Name),
+		Type: graph.NodeService,
+		Name: def.Name,
+		Properties: map[string]string{
+			"kind":   kind,
+			"source": "config",
+			"roots":  strings.Join(def.Roots, ","),
+		},
+	}
+	if existing, err := l.store.GetNode(ctx, svc.ID); err == nil && existing != nil {
+		if err := l.store.UpdateNode(ctx, svc); err != nil {
+			return nil, fmt.Errorf("update declared service %s: %w", def.Name, err)
+		}
+		return svc, nil
+	}
+	if err := l.store.AddNode(ctx, svc); err != nil {
+		return nil, fmt.Errorf("add declared service %s: %w", def.Name, err)
+	}
+	return svc, nil
+}
+
 // topDir extracts the top-level directory from a file path.
 // For "hypatia/src/main.py" it returns "hypatia".
 // For "main.py" (root level) it returns "(root)".
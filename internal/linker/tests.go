@@ -92,13 +92,13 @@ func (l *Linker) linkTestFiles(ctx context.Context) (int, error) {
 			}
 
 			edge := &graph.Edge{
-				ID:       graph.NewNodeID(string(graph.EdgeTests), tf.ID, target.ID),
+				ID:       graph.NewEdgeID(graph.EdgeTests, tf.ID, target.ID),
 				Type:     graph.EdgeTests,
 				SourceID: tf.ID,
 				TargetID: target.ID,
-				Properties: map[string]string{
+				Properties: withConfidence(map[string]string{
 					"kind": "file_coverage",
-				},
+				}, graph.ConfidenceHeuristic, scoreStrong),
 			}
 			if err := l.store.AddEdge(ctx, edge); err != nil {
 				continue
@@ -245,13 +245,13 @@ func (l *Linker) linkTestFunctions(ctx context.Context) (int, error) {
 		}
 
 		edge := &graph.Edge{
-			ID:       graph.NewNodeID(string(graph.EdgeTests), tf.ID, target.ID),
+			ID:       graph.NewEdgeID(graph.EdgeTests, tf.ID, target.ID),
 			Type:     graph.EdgeTests,
 			SourceID: tf.ID,
 			TargetID: target.ID,
-			Properties: map[string]string{
+			Properties: withConfidence(map[string]string{
 				"kind": "function_coverage",
-			},
+			}, graph.ConfidenceHeuristic, scoreMedium),
 		}
 		if err := l.store.AddEdge(ctx, edge); err != nil {
 			continue
@@ -481,6 +481,15 @@ func deriveSourceFilePaths(testPath, language string) []string {
 	return candidates
 }
 
+// TestedNames returns the names of the source functions a test function is
+// assumed to exercise, by the naming conventions used for Tests edges.
+func TestedNames(testName, language, filePath string) []string {
+	if language == "" {
+		language = inferLanguageFromPath(filePath)
+	}
+	return deriveSourceFuncNames(testName, language)
+}
+
 // deriveSourceFuncNames generates candidate source function names from a test function name.
 func deriveSourceFuncNames(testName, language string) []string {
 	var candidates []string
@@ -0,0 +1,114 @@
+package linker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// linkTSImplements resolves the interfaces TypeScript classes implement.
+// The parser links an imported interface to the import's Dependency node,
+// recording the local name ("interface") and the name the module exports it
+// under ("export"). This phase resolves the import for the class's file and
+// follows the module's re-export chain to the declaring interface, creating
+// an Implements edge (kind=cross_module, "via" listing the re-exporting
+// files). An interface imported from an external package gets a placeholder
+// Interface node (external=true) shared by every implementer. Interfaces
+// neither declared in the class's file nor imported (ambient declarations)
+// are Unresolved references, bound by linkUnresolved.
+func (l *Linker) linkTSImplements(ctx context.Context) (int, error) {
+	idx, err := l.tsModuleIndex(ctx, graph.NodeInterface)
+	if err != nil {
+		return 0, err
+	}
+	imports, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Language:   "typescript",
+		Properties: map[string]string{"kind": "import"},
+	})
+	if err != nil {
+		return 0, err
+	}
+	aliased, err := l.aliasedImports(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, imp := range imports {
+		edges, err := l.store.GetEdges(ctx, imp.ID, graph.EdgeImplements)
+		if err != nil {
+			return linked, err
+		}
+		for _, e := range edges {
+			export := e.Properties["export"]
+			if e.TargetID != imp.ID || export == "" {
+				continue
+			}
+			var target *graph.Node
+			props := map[string]string{"kind": "cross_module", "interface": e.Properties["interface"]}
+			if file := l.resolveImportFor(ctx, idx, aliased, imp, e.SourceID); file != "" {
+				var via []string
+				if target, via = idx.findExport(file, export, make(map[string]bool)); target == nil {
+					continue
+				}
+				if len(via) > 0 {
+					props["via"] = strings.Join(via, ",")
+				}
+			} else if imp.FilePath == "" {
+				if target, err = l.externalInterface(ctx, imp, e); err != nil {
+					return linked, err
+				}
+				props["kind"] = "external"
+			} else {
+				continue
+			}
+
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(graph.EdgeImplements, e.SourceID, target.ID),
+				Type:       graph.EdgeImplements,
+				SourceID:   e.SourceID,
+				TargetID:   target.ID,
+				Properties: withConfidence(props, graph.ConfidenceExact, scoreExact),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+
+			if l.verbose {
+				l.log("    TS implements: %s -> %s (%s)", e.Properties["interface"], target.QualifiedName, props["kind"])
+			}
+		}
+	}
+	return linked, nil
+}
+
+// externalInterface returns the placeholder node of the interface the
+// Implements edge e names from the external package imported by imp,
+// creating it if needed.
+func (l *Linker) externalInterface(ctx context.Context, imp *graph.Node, e *graph.Edge) (*graph.Node, error) {
+	name := e.Properties["export"]
+	if name == "default" {
+		name = e.Properties["interface"]
+	}
+	id := graph.NewExternalTypeID("typescript", imp.Name, name)
+	if n, err := l.store.GetNode(ctx, id); err == nil && n != nil {
+		return n, nil
+	}
+	n := &graph.Node{
+		ID:            id,
+		Type:          graph.NodeInterface,
+		Name:          name,
+		QualifiedName: imp.Name + "." + name,
+		Package:       imp.Name,
+		Language:      "typescript",
+		Exported:      true,
+		Properties:    map[string]string{"external": "true"},
+	}
+	if err := l.store.AddNode(ctx, n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
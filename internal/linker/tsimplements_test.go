@@ -0,0 +1,83 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestLinkTSImplements(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	ts := func(n *graph.Node) *graph.Node { n.Language = "typescript"; return n }
+	module := func(file, exports string) *graph.Node {
+		n := ts(&graph.Node{ID: "mod-" + file, Type: graph.NodeModule, Name: file, FilePath: file})
+		if exports != "" {
+			n.Properties = map[string]string{"exports": exports}
+		}
+		return n
+	}
+	nest := graph.NewDependencyID("typescript", "@nestjs/common")
+	addNodes(t, store,
+		module("src/app.ts", ""),
+		module("src/contracts/index.ts", "*=*@./repository"),
+		module("src/contracts/repository.ts", ""),
+		ts(&graph.Node{ID: "repo", Type: graph.NodeInterface, Name: "Repository", QualifiedName: "src/contracts/repository.ts.Repository", FilePath: "src/contracts/repository.ts", Exported: true}),
+		ts(&graph.Node{ID: "dep-contracts", Type: graph.NodeDependency, Name: "./contracts", FilePath: "src/app.ts", Properties: map[string]string{"kind": "import"}}),
+		ts(&graph.Node{ID: nest, Type: graph.NodeDependency, Name: "@nestjs/common", Properties: map[string]string{"kind": "import"}}),
+		ts(&graph.Node{ID: "app", Type: graph.NodeClass, Name: "AppService", FilePath: "src/app.ts",
+			Properties: map[string]string{"implements": "Repo,OnModuleInit"}}),
+	)
+	for _, e := range []*graph.Edge{
+		{ID: "i1", Type: graph.EdgeImplements, SourceID: "app", TargetID: "dep-contracts", Properties: map[string]string{"interface": "Repo", "export": "Repository"}},
+		{ID: "i2", Type: graph.EdgeImplements, SourceID: "app", TargetID: nest, Properties: map[string]string{"interface": "OnModuleInit", "export": "OnModuleInit"}},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkTSImplements(ctx)
+	if err != nil {
+		t.Fatalf("linkTSImplements: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("linkTSImplements returned %d, want 2", count)
+	}
+
+	external := graph.NewExternalTypeID("typescript", "@nestjs/common", "OnModuleInit")
+	ext, err := store.GetNode(ctx, external)
+	if err != nil || ext == nil {
+		t.Fatalf("external interface node missing: %v", err)
+	}
+	if ext.Type != graph.NodeInterface || ext.Name != "OnModuleInit" || ext.Properties["external"] != "true" {
+		t.Errorf("external node = %+v", ext)
+	}
+
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeImplements, SourceID: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]map[string]string)
+	for _, e := range edges {
+		got[e.TargetID] = e.Properties
+	}
+	tests := []struct {
+		target, kind, via string
+	}{
+		{"repo", "cross_module", "src/contracts/index.ts"},
+		{external, "external", ""},
+	}
+	for _, tt := range tests {
+		props, ok := got[tt.target]
+		if !ok {
+			t.Errorf("missing Implements edge to %s", tt.target)
+			continue
+		}
+		if props["kind"] != tt.kind || props["via"] != tt.via {
+			t.Errorf("edge to %s: kind=%q via=%q, want %q %q", tt.target, props["kind"], props["via"], tt.kind, tt.via)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package linker
+
+import (
+	"context"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// qualifiedLanguages are the languages whose phases resolve references by
+// qualified name (linkJavaHierarchy, linkCSharpImplements): what they leave
+// unresolved is declared outside the graph, not somewhere to match by name.
+var qualifiedLanguages = map[string]bool{"java": true, "csharp": true}
+
+// linkUnresolved binds the Unresolved nodes parsers emit for references
+// they can name but not locate (see parser.Unresolved). Language phases
+// running before it bind the references they can resolve precisely and
+// remove the edges to their Unresolved nodes; a node left without edges is
+// deleted. The references of other languages are matched by name to a node
+// of the target type in the same language, preferring the referrer's
+// service: each edge to the Unresolved node is re-created to the match
+// (kind=nominal) and the Unresolved node deleted. The rest stay in the graph
+// as Unresolved nodes ('codeeagle query unresolved' lists them). It returns
+// the number of edges bound.
+func (l *Linker) linkUnresolved(ctx context.Context) (int, error) {
+	refs, err := l.store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeUnresolved})
+	if err != nil {
+		return 0, err
+	}
+
+	byName := make(map[string]map[string][]*graph.Node) // language + "\x00" + type -> name -> nodes
+	candidatesOf := func(ref *graph.Node) ([]*graph.Node, error) {
+		key := ref.Language + "\x00" + ref.Properties["target_type"]
+		if byName[key] == nil {
+			nodes, err := l.store.QueryNodes(ctx, graph.NodeFilter{
+				Type:     graph.NodeType(ref.Properties["target_type"]),
+				Language: ref.Language,
+			})
+			if err != nil {
+				return nil, err
+			}
+			byName[key] = make(map[string][]*graph.Node)
+			for _, n := range nodes {
+				// Placeholders of external types have no file to bind to.
+				if n.FilePath != "" {
+					byName[key][n.Name] = append(byName[key][n.Name], n)
+				}
+			}
+		}
+		return byName[key][ref.Name], nil
+	}
+
+	linked, remaining := 0, 0
+	for _, ref := range refs {
+		edges, err := l.store.GetIncomingEdges(ctx, ref.ID, "")
+		if err != nil {
+			return linked, err
+		}
+		if len(edges) == 0 {
+			if err := l.store.DeleteNode(ctx, ref.ID); err != nil {
+				return linked, err
+			}
+			continue
+		}
+		if qualifiedLanguages[ref.Language] || ref.Properties["target_type"] == "" {
+			remaining++
+			continue
+		}
+		candidates, err := candidatesOf(ref)
+		if err != nil {
+			return linked, err
+		}
+		target := l.bestMatch(ref, candidates)
+		if target == nil {
+			remaining++
+			continue
+		}
+
+		level, score := nameMatchConfidence(len(candidates))
+		for _, e := range edges {
+			if e.SourceID == target.ID {
+				continue
+			}
+			props := map[string]string{"kind": "nominal"}
+			for k, v := range e.Properties {
+				if k != graph.PropGraphSource {
+					props[k] = v
+				}
+			}
+			edge := &graph.Edge{
+				ID:         graph.NewEdgeID(e.Type, e.SourceID, target.ID),
+				Type:       e.Type,
+				SourceID:   e.SourceID,
+				TargetID:   target.ID,
+				Properties: withConfidence(props, level, score),
+			}
+			if err := l.store.AddEdge(ctx, edge); err != nil {
+				continue
+			}
+			linked++
+		}
+		if err := l.store.DeleteNode(ctx, ref.ID); err != nil {
+			return linked, err
+		}
+
+		if l.verbose {
+			l.log("    Bound %s %s (%s) -> %s", ref.Properties["target_type"], ref.Name, ref.FilePath, target.FilePath)
+		}
+	}
+
+	if l.verbose && remaining > 0 {
+		l.log("  %d references remain unresolved", remaining)
+	}
+	return linked, nil
+}
+
+// unbind removes the edge of edgeType from sourceID to the Unresolved node
+// of ref, once a language phase has resolved the reference. The edge is
+// missing when the parser bound the reference within its file.
+func (l *Linker) unbind(ctx context.Context, edgeType graph.EdgeType, sourceID string, ref parser.Unresolved) {
+	edges, err := l.store.QueryEdges(ctx, graph.EdgeFilter{Type: edgeType, SourceID: sourceID, TargetID: ref.ID()})
+	if err != nil {
+		return
+	}
+	for _, e := range edges {
+		if err := l.store.DeleteEdge(ctx, e.ID); err != nil && l.verbose {
+			l.log("  Warning: unbind %s of %s: %v", ref.Name, sourceID, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package linker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+func TestLinkUnresolved(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	addNodes(t, store,
+		&graph.Node{ID: "iface", Type: graph.NodeInterface, Name: "Auditable", Language: "typescript", FilePath: "src/types.d.ts"},
+		&graph.Node{ID: "external", Type: graph.NodeInterface, Name: "OnModuleInit", Language: "typescript", Properties: map[string]string{"external": "true"}},
+		&graph.Node{ID: "ts-cls", Type: graph.NodeClass, Name: "Order", Language: "typescript", FilePath: "src/order.ts"},
+		&graph.Node{ID: "java-cls", Type: graph.NodeClass, Name: "OrderService", Language: "java", FilePath: "src/OrderService.java"},
+		&graph.Node{ID: "java-iface", Type: graph.NodeInterface, Name: "Serializable", Language: "java", FilePath: "src/Serializable.java"},
+	)
+	refs := []struct {
+		ref    parser.Unresolved
+		source string
+	}{
+		{parser.Unresolved{Language: parser.LangTypeScript, TargetType: graph.NodeInterface, Name: "Auditable", FilePath: "src/order.ts"}, "ts-cls"},
+		{parser.Unresolved{Language: parser.LangTypeScript, TargetType: graph.NodeInterface, Name: "OnModuleInit", FilePath: "src/order.ts"}, "ts-cls"},
+		{parser.Unresolved{Language: parser.LangJava, TargetType: graph.NodeInterface, Name: "Serializable", FilePath: "src/OrderService.java"}, "java-cls"},
+		{parser.Unresolved{Language: parser.LangJava, TargetType: graph.NodeInterface, Name: "Resolved", FilePath: "src/OrderService.java"}, ""},
+	}
+	for _, r := range refs {
+		node, edge := r.ref.Link(graph.EdgeImplements, r.source)
+		addNodes(t, store, node)
+		if r.source == "" {
+			continue
+		}
+		if err := store.AddEdge(ctx, edge); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := NewLinker(store, nil, nil, false).linkUnresolved(ctx)
+	if err != nil {
+		t.Fatalf("linkUnresolved: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("linkUnresolved returned %d, want 1", count)
+	}
+
+	edges, err := store.QueryEdges(ctx, graph.EdgeFilter{Type: graph.EdgeImplements, SourceID: "ts-cls", TargetID: "iface"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 || edges[0].Properties["kind"] != "nominal" {
+		t.Errorf("edges to Auditable = %v, want one with kind=nominal", edges)
+	}
+
+	tests := []struct {
+		ref  parser.Unresolved
+		kept bool
+	}{
+		{refs[0].ref, false}, // bound
+		{refs[1].ref, true},  // only an external placeholder matches
+		{refs[2].ref, true},  // Java is resolved by qualified name
+		{refs[3].ref, false}, // no edges left
+	}
+	for _, tt := range tests {
+		n, err := store.GetNode(ctx, tt.ref.ID())
+		if kept := err == nil && n != nil; kept != tt.kept {
+			t.Errorf("Unresolved %s kept = %v, want %v", tt.ref.Name, kept, tt.kept)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+const defaultAzureOpenAIAPIVersion = "2024-10-21"
+
+func init() {
+	llm.RegisterProvider("azure-openai", newAzureOpenAIClient)
+}
+
+// azureOpenAIClient implements llm.Client, llm.ToolCapableClient,
+// llm.StreamingClient and llm.StructuredClient using the Azure OpenAI chat
+// completions API. The model is the name of the deployment in the Azure
+// resource.
+type azureOpenAIClient struct {
+	endpoint   string
+	deployment string
+	apiVersion string
+	// apiKey is sent in the api-key header; when empty, token is sent as
+	// a Microsoft Entra ID bearer token instead.
+	apiKey string
+	token  string
+	client *http.Client
+}
+
+// newAzureOpenAIClient creates a new Azure OpenAI client. The endpoint and
+// credentials fall back to the AZURE_OPENAI_ENDPOINT, AZURE_OPENAI_API_KEY
+// and AZURE_OPENAI_AD_TOKEN environment variables.
+func newAzureOpenAIClient(cfg llm.Config) (llm.Client, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model (deployment name) is required for Azure OpenAI provider")
+	}
+
+	endpoint := cfg.BaseURL
+	if endpoint == "" {
+		endpoint = os.Getenv("AZURE_OPENAI_ENDPOINT")
+	}
+	if endpoint == "" {
+		return nil, fmt.Errorf("base URL (resource endpoint) is required for Azure OpenAI provider")
+	}
+
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("AZURE_OPENAI_API_KEY")
+	}
+	token := ""
+	if apiKey == "" {
+		token = os.Getenv("AZURE_OPENAI_AD_TOKEN")
+	}
+	if apiKey == "" && token == "" {
+		return nil, fmt.Errorf("API key or AZURE_OPENAI_AD_TOKEN is required for Azure OpenAI provider")
+	}
+
+	apiVersion := cfg.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAzureOpenAIAPIVersion
+	}
+
+	return &azureOpenAIClient{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		deployment: cfg.Model,
+		apiVersion: apiVersion,
+		apiKey:     apiKey,
+		token:      token,
+		client:     &http.Client{},
+	}, nil
+}
+
+// --- Wire format types ---
+
+// azureChatRequest is the request body for the chat completions endpoint.
+type azureChatRequest struct {
+	Messages       []azureMessage       `json:"messages"`
+	MaxTokens      int                  `json:"max_tokens,omitempty"`
+	Tools          []azureToolDef       `json:"tools,omitempty"`
+	Stream         bool                 `json:"stream,omitempty"`
+	StreamOptions  *azureStreamOptions  `json:"stream_options,omitempty"`
+	ResponseFormat *azureResponseFormat `json:"response_format,omitempty"`
+}
+
+// azureResponseFormat constrains the reply to a JSON schema.
+type azureResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema azureJSONSchema `json:"json_schema"`
+}
+
+// azureJSONSchema names the schema of a structured reply.
+type azureJSONSchema struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema"`
+}
+
+// azureStreamOptions asks for token usage in the last streamed chunk.
+type azureStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// azureMessage is a message in the chat completions format.
+type azureMessage struct {
+	Role       string          `json:"role"`
+	Content    string          `json:"content"`
+	ToolCalls  []azureToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+// azureToolCall is a function call requested by the model.
+type azureToolCall struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Function azureFunctionCall `json:"function"`
+}
+
+// azureFunctionCall holds the function name and its JSON-encoded arguments.
+type azureFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// azureToolDef describes a tool available to the model.
+type azureToolDef struct {
+	Type     string            `json:"type"`
+	Function azureFunctionDecl `json:"function"`
+}
+
+// azureFunctionDecl describes a function in a tool definition.
+type azureFunctionDecl struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// azureChatResponse is the response from the chat completions endpoint. It
+// is also the shape of each streamed chunk, with delta in place of message.
+type azureChatResponse struct {
+	Choices []struct {
+		Message      azureMessage `json:"message"`
+		Delta        azureMessage `json:"delta"`
+		FinishReason string       `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *azureUsage `json:"usage"`
+}
+
+// azureUsage contains token usage from the response.
+type azureUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// azureErrorResponse is the error response from the API.
+type azureErrorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Chat sends a system prompt and messages to the chat completions endpoint.
+func (c *azureOpenAIClient) Chat(ctx context.Context, systemPrompt string, messages []llm.Message) (*llm.Response, error) {
+	return c.doChat(ctx, azureChatRequest{
+		Messages:  convertToAzureMessages(systemPrompt, messages),
+		MaxTokens: defaultMaxTokens,
+	})
+}
+
+// ChatWithTools sends messages with tool definitions to the chat completions endpoint.
+func (c *azureOpenAIClient) ChatWithTools(ctx context.Context, systemPrompt string, messages []llm.Message, tools []llm.Tool) (*llm.Response, error) {
+	return c.doChat(ctx, azureChatRequest{
+		Messages:  convertToAzureMessages(systemPrompt, messages),
+		MaxTokens: defaultMaxTokens,
+		Tools:     convertToAzureTools(tools),
+	})
+}
+
+// ChatJSON sends messages to the chat completions endpoint with the reply
+// constrained to the schema.
+func (c *azureOpenAIClient) ChatJSON(ctx context.Context, systemPrompt string, messages []llm.Message, schema llm.Schema) (*llm.Response, error) {
+	return c.doChat(ctx, azureChatRequest{
+		Messages:  convertToAzureMessages(systemPrompt, messages),
+		MaxTokens: defaultMaxTokens,
+		ResponseFormat: &azureResponseFormat{
+			Type: "json_schema",
+			JSONSchema: azureJSONSchema{
+				Name:        schema.Name,
+				Description: schema.Description,
+				Schema:      schema.Schema,
+			},
+		},
+	})
+}
+
+// ChatStream sends messages to the chat completions endpoint with streaming
+// enabled and calls onDelta with each chunk of the reply.
+func (c *azureOpenAIClient) ChatStream(ctx context.Context, systemPrompt string, messages []llm.Message, onDelta func(string)) (*llm.Response, error) {
+	resp, err := c.post(ctx, azureChatRequest{
+		Messages:      convertToAzureMessages(systemPrompt, messages),
+		MaxTokens:     defaultMaxTokens,
+		Stream:        true,
+		StreamOptions: &azureStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &llm.Response{}
+	err = readServerSentEvents(resp.Body, func(data string) (bool, error) {
+		if data == "[DONE]" {
+			return true, nil
+		}
+		var chunk azureChatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return false, fmt.Errorf("unmarshal stream chunk: %w", err)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				result.Content += choice.Delta.Content
+				onDelta(choice.Delta.Content)
+			}
+			if choice.FinishReason != "" {
+				result.FinishReason = choice.FinishReason
+			}
+		}
+		if chunk.Usage != nil {
+			result.Usage = llm.TokenUsage{
+				InputTokens:  chunk.Usage.PromptTokens,
+				OutputTokens: chunk.Usage.CompletionTokens,
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// doChat sends a non-streaming request and parses the response.
+func (c *azureOpenAIClient) doChat(ctx context.Context, reqBody azureChatRequest) (*llm.Response, error) {
+	resp, err := c.post(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	var chatResp azureChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return nil, fmt.Errorf("unmarshal chat response: %w", err)
+	}
+
+	return parseAzureResponse(chatResp)
+}
+
+// post sends a chat completions request and returns the response when its
+// status is OK. The caller closes the body.
+func (c *azureOpenAIClient) post(ctx context.Context, reqBody azureChatRequest) (*http.Response, error) {
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal chat request: %w", err)
+	}
+
+	u := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+		c.endpoint, url.PathEscape(c.deployment), url.QueryEscape(c.apiVersion))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("create chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("api-key", c.apiKey)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		var apiErr azureErrorResponse
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("azure OpenAI API error (HTTP %d): %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("azure OpenAI API error (HTTP %d): %s", resp.StatusCode, string(respBody))
+	}
+	return resp, nil
+}
+
+// parseAzureResponse converts a chat completions response to llm.Response.
+func parseAzureResponse(chatResp azureChatResponse) (*llm.Response, error) {
+	resp := &llm.Response{}
+	if chatResp.Usage != nil {
+		resp.Usage = llm.TokenUsage{
+			InputTokens:  chatResp.Usage.PromptTokens,
+			OutputTokens: chatResp.Usage.CompletionTokens,
+		}
+	}
+	if len(chatResp.Choices) == 0 {
+		return resp, nil
+	}
+
+	choice := chatResp.Choices[0]
+	resp.Content = choice.Message.Content
+	resp.FinishReason = choice.FinishReason
+	for _, tc := range choice.Message.ToolCalls {
+		var args map[string]any
+		if tc.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+				return nil, fmt.Errorf("unmarshal arguments of tool call %s: %w", tc.Function.Name, err)
+			}
+		}
+		resp.ToolCalls = append(resp.ToolCalls, llm.ToolCall{
+			ID:        tc.ID,
+			Name:      tc.Function.Name,
+			Arguments: args,
+		})
+	}
+	return resp, nil
+}
+
+// convertToAzureMessages converts a system prompt and llm.Messages, including
+// tool calls and tool results, to the chat completions format.
+func convertToAzureMessages(systemPrompt string, messages []llm.Message) []azureMessage {
+	var result []azureMessage
+
+	if systemPrompt != "" {
+		result = append(result, azureMessage{
+			Role:    "system",
+			Content: systemPrompt,
+		})
+	}
+
+	for _, msg := range messages {
+		am := azureMessage{
+			Role:    string(msg.Role),
+			Content: msg.Content,
+		}
+		switch msg.Role {
+		case llm.RoleTool:
+			am.ToolCallID = msg.ToolCallID
+		case llm.RoleAssistant:
+			for _, tc := range msg.ToolCalls {
+				args, _ := json.Marshal(tc.Arguments)
+				am.ToolCalls = append(am.ToolCalls, azureToolCall{
+					ID:   tc.ID,
+					Type: "function",
+					Function: azureFunctionCall{
+						Name:      tc.Name,
+						Arguments: string(args),
+					},
+				})
+			}
+		}
+		result = append(result, am)
+	}
+
+	return result
+}
+
+// convertToAzureTools converts llm.Tool to chat completions tool definitions.
+func convertToAzureTools(tools []llm.Tool) []azureToolDef {
+	if len(tools) == 0 {
+		return nil
+	}
+	defs := make([]azureToolDef, len(tools))
+	for i, t := range tools {
+		defs[i] = azureToolDef{
+			Type: "function",
+			Function: azureFunctionDecl{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		}
+	}
+	return defs
+}
+
+// readServerSentEvents calls handle with the data of each server-sent event
+// in r until handle reports done, returns an error, or r ends.
+func readServerSentEvents(r io.Reader, handle func(data string) (done bool, err error)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) == 0 {
+				continue
+			}
+			done, err := handle(strings.Join(data, "\n"))
+			if err != nil || done {
+				return err
+			}
+			data = data[:0]
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(rest, " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read stream: %w", err)
+	}
+	if len(data) > 0 {
+		_, err := handle(strings.Join(data, "\n"))
+		return err
+	}
+	return nil
+}
+
+// Model returns the deployment name being used.
+func (c *azureOpenAIClient) Model() string {
+	return c.deployment
+}
+
+// Provider returns the provider name.
+func (c *azureOpenAIClient) Provider() string {
+	return "azure-openai"
+}
+
+// Close releases resources held by the client.
+func (c *azureOpenAIClient) Close() error {
+	return nil
+}
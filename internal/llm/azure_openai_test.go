@@ -0,0 +1,219 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+func TestAzureOpenAIProviderRegistration(t *testing.T) {
+	if !llm.IsProviderRegistered("azure-openai") {
+		t.Fatal("expected 'azure-openai' provider to be registered via init()")
+	}
+}
+
+func TestAzureOpenAINewClientValidation(t *testing.T) {
+	t.Setenv("AZURE_OPENAI_ENDPOINT", "")
+	t.Setenv("AZURE_OPENAI_API_KEY", "")
+	t.Setenv("AZURE_OPENAI_AD_TOKEN", "")
+
+	tests := []struct {
+		name    string
+		cfg     llm.Config
+		wantErr string
+	}{
+		{
+			name:    "missing deployment",
+			cfg:     llm.Config{BaseURL: "https://x.openai.azure.com", APIKey: "k"},
+			wantErr: "model (deployment name) is required for Azure OpenAI provider",
+		},
+		{
+			name:    "missing endpoint",
+			cfg:     llm.Config{Model: "gpt-4o", APIKey: "k"},
+			wantErr: "base URL (resource endpoint) is required for Azure OpenAI provider",
+		},
+		{
+			name:    "missing credentials",
+			cfg:     llm.Config{Model: "gpt-4o", BaseURL: "https://x.openai.azure.com"},
+			wantErr: "API key or AZURE_OPENAI_AD_TOKEN is required for Azure OpenAI provider",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newAzureOpenAIClient(tt.cfg)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAzureOpenAIEntraIDToken(t *testing.T) {
+	t.Setenv("AZURE_OPENAI_API_KEY", "")
+	t.Setenv("AZURE_OPENAI_AD_TOKEN", "entra-token")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer entra-token" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		if r.Header.Get("api-key") != "" {
+			t.Error("expected no api-key header")
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := newAzureOpenAIClient(llm.Config{Model: "gpt-4o", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Chat(context.Background(), "", []llm.Message{{Role: llm.RoleUser, Content: "hi"}}); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+}
+
+func TestAzureOpenAIChatWithTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/my-gpt/chat/completions" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api-version"); got != "2024-06-01" {
+			t.Errorf("expected api-version 2024-06-01, got %q", got)
+		}
+		if got := r.Header.Get("api-key"); got != "secret" {
+			t.Errorf("expected api-key header 'secret', got %q", got)
+		}
+
+		var req azureChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			w.WriteHeader(500)
+			return
+		}
+		if len(req.Tools) != 1 || req.Tools[0].Function.Name != "search" {
+			t.Errorf("expected the search tool, got %+v", req.Tools)
+		}
+		// system, user, assistant tool call, tool result
+		if len(req.Messages) != 4 {
+			t.Fatalf("expected 4 messages, got %d", len(req.Messages))
+		}
+		if tc := req.Messages[2].ToolCalls; len(tc) != 1 || tc[0].Function.Arguments != `{"q":"X"}` {
+			t.Errorf("unexpected assistant tool calls %+v", tc)
+		}
+		if req.Messages[3].ToolCallID != "call_1" {
+			t.Errorf("expected tool_call_id call_1, got %q", req.Messages[3].ToolCallID)
+		}
+
+		w.Write([]byte(`{
+			"choices":[{"message":{"role":"assistant","content":"","tool_calls":[
+				{"id":"call_2","type":"function","function":{"name":"search","arguments":"{\"q\":\"Y\"}"}}
+			]},"finish_reason":"tool_calls"}],
+			"usage":{"prompt_tokens":30,"completion_tokens":8}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := newAzureOpenAIClient(llm.Config{Model: "my-gpt", BaseURL: server.URL + "/", APIKey: "secret", APIVersion: "2024-06-01"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tc := client.(llm.ToolCapableClient)
+	resp, err := tc.ChatWithTools(context.Background(), "sys", []llm.Message{
+		{Role: llm.RoleUser, Content: "What is X?"},
+		{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "search", Arguments: map[string]any{"q": "X"}}}},
+		{Role: llm.RoleTool, Content: "X is a struct", ToolCallID: "call_1"},
+	}, []llm.Tool{{Name: "search", Description: "Search", Parameters: map[string]any{"type": "object"}}})
+	if err != nil {
+		t.Fatalf("ChatWithTools: %v", err)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].ID != "call_2" || resp.ToolCalls[0].Arguments["q"] != "Y" {
+		t.Errorf("unexpected tool calls %+v", resp.ToolCalls)
+	}
+	if resp.FinishReason != "tool_calls" || resp.Usage.InputTokens != 30 || resp.Usage.OutputTokens != 8 {
+		t.Errorf("finish = %q, usage = %+v", resp.FinishReason, resp.Usage)
+	}
+}
+
+func TestAzureOpenAIChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req azureChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Error("expected streaming with usage")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"stop\"}]}\n\n" +
+			"data: {\"choices\":[],\"usage\":{\"prompt_tokens\":4,\"completion_tokens\":2}}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client, err := newAzureOpenAIClient(llm.Config{Model: "my-gpt", BaseURL: server.URL, APIKey: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var deltas []string
+	resp, err := llm.ChatStream(context.Background(), client, "", []llm.Message{{Role: llm.RoleUser, Content: "hi"}}, func(s string) {
+		deltas = append(deltas, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	if len(deltas) != 2 || resp.Content != "Hello" || resp.FinishReason != "stop" {
+		t.Errorf("deltas = %q, content = %q, finish = %q", deltas, resp.Content, resp.FinishReason)
+	}
+	if resp.Usage.InputTokens != 4 || resp.Usage.OutputTokens != 2 {
+		t.Errorf("usage = %+v, want 4 input and 2 output tokens", resp.Usage)
+	}
+}
+
+func TestAzureOpenAIAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"code":"429","message":"rate limited"}}`))
+	}))
+	defer server.Close()
+
+	client, err := newAzureOpenAIClient(llm.Config{Model: "my-gpt", BaseURL: server.URL, APIKey: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = client.Chat(context.Background(), "", []llm.Message{{Role: llm.RoleUser, Content: "hi"}})
+	if err == nil || err.Error() != "azure OpenAI API error (HTTP 429): rate limited" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestAzureOpenAIChatJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req azureChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.Type != "json_schema" || req.ResponseFormat.JSONSchema.Name != "answer" {
+			t.Errorf("unexpected response_format %+v", req.ResponseFormat)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"ok\":true}"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := newAzureOpenAIClient(llm.Config{Model: "my-gpt", BaseURL: server.URL, APIKey: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema := llm.Schema{Name: "answer", Schema: map[string]any{"type": "object", "required": []string{"ok"}}}
+	resp, err := llm.ChatJSON(context.Background(), client, "", []llm.Message{{Role: llm.RoleUser, Content: "hi"}}, schema, 0)
+	if err != nil {
+		t.Fatalf("ChatJSON: %v", err)
+	}
+	if resp.Content != `{"ok":true}` {
+		t.Errorf("content = %q", resp.Content)
+	}
+}
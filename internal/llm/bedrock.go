@@ -0,0 +1,387 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+func init() {
+	llm.RegisterProvider("bedrock", newBedrockClient)
+}
+
+// bedrockClient implements llm.Client and llm.StreamingClient using the
+// Amazon Bedrock Converse API, which serves every Bedrock text model
+// through one request format.
+type bedrockClient struct {
+	baseURL string
+	region  string
+	model   string
+	// bearerToken is a Bedrock API key; when empty, requests are signed
+	// with creds instead.
+	bearerToken string
+	creds       awsCredentials
+	client      *http.Client
+	now         func() time.Time
+}
+
+// newBedrockClient creates a new Bedrock client. The region falls back to
+// AWS_REGION and AWS_DEFAULT_REGION. The API key (or AWS_BEARER_TOKEN_BEDROCK)
+// is used as a bearer token when set; otherwise requests are signed with
+// the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+// environment credentials.
+func newBedrockClient(cfg llm.Config) (llm.Client, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model is required for Bedrock provider")
+	}
+
+	region := cfg.Location
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("region is required for Bedrock provider")
+	}
+
+	c := &bedrockClient{
+		baseURL:     cfg.BaseURL,
+		region:      region,
+		model:       cfg.Model,
+		bearerToken: cfg.APIKey,
+		client:      &http.Client{},
+		now:         time.Now,
+	}
+	if c.baseURL == "" {
+		c.baseURL = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)
+	}
+	if c.bearerToken == "" {
+		c.bearerToken = os.Getenv("AWS_BEARER_TOKEN_BEDROCK")
+	}
+	if c.bearerToken == "" {
+		c.creds = awsCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if c.creds.AccessKeyID == "" || c.creds.SecretAccessKey == "" {
+			return nil, fmt.Errorf("AWS credentials are required for Bedrock provider (set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or AWS_BEARER_TOKEN_BEDROCK)")
+		}
+	}
+	c.baseURL = strings.TrimRight(c.baseURL, "/")
+	return c, nil
+}
+
+// --- Wire format types ---
+
+// bedrockRequest is the request body for the Converse and ConverseStream APIs.
+type bedrockRequest struct {
+	Messages        []bedrockMessage        `json:"messages"`
+	System          []bedrockContentBlock   `json:"system,omitempty"`
+	InferenceConfig *bedrockInferenceConfig `json:"inferenceConfig,omitempty"`
+}
+
+// bedrockMessage is a message in the Converse format.
+type bedrockMessage struct {
+	Role    string                `json:"role"`
+	Content []bedrockContentBlock `json:"content"`
+}
+
+// bedrockContentBlock is a text content block.
+type bedrockContentBlock struct {
+	Text string `json:"text"`
+}
+
+// bedrockInferenceConfig holds generation parameters.
+type bedrockInferenceConfig struct {
+	MaxTokens int `json:"maxTokens,omitempty"`
+}
+
+// bedrockResponse is the response from the Converse API.
+type bedrockResponse struct {
+	Output struct {
+		Message bedrockMessage `json:"message"`
+	} `json:"output"`
+	StopReason string       `json:"stopReason"`
+	Usage      bedrockUsage `json:"usage"`
+}
+
+// bedrockUsage contains token usage from the response.
+type bedrockUsage struct {
+	InputTokens  int `json:"inputTokens"`
+	OutputTokens int `json:"outputTokens"`
+}
+
+// bedrockStreamEvent is the union of the ConverseStream event payloads used
+// here: contentBlockDelta, messageStop and metadata.
+type bedrockStreamEvent struct {
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+	StopReason string        `json:"stopReason"`
+	Usage      *bedrockUsage `json:"usage"`
+}
+
+// bedrockError is the error body returned by Bedrock.
+type bedrockError struct {
+	Message string `json:"message"`
+}
+
+// Chat sends a system prompt and messages to the Converse API.
+func (c *bedrockClient) Chat(ctx context.Context, systemPrompt string, messages []llm.Message) (*llm.Response, error) {
+	resp, err := c.post(ctx, "converse", newBedrockRequest(systemPrompt, messages))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	var br bedrockResponse
+	if err := json.Unmarshal(respBody, &br); err != nil {
+		return nil, fmt.Errorf("unmarshal converse response: %w", err)
+	}
+
+	result := &llm.Response{
+		FinishReason: br.StopReason,
+		Usage: llm.TokenUsage{
+			InputTokens:  br.Usage.InputTokens,
+			OutputTokens: br.Usage.OutputTokens,
+		},
+	}
+	for _, block := range br.Output.Message.Content {
+		result.Content += block.Text
+	}
+	return result, nil
+}
+
+// ChatStream sends messages to the ConverseStream API and calls onDelta
+// with each chunk of the reply.
+func (c *bedrockClient) ChatStream(ctx context.Context, systemPrompt string, messages []llm.Message, onDelta func(string)) (*llm.Response, error) {
+	resp, err := c.post(ctx, "converse-stream", newBedrockRequest(systemPrompt, messages))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &llm.Response{}
+	for {
+		msg, err := readEventStreamMessage(resp.Body)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read converse stream: %w", err)
+		}
+
+		if msg.headers[":message-type"] != "event" {
+			var apiErr bedrockError
+			_ = json.Unmarshal(msg.payload, &apiErr)
+			return nil, fmt.Errorf("bedrock stream error (%s): %s", msg.headers[":exception-type"], apiErr.Message)
+		}
+
+		var ev bedrockStreamEvent
+		if err := json.Unmarshal(msg.payload, &ev); err != nil {
+			return nil, fmt.Errorf("unmarshal %s event: %w", msg.headers[":event-type"], err)
+		}
+		switch msg.headers[":event-type"] {
+		case "contentBlockDelta":
+			if ev.Delta.Text != "" {
+				result.Content += ev.Delta.Text
+				onDelta(ev.Delta.Text)
+			}
+		case "messageStop":
+			result.FinishReason = ev.StopReason
+		case "metadata":
+			if ev.Usage != nil {
+				result.Usage = llm.TokenUsage{
+					InputTokens:  ev.Usage.InputTokens,
+					OutputTokens: ev.Usage.OutputTokens,
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// post sends a request to the given model action and returns the response
+// when its status is OK. The caller closes the body.
+func (c *bedrockClient) post(ctx context.Context, action string, reqBody bedrockRequest) (*http.Response, error) {
+	data, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s request: %w", action, err)
+	}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse base URL: %w", err)
+	}
+	// Model IDs contain colons, which Bedrock expects percent-encoded.
+	u.RawPath = u.EscapedPath() + "/model/" + awsURIEncode(c.model) + "/" + action
+	u.Path += "/model/" + c.model + "/" + action
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("create %s request: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	} else {
+		signAWSRequest(req, data, c.creds, c.region, "bedrock", c.now())
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request: %w", action, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		var apiErr bedrockError
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("bedrock API error (HTTP %d): %s", resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("bedrock API error (HTTP %d): %s", resp.StatusCode, string(respBody))
+	}
+	return resp, nil
+}
+
+// newBedrockRequest converts a system prompt and llm.Messages to a Converse
+// request. Tool results are sent as user text, since tools are not offered.
+func newBedrockRequest(systemPrompt string, messages []llm.Message) bedrockRequest {
+	req := bedrockRequest{
+		InferenceConfig: &bedrockInferenceConfig{MaxTokens: defaultMaxTokens},
+	}
+	if systemPrompt != "" {
+		req.System = []bedrockContentBlock{{Text: systemPrompt}}
+	}
+	for _, msg := range messages {
+		role := "user"
+		if msg.Role == llm.RoleAssistant {
+			role = "assistant"
+		}
+		req.Messages = append(req.Messages, bedrockMessage{
+			Role:    role,
+			Content: []bedrockContentBlock{{Text: msg.Content}},
+		})
+	}
+	return req
+}
+
+// eventStreamMessage is one decoded message of the AWS event stream
+// encoding used by Bedrock's streaming APIs.
+type eventStreamMessage struct {
+	headers map[string]string
+	payload []byte
+}
+
+// readEventStreamMessage reads and checks one event stream message: a
+// 12-byte prelude (total length, headers length, prelude CRC), the headers,
+// the payload and a CRC of the whole message. Only string-valued headers
+// are kept.
+func readEventStreamMessage(r io.Reader) (*eventStreamMessage, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated message prelude")
+		}
+		return nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, fmt.Errorf("prelude checksum mismatch")
+	}
+	if totalLen < 16+headersLen || totalLen > 16<<20 {
+		return nil, fmt.Errorf("invalid message length %d", totalLen)
+	}
+
+	rest := make([]byte, totalLen-12)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+	body, crc := rest[:len(rest)-4], binary.BigEndian.Uint32(rest[len(rest)-4:])
+	h := crc32.NewIEEE()
+	h.Write(prelude)
+	h.Write(body)
+	if h.Sum32() != crc {
+		return nil, fmt.Errorf("message checksum mismatch")
+	}
+
+	headers, err := parseEventStreamHeaders(body[:headersLen])
+	if err != nil {
+		return nil, err
+	}
+	return &eventStreamMessage{headers: headers, payload: body[headersLen:]}, nil
+}
+
+// eventStreamValueSizes gives the size of the fixed-width header value
+// types, indexed by type: bool true, bool false, byte, short, int, long,
+// (bytes), (string), timestamp, uuid.
+var eventStreamValueSizes = [10]int{0, 0, 1, 2, 4, 8, -1, -1, 8, 16}
+
+// parseEventStreamHeaders decodes event stream headers, keeping those with
+// string values.
+func parseEventStreamHeaders(b []byte) (map[string]string, error) {
+	headers := make(map[string]string)
+	for len(b) > 0 {
+		nameLen := int(b[0])
+		if len(b) < 1+nameLen+1 {
+			return nil, fmt.Errorf("truncated header")
+		}
+		name := string(b[1 : 1+nameLen])
+		typ := b[1+nameLen]
+		b = b[2+nameLen:]
+		if int(typ) >= len(eventStreamValueSizes) {
+			return nil, fmt.Errorf("unknown header value type %d", typ)
+		}
+		size := eventStreamValueSizes[typ]
+		if size < 0 {
+			if len(b) < 2 {
+				return nil, fmt.Errorf("truncated header %s", name)
+			}
+			size = int(binary.BigEndian.Uint16(b[:2]))
+			b = b[2:]
+		}
+		if len(b) < size {
+			return nil, fmt.Errorf("truncated header %s", name)
+		}
+		if typ == 7 {
+			headers[name] = string(b[:size])
+		}
+		b = b[size:]
+	}
+	return headers, nil
+}
+
+// Model returns the model ID being used.
+func (c *bedrockClient) Model() string {
+	return c.model
+}
+
+// Provider returns the provider name.
+func (c *bedrockClient) Provider() string {
+	return "bedrock"
+}
+
+// Close releases resources held by the client.
+func (c *bedrockClient) Close() error {
+	return nil
+}
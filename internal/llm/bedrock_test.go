@@ -0,0 +1,176 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+func TestBedrockProviderRegistration(t *testing.T) {
+	if !llm.IsProviderRegistered("bedrock") {
+		t.Fatal("expected 'bedrock' provider to be registered via init()")
+	}
+}
+
+func TestBedrockNewClientValidation(t *testing.T) {
+	for _, env := range []string{"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_BEARER_TOKEN_BEDROCK", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY"} {
+		t.Setenv(env, "")
+	}
+
+	tests := []struct {
+		name    string
+		cfg     llm.Config
+		wantErr string
+	}{
+		{"missing model", llm.Config{Location: "us-east-1"}, "model is required for Bedrock provider"},
+		{"missing region", llm.Config{Model: "m"}, "region is required for Bedrock provider"},
+		{"missing credentials", llm.Config{Model: "m", Location: "us-east-1"}, "AWS credentials are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newBedrockClient(tt.cfg)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBedrockChatSigned(t *testing.T) {
+	t.Setenv("AWS_BEARER_TOKEN_BEDROCK", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "session")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/model/anthropic.claude-v2%3A1/converse" {
+			t.Errorf("unexpected path %q", r.URL.EscapedPath())
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20250102/eu-west-1/bedrock/aws4_request, ") ||
+			!strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,") {
+			t.Errorf("unexpected Authorization %q", auth)
+		}
+		if r.Header.Get("X-Amz-Security-Token") != "session" {
+			t.Error("expected session token header")
+		}
+
+		var req bedrockRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if len(req.System) != 1 || req.System[0].Text != "sys" {
+			t.Errorf("unexpected system %+v", req.System)
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Role != "user" || req.Messages[0].Content[0].Text != "hi" {
+			t.Errorf("unexpected messages %+v", req.Messages)
+		}
+		w.Write([]byte(`{"output":{"message":{"role":"assistant","content":[{"text":"Hello"}]}},"stopReason":"end_turn","usage":{"inputTokens":5,"outputTokens":1}}`))
+	}))
+	defer server.Close()
+
+	client, err := newBedrockClient(llm.Config{Model: "anthropic.claude-v2:1", Location: "eu-west-1", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.(*bedrockClient).now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	resp, err := client.Chat(context.Background(), "sys", []llm.Message{{Role: llm.RoleUser, Content: "hi"}})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp.Content != "Hello" || resp.FinishReason != "end_turn" || resp.Usage.InputTokens != 5 || resp.Usage.OutputTokens != 1 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
+
+// encodeEventStreamMessage encodes a message with string headers in the AWS
+// event stream format.
+func encodeEventStreamMessage(headers map[string]string, payload string) []byte {
+	var hb bytes.Buffer
+	for name, value := range headers {
+		hb.WriteByte(byte(len(name)))
+		hb.WriteString(name)
+		hb.WriteByte(7)
+		binary.Write(&hb, binary.BigEndian, uint16(len(value)))
+		hb.WriteString(value)
+	}
+	var msg bytes.Buffer
+	binary.Write(&msg, binary.BigEndian, uint32(16+hb.Len()+len(payload)))
+	binary.Write(&msg, binary.BigEndian, uint32(hb.Len()))
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(hb.Bytes())
+	msg.WriteString(payload)
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	return msg.Bytes()
+}
+
+func TestBedrockChatStream(t *testing.T) {
+	event := func(typ, payload string) []byte {
+		return encodeEventStreamMessage(map[string]string{":message-type": "event", ":event-type": typ}, payload)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer api-key" {
+			t.Errorf("expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		if !strings.HasSuffix(r.URL.Path, "/converse-stream") {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		w.Write(event("messageStart", `{"role":"assistant"}`))
+		w.Write(event("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"Hel"}}`))
+		w.Write(event("contentBlockDelta", `{"contentBlockIndex":0,"delta":{"text":"lo"}}`))
+		w.Write(event("messageStop", `{"stopReason":"end_turn"}`))
+		w.Write(event("metadata", `{"usage":{"inputTokens":3,"outputTokens":2}}`))
+	}))
+	defer server.Close()
+
+	client, err := newBedrockClient(llm.Config{Model: "m", Location: "us-east-1", BaseURL: server.URL, APIKey: "api-key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var deltas []string
+	resp, err := llm.ChatStream(context.Background(), client, "", []llm.Message{{Role: llm.RoleUser, Content: "hi"}}, func(s string) {
+		deltas = append(deltas, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	if len(deltas) != 2 || resp.Content != "Hello" || resp.FinishReason != "end_turn" {
+		t.Errorf("deltas = %q, response = %+v", deltas, resp)
+	}
+	if resp.Usage.InputTokens != 3 || resp.Usage.OutputTokens != 2 {
+		t.Errorf("usage = %+v, want 3 input and 2 output tokens", resp.Usage)
+	}
+}
+
+func TestReadEventStreamMessage(t *testing.T) {
+	exception := encodeEventStreamMessage(map[string]string{":message-type": "exception", ":exception-type": "throttlingException"}, `{"message":"slow down"}`)
+
+	msg, err := readEventStreamMessage(bytes.NewReader(exception))
+	if err != nil {
+		t.Fatalf("readEventStreamMessage: %v", err)
+	}
+	if msg.headers[":exception-type"] != "throttlingException" || string(msg.payload) != `{"message":"slow down"}` {
+		t.Errorf("unexpected message %+v", msg)
+	}
+
+	corrupt := append([]byte(nil), exception...)
+	corrupt[len(corrupt)-6] ^= 0xff
+	if _, err := readEventStreamMessage(bytes.NewReader(corrupt)); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+	if _, err := readEventStreamMessage(bytes.NewReader(nil)); err != io.EOF {
+		t.Errorf("expected io.EOF at end of stream, got %v", err)
+	}
+}
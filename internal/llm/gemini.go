@@ -0,0 +1,53 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"google.golang.org/genai"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+const defaultGeminiModel = "gemini-2.0-flash"
+
+func init() {
+	llm.RegisterProvider("gemini", newGeminiClient)
+}
+
+// newGeminiClient creates a client for the Gemini Developer API, which
+// authenticates with an API key instead of GCP credentials. The key falls
+// back to the GEMINI_API_KEY and GOOGLE_API_KEY environment variables.
+func newGeminiClient(cfg llm.Config) (llm.Client, error) {
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = os.Getenv("GEMINI_API_KEY")
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv("GOOGLE_API_KEY")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required for Gemini provider")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultGeminiModel
+	}
+
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      apiKey,
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: cfg.BaseURL},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	return &vertexAIClient{
+		client:   client,
+		model:    model,
+		provider: "gemini",
+	}, nil
+}
@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/pkg/llm"
+)
+
+func TestGeminiProviderRegistration(t *testing.T) {
+	if !llm.IsProviderRegistered("gemini") {
+		t.Fatal("expected 'gemini' provider to be registered via init()")
+	}
+}
+
+func TestGeminiConfigValidation(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_API_KEY", "")
+
+	_, err := llm.NewClient(llm.Config{Provider: "gemini"})
+	if err == nil || err.Error() != "API key is required for Gemini provider" {
+		t.Fatalf("expected missing API key error, got %v", err)
+	}
+}
+
+func TestGeminiChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/models/gemini-test:streamGenerateContent") {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if got := r.Header.Get("x-goog-api-key"); got != "key" {
+			t.Errorf("expected API key header, got %q", got)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"Hel"}]}}],"usageMetadata":{"promptTokenCount":4,"candidatesTokenCount":1}}` + "\n\n" +
+			`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"lo"}]},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":4,"candidatesTokenCount":2}}` + "\n\n"))
+	}))
+	defer server.Close()
+
+	client, err := newGeminiClient(llm.Config{Model: "gemini-test", APIKey: "key", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.Provider() != "gemini" {
+		t.Errorf("expected provider gemini, got %q", client.Provider())
+	}
+
+	var deltas []string
+	resp, err := llm.ChatStream(context.Background(), client, "sys", []llm.Message{{Role: llm.RoleUser, Content: "hi"}}, func(s string) {
+		deltas = append(deltas, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	if len(deltas) != 2 || resp.Content != "Hello" || resp.FinishReason != "STOP" {
+		t.Errorf("deltas = %q, response = %+v", deltas, resp)
+	}
+	if resp.Usage.InputTokens != 4 || resp.Usage.OutputTokens != 2 {
+		t.Errorf("usage = %+v, want 4 input and 2 output tokens", resp.Usage)
+	}
+}
@@ -1,6 +1,7 @@
 package llm
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -17,8 +18,9 @@ func init() {
 	llm.RegisterProvider("ollama", newOllamaClient)
 }
 
-// ollamaClient implements llm.Client and llm.ToolCapableClient using the
-// Ollama /api/chat endpoint.
+// ollamaClient implements llm.Client, llm.ToolCapableClient,
+// llm.StreamingClient and llm.StructuredClient using the Ollama /api/chat
+// endpoint.
 type ollamaClient struct {
 	baseURL string
 	model   string
@@ -51,6 +53,8 @@ type ollamaChatRequest struct {
 	Messages []ollamaMessage `json:"messages"`
 	Stream   bool            `json:"stream"`
 	Tools    []ollamaToolDef `json:"tools,omitempty"`
+	// Format constrains the reply to a JSON schema.
+	Format map[string]any `json:"format,omitempty"`
 }
 
 // ollamaMessage represents a message in the Ollama chat format.
@@ -127,8 +131,91 @@ func (c *ollamaClient) ChatWithTools(ctx context.Context, systemPrompt string, m
 	return c.doChat(ctx, reqBody)
 }
 
+// ChatJSON sends messages to the Ollama /api/chat endpoint with the reply
+// constrained to the schema.
+func (c *ollamaClient) ChatJSON(ctx context.Context, systemPrompt string, messages []llm.Message, schema llm.Schema) (*llm.Response, error) {
+	return c.doChat(ctx, ollamaChatRequest{
+		Model:    c.model,
+		Messages: convertToOllamaMessages(systemPrompt, messages),
+		Format:   schema.Schema,
+	})
+}
+
+// ChatStream sends messages to the Ollama /api/chat endpoint with streaming
+// enabled and calls onDelta with each chunk of the reply.
+func (c *ollamaClient) ChatStream(ctx context.Context, systemPrompt string, messages []llm.Message, onDelta func(string)) (*llm.Response, error) {
+	reqBody := ollamaChatRequest{
+		Model:    c.model,
+		Messages: convertToOllamaMessages(systemPrompt, messages),
+		Stream:   true,
+	}
+
+	resp, err := c.post(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// The stream is one JSON object per line; the last one has done set
+	// and carries the token counts.
+	var content string
+	var last ollamaChatResponse
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			var apiErr ollamaErrorResponse
+			if json.Unmarshal(line, &apiErr) == nil && apiErr.Error != "" {
+				return nil, fmt.Errorf("ollama API error: %s", apiErr.Error)
+			}
+			return nil, fmt.Errorf("unmarshal stream chunk: %w", err)
+		}
+		if chunk.Message.Content != "" {
+			content += chunk.Message.Content
+			onDelta(chunk.Message.Content)
+		}
+		last = chunk
+		if chunk.Done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read stream: %w", err)
+	}
+
+	last.Message.Content = content
+	return parseOllamaResponse(last), nil
+}
+
 // doChat sends a chat request to the Ollama API and parses the response.
 func (c *ollamaClient) doChat(ctx context.Context, reqBody ollamaChatRequest) (*llm.Response, error) {
+	resp, err := c.post(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	var chatResp ollamaChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return nil, fmt.Errorf("unmarshal chat response: %w", err)
+	}
+
+	return parseOllamaResponse(chatResp), nil
+}
+
+// post sends a chat request to the Ollama API and returns the response when
+// its status is OK. The caller closes the body.
+func (c *ollamaClient) post(ctx context.Context, reqBody ollamaChatRequest) (*http.Response, error) {
 	data, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("marshal chat request: %w", err)
@@ -145,27 +232,16 @@ func (c *ollamaClient) doChat(ctx context.Context, reqBody ollamaChatRequest) (*
 	if err != nil {
 		return nil, fmt.Errorf("chat request: %w", err)
 	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read response body: %w", err)
-	}
-
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
 		var apiErr ollamaErrorResponse
 		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error != "" {
 			return nil, fmt.Errorf("ollama API error (HTTP %d): %s", resp.StatusCode, apiErr.Error)
 		}
 		return nil, fmt.Errorf("ollama API error (HTTP %d): %s", resp.StatusCode, string(respBody))
 	}
-
-	var chatResp ollamaChatResponse
-	if err := json.Unmarshal(respBody, &chatResp); err != nil {
-		return nil, fmt.Errorf("unmarshal chat response: %w", err)
-	}
-
-	return parseOllamaResponse(chatResp), nil
+	return resp, nil
 }
 
 // parseOllamaResponse converts an Ollama chat response to llm.Response.
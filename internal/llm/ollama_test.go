@@ -438,3 +438,70 @@ func searchString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestOllamaChatStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if !req.Stream {
+			t.Error("expected stream to be enabled")
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, line := range []string{
+			`{"message":{"role":"assistant","content":"Hel"},"done":false}`,
+			`{"message":{"role":"assistant","content":"lo"},"done":false}`,
+			`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":7,"eval_count":2}`,
+		} {
+			w.Write([]byte(line + "\n"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := newOllamaClient(llm.Config{Model: "test-model", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var deltas []string
+	resp, err := llm.ChatStream(context.Background(), client, "sys", []llm.Message{{Role: llm.RoleUser, Content: "hi"}}, func(s string) {
+		deltas = append(deltas, s)
+	})
+	if err != nil {
+		t.Fatalf("ChatStream: %v", err)
+	}
+	if len(deltas) != 2 || resp.Content != "Hello" {
+		t.Errorf("deltas = %q, content = %q; want [Hel lo] and Hello", deltas, resp.Content)
+	}
+	if resp.FinishReason != "stop" || resp.Usage.InputTokens != 7 || resp.Usage.OutputTokens != 2 {
+		t.Errorf("finish = %q, usage = %+v; want stop, 7 input and 2 output tokens", resp.FinishReason, resp.Usage)
+	}
+}
+
+func TestOllamaChatJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if req.Format["type"] != "object" {
+			t.Errorf("expected the schema as format, got %v", req.Format)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"{\"ok\": true}"},"done":true}`))
+	}))
+	defer server.Close()
+
+	client, err := newOllamaClient(llm.Config{Model: "test-model", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema := llm.Schema{Name: "answer", Schema: map[string]any{"type": "object"}}
+	resp, err := llm.ChatJSON(context.Background(), client, "", []llm.Message{{Role: llm.RoleUser, Content: "hi"}}, schema, 0)
+	if err != nil {
+		t.Fatalf("ChatJSON: %v", err)
+	}
+	if resp.Content != `{"ok":true}` {
+		t.Errorf("content = %q", resp.Content)
+	}
+}
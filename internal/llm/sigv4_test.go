@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestSignAWSRequest checks the signer against the IAM ListUsers example
+// from the AWS Signature Version 4 documentation.
+func TestSignAWSRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	creds := awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	signAWSRequest(req, nil, creds, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date = %q", got)
+	}
+}
+
+func TestAWSCanonicalURI(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"", "/"},
+		{"/model/anthropic.claude-v2/converse", "/model/anthropic.claude-v2/converse"},
+		// The escaped colon is encoded a second time.
+		{"/model/anthropic.claude-v2%3A1/converse", "/model/anthropic.claude-v2%253A1/converse"},
+	}
+	for _, tt := range tests {
+		if got := awsCanonicalURI(tt.path); got != tt.want {
+			t.Errorf("awsCanonicalURI(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -20,10 +20,12 @@ func init() {
 }
 
 // vertexAIClient implements llm.Client using Google's GenAI SDK with the Vertex AI backend.
-// It supports both Gemini models and Claude models hosted on Vertex AI.
+// It supports both Gemini models and Claude models hosted on Vertex AI. The
+// gemini provider uses it with the Gemini Developer API backend.
 type vertexAIClient struct {
-	client *genai.Client
-	model  string
+	client   *genai.Client
+	model    string
+	provider string
 }
 
 // newVertexAIClient creates a new Vertex AI client.
@@ -59,8 +61,9 @@ func newVertexAIClient(cfg llm.Config) (llm.Client, error) {
 	}
 
 	return &vertexAIClient{
-		client: client,
-		model:  model,
+		client:   client,
+		model:    model,
+		provider: "vertex-ai",
 	}, nil
 }
 
@@ -85,6 +88,57 @@ func (c *vertexAIClient) Chat(ctx context.Context, systemPrompt string, messages
 	return convertResponse(resp), nil
 }
 
+// ChatJSON sends messages with the reply constrained to the schema.
+func (c *vertexAIClient) ChatJSON(ctx context.Context, systemPrompt string, messages []llm.Message, schema llm.Schema) (*llm.Response, error) {
+	config := &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		ResponseJsonSchema: schema.Schema,
+	}
+	if systemPrompt != "" {
+		config.SystemInstruction = &genai.Content{
+			Parts: []*genai.Part{genai.NewPartFromText(systemPrompt)},
+		}
+	}
+
+	resp, err := c.client.Models.GenerateContent(ctx, c.model, convertMessages(messages), config)
+	if err != nil {
+		return nil, fmt.Errorf("generate structured content failed: %w", err)
+	}
+
+	return convertResponse(resp), nil
+}
+
+// ChatStream streams a response from the API and calls onDelta with each
+// chunk of text.
+func (c *vertexAIClient) ChatStream(ctx context.Context, systemPrompt string, messages []llm.Message, onDelta func(string)) (*llm.Response, error) {
+	config := &genai.GenerateContentConfig{}
+	if systemPrompt != "" {
+		config.SystemInstruction = &genai.Content{
+			Parts: []*genai.Part{genai.NewPartFromText(systemPrompt)},
+		}
+	}
+
+	result := &llm.Response{}
+	for resp, err := range c.client.Models.GenerateContentStream(ctx, c.model, convertMessages(messages), config) {
+		if err != nil {
+			return nil, fmt.Errorf("generate content stream failed: %w", err)
+		}
+		chunk := convertResponse(resp)
+		if chunk.Content != "" {
+			result.Content += chunk.Content
+			onDelta(chunk.Content)
+		}
+		if len(resp.Candidates) > 0 && resp.Candidates[0].FinishReason != "" {
+			result.FinishReason = string(resp.Candidates[0].FinishReason)
+		}
+		// Usage is cumulative, so the last chunk's counts are the totals.
+		if resp.UsageMetadata != nil {
+			result.Usage = chunk.Usage
+		}
+	}
+	return result, nil
+}
+
 // ChatWithTools sends messages with tool definitions to the Vertex AI API.
 func (c *vertexAIClient) ChatWithTools(ctx context.Context, systemPrompt string, messages []llm.Message, tools []llm.Tool) (*llm.Response, error) {
 	contents := convertMessagesWithTools(messages)
@@ -233,7 +287,7 @@ func (c *vertexAIClient) Model() string {
 
 // Provider returns the provider name.
 func (c *vertexAIClient) Provider() string {
-	return "vertex-ai"
+	return c.provider
 }
 
 // Close releases resources held by the client.
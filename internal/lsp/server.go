@@ -0,0 +1,480 @@
+// Package lsp implements a subset of the Language Server Protocol over
+// stdio, backed by the knowledge graph: workspace symbols, references from
+// Calls edges, and a custom codeeagle/impact request. It complements the
+// editor's native language servers with cross-language navigation.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/agents"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+const (
+	serverName    = "codeeagle"
+	serverVersion = "1.0.0"
+
+	// maxWorkspaceSymbols caps workspace/symbol results.
+	maxWorkspaceSymbols = 500
+	// impactDepth is how many levels codeeagle/impact follows.
+	impactDepth = 3
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeInvalidParams  = -32602
+	codeMethodNotFound = -32601
+	codeInternalError  = -32603
+)
+
+// request is a JSON-RPC 2.0 request or notification.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // nil for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC 2.0 response. Result is raw so a null result is
+// still sent.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Position is a zero-based line and character offset.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span between two positions.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range in a document.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// SymbolInformation is a workspace/symbol result.
+type SymbolInformation struct {
+	Name          string   `json:"name"`
+	Kind          int      `json:"kind"`
+	Location      Location `json:"location"`
+	ContainerName string   `json:"containerName,omitempty"`
+}
+
+// ImpactedSymbol is one entry of a codeeagle/impact result.
+type ImpactedSymbol struct {
+	Name     string         `json:"name"`
+	Type     graph.NodeType `json:"type"`
+	Level    int            `json:"level"` // 1 = direct dependent
+	Location Location       `json:"location"`
+}
+
+// ImpactResult is the result of a codeeagle/impact request.
+type ImpactResult struct {
+	Symbol   *SymbolInformation `json:"symbol"`
+	Impacted []ImpactedSymbol   `json:"impacted"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position Position `json:"position"`
+}
+
+type referenceParams struct {
+	textDocumentPositionParams
+	Context struct {
+		IncludeDeclaration bool `json:"includeDeclaration"`
+	} `json:"context"`
+}
+
+// symbolKinds maps node types to LSP SymbolKind values. Other node types
+// are not reported as symbols.
+var symbolKinds = map[graph.NodeType]int{
+	graph.NodeFunction:     12,
+	graph.NodeTestFunction: 12,
+	graph.NodeAPIEndpoint:  12,
+	graph.NodeMethod:       6,
+	graph.NodeClass:        5,
+	graph.NodeType_:        5,
+	graph.NodeInterface:    11,
+	graph.NodeEnum:         10,
+	graph.NodeConstant:     14,
+	graph.NodeStruct:       23,
+	graph.NodeDBModel:      23,
+}
+
+// Server answers LSP requests over stdio from the graph.
+type Server struct {
+	store    graph.Store
+	ctxb     *agents.ContextBuilder
+	roots    []string
+	reader   *bufio.Reader
+	writer   io.Writer
+	log      func(format string, args ...any)
+	shutdown bool
+}
+
+// NewServer creates an LSP server that reads from stdin and writes to
+// stdout. repoRoots are the absolute repository paths the graph's relative
+// file paths are resolved against.
+func NewServer(store graph.Store, repoRoots []string) *Server {
+	return NewServerWithIO(store, repoRoots, os.Stdin, os.Stdout)
+}
+
+// NewServerWithIO creates an LSP server with custom I/O (for testing).
+func NewServerWithIO(store graph.Store, repoRoots []string, reader io.Reader, writer io.Writer) *Server {
+	return &Server{
+		store:  store,
+		ctxb:   agents.NewContextBuilder(store, repoRoots...),
+		roots:  repoRoots,
+		reader: bufio.NewReader(reader),
+		writer: writer,
+		log:    func(string, ...any) {},
+	}
+}
+
+// SetLogger sets a function that receives one line per handled request.
+func (s *Server) SetLogger(fn func(format string, args ...any)) {
+	s.log = fn
+}
+
+// Run reads Content-Length framed messages until the client sends exit or
+// closes the stream.
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		body, err := s.readMessage()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.writeError(nil, codeParseError, "Parse error: "+err.Error())
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		s.dispatch(ctx, &req)
+	}
+}
+
+// readMessage reads one message's headers and body.
+func (s *Server) readMessage() ([]byte, error) {
+	length := -1
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && line == "" && length < 0 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("bad Content-Length %q: %w", value, err)
+			}
+			length = n
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("message without Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.reader, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return body, nil
+}
+
+// dispatch routes a request to its handler. Unknown notifications (such as
+// didOpen and $/cancelRequest) are ignored.
+func (s *Server) dispatch(ctx context.Context, req *request) {
+	s.log("lsp: %s", req.Method)
+	if s.shutdown {
+		if req.ID != nil {
+			s.writeError(req.ID, codeInvalidRequest, "server is shut down")
+		}
+		return
+	}
+	var (
+		result any
+		err    error
+	)
+	switch req.Method {
+	case "initialize":
+		result = s.initialize()
+	case "initialized":
+		return
+	case "shutdown":
+		s.shutdown = true
+	case "workspace/symbol":
+		var params struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.writeError(req.ID, codeInvalidParams, "Invalid params: "+err.Error())
+			return
+		}
+		result, err = s.workspaceSymbols(ctx, params.Query)
+	case "textDocument/references":
+		var params referenceParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.writeError(req.ID, codeInvalidParams, "Invalid params: "+err.Error())
+			return
+		}
+		result, err = s.references(ctx, params)
+	case "codeeagle/impact":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			s.writeError(req.ID, codeInvalidParams, "Invalid params: "+err.Error())
+			return
+		}
+		result, err = s.impact(ctx, params)
+	default:
+		if req.ID != nil {
+			s.writeError(req.ID, codeMethodNotFound, "Method not found: "+req.Method)
+		}
+		return
+	}
+	if req.ID == nil {
+		return
+	}
+	if err != nil {
+		s.writeError(req.ID, codeInternalError, err.Error())
+		return
+	}
+	s.writeResult(req.ID, result)
+}
+
+func (s *Server) initialize() any {
+	return map[string]any{
+		"serverInfo": map[string]string{"name": serverName, "version": serverVersion},
+		"capabilities": map[string]any{
+			"workspaceSymbolProvider": true,
+			"referencesProvider":      true,
+			"experimental": map[string]any{
+				"codeeagleImpactProvider": true,
+			},
+		},
+	}
+}
+
+// workspaceSymbols returns symbols whose name contains query, ignoring
+// case, sorted by name and location.
+func (s *Server) workspaceSymbols(ctx context.Context, query string) ([]SymbolInformation, error) {
+	query = strings.ToLower(query)
+	var nodes []*graph.Node
+	for t := range symbolKinds {
+		found, err := s.store.QueryNodes(ctx, graph.NodeFilter{Type: t})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		for _, n := range found {
+			if n.FilePath != "" && strings.Contains(strings.ToLower(n.Name), query) {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Name != nodes[j].Name {
+			return nodes[i].Name < nodes[j].Name
+		}
+		if nodes[i].FilePath != nodes[j].FilePath {
+			return nodes[i].FilePath < nodes[j].FilePath
+		}
+		return nodes[i].Line < nodes[j].Line
+	})
+	if len(nodes) > maxWorkspaceSymbols {
+		nodes = nodes[:maxWorkspaceSymbols]
+	}
+	symbols := make([]SymbolInformation, 0, len(nodes))
+	for _, n := range nodes {
+		symbols = append(symbols, s.symbol(n))
+	}
+	return symbols, nil
+}
+
+// references returns the callers of the function or method at the
+// position, from Calls edges, which include calls across languages and
+// services resolved by the linker.
+func (s *Server) references(ctx context.Context, params referenceParams) ([]Location, error) {
+	locs := []Location{}
+	node, err := s.nodeAt(ctx, params.textDocumentPositionParams, graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction, graph.NodeAPIEndpoint)
+	if err != nil || node == nil {
+		return locs, err
+	}
+	if params.Context.IncludeDeclaration {
+		locs = append(locs, s.location(node))
+	}
+	callers, err := s.store.GetNeighbors(ctx, node.ID, graph.EdgeCalls, graph.Incoming)
+	if err != nil {
+		return nil, fmt.Errorf("get callers of %s: %w", node.ID, err)
+	}
+	consumers, err := s.store.GetNeighbors(ctx, node.ID, graph.EdgeConsumes, graph.Incoming)
+	if err != nil {
+		return nil, fmt.Errorf("get consumers of %s: %w", node.ID, err)
+	}
+	for _, n := range append(callers, consumers...) {
+		if n.FilePath != "" {
+			locs = append(locs, s.location(n))
+		}
+	}
+	return locs, nil
+}
+
+// impact returns what depends on the innermost symbol at the position, as
+// agents.ContextBuilder.ImpactLevels finds it.
+func (s *Server) impact(ctx context.Context, params textDocumentPositionParams) (*ImpactResult, error) {
+	node, err := s.nodeAt(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	res := &ImpactResult{Impacted: []ImpactedSymbol{}}
+	if node == nil {
+		return res, nil
+	}
+	sym := s.symbol(node)
+	res.Symbol = &sym
+	levels, err := s.ctxb.ImpactLevels(ctx, node.ID, impactDepth)
+	if err != nil {
+		return nil, err
+	}
+	for i, nodes := range levels {
+		for _, n := range nodes {
+			if n.FilePath == "" {
+				continue
+			}
+			res.Impacted = append(res.Impacted, ImpactedSymbol{Name: n.Name, Type: n.Type, Level: i + 1, Location: s.location(n)})
+		}
+	}
+	return res, nil
+}
+
+// nodeAt returns the innermost node of the given types (any if none) at a
+// document position, or nil for documents outside the repositories.
+func (s *Server) nodeAt(ctx context.Context, params textDocumentPositionParams, types ...graph.NodeType) (*graph.Node, error) {
+	file, ok := s.relPath(params.TextDocument.URI)
+	if !ok {
+		return nil, nil
+	}
+	return graph.NodeAt(ctx, s.store, file, params.Position.Line+1, types...)
+}
+
+func (s *Server) symbol(n *graph.Node) SymbolInformation {
+	container := n.Properties["class"]
+	if container == "" {
+		container = n.Properties["receiver"]
+	}
+	if container == "" {
+		container = n.Package
+	}
+	return SymbolInformation{Name: n.Name, Kind: symbolKinds[n.Type], Location: s.location(n), ContainerName: container}
+}
+
+// location converts a node's 1-based line span to an LSP location covering
+// its whole lines.
+func (s *Server) location(n *graph.Node) Location {
+	start := max(n.Line-1, 0)
+	end := max(n.EndLine, n.Line, 1)
+	return Location{
+		URI:   s.uri(n.FilePath),
+		Range: Range{Start: Position{Line: start}, End: Position{Line: end}},
+	}
+}
+
+// uri returns the file URI for a graph path, under the first repository
+// root containing it.
+func (s *Server) uri(relPath string) string {
+	abs := relPath
+	if !filepath.IsAbs(relPath) && len(s.roots) > 0 {
+		abs = filepath.Join(s.roots[0], relPath)
+		for _, root := range s.roots {
+			if _, err := os.Stat(filepath.Join(root, relPath)); err == nil {
+				abs = filepath.Join(root, relPath)
+				break
+			}
+		}
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String()
+}
+
+// relPath converts a file URI to the graph's repository-relative path.
+func (s *Server) relPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", false
+	}
+	path := filepath.FromSlash(u.Path)
+	for _, root := range s.roots {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel, true
+		}
+	}
+	return "", false
+}
+
+// writeResult sends a successful response.
+func (s *Server) writeResult(id json.RawMessage, result any) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		s.writeError(id, codeInternalError, "marshal result: "+err.Error())
+		return
+	}
+	s.write(response{JSONRPC: "2.0", ID: id, Result: data})
+}
+
+// writeError sends an error response.
+func (s *Server) writeError(id json.RawMessage, code int, message string) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	s.write(response{JSONRPC: "2.0", ID: id, Error: &responseError{Code: code, Message: message}})
+}
+
+func (s *Server) write(resp response) {
+	data, _ := json.Marshal(resp)
+	fmt.Fprintf(s.writer, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
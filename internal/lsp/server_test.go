@@ -0,0 +1,202 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+const testRoot = "/repo"
+
+func setupTestStore(t *testing.T) graph.Store {
+	t.Helper()
+	ctx := context.Background()
+	store, err := embedded.NewStore(filepath.Join(t.TempDir(), "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	nodes := []*graph.Node{
+		{ID: "save", Type: graph.NodeMethod, Name: "Save", FilePath: "store/repo.go", Line: 10, EndLine: 20, Properties: map[string]string{"receiver": "Repo"}},
+		{ID: "handler", Type: graph.NodeFunction, Name: "handleOrder", FilePath: "api/orders.go", Line: 5, EndLine: 15, Package: "api"},
+		{ID: "client", Type: graph.NodeFunction, Name: "submitOrder", FilePath: "web/src/orders.ts", Line: 3, EndLine: 9},
+		{ID: "orders", Type: graph.NodeStruct, Name: "Order", FilePath: "store/order.go", Line: 1, EndLine: 8},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range []*graph.Edge{
+		{ID: "e1", Type: graph.EdgeCalls, SourceID: "handler", TargetID: "save"},
+		{ID: "e2", Type: graph.EdgeCalls, SourceID: "client", TargetID: "handler"},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func frame(t *testing.T, id int, method string, params any) string {
+	t.Helper()
+	msg := map[string]any{"jsonrpc": "2.0", "method": method}
+	if id > 0 {
+		msg["id"] = id
+	}
+	if params != nil {
+		msg["params"] = params
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+func run(t *testing.T, store graph.Store, messages ...string) []response {
+	t.Helper()
+	var out bytes.Buffer
+	s := NewServerWithIO(store, []string{testRoot}, strings.NewReader(strings.Join(messages, "")), &out)
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var responses []response
+	r := bufio.NewReader(&out)
+	for {
+		reader := &Server{reader: r}
+		body, err := reader.readMessage()
+		if err == io.EOF {
+			return responses
+		}
+		if err != nil {
+			t.Fatalf("read response: %v", err)
+		}
+		var resp response
+		if err := json.Unmarshal(body, &resp); err != nil {
+			t.Fatalf("parse response %s: %v", body, err)
+		}
+		responses = append(responses, resp)
+	}
+}
+
+func position(file string, line int) map[string]any {
+	return map[string]any{
+		"textDocument": map[string]string{"uri": "file://" + testRoot + "/" + file},
+		"position":     map[string]int{"line": line - 1, "character": 4},
+	}
+}
+
+func TestServerLifecycle(t *testing.T) {
+	store := setupTestStore(t)
+	responses := run(t, store,
+		frame(t, 1, "initialize", map[string]any{}),
+		frame(t, 0, "initialized", map[string]any{}),
+		frame(t, 2, "shutdown", nil),
+		frame(t, 3, "workspace/symbol", map[string]string{"query": "x"}),
+		frame(t, 0, "exit", nil),
+		frame(t, 4, "workspace/symbol", map[string]string{"query": "never read"}),
+	)
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3", len(responses))
+	}
+	var init struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	if err := json.Unmarshal(responses[0].Result, &init); err != nil {
+		t.Fatal(err)
+	}
+	if init.Capabilities["workspaceSymbolProvider"] != true || init.Capabilities["referencesProvider"] != true {
+		t.Errorf("capabilities = %v", init.Capabilities)
+	}
+	if string(responses[1].Result) != "null" || responses[1].Error != nil {
+		t.Errorf("shutdown response = %+v, want a null result", responses[1])
+	}
+	if responses[2].Error == nil || responses[2].Error.Code != codeInvalidRequest {
+		t.Errorf("request after shutdown = %+v, want an invalid request error", responses[2])
+	}
+}
+
+func TestWorkspaceSymbol(t *testing.T) {
+	store := setupTestStore(t)
+	responses := run(t, store, frame(t, 1, "workspace/symbol", map[string]string{"query": "ORDER"}))
+	var symbols []SymbolInformation
+	if err := json.Unmarshal(responses[0].Result, &symbols); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, s := range symbols {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "Order,handleOrder,submitOrder" {
+		t.Fatalf("symbols = %s, want Order,handleOrder,submitOrder", got)
+	}
+	if got := symbols[1]; got.Kind != 12 || got.ContainerName != "api" ||
+		got.Location.URI != "file:///repo/api/orders.go" || got.Location.Range.Start.Line != 4 {
+		t.Errorf("handleOrder symbol = %+v", got)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	store := setupTestStore(t)
+	params := position("store/repo.go", 12)
+	params["context"] = map[string]bool{"includeDeclaration": true}
+	responses := run(t, store,
+		frame(t, 1, "textDocument/references", params),
+		frame(t, 2, "textDocument/references", position("store/repo.go", 30)),
+		frame(t, 3, "textDocument/references", position("../elsewhere.go", 1)),
+	)
+	var locs []Location
+	if err := json.Unmarshal(responses[0].Result, &locs); err != nil {
+		t.Fatal(err)
+	}
+	if len(locs) != 2 || locs[0].URI != "file:///repo/store/repo.go" || locs[1].URI != "file:///repo/api/orders.go" {
+		t.Errorf("references = %+v, want the declaration and handleOrder", locs)
+	}
+	for _, resp := range responses[1:] {
+		if string(resp.Result) != "[]" {
+			t.Errorf("references off any symbol = %s, want []", resp.Result)
+		}
+	}
+}
+
+func TestImpact(t *testing.T) {
+	store := setupTestStore(t)
+	responses := run(t, store, frame(t, 1, "codeeagle/impact", position("store/repo.go", 15)))
+	var res ImpactResult
+	if err := json.Unmarshal(responses[0].Result, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Symbol == nil || res.Symbol.Name != "Save" {
+		t.Fatalf("symbol = %+v, want Save", res.Symbol)
+	}
+	var got []string
+	for _, s := range res.Impacted {
+		got = append(got, fmt.Sprintf("%s@%d", s.Name, s.Level))
+	}
+	if strings.Join(got, ",") != "handleOrder@1,submitOrder@2" {
+		t.Errorf("impacted = %v, want handleOrder@1,submitOrder@2", got)
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	store := setupTestStore(t)
+	responses := run(t, store,
+		frame(t, 0, "textDocument/didOpen", map[string]any{}),
+		frame(t, 1, "textDocument/hover", map[string]any{}),
+	)
+	if len(responses) != 1 || responses[0].Error == nil || responses[0].Error.Code != codeMethodNotFound {
+		t.Errorf("responses = %+v, want one method-not-found error", responses)
+	}
+}
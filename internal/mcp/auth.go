@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+)
+
+// Scope is the access a token grants within its namespaces.
+type Scope int
+
+const (
+	// ScopeRead allows queries and graph exports.
+	ScopeRead Scope = iota + 1
+	// ScopeWrite also allows graph imports.
+	ScopeWrite
+)
+
+func (s Scope) String() string {
+	if s == ScopeWrite {
+		return "write"
+	}
+	return "read"
+}
+
+// Grant is the access of one token.
+type Grant struct {
+	// Name identifies the token in the audit log.
+	Name string
+	// Namespaces lists globs of the accessible namespaces; empty allows
+	// only the default namespace.
+	Namespaces []string
+	Scope      Scope
+
+	allNamespaces bool
+}
+
+// openGrant is the access of every request when no tokens are configured.
+var openGrant = Grant{Scope: ScopeWrite, allNamespaces: true}
+
+// Allows reports whether the grant permits scope in namespace.
+func (g Grant) Allows(namespace string, scope Scope) bool {
+	if g.Scope < scope {
+		return false
+	}
+	if g.allNamespaces || (len(g.Namespaces) == 0 && namespace == "") {
+		return true
+	}
+	for _, glob := range g.Namespaces {
+		if ok, _ := path.Match(glob, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Auth checks bearer tokens against the configured grants.
+type Auth struct {
+	secrets []string
+	grants  []Grant
+}
+
+// NewAuth reads the secret of each token from the environment with
+// getenv. It returns nil when no tokens are configured.
+func NewAuth(tokens []config.ServeToken, getenv func(string) string) (*Auth, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	a := &Auth{}
+	for _, tok := range tokens {
+		secret := getenv(tok.TokenEnv)
+		if secret == "" {
+			return nil, fmt.Errorf("serve token %s: environment variable %s is not set", tok.Name, tok.TokenEnv)
+		}
+		for i, s := range a.secrets {
+			if s == secret {
+				return nil, fmt.Errorf("serve tokens %s and %s share a secret", a.grants[i].Name, tok.Name)
+			}
+		}
+		scope := ScopeRead
+		if tok.Scope == "write" {
+			scope = ScopeWrite
+		}
+		a.secrets = append(a.secrets, secret)
+		a.grants = append(a.grants, Grant{Name: tok.Name, Namespaces: tok.Namespaces, Scope: scope})
+	}
+	return a, nil
+}
+
+// Authenticate returns the grant of the request's bearer token. A nil
+// Auth grants every request full access.
+func (a *Auth) Authenticate(r *http.Request) (Grant, bool) {
+	if a == nil {
+		return openGrant, true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return Grant{}, false
+	}
+	for i, s := range a.secrets {
+		if subtle.ConstantTimeCompare([]byte(s), []byte(token)) == 1 {
+			return a.grants[i], true
+		}
+	}
+	return Grant{}, false
+}
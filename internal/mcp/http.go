@@ -0,0 +1,309 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/agents"
+	"github.com/imyousuf/CodeEagle/internal/backstage"
+)
+
+// NamespaceHeader selects the graph namespace of an HTTP request; without
+// it the default namespace is used.
+const NamespaceHeader = "X-CodeEagle-Namespace"
+
+// maxRequestSize bounds JSON-RPC request bodies.
+const maxRequestSize = 10 * 1024 * 1024
+
+// ErrNotFound is returned by HTTPOptions callbacks for a namespace or
+// branch that does not exist.
+var ErrNotFound = errors.New("not found")
+
+// HTTPOptions configures the HTTP transport.
+type HTTPOptions struct {
+	// Registry returns the tools answering queries in a namespace.
+	Registry func(namespace string) (*agents.Registry, error)
+	// Export writes a branch of a namespace as JSON lines (GET
+	// /graph/<branch>); nil disables exports.
+	Export func(ctx context.Context, namespace, branch string, w io.Writer) error
+	// Import replaces a branch of a namespace with JSON lines (PUT
+	// /graph/<branch>); nil disables imports.
+	Import func(ctx context.Context, namespace, branch string, r io.Reader) error
+	// Catalog returns the Backstage catalog entities of a namespace
+	// (/api/catalog/entities); nil disables the catalog API.
+	Catalog func(ctx context.Context, namespace string) ([]backstage.Entity, error)
+	// Auth checks bearer tokens; nil allows every request.
+	Auth *Auth
+	// Audit receives one AuditEntry JSON line per request; nil disables
+	// auditing.
+	Audit io.Writer
+}
+
+// AuditEntry records one HTTP request, allowed or not.
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Remote    string    `json:"remote"`
+	Token     string    `json:"token,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	// Action is the JSON-RPC method, "export", "import" or "catalog".
+	Action     string         `json:"action"`
+	Tool       string         `json:"tool,omitempty"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Branch     string         `json:"branch,omitempty"`
+	Status     int            `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+}
+
+type httpHandler struct {
+	opts    HTTPOptions
+	auditMu sync.Mutex
+}
+
+// NewHTTPHandler serves MCP over HTTP: JSON-RPC requests are POSTed to
+// /mcp, one per request, and graph branches are exported and imported at
+// /graph/<branch>. The graph's services, APIs and stores are also served
+// as Backstage catalog entities under /api/catalog, so a developer portal
+// can read them as it reads its own catalog backend. Requests are
+// authenticated with bearer tokens and limited to the namespaces and scope
+// of their token.
+func NewHTTPHandler(opts HTTPOptions) http.Handler {
+	h := &httpHandler{opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /mcp", h.handleMCP)
+	mux.HandleFunc("GET /graph/{branch...}", h.handleExport)
+	mux.HandleFunc("PUT /graph/{branch...}", h.handleImport)
+	mux.HandleFunc("GET /api/catalog/entities", h.handleCatalog)
+	mux.HandleFunc("GET /api/catalog/entities/by-name/{kind}/{namespace}/{name}", h.handleCatalogEntity)
+	mux.HandleFunc("POST /api/catalog/entities/by-refs", h.handleCatalogRefs)
+	return mux
+}
+
+// authorize authenticates the request and checks its token allows scope
+// in the request's namespace, writing the HTTP error when it does not.
+func (h *httpHandler) authorize(w http.ResponseWriter, r *http.Request, entry *AuditEntry, scope Scope) bool {
+	grant, ok := h.opts.Auth.Authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="codeeagle"`)
+		h.fail(w, entry, http.StatusUnauthorized, errors.New("missing or unknown bearer token"))
+		return false
+	}
+	entry.Token = grant.Name
+	if !grant.Allows(entry.Namespace, scope) {
+		h.fail(w, entry, http.StatusForbidden, errors.New("token has no "+scope.String()+" access to this namespace"))
+		return false
+	}
+	return true
+}
+
+func (h *httpHandler) handleMCP(w http.ResponseWriter, r *http.Request) {
+	entry := newAuditEntry(r, "mcp")
+	if !h.authorize(w, r, entry, ScopeRead) {
+		return
+	}
+
+	var req jsonRPCRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&req); err != nil {
+		h.fail(w, entry, http.StatusBadRequest, errors.New("parse error: "+err.Error()))
+		return
+	}
+	entry.Action = req.Method
+	if req.Method == "tools/call" {
+		var params toolCallParams
+		if json.Unmarshal(req.Params, &params) == nil {
+			entry.Tool, entry.Arguments = params.Name, params.Arguments
+		}
+	}
+
+	registry, err := h.opts.Registry(entry.Namespace)
+	if err != nil {
+		h.fail(w, entry, errorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	var buf bytes.Buffer
+	(&Server{registry: registry, writer: &buf}).dispatch(r.Context(), &req)
+	if buf.Len() == 0 {
+		// Notifications have no response.
+		w.WriteHeader(http.StatusAccepted)
+		h.audit(entry, http.StatusAccepted, nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(buf.Bytes())
+	h.audit(entry, http.StatusOK, nil)
+}
+
+func (h *httpHandler) handleExport(w http.ResponseWriter, r *http.Request) {
+	entry := newAuditEntry(r, "export")
+	if !h.authorize(w, r, entry, ScopeRead) {
+		return
+	}
+	if h.opts.Export == nil {
+		h.fail(w, entry, http.StatusNotFound, errors.New("graph export is disabled"))
+		return
+	}
+	// Buffer the export so a failure can still be reported as an error.
+	var buf bytes.Buffer
+	if err := h.opts.Export(r.Context(), entry.Namespace, entry.Branch, &buf); err != nil {
+		h.fail(w, entry, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	_, _ = w.Write(buf.Bytes())
+	h.audit(entry, http.StatusOK, nil)
+}
+
+func (h *httpHandler) handleImport(w http.ResponseWriter, r *http.Request) {
+	entry := newAuditEntry(r, "import")
+	if !h.authorize(w, r, entry, ScopeWrite) {
+		return
+	}
+	if h.opts.Import == nil {
+		h.fail(w, entry, http.StatusNotFound, errors.New("graph import is disabled"))
+		return
+	}
+	if err := h.opts.Import(r.Context(), entry.Namespace, entry.Branch, r.Body); err != nil {
+		h.fail(w, entry, errorStatus(err, http.StatusInternalServerError), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.audit(entry, http.StatusNoContent, nil)
+}
+
+// catalog returns the catalog entities of the request's namespace,
+// writing the HTTP error when it cannot.
+func (h *httpHandler) catalog(w http.ResponseWriter, r *http.Request, entry *AuditEntry) ([]backstage.Entity, bool) {
+	if !h.authorize(w, r, entry, ScopeRead) {
+		return nil, false
+	}
+	if h.opts.Catalog == nil {
+		h.fail(w, entry, http.StatusNotFound, errors.New("catalog API is disabled"))
+		return nil, false
+	}
+	entities, err := h.opts.Catalog(r.Context(), entry.Namespace)
+	if err != nil {
+		h.fail(w, entry, errorStatus(err, http.StatusInternalServerError), err)
+		return nil, false
+	}
+	return entities, true
+}
+
+// handleCatalog lists the catalog entities matching the request's filter
+// parameters, as GET /api/catalog/entities of the Backstage catalog does.
+func (h *httpHandler) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	entry := newAuditEntry(r, "catalog")
+	entities, ok := h.catalog(w, r, entry)
+	if !ok {
+		return
+	}
+	entities = backstage.Filter(entities, r.URL.Query()["filter"])
+	if entities == nil {
+		entities = []backstage.Entity{}
+	}
+	h.writeJSON(w, entry, entities)
+}
+
+// handleCatalogEntity returns one catalog entity by kind, namespace and
+// name.
+func (h *httpHandler) handleCatalogEntity(w http.ResponseWriter, r *http.Request) {
+	entry := newAuditEntry(r, "catalog")
+	entities, ok := h.catalog(w, r, entry)
+	if !ok {
+		return
+	}
+	e := backstage.Find(entities, r.PathValue("kind"), r.PathValue("namespace"), r.PathValue("name"))
+	if e == nil {
+		h.fail(w, entry, http.StatusNotFound, errors.New("entity not found"))
+		return
+	}
+	h.writeJSON(w, entry, e)
+}
+
+// handleCatalogRefs returns the entities of a list of entity references,
+// null for unknown ones, as the catalog-graph plugin requests them.
+func (h *httpHandler) handleCatalogRefs(w http.ResponseWriter, r *http.Request) {
+	entry := newAuditEntry(r, "catalog")
+	entities, ok := h.catalog(w, r, entry)
+	if !ok {
+		return
+	}
+	var req struct {
+		EntityRefs []string `json:"entityRefs"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&req); err != nil {
+		h.fail(w, entry, http.StatusBadRequest, errors.New("parse error: "+err.Error()))
+		return
+	}
+	items := make([]*backstage.Entity, len(req.EntityRefs))
+	for i, ref := range req.EntityRefs {
+		kind, rest, _ := strings.Cut(ref, ":")
+		namespace, name, found := strings.Cut(rest, "/")
+		if !found {
+			namespace, name = backstage.DefaultNamespace, rest
+		}
+		items[i] = backstage.Find(entities, kind, namespace, name)
+	}
+	h.writeJSON(w, entry, map[string]any{"items": items})
+}
+
+// writeJSON writes v as the JSON response and audits the request.
+func (h *httpHandler) writeJSON(w http.ResponseWriter, entry *AuditEntry, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		h.fail(w, entry, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+	h.audit(entry, http.StatusOK, nil)
+}
+
+func newAuditEntry(r *http.Request, action string) *AuditEntry {
+	return &AuditEntry{
+		Time:      time.Now().UTC(),
+		Remote:    r.RemoteAddr,
+		Namespace: r.Header.Get(NamespaceHeader),
+		Action:    action,
+		Branch:    r.PathValue("branch"),
+	}
+}
+
+// errorStatus maps ErrNotFound to 404 and other errors to status.
+func errorStatus(err error, status int) int {
+	if errors.Is(err, ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return status
+}
+
+// fail writes an HTTP error and audits it.
+func (h *httpHandler) fail(w http.ResponseWriter, entry *AuditEntry, status int, err error) {
+	http.Error(w, err.Error(), status)
+	h.audit(entry, status, err)
+}
+
+// audit writes the entry to the audit log, if any.
+func (h *httpHandler) audit(entry *AuditEntry, status int, err error) {
+	if h.opts.Audit == nil {
+		return
+	}
+	entry.Status = status
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	entry.DurationMS = time.Since(entry.Time).Milliseconds()
+	data, mErr := json.Marshal(entry)
+	if mErr != nil {
+		return
+	}
+	h.auditMu.Lock()
+	defer h.auditMu.Unlock()
+	_, _ = h.opts.Audit.Write(append(data, '\n'))
+}
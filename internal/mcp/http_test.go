@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/agents"
+	"github.com/imyousuf/CodeEagle/internal/backstage"
+	"github.com/imyousuf/CodeEagle/internal/config"
+)
+
+func TestGrantAllows(t *testing.T) {
+	tests := []struct {
+		name      string
+		grant     Grant
+		namespace string
+		scope     Scope
+		want      bool
+	}{
+		{"default namespace only", Grant{Scope: ScopeRead}, "", ScopeRead, true},
+		{"other namespace denied", Grant{Scope: ScopeRead}, "team-a", ScopeRead, false},
+		{"glob", Grant{Namespaces: []string{"team-*"}, Scope: ScopeRead}, "team-a", ScopeRead, true},
+		{"glob stops at slash", Grant{Namespaces: []string{"org/*"}, Scope: ScopeRead}, "org/pay/api", ScopeRead, false},
+		{"read cannot write", Grant{Namespaces: []string{"team-a"}, Scope: ScopeRead}, "team-a", ScopeWrite, false},
+		{"write can read", Grant{Namespaces: []string{"team-a"}, Scope: ScopeWrite}, "team-a", ScopeRead, true},
+		{"open", openGrant, "anything", ScopeWrite, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.grant.Allows(tt.namespace, tt.scope); got != tt.want {
+				t.Errorf("Allows(%q, %v) = %v, want %v", tt.namespace, tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAuth(t *testing.T) {
+	env := map[string]string{"READ_TOKEN": "r-secret", "WRITE_TOKEN": "w-secret"}
+	getenv := func(k string) string { return env[k] }
+
+	if a, err := NewAuth(nil, getenv); a != nil || err != nil {
+		t.Errorf("NewAuth without tokens = %v, %v; want nil", a, err)
+	}
+	if _, err := NewAuth([]config.ServeToken{{Name: "ci", TokenEnv: "MISSING"}}, getenv); err == nil || !strings.Contains(err.Error(), "MISSING is not set") {
+		t.Errorf("NewAuth with unset variable error = %v", err)
+	}
+	if _, err := NewAuth([]config.ServeToken{{Name: "a", TokenEnv: "READ_TOKEN"}, {Name: "b", TokenEnv: "READ_TOKEN"}}, getenv); err == nil {
+		t.Error("NewAuth accepted two tokens with one secret")
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	env := map[string]string{"READ_TOKEN": "r-secret", "WRITE_TOKEN": "w-secret"}
+	auth, err := NewAuth([]config.ServeToken{
+		{Name: "reader", TokenEnv: "READ_TOKEN", Namespaces: []string{"team-*"}},
+		{Name: "ci", TokenEnv: "WRITE_TOKEN", Namespaces: []string{"team-a"}, Scope: "write"},
+	}, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("NewAuth: %v", err)
+	}
+
+	imported := map[string]string{}
+	var audit bytes.Buffer
+	srv := httptest.NewServer(NewHTTPHandler(HTTPOptions{
+		Registry: func(string) (*agents.Registry, error) { return setupTestRegistry(), nil },
+		Export: func(_ context.Context, ns, branch string, w io.Writer) error {
+			data, ok := imported[ns+"/"+branch]
+			if !ok {
+				return fmt.Errorf("branch %q: %w", branch, ErrNotFound)
+			}
+			_, err := io.WriteString(w, data)
+			return err
+		},
+		Import: func(_ context.Context, ns, branch string, r io.Reader) error {
+			data, err := io.ReadAll(r)
+			imported[ns+"/"+branch] = string(data)
+			return err
+		},
+		Catalog: func(_ context.Context, ns string) ([]backstage.Entity, error) {
+			return []backstage.Entity{
+				{APIVersion: backstage.APIVersion, Kind: backstage.KindComponent, Metadata: backstage.Metadata{Name: "orders", Namespace: "default"}, Spec: map[string]any{"type": "service"}},
+				{APIVersion: backstage.APIVersion, Kind: backstage.KindAPI, Metadata: backstage.Metadata{Name: "orders", Namespace: "default"}, Spec: map[string]any{"type": "openapi"}},
+			}, nil
+		},
+		Auth:  auth,
+		Audit: &audit,
+	}))
+	defer srv.Close()
+
+	do := func(method, path, token, namespace, body string) (int, string) {
+		t.Helper()
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if namespace != "" {
+			req.Header.Set(NamespaceHeader, namespace)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	call := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_nodes","arguments":{"q":"Pay"}}}`
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		namespace  string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"no token", "POST", "/mcp", "", "team-a", call, http.StatusUnauthorized, ""},
+		{"unknown token", "POST", "/mcp", "guess", "team-a", call, http.StatusUnauthorized, ""},
+		{"namespace not granted", "POST", "/mcp", "r-secret", "", call, http.StatusForbidden, "no read access"},
+		{"tool call", "POST", "/mcp", "r-secret", "team-b", call, http.StatusOK, "found 5 nodes"},
+		{"notification", "POST", "/mcp", "r-secret", "team-b", `{"jsonrpc":"2.0","method":"initialized"}`, http.StatusAccepted, ""},
+		{"read token cannot import", "PUT", "/graph/main", "r-secret", "team-a", "{}\n", http.StatusForbidden, "no write access"},
+		{"import", "PUT", "/graph/feature/x", "w-secret", "team-a", "{\"kind\":\"node\"}\n", http.StatusNoContent, ""},
+		{"export", "GET", "/graph/feature/x", "r-secret", "team-a", "", http.StatusOK, `{"kind":"node"}`},
+		{"export missing branch", "GET", "/graph/main", "w-secret", "team-a", "", http.StatusNotFound, "not found"},
+		{"catalog without token", "GET", "/api/catalog/entities", "", "team-a", "", http.StatusUnauthorized, ""},
+		{"catalog filter", "GET", "/api/catalog/entities?filter=kind=api", "r-secret", "team-a", "", http.StatusOK, `"kind":"API"`},
+		{"catalog entity", "GET", "/api/catalog/entities/by-name/component/default/orders", "r-secret", "team-a", "", http.StatusOK, `"type":"service"`},
+		{"catalog unknown entity", "GET", "/api/catalog/entities/by-name/component/default/billing", "r-secret", "team-a", "", http.StatusNotFound, "entity not found"},
+		{"catalog by refs", "POST", "/api/catalog/entities/by-refs", "r-secret", "team-a", `{"entityRefs":["api:default/orders","component:billing"]}`, http.StatusOK, `"spec":{"type":"openapi"},"relations":null},null]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(tt.method, tt.path, tt.token, tt.namespace, tt.body)
+			if status != tt.wantStatus || !strings.Contains(body, tt.wantBody) {
+				t.Errorf("%s %s = %d %q, want %d containing %q", tt.method, tt.path, status, body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+
+	var entries []AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(audit.String()), "\n") {
+		var e AuditEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("audit line %q: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != len(tests) {
+		t.Fatalf("audit entries = %d, want %d", len(entries), len(tests))
+	}
+	if e := entries[3]; e.Token != "reader" || e.Namespace != "team-b" || e.Action != "tools/call" || e.Tool != "search_nodes" || e.Arguments["q"] != "Pay" || e.Status != http.StatusOK {
+		t.Errorf("tool call audit entry = %+v", e)
+	}
+	if e := entries[0]; e.Status != http.StatusUnauthorized || e.Token != "" || e.Error == "" {
+		t.Errorf("unauthorized audit entry = %+v", e)
+	}
+	if e := entries[6]; e.Action != "import" || e.Branch != "feature/x" || e.Token != "ci" {
+		t.Errorf("import audit entry = %+v", e)
+	}
+	if e := entries[10]; e.Action != "catalog" || e.Token != "reader" || e.Status != http.StatusOK {
+		t.Errorf("catalog audit entry = %+v", e)
+	}
+}
@@ -1,4 +1,4 @@
-// Package mcp implements a JSON-RPC 2.0 over stdio MCP server
+// Package mcp implements a JSON-RPC 2.0 MCP server, over stdio or HTTP,
 // that exposes CodeEagle tools to Claude CLI and other MCP clients.
 package mcp
 
@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+)
+
+// Notifier delivers events to the configured subscriptions.
+type Notifier struct {
+	subs   []config.NotifySubscription
+	dir    string
+	client *http.Client
+}
+
+// New returns a notifier for the subscriptions, or nil when there are
+// none. Relative NDJSON file paths are resolved against dir.
+func New(cfg config.NotifyConfig, dir string) (*Notifier, error) {
+	if len(cfg.Subscriptions) == 0 {
+		return nil, nil
+	}
+	for _, sub := range cfg.Subscriptions {
+		for _, t := range sub.Events {
+			if !slices.Contains(EventTypes, t) {
+				return nil, fmt.Errorf("notify subscription %s: unknown event %q (want one of %s)",
+					subscriptionName(sub), t, strings.Join(EventTypes, ", "))
+			}
+		}
+	}
+	return &Notifier{subs: cfg.Subscriptions, dir: dir, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func subscriptionName(sub config.NotifySubscription) string {
+	switch {
+	case sub.Name != "":
+		return sub.Name
+	case sub.Webhook != "":
+		return sub.Webhook
+	}
+	return sub.File
+}
+
+// Notify delivers the events each subscription matches. Every
+// subscription is attempted; the errors of those that failed are joined.
+func (n *Notifier) Notify(ctx context.Context, events []Event) error {
+	var errs []error
+	for _, sub := range n.subs {
+		matched := matching(sub, events)
+		if len(matched) == 0 {
+			continue
+		}
+		if sub.File != "" {
+			if err := n.appendFile(sub.File, matched); err != nil {
+				errs = append(errs, fmt.Errorf("notify %s: %w", subscriptionName(sub), err))
+			}
+		}
+		if sub.Webhook != "" {
+			if err := n.post(ctx, sub, matched); err != nil {
+				errs = append(errs, fmt.Errorf("notify %s: %w", subscriptionName(sub), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// matching returns the events of the subscription's types and services.
+func matching(sub config.NotifySubscription, events []Event) []Event {
+	var out []Event
+	for _, e := range events {
+		if len(sub.Events) > 0 && !slices.Contains(sub.Events, e.Type) {
+			continue
+		}
+		if len(sub.Services) > 0 && !matchesService(sub.Services, e) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// matchesService reports whether either service of the event matches one
+// of the globs, so a subscription to a service also sees new dependents.
+func matchesService(globs []string, e Event) bool {
+	for _, g := range globs {
+		for _, svc := range []string{e.Service, e.DependsOn} {
+			if ok, _ := filepath.Match(g, svc); ok && svc != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// appendFile writes the events to an NDJSON file, one per line.
+func (n *Notifier) appendFile(path string, events []Event) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(n.dir, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create event directory: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("marshal event: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open event file: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("write event file: %w", err)
+	}
+	return f.Close()
+}
+
+// post sends the events to the subscription's webhook.
+func (n *Notifier) post(ctx context.Context, sub config.NotifySubscription, events []Event) error {
+	var body any = struct {
+		Events []Event `json:"events"`
+	}{events}
+	if sub.Format == "slack" {
+		body = struct {
+			Text string `json:"text"`
+		}{slackText(events)}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Webhook, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook error (HTTP %d): %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// slackText formats events as a Slack message.
+func slackText(events []Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CodeEagle: %d architecture change(s)", len(events))
+	if branch := events[0].Branch; branch != "" {
+		fmt.Fprintf(&b, " on %s", branch)
+	}
+	b.WriteString("\n")
+	for _, e := range events {
+		fmt.Fprintf(&b, "• %s\n", e.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,205 @@
+// Package notify detects architectural changes between two states of the
+// knowledge graph (new service dependencies, new endpoints, endpoints
+// without tests) and delivers them to subscribers as webhook calls or
+// NDJSON event streams.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// Event types.
+const (
+	EventDependencyAdded   = "service_dependency_added"
+	EventDependencyRemoved = "service_dependency_removed"
+	EventEndpointAdded     = "endpoint_added"
+	EventEndpointRemoved   = "endpoint_removed"
+	// EventUntestedEndpoint is sent for a new endpoint without tests, and
+	// for an endpoint whose tests were removed.
+	EventUntestedEndpoint = "untested_endpoint"
+)
+
+// EventTypes lists every event type.
+var EventTypes = []string{EventDependencyAdded, EventDependencyRemoved, EventEndpointAdded, EventEndpointRemoved, EventUntestedEndpoint}
+
+// Event is a change to the graph.
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// Branch is the graph branch the change was indexed on.
+	Branch  string `json:"branch,omitempty"`
+	Service string `json:"service"`
+	// DependsOn is the other service of a dependency event.
+	DependsOn string `json:"depends_on,omitempty"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Endpoint is an API endpoint as recorded in a snapshot.
+type Endpoint struct {
+	Name    string
+	Service string
+	File    string
+	Line    int
+	Tested  bool
+}
+
+// Snapshot is the part of the graph events are derived from.
+type Snapshot struct {
+	// Empty is set when the graph had no nodes, as before a first index.
+	Empty bool
+	// Dependencies holds the [dependent, dependency] service pairs.
+	Dependencies map[[2]string]bool
+	// Endpoints is keyed by node ID.
+	Endpoints map[string]Endpoint
+}
+
+// TestedFunc reports whether an endpoint is covered by tests.
+type TestedFunc func(ctx context.Context, store graph.Store, ep *graph.Node) (bool, error)
+
+// Capture records the service dependencies and endpoints in the graph.
+// Services are identified by group (see linker.ServiceGroup), as several
+// manifests in one top-level directory describe the same service.
+func Capture(ctx context.Context, store graph.Store, tested TestedFunc) (*Snapshot, error) {
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("graph stats: %w", err)
+	}
+	snap := &Snapshot{
+		Empty:        stats.NodeCount == 0,
+		Dependencies: make(map[[2]string]bool),
+		Endpoints:    make(map[string]Endpoint),
+	}
+
+	services, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query services: %w", err)
+	}
+	groupOf := make(map[string]string, len(services))
+	for _, svc := range services {
+		groupOf[svc.ID] = serviceGroup(svc)
+	}
+	for _, svc := range services {
+		edges, err := store.GetEdges(ctx, svc.ID, graph.EdgeDependsOn)
+		if err != nil {
+			return nil, fmt.Errorf("get dependencies of %s: %w", svc.Name, err)
+		}
+		for _, e := range edges {
+			from, to := groupOf[e.SourceID], groupOf[e.TargetID]
+			if e.SourceID != svc.ID || to == "" || from == to {
+				continue
+			}
+			snap.Dependencies[[2]string{from, to}] = true
+		}
+	}
+
+	endpoints, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return nil, fmt.Errorf("query endpoints: %w", err)
+	}
+	for _, ep := range endpoints {
+		ok, err := tested(ctx, store, ep)
+		if err != nil {
+			return nil, err
+		}
+		snap.Endpoints[ep.ID] = Endpoint{
+			Name:    ep.Name,
+			Service: linker.ServiceGroup(ep.FilePath),
+			File:    ep.FilePath,
+			Line:    ep.Line,
+			Tested:  ok,
+		}
+	}
+	return snap, nil
+}
+
+func serviceGroup(svc *graph.Node) string {
+	if svc.FilePath != "" {
+		return linker.ServiceGroup(svc.FilePath)
+	}
+	return svc.Name
+}
+
+// Diff returns the events that turn prev into cur, ordered by type and
+// then by service. There are none when prev is nil or empty, so indexing
+// a new graph does not report everything in it.
+func Diff(prev, cur *Snapshot, now time.Time) []Event {
+	if prev == nil || prev.Empty || cur == nil {
+		return nil
+	}
+	var events []Event
+	for pair := range cur.Dependencies {
+		if !prev.Dependencies[pair] {
+			events = append(events, Event{Type: EventDependencyAdded, Service: pair[0], DependsOn: pair[1],
+				Message: fmt.Sprintf("service %s now depends on %s", pair[0], pair[1])})
+		}
+	}
+	for pair := range prev.Dependencies {
+		if !cur.Dependencies[pair] {
+			events = append(events, Event{Type: EventDependencyRemoved, Service: pair[0], DependsOn: pair[1],
+				Message: fmt.Sprintf("service %s no longer depends on %s", pair[0], pair[1])})
+		}
+	}
+	for id, ep := range cur.Endpoints {
+		old, existed := prev.Endpoints[id]
+		if !existed {
+			events = append(events, endpointEvent(EventEndpointAdded, ep, "new endpoint %s in %s (%s)"))
+		}
+		if !ep.Tested && (!existed || old.Tested) {
+			events = append(events, endpointEvent(EventUntestedEndpoint, ep, "endpoint %s in %s has no tests (%s)"))
+		}
+	}
+	for id, ep := range prev.Endpoints {
+		if _, ok := cur.Endpoints[id]; !ok {
+			events = append(events, endpointEvent(EventEndpointRemoved, ep, "endpoint %s removed from %s (%s)"))
+		}
+	}
+
+	order := make(map[string]int, len(EventTypes))
+	for i, t := range EventTypes {
+		order[t] = i
+	}
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.Type != b.Type {
+			return order[a.Type] < order[b.Type]
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.DependsOn != b.DependsOn {
+			return a.DependsOn < b.DependsOn
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	for i := range events {
+		events[i].Time = now
+	}
+	return events
+}
+
+func endpointEvent(typ string, ep Endpoint, format string) Event {
+	loc := ep.File
+	if ep.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", ep.File, ep.Line)
+	}
+	return Event{
+		Type:     typ,
+		Service:  ep.Service,
+		Endpoint: ep.Name,
+		File:     ep.File,
+		Line:     ep.Line,
+		Message:  fmt.Sprintf(format, ep.Name, ep.Service, loc),
+	}
+}
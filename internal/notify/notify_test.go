@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/config"
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestCapture(t *testing.T) {
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+	for _, n := range []*graph.Node{
+		{ID: "svc-orders", Type: graph.NodeService, Name: "orders", FilePath: "orders/go.mod"},
+		{ID: "svc-orders-npm", Type: graph.NodeService, Name: "orders-web", FilePath: "orders/package.json"},
+		{ID: "svc-pay", Type: graph.NodeService, Name: "payments", FilePath: "payments/go.mod"},
+		{ID: "ep", Type: graph.NodeAPIEndpoint, Name: "POST /orders", FilePath: "orders/api.go", Line: 7},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	for _, e := range []*graph.Edge{
+		{ID: "d1", Type: graph.EdgeDependsOn, SourceID: "svc-orders", TargetID: "svc-pay"},
+		{ID: "d2", Type: graph.EdgeDependsOn, SourceID: "svc-orders", TargetID: "svc-orders-npm"},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+
+	tested := func(context.Context, graph.Store, *graph.Node) (bool, error) { return true, nil }
+	snap, err := Capture(ctx, store, tested)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if snap.Empty {
+		t.Error("snapshot of a populated graph is empty")
+	}
+	if want := map[[2]string]bool{{"orders", "payments"}: true}; !reflect.DeepEqual(snap.Dependencies, want) {
+		t.Errorf("dependencies = %v, want %v", snap.Dependencies, want)
+	}
+	if want := (Endpoint{Name: "POST /orders", Service: "orders", File: "orders/api.go", Line: 7, Tested: true}); snap.Endpoints["ep"] != want {
+		t.Errorf("endpoint = %+v, want %+v", snap.Endpoints["ep"], want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	prev := &Snapshot{
+		Dependencies: map[[2]string]bool{{"orders", "payments"}: true, {"orders", "legacy"}: true},
+		Endpoints: map[string]Endpoint{
+			"ep-list": {Name: "GET /orders", Service: "orders", File: "orders/api.go", Line: 3, Tested: true},
+			"ep-old":  {Name: "GET /v1/orders", Service: "orders", File: "orders/v1.go", Line: 9},
+		},
+	}
+	cur := &Snapshot{
+		Dependencies: map[[2]string]bool{{"orders", "payments"}: true, {"orders", "users"}: true},
+		Endpoints: map[string]Endpoint{
+			"ep-list":   {Name: "GET /orders", Service: "orders", File: "orders/api.go", Line: 3},
+			"ep-create": {Name: "POST /orders", Service: "orders", File: "orders/api.go", Line: 12},
+		},
+	}
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	var got []string
+	for _, e := range Diff(prev, cur, now) {
+		if !e.Time.Equal(now) {
+			t.Errorf("event time = %v, want %v", e.Time, now)
+		}
+		got = append(got, e.Type+": "+e.Message)
+	}
+	want := []string{
+		"service_dependency_added: service orders now depends on users",
+		"service_dependency_removed: service orders no longer depends on legacy",
+		"endpoint_added: new endpoint POST /orders in orders (orders/api.go:12)",
+		"endpoint_removed: endpoint GET /v1/orders removed from orders (orders/v1.go:9)",
+		"untested_endpoint: endpoint GET /orders in orders has no tests (orders/api.go:3)",
+		"untested_endpoint: endpoint POST /orders in orders has no tests (orders/api.go:12)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if events := Diff(&Snapshot{Empty: true}, cur, now); events != nil {
+		t.Errorf("Diff from an empty graph = %v, want none", events)
+	}
+	if events := Diff(cur, cur, now); len(events) != 0 {
+		t.Errorf("Diff of unchanged snapshots = %v, want none", events)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if strings.Contains(r.URL.Path, "fail") {
+			http.Error(w, "no such hook", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	n, err := New(config.NotifyConfig{Subscriptions: []config.NotifySubscription{
+		{Name: "slack", Webhook: srv.URL + "/slack", Format: "slack", Events: []string{EventDependencyAdded}},
+		{Name: "payments", Webhook: srv.URL + "/json", Services: []string{"pay*"}},
+		{Name: "stream", File: "events/graph.ndjson"},
+		{Name: "broken", Webhook: srv.URL + "/fail", Events: []string{EventEndpointAdded}},
+	}}, dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	events := []Event{
+		{Type: EventDependencyAdded, Branch: "main", Service: "orders", DependsOn: "payments", Message: "service orders now depends on payments"},
+		{Type: EventEndpointAdded, Branch: "main", Service: "orders", Endpoint: "POST /orders", Message: "new endpoint POST /orders"},
+	}
+	err = n.Notify(context.Background(), events)
+	if err == nil || !strings.Contains(err.Error(), "notify broken") || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("Notify error = %v, want the broken webhook's 404", err)
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("webhook calls = %d, want 3: %v", len(bodies), bodies)
+	}
+	var slack struct{ Text string }
+	if err := json.Unmarshal([]byte(bodies[0]), &slack); err != nil || slack.Text != "CodeEagle: 1 architecture change(s) on main\n• service orders now depends on payments\n" {
+		t.Errorf("slack payload = %s (%v)", bodies[0], err)
+	}
+	var payload struct{ Events []Event }
+	if err := json.Unmarshal([]byte(bodies[1]), &payload); err != nil || len(payload.Events) != 1 || payload.Events[0].DependsOn != "payments" {
+		t.Errorf("json payload = %s (%v), want only the payments dependency", bodies[1], err)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "events", "graph.ndjson"))
+	if err != nil {
+		t.Fatalf("open event file: %v", err)
+	}
+	defer f.Close()
+	var types []string
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("event line %q: %v", sc.Text(), err)
+		}
+		types = append(types, e.Type)
+	}
+	if !reflect.DeepEqual(types, []string{EventDependencyAdded, EventEndpointAdded}) {
+		t.Errorf("event file types = %v", types)
+	}
+
+	if _, err := New(config.NotifyConfig{Subscriptions: []config.NotifySubscription{{File: "x", Events: []string{"endpoint_changed"}}}}, dir); err == nil {
+		t.Error("New accepted an unknown event type")
+	}
+	if n, err := New(config.NotifyConfig{}, dir); n != nil || err != nil {
+		t.Errorf("New without subscriptions = %v, %v; want nil", n, err)
+	}
+}
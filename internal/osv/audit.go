@@ -0,0 +1,245 @@
+package osv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Finding is a vulnerability affecting one manifest dependency.
+type Finding struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Severity  string   `json:"severity"`
+	Summary   string   `json:"summary,omitempty"`
+	Package   string   `json:"package"`
+	Ecosystem string   `json:"ecosystem"`
+	Version   string   `json:"version"`
+	FixedIn   string   `json:"fixed_in,omitempty"`
+	Manifest  string   `json:"manifest"`
+	Services  []string `json:"services,omitempty"`
+	// ImportedBy lists the source files importing the package. A finding
+	// with no importers is declared but not reachable from indexed code.
+	ImportedBy []string `json:"imported_by,omitempty"`
+}
+
+// Reachable reports whether indexed code imports the vulnerable package.
+func (f *Finding) Reachable() bool {
+	return len(f.ImportedBy) > 0
+}
+
+// Result summarizes an audit run.
+type Result struct {
+	Packages int       `json:"packages"` // distinct package versions looked up
+	Skipped  int       `json:"skipped"`  // dependencies without a concrete version
+	Findings []Finding `json:"findings"` // ranked: reachable first, then by severity
+}
+
+// severityRank orders OSV/GHSA severities, most severe first.
+var severityRank = map[string]int{"critical": 0, "high": 1, "moderate": 2, "medium": 2, "low": 3}
+
+// Audit looks up every manifest dependency in src and records the results in
+// the graph: a Vulnerability node per advisory with Affects edges to the
+// dependency and to the services declaring it. Vulnerability nodes from a
+// previous audit are replaced.
+func Audit(ctx context.Context, store graph.Store, src Source) (*Result, error) {
+	deps, err := store.QueryNodes(ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Properties: map[string]string{"kind": "manifest_dep"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query dependencies: %w", err)
+	}
+
+	res := &Result{}
+	var pkgs []Package
+	pkgIndex := make(map[Package]int)
+	depPkg := make(map[*graph.Node]Package)
+	for _, dep := range deps {
+		if dep.Properties["internal"] == "true" {
+			continue // workspace package, not published
+		}
+		eco := Ecosystem(dep.Properties["ecosystem"])
+		version := CleanVersion(dep.Properties["version"])
+		if eco == "" || version == "" {
+			res.Skipped++
+			continue
+		}
+		p := Package{Name: dep.Name, Ecosystem: eco, Version: version}
+		if eco == "PyPI" {
+			p.Name = NormalizeName(eco, p.Name)
+		}
+		depPkg[dep] = p
+		if _, ok := pkgIndex[p]; !ok {
+			pkgIndex[p] = len(pkgs)
+			pkgs = append(pkgs, p)
+		}
+	}
+	res.Packages = len(pkgs)
+
+	vulns, err := src.Query(ctx, pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeVulnerability})
+	if err != nil {
+		return nil, fmt.Errorf("query vulnerabilities: %w", err)
+	}
+	for _, n := range old {
+		if err := store.DeleteNode(ctx, n.ID); err != nil {
+			return nil, fmt.Errorf("delete vulnerability %s: %w", n.Name, err)
+		}
+	}
+
+	for _, dep := range deps {
+		p, ok := depPkg[dep]
+		if !ok || len(vulns[pkgIndex[p]]) == 0 {
+			continue
+		}
+		services, importers, err := dependents(ctx, store, dep.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range vulns[pkgIndex[p]] {
+			v := &vulns[pkgIndex[p]][i]
+			f := Finding{
+				ID:         v.ID,
+				Aliases:    v.Aliases,
+				Severity:   v.Severity(),
+				Summary:    v.Summary,
+				Package:    dep.Name,
+				Ecosystem:  p.Ecosystem,
+				Version:    p.Version,
+				FixedIn:    v.FixedIn(p),
+				Manifest:   dep.FilePath,
+				ImportedBy: importers,
+			}
+			for _, svc := range services {
+				f.Services = append(f.Services, svc.Name)
+			}
+			if err := writeVulnerability(ctx, store, v, &f, dep, services); err != nil {
+				return nil, err
+			}
+			res.Findings = append(res.Findings, f)
+		}
+	}
+
+	sort.SliceStable(res.Findings, func(i, j int) bool {
+		a, b := &res.Findings[i], &res.Findings[j]
+		if a.Reachable() != b.Reachable() {
+			return a.Reachable()
+		}
+		if ra, rb := rank(a.Severity), rank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if len(a.ImportedBy) != len(b.ImportedBy) {
+			return len(a.ImportedBy) > len(b.ImportedBy)
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Manifest < b.Manifest
+	})
+	return res, nil
+}
+
+func rank(severity string) int {
+	if r, ok := severityRank[severity]; ok {
+		return r
+	}
+	return len(severityRank)
+}
+
+// dependents returns the services declaring the dependency and the files
+// importing it (through the linker's import_to_manifest edges).
+func dependents(ctx context.Context, store graph.Store, depID string) ([]*graph.Node, []string, error) {
+	edges, err := store.GetEdges(ctx, depID, graph.EdgeDependsOn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get dependents of %s: %w", depID, err)
+	}
+	var services []*graph.Node
+	files := make(map[string]bool)
+	for _, e := range edges {
+		if e.TargetID != depID {
+			continue
+		}
+		src, err := store.GetNode(ctx, e.SourceID)
+		if err != nil {
+			continue
+		}
+		switch {
+		case src.Type == graph.NodeService:
+			services = append(services, src)
+		case e.Properties["kind"] == "import_to_manifest" && src.FilePath != "":
+			files[src.FilePath] = true
+		}
+	}
+	importers := make([]string, 0, len(files))
+	for f := range files {
+		importers = append(importers, f)
+	}
+	sort.Strings(importers)
+	if len(importers) == 0 {
+		importers = nil
+	}
+	return services, importers, nil
+}
+
+// writeVulnerability upserts the Vulnerability node and links it to the
+// dependency and its services.
+func writeVulnerability(ctx context.Context, store graph.Store, v *Vulnerability, f *Finding, dep *graph.Node, services []*graph.Node) error {
+	node := &graph.Node{
+		ID:         graph.NewNodeID(string(graph.NodeVulnerability), "", v.ID),
+		Type:       graph.NodeVulnerability,
+		Name:       v.ID,
+		DocComment: v.Summary,
+		Properties: map[string]string{
+			"severity": f.Severity,
+			"aliases":  strings.Join(v.Aliases, ","),
+			"url":      "https://osv.dev/vulnerability/" + v.ID,
+		},
+	}
+	if existing, err := store.GetNode(ctx, node.ID); err == nil && existing != nil {
+		node = existing
+	} else if err := store.AddNode(ctx, node); err != nil {
+		return fmt.Errorf("add vulnerability %s: %w", v.ID, err)
+	}
+
+	reachable := "false"
+	if f.Reachable() {
+		reachable = "true"
+	}
+	if err := store.AddEdge(ctx, &graph.Edge{
+		ID:       graph.NewEdgeID(graph.EdgeAffects, node.ID, dep.ID),
+		Type:     graph.EdgeAffects,
+		SourceID: node.ID,
+		TargetID: dep.ID,
+		Properties: map[string]string{
+			"version":   f.Version,
+			"fixed_in":  f.FixedIn,
+			"reachable": reachable,
+		},
+	}); err != nil {
+		return fmt.Errorf("link %s to %s: %w", v.ID, dep.Name, err)
+	}
+	for _, svc := range services {
+		if err := store.AddEdge(ctx, &graph.Edge{
+			ID:       graph.NewEdgeID(graph.EdgeAffects, node.ID, svc.ID),
+			Type:     graph.EdgeAffects,
+			SourceID: node.ID,
+			TargetID: svc.ID,
+			Properties: map[string]string{
+				"dependency": dep.Name,
+				"reachable":  reachable,
+			},
+		}); err != nil {
+			return fmt.Errorf("link %s to %s: %w", v.ID, svc.Name, err)
+		}
+	}
+	return nil
+}
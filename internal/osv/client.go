@@ -0,0 +1,141 @@
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultAPIURL is the public OSV API.
+const DefaultAPIURL = "https://api.osv.dev"
+
+// batchSize is the maximum number of queries per querybatch request.
+const batchSize = 1000
+
+// Client queries the OSV API. It batches lookups through /v1/querybatch
+// (which returns only IDs) and fetches each distinct record once.
+type Client struct {
+	baseURL string
+	client  *http.Client
+	cache   map[string]*Vulnerability
+}
+
+// NewClient creates a Client for the API at baseURL (DefaultAPIURL if empty).
+func NewClient(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &Client{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		cache:   make(map[string]*Vulnerability),
+	}
+}
+
+type osvQuery struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version string `json:"version"`
+}
+
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// Query implements Source.
+func (c *Client) Query(ctx context.Context, pkgs []Package) ([][]Vulnerability, error) {
+	results := make([][]Vulnerability, len(pkgs))
+	for start := 0; start < len(pkgs); start += batchSize {
+		end := min(start+batchSize, len(pkgs))
+
+		var req struct {
+			Queries []osvQuery `json:"queries"`
+		}
+		for _, p := range pkgs[start:end] {
+			var q osvQuery
+			q.Package.Name = p.Name
+			q.Package.Ecosystem = p.Ecosystem
+			q.Version = p.Version
+			req.Queries = append(req.Queries, q)
+		}
+
+		var resp osvBatchResponse
+		if err := c.post(ctx, "/v1/querybatch", req, &resp); err != nil {
+			return nil, err
+		}
+		for i, r := range resp.Results {
+			if start+i >= len(pkgs) {
+				break
+			}
+			for _, v := range r.Vulns {
+				vuln, err := c.vuln(ctx, v.ID)
+				if err != nil {
+					return nil, err
+				}
+				results[start+i] = append(results[start+i], *vuln)
+			}
+		}
+	}
+	return results, nil
+}
+
+// vuln fetches a full record by ID.
+func (c *Client) vuln(ctx context.Context, id string) (*Vulnerability, error) {
+	if v, ok := c.cache[id]; ok {
+		return v, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/v1/vulns/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create OSV request: %w", err)
+	}
+	var v Vulnerability
+	if err := c.do(req, &v); err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", id, err)
+	}
+	c.cache[id] = &v
+	return &v, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal OSV request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create OSV request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, out)
+}
+
+func (c *Client) do(req *http.Request, out any) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OSV request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read OSV response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OSV API error (HTTP %d): %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("unmarshal OSV response: %w", err)
+	}
+	return nil
+}
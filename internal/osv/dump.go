@@ -0,0 +1,120 @@
+package osv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Dump is an offline OSV database loaded from the per-ecosystem exports
+// (e.g., https://osv-vulnerabilities.storage.googleapis.com/PyPI/all.zip):
+// either a zip archive or a directory of OSV JSON records, searched
+// recursively (so several unzipped ecosystems can share a directory).
+type Dump struct {
+	byPackage map[string][]*Vulnerability // ecosystem + "/" + normalized name
+}
+
+// LoadDump reads an OSV dump from a .zip file or a directory.
+func LoadDump(path string) (*Dump, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("open OSV dump: %w", err)
+	}
+	d := &Dump{byPackage: make(map[string][]*Vulnerability)}
+	if !info.IsDir() {
+		if err := d.loadZip(path); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+
+	err = filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsDir():
+			return nil
+		case strings.HasSuffix(p, ".zip"):
+			return d.loadZip(p)
+		case strings.HasSuffix(p, ".json"):
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			return d.add(p, data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load OSV dump: %w", err)
+	}
+	return d, nil
+}
+
+func (d *Dump) loadZip(path string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("open OSV dump %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("read %s in %s: %w", f.Name, path, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("read %s in %s: %w", f.Name, path, err)
+		}
+		if err := d.add(f.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dump) add(name string, data []byte) error {
+	var v Vulnerability
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("parse OSV record %s: %w", name, err)
+	}
+	seen := make(map[string]bool)
+	for _, a := range v.Affected {
+		key := a.Package.Ecosystem + "/" + NormalizeName(a.Package.Ecosystem, a.Package.Name)
+		if !seen[key] {
+			seen[key] = true
+			d.byPackage[key] = append(d.byPackage[key], &v)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of package entries in the dump.
+func (d *Dump) Len() int {
+	return len(d.byPackage)
+}
+
+// Query implements Source by matching versions against the records locally.
+func (d *Dump) Query(_ context.Context, pkgs []Package) ([][]Vulnerability, error) {
+	results := make([][]Vulnerability, len(pkgs))
+	for i, p := range pkgs {
+		for _, v := range d.byPackage[p.Ecosystem+"/"+NormalizeName(p.Ecosystem, p.Name)] {
+			if v.Affects(p) {
+				results[i] = append(results[i], *v)
+			}
+		}
+	}
+	return results, nil
+}
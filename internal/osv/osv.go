@@ -0,0 +1,257 @@
+// Package osv looks up known vulnerabilities of manifest dependencies in the
+// OSV database (https://osv.dev), through its API or a local dump, and links
+// them into the knowledge graph.
+package osv
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// Package is a dependency version to look up.
+type Package struct {
+	Name      string
+	Ecosystem string // OSV ecosystem ("Go", "npm", "PyPI")
+	Version   string
+}
+
+// Vulnerability is the subset of an OSV record CodeEagle uses.
+type Vulnerability struct {
+	ID               string     `json:"id"`
+	Summary          string     `json:"summary,omitempty"`
+	Details          string     `json:"details,omitempty"`
+	Aliases          []string   `json:"aliases,omitempty"`
+	Affected         []Affected `json:"affected,omitempty"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity,omitempty"`
+	} `json:"database_specific"`
+}
+
+// Affected lists the affected versions of one package.
+type Affected struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Ranges   []Range  `json:"ranges,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Range is an OSV version range: a sequence of introduced/fixed events.
+type Range struct {
+	Type   string  `json:"type"` // SEMVER, ECOSYSTEM, or GIT
+	Events []Event `json:"events"`
+}
+
+// Event marks where a range starts or ends.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// Source looks up vulnerabilities. The result has one entry per package, in
+// the same order.
+type Source interface {
+	Query(ctx context.Context, pkgs []Package) ([][]Vulnerability, error)
+}
+
+// Severity returns the advisory's severity in lower case ("low", "moderate",
+// "high", "critical"), or "unknown" when the record doesn't say.
+func (v *Vulnerability) Severity() string {
+	if s := v.DatabaseSpecific.Severity; s != "" {
+		return strings.ToLower(s)
+	}
+	return "unknown"
+}
+
+// FixedIn returns the lowest fixed version recorded for the package, or "".
+func (v *Vulnerability) FixedIn(pkg Package) string {
+	var fixed string
+	for _, a := range v.Affected {
+		if !samePackage(a, pkg) {
+			continue
+		}
+		for _, r := range a.Ranges {
+			for _, e := range r.Events {
+				if e.Fixed != "" && (fixed == "" || compareVersions(e.Fixed, fixed) < 0) {
+					fixed = e.Fixed
+				}
+			}
+		}
+	}
+	return fixed
+}
+
+// Affects reports whether the vulnerability applies to pkg's version, using
+// the explicit version list or SEMVER/ECOSYSTEM ranges. Ecosystem ranges are
+// compared with a generic dotted-version ordering, which is exact for
+// semver-style versions and approximate for PEP 440 edge cases.
+func (v *Vulnerability) Affects(pkg Package) bool {
+	version := strings.TrimPrefix(pkg.Version, "v")
+	for _, a := range v.Affected {
+		if !samePackage(a, pkg) {
+			continue
+		}
+		for _, av := range a.Versions {
+			if strings.TrimPrefix(av, "v") == version {
+				return true
+			}
+		}
+		for _, r := range a.Ranges {
+			if (r.Type == "SEMVER" || r.Type == "ECOSYSTEM") && inRange(r.Events, version) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func samePackage(a Affected, pkg Package) bool {
+	return a.Package.Ecosystem == pkg.Ecosystem && NormalizeName(pkg.Ecosystem, a.Package.Name) == NormalizeName(pkg.Ecosystem, pkg.Name)
+}
+
+// inRange evaluates the events in order: a version is affected once an
+// introduced event at or below it is seen, until a fixed (exclusive) or
+// last_affected (inclusive) event at or below it.
+func inRange(events []Event, version string) bool {
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || compareVersions(e.Introduced, version) <= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compareVersions(e.Fixed, version) <= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compareVersions(e.LastAffected, version) < 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// compareVersions orders dotted versions numerically component by component.
+// A pre-release suffix ("-rc.1", "a1", "b2") sorts before the release.
+func compareVersions(a, b string) int {
+	a, b = strings.TrimPrefix(a, "v"), strings.TrimPrefix(b, "v")
+	aMain, aPre := splitPrerelease(a)
+	bMain, bPre := splitPrerelease(b)
+
+	ap, bp := strings.Split(aMain, "."), strings.Split(bMain, ".")
+	for i := 0; i < max(len(ap), len(bp)); i++ {
+		var x, y string
+		if i < len(ap) {
+			x = ap[i]
+		}
+		if i < len(bp) {
+			y = bp[i]
+		}
+		if c := comparePart(x, y); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return comparePart(aPre, bPre)
+}
+
+// splitPrerelease separates "1.2.3-rc.1" or "1.2.3rc1" into the numeric
+// release and the pre-release suffix. Build metadata ("+...") is dropped.
+func splitPrerelease(v string) (string, string) {
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	for i, r := range v {
+		if r != '.' && (r < '0' || r > '9') {
+			return strings.TrimSuffix(v[:i], "."), v[i:]
+		}
+	}
+	return v, ""
+}
+
+func comparePart(x, y string) int {
+	xn, xErr := strconv.Atoi(orZero(x))
+	yn, yErr := strconv.Atoi(orZero(y))
+	if xErr == nil && yErr == nil {
+		switch {
+		case xn < yn:
+			return -1
+		case xn > yn:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(x, y)
+}
+
+func orZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
+
+// Ecosystem maps the manifest parser's ecosystem names to OSV's. It returns
+// "" for ecosystems OSV lookups aren't supported for.
+func Ecosystem(manifestEcosystem string) string {
+	switch manifestEcosystem {
+	case "go":
+		return "Go"
+	case "nodejs":
+		return "npm"
+	case "python":
+		return "PyPI"
+	case "rust":
+		return "crates.io"
+	case "maven":
+		return "Maven"
+	}
+	return ""
+}
+
+// NormalizeName applies the ecosystem's package name normalization (PEP 503
+// for PyPI; names are case-sensitive elsewhere).
+func NormalizeName(ecosystem, name string) string {
+	if ecosystem == "PyPI" {
+		if i := strings.IndexByte(name, '['); i >= 0 {
+			name = name[:i]
+		}
+		return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	}
+	return name
+}
+
+// CleanVersion extracts a concrete version from a manifest version spec
+// ("^1.2.3", "==2.0.0", ">= 0.100.0, <1", "v1.4.0"); for a range that is its
+// lower bound. It returns "" for specs that don't name a lower bound, such as
+// "*", "latest", or "<2.0".
+func CleanVersion(spec string) string {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "<") || strings.HasPrefix(spec, "!") {
+		return ""
+	}
+	spec = strings.TrimSpace(strings.TrimLeft(spec, "^~=>v"))
+	if i := strings.IndexAny(spec, ", ;|"); i >= 0 {
+		spec = spec[:i]
+	}
+	if spec == "" || spec[0] < '0' || spec[0] > '9' || strings.ContainsAny(spec, "*xX") {
+		return ""
+	}
+	return spec
+}
@@ -0,0 +1,267 @@
+package osv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+const yamlAdvisory = `{
+  "id": "GHSA-yaml-0001",
+  "summary": "Arbitrary code execution in yaml.load",
+  "aliases": ["CVE-2020-1747"],
+  "affected": [{
+    "package": {"name": "PyYAML", "ecosystem": "PyPI"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "5.4"}]}]
+  }],
+  "database_specific": {"severity": "CRITICAL"}
+}`
+
+const lodashAdvisory = `{
+  "id": "GHSA-lodash-0002",
+  "summary": "Prototype pollution in lodash",
+  "affected": [{
+    "package": {"name": "lodash", "ecosystem": "npm"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "4.0.0"}, {"fixed": "4.17.21"}]}],
+    "versions": ["3.10.1"]
+  }],
+  "database_specific": {"severity": "HIGH"}
+}`
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.10.0", "1.9.9", 1},
+		{"1.2", "1.2.0", 0},
+		{"2.0.0-rc.1", "2.0.0", -1},
+		{"5.4b1", "5.4", -1},
+		{"5.3.1", "5.4", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCleanVersion(t *testing.T) {
+	tests := []struct{ spec, want string }{
+		{"^4.17.15", "4.17.15"},
+		{"~1.2.0", "1.2.0"},
+		{"==5.3.1", "5.3.1"},
+		{">= 0.100.0, <1", "0.100.0"},
+		{"v1.4.0", "1.4.0"},
+		{"<2.0", ""},
+		{"*", ""},
+		{"latest", ""},
+		{"1.x", ""},
+	}
+	for _, tt := range tests {
+		if got := CleanVersion(tt.spec); got != tt.want {
+			t.Errorf("CleanVersion(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestAffects(t *testing.T) {
+	var yaml, lodash Vulnerability
+	if err := json.Unmarshal([]byte(yamlAdvisory), &yaml); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lodashAdvisory), &lodash); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		vuln *Vulnerability
+		pkg  Package
+		want bool
+	}{
+		{"pypi name normalized", &yaml, Package{"pyyaml", "PyPI", "5.3.1"}, true},
+		{"fixed version", &yaml, Package{"PyYAML", "PyPI", "5.4"}, false},
+		{"other ecosystem", &yaml, Package{"PyYAML", "npm", "5.3.1"}, false},
+		{"semver range", &lodash, Package{"lodash", "npm", "4.17.15"}, true},
+		{"before introduced", &lodash, Package{"lodash", "npm", "3.9.0"}, false},
+		{"explicit version", &lodash, Package{"lodash", "npm", "3.10.1"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.vuln.Affects(tt.pkg); got != tt.want {
+			t.Errorf("%s: Affects = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := lodash.FixedIn(Package{"lodash", "npm", "4.17.15"}); got != "4.17.21" {
+		t.Errorf("FixedIn = %q", got)
+	}
+	if got := lodash.Severity(); got != "high" {
+		t.Errorf("Severity = %q", got)
+	}
+}
+
+func TestLoadDump(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "PyPI"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "PyPI", "GHSA-yaml-0001.json"), []byte(yamlAdvisory), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	zf, err := os.Create(filepath.Join(dir, "npm.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(zf)
+	w, _ := zw.Create("GHSA-lodash-0002.json")
+	w.Write([]byte(lodashAdvisory))
+	zw.Close()
+	zf.Close()
+
+	dump, err := LoadDump(dir)
+	if err != nil {
+		t.Fatalf("LoadDump: %v", err)
+	}
+	res, err := dump.Query(context.Background(), []Package{
+		{"lodash", "npm", "4.17.15"},
+		{"lodash", "npm", "4.17.21"},
+		{"PyYAML", "PyPI", "5.1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res[0]) != 1 || len(res[1]) != 0 || len(res[2]) != 1 || res[2][0].ID != "GHSA-yaml-0001" {
+		t.Errorf("Query = %+v", res)
+	}
+}
+
+func TestClientQuery(t *testing.T) {
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/querybatch":
+			var req struct {
+				Queries []osvQuery `json:"queries"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			var results []string
+			for _, q := range req.Queries {
+				if q.Package.Name == "lodash" && q.Version == "4.17.15" {
+					results = append(results, `{"vulns": [{"id": "GHSA-lodash-0002"}]}`)
+				} else {
+					results = append(results, `{}`)
+				}
+			}
+			w.Write([]byte(`{"results": [` + strings.Join(results, ",") + `]}`))
+		case r.URL.Path == "/v1/vulns/GHSA-lodash-0002":
+			fetches++
+			w.Write([]byte(lodashAdvisory))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	res, err := c.Query(context.Background(), []Package{
+		{"lodash", "npm", "4.17.15"},
+		{"react", "npm", "18.2.0"},
+		{"lodash", "npm", "4.17.15"},
+	})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(res) != 3 || len(res[0]) != 1 || len(res[1]) != 0 || len(res[2]) != 1 {
+		t.Fatalf("Query = %+v", res)
+	}
+	if fetches != 1 {
+		t.Errorf("fetched the record %d times, want 1 (cached)", fetches)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+
+	nodes := []*graph.Node{
+		{ID: "svc-web", Type: graph.NodeService, Name: "web", FilePath: "web/package.json"},
+		{ID: "svc-ml", Type: graph.NodeService, Name: "ml", FilePath: "ml/requirements.txt"},
+		{ID: "dep-lodash", Type: graph.NodeDependency, Name: "lodash", FilePath: "web/package.json",
+			Properties: map[string]string{"kind": "manifest_dep", "ecosystem": "nodejs", "version": "^4.17.15"}},
+		{ID: "dep-yaml", Type: graph.NodeDependency, Name: "PyYAML", FilePath: "ml/requirements.txt",
+			Properties: map[string]string{"kind": "manifest_dep", "ecosystem": "python", "version": "==5.3.1"}},
+		{ID: "dep-any", Type: graph.NodeDependency, Name: "react", FilePath: "web/package.json",
+			Properties: map[string]string{"kind": "manifest_dep", "ecosystem": "nodejs", "version": "*"}},
+		{ID: "imp-lodash", Type: graph.NodeDependency, Name: "lodash", FilePath: "web/src/util.ts",
+			Properties: map[string]string{"kind": "import"}},
+		{ID: "stale", Type: graph.NodeVulnerability, Name: "GHSA-old"},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range []*graph.Edge{
+		{ID: "e1", Type: graph.EdgeDependsOn, SourceID: "svc-web", TargetID: "dep-lodash"},
+		{ID: "e2", Type: graph.EdgeDependsOn, SourceID: "svc-ml", TargetID: "dep-yaml"},
+		{ID: "e3", Type: graph.EdgeDependsOn, SourceID: "imp-lodash", TargetID: "dep-lodash",
+			Properties: map[string]string{"kind": "import_to_manifest"}},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var yaml, lodash Vulnerability
+	json.Unmarshal([]byte(yamlAdvisory), &yaml)
+	json.Unmarshal([]byte(lodashAdvisory), &lodash)
+	dump := &Dump{byPackage: map[string][]*Vulnerability{
+		"PyPI/pyyaml": {&yaml},
+		"npm/lodash":  {&lodash},
+	}}
+
+	res, err := Audit(ctx, store, dump)
+	if err != nil {
+		t.Fatalf("Audit: %v", err)
+	}
+	if res.Packages != 2 || res.Skipped != 1 || len(res.Findings) != 2 {
+		t.Fatalf("result = %+v", res)
+	}
+	// The imported (high) lodash advisory outranks the unimported critical one.
+	first, second := res.Findings[0], res.Findings[1]
+	if first.ID != "GHSA-lodash-0002" || !first.Reachable() || first.ImportedBy[0] != "web/src/util.ts" || first.FixedIn != "4.17.21" {
+		t.Errorf("first finding = %+v", first)
+	}
+	if second.ID != "GHSA-yaml-0001" || second.Reachable() || second.Services[0] != "ml" {
+		t.Errorf("second finding = %+v", second)
+	}
+
+	vulns, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeVulnerability})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vulns) != 2 {
+		t.Errorf("expected the stale vulnerability to be replaced, got %d nodes", len(vulns))
+	}
+	edges, err := store.GetEdges(ctx, "svc-web", graph.EdgeAffects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 || edges[0].Properties["reachable"] != "true" {
+		t.Errorf("service Affects edges = %+v", edges)
+	}
+}
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/issues"
+)
+
+// annotationRe matches a TODO, FIXME, HACK or XXX marker at the start of a
+// comment (//, /*, #, --, <!--, ; or a "*" block comment continuation),
+// with an optional owner or issue in parentheses or after "@":
+//
+//	// TODO(alice): handle retries
+//	# FIXME(#123) flaky on CI
+//	/* HACK @bob - remove after JIRA-42 */
+var annotationRe = regexp.MustCompile(`(?m)(?:(?:^|[^\w:/])(?://+|/\*+|#+|--|<!--|;+)|^[ \t]*\*+)[ \t]*(?P<tag>TODO|FIXME|HACK|XXX)\b[ \t]*(?:\((?P<who>[^)\n]*)\)|@(?P<at>[\w.-]+))?[ \t]*[:\-]?[ \t]*(?P<text>[^\n]*)`)
+
+// ExtractAnnotations scans comments for TODO, FIXME, HACK and XXX markers
+// and adds an Annotation node per marker, named by its text, with an
+// Annotates edge to the enclosing function or method (or the file node).
+// Annotation nodes carry the kind property (todo, fixme, hack, xxx), the
+// author named in the marker, and the issues it references, comma-separated.
+func ExtractAnnotations(result *ParseResult, content []byte) *ParseResult {
+	if result.Language == LangMarkdown {
+		return result
+	}
+
+	fileID := ""
+	var callables []*graph.Node
+	for _, n := range result.Nodes {
+		switch n.Type {
+		case graph.NodeFile, graph.NodeTestFile:
+			if fileID == "" {
+				fileID = n.ID
+			}
+		case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
+			if n.EndLine >= n.Line && n.Line > 0 {
+				callables = append(callables, n)
+			}
+		}
+	}
+	if fileID == "" {
+		return result
+	}
+
+	tagIdx, whoIdx, atIdx, textIdx := annotationRe.SubexpIndex("tag"), annotationRe.SubexpIndex("who"),
+		annotationRe.SubexpIndex("at"), annotationRe.SubexpIndex("text")
+	group := func(m []int, i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return string(content[m[2*i]:m[2*i+1]])
+	}
+	seen := make(map[int]bool)
+	for _, m := range annotationRe.FindAllSubmatchIndex(content, -1) {
+		line := bytes.Count(content[:m[2*tagIdx]], []byte("\n")) + 1
+		// A marker after an unclosed double quote is inside a string literal.
+		lineStart := bytes.LastIndexByte(content[:m[2*tagIdx]], '\n') + 1
+		if seen[line] || bytes.Count(content[lineStart:m[2*tagIdx]], []byte(`"`))%2 == 1 {
+			continue
+		}
+		seen[line] = true
+
+		tag := group(m, tagIdx)
+		text := strings.TrimSpace(group(m, textIdx))
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(text, "*/"), "-->"))
+
+		var author string
+		var refs []string
+		addIssue := func(ref string) {
+			for _, seen := range refs {
+				if seen == ref {
+					return
+				}
+			}
+			refs = append(refs, ref)
+		}
+		for _, part := range strings.FieldsFunc(group(m, whoIdx), func(r rune) bool { return r == ',' || r == ' ' }) {
+			if ref := issues.Normalize(part); ref != "" {
+				addIssue(ref)
+			} else if author == "" {
+				author = strings.TrimPrefix(part, "@")
+			}
+		}
+		if at := group(m, atIdx); at != "" && author == "" {
+			author = at
+		}
+		for _, ref := range issues.Refs(text) {
+			addIssue(ref)
+		}
+
+		name := text
+		if name == "" {
+			name = tag
+		}
+		props := map[string]string{"kind": strings.ToLower(tag)}
+		if author != "" {
+			props["author"] = author
+		}
+		if len(refs) > 0 {
+			props["issues"] = strings.Join(refs, ",")
+		}
+		node := &graph.Node{
+			ID:         graph.NewNodeID(string(graph.NodeAnnotation), result.FilePath, strconv.Itoa(line)),
+			Type:       graph.NodeAnnotation,
+			Name:       name,
+			FilePath:   result.FilePath,
+			Line:       line,
+			Language:   string(result.Language),
+			Properties: props,
+		}
+		result.Nodes = append(result.Nodes, node)
+
+		targetID := fileID
+		if fn := enclosingCallable(callables, line); fn != nil {
+			targetID = fn.ID
+		}
+		result.Edges = append(result.Edges, &graph.Edge{
+			ID:       graph.NewEdgeID(graph.EdgeAnnotates, node.ID, targetID),
+			Type:     graph.EdgeAnnotates,
+			SourceID: node.ID,
+			TargetID: targetID,
+		})
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestExtractAnnotations(t *testing.T) {
+	src := `package pay
+
+// TODO(alice): handle partial refunds, see #42
+func Refund() error {
+	/* FIXME(#77, bob) rounding is off */
+	x := "// TODO not a comment marker"
+	return nil // HACK @carol - remove after PAY-123 -->
+}
+
+// Todo and TODOS are not markers.
+// XXX
+`
+	result := &ParseResult{
+		FilePath: "pay/refund.go",
+		Language: LangGo,
+		Nodes: []*graph.Node{
+			{ID: "file", Type: graph.NodeFile, FilePath: "pay/refund.go"},
+			{ID: "refund", Type: graph.NodeFunction, Name: "Refund", Line: 4, EndLine: 8},
+		},
+	}
+	result = ExtractAnnotations(result, []byte(src))
+
+	type want struct {
+		name, kind, author, issues, target string
+	}
+	wants := map[int]want{
+		3:  {"handle partial refunds, see #42", "todo", "alice", "#42", "file"},
+		5:  {"rounding is off", "fixme", "bob", "#77", "refund"},
+		7:  {"remove after PAY-123", "hack", "carol", "PAY-123", "refund"},
+		11: {"XXX", "xxx", "", "", "file"},
+	}
+
+	targets := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeAnnotates {
+			targets[e.SourceID] = e.TargetID
+		}
+	}
+	got := 0
+	for _, n := range result.Nodes {
+		if n.Type != graph.NodeAnnotation {
+			continue
+		}
+		got++
+		w, ok := wants[n.Line]
+		if !ok {
+			t.Errorf("unexpected annotation on line %d: %q", n.Line, n.Name)
+			continue
+		}
+		if n.Name != w.name || n.Properties["kind"] != w.kind || n.Properties["author"] != w.author ||
+			n.Properties["issues"] != w.issues || targets[n.ID] != w.target {
+			t.Errorf("line %d: got %q %v -> %s, want %+v", n.Line, n.Name, n.Properties, targets[n.ID], w)
+		}
+	}
+	if got != len(wants) {
+		t.Errorf("got %d annotations, want %d", got, len(wants))
+	}
+}
@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Endpoint auth properties. Parsers record the middleware a route passes
+// through in PropMiddleware; the linker combines it with the annotations
+// and decorators of the endpoint's handler and controller into the
+// auth requirement (see ClassifyAuth).
+const (
+	// PropMiddleware lists the middleware applied to a route, by the
+	// route itself or by the router or group it is registered on.
+	PropMiddleware = "middleware"
+	// PropAuth is AuthProtected or AuthPublic; endpoints nothing marks
+	// either way have no auth property.
+	PropAuth = "auth"
+	// PropAuthVia lists the annotations and middleware deciding PropAuth.
+	PropAuthVia = "auth_via"
+	// PropAuthRoles lists the roles or authorities an endpoint requires,
+	// when its annotations name them.
+	PropAuthRoles = "auth_roles"
+)
+
+// Auth requirement values of PropAuth.
+const (
+	AuthProtected = "protected"
+	AuthPublic    = "public"
+)
+
+// AuthRequirement is the auth requirement of an endpoint.
+type AuthRequirement struct {
+	// Status is AuthProtected, AuthPublic, or "" when no marker decides it.
+	Status string
+	// Via names the annotations and middleware that decided Status.
+	Via []string
+	// Roles are the roles or authorities required, when named.
+	Roles []string
+}
+
+// authMarkers are lowercase substrings of annotation, decorator and
+// middleware names that require an authenticated caller.
+var authMarkers = []string{"auth", "login_required", "loggedin", "permission", "secured", "rolesallowed", "jwt", "verifytoken"}
+
+// publicMarkers are lowercase substrings of markers that explicitly open
+// an endpoint to anonymous callers. They win over auth markers, since they
+// typically exempt one action of a protected controller.
+var publicMarkers = []string{"allowanonymous", "permitall", "skipauth"}
+
+var (
+	// markerNameRe matches the name of an annotation, decorator or
+	// middleware: Authorize, login_required, passport.authenticate,
+	// authenticate_user!.
+	markerNameRe = regexp.MustCompile(`^[A-Za-z_$][\w$.]*!?$`)
+	// Role declarations: Spring Security expressions, the Roles of a C#
+	// [Authorize], and the arguments of @RolesAllowed and @Secured.
+	roleExprRe    = regexp.MustCompile(`has(?:Any)?(?:Role|Authority|Authorities|Roles)\(([^)]*)\)`)
+	roleAssignRe  = regexp.MustCompile(`Roles\s*=\s*"([^"]*)"`)
+	roleAllowedRe = regexp.MustCompile(`(?:RolesAllowed|Secured)\(([^)]*)\)`)
+	quotedRe      = regexp.MustCompile(`['"]([^'"]+)['"]`)
+)
+
+// ClassifyAuth derives an auth requirement from the annotations,
+// decorators and middleware applied to an endpoint. Markers may be
+// fragments of comma-joined annotation properties; roles are read from
+// the markers joined back together.
+func ClassifyAuth(markers []string) AuthRequirement {
+	var req AuthRequirement
+	var protectedVia, publicVia []string
+	for _, m := range markers {
+		name := markerName(m)
+		if name == "" {
+			continue
+		}
+		lm := strings.ToLower(m)
+		switch {
+		case containsMarker(lm, publicMarkers) || strings.EqualFold(name, "Public"):
+			publicVia = appendName(publicVia, name)
+		case containsMarker(strings.ToLower(name), authMarkers):
+			protectedVia = appendName(protectedVia, name)
+		}
+	}
+	switch {
+	case len(publicVia) > 0:
+		req.Status, req.Via = AuthPublic, publicVia
+	case len(protectedVia) > 0:
+		req.Status, req.Via = AuthProtected, protectedVia
+		req.Roles = authRoles(strings.Join(markers, ","))
+	}
+	return req
+}
+
+// markerName returns the name of an annotation, decorator or middleware
+// (Authorize for `[Authorize(Roles = "Admin")]`), or "" when m is not one.
+func markerName(m string) string {
+	name := strings.TrimSpace(m)
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "@"), "[")
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSpace(strings.TrimSuffix(name, "]"))
+	if !markerNameRe.MatchString(name) {
+		return ""
+	}
+	return name
+}
+
+// authRoles returns the roles named by the role declarations in text.
+func authRoles(text string) []string {
+	var roles []string
+	for _, m := range roleExprRe.FindAllStringSubmatch(text, -1) {
+		for _, q := range quotedRe.FindAllStringSubmatch(m[1], -1) {
+			roles = appendName(roles, q[1])
+		}
+	}
+	for _, m := range roleAssignRe.FindAllStringSubmatch(text, -1) {
+		for _, r := range strings.Split(m[1], ",") {
+			if r = strings.TrimSpace(r); r != "" {
+				roles = appendName(roles, r)
+			}
+		}
+	}
+	for _, m := range roleAllowedRe.FindAllStringSubmatch(text, -1) {
+		for _, q := range quotedRe.FindAllStringSubmatch(m[1], -1) {
+			roles = appendName(roles, q[1])
+		}
+	}
+	return roles
+}
+
+// containsMarker reports whether s contains any of markers.
+func containsMarker(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendName appends name to names unless already present.
+func appendName(names []string, name string) []string {
+	for _, n := range names {
+		if n == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
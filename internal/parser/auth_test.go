@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		markers []string
+		status  string
+		via     string
+		roles   string
+	}{
+		{"csharp authorize roles", strings.Split(`HttpDelete,Authorize(Roles = "Admin,Ops")`, ","), AuthProtected, "Authorize", "Admin,Ops"},
+		{"csharp allow anonymous wins", []string{"Authorize", "HttpPost", "AllowAnonymous"}, AuthPublic, "AllowAnonymous", ""},
+		{"spring preauthorize", strings.Split(`GetMapping("/orders"),PreAuthorize("hasAnyRole('ADMIN','AUTHOR')")`, ","), AuthProtected, "PreAuthorize", "ADMIN,AUTHOR"},
+		{"spring permit all", []string{`PreAuthorize("permitAll()")`}, AuthPublic, "PreAuthorize", ""},
+		{"jakarta roles allowed", []string{`RolesAllowed({"admin"`, `"ops"})`}, AuthProtected, "RolesAllowed", "admin,ops"},
+		{"secured", []string{`Secured("ROLE_USER")`}, AuthProtected, "Secured", "ROLE_USER"},
+		{"flask decorator", []string{"app.route('/me')", "login_required"}, AuthProtected, "login_required", ""},
+		{"express middleware", []string{"requireAuth", "passport.authenticate"}, AuthProtected, "requireAuth,passport.authenticate", ""},
+		{"gin middleware", []string{"gin.Logger", "AuthRequired"}, AuthProtected, "AuthRequired", ""},
+		{"rails filter", []string{"authenticate_user!", "set_locale"}, AuthProtected, "authenticate_user!", ""},
+		{"nest public", []string{"Get()", "Public()"}, AuthPublic, "Public", ""},
+		{"no markers", []string{"HttpGet", "gin.Logger"}, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyAuth(tt.markers)
+			if got.Status != tt.status || strings.Join(got.Via, ",") != tt.via || strings.Join(got.Roles, ",") != tt.roles {
+				t.Errorf("ClassifyAuth(%q) = %+v, want status %q via %q roles %q", tt.markers, got, tt.status, tt.via, tt.roles)
+			}
+		})
+	}
+}
@@ -141,10 +141,14 @@ func (c *Classifier) isDBModel(node *graph.Node, annotations, decorators, bases
 		}
 	case "python":
 		for _, b := range bases {
-			if b == "Model" || b == "Base" || b == "Document" {
+			if b == "Model" || b == "Base" || b == "Document" || b == "models.Model" || b == "db.Model" {
 				return true
 			}
 		}
+		// relationship() attributes or Django relation fields
+		if node.Properties[PropORM] != "" {
+			return true
+		}
 		// dataclass with Base base
 		hasDataclass := containsAny(decorators, "dataclass")
 		hasBase := containsAny(bases, "Base")
@@ -152,6 +156,9 @@ func (c *Classifier) isDBModel(node *graph.Node, annotations, decorators, bases
 			return true
 		}
 	case "go":
+		if node.Properties[PropORM] == "gorm" {
+			return true
+		}
 		name := node.Name
 		if strings.HasSuffix(name, "Model") || strings.HasSuffix(name, "Entity") {
 			// Check for json/db/gorm tags in fields
@@ -166,6 +173,14 @@ func (c *Classifier) isDBModel(node *graph.Node, annotations, decorators, bases
 				return true
 			}
 		}
+	case "csharp":
+		// EF entities mapped with [Table]; those registered only through a
+		// DbContext's DbSet<T> properties are found by the linker.
+		for _, a := range annotations {
+			if a == "Table" || strings.HasPrefix(a, "Table(") {
+				return true
+			}
+		}
 	}
 
 	return false
@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Config node kinds.
+const (
+	// ConfigKindEnvVar is an environment variable.
+	ConfigKindEnvVar = "env_var"
+	// ConfigKindKey is a key in an application config file (viper, Spring
+	// properties, appsettings.json).
+	ConfigKindKey = "config_key"
+	// ConfigKindRoute is a reverse proxy or ingress route. Route nodes
+	// carry the RouteProp* properties.
+	ConfigKindRoute = "route"
+	// ConfigKindHost is a network hostname a service is reachable at, such
+	// as a docker-compose service name. Host nodes carry the
+	// build_context property of the service.
+	ConfigKindHost = "host"
+)
+
+// Route node properties.
+const (
+	// RoutePropPrefix is the path prefix the proxy matches (e.g. "/api").
+	RoutePropPrefix = "prefix"
+	// RoutePropRewrite is the prefix the matched prefix is replaced with
+	// before forwarding ("/" when it is stripped). It equals the prefix
+	// when the path is forwarded unchanged.
+	RoutePropRewrite = "rewrite"
+	// RoutePropUpstream is the host the proxy forwards to, without scheme
+	// or port (e.g. "backend").
+	RoutePropUpstream = "upstream"
+)
+
+// Environment variable producer properties, set when the value is an
+// absolute http(s) URL. Other values are never recorded since they often
+// hold secrets.
+const (
+	// EnvPropURLHost is the URL's host without scheme, credentials or port.
+	EnvPropURLHost = "url_host"
+	// EnvPropURLPath is the URL's path, when it has one (e.g. "/api").
+	EnvPropURLPath = "url_path"
+)
+
+// Config node roles.
+const (
+	// ConfigRoleConsumer marks a Config node read by code.
+	ConfigRoleConsumer = "consumer"
+	// ConfigRoleProducer marks a Config node set by deployment config
+	// (docker-compose, Kubernetes manifests).
+	ConfigRoleProducer = "producer"
+)
+
+// configReadPattern matches a config read; the first submatch is the name.
+type configReadPattern struct {
+	re   *regexp.Regexp
+	kind string
+}
+
+// configReadPatterns lists the env var and config key accessors per language.
+var configReadPatterns = map[Language][]configReadPattern{
+	LangGo: {
+		{regexp.MustCompile(`os\.(?:Getenv|LookupEnv)\(\s*"([A-Za-z_][A-Za-z0-9_]*)"`), ConfigKindEnvVar},
+		{regexp.MustCompile(`viper\.(?:Get\w*|IsSet)\(\s*"([^"]+)"`), ConfigKindKey},
+	},
+	LangPython: {
+		{regexp.MustCompile(`os\.environ(?:\.get\(|\[)\s*["']([A-Za-z_][A-Za-z0-9_]*)["']`), ConfigKindEnvVar},
+		{regexp.MustCompile(`os\.getenv\(\s*["']([A-Za-z_][A-Za-z0-9_]*)["']`), ConfigKindEnvVar},
+	},
+	LangTypeScript: jsConfigReadPatterns,
+	LangJavaScript: jsConfigReadPatterns,
+	LangJava: {
+		{regexp.MustCompile(`System\.getenv\(\s*"([A-Za-z_][A-Za-z0-9_]*)"`), ConfigKindEnvVar},
+		{regexp.MustCompile(`@Value\(\s*"\$\{([A-Za-z0-9_.\-]+)(?::[^}]*)?\}"`), ConfigKindKey},
+		{regexp.MustCompile(`\.getProperty\(\s*"([A-Za-z0-9_.\-]+)"`), ConfigKindKey},
+	},
+	LangRuby: {
+		{regexp.MustCompile(`ENV(?:\.fetch\(|\[)\s*["']([A-Za-z_][A-Za-z0-9_]*)["']`), ConfigKindEnvVar},
+	},
+	LangRust: {
+		{regexp.MustCompile(`env::var(?:_os)?\(\s*"([A-Za-z_][A-Za-z0-9_]*)"`), ConfigKindEnvVar},
+		{regexp.MustCompile(`env!\(\s*"([A-Za-z_][A-Za-z0-9_]*)"`), ConfigKindEnvVar},
+	},
+	LangCSharp: {
+		{regexp.MustCompile(`Environment\.GetEnvironmentVariable\(\s*"([A-Za-z_][A-Za-z0-9_]*)"`), ConfigKindEnvVar},
+		{regexp.MustCompile(`(?i:configuration|config)\[\s*"([^"]+)"\s*\]`), ConfigKindKey},
+		{regexp.MustCompile(`\.GetValue<[^>]+>\(\s*"([^"]+)"`), ConfigKindKey},
+	},
+}
+
+var jsConfigReadPatterns = []configReadPattern{
+	{regexp.MustCompile(`(?:process|import\.meta)\.env\.([A-Za-z_][A-Za-z0-9_]*)`), ConfigKindEnvVar},
+	{regexp.MustCompile(`(?:process|import\.meta)\.env\[\s*["'` + "`" + `]([A-Za-z_][A-Za-z0-9_]*)["'` + "`" + `]\s*\]`), ConfigKindEnvVar},
+}
+
+// ExtractConfigUsage scans source content for environment variable and config
+// key reads and adds a Config node (role=consumer) per distinct name in the
+// file, with Reads edges from each enclosing function or method (or from the
+// file node for top-level reads).
+func ExtractConfigUsage(result *ParseResult, content []byte) *ParseResult {
+	patterns := configReadPatterns[result.Language]
+	if len(patterns) == 0 {
+		return result
+	}
+
+	fileID := ""
+	var callables []*graph.Node
+	for _, n := range result.Nodes {
+		switch n.Type {
+		case graph.NodeFile, graph.NodeTestFile:
+			if fileID == "" {
+				fileID = n.ID
+			}
+		case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
+			if n.EndLine >= n.Line && n.Line > 0 {
+				callables = append(callables, n)
+			}
+		}
+	}
+	if fileID == "" {
+		return result
+	}
+
+	configs := make(map[string]*graph.Node) // kind:name -> Config node
+	edges := make(map[string]bool)
+	for _, p := range patterns {
+		for _, m := range p.re.FindAllSubmatchIndex(content, -1) {
+			name := string(content[m[2]:m[3]])
+			line := bytes.Count(content[:m[0]], []byte("\n")) + 1
+
+			key := p.kind + ":" + name
+			cfg, ok := configs[key]
+			if !ok {
+				cfg = &graph.Node{
+					ID:       graph.NewNodeID(string(graph.NodeConfig), result.FilePath, key),
+					Type:     graph.NodeConfig,
+					Name:     name,
+					FilePath: result.FilePath,
+					Line:     line,
+					Language: string(result.Language),
+					Properties: map[string]string{
+						"kind": p.kind,
+						"role": ConfigRoleConsumer,
+					},
+				}
+				configs[key] = cfg
+				result.Nodes = append(result.Nodes, cfg)
+			} else if line < cfg.Line {
+				cfg.Line = line
+			}
+
+			readerID := fileID
+			if fn := enclosingCallable(callables, line); fn != nil {
+				readerID = fn.ID
+			}
+			edgeID := graph.NewEdgeID(graph.EdgeReads, readerID, cfg.ID)
+			if edges[edgeID] {
+				continue
+			}
+			edges[edgeID] = true
+			result.Edges = append(result.Edges, &graph.Edge{
+				ID:       edgeID,
+				Type:     graph.EdgeReads,
+				SourceID: readerID,
+				TargetID: cfg.ID,
+			})
+		}
+	}
+	return result
+}
+
+// enclosingCallable returns the innermost function or method spanning line.
+func enclosingCallable(callables []*graph.Node, line int) *graph.Node {
+	var best *graph.Node
+	for _, n := range callables {
+		if n.Line <= line && line <= n.EndLine && (best == nil || n.Line > best.Line) {
+			best = n
+		}
+	}
+	return best
+}
@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestExtractConfigUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		lang Language
+		src  string
+		want map[string]string // config name -> kind
+	}{
+		{"go", LangGo, `package main
+func load() {
+	url := os.Getenv("DATABASE_URL")
+	if v, ok := os.LookupEnv("DEBUG"); ok {}
+	port := viper.GetInt("server.port")
+}`, map[string]string{"DATABASE_URL": ConfigKindEnvVar, "DEBUG": ConfigKindEnvVar, "server.port": ConfigKindKey}},
+		{"python", LangPython, `import os
+KEY = os.environ["API_KEY"]
+def f():
+    return os.environ.get('REGION'), os.getenv("STAGE")`,
+			map[string]string{"API_KEY": ConfigKindEnvVar, "REGION": ConfigKindEnvVar, "STAGE": ConfigKindEnvVar}},
+		{"typescript", LangTypeScript, "const u = process.env.DATABASE_URL ?? process.env['FALLBACK_URL'];\nconst m = import.meta.env.VITE_MODE;",
+			map[string]string{"DATABASE_URL": ConfigKindEnvVar, "FALLBACK_URL": ConfigKindEnvVar, "VITE_MODE": ConfigKindEnvVar}},
+		{"java", LangJava, `@Value("${payments.timeout:30}") int timeout;
+String key = System.getenv("STRIPE_KEY");`,
+			map[string]string{"payments.timeout": ConfigKindKey, "STRIPE_KEY": ConfigKindEnvVar}},
+		{"ruby", LangRuby, `token = ENV["TOKEN"] || ENV.fetch('FALLBACK')`,
+			map[string]string{"TOKEN": ConfigKindEnvVar, "FALLBACK": ConfigKindEnvVar}},
+		{"rust", LangRust, `let p = std::env::var("PORT").unwrap(); const V: &str = env!("CARGO_PKG_VERSION");`,
+			map[string]string{"PORT": ConfigKindEnvVar, "CARGO_PKG_VERSION": ConfigKindEnvVar}},
+		{"csharp", LangCSharp, `var cs = Configuration["ConnectionStrings:Default"]; var e = Environment.GetEnvironmentVariable("ASPNETCORE_ENV");`,
+			map[string]string{"ConnectionStrings:Default": ConfigKindKey, "ASPNETCORE_ENV": ConfigKindEnvVar}},
+		{"markdown", LangMarkdown, `Set os.Getenv("IGNORED")`, map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ParseResult{
+				FilePath: "svc/file",
+				Language: tt.lang,
+				Nodes:    []*graph.Node{{ID: "file", Type: graph.NodeFile, FilePath: "svc/file"}},
+			}
+			ExtractConfigUsage(result, []byte(tt.src))
+			got := make(map[string]string)
+			for _, n := range result.Nodes {
+				if n.Type == graph.NodeConfig {
+					got[n.Name] = n.Properties["kind"]
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("configs = %v, want %v", got, tt.want)
+			}
+			for name, kind := range tt.want {
+				if got[name] != kind {
+					t.Errorf("%s kind = %q, want %q", name, got[name], kind)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractConfigUsageReaders(t *testing.T) {
+	src := `package main
+
+var region = os.Getenv("REGION")
+
+func connect() {
+	a := os.Getenv("DATABASE_URL")
+	b := os.Getenv("DATABASE_URL")
+}
+`
+	result := &ParseResult{
+		FilePath: "api/db.go",
+		Language: LangGo,
+		Nodes: []*graph.Node{
+			{ID: "file", Type: graph.NodeFile, FilePath: "api/db.go"},
+			{ID: "connect", Type: graph.NodeFunction, Name: "connect", FilePath: "api/db.go", Line: 5, EndLine: 8},
+		},
+	}
+	ExtractConfigUsage(result, []byte(src))
+
+	reads := make(map[string]string) // config name -> reader ID
+	names := make(map[string]string)
+	for _, n := range result.Nodes {
+		names[n.ID] = n.Name
+	}
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeReads {
+			if prev, dup := reads[names[e.TargetID]]; dup {
+				t.Errorf("duplicate Reads edge to %s (from %s and %s)", names[e.TargetID], prev, e.SourceID)
+			}
+			reads[names[e.TargetID]] = e.SourceID
+		}
+	}
+	if reads["REGION"] != "file" || reads["DATABASE_URL"] != "connect" {
+		t.Errorf("Reads edges = %v, want REGION from file and DATABASE_URL from connect", reads)
+	}
+}
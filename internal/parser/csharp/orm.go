@@ -0,0 +1,121 @@
+package csharp
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// navigationCollections are the collection types of Entity Framework
+// collection navigation properties.
+var navigationCollections = map[string]bool{
+	"ICollection": true, "IList": true, "List": true, "HashSet": true, "ISet": true,
+	"IEnumerable": true, "Collection": true, "IReadOnlyCollection": true, "IReadOnlyList": true,
+}
+
+// scalarTypes are named property types that hold column values rather
+// than navigations.
+var scalarTypes = map[string]bool{
+	"String": true, "DateTime": true, "DateTimeOffset": true, "DateOnly": true, "TimeOnly": true,
+	"TimeSpan": true, "Guid": true, "Decimal": true, "Uri": true, "Byte": true, "Int32": true, "Int64": true,
+}
+
+// navigationRelations reads the Entity Framework navigation properties of a
+// class body. A collection of a class (ICollection<Order>) is one-to-many;
+// a reference to a class is many-to-one when the class holds its foreign
+// key (a property named after the navigation suffixed with Id, or a
+// [ForeignKey] attribute) and one-to-one otherwise. Whether the class and
+// its targets are entities is left to the linker, which knows the DbSet
+// properties of every DbContext.
+func (e *extractor) navigationRelations(body *sitter.Node) []parser.ModelRelation {
+	type navigation struct {
+		name, target string
+		collection   bool
+		foreignKey   bool
+	}
+	var navs []navigation
+	names := make(map[string]bool)
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		prop := body.NamedChild(i)
+		if prop.Type() != "property_declaration" {
+			continue
+		}
+		nameNode, typeNode := prop.ChildByFieldName("name"), prop.ChildByFieldName("type")
+		if nameNode == nil || typeNode == nil {
+			continue
+		}
+		name := e.nodeText(nameNode)
+		names[name] = true
+
+		var annotations []string
+		for j := 0; j < int(prop.NamedChildCount()); j++ {
+			if child := prop.NamedChild(j); child.Type() == "attribute_list" {
+				annotations = append(annotations, e.extractAttributes(child)...)
+			}
+		}
+		if hasAnnotation(annotations, "NotMapped") {
+			continue
+		}
+
+		target, collection := navigationTarget(e.nodeText(typeNode))
+		if target == "" {
+			continue
+		}
+		navs = append(navs, navigation{
+			name:       name,
+			target:     target,
+			collection: collection,
+			foreignKey: hasAnnotation(annotations, "ForeignKey"),
+		})
+	}
+
+	var relations []parser.ModelRelation
+	for _, n := range navs {
+		r := parser.ModelRelation{Field: n.name, Target: n.target}
+		switch {
+		case n.collection:
+			r.Cardinality = parser.CardinalityOneToMany
+		case n.foreignKey || names[n.name+"Id"] || names[n.name+"ID"]:
+			r.Cardinality = parser.CardinalityManyToOne
+		default:
+			r.Cardinality = parser.CardinalityOneToOne
+		}
+		relations = append(relations, r)
+	}
+	return relations
+}
+
+// navigationTarget returns the class a property type navigates to and
+// whether it is a collection: "ICollection<Order>" yields Order and true,
+// "Customer?" yields Customer and false. Built-in, scalar and other
+// generic types yield "".
+func navigationTarget(typ string) (string, bool) {
+	typ = strings.TrimSuffix(strings.TrimSpace(typ), "?")
+	collection := false
+	if open := strings.Index(typ, "<"); open > 0 && strings.HasSuffix(typ, ">") {
+		if !navigationCollections[lastSegment(typ[:open])] {
+			return "", false
+		}
+		typ, collection = strings.TrimSuffix(strings.TrimSpace(typ[open+1:len(typ)-1]), "?"), true
+	}
+	typ = lastSegment(typ)
+	if typ == "" || typ[0] < 'A' || typ[0] > 'Z' || scalarTypes[typ] {
+		return "", false
+	}
+	for _, r := range typ {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return "", false
+		}
+	}
+	return typ, collection
+}
+
+// lastSegment returns the last dotted segment of a qualified name.
+func lastSegment(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -14,13 +16,20 @@ import (
 )
 
 // CSharpParser extracts knowledge graph nodes and edges from C# source files.
-type CSharpParser struct{}
+type CSharpParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new C# parser.
 func NewParser() *CSharpParser {
 	return &CSharpParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *CSharpParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *CSharpParser) Language() parser.Language {
 	return parser.LangCSharp
 }
@@ -40,17 +49,19 @@ func (p *CSharpParser) ParseFile(filePath string, content []byte) (*parser.Parse
 	}
 
 	e := &extractor{
-		filePath: filePath,
-		content:  content,
-		tree:     tree,
+		filePath:     filePath,
+		content:      content,
+		tree:         tree,
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
 	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangCSharp,
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangCSharp,
+		Diagnostics: parser.TreeSitterDiagnostics(tree.RootNode(), content),
 	}, nil
 }
 
@@ -62,31 +73,109 @@ type extractor struct {
 	nodes    []*graph.Node
 	edges    []*graph.Edge
 
-	nsNodeID   string
-	fileNodeID string
-	nsName     string
-	isTestFile bool
+	nsNodeID     string
+	fileNodeID   string
+	nsName       string
+	isTestFile   bool
+	testPatterns *parser.TestPatterns
+	partials     map[string]*graph.Node // class node ID -> node, for partial classes
 
 	// Lookup maps for function call resolution (built after walkProgram)
-	importMap      map[string]string            // simple class name -> dep node ID
-	classMethodMap map[string]map[string]string // className -> methodName -> node ID
+	importMap      map[string]string                   // simple class name -> dep node ID
+	classMethodMap map[string]map[string][]*graph.Node // className -> methodName -> overloads
+	methodIDs      map[string]string                   // scope + qualified signature -> node ID
+	memberTypes    map[string]map[string]string        // className -> field/property name -> type
+	memberCalls    map[string][]string                 // method node ID -> "Type.Method/argc" calls on typed members
+
+	// scope lists the enclosing type names while a type body is walked.
+	scope []string
 }
 
 func (e *extractor) extract() {
+	e.partials = make(map[string]*graph.Node)
 	e.extractFileNode()
 
 	root := e.tree.RootNode()
 	// First pass: extract all declarations
 	e.walkProgram(root)
+	// Point implements at interfaces declared in this file
+	e.nodes, e.edges = parser.BindLocal(e.nodes, e.edges)
 	// Build lookup maps
 	e.buildCallMaps()
 	// Second pass: walk method bodies for function calls and HTTP client calls
 	e.walkMethodBodies(root)
+	e.attachMemberCalls()
+	// DI container registrations may appear in top-level statements,
+	// Startup.ConfigureServices, or IServiceCollection extension methods.
+	e.extractDIRegistrations(root)
+}
+
+// symbolID returns the ID of a symbol declared within scope. Types nested
+// in other types get scoped IDs so same-named nested types (Order.Builder,
+// Invoice.Builder) stay distinct; top-level symbols keep plain IDs. Method
+// names carry their parameter types, which tells overloads apart.
+func (e *extractor) symbolID(nodeType graph.NodeType, scope []string, name string) string {
+	return graph.SymbolKey{
+		Language: string(parser.LangCSharp),
+		Type:     nodeType,
+		FilePath: e.filePath,
+		Scope:    scope,
+		Name:     name,
+	}.ID()
+}
+
+func (e *extractor) pushScope(name string) { e.scope = append(e.scope, name) }
+func (e *extractor) popScope()             { e.scope = e.scope[:len(e.scope)-1] }
+
+// memberScope is the scope of the members of the type being walked: the
+// types enclosing it, since member names are already qualified with the
+// type's own name.
+func (e *extractor) memberScope() []string {
+	if len(e.scope) == 0 {
+		return nil
+	}
+	return e.scope[:len(e.scope)-1]
+}
+
+// typeQualifiedName returns the C# qualified name of a type declared in
+// the current scope, e.g. Acme.Order.Builder.
+func (e *extractor) typeQualifiedName(name string) string {
+	parts := append(append([]string(nil), e.scope...), name)
+	if e.nsName != "" {
+		parts = append([]string{e.nsName}, parts...)
+	}
+	return strings.Join(parts, ".")
+}
+
+// scopeProps returns node properties recording a non-empty scope as
+// Properties["scope"] (enclosing type names joined with ".").
+func scopeProps(scope []string) map[string]string {
+	props := make(map[string]string)
+	if len(scope) > 0 {
+		props["scope"] = strings.Join(scope, ".")
+	}
+	return props
+}
+
+// splitScope reverses the Properties["scope"] encoding.
+func splitScope(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ".")
+}
+
+// methodKey keys methodIDs by scope and qualified signature.
+func methodKey(scope []string, qualifiedName string) string {
+	if len(scope) == 0 {
+		return qualifiedName
+	}
+	return strings.Join(scope, ".") + "$" + qualifiedName
 }
 
 func (e *extractor) extractFileNode() {
 	base := filepath.Base(e.filePath)
-	e.isTestFile = isTestFilename(base)
+	e.isTestFile = isTestFilename(base) || e.testPatterns.MatchesFile(e.filePath)
 
 	fileType := graph.NodeFile
 	if e.isTestFile {
@@ -115,16 +204,19 @@ func isTestFilename(base string) bool {
 }
 
 func (e *extractor) walkProgram(root *sitter.Node) {
+	var globalStatements []*sitter.Node
 	for i := 0; i < int(root.NamedChildCount()); i++ {
 		child := root.NamedChild(i)
 		switch child.Type() {
+		case "global_statement":
+			globalStatements = append(globalStatements, child)
 		case "using_directive":
 			e.extractUsing(child)
 		case "namespace_declaration":
 			e.extractNamespace(child)
 		case "file_scoped_namespace_declaration":
 			e.extractFileScopedNamespace(child)
-		case "class_declaration":
+		case "class_declaration", "record_declaration":
 			e.extractClass(child, e.parentID())
 		case "interface_declaration":
 			e.extractInterface(child, e.parentID())
@@ -134,6 +226,10 @@ func (e *extractor) walkProgram(root *sitter.Node) {
 			e.extractEnum(child, e.parentID())
 		}
 	}
+	// Top-level statements (Program.cs) may register minimal API endpoints.
+	if len(globalStatements) > 0 {
+		e.extractMinimalAPIs(globalStatements)
+	}
 }
 
 func (e *extractor) parentID() string {
@@ -223,7 +319,7 @@ func (e *extractor) extractFileScopedNamespace(node *sitter.Node) {
 		switch child.Type() {
 		case "using_directive":
 			e.extractUsing(child)
-		case "class_declaration":
+		case "class_declaration", "record_declaration":
 			e.extractClass(child, e.nsNodeID)
 		case "interface_declaration":
 			e.extractInterface(child, e.nsNodeID)
@@ -239,7 +335,7 @@ func (e *extractor) walkDeclarationList(body *sitter.Node) {
 	for i := 0; i < int(body.NamedChildCount()); i++ {
 		child := body.NamedChild(i)
 		switch child.Type() {
-		case "class_declaration":
+		case "class_declaration", "record_declaration":
 			e.extractClass(child, e.parentID())
 		case "interface_declaration":
 			e.extractInterface(child, e.parentID())
@@ -264,32 +360,22 @@ func (e *extractor) extractUsing(node *sitter.Node) {
 		return
 	}
 
-	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, name)
-
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
+	dep, edge := parser.Import{
+		Language: parser.LangCSharp,
 		Name:     name,
 		FilePath: e.filePath,
 		Line:     int(node.StartPoint().Row) + 1,
-		Language: string(parser.LangCSharp),
-		Package:  e.nsName,
-		Properties: map[string]string{
-			"kind": "import",
-		},
-	})
-
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.parentID(), depID, string(graph.EdgeImports)),
-		Type:     graph.EdgeImports,
-		SourceID: e.parentID(),
-		TargetID: depID,
-	})
+	}.Link(e.parentID())
+	e.nodes = append(e.nodes, dep)
+	e.edges = append(e.edges, edge)
 }
 
+// extractClass extracts a class or record declaration. Records become classes
+// with a "record" property; partial declarations of the same class in a file
+// are merged into one node, and primary-constructor parameters become fields.
 func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 	name := ""
-	var bodyNode *sitter.Node
+	var bodyNode, primaryParams *sitter.Node
 	var baseTypes []string
 	var annotations []string
 	modifiers := ""
@@ -310,6 +396,8 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 			annotations = append(annotations, e.extractAttributes(child)...)
 		case "base_list":
 			baseTypes = e.extractBaseList(child)
+		case "parameter_list":
+			primaryParams = child
 		case "declaration_list":
 			bodyNode = child
 		}
@@ -321,16 +409,35 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
+	isRecord := node.Type() == "record_declaration"
+
+	qualifiedName := e.typeQualifiedName(name)
+
+	// Partial declarations are keyed by qualified name so that every part
+	// in the file resolves to the same node.
+	classID := e.symbolID(graph.NodeClass, e.scope, name)
+	isPartial := hasModifier(modifiers, "partial")
+	if isPartial {
+		classID = e.symbolID(graph.NodeClass, e.scope, qualifiedName)
+		if existing := e.partials[classID]; existing != nil {
+			e.mergePartialClass(node, existing, primaryParams, bodyNode, baseTypes, annotations)
+			return
+		}
+	}
 
-	classID := graph.NewNodeID(string(graph.NodeClass), e.filePath, name)
-
-	props := make(map[string]string)
+	props := scopeProps(e.scope)
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
 	if len(annotations) > 0 {
 		props["annotations"] = strings.Join(annotations, ",")
 	}
+	if isRecord {
+		props["record"] = "true"
+	}
+	if isPartial {
+		props["partial"] = "true"
+	}
 
 	// Separate base class and interfaces.
 	// In C#, the first base type could be a class or interface.
@@ -349,17 +456,21 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 
 	if extends != "" {
 		props["extends"] = extends
+		if parser.IsErrorBase(extends) {
+			props[parser.PropErrorType] = "true"
+		}
 	}
 	if len(implements) > 0 {
 		props["implements"] = strings.Join(implements, ",")
 	}
-
-	qualifiedName := name
-	if e.nsName != "" {
-		qualifiedName = e.nsName + "." + name
+	if bodyNode != nil {
+		if relations := e.navigationRelations(bodyNode); len(relations) > 0 {
+			props[parser.PropORM] = "ef"
+			props[parser.PropModelRelations] = parser.FormatModelRelations(relations)
+		}
 	}
 
-	e.nodes = append(e.nodes, &graph.Node{
+	classNode := &graph.Node{
 		ID:            classID,
 		Type:          graph.NodeClass,
 		Name:          name,
@@ -372,7 +483,11 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 		Exported:      isPublicOrInternal(modifiers),
 		DocComment:    docComment,
 		Properties:    props,
-	})
+	}
+	e.nodes = append(e.nodes, classNode)
+	if isPartial {
+		e.partials[classID] = classNode
+	}
 
 	e.edges = append(e.edges, &graph.Edge{
 		ID:       edgeID(parentID, classID, string(graph.EdgeContains)),
@@ -382,24 +497,133 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 	})
 
 	// Implements edges for interfaces
-	for _, iface := range implements {
-		ifaceID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, iface)
-		e.edges = append(e.edges, &graph.Edge{
-			ID:       edgeID(classID, ifaceID, string(graph.EdgeImplements)),
-			Type:     graph.EdgeImplements,
-			SourceID: classID,
-			TargetID: ifaceID,
-		})
-	}
+	e.addImplementsEdges(classID, implements, startLine)
 
-	// Check if this is an ASP.NET controller
-	isController := hasAnnotation(annotations, "ApiController") ||
-		strings.HasSuffix(name, "Controller") ||
-		extends == "ControllerBase" || extends == "Controller"
+	e.pushScope(name)
+	defer e.popScope()
+	if primaryParams != nil {
+		e.extractPrimaryConstructorParams(primaryParams, classID, name, isRecord)
+	}
 
 	// Walk class body
 	if bodyNode != nil {
-		e.walkClassBody(bodyNode, classID, name, isController)
+		e.walkClassBody(bodyNode, classID, name, isControllerClass(classNode))
+	}
+}
+
+// mergePartialClass folds another partial declaration of a class in the same
+// file into its existing node: base types and attributes are unioned, the
+// line range widened, and the declaration's members attached to the node.
+func (e *extractor) mergePartialClass(node *sitter.Node, classNode *graph.Node, primaryParams, bodyNode *sitter.Node, baseTypes, annotations []string) {
+	props := classNode.Properties
+	if endLine := int(node.EndPoint().Row) + 1; endLine > classNode.EndLine {
+		classNode.EndLine = endLine
+	}
+	if classNode.DocComment == "" {
+		classNode.DocComment = e.extractDocComment(node)
+	}
+	props["annotations"] = joinUnique(props["annotations"], annotations)
+	if props["annotations"] == "" {
+		delete(props, "annotations")
+	}
+
+	var implements []string
+	for _, bt := range baseTypes {
+		if strings.HasPrefix(bt, "I") && len(bt) > 1 && bt[1] >= 'A' && bt[1] <= 'Z' {
+			implements = append(implements, bt)
+		} else if props["extends"] == "" {
+			props["extends"] = bt
+		} else if bt != props["extends"] {
+			implements = append(implements, bt)
+		}
+	}
+	if parser.IsErrorBase(props["extends"]) {
+		props[parser.PropErrorType] = "true"
+	}
+	if len(implements) > 0 {
+		props["implements"] = joinUnique(props["implements"], implements)
+		e.addImplementsEdges(classNode.ID, implements, int(node.StartPoint().Row)+1)
+	}
+
+	e.pushScope(classNode.Name)
+	defer e.popScope()
+	if primaryParams != nil {
+		e.extractPrimaryConstructorParams(primaryParams, classNode.ID, classNode.Name, props["record"] == "true")
+	}
+	if bodyNode != nil {
+		if relations := e.navigationRelations(bodyNode); len(relations) > 0 {
+			props[parser.PropORM] = "ef"
+			props[parser.PropModelRelations] = joinUnique(props[parser.PropModelRelations], strings.Split(parser.FormatModelRelations(relations), ","))
+		}
+		e.walkClassBody(bodyNode, classNode.ID, classNode.Name, isControllerClass(classNode))
+	}
+}
+
+// isControllerClass reports whether a class node is an ASP.NET controller.
+func isControllerClass(classNode *graph.Node) bool {
+	extends := classNode.Properties["extends"]
+	return hasAnnotation(strings.Split(classNode.Properties["annotations"], ","), "ApiController") ||
+		strings.HasSuffix(classNode.Name, "Controller") ||
+		extends == "ControllerBase" || extends == "Controller"
+}
+
+func (e *extractor) addImplementsEdges(ownerID string, implements []string, line int) {
+	for _, iface := range implements {
+		ref, edge := parser.Unresolved{
+			Language:   parser.LangCSharp,
+			TargetType: graph.NodeInterface,
+			Name:       iface,
+			FilePath:   e.filePath,
+			Line:       line,
+		}.Link(graph.EdgeImplements, ownerID)
+		e.nodes = append(e.nodes, ref)
+		e.edges = append(e.edges, edge)
+	}
+}
+
+// extractPrimaryConstructorParams extracts the parameters of a primary
+// constructor (class Service(ILogger logger)) or positional record
+// (record Person(string Name)) as fields of the type. Record parameters are
+// public properties; class parameters are captured privately.
+func (e *extractor) extractPrimaryConstructorParams(params *sitter.Node, ownerID, className string, isRecord bool) {
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != "parameter" {
+			continue
+		}
+		nameNode, typeNode := param.ChildByFieldName("name"), param.ChildByFieldName("type")
+		if nameNode == nil {
+			continue
+		}
+		varName := e.nodeText(nameNode)
+		qualifiedName := className + "." + varName
+
+		props := scopeProps(e.memberScope())
+		props["class"] = className
+		props["primary_constructor"] = "true"
+		if typeNode != nil {
+			props["type"] = e.nodeText(typeNode)
+		}
+
+		varID := e.symbolID(graph.NodeVariable, e.memberScope(), qualifiedName)
+		e.nodes = append(e.nodes, &graph.Node{
+			ID:            varID,
+			Type:          graph.NodeVariable,
+			Name:          varName,
+			QualifiedName: qualifiedName,
+			FilePath:      e.filePath,
+			Line:          int(param.StartPoint().Row) + 1,
+			Package:       e.nsName,
+			Language:      string(parser.LangCSharp),
+			Exported:      isRecord,
+			Properties:    props,
+		})
+		e.edges = append(e.edges, &graph.Edge{
+			ID:       edgeID(ownerID, varID, string(graph.EdgeContains)),
+			Type:     graph.EdgeContains,
+			SourceID: ownerID,
+			TargetID: varID,
+		})
 	}
 }
 
@@ -435,9 +659,9 @@ func (e *extractor) extractInterface(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	ifaceID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, name)
+	ifaceID := e.symbolID(graph.NodeInterface, e.scope, name)
 
-	props := make(map[string]string)
+	props := scopeProps(e.scope)
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -462,10 +686,7 @@ func (e *extractor) extractInterface(node *sitter.Node, parentID string) {
 		props["methods"] = strings.Join(methodNames, ",")
 	}
 
-	qualifiedName := name
-	if e.nsName != "" {
-		qualifiedName = e.nsName + "." + name
-	}
+	qualifiedName := e.typeQualifiedName(name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            ifaceID,
@@ -491,7 +712,9 @@ func (e *extractor) extractInterface(node *sitter.Node, parentID string) {
 
 	// Extract methods from interface body
 	if bodyNode != nil {
+		e.pushScope(name)
 		e.walkInterfaceBody(bodyNode, ifaceID, name)
+		e.popScope()
 	}
 }
 
@@ -530,9 +753,9 @@ func (e *extractor) extractStruct(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	structID := graph.NewNodeID(string(graph.NodeStruct), e.filePath, name)
+	structID := e.symbolID(graph.NodeStruct, e.scope, name)
 
-	props := make(map[string]string)
+	props := scopeProps(e.scope)
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -543,10 +766,7 @@ func (e *extractor) extractStruct(node *sitter.Node, parentID string) {
 		props["implements"] = strings.Join(baseTypes, ",")
 	}
 
-	qualifiedName := name
-	if e.nsName != "" {
-		qualifiedName = e.nsName + "." + name
-	}
+	qualifiedName := e.typeQualifiedName(name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            structID,
@@ -570,19 +790,12 @@ func (e *extractor) extractStruct(node *sitter.Node, parentID string) {
 		TargetID: structID,
 	})
 
-	// Implements edges
-	for _, iface := range baseTypes {
-		ifaceID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, iface)
-		e.edges = append(e.edges, &graph.Edge{
-			ID:       edgeID(structID, ifaceID, string(graph.EdgeImplements)),
-			Type:     graph.EdgeImplements,
-			SourceID: structID,
-			TargetID: ifaceID,
-		})
-	}
+	e.addImplementsEdges(structID, baseTypes, startLine)
 
 	if bodyNode != nil {
+		e.pushScope(name)
 		e.walkClassBody(bodyNode, structID, name, false)
+		e.popScope()
 	}
 }
 
@@ -615,9 +828,9 @@ func (e *extractor) extractEnum(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	enumID := graph.NewNodeID(string(graph.NodeEnum), e.filePath, name)
+	enumID := e.symbolID(graph.NodeEnum, e.scope, name)
 
-	props := make(map[string]string)
+	props := scopeProps(e.scope)
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -647,10 +860,7 @@ func (e *extractor) extractEnum(node *sitter.Node, parentID string) {
 		props["constants"] = strings.Join(members, ",")
 	}
 
-	qualifiedName := name
-	if e.nsName != "" {
-		qualifiedName = e.nsName + "." + name
-	}
+	qualifiedName := e.typeQualifiedName(name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            enumID,
@@ -687,7 +897,7 @@ func (e *extractor) walkClassBody(body *sitter.Node, ownerID, className string,
 			e.extractField(child, ownerID, className)
 		case "property_declaration":
 			e.extractProperty(child, ownerID, className)
-		case "class_declaration":
+		case "class_declaration", "record_declaration":
 			e.extractClass(child, ownerID)
 		case "interface_declaration":
 			e.extractInterface(child, ownerID)
@@ -742,6 +952,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string,
 	name := ""
 	returnType := ""
 	params := ""
+	var paramsNode *sitter.Node
 	var annotations []string
 	modifiers := ""
 
@@ -773,6 +984,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string,
 			annotations = append(annotations, e.extractAttributes(child)...)
 		case "parameter_list":
 			params = e.nodeText(child)
+			paramsNode = child
 		case "predefined_type", "generic_name", "nullable_type",
 			"array_type", "qualified_name":
 			returnType = e.nodeText(child)
@@ -797,11 +1009,12 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string,
 
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
-	qualifiedName := className + "." + name
+	paramTypes, arity := e.paramSignature(paramsNode)
+	qualifiedName := parser.MethodSignatureKey(className, name, paramTypes)
 
 	sig := returnType + " " + name + params
 
-	props := make(map[string]string)
+	props := scopeProps(e.memberScope())
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -809,14 +1022,15 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string,
 		props["annotations"] = strings.Join(annotations, ",")
 	}
 	props["class"] = className
+	props["arity"] = arity.String()
 
 	// Determine if this is a test method
 	nodeType := graph.NodeMethod
-	if e.isTestFile && hasTestAnnotation(annotations) {
+	if e.isTestFile && (hasTestAnnotation(annotations) || e.testPatterns.MatchesAnnotation(annotations)) {
 		nodeType = graph.NodeTestFunction
 	}
 
-	methodID := graph.NewNodeID(string(nodeType), e.filePath, qualifiedName)
+	methodID := e.symbolID(nodeType, e.memberScope(), qualifiedName)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            methodID,
@@ -850,6 +1064,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string,
 func (e *extractor) extractConstructor(node *sitter.Node, parentID, className string) {
 	name := ""
 	params := ""
+	var paramsNode *sitter.Node
 	var annotations []string
 	modifiers := ""
 
@@ -869,6 +1084,7 @@ func (e *extractor) extractConstructor(node *sitter.Node, parentID, className st
 			annotations = append(annotations, e.extractAttributes(child)...)
 		case "parameter_list":
 			params = e.nodeText(child)
+			paramsNode = child
 		}
 	}
 
@@ -878,11 +1094,12 @@ func (e *extractor) extractConstructor(node *sitter.Node, parentID, className st
 
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
-	qualifiedName := className + "." + name
+	paramTypes, arity := e.paramSignature(paramsNode)
+	qualifiedName := parser.MethodSignatureKey(className, name, paramTypes)
 
 	sig := name + params
 
-	props := make(map[string]string)
+	props := scopeProps(e.memberScope())
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -891,8 +1108,9 @@ func (e *extractor) extractConstructor(node *sitter.Node, parentID, className st
 	}
 	props["class"] = className
 	props["constructor"] = "true"
+	props["arity"] = arity.String()
 
-	methodID := graph.NewNodeID(string(graph.NodeMethod), e.filePath, qualifiedName)
+	methodID := e.symbolID(graph.NodeMethod, e.memberScope(), qualifiedName)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            methodID,
@@ -964,7 +1182,7 @@ func (e *extractor) extractField(node *sitter.Node, parentID, className string)
 					line := int(node.StartPoint().Row) + 1
 					qualifiedName := className + "." + varName
 
-					props := make(map[string]string)
+					props := scopeProps(e.memberScope())
 					if modifiers != "" {
 						props["modifiers"] = modifiers
 					}
@@ -981,7 +1199,7 @@ func (e *extractor) extractField(node *sitter.Node, parentID, className string)
 						nodeType = graph.NodeConstant
 					}
 
-					varID := graph.NewNodeID(string(nodeType), e.filePath, qualifiedName)
+					varID := e.symbolID(nodeType, e.memberScope(), qualifiedName)
 
 					e.nodes = append(e.nodes, &graph.Node{
 						ID:            varID,
@@ -1036,11 +1254,18 @@ func (e *extractor) extractProperty(node *sitter.Node, parentID, className strin
 	if name == "" {
 		return
 	}
+	if propType == "" {
+		// Named types are plain identifiers, indistinguishable from the
+		// property name without the field name.
+		if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+			propType = e.nodeText(typeNode)
+		}
+	}
 
 	line := int(node.StartPoint().Row) + 1
 	qualifiedName := className + "." + name
 
-	props := make(map[string]string)
+	props := scopeProps(e.memberScope())
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -1053,7 +1278,7 @@ func (e *extractor) extractProperty(node *sitter.Node, parentID, className strin
 	props["class"] = className
 	props["property"] = "true"
 
-	varID := graph.NewNodeID(string(graph.NodeVariable), e.filePath, qualifiedName)
+	varID := e.symbolID(graph.NodeVariable, e.memberScope(), qualifiedName)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            varID,
@@ -1077,21 +1302,25 @@ func (e *extractor) extractProperty(node *sitter.Node, parentID, className strin
 }
 
 // extractAPIEndpoints extracts ASP.NET API endpoint nodes from controller method attributes.
+// The class-level [Route] template is combined with the template of the
+// HTTP verb attribute, or of a [Route] on the method; a template starting
+// with "/" or "~/" overrides the class route, and the [controller] and
+// [action] tokens are replaced.
 func (e *extractor) extractAPIEndpoints(node *sitter.Node, annotations []string, methodID, className, methodName string, line int) {
 	// Find the class-level [Route] attribute for base path
 	classRoute := e.findClassRoute(node)
 
+	methodRoute := ""
+	for _, ann := range annotations {
+		if strings.HasPrefix(ann, "Route(") {
+			methodRoute = attributeTemplate(ann)
+		}
+	}
+
 	for _, ann := range annotations {
-		// Parse annotation name and argument
 		annName := ann
-		annArg := ""
 		if idx := strings.Index(ann, "("); idx > 0 {
 			annName = ann[:idx]
-			end := strings.LastIndex(ann, ")")
-			if end > idx {
-				annArg = ann[idx+1 : end]
-				annArg = strings.Trim(annArg, "\"")
-			}
 		}
 
 		httpMethod, ok := aspnetHTTPAttributes[annName]
@@ -1099,41 +1328,31 @@ func (e *extractor) extractAPIEndpoints(node *sitter.Node, annotations []string,
 			continue
 		}
 
-		// Build the route path
-		path := classRoute
-		if annArg != "" {
-			if path != "" && !strings.HasSuffix(path, "/") {
-				path += "/"
-			}
-			path += annArg
-		}
-		if path == "" {
-			path = "/" + methodName
-		}
-
-		// Normalize path to start with /
-		if !strings.HasPrefix(path, "/") {
-			path = "/" + path
+		template := attributeTemplate(ann)
+		if template == "" {
+			template = methodRoute
 		}
+		path := aspnetRoutePath(classRoute, template, className, methodName)
 
 		endpointName := httpMethod + " " + path
 		endpointID := graph.NewNodeID(string(graph.NodeAPIEndpoint), e.filePath,
 			endpointName+":"+fmt.Sprintf("%d", line))
 
+		props := map[string]string{
+			"http_method": httpMethod,
+			"path":        path,
+			"controller":  className,
+			"action":      methodName,
+		}
 		e.nodes = append(e.nodes, &graph.Node{
-			ID:       endpointID,
-			Type:     graph.NodeAPIEndpoint,
-			Name:     endpointName,
-			FilePath: e.filePath,
-			Line:     line,
-			Package:  e.nsName,
-			Language: string(parser.LangCSharp),
-			Properties: map[string]string{
-				"http_method": httpMethod,
-				"path":        path,
-				"controller":  className,
-				"action":      methodName,
-			},
+			ID:         endpointID,
+			Type:       graph.NodeAPIEndpoint,
+			Name:       endpointName,
+			FilePath:   e.filePath,
+			Line:       line,
+			Package:    e.nsName,
+			Language:   string(parser.LangCSharp),
+			Properties: props,
 		})
 
 		// EdgeExposes: method -> endpoint
@@ -1146,6 +1365,51 @@ func (e *extractor) extractAPIEndpoints(node *sitter.Node, annotations []string,
 	}
 }
 
+// attributeTemplate returns the route template an attribute such as
+// HttpGet("{id}", Name = "GetUser") or Route("api/[controller]") passes as
+// its first argument, or "" when it has none.
+func attributeTemplate(attr string) string {
+	open := strings.Index(attr, "(")
+	if open < 0 {
+		return ""
+	}
+	arg := strings.TrimSpace(attr[open+1:])
+	arg = strings.TrimPrefix(arg, "@")
+	if !strings.HasPrefix(arg, "\"") {
+		return ""
+	}
+	if end := strings.Index(arg[1:], "\""); end >= 0 {
+		return arg[1 : end+1]
+	}
+	return ""
+}
+
+// aspnetRoutePath combines a controller's route template with an action's
+// the way ASP.NET attribute routing does, replacing the [controller] and
+// [action] tokens. An action without either template is routed by its
+// name.
+func aspnetRoutePath(classRoute, template, className, methodName string) string {
+	path := template
+	switch {
+	case strings.HasPrefix(template, "~/"):
+		path = template[1:]
+	case strings.HasPrefix(template, "/"):
+	case classRoute != "" && template != "":
+		path = strings.TrimRight(classRoute, "/") + "/" + template
+	case classRoute != "":
+		path = classRoute
+	case template == "":
+		path = methodName
+	}
+
+	path = strings.ReplaceAll(path, "[controller]", strings.TrimSuffix(className, "Controller"))
+	path = strings.ReplaceAll(path, "[action]", methodName)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
+
 // findClassRoute walks up to the class node and extracts the [Route] attribute value.
 func (e *extractor) findClassRoute(methodNode *sitter.Node) string {
 	// Walk up to find the class_declaration parent
@@ -1167,12 +1431,7 @@ func (e *extractor) findClassRoute(methodNode *sitter.Node) string {
 			attrs := e.extractAttributes(child)
 			for _, attr := range attrs {
 				if strings.HasPrefix(attr, "Route(") {
-					end := strings.LastIndex(attr, ")")
-					if end > 6 {
-						route := attr[6:end]
-						route = strings.Trim(route, "\"")
-						return route
-					}
+					return attributeTemplate(attr)
 				}
 			}
 		}
@@ -1181,6 +1440,352 @@ func (e *extractor) findClassRoute(methodNode *sitter.Node) string {
 	return ""
 }
 
+// aspnetMinimalAPIMethods maps minimal API route builder methods to HTTP methods.
+var aspnetMinimalAPIMethods = map[string]string{
+	"MapGet":    "GET",
+	"MapPost":   "POST",
+	"MapPut":    "PUT",
+	"MapDelete": "DELETE",
+	"MapPatch":  "PATCH",
+}
+
+// extractMinimalAPIs detects ASP.NET Core minimal API registrations such as
+// app.MapGet("/path", handler) in top-level statements. Route groups created
+// with MapGroup and assigned to local variables are tracked in statement order
+// so their prefixes apply to endpoints registered on them later.
+func (e *extractor) extractMinimalAPIs(statements []*sitter.Node) {
+	groups := make(map[string]string) // variable name -> group prefix
+	for _, stmt := range statements {
+		e.walkMinimalAPIStatement(stmt, groups)
+	}
+}
+
+func (e *extractor) walkMinimalAPIStatement(node *sitter.Node, groups map[string]string) {
+	switch node.Type() {
+	case "variable_declarator":
+		// var api = app.MapGroup("/api");
+		nameNode := node.ChildByFieldName("name")
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			if child.Type() != "invocation_expression" || nameNode == nil {
+				continue
+			}
+			if prefix, ok := e.minimalAPIGroupPrefix(child, groups); ok {
+				groups[e.nodeText(nameNode)] = prefix
+			}
+		}
+	case "invocation_expression":
+		e.checkMinimalAPIMapCall(node, groups)
+	case "lambda_expression", "local_function_statement":
+		// Handler bodies don't register routes.
+		return
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		e.walkMinimalAPIStatement(node.NamedChild(i), groups)
+	}
+}
+
+// minimalAPIGroupPrefix returns the full route prefix if node is a MapGroup
+// call, optionally followed by fluent configuration calls such as
+// .WithTags("Users") or .RequireAuthorization().
+func (e *extractor) minimalAPIGroupPrefix(node *sitter.Node, groups map[string]string) (string, bool) {
+	fn := node.ChildByFieldName("function")
+	if fn == nil || fn.Type() != "member_access_expression" {
+		return "", false
+	}
+	nameNode := fn.ChildByFieldName("name")
+	recv := fn.ChildByFieldName("expression")
+	if nameNode == nil || recv == nil {
+		return "", false
+	}
+	if e.nodeText(nameNode) != "MapGroup" {
+		if recv.Type() == "invocation_expression" {
+			return e.minimalAPIGroupPrefix(recv, groups)
+		}
+		return "", false
+	}
+	segment := ""
+	if args := e.invocationArguments(node); len(args) > 0 {
+		segment, _ = e.csharpStringValue(args[0].NamedChild(0))
+	}
+	return joinRoute(e.minimalAPIReceiverPrefix(recv, groups), segment), true
+}
+
+// minimalAPIReceiverPrefix resolves the route prefix contributed by the
+// receiver of a Map* call: a tracked group variable or an inline MapGroup chain.
+func (e *extractor) minimalAPIReceiverPrefix(node *sitter.Node, groups map[string]string) string {
+	switch node.Type() {
+	case "identifier":
+		return groups[e.nodeText(node)]
+	case "invocation_expression":
+		if prefix, ok := e.minimalAPIGroupPrefix(node, groups); ok {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// checkMinimalAPIMapCall creates API endpoint nodes for MapGet/MapPost/
+// MapPut/MapDelete/MapPatch/MapMethods invocations.
+func (e *extractor) checkMinimalAPIMapCall(node *sitter.Node, groups map[string]string) {
+	fn := node.ChildByFieldName("function")
+	if fn == nil || fn.Type() != "member_access_expression" {
+		return
+	}
+	nameNode := fn.ChildByFieldName("name")
+	recv := fn.ChildByFieldName("expression")
+	if nameNode == nil || recv == nil {
+		return
+	}
+
+	args := e.invocationArguments(node)
+	if len(args) < 2 {
+		return
+	}
+
+	var httpMethods []string
+	methodName := e.nodeText(nameNode)
+	if m, ok := aspnetMinimalAPIMethods[methodName]; ok {
+		httpMethods = []string{m}
+	} else if methodName == "MapMethods" && len(args) >= 3 {
+		// app.MapMethods("/path", new[] { "PATCH", "HEAD" }, handler)
+		for _, m := range e.collectStringLiterals(args[1]) {
+			httpMethods = append(httpMethods, strings.ToUpper(m))
+		}
+	}
+	if len(httpMethods) == 0 {
+		return
+	}
+
+	route, ok := e.csharpStringValue(args[0].NamedChild(0))
+	if !ok {
+		return
+	}
+	prefix := e.minimalAPIReceiverPrefix(recv, groups)
+	path := joinRoute(prefix, route)
+
+	handler := ""
+	if h := args[len(args)-1].NamedChild(0); h != nil {
+		switch h.Type() {
+		case "identifier", "member_access_expression":
+			handler = e.nodeText(h)
+		case "lambda_expression", "anonymous_method_expression":
+			handler = "lambda"
+		}
+	}
+
+	line := int(node.StartPoint().Row) + 1
+	for _, httpMethod := range httpMethods {
+		endpointName := httpMethod + " " + path
+		endpointID := graph.NewNodeID(string(graph.NodeAPIEndpoint), e.filePath,
+			endpointName+":"+fmt.Sprintf("%d", line))
+
+		props := map[string]string{
+			"http_method": httpMethod,
+			"path":        path,
+			"framework":   "aspnet_minimal",
+		}
+		if handler != "" {
+			props["handler"] = handler
+		}
+		if prefix != "" {
+			props["route_group"] = prefix
+		}
+
+		e.nodes = append(e.nodes, &graph.Node{
+			ID:         endpointID,
+			Type:       graph.NodeAPIEndpoint,
+			Name:       endpointName,
+			FilePath:   e.filePath,
+			Line:       line,
+			Package:    e.nsName,
+			Language:   string(parser.LangCSharp),
+			Properties: props,
+		})
+
+		// EdgeExposes: file -> endpoint
+		e.edges = append(e.edges, &graph.Edge{
+			ID:       edgeID(e.fileNodeID, endpointID, string(graph.EdgeExposes)),
+			Type:     graph.EdgeExposes,
+			SourceID: e.fileNodeID,
+			TargetID: endpointID,
+		})
+	}
+}
+
+// extractDIRegistrations detects Microsoft.Extensions.DependencyInjection
+// service registrations such as services.AddScoped<IFoo, Foo>() or
+// services.AddSingleton(typeof(IFoo), typeof(Foo)) and records each as a
+// Dependency node (kind=di_registration) for the linker to resolve.
+func (e *extractor) extractDIRegistrations(node *sitter.Node) {
+	if node.Type() == "invocation_expression" {
+		e.checkDIRegistration(node)
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		e.extractDIRegistrations(node.NamedChild(i))
+	}
+}
+
+func (e *extractor) checkDIRegistration(node *sitter.Node) {
+	fn := node.ChildByFieldName("function")
+	if fn == nil || fn.Type() != "member_access_expression" {
+		return
+	}
+	nameNode := fn.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+
+	var method string
+	var typeArgs []string
+	switch nameNode.Type() {
+	case "identifier":
+		method = e.nodeText(nameNode)
+	case "generic_name":
+		for i := 0; i < int(nameNode.NamedChildCount()); i++ {
+			child := nameNode.NamedChild(i)
+			switch child.Type() {
+			case "identifier":
+				method = e.nodeText(child)
+			case "type_argument_list":
+				for j := 0; j < int(child.NamedChildCount()); j++ {
+					typeArgs = append(typeArgs, e.nodeText(child.NamedChild(j)))
+				}
+			}
+		}
+	}
+	lifetime := diLifetime(method)
+	if lifetime == "" {
+		return
+	}
+
+	if len(typeArgs) == 0 {
+		// services.AddScoped(typeof(IRepository<>), typeof(Repository<>))
+		for _, arg := range e.invocationArguments(node) {
+			if t := arg.NamedChild(0); t != nil && t.Type() == "typeof_expression" {
+				if typ := t.ChildByFieldName("type"); typ != nil {
+					typeArgs = append(typeArgs, e.nodeText(typ))
+				}
+			}
+		}
+	}
+	// A single type argument registers a concrete type as itself; only
+	// service/implementation pairs tell us which class backs an interface.
+	if len(typeArgs) != 2 {
+		return
+	}
+	service, impl := simpleTypeName(typeArgs[0]), simpleTypeName(typeArgs[1])
+	if service == "" || impl == "" || service == impl {
+		return
+	}
+
+	line := int(node.StartPoint().Row) + 1
+	name := service + " => " + impl
+	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, fmt.Sprintf("%s:%d", name, line))
+
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:       depID,
+		Type:     graph.NodeDependency,
+		Name:     name,
+		FilePath: e.filePath,
+		Line:     line,
+		Language: string(parser.LangCSharp),
+		Package:  e.nsName,
+		Properties: map[string]string{
+			"kind":           "di_registration",
+			"service":        service,
+			"implementation": impl,
+			"lifetime":       lifetime,
+		},
+	})
+
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.fileNodeID, depID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.fileNodeID,
+		TargetID: depID,
+	})
+}
+
+// diLifetime returns the service lifetime registered by an IServiceCollection
+// method (AddScoped, TryAddSingleton, AddKeyedTransient, ...), or "" if the
+// method is not a registration.
+func diLifetime(method string) string {
+	name := strings.TrimPrefix(method, "Try")
+	if !strings.HasPrefix(name, "Add") {
+		return ""
+	}
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "Add"), "Keyed")
+	switch name {
+	case "Scoped", "Transient", "Singleton":
+		return strings.ToLower(name)
+	}
+	return ""
+}
+
+// simpleTypeName strips namespace qualification, generic arguments, and
+// nullability from a C# type: "Acme.Data.IRepository<User>?" -> "IRepository".
+func simpleTypeName(typ string) string {
+	if i := strings.IndexByte(typ, '<'); i >= 0 {
+		typ = typ[:i]
+	}
+	typ = strings.TrimSpace(strings.TrimSuffix(typ, "?"))
+	if i := strings.LastIndexAny(typ, ".:"); i >= 0 {
+		typ = typ[i+1:]
+	}
+	return typ
+}
+
+// invocationArguments returns the argument nodes of an invocation expression.
+func (e *extractor) invocationArguments(invocation *sitter.Node) []*sitter.Node {
+	argList := invocation.ChildByFieldName("arguments")
+	if argList == nil {
+		return nil
+	}
+	var args []*sitter.Node
+	for i := 0; i < int(argList.NamedChildCount()); i++ {
+		child := argList.NamedChild(i)
+		if child.Type() == "argument" {
+			args = append(args, child)
+		}
+	}
+	return args
+}
+
+// csharpStringValue returns the value of a regular or verbatim string literal.
+func (e *extractor) csharpStringValue(node *sitter.Node) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	text := e.nodeText(node)
+	switch node.Type() {
+	case "string_literal":
+		return strings.Trim(text, "\""), true
+	case "verbatim_string_literal":
+		return strings.Trim(strings.TrimPrefix(text, "@"), "\""), true
+	}
+	return "", false
+}
+
+// collectStringLiterals returns the values of all string literals under node.
+func (e *extractor) collectStringLiterals(node *sitter.Node) []string {
+	if v, ok := e.csharpStringValue(node); ok {
+		return []string{v}
+	}
+	var values []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		values = append(values, e.collectStringLiterals(node.NamedChild(i))...)
+	}
+	return values
+}
+
+// joinRoute joins a route group prefix and a route segment into a path
+// with a single leading slash and no trailing slash.
+func joinRoute(prefix, segment string) string {
+	joined := strings.Trim(strings.Trim(prefix, "/")+"/"+strings.Trim(segment, "/"), "/")
+	return "/" + joined
+}
+
 func (e *extractor) extractAttributes(node *sitter.Node) []string {
 	var attrs []string
 	for i := 0; i < int(node.NamedChildCount()); i++ {
@@ -1211,14 +1816,18 @@ func (e *extractor) extractBaseList(node *sitter.Node) []string {
 	return bases
 }
 
+// getMethodName returns the name of a method or constructor declaration:
+// its last identifier, as in extractMethod, since a named return type is
+// an identifier too.
 func (e *extractor) getMethodName(node *sitter.Node) string {
+	name := ""
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(i)
 		if child.Type() == "identifier" {
-			return e.nodeText(child)
+			name = e.nodeText(child)
 		}
 	}
-	return ""
+	return name
 }
 
 func (e *extractor) extractDocComment(node *sitter.Node) string {
@@ -1269,7 +1878,10 @@ func (e *extractor) extractDocComment(node *sitter.Node) string {
 // buildCallMaps populates lookup maps from already-extracted nodes.
 func (e *extractor) buildCallMaps() {
 	e.importMap = make(map[string]string)
-	e.classMethodMap = make(map[string]map[string]string)
+	e.classMethodMap = make(map[string]map[string][]*graph.Node)
+	e.methodIDs = make(map[string]string)
+	e.memberTypes = make(map[string]map[string]string)
+	e.memberCalls = make(map[string][]string)
 
 	for _, n := range e.nodes {
 		switch n.Type {
@@ -1283,9 +1895,18 @@ func (e *extractor) buildCallMaps() {
 			className := n.Properties["class"]
 			if className != "" {
 				if e.classMethodMap[className] == nil {
-					e.classMethodMap[className] = make(map[string]string)
+					e.classMethodMap[className] = make(map[string][]*graph.Node)
+				}
+				e.classMethodMap[className][n.Name] = append(e.classMethodMap[className][n.Name], n)
+				e.methodIDs[methodKey(splitScope(n.Properties["scope"]), n.QualifiedName)] = n.ID
+			}
+		case graph.NodeVariable:
+			className, typ := n.Properties["class"], n.Properties["type"]
+			if className != "" && typ != "" {
+				if e.memberTypes[className] == nil {
+					e.memberTypes[className] = make(map[string]string)
 				}
-				e.classMethodMap[className][n.Name] = n.ID
+				e.memberTypes[className][n.Name] = simpleTypeName(typ)
 			}
 		}
 	}
@@ -1300,7 +1921,7 @@ func (e *extractor) walkForClassBodies(node *sitter.Node) {
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(i)
 		switch child.Type() {
-		case "class_declaration", "struct_declaration":
+		case "class_declaration", "struct_declaration", "record_declaration":
 			e.walkClassBodiesForCalls(child)
 		case "namespace_declaration":
 			for j := 0; j < int(child.NamedChildCount()); j++ {
@@ -1330,6 +1951,8 @@ func (e *extractor) walkClassBodiesForCalls(classNode *sitter.Node) {
 	if className == "" || bodyNode == nil {
 		return
 	}
+	e.pushScope(className)
+	defer e.popScope()
 
 	for i := 0; i < int(bodyNode.NamedChildCount()); i++ {
 		child := bodyNode.NamedChild(i)
@@ -1339,29 +1962,99 @@ func (e *extractor) walkClassBodiesForCalls(classNode *sitter.Node) {
 			if methodName == "" {
 				continue
 			}
-			methodID := ""
-			if methods, ok := e.classMethodMap[className]; ok {
-				methodID = methods[methodName]
-			}
+			paramTypes, _ := e.paramSignature(child.ChildByFieldName("parameters"))
+			qualifiedName := parser.MethodSignatureKey(className, methodName, paramTypes)
+			methodID := e.methodIDs[methodKey(e.memberScope(), qualifiedName)]
 			if methodID == "" {
-				qualifiedName := className + "." + methodName
-				methodID = graph.NewNodeID(string(graph.NodeMethod), e.filePath, qualifiedName)
+				methodID = e.symbolID(graph.NodeMethod, e.memberScope(), qualifiedName)
 			}
 			// Walk the method body for calls
 			e.walkNodeForCalls(child, methodID, className)
-		case "class_declaration", "struct_declaration":
+		case "class_declaration", "struct_declaration", "record_declaration":
 			e.walkClassBodiesForCalls(child)
 		}
 	}
 }
 
+// paramSignature returns the normalized parameter types of a parameter_list
+// node and the arity they give the method. Parameters with default values are
+// optional, and a trailing params array makes the method variadic.
+func (e *extractor) paramSignature(params *sitter.Node) ([]string, parser.Arity) {
+	types := []string{}
+	if params == nil {
+		return types, parser.Arity{}
+	}
+	optional, variadic := 0, false
+	for i := 0; i < int(params.ChildCount()); i++ {
+		child := params.Child(i)
+		switch {
+		case child.Type() == "parameter":
+			typ := child.ChildByFieldName("type")
+			if typ == nil {
+				continue
+			}
+			var mods []string
+			hasDefault := false
+			for j := 0; j < int(child.ChildCount()); j++ {
+				switch c := child.Child(j); c.Type() {
+				case "modifier":
+					mods = append(mods, e.nodeText(c))
+					variadic = variadic || e.nodeText(c) == "params"
+				case "=":
+					hasDefault = true
+				}
+			}
+			types = append(types, parser.NormalizeParamType(strings.Join(append(mods, e.nodeText(typ)), " ")))
+			if hasDefault {
+				optional++
+			}
+		case params.FieldNameForChild(i) == "type":
+			// The grammar flattens "params T[] name" into the list itself.
+			types = append(types, parser.NormalizeParamType(e.nodeText(child)))
+			variadic = true
+		}
+	}
+	arity := parser.Arity{Min: len(types) - optional, Max: len(types)}
+	if variadic {
+		arity = parser.Arity{Min: len(types) - optional - 1, Max: -1}
+	}
+	return types, arity
+}
+
+// argumentCount returns the number of arguments passed by an
+// invocation_expression, or -1 when it has no argument list.
+func argumentCount(invocation *sitter.Node) int {
+	args := invocation.ChildByFieldName("arguments")
+	if args == nil {
+		return -1
+	}
+	n := 0
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		if args.NamedChild(i).Type() == "argument" {
+			n++
+		}
+	}
+	return n
+}
+
 func (e *extractor) walkNodeForCalls(node *sitter.Node, methodID, className string) {
 	if node == nil {
 		return
 	}
 
-	if node.Type() == "invocation_expression" {
+	switch node.Type() {
+	case "invocation_expression":
 		e.checkFunctionCall(node, methodID, className)
+	case "throw_statement", "throw_expression":
+		// throw new PaymentDeclinedException(...); rethrows are not recorded.
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if c := node.NamedChild(i); c.Type() == "object_creation_expression" {
+				if t := c.ChildByFieldName("type"); t != nil {
+					name, _, _ := strings.Cut(e.nodeText(t), "<")
+					parser.AddThrows(e.nodes, methodID, name)
+				}
+			}
+		}
 	}
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
@@ -1399,18 +2092,17 @@ func (e *extractor) checkFunctionCall(node *sitter.Node, methodID, className str
 
 	// Case 1: no object or "this" -> same-class call
 	if objectName == "" || objectName == "this" {
-		if methods, ok := e.classMethodMap[className]; ok {
-			if targetID, ok := methods[calledMethod]; ok {
-				e.edges = append(e.edges, &graph.Edge{
-					ID:       edgeID(methodID, targetID, string(graph.EdgeCalls)),
-					Type:     graph.EdgeCalls,
-					SourceID: methodID,
-					TargetID: targetID,
-					Properties: map[string]string{
-						"callee": calledMethod,
-					},
-				})
-			}
+		// Overloads are told apart by argument count.
+		if target := parser.PickOverload(e.classMethodMap[className][calledMethod], argumentCount(node)); target != nil {
+			e.edges = append(e.edges, &graph.Edge{
+				ID:       edgeID(methodID, target.ID, string(graph.EdgeCalls)),
+				Type:     graph.EdgeCalls,
+				SourceID: methodID,
+				TargetID: target.ID,
+				Properties: map[string]string{
+					"callee": calledMethod,
+				},
+			})
 		}
 		return
 	}
@@ -1426,6 +2118,41 @@ func (e *extractor) checkFunctionCall(node *sitter.Node, methodID, className str
 				"callee": calledMethod,
 			},
 		})
+		return
+	}
+
+	// Case 3: _field.Method() -> call on a typed field or property, usually
+	// an injected interface. Recorded as "Type.Method/argc" for the linker to
+	// resolve across files.
+	if typ := e.memberTypes[className][objectName]; typ != "" {
+		call := typ + "." + calledMethod
+		if n := argumentCount(node); n >= 0 {
+			call += "/" + strconv.Itoa(n)
+		}
+		for _, c := range e.memberCalls[methodID] {
+			if c == call {
+				return
+			}
+		}
+		e.memberCalls[methodID] = append(e.memberCalls[methodID], call)
+	}
+}
+
+// attachMemberCalls stores the calls recorded on typed members as the
+// "member_calls" property of the calling method nodes.
+func (e *extractor) attachMemberCalls() {
+	if len(e.memberCalls) == 0 {
+		return
+	}
+	for _, n := range e.nodes {
+		calls := e.memberCalls[n.ID]
+		if len(calls) == 0 {
+			continue
+		}
+		if n.Properties == nil {
+			n.Properties = make(map[string]string)
+		}
+		n.Properties["member_calls"] = strings.Join(calls, ",")
 	}
 }
 
@@ -1496,7 +2223,31 @@ func (e *extractor) nodeText(node *sitter.Node) string {
 // Helper functions
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
+}
+
+// hasModifier reports whether a space-separated modifier list contains mod.
+func hasModifier(modifiers, mod string) bool {
+	for _, m := range strings.Fields(modifiers) {
+		if m == mod {
+			return true
+		}
+	}
+	return false
+}
+
+// joinUnique appends the values not already present to a comma-separated list.
+func joinUnique(list string, values []string) string {
+	var items []string
+	if list != "" {
+		items = strings.Split(list, ",")
+	}
+	for _, v := range values {
+		if !slices.Contains(items, v) {
+			items = append(items, v)
+		}
+	}
+	return strings.Join(items, ",")
 }
 
 func isPublicOrInternal(modifiers string) bool {
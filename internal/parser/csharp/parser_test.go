@@ -3,6 +3,7 @@ package csharp
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -390,6 +391,50 @@ func TestParseControllerFixture(t *testing.T) {
 	}
 }
 
+func TestControllerRouteTemplates(t *testing.T) {
+	src := `namespace Shop.Api
+{
+    [ApiController]
+    [Route("api/[controller]")]
+    public class OrdersController : ControllerBase
+    {
+        [HttpGet]
+        public IActionResult List() => Ok();
+
+        [HttpGet("{id}", Name = "GetOrder")]
+        public IActionResult Get(int id) => Ok();
+
+        [HttpPost]
+        [Route("[action]")]
+        public IActionResult Archive() => Ok();
+
+        [HttpGet("~/health")]
+        public IActionResult Health() => Ok();
+    }
+}
+`
+	result, err := NewParser().ParseFile("Controllers/OrdersController.cs", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseFile error: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeAPIEndpoint {
+			got[n.Name] = true
+		}
+	}
+	want := []string{"GET /api/Orders", "GET /api/Orders/{id}", "POST /api/Orders/Archive", "GET /health"}
+	if len(got) != len(want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+	for _, name := range want {
+		if !got[name] {
+			t.Errorf("missing endpoint %q, got %v", name, got)
+		}
+	}
+}
+
 func TestTestFileDetection(t *testing.T) {
 	source := `using System;
 using Xunit;
@@ -653,3 +698,455 @@ func findNodeByNameAndType(nodes []*graph.Node, name string, nodeType graph.Node
 	}
 	return nil
 }
+
+func TestParseMinimalAPIFixture(t *testing.T) {
+	_, thisFile, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatal("could not determine test file path")
+	}
+	programPath := filepath.Join(filepath.Dir(thisFile), "testdata", "Program.cs")
+
+	content, err := os.ReadFile(programPath)
+	if err != nil {
+		t.Fatalf("could not read testdata/Program.cs: %v", err)
+	}
+
+	p := NewParser()
+	result, err := p.ParseFile(programPath, content)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	endpoints := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeAPIEndpoint {
+			endpoints[n.Name] = n
+		}
+	}
+
+	tests := []struct {
+		name    string
+		handler string
+		group   string
+	}{
+		{"GET /health", "lambda", ""},
+		{"GET /api/v1/users/{id}", "GetUser", "/api/v1/users"},
+		{"POST /api/v1/users", "lambda", "/api/v1/users"},
+		{"DELETE /api/v1/users/{id}", "UserHandlers.Delete", "/api/v1/users"},
+		{"PUT /admin/settings", "UpdateSettings", "/admin"},
+		{"HEAD /api/v1/ping", "lambda", ""},
+		{"OPTIONS /api/v1/ping", "lambda", ""},
+	}
+	for _, tt := range tests {
+		ep, ok := endpoints[tt.name]
+		if !ok {
+			t.Errorf("missing endpoint %q (got %v)", tt.name, endpointNames(endpoints))
+			continue
+		}
+		if ep.Properties["framework"] != "aspnet_minimal" {
+			t.Errorf("%s framework = %q, want aspnet_minimal", tt.name, ep.Properties["framework"])
+		}
+		if ep.Properties["handler"] != tt.handler {
+			t.Errorf("%s handler = %q, want %q", tt.name, ep.Properties["handler"], tt.handler)
+		}
+		if ep.Properties["route_group"] != tt.group {
+			t.Errorf("%s route_group = %q, want %q", tt.name, ep.Properties["route_group"], tt.group)
+		}
+	}
+	if len(endpoints) != len(tests) {
+		t.Errorf("expected %d endpoints, got %d: %v", len(tests), len(endpoints), endpointNames(endpoints))
+	}
+
+	exposes := 0
+	for _, edge := range result.Edges {
+		if edge.Type == graph.EdgeExposes {
+			exposes++
+		}
+	}
+	if exposes != len(tests) {
+		t.Errorf("expected %d EdgeExposes edges, got %d", len(tests), exposes)
+	}
+}
+
+func endpointNames(endpoints map[string]*graph.Node) []string {
+	var names []string
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestDIRegistrations(t *testing.T) {
+	source := `using Microsoft.Extensions.DependencyInjection;
+
+namespace Shop.Api;
+
+public class Startup
+{
+    public void ConfigureServices(IServiceCollection services)
+    {
+        services.AddScoped<IOrderService, OrderService>();
+        services.AddSingleton<Shop.Data.IClock, SystemClock>();
+        services.TryAddTransient<INotifier, EmailNotifier>();
+        services.AddKeyedScoped<IPaymentGateway, StripeGateway>("stripe");
+        services.AddScoped(typeof(IRepository<>), typeof(EfRepository<>));
+        services.AddSingleton<Metrics>();
+        services.AddControllers();
+    }
+}
+
+public class CheckoutController
+{
+    private readonly IOrderService _orders;
+    public INotifier Notifier { get; set; }
+
+    public void Checkout()
+    {
+        _orders.PlaceOrder();
+        _orders.PlaceOrder();
+        Notifier.Send();
+    }
+}
+`
+	p := NewParser()
+	result, err := p.ParseFile("api/Startup.cs", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	regs := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeDependency && n.Properties["kind"] == "di_registration" {
+			regs[n.Properties["service"]] = n
+		}
+	}
+	tests := []struct {
+		service, impl, lifetime string
+	}{
+		{"IOrderService", "OrderService", "scoped"},
+		{"IClock", "SystemClock", "singleton"},
+		{"INotifier", "EmailNotifier", "transient"},
+		{"IPaymentGateway", "StripeGateway", "scoped"},
+		{"IRepository", "EfRepository", "scoped"},
+	}
+	for _, tt := range tests {
+		reg, ok := regs[tt.service]
+		if !ok {
+			t.Errorf("missing registration for %s", tt.service)
+			continue
+		}
+		if reg.Properties["implementation"] != tt.impl || reg.Properties["lifetime"] != tt.lifetime {
+			t.Errorf("%s registration = %v, want %s (%s)", tt.service, reg.Properties, tt.impl, tt.lifetime)
+		}
+	}
+	if len(regs) != len(tests) {
+		t.Errorf("expected %d registrations, got %d", len(tests), len(regs))
+	}
+
+	checkout := findNodeByNameAndType(result.Nodes, "Checkout", graph.NodeMethod)
+	if checkout == nil {
+		t.Fatal("expected Checkout method node")
+	}
+	if got, want := checkout.Properties["member_calls"], "IOrderService.PlaceOrder/0,INotifier.Send/0"; got != want {
+		t.Errorf("member_calls = %q, want %q", got, want)
+	}
+}
+
+func TestOverloadedMethods(t *testing.T) {
+	src := []byte(`namespace Shop;
+
+public class Pricing
+{
+    public Pricing() {}
+    public Pricing(System.Collections.Generic.List<int> tiers) {}
+
+    public decimal Quote(int qty) => 0;
+    public decimal Quote(int qty, string currency = "USD", bool tax = false) => 0;
+    public decimal Quote(ref Order order, params Item[] extras) => 0;
+    public bool TryQuote(out decimal price) { price = 0; return true; }
+
+    public void Run()
+    {
+        Quote();
+        Quote(1, "EUR");
+        Quote(ref o, a, b, c, d);
+    }
+}
+`)
+	result, err := NewParser().ParseFile("Pricing.cs", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	byQName := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeMethod {
+			byQName[n.QualifiedName] = n
+		}
+	}
+	for qname, arity := range map[string]string{
+		"Pricing.Pricing()":               "0",
+		"Pricing.Pricing(List)":           "1",
+		"Pricing.Quote(int)":              "1",
+		"Pricing.Quote(int,string,bool)":  "1-3",
+		"Pricing.Quote(ref Order,Item[])": "1+",
+		"Pricing.TryQuote(out decimal)":   "1",
+		"Pricing.Run()":                   "0",
+	} {
+		n, ok := byQName[qname]
+		if !ok {
+			t.Errorf("missing method %s (have %v)", qname, byQName)
+			continue
+		}
+		if n.Properties["arity"] != arity {
+			t.Errorf("%s arity = %q, want %q", qname, n.Properties["arity"], arity)
+		}
+	}
+
+	run := byQName["Pricing.Run()"]
+	called := make(map[string]bool)
+	for _, edge := range result.Edges {
+		if edge.Type == graph.EdgeCalls && run != nil && edge.SourceID == run.ID {
+			for _, n := range byQName {
+				if n.ID == edge.TargetID {
+					called[n.QualifiedName] = true
+				}
+			}
+		}
+	}
+	// Quote() matches no overload and falls back to the first declared.
+	for _, want := range []string{"Pricing.Quote(int)", "Pricing.Quote(int,string,bool)", "Pricing.Quote(ref Order,Item[])"} {
+		if !called[want] {
+			t.Errorf("expected Run() to call %s, got %v", want, called)
+		}
+	}
+}
+
+func TestNestedTypeIDs(t *testing.T) {
+	src := []byte(`namespace Acme;
+
+public class Order {
+    public class Builder {
+        public Order Build() { return Validate(); }
+        public Order Build(int count) { return null; }
+        private Order Validate() { return null; }
+    }
+}
+
+public class Invoice {
+    public class Builder {
+        public Invoice Build() { return null; }
+    }
+}
+`)
+	result, err := NewParser().ParseFile("Billing.cs", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	ids := make(map[string]bool)
+	var builders, builds []*graph.Node
+	for _, n := range result.Nodes {
+		if ids[n.ID] {
+			t.Errorf("duplicate node ID for %s %s", n.Type, n.QualifiedName)
+		}
+		ids[n.ID] = true
+		switch {
+		case n.Type == graph.NodeClass && n.Name == "Builder":
+			builders = append(builders, n)
+		case n.Type == graph.NodeMethod && n.Name == "Build":
+			builds = append(builds, n)
+		}
+	}
+	if len(builders) != 2 || len(builds) != 3 {
+		t.Fatalf("got %d Builder classes and %d Build methods, want 2 and 3", len(builders), len(builds))
+	}
+	if builders[0].QualifiedName != "Acme.Order.Builder" || builders[0].Properties["scope"] != "Order" {
+		t.Errorf("nested class = %s scope %q, want Acme.Order.Builder scope Order",
+			builders[0].QualifiedName, builders[0].Properties["scope"])
+	}
+
+	// Top-level types keep their unscoped IDs.
+	order := findNodeByNameAndType(result.Nodes, "Order", graph.NodeClass)
+	if order == nil || order.ID != graph.NewNodeID(string(graph.NodeClass), "Billing.cs", "Order") {
+		t.Errorf("top-level class ID changed: %+v", order)
+	}
+
+	// Calls inside a nested type resolve to that type's methods.
+	validate := findNodeByNameAndType(result.Nodes, "Validate", graph.NodeMethod)
+	var resolved bool
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && e.SourceID == builds[0].ID {
+			resolved = validate != nil && e.TargetID == validate.ID
+		}
+	}
+	if !resolved {
+		t.Error("Order.Builder.Build() -> Validate() call not resolved")
+	}
+}
+
+func TestRecordsPartialsAndPrimaryConstructors(t *testing.T) {
+	src := []byte(`namespace Shop;
+
+public record Person(string Name, int Age) : IEntity
+{
+    public string Greet() => Name;
+}
+
+public partial class OrderService(IOrderRepository repo) : IOrderService
+{
+    public void Place() { repo.Save(); }
+}
+
+public partial class OrderService : IDisposable
+{
+    public void Dispose() {}
+}
+`)
+	result, err := NewParser().ParseFile("Shop.cs", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	classes := make(map[string][]*graph.Node)
+	fields := make(map[string]*graph.Node)
+	methods := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		switch n.Type {
+		case graph.NodeClass:
+			classes[n.Name] = append(classes[n.Name], n)
+		case graph.NodeVariable:
+			fields[n.QualifiedName] = n
+		case graph.NodeMethod:
+			methods[n.Name] = n
+		}
+	}
+
+	if len(classes["Person"]) != 1 || classes["Person"][0].Properties["record"] != "true" {
+		t.Fatalf("expected Person record class, got %+v", classes["Person"])
+	}
+	if f := fields["Person.Name"]; f == nil || f.Properties["type"] != "string" || !f.Exported {
+		t.Errorf("expected exported record field Person.Name, got %+v", f)
+	}
+
+	parts := classes["OrderService"]
+	if len(parts) != 1 {
+		t.Fatalf("expected partial declarations merged into one node, got %d", len(parts))
+	}
+	svc := parts[0]
+	if svc.Properties["partial"] != "true" || svc.Properties["implements"] != "IOrderService,IDisposable" {
+		t.Errorf("merged partial props = %v", svc.Properties)
+	}
+	if svc.EndLine != 16 {
+		t.Errorf("merged EndLine = %d, want 16", svc.EndLine)
+	}
+	if f := fields["OrderService.repo"]; f == nil || f.Properties["primary_constructor"] != "true" || f.Exported {
+		t.Errorf("expected private primary-constructor field repo, got %+v", f)
+	}
+
+	contained := make(map[string]bool)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeContains && e.SourceID == svc.ID {
+			contained[e.TargetID] = true
+		}
+	}
+	for _, name := range []string{"Place", "Dispose"} {
+		if m := methods[name]; m == nil || !contained[m.ID] {
+			t.Errorf("expected merged class to contain %s", name)
+		}
+	}
+	if got := methods["Place"].Properties["member_calls"]; got != "IOrderRepository.Save/0" {
+		t.Errorf("member_calls = %q, want call through primary-constructor parameter", got)
+	}
+}
+
+func TestErrorSites(t *testing.T) {
+	source := `namespace Acme {
+public class PaymentDeclinedException : Exception {
+  public PaymentDeclinedException(string m) : base(m) {}
+}
+public class Gateway {
+  public void Charge(int? amount) {
+    if (amount < 0) throw new PaymentDeclinedException("negative");
+    var a = amount ?? throw new ArgumentNullException(nameof(amount));
+    try { Send(); } catch (Exception) { throw; }
+  }
+}
+}`
+	result, err := NewParser().ParseFile("src/Gateway.cs", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	wantThrows := map[string]string{"Charge": "PaymentDeclinedException,ArgumentNullException"}
+	wantErrorTypes := map[string]bool{"PaymentDeclinedException": true}
+	gotThrows := make(map[string]string)
+	gotErrorTypes := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if throws := n.Properties[parser.PropThrows]; throws != "" {
+			gotThrows[n.Name] = throws
+		}
+		if n.Properties[parser.PropErrorType] == "true" {
+			gotErrorTypes[n.Name] = true
+		}
+	}
+	if !reflect.DeepEqual(gotThrows, wantThrows) {
+		t.Errorf("throws = %v, want %v", gotThrows, wantThrows)
+	}
+	if !reflect.DeepEqual(gotErrorTypes, wantErrorTypes) {
+		t.Errorf("error types = %v, want %v", gotErrorTypes, wantErrorTypes)
+	}
+}
+
+func TestParseNavigationProperties(t *testing.T) {
+	source := `namespace Shop.Data;
+
+public class Order
+{
+    public int Id { get; set; }
+    public string Number { get; set; }
+    public DateTime PlacedAt { get; set; }
+    public int CustomerId { get; set; }
+    public Customer Customer { get; set; }
+    [ForeignKey("ShipperKey")]
+    public Shipper? Shipper { get; set; }
+    public virtual Invoice Invoice { get; set; }
+    public virtual ICollection<OrderLine> Lines { get; set; } = new List<OrderLine>();
+    [NotMapped]
+    public Summary Summary { get; set; }
+}
+
+public class ShopContext : DbContext
+{
+    public DbSet<Order> Orders { get; set; }
+}
+`
+	result, err := NewParser().ParseFile("Shop/Data/Order.cs", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	tests := []struct {
+		class, orm, relations string
+	}{
+		{"Order", "ef", "Customer:Customer:many_to_one,Shipper:Shipper:many_to_one,Invoice:Invoice:one_to_one,Lines:OrderLine:one_to_many"},
+		{"ShopContext", "", ""},
+	}
+	for _, tt := range tests {
+		var found *graph.Node
+		for _, n := range result.Nodes {
+			if n.Type == graph.NodeClass && n.Name == tt.class {
+				found = n
+			}
+		}
+		if found == nil {
+			t.Errorf("class %s not found", tt.class)
+			continue
+		}
+		if got := found.Properties[parser.PropORM]; got != tt.orm {
+			t.Errorf("%s orm = %q, want %q", tt.class, got, tt.orm)
+		}
+		if got := found.Properties[parser.PropModelRelations]; got != tt.relations {
+			t.Errorf("%s relations = %q, want %q", tt.class, got, tt.relations)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+using Microsoft.AspNetCore.Builder;
+
+var builder = WebApplication.CreateBuilder(args);
+var app = builder.Build();
+
+app.MapGet("/health", () => "ok");
+
+var api = app.MapGroup("/api/v1").WithTags("Api");
+var users = api.MapGroup("/users");
+
+users.MapGet("/{id}", GetUser);
+users.MapPost("/", async (User user) => Results.Created($"/users/{user.Id}", user))
+    .RequireAuthorization();
+users.MapDelete("/{id}", UserHandlers.Delete);
+
+app.MapGroup("/admin").MapPut("/settings", UpdateSettings);
+app.MapMethods("/api/v1/ping", new[] { "HEAD", "OPTIONS" }, () => Results.Ok());
+
+app.Run();
+
+static IResult GetUser(int id) => Results.Ok(id);
+static IResult UpdateSettings() => Results.NoContent();
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Data classification properties. Parsers record the fields a type tags
+// as sensitive in its source (Go struct tags) in PropTaggedFields; the
+// linker merges them with fields tagged by annotations or configured
+// patterns into PropSensitiveFields, then propagates the classes to the
+// endpoints and services exposing the types.
+const (
+	// PropTaggedFields lists the fields a type's source tags with a data
+	// class, encoded by FormatSensitiveFields.
+	PropTaggedFields = "tagged_fields"
+	// PropSensitiveFields lists all of a type's sensitive fields, encoded
+	// by FormatSensitiveFields.
+	PropSensitiveFields = "sensitive_fields"
+	// PropDataClasses lists the data classes (pii, pci, ...) a type holds
+	// or an endpoint or service exposes.
+	PropDataClasses = "data_classes"
+	// PropDataTypes lists the sensitive types an endpoint exposes.
+	PropDataTypes = "data_types"
+)
+
+// Data classes recognised in annotations. Any other class may be
+// configured or named by a DataClassification annotation.
+const (
+	DataClassPII       = "pii"
+	DataClassPHI       = "phi"
+	DataClassPCI       = "pci"
+	DataClassSensitive = "sensitive"
+)
+
+// dataClassAnnotations maps lowercase annotation names to the class they
+// tag a field with.
+var dataClassAnnotations = map[string]string{
+	"pii":                    DataClassPII,
+	"personaldata":           DataClassPII,
+	"protectedpersonaldata":  DataClassPII,
+	"personallyidentifiable": DataClassPII,
+	"phi":                    DataClassPHI,
+	"pci":                    DataClassPCI,
+	"cardholderdata":         DataClassPCI,
+	"sensitive":              DataClassSensitive,
+	"sensitivedata":          DataClassSensitive,
+	"confidential":           DataClassSensitive,
+}
+
+// AnnotationDataClass returns the data class an annotation tags a field
+// with: @PII, [PersonalData], @Sensitive, or @DataClassification("phi")
+// naming the class. It returns "" for other annotations.
+func AnnotationDataClass(ann string) string {
+	name := markerName(ann)
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	lname := strings.ToLower(name)
+	if class, ok := dataClassAnnotations[lname]; ok {
+		return class
+	}
+	if lname == "dataclassification" || lname == "classification" {
+		if m := quotedRe.FindStringSubmatch(ann); m != nil {
+			return strings.ToLower(m[1])
+		}
+	}
+	return ""
+}
+
+// FormatSensitiveFields encodes field -> class as comma-separated
+// "field:class" entries, sorted by field.
+func FormatSensitiveFields(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for f := range fields {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+	entries := make([]string, len(names))
+	for i, f := range names {
+		entries[i] = f + ":" + fields[f]
+	}
+	return strings.Join(entries, ",")
+}
+
+// ParseSensitiveFields decodes a PropTaggedFields or PropSensitiveFields
+// value, skipping malformed entries.
+func ParseSensitiveFields(s string) map[string]string {
+	fields := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		field, class, ok := strings.Cut(entry, ":")
+		if !ok || field == "" || class == "" {
+			continue
+		}
+		fields[field] = class
+	}
+	return fields
+}
@@ -0,0 +1,38 @@
+package parser
+
+import "testing"
+
+func TestAnnotationDataClass(t *testing.T) {
+	tests := []struct {
+		ann  string
+		want string
+	}{
+		{"PII", DataClassPII},
+		{"@Pii", DataClassPII},
+		{"PersonalData", DataClassPII},
+		{"com.acme.privacy.Sensitive", DataClassSensitive},
+		{"Confidential", DataClassSensitive},
+		{"CardholderData", DataClassPCI},
+		{`DataClassification("PHI")`, DataClassPHI},
+		{`Classification(value = "financial")`, "financial"},
+		{"Column(name = \"email\")", ""},
+		{"JsonProperty", ""},
+	}
+	for _, tt := range tests {
+		if got := AnnotationDataClass(tt.ann); got != tt.want {
+			t.Errorf("AnnotationDataClass(%q) = %q, want %q", tt.ann, got, tt.want)
+		}
+	}
+}
+
+func TestSensitiveFieldsRoundTrip(t *testing.T) {
+	fields := map[string]string{"ssn": DataClassPII, "CardNumber": DataClassPCI}
+	encoded := FormatSensitiveFields(fields)
+	if encoded != "CardNumber:pci,ssn:pii" {
+		t.Errorf("FormatSensitiveFields = %q", encoded)
+	}
+	got := ParseSensitiveFields(encoded + ",malformed,:pii")
+	if len(got) != 2 || got["ssn"] != DataClassPII || got["CardNumber"] != DataClassPCI {
+		t.Errorf("ParseSensitiveFields = %v, want %v", got, fields)
+	}
+}
@@ -0,0 +1,352 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// ExternalStore node kinds.
+const (
+	StoreKindRedis         = "redis"
+	StoreKindMemcached     = "memcached"
+	StoreKindElasticsearch = "elasticsearch"
+	StoreKindS3            = "s3"
+)
+
+// ExternalStore operation kinds, listed in the "operations" property of
+// Uses edges.
+const (
+	StoreOpRead      = "read"
+	StoreOpWrite     = "write"
+	StoreOpDelete    = "delete"
+	StoreOpSearch    = "search"
+	StoreOpPublish   = "publish"
+	StoreOpSubscribe = "subscribe"
+)
+
+// storeClientPattern matches the creation or declaration of a store
+// client. Declarations bind the "var" submatch; constructors bind the
+// variable, field or property they are assigned to.
+type storeClientPattern struct {
+	re   *regexp.Regexp
+	kind string
+}
+
+// storeClientPatterns lists the client constructors and typed client
+// declarations per language.
+var storeClientPatterns = map[Language][]storeClientPattern{
+	LangGo: {
+		{regexp.MustCompile(`redis\.New(?:Client|ClusterClient|FailoverClient|UniversalClient|Ring)\(`), StoreKindRedis},
+		{regexp.MustCompile(`(?P<var>\w+)\s+\*?redis\.(?:Client|ClusterClient|UniversalClient|Ring)\b`), StoreKindRedis},
+		{regexp.MustCompile(`memcache\.New\(`), StoreKindMemcached},
+		{regexp.MustCompile(`(?P<var>\w+)\s+\*memcache\.Client\b`), StoreKindMemcached},
+		{regexp.MustCompile(`elasticsearch\d*\.New(?:Default|Typed)?Client\(`), StoreKindElasticsearch},
+		{regexp.MustCompile(`(?P<var>\w+)\s+\*elasticsearch\d*\.(?:Client|TypedClient)\b`), StoreKindElasticsearch},
+		{regexp.MustCompile(`\bs3(?:manager)?\.New(?:FromConfig|Uploader|Downloader)?\(`), StoreKindS3},
+		{regexp.MustCompile(`(?P<var>\w+)\s+\*s3\.(?:Client|S3)\b`), StoreKindS3},
+	},
+	LangPython: {
+		{regexp.MustCompile(`\b(?:aio)?redis\.(?:(?:Strict)?Redis(?:Cluster)?(?:\.from_url)?|from_url)\(`), StoreKindRedis},
+		{regexp.MustCompile(`\b(?:pymemcache|memcache|pylibmc|bmemcached)(?:\.client(?:\.base)?)?\.Client\(`), StoreKindMemcached},
+		{regexp.MustCompile(`\b(?:Async)?Elasticsearch\(`), StoreKindElasticsearch},
+		{regexp.MustCompile(`\.(?:client|resource)\(\s*["']s3["']`), StoreKindS3},
+	},
+	LangTypeScript: jsStoreClientPatterns,
+	LangJavaScript: jsStoreClientPatterns,
+	LangJava: {
+		{regexp.MustCompile(`\bnew\s+(?:Jedis|JedisPool|JedisPooled|JedisCluster)\(|\bRedisClient\.create\(`), StoreKindRedis},
+		{regexp.MustCompile(`\b(?:Jedis|JedisPool|JedisPooled|JedisCluster|StringRedisTemplate|RedisTemplate<[^>]*>|RedisCommands<[^>]*>)\s+(?P<var>\w+)\s*[;=)]`), StoreKindRedis},
+		{regexp.MustCompile(`\bnew\s+MemcachedClient\(`), StoreKindMemcached},
+		{regexp.MustCompile(`\bMemcachedClient\s+(?P<var>\w+)\s*[;=)]`), StoreKindMemcached},
+		{regexp.MustCompile(`\bnew\s+(?:RestHighLevelClient|ElasticsearchClient)\(`), StoreKindElasticsearch},
+		{regexp.MustCompile(`\b(?:RestHighLevelClient|ElasticsearchClient)\s+(?P<var>\w+)\s*[;=)]`), StoreKindElasticsearch},
+		{regexp.MustCompile(`\bS3Client\.(?:builder|create)\(|\bAmazonS3ClientBuilder\.`), StoreKindS3},
+		{regexp.MustCompile(`\b(?:S3Client|AmazonS3)\s+(?P<var>\w+)\s*[;=)]`), StoreKindS3},
+	},
+	LangRuby: {
+		{regexp.MustCompile(`\bRedis\.new\b`), StoreKindRedis},
+		{regexp.MustCompile(`\bDalli::Client\.new\b`), StoreKindMemcached},
+		{regexp.MustCompile(`\bElasticsearch::Client\.new\b`), StoreKindElasticsearch},
+		{regexp.MustCompile(`\bAws::S3::(?:Client|Resource)\.new\b`), StoreKindS3},
+	},
+	LangCSharp: {
+		{regexp.MustCompile(`\.GetDatabase\(`), StoreKindRedis},
+		{regexp.MustCompile(`\b(?:IDatabase|IDatabaseAsync)\s+(?P<var>\w+)\s*[;=),]`), StoreKindRedis},
+		{regexp.MustCompile(`\b(?:IMemcachedClient|MemcachedClient)\s+(?P<var>\w+)\s*[;=),]`), StoreKindMemcached},
+		{regexp.MustCompile(`\bnew\s+(?:ElasticClient|ElasticsearchClient)\(`), StoreKindElasticsearch},
+		{regexp.MustCompile(`\b(?:IElasticClient|ElasticClient|ElasticsearchClient)\s+(?P<var>\w+)\s*[;=),]`), StoreKindElasticsearch},
+		{regexp.MustCompile(`\bnew\s+AmazonS3Client\(`), StoreKindS3},
+		{regexp.MustCompile(`\b(?:IAmazonS3|AmazonS3Client)\s+(?P<var>\w+)\s*[;=),]`), StoreKindS3},
+	},
+}
+
+var jsStoreClientPatterns = []storeClientPattern{
+	{regexp.MustCompile(`\bnew\s+(?:Redis|IORedis)(?:\.Cluster)?\(|\bredis\.createClient\(`), StoreKindRedis},
+	{regexp.MustCompile(`\bnew\s+Memcached\(|\bmemjs\.Client\.create\(`), StoreKindMemcached},
+	{regexp.MustCompile(`\bnew\s+(?:elasticsearch\.)?Client\(\s*\{\s*(?:node|nodes|cloud)\s*:`), StoreKindElasticsearch},
+	{regexp.MustCompile(`\bnew\s+(?:AWS\.)?S3(?:Client)?\(`), StoreKindS3},
+}
+
+// storeReceiverWords name receivers that hold a store client when no
+// constructor or declaration in the file binds them, for clients injected
+// from elsewhere (redisClient, self.s3, elasticClient).
+var storeReceiverWords = []struct {
+	re   *regexp.Regexp
+	kind string
+}{
+	{regexp.MustCompile(`(?i)redis`), StoreKindRedis},
+	{regexp.MustCompile(`(?i)memcache`), StoreKindMemcached},
+	{regexp.MustCompile(`(?i)elastic`), StoreKindElasticsearch},
+	{regexp.MustCompile(`(?i)s3`), StoreKindS3},
+}
+
+// storeOperations maps the normalized method names of each store's clients
+// (lower case, without underscores and Async/WithContext suffixes) to
+// operation kinds. Methods not listed, such as Close or Ping, are ignored.
+var storeOperations = map[string]map[string]string{
+	StoreKindRedis: storeOps(map[string][]string{
+		StoreOpRead: {"get", "mget", "getex", "getdel", "stringget", "hget", "hgetall", "hmget", "hkeys", "hvals", "hexists",
+			"hashget", "hashgetall", "exists", "keyexists", "keys", "scan", "ttl", "pttl", "lrange", "lindex", "llen", "smembers",
+			"sismember", "scard", "zrange", "zrevrange", "zrangebyscore", "zscore", "zcard", "xread", "xrange", "opsforvalue", "opsforhash"},
+		StoreOpWrite: {"set", "setex", "setnx", "psetex", "mset", "getset", "stringset", "hset", "hsetnx", "hmset", "hashset",
+			"hincrby", "incr", "incrby", "decr", "decrby", "stringincrement", "append", "expire", "expireat", "keyexpire", "persist",
+			"lpush", "rpush", "listleftpush", "listrightpush", "sadd", "setadd", "zadd", "sortedsetadd", "xadd", "rename"},
+		StoreOpDelete: {"del", "delete", "unlink", "keydelete", "hdel", "hashdelete", "srem", "zrem", "lpop", "rpop", "blpop", "brpop",
+			"spop", "ltrim", "flushdb", "flushall"},
+		StoreOpPublish:   {"publish"},
+		StoreOpSubscribe: {"subscribe", "psubscribe"},
+	}),
+	StoreKindMemcached: storeOps(map[string][]string{
+		StoreOpRead:   {"get", "gets", "getmulti", "getmany", "getbulk"},
+		StoreOpWrite:  {"set", "setmulti", "setmany", "add", "replace", "cas", "incr", "decr", "increment", "decrement", "touch", "store", "append", "prepend"},
+		StoreOpDelete: {"delete", "deletemulti", "deletemany", "remove", "flushall"},
+	}),
+	StoreKindElasticsearch: storeOps(map[string][]string{
+		StoreOpSearch: {"search", "msearch", "count", "scroll", "searchtemplate"},
+		StoreOpRead:   {"get", "mget", "exists", "getsource"},
+		StoreOpWrite:  {"index", "indexdocument", "create", "update", "bulk", "updatebyquery", "reindex"},
+		StoreOpDelete: {"delete", "deletebyquery"},
+	}),
+	StoreKindS3: storeOps(map[string][]string{
+		StoreOpRead: {"getobject", "headobject", "listobjects", "listobjectsv2", "listbuckets", "downloadfile", "downloadfileobj",
+			"download", "getobjectrequest", "generatepresignedurl", "getsignedurl"},
+		StoreOpWrite: {"putobject", "uploadfile", "uploadfileobj", "upload", "copyobject", "createmultipartupload", "uploadpart",
+			"completemultipartupload"},
+		StoreOpDelete: {"deleteobject", "deleteobjects"},
+	}),
+}
+
+// storeOps inverts an operation -> methods table.
+func storeOps(byOp map[string][]string) map[string]string {
+	ops := make(map[string]string)
+	for op, methods := range byOp {
+		for _, m := range methods {
+			ops[m] = op
+		}
+	}
+	return ops
+}
+
+var (
+	// storeCallRe matches a method call on a named receiver:
+	// "rdb.Get(", "self.cache.set(", "client?.search(".
+	storeCallRe = regexp.MustCompile(`\b([A-Za-z_]\w*)\s*\??\.\s*([A-Za-z_]\w*)\s*(?:<[^<>()]*>)?\(`)
+	// s3CommandRe matches AWS SDK v3 S3 commands: new PutObjectCommand({...}).
+	s3CommandRe = regexp.MustCompile(`\bnew\s+(\w+Object|\w+Objects|ListObjectsV2|ListBuckets|\w+MultipartUpload|UploadPart)Command\(`)
+	// storeResourceRe matches a bucket or index named in call arguments:
+	// Bucket: aws.String("b"), Bucket="b", index: 'i', .bucket("b").
+	storeResourceRe = regexp.MustCompile(`(?i)(?:\b(?:bucket(?:_?name)?|index(?:_?name)?)\s*[:=]\s*(?:aws\.String\(\s*)?|\.(?:bucket|index|withindex)\(\s*)["'` + "`" + `]([\w.\-]+)["'` + "`" + `]`)
+)
+
+// ExtractExternalStores scans source content for Redis, Memcached,
+// Elasticsearch and S3 client calls and adds an ExternalStore node per
+// store, with Uses edges from each enclosing function or method (or from
+// the file node for top-level calls) listing the operations performed.
+//
+// Calls are attributed to a store through their receiver: a variable,
+// field or property a client constructor in the file is assigned to, one
+// declared with a client type, or one whose name mentions the store
+// (redisClient, self.s3). Stores are shared across files: S3 buckets and
+// Elasticsearch indexes named by a literal in the call's arguments get a
+// node of their own, other calls use the store kind's node.
+func ExtractExternalStores(result *ParseResult, content []byte) *ParseResult {
+	patterns := storeClientPatterns[result.Language]
+	if len(patterns) == 0 {
+		return result
+	}
+
+	fileID := ""
+	var callables []*graph.Node
+	for _, n := range result.Nodes {
+		switch n.Type {
+		case graph.NodeFile, graph.NodeTestFile:
+			if fileID == "" {
+				fileID = n.ID
+			}
+		case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
+			if n.EndLine >= n.Line && n.Line > 0 {
+				callables = append(callables, n)
+			}
+		}
+	}
+	if fileID == "" {
+		return result
+	}
+
+	clients := make(map[string]string) // receiver -> store kind
+	for _, p := range patterns {
+		varIdx := p.re.SubexpIndex("var")
+		for _, m := range p.re.FindAllSubmatchIndex(content, -1) {
+			var v string
+			if varIdx >= 0 && m[2*varIdx] >= 0 {
+				v = string(content[m[2*varIdx]:m[2*varIdx+1]])
+			} else {
+				v = clientVariable(content, m[0])
+			}
+			if v != "" && v != "self" && v != "this" {
+				clients[v] = p.kind
+			}
+		}
+	}
+
+	stores := make(map[string]*graph.Node) // kind:name -> ExternalStore node
+	edges := make(map[string]*graph.Edge)
+	use := func(kind, op string, start, argsStart int) {
+		name := kind
+		if kind == StoreKindS3 || kind == StoreKindElasticsearch {
+			if m := storeResourceRe.FindSubmatch(callArgs(content, argsStart)); m != nil {
+				name = string(m[1])
+			}
+		}
+		key := kind + ":" + name
+		store, ok := stores[key]
+		if !ok {
+			store = &graph.Node{
+				ID:         graph.NewNodeID(string(graph.NodeExternalStore), "", key),
+				Type:       graph.NodeExternalStore,
+				Name:       name,
+				Properties: map[string]string{"kind": kind},
+			}
+			stores[key] = store
+			result.Nodes = append(result.Nodes, store)
+		}
+
+		line := bytes.Count(content[:start], []byte("\n")) + 1
+		userID := fileID
+		if fn := enclosingCallable(callables, line); fn != nil {
+			userID = fn.ID
+		}
+		edgeID := graph.NewEdgeID(graph.EdgeUses, userID, store.ID)
+		edge, ok := edges[edgeID]
+		if !ok {
+			edge = &graph.Edge{
+				ID:         edgeID,
+				Type:       graph.EdgeUses,
+				SourceID:   userID,
+				TargetID:   store.ID,
+				Properties: map[string]string{"operations": op},
+			}
+			edges[edgeID] = edge
+			result.Edges = append(result.Edges, edge)
+			return
+		}
+		edge.Properties["operations"] = MergeStoreOperations(edge.Properties["operations"], op)
+	}
+
+	for _, m := range storeCallRe.FindAllSubmatchIndex(content, -1) {
+		receiver := string(content[m[2]:m[3]])
+		kind, ok := clients[receiver]
+		if !ok {
+			for _, w := range storeReceiverWords {
+				if w.re.MatchString(receiver) {
+					kind, ok = w.kind, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		if op, found := storeOperations[kind][normalizeStoreMethod(string(content[m[4]:m[5]]))]; found {
+			use(kind, op, m[0], m[1])
+		}
+	}
+	for _, m := range s3CommandRe.FindAllSubmatchIndex(content, -1) {
+		if op, found := storeOperations[StoreKindS3][normalizeStoreMethod(string(content[m[2]:m[3]]))]; found {
+			use(StoreKindS3, op, m[0], m[1])
+		}
+	}
+	return result
+}
+
+// multiAssignRe matches a Go multiple assignment up to the end of a line
+// prefix, capturing the first name: "es, err := ".
+var multiAssignRe = regexp.MustCompile(`([A-Za-z_]\w*)\s*,[\w\s,]*:?=[^=]*$`)
+
+// clientVariable returns the variable a client constructor starting at
+// start is assigned to, taking the client rather than the error of a Go
+// multiple assignment (es, err := elasticsearch.NewDefaultClient()).
+func clientVariable(content []byte, start int) string {
+	v := assignedVariable(content, start)
+	if v != "_" && v != "err" {
+		return v
+	}
+	line := content[:start]
+	if nl := bytes.LastIndexByte(line, '\n'); nl >= 0 {
+		line = line[nl+1:]
+	}
+	if m := multiAssignRe.FindSubmatch(line); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// MergeStoreOperations adds the comma-separated operations of add to those
+// of ops, returning them sorted and deduplicated.
+func MergeStoreOperations(ops, add string) string {
+	set := make(map[string]bool)
+	for _, list := range []string{ops, add} {
+		for _, op := range strings.Split(list, ",") {
+			if op != "" {
+				set[op] = true
+			}
+		}
+	}
+	merged := make([]string, 0, len(set))
+	for op := range set {
+		merged = append(merged, op)
+	}
+	sort.Strings(merged)
+	return strings.Join(merged, ",")
+}
+
+// normalizeStoreMethod lower-cases a client method name and drops
+// underscores and the Async and WithContext suffixes.
+func normalizeStoreMethod(method string) string {
+	s := strings.ToLower(strings.ReplaceAll(method, "_", ""))
+	s = strings.TrimSuffix(s, "async")
+	return strings.TrimSuffix(s, "withcontext")
+}
+
+// callArgs returns the argument text of the call whose opening parenthesis
+// ends at start (exclusive), up to the matching parenthesis or 500 bytes.
+func callArgs(content []byte, start int) []byte {
+	depth := 1
+	end := start
+	for ; end < len(content) && end < start+500; end++ {
+		switch content[end] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	return content[start:end]
+}
@@ -0,0 +1,218 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestExtractExternalStores(t *testing.T) {
+	tests := []struct {
+		name string
+		lang Language
+		src  string
+		want map[string]string // kind:name -> operations
+	}{
+		{
+			name: "go",
+			lang: LangGo,
+			src: `type Cache struct {
+	rdb *redis.Client
+}
+
+func (c *Cache) Load(ctx context.Context, id string) (string, error) {
+	if v, err := c.rdb.Get(ctx, "order:"+id).Result(); err == nil {
+		return v, nil
+	}
+	c.rdb.Set(ctx, "order:"+id, "x", time.Minute)
+	c.rdb.Close()
+	client := s3.NewFromConfig(cfg)
+	client.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("invoices"), Key: aws.String(id)})
+	es, _ := elasticsearch.NewDefaultClient()
+	es.Search(es.Search.WithIndex("orders"))
+	m := map[string]string{}
+	m.Get("x")
+	return "", nil
+}`,
+			want: map[string]string{
+				"redis:redis":          "read,write",
+				"s3:invoices":          "write",
+				"elasticsearch:orders": "search",
+			},
+		},
+		{
+			name: "python",
+			lang: LangPython,
+			src: `import boto3, redis
+from elasticsearch import Elasticsearch
+
+class Store:
+    def __init__(self):
+        self.cache = redis.Redis(host="cache")
+        self.s3 = boto3.client("s3")
+        self.es = Elasticsearch("http://es:9200")
+
+    def save(self, doc):
+        self.cache.delete("doc")
+        self.cache.publish("docs", doc.id)
+        self.s3.put_object(Bucket="docs", Key=doc.id, Body=doc.body)
+        self.s3.get_object(Bucket="docs", Key=doc.id)
+        self.es.index(index="documents", id=doc.id, document=doc.body)
+        self.es.search(index="documents", query={"match_all": {}})
+        settings.get("x")
+`,
+			want: map[string]string{
+				"redis:redis":             "delete,publish",
+				"s3:docs":                 "read,write",
+				"elasticsearch:documents": "search,write",
+			},
+		},
+		{
+			name: "typescript",
+			lang: LangTypeScript,
+			src: `const redis = new Redis(process.env.REDIS_URL);
+const s3 = new S3Client({ region: "us-east-1" });
+
+export async function upload(key: string, body: Buffer) {
+  await s3.send(new PutObjectCommand({ Bucket: "uploads", Key: key, Body: body }));
+  await redis.hset("uploads", key, "1");
+  await memcachedClient.get(key);
+}`,
+			want: map[string]string{
+				"s3:uploads":          "write",
+				"redis:redis":         "write",
+				"memcached:memcached": "read",
+			},
+		},
+		{
+			name: "java",
+			lang: LangJava,
+			src: `class Sessions {
+    private final JedisPool pool;
+    private final S3Client s3Client;
+
+    void touch(String id) {
+        try (Jedis jedis = pool.getResource()) {
+            jedis.expire(id, 60);
+        }
+        s3Client.deleteObject(DeleteObjectRequest.builder().bucket("sessions").key(id).build());
+    }
+}`,
+			want: map[string]string{
+				"redis:redis": "write",
+				"s3:sessions": "delete",
+			},
+		},
+		{
+			name: "ruby",
+			lang: LangRuby,
+			src: `class Catalog
+  def initialize
+    @cache = Dalli::Client.new("localhost:11211")
+    @search = Elasticsearch::Client.new(url: ENV["ES_URL"])
+  end
+
+  def find(id)
+    @cache.fetch(id)
+    @cache.get(id)
+    @search.search(index: "products", body: { query: { match: { id: id } } })
+  end
+end`,
+			want: map[string]string{
+				"memcached:memcached":    "read",
+				"elasticsearch:products": "search",
+			},
+		},
+		{
+			name: "csharp",
+			lang: LangCSharp,
+			src: `public class CartStore
+{
+    private readonly IDatabase _db;
+    private readonly IAmazonS3 _storage;
+
+    public async Task Save(Cart cart)
+    {
+        await _db.StringSetAsync(cart.Id, cart.Json);
+        await _db.KeyDeleteAsync("stale");
+        await _storage.PutObjectAsync(new PutObjectRequest { BucketName = "carts", Key = cart.Id });
+    }
+}`,
+			want: map[string]string{
+				"redis:redis": "delete,write",
+				"s3:carts":    "write",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ParseResult{
+				FilePath: "svc/file",
+				Language: tt.lang,
+				Nodes:    []*graph.Node{{ID: "file", Type: graph.NodeFile, FilePath: "svc/file"}},
+			}
+			ExtractExternalStores(result, []byte(tt.src))
+			keys := make(map[string]string) // node ID -> kind:name
+			for _, n := range result.Nodes {
+				if n.Type == graph.NodeExternalStore {
+					keys[n.ID] = n.Properties["kind"] + ":" + n.Name
+				}
+			}
+			got := make(map[string]string)
+			for _, e := range result.Edges {
+				if e.Type == graph.EdgeUses {
+					key := keys[e.TargetID]
+					got[key] = MergeStoreOperations(got[key], e.Properties["operations"])
+				}
+			}
+			if len(got) != len(tt.want) || len(keys) != len(tt.want) {
+				t.Errorf("stores = %v, want %v", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s operations = %q, want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractExternalStores_Users(t *testing.T) {
+	src := `package cache
+
+var rdb = redis.NewClient(&redis.Options{})
+
+func Warm() {
+	rdb.Set(ctx, "k", "v", 0)
+}
+
+func Read() {
+	rdb.Get(ctx, "k")
+	rdb.MGet(ctx, "a", "b")
+}
+`
+	result := &ParseResult{
+		FilePath: "cache/cache.go",
+		Language: LangGo,
+		Nodes: []*graph.Node{
+			{ID: "file", Type: graph.NodeFile, FilePath: "cache/cache.go"},
+			{ID: "warm", Type: graph.NodeFunction, Name: "Warm", Line: 5, EndLine: 7},
+			{ID: "read", Type: graph.NodeFunction, Name: "Read", Line: 9, EndLine: 12},
+		},
+	}
+	ExtractExternalStores(result, []byte(src))
+
+	want := graph.NewNodeID(string(graph.NodeExternalStore), "", "redis:redis")
+	got := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type != graph.EdgeUses || e.TargetID != want {
+			t.Errorf("unexpected edge %s -> %s", e.Type, e.TargetID)
+			continue
+		}
+		got[e.SourceID] = e.Properties["operations"]
+	}
+	if len(got) != 2 || got["warm"] != "write" || got["read"] != "read" {
+		t.Errorf("users = %v, want warm=write and read=read", got)
+	}
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// MaxDiagnostics caps the diagnostics recorded per file; a file that fails
+// badly produces one error per token otherwise.
+const MaxDiagnostics = 20
+
+// Diagnostic is a syntax error a parser recovered from. The parse result
+// still holds whatever could be extracted around it.
+type Diagnostic struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// TreeSitterDiagnostics returns the ERROR and MISSING nodes of a tree-sitter
+// parse tree as diagnostics, outermost first, at most MaxDiagnostics.
+func TreeSitterDiagnostics(root *sitter.Node, content []byte) []Diagnostic {
+	if root == nil || !root.HasError() {
+		return nil
+	}
+	var diags []Diagnostic
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if len(diags) >= MaxDiagnostics {
+			return
+		}
+		pos := n.StartPoint()
+		switch {
+		case n.IsMissing():
+			diags = append(diags, Diagnostic{
+				Line:    int(pos.Row) + 1,
+				Column:  int(pos.Column) + 1,
+				Message: fmt.Sprintf("missing %s", n.Type()),
+			})
+			return
+		case n.IsError():
+			diags = append(diags, Diagnostic{
+				Line:    int(pos.Row) + 1,
+				Column:  int(pos.Column) + 1,
+				Message: fmt.Sprintf("syntax error near %q", snippet(n.Content(content))),
+			})
+			return
+		case !n.HasError():
+			return
+		}
+		for i := 0; i < int(n.ChildCount()); i++ {
+			walk(n.Child(i))
+		}
+	}
+	walk(root)
+	return diags
+}
+
+// snippet shortens source text to its first line, at most 40 bytes.
+func snippet(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > 40 {
+		s = s[:40] + "..."
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Error type and error site properties.
+const (
+	// PropErrorType is "true" on classes, structs, types and Go sentinel
+	// variables that define an error or exception type.
+	PropErrorType = "error_type"
+	// PropThrows lists, comma-separated and as written, the error types a
+	// function or method throws, raises, panics with, declares (Java
+	// throws clauses) or, in Go, returns. The linker resolves them to
+	// Throws edges.
+	PropThrows = "throws"
+	// PropPanics is "true" on Go functions that call panic.
+	PropPanics = "panics"
+)
+
+// IsErrorBase reports whether one of the comma-separated base class names
+// makes a class an error type: a name ending in Error or Exception
+// (Exception, RuntimeException, StandardError, PaymentError) or Throwable.
+// Qualifiers and type arguments are ignored.
+func IsErrorBase(bases string) bool {
+	for _, b := range strings.Split(bases, ",") {
+		b = strings.TrimSpace(b)
+		if i := strings.IndexAny(b, "<(["); i >= 0 {
+			b = b[:i]
+		}
+		b = b[strings.LastIndexAny(b, ".:")+1:]
+		if strings.HasSuffix(b, "Error") || strings.HasSuffix(b, "Exception") || b == "Throwable" {
+			return true
+		}
+	}
+	return false
+}
+
+// AddThrows records that the function or method with ID fnID among nodes
+// surfaces the error type name. Names already recorded are ignored.
+func AddThrows(nodes []*graph.Node, fnID, name string) {
+	if fnID == "" || name == "" {
+		return
+	}
+	for _, n := range nodes {
+		if n.ID != fnID {
+			continue
+		}
+		if n.Properties == nil {
+			n.Properties = make(map[string]string)
+		}
+		existing := n.Properties[PropThrows]
+		if existing == "" {
+			n.Properties[PropThrows] = name
+			return
+		}
+		for _, t := range strings.Split(existing, ",") {
+			if t == name {
+				return
+			}
+		}
+		n.Properties[PropThrows] = existing + "," + name
+		return
+	}
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestIsErrorBase(t *testing.T) {
+	tests := []struct {
+		bases string
+		want  bool
+	}{
+		{"Exception", true},
+		{"RuntimeException", true},
+		{"StandardError", true},
+		{"Payments::Error", true},
+		{"errors.BaseError", true},
+		{"Throwable", true},
+		{"Model,PaymentError", true},
+		{"GenericException<T>", true},
+		{"Base", false},
+		{"ErrorHandler", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsErrorBase(tt.bases); got != tt.want {
+			t.Errorf("IsErrorBase(%q) = %v, want %v", tt.bases, got, tt.want)
+		}
+	}
+}
+
+func TestAddThrows(t *testing.T) {
+	fn := &graph.Node{ID: "fn"}
+	nodes := []*graph.Node{{ID: "other"}, fn}
+	for _, name := range []string{"NotFound", "Declined", "NotFound", ""} {
+		AddThrows(nodes, "fn", name)
+	}
+	AddThrows(nodes, "missing", "Ignored")
+	if got := fn.Properties[PropThrows]; got != "NotFound,Declined" {
+		t.Errorf("throws = %q, want %q", got, "NotFound,Declined")
+	}
+	if nodes[0].Properties != nil {
+		t.Errorf("other node properties = %v, want nil", nodes[0].Properties)
+	}
+}
@@ -0,0 +1,261 @@
+// Package external runs parsers for languages CodeEagle doesn't support as
+// separate processes, so proprietary languages and DSLs can be indexed
+// without forking the repository.
+//
+// For every file the parser starts the configured command, writes a Request
+// as JSON to its stdin, and reads a Response as JSON from its stdout:
+//
+//	request:  {"version": 1, "file_path": "src/a.cbl", "language": "cobol", "content": "..."}
+//	response: {"nodes": [{"id": ..., "type": "Function", ...}], "edges": [...]}
+//
+// Nodes and edges use the graph package's JSON encoding. A response with a
+// non-empty "error", a non-zero exit status, or invalid JSON fails the file.
+// Nodes without a file_path or language get the file's; node and edge IDs
+// and types are required.
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// ProtocolVersion is the request format version sent to external parsers.
+const ProtocolVersion = 1
+
+// DefaultTimeout bounds a single file's parse when no timeout is configured.
+const DefaultTimeout = 30 * time.Second
+
+// Request is the JSON document written to an external parser's stdin.
+type Request struct {
+	Version  int    `json:"version"`
+	FilePath string `json:"file_path"`
+	Language string `json:"language"`
+	Content  string `json:"content"`
+}
+
+// Response is the JSON document an external parser writes to stdout.
+type Response struct {
+	Nodes []*graph.Node `json:"nodes"`
+	Edges []*graph.Edge `json:"edges"`
+	Error string        `json:"error,omitempty"`
+}
+
+// Config describes one external parser.
+type Config struct {
+	// Language names the language; it becomes the nodes' default language.
+	Language string
+	// Extensions and Filenames select the files the parser handles.
+	Extensions []string
+	Filenames  []string
+	// Command is the executable and its arguments.
+	Command []string
+	// Dir is the working directory for the command ("" for the current one).
+	Dir string
+	// Timeout bounds each file's parse; zero uses DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Parser implements parser.FilenameParser and parser.StreamingParser by
+// delegating to a subprocess.
+type Parser struct {
+	cfg Config
+}
+
+// NewParser creates an external parser. The language and command are
+// required.
+func NewParser(cfg Config) (*Parser, error) {
+	if cfg.Language == "" {
+		return nil, fmt.Errorf("external parser: language is required")
+	}
+	if len(cfg.Command) == 0 || cfg.Command[0] == "" {
+		return nil, fmt.Errorf("external parser %s: command is required", cfg.Language)
+	}
+	if len(cfg.Extensions) == 0 && len(cfg.Filenames) == 0 {
+		return nil, fmt.Errorf("external parser %s: at least one extension or filename is required", cfg.Language)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Parser{cfg: cfg}, nil
+}
+
+func (p *Parser) Language() parser.Language {
+	return parser.Language(p.cfg.Language)
+}
+
+func (p *Parser) Extensions() []string {
+	return p.cfg.Extensions
+}
+
+func (p *Parser) Filenames() []string {
+	return p.cfg.Filenames
+}
+
+func (p *Parser) ParseFile(filePath string, content []byte) (*parser.ParseResult, error) {
+	return parser.Collect(p, filePath, content)
+}
+
+// StreamFile runs the command and hands each node and edge to sink as it is
+// decoded from the response, so a large response is never held in memory
+// whole. Elements decoded before a failure have already reached the sink.
+func (p *Parser) StreamFile(filePath string, content []byte, sink parser.Sink) ([]parser.Diagnostic, error) {
+	req, err := json.Marshal(Request{
+		Version:  ProtocolVersion,
+		FilePath: filePath,
+		Language: p.cfg.Language,
+		Content:  string(content),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.cfg.Command[0], p.cfg.Command[1:]...)
+	cmd.Dir = p.cfg.Dir
+	cmd.Stdin = bytes.NewReader(req)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("%s parser: %w", p.cfg.Language, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%s parser: %w", p.cfg.Language, err)
+	}
+
+	decodeErr := p.decode(stdout, filePath, sink)
+	var se *sinkError
+	if errors.As(decodeErr, &se) {
+		cancel() // the rest of the output is unwanted
+	}
+	_, _ = io.Copy(io.Discard, stdout)
+	waitErr := cmd.Wait()
+
+	switch {
+	case se != nil:
+		return nil, se.err
+	case waitErr != nil:
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s parser timed out after %s", p.cfg.Language, p.cfg.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s parser: %w: %s", p.cfg.Language, waitErr, msg)
+		}
+		return nil, fmt.Errorf("%s parser: %w", p.cfg.Language, waitErr)
+	case decodeErr != nil:
+		return nil, decodeErr
+	}
+	return nil, nil
+}
+
+// sinkError marks an error returned by the sink rather than the parser.
+type sinkError struct{ err error }
+
+func (e *sinkError) Error() string { return e.err.Error() }
+
+// decode reads a Response from r one array element at a time.
+func (p *Parser) decode(r io.Reader, filePath string, sink parser.Sink) error {
+	dec := json.NewDecoder(r)
+	decodeErr := func(err error) error {
+		return fmt.Errorf("%s parser: decode response: %w", p.cfg.Language, err)
+	}
+	if tok, err := dec.Token(); err != nil {
+		return decodeErr(err)
+	} else if tok != json.Delim('{') {
+		return decodeErr(fmt.Errorf("expected object, got %v", tok))
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return decodeErr(err)
+		}
+		switch tok {
+		case "nodes":
+			err = eachElement(dec, decodeErr, func(i int) error {
+				var n *graph.Node
+				if err := dec.Decode(&n); err != nil {
+					return decodeErr(err)
+				}
+				if n == nil || n.ID == "" || n.Type == "" {
+					return fmt.Errorf("%s parser: node %d: id and type are required", p.cfg.Language, i)
+				}
+				if n.FilePath == "" {
+					n.FilePath = filePath
+				}
+				if n.Language == "" {
+					n.Language = p.cfg.Language
+				}
+				if err := sink.AddNode(n); err != nil {
+					return &sinkError{err}
+				}
+				return nil
+			})
+		case "edges":
+			err = eachElement(dec, decodeErr, func(i int) error {
+				var e *graph.Edge
+				if err := dec.Decode(&e); err != nil {
+					return decodeErr(err)
+				}
+				if e == nil || e.ID == "" || e.Type == "" || e.SourceID == "" || e.TargetID == "" {
+					return fmt.Errorf("%s parser: edge %d: id, type, source_id and target_id are required", p.cfg.Language, i)
+				}
+				if err := sink.AddEdge(e); err != nil {
+					return &sinkError{err}
+				}
+				return nil
+			})
+		case "error":
+			var msg string
+			if err := dec.Decode(&msg); err != nil {
+				return decodeErr(err)
+			}
+			if msg != "" {
+				return fmt.Errorf("%s parser: %s", p.cfg.Language, msg)
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return decodeErr(err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachElement calls fn for each element of the JSON array at the decoder's
+// position; null is an empty array. Malformed JSON is reported through wrap.
+func eachElement(dec *json.Decoder, wrap func(error) error, fn func(i int) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return wrap(err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return wrap(fmt.Errorf("expected array, got %v", tok))
+	}
+	for i := 0; dec.More(); i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // closing ]
+		return wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// TestHelperProcess is not a real test: it is the external parser the other
+// tests run, re-executing the test binary with EXTERNAL_PARSER_MODE set.
+func TestHelperProcess(t *testing.T) {
+	mode := os.Getenv("EXTERNAL_PARSER_MODE")
+	if mode == "" {
+		return
+	}
+	var req Request
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	switch mode {
+	case "ok":
+		var resp Response
+		fileID := "file:" + req.FilePath
+		resp.Nodes = append(resp.Nodes, &graph.Node{ID: fileID, Type: graph.NodeFile, Name: req.FilePath})
+		for i, line := range strings.Split(req.Content, "\n") {
+			if name, ok := strings.CutPrefix(line, "PROC "); ok {
+				id := "proc:" + name
+				resp.Nodes = append(resp.Nodes, &graph.Node{ID: id, Type: graph.NodeFunction, Name: name, Line: i + 1})
+				resp.Edges = append(resp.Edges, &graph.Edge{ID: fileID + "->" + id, Type: graph.EdgeContains, SourceID: fileID, TargetID: id})
+			}
+		}
+		json.NewEncoder(os.Stdout).Encode(resp)
+	case "error":
+		json.NewEncoder(os.Stdout).Encode(Response{Error: "unexpected token"})
+	case "crash":
+		fmt.Fprintln(os.Stderr, "boom")
+		os.Exit(3)
+	case "garbage":
+		fmt.Print("not json")
+	case "bad-node":
+		json.NewEncoder(os.Stdout).Encode(Response{Nodes: []*graph.Node{{Name: "x"}}})
+	case "slow":
+		time.Sleep(5 * time.Second)
+	}
+	os.Exit(0)
+}
+
+func helperParser(t *testing.T, mode string, timeout time.Duration) *Parser {
+	t.Helper()
+	t.Setenv("EXTERNAL_PARSER_MODE", mode)
+	p, err := NewParser(Config{
+		Language:   "cobol",
+		Extensions: []string{".cbl"},
+		Command:    []string{os.Args[0], "-test.run=TestHelperProcess"},
+		Timeout:    timeout,
+	})
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+	return p
+}
+
+func TestParseFile(t *testing.T) {
+	p := helperParser(t, "ok", 0)
+	result, err := p.ParseFile("src/pay.cbl", []byte("PROC CALC-PAY\nPROC PRINT-SLIP\n"))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(result.Nodes) != 3 || len(result.Edges) != 2 {
+		t.Fatalf("got %d nodes, %d edges, want 3 and 2", len(result.Nodes), len(result.Edges))
+	}
+	fn := result.Nodes[2]
+	if fn.Name != "PRINT-SLIP" || fn.Line != 2 {
+		t.Errorf("node = %s line %d, want PRINT-SLIP line 2", fn.Name, fn.Line)
+	}
+	if fn.FilePath != "src/pay.cbl" || fn.Language != "cobol" {
+		t.Errorf("defaults not applied: file %q language %q", fn.FilePath, fn.Language)
+	}
+	if result.Language != "cobol" || result.FilePath != "src/pay.cbl" {
+		t.Errorf("result = %s %s", result.Language, result.FilePath)
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tests := []struct {
+		mode    string
+		timeout time.Duration
+		want    string
+	}{
+		{"error", 0, "unexpected token"},
+		{"crash", 0, "boom"},
+		{"garbage", 0, "decode response"},
+		{"bad-node", 0, "id and type are required"},
+		{"slow", 200 * time.Millisecond, "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			p := helperParser(t, tt.mode, tt.timeout)
+			_, err := p.ParseFile("a.cbl", []byte("x"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewParserValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"no language", Config{Command: []string{"x"}, Extensions: []string{".x"}}, "language is required"},
+		{"no command", Config{Language: "x", Extensions: []string{".x"}}, "command is required"},
+		{"no files", Config{Language: "x", Command: []string{"x"}}, "extension or filename"},
+	}
+	for _, tt := range tests {
+		if _, err := NewParser(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// countingSink fails once it has received limit nodes.
+type countingSink struct {
+	nodes, edges, limit int
+}
+
+func (s *countingSink) AddNode(*graph.Node) error {
+	if s.nodes == s.limit {
+		return fmt.Errorf("sink full")
+	}
+	s.nodes++
+	return nil
+}
+
+func (s *countingSink) AddEdge(*graph.Edge) error {
+	s.edges++
+	return nil
+}
+
+func TestStreamFile(t *testing.T) {
+	p := helperParser(t, "ok", 0)
+	content := []byte("PROC A\nPROC B\nPROC C\n")
+
+	sink := &countingSink{limit: -1}
+	if _, err := p.StreamFile("a.cbl", content, sink); err != nil {
+		t.Fatalf("StreamFile: %v", err)
+	}
+	if sink.nodes != 4 || sink.edges != 3 {
+		t.Errorf("streamed %d nodes, %d edges, want 4 and 3", sink.nodes, sink.edges)
+	}
+
+	// A sink error stops the parse and is returned as is.
+	sink = &countingSink{limit: 2}
+	if _, err := p.StreamFile("a.cbl", content, sink); err == nil || err.Error() != "sink full" {
+		t.Errorf("err = %v, want the sink's error", err)
+	}
+	if sink.edges != 0 {
+		t.Errorf("streamed %d edges after the sink failed", sink.edges)
+	}
+}
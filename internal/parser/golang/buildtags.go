@@ -0,0 +1,81 @@
+package golang
+
+import (
+	"go/build/constraint"
+	"path"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// extractBuildConstraint records the file's build constraint on all of its
+// nodes (graph.PropBuildConstraint), so the same-named symbols of platform
+// variants such as foo_linux.go and foo_windows.go can be told apart.
+func (e *extractor) extractBuildConstraint() {
+	expr := e.buildConstraint()
+	if expr == nil {
+		return
+	}
+	s := expr.String()
+	for _, n := range e.nodes {
+		if n.Properties == nil {
+			n.Properties = make(map[string]string)
+		}
+		n.Properties[graph.PropBuildConstraint] = s
+	}
+}
+
+// buildConstraint returns the constraint of the file's //go:build line, or
+// of its // +build lines in files that predate it, and of its name's
+// _GOOS, _GOARCH or _GOOS_GOARCH suffix, as go/build applies them. It
+// returns nil for a file built everywhere.
+func (e *extractor) buildConstraint() constraint.Expr {
+	var expr constraint.Expr
+	and := func(x constraint.Expr) {
+		if expr == nil {
+			expr = x
+		} else {
+			expr = &constraint.AndExpr{X: expr, Y: x}
+		}
+	}
+
+	var plusBuild []constraint.Expr
+	goBuild := false
+	for _, group := range e.file.Comments {
+		if group.Pos() >= e.file.Package {
+			break // constraints must precede the package clause
+		}
+		for _, c := range group.List {
+			switch {
+			case constraint.IsGoBuild(c.Text):
+				if x, err := constraint.Parse(c.Text); err == nil && !goBuild {
+					and(x)
+					goBuild = true
+				}
+			case constraint.IsPlusBuild(c.Text):
+				if x, err := constraint.Parse(c.Text); err == nil {
+					plusBuild = append(plusBuild, x)
+				}
+			}
+		}
+	}
+	if !goBuild {
+		for _, x := range plusBuild {
+			and(x)
+		}
+	}
+
+	name := strings.TrimSuffix(path.Base(e.filePath), ".go")
+	name = strings.TrimSuffix(name, "_test")
+	if _, suffix, ok := strings.Cut(name, "_"); ok {
+		parts := strings.Split(suffix, "_")
+		n := len(parts)
+		switch {
+		case n >= 2 && graph.IsGOOS(parts[n-2]) && graph.IsGOARCH(parts[n-1]):
+			and(&constraint.AndExpr{X: &constraint.TagExpr{Tag: parts[n-2]}, Y: &constraint.TagExpr{Tag: parts[n-1]}})
+		case graph.IsGOOS(parts[n-1]) || graph.IsGOARCH(parts[n-1]):
+			and(&constraint.TagExpr{Tag: parts[n-1]})
+		}
+	}
+	return expr
+}
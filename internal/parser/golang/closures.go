@@ -0,0 +1,168 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// closureMinStmts is how many statements an unbound function literal needs
+// to get a node of its own. Smaller ones, such as deferred unlocks and sort
+// comparators, stay part of the function they are declared in.
+const closureMinStmts = 3
+
+// closure is a function literal with a node of its own.
+type closure struct {
+	lit *ast.FuncLit
+	id  string
+}
+
+// extractClosures adds a Function node for each significant function
+// literal in a function or method body: one bound to a variable, named
+// after it, or one of at least closureMinStmts statements, named
+// anonymous@line:column. Inline route and job handlers get one when the
+// handler is linked (see closureID). Calls made inside a closure are
+// attributed to it rather than to the enclosing function.
+func (e *extractor) extractClosures() {
+	e.litDecls = make(map[*ast.FuncLit]*ast.FuncDecl)
+	e.closureVars = make(map[*ast.Object]string)
+	for _, decl := range e.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		bound := make(map[*ast.FuncLit]*ast.Ident)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.AssignStmt:
+				for i, rhs := range x.Rhs {
+					if lit, ok := rhs.(*ast.FuncLit); ok && len(x.Lhs) == len(x.Rhs) {
+						if ident, ok := x.Lhs[i].(*ast.Ident); ok && ident.Name != "_" {
+							bound[lit] = ident
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				for i, v := range x.Values {
+					if lit, ok := v.(*ast.FuncLit); ok && len(x.Names) == len(x.Values) && x.Names[i].Name != "_" {
+						bound[lit] = x.Names[i]
+					}
+				}
+			case *ast.FuncLit:
+				e.litDecls[x] = fn
+				if ident, ok := bound[x]; ok {
+					id := e.addClosure(x, ident.Name, false)
+					if ident.Obj != nil {
+						e.closureVars[ident.Obj] = id
+					}
+				} else if len(x.Body.List) >= closureMinStmts {
+					e.addClosure(x, "", true)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// closureID returns the node ID of a function literal, adding a node for
+// it when it has none yet. It returns "" for a literal outside a function
+// body.
+func (e *extractor) closureID(lit *ast.FuncLit) string {
+	for _, c := range e.closures {
+		if c.lit == lit {
+			return c.id
+		}
+	}
+	if e.litDecls[lit] == nil {
+		return ""
+	}
+	return e.addClosure(lit, "", false)
+}
+
+// addClosure adds the node of a function literal declared in e.litDecls'
+// function, named name or anonymous@line:column. It is contained by the
+// innermost function or closure around it, which calls it when called is
+// set (an unbound literal is called, or passed on to be called, where it
+// is declared).
+func (e *extractor) addClosure(lit *ast.FuncLit, name string, called bool) string {
+	fn := e.litDecls[lit]
+	outer := fn.Name.Name
+	scope := []string{fn.Name.Name}
+	if _, recv := receiverParam(fn); recv != "" {
+		outer = recv + "." + outer
+		scope = []string{recv, fn.Name.Name}
+	}
+	pos := e.fset.Position(lit.Pos())
+	anonymous := fmt.Sprintf("anonymous@%d:%d", pos.Line, pos.Column)
+	if name == "" {
+		name = anonymous
+	}
+	key := outer + "." + name
+	symbol := graph.SymbolKey{
+		Language: string(parser.LangGo),
+		Type:     graph.NodeFunction,
+		FilePath: e.filePath,
+		Scope:    scope,
+		Name:     name,
+	}
+	id := symbol.ID()
+	for _, c := range e.closures {
+		if c.id == id { // a name bound twice in one function
+			key = outer + "." + name + "@" + anonymous
+			symbol.Disambiguator = anonymous
+			id = symbol.ID()
+			break
+		}
+	}
+	ownerID := e.closureOwner(e.enclosingFuncNodeID(fn), lit.Pos())
+
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:            id,
+		Type:          graph.NodeFunction,
+		Name:          name,
+		QualifiedName: e.file.Name.Name + "." + key,
+		FilePath:      e.filePath,
+		Line:          pos.Line,
+		EndLine:       e.pos(lit.End()),
+		Package:       e.file.Name.Name,
+		Language:      string(parser.LangGo),
+		Signature:     "func " + name + funcTypeSignature(lit.Type),
+		Properties: map[string]string{
+			"anonymous": "true",
+			"enclosing": outer,
+		},
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(ownerID, id, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: ownerID,
+		TargetID: id,
+	})
+	if called {
+		e.edges = append(e.edges, &graph.Edge{
+			ID:         edgeID(ownerID, id, string(graph.EdgeCalls)),
+			Type:       graph.EdgeCalls,
+			SourceID:   ownerID,
+			TargetID:   id,
+			Properties: map[string]string{"callee": name},
+		})
+	}
+	e.closures = append(e.closures, closure{lit: lit, id: id})
+	return id
+}
+
+// closureOwner returns the node ID code at pos is attributed to: the
+// innermost closure around it, or fnID, the function it is declared in.
+func (e *extractor) closureOwner(fnID string, pos token.Pos) string {
+	ownerID := fnID
+	var inner *ast.FuncLit
+	for _, c := range e.closures {
+		if c.lit.Pos() <= pos && pos < c.lit.End() && (inner == nil || c.lit.Pos() > inner.Pos()) {
+			inner, ownerID = c.lit, c.id
+		}
+	}
+	return ownerID
+}
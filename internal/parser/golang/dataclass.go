@@ -0,0 +1,60 @@
+package golang
+
+import (
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// sensitiveFields returns the fields of st tagged with a data class by a
+// struct tag: `pii:"true"`, `sensitive:"true"`, or a class named by
+// `classification:"pci"` or `sensitive:"phi"`.
+func sensitiveFields(st *ast.StructType) map[string]string {
+	fields := make(map[string]string)
+	if st.Fields == nil {
+		return fields
+	}
+	for _, f := range st.Fields.List {
+		if f.Tag == nil || len(f.Names) == 0 {
+			continue
+		}
+		raw, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+		class := tagDataClass(reflect.StructTag(raw))
+		if class == "" {
+			continue
+		}
+		for _, n := range f.Names {
+			fields[n.Name] = class
+		}
+	}
+	return fields
+}
+
+// tagDataClass returns the data class a struct tag assigns, or "".
+func tagDataClass(tag reflect.StructTag) string {
+	if v, ok := tag.Lookup("pii"); ok && enabledTag(v) {
+		return parser.DataClassPII
+	}
+	for _, key := range []string{"classification", "sensitive"} {
+		v, ok := tag.Lookup(key)
+		if !ok || !enabledTag(v) {
+			continue
+		}
+		if v == "" || v == "true" {
+			return parser.DataClassSensitive
+		}
+		return strings.ToLower(v)
+	}
+	return ""
+}
+
+// enabledTag reports whether a boolean-ish tag value turns a setting on.
+func enabledTag(v string) bool {
+	return v != "false" && v != "-"
+}
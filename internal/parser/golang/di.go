@@ -0,0 +1,242 @@
+package golang
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// diContainers maps the import paths of Go DI libraries to the container
+// name recorded on their registrations.
+var diContainers = map[string]string{
+	"github.com/google/wire": "wire",
+	"go.uber.org/fx":         "fx",
+	"go.uber.org/dig":        "dig",
+}
+
+// diRegistration is a constructor handed to a DI container, or an
+// interface binding.
+type diRegistration struct {
+	container string
+	role      string // "provide", "invoke" or "bind"
+	set       string // wire provider set or fx module, if any
+	expr      ast.Expr
+	line      int
+	// iface and impl name the types of a wire.Bind.
+	iface, impl string
+}
+
+// extractDIProviders records the constructors registered with google/wire,
+// uber fx and dig as Dependency nodes (kind=di_provider) for the linker to
+// resolve into Provides and InjectedInto edges:
+//
+//	var Set = wire.NewSet(NewStore, wire.Bind(new(Repo), new(*Store)))
+//	fx.New(fx.Module("users", fx.Provide(NewStore)), fx.Invoke(Register))
+//	c.Provide(NewStore); c.Invoke(Register) // dig
+func (e *extractor) extractDIProviders() {
+	aliases := make(map[string]string) // local package name -> container
+	for _, imp := range e.file.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		container, ok := diContainers[p]
+		if !ok {
+			continue
+		}
+		name := path.Base(p)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		aliases[name] = container
+	}
+	if len(aliases) == 0 {
+		return
+	}
+	hasDig := false
+	for _, c := range aliases {
+		hasDig = hasDig || c == "dig"
+	}
+
+	var regs []diRegistration
+	ast.Inspect(e.file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			// var ProviderSet = wire.NewSet(...) names the set.
+			before := len(regs)
+			for i, v := range n.Values {
+				if i < len(n.Names) {
+					regs = e.diCall(regs, aliases, hasDig, v, n.Names[i].Name)
+				}
+			}
+			return len(regs) == before
+		case *ast.CallExpr:
+			before := len(regs)
+			regs = e.diCall(regs, aliases, hasDig, n, "")
+			return len(regs) == before
+		}
+		return true
+	})
+
+	for _, r := range regs {
+		e.addDIRegistration(r)
+	}
+}
+
+// diCall appends the registrations made by x, a call to a wire, fx or dig
+// function, recursing into provider sets, modules and options.
+func (e *extractor) diCall(regs []diRegistration, aliases map[string]string, hasDig bool, x ast.Expr, set string) []diRegistration {
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		return regs
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return regs
+	}
+	line := e.pos(call.Pos())
+	container := ""
+	if id, ok := sel.X.(*ast.Ident); ok {
+		container = aliases[id.Name]
+	}
+
+	switch {
+	case container == "wire":
+		switch sel.Sel.Name {
+		case "NewSet", "Build":
+			for _, arg := range call.Args {
+				if inner, ok := arg.(*ast.CallExpr); ok {
+					regs = e.diCall(regs, aliases, hasDig, inner, set)
+					continue
+				}
+				regs = append(regs, diRegistration{container: "wire", role: "provide", set: set, expr: arg, line: line})
+			}
+		case "Bind":
+			if len(call.Args) == 2 {
+				iface, impl := newTypeName(call.Args[0]), newTypeName(call.Args[1])
+				if iface != "" && impl != "" {
+					regs = append(regs, diRegistration{container: "wire", role: "bind", set: set, line: line, iface: iface, impl: impl})
+				}
+			}
+		}
+	case container == "fx":
+		switch sel.Sel.Name {
+		case "Provide", "Invoke":
+			role := strings.ToLower(sel.Sel.Name)
+			for _, arg := range call.Args {
+				if inner, ok := arg.(*ast.CallExpr); ok {
+					// fx.Annotate(NewStore, fx.As(new(Repo))) provides NewStore.
+					if s, ok := inner.Fun.(*ast.SelectorExpr); ok && s.Sel.Name == "Annotate" && len(inner.Args) > 0 {
+						arg = inner.Args[0]
+					} else {
+						continue
+					}
+				}
+				regs = append(regs, diRegistration{container: "fx", role: role, set: set, expr: arg, line: line})
+			}
+		case "Module", "Options", "New":
+			args := call.Args
+			if sel.Sel.Name == "New" {
+				set = ""
+			}
+			if sel.Sel.Name == "Module" && len(args) > 0 {
+				if name := stringLit(args[0]); name != "" {
+					set = name
+				}
+				args = args[1:]
+			}
+			for _, arg := range args {
+				regs = e.diCall(regs, aliases, hasDig, arg, set)
+			}
+		}
+	case container == "" && hasDig:
+		// container.Provide(NewStore) and container.Invoke(Register).
+		switch sel.Sel.Name {
+		case "Provide", "Invoke":
+			if len(call.Args) > 0 {
+				regs = append(regs, diRegistration{container: "dig", role: strings.ToLower(sel.Sel.Name), set: set, expr: call.Args[0], line: line})
+			}
+		}
+	}
+	return regs
+}
+
+// addDIRegistration records r as a Dependency node contained by the file.
+// Named constructors are recorded by name; function literals by their
+// signature.
+func (e *extractor) addDIRegistration(r diRegistration) {
+	if r.expr != nil {
+		r.line = e.pos(r.expr.Pos())
+	}
+	props := map[string]string{
+		"kind":      "di_provider",
+		"container": r.container,
+		"role":      r.role,
+	}
+	if r.set != "" {
+		props["set"] = r.set
+	}
+
+	var name string
+	switch x := r.expr.(type) {
+	case nil:
+		name = r.iface + " => " + r.impl
+		props["interface"] = r.iface
+		props["implementation"] = r.impl
+	case *ast.Ident, *ast.SelectorExpr:
+		name = typeExprString(x)
+		props["provider"] = name
+	case *ast.FuncLit:
+		var b strings.Builder
+		b.WriteString("func(")
+		if x.Type.Params != nil {
+			writeFieldList(&b, x.Type.Params)
+		}
+		b.WriteString(")")
+		if x.Type.Results != nil && len(x.Type.Results.List) > 0 {
+			b.WriteString(" (")
+			writeFieldList(&b, x.Type.Results)
+			b.WriteString(")")
+		}
+		name = b.String()
+		props["signature"] = name
+	default:
+		return
+	}
+
+	id := graph.NewNodeID(string(graph.NodeDependency), e.filePath, fmt.Sprintf("%s:%s:%d", r.role, name, r.line))
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:         id,
+		Type:       graph.NodeDependency,
+		Name:       name,
+		FilePath:   e.filePath,
+		Line:       r.line,
+		Language:   string(parser.LangGo),
+		Package:    e.file.Name.Name,
+		Properties: props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.fileNodeID, id, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.fileNodeID,
+		TargetID: id,
+	})
+}
+
+// newTypeName returns the type named by new(T) or new(*T), without the
+// pointer, or "".
+func newTypeName(x ast.Expr) string {
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return ""
+	}
+	if id, ok := call.Fun.(*ast.Ident); !ok || id.Name != "new" {
+		return ""
+	}
+	return strings.TrimPrefix(typeExprString(call.Args[0]), "*")
+}
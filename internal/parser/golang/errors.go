@@ -0,0 +1,163 @@
+package golang
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"unicode"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// extractErrorSites marks the file's error types and records the errors
+// each function surfaces:
+//
+//	func (e *DeclinedError) Error() string       // DeclinedError is an error type
+//	var ErrNotFound = errors.New("not found")    // ErrNotFound is a sentinel error
+//	return nil, &DeclinedError{Code: code}       // throws DeclinedError
+//	return fmt.Errorf("load: %w", ErrNotFound)   // throws ErrNotFound
+//	panic(ErrCorrupt)                            // panics, throws ErrCorrupt
+//
+// Returns are only inspected in functions whose last result is an error.
+// Function literals are skipped, since their returns are not the
+// enclosing function's.
+func (e *extractor) extractErrorSites() {
+	byID := make(map[string]*graph.Node, len(e.nodes))
+	for _, n := range e.nodes {
+		byID[n.ID] = n
+	}
+	markErrorType := func(id string) {
+		if n := byID[id]; n != nil {
+			if n.Properties == nil {
+				n.Properties = make(map[string]string)
+			}
+			n.Properties[parser.PropErrorType] = "true"
+		}
+	}
+
+	for recv, methods := range e.methodsByReceiver {
+		if _, ok := methods["Error"]; ok {
+			markErrorType(graph.NewNodeID(string(graph.NodeStruct), e.filePath, recv))
+			markErrorType(graph.NewNodeID(string(graph.NodeType_), e.filePath, recv))
+		}
+	}
+
+	for _, decl := range e.file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range d.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, name := range vs.Names {
+					if i < len(vs.Values) && isErrorConstructor(vs.Values[i]) {
+						markErrorType(graph.NewNodeID(string(graph.NodeVariable), e.filePath, name.Name))
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Body == nil {
+				continue
+			}
+			fnID := e.enclosingFuncNodeID(d)
+			returnsError := false
+			if res := d.Type.Results; res != nil && len(res.List) > 0 {
+				last, ok := res.List[len(res.List)-1].Type.(*ast.Ident)
+				returnsError = ok && last.Name == "error"
+			}
+			ast.Inspect(d.Body, func(n ast.Node) bool {
+				switch x := n.(type) {
+				case *ast.FuncLit:
+					return false
+				case *ast.ReturnStmt:
+					if returnsError && len(x.Results) > 0 {
+						for _, name := range errorNames(x.Results[len(x.Results)-1]) {
+							parser.AddThrows(e.nodes, fnID, name)
+						}
+					}
+				case *ast.CallExpr:
+					if id, ok := x.Fun.(*ast.Ident); ok && id.Name == "panic" && len(x.Args) == 1 {
+						if fn := byID[fnID]; fn != nil {
+							if fn.Properties == nil {
+								fn.Properties = make(map[string]string)
+							}
+							fn.Properties[parser.PropPanics] = "true"
+						}
+						for _, name := range errorNames(x.Args[0]) {
+							parser.AddThrows(e.nodes, fnID, name)
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// errorNames returns the error types and sentinel errors an error
+// expression evaluates to or wraps: &T{...}, T{...}, ErrX, pkg.ErrX, and
+// those passed to fmt.Errorf or errors.Join.
+func errorNames(x ast.Expr) []string {
+	switch v := x.(type) {
+	case *ast.UnaryExpr:
+		if v.Op == token.AND {
+			return errorNames(v.X)
+		}
+	case *ast.CompositeLit:
+		switch v.Type.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			return []string{types.ExprString(v.Type)}
+		}
+	case *ast.Ident:
+		if isSentinelName(v.Name) {
+			return []string{v.Name}
+		}
+	case *ast.SelectorExpr:
+		if _, ok := v.X.(*ast.Ident); ok && isSentinelName(v.Sel.Name) {
+			return []string{types.ExprString(v)}
+		}
+	case *ast.CallExpr:
+		if sel, ok := v.Fun.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok &&
+				(pkg.Name == "fmt" && sel.Sel.Name == "Errorf" || pkg.Name == "errors" && sel.Sel.Name == "Join") {
+				var names []string
+				for _, arg := range v.Args {
+					names = append(names, errorNames(arg)...)
+				}
+				return names
+			}
+		}
+	}
+	return nil
+}
+
+// isSentinelName reports whether name follows the ErrX / errX naming of
+// sentinel errors.
+func isSentinelName(name string) bool {
+	for _, prefix := range []string{"Err", "err"} {
+		if len(name) > len(prefix) && name[:len(prefix)] == prefix && unicode.IsUpper(rune(name[len(prefix)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorConstructor reports whether x is an errors.New or fmt.Errorf
+// call.
+func isErrorConstructor(x ast.Expr) bool {
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && (pkg.Name == "errors" && sel.Sel.Name == "New" || pkg.Name == "fmt" && sel.Sel.Name == "Errorf")
+}
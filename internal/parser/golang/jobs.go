@@ -0,0 +1,196 @@
+package golang
+
+import (
+	"cmp"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// extractScheduledJobs records cron registrations as Job nodes that call
+// their handler:
+//
+//	c.AddFunc("0 3 * * *", cleanup)                            // robfig/cron
+//	s.Every(5).Minutes().Do(poll)                              // go-co-op/gocron
+//	s.NewJob(gocron.CronJob("0 3 * * *", false), gocron.NewTask(cleanup)) // gocron v2
+func (e *extractor) extractScheduledJobs() {
+	for _, decl := range e.file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		recvParamName, recvTypeName := receiverParam(fn)
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			var schedule, framework string
+			var handler ast.Expr
+			switch sel.Sel.Name {
+			case "AddFunc", "AddJob":
+				if len(call.Args) >= 2 {
+					if spec := stringLit(call.Args[0]); parser.IsCronSchedule(spec) {
+						schedule, framework, handler = spec, "robfig/cron", call.Args[1]
+					}
+				}
+			case "Do":
+				if len(call.Args) >= 1 {
+					if schedule = gocronChainSchedule(sel.X); schedule != "" {
+						framework, handler = "gocron", call.Args[0]
+					}
+				}
+			case "NewJob":
+				if len(call.Args) >= 2 {
+					if schedule = gocronDefinitionSchedule(call.Args[0]); schedule != "" {
+						framework, handler = "gocron", gocronTask(call.Args[1])
+					}
+				}
+			}
+			if framework == "" || handler == nil {
+				return true
+			}
+
+			name := ""
+			switch h := handler.(type) {
+			case *ast.Ident, *ast.SelectorExpr:
+				name = typeExprString(h)
+			case *ast.FuncLit:
+			default:
+				handler = nil
+			}
+			job := parser.NewJobNode(e.filePath, parser.LangGo, parser.Job{
+				Name:      cmp.Or(name, "cron "+schedule),
+				Schedule:  schedule,
+				Framework: framework,
+				Handler:   name,
+				Line:      e.pos(call.Pos()),
+			})
+			job.Package = e.file.Name.Name
+			e.nodes = append(e.nodes, job)
+			e.edges = append(e.edges, &graph.Edge{
+				ID:       edgeID(e.fileNodeID, job.ID, string(graph.EdgeContains)),
+				Type:     graph.EdgeContains,
+				SourceID: e.fileNodeID,
+				TargetID: job.ID,
+			})
+			if handler != nil {
+				e.linkRouteHandler(job, handler, recvParamName, recvTypeName)
+			}
+			return true
+		})
+	}
+}
+
+// gocronUnits are the gocron v1 scheduler methods naming an interval unit.
+var gocronUnits = map[string]string{
+	"Second": "second", "Seconds": "seconds", "Minute": "minute", "Minutes": "minutes",
+	"Hour": "hour", "Hours": "hours", "Day": "day", "Days": "days",
+	"Week": "week", "Weeks": "weeks", "Month": "month", "Months": "months",
+	"Monday": "monday", "Tuesday": "tuesday", "Wednesday": "wednesday", "Thursday": "thursday",
+	"Friday": "friday", "Saturday": "saturday", "Sunday": "sunday",
+}
+
+// gocronChainSchedule returns the schedule of a gocron v1 builder chain
+// such as s.Every(5).Minutes().At("10:30") ("every 5 minutes at 10:30") or
+// s.Cron("0 3 * * *"), or "" when x is not one.
+func gocronChainSchedule(x ast.Expr) string {
+	var every, unit, at string
+	for {
+		call, ok := x.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		switch name := sel.Sel.Name; name {
+		case "Cron", "CronWithSeconds":
+			if len(call.Args) == 1 {
+				return stringLit(call.Args[0])
+			}
+		case "Every":
+			if len(call.Args) == 1 {
+				every = strings.Trim(types.ExprString(call.Args[0]), `"`)
+			}
+		case "At":
+			if len(call.Args) == 1 {
+				at = strings.Trim(types.ExprString(call.Args[0]), `"`)
+			}
+		default:
+			if u, ok := gocronUnits[name]; ok {
+				unit = u
+			}
+		}
+		x = sel.X
+	}
+	if every == "" {
+		return ""
+	}
+	schedule := "every " + every
+	if unit != "" {
+		schedule += " " + unit
+	}
+	if at != "" {
+		schedule += " at " + at
+	}
+	return schedule
+}
+
+// gocronDefinitionSchedule returns the schedule of a gocron v2 job
+// definition (gocron.CronJob, DurationJob, DailyJob, ...), or "".
+func gocronDefinitionSchedule(x ast.Expr) string {
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return ""
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	switch sel.Sel.Name {
+	case "CronJob":
+		return stringLit(call.Args[0])
+	case "DurationJob":
+		return "@every " + types.ExprString(call.Args[0])
+	case "DailyJob":
+		return "daily"
+	case "WeeklyJob":
+		return "weekly"
+	case "MonthlyJob":
+		return "monthly"
+	}
+	return ""
+}
+
+// gocronTask returns the function of a gocron.NewTask(fn, args...) call.
+func gocronTask(x ast.Expr) ast.Expr {
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	if sel, ok := call.Fun.(*ast.SelectorExpr); !ok || sel.Sel.Name != "NewTask" {
+		return nil
+	}
+	return call.Args[0]
+}
+
+// stringLit returns the value of a string literal, or "".
+func stringLit(x ast.Expr) string {
+	lit, ok := x.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	return strings.Trim(lit.Value, "`\"")
+}
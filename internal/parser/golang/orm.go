@@ -0,0 +1,121 @@
+package golang
+
+import (
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// gormValueTypes are struct field types that hold column values rather
+// than associations.
+var gormValueTypes = map[string]bool{
+	"Time": true, "Duration": true, "Model": true, "DeletedAt": true, "UUID": true,
+	"JSON": true, "JSONMap": true, "JSONType": true, "Date": true, "Decimal": true,
+	"RawMessage": true, "NullString": true, "NullInt64": true, "NullInt32": true,
+	"NullBool": true, "NullFloat64": true, "NullTime": true,
+}
+
+// gormRelations reports whether a struct is a GORM model, one embedding
+// gorm.Model or tagging a field with `gorm:"..."`, and returns the
+// associations its fields declare. A slice of structs is a has-many, or
+// many-to-many with a many2many tag naming the join table. A single
+// struct is a belongs-to when the model holds its foreign key (the field
+// name suffixed with ID, or a foreignKey tag naming a field of the
+// model), and a has-one otherwise.
+func gormRelations(st *ast.StructType) (bool, []parser.ModelRelation) {
+	if st.Fields == nil {
+		return false, nil
+	}
+	isModel := false
+	fieldNames := make(map[string]bool)
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 && typeExprString(f.Type) == "gorm.Model" {
+			isModel = true
+		}
+		if _, ok := gormTag(f); ok {
+			isModel = true
+		}
+		for _, n := range f.Names {
+			fieldNames[n.Name] = true
+		}
+	}
+	if !isModel {
+		return false, nil
+	}
+
+	var relations []parser.ModelRelation
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			continue
+		}
+		tag, _ := gormTag(f)
+		if tag["-"] != "" {
+			continue
+		}
+		typ, collection := f.Type, false
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		if arr, ok := typ.(*ast.ArrayType); ok && arr.Len == nil {
+			typ, collection = arr.Elt, true
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+		}
+		target := ""
+		switch t := typ.(type) {
+		case *ast.Ident:
+			target = t.Name
+		case *ast.SelectorExpr:
+			target = t.Sel.Name
+		}
+		if target == "" || !ast.IsExported(target) || gormValueTypes[target] {
+			continue
+		}
+		for _, n := range f.Names {
+			r := parser.ModelRelation{Field: n.Name, Target: target}
+			switch {
+			case collection && tag["many2many"] != "":
+				r.Cardinality, r.Through = parser.CardinalityManyToMany, tag["many2many"]
+			case collection:
+				r.Cardinality = parser.CardinalityOneToMany
+			case fieldNames[n.Name+"ID"] || fieldNames[tag["foreignkey"]]:
+				r.Cardinality = parser.CardinalityManyToOne
+			default:
+				r.Cardinality = parser.CardinalityOneToOne
+			}
+			relations = append(relations, r)
+		}
+	}
+	return true, relations
+}
+
+// gormTag returns the settings of a field's gorm struct tag, keyed by
+// lower-cased name: `gorm:"foreignKey:UserID;many2many:user_roles"` yields
+// foreignkey=UserID and many2many=user_roles. Flags such as "-" map to
+// themselves.
+func gormTag(f *ast.Field) (map[string]string, bool) {
+	if f.Tag == nil {
+		return nil, false
+	}
+	raw, err := strconv.Unquote(f.Tag.Value)
+	if err != nil {
+		return nil, false
+	}
+	value, ok := reflect.StructTag(raw).Lookup("gorm")
+	if !ok {
+		return nil, false
+	}
+	settings := make(map[string]string)
+	for _, setting := range strings.Split(value, ";") {
+		k, v, found := strings.Cut(strings.TrimSpace(setting), ":")
+		if !found {
+			v = k
+		}
+		settings[strings.ToLower(k)] = v
+	}
+	return settings, true
+}
@@ -1,10 +1,14 @@
 package golang
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	goparser "go/parser"
+	"go/scanner"
 	"go/token"
+	"go/types"
+	"slices"
 	"strings"
 	"unicode"
 
@@ -13,13 +17,20 @@ import (
 )
 
 // GoParser extracts knowledge graph nodes and edges from Go source files.
-type GoParser struct{}
+type GoParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new Go parser.
 func NewParser() *GoParser {
 	return &GoParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *GoParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *GoParser) Language() parser.Language {
 	return parser.LangGo
 }
@@ -31,22 +42,36 @@ func (p *GoParser) Extensions() []string {
 func (p *GoParser) ParseFile(filePath string, content []byte) (*parser.ParseResult, error) {
 	fset := token.NewFileSet()
 	file, err := goparser.ParseFile(fset, filePath, content, goparser.ParseComments)
+	// Syntax errors still yield a partial AST; extract what it holds unless
+	// even the package clause is unreadable.
+	var diags []parser.Diagnostic
 	if err != nil {
-		return nil, fmt.Errorf("parsing %s: %w", filePath, err)
+		var list scanner.ErrorList
+		if file == nil || file.Name == nil || file.Name.Name == "" || !errors.As(err, &list) {
+			return nil, fmt.Errorf("parsing %s: %w", filePath, err)
+		}
+		for _, e := range list {
+			if len(diags) == parser.MaxDiagnostics {
+				break
+			}
+			diags = append(diags, parser.Diagnostic{Line: e.Pos.Line, Column: e.Pos.Column, Message: e.Msg})
+		}
 	}
 
 	e := &extractor{
-		fset:     fset,
-		file:     file,
-		filePath: filePath,
+		fset:         fset,
+		file:         file,
+		filePath:     filePath,
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
 	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangGo,
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangGo,
+		Diagnostics: diags,
 	}, nil
 }
 
@@ -61,9 +86,10 @@ type extractor struct {
 	nodes    []*graph.Node
 	edges    []*graph.Edge
 
-	pkgNodeID  string
-	fileNodeID string
-	isTestFile bool
+	pkgNodeID    string
+	fileNodeID   string
+	isTestFile   bool
+	testPatterns *parser.TestPatterns
 
 	// Track interfaces and struct methods for Implements edge detection.
 	interfaces    map[string]map[string]bool // interface name -> set of method names
@@ -76,6 +102,11 @@ type extractor struct {
 	importAliasMap    map[string]string            // import alias → dep node ID
 	funcNameMap       map[string]string            // function name → node ID
 	methodsByReceiver map[string]map[string]string // receiver type → method name → node ID
+
+	// Function literals with nodes of their own, built by extractClosures().
+	closures    []closure
+	litDecls    map[*ast.FuncLit]*ast.FuncDecl // literal → function declaring it
+	closureVars map[*ast.Object]string         // variable bound to a closure → node ID
 }
 
 func (e *extractor) extract() {
@@ -87,16 +118,22 @@ func (e *extractor) extract() {
 	e.extractPackage()
 	e.extractImports()
 	e.extractDeclarations()
+	e.extractReceiverContains()
+	e.extractClosures()
+	e.buildCallMaps()
 	e.extractHTTPRoutes()
+	e.extractScheduledJobs()
+	e.extractDIProviders()
 	e.extractHTTPClientCalls()
 	e.extractImplementsEdges()
-	e.buildCallMaps()
 	e.extractFunctionCalls()
+	e.extractErrorSites()
+	e.extractBuildConstraint()
 }
 
 func (e *extractor) extractFileNode() {
 	nodeType := graph.NodeFile
-	if strings.HasSuffix(e.filePath, "_test.go") {
+	if strings.HasSuffix(e.filePath, "_test.go") || e.testPatterns.MatchesFile(e.filePath) {
 		nodeType = graph.NodeTestFile
 		e.isTestFile = true
 	}
@@ -140,28 +177,21 @@ func (e *extractor) extractPackage() {
 
 func (e *extractor) extractImports() {
 	for _, imp := range e.file.Imports {
-		path := strings.Trim(imp.Path.Value, `"`)
-		depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, path)
-
-		e.nodes = append(e.nodes, &graph.Node{
-			ID:       depID,
-			Type:     graph.NodeDependency,
-			Name:     path,
-			FilePath: e.filePath,
-			Line:     e.pos(imp.Pos()),
-			Language: string(parser.LangGo),
-			Package:  e.file.Name.Name,
-			Properties: map[string]string{
-				"kind": "import",
-			},
-		})
+		dep, edge := e.importOf(imp).Link(e.pkgNodeID)
+		e.nodes = append(e.nodes, dep)
+		e.edges = append(e.edges, edge)
+	}
+}
 
-		e.edges = append(e.edges, &graph.Edge{
-			ID:       edgeID(e.pkgNodeID, depID, string(graph.EdgeImports)),
-			Type:     graph.EdgeImports,
-			SourceID: e.pkgNodeID,
-			TargetID: depID,
-		})
+// importOf describes an import spec of the file.
+func (e *extractor) importOf(imp *ast.ImportSpec) parser.Import {
+	path := strings.Trim(imp.Path.Value, `"`)
+	return parser.Import{
+		Language: parser.LangGo,
+		Name:     path,
+		FilePath: e.filePath,
+		Line:     e.pos(imp.Pos()),
+		Local:    strings.HasPrefix(path, "."),
 	}
 }
 
@@ -226,7 +256,7 @@ func (e *extractor) extractFuncDecl(fn *ast.FuncDecl) {
 	} else {
 		// Function — detect test functions in test files.
 		nodeType := graph.NodeFunction
-		if e.isTestFile && isTestFuncName(name) {
+		if e.isTestFile && (isTestFuncName(name) || e.testPatterns.MatchesFunction(name)) {
 			nodeType = graph.NodeTestFunction
 		}
 		funcID := graph.NewNodeID(string(nodeType), e.filePath, name)
@@ -256,6 +286,33 @@ func (e *extractor) extractFuncDecl(fn *ast.FuncDecl) {
 	}
 }
 
+// extractReceiverContains adds a Contains edge from each type declared in
+// the file to its methods declared in the file, so methods hang off their
+// type as they do off classes in other languages. The linker's go_methods
+// phase links methods declared in other files of the package.
+func (e *extractor) extractReceiverContains() {
+	typeIDs := make(map[string]string) // type name -> node ID
+	for _, n := range e.nodes {
+		if n.Type == graph.NodeStruct || n.Type == graph.NodeType_ {
+			typeIDs[n.Name] = n.ID
+		}
+	}
+	for _, n := range e.nodes {
+		if n.Type != graph.NodeMethod {
+			continue
+		}
+		recv, _, _ := strings.Cut(n.Properties["receiver"], "[") // generic receivers
+		if typeID, ok := typeIDs[recv]; ok {
+			e.edges = append(e.edges, &graph.Edge{
+				ID:       edgeID(typeID, n.ID, string(graph.EdgeContains)),
+				Type:     graph.EdgeContains,
+				SourceID: typeID,
+				TargetID: n.ID,
+			})
+		}
+	}
+}
+
 func (e *extractor) extractGenDecl(decl *ast.GenDecl) {
 	for _, spec := range decl.Specs {
 		switch s := spec.(type) {
@@ -333,6 +390,15 @@ func (e *extractor) extractStruct(structName string, exported bool, doc string,
 		}
 		props["fields"] = strings.Join(fields, ",")
 	}
+	if fields := sensitiveFields(st); len(fields) > 0 {
+		props[parser.PropTaggedFields] = parser.FormatSensitiveFields(fields)
+	}
+	if isModel, relations := gormRelations(st); isModel {
+		props[parser.PropORM] = "gorm"
+		if len(relations) > 0 {
+			props[parser.PropModelRelations] = parser.FormatModelRelations(relations)
+		}
+	}
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            structID,
@@ -458,13 +524,28 @@ var ginMethods = map[string]bool{
 	"Any":    true,
 }
 
+// chiMethods maps chi's route registration methods to HTTP verbs.
+var chiMethods = map[string]string{
+	"Get":     "GET",
+	"Post":    "POST",
+	"Put":     "PUT",
+	"Patch":   "PATCH",
+	"Delete":  "DELETE",
+	"Head":    "HEAD",
+	"Options": "OPTIONS",
+}
+
 // routeInfo holds a detected HTTP route.
 type routeInfo struct {
-	method    string // HTTP method (GET, POST, etc.)
-	path      string // Route path
-	framework string // "gin", "net/http", "gorilla/mux"
-	handler   string // Handler function/identifier name
-	line      int    // Source line
+	method    string   // HTTP method (GET, POST, etc.)
+	path      string   // Route path
+	framework string   // "gin", "chi", "net/http", "gorilla/mux"
+	handler   string   // Handler function/identifier name
+	handlerX  ast.Expr // Handler expression, for resolving it to its declaration
+	line      int      // Source line
+	// middleware names the middleware passed to the registration itself,
+	// as in r.GET("/admin", AuthRequired(), handler).
+	middleware []string
 }
 
 func (e *extractor) extractHTTPRoutes() {
@@ -475,10 +556,7 @@ func (e *extractor) extractHTTPRoutes() {
 		}
 
 		enclosingNodeID := e.enclosingFuncNodeID(fn)
-
-		// Collect group prefix assignments: variable name -> prefix path.
-		groupPrefixes := make(map[string]string)
-		e.collectGroupPrefixes(fn.Body, groupPrefixes)
+		recvParamName, recvTypeName := receiverParam(fn)
 
 		// Track inner calls consumed by chained .Methods() to avoid duplicates.
 		consumedCalls := make(map[*ast.CallExpr]bool)
@@ -500,29 +578,109 @@ func (e *extractor) extractHTTPRoutes() {
 		})
 
 		// Second pass: match all route registrations.
-		ast.Inspect(fn.Body, func(n ast.Node) bool {
-			call, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
+		e.walkRoutes(fn.Body, make(map[string]string), make(map[string][]string), func(call *ast.CallExpr, groupPrefixes map[string]string, middleware map[string][]string) {
 			if consumedCalls[call] {
-				return true
+				return
 			}
-
 			routes := e.matchRouteCall(call, groupPrefixes)
 			for _, r := range routes {
-				e.addRouteNode(r, enclosingNodeID)
+				endpoint := e.addRouteNode(r, enclosingNodeID)
+				if mw := append(slices.Clone(middleware[routerName(call)]), r.middleware...); len(mw) > 0 {
+					endpoint.Properties[parser.PropMiddleware] = strings.Join(mw, ",")
+				}
+				e.linkRouteHandler(endpoint, r.handlerX, recvParamName, recvTypeName)
 			}
+		})
+	}
+}
 
+// walkRoutes calls visit for each call in body with the route prefixes of
+// the router variables in scope (variable name -> prefix path) and the
+// middleware they apply (variable name -> middleware names). A chi
+// sub-router, r.Route("/users", func(r chi.Router) { ... }), is walked
+// with its parameter mapped to the receiver's prefix plus the pattern.
+func (e *extractor) walkRoutes(body *ast.BlockStmt, inherited map[string]string, inheritedMW map[string][]string, visit func(*ast.CallExpr, map[string]string, map[string][]string)) {
+	prefixes := make(map[string]string, len(inherited))
+	for k, v := range inherited {
+		prefixes[k] = v
+	}
+	e.collectGroupPrefixes(body, prefixes)
+	middleware := make(map[string][]string, len(inheritedMW))
+	for k, v := range inheritedMW {
+		middleware[k] = v
+	}
+	collectRouterMiddleware(body, middleware)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
 			return true
-		})
+		}
+		if sub, inner, ok := chiSubRouter(call); ok {
+			sel := call.Fun.(*ast.SelectorExpr)
+			prefix := joinRoutePath(groupPrefix(sel.X, prefixes), sub)
+			scoped := map[string]string{}
+			for k, v := range prefixes {
+				scoped[k] = v
+			}
+			scopedMW := map[string][]string{}
+			for k, v := range middleware {
+				scopedMW[k] = v
+			}
+			if params := inner.Type.Params.List; len(params) > 0 && len(params[0].Names) > 0 {
+				scoped[params[0].Names[0].Name] = prefix
+				scopedMW[params[0].Names[0].Name] = middleware[routerName(call)]
+			}
+			e.walkRoutes(inner.Body, scoped, scopedMW, visit)
+			return false
+		}
+		visit(call, prefixes, middleware)
+		return true
+	})
+}
+
+// chiSubRouter reports whether call is a chi sub-router declaration,
+// r.Route(pattern, func(r chi.Router) { ... }), returning its pattern and
+// function.
+func chiSubRouter(call *ast.CallExpr) (string, *ast.FuncLit, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Route" || len(call.Args) != 2 {
+		return "", nil, false
+	}
+	pathLit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || pathLit.Kind != token.STRING {
+		return "", nil, false
+	}
+	fn, ok := call.Args[1].(*ast.FuncLit)
+	if !ok || fn.Body == nil {
+		return "", nil, false
 	}
+	return strings.Trim(pathLit.Value, `"`), fn, true
 }
 
-// collectGroupPrefixes scans for Gin router group assignments like:
+// groupPrefix returns the route prefix of a router expression: the prefix
+// recorded for a group variable, or "".
+func groupPrefix(recv ast.Expr, prefixes map[string]string) string {
+	if ident, ok := recv.(*ast.Ident); ok {
+		return prefixes[ident.Name]
+	}
+	return ""
+}
+
+// joinRoutePath appends a route pattern to a group prefix.
+func joinRoutePath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	return strings.TrimRight(prefix, "/") + path
+}
+
+// collectGroupPrefixes scans for Gin/Echo router group and gorilla/mux
+// subrouter assignments like:
 //
 //	v1 := r.Group("/api/v1")
 //	api := router.Group("/api")
+//	s := r.PathPrefix("/admin").Subrouter()
 func (e *extractor) collectGroupPrefixes(body *ast.BlockStmt, prefixes map[string]string) {
 	for _, stmt := range body.List {
 		assign, ok := stmt.(*ast.AssignStmt)
@@ -534,7 +692,20 @@ func (e *extractor) collectGroupPrefixes(body *ast.BlockStmt, prefixes map[strin
 			continue
 		}
 		sel, ok := call.Fun.(*ast.SelectorExpr)
-		if !ok || sel.Sel.Name != "Group" {
+		if !ok {
+			continue
+		}
+		if sel.Sel.Name == "Subrouter" {
+			inner, ok := sel.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+			if !ok || innerSel.Sel.Name != "PathPrefix" {
+				continue
+			}
+			call, sel = inner, innerSel
+		} else if sel.Sel.Name != "Group" {
 			continue
 		}
 		if len(call.Args) < 1 {
@@ -544,14 +715,9 @@ func (e *extractor) collectGroupPrefixes(body *ast.BlockStmt, prefixes map[strin
 		if !ok || pathLit.Kind != token.STRING {
 			continue
 		}
-		prefix := strings.Trim(pathLit.Value, `"`)
 
-		// Check if the receiver itself is a known group variable.
-		if recvIdent, ok := sel.X.(*ast.Ident); ok {
-			if parentPrefix, exists := prefixes[recvIdent.Name]; exists {
-				prefix = strings.TrimRight(parentPrefix, "/") + prefix
-			}
-		}
+		// The receiver itself may be a known group variable.
+		prefix := joinRoutePath(groupPrefix(sel.X, prefixes), strings.Trim(pathLit.Value, `"`))
 
 		// Store for each LHS identifier.
 		for _, lhs := range assign.Lhs {
@@ -562,6 +728,99 @@ func (e *extractor) collectGroupPrefixes(body *ast.BlockStmt, prefixes map[strin
 	}
 }
 
+// collectRouterMiddleware records, in statement order, the middleware
+// router variables apply to the routes registered on them:
+//
+//	r.Use(Logger(), AuthRequired())
+//	admin := r.Group("/admin", RequireRole("admin"))
+//	s := r.PathPrefix("/api").Subrouter()
+//
+// Groups and subrouters inherit the middleware of their parent.
+func collectRouterMiddleware(body *ast.BlockStmt, middleware map[string][]string) {
+	for _, stmt := range body.List {
+		switch st := stmt.(type) {
+		case *ast.ExprStmt:
+			call, ok := st.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Use" {
+				continue
+			}
+			if recv, ok := sel.X.(*ast.Ident); ok {
+				middleware[recv.Name] = append(slices.Clone(middleware[recv.Name]), middlewareNames(call.Args)...)
+			}
+		case *ast.AssignStmt:
+			if len(st.Rhs) == 0 {
+				continue
+			}
+			call, ok := st.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			var mw []string
+			switch sel.Sel.Name {
+			case "Group":
+				if len(call.Args) == 0 {
+					continue
+				}
+				mw = append(slices.Clone(middleware[routerName(call)]), middlewareNames(call.Args[1:])...)
+			case "Subrouter":
+				mw = middleware[routerName(call)]
+			default:
+				continue
+			}
+			if len(mw) == 0 {
+				continue
+			}
+			for _, lhs := range st.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					middleware[ident.Name] = mw
+				}
+			}
+		}
+	}
+}
+
+// routerName returns the router variable a route registration or router
+// method is called on, following chained calls such as
+// r.HandleFunc(...).Methods("GET") and r.PathPrefix(...).Subrouter().
+func routerName(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.CallExpr:
+		return routerName(x)
+	}
+	return ""
+}
+
+// middlewareNames returns the names of middleware arguments: the function
+// of a middleware constructor call (AuthRequired for AuthRequired()) or the
+// middleware identifier itself. Function literals are skipped.
+func middlewareNames(args []ast.Expr) []string {
+	var names []string
+	for _, arg := range args {
+		if call, ok := arg.(*ast.CallExpr); ok {
+			arg = call.Fun
+		}
+		switch arg.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			names = append(names, typeExprString(arg))
+		}
+	}
+	return names
+}
+
 // matchRouteCall attempts to match a call expression as an HTTP route registration.
 // Returns nil if it doesn't match.
 func (e *extractor) matchRouteCall(call *ast.CallExpr, groupPrefixes map[string]string) []routeInfo {
@@ -577,12 +836,17 @@ func (e *extractor) matchRouteCall(call *ast.CallExpr, groupPrefixes map[string]
 		return e.matchGinRoute(call, sel, methodName, groupPrefixes)
 	}
 
-	// Case 2: net/http or gorilla/mux — mux.HandleFunc("/path", handler) or http.Handle("/path", handler)
+	// Case 2: chi routes — r.Get("/path", handler)
+	if httpMethod, ok := chiMethods[methodName]; ok {
+		return e.matchChiRoute(call, sel, httpMethod, groupPrefixes)
+	}
+
+	// Case 3: net/http or gorilla/mux — mux.HandleFunc("/path", handler) or http.Handle("/path", handler)
 	if methodName == "HandleFunc" || methodName == "Handle" {
-		return e.matchHandleFuncRoute(call, sel)
+		return e.matchHandleFuncRoute(call, sel, groupPrefixes)
 	}
 
-	// Case 3: gorilla/mux chained — r.HandleFunc("/path", handler).Methods("GET")
+	// Case 4: gorilla/mux chained — r.HandleFunc("/path", handler).Methods("GET")
 	// This is handled when we see the outer Methods() call.
 	if methodName == "Methods" {
 		return e.matchGorillaMethodsChain(call, sel, groupPrefixes)
@@ -600,32 +864,88 @@ func (e *extractor) matchGinRoute(call *ast.CallExpr, sel *ast.SelectorExpr, met
 	if !ok || pathLit.Kind != token.STRING {
 		return nil
 	}
-	path := strings.Trim(pathLit.Value, `"`)
-
-	// Check if receiver is a group variable with a known prefix.
-	if recvIdent, ok := sel.X.(*ast.Ident); ok {
-		if prefix, exists := groupPrefixes[recvIdent.Name]; exists {
-			path = strings.TrimRight(prefix, "/") + path
-		}
-	}
+	// The receiver may be a group variable with a known prefix.
+	path := joinRoutePath(groupPrefix(sel.X, groupPrefixes), strings.Trim(pathLit.Value, `"`))
 
 	httpMethod := methodName
 	if methodName == "Handle" || methodName == "Any" {
 		httpMethod = methodName
 	}
 
-	handler := e.extractHandlerName(call, 1)
+	handlerIdx := ginHandlerIndex(call.Args)
+	handler, handlerX := e.extractHandlerName(call, handlerIdx)
+	var middleware []string
+	for i := 1; i < len(call.Args); i++ {
+		if i != handlerIdx {
+			middleware = append(middleware, middlewareNames(call.Args[i:i+1])...)
+		}
+	}
+
+	return []routeInfo{{
+		method:     httpMethod,
+		path:       path,
+		framework:  "gin",
+		handler:    handler,
+		handlerX:   handlerX,
+		line:       e.pos(call.Pos()),
+		middleware: middleware,
+	}}
+}
+
+// ginHandlerIndex returns the index of the handler among the arguments of a
+// Gin or Echo route registration. Gin takes middleware before the handler,
+// r.GET(path, AuthRequired(), handler); Echo after it, e.GET(path, handler,
+// middleware.JWT(key)). Middleware are usually constructor calls, so a call
+// right after the path means Gin and a trailing call means Echo; otherwise
+// the Gin order is assumed.
+func ginHandlerIndex(args []ast.Expr) int {
+	if len(args) <= 2 {
+		return 1
+	}
+	if _, ok := args[1].(*ast.CallExpr); ok {
+		return len(args) - 1
+	}
+	if _, ok := args[len(args)-1].(*ast.CallExpr); ok {
+		return 1
+	}
+	return len(args) - 1
+}
+
+func (e *extractor) matchChiRoute(call *ast.CallExpr, sel *ast.SelectorExpr, httpMethod string, groupPrefixes map[string]string) []routeInfo {
+	if len(call.Args) < 2 {
+		return nil
+	}
+
+	pathLit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || pathLit.Kind != token.STRING {
+		return nil
+	}
+	// Get and friends are common method names: require a pattern and a
+	// handler argument.
+	pattern := strings.Trim(pathLit.Value, `"`)
+	if !strings.HasPrefix(pattern, "/") {
+		return nil
+	}
+	switch call.Args[1].(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.FuncLit, *ast.CallExpr:
+	default:
+		return nil
+	}
+	path := joinRoutePath(groupPrefix(sel.X, groupPrefixes), pattern)
+
+	handler, handlerX := e.extractHandlerName(call, 1)
 
 	return []routeInfo{{
 		method:    httpMethod,
 		path:      path,
-		framework: "gin",
+		framework: "chi",
 		handler:   handler,
+		handlerX:  handlerX,
 		line:      e.pos(call.Pos()),
 	}}
 }
 
-func (e *extractor) matchHandleFuncRoute(call *ast.CallExpr, sel *ast.SelectorExpr) []routeInfo {
+func (e *extractor) matchHandleFuncRoute(call *ast.CallExpr, sel *ast.SelectorExpr, groupPrefixes map[string]string) []routeInfo {
 	if len(call.Args) < 1 {
 		return nil
 	}
@@ -634,7 +954,7 @@ func (e *extractor) matchHandleFuncRoute(call *ast.CallExpr, sel *ast.SelectorEx
 	if !ok || pathLit.Kind != token.STRING {
 		return nil
 	}
-	path := strings.Trim(pathLit.Value, `"`)
+	path := joinRoutePath(groupPrefix(sel.X, groupPrefixes), strings.Trim(pathLit.Value, `"`))
 
 	// Determine framework: if receiver is "http" package selector, it's net/http.
 	// Otherwise, assume gorilla/mux or net/http (both use HandleFunc).
@@ -646,13 +966,14 @@ func (e *extractor) matchHandleFuncRoute(call *ast.CallExpr, sel *ast.SelectorEx
 		}
 	}
 
-	handler := e.extractHandlerName(call, 1)
+	handler, handlerX := e.extractHandlerName(call, 1)
 
 	return []routeInfo{{
 		method:    "ANY",
 		path:      path,
 		framework: framework,
 		handler:   handler,
+		handlerX:  handlerX,
 		line:      e.pos(call.Pos()),
 	}}
 }
@@ -681,7 +1002,7 @@ func (e *extractor) matchGorillaMethodsChain(call *ast.CallExpr, sel *ast.Select
 	if !ok || pathLit.Kind != token.STRING {
 		return nil
 	}
-	path := strings.Trim(pathLit.Value, `"`)
+	path := joinRoutePath(groupPrefix(innerSel.X, groupPrefixes), strings.Trim(pathLit.Value, `"`))
 
 	// Extract the HTTP method from Methods() args.
 	httpMethod := "ANY"
@@ -691,49 +1012,55 @@ func (e *extractor) matchGorillaMethodsChain(call *ast.CallExpr, sel *ast.Select
 		}
 	}
 
-	handler := e.extractHandlerName(innerCall, 1)
+	handler, handlerX := e.extractHandlerName(innerCall, 1)
 
 	return []routeInfo{{
 		method:    httpMethod,
 		path:      path,
 		framework: "gorilla/mux",
 		handler:   handler,
+		handlerX:  handlerX,
 		line:      e.pos(innerCall.Pos()),
 	}}
 }
 
-// extractHandlerName extracts the handler function/identifier name from the argIndex-th argument.
-func (e *extractor) extractHandlerName(call *ast.CallExpr, argIndex int) string {
+// extractHandlerName extracts the handler function/identifier name from the
+// argIndex-th argument, along with the argument itself.
+func (e *extractor) extractHandlerName(call *ast.CallExpr, argIndex int) (string, ast.Expr) {
 	if argIndex >= len(call.Args) {
-		return ""
+		return "", nil
 	}
 	arg := call.Args[argIndex]
 	switch h := arg.(type) {
 	case *ast.Ident:
-		return h.Name
+		return h.Name, h
 	case *ast.SelectorExpr:
-		return typeExprString(h)
+		return typeExprString(h), h
+	case *ast.FuncLit:
+		return "", h
 	default:
-		return ""
+		return "", nil
 	}
 }
 
-func (e *extractor) addRouteNode(r routeInfo, enclosingNodeID string) {
+func (e *extractor) addRouteNode(r routeInfo, enclosingNodeID string) *graph.Node {
 	endpointID := graph.NewNodeID(string(graph.NodeAPIEndpoint), e.filePath, r.method+":"+r.path)
-	e.nodes = append(e.nodes, &graph.Node{
+	endpoint := &graph.Node{
 		ID:       endpointID,
 		Type:     graph.NodeAPIEndpoint,
 		Name:     r.method + " " + r.path,
 		FilePath: e.filePath,
 		Line:     r.line,
 		Language: string(parser.LangGo),
+		Package:  e.file.Name.Name,
 		Properties: map[string]string{
 			"http_method": r.method,
 			"path":        r.path,
 			"framework":   r.framework,
 			"handler":     r.handler,
 		},
-	})
+	}
+	e.nodes = append(e.nodes, endpoint)
 
 	e.edges = append(e.edges, &graph.Edge{
 		ID:       edgeID(enclosingNodeID, endpointID, string(graph.EdgeExposes)),
@@ -741,6 +1068,74 @@ func (e *extractor) addRouteNode(r routeInfo, enclosingNodeID string) {
 		SourceID: enclosingNodeID,
 		TargetID: endpointID,
 	})
+	return endpoint
+}
+
+// linkRouteHandler adds a Calls edge from an endpoint or job to its handler: a
+// function or method in this file (including methods reached through the
+// registering method's receiver and its fields), or an imported package
+// function (an edge to the import's dependency node, with the callee named).
+// A bare handler name not declared in this file is stored in the endpoint's
+// unresolved_calls for the linker to resolve across the package.
+func (e *extractor) linkRouteHandler(endpoint *graph.Node, handler ast.Expr, recvParamName, recvTypeName string) {
+	switch h := handler.(type) {
+	case *ast.FuncLit:
+		if targetID := e.closureID(h); targetID != "" {
+			e.addHandlerEdge(endpoint.ID, targetID, "")
+		}
+	case *ast.Ident:
+		if targetID, ok := e.closureVars[h.Obj]; ok {
+			e.addHandlerEdge(endpoint.ID, targetID, h.Name)
+		} else if targetID, ok := e.funcNameMap[h.Name]; ok {
+			e.addHandlerEdge(endpoint.ID, targetID, "")
+		} else if !goBuiltins[h.Name] {
+			endpoint.Properties["unresolved_calls"] = h.Name
+		}
+	case *ast.SelectorExpr:
+		callee := h.Sel.Name
+		if x, ok := h.X.(*ast.Ident); ok {
+			// recv.handleX
+			if x.Name == recvParamName {
+				if targetID, ok := e.methodsByReceiver[recvTypeName][callee]; ok {
+					e.addHandlerEdge(endpoint.ID, targetID, recvTypeName+"."+callee)
+				}
+				return
+			}
+			// handlers.ListUsers
+			if depID, ok := e.importAliasMap[x.Name]; ok {
+				e.addHandlerEdge(endpoint.ID, depID, callee)
+				return
+			}
+		}
+		// recv.handlers.List
+		if fieldTypeStr, ok := e.resolveFieldChain(h.X, recvParamName, recvTypeName); ok {
+			typeName := extractTypeName(fieldTypeStr)
+			qualifiedCallee := typeName + "." + callee
+			if pkg := extractPackagePrefix(fieldTypeStr); pkg != "" {
+				if depID, ok := e.importAliasMap[pkg]; ok {
+					e.addHandlerEdge(endpoint.ID, depID, qualifiedCallee)
+				}
+				return
+			}
+			if targetID, ok := e.methodsByReceiver[typeName][callee]; ok {
+				e.addHandlerEdge(endpoint.ID, targetID, qualifiedCallee)
+			}
+		}
+	}
+}
+
+func (e *extractor) addHandlerEdge(endpointID, targetID, callee string) {
+	var props map[string]string
+	if callee != "" {
+		props = map[string]string{"callee": callee}
+	}
+	e.edges = append(e.edges, &graph.Edge{
+		ID:         edgeID(endpointID, targetID, string(graph.EdgeCalls)),
+		Type:       graph.EdgeCalls,
+		SourceID:   endpointID,
+		TargetID:   targetID,
+		Properties: props,
+	})
 }
 
 func (e *extractor) extractImplementsEdges() {
@@ -809,7 +1204,7 @@ func (e *extractor) extractHTTPClientCalls() {
 			continue
 		}
 
-		enclosingNodeID := e.enclosingFuncNodeID(fn)
+		fnNodeID := e.enclosingFuncNodeID(fn)
 
 		ast.Inspect(fn.Body, func(n ast.Node) bool {
 			call, ok := n.(*ast.CallExpr)
@@ -823,13 +1218,14 @@ func (e *extractor) extractHTTPClientCalls() {
 			}
 
 			methodName := sel.Sel.Name
+			enclosingNodeID := e.closureOwner(fnNodeID, call.Pos())
 
 			// Case 1: http.Get(url), http.Post(url, ...), http.Head(url), http.PostForm(url, ...)
 			if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == httpAlias {
 				if httpMethod, ok := goHTTPPackageFuncs[methodName]; ok {
 					path := e.extractStringArg(call, 0)
 					if path != "" {
-						e.addHTTPClientCallNode(httpMethod, path, "net/http", enclosingNodeID, e.pos(call.Pos()))
+						e.addHTTPClientCallNode(httpMethod, path, urlBaseExpr(call, 0), "net/http", enclosingNodeID, e.pos(call.Pos()))
 					}
 					return true
 				}
@@ -848,7 +1244,7 @@ func (e *extractor) extractHTTPClientCalls() {
 						httpMethod = "UNKNOWN"
 					}
 					if path != "" {
-						e.addHTTPClientCallNode(httpMethod, path, "net/http", enclosingNodeID, e.pos(call.Pos()))
+						e.addHTTPClientCallNode(httpMethod, path, urlBaseExpr(call, urlArgIdx), "net/http", enclosingNodeID, e.pos(call.Pos()))
 					}
 					return true
 				}
@@ -858,14 +1254,14 @@ func (e *extractor) extractHTTPClientCalls() {
 			if httpMethod, ok := goHTTPClientMethodsWithURL[methodName]; ok {
 				path := e.extractStringArg(call, 0)
 				if path != "" {
-					e.addHTTPClientCallNode(httpMethod, path, "net/http", enclosingNodeID, e.pos(call.Pos()))
+					e.addHTTPClientCallNode(httpMethod, path, urlBaseExpr(call, 0), "net/http", enclosingNodeID, e.pos(call.Pos()))
 				}
 				return true
 			}
 
 			// Case 4: client.Do(req) — method is on the request, mark as UNKNOWN
 			if methodName == "Do" {
-				e.addHTTPClientCallNode("UNKNOWN", "UNKNOWN", "net/http", enclosingNodeID, e.pos(call.Pos()))
+				e.addHTTPClientCallNode("UNKNOWN", "UNKNOWN", "", "net/http", enclosingNodeID, e.pos(call.Pos()))
 			}
 
 			return true
@@ -906,27 +1302,60 @@ func (e *extractor) extractConcatStringArg(expr *ast.BinaryExpr) string {
 		left = e.extractConcatStringArg(l)
 	}
 	if left == "" {
+		// A dynamic base followed by a path literal: baseURL + "/users".
+		if r, ok := expr.Y.(*ast.BasicLit); ok && r.Kind == token.STRING {
+			if right := strings.Trim(r.Value, `"`); strings.HasPrefix(right, "/") {
+				return "*" + right
+			}
+		}
 		return ""
 	}
 	return left + "*"
 }
 
-func (e *extractor) addHTTPClientCallNode(httpMethod, path, framework, enclosingNodeID string, line int) {
+// urlBaseExpr returns the source form of the dynamic base a URL argument
+// starts with, such as os.Getenv("API_URL") in os.Getenv("API_URL") +
+// "/users", or "" when it starts with a literal.
+func urlBaseExpr(call *ast.CallExpr, idx int) string {
+	if idx >= len(call.Args) {
+		return ""
+	}
+	left := call.Args[idx]
+	for {
+		bin, ok := left.(*ast.BinaryExpr)
+		if !ok {
+			break
+		}
+		left = bin.X
+	}
+	if _, ok := left.(*ast.BasicLit); ok || left == call.Args[idx] {
+		return ""
+	}
+	return types.ExprString(left)
+}
+
+// addHTTPClientCallNode records an api_call. base is the source form of the
+// URL's dynamic base, if any (see parser.APICallPropBase).
+func (e *extractor) addHTTPClientCallNode(httpMethod, path, base, framework, enclosingNodeID string, line int) {
 	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, "api_call:"+httpMethod+":"+path+":"+fmt.Sprintf("%d", line))
 
+	props := map[string]string{
+		"kind":        "api_call",
+		"http_method": httpMethod,
+		"path":        path,
+		"framework":   framework,
+	}
+	if base != "" && strings.HasPrefix(path, "*") {
+		props[parser.APICallPropBase] = base
+	}
 	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
-		Name:     httpMethod + " " + path,
-		FilePath: e.filePath,
-		Line:     line,
-		Language: string(parser.LangGo),
-		Properties: map[string]string{
-			"kind":        "api_call",
-			"http_method": httpMethod,
-			"path":        path,
-			"framework":   framework,
-		},
+		ID:         depID,
+		Type:       graph.NodeDependency,
+		Name:       httpMethod + " " + path,
+		FilePath:   e.filePath,
+		Line:       line,
+		Language:   string(parser.LangGo),
+		Properties: props,
 	})
 
 	e.edges = append(e.edges, &graph.Edge{
@@ -953,7 +1382,7 @@ func (e *extractor) buildCallMaps() {
 	// Build import alias map
 	for _, imp := range e.file.Imports {
 		path := strings.Trim(imp.Path.Value, `"`)
-		depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, path)
+		depID := e.importOf(imp).ID()
 
 		if imp.Name != nil {
 			// Explicit alias
@@ -967,7 +1396,7 @@ func (e *extractor) buildCallMaps() {
 
 	// Build function name map from already-extracted nodes
 	for _, n := range e.nodes {
-		if (n.Type == graph.NodeFunction || n.Type == graph.NodeTestFunction) && n.FilePath == e.filePath {
+		if (n.Type == graph.NodeFunction || n.Type == graph.NodeTestFunction) && n.FilePath == e.filePath && n.Properties["anonymous"] != "true" {
 			e.funcNameMap[n.Name] = n.ID
 		}
 	}
@@ -994,7 +1423,7 @@ func (e *extractor) enclosingFuncNodeID(fn *ast.FuncDecl) string {
 		return graph.NewNodeID(string(graph.NodeMethod), e.filePath, recvType+"."+fn.Name.Name)
 	}
 	nodeType := graph.NodeFunction
-	if e.isTestFile && isTestFuncName(fn.Name.Name) {
+	if e.isTestFile && (isTestFuncName(fn.Name.Name) || e.testPatterns.MatchesFunction(fn.Name.Name)) {
 		nodeType = graph.NodeTestFunction
 	}
 	return graph.NewNodeID(string(nodeType), e.filePath, fn.Name.Name)
@@ -1011,23 +1440,18 @@ func (e *extractor) extractFunctionCalls() {
 			continue
 		}
 
-		enclosingNodeID := e.enclosingFuncNodeID(fn)
-		var unresolvedCalls []string // track unresolved same-package calls
+		fnNodeID := e.enclosingFuncNodeID(fn)
+		unresolvedCalls := make(map[string][]string) // caller ID → unresolved same-package calls
 
 		// Determine receiver parameter name and type for chained field access resolution.
-		var recvParamName, recvTypeName_ string
-		if fn.Recv != nil && len(fn.Recv.List) > 0 {
-			recvTypeName_ = receiverTypeName(fn.Recv.List[0].Type)
-			if len(fn.Recv.List[0].Names) > 0 {
-				recvParamName = fn.Recv.List[0].Names[0].Name
-			}
-		}
+		recvParamName, recvTypeName_ := receiverParam(fn)
 
 		ast.Inspect(fn.Body, func(n ast.Node) bool {
 			call, ok := n.(*ast.CallExpr)
 			if !ok {
 				return true
 			}
+			enclosingNodeID := e.closureOwner(fnNodeID, call.Pos())
 
 			switch funExpr := call.Fun.(type) {
 			case *ast.SelectorExpr:
@@ -1095,7 +1519,15 @@ func (e *extractor) extractFunctionCalls() {
 				if goBuiltins[name] {
 					return true
 				}
-				if targetID, ok := e.funcNameMap[name]; ok {
+				if targetID, ok := e.closureVars[funExpr.Obj]; ok {
+					e.edges = append(e.edges, &graph.Edge{
+						ID:         edgeID(enclosingNodeID, targetID, string(graph.EdgeCalls)),
+						Type:       graph.EdgeCalls,
+						SourceID:   enclosingNodeID,
+						TargetID:   targetID,
+						Properties: map[string]string{"callee": name},
+					})
+				} else if targetID, ok := e.funcNameMap[name]; ok {
 					if targetID != enclosingNodeID { // skip self-recursion noise
 						e.edges = append(e.edges, &graph.Edge{
 							ID:       edgeID(enclosingNodeID, targetID, string(graph.EdgeCalls)),
@@ -1107,28 +1539,38 @@ func (e *extractor) extractFunctionCalls() {
 				} else {
 					// Unresolved: likely a cross-file same-package call.
 					// Store for linker resolution.
-					unresolvedCalls = append(unresolvedCalls, name)
+					unresolvedCalls[enclosingNodeID] = append(unresolvedCalls[enclosingNodeID], name)
 				}
 			}
 
 			return true
 		})
 
-		// Store unresolved calls on the enclosing node for cross-file linker resolution.
-		if len(unresolvedCalls) > 0 {
-			for _, n := range e.nodes {
-				if n.ID == enclosingNodeID {
-					if n.Properties == nil {
-						n.Properties = make(map[string]string)
-					}
-					n.Properties["unresolved_calls"] = strings.Join(dedupStrings(unresolvedCalls), ",")
-					break
+		// Store unresolved calls on the enclosing nodes for cross-file linker resolution.
+		for _, n := range e.nodes {
+			if calls := unresolvedCalls[n.ID]; len(calls) > 0 {
+				if n.Properties == nil {
+					n.Properties = make(map[string]string)
 				}
+				n.Properties["unresolved_calls"] = strings.Join(dedupStrings(calls), ",")
 			}
 		}
 	}
 }
 
+// receiverParam returns the receiver parameter name and type name of a
+// method, or empty strings for a function.
+func receiverParam(fn *ast.FuncDecl) (name, typeName string) {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return "", ""
+	}
+	typeName = receiverTypeName(fn.Recv.List[0].Type)
+	if len(fn.Recv.List[0].Names) > 0 {
+		name = fn.Recv.List[0].Names[0].Name
+	}
+	return name, typeName
+}
+
 // dedupStrings returns a deduplicated copy of ss preserving order.
 func dedupStrings(ss []string) []string {
 	seen := make(map[string]bool, len(ss))
@@ -1256,19 +1698,27 @@ func funcSignature(fn *ast.FuncDecl) string {
 		b.WriteString(") ")
 	}
 	b.WriteString(fn.Name.Name)
+	b.WriteString(funcTypeSignature(fn.Type))
+	return b.String()
+}
+
+// funcTypeSignature returns the parameters and results of a function type,
+// as in "(a int) (string, error)".
+func funcTypeSignature(ft *ast.FuncType) string {
+	var b strings.Builder
 	b.WriteString("(")
-	if fn.Type.Params != nil {
-		writeFieldList(&b, fn.Type.Params)
+	if ft.Params != nil {
+		writeFieldList(&b, ft.Params)
 	}
 	b.WriteString(")")
-	if fn.Type.Results != nil && len(fn.Type.Results.List) > 0 {
+	if ft.Results != nil && len(ft.Results.List) > 0 {
 		b.WriteString(" ")
-		if len(fn.Type.Results.List) > 1 || len(fn.Type.Results.List[0].Names) > 0 {
+		if len(ft.Results.List) > 1 || len(ft.Results.List[0].Names) > 0 {
 			b.WriteString("(")
-			writeFieldList(&b, fn.Type.Results)
+			writeFieldList(&b, ft.Results)
 			b.WriteString(")")
 		} else {
-			writeFieldList(&b, fn.Type.Results)
+			writeFieldList(&b, ft.Results)
 		}
 	}
 	return b.String()
@@ -1293,7 +1743,7 @@ func writeFieldList(b *strings.Builder, fl *ast.FieldList) {
 }
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
 
 func implementsAll(structMethods, ifaceMethods map[string]bool) bool {
@@ -3,7 +3,9 @@ package golang
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 
@@ -185,11 +187,33 @@ func TestParseFile(t *testing.T) {
 func TestParseFileSyntaxError(t *testing.T) {
 	p := NewParser()
 	badSource := []byte(`package bad
+
+func Good() int { return 1 }
+
 func broken( {
 `)
-	_, err := p.ParseFile("bad.go", badSource)
-	if err == nil {
-		t.Error("expected error for syntax-error source, got nil")
+	result, err := p.ParseFile("bad.go", badSource)
+	if err != nil {
+		t.Fatalf("ParseFile: %v, want partial result", err)
+	}
+	if len(result.Diagnostics) == 0 {
+		t.Fatal("expected diagnostics for syntax-error source")
+	}
+	if d := result.Diagnostics[0]; d.Line != 5 || d.Message == "" {
+		t.Errorf("diagnostic = %+v, want line 5 with a message", d)
+	}
+	found := false
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeFunction && n.Name == "Good" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("function Good not extracted from partial AST")
+	}
+
+	if _, err := p.ParseFile("notgo.go", []byte("this is not go")); err == nil {
+		t.Error("expected error when the package clause is unreadable")
 	}
 }
 
@@ -441,6 +465,130 @@ func SetupGrouped(router *gin.Engine) {
 	}
 }
 
+func TestParseRouteMiddleware(t *testing.T) {
+	content := []byte(`package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-chi/chi/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func health(c *gin.Context)    {}
+func listUsers(c *gin.Context) {}
+func audit(c *gin.Context)     {}
+func stats(c echo.Context) error { return nil }
+func me(w http.ResponseWriter, r *http.Request) {}
+
+func setup(router *gin.Engine, e *echo.Echo) {
+	router.Use(gin.Logger())
+	router.GET("/health", health)
+
+	api := router.Group("/api", AuthRequired())
+	api.GET("/users", listUsers)
+	api.DELETE("/users/:id", RequireRole("admin"), listUsers)
+
+	admin := api.Group("/admin")
+	admin.Use(adminOnly)
+	admin.GET("/audit", audit)
+
+	e.GET("/stats", stats, middleware.JWT(key))
+}
+
+func chiRoutes(r chi.Router) {
+	r.Route("/me", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(tokenAuth))
+		r.Get("/", me)
+	})
+}
+`)
+
+	result, err := NewParser().ParseFile("routes.go", content)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	type route struct{ middleware, handler string }
+	got := make(map[string]route)
+	for _, ep := range filterNodesByType(result.Nodes, graph.NodeAPIEndpoint) {
+		got[ep.Name] = route{ep.Properties[parser.PropMiddleware], ep.Properties["handler"]}
+	}
+	want := map[string]route{
+		"GET /health":           {"gin.Logger", "health"},
+		"GET /api/users":        {"gin.Logger,AuthRequired", "listUsers"},
+		"DELETE /api/users/:id": {"gin.Logger,AuthRequired,RequireRole", "listUsers"},
+		"GET /api/admin/audit":  {"gin.Logger,AuthRequired,adminOnly", "audit"},
+		"GET /stats":            {"middleware.JWT", "stats"},
+		"GET /me/":              {"jwtauth.Verifier", "me"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("endpoint %q = %+v, want %+v", name, got[name], w)
+		}
+	}
+}
+
+func TestParseChiSubRouters(t *testing.T) {
+	content := []byte(`package main
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/mux"
+)
+
+func listUsers(w http.ResponseWriter, r *http.Request) {}
+func getUser(w http.ResponseWriter, r *http.Request)   {}
+func health(w http.ResponseWriter, r *http.Request)    {}
+func stats(w http.ResponseWriter, r *http.Request)     {}
+
+func routes(r chi.Router, cfg Config) {
+	r.Get("/health", health)
+	r.Route("/api", func(r chi.Router) {
+		r.Route("/users", func(r chi.Router) {
+			r.Get("/", listUsers)
+			r.Get("/{id}", getUser)
+		})
+	})
+	cfg.Get("name", "default")
+}
+
+func adminRoutes(r *mux.Router) {
+	admin := r.PathPrefix("/admin").Subrouter()
+	admin.HandleFunc("/stats", stats).Methods("GET")
+}
+`)
+
+	result, err := NewParser().ParseFile("routes.go", content)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, ep := range filterNodesByType(result.Nodes, graph.NodeAPIEndpoint) {
+		got[ep.Name] = ep.Properties["framework"]
+	}
+	want := map[string]string{
+		"GET /health":         "chi",
+		"GET /api/users/":     "chi",
+		"GET /api/users/{id}": "chi",
+		"GET /admin/stats":    "gorilla/mux",
+	}
+	if len(got) != len(want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+	for name, fw := range want {
+		if got[name] != fw {
+			t.Errorf("endpoint %q framework = %q, want %q", name, got[name], fw)
+		}
+	}
+}
+
 func TestParseGorillaRoutes(t *testing.T) {
 	content := []byte(`package main
 
@@ -532,6 +680,100 @@ func Setup(r *gin.Engine) {
 	}
 }
 
+func TestRouteHandlerEdges(t *testing.T) {
+	content := []byte(`package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/mux"
+
+	"example.com/shop/handlers"
+	"example.com/shop/orders"
+)
+
+type Server struct {
+	orders *orders.Service
+	users  *UserHandler
+}
+
+type UserHandler struct{}
+
+func (u *UserHandler) Get(c *gin.Context) {}
+
+func health(c *gin.Context) {}
+
+func (s *Server) list(c *gin.Context) {}
+
+func (s *Server) Routes(r *gin.Engine) {
+	r.GET("/health", health)
+	r.GET("/items", s.list)
+	r.GET("/users/:id", s.users.Get)
+	r.GET("/orders", s.orders.List)
+	r.POST("/login", handlers.Login)
+	r.GET("/metrics", metricsHandler)
+}
+
+func Legacy(m *mux.Router) {
+	m.HandleFunc("/ping", health).Methods("GET")
+	http.HandleFunc("/static", http.NotFound)
+}
+`)
+
+	p := NewParser()
+	result, err := p.ParseFile("api/routes.go", content)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nameByID := make(map[string]string, len(result.Nodes))
+	for _, n := range result.Nodes {
+		nameByID[n.ID] = n.Name
+	}
+	handlers := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type != graph.EdgeCalls {
+			continue
+		}
+		if ep := nameByID[e.SourceID]; strings.Contains(ep, " /") {
+			handlers[ep] = nameByID[e.TargetID]
+			if callee := e.Properties["callee"]; callee != "" {
+				handlers[ep] += " (" + callee + ")"
+			}
+		}
+	}
+
+	tests := map[string]string{
+		"GET /health":    "health",
+		"GET /items":     "list (Server.list)",
+		"GET /users/:id": "Get (UserHandler.Get)",
+		"GET /orders":    "example.com/shop/orders (Service.List)",
+		"POST /login":    "example.com/shop/handlers (Login)",
+		"GET /ping":      "health",
+		"ANY /static":    "net/http (NotFound)",
+		"GET /metrics":   "",
+	}
+	for ep, want := range tests {
+		if got := handlers[ep]; got != want {
+			t.Errorf("%s handler = %q, want %q", ep, got, want)
+		}
+	}
+
+	for _, n := range filterNodesByType(result.Nodes, graph.NodeAPIEndpoint) {
+		want := ""
+		if n.Name == "GET /metrics" {
+			want = "metricsHandler"
+		}
+		if got := n.Properties["unresolved_calls"]; got != want {
+			t.Errorf("%s unresolved_calls = %q, want %q", n.Name, got, want)
+		}
+		if n.Package != "api" {
+			t.Errorf("%s package = %q, want api", n.Name, n.Package)
+		}
+	}
+}
+
 func TestExtractHTTPClientCalls(t *testing.T) {
 	content, err := os.ReadFile("testdata/http_client.go")
 	if err != nil {
@@ -633,7 +875,7 @@ func TestExtractFunctionCalls(t *testing.T) {
 		return graph.NewNodeID(string(graph.NodeMethod), "testdata/calls.go", recv+"."+name)
 	}
 	depID := func(path string) string {
-		return graph.NewNodeID(string(graph.NodeDependency), "testdata/calls.go", path)
+		return graph.NewDependencyID("go", path)
 	}
 
 	// Check same-file calls
@@ -851,7 +1093,7 @@ func TestStructFieldTypeResolution(t *testing.T) {
 		return graph.NewNodeID(string(graph.NodeMethod), "testdata/field_calls.go", recv+"."+name)
 	}
 	depID := func(path string) string {
-		return graph.NewNodeID(string(graph.NodeDependency), "testdata/field_calls.go", path)
+		return graph.NewDependencyID("go", path)
 	}
 
 	hasEdge := func(src, tgt, callee string) bool {
@@ -1030,3 +1272,501 @@ func caller() {
 	}
 	t.Error("caller function not found")
 }
+
+func TestTestPatternsOverride(t *testing.T) {
+	src := []byte(`package store
+
+import "testing"
+
+func TestRoundTrip(t *testing.T) {}
+
+func ShouldPersistUsers(t *testing.T) {}
+
+func helper() {}
+`)
+	p := NewParser()
+	p.SetTestPatterns(&parser.TestPatterns{
+		FileGlobs:        []string{"*_it.go"},
+		FunctionPatterns: []string{"Should*"},
+	})
+
+	result, err := p.ParseFile("store/store_it.go", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	types := make(map[string]graph.NodeType)
+	for _, n := range result.Nodes {
+		types[n.Name] = n.Type
+	}
+	if types["store/store_it.go"] != graph.NodeTestFile {
+		t.Errorf("file type = %s, want %s", types["store/store_it.go"], graph.NodeTestFile)
+	}
+	for _, name := range []string{"TestRoundTrip", "ShouldPersistUsers"} {
+		if types[name] != graph.NodeTestFunction {
+			t.Errorf("%s type = %s, want %s", name, types[name], graph.NodeTestFunction)
+		}
+	}
+	if types["helper"] != graph.NodeFunction {
+		t.Errorf("helper type = %s, want %s", types["helper"], graph.NodeFunction)
+	}
+}
+
+func TestHTTPClientCallBase(t *testing.T) {
+	content := []byte(`package client
+
+import (
+	"net/http"
+	"os"
+)
+
+func Charge(id string) {
+	http.Get(os.Getenv("PAYMENTS_URL") + "/charges/" + id)
+	http.NewRequest("POST", baseURL+"/refunds", nil)
+	http.Get("/api/local")
+}
+`)
+	result, err := NewParser().ParseFile("client/charge.go", content)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	want := map[string]string{
+		"*/charges/*": `os.Getenv("PAYMENTS_URL")`,
+		"*/refunds":   "baseURL",
+		"/api/local":  "",
+	}
+	got := make(map[string]string)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeDependency && n.Properties["kind"] == "api_call" {
+			got[n.Properties["path"]] = n.Properties[parser.APICallPropBase]
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("api_call path -> base = %v, want %v", got, want)
+	}
+	for path, base := range want {
+		if got[path] != base {
+			t.Errorf("api_call %s base = %q, want %q", path, got[path], base)
+		}
+	}
+}
+
+func TestScheduledJobs(t *testing.T) {
+	content := []byte(`package jobs
+
+import (
+	"github.com/go-co-op/gocron"
+	"github.com/robfig/cron/v3"
+)
+
+func cleanup() {}
+func poll()    {}
+
+func Start(c *cron.Cron, s *gocron.Scheduler, s2 gocron.Scheduler) {
+	c.AddFunc("0 3 * * *", cleanup)
+	c.AddFunc("@every 1h", rotate)
+	s.Every(5).Minutes().Do(poll)
+	s.Every(1).Day().At("10:30").Do(func() {})
+	s2.NewJob(gocron.CronJob("*/10 * * * *", false), gocron.NewTask(cleanup))
+	m.AddFunc("key", cleanup)
+}
+`)
+	result, err := NewParser().ParseFile("jobs/start.go", content)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	byID := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		byID[n.ID] = n
+	}
+	calls := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls {
+			calls[e.SourceID] = byID[e.TargetID].Name
+		}
+	}
+
+	type job struct{ name, framework, calls, unresolved string }
+	want := map[string]job{ // by schedule
+		"0 3 * * *":            {"cleanup", "robfig/cron", "cleanup", ""},
+		"@every 1h":            {"rotate", "robfig/cron", "", "rotate"},
+		"every 5 minutes":      {"poll", "gocron", "poll", ""},
+		"every 1 day at 10:30": {"cron every 1 day at 10:30", "gocron", "anonymous@15:34", ""},
+		"*/10 * * * *":         {"cleanup", "gocron", "cleanup", ""},
+	}
+	got := make(map[string]job)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeJob {
+			got[n.Properties[parser.JobPropSchedule]] = job{n.Name, n.Properties[parser.JobPropFramework],
+				calls[n.ID], n.Properties["unresolved_calls"]}
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d jobs %v, want %d", len(got), got, len(want))
+	}
+	for schedule, w := range want {
+		if got[schedule] != w {
+			t.Errorf("Job %q = %+v, want %+v", schedule, got[schedule], w)
+		}
+	}
+}
+
+func TestErrorSites(t *testing.T) {
+	source := `package pay
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type DeclinedError struct{ Code string }
+
+func (e *DeclinedError) Error() string { return e.Code }
+
+func Charge(id string) (int, error) {
+	if id == "" {
+		return 0, &DeclinedError{Code: "empty"}
+	}
+	if err := load(id); err != nil {
+		return 0, fmt.Errorf("load %s: %w", id, ErrNotFound)
+	}
+	retry := func() error { return errors.New("ignored") }
+	_ = retry
+	return 0, sql.ErrNoRows
+}
+
+func mustCharge(id string) {
+	panic(ErrNotFound)
+}
+`
+	result, err := NewParser().ParseFile("pay/gateway.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	wantThrows := map[string]string{"Charge": "DeclinedError,ErrNotFound,sql.ErrNoRows", "mustCharge": "ErrNotFound"}
+	wantErrorTypes := map[string]bool{"ErrNotFound": true, "DeclinedError": true}
+	gotThrows := make(map[string]string)
+	gotErrorTypes := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if throws := n.Properties[parser.PropThrows]; throws != "" {
+			gotThrows[n.Name] = throws
+		}
+		if n.Properties[parser.PropErrorType] == "true" {
+			gotErrorTypes[n.Name] = true
+		}
+	}
+	if !reflect.DeepEqual(gotThrows, wantThrows) {
+		t.Errorf("throws = %v, want %v", gotThrows, wantThrows)
+	}
+	if !reflect.DeepEqual(gotErrorTypes, wantErrorTypes) {
+		t.Errorf("error types = %v, want %v", gotErrorTypes, wantErrorTypes)
+	}
+}
+
+func TestParseGormRelations(t *testing.T) {
+	source := `package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type User struct {
+	gorm.Model
+	Name      string
+	CompanyID uint
+	Company   Company
+	Profile   *Profile
+	Orders    []Order
+	Languages []*Language ` + "`gorm:\"many2many:user_languages;\"`" + `
+	Manager   *User ` + "`gorm:\"foreignKey:ManagerRef\"`" + `
+	ManagerRef *uint
+	Audit     Audit ` + "`gorm:\"-\"`" + `
+	Tags      []string
+	Birthday  time.Time
+}
+
+type Order struct {
+	ID     uint   ` + "`gorm:\"primaryKey\"`" + `
+	UserID uint
+	Total  int
+}
+
+type Config struct {
+	Owner User
+}
+`
+	result, err := NewParser().ParseFile("models/user.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	tests := []struct {
+		name, orm, relations string
+	}{
+		{"User", "gorm", "Company:Company:many_to_one,Profile:Profile:one_to_one,Orders:Order:one_to_many," +
+			"Languages:Language:many_to_many:user_languages,Manager:User:many_to_one"},
+		{"Order", "gorm", ""},
+		{"Config", "", ""},
+	}
+	for _, tt := range tests {
+		var found *graph.Node
+		for _, n := range result.Nodes {
+			if n.Type == graph.NodeStruct && n.Name == tt.name {
+				found = n
+			}
+		}
+		if found == nil {
+			t.Errorf("struct %s not found", tt.name)
+			continue
+		}
+		if got := found.Properties[parser.PropORM]; got != tt.orm {
+			t.Errorf("%s orm = %q, want %q", tt.name, got, tt.orm)
+		}
+		if got := found.Properties[parser.PropModelRelations]; got != tt.relations {
+			t.Errorf("%s relations = %q, want %q", tt.name, got, tt.relations)
+		}
+	}
+}
+
+func TestParseSensitiveStructTags(t *testing.T) {
+	source := "package users\n\n" +
+		"type User struct {\n" +
+		"\tID    int\n" +
+		"\tEmail string `json:\"email\" pii:\"true\"`\n" +
+		"\tSSN   string `classification:\"phi\"`\n" +
+		"\tCard  string `sensitive:\"pci\"`\n" +
+		"\tNote  string `sensitive:\"true\"`\n" +
+		"\tName  string `pii:\"false\"`\n" +
+		"}\n\n" +
+		"type Plain struct {\n\tID int `json:\"id\"`\n}\n"
+	result, err := NewParser().ParseFile("users/user.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	want := map[string]string{
+		"User":  "Card:pci,Email:pii,Note:sensitive,SSN:phi",
+		"Plain": "",
+	}
+	for _, n := range filterNodesByType(result.Nodes, graph.NodeStruct) {
+		w, ok := want[n.Name]
+		if !ok {
+			continue
+		}
+		if got := n.Properties[parser.PropTaggedFields]; got != w {
+			t.Errorf("%s tagged_fields = %q, want %q", n.Name, got, w)
+		}
+		delete(want, n.Name)
+	}
+	for name := range want {
+		t.Errorf("struct %s not found", name)
+	}
+}
+
+func TestParseDIProviders(t *testing.T) {
+	source := `package app
+
+import (
+	"github.com/google/wire"
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+)
+
+var StoreSet = wire.NewSet(
+	NewStore,
+	wire.Bind(new(Repo), new(*Store)),
+)
+
+func Module() fx.Option {
+	return fx.Module("users",
+		fx.Provide(fx.Annotate(NewService, fx.ResultTags(` + "`name:\"svc\"`" + `))),
+		fx.Provide(func(s *Service) *Handler { return nil }),
+		fx.Invoke(Register),
+	)
+}
+
+func digContainer() {
+	c := dig.New()
+	c.Provide(config.Load)
+	c.Invoke(Register)
+}
+`
+	result, err := NewParser().ParseFile("app/wiring.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, n := range filterNodesByType(result.Nodes, graph.NodeDependency) {
+		if n.Properties["kind"] != "di_provider" {
+			continue
+		}
+		p := n.Properties
+		got[p["container"]+" "+p["role"]+" "+n.Name] = p["set"]
+	}
+	want := map[string]string{
+		"wire provide NewStore":                  "StoreSet",
+		"wire bind Repo => Store":                "StoreSet",
+		"fx provide NewService":                  "users",
+		"fx provide func(s *Service) (*Handler)": "users",
+		"fx invoke Register":                     "users",
+		"dig provide config.Load":                "",
+		"dig invoke Register":                    "",
+	}
+	for key, set := range want {
+		gotSet, ok := got[key]
+		if !ok {
+			t.Errorf("missing registration %q; got %v", key, got)
+			continue
+		}
+		if gotSet != set {
+			t.Errorf("%s set = %q, want %q", key, gotSet, set)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d registrations, want %d: %v", len(got), len(want), got)
+	}
+}
+
+func TestReceiverContains(t *testing.T) {
+	source := `package store
+
+func (s *Store) Get(id string) string { return id }
+
+type Store struct{}
+
+type Set[T comparable] map[T]struct{}
+
+func (s Set[T]) Add(v T) { s[v] = struct{}{} }
+
+func (c *Cache) Flush() {} // Cache is declared in another file
+`
+	result, err := NewParser().ParseFile("store/store.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	names := make(map[string]string)
+	for _, n := range result.Nodes {
+		names[n.ID] = n.Name
+	}
+	var got []string
+	for _, e := range result.Edges {
+		if e.Type != graph.EdgeContains {
+			continue
+		}
+		if from := names[e.SourceID]; from == "Store" || from == "Set" {
+			got = append(got, from+"."+names[e.TargetID])
+		}
+	}
+	want := []string{"Store.Get", "Set.Add"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("type Contains edges = %v, want %v", got, want)
+	}
+}
+
+func TestClosures(t *testing.T) {
+	source := `package api
+
+import "net/http"
+
+func Register(mux *http.ServeMux) {
+	validate := func(r *http.Request) bool {
+		return check(r)
+	}
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		if validate(r) {
+			http.Get("http://users.internal/list")
+		}
+	})
+	go func() {
+		warm()
+		http.Get("http://cache.internal/warm")
+		done()
+	}()
+	defer func() { flush() }()
+}
+
+func check(r *http.Request) bool { return true }
+func warm()                      {}
+func done()                      {}
+func flush()                     {}
+`
+	result, err := NewParser().ParseFile("api/routes.go", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	names := make(map[string]string)
+	closures := make(map[string]string) // name -> enclosing
+	for _, n := range result.Nodes {
+		names[n.ID] = n.Name
+		if n.Properties["anonymous"] == "true" {
+			closures[n.Name] = n.Properties["enclosing"]
+		}
+	}
+	wantClosures := map[string]string{"validate": "Register", "anonymous@9:27": "Register", "anonymous@14:5": "Register"}
+	if !reflect.DeepEqual(closures, wantClosures) {
+		t.Errorf("closures = %v, want %v", closures, wantClosures)
+	}
+
+	var got []string
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls {
+			got = append(got, names[e.SourceID]+" -> "+names[e.TargetID])
+		}
+	}
+	for _, want := range []string{
+		"validate -> check",
+		"ANY /users -> anonymous@9:27",
+		"anonymous@9:27 -> validate",
+		"anonymous@9:27 -> GET http://users.internal/list",
+		"Register -> anonymous@14:5",
+		"anonymous@14:5 -> warm",
+		"anonymous@14:5 -> GET http://cache.internal/warm",
+		"Register -> flush", // too small to get a node
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("missing edge %q; got %v", want, got)
+		}
+	}
+}
+
+func TestBuildConstraint(t *testing.T) {
+	tests := []struct {
+		file   string
+		header string
+		want   string
+	}{
+		{"fs/open.go", "", ""},
+		{"fs/open_linux.go", "", "linux"},
+		{"fs/open_windows_amd64.go", "", "windows && amd64"},
+		{"fs/open_arm64_test.go", "", "arm64"},
+		{"fs/linux.go", "", ""}, // a name suffix needs an underscore
+		{"fs/open.go", "//go:build darwin || freebsd\n\n", "darwin || freebsd"},
+		{"fs/open_unix.go", "//go:build !plan9\n\n", "!plan9"}, // unix is not a GOOS
+		{"fs/open_linux.go", "//go:build cgo\n\n", "cgo && linux"},
+		{"fs/open.go", "// +build linux darwin\n// +build amd64\n\n", "(linux || darwin) && amd64"},
+		{"fs/open.go", "//go:build linux\n// +build windows\n\n", "linux"}, // //go:build wins
+		{"fs/open.go", "// Package fs opens files.\npackage fs\n\n//go:build linux\n", ""},
+	}
+	for _, tt := range tests {
+		source := tt.header
+		if !strings.Contains(source, "package fs") {
+			source += "package fs\n"
+		}
+		source += "\nfunc Open() {}\n"
+		result, err := NewParser().ParseFile(tt.file, []byte(source))
+		if err != nil {
+			t.Fatalf("%s: ParseFile returned error: %v", tt.file, err)
+		}
+		for _, n := range result.Nodes {
+			if got := n.Properties[graph.PropBuildConstraint]; got != tt.want {
+				t.Errorf("%s %q: %s %s build_constraint = %q, want %q", tt.file, tt.header, n.Type, n.Name, got, tt.want)
+			}
+		}
+	}
+}
@@ -420,5 +420,5 @@ func getAttr(n *html.Node, key string) string {
 }
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"strconv"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Import is a module, package or namespace imported by a source file.
+//
+// Package imports are shared: every file importing Name in Language points
+// at the one Dependency node graph.NewDependencyID(Language, Name), which has
+// no file path or line, and the importing file's line goes on its Imports
+// edge. Local imports name a path relative to the importing file ("./util",
+// ".models", require_relative) and so get a node of their own, scoped to
+// FilePath as before.
+type Import struct {
+	Language Language
+	// Kind is the node's kind property; empty means "import".
+	Kind     string
+	Name     string
+	FilePath string
+	Line     int
+	Local    bool
+	// Properties are set on the Imports edge, such as the module system.
+	Properties map[string]string
+}
+
+// ID returns the ID of the import's Dependency node.
+func (i Import) ID() string {
+	if i.Local {
+		return graph.NewNodeID(string(graph.NodeDependency), i.FilePath, i.Name)
+	}
+	return graph.NewDependencyID(string(i.Language), i.Name)
+}
+
+// Link returns the import's Dependency node and the Imports edge to it from
+// sourceID, the importing file's module, package or file node.
+func (i Import) Link(sourceID string) (*graph.Node, *graph.Edge) {
+	kind := i.Kind
+	if kind == "" {
+		kind = "import"
+	}
+	node := &graph.Node{
+		ID:         i.ID(),
+		Type:       graph.NodeDependency,
+		Name:       i.Name,
+		Language:   string(i.Language),
+		Properties: map[string]string{"kind": kind},
+	}
+	if i.Local {
+		node.FilePath, node.Line = i.FilePath, i.Line
+	}
+	props := map[string]string{"line": strconv.Itoa(i.Line)}
+	for k, v := range i.Properties {
+		props[k] = v
+	}
+	edge := &graph.Edge{
+		ID:         graph.NewEdgeID(graph.EdgeImports, sourceID, node.ID),
+		Type:       graph.EdgeImports,
+		SourceID:   sourceID,
+		TargetID:   node.ID,
+		Properties: props,
+	}
+	return node, edge
+}
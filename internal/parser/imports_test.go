@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestImportLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		imp      Import
+		wantID   string
+		wantFile string
+		wantKind string
+	}{
+		{
+			name:     "package import is shared",
+			imp:      Import{Language: LangPython, Name: "requests", FilePath: "app/api.py", Line: 3},
+			wantID:   graph.NewDependencyID("python", "requests"),
+			wantKind: "import",
+		},
+		{
+			name:     "local import is scoped to its file",
+			imp:      Import{Language: LangPython, Name: ".models", FilePath: "app/api.py", Line: 4, Local: true},
+			wantID:   graph.NewNodeID(string(graph.NodeDependency), "app/api.py", ".models"),
+			wantFile: "app/api.py",
+			wantKind: "import",
+		},
+		{
+			name:     "kind",
+			imp:      Import{Language: LangRust, Kind: "use", Name: "serde::Serialize", FilePath: "src/lib.rs", Line: 1},
+			wantID:   graph.NewDependencyID("rust", "serde::Serialize"),
+			wantKind: "use",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, edge := tt.imp.Link("module")
+			if node.ID != tt.wantID || node.FilePath != tt.wantFile || node.Properties["kind"] != tt.wantKind {
+				t.Errorf("node = %s file %q kind %q, want %s file %q kind %q",
+					node.ID, node.FilePath, node.Properties["kind"], tt.wantID, tt.wantFile, tt.wantKind)
+			}
+			if edge.Type != graph.EdgeImports || edge.SourceID != "module" || edge.TargetID != node.ID {
+				t.Errorf("edge = %s %s -> %s, want Imports module -> %s", edge.Type, edge.SourceID, edge.TargetID, node.ID)
+			}
+			if edge.Properties["line"] == "0" || edge.Properties["line"] == "" {
+				t.Errorf("edge line = %q, want the import's line", edge.Properties["line"])
+			}
+		})
+	}
+
+	// Two files importing the same package share its node.
+	a, _ := Import{Language: LangGo, Name: "fmt", FilePath: "a.go"}.Link("pkg-a")
+	b, _ := Import{Language: LangGo, Name: "fmt", FilePath: "b.go"}.Link("pkg-b")
+	if a.ID != b.ID {
+		t.Errorf("fmt imported by two files got IDs %s and %s, want one shared node", a.ID, b.ID)
+	}
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/issues"
+)
+
+// commentRe matches the rest of a line after a comment marker (//, /*, #,
+// --, <!--, ; or a "*" block comment continuation).
+var commentRe = regexp.MustCompile(`(?m)(?:(?:^|[^\w:/"'])(?://+|/\*+|#+|--|<!--|;+)|^[ \t]*\*+)(?P<text>[^\n]*)`)
+
+// ExtractIssueReferences scans comments for issue references (Jira keys,
+// GitHub "#123" and "owner/repo#123", tracker URLs) and adds an Issue node
+// per referenced issue, with a References edge (source=comment) from each
+// enclosing function or method, or from the file node. When the reference
+// is in a TODO, FIXME, HACK or XXX comment (see ExtractAnnotations, which
+// must run first), the edge's annotation property holds its kind, marking
+// the code as blocked by the issue.
+func ExtractIssueReferences(result *ParseResult, content []byte) *ParseResult {
+	if result.Language == LangMarkdown {
+		return result
+	}
+
+	fileID := ""
+	var callables []*graph.Node
+	annotations := make(map[int]string) // line -> annotation kind
+	for _, n := range result.Nodes {
+		switch n.Type {
+		case graph.NodeFile, graph.NodeTestFile:
+			if fileID == "" {
+				fileID = n.ID
+			}
+		case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
+			if n.EndLine >= n.Line && n.Line > 0 {
+				callables = append(callables, n)
+			}
+		case graph.NodeAnnotation:
+			annotations[n.Line] = n.Properties["kind"]
+		}
+	}
+	if fileID == "" {
+		return result
+	}
+
+	textIdx := commentRe.SubexpIndex("text")
+	nodes := make(map[string]bool)
+	edges := make(map[string]*graph.Edge)
+	for _, m := range commentRe.FindAllSubmatchIndex(content, -1) {
+		text := content[m[2*textIdx]:m[2*textIdx+1]]
+		refs := issues.Refs(string(text))
+		if len(refs) == 0 {
+			continue
+		}
+		// A marker after an unclosed double quote is inside a string literal.
+		lineStart := bytes.LastIndexByte(content[:m[2*textIdx]], '\n') + 1
+		if bytes.Count(content[lineStart:m[2*textIdx]], []byte(`"`))%2 == 1 {
+			continue
+		}
+		line := bytes.Count(content[:m[2*textIdx]], []byte("\n")) + 1
+
+		sourceID := fileID
+		if fn := enclosingCallable(callables, line); fn != nil {
+			sourceID = fn.ID
+		}
+		for _, key := range refs {
+			issue := issues.NewNode(key)
+			if !nodes[issue.ID] {
+				nodes[issue.ID] = true
+				result.Nodes = append(result.Nodes, issue)
+			}
+			edgeID := issues.EdgeID(sourceID, key, issues.SourceComment)
+			edge, ok := edges[edgeID]
+			if !ok {
+				edge = &graph.Edge{
+					ID:       edgeID,
+					Type:     graph.EdgeReferences,
+					SourceID: sourceID,
+					TargetID: issue.ID,
+					Properties: map[string]string{
+						"source": issues.SourceComment,
+						"line":   strconv.Itoa(line),
+					},
+				}
+				edges[edgeID] = edge
+				result.Edges = append(result.Edges, edge)
+			}
+			if kind := annotations[line]; kind != "" && edge.Properties["annotation"] == "" {
+				edge.Properties["annotation"] = kind
+				edge.Properties["line"] = strconv.Itoa(line)
+			}
+		}
+	}
+	return result
+}
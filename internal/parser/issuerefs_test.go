@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestExtractIssueReferences(t *testing.T) {
+	src := `package pay
+
+// Refund implements PAY-12 (see acme/pay#4).
+func Refund() error {
+	// TODO(alice): partial refunds, blocked on PAY-12
+	msg := "// PAY-99 is not a comment"
+	return nil // PAY-12
+}
+`
+	result := &ParseResult{
+		FilePath: "pay/refund.go",
+		Language: LangGo,
+		Nodes: []*graph.Node{
+			{ID: "file", Type: graph.NodeFile, FilePath: "pay/refund.go"},
+			{ID: "refund", Type: graph.NodeFunction, Name: "Refund", Line: 4, EndLine: 8},
+		},
+	}
+	result = ExtractAnnotations(result, []byte(src))
+	result = ExtractIssueReferences(result, []byte(src))
+
+	issues := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeIssue {
+			issues[n.Name] = true
+		}
+	}
+	if len(issues) != 2 || !issues["PAY-12"] || !issues["acme/pay#4"] {
+		t.Errorf("issues = %v, want PAY-12 and acme/pay#4", issues)
+	}
+
+	type ref struct{ source, annotation, line string }
+	got := make(map[string]ref)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeReferences {
+			got[e.SourceID+" -> "+e.TargetID] = ref{e.Properties["source"], e.Properties["annotation"], e.Properties["line"]}
+		}
+	}
+	want := map[string]ref{
+		"file -> " + graph.NewNodeID(string(graph.NodeIssue), "", "PAY-12"):     {"comment", "", "3"},
+		"file -> " + graph.NewNodeID(string(graph.NodeIssue), "", "acme/pay#4"): {"comment", "", "3"},
+		"refund -> " + graph.NewNodeID(string(graph.NodeIssue), "", "PAY-12"):   {"comment", "todo", "5"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d References edges, want %d: %v", len(got), len(want), got)
+	}
+	for k, w := range want {
+		if got[k] != w {
+			t.Errorf("%s = %+v, want %+v", k, got[k], w)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -14,13 +15,20 @@ import (
 )
 
 // JavaParser extracts knowledge graph nodes and edges from Java source files.
-type JavaParser struct{}
+type JavaParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new Java parser.
 func NewParser() *JavaParser {
 	return &JavaParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *JavaParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *JavaParser) Language() parser.Language {
 	return parser.LangJava
 }
@@ -40,17 +48,19 @@ func (p *JavaParser) ParseFile(filePath string, content []byte) (*parser.ParseRe
 	}
 
 	e := &extractor{
-		filePath: filePath,
-		content:  content,
-		tree:     tree,
+		filePath:     filePath,
+		content:      content,
+		tree:         tree,
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
 	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangJava,
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangJava,
+		Diagnostics: parser.TreeSitterDiagnostics(tree.RootNode(), content),
 	}, nil
 }
 
@@ -62,14 +72,22 @@ type extractor struct {
 	nodes    []*graph.Node
 	edges    []*graph.Edge
 
-	pkgNodeID  string
-	fileNodeID string
-	pkgName    string
-	isTestFile bool
+	pkgNodeID    string
+	fileNodeID   string
+	pkgName      string
+	isTestFile   bool
+	testPatterns *parser.TestPatterns
 
 	// Lookup maps for function call resolution (built after walkProgram)
-	importMap      map[string]string            // simple class name → dep node ID
-	classMethodMap map[string]map[string]string // className → methodName → node ID
+	importMap      map[string]string                   // simple class name → dep node ID
+	classMethodMap map[string]map[string][]*graph.Node // className → methodName → overloads
+	methodIDs      map[string]string                   // scope + qualified signature → node ID
+
+	// scope lists the enclosing type names while a type body is walked.
+	scope []string
+	// mappings parallels scope with the request mapping of each enclosing
+	// type; only classes map requests.
+	mappings []typeMapping
 }
 
 func (e *extractor) extract() {
@@ -78,6 +96,8 @@ func (e *extractor) extract() {
 	root := e.tree.RootNode()
 	// First pass: extract all declarations (classes, methods, fields, imports)
 	e.walkProgram(root)
+	// Point implements at interfaces declared in this file
+	e.nodes, e.edges = parser.BindLocal(e.nodes, e.edges)
 	// Build lookup maps from extracted declarations
 	e.buildCallMaps()
 	// Second pass: walk method bodies for HTTP client calls and function calls
@@ -86,7 +106,7 @@ func (e *extractor) extract() {
 
 func (e *extractor) extractFileNode() {
 	base := filepath.Base(e.filePath)
-	e.isTestFile = isTestFilename(base)
+	e.isTestFile = isTestFilename(base) || e.testPatterns.MatchesFile(e.filePath)
 
 	fileType := graph.NodeFile
 	if e.isTestFile {
@@ -140,6 +160,68 @@ func (e *extractor) parentID() string {
 	return e.fileNodeID
 }
 
+// symbolID returns the ID of a symbol declared within scope. Types nested
+// in other types get scoped IDs so same-named nested types (Order.Builder,
+// Invoice.Builder) stay distinct; top-level symbols keep plain IDs.
+func (e *extractor) symbolID(nodeType graph.NodeType, scope []string, name string) string {
+	return graph.SymbolKey{
+		Language: string(parser.LangJava),
+		Type:     nodeType,
+		FilePath: e.filePath,
+		Scope:    scope,
+		Name:     name,
+	}.ID()
+}
+
+func (e *extractor) pushScope(name string) { e.scope = append(e.scope, name) }
+func (e *extractor) popScope()             { e.scope = e.scope[:len(e.scope)-1] }
+
+// memberScope is the scope of the members of the type being walked: the
+// types enclosing it, since member names are already qualified with the
+// type's own name.
+func (e *extractor) memberScope() []string {
+	if len(e.scope) == 0 {
+		return nil
+	}
+	return e.scope[:len(e.scope)-1]
+}
+
+// typeQualifiedName returns the Java qualified name of a type declared in
+// the current scope, e.g. com.acme.Order.Builder.
+func (e *extractor) typeQualifiedName(name string) string {
+	parts := append(append([]string(nil), e.scope...), name)
+	if e.pkgName != "" {
+		parts = append([]string{e.pkgName}, parts...)
+	}
+	return strings.Join(parts, ".")
+}
+
+// scopeProps returns node properties recording a non-empty scope as
+// Properties["scope"] (enclosing type names joined with ".").
+func scopeProps(scope []string) map[string]string {
+	props := make(map[string]string)
+	if len(scope) > 0 {
+		props["scope"] = strings.Join(scope, ".")
+	}
+	return props
+}
+
+// splitScope reverses the Properties["scope"] encoding.
+func splitScope(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ".")
+}
+
+// methodKey keys methodIDs by scope and qualified signature.
+func methodKey(scope []string, qualifiedName string) string {
+	if len(scope) == 0 {
+		return qualifiedName
+	}
+	return strings.Join(scope, ".") + "$" + qualifiedName
+}
+
 func (e *extractor) extractPackage(node *sitter.Node) {
 	// package_declaration contains a scoped_identifier or identifier
 	name := ""
@@ -187,27 +269,14 @@ func (e *extractor) extractImport(node *sitter.Node) {
 		return
 	}
 
-	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, name)
-
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
+	dep, edge := parser.Import{
+		Language: parser.LangJava,
 		Name:     name,
 		FilePath: e.filePath,
 		Line:     int(node.StartPoint().Row) + 1,
-		Language: string(parser.LangJava),
-		Package:  e.pkgName,
-		Properties: map[string]string{
-			"kind": "import",
-		},
-	})
-
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.parentID(), depID, string(graph.EdgeImports)),
-		Type:     graph.EdgeImports,
-		SourceID: e.parentID(),
-		TargetID: depID,
-	})
+	}.Link(e.parentID())
+	e.nodes = append(e.nodes, dep)
+	e.edges = append(e.edges, edge)
 }
 
 func (e *extractor) extractClass(node *sitter.Node, parentID string) {
@@ -243,9 +312,9 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	classID := graph.NewNodeID(string(graph.NodeClass), e.filePath, name)
+	classID := e.symbolID(graph.NodeClass, e.scope, name)
 
-	props := make(map[string]string)
+	props := scopeProps(e.scope)
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -254,15 +323,15 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 	}
 	if superClass != "" {
 		props["extends"] = superClass
+		if parser.IsErrorBase(superClass) {
+			props[parser.PropErrorType] = "true"
+		}
 	}
 	if len(interfaces) > 0 {
 		props["implements"] = strings.Join(interfaces, ",")
 	}
 
-	qualifiedName := name
-	if e.pkgName != "" {
-		qualifiedName = e.pkgName + "." + name
-	}
+	qualifiedName := e.typeQualifiedName(name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            classID,
@@ -288,18 +357,24 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 
 	// Implements edges
 	for _, iface := range interfaces {
-		ifaceID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, iface)
-		e.edges = append(e.edges, &graph.Edge{
-			ID:       edgeID(classID, ifaceID, string(graph.EdgeImplements)),
-			Type:     graph.EdgeImplements,
-			SourceID: classID,
-			TargetID: ifaceID,
-		})
+		ref, edge := parser.Unresolved{
+			Language:   parser.LangJava,
+			TargetType: graph.NodeInterface,
+			Name:       iface,
+			FilePath:   e.filePath,
+			Line:       startLine,
+		}.Link(graph.EdgeImplements, classID)
+		e.nodes = append(e.nodes, ref)
+		e.edges = append(e.edges, edge)
 	}
 
 	// Walk class body
 	if bodyNode != nil {
+		e.pushScope(name)
+		e.mappings = append(e.mappings, typeMapping{classRequestMapping(annotations), true})
 		e.walkClassBody(bodyNode, classID, name)
+		e.mappings = e.mappings[:len(e.mappings)-1]
+		e.popScope()
 	}
 }
 
@@ -330,9 +405,9 @@ func (e *extractor) extractInterface(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	ifaceID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, name)
+	ifaceID := e.symbolID(graph.NodeInterface, e.scope, name)
 
-	props := make(map[string]string)
+	props := scopeProps(e.scope)
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -357,10 +432,7 @@ func (e *extractor) extractInterface(node *sitter.Node, parentID string) {
 		props["methods"] = strings.Join(methodNames, ",")
 	}
 
-	qualifiedName := name
-	if e.pkgName != "" {
-		qualifiedName = e.pkgName + "." + name
-	}
+	qualifiedName := e.typeQualifiedName(name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            ifaceID,
@@ -386,7 +458,11 @@ func (e *extractor) extractInterface(node *sitter.Node, parentID string) {
 
 	// Extract methods from interface body
 	if bodyNode != nil {
+		e.pushScope(name)
+		e.mappings = append(e.mappings, typeMapping{})
 		e.walkInterfaceBody(bodyNode, ifaceID, name)
+		e.mappings = e.mappings[:len(e.mappings)-1]
+		e.popScope()
 	}
 }
 
@@ -414,9 +490,9 @@ func (e *extractor) extractEnum(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	enumID := graph.NewNodeID(string(graph.NodeEnum), e.filePath, name)
+	enumID := e.symbolID(graph.NodeEnum, e.scope, name)
 
-	props := make(map[string]string)
+	props := scopeProps(e.scope)
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -446,10 +522,7 @@ func (e *extractor) extractEnum(node *sitter.Node, parentID string) {
 		props["constants"] = strings.Join(constants, ",")
 	}
 
-	qualifiedName := name
-	if e.pkgName != "" {
-		qualifiedName = e.pkgName + "." + name
-	}
+	qualifiedName := e.typeQualifiedName(name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            enumID,
@@ -472,6 +545,19 @@ func (e *extractor) extractEnum(node *sitter.Node, parentID string) {
 		SourceID: parentID,
 		TargetID: enumID,
 	})
+
+	// Walk enum members (methods, constructors, fields, nested types)
+	if body := node.ChildByFieldName("body"); body != nil {
+		for i := 0; i < int(body.NamedChildCount()); i++ {
+			if decls := body.NamedChild(i); decls.Type() == "enum_body_declarations" {
+				e.pushScope(name)
+				e.mappings = append(e.mappings, typeMapping{})
+				e.walkClassBody(decls, enumID, name)
+				e.mappings = e.mappings[:len(e.mappings)-1]
+				e.popScope()
+			}
+		}
+	}
 }
 
 func (e *extractor) walkClassBody(body *sitter.Node, classID, className string) {
@@ -497,8 +583,15 @@ func (e *extractor) walkClassBody(body *sitter.Node, classID, className string)
 func (e *extractor) walkInterfaceBody(body *sitter.Node, ifaceID, ifaceName string) {
 	for i := 0; i < int(body.NamedChildCount()); i++ {
 		child := body.NamedChild(i)
-		if child.Type() == "method_declaration" {
+		switch child.Type() {
+		case "method_declaration":
 			e.extractMethod(child, ifaceID, ifaceName)
+		case "class_declaration":
+			e.extractClass(child, ifaceID)
+		case "interface_declaration":
+			e.extractInterface(child, ifaceID)
+		case "enum_declaration":
+			e.extractEnum(child, ifaceID)
 		}
 	}
 }
@@ -527,6 +620,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string)
 	name := ""
 	returnType := ""
 	params := ""
+	var paramsNode *sitter.Node
 	var annotations []string
 	modifiers := ""
 
@@ -541,6 +635,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string)
 			modifiers, annotations = e.extractModifiers(child)
 		case "formal_parameters":
 			params = e.nodeText(child)
+			paramsNode = child
 		case "type_identifier", "void_type", "generic_type", "array_type",
 			"integral_type", "floating_point_type", "boolean_type", "scoped_type_identifier":
 			returnType = e.nodeText(child)
@@ -553,11 +648,12 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string)
 
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
-	qualifiedName := className + "." + name
+	paramTypes, arity := e.paramSignature(paramsNode)
+	qualifiedName := parser.MethodSignatureKey(className, name, paramTypes)
 
 	sig := returnType + " " + name + params
 
-	props := make(map[string]string)
+	props := scopeProps(e.memberScope())
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -565,14 +661,18 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string)
 		props["annotations"] = strings.Join(annotations, ",")
 	}
 	props["class"] = className
+	props["arity"] = arity.String()
+	if throws := e.throwsClause(node); len(throws) > 0 {
+		props[parser.PropThrows] = strings.Join(throws, ",")
+	}
 
 	// Determine if this is a test method (only in test files with test annotations).
 	nodeType := graph.NodeMethod
-	if e.isTestFile && hasTestAnnotation(annotations) {
+	if e.isTestFile && (hasTestAnnotation(annotations) || e.testPatterns.MatchesAnnotation(annotations)) {
 		nodeType = graph.NodeTestFunction
 	}
 
-	methodID := graph.NewNodeID(string(nodeType), e.filePath, qualifiedName)
+	methodID := e.symbolID(nodeType, e.memberScope(), qualifiedName)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            methodID,
@@ -596,11 +696,69 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string)
 		SourceID: parentID,
 		TargetID: methodID,
 	})
+
+	for _, ann := range annotations {
+		if schedule, ok := scheduledAnnotation(ann); ok {
+			e.addScheduledJob(className+"."+name, schedule, startLine, parentID, methodID, name)
+		}
+	}
+	e.addSpringEndpoints(annotations, className, name, methodID, startLine)
+}
+
+// scheduledArgPattern matches the schedule attributes of Spring's
+// @Scheduled: cron = "..." or fixedRate/fixedDelay (optionally as strings).
+var scheduledArgPattern = regexp.MustCompile(`\b(cron|fixedRate|fixedDelay)(?:String)?\s*=\s*("[^"]*"|[\w.]+)`)
+
+// scheduledAnnotation returns the schedule of a Spring @Scheduled
+// annotation, e.g. "0 0 * * *" or "fixedRate=5000".
+func scheduledAnnotation(ann string) (string, bool) {
+	name, args, _ := strings.Cut(ann, "(")
+	if name != "Scheduled" && !strings.HasSuffix(name, ".Scheduled") {
+		return "", false
+	}
+	m := scheduledArgPattern.FindStringSubmatch(args)
+	if m == nil {
+		return "", true
+	}
+	value := strings.Trim(m[2], `"`)
+	if m[1] == "cron" {
+		return value, true
+	}
+	return m[1] + "=" + value, true
+}
+
+// addScheduledJob records a Job node for a scheduled method, contained in
+// the method's class and calling the method.
+func (e *extractor) addScheduledJob(name, schedule string, line int, parentID, methodID, handler string) {
+	job := parser.NewJobNode(e.filePath, parser.LangJava, parser.Job{
+		Name:      name,
+		Schedule:  schedule,
+		Framework: "spring",
+		Handler:   handler,
+		Line:      line,
+	})
+	job.Package = e.pkgName
+	e.nodes = append(e.nodes, job)
+	e.edges = append(e.edges,
+		&graph.Edge{
+			ID:       edgeID(parentID, job.ID, string(graph.EdgeContains)),
+			Type:     graph.EdgeContains,
+			SourceID: parentID,
+			TargetID: job.ID,
+		},
+		&graph.Edge{
+			ID:       edgeID(job.ID, methodID, string(graph.EdgeCalls)),
+			Type:     graph.EdgeCalls,
+			SourceID: job.ID,
+			TargetID: methodID,
+		},
+	)
 }
 
 func (e *extractor) extractConstructor(node *sitter.Node, parentID, className string) {
 	name := ""
 	params := ""
+	var paramsNode *sitter.Node
 	var annotations []string
 	modifiers := ""
 
@@ -615,6 +773,7 @@ func (e *extractor) extractConstructor(node *sitter.Node, parentID, className st
 			modifiers, annotations = e.extractModifiers(child)
 		case "formal_parameters":
 			params = e.nodeText(child)
+			paramsNode = child
 		}
 	}
 
@@ -624,11 +783,12 @@ func (e *extractor) extractConstructor(node *sitter.Node, parentID, className st
 
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
-	qualifiedName := className + "." + name
+	paramTypes, arity := e.paramSignature(paramsNode)
+	qualifiedName := parser.MethodSignatureKey(className, name, paramTypes)
 
 	sig := name + params
 
-	props := make(map[string]string)
+	props := scopeProps(e.memberScope())
 	if modifiers != "" {
 		props["modifiers"] = modifiers
 	}
@@ -637,8 +797,12 @@ func (e *extractor) extractConstructor(node *sitter.Node, parentID, className st
 	}
 	props["class"] = className
 	props["constructor"] = "true"
+	props["arity"] = arity.String()
+	if throws := e.throwsClause(node); len(throws) > 0 {
+		props[parser.PropThrows] = strings.Join(throws, ",")
+	}
 
-	methodID := graph.NewNodeID(string(graph.NodeMethod), e.filePath, qualifiedName)
+	methodID := e.symbolID(graph.NodeMethod, e.memberScope(), qualifiedName)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            methodID,
@@ -693,7 +857,7 @@ func (e *extractor) extractField(node *sitter.Node, parentID, className string)
 			line := int(node.StartPoint().Row) + 1
 			qualifiedName := className + "." + name
 
-			props := make(map[string]string)
+			props := scopeProps(e.memberScope())
 			if modifiers != "" {
 				props["modifiers"] = modifiers
 			}
@@ -705,7 +869,7 @@ func (e *extractor) extractField(node *sitter.Node, parentID, className string)
 			}
 			props["class"] = className
 
-			varID := graph.NewNodeID(string(graph.NodeVariable), e.filePath, qualifiedName)
+			varID := e.symbolID(graph.NodeVariable, e.memberScope(), qualifiedName)
 
 			e.nodes = append(e.nodes, &graph.Node{
 				ID:            varID,
@@ -781,6 +945,58 @@ func (e *extractor) extractSuperInterfaces(node *sitter.Node) []string {
 	return ifaces
 }
 
+// paramSignature returns the normalized parameter types of a formal_parameters
+// node and the arity they give the method. Varargs parameters are recorded as
+// "T..." and make the method variadic.
+func (e *extractor) paramSignature(params *sitter.Node) ([]string, parser.Arity) {
+	types := []string{}
+	if params == nil {
+		return types, parser.Arity{}
+	}
+	variadic := false
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		child := params.NamedChild(i)
+		switch child.Type() {
+		case "formal_parameter":
+			if t := child.ChildByFieldName("type"); t != nil {
+				types = append(types, parser.NormalizeParamType(e.nodeText(t)))
+			}
+		case "spread_parameter":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				t := child.NamedChild(j)
+				if t.Type() != "modifiers" && t.Type() != "variable_declarator" {
+					types = append(types, parser.NormalizeParamType(e.nodeText(t))+"...")
+					variadic = true
+					break
+				}
+			}
+		}
+	}
+	arity := parser.Arity{Min: len(types), Max: len(types)}
+	if variadic {
+		arity = parser.Arity{Min: len(types) - 1, Max: -1}
+	}
+	return types, arity
+}
+
+// argumentCount returns the number of arguments passed by a
+// method_invocation, or -1 when it has no argument list.
+func argumentCount(invocation *sitter.Node) int {
+	args := invocation.ChildByFieldName("arguments")
+	if args == nil {
+		return -1
+	}
+	n := 0
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		switch args.NamedChild(i).Type() {
+		case "line_comment", "block_comment":
+		default:
+			n++
+		}
+	}
+	return n
+}
+
 func (e *extractor) getMethodName(node *sitter.Node) string {
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		child := node.NamedChild(i)
@@ -834,7 +1050,8 @@ func (e *extractor) extractJavadoc(node *sitter.Node) string {
 // walkForCalls can resolve call targets.
 func (e *extractor) buildCallMaps() {
 	e.importMap = make(map[string]string)
-	e.classMethodMap = make(map[string]map[string]string)
+	e.classMethodMap = make(map[string]map[string][]*graph.Node)
+	e.methodIDs = make(map[string]string)
 
 	for _, n := range e.nodes {
 		switch n.Type {
@@ -849,9 +1066,10 @@ func (e *extractor) buildCallMaps() {
 			className := n.Properties["class"]
 			if className != "" {
 				if e.classMethodMap[className] == nil {
-					e.classMethodMap[className] = make(map[string]string)
+					e.classMethodMap[className] = make(map[string][]*graph.Node)
 				}
-				e.classMethodMap[className][n.Name] = n.ID
+				e.classMethodMap[className][n.Name] = append(e.classMethodMap[className][n.Name], n)
+				e.methodIDs[methodKey(splitScope(n.Properties["scope"]), n.QualifiedName)] = n.ID
 			}
 		}
 	}
@@ -862,12 +1080,20 @@ func (e *extractor) buildCallMaps() {
 func (e *extractor) walkMethodBodies(root *sitter.Node) {
 	for i := 0; i < int(root.NamedChildCount()); i++ {
 		child := root.NamedChild(i)
-		if child.Type() == "class_declaration" {
+		switch child.Type() {
+		case "class_declaration", "interface_declaration", "enum_declaration":
 			e.walkClassBodiesForCalls(child)
 		}
 	}
 }
 
+// typeNodeTypes maps type declarations to the node type they are extracted as.
+var typeNodeTypes = map[string]graph.NodeType{
+	"class_declaration":     graph.NodeClass,
+	"interface_declaration": graph.NodeInterface,
+	"enum_declaration":      graph.NodeEnum,
+}
+
 func (e *extractor) walkClassBodiesForCalls(classNode *sitter.Node) {
 	className := ""
 	var bodyNode *sitter.Node
@@ -876,30 +1102,38 @@ func (e *extractor) walkClassBodiesForCalls(classNode *sitter.Node) {
 		switch child.Type() {
 		case "identifier":
 			className = e.nodeText(child)
-		case "class_body":
+		case "class_body", "interface_body", "enum_body":
 			bodyNode = child
 		}
 	}
 	if className == "" || bodyNode == nil {
 		return
 	}
+	classID := e.symbolID(typeNodeTypes[classNode.Type()], e.scope, className)
+	e.pushScope(className)
+	e.walkMembersForCalls(bodyNode, classID, className)
+	e.popScope()
+}
 
-	for i := 0; i < int(bodyNode.NamedChildCount()); i++ {
-		child := bodyNode.NamedChild(i)
+// walkMembersForCalls walks the members of a class, interface, or enum body.
+// Calls in methods, including those inside lambdas and anonymous classes, are
+// attributed to the enclosing method; calls in initializers are attributed to
+// a synthetic initializer method (see walkInitializerForCalls).
+func (e *extractor) walkMembersForCalls(body *sitter.Node, classID, className string) {
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		child := body.NamedChild(i)
 		switch child.Type() {
 		case "method_declaration", "constructor_declaration":
 			methodName := e.getMethodName(child)
 			if methodName == "" {
 				continue
 			}
-			// Look up the method ID from classMethodMap (handles both Method and TestFunction).
-			methodID := ""
-			if methods, ok := e.classMethodMap[className]; ok {
-				methodID = methods[methodName]
-			}
+			// Look up the method ID by signature (handles both Method and TestFunction).
+			paramTypes, _ := e.paramSignature(child.ChildByFieldName("parameters"))
+			qualifiedName := parser.MethodSignatureKey(className, methodName, paramTypes)
+			methodID := e.methodIDs[methodKey(e.memberScope(), qualifiedName)]
 			if methodID == "" {
-				qualifiedName := className + "." + methodName
-				methodID = graph.NewNodeID(string(graph.NodeMethod), e.filePath, qualifiedName)
+				methodID = e.symbolID(graph.NodeMethod, e.memberScope(), qualifiedName)
 			}
 			// Walk the method body for calls
 			for j := 0; j < int(child.NamedChildCount()); j++ {
@@ -908,13 +1142,72 @@ func (e *extractor) walkClassBodiesForCalls(classNode *sitter.Node) {
 					e.walkForCalls(bodyChild, methodID, className)
 				}
 			}
-		case "class_declaration":
-			// Nested class
+		case "class_declaration", "interface_declaration", "enum_declaration":
+			// Nested type
 			e.walkClassBodiesForCalls(child)
+		case "enum_body_declarations":
+			e.walkMembersForCalls(child, classID, className)
+		case "static_initializer", "constant_declaration", "enum_constant":
+			// Interface constants and enum constants (arguments and
+			// constant-specific bodies) are initialized statically.
+			e.walkInitializerForCalls(child, classID, className, true)
+		case "block":
+			e.walkInitializerForCalls(child, classID, className, false)
+		case "field_declaration":
+			static := false
+			if mods := child.NamedChild(0); mods != nil && mods.Type() == "modifiers" {
+				static = strings.Contains(e.nodeText(mods), "static")
+			}
+			e.walkInitializerForCalls(child, classID, className, static)
 		}
 	}
 }
 
+// walkInitializerForCalls walks an initializer block or field initializer
+// and attributes its calls to a synthetic method named after the JVM's
+// initializers: "<clinit>" for static initialization and "<init>" for
+// instance initialization, which runs as part of every constructor. The
+// node is only created once an initializer of the class makes a call.
+func (e *extractor) walkInitializerForCalls(node *sitter.Node, classID, className string, static bool) {
+	name, kind := "<init>", "instance"
+	if static {
+		name, kind = "<clinit>", "static"
+	}
+	qualifiedName := parser.MethodSignatureKey(className, name, nil)
+	methodID := e.symbolID(graph.NodeMethod, e.memberScope(), qualifiedName)
+
+	before := len(e.edges)
+	e.walkForCalls(node, methodID, className)
+	key := methodKey(e.memberScope(), qualifiedName)
+	if len(e.edges) == before || e.methodIDs[key] != "" {
+		return
+	}
+	e.methodIDs[key] = methodID
+
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:            methodID,
+		Type:          graph.NodeMethod,
+		Name:          name,
+		QualifiedName: qualifiedName,
+		FilePath:      e.filePath,
+		Line:          int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		Package:       e.pkgName,
+		Language:      string(parser.LangJava),
+		Properties: map[string]string{
+			"class":       className,
+			"initializer": kind,
+			"arity":       "0",
+		},
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(classID, methodID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: classID,
+		TargetID: methodID,
+	})
+}
+
 // walkForCalls recursively walks a node tree looking for method_invocation and
 // object_creation_expression nodes to detect HTTP client calls and function calls.
 func (e *extractor) walkForCalls(node *sitter.Node, methodID string, className string) {
@@ -929,6 +1222,14 @@ func (e *extractor) walkForCalls(node *sitter.Node, methodID string, className s
 		}
 	case "object_creation_expression":
 		e.checkObjectCreationHTTP(node, methodID)
+	case "throw_statement":
+		// throw new PaymentDeclinedException(...); rethrown variables are
+		// not recorded.
+		if thrown := node.NamedChild(0); thrown != nil && thrown.Type() == "object_creation_expression" {
+			if t := thrown.ChildByFieldName("type"); t != nil {
+				parser.AddThrows(e.nodes, methodID, e.typeName(t))
+			}
+		}
 	}
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
@@ -936,6 +1237,28 @@ func (e *extractor) walkForCalls(node *sitter.Node, methodID string, className s
 	}
 }
 
+// throwsClause returns the exception types in a method or constructor's
+// throws clause.
+func (e *extractor) throwsClause(node *sitter.Node) []string {
+	var types []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != "throws" {
+			continue
+		}
+		for j := 0; j < int(child.NamedChildCount()); j++ {
+			types = append(types, e.typeName(child.NamedChild(j)))
+		}
+	}
+	return types
+}
+
+// typeName returns the text of a type without its type arguments.
+func (e *extractor) typeName(node *sitter.Node) string {
+	name, _, _ := strings.Cut(e.nodeText(node), "<")
+	return strings.TrimSpace(name)
+}
+
 // RestTemplate method name → HTTP method mapping.
 var restTemplateMethods = map[string]string{
 	"getForObject":   "GET",
@@ -1237,18 +1560,17 @@ func (e *extractor) checkFunctionCall(node *sitter.Node, methodID string, classN
 
 	// Case 1: no object or "this" → same-class call
 	if objectName == "" || objectName == "this" {
-		if methods, ok := e.classMethodMap[className]; ok {
-			if targetID, ok := methods[calledMethod]; ok {
-				e.edges = append(e.edges, &graph.Edge{
-					ID:       edgeID(methodID, targetID, string(graph.EdgeCalls)),
-					Type:     graph.EdgeCalls,
-					SourceID: methodID,
-					TargetID: targetID,
-					Properties: map[string]string{
-						"callee": calledMethod,
-					},
-				})
-			}
+		// Overloads are told apart by argument count.
+		if target := parser.PickOverload(e.classMethodMap[className][calledMethod], argumentCount(node)); target != nil {
+			e.edges = append(e.edges, &graph.Edge{
+				ID:       edgeID(methodID, target.ID, string(graph.EdgeCalls)),
+				Type:     graph.EdgeCalls,
+				SourceID: methodID,
+				TargetID: target.ID,
+				Properties: map[string]string{
+					"callee": calledMethod,
+				},
+			})
 		}
 		return
 	}
@@ -1274,7 +1596,7 @@ func (e *extractor) nodeText(node *sitter.Node) string {
 // Helper functions
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
 
 func cleanJavadoc(raw string) string {
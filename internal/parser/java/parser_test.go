@@ -3,6 +3,7 @@ package java
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -403,9 +404,9 @@ func TestExtractFunctionCalls(t *testing.T) {
 	}
 
 	// Verify: processUser calls validate (same-class)
-	processUserID := graph.NewNodeID(string(graph.NodeMethod), fixturePath, "UserService.processUser")
-	validateID := graph.NewNodeID(string(graph.NodeMethod), fixturePath, "UserService.validate")
-	handleRequestID := graph.NewNodeID(string(graph.NodeMethod), fixturePath, "UserService.handleRequest")
+	processUserID := graph.NewNodeID(string(graph.NodeMethod), fixturePath, "UserService.processUser(User)")
+	validateID := graph.NewNodeID(string(graph.NodeMethod), fixturePath, "UserService.validate(User)")
+	handleRequestID := graph.NewNodeID(string(graph.NodeMethod), fixturePath, "UserService.handleRequest(User)")
 
 	foundProcessUserValidate := false
 	foundProcessUserStringUtils := false
@@ -640,3 +641,427 @@ func findNodeByNameAndType(nodes []*graph.Node, name string, nodeType graph.Node
 	}
 	return nil
 }
+
+func TestTestPatternsAnnotationOverride(t *testing.T) {
+	src := []byte(`package com.example.it;
+
+public class UserFlows {
+    @IntegrationTest(slow = true)
+    public void createsUser() {}
+
+    public void helper() {}
+}
+`)
+	p := NewParser()
+	p.SetTestPatterns(&parser.TestPatterns{
+		FileGlobs:   []string{"**/it/**"},
+		Annotations: []string{"IntegrationTest"},
+	})
+
+	result, err := p.ParseFile("src/it/java/com/example/it/UserFlows.java", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	var isTestFile bool
+	testFuncs := make(map[string]bool)
+	for _, n := range result.Nodes {
+		switch n.Type {
+		case graph.NodeTestFile:
+			isTestFile = true
+		case graph.NodeTestFunction:
+			testFuncs[n.Name] = true
+		}
+	}
+	if !isTestFile {
+		t.Error("expected file to be detected as test file via glob override")
+	}
+	if !testFuncs["createsUser"] && !testFuncs["UserFlows.createsUser"] {
+		t.Errorf("expected createsUser to be a test function, got %v", testFuncs)
+	}
+	if testFuncs["helper"] || testFuncs["UserFlows.helper"] {
+		t.Error("helper should not be a test function")
+	}
+}
+
+func TestOverloadedMethods(t *testing.T) {
+	src := []byte(`package com.example;
+
+public class Greeter {
+    public Greeter() {}
+    public Greeter(java.util.Locale locale) {}
+
+    public String greet(String s) { return s; }
+    public String greet(String s, final java.util.List<String> args) { return s; }
+    public String greet(int n, Object... rest) { return ""; }
+
+    public void run() {
+        greet("a");
+        greet("a", null);
+        greet(1, 2, 3, 4);
+    }
+}
+`)
+	result, err := NewParser().ParseFile("Greeter.java", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	byQName := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeMethod {
+			byQName[n.QualifiedName] = n
+		}
+	}
+	for qname, arity := range map[string]string{
+		"Greeter.Greeter()":            "0",
+		"Greeter.Greeter(Locale)":      "1",
+		"Greeter.greet(String)":        "1",
+		"Greeter.greet(String,List)":   "2",
+		"Greeter.greet(int,Object...)": "1+",
+		"Greeter.run()":                "0",
+	} {
+		n, ok := byQName[qname]
+		if !ok {
+			t.Errorf("missing method %s (have %v)", qname, byQName)
+			continue
+		}
+		if n.Properties["arity"] != arity {
+			t.Errorf("%s arity = %q, want %q", qname, n.Properties["arity"], arity)
+		}
+	}
+
+	run := byQName["Greeter.run()"]
+	called := make(map[string]bool)
+	for _, edge := range result.Edges {
+		if edge.Type == graph.EdgeCalls && run != nil && edge.SourceID == run.ID {
+			for _, n := range byQName {
+				if n.ID == edge.TargetID {
+					called[n.QualifiedName] = true
+				}
+			}
+		}
+	}
+	for _, want := range []string{"Greeter.greet(String)", "Greeter.greet(String,List)", "Greeter.greet(int,Object...)"} {
+		if !called[want] {
+			t.Errorf("expected run() to call %s, got %v", want, called)
+		}
+	}
+}
+
+func TestNestedAndAnonymousCallAttribution(t *testing.T) {
+	src := []byte(`package com.example;
+
+public class Worker {
+    private static final Map<String, String> CACHE;
+    static {
+        CACHE = load();
+    }
+
+    private final Runnable task = () -> refresh();
+
+    public void start() {
+        new Thread(new Runnable() {
+            @Override
+            public void run() {
+                refresh();
+            }
+        }).start();
+        list.forEach(item -> process(item));
+    }
+
+    private static Map<String, String> load() { return null; }
+    private void refresh() {}
+    private void process(String item) {}
+
+    interface Listener {
+        default void notifyAll(String event) { handle(event); }
+        void handle(String event);
+    }
+
+    enum Mode {
+        FAST, SLOW;
+
+        boolean isFast() { return check(this); }
+        static boolean check(Mode m) { return m == FAST; }
+    }
+}
+`)
+	result, err := NewParser().ParseFile("Worker.java", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	byQName := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeMethod {
+			byQName[n.QualifiedName] = n
+		}
+	}
+	calls := make(map[string]bool)
+	for _, edge := range result.Edges {
+		if edge.Type != graph.EdgeCalls {
+			continue
+		}
+		var src, dst string
+		for qname, n := range byQName {
+			if n.ID == edge.SourceID {
+				src = qname
+			}
+			if n.ID == edge.TargetID {
+				dst = qname
+			}
+		}
+		calls[src+" -> "+dst] = true
+	}
+
+	for _, want := range []string{
+		"Worker.<clinit>() -> Worker.load()",
+		"Worker.<init>() -> Worker.refresh()",
+		"Worker.start() -> Worker.refresh()",
+		"Worker.start() -> Worker.process(String)",
+		"Listener.notifyAll(String) -> Listener.handle(String)",
+		"Mode.isFast() -> Mode.check(Mode)",
+	} {
+		if !calls[want] {
+			t.Errorf("missing call %s; got %v", want, calls)
+		}
+	}
+	if n := byQName["Worker.<clinit>()"]; n == nil || n.Properties["initializer"] != "static" {
+		t.Errorf("expected static initializer node, got %+v", n)
+	}
+}
+
+func TestNestedTypeIDs(t *testing.T) {
+	src := []byte(`package com.acme;
+
+public class Order {
+    public static class Builder {
+        public Order build() { return validate(); }
+        private Order validate() { return null; }
+    }
+}
+
+class Invoice {
+    static class Builder {
+        public Invoice build() { return null; }
+    }
+}
+`)
+	result, err := NewParser().ParseFile("Billing.java", src)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	ids := make(map[string]bool)
+	var builders, builds []*graph.Node
+	for _, n := range result.Nodes {
+		if ids[n.ID] {
+			t.Errorf("duplicate node ID for %s %s", n.Type, n.QualifiedName)
+		}
+		ids[n.ID] = true
+		switch {
+		case n.Type == graph.NodeClass && n.Name == "Builder":
+			builders = append(builders, n)
+		case n.Type == graph.NodeMethod && n.Name == "build":
+			builds = append(builds, n)
+		}
+	}
+	if len(builders) != 2 || len(builds) != 2 {
+		t.Fatalf("got %d Builder classes and %d build methods, want 2 each", len(builders), len(builds))
+	}
+	if builders[0].QualifiedName != "com.acme.Order.Builder" || builders[0].Properties["scope"] != "Order" {
+		t.Errorf("nested class = %s scope %q, want com.acme.Order.Builder scope Order",
+			builders[0].QualifiedName, builders[0].Properties["scope"])
+	}
+
+	// Top-level types keep their unscoped IDs.
+	order := findNodeByNameAndType(result.Nodes, "Order", graph.NodeClass)
+	if order == nil || order.ID != graph.NewNodeID(string(graph.NodeClass), "Billing.java", "Order") {
+		t.Errorf("top-level class ID changed: %+v", order)
+	}
+
+	// Calls inside a nested type resolve to that type's methods.
+	var resolved bool
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && e.SourceID == builds[0].ID {
+			validate := findNodeByNameAndType(result.Nodes, "validate", graph.NodeMethod)
+			resolved = validate != nil && e.TargetID == validate.ID
+		}
+	}
+	if !resolved {
+		t.Error("Order.Builder.build() -> validate() call not resolved")
+	}
+}
+
+func TestScheduledJobs(t *testing.T) {
+	source := `package com.example.jobs;
+
+@Component
+public class ReportJobs {
+    @Scheduled(cron = "0 0 3 * * *")
+    public void nightly() {}
+
+    @Scheduled(fixedRate = 60000)
+    public void poll() {}
+
+    public void helper() {}
+}
+`
+	result, err := NewParser().ParseFile("src/ReportJobs.java", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	byID := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		byID[n.ID] = n
+	}
+	calls := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && byID[e.SourceID] != nil && byID[e.SourceID].Type == graph.NodeJob {
+			calls[byID[e.SourceID].Name] = byID[e.TargetID].Name
+		}
+	}
+
+	tests := []struct {
+		name, schedule, handler string
+	}{
+		{"ReportJobs.nightly", "0 0 3 * * *", "nightly"},
+		{"ReportJobs.poll", "fixedRate=60000", "poll"},
+	}
+	jobs := 0
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeJob {
+			jobs++
+		}
+	}
+	if jobs != len(tests) {
+		t.Errorf("got %d Job nodes, want %d", jobs, len(tests))
+	}
+	for _, tt := range tests {
+		job := findNodeByNameAndType(result.Nodes, tt.name, graph.NodeJob)
+		if job == nil {
+			t.Errorf("missing Job %s", tt.name)
+			continue
+		}
+		if got := job.Properties[parser.JobPropSchedule]; got != tt.schedule {
+			t.Errorf("Job %s schedule = %q, want %q", tt.name, got, tt.schedule)
+		}
+		if got := job.Properties[parser.JobPropFramework]; got != "spring" {
+			t.Errorf("Job %s framework = %q, want spring", tt.name, got)
+		}
+		if calls[tt.name] != tt.handler {
+			t.Errorf("Job %s calls %q, want %q", tt.name, calls[tt.name], tt.handler)
+		}
+	}
+}
+
+func TestErrorSites(t *testing.T) {
+	source := `package com.acme;
+
+public class PaymentDeclinedException extends RuntimeException {
+    public PaymentDeclinedException(String m) { super(m); }
+}
+
+class Gateway {
+    void charge(int amount) throws java.io.IOException {
+        if (amount < 0) throw new PaymentDeclinedException("negative");
+        try { send(); } catch (IllegalStateException e) { throw e; }
+    }
+}
+`
+	result, err := NewParser().ParseFile("src/Payments.java", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	wantThrows := map[string]string{"charge": "java.io.IOException,PaymentDeclinedException"}
+	wantErrorTypes := map[string]bool{"PaymentDeclinedException": true}
+	gotThrows := make(map[string]string)
+	gotErrorTypes := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if throws := n.Properties[parser.PropThrows]; throws != "" {
+			gotThrows[n.Name] = throws
+		}
+		if n.Properties[parser.PropErrorType] == "true" {
+			gotErrorTypes[n.Name] = true
+		}
+	}
+	if !reflect.DeepEqual(gotThrows, wantThrows) {
+		t.Errorf("throws = %v, want %v", gotThrows, wantThrows)
+	}
+	if !reflect.DeepEqual(gotErrorTypes, wantErrorTypes) {
+		t.Errorf("error types = %v, want %v", gotErrorTypes, wantErrorTypes)
+	}
+}
+
+func TestSpringEndpoints(t *testing.T) {
+	src := `package com.acme.orders;
+
+@RestController
+@RequestMapping("/api/orders")
+public class OrderController {
+    @GetMapping
+    public List<Order> list() { return null; }
+
+    @GetMapping("/{id}")
+    public Order get(@PathVariable long id) { return null; }
+
+    @RequestMapping(value = {"/search", "/find"}, method = RequestMethod.POST)
+    public List<Order> search() { return null; }
+}
+
+@FeignClient("billing")
+interface BillingClient {
+    @GetMapping("/invoices")
+    List<Invoice> invoices();
+}
+`
+	result, err := NewParser().ParseFile("OrderController.java", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseFile error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeAPIEndpoint {
+			got[n.Name] = n.Properties["action"]
+		}
+	}
+	want := map[string]string{
+		"GET /api/orders":         "list",
+		"GET /api/orders/{id}":    "get",
+		"POST /api/orders/search": "search",
+		"POST /api/orders/find":   "search",
+	}
+	if len(got) != len(want) {
+		t.Errorf("endpoints = %v, want %v", got, want)
+	}
+	for name, action := range want {
+		if got[name] != action {
+			t.Errorf("endpoint %q action = %q, want %q", name, got[name], action)
+		}
+	}
+}
+
+func TestSpringMapping(t *testing.T) {
+	tests := []struct {
+		ann   string
+		verb  string
+		paths []string
+	}{
+		{`GetMapping("/{id}")`, "GET", []string{"/{id}"}},
+		{`PostMapping(path = "/orders", consumes = "application/json")`, "POST", []string{"/orders"}},
+		{`RequestMapping({"/a", "/b/{x}"})`, "ANY", []string{"/a", "/b/{x}"}},
+		{`RequestMapping(method = RequestMethod.DELETE, value = "/x")`, "DELETE", []string{"/x"}},
+		{`DeleteMapping`, "DELETE", []string{""}},
+	}
+	for _, tt := range tests {
+		verb, paths, ok := springMapping(tt.ann)
+		if !ok || verb != tt.verb || strings.Join(paths, ",") != strings.Join(tt.paths, ",") {
+			t.Errorf("springMapping(%q) = %q %v %v, want %q %v", tt.ann, verb, paths, ok, tt.verb, tt.paths)
+		}
+	}
+	if _, _, ok := springMapping("Scheduled(cron = \"0 0 * * *\")"); ok {
+		t.Error("springMapping accepted @Scheduled")
+	}
+}
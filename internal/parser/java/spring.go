@@ -0,0 +1,143 @@
+package java
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// springMappingMethods maps Spring's request mapping annotations to HTTP
+// verbs; @RequestMapping takes its verb from the method attribute.
+var springMappingMethods = map[string]string{
+	"GetMapping":     "GET",
+	"PostMapping":    "POST",
+	"PutMapping":     "PUT",
+	"DeleteMapping":  "DELETE",
+	"PatchMapping":   "PATCH",
+	"RequestMapping": "",
+}
+
+// springPathsExpr matches a path or array of paths: "..." or {"...", "..."}.
+const springPathsExpr = `\{(?:\s*"[^"]*"\s*,?)*\s*\}|"[^"]*"`
+
+var (
+	// springPathAttrPattern matches the path attribute of a mapping
+	// annotation: value = "..." or path = {"...", "..."}.
+	springPathAttrPattern = regexp.MustCompile(`\b(?:value|path)\s*=\s*(` + springPathsExpr + `)`)
+	// springPathsPattern matches a leading positional path argument.
+	springPathsPattern = regexp.MustCompile(`^(?:` + springPathsExpr + `)`)
+	// springVerbPattern matches a RequestMethod constant.
+	springVerbPattern = regexp.MustCompile(`RequestMethod\.([A-Z]+)`)
+	stringLitPattern  = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// springMapping parses a Spring request mapping annotation such as
+// GetMapping("/{id}") or RequestMapping(value = "/orders", method =
+// RequestMethod.POST), returning its HTTP verb ("ANY" for a
+// @RequestMapping without one) and paths. A mapping without a path maps
+// the empty path.
+func springMapping(ann string) (verb string, paths []string, ok bool) {
+	name, args, _ := strings.Cut(ann, "(")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	verb, ok = springMappingMethods[name]
+	if !ok {
+		return "", nil, false
+	}
+	args = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(args), ")"))
+
+	if verb == "" {
+		verb = "ANY"
+		if m := springVerbPattern.FindStringSubmatch(args); m != nil {
+			verb = m[1]
+		}
+	}
+
+	value := springPathsPattern.FindString(args)
+	if m := springPathAttrPattern.FindStringSubmatch(args); value == "" && m != nil {
+		value = m[1]
+	}
+	for _, m := range stringLitPattern.FindAllStringSubmatch(value, -1) {
+		paths = append(paths, m[1])
+	}
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+	return verb, paths, true
+}
+
+// classRequestMapping returns the path of a controller's class-level
+// @RequestMapping, the prefix of its handler methods' paths.
+func classRequestMapping(annotations []string) string {
+	for _, ann := range annotations {
+		if name, _, _ := strings.Cut(ann, "("); strings.HasSuffix(name, "RequestMapping") {
+			if _, paths, ok := springMapping(ann); ok {
+				return paths[0]
+			}
+		}
+	}
+	return ""
+}
+
+// typeMapping is the request mapping of a type being walked: the path of a
+// class-level @RequestMapping, and whether the type's methods can map
+// requests (interface methods declare clients, such as Feign's).
+type typeMapping struct {
+	path     string
+	handlers bool
+}
+
+// joinSpringPath joins a class mapping and a method mapping into a path.
+func joinSpringPath(prefix, path string) string {
+	joined := strings.Trim(strings.Trim(prefix, "/")+"/"+strings.Trim(path, "/"), "/")
+	return "/" + joined
+}
+
+// addSpringEndpoints records an APIEndpoint for each path of each request
+// mapping annotation on a handler method, prefixed with its controller's
+// class-level mapping, exposed by the method.
+func (e *extractor) addSpringEndpoints(annotations []string, className, methodName, methodID string, line int) {
+	if len(e.mappings) == 0 || !e.mappings[len(e.mappings)-1].handlers {
+		return
+	}
+	prefix := e.mappings[len(e.mappings)-1].path
+	for _, ann := range annotations {
+		verb, paths, ok := springMapping(ann)
+		if !ok {
+			continue
+		}
+		for _, p := range paths {
+			path := joinSpringPath(prefix, p)
+			endpointName := verb + " " + path
+			endpointID := graph.NewNodeID(string(graph.NodeAPIEndpoint), e.filePath,
+				endpointName+":"+fmt.Sprintf("%d", line))
+
+			e.nodes = append(e.nodes, &graph.Node{
+				ID:       endpointID,
+				Type:     graph.NodeAPIEndpoint,
+				Name:     endpointName,
+				FilePath: e.filePath,
+				Line:     line,
+				Package:  e.pkgName,
+				Language: string(parser.LangJava),
+				Properties: map[string]string{
+					"http_method": verb,
+					"path":        path,
+					"framework":   "spring",
+					"controller":  className,
+					"action":      methodName,
+				},
+			})
+			e.edges = append(e.edges, &graph.Edge{
+				ID:       edgeID(methodID, endpointID, string(graph.EdgeExposes)),
+				Type:     graph.EdgeExposes,
+				SourceID: methodID,
+				TargetID: endpointID,
+			})
+		}
+	}
+}
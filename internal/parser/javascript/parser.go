@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -14,13 +15,20 @@ import (
 )
 
 // JavaScriptParser extracts knowledge graph nodes and edges from JavaScript source files.
-type JavaScriptParser struct{}
+type JavaScriptParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new JavaScript parser.
 func NewParser() *JavaScriptParser {
 	return &JavaScriptParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *JavaScriptParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *JavaScriptParser) Language() parser.Language {
 	return parser.LangJavaScript
 }
@@ -41,17 +49,19 @@ func (p *JavaScriptParser) ParseFile(filePath string, content []byte) (*parser.P
 	defer tree.Close()
 
 	e := &extractor{
-		filePath: filePath,
-		content:  content,
-		root:     tree.RootNode(),
+		filePath:     filePath,
+		content:      content,
+		root:         tree.RootNode(),
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
 	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangJavaScript,
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangJavaScript,
+		Diagnostics: parser.TreeSitterDiagnostics(tree.RootNode(), content),
 	}, nil
 }
 
@@ -66,14 +76,28 @@ type extractor struct {
 	fileNodeID   string
 	moduleNodeID string
 	isTestFile   bool
+	testPatterns *parser.TestPatterns
 
 	// Lookup maps for function call graph extraction, built by buildCallMaps().
 	importNames      map[string]string            // imported module simple name → dep node ID
 	funcNames        map[string]string            // function name → node ID
 	classMethodNames map[string]map[string]string // className → methodName → node ID
+	components       map[string]bool              // node IDs of component=true functions
+	renders          map[string]bool              // Renders edge IDs already emitted
+
+	// callbackOwners maps the start byte of an inline function (route handler,
+	// test case callback, anonymous function node) to the node ID calls made
+	// inside it are attributed to.
+	callbackOwners map[uint32]string
+
+	// routerMiddleware maps a router or app variable to the middleware its
+	// use() calls apply to the routes registered on it afterwards.
+	routerMiddleware map[string][]string
 }
 
 func (e *extractor) extract() {
+	e.callbackOwners = make(map[uint32]string)
+	e.routerMiddleware = make(map[string][]string)
 	e.extractFileNode()
 	e.extractModuleNode()
 	e.walkChildren(e.root)
@@ -86,7 +110,7 @@ func (e *extractor) extract() {
 
 func (e *extractor) extractFileNode() {
 	base := filepath.Base(e.filePath)
-	e.isTestFile = isTestFilename(base)
+	e.isTestFile = isTestFilename(base) || e.testPatterns.MatchesFile(e.filePath)
 
 	fileType := graph.NodeFile
 	if e.isTestFile {
@@ -161,24 +185,7 @@ func (e *extractor) extractImport(node *sitter.Node) {
 	}
 	modulePath := stripQuotes(e.nodeText(source))
 
-	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, modulePath)
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
-		Name:     modulePath,
-		FilePath: e.filePath,
-		Line:     startLine(node),
-		Language: string(parser.LangJavaScript),
-		Properties: map[string]string{
-			"kind": "import",
-		},
-	})
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, depID, string(graph.EdgeImports)),
-		Type:     graph.EdgeImports,
-		SourceID: e.moduleNodeID,
-		TargetID: depID,
-	})
+	e.addImport(modulePath, startLine(node), "")
 }
 
 func (e *extractor) extractExportStatement(node *sitter.Node) {
@@ -212,11 +219,17 @@ func (e *extractor) extractClass(node *sitter.Node, exported bool) {
 				gc := child.Child(j)
 				if gc.Type() == "identifier" || gc.Type() == "member_expression" {
 					props["extends"] = e.nodeText(gc)
+					if parser.IsErrorBase(props["extends"]) {
+						props[parser.PropErrorType] = "true"
+					}
 				}
 			}
 		}
 	}
 
+	doc := parser.LeadingJSDoc(node, e.content)
+	doc.SetProperties(props)
+
 	classID := graph.NewNodeID(string(graph.NodeClass), e.filePath, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            classID,
@@ -228,6 +241,7 @@ func (e *extractor) extractClass(node *sitter.Node, exported bool) {
 		EndLine:       endLine(node),
 		Language:      string(parser.LangJavaScript),
 		Exported:      exported,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
 	e.edges = append(e.edges, &graph.Edge{
@@ -247,8 +261,11 @@ func (e *extractor) extractClass(node *sitter.Node, exported bool) {
 func (e *extractor) extractClassMembers(body *sitter.Node, className, classID string) {
 	for i := 0; i < int(body.ChildCount()); i++ {
 		child := body.Child(i)
-		if child.Type() == "method_definition" {
+		switch child.Type() {
+		case "method_definition":
 			e.extractMethod(child, className, classID)
+		case "field_definition":
+			e.extractFieldFunction(child, className, classID)
 		}
 	}
 }
@@ -258,15 +275,35 @@ func (e *extractor) extractMethod(node *sitter.Node, className, classID string)
 	if nameNode == nil {
 		return
 	}
-	name := e.nodeText(nameNode)
+	e.addMethod(node, node, e.nodeText(nameNode), className, classID)
+}
 
-	sig := e.buildFuncSignature(node, name)
+// extractFieldFunction extracts a class property holding a function, e.g.
+// handleClick = async () => { ... }, as a method of the class.
+func (e *extractor) extractFieldFunction(node *sitter.Node, className, classID string) {
+	nameNode := e.findChildByFieldName(node, "property")
+	valueNode := e.findChildByFieldName(node, "value")
+	if nameNode == nil || valueNode == nil || !isFunctionExpression(valueNode) {
+		return
+	}
+	e.addMethod(node, valueNode, e.nodeText(nameNode), className, classID)
+}
+
+// addMethod adds a method node declared by decl, whose function is fn (the
+// method definition itself, or the value of a class property).
+func (e *extractor) addMethod(decl, fn *sitter.Node, name, className, classID string) {
+	sig := e.buildFuncSignature(fn, name)
 
 	props := make(map[string]string)
 	props["receiver"] = className
-	if e.hasChildWithValue(node, "async") {
+	if e.hasChildWithValue(fn, "async") {
 		props["async"] = "true"
 	}
+	if fn.Type() == "arrow_function" {
+		props["arrow"] = "true"
+	}
+	doc := parser.LeadingJSDoc(decl, e.content)
+	doc.SetProperties(props)
 
 	methodID := graph.NewNodeID(string(graph.NodeMethod), e.filePath, className+"."+name)
 	e.nodes = append(e.nodes, &graph.Node{
@@ -275,10 +312,11 @@ func (e *extractor) extractMethod(node *sitter.Node, className, classID string)
 		Name:          name,
 		QualifiedName: className + "." + name,
 		FilePath:      e.filePath,
-		Line:          startLine(node),
-		EndLine:       endLine(node),
+		Line:          startLine(decl),
+		EndLine:       endLine(decl),
 		Language:      string(parser.LangJavaScript),
 		Signature:     sig,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
 	e.edges = append(e.edges, &graph.Edge{
@@ -307,6 +345,8 @@ func (e *extractor) extractFunction(node *sitter.Node, exported bool) {
 	if e.containsJSXReturn(node) {
 		props["component"] = "true"
 	}
+	doc := parser.LeadingJSDoc(node, e.content)
+	doc.SetProperties(props)
 
 	funcID := graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
 	e.nodes = append(e.nodes, &graph.Node{
@@ -320,6 +360,7 @@ func (e *extractor) extractFunction(node *sitter.Node, exported bool) {
 		Language:      string(parser.LangJavaScript),
 		Exported:      exported,
 		Signature:     sig,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
 	e.edges = append(e.edges, &graph.Edge{
@@ -330,6 +371,24 @@ func (e *extractor) extractFunction(node *sitter.Node, exported bool) {
 	})
 }
 
+// addImport records an import of modulePath; system names the module system
+// ("commonjs" for require()) on the Imports edge when set.
+func (e *extractor) addImport(modulePath string, line int, system string) {
+	imp := parser.Import{
+		Language: parser.LangJavaScript,
+		Name:     modulePath,
+		FilePath: e.filePath,
+		Line:     line,
+		Local:    strings.HasPrefix(modulePath, ".") || strings.HasPrefix(modulePath, "/"),
+	}
+	if system != "" {
+		imp.Properties = map[string]string{"system": system}
+	}
+	dep, edge := imp.Link(e.moduleNodeID)
+	e.nodes = append(e.nodes, dep)
+	e.edges = append(e.edges, edge)
+}
+
 func (e *extractor) extractLexicalDeclaration(node *sitter.Node, exported bool) {
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -339,25 +398,7 @@ func (e *extractor) extractLexicalDeclaration(node *sitter.Node, exported bool)
 			if valueNode != nil && e.isRequireCall(valueNode) {
 				modulePath := e.extractRequireModulePath(valueNode)
 				if modulePath != "" {
-					depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, modulePath)
-					e.nodes = append(e.nodes, &graph.Node{
-						ID:       depID,
-						Type:     graph.NodeDependency,
-						Name:     modulePath,
-						FilePath: e.filePath,
-						Line:     startLine(child),
-						Language: string(parser.LangJavaScript),
-						Properties: map[string]string{
-							"kind":   "import",
-							"system": "commonjs",
-						},
-					})
-					e.edges = append(e.edges, &graph.Edge{
-						ID:       edgeID(e.moduleNodeID, depID, string(graph.EdgeImports)),
-						Type:     graph.EdgeImports,
-						SourceID: e.moduleNodeID,
-						TargetID: depID,
-					})
+					e.addImport(modulePath, startLine(child), "commonjs")
 				}
 				continue
 			}
@@ -386,24 +427,7 @@ func (e *extractor) extractRequireOrDeclarator(node *sitter.Node) {
 	if e.isRequireCall(valueNode) {
 		modulePath := e.extractRequireModulePath(valueNode)
 		if modulePath != "" {
-			depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, modulePath)
-			e.nodes = append(e.nodes, &graph.Node{
-				ID:       depID,
-				Type:     graph.NodeDependency,
-				Name:     modulePath,
-				FilePath: e.filePath,
-				Line:     startLine(node),
-				Language: string(parser.LangJavaScript),
-				Properties: map[string]string{
-					"system": "commonjs",
-				},
-			})
-			e.edges = append(e.edges, &graph.Edge{
-				ID:       edgeID(e.moduleNodeID, depID, string(graph.EdgeImports)),
-				Type:     graph.EdgeImports,
-				SourceID: e.moduleNodeID,
-				TargetID: depID,
-			})
+			e.addImport(modulePath, startLine(node), "commonjs")
 		}
 		return
 	}
@@ -433,8 +457,7 @@ func (e *extractor) extractVariableDeclarator(node *sitter.Node, exported bool)
 		return
 	}
 
-	switch valueNode.Type() {
-	case "arrow_function", "function":
+	if isFunctionExpression(valueNode) {
 		e.extractArrowFunction(node, name, valueNode, exported)
 	}
 }
@@ -449,6 +472,8 @@ func (e *extractor) extractArrowFunction(declNode *sitter.Node, name string, fnN
 	if e.containsJSXReturn(fnNode) {
 		props["component"] = "true"
 	}
+	doc := parser.LeadingJSDoc(declNode, e.content)
+	doc.SetProperties(props)
 
 	funcID := graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
 	e.nodes = append(e.nodes, &graph.Node{
@@ -461,6 +486,7 @@ func (e *extractor) extractArrowFunction(declNode *sitter.Node, name string, fnN
 		EndLine:       endLine(declNode),
 		Language:      string(parser.LangJavaScript),
 		Exported:      exported,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
 	e.edges = append(e.edges, &graph.Edge{
@@ -543,6 +569,9 @@ func (e *extractor) extractTestFunctions(node *sitter.Node) {
 							SourceID: e.moduleNodeID,
 							TargetID: testFuncID,
 						})
+						if fn := e.lastFunctionArg(args); fn != nil {
+							e.callbackOwners[fn.StartByte()] = testFuncID
+						}
 					}
 				}
 			}
@@ -573,14 +602,149 @@ var expressHTTPMethods = map[string]bool{
 
 func (e *extractor) walkAllNodes(node *sitter.Node) {
 	e.checkForExpressRoute(node)
+	e.checkForScheduledJob(node)
+	e.checkForThrow(node)
 	if !e.checkForHTTPClientCall(node) {
 		e.checkForFunctionCall(node)
 	}
+	e.checkForJSXRender(node)
 	for i := 0; i < int(node.ChildCount()); i++ {
 		e.walkAllNodes(node.Child(i))
 	}
 }
 
+// Throw site detection
+
+// checkForThrow records the error class of `throw new PaymentError(...)`
+// in the enclosing function's throws property. Rethrown values and throws
+// outside functions are not recorded.
+func (e *extractor) checkForThrow(node *sitter.Node) {
+	if node.Type() != "throw_statement" {
+		return
+	}
+	var thrown *sitter.Node
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if c := node.NamedChild(i); c.Type() == "new_expression" {
+			thrown = c
+			break
+		}
+	}
+	if thrown == nil {
+		return
+	}
+	ctor := e.findChildByFieldName(thrown, "constructor")
+	if ctor == nil || (ctor.Type() != "identifier" && ctor.Type() != "member_expression") {
+		return
+	}
+	parser.AddThrows(e.nodes, e.findContainingFunctionID(node), e.nodeText(ctor))
+}
+
+// Scheduled job detection
+
+// checkForScheduledJob records node-cron's cron.schedule("* * * * *", fn)
+// and the cron package's new CronJob("* * * * *", fn) as Job nodes that
+// call their handler.
+func (e *extractor) checkForScheduledJob(node *sitter.Node) {
+	var fnNode *sitter.Node
+	framework := ""
+	switch node.Type() {
+	case "call_expression":
+		fnNode = e.findChildByFieldName(node, "function")
+		if fnNode == nil || fnNode.Type() != "member_expression" {
+			return
+		}
+		if prop := e.findChildByFieldName(fnNode, "property"); prop == nil || e.nodeText(prop) != "schedule" {
+			return
+		}
+		framework = "node-cron"
+	case "new_expression":
+		fnNode = e.findChildByFieldName(node, "constructor")
+		if fnNode == nil {
+			return
+		}
+		if name := e.nodeText(fnNode); name != "CronJob" && !strings.HasSuffix(name, ".CronJob") {
+			return
+		}
+		framework = "cron"
+	default:
+		return
+	}
+
+	args := e.findChildByFieldName(node, "arguments")
+	if args == nil {
+		return
+	}
+	var argNodes []*sitter.Node
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		argNodes = append(argNodes, args.NamedChild(i))
+	}
+	if len(argNodes) < 2 || (argNodes[0].Type() != "string" && argNodes[0].Type() != "template_string") {
+		return
+	}
+	schedule := stripQuotes(e.nodeText(argNodes[0]))
+	if !parser.IsCronSchedule(schedule) {
+		return
+	}
+
+	handler := argNodes[1]
+	var handlerName, targetID string
+	switch {
+	case handler.Type() == "identifier" || handler.Type() == "member_expression":
+		handlerName = e.nodeText(handler)
+		localID, importID := e.resolveHandler(handler)
+		targetID = localID
+		if targetID == "" {
+			targetID = importID
+		}
+	case isFunctionExpression(handler):
+		targetID = e.anonymousFunctionID(handler)
+	}
+	name := handlerName
+	if name == "" {
+		name = "cron " + schedule
+	}
+
+	job := parser.NewJobNode(e.filePath, parser.LangJavaScript, parser.Job{
+		Name:      name,
+		Schedule:  schedule,
+		Framework: framework,
+		Handler:   handlerName,
+		Line:      startLine(node),
+	})
+	e.nodes = append(e.nodes, job)
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.moduleNodeID, job.ID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.moduleNodeID,
+		TargetID: job.ID,
+	})
+	if targetID != "" {
+		e.edges = append(e.edges, &graph.Edge{
+			ID:       edgeID(job.ID, targetID, string(graph.EdgeCalls)),
+			Type:     graph.EdgeCalls,
+			SourceID: job.ID,
+			TargetID: targetID,
+		})
+	}
+}
+
+// middlewareNames returns the names of Express middleware arguments: a
+// middleware identifier (requireAuth), member (auth.required), or the
+// function of a middleware factory call (passport.authenticate for
+// passport.authenticate("jwt")). Inline functions are skipped.
+func (e *extractor) middlewareNames(args []*sitter.Node) []string {
+	var names []string
+	for _, arg := range args {
+		if arg.Type() == "call_expression" {
+			arg = e.findChildByFieldName(arg, "function")
+		}
+		if arg != nil && (arg.Type() == "identifier" || arg.Type() == "member_expression") {
+			names = append(names, e.nodeText(arg))
+		}
+	}
+	return names
+}
+
 func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 	if node.Type() != "call_expression" {
 		return
@@ -617,6 +781,13 @@ func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 		return
 	}
 
+	// Check for app.use(requireAuth) — router-wide middleware.
+	if methodName == "use" && argNodes[0].Type() != "string" && argNodes[0].Type() != "template_string" {
+		router := e.nodeText(objectNode)
+		e.routerMiddleware[router] = append(e.routerMiddleware[router], e.middlewareNames(argNodes)...)
+		return
+	}
+
 	// Check for app.use("/prefix", router) — router mount pattern.
 	if methodName == "use" && len(argNodes) >= 2 {
 		firstArg := argNodes[0]
@@ -659,23 +830,41 @@ func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 	}
 	path := stripQuotes(e.nodeText(firstArg))
 
-	// Determine handler name from the last non-path argument.
+	// Determine handler name from the last non-path argument. Handlers
+	// defined in this file (and inline handlers, which get a synthetic function
+	// node) expose the endpoint; imported handlers are linked by a Calls edge
+	// from the endpoint to the import.
 	handlerName := ""
+	exposerID := e.moduleNodeID
+	importID := ""
 	if len(argNodes) >= 2 {
 		lastArg := argNodes[len(argNodes)-1]
 		switch lastArg.Type() {
-		case "identifier":
-			handlerName = e.nodeText(lastArg)
-		case "member_expression":
+		case "identifier", "member_expression":
 			handlerName = e.nodeText(lastArg)
+			var localID string
+			localID, importID = e.resolveHandler(lastArg)
+			if localID != "" {
+				exposerID = localID
+			}
 		default:
 			handlerName = "anonymous"
+			if isFunctionExpression(lastArg) {
+				exposerID = e.anonymousFunctionID(lastArg)
+			}
 		}
 	}
 
+	// Middleware run before the handler: those of the router, then those
+	// passed to the route, as in router.get("/me", requireAuth, getMe).
+	middleware := slices.Clone(e.routerMiddleware[e.nodeText(objectNode)])
+	if len(argNodes) > 2 {
+		middleware = append(middleware, e.middlewareNames(argNodes[1:len(argNodes)-1])...)
+	}
+
 	httpMethod := strings.ToUpper(methodName)
 	endpointID := graph.NewNodeID(string(graph.NodeAPIEndpoint), e.filePath, httpMethod+":"+path)
-	e.nodes = append(e.nodes, &graph.Node{
+	endpoint := &graph.Node{
 		ID:       endpointID,
 		Type:     graph.NodeAPIEndpoint,
 		Name:     httpMethod + " " + path,
@@ -688,13 +877,54 @@ func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 			"framework":   "express",
 			"handler":     handlerName,
 		},
-	})
+	}
+	if len(middleware) > 0 {
+		endpoint.Properties[parser.PropMiddleware] = strings.Join(middleware, ",")
+	}
+	e.nodes = append(e.nodes, endpoint)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, endpointID, string(graph.EdgeExposes)),
+		ID:       edgeID(exposerID, endpointID, string(graph.EdgeExposes)),
 		Type:     graph.EdgeExposes,
-		SourceID: e.moduleNodeID,
+		SourceID: exposerID,
 		TargetID: endpointID,
 	})
+	if importID != "" {
+		e.edges = append(e.edges, &graph.Edge{
+			ID:       edgeID(endpointID, importID, string(graph.EdgeCalls)),
+			Type:     graph.EdgeCalls,
+			SourceID: endpointID,
+			TargetID: importID,
+			Properties: map[string]string{
+				"callee": handlerName,
+			},
+		})
+	}
+}
+
+// resolveHandler resolves a named route handler (handler, Controller.list,
+// users.list) to the function or method defining it in this file, or else to
+// the dependency node of the import it comes from.
+func (e *extractor) resolveHandler(arg *sitter.Node) (localID, importID string) {
+	switch arg.Type() {
+	case "identifier":
+		name := e.nodeText(arg)
+		if id, ok := e.funcNames[name]; ok {
+			return id, ""
+		}
+		return "", e.importNames[name]
+	case "member_expression":
+		objectNode := e.findChildByFieldName(arg, "object")
+		propertyNode := e.findChildByFieldName(arg, "property")
+		if objectNode == nil || propertyNode == nil {
+			return "", ""
+		}
+		objName := e.nodeText(objectNode)
+		if id, ok := e.classMethodNames[objName][e.nodeText(propertyNode)]; ok {
+			return id, ""
+		}
+		return "", e.importNames[objName]
+	}
+	return "", ""
 }
 
 // HTTP client call detection
@@ -787,20 +1017,26 @@ func (e *extractor) checkForHTTPClientCall(node *sitter.Node) bool {
 		return false
 	}
 
+	props := map[string]string{
+		"kind":        "api_call",
+		"http_method": httpMethod,
+		"path":        path,
+		"framework":   framework,
+	}
+	if strings.HasPrefix(path, "*") {
+		if base := e.urlBaseExpr(argNodes[0]); base != "" {
+			props[parser.APICallPropBase] = base
+		}
+	}
 	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, framework+":"+httpMethod+":"+path)
 	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
-		Name:     httpMethod + " " + path,
-		FilePath: e.filePath,
-		Line:     startLine(node),
-		Language: string(parser.LangJavaScript),
-		Properties: map[string]string{
-			"kind":        "api_call",
-			"http_method": httpMethod,
-			"path":        path,
-			"framework":   framework,
-		},
+		ID:         depID,
+		Type:       graph.NodeDependency,
+		Name:       httpMethod + " " + path,
+		FilePath:   e.filePath,
+		Line:       startLine(node),
+		Language:   string(parser.LangJavaScript),
+		Properties: props,
 	})
 
 	// Find containing function and create EdgeCalls.
@@ -855,11 +1091,47 @@ func (e *extractor) extractConcatPath(node *sitter.Node) string {
 	}
 	leftPath := e.extractConcatPath(left)
 	if leftPath == "" {
+		// A dynamic base followed by a path literal: BASE_URL + "/users".
+		right := e.findChildByFieldName(node, "right")
+		if right != nil && right.Type() == "string" {
+			if rightPath := stripQuotes(e.nodeText(right)); strings.HasPrefix(rightPath, "/") {
+				return "*" + rightPath
+			}
+		}
 		return ""
 	}
 	return leftPath + "*"
 }
 
+// urlBaseExpr returns the source text of the dynamic base a URL argument
+// starts with, such as process.env.API_URL in `${process.env.API_URL}/users`
+// or API_URL in API_URL + "/users", or "" when it starts with a literal.
+func (e *extractor) urlBaseExpr(arg *sitter.Node) string {
+	switch arg.Type() {
+	case "template_string":
+		for i := 0; i < int(arg.ChildCount()); i++ {
+			child := arg.Child(i)
+			switch child.Type() {
+			case "`":
+				continue
+			case "template_substitution":
+				text := e.nodeText(child)
+				return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "${"), "}"))
+			}
+			return ""
+		}
+	case "binary_expression":
+		left := arg
+		for left != nil && left.Type() == "binary_expression" {
+			left = e.findChildByFieldName(left, "left")
+		}
+		if left != nil && left.Type() != "string" && left.Type() != "template_string" {
+			return e.nodeText(left)
+		}
+	}
+	return ""
+}
+
 func (e *extractor) extractTemplateLiteralPath(node *sitter.Node) string {
 	var parts []string
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -878,7 +1150,13 @@ func (e *extractor) extractTemplateLiteralPath(node *sitter.Node) string {
 	return strings.Join(parts, "")
 }
 
+// findContainingFunctionID returns the ID of the function the calls at node
+// are attributed to: the nearest named function or method, or the owner of an
+// inline route handler or test callback. Calls in anonymous functions without
+// a named ancestor (IIFEs, module-level callbacks) go to a synthetic node for
+// the outermost one. Returns "" for module-level code.
 func (e *extractor) findContainingFunctionID(node *sitter.Node) string {
+	var anonymous *sitter.Node
 	current := node.Parent()
 	for current != nil {
 		switch current.Type() {
@@ -897,21 +1175,105 @@ func (e *extractor) findContainingFunctionID(node *sitter.Node) string {
 					return graph.NewNodeID(string(graph.NodeMethod), e.filePath, className+"."+methodName)
 				}
 			}
-		case "arrow_function", "function":
-			parent := current.Parent()
-			if parent != nil && parent.Type() == "variable_declarator" {
-				nameNode := e.findChildByFieldName(parent, "name")
-				if nameNode != nil {
-					name := e.nodeText(nameNode)
-					return graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
-				}
+		case "arrow_function", "function", "function_expression":
+			if id, ok := e.callbackOwners[current.StartByte()]; ok {
+				return id
 			}
+			if id := e.boundFunctionID(current); id != "" {
+				return id
+			}
+			anonymous = current
 		}
 		current = current.Parent()
 	}
+	if anonymous != nil {
+		return e.anonymousFunctionID(anonymous)
+	}
+	return ""
+}
+
+// boundFunctionID returns the node ID of a function expression bound to a
+// name: a variable (const foo = () => ...) or a class property
+// (handle = () => ...). Returns "" if the function is anonymous.
+func (e *extractor) boundFunctionID(fn *sitter.Node) string {
+	parent := fn.Parent()
+	if parent == nil {
+		return ""
+	}
+	switch parent.Type() {
+	case "variable_declarator":
+		if nameNode := e.findChildByFieldName(parent, "name"); nameNode != nil {
+			return graph.NewNodeID(string(graph.NodeFunction), e.filePath, e.nodeText(nameNode))
+		}
+	case "field_definition":
+		nameNode := e.findChildByFieldName(parent, "property")
+		className := e.findAncestorClassName(parent)
+		if nameNode != nil && className != "" {
+			return graph.NewNodeID(string(graph.NodeMethod), e.filePath, className+"."+e.nodeText(nameNode))
+		}
+	}
 	return ""
 }
 
+// anonymousFunctionID returns the ID of the synthetic node standing in for an
+// anonymous function, creating it on first use. The node is named after the
+// function's position (anonymous@line:column), which keeps its ID stable
+// across parses of unchanged code.
+func (e *extractor) anonymousFunctionID(fn *sitter.Node) string {
+	if id, ok := e.callbackOwners[fn.StartByte()]; ok {
+		return id
+	}
+
+	name := fmt.Sprintf("anonymous@%d:%d", startLine(fn), fn.StartPoint().Column+1)
+	props := map[string]string{"anonymous": "true"}
+	if fn.Type() == "arrow_function" {
+		props["arrow"] = "true"
+	}
+	if e.hasChildWithValue(fn, "async") {
+		props["async"] = "true"
+	}
+
+	funcID := graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:            funcID,
+		Type:          graph.NodeFunction,
+		Name:          name,
+		QualifiedName: e.filePath + "." + name,
+		FilePath:      e.filePath,
+		Line:          startLine(fn),
+		EndLine:       endLine(fn),
+		Language:      string(parser.LangJavaScript),
+		Properties:    props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.moduleNodeID, funcID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.moduleNodeID,
+		TargetID: funcID,
+	})
+	e.callbackOwners[fn.StartByte()] = funcID
+	return funcID
+}
+
+// lastFunctionArg returns the last argument that is an inline function.
+func (e *extractor) lastFunctionArg(args *sitter.Node) *sitter.Node {
+	for i := int(args.ChildCount()) - 1; i >= 0; i-- {
+		if child := args.Child(i); isFunctionExpression(child) {
+			return child
+		}
+	}
+	return nil
+}
+
+// isFunctionExpression reports whether node is an inline function.
+func isFunctionExpression(node *sitter.Node) bool {
+	switch node.Type() {
+	case "arrow_function", "function", "function_expression":
+		return true
+	}
+	return false
+}
+
 func (e *extractor) findAncestorClassName(node *sitter.Node) string {
 	current := node.Parent()
 	for current != nil {
@@ -942,6 +1304,8 @@ func (e *extractor) buildCallMaps() {
 	e.importNames = make(map[string]string)
 	e.funcNames = make(map[string]string)
 	e.classMethodNames = make(map[string]map[string]string)
+	e.components = make(map[string]bool)
+	e.renders = make(map[string]bool)
 
 	// Build a map from module path to dependency node ID.
 	depByModule := make(map[string]string)
@@ -960,6 +1324,9 @@ func (e *extractor) buildCallMaps() {
 			}
 		case graph.NodeFunction, graph.NodeTestFunction:
 			e.funcNames[n.Name] = n.ID
+			if n.Properties["component"] == "true" {
+				e.components[n.ID] = true
+			}
 		case graph.NodeMethod:
 			if n.Properties != nil && n.Properties["receiver"] != "" {
 				className := n.Properties["receiver"]
@@ -1149,6 +1516,58 @@ func (e *extractor) checkForFunctionCall(node *sitter.Node) {
 	}
 }
 
+// checkForJSXRender emits a Renders edge for each component a component
+// function renders: <Header /> resolves to a function in this file, and
+// <Button /> or <UI.Card> to the dependency node of the import it comes from.
+// Intrinsic elements (<div>) are skipped.
+func (e *extractor) checkForJSXRender(node *sitter.Node) {
+	if node.Type() != "jsx_opening_element" && node.Type() != "jsx_self_closing_element" {
+		return
+	}
+	nameNode := e.findChildByFieldName(node, "name")
+	if nameNode == nil {
+		return
+	}
+	callerID := e.findContainingFunctionID(node)
+	if !e.components[callerID] {
+		return
+	}
+
+	name := e.nodeText(nameNode)
+	var targetID string
+	switch nameNode.Type() {
+	case "identifier":
+		if name == "" || name[0] < 'A' || name[0] > 'Z' {
+			return
+		}
+		if id, ok := e.funcNames[name]; ok {
+			targetID = id
+		} else {
+			targetID = e.importNames[name]
+		}
+	case "member_expression", "nested_identifier":
+		if objectNode := e.findChildByFieldName(nameNode, "object"); objectNode != nil {
+			targetID = e.importNames[e.nodeText(objectNode)]
+		}
+	}
+	if targetID == "" || targetID == callerID {
+		return
+	}
+
+	id := edgeID(callerID, targetID, string(graph.EdgeRenders)+":"+name)
+	if e.renders[id] {
+		return
+	}
+	e.renders[id] = true
+	e.edges = append(e.edges, &graph.Edge{
+		ID:         id,
+		Type:       graph.EdgeRenders,
+		SourceID:   callerID,
+		TargetID:   targetID,
+		Properties: map[string]string{"component": name},
+	})
+}
+
 // lastPathComponent extracts the last segment of a module path.
 // e.g., "./utils" -> "utils", "axios" -> "axios", "@scope/pkg" -> "pkg".
 func lastPathComponent(modulePath string) string {
@@ -1234,5 +1653,5 @@ func stripQuotes(s string) string {
 }
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
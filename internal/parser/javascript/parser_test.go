@@ -3,6 +3,7 @@ package javascript
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"testing"
 
@@ -115,13 +116,19 @@ func TestParseFile(t *testing.T) {
 		}
 	}
 
-	// Verify CommonJS require dependency.
+	// Verify CommonJS require dependency: a shared node, with the module
+	// system on the Imports edge.
 	foundExpress := false
 	for _, n := range result.Nodes {
 		if n.Type == graph.NodeDependency && n.Name == "express" {
 			foundExpress = true
-			if n.Properties["system"] != "commonjs" {
-				t.Error("express dependency should have system=commonjs")
+			if n.ID != graph.NewDependencyID("javascript", "express") || n.FilePath != "" {
+				t.Errorf("express dependency: ID %s, file %q, want the shared node", n.ID, n.FilePath)
+			}
+			for _, e := range result.Edges {
+				if e.Type == graph.EdgeImports && e.TargetID == n.ID && e.Properties["system"] != "commonjs" {
+					t.Error("express import should have system=commonjs")
+				}
 			}
 		}
 	}
@@ -440,6 +447,45 @@ app.use('/admin', adminRouter);
 	}
 }
 
+func TestParseExpressJSMiddleware(t *testing.T) {
+	source := `
+const express = require('express');
+const passport = require('passport');
+
+const router = express.Router();
+router.get('/health', health);
+router.use(requireAuth);
+router.get('/me', getMe);
+router.post('/orders', passport.authenticate('jwt', { session: false }), auth.hasRole('buyer'), createOrder);
+`
+	result, err := NewParser().ParseFile("routes.js", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nodeByName := indexByName(result.Nodes)
+	tests := []struct {
+		endpoint, middleware, handler string
+	}{
+		{"GET /health", "", "health"},
+		{"GET /me", "requireAuth", "getMe"},
+		{"POST /orders", "requireAuth,passport.authenticate,auth.hasRole", "createOrder"},
+	}
+	for _, tt := range tests {
+		n, ok := nodeByName[tt.endpoint]
+		if !ok {
+			t.Errorf("endpoint %q not found", tt.endpoint)
+			continue
+		}
+		if got := n.Properties[parser.PropMiddleware]; got != tt.middleware {
+			t.Errorf("%s middleware = %q, want %q", tt.endpoint, got, tt.middleware)
+		}
+		if got := n.Properties["handler"]; got != tt.handler {
+			t.Errorf("%s handler = %q, want %q", tt.endpoint, got, tt.handler)
+		}
+	}
+}
+
 func TestParseExpressJSRoutesFromFile(t *testing.T) {
 	_, thisFile, _, ok := runtime.Caller(0)
 	if !ok {
@@ -674,6 +720,161 @@ class ApiService {
 	}
 }
 
+func TestExpressNamedHandlerLinking(t *testing.T) {
+	source := `
+import express from 'express';
+import { createUser } from './handlers';
+import * as orders from './orders';
+
+const router = express.Router();
+
+function listUsers(req, res) { res.json([]); }
+const getUser = async (req, res) => { res.json({}); };
+
+class UserController {
+  static remove(req, res) { res.sendStatus(204); }
+}
+
+router.get('/users', listUsers);
+router.get('/users/:id', auth, getUser);
+router.delete('/users/:id', UserController.remove);
+router.post('/users', createUser);
+router.get('/orders', orders.list);
+router.put('/users/:id', unknownHandler);
+`
+	p := NewParser()
+	result, err := p.ParseFile("routes.js", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nameByID := make(map[string]string, len(result.Nodes))
+	for _, n := range result.Nodes {
+		nameByID[n.ID] = n.Name
+	}
+	edges := make(map[string]string)
+	for _, e := range result.Edges {
+		switch e.Type {
+		case graph.EdgeExposes:
+			edges[nameByID[e.TargetID]] = "exposed by " + nameByID[e.SourceID]
+		case graph.EdgeCalls:
+			if e.Properties["callee"] != "" {
+				edges[nameByID[e.SourceID]] = "calls " + nameByID[e.TargetID] + " (" + e.Properties["callee"] + ")"
+			}
+		}
+	}
+
+	tests := map[string]string{
+		"GET /users":        "exposed by listUsers",
+		"GET /users/:id":    "exposed by getUser",
+		"DELETE /users/:id": "exposed by remove",
+		"POST /users":       "calls ./handlers (createUser)",
+		"GET /orders":       "calls ./orders (orders.list)",
+		"PUT /users/:id":    "exposed by routes.js",
+	}
+	for endpoint, want := range tests {
+		if got := edges[endpoint]; got != want {
+			t.Errorf("%s: got %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestInlineFunctionCallAttribution(t *testing.T) {
+	source := `
+(function () { fetch('/api/boot'); })();
+
+app.get('/orders', async (req, res) => {
+  const items = await fetch('/api/inventory');
+  return items.map((i) => helper(i));
+});
+
+function registerRoutes(router) {
+  router.post('/pay', function (req, res) { fetch('/api/charge'); });
+  setTimeout(() => fetch('/api/ping'), 10);
+}
+
+class Widget {
+  load = async () => { await fetch('/api/widgets'); };
+  render() { this.load(); }
+}
+
+function helper(x) { return x; }
+`
+	p := NewParser()
+	result, err := p.ParseFile("app.js", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nameByID := make(map[string]string, len(result.Nodes))
+	for _, n := range result.Nodes {
+		nameByID[n.ID] = n.Name
+	}
+	edges := make(map[string]bool)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls || e.Type == graph.EdgeExposes {
+			edges[string(e.Type)+" "+nameByID[e.SourceID]+" -> "+nameByID[e.TargetID]] = true
+		}
+	}
+
+	tests := []string{
+		"Calls anonymous@2:2 -> UNKNOWN /api/boot",
+		"Exposes anonymous@4:20 -> GET /orders",
+		"Calls anonymous@4:20 -> UNKNOWN /api/inventory",
+		"Calls anonymous@4:20 -> helper",
+		"Exposes anonymous@10:23 -> POST /pay",
+		"Calls anonymous@10:23 -> UNKNOWN /api/charge",
+		"Calls registerRoutes -> UNKNOWN /api/ping",
+		"Calls load -> UNKNOWN /api/widgets",
+		"Calls render -> load",
+	}
+	for _, want := range tests {
+		if !edges[want] {
+			t.Errorf("missing edge %q", want)
+		}
+	}
+
+	nodeByName := indexByName(result.Nodes)
+	if n := nodeByName["anonymous@4:20"]; n == nil || n.Properties["anonymous"] != "true" || n.Properties["async"] != "true" {
+		t.Errorf("anonymous handler node = %+v", n)
+	}
+	if n := nodeByName["load"]; n == nil || n.Type != graph.NodeMethod || n.Properties["receiver"] != "Widget" {
+		t.Errorf("class property method = %+v", n)
+	}
+	if n := nodeByName["anonymous@11:14"]; n != nil {
+		t.Errorf("callback inside a named function should not get its own node: %+v", n)
+	}
+}
+
+func TestTestCallbackCallAttribution(t *testing.T) {
+	source := `
+describe('orders', () => {
+  it('lists orders', async () => {
+    await fetch('/api/orders');
+  });
+});
+`
+	p := NewParser()
+	result, err := p.ParseFile("orders.test.js", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	nodeByName := indexByName(result.Nodes)
+	test, call := nodeByName["lists orders"], nodeByName["UNKNOWN /api/orders"]
+	if test == nil || call == nil {
+		t.Fatal("expected test function and api_call nodes")
+	}
+	found := false
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && e.SourceID == test.ID && e.TargetID == call.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected Calls edge from the test case to the api call")
+	}
+}
+
 func TestExtractFunctionCalls(t *testing.T) {
 	_, thisFile, _, ok := runtime.Caller(0)
 	if !ok {
@@ -1029,3 +1230,187 @@ func indexByName(nodes []*graph.Node) map[string]*graph.Node {
 	}
 	return m
 }
+
+func TestJSXRenders(t *testing.T) {
+	source := `
+import Button from './Button';
+import * as UI from '@acme/ui';
+
+function Header() {
+  return <h1>Shop</h1>;
+}
+
+export const App = ({ children }) => (
+  <main>
+    <Header />
+    <Button label="Buy" />
+    <UI.Card>{children}</UI.Card>
+  </main>
+);
+`
+	p := NewParser()
+	result, err := p.ParseFile("src/App.jsx", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	ids := make(map[string]string) // node ID -> name
+	for _, n := range result.Nodes {
+		ids[n.ID] = n.Name
+	}
+	got := make(map[string]string) // component -> target name
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeRenders && ids[e.SourceID] == "App" {
+			got[e.Properties["component"]] = ids[e.TargetID]
+		}
+	}
+	want := map[string]string{"Header": "Header", "Button": "./Button", "UI.Card": "@acme/ui"}
+	if len(got) != len(want) {
+		t.Errorf("Renders edges = %v, want %v", got, want)
+	}
+	for component, target := range want {
+		if got[component] != target {
+			t.Errorf("Renders %s -> %q, want %q", component, got[component], target)
+		}
+	}
+}
+
+func TestScheduledJobs(t *testing.T) {
+	source := `
+const cron = require('node-cron');
+const { CronJob } = require('cron');
+
+function cleanup() {}
+
+cron.schedule('*/5 * * * *', cleanup);
+new CronJob('0 3 * * MON-FRI', () => {});
+cron.schedule('not a schedule', cleanup);
+`
+	result, err := NewParser().ParseFile("jobs.js", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	byID := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		byID[n.ID] = n
+	}
+	calls := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && byID[e.SourceID] != nil && byID[e.SourceID].Type == graph.NodeJob {
+			calls[e.SourceID] = e.TargetID
+		}
+	}
+
+	type job struct{ schedule, framework, handler string }
+	want := map[string]job{
+		"cleanup":              {"*/5 * * * *", "node-cron", "cleanup"},
+		"cron 0 3 * * MON-FRI": {"0 3 * * MON-FRI", "cron", ""},
+	}
+	got := make(map[string]job)
+	for _, n := range result.Nodes {
+		if n.Type != graph.NodeJob {
+			continue
+		}
+		got[n.Name] = job{n.Properties[parser.JobPropSchedule], n.Properties[parser.JobPropFramework], n.Properties[parser.JobPropHandler]}
+		target := byID[calls[n.ID]]
+		if target == nil {
+			t.Errorf("Job %s has no Calls edge to a node in the file", n.Name)
+		} else if n.Name == "cleanup" && target.Name != "cleanup" {
+			t.Errorf("Job %s calls %s, want cleanup", n.Name, target.Name)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d jobs %v, want %d", len(got), got, len(want))
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("Job %q = %+v, want %+v", name, got[name], w)
+		}
+	}
+}
+
+func TestErrorSites(t *testing.T) {
+	source := `
+class PaymentDeclinedError extends Error {}
+
+function charge(amount) {
+  if (amount < 0) { throw new PaymentDeclinedError("negative"); }
+  throw new Error("unreachable");
+}
+`
+	result, err := NewParser().ParseFile("src/payments.js", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	wantThrows := map[string]string{"charge": "PaymentDeclinedError,Error"}
+	wantErrorTypes := map[string]bool{"PaymentDeclinedError": true}
+	gotThrows := make(map[string]string)
+	gotErrorTypes := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if throws := n.Properties[parser.PropThrows]; throws != "" {
+			gotThrows[n.Name] = throws
+		}
+		if n.Properties[parser.PropErrorType] == "true" {
+			gotErrorTypes[n.Name] = true
+		}
+	}
+	if !reflect.DeepEqual(gotThrows, wantThrows) {
+		t.Errorf("throws = %v, want %v", gotThrows, wantThrows)
+	}
+	if !reflect.DeepEqual(gotErrorTypes, wantErrorTypes) {
+		t.Errorf("error types = %v, want %v", gotErrorTypes, wantErrorTypes)
+	}
+}
+
+func TestJSDoc(t *testing.T) {
+	source := `
+/**
+ * Charges a card.
+ * @param {number} amount - in cents
+ * @param {Object} [opts={}] options
+ * @return {Promise<Receipt>} the receipt
+ */
+async function charge(amount, opts) {}
+
+/** A payment client. */
+class PaymentClient {
+  /** Refunds a charge. @param id ignored, not at the start of a line */
+  refund(id) {}
+}
+
+/**
+ * Logs a line.
+ * @param {string} line
+ */
+const log = (line) => console.log(line);
+
+module.exports = { charge, PaymentClient, log };
+`
+	result, err := NewParser().ParseFile("src/payments.js", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		params  string
+		returns string
+	}{
+		{"charge", "Charges a card.\n@param {number} amount - in cents\n@param {Object} [opts={}] options\n@return {Promise<Receipt>} the receipt", "amount {number}: in cents\nopts {Object}: options", "{Promise<Receipt>} the receipt"},
+		{"PaymentClient", "A payment client.", "", ""},
+		{"refund", "Refunds a charge. @param id ignored, not at the start of a line", "", ""},
+		{"log", "Logs a line.\n@param {string} line", "line {string}", ""},
+	}
+	nodeByName := indexByName(result.Nodes)
+	for _, tt := range tests {
+		n, ok := nodeByName[tt.name]
+		if !ok {
+			t.Fatalf("expected %s node", tt.name)
+		}
+		if n.DocComment != tt.doc || n.Properties["doc_params"] != tt.params || n.Properties["doc_returns"] != tt.returns {
+			t.Errorf("%s doc = %q, params = %q, returns = %q; want %q, %q, %q",
+				tt.name, n.DocComment, n.Properties["doc_params"], n.Properties["doc_returns"], tt.doc, tt.params, tt.returns)
+		}
+	}
+}
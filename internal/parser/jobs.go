@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Job node properties.
+const (
+	// JobPropSchedule is the job's schedule: a cron expression ("0 3 * * *"),
+	// a descriptor ("@every 5m", "@daily"), or an interval such as
+	// "fixedRate=5000".
+	JobPropSchedule = "schedule"
+	// JobPropFramework is the scheduler that runs the job (e.g.
+	// "kubernetes", "spring", "node-cron", "sidekiq-cron", "robfig/cron").
+	JobPropFramework = "framework"
+	// JobPropHandler is the name of the function, method or class the job
+	// runs. The linker resolves it when the parser could not link the job.
+	JobPropHandler = "handler"
+)
+
+// Job is a scheduled job found by a parser.
+type Job struct {
+	// Name identifies the job within its file.
+	Name      string
+	Schedule  string
+	Framework string
+	Handler   string
+	Line      int
+	// Properties holds extra properties (e.g. the container image of a
+	// Kubernetes CronJob).
+	Properties map[string]string
+}
+
+// NewJobNode returns the Job node for j. Its ID is derived from the file,
+// name and line, so a file may define several jobs with the same name.
+func NewJobNode(filePath string, lang Language, j Job) *graph.Node {
+	props := make(map[string]string, len(j.Properties)+3)
+	for k, v := range j.Properties {
+		props[k] = v
+	}
+	props[JobPropFramework] = j.Framework
+	if j.Schedule != "" {
+		props[JobPropSchedule] = j.Schedule
+	}
+	if j.Handler != "" {
+		props[JobPropHandler] = j.Handler
+	}
+	return &graph.Node{
+		ID:         graph.NewNodeID(string(graph.NodeJob), filePath, fmt.Sprintf("%s:%d", j.Name, j.Line)),
+		Type:       graph.NodeJob,
+		Name:       j.Name,
+		FilePath:   filePath,
+		Line:       j.Line,
+		Language:   string(lang),
+		Properties: props,
+	}
+}
+
+// IsCronSchedule reports whether s looks like a cron expression (five to
+// seven fields) or a cron descriptor such as "@hourly" or "@every 1h".
+func IsCronSchedule(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "@") {
+		switch name, _, _ := strings.Cut(s, " "); name {
+		case "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly", "@every", "@reboot":
+			return true
+		}
+		return false
+	}
+	fields := strings.Fields(s)
+	if len(fields) < 5 || len(fields) > 7 {
+		return false
+	}
+	for _, f := range fields {
+		if strings.Trim(f, "0123456789*/,-?LW#") != "" && !isCronName(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// cronNames are the month and weekday names cron fields may use.
+var cronNames = map[string]bool{
+	"JAN": true, "FEB": true, "MAR": true, "APR": true, "MAY": true, "JUN": true,
+	"JUL": true, "AUG": true, "SEP": true, "OCT": true, "NOV": true, "DEC": true,
+	"SUN": true, "MON": true, "TUE": true, "WED": true, "THU": true, "FRI": true, "SAT": true,
+}
+
+// isCronName reports whether a cron field uses month or weekday names
+// ("JAN", "MON-FRI").
+func isCronName(f string) bool {
+	for _, part := range strings.FieldsFunc(f, func(r rune) bool { return r == ',' || r == '-' || r == '/' }) {
+		if strings.Trim(part, "0123456789*?LW#") != "" && !cronNames[strings.ToUpper(part)] {
+			return false
+		}
+	}
+	return true
+}
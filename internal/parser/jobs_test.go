@@ -0,0 +1,29 @@
+package parser
+
+import "testing"
+
+func TestIsCronSchedule(t *testing.T) {
+	tests := []struct {
+		spec string
+		want bool
+	}{
+		{"0 3 * * *", true},
+		{"*/5 * * * *", true},
+		{"0 0 3 * * *", true},
+		{"0 0 9 ? * MON-FRI", true},
+		{"0 3 * JAN,JUL sun", true},
+		{"@daily", true},
+		{"@every 1h30m", true},
+		{"", false},
+		{"@weird", false},
+		{"not a schedule", false},
+		{"* * *", false},
+		{"0 3 * * * * * *", false},
+		{"/api/users/:id", false},
+	}
+	for _, tt := range tests {
+		if got := IsCronSchedule(tt.spec); got != tt.want {
+			t.Errorf("IsCronSchedule(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
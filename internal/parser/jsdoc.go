@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// JSDoc is a /** */ documentation comment of JavaScript or TypeScript code,
+// in JSDoc or TSDoc style.
+type JSDoc struct {
+	// Text is the comment without its delimiters and leading asterisks,
+	// block tags included.
+	Text string
+	// Params are the @param tags, in order.
+	Params []JSDocParam
+	// Returns is the description of the @returns tag, and ReturnType the
+	// type given in braces, if any.
+	Returns    string
+	ReturnType string
+}
+
+// JSDocParam is one @param tag. Type is empty when the tag gives none, as
+// TSDoc tags do.
+type JSDocParam struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// LeadingJSDoc returns the JSDoc comment documenting the declaration decl:
+// the /** */ comment right before it, before the export statement or
+// lexical declaration wrapping it, or before its decorators. It returns
+// the zero JSDoc when there is none.
+func LeadingJSDoc(decl *sitter.Node, content []byte) JSDoc {
+	node := decl
+	for parent := node.Parent(); parent != nil; parent = node.Parent() {
+		switch {
+		case parent.Type() == "export_statement":
+		case node.Type() == "variable_declarator" && (parent.Type() == "lexical_declaration" || parent.Type() == "variable_declaration"):
+			if parent.NamedChild(0) != node {
+				return JSDoc{} // the comment documents the first declarator
+			}
+		default:
+			return jsDocBefore(node, content)
+		}
+		node = parent
+	}
+	return jsDocBefore(node, content)
+}
+
+// jsDocBefore parses the /** */ comment preceding node, skipping its
+// decorators.
+func jsDocBefore(node *sitter.Node, content []byte) JSDoc {
+	prev := node.PrevSibling()
+	for prev != nil && prev.Type() == "decorator" {
+		prev = prev.PrevSibling()
+	}
+	if prev == nil || prev.Type() != "comment" {
+		return JSDoc{}
+	}
+	text := prev.Content(content)
+	if !strings.HasPrefix(text, "/**") || text == "/**/" {
+		return JSDoc{}
+	}
+	return ParseJSDoc(text)
+}
+
+// ParseJSDoc parses the text of a /** */ comment. Lines of a block tag's
+// description may continue on the following lines.
+func ParseJSDoc(raw string) JSDoc {
+	s := strings.TrimPrefix(raw, "/**")
+	s = strings.TrimSuffix(s, "*/")
+
+	var doc JSDoc
+	var lines []string
+	var tag *string // description being continued
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			tag = nil
+			continue
+		}
+		lines = append(lines, line)
+
+		name, rest, _ := strings.Cut(line, " ")
+		switch name {
+		case "@param", "@arg", "@argument":
+			typ, rest := jsDocType(rest)
+			pname, desc, _ := strings.Cut(rest, " ")
+			pname = strings.TrimSuffix(strings.TrimPrefix(pname, "["), "]")
+			pname, _, _ = strings.Cut(pname, "=") // [name=default]
+			if pname == "" {
+				tag = nil
+				continue
+			}
+			doc.Params = append(doc.Params, JSDocParam{Name: pname, Type: typ, Description: jsDocDescription(desc)})
+			tag = &doc.Params[len(doc.Params)-1].Description
+		case "@returns", "@return":
+			doc.ReturnType, rest = jsDocType(rest)
+			doc.Returns = jsDocDescription(rest)
+			tag = &doc.Returns
+		default:
+			if strings.HasPrefix(line, "@") {
+				tag = nil
+			} else if tag != nil {
+				*tag = strings.TrimSpace(*tag + " " + line)
+			}
+		}
+	}
+	doc.Text = strings.Join(lines, "\n")
+	return doc
+}
+
+// jsDocType splits a leading {type} off s.
+func jsDocType(s string) (typ, rest string) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") {
+		return "", s
+	}
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(s[1:i]), strings.TrimSpace(s[i+1:])
+			}
+		}
+	}
+	return "", s
+}
+
+// jsDocDescription drops the hyphen that may separate a tag's name from its
+// description.
+func jsDocDescription(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.TrimSpace(strings.TrimPrefix(s, "- "))
+}
+
+// SetProperties records the @param and @returns tags in props:
+// "doc_params" holds one "name {type}: description" line per parameter,
+// without the parts the tag leaves out, and "doc_returns" the return
+// description, prefixed with its braced type when given.
+func (d JSDoc) SetProperties(props map[string]string) {
+	if len(d.Params) > 0 {
+		lines := make([]string, 0, len(d.Params))
+		for _, p := range d.Params {
+			line := p.Name
+			if p.Type != "" {
+				line += " {" + p.Type + "}"
+			}
+			if p.Description != "" {
+				line += ": " + p.Description
+			}
+			lines = append(lines, line)
+		}
+		props["doc_params"] = strings.Join(lines, "\n")
+	}
+	returns := d.Returns
+	if d.ReturnType != "" {
+		returns = strings.TrimSpace("{" + d.ReturnType + "} " + returns)
+	}
+	if returns != "" {
+		props["doc_returns"] = returns
+	}
+}
@@ -0,0 +1,312 @@
+package manifest
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// Maven and Gradle dependencies are named "group:artifact" and carry the
+// group, artifact, and (resolved) version as properties, so the linker can
+// match Java/Kotlin imports against the group's package prefix.
+
+// addMavenDependency adds a manifest_dep for group:artifact.
+func (e *extractor) addMavenDependency(group, artifact, version string, line int) *graph.Node {
+	name := artifact
+	if group != "" {
+		name = group + ":" + artifact
+	}
+	dep := e.addDependencyNode(name, version, line)
+	if group != "" {
+		dep.Properties["group"] = group
+	}
+	dep.Properties["artifact"] = artifact
+	return dep
+}
+
+// --- Maven ---
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Optional   string `xml:"optional"`
+}
+
+type pomFile struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"parent"`
+	Properties           pomProperties   `xml:"properties"`
+	Modules              []string        `xml:"modules>module"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+}
+
+// pomProperties collects the free-form <properties> children.
+type pomProperties map[string]string
+
+func (p *pomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = make(pomProperties)
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+var pomPropertyRe = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// interpolate expands ${...} references from the POM properties and project
+// coordinates. Unresolved references yield "".
+func (pom *pomFile) interpolate(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	for range 5 { // properties may reference other properties
+		resolved := true
+		s = pomPropertyRe.ReplaceAllStringFunc(s, func(ref string) string {
+			key := ref[2 : len(ref)-1]
+			switch key {
+			case "project.version", "pom.version", "version":
+				return pom.version()
+			case "project.groupId", "pom.groupId":
+				return pom.groupID()
+			case "project.artifactId":
+				return pom.ArtifactID
+			case "project.parent.version":
+				return pom.Parent.Version
+			}
+			if v, ok := pom.Properties[key]; ok {
+				return v
+			}
+			resolved = false
+			return ref
+		})
+		if !strings.Contains(s, "${") {
+			return s
+		}
+		if !resolved {
+			break
+		}
+	}
+	return ""
+}
+
+func (pom *pomFile) groupID() string {
+	if pom.GroupID != "" {
+		return pom.GroupID
+	}
+	return pom.Parent.GroupID
+}
+
+func (pom *pomFile) version() string {
+	if pom.Version != "" {
+		return pom.Version
+	}
+	return pom.Parent.Version
+}
+
+func parsePomXml(filePath string, content []byte) (*parser.ParseResult, error) {
+	var pom pomFile
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return nil, err
+	}
+
+	e := &extractor{filePath: filePath, ecosystem: "maven"}
+	e.addFileNode()
+
+	groupID := pom.interpolate(pom.groupID())
+	name := pom.ArtifactID
+	if name == "" {
+		name = filepath.Base(filepath.Dir(filePath))
+	}
+	e.addServiceNode(name, pom.interpolate(pom.version()))
+	if groupID != "" && pom.ArtifactID != "" {
+		e.nodes[len(e.nodes)-1].Properties["maven_coordinates"] = groupID + ":" + pom.ArtifactID
+	}
+
+	// Versions omitted in <dependencies> come from <dependencyManagement>.
+	managed := make(map[string]string)
+	for _, d := range pom.DependencyManagement {
+		managed[pom.interpolate(d.GroupID)+":"+d.ArtifactID] = pom.interpolate(d.Version)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for _, d := range pom.Dependencies {
+		if d.ArtifactID == "" {
+			continue
+		}
+		group := pom.interpolate(d.GroupID)
+		version := pom.interpolate(d.Version)
+		if version == "" {
+			version = managed[group+":"+d.ArtifactID]
+		}
+		dep := e.addMavenDependency(group, d.ArtifactID, version, findLine(lines, "<artifactId>"+d.ArtifactID+"</artifactId>"))
+		if d.Scope != "" && d.Scope != "compile" {
+			dep.Properties["scope"] = d.Scope
+		}
+		if strings.TrimSpace(d.Optional) == "true" {
+			dep.Properties["optional"] = "true"
+		}
+	}
+
+	if members := cleanMembers(pom.Modules); len(members) > 0 {
+		e.addWorkspaceNode("maven", members)
+	}
+	return e.result(), nil
+}
+
+// --- Gradle ---
+
+var (
+	// gradleDepRe matches `implementation "g:a:v"`, `api("g:a")`, and
+	// `implementation project(":libs:core")` (Groovy and Kotlin DSL).
+	gradleDepRe = regexp.MustCompile(`(?m)^\s*(\w+)\s*\(?\s*(?:project\s*\(\s*(?:path\s*[:=]\s*)?["'](:[^"']*)["']\s*\)|["']([^"':\s]+):([^"':\s]+)(?::([^"'\s]+))?["'])`)
+	// gradleMapDepRe matches the map notation:
+	// `implementation group: 'g', name: 'a', version: 'v'`.
+	gradleMapDepRe = regexp.MustCompile(`(?m)^\s*(\w+)\s*\(?\s*group\s*[:=]\s*["']([^"']+)["']\s*,\s*name\s*[:=]\s*["']([^"']+)["'](?:\s*,\s*version\s*[:=]\s*["']([^"']+)["'])?`)
+	// gradleVarRe matches simple string assignments used for versions and
+	// coordinates: `def x = '1'`, `val x = "1"`, `ext.x = '1'`, `x = '1'` inside
+	// ext {}, `extra["x"] = "1"`, and `group = 'com.acme'`.
+	gradleVarRe = regexp.MustCompile(`(?m)^\s*(?:(?:def|val|var)\s+|ext\.|project\.ext\.|extra\[["'])?([A-Za-z_][\w.]*?)(?:["']\])?\s*=\s*["']([^"'$]*)["']`)
+	// gradleIncludeRe matches include statements; the quoted project paths
+	// are extracted from the rest of the line.
+	gradleIncludeRe = regexp.MustCompile(`(?m)^\s*include\b(.*)$`)
+	gradleQuotedRe  = regexp.MustCompile(`["']([^"']+)["']`)
+	gradleRefRe     = regexp.MustCompile(`\$\{?([A-Za-z_][\w.]*)\}?`)
+)
+
+// gradleConfigurations are the dependency configurations recorded, mapped to
+// the scope property ("" for main).
+var gradleConfigurations = map[string]string{
+	"implementation": "", "api": "", "compile": "", "compileOnly": "provided",
+	"runtimeOnly": "runtime", "annotationProcessor": "provided", "kapt": "provided",
+	"testImplementation": "test", "testCompileOnly": "test", "testRuntimeOnly": "test",
+	"androidTestImplementation": "test",
+}
+
+// gradleVars collects the string variables of a build script.
+func gradleVars(text string) map[string]string {
+	vars := make(map[string]string)
+	for _, m := range gradleVarRe.FindAllStringSubmatch(text, -1) {
+		vars[m[1]] = m[2]
+		if i := strings.LastIndex(m[1], "."); i >= 0 {
+			vars[m[1][i+1:]] = m[2] // rootProject.ext.x is referenced as $x
+		}
+	}
+	return vars
+}
+
+// gradleInterpolate expands $x and ${x} references. Unresolved references
+// yield "".
+func gradleInterpolate(s string, vars map[string]string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	resolved := true
+	s = gradleRefRe.ReplaceAllStringFunc(s, func(ref string) string {
+		key := strings.Trim(ref, "${}")
+		if v, ok := vars[key]; ok {
+			return v
+		}
+		resolved = false
+		return ref
+	})
+	if !resolved {
+		return ""
+	}
+	return s
+}
+
+func parseBuildGradle(filePath string, content []byte) (*parser.ParseResult, error) {
+	text := string(content)
+	vars := gradleVars(text)
+
+	e := &extractor{filePath: filePath, ecosystem: "maven"}
+	e.addFileNode()
+	project := filepath.Base(filepath.Dir(filePath))
+	e.addServiceNode(project, gradleInterpolate(vars["version"], vars))
+	if group := gradleInterpolate(vars["group"], vars); group != "" {
+		e.nodes[len(e.nodes)-1].Properties["maven_coordinates"] = group + ":" + project
+	}
+
+	lineAt := func(offset int) int { return strings.Count(text[:offset], "\n") + 1 }
+	for _, m := range gradleDepRe.FindAllStringSubmatchIndex(text, -1) {
+		scope, ok := gradleConfigurations[text[m[2]:m[3]]]
+		if !ok {
+			continue
+		}
+		var dep *graph.Node
+		if m[4] >= 0 {
+			project := text[m[4]:m[5]]
+			dep = e.addDependencyNode(project, "", lineAt(m[0]))
+			dep.Properties["internal"] = "true"
+			dep.Properties["gradle_project"] = project
+		} else {
+			version := ""
+			if m[10] >= 0 {
+				// Drop a classifier: "g:a:v:classifier".
+				version, _, _ = strings.Cut(text[m[10]:m[11]], ":")
+			}
+			dep = e.addMavenDependency(text[m[6]:m[7]], text[m[8]:m[9]], gradleInterpolate(version, vars), lineAt(m[0]))
+		}
+		if scope != "" {
+			dep.Properties["scope"] = scope
+		}
+	}
+	for _, m := range gradleMapDepRe.FindAllStringSubmatchIndex(text, -1) {
+		scope, ok := gradleConfigurations[text[m[2]:m[3]]]
+		if !ok {
+			continue
+		}
+		version := ""
+		if m[8] >= 0 {
+			version = gradleInterpolate(text[m[8]:m[9]], vars)
+		}
+		dep := e.addMavenDependency(text[m[4]:m[5]], text[m[6]:m[7]], version, lineAt(m[0]))
+		if scope != "" {
+			dep.Properties["scope"] = scope
+		}
+	}
+	return e.result(), nil
+}
+
+func parseSettingsGradle(filePath string, content []byte) (*parser.ParseResult, error) {
+	e := &extractor{filePath: filePath, ecosystem: "maven"}
+	e.addFileNode()
+
+	var members []string
+	for _, m := range gradleIncludeRe.FindAllStringSubmatch(string(content), -1) {
+		for _, q := range gradleQuotedRe.FindAllStringSubmatch(m[1], -1) {
+			// ":libs:core" lives in libs/core by default.
+			members = append(members, strings.ReplaceAll(strings.TrimPrefix(q[1], ":"), ":", "/"))
+		}
+	}
+	if members = cleanMembers(members); len(members) > 0 {
+		e.addWorkspaceNode("gradle", members)
+	}
+	return e.result(), nil
+}
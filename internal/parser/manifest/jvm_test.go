@@ -0,0 +1,141 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestParsePomXmlVersions(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <groupId>com.acme</groupId>
+  <artifactId>orders</artifactId>
+  <version>1.4.0</version>
+  <properties>
+    <spring.version>6.1.2</spring.version>
+    <jackson.version>2.16.1</jackson.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>com.fasterxml.jackson.core</groupId>
+        <artifactId>jackson-databind</artifactId>
+        <version>${jackson.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework</groupId>
+      <artifactId>spring-web</artifactId>
+      <version>${spring.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>orders-api</artifactId>
+      <version>${project.version}</version>
+      <optional>true</optional>
+    </dependency>
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>mystery</artifactId>
+      <version>${undefined.version}</version>
+    </dependency>
+  </dependencies>
+</project>`
+
+	p := NewParser()
+	result, err := p.ParseFile("orders/pom.xml", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	byName := indexByName(result.Nodes)
+
+	tests := []struct {
+		name, group, artifact, version string
+	}{
+		{"org.springframework:spring-web", "org.springframework", "spring-web", "6.1.2"},
+		{"com.fasterxml.jackson.core:jackson-databind", "com.fasterxml.jackson.core", "jackson-databind", "2.16.1"},
+		{"com.acme:orders-api", "com.acme", "orders-api", "1.4.0"},
+		{"org.example:mystery", "org.example", "mystery", ""},
+	}
+	for _, tt := range tests {
+		d := byName[tt.name]
+		if d == nil {
+			t.Errorf("missing dependency %s", tt.name)
+			continue
+		}
+		if d.Type != graph.NodeDependency || d.Properties["kind"] != "manifest_dep" || d.Properties["ecosystem"] != "maven" {
+			t.Errorf("%s: unexpected node %+v", tt.name, d)
+		}
+		if d.Properties["group"] != tt.group || d.Properties["artifact"] != tt.artifact || d.Properties["version"] != tt.version {
+			t.Errorf("%s: group/artifact/version = %q/%q/%q, want %q/%q/%q", tt.name,
+				d.Properties["group"], d.Properties["artifact"], d.Properties["version"], tt.group, tt.artifact, tt.version)
+		}
+	}
+	if byName["com.acme:orders-api"].Properties["optional"] != "true" {
+		t.Error("expected orders-api to be optional")
+	}
+	if svc := byName["orders"]; svc == nil || svc.Properties["version"] != "1.4.0" || svc.Properties["maven_coordinates"] != "com.acme:orders" {
+		t.Errorf("service = %+v", svc)
+	}
+}
+
+func TestParseBuildGradleVersions(t *testing.T) {
+	content := `plugins {
+    id 'java'
+}
+
+group = 'com.acme'
+version = '0.3.0'
+
+ext {
+    jacksonVersion = '2.16.1'
+}
+def springVersion = "6.1.2"
+
+dependencies {
+    implementation "org.springframework:spring-web:$springVersion"
+    implementation "com.fasterxml.jackson.core:jackson-databind:${jacksonVersion}"
+    implementation group: 'org.apache.commons', name: 'commons-lang3', version: '3.14.0'
+    runtimeOnly 'org.postgresql:postgresql:42.7.1:jdk8'
+    compileOnly "org.example:unresolved:$missing"
+}
+`
+	p := NewParser()
+	result, err := p.ParseFile("billing/build.gradle", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	byName := indexByName(result.Nodes)
+
+	tests := []struct {
+		name, artifact, version, scope string
+		line                           int
+	}{
+		{"org.springframework:spring-web", "spring-web", "6.1.2", "", 14},
+		{"com.fasterxml.jackson.core:jackson-databind", "jackson-databind", "2.16.1", "", 15},
+		{"org.apache.commons:commons-lang3", "commons-lang3", "3.14.0", "", 16},
+		{"org.postgresql:postgresql", "postgresql", "42.7.1", "runtime", 17},
+		{"org.example:unresolved", "unresolved", "", "provided", 18},
+	}
+	for _, tt := range tests {
+		d := byName[tt.name]
+		if d == nil {
+			t.Errorf("missing dependency %s", tt.name)
+			continue
+		}
+		if d.Properties["artifact"] != tt.artifact || d.Properties["version"] != tt.version ||
+			d.Properties["scope"] != tt.scope || d.Line != tt.line {
+			t.Errorf("%s = %+v (line %d)", tt.name, d.Properties, d.Line)
+		}
+	}
+	if svc := byName["billing"]; svc == nil || svc.Properties["version"] != "0.3.0" || svc.Properties["maven_coordinates"] != "com.acme:billing" {
+		t.Errorf("service = %+v", svc)
+	}
+}
@@ -13,7 +13,10 @@ import (
 )
 
 // ManifestParser extracts knowledge graph nodes and edges from project manifest
-// files (pyproject.toml, requirements.txt, package.json, go.mod).
+// files (pyproject.toml, requirements.txt, package.json, go.mod, Cargo.toml,
+// pom.xml, build.gradle), workspace definitions (go.work,
+// pnpm-workspace.yaml, settings.gradle), and TypeScript path aliases
+// (tsconfig.json, jsconfig.json).
 type ManifestParser struct{}
 
 // NewParser creates a new manifest file parser.
@@ -30,7 +33,12 @@ func (p *ManifestParser) Extensions() []string {
 }
 
 func (p *ManifestParser) Filenames() []string {
-	return []string{"pyproject.toml", "requirements.txt", "setup.py", "package.json", "go.mod"}
+	return []string{
+		"pyproject.toml", "requirements.txt", "setup.py", "package.json", "go.mod",
+		"go.work", "pnpm-workspace.yaml", "Cargo.toml", "pom.xml",
+		"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts",
+		"tsconfig.json", "tsconfig.base.json", "jsconfig.json",
+	}
 }
 
 func (p *ManifestParser) ParseFile(filePath string, content []byte) (*parser.ParseResult, error) {
@@ -44,6 +52,20 @@ func (p *ManifestParser) ParseFile(filePath string, content []byte) (*parser.Par
 		return parsePackageJson(filePath, content)
 	case "go.mod":
 		return parseGoMod(filePath, content)
+	case "go.work":
+		return parseGoWork(filePath, content)
+	case "pnpm-workspace.yaml":
+		return parsePnpmWorkspace(filePath, content)
+	case "Cargo.toml":
+		return parseCargoToml(filePath, content)
+	case "pom.xml":
+		return parsePomXml(filePath, content)
+	case "build.gradle", "build.gradle.kts":
+		return parseBuildGradle(filePath, content)
+	case "settings.gradle", "settings.gradle.kts":
+		return parseSettingsGradle(filePath, content)
+	case "tsconfig.json", "tsconfig.base.json", "jsconfig.json":
+		return parseTsconfig(filePath, content)
 	default:
 		return &parser.ParseResult{FilePath: filePath, Language: parser.LangManifest}, nil
 	}
@@ -146,6 +168,8 @@ type packageJsonFile struct {
 	Version         string            `json:"version"`
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
+	// Workspaces is a list of globs (npm, yarn) or {"packages": [...]} (yarn).
+	Workspaces json.RawMessage `json:"workspaces"`
 }
 
 func parsePackageJson(filePath string, content []byte) (*parser.ParseResult, error) {
@@ -167,12 +191,18 @@ func parsePackageJson(filePath string, content []byte) (*parser.ParseResult, err
 
 	for name, version := range pj.Dependencies {
 		line := findLine(lines, name)
-		e.addDependencyNode(name, version, line)
+		dep := e.addDependencyNode(name, version, line)
+		markLocalNpmDep(dep, version)
 	}
 	for name, version := range pj.DevDependencies {
 		line := findLine(lines, name)
 		dep := e.addDependencyNode(name, version, line)
 		dep.Properties["scope"] = "dev"
+		markLocalNpmDep(dep, version)
+	}
+
+	if members := npmWorkspaces(pj.Workspaces); len(members) > 0 {
+		e.addWorkspaceNode("npm", members)
 	}
 
 	return e.result(), nil
@@ -338,7 +368,7 @@ func (e *extractor) result() *parser.ParseResult {
 }
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
 
 // findLine searches for the first line containing substr (1-indexed). Returns 0 if not found.
@@ -264,11 +264,22 @@ func TestLanguageAndFilenames(t *testing.T) {
 
 	filenames := p.Filenames()
 	expected := map[string]bool{
-		"pyproject.toml":   true,
-		"requirements.txt": true,
-		"setup.py":         true,
-		"package.json":     true,
-		"go.mod":           true,
+		"pyproject.toml":      true,
+		"requirements.txt":    true,
+		"setup.py":            true,
+		"package.json":        true,
+		"go.mod":              true,
+		"go.work":             true,
+		"pnpm-workspace.yaml": true,
+		"Cargo.toml":          true,
+		"pom.xml":             true,
+		"build.gradle":        true,
+		"build.gradle.kts":    true,
+		"settings.gradle":     true,
+		"settings.gradle.kts": true,
+		"tsconfig.json":       true,
+		"tsconfig.base.json":  true,
+		"jsconfig.json":       true,
 	}
 	if len(filenames) != len(expected) {
 		t.Errorf("Filenames() has %d entries, want %d", len(filenames), len(expected))
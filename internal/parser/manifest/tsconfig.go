@@ -0,0 +1,156 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// tsconfig.json / jsconfig.json module resolution settings become a Module
+// node (kind=tsconfig) so the linker can resolve aliased imports such as
+// "@app/shared/utils" to files in the repository. Paths are stored relative
+// to the repository root:
+//
+//	base_url  the baseUrl directory, if set
+//	paths     "pattern=target1|target2" entries, comma-separated, with the
+//	          targets resolved against baseUrl (or the config's directory)
+
+type tsconfigFile struct {
+	CompilerOptions struct {
+		BaseURL string              `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+func parseTsconfig(filePath string, content []byte) (*parser.ParseResult, error) {
+	var tc tsconfigFile
+	if err := json.Unmarshal(stripJSONC(content), &tc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Base(filePath), err)
+	}
+
+	e := &extractor{filePath: filePath, ecosystem: "nodejs"}
+	e.addFileNode()
+
+	dir := filepath.ToSlash(filepath.Dir(filePath))
+	props := map[string]string{"kind": "tsconfig"}
+	base := dir
+	if tc.CompilerOptions.BaseURL != "" {
+		base = path.Join(dir, filepath.ToSlash(tc.CompilerOptions.BaseURL))
+		props["base_url"] = base
+	}
+
+	patterns := make([]string, 0, len(tc.CompilerOptions.Paths))
+	for pattern := range tc.CompilerOptions.Paths {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	var entries []string
+	for _, pattern := range patterns {
+		var targets []string
+		for _, t := range tc.CompilerOptions.Paths[pattern] {
+			targets = append(targets, path.Join(base, filepath.ToSlash(t)))
+		}
+		if len(targets) > 0 {
+			entries = append(entries, pattern+"="+strings.Join(targets, "|"))
+		}
+	}
+	if len(entries) > 0 {
+		props["paths"] = strings.Join(entries, ",")
+	}
+
+	id := graph.NewNodeID(string(graph.NodeModule), filePath, "tsconfig")
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:         id,
+		Type:       graph.NodeModule,
+		Name:       dir,
+		FilePath:   filePath,
+		Line:       1,
+		Language:   string(parser.LangManifest),
+		Properties: props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.fileNodeID, id, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.fileNodeID,
+		TargetID: id,
+	})
+
+	return e.result(), nil
+}
+
+// stripJSONC removes the comments and trailing commas tsconfig files allow
+// so the content can be decoded as plain JSON.
+func stripJSONC(content []byte) []byte {
+	return stripTrailingCommas(stripComments(content))
+}
+
+func stripComments(content []byte) []byte {
+	out := make([]byte, 0, len(content))
+	inString := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				out = append(out, content[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i+1 < len(content) && content[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(string(content[i+2:]), "*/")
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func stripTrailingCommas(content []byte) []byte {
+	out := make([]byte, 0, len(content))
+	inString := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case inString:
+			out = append(out, c)
+			if c == '\\' && i+1 < len(content) {
+				i++
+				out = append(out, content[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		case c == '"':
+			inString = true
+		case c == ',':
+			j := i + 1
+			for j < len(content) && strings.ContainsRune(" \t\r\n", rune(content[j])) {
+				j++
+			}
+			if j < len(content) && (content[j] == '}' || content[j] == ']') {
+				continue
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestParseTsconfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		content     string
+		wantBaseURL string
+		wantPaths   string
+	}{
+		{
+			name: "baseUrl with comments and trailing commas",
+			path: "apps/web/tsconfig.json",
+			content: `{
+  // Compiler settings
+  "compilerOptions": {
+    "baseUrl": "./src", /* resolved against this file */
+    "paths": {
+      "@app/*": ["app/*", "generated/*",],
+      "@env": ["env/index.ts"], // exact alias
+    },
+  },
+}`,
+			wantBaseURL: "apps/web/src",
+			wantPaths:   "@app/*=apps/web/src/app/*|apps/web/src/generated/*,@env=apps/web/src/env/index.ts",
+		},
+		{
+			name:      "paths without baseUrl",
+			path:      "tsconfig.base.json",
+			content:   `{"compilerOptions": {"paths": {"@acme/shared/*": ["libs/shared/src/*"], "http://x": ["./y"]}}}`,
+			wantPaths: "@acme/shared/*=libs/shared/src/*,http://x=y",
+		},
+		{
+			name:    "no resolution settings",
+			path:    "jsconfig.json",
+			content: `{"compilerOptions": {"checkJs": true}}`,
+		},
+	}
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.ParseFile(tt.path, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseFile: %v", err)
+			}
+			var cfg *graph.Node
+			for _, n := range result.Nodes {
+				if n.Type == graph.NodeModule && n.Properties["kind"] == "tsconfig" {
+					cfg = n
+				}
+			}
+			if cfg == nil {
+				t.Fatal("expected a tsconfig Module node")
+			}
+			if got := cfg.Properties["base_url"]; got != tt.wantBaseURL {
+				t.Errorf("base_url = %q, want %q", got, tt.wantBaseURL)
+			}
+			if got := cfg.Properties["paths"]; got != tt.wantPaths {
+				t.Errorf("paths = %q, want %q", got, tt.wantPaths)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package manifest
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// Workspace definitions become a Module node (kind=workspace) recording the
+// build tool and member globs relative to the workspace directory. The linker
+// expands the members against the Service nodes of their own manifests.
+//
+// Dependencies declared as local (npm "workspace:"/"file:"/"link:" versions,
+// Cargo path dependencies, Gradle project() dependencies) are marked
+// internal=true so they aren't treated as third-party packages.
+
+// addWorkspaceNode adds the workspace Module node for this file.
+func (e *extractor) addWorkspaceNode(tool string, members []string) {
+	dir := filepath.ToSlash(filepath.Dir(e.filePath))
+	id := graph.NewNodeID(string(graph.NodeModule), e.filePath, "workspace")
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:       id,
+		Type:     graph.NodeModule,
+		Name:     dir,
+		FilePath: e.filePath,
+		Line:     1,
+		Language: string(parser.LangManifest),
+		Properties: map[string]string{
+			"kind":    "workspace",
+			"tool":    tool,
+			"members": strings.Join(members, ","),
+		},
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.fileNodeID, id, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.fileNodeID,
+		TargetID: id,
+	})
+}
+
+// cleanMembers normalizes member paths ("./a/" -> "a") and drops exclusions
+// ("!packages/legacy") and duplicates.
+func cleanMembers(members []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range members {
+		m = strings.TrimSpace(m)
+		if m == "" || strings.HasPrefix(m, "!") {
+			continue
+		}
+		m = path.Clean(filepath.ToSlash(m))
+		if !seen[m] {
+			seen[m] = true
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// --- npm / yarn / pnpm ---
+
+// npmWorkspaces decodes package.json "workspaces".
+func npmWorkspaces(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) != nil {
+		var obj struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(raw, &obj) != nil {
+			return nil
+		}
+		list = obj.Packages
+	}
+	return cleanMembers(list)
+}
+
+// markLocalNpmDep flags dependencies resolved from the workspace or the
+// local filesystem rather than the registry.
+func markLocalNpmDep(dep *graph.Node, version string) {
+	for _, prefix := range []string{"workspace:", "file:", "link:", "portal:"} {
+		if strings.HasPrefix(version, prefix) {
+			dep.Properties["internal"] = "true"
+			if prefix != "workspace:" {
+				dep.Properties["path"] = strings.TrimPrefix(version, prefix)
+			}
+			return
+		}
+	}
+}
+
+func parsePnpmWorkspace(filePath string, content []byte) (*parser.ParseResult, error) {
+	var ws struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(content, &ws); err != nil {
+		return nil, err
+	}
+	e := &extractor{filePath: filePath, ecosystem: "nodejs"}
+	e.addFileNode()
+	if members := cleanMembers(ws.Packages); len(members) > 0 {
+		e.addWorkspaceNode("pnpm", members)
+	}
+	return e.result(), nil
+}
+
+// --- go.work ---
+
+func parseGoWork(filePath string, content []byte) (*parser.ParseResult, error) {
+	e := &extractor{filePath: filePath, ecosystem: "go"}
+	e.addFileNode()
+
+	var members []string
+	inUse := false
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if i := strings.Index(trimmed, "//"); i >= 0 {
+			trimmed = strings.TrimSpace(trimmed[:i])
+		}
+		switch {
+		case trimmed == "use (":
+			inUse = true
+		case inUse && trimmed == ")":
+			inUse = false
+		case inUse && trimmed != "":
+			members = append(members, strings.Trim(trimmed, `"`))
+		case strings.HasPrefix(trimmed, "use "):
+			members = append(members, strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "use ")), `"`))
+		}
+	}
+	if members = cleanMembers(members); len(members) > 0 {
+		e.addWorkspaceNode("go", members)
+	}
+	return e.result(), nil
+}
+
+// --- Cargo.toml ---
+
+func parseCargoToml(filePath string, content []byte) (*parser.ParseResult, error) {
+	var cf struct {
+		Package struct {
+			Name    string `toml:"name"`
+			Version any    `toml:"version"` // string, or {workspace = true}
+		} `toml:"package"`
+		Dependencies      map[string]any `toml:"dependencies"`
+		DevDependencies   map[string]any `toml:"dev-dependencies"`
+		BuildDependencies map[string]any `toml:"build-dependencies"`
+		Workspace         *struct {
+			Members []string `toml:"members"`
+		} `toml:"workspace"`
+	}
+	if err := toml.Unmarshal(content, &cf); err != nil {
+		return nil, err
+	}
+
+	e := &extractor{filePath: filePath, ecosystem: "rust"}
+	e.addFileNode()
+
+	// A virtual workspace manifest has no [package].
+	if cf.Package.Name != "" {
+		version, _ := cf.Package.Version.(string)
+		e.addServiceNode(cf.Package.Name, version)
+
+		lines := strings.Split(string(content), "\n")
+		for _, section := range []struct {
+			deps  map[string]any
+			scope string
+		}{{cf.Dependencies, ""}, {cf.DevDependencies, "dev"}, {cf.BuildDependencies, "build"}} {
+			for _, name := range sortedKeys(section.deps) {
+				dep := e.addDependencyNode(name, "", findLine(lines, name))
+				switch spec := section.deps[name].(type) {
+				case string:
+					dep.Properties["version"] = spec
+				case map[string]any:
+					if v, ok := spec["version"].(string); ok {
+						dep.Properties["version"] = v
+					}
+					if p, ok := spec["path"].(string); ok {
+						dep.Properties["internal"] = "true"
+						dep.Properties["path"] = p
+					}
+					if pkg, ok := spec["package"].(string); ok {
+						dep.Properties["package"] = pkg // renamed dependency
+					}
+				}
+				if section.scope != "" {
+					dep.Properties["scope"] = section.scope
+				}
+			}
+		}
+	}
+
+	if cf.Workspace != nil {
+		if members := cleanMembers(cf.Workspace.Members); len(members) > 0 {
+			e.addWorkspaceNode("cargo", members)
+		}
+	}
+	return e.result(), nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,163 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func workspaceNode(t *testing.T, nodes []*graph.Node) *graph.Node {
+	t.Helper()
+	for _, n := range nodes {
+		if n.Type == graph.NodeModule && n.Properties["kind"] == "workspace" {
+			return n
+		}
+	}
+	t.Fatal("expected a workspace Module node")
+	return nil
+}
+
+func TestParseWorkspaces(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		content     string
+		wantTool    string
+		wantMembers string
+	}{
+		{"npm array", "package.json", `{"name": "root", "private": true, "workspaces": ["packages/*", "./apps/web/"]}`, "npm", "packages/*,apps/web"},
+		{"yarn object", "package.json", `{"name": "root", "workspaces": {"packages": ["libs/**", "!libs/old"]}}`, "npm", "libs/**"},
+		{"pnpm", "pnpm-workspace.yaml", "packages:\n  - 'packages/*'\n  - '!**/test/**'\n", "pnpm", "packages/*"},
+		{"go.work", "go.work", "go 1.22\n\nuse (\n\t./api // service\n\t./lib\n)\nuse ./tools\n", "go", "api,lib,tools"},
+		{"cargo", "Cargo.toml", "[workspace]\nmembers = [\"crates/*\", \"cli\"]\n", "cargo", "crates/*,cli"},
+		{"maven", "pom.xml", "<project><groupId>com.acme</groupId><artifactId>parent</artifactId><modules><module>core</module><module>web</module></modules></project>", "maven", "core,web"},
+		{"gradle", "settings.gradle.kts", "rootProject.name = \"shop\"\ninclude(\":core\", \":libs:util\")\ninclude 'app'\n", "gradle", "core,libs/util,app"},
+	}
+	p := NewParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.ParseFile(tt.path, []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseFile: %v", err)
+			}
+			ws := workspaceNode(t, result.Nodes)
+			if ws.Properties["tool"] != tt.wantTool || ws.Properties["members"] != tt.wantMembers {
+				t.Errorf("workspace = %s %q, want %s %q", ws.Properties["tool"], ws.Properties["members"], tt.wantTool, tt.wantMembers)
+			}
+			if countEdgeType(result.Edges, graph.EdgeContains) == 0 {
+				t.Error("expected the file to contain the workspace node")
+			}
+		})
+	}
+}
+
+func TestParseWorkspaceDependencies(t *testing.T) {
+	p := NewParser()
+
+	t.Run("npm workspace protocol", func(t *testing.T) {
+		result, err := p.ParseFile("packages/web/package.json", []byte(`{"name": "@acme/web",
+			"dependencies": {"@acme/ui": "workspace:^", "react": "^18.2.0", "local": "file:../local"}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		byName := indexByName(result.Nodes)
+		if byName["@acme/ui"].Properties["internal"] != "true" || byName["react"].Properties["internal"] != "" {
+			t.Errorf("internal flags: ui=%q react=%q", byName["@acme/ui"].Properties["internal"], byName["react"].Properties["internal"])
+		}
+		if byName["local"].Properties["path"] != "../local" {
+			t.Errorf("file: dependency path = %q", byName["local"].Properties["path"])
+		}
+	})
+
+	t.Run("cargo package", func(t *testing.T) {
+		content := `[package]
+name = "api"
+version = "0.3.0"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+tokio = "1"
+core-lib = { path = "../core" }
+
+[dev-dependencies]
+mockall = "0.12"
+`
+		result, err := p.ParseFile("crates/api/Cargo.toml", []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts := countByType(result.Nodes)
+		assertCount(t, counts, graph.NodeService, 1)
+		assertCount(t, counts, graph.NodeDependency, 4)
+		byName := indexByName(result.Nodes)
+		if svc := byName["api"]; svc == nil || svc.Properties["version"] != "0.3.0" || svc.Properties["ecosystem"] != "rust" {
+			t.Errorf("service = %+v", svc)
+		}
+		if d := byName["serde"]; d.Properties["version"] != "1.0" || d.Line != 6 {
+			t.Errorf("serde = %+v (line %d)", d.Properties, d.Line)
+		}
+		if d := byName["core-lib"]; d.Properties["internal"] != "true" || d.Properties["path"] != "../core" {
+			t.Errorf("core-lib = %+v", d.Properties)
+		}
+		if byName["mockall"].Properties["scope"] != "dev" {
+			t.Errorf("mockall scope = %q", byName["mockall"].Properties["scope"])
+		}
+	})
+
+	t.Run("maven module", func(t *testing.T) {
+		content := `<project>
+  <parent><groupId>com.acme</groupId><artifactId>parent</artifactId><version>2.1.0</version></parent>
+  <artifactId>web</artifactId>
+  <dependencies>
+    <dependency><groupId>com.acme</groupId><artifactId>core</artifactId><version>${project.version}</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`
+		result, err := p.ParseFile("web/pom.xml", []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		byName := indexByName(result.Nodes)
+		svc := byName["web"]
+		if svc == nil || svc.Properties["maven_coordinates"] != "com.acme:web" || svc.Properties["version"] != "2.1.0" {
+			t.Fatalf("service = %+v", svc)
+		}
+		if d := byName["junit:junit"]; d == nil || d.Properties["scope"] != "test" || d.Line != 6 {
+			t.Errorf("junit = %+v", d)
+		}
+		if byName["com.acme:core"] == nil {
+			t.Error("expected com.acme:core dependency")
+		}
+	})
+
+	t.Run("gradle build", func(t *testing.T) {
+		content := `plugins { id("java") }
+dependencies {
+    implementation(project(":libs:util"))
+    implementation "com.google.guava:guava:33.0-jre"
+    testImplementation("org.junit.jupiter:junit-jupiter:5.10.0")
+    classpath "not:a:dependency"
+}
+`
+		result, err := p.ParseFile("app/build.gradle.kts", []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		byName := indexByName(result.Nodes)
+		if byName["app"] == nil {
+			t.Fatal("expected service named after the project directory")
+		}
+		if d := byName[":libs:util"]; d == nil || d.Properties["internal"] != "true" || d.Properties["gradle_project"] != ":libs:util" {
+			t.Errorf("project dependency = %+v", d)
+		}
+		if d := byName["com.google.guava:guava"]; d == nil || d.Properties["version"] != "33.0-jre" || d.Line != 4 {
+			t.Errorf("guava = %+v", d)
+		}
+		if d := byName["org.junit.jupiter:junit-jupiter"]; d == nil || d.Properties["scope"] != "test" {
+			t.Errorf("junit = %+v", d)
+		}
+		if byName["not:a"] != nil {
+			t.Error("classpath entries are not project dependencies")
+		}
+	})
+}
@@ -0,0 +1,301 @@
+// Package nginx extracts reverse proxy routes from nginx configuration.
+package nginx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// NginxParser extracts proxied location blocks from nginx configuration
+// files as route Config nodes.
+type NginxParser struct{}
+
+// NewParser creates a new nginx parser.
+func NewParser() *NginxParser {
+	return &NginxParser{}
+}
+
+func (p *NginxParser) Language() parser.Language {
+	return parser.LangNginx
+}
+
+func (p *NginxParser) Extensions() []string {
+	return parser.FileExtensions[parser.LangNginx]
+}
+
+// Filenames covers the main config and the default site of the official
+// Docker image, including its envsubst templates.
+func (p *NginxParser) Filenames() []string {
+	return []string{"nginx.conf", "default.conf", "nginx.conf.template", "default.conf.template"}
+}
+
+func (p *NginxParser) ParseFile(filePath string, content []byte) (*parser.ParseResult, error) {
+	e := &extractor{filePath: filePath}
+	e.extractFileNode()
+
+	root, err := parse(string(content))
+	if err != nil {
+		// Keep the file node; routes are best effort.
+		return &parser.ParseResult{
+			Nodes:    e.nodes,
+			FilePath: filePath,
+			Language: parser.LangNginx,
+			Diagnostics: []parser.Diagnostic{{
+				Line:    err.line,
+				Message: err.msg,
+			}},
+		}, nil
+	}
+
+	e.upstreams = make(map[string]string)
+	e.collectUpstreams(root)
+	e.extractLocations(root)
+
+	return &parser.ParseResult{
+		Nodes:    e.nodes,
+		Edges:    e.edges,
+		FilePath: filePath,
+		Language: parser.LangNginx,
+	}, nil
+}
+
+// directive is one nginx directive, with its block if it has one.
+type directive struct {
+	name  string
+	args  []string
+	line  int
+	block []*directive
+}
+
+type syntaxError struct {
+	line int
+	msg  string
+}
+
+// parse tokenizes content and builds the directive tree.
+func parse(content string) ([]*directive, *syntaxError) {
+	type frame struct {
+		siblings []*directive
+		owner    *directive
+	}
+	var stack []frame
+	var cur []*directive
+	var pending *directive
+	line := 1
+	for _, tok := range tokenize(content) {
+		line = tok.line
+		punct := !tok.quoted && len(tok.text) == 1 && strings.Contains(";{}", tok.text)
+		switch {
+		case punct && tok.text == ";":
+			if pending != nil {
+				cur = append(cur, pending)
+				pending = nil
+			}
+		case punct && tok.text == "{":
+			if pending == nil {
+				return nil, &syntaxError{tok.line, "block without a directive"}
+			}
+			stack = append(stack, frame{siblings: cur, owner: pending})
+			cur, pending = nil, nil
+		case punct && tok.text == "}":
+			if len(stack) == 0 {
+				return nil, &syntaxError{tok.line, `unexpected "}"`}
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			top.owner.block = cur
+			cur, pending = append(top.siblings, top.owner), nil
+		case pending == nil:
+			pending = &directive{name: tok.text, line: tok.line}
+		default:
+			pending.args = append(pending.args, tok.text)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, &syntaxError{line, "unclosed block"}
+	}
+	return cur, nil
+}
+
+type token struct {
+	text   string
+	line   int
+	quoted bool
+}
+
+// tokenize splits nginx configuration into words, quoted strings and the
+// ";", "{" and "}" punctuation, dropping comments.
+func tokenize(s string) []token {
+	var toks []token
+	line := 1
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == ';' || c == '{' || c == '}':
+			toks = append(toks, token{text: string(c), line: line})
+			i++
+		case c == '"' || c == '\'':
+			start, startLine := i+1, line
+			i++
+			for i < len(s) && s[i] != c {
+				if s[i] == '\\' {
+					i++
+				} else if s[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			toks = append(toks, token{text: s[start:min(i, len(s))], line: startLine, quoted: true})
+			i++
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\r\n;{}#", rune(s[i])) {
+				i++
+			}
+			toks = append(toks, token{text: s[start:i], line: line})
+		}
+	}
+	return toks
+}
+
+type extractor struct {
+	filePath   string
+	nodes      []*graph.Node
+	edges      []*graph.Edge
+	fileNodeID string
+	// upstreams maps upstream block names to their first server's host.
+	upstreams map[string]string
+}
+
+func (e *extractor) extractFileNode() {
+	e.fileNodeID = graph.NewNodeID(string(graph.NodeFile), e.filePath, e.filePath)
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:       e.fileNodeID,
+		Type:     graph.NodeFile,
+		Name:     e.filePath,
+		FilePath: e.filePath,
+		Language: string(parser.LangNginx),
+	})
+}
+
+func (e *extractor) collectUpstreams(dirs []*directive) {
+	for _, d := range dirs {
+		if d.name == "upstream" && len(d.args) == 1 {
+			for _, s := range d.block {
+				if s.name == "server" && len(s.args) > 0 {
+					e.upstreams[d.args[0]] = parser.UpstreamHost(s.args[0])
+					break
+				}
+			}
+			continue
+		}
+		e.collectUpstreams(d.block)
+	}
+}
+
+// extractLocations records every prefix location that proxies requests.
+// Regex and named locations are skipped since they have no fixed prefix.
+func (e *extractor) extractLocations(dirs []*directive) {
+	for _, d := range dirs {
+		if d.name == "location" {
+			if prefix, ok := locationPrefix(d.args); ok {
+				e.addLocationRoute(d, prefix)
+			}
+		}
+		e.extractLocations(d.block)
+	}
+}
+
+// locationPrefix returns the path a location matches for the plain, "="
+// and "^~" forms.
+func locationPrefix(args []string) (string, bool) {
+	switch {
+	case len(args) == 1 && strings.HasPrefix(args[0], "/"):
+		return args[0], true
+	case len(args) == 2 && (args[0] == "=" || args[0] == "^~"):
+		return args[1], true
+	}
+	return "", false
+}
+
+// rewritePattern matches the common "rewrite ^/prefix/(.*)$ /new/$1" form.
+var rewritePattern = regexp.MustCompile(`^\^(/[^()*+?\[\]{}|\\$]*)\(\.\*\)\$?$`)
+
+func (e *extractor) addLocationRoute(loc *directive, prefix string) {
+	var target string
+	var rewriteFrom, rewriteTo string
+	for _, d := range loc.block {
+		switch d.name {
+		case "proxy_pass", "grpc_pass":
+			if len(d.args) > 0 {
+				target = d.args[0]
+			}
+		case "rewrite":
+			if len(d.args) >= 2 {
+				if m := rewritePattern.FindStringSubmatch(d.args[0]); m != nil && strings.HasSuffix(d.args[1], "$1") {
+					rewriteFrom, rewriteTo = m[1], strings.TrimSuffix(d.args[1], "$1")
+				}
+			}
+		}
+	}
+	if target == "" {
+		return
+	}
+	host := parser.UpstreamHost(target)
+	if host == "" || strings.ContainsAny(host, "$:") {
+		return
+	}
+	if server, ok := e.upstreams[host]; ok {
+		host = server
+	}
+
+	// A URI on proxy_pass replaces the matched prefix; without one the path
+	// is forwarded unchanged. A rewrite in the location takes precedence.
+	rewrite := prefix
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		if i := strings.Index(rest, "/"); i >= 0 && !strings.Contains(rest[i:], "$") {
+			rewrite = rest[i:]
+		}
+	}
+	if rewriteFrom != "" && strings.HasPrefix(rewriteFrom, strings.TrimRight(prefix, "/")) {
+		prefix, rewrite = rewriteFrom, rewriteTo
+	}
+
+	prefix = parser.RoutePrefix(prefix)
+	cfgID := graph.NewNodeID(string(graph.NodeConfig), e.filePath,
+		parser.ConfigKindRoute+":"+prefix+":"+fmt.Sprint(loc.line))
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:       cfgID,
+		Type:     graph.NodeConfig,
+		Name:     prefix,
+		FilePath: e.filePath,
+		Line:     loc.line,
+		Language: string(parser.LangNginx),
+		Properties: map[string]string{
+			"kind":                   parser.ConfigKindRoute,
+			"source":                 "nginx",
+			parser.RoutePropPrefix:   prefix,
+			parser.RoutePropRewrite:  parser.RoutePrefix(rewrite),
+			parser.RoutePropUpstream: host,
+		},
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       graph.NewEdgeID(graph.EdgeConfigures, e.fileNodeID, cfgID),
+		Type:     graph.EdgeConfigures,
+		SourceID: e.fileNodeID,
+		TargetID: cfgID,
+	})
+}
@@ -0,0 +1,132 @@
+package nginx
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+const testConfig = `
+upstream api_pool {
+    server backend:8080 weight=2;
+    server backend-2:8080;
+}
+
+server {
+    listen 80;
+    server_name example.com;
+
+    # Static frontend.
+    location / {
+        root /usr/share/nginx/html;
+    }
+
+    location /api/ {
+        proxy_pass http://api_pool/;
+    }
+
+    location ^~ /auth {
+        proxy_pass http://auth-svc.default.svc.cluster.local:9000;
+    }
+
+    location /orders/ {
+        rewrite ^/orders/(.*)$ /v2/orders/$1 break;
+        proxy_pass http://orders:3000;
+    }
+
+    location ~ \.php$ {
+        proxy_pass http://php;
+    }
+
+    location /dynamic/ {
+        proxy_pass http://$upstream_host;
+    }
+
+    location @fallback {
+        proxy_pass http://backend;
+    }
+}
+`
+
+func TestParseRoutes(t *testing.T) {
+	result, err := NewParser().ParseFile("deploy/nginx.conf", []byte(testConfig))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if result.Language != parser.LangNginx {
+		t.Errorf("Language = %q, want %q", result.Language, parser.LangNginx)
+	}
+
+	routes := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeConfig && n.Properties["kind"] == parser.ConfigKindRoute {
+			routes[n.Name] = n
+		}
+	}
+
+	tests := []struct {
+		prefix   string
+		rewrite  string
+		upstream string
+	}{
+		{"/api", "/", "backend"},
+		{"/auth", "/auth", "auth-svc"},
+		{"/orders", "/v2/orders", "orders"},
+	}
+	if len(routes) != len(tests) {
+		t.Errorf("got %d routes, want %d: %v", len(routes), len(tests), routes)
+	}
+	for _, tc := range tests {
+		r := routes[tc.prefix]
+		if r == nil {
+			t.Errorf("missing route %s", tc.prefix)
+			continue
+		}
+		if got := r.Properties[parser.RoutePropRewrite]; got != tc.rewrite {
+			t.Errorf("route %s rewrite = %q, want %q", tc.prefix, got, tc.rewrite)
+		}
+		if got := r.Properties[parser.RoutePropUpstream]; got != tc.upstream {
+			t.Errorf("route %s upstream = %q, want %q", tc.prefix, got, tc.upstream)
+		}
+		if r.Properties["source"] != "nginx" {
+			t.Errorf("route %s source = %q, want nginx", tc.prefix, r.Properties["source"])
+		}
+	}
+
+	configures := 0
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeConfigures {
+			configures++
+		}
+	}
+	if configures != len(routes) {
+		t.Errorf("got %d Configures edges, want %d", configures, len(routes))
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	result, err := NewParser().ParseFile("nginx.conf", []byte("server {\n  location /api/ {\n    proxy_pass http://api;\n"))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(result.Diagnostics) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(result.Diagnostics))
+	}
+	if len(result.Nodes) != 1 || result.Nodes[0].Type != graph.NodeFile {
+		t.Errorf("expected only the file node, got %d nodes", len(result.Nodes))
+	}
+}
+
+func TestTokenizeQuoted(t *testing.T) {
+	toks := tokenize(`add_header X-Note "a;b {c}"; # trailing comment`)
+	want := []string{"add_header", "X-Note", "a;b {c}", ";"}
+	if len(toks) != len(want) {
+		t.Fatalf("got %d tokens, want %d", len(toks), len(want))
+	}
+	for i, w := range want {
+		if toks[i].text != w {
+			t.Errorf("token %d = %q, want %q", i, toks[i].text, w)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package parser
+
+import "strings"
+
+// ORM model properties, recorded by parsers on the classes and structs
+// whose fields declare associations with other models. The linker binds
+// each relation to the DBModel it names (see linkORMRelations).
+const (
+	// PropORM is the ORM a model's associations were read from:
+	// "active_record", "sqlalchemy", "django", "gorm" or "ef".
+	PropORM = "orm"
+	// PropModelRelations lists a model's associations, encoded by
+	// FormatModelRelations.
+	PropModelRelations = "relations"
+)
+
+// Relation cardinalities, seen from the model declaring the association.
+const (
+	CardinalityOneToOne   = "one_to_one"
+	CardinalityOneToMany  = "one_to_many"
+	CardinalityManyToOne  = "many_to_one"
+	CardinalityManyToMany = "many_to_many"
+)
+
+// ModelRelation is an association an ORM model declares with another.
+type ModelRelation struct {
+	// Field is the attribute holding the association (orders, Customer).
+	Field string
+	// Target is the unqualified name of the associated model.
+	Target string
+	// Cardinality is one of the Cardinality constants.
+	Cardinality string
+	// Through names the join model or table of a many-to-many relation,
+	// when declared.
+	Through string
+}
+
+// FormatModelRelations encodes relations as comma-separated
+// "field:Target:cardinality[:through]" entries.
+func FormatModelRelations(relations []ModelRelation) string {
+	entries := make([]string, 0, len(relations))
+	for _, r := range relations {
+		entry := r.Field + ":" + r.Target + ":" + r.Cardinality
+		if r.Through != "" {
+			entry += ":" + r.Through
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, ",")
+}
+
+// ParseModelRelations decodes a PropModelRelations value, skipping
+// malformed entries.
+func ParseModelRelations(s string) []ModelRelation {
+	var relations []ModelRelation
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(entry, ":")
+		if len(parts) < 3 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		r := ModelRelation{Field: parts[0], Target: parts[1], Cardinality: parts[2]}
+		if len(parts) > 3 {
+			r.Through = parts[3]
+		}
+		relations = append(relations, r)
+	}
+	return relations
+}
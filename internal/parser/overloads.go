@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Arity is the number of arguments a method accepts: Min required and Max
+// declared parameters, with Max -1 for variadic methods. Languages with
+// overloading (Java, C#) record it on method nodes as Properties["arity"] so
+// calls can be resolved to the overload taking their argument count.
+type Arity struct {
+	Min, Max int
+}
+
+// String formats the arity as "2", "1-3" (optional parameters), or "1+"
+// (variadic).
+func (a Arity) String() string {
+	switch {
+	case a.Max < 0:
+		return strconv.Itoa(a.Min) + "+"
+	case a.Min == a.Max:
+		return strconv.Itoa(a.Min)
+	default:
+		return strconv.Itoa(a.Min) + "-" + strconv.Itoa(a.Max)
+	}
+}
+
+// Accepts reports whether a call with n arguments can bind to the method.
+func (a Arity) Accepts(n int) bool {
+	return n >= a.Min && (a.Max < 0 || n <= a.Max)
+}
+
+// ParseArity parses the String form of an arity.
+func ParseArity(s string) (Arity, bool) {
+	if min, ok := strings.CutSuffix(s, "+"); ok {
+		n, err := strconv.Atoi(min)
+		return Arity{Min: n, Max: -1}, err == nil
+	}
+	if min, max, ok := strings.Cut(s, "-"); ok {
+		lo, err1 := strconv.Atoi(min)
+		hi, err2 := strconv.Atoi(max)
+		return Arity{Min: lo, Max: hi}, err1 == nil && err2 == nil
+	}
+	n, err := strconv.Atoi(s)
+	return Arity{Min: n, Max: n}, err == nil
+}
+
+// PickOverload returns the first method among same-named candidates whose
+// arity accepts a call with args arguments, falling back to the first
+// candidate when none does (or the call's arity is unknown, args < 0).
+func PickOverload(candidates []*graph.Node, args int) *graph.Node {
+	if len(candidates) == 0 {
+		return nil
+	}
+	if args >= 0 {
+		for _, c := range candidates {
+			if a, ok := ParseArity(c.Properties["arity"]); ok && a.Accepts(args) {
+				return c
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// MethodSignatureKey returns the name used in a method's node ID and
+// QualifiedName, Class.method(T1,T2), so overloads get distinct nodes.
+func MethodSignatureKey(className, name string, paramTypes []string) string {
+	return className + "." + name + "(" + strings.Join(paramTypes, ",") + ")"
+}
+
+// overloadModifiers are parameter modifiers that distinguish overloads.
+var overloadModifiers = map[string]bool{"ref": true, "out": true, "in": true}
+
+// NormalizeParamType reduces a declared parameter type to the form used in
+// method signature keys: generic arguments and namespace or package
+// qualifiers are dropped, while array, nullable, and varargs suffixes and
+// ref/out/in modifiers are kept. "final java.util.List<String>" becomes
+// "List", "ref int" stays "ref int", "String..." stays "String...".
+func NormalizeParamType(t string) string {
+	// Drop generic arguments.
+	var b strings.Builder
+	depth := 0
+	for _, r := range t {
+		switch {
+		case r == '<':
+			depth++
+		case r == '>':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+
+	fields := strings.Fields(b.String())
+	if len(fields) == 0 {
+		return ""
+	}
+	var mods []string
+	for _, f := range fields[:len(fields)-1] {
+		if overloadModifiers[f] {
+			mods = append(mods, f)
+		}
+	}
+
+	typ := fields[len(fields)-1]
+	base := strings.TrimRight(typ, "[].?")
+	suffix := typ[len(base):]
+	if i := strings.LastIndex(base, "."); i >= 0 {
+		base = base[i+1:]
+	}
+	return strings.Join(append(mods, base+suffix), " ")
+}
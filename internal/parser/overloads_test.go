@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestNormalizeParamType(t *testing.T) {
+	tests := map[string]string{
+		"String":                                "String",
+		"final java.util.List<String>":          "List",
+		"Map<String, List<Integer>>":            "Map",
+		"int[]":                                 "int[]",
+		"String...":                             "String...",
+		"ref int":                               "ref int",
+		"out System.Decimal":                    "out Decimal",
+		"this Foo":                              "Foo",
+		"System.Collections.Generic.List<int>?": "List?",
+		"":                                      "",
+	}
+	for in, want := range tests {
+		if got := NormalizeParamType(in); got != want {
+			t.Errorf("NormalizeParamType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestArity(t *testing.T) {
+	tests := []struct {
+		arity   Arity
+		str     string
+		accepts []int
+		rejects []int
+	}{
+		{Arity{0, 0}, "0", []int{0}, []int{1}},
+		{Arity{2, 2}, "2", []int{2}, []int{1, 3}},
+		{Arity{1, 3}, "1-3", []int{1, 2, 3}, []int{0, 4}},
+		{Arity{1, -1}, "1+", []int{1, 5}, []int{0}},
+	}
+	for _, tt := range tests {
+		if got := tt.arity.String(); got != tt.str {
+			t.Errorf("%+v.String() = %q, want %q", tt.arity, got, tt.str)
+		}
+		if parsed, ok := ParseArity(tt.str); !ok || parsed != tt.arity {
+			t.Errorf("ParseArity(%q) = %+v, %v", tt.str, parsed, ok)
+		}
+		for _, n := range tt.accepts {
+			if !tt.arity.Accepts(n) {
+				t.Errorf("%s should accept %d", tt.str, n)
+			}
+		}
+		for _, n := range tt.rejects {
+			if tt.arity.Accepts(n) {
+				t.Errorf("%s should reject %d", tt.str, n)
+			}
+		}
+	}
+	if _, ok := ParseArity(""); ok {
+		t.Error("ParseArity(\"\") should fail")
+	}
+}
+
+func TestPickOverload(t *testing.T) {
+	one := &graph.Node{ID: "one", Properties: map[string]string{"arity": "1"}}
+	two := &graph.Node{ID: "two", Properties: map[string]string{"arity": "2-3"}}
+	legacy := &graph.Node{ID: "legacy"}
+	candidates := []*graph.Node{one, two}
+
+	tests := []struct {
+		candidates []*graph.Node
+		args       int
+		want       *graph.Node
+	}{
+		{candidates, 1, one},
+		{candidates, 3, two},
+		{candidates, 5, one}, // no match falls back to the first
+		{candidates, -1, one},
+		{[]*graph.Node{legacy, two}, 2, two},
+		{nil, 1, nil},
+	}
+	for _, tt := range tests {
+		if got := PickOverload(tt.candidates, tt.args); got != tt.want {
+			t.Errorf("PickOverload(args=%d) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
@@ -21,12 +21,13 @@ const (
 	LangRust       Language = "rust"
 	LangCSharp     Language = "csharp"
 	LangRuby       Language = "ruby"
+	LangNginx      Language = "nginx"
 )
 
 // FileExtensions maps each language to its recognized file extensions.
 var FileExtensions = map[Language][]string{
 	LangGo:         {".go"},
-	LangPython:     {".py", ".pyi"},
+	LangPython:     {".py", ".pyi", ".ipynb"},
 	LangTypeScript: {".ts", ".tsx"},
 	LangJavaScript: {".js", ".jsx", ".mjs", ".cjs"},
 	LangJava:       {".java"},
@@ -40,14 +41,18 @@ var FileExtensions = map[Language][]string{
 	LangRust:       {".rs"},
 	LangCSharp:     {".cs"},
 	LangRuby:       {".rb", ".rake"},
+	LangNginx:      {".nginx"},
 }
 
 // ParseResult holds the extracted nodes and edges from parsing a file.
+// Diagnostics lists syntax errors the parser recovered from; the nodes and
+// edges then cover only what could be extracted.
 type ParseResult struct {
-	Nodes    []*graph.Node
-	Edges    []*graph.Edge
-	FilePath string
-	Language Language
+	Nodes       []*graph.Node
+	Edges       []*graph.Edge
+	FilePath    string
+	Language    Language
+	Diagnostics []Diagnostic
 }
 
 // Parser defines the interface for language-specific source code parsers.
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Pipeline and PipelineJob node properties. Paths are relative to the
+// graph root like file paths: the parser joins them with the directory
+// holding the CI configuration's repository.
+const (
+	// PipelinePropCI is the CI system: "github_actions" or "gitlab_ci".
+	PipelinePropCI = "ci"
+	// PipelinePropTriggerPaths lists, comma-separated, the path globs a
+	// change must touch for the pipeline (GitHub on.<event>.paths) or job
+	// (GitLab rules:changes, only:changes) to run. Empty means any change.
+	PipelinePropTriggerPaths = "trigger_paths"
+	// PipelinePropIgnorePaths lists the path globs of changes that do not
+	// trigger the pipeline (GitHub on.<event>.paths-ignore).
+	PipelinePropIgnorePaths = "ignore_paths"
+	// PipelinePropPaths lists the directories a PipelineJob works in: its
+	// working directories and the paths its commands name.
+	PipelinePropPaths = "paths"
+	// PipelinePropActions lists what a PipelineJob does, of "build",
+	// "test" and "deploy", from its name, commands and actions.
+	PipelinePropActions = "actions"
+	// PipelinePropStage is the GitLab stage of a PipelineJob.
+	PipelinePropStage = "stage"
+)
+
+// pipelineActionWords maps the words of job names, commands and action
+// references to what the job does.
+var pipelineActionWords = map[string]string{
+	"build": "build", "builds": "build", "compile": "build", "package": "build", "bake": "build", "assemble": "build",
+	"test": "test", "tests": "test", "pytest": "test", "jest": "test", "vitest": "test", "mocha": "test",
+	"rspec": "test", "tox": "test", "phpunit": "test", "ctest": "test", "e2e": "test",
+	"deploy": "deploy", "deploys": "deploy", "deployment": "deploy", "kubectl": "deploy", "helm": "deploy",
+	"release": "deploy", "publish": "deploy", "push": "deploy", "rollout": "deploy",
+}
+
+// PipelineActions returns, sorted, the actions ("build", "test",
+// "deploy") named by the words of texts, such as a job's name, commands
+// and the actions its steps use.
+func PipelineActions(texts ...string) []string {
+	found := make(map[string]bool)
+	for _, text := range texts {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, w := range words {
+			if a, ok := pipelineActionWords[w]; ok {
+				found[a] = true
+			}
+		}
+	}
+	actions := make([]string, 0, len(found))
+	for a := range found {
+		actions = append(actions, a)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// pathFlags are command flags whose value is a directory or file the
+// command works in (make -C dir, npm --prefix dir, docker build -f file).
+var pathFlags = map[string]bool{
+	"-C": true, "--prefix": true, "--cwd": true, "--dir": true, "--directory": true, "--chdir": true,
+	"-f": true, "--file": true, "--project-dir": true, "--working-directory": true, "--context": true,
+}
+
+// PipelineCommandPaths returns the relative paths a shell script works in
+// or on, resolved against dir (a repository-relative directory, "" for the
+// root): cd targets, path flag values, and arguments that look like
+// repository paths (./services/orders/..., web/Dockerfile). Paths after a
+// cd resolve against its target. A file yields its directory. Absolute,
+// home, parent and variable paths, and the root itself, are left out.
+func PipelineCommandPaths(dir, script string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(p string) string {
+		p = CleanPipelinePath(dir, p)
+		if p != "" && !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+		return p
+	}
+	for _, line := range strings.Split(script, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return unicode.IsSpace(r) || strings.ContainsRune(";&|()", r)
+		})
+		for i := 0; i < len(fields); i++ {
+			f := strings.Trim(fields[i], `"'`)
+			switch {
+			case f == "cd" && i+1 < len(fields):
+				i++
+				if p := add(strings.Trim(fields[i], `"'`)); p != "" {
+					dir = p
+				}
+			case pathFlags[f] && i+1 < len(fields):
+				i++
+				add(strings.Trim(fields[i], `"'`))
+			case strings.HasPrefix(f, "-"):
+				if flag, val, ok := strings.Cut(f, "="); ok && pathFlags[flag] {
+					add(val)
+				}
+			case strings.HasPrefix(f, "./") || strings.Contains(f, "/"):
+				add(f)
+			}
+		}
+	}
+	return paths
+}
+
+// CleanPipelinePath resolves p, a path named in a CI job, against dir and
+// returns the directory it stands for, or "" when p is not a repository
+// path. Go package patterns ("./svc/...") yield their root directory.
+func CleanPipelinePath(dir, p string) string {
+	p = strings.TrimSuffix(strings.TrimSuffix(p, "..."), "/")
+	if p == "" || p == "." || strings.ContainsAny(p, "$*?{}:@=<>~\\") || strings.HasPrefix(p, "/") {
+		return ""
+	}
+	base := path.Base(p)
+	if strings.Contains(strings.TrimPrefix(base, "."), ".") || strings.HasPrefix(base, "Dockerfile") ||
+		base == "Makefile" || base == "Containerfile" {
+		p = path.Dir(p)
+	}
+	p = path.Clean(path.Join(dir, p))
+	if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+		return ""
+	}
+	return p
+}
+
+// MatchPipelinePath reports whether the repository path p matches a
+// trigger path glob, where "**" matches any number of directories.
+func MatchPipelinePath(glob, p string) bool {
+	return matchGlobParts(splitSlash(glob), splitSlash(p))
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPipelineCommandPaths(t *testing.T) {
+	tests := []struct {
+		dir, script string
+		want        []string
+	}{
+		{"", "go test ./services/orders/... ./libs/...", []string{"services/orders", "libs"}},
+		{"", "go test ./...", nil},
+		{"services/orders", "go build -o bin/server ./cmd/server", []string{"services/orders/bin/server", "services/orders/cmd/server"}},
+		{"", "cd web && npm ci && npm run build --prefix=../admin", []string{"web", "admin"}},
+		{"", "make -C services/billing test # runs unit tests", []string{"services/billing"}},
+		{"", "docker build -f services/api/Dockerfile.prod .", []string{"services/api"}},
+		{"", "curl https://example.com/health && cp /etc/hosts ~/hosts $OUT/x", nil},
+		{"", "cd ../other; ls", nil},
+		{"shop", "cd services\ncd orders\npytest tests/unit", []string{"shop/services", "shop/services/orders", "shop/services/orders/tests/unit"}},
+	}
+	for _, tt := range tests {
+		if got := PipelineCommandPaths(tt.dir, tt.script); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PipelineCommandPaths(%q, %q) = %v, want %v", tt.dir, tt.script, got, tt.want)
+		}
+	}
+}
+
+func TestPipelineActions(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  []string
+	}{
+		{[]string{"unit-tests", "go vet ./..."}, []string{"test"}},
+		{[]string{"ci", "docker/build-push-action@v5"}, []string{"build", "deploy"}},
+		{[]string{"release", "helm upgrade --install api ./chart"}, []string{"deploy"}},
+		{[]string{"lint", "golangci/golangci-lint-action@v6"}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := PipelineActions(tt.texts...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PipelineActions(%q) = %v, want %v", tt.texts, got, tt.want)
+		}
+	}
+}
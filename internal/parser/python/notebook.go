@@ -0,0 +1,171 @@
+package python
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// notebookExtension is the extension of Jupyter notebooks. Their Python
+// code cells are parsed as one module, and the nodes found in a cell carry
+// its index (graph.PropNotebookCell) with lines counted from the cell's
+// start.
+const notebookExtension = ".ipynb"
+
+// notebook is the part of the Jupyter notebook format (nbformat 4) the
+// parser reads. Polyglot notebooks (VS Code, .NET Interactive) record the
+// language of each cell in its metadata.
+type notebook struct {
+	Cells    []notebookCell `json:"cells"`
+	Metadata struct {
+		KernelSpec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type notebookCell struct {
+	CellType string         `json:"cell_type"`
+	Source   notebookSource `json:"source"`
+	Metadata struct {
+		VSCode struct {
+			LanguageID string `json:"languageId"`
+		} `json:"vscode"`
+		PolyglotNotebook struct {
+			KernelName string `json:"kernelName"`
+		} `json:"polyglot_notebook"`
+		DotnetInteractive struct {
+			Language string `json:"language"`
+		} `json:"dotnet_interactive"`
+	} `json:"metadata"`
+}
+
+// language returns the cell's language, or def when its metadata names none.
+func (c notebookCell) language(def string) string {
+	for _, l := range []string{c.Metadata.VSCode.LanguageID, c.Metadata.PolyglotNotebook.KernelName, c.Metadata.DotnetInteractive.Language} {
+		if l != "" {
+			return l
+		}
+	}
+	return def
+}
+
+// notebookSource is a cell's source, stored either as one string or as a
+// list of lines.
+type notebookSource string
+
+func (s *notebookSource) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*s = notebookSource(strings.Join(lines, ""))
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	*s = notebookSource(text)
+	return nil
+}
+
+// cellSpan locates a code cell in the source assembled from a notebook.
+type cellSpan struct {
+	index int // position in the notebook's cell list
+	start int // 1-based line of the cell's first line
+}
+
+// notebookPython assembles the Python code cells of a notebook into one
+// source, in order, and returns it with the span of each cell. IPython
+// line magics and shell escapes (%pip, !ls) become blank lines; cells
+// starting with a cell magic (%%bash) are not Python and are skipped.
+func notebookPython(content []byte) ([]byte, []cellSpan, error) {
+	var nb notebook
+	if err := json.Unmarshal(content, &nb); err != nil {
+		return nil, nil, fmt.Errorf("decode notebook: %w", err)
+	}
+	def := nb.Metadata.KernelSpec.Language
+	if def == "" {
+		def = nb.Metadata.LanguageInfo.Name
+	}
+	if def == "" {
+		def = string(parser.LangPython)
+	}
+
+	var (
+		src   bytes.Buffer
+		spans []cellSpan
+		line  = 1
+	)
+	for i, c := range nb.Cells {
+		if c.CellType != "code" || !isPythonLanguage(c.language(def)) {
+			continue
+		}
+		text := string(c.Source)
+		if strings.HasPrefix(strings.TrimSpace(text), "%%") {
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		spans = append(spans, cellSpan{index: i, start: line})
+		for _, l := range lines {
+			if t := strings.TrimSpace(l); strings.HasPrefix(t, "%") || strings.HasPrefix(t, "!") {
+				l = ""
+			}
+			src.WriteString(l)
+			src.WriteByte('\n')
+		}
+		line += len(lines)
+	}
+	return src.Bytes(), spans, nil
+}
+
+func isPythonLanguage(lang string) bool {
+	lang = strings.ToLower(lang)
+	return strings.HasPrefix(lang, "python") || lang == "ipython"
+}
+
+// cellAt returns the span of the cell holding line of the assembled source.
+func cellAt(spans []cellSpan, line int) (cellSpan, bool) {
+	i := sort.Search(len(spans), func(i int) bool { return spans[i].start > line }) - 1
+	if i < 0 || line < 1 {
+		return cellSpan{}, false
+	}
+	return spans[i], true
+}
+
+// mapNotebookLines rewrites the lines of the nodes and diagnostics of a
+// parsed notebook from the assembled source to their cells. The file and
+// module nodes describe the whole notebook and are left alone.
+func mapNotebookLines(result *parser.ParseResult, spans []cellSpan, moduleID string) {
+	for _, n := range result.Nodes {
+		if n.ID == moduleID || n.Type == graph.NodeFile || n.Type == graph.NodeTestFile {
+			continue
+		}
+		cell, ok := cellAt(spans, n.Line)
+		if !ok {
+			continue
+		}
+		if n.Properties == nil {
+			n.Properties = make(map[string]string)
+		}
+		n.Properties[graph.PropNotebookCell] = strconv.Itoa(cell.index)
+		n.Line -= cell.start - 1
+		if n.EndLine > 0 {
+			n.EndLine -= cell.start - 1
+		}
+	}
+	for i, d := range result.Diagnostics {
+		if cell, ok := cellAt(spans, d.Line); ok {
+			result.Diagnostics[i].Line -= cell.start - 1
+			result.Diagnostics[i].Message = fmt.Sprintf("cell %d: %s", cell.index, d.Message)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package python
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+const sampleNotebook = `{
+ "metadata": {"kernelspec": {"name": "python3", "language": "python"}},
+ "nbformat": 4,
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Orders report\n"]},
+  {"cell_type": "code", "metadata": {}, "source": ["%pip install requests\n", "import requests\n", "BASE = \"https://orders.internal\"\n"]},
+  {"cell_type": "code", "metadata": {}, "source": "%%bash\necho not python\n"},
+  {"cell_type": "code", "metadata": {"vscode": {"languageId": "sql"}}, "source": ["SELECT * FROM orders\n"]},
+  {"cell_type": "code", "metadata": {}, "source": ["!ls data\n", "\n", "def load_orders(status):\n", "    return requests.get(\"/api/orders\", params={\"status\": status}).json()\n"]}
+ ]
+}`
+
+func TestParseNotebook(t *testing.T) {
+	result, err := NewParser().ParseFile("reports/orders.ipynb", []byte(sampleNotebook))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(result.Diagnostics) != 0 {
+		t.Errorf("diagnostics = %v, want none (magics and non-Python cells skipped)", result.Diagnostics)
+	}
+
+	var fn, call, base *graph.Node
+	for _, n := range result.Nodes {
+		switch {
+		case n.Type == graph.NodeFunction && n.Name == "load_orders":
+			fn = n
+		case n.Type == graph.NodeDependency && n.Properties["kind"] == "api_call":
+			call = n
+		case n.Name == "BASE":
+			base = n
+		case n.Type == graph.NodeModule && n.Properties[graph.PropNotebookCell] != "":
+			t.Errorf("module node tagged with cell %s", n.Properties[graph.PropNotebookCell])
+		}
+	}
+	tests := []struct {
+		name     string
+		node     *graph.Node
+		cell     string
+		wantLine int
+	}{
+		{"constant", base, "1", 3},
+		{"function", fn, "4", 3},
+		{"api call", call, "4", 4},
+	}
+	for _, tt := range tests {
+		if tt.node == nil {
+			t.Errorf("%s: node missing", tt.name)
+			continue
+		}
+		if got := tt.node.Properties[graph.PropNotebookCell]; got != tt.cell {
+			t.Errorf("%s: cell = %q, want %q", tt.name, got, tt.cell)
+		}
+		if tt.node.Line != tt.wantLine {
+			t.Errorf("%s: line = %d, want %d", tt.name, tt.node.Line, tt.wantLine)
+		}
+	}
+	if call != nil && call.Properties["path"] != "/api/orders" {
+		t.Errorf("api call path = %q, want /api/orders", call.Properties["path"])
+	}
+}
+
+func TestParseNotebookDiagnostics(t *testing.T) {
+	nb := `{"cells": [
+	  {"cell_type": "code", "source": "x = 1\n"},
+	  {"cell_type": "code", "source": "y = 2\ndef broken(:\n    pass\n"}
+	]}`
+	result, err := NewParser().ParseFile("broken.ipynb", []byte(nb))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(result.Diagnostics) == 0 {
+		t.Fatal("expected a diagnostic for the broken cell")
+	}
+	if d := result.Diagnostics[0]; d.Line != 2 || !strings.HasPrefix(d.Message, "cell 1: ") {
+		t.Errorf("diagnostic = %+v, want line 2 of cell 1", d)
+	}
+}
+
+func TestParseNotebookInvalid(t *testing.T) {
+	if _, err := NewParser().ParseFile("bad.ipynb", []byte("not json")); err == nil {
+		t.Error("expected an error for a notebook that is not JSON")
+	}
+}
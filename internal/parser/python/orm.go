@@ -0,0 +1,204 @@
+package python
+
+import (
+	"strings"
+	"unicode"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// djangoRelationFields maps Django relation field classes to the
+// cardinality they declare.
+var djangoRelationFields = map[string]string{
+	"ForeignKey":      parser.CardinalityManyToOne,
+	"OneToOneField":   parser.CardinalityOneToOne,
+	"ManyToManyField": parser.CardinalityManyToMany,
+}
+
+// typingWrappers are the names wrapping a model in a relationship's type
+// annotation, skipped when looking for the model.
+var typingWrappers = map[string]bool{
+	"Mapped": true, "Optional": true, "List": true, "list": true, "Set": true, "set": true,
+	"Sequence": true, "typing": true, "None": true, "WriteOnlyMapped": true, "DynamicMapped": true,
+}
+
+// modelRelations reads the associations declared by the attribute
+// assignments of a class body: SQLAlchemy relationship() calls (orm
+// "sqlalchemy") and Django ForeignKey, OneToOneField and ManyToManyField
+// fields (orm "django").
+func (e *extractor) modelRelations(body *sitter.Node, className string) (string, []parser.ModelRelation) {
+	type attribute struct {
+		name       string
+		annotation string
+		call       *sitter.Node
+	}
+	var attrs []attribute
+	names := make(map[string]bool)
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		stmt := body.NamedChild(i)
+		if stmt.Type() != "expression_statement" || stmt.NamedChildCount() == 0 {
+			continue
+		}
+		assign := stmt.NamedChild(0)
+		if assign.Type() != "assignment" {
+			continue
+		}
+		left := assign.ChildByFieldName("left")
+		if left == nil || left.Type() != "identifier" {
+			continue
+		}
+		a := attribute{name: e.nodeText(left)}
+		names[a.name] = true
+		if typ := assign.ChildByFieldName("type"); typ != nil {
+			a.annotation = e.nodeText(typ)
+		}
+		if right := assign.ChildByFieldName("right"); right != nil && right.Type() == "call" {
+			a.call = right
+		}
+		attrs = append(attrs, a)
+	}
+
+	orm := ""
+	var relations []parser.ModelRelation
+	for _, a := range attrs {
+		if a.call == nil {
+			continue
+		}
+		fn := a.call.ChildByFieldName("function")
+		args := a.call.ChildByFieldName("arguments")
+		if fn == nil || args == nil {
+			continue
+		}
+		callee := e.nodeText(fn)
+		if i := strings.LastIndex(callee, "."); i >= 0 {
+			callee = callee[i+1:]
+		}
+		positional, keywords := e.callArguments(args)
+
+		if callee == "relationship" {
+			r := parser.ModelRelation{Field: a.name}
+			collection, annotated := false, false
+			if len(positional) > 0 {
+				r.Target = modelName(positional[0])
+			}
+			if a.annotation != "" {
+				collection, annotated = isCollectionAnnotation(a.annotation), true
+				if r.Target == "" {
+					r.Target = annotatedModel(a.annotation)
+				}
+			}
+			switch {
+			case keywords["secondary"] != "":
+				r.Cardinality, r.Through = parser.CardinalityManyToMany, modelName(keywords["secondary"])
+			case keywords["uselist"] == "False":
+				r.Cardinality = parser.CardinalityOneToOne
+			case keywords["uselist"] == "True", annotated && collection:
+				r.Cardinality = parser.CardinalityOneToMany
+			case annotated, names[a.name+"_id"]:
+				r.Cardinality = parser.CardinalityManyToOne
+			case strings.HasSuffix(a.name, "s"):
+				r.Cardinality = parser.CardinalityOneToMany
+			default:
+				r.Cardinality = parser.CardinalityManyToOne
+			}
+			if r.Target != "" {
+				relations = append(relations, r)
+				if orm == "" {
+					orm = "sqlalchemy"
+				}
+			}
+			continue
+		}
+
+		if cardinality, ok := djangoRelationFields[callee]; ok {
+			target := keywords["to"]
+			if len(positional) > 0 {
+				target = positional[0]
+			}
+			if target = modelName(target); target == "self" {
+				target = className
+			}
+			if target == "" {
+				continue
+			}
+			relations = append(relations, parser.ModelRelation{
+				Field:       a.name,
+				Target:      target,
+				Cardinality: cardinality,
+				Through:     modelName(keywords["through"]),
+			})
+			if orm == "" {
+				orm = "django"
+			}
+		}
+	}
+	return orm, relations
+}
+
+// callArguments returns the text of a call's positional arguments and its
+// keyword arguments by name, with string literals unquoted.
+func (e *extractor) callArguments(args *sitter.Node) ([]string, map[string]string) {
+	var positional []string
+	keywords := make(map[string]string)
+	value := func(n *sitter.Node) string {
+		if n.Type() == "string" {
+			return cleanStringLiteral(e.nodeText(n))
+		}
+		return e.nodeText(n)
+	}
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		if arg.Type() == "keyword_argument" {
+			name, val := arg.ChildByFieldName("name"), arg.ChildByFieldName("value")
+			if name != nil && val != nil {
+				keywords[e.nodeText(name)] = value(val)
+			}
+			continue
+		}
+		positional = append(positional, value(arg))
+	}
+	return positional, keywords
+}
+
+// modelName returns the class name a relationship argument refers to:
+// "app.User", "models.User" and "User" all yield "User". Lambdas and other
+// expressions yield "".
+func modelName(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndex(s, "."); i >= 0 {
+		s = s[i+1:]
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return ""
+		}
+	}
+	return s
+}
+
+// isCollectionAnnotation reports whether a relationship's type annotation
+// holds a collection of models: Mapped[List["Order"]], Mapped[set[Tag]].
+func isCollectionAnnotation(annotation string) bool {
+	for _, wrapper := range []string{"List[", "list[", "Set[", "set[", "Sequence["} {
+		if strings.Contains(annotation, wrapper) {
+			return true
+		}
+	}
+	return false
+}
+
+// annotatedModel returns the model named by a relationship's type
+// annotation: Mapped[Optional["User"]] yields "User".
+func annotatedModel(annotation string) string {
+	words := strings.FieldsFunc(annotation, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.'
+	})
+	for i := len(words) - 1; i >= 0; i-- {
+		if name := modelName(words[i]); name != "" && !typingWrappers[name] {
+			return name
+		}
+	}
+	return ""
+}
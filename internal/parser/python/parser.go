@@ -15,13 +15,20 @@ import (
 )
 
 // PythonParser extracts knowledge graph nodes and edges from Python source files.
-type PythonParser struct{}
+type PythonParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new Python parser.
 func NewParser() *PythonParser {
 	return &PythonParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *PythonParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *PythonParser) Language() parser.Language {
 	return parser.LangPython
 }
@@ -31,6 +38,14 @@ func (p *PythonParser) Extensions() []string {
 }
 
 func (p *PythonParser) ParseFile(filePath string, content []byte) (*parser.ParseResult, error) {
+	var cells []cellSpan
+	if filepath.Ext(filePath) == notebookExtension {
+		var err error
+		if content, cells, err = notebookPython(content); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", filePath, err)
+		}
+	}
+
 	lang := python.GetLanguage()
 	sitterParser := sitter.NewParser()
 	sitterParser.SetLanguage(lang)
@@ -41,18 +56,24 @@ func (p *PythonParser) ParseFile(filePath string, content []byte) (*parser.Parse
 	}
 
 	e := &extractor{
-		filePath: filePath,
-		content:  content,
-		tree:     tree,
+		filePath:     filePath,
+		content:      content,
+		tree:         tree,
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
-	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangPython,
-	}, nil
+	result := &parser.ParseResult{
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangPython,
+		Diagnostics: parser.TreeSitterDiagnostics(tree.RootNode(), content),
+	}
+	if cells != nil {
+		mapNotebookLines(result, cells, e.moduleNodeID)
+	}
+	return result, nil
 }
 
 // extractor walks a tree-sitter Python AST and builds graph nodes and edges.
@@ -66,6 +87,7 @@ type extractor struct {
 	moduleNodeID string
 	fileNodeID   string
 	isTestFile   bool
+	testPatterns *parser.TestPatterns
 
 	// Protocol detection
 	protocolNames map[string]bool // tracks Protocol aliases (e.g., "Protocol", "Proto")
@@ -73,23 +95,32 @@ type extractor struct {
 	// Lookup maps for function call resolution (built after walkTopLevel)
 	importNames      map[string]string            // module/alias name → dep node ID
 	funcNames        map[string]string            // function name → node ID
-	classMethodNames map[string]map[string]string // className → methodName → node ID
+	classMethodNames map[string]map[string]string // class path → methodName → node ID
+
+	// scope lists the classes enclosing the declaration being extracted,
+	// outermost first.
+	scope []string
+
+	// routerPrefixes maps FastAPI APIRouter and Flask Blueprint variables
+	// to their URL prefix.
+	routerPrefixes map[string]string
 }
 
 func (e *extractor) extract() {
 	e.protocolNames = make(map[string]bool)
+	e.routerPrefixes = make(map[string]string)
 	e.extractFileNode()
 	e.extractModule()
 
 	root := e.tree.RootNode()
 	e.walkTopLevel(root)
 	e.buildCallMaps()
-	e.walkForCalls(root, e.moduleNodeID, "")
+	e.walkForCalls(root, e.moduleNodeID, nil)
 }
 
 func (e *extractor) extractFileNode() {
 	base := filepath.Base(e.filePath)
-	e.isTestFile = isTestFilename(base)
+	e.isTestFile = isTestFilename(base) || e.testPatterns.MatchesFile(e.filePath)
 
 	fileType := graph.NodeFile
 	if e.isTestFile {
@@ -238,26 +269,45 @@ func (e *extractor) extractFromImport(node *sitter.Node) {
 }
 
 func (e *extractor) addDependency(name string, line int) {
-	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, name)
-
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
+	// Relative imports (from . import x, from .models import y) are local.
+	dep, edge := parser.Import{
+		Language: parser.LangPython,
 		Name:     name,
 		FilePath: e.filePath,
 		Line:     line,
+		Local:    strings.HasPrefix(name, "."),
+	}.Link(e.moduleNodeID)
+	e.nodes = append(e.nodes, dep)
+	e.edges = append(e.edges, edge)
+}
+
+// symbolID returns the ID of a symbol declared within scope. Nested
+// declarations get scoped IDs so same-named ones (Order.Builder,
+// Invoice.Builder) stay distinct; top-level IDs are unchanged.
+func (e *extractor) symbolID(nodeType graph.NodeType, scope []string, name string) string {
+	return graph.SymbolKey{
 		Language: string(parser.LangPython),
-		Properties: map[string]string{
-			"kind": "import",
-		},
-	})
+		Type:     nodeType,
+		FilePath: e.filePath,
+		Scope:    scope,
+		Name:     name,
+	}.ID()
+}
 
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, depID, string(graph.EdgeImports)),
-		Type:     graph.EdgeImports,
-		SourceID: e.moduleNodeID,
-		TargetID: depID,
-	})
+// memberScope is the scope of the members of the class being walked: the
+// classes enclosing it, since member names are already qualified with the
+// class's own name.
+func (e *extractor) memberScope() []string {
+	if len(e.scope) == 0 {
+		return nil
+	}
+	return e.scope[:len(e.scope)-1]
+}
+
+// classPath keys classMethodNames: the names of a class and the classes
+// enclosing it, joined with ".".
+func classPath(scope []string, className string) string {
+	return strings.Join(append(append([]string(nil), scope...), className), ".")
 }
 
 func (e *extractor) extractClass(node *sitter.Node, parentID string) {
@@ -301,14 +351,20 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 		nodeType = graph.NodeInterface
 	}
 
-	classID := graph.NewNodeID(string(nodeType), e.filePath, name)
+	classID := e.symbolID(nodeType, e.scope, name)
 
 	props := make(map[string]string)
+	if len(e.scope) > 0 {
+		props["scope"] = strings.Join(e.scope, ".")
+	}
 	if isProtocol {
 		props["protocol"] = "true"
 	}
 	if len(filteredBases) > 0 {
 		props["bases"] = strings.Join(filteredBases, ",")
+		if parser.IsErrorBase(props["bases"]) {
+			props[parser.PropErrorType] = "true"
+		}
 	}
 
 	// Extract docstring from class body
@@ -348,11 +404,18 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 		}
 	}
 
+	if bodyNode != nil {
+		if orm, relations := e.modelRelations(bodyNode, name); len(relations) > 0 {
+			props[parser.PropORM] = orm
+			props[parser.PropModelRelations] = parser.FormatModelRelations(relations)
+		}
+	}
+
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            classID,
 		Type:          nodeType,
 		Name:          name,
-		QualifiedName: name,
+		QualifiedName: classPath(e.scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine,
 		EndLine:       endLine,
@@ -371,7 +434,9 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 
 	// Extract methods and nested entities from body
 	if bodyNode != nil {
+		e.scope = append(e.scope, name)
 		e.walkClassBody(bodyNode, classID, name)
+		e.scope = e.scope[:len(e.scope)-1]
 	}
 }
 
@@ -390,6 +455,8 @@ func (e *extractor) walkClassBody(body *sitter.Node, classID, className string)
 		switch child.Type() {
 		case "function_definition", "decorated_definition":
 			e.extractFunctionOrDecorated(child, classID, className)
+		case "class_definition":
+			e.extractClass(child, classID)
 		}
 	}
 }
@@ -499,7 +566,7 @@ func (e *extractor) extractFunction(node *sitter.Node, parentID, className strin
 		nodeType = graph.NodeMethod
 	}
 	// Detect test functions: test_* prefix in test files (non-method functions only)
-	if e.isTestFile && !isMethod && strings.HasPrefix(name, "test_") {
+	if e.isTestFile && !isMethod && (strings.HasPrefix(name, "test_") || e.testPatterns.MatchesFunction(name)) {
 		nodeType = graph.NodeTestFunction
 	}
 
@@ -522,6 +589,9 @@ func (e *extractor) extractFunction(node *sitter.Node, parentID, className strin
 	}
 	if isMethod {
 		props["class"] = className
+		if scope := e.memberScope(); len(scope) > 0 {
+			props["scope"] = strings.Join(scope, ".")
+		}
 	}
 
 	// Extract docstring from function body
@@ -530,7 +600,7 @@ func (e *extractor) extractFunction(node *sitter.Node, parentID, className strin
 		docComment = e.extractDocstring(bodyNode)
 	}
 
-	funcID := graph.NewNodeID(string(nodeType), e.filePath, qualifiedName)
+	funcID := e.symbolID(nodeType, e.memberScope(), qualifiedName)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            funcID,
@@ -578,6 +648,7 @@ func (e *extractor) extractAssignment(node *sitter.Node) {
 
 	name := e.nodeText(lhs)
 	line := int(node.StartPoint().Row) + 1
+	e.detectRouterPrefix(name, child.NamedChild(1))
 
 	// Determine if it's a constant (UPPER_CASE) or variable
 	nodeType := graph.NodeVariable
@@ -635,6 +706,9 @@ func (e *extractor) detectHTTPEndpoints(funcNode *sitter.Node, funcID string, de
 		if httpMethod == "" {
 			continue
 		}
+		if i := strings.LastIndex(dec.name, "."); i > 0 {
+			path = e.routerPrefixes[dec.name[:i]] + path
+		}
 
 		endpointID := graph.NewNodeID(string(graph.NodeAPIEndpoint), e.filePath, httpMethod+":"+path)
 		e.nodes = append(e.nodes, &graph.Node{
@@ -693,6 +767,42 @@ func classifyHTTPDecorator(dec decoratorInfo) (string, string, string) {
 	return "", "", ""
 }
 
+// routerPrefixArgs maps router constructors to the keyword argument holding
+// their URL prefix.
+var routerPrefixArgs = map[string]string{
+	"APIRouter": "prefix",     // FastAPI
+	"Blueprint": "url_prefix", // Flask
+}
+
+// detectRouterPrefix records the URL prefix of a router assigned to name,
+// so routes declared on it get complete paths:
+//
+//	router = APIRouter(prefix="/users")
+//	bp = Blueprint("orders", __name__, url_prefix="/orders")
+func (e *extractor) detectRouterPrefix(name string, rhs *sitter.Node) {
+	if rhs == nil || rhs.Type() != "call" || rhs.NamedChildCount() < 2 {
+		return
+	}
+	fn := e.nodeText(rhs.NamedChild(0))
+	if i := strings.LastIndex(fn, "."); i >= 0 {
+		fn = fn[i+1:]
+	}
+	keyword, ok := routerPrefixArgs[fn]
+	if !ok {
+		return
+	}
+	args := rhs.NamedChild(1)
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(i)
+		if arg.Type() != "keyword_argument" || arg.NamedChildCount() < 2 {
+			continue
+		}
+		if e.nodeText(arg.NamedChild(0)) == keyword && arg.NamedChild(1).Type() == "string" {
+			e.routerPrefixes[name] = strings.TrimRight(cleanStringLiteral(e.nodeText(arg.NamedChild(1))), "/")
+		}
+	}
+}
+
 // detectIncludeRouter detects FastAPI include_router calls like:
 //
 //	app.include_router(router, prefix="/api/v1")
@@ -815,10 +925,15 @@ func (e *extractor) buildCallMaps() {
 		case graph.NodeMethod:
 			className := n.Properties["class"]
 			if className != "" {
-				if e.classMethodNames[className] == nil {
-					e.classMethodNames[className] = make(map[string]string)
+				var scope []string
+				if s := n.Properties["scope"]; s != "" {
+					scope = strings.Split(s, ".")
+				}
+				key := classPath(scope, className)
+				if e.classMethodNames[key] == nil {
+					e.classMethodNames[key] = make(map[string]string)
 				}
-				e.classMethodNames[className][n.Name] = n.ID
+				e.classMethodNames[key][n.Name] = n.ID
 			}
 		}
 	}
@@ -845,14 +960,15 @@ var httpClientObjects = map[string]bool{
 
 // walkForCalls recursively walks the AST looking for call expressions:
 // HTTP client calls (requests.get, httpx.post, etc.) and general function calls.
-// className tracks the enclosing class name when walking inside a class body.
-func (e *extractor) walkForCalls(node *sitter.Node, parentFuncID string, className string) {
+// classes tracks the enclosing classes, outermost first, when walking inside
+// a class body.
+func (e *extractor) walkForCalls(node *sitter.Node, parentFuncID string, classes []string) {
 	if node == nil {
 		return
 	}
 
 	currentFuncID := parentFuncID
-	currentClassName := className
+	currentClasses := classes
 
 	switch node.Type() {
 	case "class_definition":
@@ -860,7 +976,7 @@ func (e *extractor) walkForCalls(node *sitter.Node, parentFuncID string, classNa
 		for i := 0; i < int(node.NamedChildCount()); i++ {
 			child := node.NamedChild(i)
 			if child.Type() == "identifier" {
-				currentClassName = e.nodeText(child)
+				currentClasses = append(append([]string(nil), classes...), e.nodeText(child))
 				break
 			}
 		}
@@ -870,11 +986,11 @@ func (e *extractor) walkForCalls(node *sitter.Node, parentFuncID string, classNa
 			child := node.NamedChild(i)
 			if child.Type() == "identifier" {
 				name := e.nodeText(child)
-				if currentClassName != "" {
-					currentFuncID = graph.NewNodeID(string(graph.NodeMethod), e.filePath, currentClassName+"."+name)
+				if n := len(currentClasses); n > 0 {
+					currentFuncID = e.symbolID(graph.NodeMethod, currentClasses[:n-1], currentClasses[n-1]+"."+name)
 				} else {
 					funcType := graph.NodeFunction
-					if e.isTestFile && strings.HasPrefix(name, "test_") {
+					if e.isTestFile && (strings.HasPrefix(name, "test_") || e.testPatterns.MatchesFunction(name)) {
 						funcType = graph.NodeTestFunction
 					}
 					currentFuncID = graph.NewNodeID(string(funcType), e.filePath, name)
@@ -884,18 +1000,43 @@ func (e *extractor) walkForCalls(node *sitter.Node, parentFuncID string, classNa
 		}
 	}
 
-	if node.Type() == "call" {
+	switch node.Type() {
+	case "call":
 		// HTTP client check first; if it matches, skip general call check
 		if !e.checkHTTPClientCall(node, currentFuncID) {
-			e.checkFunctionCall(node, currentFuncID, currentClassName)
+			e.checkFunctionCall(node, currentFuncID, strings.Join(currentClasses, "."))
 		}
+	case "raise_statement":
+		e.checkRaise(node, currentFuncID)
 	}
 
 	for i := 0; i < int(node.NamedChildCount()); i++ {
-		e.walkForCalls(node.NamedChild(i), currentFuncID, currentClassName)
+		e.walkForCalls(node.NamedChild(i), currentFuncID, currentClasses)
 	}
 }
 
+// checkRaise records the exception class of `raise PaymentError(...)` or
+// `raise PaymentError` in the enclosing function's throws property.
+// Re-raised variables (lowercase names) are not recorded.
+func (e *extractor) checkRaise(node *sitter.Node, funcID string) {
+	exc := node.NamedChild(0)
+	if exc == nil {
+		return
+	}
+	if exc.Type() == "call" {
+		exc = exc.ChildByFieldName("function")
+	}
+	if exc == nil || (exc.Type() != "identifier" && exc.Type() != "attribute") {
+		return
+	}
+	name := e.nodeText(exc)
+	last := name[strings.LastIndex(name, ".")+1:]
+	if last == "" || !unicode.IsUpper(rune(last[0])) {
+		return
+	}
+	parser.AddThrows(e.nodes, funcID, name)
+}
+
 // checkHTTPClientCall checks if a call node is an HTTP client call like
 // requests.get("/path") or httpx.post("/path") and creates appropriate nodes.
 // Returns true if the node was recognized as an HTTP client call.
@@ -928,7 +1069,7 @@ func (e *extractor) checkHTTPClientCall(node *sitter.Node, funcID string) bool {
 	}
 
 	// Extract URL path from first argument
-	path := ""
+	path, base := "", ""
 	for i := 0; i < int(node.NamedChildCount()); i++ {
 		argList := node.NamedChild(i)
 		if argList.Type() != "argument_list" {
@@ -937,7 +1078,12 @@ func (e *extractor) checkHTTPClientCall(node *sitter.Node, funcID string) bool {
 		for j := 0; j < int(argList.NamedChildCount()); j++ {
 			arg := argList.NamedChild(j)
 			if arg.Type() == "string" {
-				path = cleanStringLiteral(e.nodeText(arg))
+				raw := e.nodeText(arg)
+				if isFString(raw) {
+					path, base = extractFStringPath(raw), fStringBase(raw)
+				} else {
+					path = cleanStringLiteral(raw)
+				}
 				break
 			}
 			if arg.Type() == "concatenated_string" {
@@ -945,6 +1091,16 @@ func (e *extractor) checkHTTPClientCall(node *sitter.Node, funcID string) bool {
 				path = extractFStringPath(e.nodeText(arg))
 				break
 			}
+			if arg.Type() == "binary_operator" {
+				// A dynamic base followed by a path literal: BASE_URL + "/users".
+				left, right := arg.ChildByFieldName("left"), arg.ChildByFieldName("right")
+				if left != nil && right != nil && right.Type() == "string" && left.Type() != "string" {
+					if rest := cleanStringLiteral(e.nodeText(right)); strings.HasPrefix(rest, "/") {
+						path, base = "*"+rest, e.nodeText(left)
+					}
+				}
+				break
+			}
 		}
 		break
 	}
@@ -959,19 +1115,23 @@ func (e *extractor) checkHTTPClientCall(node *sitter.Node, funcID string) bool {
 	line := int(node.StartPoint().Row) + 1
 	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, "api_call:"+httpMethod+":"+path+":"+fmt.Sprintf("%d", line))
 
+	props := map[string]string{
+		"kind":        "api_call",
+		"http_method": httpMethod,
+		"path":        path,
+		"framework":   framework,
+	}
+	if base != "" && strings.HasPrefix(path, "*") {
+		props[parser.APICallPropBase] = base
+	}
 	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
-		Name:     httpMethod + " " + path,
-		FilePath: e.filePath,
-		Line:     line,
-		Language: string(parser.LangPython),
-		Properties: map[string]string{
-			"kind":        "api_call",
-			"http_method": httpMethod,
-			"path":        path,
-			"framework":   framework,
-		},
+		ID:         depID,
+		Type:       graph.NodeDependency,
+		Name:       httpMethod + " " + path,
+		FilePath:   e.filePath,
+		Line:       line,
+		Language:   string(parser.LangPython),
+		Properties: props,
 	})
 
 	if funcID != "" {
@@ -988,6 +1148,7 @@ func (e *extractor) checkHTTPClientCall(node *sitter.Node, funcID string) bool {
 
 // checkFunctionCall checks if a call node is a general function call and creates
 // EdgeCalls edges for: import-qualified calls, same-file calls, and self/cls calls.
+// className is the enclosing class path (see classPath), "" outside classes.
 func (e *extractor) checkFunctionCall(node *sitter.Node, funcID string, className string) {
 	if funcID == "" || node.NamedChildCount() == 0 {
 		return
@@ -1075,7 +1236,7 @@ func (e *extractor) nodeText(node *sitter.Node) string {
 // Helper functions
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
 
 func isExported(name string) bool {
@@ -1101,10 +1262,10 @@ func isConstantName(name string) bool {
 
 // cleanStringLiteral removes surrounding quotes from a Python string literal.
 func cleanStringLiteral(s string) string {
-	// Remove f-string prefix
-	s = strings.TrimPrefix(s, "f")
-	s = strings.TrimPrefix(s, "r")
-	s = strings.TrimPrefix(s, "b")
+	// Remove string prefixes (f, r, b, u and combinations like rf)
+	if i := strings.IndexAny(s, `"'`); i > 0 && strings.Trim(s[:i], "rRbBfFuU") == "" {
+		s = s[i:]
+	}
 
 	// Remove surrounding quotes
 	for _, q := range []string{`"""`, `'''`, `"`, `'`} {
@@ -1142,6 +1303,31 @@ func extractFStringPath(raw string) string {
 	return result.String()
 }
 
+// isFString reports whether a string literal has an f prefix (f"", rf"",
+// F”, ...).
+func isFString(raw string) bool {
+	i := strings.IndexAny(raw, `"'`)
+	return i > 0 && strings.ContainsAny(raw[:i], "fF")
+}
+
+// fStringBase returns the expression an f-string starts with, e.g.
+// settings.API_URL for f"{settings.API_URL}/users", or "".
+func fStringBase(raw string) string {
+	s := cleanStringLiteral(raw)
+	if !strings.HasPrefix(s, "{") {
+		return ""
+	}
+	end := strings.Index(s, "}")
+	if end < 0 {
+		return ""
+	}
+	expr := s[1:end]
+	if i := strings.IndexAny(expr, "!:"); i >= 0 {
+		expr = expr[:i]
+	}
+	return strings.TrimSpace(expr)
+}
+
 func cleanDocstring(raw string) string {
 	// Remove surrounding triple quotes
 	s := raw
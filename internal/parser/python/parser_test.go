@@ -3,6 +3,7 @@ package python
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"testing"
 
@@ -234,8 +235,8 @@ func TestLanguageAndExtensions(t *testing.T) {
 		t.Errorf("Language() = %q, want %q", p.Language(), parser.LangPython)
 	}
 	exts := p.Extensions()
-	if len(exts) != 2 || exts[0] != ".py" {
-		t.Errorf("Extensions() = %v, want [\".py\", \".pyi\"]", exts)
+	if len(exts) != 3 || exts[0] != ".py" || exts[2] != ".ipynb" {
+		t.Errorf("Extensions() = %v, want [\".py\", \".pyi\", \".ipynb\"]", exts)
 	}
 }
 
@@ -492,6 +493,41 @@ func TestParseIncludeRouter(t *testing.T) {
 	}
 }
 
+func TestParseRouterPrefixes(t *testing.T) {
+	source := `from fastapi import APIRouter
+from flask import Blueprint
+
+router = APIRouter(prefix="/users", tags=["users"])
+orders = Blueprint("orders", __name__, url_prefix="/orders/")
+
+
+@router.get("/{user_id}")
+def get_user(user_id: int):
+    pass
+
+
+@orders.route("/recent")
+def recent_orders():
+    pass
+`
+	result, err := NewParser().ParseFile("app/users.py", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeAPIEndpoint {
+			got[n.Name] = true
+		}
+	}
+	for _, want := range []string{"GET /users/{user_id}", "GET /orders/recent"} {
+		if !got[want] {
+			t.Errorf("missing endpoint %q, got %v", want, got)
+		}
+	}
+}
+
 const httpClientSource = `"""Python code with HTTP client calls."""
 
 import requests
@@ -800,6 +836,84 @@ class JsonSerializer(Serializable):
         pass
 `
 
+func TestNestedClassIDs(t *testing.T) {
+	source := `
+class Order:
+    class Builder:
+        def build(self):
+            return self.validate()
+
+        def validate(self):
+            return True
+
+
+class Invoice:
+    class Builder:
+        def build(self):
+            pass
+
+
+class Builder:
+    pass
+`
+	result, err := NewParser().ParseFile("billing.py", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	seen := make(map[string]string)
+	counts := make(map[string]int)
+	byQN := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if prev, ok := seen[n.ID]; ok {
+			t.Errorf("duplicate ID %s for %s and %s", n.ID, prev, n.QualifiedName)
+		}
+		seen[n.ID] = n.QualifiedName
+		counts[n.Name]++
+		if n.Type == graph.NodeClass {
+			byQN[n.QualifiedName] = n
+		}
+	}
+	if counts["Builder"] != 3 {
+		t.Errorf("Builder nodes = %d, want 3", counts["Builder"])
+	}
+	if counts["build"] != 2 {
+		t.Errorf("build nodes = %d, want 2", counts["build"])
+	}
+
+	nested, ok := byQN["Order.Builder"]
+	if !ok {
+		t.Fatal("expected class Order.Builder")
+	}
+	if nested.Properties["scope"] != "Order" {
+		t.Errorf("Order.Builder scope = %q, want Order", nested.Properties["scope"])
+	}
+	if top, ok := byQN["Builder"]; !ok {
+		t.Error("expected top-level Builder class")
+	} else if want := graph.NewNodeID(string(graph.NodeClass), "billing.py", "Builder"); top.ID != want {
+		t.Errorf("top-level Builder ID = %s, want unscoped %s", top.ID, want)
+	}
+
+	var validate *graph.Node
+	for _, n := range result.Nodes {
+		if n.Name == "validate" {
+			validate = n
+		}
+	}
+	if validate == nil {
+		t.Fatal("expected validate method")
+	}
+	called := false
+	for _, e := range result.Edges {
+		if _, ok := seen[e.SourceID]; ok && e.Type == graph.EdgeCalls && e.TargetID == validate.ID {
+			called = true
+		}
+	}
+	if !called {
+		t.Error("expected Order.Builder.build to call validate")
+	}
+}
+
 func TestParseProtocol(t *testing.T) {
 	p := NewParser()
 	result, err := p.ParseFile("mylib/protocols.py", []byte(protocolSource))
@@ -1087,3 +1201,184 @@ func indexByName(nodes []*graph.Node) map[string]*graph.Node {
 	}
 	return m
 }
+
+func TestParseFileDiagnostics(t *testing.T) {
+	src := `def good():
+    return 1
+
+def broken(:
+    pass
+
+def after():
+    return 2
+`
+	result, err := NewParser().ParseFile("broken.py", []byte(src))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(result.Diagnostics) == 0 {
+		t.Fatal("expected diagnostics for syntax error")
+	}
+	if d := result.Diagnostics[0]; d.Line != 4 {
+		t.Errorf("diagnostic = %+v, want line 4", d)
+	}
+	found := map[string]bool{}
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeFunction {
+			found[n.Name] = true
+		}
+	}
+	if !found["good"] || !found["after"] {
+		t.Errorf("functions = %v, want good and after extracted around the error", found)
+	}
+
+	clean, err := NewParser().ParseFile("ok.py", []byte("def ok():\n    pass\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clean.Diagnostics) != 0 {
+		t.Errorf("clean file diagnostics = %+v, want none", clean.Diagnostics)
+	}
+}
+
+func TestHTTPClientCallBase(t *testing.T) {
+	source := `import os
+import requests
+
+
+def charge(charge_id):
+    requests.get(f"{settings.PAYMENTS_URL}/charges/{charge_id}")
+    requests.post(os.environ["PAYMENTS_URL"] + "/refunds")
+    requests.get(rf"/api/local/{charge_id}")
+`
+	result, err := NewParser().ParseFile("client/charge.py", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	want := map[string]string{
+		"*/charges/*":  "settings.PAYMENTS_URL",
+		"*/refunds":    `os.environ["PAYMENTS_URL"]`,
+		"/api/local/*": "",
+	}
+	got := make(map[string]string)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeDependency && n.Properties["kind"] == "api_call" {
+			got[n.Properties["path"]] = n.Properties[parser.APICallPropBase]
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("api_call path -> base = %v, want %v", got, want)
+	}
+	for path, base := range want {
+		if got[path] != base {
+			t.Errorf("api_call %s base = %q, want %q", path, got[path], base)
+		}
+	}
+}
+
+func TestErrorSites(t *testing.T) {
+	source := `class PaymentDeclinedError(Exception):
+    pass
+
+class Receipt(Model):
+    pass
+
+def charge(amount):
+    if amount < 0:
+        raise PaymentDeclinedError("negative")
+    try:
+        send()
+    except OSError as err:
+        raise err
+    raise errors.Timeout from None
+
+class Gateway:
+    def refund(self):
+        raise ValueError
+`
+	result, err := NewParser().ParseFile("payments/gateway.py", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	wantThrows := map[string]string{"charge": "PaymentDeclinedError,errors.Timeout", "refund": "ValueError"}
+	wantErrorTypes := map[string]bool{"PaymentDeclinedError": true}
+	gotThrows := make(map[string]string)
+	gotErrorTypes := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if throws := n.Properties[parser.PropThrows]; throws != "" {
+			gotThrows[n.Name] = throws
+		}
+		if n.Properties[parser.PropErrorType] == "true" {
+			gotErrorTypes[n.Name] = true
+		}
+	}
+	if !reflect.DeepEqual(gotThrows, wantThrows) {
+		t.Errorf("throws = %v, want %v", gotThrows, wantThrows)
+	}
+	if !reflect.DeepEqual(gotErrorTypes, wantErrorTypes) {
+		t.Errorf("error types = %v, want %v", gotErrorTypes, wantErrorTypes)
+	}
+}
+
+func TestParseModelRelations(t *testing.T) {
+	source := `from typing import List, Optional
+from sqlalchemy.orm import Mapped, relationship
+from django.db import models
+
+
+class User(Base):
+    __tablename__ = "users"
+    orders: Mapped[List["Order"]] = relationship(back_populates="user")
+    profile = relationship("Profile", uselist=False)
+    roles = relationship("Role", secondary=user_roles)
+    addresses = relationship("Address")
+
+
+class Order(Base):
+    user_id = Column(Integer, ForeignKey("users.id"))
+    user = relationship("User", back_populates="orders")
+    coupon: Mapped[Optional["Coupon"]] = relationship()
+
+
+class Article(models.Model):
+    author = models.ForeignKey("auth.User", on_delete=models.CASCADE)
+    parent = models.ForeignKey("self", null=True, on_delete=models.SET_NULL)
+    detail = models.OneToOneField(to=ArticleDetail, on_delete=models.CASCADE)
+    tags = models.ManyToManyField(Tag, through="ArticleTag")
+
+
+class Plain:
+    name = "plain"
+`
+	result, err := NewParser().ParseFile("app/models.py", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	tests := []struct {
+		class, orm, relations string
+	}{
+		{"User", "sqlalchemy", "orders:Order:one_to_many,profile:Profile:one_to_one,roles:Role:many_to_many:user_roles,addresses:Address:one_to_many"},
+		{"Order", "sqlalchemy", "user:User:many_to_one,coupon:Coupon:many_to_one"},
+		{"Article", "django", "author:User:many_to_one,parent:Article:many_to_one,detail:ArticleDetail:one_to_one,tags:Tag:many_to_many:ArticleTag"},
+		{"Plain", "", ""},
+	}
+	for _, tt := range tests {
+		var found *graph.Node
+		for _, n := range result.Nodes {
+			if n.Name == tt.class {
+				found = n
+			}
+		}
+		if found == nil {
+			t.Errorf("class %s not found", tt.class)
+			continue
+		}
+		if got := found.Properties[parser.PropORM]; got != tt.orm {
+			t.Errorf("%s orm = %q, want %q", tt.class, got, tt.orm)
+		}
+		if got := found.Properties[parser.PropModelRelations]; got != tt.relations {
+			t.Errorf("%s relations = %q, want %q", tt.class, got, tt.relations)
+		}
+	}
+}
@@ -14,6 +14,7 @@ type Registry struct {
 	order         []Language
 	fallback      Parser   // fallback parser for files with no registered language parser
 	excludeExts   []string // extensions to exclude from fallback processing
+	testPatterns  *TestPatterns
 }
 
 // NewRegistry creates a new parser registry.
@@ -37,6 +38,9 @@ func (r *Registry) Register(p Parser) {
 		r.order = append(r.order, lang)
 	}
 	r.parsers[lang] = p
+	if tpa, ok := p.(TestPatternsAware); ok && r.testPatterns != nil {
+		tpa.SetTestPatterns(r.testPatterns)
+	}
 	for _, ext := range p.Extensions() {
 		r.extIndex[ext] = p
 	}
@@ -67,19 +71,20 @@ func (r *Registry) GetByExtension(ext string) (Parser, bool) {
 }
 
 // ParserForFile resolves the appropriate parser for a given file path.
-// It first tries extension-based lookup, then filename-based lookup,
+// It first tries filename-based lookup (so "pnpm-workspace.yaml" reaches the
+// manifest parser rather than the YAML one), then extension-based lookup,
 // then falls back to the generic fallback parser (if set).
 func (r *Registry) ParserForFile(filePath string) (Parser, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	ext := filepath.Ext(filePath)
-	if p, ok := r.extIndex[ext]; ok {
+	base := filepath.Base(filePath)
+	if p, ok := r.filenameIndex[base]; ok {
 		return p, true
 	}
 
-	base := filepath.Base(filePath)
-	if p, ok := r.filenameIndex[base]; ok {
+	ext := filepath.Ext(filePath)
+	if p, ok := r.extIndex[ext]; ok {
 		return p, true
 	}
 
@@ -118,6 +123,19 @@ func (r *Registry) ExcludeExtensions() []string {
 	return r.excludeExts
 }
 
+// SetTestPatterns configures project-specific test detection overrides on
+// every registered parser that supports them, including parsers registered later.
+func (r *Registry) SetTestPatterns(tp *TestPatterns) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.testPatterns = tp
+	for _, p := range r.parsers {
+		if tpa, ok := p.(TestPatternsAware); ok {
+			tpa.SetTestPatterns(tp)
+		}
+	}
+}
+
 // All returns all registered parsers in registration order.
 func (r *Registry) All() []Parser {
 	r.mu.RLock()
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"net"
+	"strings"
+)
+
+// UpstreamHost returns the host part of a proxy target such as
+// "http://backend:8080/v1", "backend:8080" or "h2c://api.default.svc",
+// without scheme, port or path, and reduced to the service name for
+// Kubernetes cluster DNS names.
+func UpstreamHost(target string) string {
+	target = strings.TrimSpace(target)
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = rest
+	}
+	if i := strings.IndexAny(target, "/?"); i >= 0 {
+		target = target[:i]
+	}
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		target = target[i+1:]
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		target = host
+	}
+	if i := strings.Index(target, ".svc"); i > 0 {
+		// <service>.<namespace>.svc.cluster.local
+		target, _, _ = strings.Cut(target[:i], ".")
+	}
+	return strings.ToLower(target)
+}
+
+// RoutePrefix normalizes a route path prefix: it starts with "/" and has no
+// trailing slash unless it is the root.
+func RoutePrefix(p string) string {
+	p = strings.TrimSpace(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if len(p) > 1 {
+		p = strings.TrimRight(p, "/")
+	}
+	if p == "" {
+		return "/"
+	}
+	return p
+}
+
+// APICallPropBase is set on api_call dependency nodes whose URL starts with
+// a dynamic base, as in `${PAYMENTS_URL}/charges` or
+// os.Getenv("PAYMENTS_URL") + "/charges". It holds the source text of the
+// base expression; the node's path then starts with "*".
+const APICallPropBase = "base"
+
+// SplitURL splits an absolute http(s) URL such as
+// "https://payments.internal:8443/api/v1?x=1" into its host, reduced as by
+// UpstreamHost, and its path ("/api/v1"). It reports false for anything
+// else, including URLs whose host is templated.
+func SplitURL(raw string) (host, path string, ok bool) {
+	raw = strings.TrimSpace(raw)
+	scheme, rest, found := strings.Cut(raw, "://")
+	if !found {
+		return "", "", false
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https":
+	default:
+		return "", "", false
+	}
+	authority := rest
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		authority, path = rest[:i], rest[i:]
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	host = UpstreamHost(authority)
+	if host == "" || strings.ContainsAny(host, "${}*") {
+		return "", "", false
+	}
+	return host, path, true
+}
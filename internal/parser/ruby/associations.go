@@ -0,0 +1,97 @@
+package ruby
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// associationCardinality maps ActiveRecord association macros to the
+// cardinality they declare.
+var associationCardinality = map[string]string{
+	"belongs_to":              parser.CardinalityManyToOne,
+	"has_one":                 parser.CardinalityOneToOne,
+	"has_many":                parser.CardinalityOneToMany,
+	"has_and_belongs_to_many": parser.CardinalityManyToMany,
+}
+
+// association reads an ActiveRecord association macro call such as
+// `has_many :line_items, class_name: "OrderLine"`. The associated model is
+// the class_name option, or the camelized association name, singularized
+// for collections. A has_many through another association is many-to-many
+// via that association's model. Polymorphic belongs_to associations name
+// no model and are skipped.
+func (e *extractor) association(macro string, argsNode *sitter.Node) (parser.ModelRelation, bool) {
+	var name string
+	for i := 0; i < int(argsNode.NamedChildCount()); i++ {
+		if child := argsNode.NamedChild(i); child.Type() == "simple_symbol" {
+			name = strings.TrimPrefix(e.nodeText(child), ":")
+			break
+		}
+	}
+	opts := e.hashOptions(argsNode)
+	if name == "" || opts["polymorphic"] == "true" {
+		return parser.ModelRelation{}, false
+	}
+
+	collection := macro == "has_many" || macro == "has_and_belongs_to_many"
+	r := parser.ModelRelation{Field: name, Cardinality: associationCardinality[macro]}
+	switch {
+	case opts["class_name"] != "":
+		r.Target = lastConstant(opts["class_name"])
+	case collection:
+		r.Target = camelize(singularize(name))
+	default:
+		r.Target = camelize(name)
+	}
+	if through := opts["through"]; through != "" {
+		r.Through = camelize(singularize(through))
+		if collection {
+			r.Cardinality = parser.CardinalityManyToMany
+		}
+	}
+	return r, true
+}
+
+// lastConstant returns the last segment of a constant path: "Admin::User"
+// yields "User".
+func lastConstant(s string) string {
+	s = strings.TrimPrefix(s, "::")
+	if i := strings.LastIndex(s, "::"); i >= 0 {
+		return s[i+2:]
+	}
+	return s
+}
+
+// camelize converts a snake_case association name to a class name:
+// "line_item" yields "LineItem".
+func camelize(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// singularize returns the singular of an English plural following the
+// common inflection rules: "categories" yields "category", "addresses"
+// yields "address" and "orders" yields "order".
+func singularize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"),
+		strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s"):
+		return s[:len(s)-1]
+	}
+	return s
+}
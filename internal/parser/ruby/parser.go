@@ -14,13 +14,20 @@ import (
 )
 
 // RubyParser extracts knowledge graph nodes and edges from Ruby source files.
-type RubyParser struct{}
+type RubyParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new Ruby parser.
 func NewParser() *RubyParser {
 	return &RubyParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *RubyParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *RubyParser) Language() parser.Language {
 	return parser.LangRuby
 }
@@ -40,17 +47,19 @@ func (p *RubyParser) ParseFile(filePath string, content []byte) (*parser.ParseRe
 	}
 
 	e := &extractor{
-		filePath: filePath,
-		content:  content,
-		tree:     tree,
+		filePath:     filePath,
+		content:      content,
+		tree:         tree,
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
 	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangRuby,
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangRuby,
+		Diagnostics: parser.TreeSitterDiagnostics(tree.RootNode(), content),
 	}, nil
 }
 
@@ -62,9 +71,13 @@ type extractor struct {
 	nodes    []*graph.Node
 	edges    []*graph.Edge
 
-	fileNodeID string
-	isTestFile bool
-	isRoutes   bool
+	fileNodeID   string
+	isTestFile   bool
+	testPatterns *parser.TestPatterns
+	isRoutes     bool
+
+	// Enclosing namespace/scope blocks of a routes file.
+	routeScopes []routeScope
 
 	// Current module namespace stack for qualified names.
 	moduleStack []string
@@ -75,7 +88,7 @@ type extractor struct {
 	currentVisibility string
 
 	// Lookup maps for function call resolution.
-	classMethodMap map[string]map[string]string // className -> methodName -> node ID
+	classMethodMap map[string]map[string]string // qualified class name -> methodName -> node ID
 }
 
 func (e *extractor) extract() {
@@ -92,7 +105,7 @@ func (e *extractor) extract() {
 
 func (e *extractor) extractFileNode() {
 	base := filepath.Base(e.filePath)
-	e.isTestFile = isTestFilename(base)
+	e.isTestFile = isTestFilename(base) || e.testPatterns.MatchesFile(e.filePath)
 
 	fileType := graph.NodeFile
 	if e.isTestFile {
@@ -148,6 +161,31 @@ func (e *extractor) currentNamespace() string {
 	return strings.Join(e.moduleStack, "::")
 }
 
+// symbolID returns the ID of a symbol declared within the modules and
+// classes of scope. Nested declarations get scoped IDs so same-named ones
+// (Order::Builder#build, Invoice::Builder#build) stay distinct; top-level
+// IDs are unchanged.
+func (e *extractor) symbolID(nodeType graph.NodeType, scope []string, name string) string {
+	return graph.SymbolKey{
+		Language: string(parser.LangRuby),
+		Type:     nodeType,
+		FilePath: e.filePath,
+		Scope:    scope,
+		Name:     name,
+	}.ID()
+}
+
+// memberID returns the ID of a method named qname declared in the current
+// scope. Methods of a class are qualified with the class name (Class#name),
+// so their scope is the namespace enclosing the class.
+func (e *extractor) memberID(nodeType graph.NodeType, className, qname string) string {
+	scope := e.moduleStack
+	if className != "" && len(scope) > 0 {
+		scope = scope[:len(scope)-1]
+	}
+	return e.symbolID(nodeType, scope, qname)
+}
+
 func (e *extractor) qualifiedName(name string) string {
 	ns := e.currentNamespace()
 	if ns != "" {
@@ -211,7 +249,7 @@ func (e *extractor) extractModule(node *sitter.Node, parentID string) {
 	endLine := int(node.EndPoint().Row) + 1
 
 	qname := e.qualifiedName(name)
-	modID := graph.NewNodeID(string(graph.NodeModule), e.filePath, qname)
+	modID := e.symbolID(graph.NodeModule, e.moduleStack, name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            modID,
@@ -275,11 +313,14 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 		nodeType = graph.NodeDBModel
 	}
 
-	classID := graph.NewNodeID(string(nodeType), e.filePath, qname)
+	classID := e.symbolID(nodeType, e.moduleStack, name)
 
 	props := make(map[string]string)
 	if superclass != "" {
 		props["bases"] = superclass
+		if parser.IsErrorBase(superclass) {
+			props[parser.PropErrorType] = "true"
+		}
 	}
 
 	e.nodes = append(e.nodes, &graph.Node{
@@ -315,6 +356,8 @@ func (e *extractor) extractClass(node *sitter.Node, parentID string) {
 
 func (e *extractor) walkClassBody(body *sitter.Node, classID, className string) {
 	var includes []string
+	var relations []parser.ModelRelation
+	var filters []string
 
 	for i := 0; i < int(body.NamedChildCount()); i++ {
 		child := body.NamedChild(i)
@@ -328,7 +371,7 @@ func (e *extractor) walkClassBody(body *sitter.Node, classID, className string)
 		case "module":
 			e.extractModule(child, classID)
 		case "call":
-			e.handleClassLevelCall(child, classID, className, &includes)
+			e.handleClassLevelCall(child, classID, className, &includes, &relations, &filters)
 		case "assignment":
 			e.extractConstant(child, classID)
 		case "identifier":
@@ -341,23 +384,34 @@ func (e *extractor) walkClassBody(body *sitter.Node, classID, className string)
 		}
 	}
 
-	// Add includes to class properties.
-	if len(includes) > 0 {
-		for _, n := range e.nodes {
-			if n.ID == classID {
-				if n.Properties == nil {
-					n.Properties = make(map[string]string)
-				}
+	// Add includes, associations and filters to class properties.
+	if len(includes) == 0 && len(relations) == 0 && len(filters) == 0 {
+		return
+	}
+	for _, n := range e.nodes {
+		if n.ID == classID {
+			if n.Properties == nil {
+				n.Properties = make(map[string]string)
+			}
+			if len(includes) > 0 {
 				n.Properties["includes"] = strings.Join(includes, ",")
-				break
 			}
+			if len(relations) > 0 {
+				n.Properties[parser.PropORM] = "active_record"
+				n.Properties[parser.PropModelRelations] = parser.FormatModelRelations(relations)
+			}
+			if len(filters) > 0 {
+				n.Properties["before_actions"] = strings.Join(filters, ",")
+			}
+			break
 		}
 	}
 }
 
 // handleClassLevelCall processes calls at class body level: include, extend,
-// attr_reader/writer/accessor, private/protected, and Rails route methods.
-func (e *extractor) handleClassLevelCall(node *sitter.Node, classID, className string, includes *[]string) {
+// attr_reader/writer/accessor, private/protected, ActiveRecord associations,
+// controller before_action filters, and Rails route methods.
+func (e *extractor) handleClassLevelCall(node *sitter.Node, classID, className string, includes *[]string, relations *[]parser.ModelRelation, filters *[]string) {
 	methodName := ""
 	var argsNode *sitter.Node
 
@@ -378,7 +432,8 @@ func (e *extractor) handleClassLevelCall(node *sitter.Node, classID, className s
 			if modName != "" {
 				*includes = append(*includes, modName)
 				// Create EdgeImplements for mixin.
-				modNodeID := graph.NewNodeID(string(graph.NodeModule), e.filePath, modName)
+				parts := strings.Split(modName, "::")
+				modNodeID := e.symbolID(graph.NodeModule, parts[:len(parts)-1], parts[len(parts)-1])
 				e.edges = append(e.edges, &graph.Edge{
 					ID:       edgeID(classID, modNodeID, string(graph.EdgeImplements)),
 					Type:     graph.EdgeImplements,
@@ -400,6 +455,21 @@ func (e *extractor) handleClassLevelCall(node *sitter.Node, classID, className s
 		}
 	case "private", "protected", "public":
 		e.currentVisibility = methodName
+	case "belongs_to", "has_one", "has_many", "has_and_belongs_to_many":
+		if argsNode != nil {
+			if r, ok := e.association(methodName, argsNode); ok {
+				*relations = append(*relations, r)
+			}
+		}
+	case "before_action", "prepend_before_action":
+		// before_action :authenticate_user!, except: [:index]
+		if argsNode != nil {
+			for i := 0; i < int(argsNode.NamedChildCount()); i++ {
+				if arg := argsNode.NamedChild(i); arg.Type() == "simple_symbol" {
+					*filters = append(*filters, strings.TrimPrefix(e.nodeText(arg), ":"))
+				}
+			}
+		}
 	}
 }
 
@@ -411,7 +481,7 @@ func (e *extractor) extractAttrAccessors(argsNode *sitter.Node, classID, classNa
 			line := int(child.StartPoint().Row) + 1
 			qname := className + "#" + attrName
 
-			methodID := graph.NewNodeID(string(graph.NodeMethod), e.filePath, qname)
+			methodID := e.memberID(graph.NodeMethod, className, qname)
 			e.nodes = append(e.nodes, &graph.Node{
 				ID:            methodID,
 				Type:          graph.NodeMethod,
@@ -477,7 +547,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string)
 	if className == "" {
 		nodeType = graph.NodeFunction
 	}
-	if e.isTestFile && isTestMethodName(name, e.filePath) {
+	if e.isTestFile && (isTestMethodName(name, e.filePath) || e.testPatterns.MatchesFunction(name)) {
 		nodeType = graph.NodeTestFunction
 	}
 
@@ -486,7 +556,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, className string)
 		qname = className + "#" + name
 	}
 
-	methodID := graph.NewNodeID(string(nodeType), e.filePath, qname)
+	methodID := e.memberID(nodeType, className, qname)
 
 	props := map[string]string{
 		"visibility": visibility,
@@ -546,7 +616,7 @@ func (e *extractor) extractSingletonMethod(node *sitter.Node, parentID, classNam
 		qname = className + "." + name
 	}
 
-	methodID := graph.NewNodeID(string(graph.NodeMethod), e.filePath, qname)
+	methodID := e.memberID(graph.NodeMethod, className, qname)
 
 	props := map[string]string{
 		"visibility": "public",
@@ -634,12 +704,25 @@ func (e *extractor) extractCall(node *sitter.Node, parentID string) bool {
 		return true
 	}
 
-	// Handle Rails routes: get/post/put/delete/patch.
+	// Handle Rails routes: get/post/put/delete/patch, within namespace and
+	// scope blocks.
 	if e.isRoutes {
 		if httpMethod, ok := httpMethods[methodName]; ok {
 			e.extractRouteEndpoint(node, parentID, httpMethod, argsNode)
 			return true
 		}
+		if methodName == "namespace" || methodName == "scope" {
+			e.extractRouteScope(node, parentID, methodName, argsNode)
+			return true
+		}
+	}
+
+	// Handle sidekiq-cron: Sidekiq::Cron::Job.create(name: ..., cron: ..., class: ...).
+	if methodName == "create" && argsNode != nil {
+		if recv := node.ChildByFieldName("receiver"); recv != nil && strings.HasSuffix(e.nodeText(recv), "Cron::Job") {
+			e.extractSidekiqCronJob(node, parentID, argsNode)
+			return true
+		}
 	}
 
 	// Handle RSpec describe/it blocks for test extraction.
@@ -653,6 +736,69 @@ func (e *extractor) extractCall(node *sitter.Node, parentID string) bool {
 	return false
 }
 
+// extractSidekiqCronJob records a sidekiq-cron job. Its worker class is
+// resolved by the linker.
+func (e *extractor) extractSidekiqCronJob(node *sitter.Node, parentID string, argsNode *sitter.Node) {
+	opts := e.hashOptions(argsNode)
+	schedule, worker := opts["cron"], opts["class"]
+	if schedule == "" || worker == "" {
+		return
+	}
+	name := opts["name"]
+	if name == "" {
+		name = worker
+	}
+	job := parser.NewJobNode(e.filePath, parser.LangRuby, parser.Job{
+		Name:      name,
+		Schedule:  schedule,
+		Framework: "sidekiq-cron",
+		Handler:   worker,
+		Line:      int(node.StartPoint().Row) + 1,
+	})
+	e.nodes = append(e.nodes, job)
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(parentID, job.ID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: parentID,
+		TargetID: job.ID,
+	})
+}
+
+// hashOptions returns the string, symbol, constant and boolean values of
+// the hash pairs in an argument list, keyed by name: `cron: '...'`, `:cron => '...'` and
+// `'cron' => '...'` all yield "cron".
+func (e *extractor) hashOptions(argsNode *sitter.Node) map[string]string {
+	opts := make(map[string]string)
+	for i := 0; i < int(argsNode.NamedChildCount()); i++ {
+		pair := argsNode.NamedChild(i)
+		if pair.Type() != "pair" {
+			continue
+		}
+		key, value := pair.ChildByFieldName("key"), pair.ChildByFieldName("value")
+		if key == nil || value == nil {
+			continue
+		}
+		var k string
+		switch key.Type() {
+		case "hash_key_symbol":
+			k = e.nodeText(key)
+		case "simple_symbol":
+			k = strings.TrimPrefix(e.nodeText(key), ":")
+		case "string":
+			k = e.extractStringContent(key)
+		}
+		switch value.Type() {
+		case "string":
+			opts[k] = e.extractStringContent(value)
+		case "constant", "scope_resolution", "true", "false":
+			opts[k] = e.nodeText(value)
+		case "simple_symbol":
+			opts[k] = strings.TrimPrefix(e.nodeText(value), ":")
+		}
+	}
+	return opts
+}
+
 func (e *extractor) extractRequire(node *sitter.Node, parentID, kind string, argsNode *sitter.Node) {
 	if argsNode == nil {
 		return
@@ -663,27 +809,81 @@ func (e *extractor) extractRequire(node *sitter.Node, parentID, kind string, arg
 		return
 	}
 
-	line := int(node.StartPoint().Row) + 1
-	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, name)
-
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
+	dep, edge := parser.Import{
+		Language: parser.LangRuby,
+		Kind:     kind,
 		Name:     name,
 		FilePath: e.filePath,
-		Line:     line,
-		Language: string(parser.LangRuby),
-		Properties: map[string]string{
-			"kind": kind,
-		},
-	})
+		Line:     int(node.StartPoint().Row) + 1,
+		Local:    kind == "require_relative" || strings.HasPrefix(name, "."),
+	}.Link(parentID)
+	e.nodes = append(e.nodes, dep)
+	e.edges = append(e.edges, edge)
+}
 
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(parentID, depID, string(graph.EdgeImports)),
-		Type:     graph.EdgeImports,
-		SourceID: parentID,
-		TargetID: depID,
-	})
+// routeScope is a namespace or scope block of a routes file: the path
+// prefix and controller module it adds to the routes inside.
+type routeScope struct {
+	path   string
+	module string
+}
+
+// extractRouteScope walks the block of a namespace or scope call with its
+// path prefix and controller module in effect:
+//
+//	namespace :admin do ... end            # /admin, Admin:: controllers
+//	namespace :api, path: 'v1' do ... end  # /v1, Api:: controllers
+//	scope '/beta' do ... end               # /beta
+//	scope module: 'internal' do ... end    # Internal:: controllers only
+func (e *extractor) extractRouteScope(node *sitter.Node, parentID, kind string, argsNode *sitter.Node) {
+	var sc routeScope
+	if argsNode != nil {
+		opts := e.hashOptions(argsNode)
+		if kind == "namespace" {
+			name := e.extractFirstStringArg(argsNode)
+			for i := 0; i < int(argsNode.NamedChildCount()) && name == ""; i++ {
+				if child := argsNode.NamedChild(i); child.Type() == "simple_symbol" {
+					name = strings.TrimPrefix(e.nodeText(child), ":")
+				}
+			}
+			sc = routeScope{path: name, module: name}
+		} else {
+			sc = routeScope{path: e.extractFirstStringArg(argsNode), module: opts["module"]}
+		}
+		if p, ok := opts["path"]; ok {
+			sc.path = p
+		}
+	}
+
+	e.routeScopes = append(e.routeScopes, sc)
+	defer func() { e.routeScopes = e.routeScopes[:len(e.routeScopes)-1] }()
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); child.Type() == "do_block" || child.Type() == "block" {
+			e.walkDoBlock(child, parentID)
+		}
+	}
+}
+
+// scopedRoute returns a route's path and controller#action with the
+// enclosing namespace and scope blocks applied.
+func (e *extractor) scopedRoute(path, controller string) (string, string) {
+	var segments, modules []string
+	for _, sc := range e.routeScopes {
+		if p := strings.Trim(sc.path, "/"); p != "" {
+			segments = append(segments, p)
+		}
+		if m := strings.Trim(sc.module, "/"); m != "" {
+			modules = append(modules, m)
+		}
+	}
+	if len(segments) > 0 {
+		path = "/" + strings.Join(append(segments, strings.TrimLeft(path, "/")), "/")
+		path = strings.TrimSuffix(path, "/")
+	}
+	if len(modules) > 0 && controller != "" && !strings.HasPrefix(controller, "/") {
+		controller = strings.Join(modules, "/") + "/" + controller
+	}
+	return path, controller
 }
 
 func (e *extractor) extractRouteEndpoint(node *sitter.Node, parentID, httpMethod string, argsNode *sitter.Node) {
@@ -695,6 +895,8 @@ func (e *extractor) extractRouteEndpoint(node *sitter.Node, parentID, httpMethod
 	if path == "" {
 		return
 	}
+	// Extract controller#action from `to:` option.
+	path, controller := e.scopedRoute(path, e.extractToOption(argsNode))
 
 	line := int(node.StartPoint().Row) + 1
 	endpointName := httpMethod + " " + path
@@ -705,9 +907,6 @@ func (e *extractor) extractRouteEndpoint(node *sitter.Node, parentID, httpMethod
 		"http_method": httpMethod,
 		"path":        path,
 	}
-
-	// Extract controller#action from `to:` option.
-	controller := e.extractToOption(argsNode)
 	if controller != "" {
 		props["controller"] = controller
 	}
@@ -812,7 +1011,7 @@ func (e *extractor) extractConstant(node *sitter.Node, parentID string) {
 	name := e.nodeText(left)
 	line := int(node.StartPoint().Row) + 1
 
-	constID := graph.NewNodeID(string(graph.NodeConstant), e.filePath, e.qualifiedName(name))
+	constID := e.symbolID(graph.NodeConstant, e.moduleStack, name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:       constID,
@@ -841,12 +1040,13 @@ func (e *extractor) buildCallMaps() {
 	for _, n := range e.nodes {
 		switch n.Type {
 		case graph.NodeMethod, graph.NodeTestFunction:
-			className := n.Properties["class"]
-			if className != "" {
-				if e.classMethodMap[className] == nil {
-					e.classMethodMap[className] = make(map[string]string)
+			// Methods of a class share its namespace, the class's
+			// qualified name.
+			if n.Properties["class"] != "" {
+				if e.classMethodMap[n.Package] == nil {
+					e.classMethodMap[n.Package] = make(map[string]string)
 				}
-				e.classMethodMap[className][n.Name] = n.ID
+				e.classMethodMap[n.Package][n.Name] = n.ID
 			}
 		}
 	}
@@ -864,7 +1064,22 @@ func (e *extractor) walkForCallsRoot(root *sitter.Node) {
 	}
 }
 
+// walkModuleForCalls walks the classes of a module, tracking the module in
+// moduleStack so method IDs resolve to the scoped IDs of the first pass.
 func (e *extractor) walkModuleForCalls(modNode *sitter.Node) {
+	name := ""
+	for i := 0; i < int(modNode.NamedChildCount()); i++ {
+		if child := modNode.NamedChild(i); child.Type() == "constant" {
+			name = e.nodeText(child)
+			break
+		}
+	}
+	if name == "" {
+		return
+	}
+	e.moduleStack = append(e.moduleStack, name)
+	defer func() { e.moduleStack = e.moduleStack[:len(e.moduleStack)-1] }()
+
 	for i := 0; i < int(modNode.NamedChildCount()); i++ {
 		child := modNode.NamedChild(i)
 		if child.Type() == "body_statement" {
@@ -896,9 +1111,19 @@ func (e *extractor) walkClassForCalls(classNode *sitter.Node) {
 	if className == "" || bodyNode == nil {
 		return
 	}
+	e.moduleStack = append(e.moduleStack, className)
+	defer func() { e.moduleStack = e.moduleStack[:len(e.moduleStack)-1] }()
 
 	for i := 0; i < int(bodyNode.NamedChildCount()); i++ {
 		child := bodyNode.NamedChild(i)
+		switch child.Type() {
+		case "class":
+			e.walkClassForCalls(child)
+			continue
+		case "module":
+			e.walkModuleForCalls(child)
+			continue
+		}
 		if child.Type() == "method" {
 			methodName := ""
 			var methodBody *sitter.Node
@@ -918,12 +1143,12 @@ func (e *extractor) walkClassForCalls(classNode *sitter.Node) {
 			// Resolve method ID.
 			qname := className + "#" + methodName
 			nodeType := graph.NodeMethod
-			if e.isTestFile && isTestMethodName(methodName, e.filePath) {
+			if e.isTestFile && (isTestMethodName(methodName, e.filePath) || e.testPatterns.MatchesFunction(methodName)) {
 				nodeType = graph.NodeTestFunction
 			}
-			methodID := graph.NewNodeID(string(nodeType), e.filePath, qname)
+			methodID := e.memberID(nodeType, className, qname)
 
-			e.scanForCalls(methodBody, methodID, className)
+			e.scanForCalls(methodBody, methodID, e.currentNamespace())
 		}
 	}
 }
@@ -950,6 +1175,8 @@ var rubyBuiltins = map[string]bool{
 	"after_action": true, "around_action": true,
 }
 
+// className is the qualified name of the enclosing class, which keys
+// classMethodMap.
 func (e *extractor) scanForCalls(node *sitter.Node, methodID, className string) {
 	if node == nil {
 		return
@@ -957,7 +1184,9 @@ func (e *extractor) scanForCalls(node *sitter.Node, methodID, className string)
 
 	switch node.Type() {
 	case "call":
-		e.checkFunctionCall(node, methodID, className)
+		if !e.checkRaise(node, methodID) {
+			e.checkFunctionCall(node, methodID, className)
+		}
 	case "identifier":
 		// In Ruby, bare method calls without arguments/parens are parsed as identifiers.
 		e.checkBareCall(node, methodID, className)
@@ -968,6 +1197,35 @@ func (e *extractor) scanForCalls(node *sitter.Node, methodID, className string)
 	}
 }
 
+// checkRaise records the exception class of `raise PaymentError, "..."`
+// or `raise PaymentError.new(...)` (or fail) in the enclosing method's
+// throws property. It reports whether node is a raise.
+func (e *extractor) checkRaise(node *sitter.Node, methodID string) bool {
+	method := node.ChildByFieldName("method")
+	if method == nil || node.ChildByFieldName("receiver") != nil {
+		return false
+	}
+	if name := e.nodeText(method); name != "raise" && name != "fail" {
+		return false
+	}
+	args := node.ChildByFieldName("arguments")
+	if args == nil || args.NamedChildCount() == 0 {
+		return true
+	}
+	exc := args.NamedChild(0)
+	if exc.Type() == "call" {
+		// PaymentError.new("...")
+		if m := exc.ChildByFieldName("method"); m == nil || e.nodeText(m) != "new" {
+			return true
+		}
+		exc = exc.ChildByFieldName("receiver")
+	}
+	if exc != nil && (exc.Type() == "constant" || exc.Type() == "scope_resolution") {
+		parser.AddThrows(e.nodes, methodID, e.nodeText(exc))
+	}
+	return true
+}
+
 // checkBareCall handles Ruby bare method calls (no parens/args) which tree-sitter
 // parses as plain identifier nodes rather than call nodes.
 func (e *extractor) checkBareCall(node *sitter.Node, methodID, className string) {
@@ -1110,5 +1368,5 @@ func isTestMethodName(name, filePath string) bool {
 // Helper functions
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
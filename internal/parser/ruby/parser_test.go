@@ -3,6 +3,7 @@ package ruby
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -229,6 +230,45 @@ func TestParseRoutes(t *testing.T) {
 	}
 }
 
+func TestParseRouteScopes(t *testing.T) {
+	content := []byte(`Rails.application.routes.draw do
+  get '/health', to: 'health#show'
+  namespace :api do
+    namespace :v1, path: 'version1' do
+      get 'users/:id', to: 'users#show'
+    end
+    scope '/beta', module: 'labs' do
+      post '/features', to: 'features#create'
+    end
+  end
+end
+`)
+	result, err := NewParser().ParseFile("config/routes.rb", content)
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeAPIEndpoint {
+			got[n.Properties["path"]] = n.Properties["controller"]
+		}
+	}
+	want := map[string]string{
+		"/health":                 "health#show",
+		"/api/version1/users/:id": "api/v1/users#show",
+		"/api/beta/features":      "api/labs/features#create",
+	}
+	if len(got) != len(want) {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+	for path, controller := range want {
+		if got[path] != controller {
+			t.Errorf("route %s controller = %q, want %q", path, got[path], controller)
+		}
+	}
+}
+
 func TestParseController(t *testing.T) {
 	_, thisFile, _, ok := runtime.Caller(0)
 	if !ok {
@@ -264,6 +304,9 @@ func TestParseController(t *testing.T) {
 		if n.Properties["bases"] != "ApplicationController" {
 			t.Errorf("PostsController bases = %q, want ApplicationController", n.Properties["bases"])
 		}
+		if n.Properties["before_actions"] != "authenticate_user!,set_locale" {
+			t.Errorf("PostsController before_actions = %q, want authenticate_user!,set_locale", n.Properties["before_actions"])
+		}
 	} else {
 		t.Error("expected PostsController class node")
 	}
@@ -527,9 +570,14 @@ end
 		}
 	}
 
-	processID := graph.NewNodeID(string(graph.NodeMethod), "app/services/user_service.rb", "UserService#process")
-	validateID := graph.NewNodeID(string(graph.NodeMethod), "app/services/user_service.rb", "UserService#validate_input")
-	greetID := graph.NewNodeID(string(graph.NodeMethod), "app/services/user_service.rb", "UserService#greet")
+	// UserService is nested in MyApp, so its methods have scoped IDs.
+	methodID := func(name string) string {
+		return graph.SymbolKey{Language: string(parser.LangRuby), Type: graph.NodeMethod, FilePath: "app/services/user_service.rb",
+			Scope: []string{"MyApp"}, Name: "UserService#" + name}.ID()
+	}
+	processID := methodID("process")
+	validateID := methodID("validate_input")
+	greetID := methodID("greet")
 
 	foundProcessValidate := false
 	foundProcessGreet := false
@@ -550,6 +598,76 @@ end
 	}
 }
 
+func TestNestedClassIDs(t *testing.T) {
+	source := `module Order
+  class Builder
+    def build
+      check_totals
+    end
+
+    def check_totals
+      true
+    end
+  end
+end
+
+module Invoice
+  class Builder
+    def build
+    end
+  end
+end
+
+class Builder
+end
+`
+	result, err := NewParser().ParseFile("billing.rb", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	seen := make(map[string]string)
+	counts := make(map[string]int)
+	byQN := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if prev, ok := seen[n.ID]; ok {
+			t.Errorf("duplicate ID %s for %s and %s", n.ID, prev, n.QualifiedName)
+		}
+		seen[n.ID] = n.QualifiedName
+		counts[n.Name]++
+		byQN[n.QualifiedName] = n
+	}
+	if counts["Builder"] != 3 {
+		t.Errorf("Builder nodes = %d, want 3", counts["Builder"])
+	}
+	if counts["build"] != 2 {
+		t.Errorf("build nodes = %d, want 2", counts["build"])
+	}
+	for _, qn := range []string{"Order::Builder", "Invoice::Builder"} {
+		if _, ok := byQN[qn]; !ok {
+			t.Errorf("expected class %s", qn)
+		}
+	}
+	if top, ok := byQN["Builder"]; !ok {
+		t.Error("expected top-level Builder class")
+	} else if want := graph.NewNodeID(string(graph.NodeClass), "billing.rb", "Builder"); top.ID != want {
+		t.Errorf("top-level Builder ID = %s, want unscoped %s", top.ID, want)
+	}
+
+	called := false
+	for _, e := range result.Edges {
+		if e.Type != graph.EdgeCalls || e.Properties["callee"] != "check_totals" {
+			continue
+		}
+		if _, ok := seen[e.SourceID]; ok && seen[e.TargetID] == "Builder#check_totals" {
+			called = true
+		}
+	}
+	if !called {
+		t.Error("expected Order::Builder#build to call check_totals")
+	}
+}
+
 func TestNonTestFileHasNoTestNodes(t *testing.T) {
 	source := `class UserService
   def test_method
@@ -598,3 +716,130 @@ func findNodeByNameAndType(nodes []*graph.Node, name string, nodeType graph.Node
 	}
 	return nil
 }
+
+func TestSidekiqCronJobs(t *testing.T) {
+	source := `Sidekiq::Cron::Job.create(name: 'Nightly report', cron: '0 3 * * *', class: 'Reports::NightlyWorker')
+Sidekiq::Cron::Job.create(:cron => '*/15 * * * *', :class => CleanupWorker)
+Sidekiq::Cron::Job.create(name: 'no schedule', class: 'OtherWorker')
+`
+	result, err := NewParser().ParseFile("config/initializers/sidekiq.rb", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	tests := []struct {
+		name, schedule, handler string
+	}{
+		{"Nightly report", "0 3 * * *", "Reports::NightlyWorker"},
+		{"CleanupWorker", "*/15 * * * *", "CleanupWorker"},
+	}
+	jobs := 0
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeJob {
+			jobs++
+		}
+	}
+	if jobs != len(tests) {
+		t.Errorf("got %d Job nodes, want %d", jobs, len(tests))
+	}
+	for _, tt := range tests {
+		job := findNodeByNameAndType(result.Nodes, tt.name, graph.NodeJob)
+		if job == nil {
+			t.Errorf("missing Job %q", tt.name)
+			continue
+		}
+		if got := job.Properties[parser.JobPropSchedule]; got != tt.schedule {
+			t.Errorf("Job %q schedule = %q, want %q", tt.name, got, tt.schedule)
+		}
+		if got := job.Properties[parser.JobPropHandler]; got != tt.handler {
+			t.Errorf("Job %q handler = %q, want %q", tt.name, got, tt.handler)
+		}
+		if got := job.Properties[parser.JobPropFramework]; got != "sidekiq-cron" {
+			t.Errorf("Job %q framework = %q, want sidekiq-cron", tt.name, got)
+		}
+	}
+}
+
+func TestErrorSites(t *testing.T) {
+	source := `module Payments
+  class DeclinedError < StandardError; end
+end
+
+class Gateway
+  def charge(amount)
+    raise Payments::DeclinedError, "negative" if amount < 0
+    raise CardError.new("expired")
+    raise "plain message"
+  end
+end
+`
+	result, err := NewParser().ParseFile("app/services/gateway.rb", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	wantThrows := map[string]string{"charge": "Payments::DeclinedError,CardError"}
+	wantErrorTypes := map[string]bool{"DeclinedError": true}
+	gotThrows := make(map[string]string)
+	gotErrorTypes := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if throws := n.Properties[parser.PropThrows]; throws != "" {
+			gotThrows[n.Name] = throws
+		}
+		if n.Properties[parser.PropErrorType] == "true" {
+			gotErrorTypes[n.Name] = true
+		}
+	}
+	if !reflect.DeepEqual(gotThrows, wantThrows) {
+		t.Errorf("throws = %v, want %v", gotThrows, wantThrows)
+	}
+	if !reflect.DeepEqual(gotErrorTypes, wantErrorTypes) {
+		t.Errorf("error types = %v, want %v", gotErrorTypes, wantErrorTypes)
+	}
+}
+
+func TestParseAssociations(t *testing.T) {
+	content := []byte(`class Order < ApplicationRecord
+  belongs_to :customer
+  belongs_to :owner, class_name: "Admin::User"
+  belongs_to :billable, polymorphic: true
+  has_one :invoice
+  has_many :line_items, dependent: :destroy
+  has_many :categories
+  has_many :tags, through: :taggings
+  has_and_belongs_to_many :coupons
+end
+
+class Report
+  has_many :rows
+end
+`)
+	result, err := NewParser().ParseFile("app/models/order.rb", content)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	tests := []struct {
+		class, orm, relations string
+	}{
+		{"Order", "active_record", "customer:Customer:many_to_one,owner:User:many_to_one,invoice:Invoice:one_to_one," +
+			"line_items:LineItem:one_to_many,categories:Category:one_to_many,tags:Tag:many_to_many:Tagging,coupons:Coupon:many_to_many"},
+		{"Report", "active_record", "rows:Row:one_to_many"},
+	}
+	for _, tt := range tests {
+		var found *graph.Node
+		for _, n := range result.Nodes {
+			if n.Name == tt.class {
+				found = n
+			}
+		}
+		if found == nil {
+			t.Errorf("class %s not found", tt.class)
+			continue
+		}
+		if got := found.Properties[parser.PropORM]; got != tt.orm {
+			t.Errorf("%s orm = %q, want %q", tt.class, got, tt.orm)
+		}
+		if got := found.Properties[parser.PropModelRelations]; got != tt.relations {
+			t.Errorf("%s relations = %q, want %q", tt.class, got, tt.relations)
+		}
+	}
+}
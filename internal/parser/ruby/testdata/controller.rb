@@ -1,4 +1,7 @@
 class PostsController < ApplicationController
+  before_action :authenticate_user!, except: [:index]
+  before_action :set_locale
+
   def index
     @posts = Post.all
     render json: @posts
@@ -14,13 +14,20 @@ import (
 )
 
 // RustParser extracts knowledge graph nodes and edges from Rust source files.
-type RustParser struct{}
+type RustParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new Rust parser.
 func NewParser() *RustParser {
 	return &RustParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *RustParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *RustParser) Language() parser.Language {
 	return parser.LangRust
 }
@@ -40,17 +47,19 @@ func (p *RustParser) ParseFile(filePath string, content []byte) (*parser.ParseRe
 	}
 
 	e := &extractor{
-		filePath: filePath,
-		content:  content,
-		tree:     tree,
+		filePath:     filePath,
+		content:      content,
+		tree:         tree,
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
 	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangRust,
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangRust,
+		Diagnostics: parser.TreeSitterDiagnostics(tree.RootNode(), content),
 	}, nil
 }
 
@@ -62,12 +71,15 @@ type extractor struct {
 	nodes    []*graph.Node
 	edges    []*graph.Edge
 
-	fileNodeID string
-	modName    string // current module name (from mod declarations or file name)
-	isTestFile bool
+	fileNodeID   string
+	modName      string   // current module name (from mod declarations or file name)
+	scope        []string // inline mod blocks enclosing the declaration, outermost first
+	isTestFile   bool
+	testPatterns *parser.TestPatterns
 
 	// Lookup maps for function call resolution (built after first pass)
 	funcMap map[string]string // funcName -> node ID
+	nodeIDs map[string]bool   // IDs of the extracted nodes
 }
 
 func (e *extractor) extract() {
@@ -76,6 +88,8 @@ func (e *extractor) extract() {
 	root := e.tree.RootNode()
 	// First pass: extract all declarations
 	e.walkRoot(root)
+	// Point trait impls at traits declared in this file
+	e.nodes, e.edges = parser.BindLocal(e.nodes, e.edges)
 	// Build call maps
 	e.buildCallMaps()
 	// Second pass: walk function bodies for call edges
@@ -84,7 +98,7 @@ func (e *extractor) extract() {
 
 func (e *extractor) extractFileNode() {
 	base := filepath.Base(e.filePath)
-	e.isTestFile = isTestFilePath(e.filePath)
+	e.isTestFile = isTestFilePath(e.filePath) || e.testPatterns.MatchesFile(e.filePath)
 
 	fileType := graph.NodeFile
 	if e.isTestFile {
@@ -192,7 +206,7 @@ func (e *extractor) extractFunction(node *sitter.Node, parentID string) {
 	}
 
 	// Check for #[test] attribute
-	isTest = e.hasTestAttribute(node)
+	isTest = e.hasTestAttribute(node) || (e.isTestFile && e.testPatterns.MatchesFunction(name))
 
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
@@ -207,7 +221,7 @@ func (e *extractor) extractFunction(node *sitter.Node, parentID string) {
 		nodeType = graph.NodeTestFunction
 	}
 
-	funcID := graph.NewNodeID(string(nodeType), e.filePath, name)
+	funcID := e.symbolID(nodeType, e.scope, name)
 
 	props := make(map[string]string)
 	if isTest {
@@ -261,7 +275,7 @@ func (e *extractor) extractStruct(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	structID := graph.NewNodeID(string(graph.NodeStruct), e.filePath, name)
+	structID := e.symbolID(graph.NodeStruct, e.scope, name)
 
 	// Extract field names
 	var fields []string
@@ -336,7 +350,7 @@ func (e *extractor) extractTrait(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	traitID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, name)
+	traitID := e.symbolID(graph.NodeInterface, e.scope, name)
 
 	// Extract method names from trait body
 	var methodNames []string
@@ -403,7 +417,7 @@ func (e *extractor) extractEnum(node *sitter.Node, parentID string) {
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
 
-	enumID := graph.NewNodeID(string(graph.NodeEnum), e.filePath, name)
+	enumID := e.symbolID(graph.NodeEnum, e.scope, name)
 
 	// Extract variant names
 	var variants []string
@@ -498,18 +512,17 @@ func (e *extractor) extractImpl(node *sitter.Node, parentID string) {
 
 	// Create implements edge if this is a trait impl
 	if traitName != "" {
-		structID := graph.NewNodeID(string(graph.NodeStruct), e.filePath, typeName)
-		traitID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, traitName)
-
-		e.edges = append(e.edges, &graph.Edge{
-			ID:       edgeID(structID, traitID, string(graph.EdgeImplements)),
-			Type:     graph.EdgeImplements,
-			SourceID: structID,
-			TargetID: traitID,
-			Properties: map[string]string{
-				"implements": traitName,
-			},
-		})
+		structID := e.symbolID(graph.NodeStruct, e.scope, typeName)
+		ref, edge := parser.Unresolved{
+			Language:   parser.LangRust,
+			TargetType: graph.NodeInterface,
+			Name:       traitName,
+			FilePath:   e.filePath,
+			Line:       int(node.StartPoint().Row) + 1,
+		}.Link(graph.EdgeImplements, structID)
+		edge.Properties = map[string]string{"implements": traitName}
+		e.nodes = append(e.nodes, ref)
+		e.edges = append(e.edges, edge)
 	}
 
 	// Extract methods from impl body
@@ -567,7 +580,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, typeName, traitNa
 		return
 	}
 
-	isTest = e.hasTestAttribute(node)
+	isTest = e.hasTestAttribute(node) || (e.isTestFile && e.testPatterns.MatchesFunction(name))
 
 	startLine := int(node.StartPoint().Row) + 1
 	endLine := int(node.EndPoint().Row) + 1
@@ -583,7 +596,7 @@ func (e *extractor) extractMethod(node *sitter.Node, parentID, typeName, traitNa
 		nodeType = graph.NodeTestFunction
 	}
 
-	methodID := graph.NewNodeID(string(nodeType), e.filePath, qualifiedName)
+	methodID := e.symbolID(nodeType, e.scope, qualifiedName)
 
 	props := make(map[string]string)
 	props["struct"] = typeName
@@ -633,27 +646,23 @@ func (e *extractor) extractUse(node *sitter.Node, parentID string) {
 		return
 	}
 
-	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, name)
-
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
+	// Paths into the current crate or module are local to it.
+	local := false
+	for _, prefix := range []string{"crate", "self", "super"} {
+		if name == prefix || strings.HasPrefix(name, prefix+"::") {
+			local = true
+		}
+	}
+	dep, edge := parser.Import{
+		Language: parser.LangRust,
+		Kind:     "use",
 		Name:     name,
 		FilePath: e.filePath,
 		Line:     int(node.StartPoint().Row) + 1,
-		Language: string(parser.LangRust),
-		Package:  e.modName,
-		Properties: map[string]string{
-			"kind": "use",
-		},
-	})
-
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(parentID, depID, string(graph.EdgeImports)),
-		Type:     graph.EdgeImports,
-		SourceID: parentID,
-		TargetID: depID,
-	})
+		Local:    local,
+	}.Link(parentID)
+	e.nodes = append(e.nodes, dep)
+	e.edges = append(e.edges, edge)
 }
 
 func (e *extractor) extractMod(node *sitter.Node, parentID string) {
@@ -674,7 +683,7 @@ func (e *extractor) extractMod(node *sitter.Node, parentID string) {
 		return
 	}
 
-	modID := graph.NewNodeID(string(graph.NodePackage), e.filePath, name)
+	modID := e.symbolID(graph.NodePackage, e.scope, name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:       modID,
@@ -695,10 +704,12 @@ func (e *extractor) extractMod(node *sitter.Node, parentID string) {
 
 	// If the mod has an inline body, walk its declarations
 	if bodyNode != nil {
+		e.scope = append(e.scope, name)
 		for i := 0; i < int(bodyNode.NamedChildCount()); i++ {
 			child := bodyNode.NamedChild(i)
 			e.extractDeclaration(child, modID)
 		}
+		e.scope = e.scope[:len(e.scope)-1]
 	}
 }
 
@@ -728,7 +739,7 @@ func (e *extractor) extractConst(node *sitter.Node, parentID string) {
 		return
 	}
 
-	constID := graph.NewNodeID(string(graph.NodeConstant), e.filePath, name)
+	constID := e.symbolID(graph.NodeConstant, e.scope, name)
 
 	props := make(map[string]string)
 	props["kind"] = "const"
@@ -784,7 +795,7 @@ func (e *extractor) extractStatic(node *sitter.Node, parentID string) {
 		return
 	}
 
-	constID := graph.NewNodeID(string(graph.NodeConstant), e.filePath, name)
+	constID := e.symbolID(graph.NodeConstant, e.scope, name)
 
 	props := make(map[string]string)
 	props["kind"] = "static"
@@ -836,7 +847,7 @@ func (e *extractor) extractTypeAlias(node *sitter.Node, parentID string) {
 		return
 	}
 
-	typeID := graph.NewNodeID(string(graph.NodeType_), e.filePath, name)
+	typeID := e.symbolID(graph.NodeType_, e.scope, name)
 
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            typeID,
@@ -886,6 +897,10 @@ func (e *extractor) hasTestAttribute(node *sitter.Node) bool {
 			if strings.Contains(text, "#[test]") || strings.Contains(text, "#[tokio::test]") {
 				return true
 			}
+			attr := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(text), "#["), "]")
+			if e.testPatterns.MatchesAnnotation([]string{attr}) {
+				return true
+			}
 		} else if prev.Type() == "line_comment" || prev.Type() == "block_comment" {
 			// Skip comments between attributes and function
 			continue
@@ -948,7 +963,9 @@ func (e *extractor) extractDocComment(node *sitter.Node) string {
 // buildCallMaps populates lookup maps from extracted nodes.
 func (e *extractor) buildCallMaps() {
 	e.funcMap = make(map[string]string)
+	e.nodeIDs = make(map[string]bool, len(e.nodes))
 	for _, n := range e.nodes {
+		e.nodeIDs[n.ID] = true
 		switch n.Type {
 		case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
 			e.funcMap[n.Name] = n.ID
@@ -966,7 +983,7 @@ func (e *extractor) walkBodiesForCalls(root *sitter.Node) {
 			if name == "" {
 				continue
 			}
-			funcID := e.funcMap[name]
+			funcID := e.extractedID(name, graph.NodeFunction, graph.NodeTestFunction)
 			if funcID == "" {
 				continue
 			}
@@ -975,10 +992,19 @@ func (e *extractor) walkBodiesForCalls(root *sitter.Node) {
 			e.walkImplBodiesForCalls(child)
 		case "mod_item":
 			// Recurse into inline mod bodies
+			modName := ""
 			for j := 0; j < int(child.NamedChildCount()); j++ {
 				gc := child.NamedChild(j)
-				if gc.Type() == "declaration_list" {
+				switch gc.Type() {
+				case "identifier":
+					modName = e.nodeText(gc)
+				case "declaration_list":
+					if modName == "" {
+						continue
+					}
+					e.scope = append(e.scope, modName)
 					e.walkBodiesForCalls(gc)
+					e.scope = e.scope[:len(e.scope)-1]
 				}
 			}
 		}
@@ -1012,27 +1038,9 @@ func (e *extractor) walkImplBodiesForCalls(implNode *sitter.Node) {
 				continue
 			}
 			qualifiedName := typeName + "." + name
-			methodID := ""
-			// Try both Method and TestFunction IDs
-			for _, nt := range []graph.NodeType{graph.NodeMethod, graph.NodeTestFunction} {
-				candidateID := graph.NewNodeID(string(nt), e.filePath, qualifiedName)
-				if _, ok := e.funcMap[name]; ok {
-					methodID = candidateID
-					break
-				}
-				// Check if this ID is in our nodes
-				for _, n := range e.nodes {
-					if n.ID == candidateID {
-						methodID = candidateID
-						break
-					}
-				}
-				if methodID != "" {
-					break
-				}
-			}
+			methodID := e.extractedID(qualifiedName, graph.NodeMethod, graph.NodeTestFunction)
 			if methodID == "" {
-				methodID = graph.NewNodeID(string(graph.NodeMethod), e.filePath, qualifiedName)
+				methodID = e.symbolID(graph.NodeMethod, e.scope, qualifiedName)
 			}
 			e.walkForCalls(child, methodID)
 		}
@@ -1114,8 +1122,15 @@ func (e *extractor) checkFunctionCall(node *sitter.Node, callerID string) {
 		return
 	}
 
-	// Look up target in our function map
-	if targetID, ok := e.funcMap[calledName]; ok {
+	// Look up target in our function map, preferring a plain function call's
+	// namesake in the caller's own module.
+	targetID, ok := e.funcMap[calledName]
+	if funcNode.Type() == "identifier" {
+		if id := e.extractedID(calledName, graph.NodeFunction, graph.NodeTestFunction); id != "" {
+			targetID, ok = id, true
+		}
+	}
+	if ok {
 		e.edges = append(e.edges, &graph.Edge{
 			ID:       edgeID(callerID, targetID, string(graph.EdgeCalls)),
 			Type:     graph.EdgeCalls,
@@ -1143,14 +1158,39 @@ func (e *extractor) nodeText(node *sitter.Node) string {
 }
 
 func (e *extractor) qualifiedName(name string) string {
+	parts := append(append([]string(nil), e.scope...), name)
 	if e.modName != "" {
-		return e.modName + "::" + name
+		parts = append([]string{e.modName}, parts...)
 	}
-	return name
+	return strings.Join(parts, "::")
+}
+
+// symbolID returns the ID of a symbol declared within the inline modules
+// of scope. Declarations in inline modules get scoped IDs so same-named
+// ones (a::new, b::new) stay distinct; top-level IDs are unchanged.
+func (e *extractor) symbolID(nodeType graph.NodeType, scope []string, name string) string {
+	return graph.SymbolKey{
+		Language: string(parser.LangRust),
+		Type:     nodeType,
+		FilePath: e.filePath,
+		Scope:    scope,
+		Name:     name,
+	}.ID()
+}
+
+// extractedID returns the ID under which the first pass extracted the
+// symbol name of the current scope as one of types, or "".
+func (e *extractor) extractedID(name string, types ...graph.NodeType) string {
+	for _, t := range types {
+		if id := e.symbolID(t, e.scope, name); e.nodeIDs[id] {
+			return id
+		}
+	}
+	return ""
 }
 
 // Helper functions
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
@@ -594,6 +594,77 @@ mod external;
 	}
 }
 
+func TestNestedModuleIDs(t *testing.T) {
+	source := `mod order {
+    pub struct Builder;
+
+    impl Builder {
+        pub fn build(&self) -> bool {
+            check_totals()
+        }
+    }
+
+    fn check_totals() -> bool {
+        true
+    }
+}
+
+mod invoice {
+    pub struct Builder;
+
+    impl Builder {
+        pub fn build(&self) {}
+    }
+
+    fn check_totals() -> bool {
+        false
+    }
+}
+
+pub struct Builder;
+`
+	result, err := NewParser().ParseFile("src/billing.rs", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	seen := make(map[string]string)
+	counts := make(map[string]int)
+	byQN := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if prev, ok := seen[n.ID]; ok {
+			t.Errorf("duplicate ID %s for %s and %s", n.ID, prev, n.QualifiedName)
+		}
+		seen[n.ID] = n.QualifiedName
+		counts[n.Name]++
+		byQN[n.QualifiedName] = n
+	}
+	for name, want := range map[string]int{"Builder": 3, "build": 2, "check_totals": 2} {
+		if counts[name] != want {
+			t.Errorf("%s nodes = %d, want %d", name, counts[name], want)
+		}
+	}
+	if top, ok := byQN["billing::Builder"]; !ok {
+		t.Error("expected top-level Builder struct")
+	} else if want := graph.NewNodeID(string(graph.NodeStruct), "src/billing.rs", "Builder"); top.ID != want {
+		t.Errorf("top-level Builder ID = %s, want unscoped %s", top.ID, want)
+	}
+
+	check, ok := byQN["billing::order::check_totals"]
+	if !ok {
+		t.Fatal("expected function billing::order::check_totals")
+	}
+	called := false
+	for _, e := range result.Edges {
+		if _, ok := seen[e.SourceID]; ok && e.Type == graph.EdgeCalls && e.TargetID == check.ID {
+			called = true
+		}
+	}
+	if !called {
+		t.Error("expected order::Builder::build to call order::check_totals")
+	}
+}
+
 func TestUseImports(t *testing.T) {
 	source := `use std::io;
 use std::collections::HashMap;
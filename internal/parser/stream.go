@@ -0,0 +1,70 @@
+package parser
+
+import "github.com/imyousuf/CodeEagle/internal/graph"
+
+// Sink receives nodes and edges as a streaming parser extracts them.
+type Sink interface {
+	AddNode(node *graph.Node) error
+	AddEdge(edge *graph.Edge) error
+}
+
+// StreamingParser is implemented by parsers that emit nodes and edges to a
+// Sink as they extract them instead of collecting a ParseResult, so a huge
+// file is never held in memory as a whole graph. Diagnostics are returned
+// once the file is done. An error from the sink stops the parse and is
+// returned.
+type StreamingParser interface {
+	Parser
+	StreamFile(filePath string, content []byte, sink Sink) ([]Diagnostic, error)
+}
+
+// Stream parses a file into sink. Streaming parsers emit as they go; other
+// parsers are run to completion and their nodes, then edges, are emitted,
+// so nothing reaches the sink when ParseFile fails.
+func Stream(p Parser, filePath string, content []byte, sink Sink) ([]Diagnostic, error) {
+	if sp, ok := p.(StreamingParser); ok {
+		return sp.StreamFile(filePath, content, sink)
+	}
+	result, err := p.ParseFile(filePath, content)
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range result.Nodes {
+		if err := sink.AddNode(n); err != nil {
+			return nil, err
+		}
+		result.Nodes[i] = nil // let the sink own the only reference
+	}
+	for i, e := range result.Edges {
+		if err := sink.AddEdge(e); err != nil {
+			return nil, err
+		}
+		result.Edges[i] = nil
+	}
+	return result.Diagnostics, nil
+}
+
+// Collect runs a streaming parser into a ParseResult, which is how
+// streaming parsers implement ParseFile.
+func Collect(p StreamingParser, filePath string, content []byte) (*ParseResult, error) {
+	result := &ParseResult{FilePath: filePath, Language: p.Language()}
+	diags, err := p.StreamFile(filePath, content, (*resultSink)(result))
+	if err != nil {
+		return nil, err
+	}
+	result.Diagnostics = diags
+	return result, nil
+}
+
+// resultSink appends to a ParseResult.
+type resultSink ParseResult
+
+func (r *resultSink) AddNode(node *graph.Node) error {
+	r.Nodes = append(r.Nodes, node)
+	return nil
+}
+
+func (r *resultSink) AddEdge(edge *graph.Edge) error {
+	r.Edges = append(r.Edges, edge)
+	return nil
+}
@@ -0,0 +1,336 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Telemetry node kinds.
+const (
+	// TelemetryKindMetric is a metric (Prometheus, StatsD, Micrometer,
+	// OpenTelemetry meters). Metric nodes carry the instrument property
+	// (counter, gauge, histogram, summary, timer, distribution, ...).
+	TelemetryKindMetric = "metric"
+	// TelemetryKindSpan is a tracing span (OpenTelemetry, .NET activities,
+	// Rust tracing).
+	TelemetryKindSpan = "span"
+	// TelemetryKindLogEvent is a log statement with a literal message or
+	// event name. Log event nodes carry the level property.
+	TelemetryKindLogEvent = "log_event"
+)
+
+// telemetryPattern matches a telemetry call. The "name" submatch is the
+// metric, span or event name; Go Prometheus options match an "opts"
+// submatch instead, holding the options literal. The "inst" submatch, when
+// present, is the instrument of a metric or the level of a log statement.
+type telemetryPattern struct {
+	re      *regexp.Regexp
+	kind    string
+	library string
+	// defines marks instrument definitions (Prometheus collectors,
+	// Micrometer builders, OpenTelemetry meter instruments). Emissions
+	// through the variable a definition is assigned to are attributed to
+	// the functions using it.
+	defines bool
+}
+
+// telemetryPatterns lists the metric, span and structured log calls per
+// language.
+var telemetryPatterns = map[Language][]telemetryPattern{
+	LangGo: {
+		{re: regexp.MustCompile(`prometheus\.(?P<inst>Counter|Gauge|Histogram|Summary)(?:Vec)?Opts\{(?P<opts>[^}]*)\}`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\.(?:Int64|Float64)(?P<inst>Counter|UpDownCounter|Histogram|Gauge|ObservableCounter|ObservableGauge|ObservableUpDownCounter)\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "opentelemetry", defines: true},
+		{re: regexp.MustCompile(`\b\w*(?i:statsd|stats|metrics)\w*\.(?P<inst>Incr|Decr|Count|Gauge|Histogram|Distribution|Timing|TimeInMilliseconds)\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "statsd"},
+		{re: regexp.MustCompile(`\b\w*(?i:tracer)\w*(?:\([^)]*\))?\.Start\(\s*[\w.]+(?:\(\))?\s*,\s*"(?P<name>[^"]+)"`), kind: TelemetryKindSpan, library: "opentelemetry"},
+		{re: regexp.MustCompile(`\b\w*(?i:log)\w*\.(?P<inst>Info|Warn|Error|Debug)(?:Context|w)?\(\s*(?:\w+\s*,\s*)?"(?P<name>[^"]+)"`), kind: TelemetryKindLogEvent, library: "log"},
+		{re: regexp.MustCompile(`\.(?P<inst>Info|Warn|Error|Debug)\(\)(?:\s*\.\w+\([^()]*(?:\([^()]*\)[^()]*)*\))*\s*\.Msg\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindLogEvent, library: "zerolog"},
+	},
+	LangPython: {
+		{re: regexp.MustCompile(`\b(?P<inst>Counter|Gauge|Histogram|Summary)\(\s*["'](?P<name>[^"']+)["']\s*,\s*["']`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\.create_(?P<inst>counter|up_down_counter|histogram|gauge|observable_counter|observable_gauge|observable_up_down_counter)\(\s*["'](?P<name>[^"']+)["']`), kind: TelemetryKindMetric, library: "opentelemetry", defines: true},
+		{re: regexp.MustCompile(`\b\w*(?i:statsd|stats|metrics)\w*\.(?P<inst>incr|decr|increment|decrement|gauge|timing|timer|histogram|distribution)\(\s*["'](?P<name>[^"']+)["']`), kind: TelemetryKindMetric, library: "statsd"},
+		{re: regexp.MustCompile(`\.start_(?:as_current_)?span\(\s*["'](?P<name>[^"']+)["']`), kind: TelemetryKindSpan, library: "opentelemetry"},
+		{re: regexp.MustCompile(`\b\w*(?i:log)\w*\.(?P<inst>info|warning|warn|error|debug|critical|exception)\(\s*["'](?P<name>[^"'\n]+)["']`), kind: TelemetryKindLogEvent, library: "log"},
+	},
+	LangTypeScript: jsTelemetryPatterns,
+	LangJavaScript: jsTelemetryPatterns,
+	LangJava: {
+		{re: regexp.MustCompile(`\b(?P<inst>Counter|Gauge|Timer|DistributionSummary|LongTaskTimer)\s*\.\s*builder\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "micrometer", defines: true},
+		{re: regexp.MustCompile(`\b\w*(?i:registry)\w*\s*\.\s*(?P<inst>counter|gauge|timer|summary)\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "micrometer", defines: true},
+		{re: regexp.MustCompile(`\b(?P<inst>Counter|Gauge|Histogram|Summary)\s*\.\s*build(?:er)?\(\)(?:\s*\.\s*\w+\([^)]*\))*?\s*\.\s*name\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\b(?P<inst>Counter|Gauge|Histogram|Summary)\s*\.\s*build\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\.\s*(?P<inst>counter|upDownCounter|histogram|gauge)Builder\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "opentelemetry", defines: true},
+		{re: regexp.MustCompile(`\b\w*(?i:statsd)\w*\.(?P<inst>incrementCounter|decrementCounter|count|gauge|recordGaugeValue|histogram|recordHistogramValue|distribution|recordDistributionValue|time|recordExecutionTime)\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "statsd"},
+		{re: regexp.MustCompile(`\.\s*spanBuilder\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindSpan, library: "opentelemetry"},
+		{re: regexp.MustCompile(`\b\w*(?i:log)\w*\.(?P<inst>info|warn|error|debug|trace)\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindLogEvent, library: "log"},
+	},
+	LangRuby: {
+		{re: regexp.MustCompile(`Prometheus::Client::(?P<inst>Counter|Gauge|Histogram|Summary)\.new\(\s*:(?P<name>\w+)`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\b\w*(?i:registry|prometheus)\w*\.(?P<inst>counter|gauge|histogram|summary)\(\s*:(?P<name>\w+)`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\b\w*(?i:statsd|stats|metrics)\w*\.(?P<inst>increment|decrement|count|gauge|timing|time|histogram|distribution)\(\s*["'](?P<name>[^"'\n]+)["']`), kind: TelemetryKindMetric, library: "statsd"},
+		{re: regexp.MustCompile(`\.(?:in_span|start_span)\(\s*["'](?P<name>[^"'\n]+)["']`), kind: TelemetryKindSpan, library: "opentelemetry"},
+		{re: regexp.MustCompile(`\b\w*(?i:log)\w*\.(?P<inst>info|warn|error|debug|fatal)[(\s]\s*["'](?P<name>[^"'\n]+)["']`), kind: TelemetryKindLogEvent, library: "log"},
+	},
+	LangRust: {
+		{re: regexp.MustCompile(`register_(?:int_)?(?P<inst>counter|gauge|histogram)(?:_vec)?!\(\s*(?:opts!\(\s*)?"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\b(?:metrics::)?(?P<inst>counter|gauge|histogram)!\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "metrics"},
+		{re: regexp.MustCompile(`\b(?:\w+_)?span!\(\s*(?:Level::\w+\s*,\s*)?"(?P<name>[^"]+)"`), kind: TelemetryKindSpan, library: "tracing"},
+		{re: regexp.MustCompile(`\b(?P<inst>info|warn|error|debug|trace)!\(\s*(?:[^;"()]*?,\s*)?"(?P<name>[^"]+)"`), kind: TelemetryKindLogEvent, library: "log"},
+	},
+	LangCSharp: {
+		{re: regexp.MustCompile(`Metrics\s*\.\s*Create(?P<inst>Counter|Gauge|Histogram|Summary)\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+		{re: regexp.MustCompile(`\.Create(?P<inst>Counter|Histogram|UpDownCounter|Gauge|ObservableCounter|ObservableGauge|ObservableUpDownCounter)<[^>]+>\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "opentelemetry", defines: true},
+		{re: regexp.MustCompile(`\b\w*(?i:statsd|stats)\w*\.(?P<inst>Increment|Decrement|Counter|Gauge|Histogram|Distribution|Timer|Timing)\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindMetric, library: "statsd"},
+		{re: regexp.MustCompile(`\.StartActivity\(\s*"(?P<name>[^"]+)"`), kind: TelemetryKindSpan, library: "opentelemetry"},
+		{re: regexp.MustCompile(`\b\w*(?i:log)\w*\.(?:Log)?(?P<inst>Information|Warning|Error|Debug|Critical|Trace|Fatal|Verbose)\(\s*(?:\w+\s*,\s*)?"(?P<name>[^"]+)"`), kind: TelemetryKindLogEvent, library: "log"},
+	},
+}
+
+var jsTelemetryPatterns = []telemetryPattern{
+	{re: regexp.MustCompile(`\bnew\s+(?:\w+\.)?(?P<inst>Counter|Gauge|Histogram|Summary)\(\s*\{[^}]*?\bname\s*:\s*['"](?P<name>[^'"]+)['"]`), kind: TelemetryKindMetric, library: "prometheus", defines: true},
+	{re: regexp.MustCompile(`\.create(?P<inst>Counter|UpDownCounter|Histogram|Gauge|ObservableCounter|ObservableGauge|ObservableUpDownCounter)\(\s*['"](?P<name>[^'"]+)['"]`), kind: TelemetryKindMetric, library: "opentelemetry", defines: true},
+	{re: regexp.MustCompile(`\b\w*(?i:statsd|stats|metrics)\w*\.(?P<inst>increment|decrement|gauge|histogram|distribution|timing|timer)\(\s*['"](?P<name>[^'"]+)['"]`), kind: TelemetryKindMetric, library: "statsd"},
+	{re: regexp.MustCompile(`\b\w*(?i:tracer)\w*\.start(?:Active)?Span\(\s*['"` + "`" + `](?P<name>[^'"` + "`" + `]+)['"` + "`" + `]`), kind: TelemetryKindSpan, library: "opentelemetry"},
+	{re: regexp.MustCompile(`\b\w*(?i:log)\w*\.(?P<inst>info|warn|error|debug|fatal)\(\s*(?:(?:\{[^{}]*\}|\w+)\s*,\s*)?['"` + "`" + `](?P<name>[^'"` + "`" + `\n]+)['"` + "`" + `]`), kind: TelemetryKindLogEvent, library: "log"},
+}
+
+// promOptsFieldRe matches the string fields of Go Prometheus options.
+var promOptsFieldRe = regexp.MustCompile(`\b(Namespace|Subsystem|Name)\s*:\s*"([^"]*)"`)
+
+// ExtractTelemetry scans source content for metric definitions and
+// emissions, tracing spans and structured log statements, and adds a
+// Telemetry node per distinct kind and name in the file, with Emits edges
+// from each enclosing function or method (or from the file node for
+// top-level definitions). Edges from instrument definitions carry
+// definition=true. Metrics emitted through a variable holding a definition
+// in the same file (requestsTotal.WithLabelValues(...).Inc()) are
+// attributed to the functions using the variable.
+func ExtractTelemetry(result *ParseResult, content []byte) *ParseResult {
+	patterns := telemetryPatterns[result.Language]
+	if len(patterns) == 0 {
+		return result
+	}
+
+	fileID := ""
+	var callables []*graph.Node
+	for _, n := range result.Nodes {
+		switch n.Type {
+		case graph.NodeFile, graph.NodeTestFile:
+			if fileID == "" {
+				fileID = n.ID
+			}
+		case graph.NodeFunction, graph.NodeMethod, graph.NodeTestFunction:
+			if n.EndLine >= n.Line && n.Line > 0 {
+				callables = append(callables, n)
+			}
+		}
+	}
+	if fileID == "" {
+		return result
+	}
+
+	signals := make(map[string]*graph.Node) // kind:name -> Telemetry node
+	edges := make(map[string]bool)
+	emit := func(sourceID string, target *graph.Node, definition bool) {
+		edgeID := graph.NewEdgeID(graph.EdgeEmits, sourceID, target.ID)
+		if edges[edgeID] {
+			return
+		}
+		edges[edgeID] = true
+		edge := &graph.Edge{
+			ID:       edgeID,
+			Type:     graph.EdgeEmits,
+			SourceID: sourceID,
+			TargetID: target.ID,
+		}
+		if definition {
+			edge.Properties = map[string]string{"definition": "true"}
+		}
+		result.Edges = append(result.Edges, edge)
+	}
+
+	variables := make(map[string]*graph.Node) // variable -> defined metric
+	for _, p := range patterns {
+		nameIdx, optsIdx, instIdx := p.re.SubexpIndex("name"), p.re.SubexpIndex("opts"), p.re.SubexpIndex("inst")
+		for _, m := range p.re.FindAllSubmatchIndex(content, -1) {
+			var name string
+			switch {
+			case nameIdx >= 0 && m[2*nameIdx] >= 0:
+				name = string(content[m[2*nameIdx]:m[2*nameIdx+1]])
+			case optsIdx >= 0 && m[2*optsIdx] >= 0:
+				name = promOptsName(content[m[2*optsIdx]:m[2*optsIdx+1]])
+			}
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			var inst string
+			if instIdx >= 0 && m[2*instIdx] >= 0 {
+				inst = string(content[m[2*instIdx]:m[2*instIdx+1]])
+			}
+			line := bytes.Count(content[:m[0]], []byte("\n")) + 1
+
+			key := p.kind + ":" + name
+			node, ok := signals[key]
+			if !ok {
+				props := map[string]string{
+					"kind":    p.kind,
+					"library": p.library,
+				}
+				switch p.kind {
+				case TelemetryKindMetric:
+					if inst != "" {
+						props["instrument"] = normalizeInstrument(inst)
+					}
+				case TelemetryKindLogEvent:
+					if inst != "" {
+						props["level"] = normalizeLogLevel(inst)
+					}
+				}
+				node = &graph.Node{
+					ID:         graph.NewNodeID(string(graph.NodeTelemetry), result.FilePath, key),
+					Type:       graph.NodeTelemetry,
+					Name:       name,
+					FilePath:   result.FilePath,
+					Line:       line,
+					Language:   string(result.Language),
+					Properties: props,
+				}
+				signals[key] = node
+				result.Nodes = append(result.Nodes, node)
+			} else if line < node.Line {
+				node.Line = line
+			}
+
+			sourceID := fileID
+			if fn := enclosingCallable(callables, line); fn != nil {
+				sourceID = fn.ID
+			}
+			emit(sourceID, node, p.defines)
+			if p.defines {
+				if v := assignedVariable(content, m[0]); len(v) > 2 && v != "self" && v != "this" {
+					variables[v] = node
+				}
+			}
+		}
+	}
+
+	for v, node := range variables {
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(v) + `\s*\.\s*\w`)
+		for _, m := range re.FindAllIndex(content, -1) {
+			line := bytes.Count(content[:m[0]], []byte("\n")) + 1
+			if fn := enclosingCallable(callables, line); fn != nil {
+				emit(fn.ID, node, false)
+			}
+		}
+	}
+	return result
+}
+
+// promOptsName returns the fully qualified metric name of Go Prometheus
+// options: Namespace, Subsystem and Name joined with underscores.
+func promOptsName(opts []byte) string {
+	fields := make(map[string]string)
+	for _, m := range promOptsFieldRe.FindAllSubmatch(opts, -1) {
+		fields[string(m[1])] = string(m[2])
+	}
+	if fields["Name"] == "" {
+		return ""
+	}
+	var parts []string
+	for _, f := range []string{"Namespace", "Subsystem", "Name"} {
+		if fields[f] != "" {
+			parts = append(parts, fields[f])
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// assignedVariable returns the variable, field or property the expression
+// starting at start is assigned to (x = ..., x := ..., x: ...), looking
+// back within the statement. Type annotations (const x: Counter = ...) and
+// declaration keywords are skipped.
+func assignedVariable(content []byte, start int) string {
+	i := start - 1
+	for limit := start - 200; i >= 0 && i > limit; i-- {
+		c := content[i]
+		if c == ';' || c == '{' || c == '}' {
+			return ""
+		}
+		if c == '=' {
+			if i > 0 && strings.IndexByte("=!<>", content[i-1]) >= 0 ||
+				i+1 < len(content) && (content[i+1] == '=' || content[i+1] == '>') {
+				return ""
+			}
+			if i > 0 && content[i-1] == ':' {
+				i--
+			}
+			break
+		}
+		if c == ':' && (i == 0 || content[i-1] != ':') && i+1 < len(content) && content[i+1] != ':' {
+			break
+		}
+	}
+	if i < 0 || i <= start-200 {
+		return ""
+	}
+	lhs := content[:i]
+	if nl := bytes.LastIndexByte(lhs, '\n'); nl >= 0 {
+		lhs = lhs[nl+1:]
+	}
+	m := assignedNameRe.FindSubmatch(lhs)
+	if m == nil {
+		return ""
+	}
+	return string(m[1])
+}
+
+// assignedNameRe matches the assigned name at the end of an assignment's
+// left-hand side, before an optional type annotation.
+var assignedNameRe = regexp.MustCompile(`([A-Za-z_]\w*)\s*(?::\s*[\w.<>\[\], ]+)?\s*$`)
+
+// normalizeInstrument maps a library's instrument or method name to a
+// common instrument name: counter, up_down_counter, gauge, histogram,
+// summary, timer or distribution.
+func normalizeInstrument(inst string) string {
+	s := strings.ToLower(strings.ReplaceAll(inst, "_", ""))
+	switch {
+	case strings.Contains(s, "updown"):
+		return "up_down_counter"
+	case strings.Contains(s, "count"), strings.Contains(s, "incr"), strings.Contains(s, "decr"):
+		return "counter"
+	case strings.Contains(s, "gauge"):
+		return "gauge"
+	case strings.Contains(s, "histogram"):
+		return "histogram"
+	case strings.Contains(s, "summary"):
+		return "summary"
+	case strings.Contains(s, "distribution"):
+		return "distribution"
+	case strings.Contains(s, "tim"):
+		return "timer"
+	}
+	return s
+}
+
+// normalizeLogLevel maps a logging method name to a level: debug, info,
+// warn, error or fatal (trace and verbose map to debug).
+func normalizeLogLevel(level string) string {
+	switch s := strings.ToLower(level); s {
+	case "information":
+		return "info"
+	case "warning":
+		return "warn"
+	case "exception":
+		return "error"
+	case "critical":
+		return "fatal"
+	case "trace", "verbose":
+		return "debug"
+	default:
+		return s
+	}
+}
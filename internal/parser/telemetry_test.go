@@ -0,0 +1,256 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestExtractTelemetry(t *testing.T) {
+	tests := []struct {
+		name string
+		lang Language
+		src  string
+		want map[string]string // name -> kind/instrument or kind/level
+	}{
+		{
+			name: "go",
+			lang: LangGo,
+			src: `var requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "payments",
+	Name:      "charges_total",
+	Help:      "Charges.",
+}, []string{"status"})
+
+func Charge(ctx context.Context) error {
+	ctx, span := otel.Tracer("payments").Start(ctx, "charge")
+	defer span.End()
+	statsd.Incr("payments.charge", nil, 1)
+	hist, _ := meter.Float64Histogram("charge.duration")
+	slog.InfoContext(ctx, "charge accepted", "id", id)
+	logger.Error(err, "charge failed")
+	log.Warn().Str("id", id).Msg("retrying charge")
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Printf("not telemetry")
+}`,
+			want: map[string]string{
+				"payments_charges_total": "metric/counter",
+				"charge":                 "span/",
+				"payments.charge":        "metric/counter",
+				"charge.duration":        "metric/histogram",
+				"charge accepted":        "log_event/info",
+				"charge failed":          "log_event/error",
+				"retrying charge":        "log_event/warn",
+			},
+		},
+		{
+			name: "python",
+			lang: LangPython,
+			src: `REQUESTS = Counter('http_requests_total', 'Requests', ['path'])
+LATENCY = Histogram("http_latency_seconds", "Latency")
+counts = Counter("hello")
+
+def handle():
+    with tracer.start_as_current_span("handle"):
+        statsd.incr("handled")
+        logger.warning("slow request")
+        log.info("payment_charged", amount=1)
+`,
+			want: map[string]string{
+				"http_requests_total":  "metric/counter",
+				"http_latency_seconds": "metric/histogram",
+				"handle":               "span/",
+				"handled":              "metric/counter",
+				"slow request":         "log_event/warn",
+				"payment_charged":      "log_event/info",
+			},
+		},
+		{
+			name: "typescript",
+			lang: LangTypeScript,
+			src: `const orders = new client.Counter({ name: 'orders_total', help: 'Orders', labelNames: ['status'] });
+const latency = meter.createHistogram("order.latency");
+export async function place() {
+  const span = tracer.startSpan('place-order');
+  metrics.increment('orders.placed');
+  logger.info({ orderId }, 'order placed');
+  console.log("not telemetry");
+}`,
+			want: map[string]string{
+				"orders_total":  "metric/counter",
+				"order.latency": "metric/histogram",
+				"place-order":   "span/",
+				"orders.placed": "metric/counter",
+				"order placed":  "log_event/info",
+			},
+		},
+		{
+			name: "java",
+			lang: LangJava,
+			src: `class OrderService {
+    private final Counter placed = Counter.builder("orders.placed").register(registry);
+    private static final Histogram latency = Histogram.build().name("order_latency_seconds").help("x").register();
+    void place() {
+        Timer t = meterRegistry.timer("orders.timer");
+        Span span = tracer.spanBuilder("place").startSpan();
+        log.info("order placed {}", id);
+    }
+}`,
+			want: map[string]string{
+				"orders.placed":         "metric/counter",
+				"order_latency_seconds": "metric/histogram",
+				"orders.timer":          "metric/timer",
+				"place":                 "span/",
+				"order placed {}":       "log_event/info",
+			},
+		},
+		{
+			name: "ruby",
+			lang: LangRuby,
+			src: `REQUESTS = prometheus.counter(:http_requests_total, docstring: 'Requests')
+def charge
+  StatsD.increment('charges')
+  tracer.in_span('charge') do
+    Rails.logger.info "charge accepted"
+  end
+end`,
+			want: map[string]string{
+				"http_requests_total": "metric/counter",
+				"charges":             "metric/counter",
+				"charge":              "span/",
+				"charge accepted":     "log_event/info",
+			},
+		},
+		{
+			name: "rust",
+			lang: LangRust,
+			src: `fn charge() {
+    let span = tracing::info_span!("charge");
+    metrics::counter!("charges_total").increment(1);
+    info!(user_id = %id, "charge accepted");
+    error!("charge failed");
+}`,
+			want: map[string]string{
+				"charge":          "span/",
+				"charges_total":   "metric/counter",
+				"charge accepted": "log_event/info",
+				"charge failed":   "log_event/error",
+			},
+		},
+		{
+			name: "csharp",
+			lang: LangCSharp,
+			src: `private static readonly Counter Charges = Metrics.CreateCounter("charges_total", "Charges");
+public void Charge() {
+    using var activity = Source.StartActivity("Charge");
+    var h = meter.CreateHistogram<double>("charge.duration");
+    _logger.LogWarning(ex, "Charge retried");
+    Log.Information("Charge accepted");
+}`,
+			want: map[string]string{
+				"charges_total":   "metric/counter",
+				"Charge":          "span/",
+				"charge.duration": "metric/histogram",
+				"Charge retried":  "log_event/warn",
+				"Charge accepted": "log_event/info",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ParseResult{
+				FilePath: "svc/file",
+				Language: tt.lang,
+				Nodes:    []*graph.Node{{ID: "file", Type: graph.NodeFile, FilePath: "svc/file"}},
+			}
+			ExtractTelemetry(result, []byte(tt.src))
+			got := make(map[string]string)
+			for _, n := range result.Nodes {
+				if n.Type == graph.NodeTelemetry {
+					got[n.Name] = n.Properties["kind"] + "/" + n.Properties["instrument"] + n.Properties["level"]
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("telemetry = %v, want %v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractTelemetry_Emitters(t *testing.T) {
+	src := `package payments
+
+var requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "charges_total",
+}, []string{"status"})
+
+func Charge() {
+	requestsTotal.WithLabelValues("ok").Inc()
+}
+
+func Refund() {
+	requestsTotal.
+		WithLabelValues("refund").Inc()
+}
+
+func Unrelated() {
+	otherRequestsTotal.Inc()
+}
+`
+	result := &ParseResult{
+		FilePath: "payments/charge.go",
+		Language: LangGo,
+		Nodes: []*graph.Node{
+			{ID: "file", Type: graph.NodeFile, FilePath: "payments/charge.go"},
+			{ID: "charge", Type: graph.NodeFunction, Name: "Charge", Line: 7, EndLine: 9},
+			{ID: "refund", Type: graph.NodeFunction, Name: "Refund", Line: 11, EndLine: 14},
+			{ID: "unrelated", Type: graph.NodeFunction, Name: "Unrelated", Line: 16, EndLine: 18},
+		},
+	}
+	ExtractTelemetry(result, []byte(src))
+
+	got := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type != graph.EdgeEmits {
+			t.Errorf("unexpected edge type %s", e.Type)
+			continue
+		}
+		got[e.SourceID] = e.Properties["definition"]
+	}
+	want := map[string]string{"file": "true", "charge": "", "refund": ""}
+	if len(got) != len(want) {
+		t.Errorf("emitters = %v, want %v", got, want)
+	}
+	for id, def := range want {
+		if d, ok := got[id]; !ok || d != def {
+			t.Errorf("emitter %s definition = %q (present %v), want %q", id, d, ok, def)
+		}
+	}
+}
+
+func TestAssignedVariable(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"var requestsTotal = promauto.NewCounter(X", "requestsTotal"},
+		{"requests := promauto.NewCounter(X", "requests"},
+		{"\trequests: promauto.NewCounter(X", "requests"},
+		{"const orders: client.Counter = X", "orders"},
+		{"private final Counter placed = X", "placed"},
+		{"self.latency = X", "latency"},
+		{"if a == X", ""},
+		{"return X", ""},
+	}
+	for _, tt := range tests {
+		if got := assignedVariable([]byte(tt.src), len(tt.src)-1); got != tt.want {
+			t.Errorf("assignedVariable(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
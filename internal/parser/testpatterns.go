@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// TestPatterns holds project-configured test detection rules. They extend
+// (never replace) the built-in per-language heuristics, so teams with
+// nonstandard conventions (e.g., *_it.go, __tests__ directories) still get
+// TestFile and TestFunction nodes. A nil *TestPatterns matches nothing.
+type TestPatterns struct {
+	// FileGlobs are matched against the slash-separated relative file path.
+	// Patterns without a "/" match the file's base name; "**" matches any
+	// number of directories.
+	FileGlobs []string
+	// FunctionPatterns are globs matched against function/method names in
+	// test files.
+	FunctionPatterns []string
+	// Annotations are extra annotation, attribute, or decorator names that
+	// mark a method in a test file as a test.
+	Annotations []string
+}
+
+// TestPatternsAware is implemented by parsers that accept project-configured
+// test detection overrides.
+type TestPatternsAware interface {
+	SetTestPatterns(tp *TestPatterns)
+}
+
+// IsEmpty reports whether no overrides are configured.
+func (tp *TestPatterns) IsEmpty() bool {
+	return tp == nil || (len(tp.FileGlobs) == 0 && len(tp.FunctionPatterns) == 0 && len(tp.Annotations) == 0)
+}
+
+// MatchesFile reports whether filePath matches any configured test file glob.
+func (tp *TestPatterns) MatchesFile(filePath string) bool {
+	if tp == nil {
+		return false
+	}
+	p := filepath.ToSlash(filePath)
+	for _, glob := range tp.FileGlobs {
+		if matchTestGlob(glob, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesFunction reports whether name matches any configured test function pattern.
+func (tp *TestPatterns) MatchesFunction(name string) bool {
+	if tp == nil {
+		return false
+	}
+	for _, pattern := range tp.FunctionPatterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesAnnotation reports whether any of the given annotations is a
+// configured test annotation. Annotation arguments and a leading "@" are
+// ignored, so "IntegrationTest" matches "@IntegrationTest(slow=true)".
+func (tp *TestPatterns) MatchesAnnotation(annotations []string) bool {
+	if tp == nil || len(tp.Annotations) == 0 {
+		return false
+	}
+	for _, ann := range annotations {
+		name := strings.TrimPrefix(strings.TrimSpace(ann), "@")
+		if idx := strings.Index(name, "("); idx > 0 {
+			name = name[:idx]
+		}
+		for _, want := range tp.Annotations {
+			if name == strings.TrimPrefix(want, "@") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchTestGlob matches a slash-separated path against a glob with "**" support.
+func matchTestGlob(glob, p string) bool {
+	glob = filepath.ToSlash(glob)
+	if !strings.Contains(glob, "/") {
+		matched, _ := path.Match(glob, path.Base(p))
+		return matched
+	}
+	return matchGlobParts(splitSlash(glob), splitSlash(p))
+}
+
+func matchGlobParts(globParts, pathParts []string) bool {
+	if len(globParts) == 0 {
+		return len(pathParts) == 0
+	}
+	if globParts[0] == "**" {
+		for i := 0; i <= len(pathParts); i++ {
+			if matchGlobParts(globParts[1:], pathParts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(pathParts) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(globParts[0], pathParts[0]); !matched {
+		return false
+	}
+	return matchGlobParts(globParts[1:], pathParts[1:])
+}
+
+func splitSlash(p string) []string {
+	var parts []string
+	for _, part := range strings.Split(p, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
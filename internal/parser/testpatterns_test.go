@@ -0,0 +1,80 @@
+package parser
+
+import "testing"
+
+func TestTestPatternsMatchesFile(t *testing.T) {
+	tp := &TestPatterns{FileGlobs: []string{"*_it.go", "**/__tests__/**", "spec/**/*.rb"}}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"pkg/store/store_it.go", true},
+		{"store_it.go", true},
+		{"pkg/store/store.go", false},
+		{"web/src/__tests__/app.js", true},
+		{"__tests__/util/helpers.ts", true},
+		{"web/src/app.js", false},
+		{"spec/models/user.rb", true},
+		{"spec/user.rb", true},
+		{"lib/spec/user.rb", false},
+	}
+	for _, tt := range tests {
+		if got := tp.MatchesFile(tt.path); got != tt.want {
+			t.Errorf("MatchesFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTestPatternsMatchesFunctionAndAnnotation(t *testing.T) {
+	tp := &TestPatterns{
+		FunctionPatterns: []string{"Should*", "it_*"},
+		Annotations:      []string{"@IntegrationTest", "rstest"},
+	}
+	if !tp.MatchesFunction("ShouldReturnUser") || !tp.MatchesFunction("it_works") {
+		t.Error("expected function patterns to match")
+	}
+	if tp.MatchesFunction("helper") {
+		t.Error("unexpected match for helper")
+	}
+	if !tp.MatchesAnnotation([]string{"Override", "IntegrationTest(slow = true)"}) {
+		t.Error("expected IntegrationTest annotation to match")
+	}
+	if !tp.MatchesAnnotation([]string{"rstest"}) {
+		t.Error("expected rstest attribute to match")
+	}
+	if tp.MatchesAnnotation([]string{"Test"}) {
+		t.Error("unexpected match for built-in annotation")
+	}
+
+	var nilTP *TestPatterns
+	if !nilTP.IsEmpty() || nilTP.MatchesFile("a_test.go") || nilTP.MatchesFunction("Test") || nilTP.MatchesAnnotation([]string{"Test"}) {
+		t.Error("nil TestPatterns should match nothing")
+	}
+}
+
+func TestRegistryAppliesTestPatterns(t *testing.T) {
+	r := NewRegistry()
+	tp := &TestPatterns{FileGlobs: []string{"*_it.go"}}
+	r.SetTestPatterns(tp)
+
+	p := &patternsParser{}
+	r.Register(p)
+	if p.tp != tp {
+		t.Error("expected patterns to be applied to parser registered after SetTestPatterns")
+	}
+
+	tp2 := &TestPatterns{}
+	r.SetTestPatterns(tp2)
+	if p.tp != tp2 {
+		t.Error("expected patterns to be applied to already-registered parser")
+	}
+}
+
+type patternsParser struct {
+	tp *TestPatterns
+}
+
+func (p *patternsParser) Language() Language                             { return LangGo }
+func (p *patternsParser) Extensions() []string                           { return []string{".go"} }
+func (p *patternsParser) ParseFile(string, []byte) (*ParseResult, error) { return &ParseResult{}, nil }
+func (p *patternsParser) SetTestPatterns(tp *TestPatterns)               { p.tp = tp }
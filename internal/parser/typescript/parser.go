@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
 	tsgrammar "github.com/smacker/go-tree-sitter/typescript/typescript"
 
 	"github.com/imyousuf/CodeEagle/internal/graph"
@@ -14,13 +16,20 @@ import (
 )
 
 // TypeScriptParser extracts knowledge graph nodes and edges from TypeScript source files.
-type TypeScriptParser struct{}
+type TypeScriptParser struct {
+	testPatterns *parser.TestPatterns
+}
 
 // NewParser creates a new TypeScript parser.
 func NewParser() *TypeScriptParser {
 	return &TypeScriptParser{}
 }
 
+// SetTestPatterns configures project-specific test detection overrides.
+func (p *TypeScriptParser) SetTestPatterns(tp *parser.TestPatterns) {
+	p.testPatterns = tp
+}
+
 func (p *TypeScriptParser) Language() parser.Language {
 	return parser.LangTypeScript
 }
@@ -30,7 +39,12 @@ func (p *TypeScriptParser) Extensions() []string {
 }
 
 func (p *TypeScriptParser) ParseFile(filePath string, content []byte) (*parser.ParseResult, error) {
+	// .tsx needs the TSX grammar: the plain TypeScript one reads JSX as
+	// type assertions and comparisons.
 	lang := tsgrammar.GetLanguage()
+	if filepath.Ext(filePath) == ".tsx" {
+		lang = tsx.GetLanguage()
+	}
 	psr := sitter.NewParser()
 	psr.SetLanguage(lang)
 
@@ -41,17 +55,19 @@ func (p *TypeScriptParser) ParseFile(filePath string, content []byte) (*parser.P
 	defer tree.Close()
 
 	e := &extractor{
-		filePath: filePath,
-		content:  content,
-		root:     tree.RootNode(),
+		filePath:     filePath,
+		content:      content,
+		root:         tree.RootNode(),
+		testPatterns: p.testPatterns,
 	}
 	e.extract()
 
 	return &parser.ParseResult{
-		Nodes:    e.nodes,
-		Edges:    e.edges,
-		FilePath: filePath,
-		Language: parser.LangTypeScript,
+		Nodes:       e.nodes,
+		Edges:       e.edges,
+		FilePath:    filePath,
+		Language:    parser.LangTypeScript,
+		Diagnostics: parser.TreeSitterDiagnostics(tree.RootNode(), content),
 	}, nil
 }
 
@@ -66,14 +82,38 @@ type extractor struct {
 	fileNodeID   string
 	moduleNodeID string
 	isTestFile   bool
+	testPatterns *parser.TestPatterns
 
 	// Lookup maps for function call graph extraction, built by buildCallMaps().
 	importNames      map[string]string            // imported module simple name → dep node ID
+	importExports    map[string]string            // local import binding → name exported by its module
 	funcNames        map[string]string            // function name → node ID
 	classMethodNames map[string]map[string]string // className → methodName → node ID
+	components       map[string]bool              // node IDs of component=true functions
+	renders          map[string]bool              // Renders edge IDs already emitted
+
+	// implements lists the interfaces each class implements, linked by
+	// linkImplements once import bindings are known.
+	implements []implementsRef
+
+	// exports lists the module's export bindings that are not plain exported
+	// declarations, as "exported=original@source" (source is empty for local
+	// bindings, original and exported are "*" for export * from).
+	exports []string
+
+	// callbackOwners maps the start byte of an inline function (route handler,
+	// test case callback, anonymous function node) to the node ID calls made
+	// inside it are attributed to.
+	callbackOwners map[uint32]string
+
+	// routerMiddleware maps a router or app variable to the middleware its
+	// use() calls apply to the routes registered on it afterwards.
+	routerMiddleware map[string][]string
 }
 
 func (e *extractor) extract() {
+	e.callbackOwners = make(map[uint32]string)
+	e.routerMiddleware = make(map[string][]string)
 	e.extractFileNode()
 	e.extractModuleNode()
 	e.walkChildren(e.root)
@@ -81,12 +121,20 @@ func (e *extractor) extract() {
 		e.extractTestFunctions(e.root)
 	}
 	e.buildCallMaps()
+	e.linkImplements()
 	e.walkAllNodes(e.root)
+	if len(e.exports) > 0 {
+		for _, n := range e.nodes {
+			if n.ID == e.moduleNodeID {
+				n.Properties = map[string]string{"exports": strings.Join(e.exports, ",")}
+			}
+		}
+	}
 }
 
 func (e *extractor) extractFileNode() {
 	base := filepath.Base(e.filePath)
-	e.isTestFile = isTestFilename(base)
+	e.isTestFile = isTestFilename(base) || e.testPatterns.MatchesFile(e.filePath)
 
 	fileType := graph.NodeFile
 	if e.isTestFile {
@@ -158,6 +206,11 @@ func (e *extractor) visitNode(node *sitter.Node) {
 		e.extractLexicalDeclaration(node, false)
 	case "module", "internal_module":
 		e.extractNamespace(node, false)
+	case "expression_statement":
+		// A namespace that is not exported parses as an expression.
+		if ns := e.findChildByType(node, "internal_module"); ns != nil {
+			e.extractNamespace(ns, false)
+		}
 	}
 }
 
@@ -169,27 +222,20 @@ func (e *extractor) extractImport(node *sitter.Node) {
 	}
 	modulePath := stripQuotes(e.nodeText(source))
 
-	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, modulePath)
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
+	dep, edge := parser.Import{
+		Language: parser.LangTypeScript,
 		Name:     modulePath,
 		FilePath: e.filePath,
 		Line:     startLine(node),
-		Language: string(parser.LangTypeScript),
-		Properties: map[string]string{
-			"kind": "import",
-		},
-	})
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, depID, string(graph.EdgeImports)),
-		Type:     graph.EdgeImports,
-		SourceID: e.moduleNodeID,
-		TargetID: depID,
-	})
+		Local:    strings.HasPrefix(modulePath, ".") || strings.HasPrefix(modulePath, "/"),
+	}.Link(e.moduleNodeID)
+	e.nodes = append(e.nodes, dep)
+	e.edges = append(e.edges, edge)
 }
 
 func (e *extractor) extractExportStatement(node *sitter.Node) {
+	e.recordExportBindings(node)
+
 	// An export_statement wraps a declaration. Walk its children to find the
 	// actual declaration and mark it as exported.
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -213,6 +259,58 @@ func (e *extractor) extractExportStatement(node *sitter.Node) {
 	}
 }
 
+// recordExportBindings records the export bindings of an export_statement
+// that the linker needs to follow re-export chains: export * from './a',
+// export { a as b } from './a', export { a as b }, and default exports.
+func (e *extractor) recordExportBindings(node *sitter.Node) {
+	source := ""
+	if src := e.findChildByFieldName(node, "source"); src != nil {
+		source = stripQuotes(e.nodeText(src))
+	}
+	isDefault := false
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case "default":
+			isDefault = true
+		case "namespace_export":
+			if id := e.findChildByType(child, "identifier"); id != nil {
+				e.exports = append(e.exports, e.nodeText(id)+"=*@"+source)
+			}
+			return
+		case "export_clause":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				spec := child.NamedChild(j)
+				nameNode := e.findChildByFieldName(spec, "name")
+				if spec.Type() != "export_specifier" || nameNode == nil {
+					continue
+				}
+				exported := e.nodeText(nameNode)
+				if alias := e.findChildByFieldName(spec, "alias"); alias != nil {
+					exported = e.nodeText(alias)
+				}
+				e.exports = append(e.exports, exported+"="+e.nodeText(nameNode)+"@"+source)
+			}
+			return
+		}
+	}
+	if source != "" {
+		e.exports = append(e.exports, "*=*@"+source)
+		return
+	}
+	if !isDefault {
+		return
+	}
+	// export default foo; export default function foo() {}
+	if value := e.findChildByFieldName(node, "value"); value != nil && value.Type() == "identifier" {
+		e.exports = append(e.exports, "default="+e.nodeText(value)+"@")
+	} else if decl := e.findChildByFieldName(node, "declaration"); decl != nil {
+		if name := e.findChildByFieldName(decl, "name"); name != nil {
+			e.exports = append(e.exports, "default="+e.nodeText(name)+"@")
+		}
+	}
+}
+
 func (e *extractor) extractClass(node *sitter.Node, exported bool) {
 	nameNode := e.findChildByFieldName(node, "name")
 	if nameNode == nil {
@@ -236,23 +334,29 @@ func (e *extractor) extractClass(node *sitter.Node, exported bool) {
 		props["decorators"] = strings.Join(decorators, ",")
 	}
 
-	classID := graph.NewNodeID(string(graph.NodeClass), e.filePath, name)
+	doc := parser.LeadingJSDoc(node, e.content)
+	doc.SetProperties(props)
+
+	scope := e.scopeOf(node)
+	classID := e.symbolID(graph.NodeClass, scope, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            classID,
 		Type:          graph.NodeClass,
 		Name:          name,
-		QualifiedName: e.filePath + "." + name,
+		QualifiedName: e.qualifiedName(scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine(node),
 		EndLine:       endLine(node),
 		Language:      string(parser.LangTypeScript),
 		Exported:      exported,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
+	parentID := e.parentOf(node)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, classID, string(graph.EdgeContains)),
+		ID:       edgeID(parentID, classID, string(graph.EdgeContains)),
 		Type:     graph.EdgeContains,
-		SourceID: e.moduleNodeID,
+		SourceID: parentID,
 		TargetID: classID,
 	})
 
@@ -262,21 +366,76 @@ func (e *extractor) extractClass(node *sitter.Node, exported bool) {
 		e.extractClassMembers(body, name, classID)
 	}
 
-	// Generate Implements edges.
 	if implStr, ok := props["implements"]; ok {
 		for _, iface := range strings.Split(implStr, ",") {
-			iface = strings.TrimSpace(iface)
-			if iface == "" {
-				continue
+			if iface = strings.TrimSpace(iface); iface != "" {
+				e.implements = append(e.implements, implementsRef{classID: classID, name: iface, line: startLine(node), scope: scope})
 			}
-			ifaceID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, iface)
+		}
+	}
+}
+
+// implementsRef is an interface named in a class's implements clause.
+type implementsRef struct {
+	classID string
+	name    string
+	line    int
+	scope   []string // namespaces enclosing the class
+}
+
+// linkImplements generates the Implements edges of the file's classes. An
+// interface declared in the file, in the class's namespace or one enclosing
+// it, is linked by its ID. An imported interface
+// is linked to the import's Dependency node, recording the local name in the
+// edge's "interface" property and the name its module exports it under in
+// "export", for the linker to resolve to the declaring module. Any other
+// interface (an ambient declaration) is an Unresolved reference.
+func (e *extractor) linkImplements() {
+	declared := make(map[string]bool)
+	for _, n := range e.nodes {
+		if n.Type == graph.NodeInterface {
+			declared[n.ID] = true
+		}
+	}
+	unresolved := make(map[string]bool)
+	for _, ref := range e.implements {
+		ifaceID := ""
+		for i := len(ref.scope); i >= 0 && !declared[ifaceID]; i-- {
+			ifaceID = e.symbolID(graph.NodeInterface, ref.scope[:i], ref.name)
+		}
+		export := e.importExports[ref.name]
+		if declared[ifaceID] {
 			e.edges = append(e.edges, &graph.Edge{
-				ID:       edgeID(classID, ifaceID, string(graph.EdgeImplements)),
+				ID:       edgeID(ref.classID, ifaceID, string(graph.EdgeImplements)),
 				Type:     graph.EdgeImplements,
-				SourceID: classID,
+				SourceID: ref.classID,
 				TargetID: ifaceID,
 			})
+			continue
 		}
+		if export == "" || export == "*" {
+			node, edge := parser.Unresolved{
+				Language:   parser.LangTypeScript,
+				TargetType: graph.NodeInterface,
+				Name:       ref.name,
+				FilePath:   e.filePath,
+				Line:       ref.line,
+			}.Link(graph.EdgeImplements, ref.classID)
+			if !unresolved[node.ID] {
+				unresolved[node.ID] = true
+				e.nodes = append(e.nodes, node)
+			}
+			e.edges = append(e.edges, edge)
+			continue
+		}
+		depID := e.importNames[ref.name]
+		e.edges = append(e.edges, &graph.Edge{
+			ID:         edgeID(ref.classID, depID, string(graph.EdgeImplements)+":"+ref.name),
+			Type:       graph.EdgeImplements,
+			SourceID:   ref.classID,
+			TargetID:   depID,
+			Properties: map[string]string{"interface": ref.name, "export": export},
+		})
 	}
 }
 
@@ -306,6 +465,9 @@ func (e *extractor) parseClassHeritage(node *sitter.Node, props map[string]strin
 		}
 		if len(extendsList) > 0 {
 			props["extends"] = strings.Join(extendsList, ",")
+			if parser.IsErrorBase(props["extends"]) {
+				props[parser.PropErrorType] = "true"
+			}
 		}
 		if len(implList) > 0 {
 			props["implements"] = strings.Join(implList, ",")
@@ -328,6 +490,12 @@ func (e *extractor) collectDecorators(node *sitter.Node) []string {
 			}
 		}
 	}
+	// Decorators on non-exported classes are children of the declaration.
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == "decorator" {
+			decorators = append(decorators, e.nodeText(child))
+		}
+	}
 	return decorators
 }
 
@@ -338,7 +506,7 @@ func (e *extractor) extractClassMembers(body *sitter.Node, className, classID st
 		case "method_definition":
 			e.extractMethod(child, className, classID)
 		case "public_field_definition":
-			// Skip field declarations for now.
+			e.extractFieldFunction(child, className, classID)
 		}
 	}
 }
@@ -348,29 +516,51 @@ func (e *extractor) extractMethod(node *sitter.Node, className, classID string)
 	if nameNode == nil {
 		return
 	}
-	name := e.nodeText(nameNode)
+	e.addMethod(node, node, e.nodeText(nameNode), className, classID)
+}
 
+// extractFieldFunction extracts a class property holding a function, e.g.
+// handleClick = async () => { ... }, as a method of the class. Other field
+// declarations are skipped.
+func (e *extractor) extractFieldFunction(node *sitter.Node, className, classID string) {
+	nameNode := e.findChildByFieldName(node, "name")
+	valueNode := e.findChildByFieldName(node, "value")
+	if nameNode == nil || valueNode == nil || !isFunctionExpression(valueNode) {
+		return
+	}
+	e.addMethod(node, valueNode, e.nodeText(nameNode), className, classID)
+}
+
+// addMethod adds a method node declared by decl, whose function is fn (the
+// method definition itself, or the value of a class property).
+func (e *extractor) addMethod(decl, fn *sitter.Node, name, className, classID string) {
 	// Build signature.
-	sig := e.buildFuncSignature(node, name)
+	sig := e.buildFuncSignature(fn, name)
 
 	// Check for async.
 	props := make(map[string]string)
 	props["receiver"] = className
-	if e.hasChildWithValue(node, "async") {
+	if e.hasChildWithValue(fn, "async") {
 		props["async"] = "true"
 	}
+	if fn.Type() == "arrow_function" {
+		props["arrow"] = "true"
+	}
+	doc := parser.LeadingJSDoc(decl, e.content)
+	doc.SetProperties(props)
 
-	methodID := graph.NewNodeID(string(graph.NodeMethod), e.filePath, className+"."+name)
+	methodID := e.symbolID(graph.NodeMethod, e.scopeOf(decl), className+"."+name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            methodID,
 		Type:          graph.NodeMethod,
 		Name:          name,
 		QualifiedName: className + "." + name,
 		FilePath:      e.filePath,
-		Line:          startLine(node),
-		EndLine:       endLine(node),
+		Line:          startLine(decl),
+		EndLine:       endLine(decl),
 		Language:      string(parser.LangTypeScript),
 		Signature:     sig,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
 	e.edges = append(e.edges, &graph.Edge{
@@ -425,23 +615,29 @@ func (e *extractor) extractInterface(node *sitter.Node, exported bool) {
 		}
 	}
 
-	ifaceID := graph.NewNodeID(string(graph.NodeInterface), e.filePath, name)
+	doc := parser.LeadingJSDoc(node, e.content)
+	doc.SetProperties(props)
+
+	scope := e.scopeOf(node)
+	ifaceID := e.symbolID(graph.NodeInterface, scope, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            ifaceID,
 		Type:          graph.NodeInterface,
 		Name:          name,
-		QualifiedName: e.filePath + "." + name,
+		QualifiedName: e.qualifiedName(scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine(node),
 		EndLine:       endLine(node),
 		Language:      string(parser.LangTypeScript),
 		Exported:      exported,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
+	parentID := e.parentOf(node)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, ifaceID, string(graph.EdgeContains)),
+		ID:       edgeID(parentID, ifaceID, string(graph.EdgeContains)),
 		Type:     graph.EdgeContains,
-		SourceID: e.moduleNodeID,
+		SourceID: parentID,
 		TargetID: ifaceID,
 	})
 }
@@ -453,22 +649,25 @@ func (e *extractor) extractTypeAlias(node *sitter.Node, exported bool) {
 	}
 	name := e.nodeText(nameNode)
 
-	typeID := graph.NewNodeID(string(graph.NodeType_), e.filePath, name)
+	scope := e.scopeOf(node)
+	typeID := e.symbolID(graph.NodeType_, scope, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            typeID,
 		Type:          graph.NodeType_,
 		Name:          name,
-		QualifiedName: e.filePath + "." + name,
+		QualifiedName: e.qualifiedName(scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine(node),
 		EndLine:       endLine(node),
 		Language:      string(parser.LangTypeScript),
 		Exported:      exported,
+		DocComment:    parser.LeadingJSDoc(node, e.content).Text,
 	})
+	parentID := e.parentOf(node)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, typeID, string(graph.EdgeContains)),
+		ID:       edgeID(parentID, typeID, string(graph.EdgeContains)),
 		Type:     graph.EdgeContains,
-		SourceID: e.moduleNodeID,
+		SourceID: parentID,
 		TargetID: typeID,
 	})
 }
@@ -501,23 +700,26 @@ func (e *extractor) extractEnum(node *sitter.Node, exported bool) {
 		}
 	}
 
-	enumID := graph.NewNodeID(string(graph.NodeEnum), e.filePath, name)
+	scope := e.scopeOf(node)
+	enumID := e.symbolID(graph.NodeEnum, scope, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            enumID,
 		Type:          graph.NodeEnum,
 		Name:          name,
-		QualifiedName: e.filePath + "." + name,
+		QualifiedName: e.qualifiedName(scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine(node),
 		EndLine:       endLine(node),
 		Language:      string(parser.LangTypeScript),
 		Exported:      exported,
+		DocComment:    parser.LeadingJSDoc(node, e.content).Text,
 		Properties:    props,
 	})
+	parentID := e.parentOf(node)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, enumID, string(graph.EdgeContains)),
+		ID:       edgeID(parentID, enumID, string(graph.EdgeContains)),
 		Type:     graph.EdgeContains,
-		SourceID: e.moduleNodeID,
+		SourceID: parentID,
 		TargetID: enumID,
 	})
 }
@@ -540,25 +742,30 @@ func (e *extractor) extractFunction(node *sitter.Node, exported bool) {
 	if e.containsJSXReturn(node) {
 		props["component"] = "true"
 	}
+	doc := parser.LeadingJSDoc(node, e.content)
+	doc.SetProperties(props)
 
-	funcID := graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
+	scope := e.scopeOf(node)
+	funcID := e.symbolID(graph.NodeFunction, scope, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            funcID,
 		Type:          graph.NodeFunction,
 		Name:          name,
-		QualifiedName: e.filePath + "." + name,
+		QualifiedName: e.qualifiedName(scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine(node),
 		EndLine:       endLine(node),
 		Language:      string(parser.LangTypeScript),
 		Exported:      exported,
 		Signature:     sig,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
+	parentID := e.parentOf(node)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, funcID, string(graph.EdgeContains)),
+		ID:       edgeID(parentID, funcID, string(graph.EdgeContains)),
 		Type:     graph.EdgeContains,
-		SourceID: e.moduleNodeID,
+		SourceID: parentID,
 		TargetID: funcID,
 	})
 }
@@ -586,13 +793,9 @@ func (e *extractor) extractVariableDeclarator(node *sitter.Node, exported bool)
 		return
 	}
 
-	switch valueNode.Type() {
-	case "arrow_function":
-		e.extractArrowFunction(node, name, valueNode, exported)
-	case "function":
+	// Variable assignments that aren't functions are skipped for now.
+	if isFunctionExpression(valueNode) {
 		e.extractArrowFunction(node, name, valueNode, exported)
-	default:
-		// It's a variable assignment, not a function. Skip for now.
 	}
 }
 
@@ -607,24 +810,29 @@ func (e *extractor) extractArrowFunction(declNode *sitter.Node, name string, fnN
 	if e.containsJSXReturn(fnNode) {
 		props["component"] = "true"
 	}
+	doc := parser.LeadingJSDoc(declNode, e.content)
+	doc.SetProperties(props)
 
-	funcID := graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
+	scope := e.scopeOf(declNode)
+	funcID := e.symbolID(graph.NodeFunction, scope, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            funcID,
 		Type:          graph.NodeFunction,
 		Name:          name,
-		QualifiedName: e.filePath + "." + name,
+		QualifiedName: e.qualifiedName(scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine(declNode),
 		EndLine:       endLine(declNode),
 		Language:      string(parser.LangTypeScript),
 		Exported:      exported,
+		DocComment:    doc.Text,
 		Properties:    props,
 	})
+	parentID := e.parentOf(declNode)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, funcID, string(graph.EdgeContains)),
+		ID:       edgeID(parentID, funcID, string(graph.EdgeContains)),
 		Type:     graph.EdgeContains,
-		SourceID: e.moduleNodeID,
+		SourceID: parentID,
 		TargetID: funcID,
 	})
 }
@@ -640,24 +848,89 @@ func (e *extractor) extractNamespace(node *sitter.Node, exported bool) {
 	}
 	name := e.nodeText(nameNode)
 
-	nsID := graph.NewNodeID(string(graph.NodeModule), e.filePath, name)
+	scope := e.scopeOf(node)
+	nsID := e.symbolID(graph.NodeModule, scope, name)
 	e.nodes = append(e.nodes, &graph.Node{
 		ID:            nsID,
 		Type:          graph.NodeModule,
 		Name:          name,
-		QualifiedName: e.filePath + "." + name,
+		QualifiedName: e.qualifiedName(scope, name),
 		FilePath:      e.filePath,
 		Line:          startLine(node),
 		EndLine:       endLine(node),
 		Language:      string(parser.LangTypeScript),
 		Exported:      exported,
 	})
+	parentID := e.parentOf(node)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, nsID, string(graph.EdgeContains)),
+		ID:       edgeID(parentID, nsID, string(graph.EdgeContains)),
 		Type:     graph.EdgeContains,
-		SourceID: e.moduleNodeID,
+		SourceID: parentID,
 		TargetID: nsID,
 	})
+
+	if body := e.findChildByFieldName(node, "body"); body != nil {
+		e.walkChildren(body)
+	}
+}
+
+// namespaceName returns the name of a namespace declaration (module or
+// internal_module), "" for other nodes.
+func (e *extractor) namespaceName(node *sitter.Node) string {
+	if t := node.Type(); t != "module" && t != "internal_module" {
+		return ""
+	}
+	nameNode := e.findChildByFieldName(node, "name")
+	if nameNode == nil {
+		nameNode = e.findChildByType(node, "identifier")
+	}
+	if nameNode == nil {
+		return ""
+	}
+	return e.nodeText(nameNode)
+}
+
+// scopeOf returns the names of the namespaces enclosing node, outermost
+// first.
+func (e *extractor) scopeOf(node *sitter.Node) []string {
+	var scope []string
+	for p := node.Parent(); p != nil; p = p.Parent() {
+		if name := e.namespaceName(p); name != "" {
+			scope = append([]string{name}, scope...)
+		}
+	}
+	return scope
+}
+
+// parentOf returns the ID of the node containing a declaration: its
+// innermost namespace, or the file's module.
+func (e *extractor) parentOf(node *sitter.Node) string {
+	for p := node.Parent(); p != nil; p = p.Parent() {
+		if name := e.namespaceName(p); name != "" {
+			return e.symbolID(graph.NodeModule, e.scopeOf(p), name)
+		}
+	}
+	return e.moduleNodeID
+}
+
+// symbolID returns the ID of a symbol declared within the namespaces of
+// scope. Symbols in namespaces get scoped IDs so same-named declarations
+// of different namespaces (Order.Builder, Invoice.Builder) stay distinct;
+// top-level symbols keep plain IDs.
+func (e *extractor) symbolID(nodeType graph.NodeType, scope []string, name string) string {
+	return graph.SymbolKey{
+		Language: string(parser.LangTypeScript),
+		Type:     nodeType,
+		FilePath: e.filePath,
+		Scope:    scope,
+		Name:     name,
+	}.ID()
+}
+
+// qualifiedName returns the file-qualified name of a symbol declared
+// within the namespaces of scope.
+func (e *extractor) qualifiedName(scope []string, name string) string {
+	return strings.Join(append(append([]string{e.filePath}, scope...), name), ".")
 }
 
 // Test function detection
@@ -700,6 +973,9 @@ func (e *extractor) extractTestFunctions(node *sitter.Node) {
 							SourceID: e.moduleNodeID,
 							TargetID: testFuncID,
 						})
+						if fn := e.lastFunctionArg(args); fn != nil {
+							e.callbackOwners[fn.StartByte()] = testFuncID
+						}
 					}
 				}
 			}
@@ -730,14 +1006,149 @@ var expressHTTPMethods = map[string]bool{
 
 func (e *extractor) walkAllNodes(node *sitter.Node) {
 	e.checkForExpressRoute(node)
+	e.checkForScheduledJob(node)
+	e.checkForThrow(node)
 	if !e.checkForHTTPClientCall(node) {
 		e.checkForFunctionCall(node)
 	}
+	e.checkForJSXRender(node)
 	for i := 0; i < int(node.ChildCount()); i++ {
 		e.walkAllNodes(node.Child(i))
 	}
 }
 
+// Throw site detection
+
+// checkForThrow records the error class of `throw new PaymentError(...)`
+// in the enclosing function's throws property. Rethrown values and throws
+// outside functions are not recorded.
+func (e *extractor) checkForThrow(node *sitter.Node) {
+	if node.Type() != "throw_statement" {
+		return
+	}
+	var thrown *sitter.Node
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if c := node.NamedChild(i); c.Type() == "new_expression" {
+			thrown = c
+			break
+		}
+	}
+	if thrown == nil {
+		return
+	}
+	ctor := e.findChildByFieldName(thrown, "constructor")
+	if ctor == nil || (ctor.Type() != "identifier" && ctor.Type() != "member_expression") {
+		return
+	}
+	parser.AddThrows(e.nodes, e.findContainingFunctionID(node), e.nodeText(ctor))
+}
+
+// Scheduled job detection
+
+// checkForScheduledJob records node-cron's cron.schedule("* * * * *", fn)
+// and the cron package's new CronJob("* * * * *", fn) as Job nodes that
+// call their handler.
+func (e *extractor) checkForScheduledJob(node *sitter.Node) {
+	var fnNode *sitter.Node
+	framework := ""
+	switch node.Type() {
+	case "call_expression":
+		fnNode = e.findChildByFieldName(node, "function")
+		if fnNode == nil || fnNode.Type() != "member_expression" {
+			return
+		}
+		if prop := e.findChildByFieldName(fnNode, "property"); prop == nil || e.nodeText(prop) != "schedule" {
+			return
+		}
+		framework = "node-cron"
+	case "new_expression":
+		fnNode = e.findChildByFieldName(node, "constructor")
+		if fnNode == nil {
+			return
+		}
+		if name := e.nodeText(fnNode); name != "CronJob" && !strings.HasSuffix(name, ".CronJob") {
+			return
+		}
+		framework = "cron"
+	default:
+		return
+	}
+
+	args := e.findChildByFieldName(node, "arguments")
+	if args == nil {
+		return
+	}
+	var argNodes []*sitter.Node
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		argNodes = append(argNodes, args.NamedChild(i))
+	}
+	if len(argNodes) < 2 || (argNodes[0].Type() != "string" && argNodes[0].Type() != "template_string") {
+		return
+	}
+	schedule := stripQuotes(e.nodeText(argNodes[0]))
+	if !parser.IsCronSchedule(schedule) {
+		return
+	}
+
+	handler := argNodes[1]
+	var handlerName, targetID string
+	switch {
+	case handler.Type() == "identifier" || handler.Type() == "member_expression":
+		handlerName = e.nodeText(handler)
+		localID, importID := e.resolveHandler(handler)
+		targetID = localID
+		if targetID == "" {
+			targetID = importID
+		}
+	case isFunctionExpression(handler):
+		targetID = e.anonymousFunctionID(handler)
+	}
+	name := handlerName
+	if name == "" {
+		name = "cron " + schedule
+	}
+
+	job := parser.NewJobNode(e.filePath, parser.LangTypeScript, parser.Job{
+		Name:      name,
+		Schedule:  schedule,
+		Framework: framework,
+		Handler:   handlerName,
+		Line:      startLine(node),
+	})
+	e.nodes = append(e.nodes, job)
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.moduleNodeID, job.ID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.moduleNodeID,
+		TargetID: job.ID,
+	})
+	if targetID != "" {
+		e.edges = append(e.edges, &graph.Edge{
+			ID:       edgeID(job.ID, targetID, string(graph.EdgeCalls)),
+			Type:     graph.EdgeCalls,
+			SourceID: job.ID,
+			TargetID: targetID,
+		})
+	}
+}
+
+// middlewareNames returns the names of Express middleware arguments: a
+// middleware identifier (requireAuth), member (auth.required), or the
+// function of a middleware factory call (passport.authenticate for
+// passport.authenticate("jwt")). Inline functions are skipped.
+func (e *extractor) middlewareNames(args []*sitter.Node) []string {
+	var names []string
+	for _, arg := range args {
+		if arg.Type() == "call_expression" {
+			arg = e.findChildByFieldName(arg, "function")
+		}
+		if arg != nil && (arg.Type() == "identifier" || arg.Type() == "member_expression") {
+			names = append(names, e.nodeText(arg))
+		}
+	}
+	return names
+}
+
 func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 	if node.Type() != "call_expression" {
 		return
@@ -774,6 +1185,13 @@ func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 		return
 	}
 
+	// Check for app.use(requireAuth) — router-wide middleware.
+	if methodName == "use" && argNodes[0].Type() != "string" && argNodes[0].Type() != "template_string" {
+		router := e.nodeText(objectNode)
+		e.routerMiddleware[router] = append(e.routerMiddleware[router], e.middlewareNames(argNodes)...)
+		return
+	}
+
 	// Check for app.use("/prefix", router) — router mount pattern.
 	if methodName == "use" && len(argNodes) >= 2 {
 		firstArg := argNodes[0]
@@ -816,23 +1234,41 @@ func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 	}
 	path := stripQuotes(e.nodeText(firstArg))
 
-	// Determine handler name from the last non-path argument.
+	// Determine handler name from the last non-path argument. Handlers
+	// defined in this file (and inline handlers, which get a synthetic function
+	// node) expose the endpoint; imported handlers are linked by a Calls edge
+	// from the endpoint to the import.
 	handlerName := ""
+	exposerID := e.moduleNodeID
+	importID := ""
 	if len(argNodes) >= 2 {
 		lastArg := argNodes[len(argNodes)-1]
 		switch lastArg.Type() {
-		case "identifier":
-			handlerName = e.nodeText(lastArg)
-		case "member_expression":
+		case "identifier", "member_expression":
 			handlerName = e.nodeText(lastArg)
+			var localID string
+			localID, importID = e.resolveHandler(lastArg)
+			if localID != "" {
+				exposerID = localID
+			}
 		default:
 			handlerName = "anonymous"
+			if isFunctionExpression(lastArg) {
+				exposerID = e.anonymousFunctionID(lastArg)
+			}
 		}
 	}
 
+	// Middleware run before the handler: those of the router, then those
+	// passed to the route, as in router.get("/me", requireAuth, getMe).
+	middleware := slices.Clone(e.routerMiddleware[e.nodeText(objectNode)])
+	if len(argNodes) > 2 {
+		middleware = append(middleware, e.middlewareNames(argNodes[1:len(argNodes)-1])...)
+	}
+
 	httpMethod := strings.ToUpper(methodName)
 	endpointID := graph.NewNodeID(string(graph.NodeAPIEndpoint), e.filePath, httpMethod+":"+path)
-	e.nodes = append(e.nodes, &graph.Node{
+	endpoint := &graph.Node{
 		ID:       endpointID,
 		Type:     graph.NodeAPIEndpoint,
 		Name:     httpMethod + " " + path,
@@ -845,13 +1281,76 @@ func (e *extractor) checkForExpressRoute(node *sitter.Node) {
 			"framework":   "express",
 			"handler":     handlerName,
 		},
-	})
+	}
+	if len(middleware) > 0 {
+		endpoint.Properties[parser.PropMiddleware] = strings.Join(middleware, ",")
+	}
+	e.nodes = append(e.nodes, endpoint)
 	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.moduleNodeID, endpointID, string(graph.EdgeExposes)),
+		ID:       edgeID(exposerID, endpointID, string(graph.EdgeExposes)),
 		Type:     graph.EdgeExposes,
-		SourceID: e.moduleNodeID,
+		SourceID: exposerID,
 		TargetID: endpointID,
 	})
+	if importID != "" {
+		props := map[string]string{"callee": handlerName}
+		if export := e.importedExport(argNodes[len(argNodes)-1]); export != "" {
+			props["export"] = export
+		}
+		e.edges = append(e.edges, &graph.Edge{
+			ID:         edgeID(endpointID, importID, string(graph.EdgeCalls)),
+			Type:       graph.EdgeCalls,
+			SourceID:   endpointID,
+			TargetID:   importID,
+			Properties: props,
+		})
+	}
+}
+
+// importedExport returns the name a reference to an imported binding has in
+// the exporting module: the original name of a named or default import
+// (fn, "default"), or the member of a namespace import (ns.fn). It returns ""
+// when the reference does not name a module export.
+func (e *extractor) importedExport(ref *sitter.Node) string {
+	switch ref.Type() {
+	case "identifier":
+		if export := e.importExports[e.nodeText(ref)]; export != "*" {
+			return export
+		}
+	case "member_expression":
+		objectNode := e.findChildByFieldName(ref, "object")
+		propertyNode := e.findChildByFieldName(ref, "property")
+		if objectNode != nil && propertyNode != nil && e.importExports[e.nodeText(objectNode)] == "*" {
+			return e.nodeText(propertyNode)
+		}
+	}
+	return ""
+}
+
+// resolveHandler resolves a named route handler (handler, Controller.list,
+// users.list) to the function or method defining it in this file, or else to
+// the dependency node of the import it comes from.
+func (e *extractor) resolveHandler(arg *sitter.Node) (localID, importID string) {
+	switch arg.Type() {
+	case "identifier":
+		name := e.nodeText(arg)
+		if id, ok := e.funcNames[name]; ok {
+			return id, ""
+		}
+		return "", e.importNames[name]
+	case "member_expression":
+		objectNode := e.findChildByFieldName(arg, "object")
+		propertyNode := e.findChildByFieldName(arg, "property")
+		if objectNode == nil || propertyNode == nil {
+			return "", ""
+		}
+		objName := e.nodeText(objectNode)
+		if id, ok := e.classMethodNames[objName][e.nodeText(propertyNode)]; ok {
+			return id, ""
+		}
+		return "", e.importNames[objName]
+	}
+	return "", ""
 }
 
 // HTTP client call detection
@@ -946,20 +1445,26 @@ func (e *extractor) checkForHTTPClientCall(node *sitter.Node) bool {
 		return false
 	}
 
+	props := map[string]string{
+		"kind":        "api_call",
+		"http_method": httpMethod,
+		"path":        path,
+		"framework":   framework,
+	}
+	if strings.HasPrefix(path, "*") {
+		if base := e.urlBaseExpr(argNodes[0]); base != "" {
+			props[parser.APICallPropBase] = base
+		}
+	}
 	depID := graph.NewNodeID(string(graph.NodeDependency), e.filePath, framework+":"+httpMethod+":"+path)
 	e.nodes = append(e.nodes, &graph.Node{
-		ID:       depID,
-		Type:     graph.NodeDependency,
-		Name:     httpMethod + " " + path,
-		FilePath: e.filePath,
-		Line:     startLine(node),
-		Language: string(parser.LangTypeScript),
-		Properties: map[string]string{
-			"kind":        "api_call",
-			"http_method": httpMethod,
-			"path":        path,
-			"framework":   framework,
-		},
+		ID:         depID,
+		Type:       graph.NodeDependency,
+		Name:       httpMethod + " " + path,
+		FilePath:   e.filePath,
+		Line:       startLine(node),
+		Language:   string(parser.LangTypeScript),
+		Properties: props,
 	})
 
 	// Find containing function and create EdgeCalls.
@@ -1017,11 +1522,47 @@ func (e *extractor) extractConcatPath(node *sitter.Node) string {
 	}
 	leftPath := e.extractConcatPath(left)
 	if leftPath == "" {
+		// A dynamic base followed by a path literal: BASE_URL + "/users".
+		right := e.findChildByFieldName(node, "right")
+		if right != nil && right.Type() == "string" {
+			if rightPath := stripQuotes(e.nodeText(right)); strings.HasPrefix(rightPath, "/") {
+				return "*" + rightPath
+			}
+		}
 		return ""
 	}
 	return leftPath + "*"
 }
 
+// urlBaseExpr returns the source text of the dynamic base a URL argument
+// starts with, such as process.env.API_URL in `${process.env.API_URL}/users`
+// or API_URL in API_URL + "/users", or "" when it starts with a literal.
+func (e *extractor) urlBaseExpr(arg *sitter.Node) string {
+	switch arg.Type() {
+	case "template_string":
+		for i := 0; i < int(arg.ChildCount()); i++ {
+			child := arg.Child(i)
+			switch child.Type() {
+			case "`":
+				continue
+			case "template_substitution":
+				text := e.nodeText(child)
+				return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "${"), "}"))
+			}
+			return ""
+		}
+	case "binary_expression":
+		left := arg
+		for left != nil && left.Type() == "binary_expression" {
+			left = e.findChildByFieldName(left, "left")
+		}
+		if left != nil && left.Type() != "string" && left.Type() != "template_string" {
+			return e.nodeText(left)
+		}
+	}
+	return ""
+}
+
 func (e *extractor) extractTemplateLiteralPath(node *sitter.Node) string {
 	// Walk template string children: template_substitution nodes become "*".
 	var parts []string
@@ -1041,7 +1582,13 @@ func (e *extractor) extractTemplateLiteralPath(node *sitter.Node) string {
 	return strings.Join(parts, "")
 }
 
+// findContainingFunctionID returns the ID of the function the calls at node
+// are attributed to: the nearest named function or method, or the owner of an
+// inline route handler or test callback. Calls in anonymous functions without
+// a named ancestor (IIFEs, module-level callbacks) go to a synthetic node for
+// the outermost one. Returns "" for module-level code.
 func (e *extractor) findContainingFunctionID(node *sitter.Node) string {
+	var anonymous *sitter.Node
 	current := node.Parent()
 	for current != nil {
 		switch current.Type() {
@@ -1049,7 +1596,7 @@ func (e *extractor) findContainingFunctionID(node *sitter.Node) string {
 			nameNode := e.findChildByFieldName(current, "name")
 			if nameNode != nil {
 				name := e.nodeText(nameNode)
-				return graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
+				return e.symbolID(graph.NodeFunction, e.scopeOf(current), name)
 			}
 		case "method_definition":
 			nameNode := e.findChildByFieldName(current, "name")
@@ -1058,25 +1605,108 @@ func (e *extractor) findContainingFunctionID(node *sitter.Node) string {
 				// Find the class name by looking for the class_declaration ancestor.
 				className := e.findAncestorClassName(current)
 				if className != "" {
-					return graph.NewNodeID(string(graph.NodeMethod), e.filePath, className+"."+methodName)
+					return e.symbolID(graph.NodeMethod, e.scopeOf(current), className+"."+methodName)
 				}
 			}
-		case "arrow_function", "function":
-			// Check if this is assigned to a variable (const foo = () => ...).
-			parent := current.Parent()
-			if parent != nil && parent.Type() == "variable_declarator" {
-				nameNode := e.findChildByFieldName(parent, "name")
-				if nameNode != nil {
-					name := e.nodeText(nameNode)
-					return graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
-				}
+		case "arrow_function", "function", "function_expression":
+			if id, ok := e.callbackOwners[current.StartByte()]; ok {
+				return id
+			}
+			if id := e.boundFunctionID(current); id != "" {
+				return id
 			}
+			anonymous = current
 		}
 		current = current.Parent()
 	}
+	if anonymous != nil {
+		return e.anonymousFunctionID(anonymous)
+	}
+	return ""
+}
+
+// boundFunctionID returns the node ID of a function expression bound to a
+// name: a variable (const foo = () => ...) or a class property
+// (handle = () => ...). Returns "" if the function is anonymous.
+func (e *extractor) boundFunctionID(fn *sitter.Node) string {
+	parent := fn.Parent()
+	if parent == nil {
+		return ""
+	}
+	switch parent.Type() {
+	case "variable_declarator":
+		if nameNode := e.findChildByFieldName(parent, "name"); nameNode != nil {
+			return e.symbolID(graph.NodeFunction, e.scopeOf(parent), e.nodeText(nameNode))
+		}
+	case "public_field_definition":
+		nameNode := e.findChildByFieldName(parent, "name")
+		className := e.findAncestorClassName(parent)
+		if nameNode != nil && className != "" {
+			return e.symbolID(graph.NodeMethod, e.scopeOf(parent), className+"."+e.nodeText(nameNode))
+		}
+	}
 	return ""
 }
 
+// anonymousFunctionID returns the ID of the synthetic node standing in for an
+// anonymous function, creating it on first use. The node is named after the
+// function's position (anonymous@line:column), which keeps its ID stable
+// across parses of unchanged code.
+func (e *extractor) anonymousFunctionID(fn *sitter.Node) string {
+	if id, ok := e.callbackOwners[fn.StartByte()]; ok {
+		return id
+	}
+
+	name := fmt.Sprintf("anonymous@%d:%d", startLine(fn), fn.StartPoint().Column+1)
+	props := map[string]string{"anonymous": "true"}
+	if fn.Type() == "arrow_function" {
+		props["arrow"] = "true"
+	}
+	if e.hasChildWithValue(fn, "async") {
+		props["async"] = "true"
+	}
+
+	funcID := graph.NewNodeID(string(graph.NodeFunction), e.filePath, name)
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:            funcID,
+		Type:          graph.NodeFunction,
+		Name:          name,
+		QualifiedName: e.filePath + "." + name,
+		FilePath:      e.filePath,
+		Line:          startLine(fn),
+		EndLine:       endLine(fn),
+		Language:      string(parser.LangTypeScript),
+		Properties:    props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.moduleNodeID, funcID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.moduleNodeID,
+		TargetID: funcID,
+	})
+	e.callbackOwners[fn.StartByte()] = funcID
+	return funcID
+}
+
+// lastFunctionArg returns the last argument that is an inline function.
+func (e *extractor) lastFunctionArg(args *sitter.Node) *sitter.Node {
+	for i := int(args.ChildCount()) - 1; i >= 0; i-- {
+		if child := args.Child(i); isFunctionExpression(child) {
+			return child
+		}
+	}
+	return nil
+}
+
+// isFunctionExpression reports whether node is an inline function.
+func isFunctionExpression(node *sitter.Node) bool {
+	switch node.Type() {
+	case "arrow_function", "function", "function_expression":
+		return true
+	}
+	return false
+}
+
 func (e *extractor) findAncestorClassName(node *sitter.Node) string {
 	current := node.Parent()
 	for current != nil {
@@ -1105,8 +1735,11 @@ var tsBuiltins = map[string]bool{
 
 func (e *extractor) buildCallMaps() {
 	e.importNames = make(map[string]string)
+	e.importExports = make(map[string]string)
 	e.funcNames = make(map[string]string)
 	e.classMethodNames = make(map[string]map[string]string)
+	e.components = make(map[string]bool)
+	e.renders = make(map[string]bool)
 
 	// Build a map from module path to dependency node ID.
 	depByModule := make(map[string]string)
@@ -1125,6 +1758,9 @@ func (e *extractor) buildCallMaps() {
 			}
 		case graph.NodeFunction, graph.NodeTestFunction:
 			e.funcNames[n.Name] = n.ID
+			if n.Properties["component"] == "true" {
+				e.components[n.ID] = true
+			}
 		case graph.NodeMethod:
 			if n.Properties != nil && n.Properties["receiver"] != "" {
 				className := n.Properties["receiver"]
@@ -1175,21 +1811,23 @@ func (e *extractor) extractImportClauseBindings(node *sitter.Node, depID string)
 		case "identifier":
 			// Default import: import axios from 'axios'
 			e.importNames[e.nodeText(child)] = depID
+			e.importExports[e.nodeText(child)] = "default"
 		case "named_imports":
 			// Named imports: import { format, parse } from './utils'
 			for j := 0; j < int(child.ChildCount()); j++ {
 				spec := child.Child(j)
 				if spec.Type() == "import_specifier" {
 					// The local name is the "name" field, or if aliased, the "alias" field.
-					alias := e.findChildByFieldName(spec, "alias")
-					if alias != nil {
-						e.importNames[e.nodeText(alias)] = depID
-					} else {
-						nameNode := e.findChildByFieldName(spec, "name")
-						if nameNode != nil {
-							e.importNames[e.nodeText(nameNode)] = depID
-						}
+					nameNode := e.findChildByFieldName(spec, "name")
+					if nameNode == nil {
+						continue
 					}
+					local := nameNode
+					if alias := e.findChildByFieldName(spec, "alias"); alias != nil {
+						local = alias
+					}
+					e.importNames[e.nodeText(local)] = depID
+					e.importExports[e.nodeText(local)] = e.nodeText(nameNode)
 				}
 			}
 		case "namespace_import":
@@ -1198,6 +1836,7 @@ func (e *extractor) extractImportClauseBindings(node *sitter.Node, depID string)
 				gc := child.Child(j)
 				if gc.Type() == "identifier" {
 					e.importNames[e.nodeText(gc)] = depID
+					e.importExports[e.nodeText(gc)] = "*"
 				}
 			}
 		}
@@ -1237,14 +1876,16 @@ func (e *extractor) checkForFunctionCall(node *sitter.Node) {
 		}
 		// Match against imports (e.g., named import used as direct call).
 		if targetID, ok := e.importNames[name]; ok {
+			props := map[string]string{"callee": name}
+			if export := e.importedExport(fnNode); export != "" {
+				props["export"] = export
+			}
 			e.edges = append(e.edges, &graph.Edge{
-				ID:       edgeID(callerID, targetID, string(graph.EdgeCalls)),
-				Type:     graph.EdgeCalls,
-				SourceID: callerID,
-				TargetID: targetID,
-				Properties: map[string]string{
-					"callee": name,
-				},
+				ID:         edgeID(callerID, targetID, string(graph.EdgeCalls)),
+				Type:       graph.EdgeCalls,
+				SourceID:   callerID,
+				TargetID:   targetID,
+				Properties: props,
 			})
 		}
 
@@ -1281,19 +1922,78 @@ func (e *extractor) checkForFunctionCall(node *sitter.Node) {
 
 		// obj.method() — match obj against imports.
 		if targetID, ok := e.importNames[objName]; ok {
+			props := map[string]string{"callee": methodName}
+			if export := e.importedExport(fnNode); export != "" {
+				props["export"] = export
+			}
 			e.edges = append(e.edges, &graph.Edge{
-				ID:       edgeID(callerID, targetID, string(graph.EdgeCalls)),
-				Type:     graph.EdgeCalls,
-				SourceID: callerID,
-				TargetID: targetID,
-				Properties: map[string]string{
-					"callee": methodName,
-				},
+				ID:         edgeID(callerID, targetID, string(graph.EdgeCalls)),
+				Type:       graph.EdgeCalls,
+				SourceID:   callerID,
+				TargetID:   targetID,
+				Properties: props,
 			})
 		}
 	}
 }
 
+// checkForJSXRender emits a Renders edge for each component a component
+// function renders: <Header /> resolves to a function in this file, and
+// <Button /> or <UI.Card> to the dependency node of the import it comes from,
+// with the imported export recorded for the linker. Intrinsic elements
+// (<div>) are skipped.
+func (e *extractor) checkForJSXRender(node *sitter.Node) {
+	if node.Type() != "jsx_opening_element" && node.Type() != "jsx_self_closing_element" {
+		return
+	}
+	nameNode := e.findChildByFieldName(node, "name")
+	if nameNode == nil {
+		return
+	}
+	callerID := e.findContainingFunctionID(node)
+	if !e.components[callerID] {
+		return
+	}
+
+	name := e.nodeText(nameNode)
+	var targetID string
+	switch nameNode.Type() {
+	case "identifier":
+		if name == "" || name[0] < 'A' || name[0] > 'Z' {
+			return
+		}
+		if id, ok := e.funcNames[name]; ok {
+			targetID = id
+		} else {
+			targetID = e.importNames[name]
+		}
+	case "member_expression":
+		if objectNode := e.findChildByFieldName(nameNode, "object"); objectNode != nil {
+			targetID = e.importNames[e.nodeText(objectNode)]
+		}
+	}
+	if targetID == "" || targetID == callerID {
+		return
+	}
+
+	id := edgeID(callerID, targetID, string(graph.EdgeRenders)+":"+name)
+	if e.renders[id] {
+		return
+	}
+	e.renders[id] = true
+	props := map[string]string{"component": name}
+	if export := e.importedExport(nameNode); export != "" {
+		props["export"] = export
+	}
+	e.edges = append(e.edges, &graph.Edge{
+		ID:         id,
+		Type:       graph.EdgeRenders,
+		SourceID:   callerID,
+		TargetID:   targetID,
+		Properties: props,
+	})
+}
+
 // lastPathComponent extracts the last segment of a module path.
 // e.g., "./utils" -> "utils", "axios" -> "axios", "@scope/pkg" -> "pkg".
 func lastPathComponent(modulePath string) string {
@@ -1387,5 +2087,5 @@ func extractBaseTypeName(s string) string {
 }
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
@@ -3,6 +3,7 @@ package typescript
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"testing"
 
@@ -318,6 +319,109 @@ export class MyService {
 	}
 }
 
+func TestDecoratorsCaptured(t *testing.T) {
+	source := `
+@Injectable()
+export class UsersService {}
+
+@Controller('users')
+class UsersController {
+  constructor(private readonly users: UsersService) {}
+}
+`
+	p := NewParser()
+	result, err := p.ParseFile("users.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nodeByName := indexByName(result.Nodes)
+	for name, want := range map[string]string{
+		"UsersService":    "@Injectable()",
+		"UsersController": "@Controller('users')",
+	} {
+		n, ok := nodeByName[name]
+		if !ok {
+			t.Fatalf("expected %s class node", name)
+		}
+		if got := n.Properties["decorators"]; got != want {
+			t.Errorf("%s decorators = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNamespaceIDs(t *testing.T) {
+	source := `
+namespace Order {
+  export class Builder {
+    build() { return validate(); }
+  }
+  function validate() { return true; }
+}
+
+export namespace Invoice {
+  export class Builder {
+    build() {}
+  }
+}
+
+export class Builder {}
+`
+	result, err := NewParser().ParseFile("b.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	seen := make(map[string]string)
+	counts := make(map[string]int)
+	byQN := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		if prev, ok := seen[n.ID]; ok {
+			t.Errorf("duplicate ID %s for %s and %s", n.ID, prev, n.QualifiedName)
+		}
+		seen[n.ID] = n.QualifiedName
+		counts[n.Name]++
+		byQN[n.QualifiedName] = n
+	}
+	if counts["Builder"] != 3 {
+		t.Errorf("Builder nodes = %d, want 3", counts["Builder"])
+	}
+	if counts["build"] != 2 {
+		t.Errorf("build nodes = %d, want 2", counts["build"])
+	}
+
+	for _, qn := range []string{"b.ts.Order.Builder", "b.ts.Invoice.Builder", "b.ts.Order.validate"} {
+		if _, ok := byQN[qn]; !ok {
+			t.Errorf("expected node %s", qn)
+		}
+	}
+	if top, ok := byQN["b.ts.Builder"]; !ok {
+		t.Error("expected top-level Builder node")
+	} else if want := graph.NewNodeID(string(graph.NodeClass), "b.ts", "Builder"); top.ID != want {
+		t.Errorf("top-level Builder ID = %s, want unscoped %s", top.ID, want)
+	}
+
+	order, validate := byQN["b.ts.Order"], byQN["b.ts.Order.validate"]
+	if order == nil || validate == nil {
+		t.Fatal("expected Order namespace and validate function nodes")
+	}
+	var contained, called bool
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeContains && e.SourceID == order.ID && e.TargetID == byQN["b.ts.Order.Builder"].ID {
+			contained = true
+		}
+		if e.Type == graph.EdgeCalls && e.TargetID == validate.ID {
+			called = true
+		}
+	}
+	if !contained {
+		t.Error("expected Order namespace to contain Order.Builder")
+	}
+	if !called {
+		t.Error("expected build to call the namespaced validate function")
+	}
+}
+
 func TestParseExpressRoutes(t *testing.T) {
 	source := `
 import express from 'express';
@@ -412,6 +516,45 @@ app.get('/health', (req: Request, res: Response) => {
 	}
 }
 
+func TestParseExpressMiddleware(t *testing.T) {
+	source := `
+import express from 'express';
+import passport from 'passport';
+
+const router = express.Router();
+router.get('/health', health);
+router.use(requireAuth);
+router.get('/me', getMe);
+router.post('/orders', passport.authenticate('jwt', { session: false }), auth.hasRole('buyer'), createOrder);
+`
+	result, err := NewParser().ParseFile("routes.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nodeByName := indexByName(result.Nodes)
+	tests := []struct {
+		endpoint, middleware, handler string
+	}{
+		{"GET /health", "", "health"},
+		{"GET /me", "requireAuth", "getMe"},
+		{"POST /orders", "requireAuth,passport.authenticate,auth.hasRole", "createOrder"},
+	}
+	for _, tt := range tests {
+		n, ok := nodeByName[tt.endpoint]
+		if !ok {
+			t.Errorf("endpoint %q not found", tt.endpoint)
+			continue
+		}
+		if got := n.Properties[parser.PropMiddleware]; got != tt.middleware {
+			t.Errorf("%s middleware = %q, want %q", tt.endpoint, got, tt.middleware)
+		}
+		if got := n.Properties["handler"]; got != tt.handler {
+			t.Errorf("%s handler = %q, want %q", tt.endpoint, got, tt.handler)
+		}
+	}
+}
+
 func TestParseExpressUseMount(t *testing.T) {
 	source := `
 import express from 'express';
@@ -757,6 +900,161 @@ class ApiService {
 	}
 }
 
+func TestExpressNamedHandlerLinking(t *testing.T) {
+	source := `
+import express from 'express';
+import { createUser } from './handlers';
+import * as orders from './orders';
+
+const router = express.Router();
+
+function listUsers(req, res) { res.json([]); }
+const getUser = async (req, res) => { res.json({}); };
+
+class UserController {
+  static remove(req, res) { res.sendStatus(204); }
+}
+
+router.get('/users', listUsers);
+router.get('/users/:id', auth, getUser);
+router.delete('/users/:id', UserController.remove);
+router.post('/users', createUser);
+router.get('/orders', orders.list);
+router.put('/users/:id', unknownHandler);
+`
+	p := NewParser()
+	result, err := p.ParseFile("routes.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nameByID := make(map[string]string, len(result.Nodes))
+	for _, n := range result.Nodes {
+		nameByID[n.ID] = n.Name
+	}
+	edges := make(map[string]string)
+	for _, e := range result.Edges {
+		switch e.Type {
+		case graph.EdgeExposes:
+			edges[nameByID[e.TargetID]] = "exposed by " + nameByID[e.SourceID]
+		case graph.EdgeCalls:
+			if e.Properties["callee"] != "" {
+				edges[nameByID[e.SourceID]] = "calls " + nameByID[e.TargetID] + " (" + e.Properties["callee"] + ")"
+			}
+		}
+	}
+
+	tests := map[string]string{
+		"GET /users":        "exposed by listUsers",
+		"GET /users/:id":    "exposed by getUser",
+		"DELETE /users/:id": "exposed by remove",
+		"POST /users":       "calls ./handlers (createUser)",
+		"GET /orders":       "calls ./orders (orders.list)",
+		"PUT /users/:id":    "exposed by routes.ts",
+	}
+	for endpoint, want := range tests {
+		if got := edges[endpoint]; got != want {
+			t.Errorf("%s: got %q, want %q", endpoint, got, want)
+		}
+	}
+}
+
+func TestInlineFunctionCallAttribution(t *testing.T) {
+	source := `
+(function () { fetch('/api/boot'); })();
+
+app.get('/orders', async (req: Request, res: Response) => {
+  const items = await fetch('/api/inventory');
+  return items.map((i: number) => helper(i));
+});
+
+function registerRoutes(router: Router) {
+  router.post('/pay', function (req: Request, res: Response) { fetch('/api/charge'); });
+  setTimeout(() => fetch('/api/ping'), 10);
+}
+
+class Widget {
+  load = async () => { await fetch('/api/widgets'); };
+  render() { this.load(); }
+}
+
+function helper(x: number) { return x; }
+`
+	p := NewParser()
+	result, err := p.ParseFile("app.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	nameByID := make(map[string]string, len(result.Nodes))
+	for _, n := range result.Nodes {
+		nameByID[n.ID] = n.Name
+	}
+	edges := make(map[string]bool)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls || e.Type == graph.EdgeExposes {
+			edges[string(e.Type)+" "+nameByID[e.SourceID]+" -> "+nameByID[e.TargetID]] = true
+		}
+	}
+
+	tests := []string{
+		"Calls anonymous@2:2 -> UNKNOWN /api/boot",
+		"Exposes anonymous@4:20 -> GET /orders",
+		"Calls anonymous@4:20 -> UNKNOWN /api/inventory",
+		"Calls anonymous@4:20 -> helper",
+		"Exposes anonymous@10:23 -> POST /pay",
+		"Calls anonymous@10:23 -> UNKNOWN /api/charge",
+		"Calls registerRoutes -> UNKNOWN /api/ping",
+		"Calls load -> UNKNOWN /api/widgets",
+		"Calls render -> load",
+	}
+	for _, want := range tests {
+		if !edges[want] {
+			t.Errorf("missing edge %q", want)
+		}
+	}
+
+	nodeByName := indexByName(result.Nodes)
+	if n := nodeByName["anonymous@4:20"]; n == nil || n.Properties["anonymous"] != "true" || n.Properties["async"] != "true" {
+		t.Errorf("anonymous handler node = %+v", n)
+	}
+	if n := nodeByName["load"]; n == nil || n.Type != graph.NodeMethod || n.Properties["receiver"] != "Widget" {
+		t.Errorf("class property method = %+v", n)
+	}
+	if n := nodeByName["anonymous@11:14"]; n != nil {
+		t.Errorf("callback inside a named function should not get its own node: %+v", n)
+	}
+}
+
+func TestTestCallbackCallAttribution(t *testing.T) {
+	source := `
+describe('orders', () => {
+  it('lists orders', async () => {
+    await fetch('/api/orders');
+  });
+});
+`
+	p := NewParser()
+	result, err := p.ParseFile("orders.test.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	nodeByName := indexByName(result.Nodes)
+	test, call := nodeByName["lists orders"], nodeByName["UNKNOWN /api/orders"]
+	if test == nil || call == nil {
+		t.Fatal("expected test function and api_call nodes")
+	}
+	found := false
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && e.SourceID == test.ID && e.TargetID == call.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected Calls edge from the test case to the api call")
+	}
+}
+
 func TestExtractFunctionCalls(t *testing.T) {
 	_, thisFile, _, ok := runtime.Caller(0)
 	if !ok {
@@ -1104,3 +1402,342 @@ func indexByName(nodes []*graph.Node) map[string]*graph.Node {
 	}
 	return m
 }
+
+func TestExportBindings(t *testing.T) {
+	source := `
+import pad from './strings';
+import { formatDate as fmt } from './lib';
+import * as lib from './lib';
+
+export * from './format';
+export * as text from './text';
+export { slugify, default as trim } from './strings';
+function slug() {}
+export { slug as makeSlug };
+export default function main() {
+  pad();
+  fmt();
+  lib.parse();
+}
+`
+	p := NewParser()
+	result, err := p.ParseFile("src/index.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	var exports string
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeModule {
+			exports = n.Properties["exports"]
+		}
+	}
+	want := "*=*@./format,text=*@./text,slugify=slugify@./strings,trim=default@./strings,makeSlug=slug@,default=main@"
+	if exports != want {
+		t.Errorf("exports = %q, want %q", exports, want)
+	}
+
+	calls := make(map[string]string) // callee -> export
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && e.Properties["callee"] != "" {
+			calls[e.Properties["callee"]] = e.Properties["export"]
+		}
+	}
+	for callee, export := range map[string]string{"pad": "default", "fmt": "formatDate", "parse": "parse"} {
+		if calls[callee] != export {
+			t.Errorf("call %s export = %q, want %q (calls %v)", callee, calls[callee], export, calls)
+		}
+	}
+}
+
+func TestImportedImplements(t *testing.T) {
+	source := `
+import { Repository as Repo } from './contracts';
+import { OnModuleInit } from '@nestjs/common';
+
+interface Auditable {}
+
+export class OrderService implements Repo, OnModuleInit, Auditable, Global {}
+`
+	p := NewParser()
+	result, err := p.ParseFile("src/orders.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	names := make(map[string]string)
+	types := make(map[string]graph.NodeType)
+	for _, n := range result.Nodes {
+		names[n.ID], types[n.ID] = n.Name, n.Type
+	}
+	got := make(map[string]string) // implemented name -> target name, export
+	for _, e := range result.Edges {
+		if e.Type != graph.EdgeImplements {
+			continue
+		}
+		if iface := e.Properties["interface"]; iface != "" {
+			got[iface] = names[e.TargetID] + "," + e.Properties["export"]
+			continue
+		}
+		got[names[e.TargetID]] = string(types[e.TargetID])
+	}
+	want := map[string]string{
+		"Repo":         "./contracts,Repository",
+		"OnModuleInit": "@nestjs/common,OnModuleInit",
+		"Auditable":    "Interface",
+		"Global":       "Unresolved",
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("implements %s = %q, want %q", name, got[name], w)
+		}
+	}
+}
+
+func TestJSXRenders(t *testing.T) {
+	source := `
+import { Button } from './Button';
+import * as UI from '@acme/ui';
+
+function Header() {
+  return <h1>Shop</h1>;
+}
+
+export function App(props: Props) {
+  const items = props.items as Item[];
+  return (
+    <div>
+      <Header />
+      <Button label="Buy" onClick={() => buy(items)} />
+      <Button label="Cancel" />
+      <UI.Card>{props.children}</UI.Card>
+    </div>
+  );
+}
+
+export function helper() {
+  return 1;
+}
+`
+	p := NewParser()
+	result, err := p.ParseFile("src/App.tsx", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	ids := make(map[string]string) // node ID -> name
+	for _, n := range result.Nodes {
+		ids[n.ID] = n.Name
+		if n.Name == "App" && n.Properties["component"] != "true" {
+			t.Error("App should be flagged as a component")
+		}
+	}
+
+	got := make(map[string]string) // component -> target name:export
+	for _, e := range result.Edges {
+		if e.Type != graph.EdgeRenders {
+			continue
+		}
+		if ids[e.SourceID] != "App" {
+			t.Errorf("unexpected Renders edge from %s", ids[e.SourceID])
+		}
+		got[e.Properties["component"]] = ids[e.TargetID] + ":" + e.Properties["export"]
+	}
+	want := map[string]string{
+		"Header":  "Header:",
+		"Button":  "./Button:Button",
+		"UI.Card": "@acme/ui:Card",
+	}
+	if len(got) != len(want) {
+		t.Errorf("Renders edges = %v, want %v", got, want)
+	}
+	for component, target := range want {
+		if got[component] != target {
+			t.Errorf("Renders %s -> %q, want %q", component, got[component], target)
+		}
+	}
+}
+
+func TestHTTPClientCallBase(t *testing.T) {
+	source := `
+const API = process.env.PAYMENTS_URL;
+async function load(id: string) {
+  await fetch(` + "`${process.env.PAYMENTS_URL}/charges/${id}`" + `);
+  await axios.post(API + '/refunds');
+  await fetch('/api/local');
+}
+`
+	result, err := NewParser().ParseFile("client.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	want := map[string]string{
+		"*/charges/*": "process.env.PAYMENTS_URL",
+		"*/refunds":   "API",
+		"/api/local":  "",
+	}
+	got := make(map[string]string)
+	for _, n := range result.Nodes {
+		if n.Type == graph.NodeDependency && n.Properties["kind"] == "api_call" {
+			got[n.Properties["path"]] = n.Properties[parser.APICallPropBase]
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("api_call path -> base = %v, want %v", got, want)
+	}
+}
+
+func TestScheduledJobs(t *testing.T) {
+	source := `
+const cron = require('node-cron');
+const { CronJob } = require('cron');
+
+function cleanup() {}
+
+cron.schedule('*/5 * * * *', cleanup);
+new CronJob('0 3 * * MON-FRI', () => {});
+cron.schedule('not a schedule', cleanup);
+`
+	result, err := NewParser().ParseFile("jobs.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	byID := make(map[string]*graph.Node)
+	for _, n := range result.Nodes {
+		byID[n.ID] = n
+	}
+	calls := make(map[string]string)
+	for _, e := range result.Edges {
+		if e.Type == graph.EdgeCalls && byID[e.SourceID] != nil && byID[e.SourceID].Type == graph.NodeJob {
+			calls[e.SourceID] = e.TargetID
+		}
+	}
+
+	type job struct{ schedule, framework, handler string }
+	want := map[string]job{
+		"cleanup":              {"*/5 * * * *", "node-cron", "cleanup"},
+		"cron 0 3 * * MON-FRI": {"0 3 * * MON-FRI", "cron", ""},
+	}
+	got := make(map[string]job)
+	for _, n := range result.Nodes {
+		if n.Type != graph.NodeJob {
+			continue
+		}
+		got[n.Name] = job{n.Properties[parser.JobPropSchedule], n.Properties[parser.JobPropFramework], n.Properties[parser.JobPropHandler]}
+		target := byID[calls[n.ID]]
+		if target == nil {
+			t.Errorf("Job %s has no Calls edge to a node in the file", n.Name)
+		} else if n.Name == "cleanup" && target.Name != "cleanup" {
+			t.Errorf("Job %s calls %s, want cleanup", n.Name, target.Name)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d jobs %v, want %d", len(got), got, len(want))
+	}
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("Job %q = %+v, want %+v", name, got[name], w)
+		}
+	}
+}
+
+func TestErrorSites(t *testing.T) {
+	source := `
+export class PaymentDeclinedError extends Error {}
+export class Receipt {}
+
+export async function charge(amount: number) {
+  if (amount < 0) { throw new PaymentDeclinedError("negative"); }
+  try { await send(); } catch (e) { throw e; }
+}
+
+class Gateway {
+  refund() { throw new errors.RefundError(); }
+}
+`
+	result, err := NewParser().ParseFile("src/payments.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+	wantThrows := map[string]string{"charge": "PaymentDeclinedError", "refund": "errors.RefundError"}
+	wantErrorTypes := map[string]bool{"PaymentDeclinedError": true}
+	gotThrows := make(map[string]string)
+	gotErrorTypes := make(map[string]bool)
+	for _, n := range result.Nodes {
+		if throws := n.Properties[parser.PropThrows]; throws != "" {
+			gotThrows[n.Name] = throws
+		}
+		if n.Properties[parser.PropErrorType] == "true" {
+			gotErrorTypes[n.Name] = true
+		}
+	}
+	if !reflect.DeepEqual(gotThrows, wantThrows) {
+		t.Errorf("throws = %v, want %v", gotThrows, wantThrows)
+	}
+	if !reflect.DeepEqual(gotErrorTypes, wantErrorTypes) {
+		t.Errorf("error types = %v, want %v", gotErrorTypes, wantErrorTypes)
+	}
+}
+
+func TestJSDoc(t *testing.T) {
+	source := `
+/**
+ * Adds two numbers.
+ * @param a - the first operand
+ * @param b - the second
+ *   operand
+ * @returns the sum
+ */
+export function add(a: number, b: number): number { return a + b; }
+
+/** Serves users. */
+@Injectable()
+export class UsersService {
+  /**
+   * Finds a user.
+   * @param id the user ID
+   */
+  @Get(':id')
+  find(id: string) {}
+
+  // Not a doc comment.
+  remove(id: string) {}
+}
+
+/** Formats a role. */
+export const formatRole = (role: string) => role, other = () => 1;
+
+/** A shape. */
+interface Shape {}
+`
+	result, err := NewParser().ParseFile("users.ts", []byte(source))
+	if err != nil {
+		t.Fatalf("ParseFile returned error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		doc     string
+		params  string
+		returns string
+	}{
+		{"add", "Adds two numbers.\n@param a - the first operand\n@param b - the second\noperand\n@returns the sum", "a: the first operand\nb: the second operand", "the sum"},
+		{"UsersService", "Serves users.", "", ""},
+		{"find", "Finds a user.\n@param id the user ID", "id: the user ID", ""},
+		{"remove", "", "", ""},
+		{"formatRole", "Formats a role.", "", ""},
+		{"other", "", "", ""},
+		{"Shape", "A shape.", "", ""},
+	}
+	nodeByName := indexByName(result.Nodes)
+	for _, tt := range tests {
+		n, ok := nodeByName[tt.name]
+		if !ok {
+			t.Fatalf("expected %s node", tt.name)
+		}
+		if n.DocComment != tt.doc || n.Properties["doc_params"] != tt.params || n.Properties["doc_returns"] != tt.returns {
+			t.Errorf("%s doc = %q, params = %q, returns = %q; want %q, %q, %q",
+				tt.name, n.DocComment, n.Properties["doc_params"], n.Properties["doc_returns"], tt.doc, tt.params, tt.returns)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Unresolved is a reference a parser can name but not locate, such as the
+// interface a class implements when it may be declared in another file.
+//
+// The reference gets a NodeUnresolved node scoped to FilePath, which edges
+// point at until BindLocal re-points them at a node the file declares or
+// the linker binds them to one declared elsewhere. References the linker
+// cannot bind (types of external libraries) stay unresolved.
+type Unresolved struct {
+	Language Language
+	// TargetType is the type of node the reference names.
+	TargetType graph.NodeType
+	// Name is the name as written; type arguments (Repository<Order>) are
+	// ignored.
+	Name     string
+	FilePath string
+	Line     int
+}
+
+// ID returns the ID of the reference's Unresolved node.
+func (u Unresolved) ID() string {
+	return graph.NewNodeID(string(graph.NodeUnresolved), u.FilePath, string(u.TargetType)+":"+u.name())
+}
+
+// name returns Name without type arguments.
+func (u Unresolved) name() string {
+	if i := strings.IndexByte(u.Name, '<'); i >= 0 {
+		return strings.TrimSpace(u.Name[:i])
+	}
+	return strings.TrimSpace(u.Name)
+}
+
+// Link returns the reference's Unresolved node and an edge of edgeType to it
+// from sourceID.
+func (u Unresolved) Link(edgeType graph.EdgeType, sourceID string) (*graph.Node, *graph.Edge) {
+	node := &graph.Node{
+		ID:         u.ID(),
+		Type:       graph.NodeUnresolved,
+		Name:       u.name(),
+		FilePath:   u.FilePath,
+		Line:       u.Line,
+		Language:   string(u.Language),
+		Properties: map[string]string{"target_type": string(u.TargetType)},
+	}
+	edge := &graph.Edge{
+		ID:       graph.NewEdgeID(edgeType, sourceID, node.ID),
+		Type:     edgeType,
+		SourceID: sourceID,
+		TargetID: node.ID,
+	}
+	return node, edge
+}
+
+// BindLocal re-points the edges to Unresolved nodes naming a node declared
+// among nodes (one of the target type and name) at that node, and drops
+// the Unresolved nodes so bound. It also drops repeated Unresolved nodes,
+// emitted once per reference.
+func BindLocal(nodes []*graph.Node, edges []*graph.Edge) ([]*graph.Node, []*graph.Edge) {
+	declared := make(map[string]string) // target type + ":" + name -> ID
+	for _, n := range nodes {
+		if n.Type == graph.NodeUnresolved {
+			continue
+		}
+		key := string(n.Type) + ":" + n.Name
+		if _, ok := declared[key]; ok {
+			declared[key] = "" // ambiguous
+			continue
+		}
+		declared[key] = n.ID
+	}
+
+	bound := make(map[string]string) // Unresolved ID -> declared ID
+	seen := make(map[string]bool)
+	kept := nodes[:0]
+	for _, n := range nodes {
+		if n.Type == graph.NodeUnresolved {
+			if seen[n.ID] {
+				continue
+			}
+			seen[n.ID] = true
+			if id := declared[n.Properties["target_type"]+":"+n.Name]; id != "" {
+				bound[n.ID] = id
+				continue
+			}
+		}
+		kept = append(kept, n)
+	}
+
+	for _, e := range edges {
+		id, ok := bound[e.TargetID]
+		if !ok {
+			continue
+		}
+		if e.ID == graph.NewEdgeID(e.Type, e.SourceID, e.TargetID) {
+			e.ID = graph.NewEdgeID(e.Type, e.SourceID, id)
+		}
+		e.TargetID = id
+	}
+	return kept, edges
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+func TestBindLocal(t *testing.T) {
+	const file = "src/order.ts"
+	ref := func(name string) Unresolved {
+		return Unresolved{Language: LangTypeScript, TargetType: graph.NodeInterface, Name: name, FilePath: file}
+	}
+	local, e1 := ref("Auditable<Order>").Link(graph.EdgeImplements, "order")
+	dup, e2 := ref("Auditable").Link(graph.EdgeImplements, "invoice")
+	ambig, e3 := ref("Named").Link(graph.EdgeImplements, "order")
+	nodes := []*graph.Node{
+		{ID: "auditable", Type: graph.NodeInterface, Name: "Auditable", FilePath: file},
+		{ID: "named-1", Type: graph.NodeInterface, Name: "Named", FilePath: file},
+		{ID: "named-2", Type: graph.NodeInterface, Name: "Named", FilePath: file},
+		{ID: "auditable-class", Type: graph.NodeClass, Name: "Auditable", FilePath: file},
+		local, dup, ambig,
+	}
+
+	nodes, edges := BindLocal(nodes, []*graph.Edge{e1, e2, e3})
+
+	unresolved := 0
+	for _, n := range nodes {
+		if n.Type == graph.NodeUnresolved {
+			unresolved++
+			if n.Name != "Named" {
+				t.Errorf("kept Unresolved node %q, want only Named", n.Name)
+			}
+		}
+	}
+	if unresolved != 1 {
+		t.Errorf("kept %d Unresolved nodes, want 1", unresolved)
+	}
+
+	tests := []struct {
+		edge   *graph.Edge
+		target string
+	}{
+		{edges[0], "auditable"},
+		{edges[1], "auditable"},
+		{edges[2], ambig.ID},
+	}
+	for _, tt := range tests {
+		if tt.edge.TargetID != tt.target {
+			t.Errorf("edge from %s targets %s, want %s", tt.edge.SourceID, tt.edge.TargetID, tt.target)
+		}
+		if want := graph.NewEdgeID(tt.edge.Type, tt.edge.SourceID, tt.edge.TargetID); tt.edge.ID != want {
+			t.Errorf("edge from %s has ID %s, want %s", tt.edge.SourceID, tt.edge.ID, want)
+		}
+	}
+}
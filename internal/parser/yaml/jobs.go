@@ -0,0 +1,140 @@
+package yaml
+
+import (
+	"bytes"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// --- Scheduled jobs ---
+
+// extractJobs records Kubernetes CronJobs and sidekiq-cron /
+// sidekiq-scheduler schedules as Job nodes contained in the file. Sidekiq
+// jobs name their worker class as the handler for the linker to resolve.
+// Every document of a multi-document file is inspected.
+func (e *extractor) extractJobs() {
+	dec := yamlv3.NewDecoder(bytes.NewReader(e.content))
+	for {
+		var doc yamlv3.Node
+		if err := dec.Decode(&doc); err != nil {
+			return // io.EOF, or a malformed later document
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+			continue
+		}
+		root := doc.Content[0]
+		if scalarAt(root, "kind") == "CronJob" && scalarAt(root, "apiVersion") != "" {
+			e.extractK8sCronJob(root)
+			continue
+		}
+		e.extractSidekiqSchedule(root)
+	}
+}
+
+func (e *extractor) extractK8sCronJob(root *yamlv3.Node) {
+	schedule := scalarAt(root, "spec", "schedule")
+	name := scalarAt(root, "metadata", "name")
+	if schedule == "" || name == "" {
+		return
+	}
+	props := make(map[string]string)
+	if containers := valueAt(root, "spec", "jobTemplate", "spec", "template", "spec", "containers"); containers != nil &&
+		containers.Kind == yamlv3.SequenceNode && len(containers.Content) > 0 {
+		c := containers.Content[0]
+		if image := scalarAt(c, "image"); image != "" {
+			props["image"] = image
+		}
+		cmd := append(scalarList(mappingValue(c, "command")), scalarList(mappingValue(c, "args"))...)
+		if len(cmd) > 0 {
+			props["command"] = strings.Join(cmd, " ")
+		}
+	}
+	e.addJob(parser.Job{
+		Name:       name,
+		Schedule:   schedule,
+		Framework:  "kubernetes",
+		Line:       root.Line,
+		Properties: props,
+	}, e.fileNodeID)
+}
+
+// extractSidekiqSchedule handles sidekiq-cron schedule files (job name ->
+// cron and class at the top level) and sidekiq-scheduler's :scheduler:
+// :schedule: section in sidekiq.yml.
+func (e *extractor) extractSidekiqSchedule(root *yamlv3.Node) {
+	framework := "sidekiq-cron"
+	schedule := root
+	for _, key := range []string{":scheduler", "scheduler", ":schedule", "schedule"} {
+		if v := mappingValue(schedule, key); v != nil && v.Kind == yamlv3.MappingNode {
+			schedule, framework = v, "sidekiq-scheduler"
+		}
+	}
+	for i := 0; i < len(schedule.Content)-1; i += 2 {
+		name, def := schedule.Content[i].Value, schedule.Content[i+1]
+		if def.Kind != yamlv3.MappingNode {
+			continue
+		}
+		worker := scalarAt(def, "class")
+		if worker == "" {
+			continue
+		}
+		keys := []string{"cron"}
+		if framework == "sidekiq-scheduler" {
+			keys = append(keys, "every", "at", "in")
+		}
+		var when string
+		for _, key := range keys {
+			if v := scalarAt(def, key); v != "" {
+				when = v
+				if key != "cron" {
+					when = key + " " + v
+				}
+				break
+			}
+		}
+		if when == "" {
+			continue
+		}
+		e.addJob(parser.Job{
+			Name:      name,
+			Schedule:  when,
+			Framework: framework,
+			Handler:   worker,
+			Line:      schedule.Content[i].Line,
+		}, e.fileNodeID)
+	}
+}
+
+// extractGHASchedule records the cron schedules of a workflow's schedule
+// trigger as Job nodes contained in the workflow.
+func (e *extractor) extractGHASchedule(triggers *yamlv3.Node, workflowID, workflowName string) {
+	schedules := mappingValue(triggers, "schedule")
+	if schedules == nil || schedules.Kind != yamlv3.SequenceNode {
+		return
+	}
+	for _, item := range schedules.Content {
+		if cron := scalarAt(item, "cron"); cron != "" {
+			e.addJob(parser.Job{
+				Name:      workflowName,
+				Schedule:  cron,
+				Framework: "github-actions",
+				Line:      item.Line,
+			}, workflowID)
+		}
+	}
+}
+
+func (e *extractor) addJob(j parser.Job, parentID string) {
+	job := parser.NewJobNode(e.filePath, parser.LangYAML, j)
+	e.nodes = append(e.nodes, job)
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(parentID, job.ID, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: parentID,
+		TargetID: job.ID,
+	})
+}
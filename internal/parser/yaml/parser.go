@@ -1,6 +1,7 @@
 package yaml
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 // YAML dialect identifiers.
 const (
 	DialectGitHubActions = "github_actions"
+	DialectGitLabCI      = "gitlab_ci"
 	DialectAnsible       = "ansible"
 	DialectGeneric       = "generic"
 )
@@ -52,11 +54,16 @@ func (p *YAMLParser) ParseFile(filePath string, content []byte) (*parser.ParseRe
 	switch dialect {
 	case DialectGitHubActions:
 		e.extractGitHubActions(&root)
+	case DialectGitLabCI:
+		e.extractGitLabCI(&root)
 	case DialectAnsible:
 		e.extractAnsiblePlaybook(&root)
 	default:
 		e.extractGenericYAML(&root)
 	}
+	e.extractEnvProducers()
+	e.extractRoutes()
+	e.extractJobs()
 
 	return &parser.ParseResult{
 		Nodes:    e.nodes,
@@ -95,6 +102,9 @@ func (e *extractor) detectDialect(filePath string, root *yamlv3.Node) string {
 	if strings.Contains(filePath, ".github/workflows/") || strings.Contains(filePath, ".github\\workflows\\") {
 		return DialectGitHubActions
 	}
+	if isGitLabCI(filePath) {
+		return DialectGitLabCI
+	}
 
 	// The root node for Unmarshal is a document node wrapping the actual content.
 	if root == nil || len(root.Content) == 0 {
@@ -173,7 +183,6 @@ func (e *extractor) extractGitHubActions(root *yamlv3.Node) {
 		return
 	}
 
-	// Create workflow document node.
 	workflowName := ""
 	var triggerNode, jobsNode *yamlv3.Node
 
@@ -194,33 +203,28 @@ func (e *extractor) extractGitHubActions(root *yamlv3.Node) {
 		workflowName = e.filePath
 	}
 
-	docNodeID := graph.NewNodeID(string(graph.NodeDocument), e.filePath, "workflow:"+workflowName)
-	e.nodes = append(e.nodes, &graph.Node{
-		ID:       docNodeID,
-		Type:     graph.NodeDocument,
-		Name:     workflowName,
-		FilePath: e.filePath,
-		Line:     1,
-		Language: string(parser.LangYAML),
-		Properties: map[string]string{
-			"kind": "workflow",
-		},
-	})
-	e.edges = append(e.edges, &graph.Edge{
-		ID:       edgeID(e.fileNodeID, docNodeID, string(graph.EdgeContains)),
-		Type:     graph.EdgeContains,
-		SourceID: e.fileNodeID,
-		TargetID: docNodeID,
-	})
+	repoDir := ciRoot(e.filePath)
+	props := map[string]string{parser.PipelinePropCI: DialectGitHubActions}
+	if triggerNode != nil {
+		var include, ignore []string
+		for _, event := range []string{"push", "pull_request", "pull_request_target"} {
+			include = append(include, scalarList(valueAt(triggerNode, event, "paths"))...)
+			ignore = append(ignore, scalarList(valueAt(triggerNode, event, "paths-ignore"))...)
+		}
+		setPathList(props, parser.PipelinePropTriggerPaths, repoDir, include)
+		setPathList(props, parser.PipelinePropIgnorePaths, repoDir, ignore)
+	}
+	pipelineID := e.addPipeline(workflowName, props)
 
 	// Extract triggers.
 	if triggerNode != nil {
-		e.extractGHATriggers(triggerNode, docNodeID)
+		e.extractGHATriggers(triggerNode, pipelineID)
+		e.extractGHASchedule(triggerNode, pipelineID, workflowName)
 	}
 
 	// Extract jobs.
 	if jobsNode != nil && jobsNode.Kind == yamlv3.MappingNode {
-		e.extractGHAJobs(jobsNode, docNodeID)
+		e.extractGHAJobs(jobsNode, pipelineID, repoDir)
 	}
 }
 
@@ -265,7 +269,11 @@ func (e *extractor) extractGHATriggers(node *yamlv3.Node, parentID string) {
 	}
 }
 
-func (e *extractor) extractGHAJobs(jobsNode *yamlv3.Node, parentID string) {
+// extractGHAJobs records the jobs of a workflow as PipelineJob nodes. A
+// job works in its default working directory, the working directories of
+// its steps and the paths its run scripts and action inputs name.
+func (e *extractor) extractGHAJobs(jobsNode *yamlv3.Node, pipelineID, root string) {
+	needs := make(map[string][]string)
 	for i := 0; i < len(jobsNode.Content)-1; i += 2 {
 		jobKey := jobsNode.Content[i].Value
 		jobVal := jobsNode.Content[i+1]
@@ -274,49 +282,76 @@ func (e *extractor) extractGHAJobs(jobsNode *yamlv3.Node, parentID string) {
 		}
 
 		runsOn := ""
-		var stepsNode *yamlv3.Node
-
-		for j := 0; j < len(jobVal.Content)-1; j += 2 {
-			k := jobVal.Content[j].Value
-			v := jobVal.Content[j+1]
-			switch k {
-			case "runs-on":
-				runsOn = nodeScalarValue(v)
-			case "steps":
-				stepsNode = v
-			}
+		if v := mappingValue(jobVal, "runs-on"); v != nil {
+			runsOn = nodeScalarValue(v)
+		}
+		workDir := root
+		if wd := scalarAt(jobVal, "defaults", "run", "working-directory"); wd != "" {
+			workDir = joinCIPath(root, wd)
 		}
 
-		props := map[string]string{
-			"kind": "gha_job",
+		texts := []string{jobKey, scalarAt(jobVal, "name")}
+		paths := []string{workDir}
+		if env := mappingValue(jobVal, "environment"); env != nil {
+			// Jobs bound to an environment deploy to it.
+			texts = append(texts, "deploy")
+		}
+		if uses := scalarAt(jobVal, "uses"); uses != "" {
+			// A call to a reusable workflow.
+			texts = append(texts, uses)
 		}
+		var stepsNode *yamlv3.Node
+		if stepsNode = mappingValue(jobVal, "steps"); stepsNode != nil && stepsNode.Kind == yamlv3.SequenceNode {
+			stepTexts, stepPaths := e.stepsOf(stepsNode, workDir, root)
+			texts = append(texts, stepTexts...)
+			paths = append(paths, stepPaths...)
+		}
+
+		props := map[string]string{}
 		if runsOn != "" {
 			props["runs_on"] = runsOn
 		}
-
-		jobID := graph.NewNodeID(string(graph.NodeFunction), e.filePath, "job:"+jobKey)
-		e.nodes = append(e.nodes, &graph.Node{
-			ID:         jobID,
-			Type:       graph.NodeFunction,
-			Name:       jobKey,
-			FilePath:   e.filePath,
-			Line:       jobsNode.Content[i].Line,
-			Language:   string(parser.LangYAML),
-			Exported:   true,
-			Properties: props,
-		})
-		e.edges = append(e.edges, &graph.Edge{
-			ID:       edgeID(parentID, jobID, string(graph.EdgeContains)),
-			Type:     graph.EdgeContains,
-			SourceID: parentID,
-			TargetID: jobID,
-		})
+		jobID := e.addPipelineJob(pipelineID, jobKey, jobsNode.Content[i].Line, props, texts, paths)
+		needs[jobID] = nodeStrings(mappingValue(jobVal, "needs"))
 
 		// Extract steps.
 		if stepsNode != nil && stepsNode.Kind == yamlv3.SequenceNode {
 			e.extractGHASteps(stepsNode, jobID)
 		}
 	}
+	e.linkJobNeeds(needs)
+}
+
+// ghaPathInputs are action inputs naming a directory or file the action
+// works in (docker/build-push-action context and file, helm chart paths).
+var ghaPathInputs = []string{"working-directory", "workdir", "context", "file", "dockerfile", "chart"}
+
+// stepsOf returns the texts (run scripts and used actions) and paths of the
+// steps of a GitHub Actions job whose default working directory is workDir.
+func (e *extractor) stepsOf(stepsNode *yamlv3.Node, workDir, root string) (texts, paths []string) {
+	for _, step := range stepsNode.Content {
+		if step.Kind != yamlv3.MappingNode {
+			continue
+		}
+		dir := workDir
+		if wd := scalarAt(step, "working-directory"); wd != "" {
+			dir = joinCIPath(root, wd)
+			paths = append(paths, dir)
+		}
+		if run := scalarAt(step, "run"); run != "" {
+			texts = append(texts, run)
+			paths = append(paths, parser.PipelineCommandPaths(dir, run)...)
+		}
+		if uses := scalarAt(step, "uses"); uses != "" {
+			texts = append(texts, uses)
+			for _, in := range ghaPathInputs {
+				if p := parser.CleanPipelinePath(root, scalarAt(step, "with", in)); p != "" {
+					paths = append(paths, p)
+				}
+			}
+		}
+	}
+	return texts, paths
 }
 
 func (e *extractor) extractGHASteps(stepsNode *yamlv3.Node, jobID string) {
@@ -696,6 +731,220 @@ func (e *extractor) extractGenericYAML(root *yamlv3.Node) {
 	}
 }
 
+// --- Environment variable producers ---
+
+// extractEnvProducers records environment variables set for containers by
+// docker-compose files and Kubernetes manifests as Config nodes
+// (role=producer), with Configures edges from the file. Every document of a
+// multi-document file is inspected. Values are not recorded since they often
+// hold secrets, except for the host and path of http(s) URLs (see
+// parser.EnvPropURLHost). Compose services that are built from this
+// repository are also recorded as host Config nodes, since other containers
+// reach them by service name.
+func (e *extractor) extractEnvProducers() {
+	dec := yamlv3.NewDecoder(bytes.NewReader(e.content))
+	for {
+		var doc yamlv3.Node
+		if err := dec.Decode(&doc); err != nil {
+			return // io.EOF, or a malformed later document
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+			continue
+		}
+		root := doc.Content[0]
+		keys := mappingKeys(root)
+		switch {
+		case keys["apiVersion"] && keys["kind"]:
+			e.extractK8sEnv(root)
+		case keys["services"]:
+			e.extractComposeEnv(mappingValue(root, "services"))
+		}
+	}
+}
+
+// extractComposeEnv handles services.<name>.environment in list
+// ("KEY=value") or mapping form.
+func (e *extractor) extractComposeEnv(services *yamlv3.Node) {
+	if services == nil || services.Kind != yamlv3.MappingNode {
+		return
+	}
+	for i := 0; i < len(services.Content)-1; i += 2 {
+		name, svc := services.Content[i].Value, services.Content[i+1]
+		if svc.Kind != yamlv3.MappingNode {
+			continue
+		}
+		buildContext := composeBuildContext(e.filePath, svc)
+		if buildContext != "" {
+			e.addComposeHosts(name, svc, buildContext)
+		}
+		env := mappingValue(svc, "environment")
+		if env == nil {
+			continue
+		}
+		switch env.Kind {
+		case yamlv3.MappingNode:
+			for j := 0; j < len(env.Content)-1; j += 2 {
+				e.addEnvProducer(env.Content[j].Value, env.Content[j+1].Value, env.Content[j].Line, name, buildContext, "docker-compose")
+			}
+		case yamlv3.SequenceNode:
+			for _, item := range env.Content {
+				key, value, _ := strings.Cut(item.Value, "=")
+				e.addEnvProducer(key, value, item.Line, name, buildContext, "docker-compose")
+			}
+		}
+	}
+}
+
+// extractK8sEnv finds containers/initContainers lists anywhere in a
+// Kubernetes manifest (Pod, Deployment, CronJob, ...) and records their env.
+func (e *extractor) extractK8sEnv(node *yamlv3.Node) {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i < len(node.Content)-1; i += 2 {
+			key, val := node.Content[i].Value, node.Content[i+1]
+			if (key == "containers" || key == "initContainers") && val.Kind == yamlv3.SequenceNode {
+				for _, c := range val.Content {
+					e.extractK8sContainerEnv(c)
+				}
+				continue
+			}
+			e.extractK8sEnv(val)
+		}
+	case yamlv3.SequenceNode:
+		for _, item := range node.Content {
+			e.extractK8sEnv(item)
+		}
+	}
+}
+
+func (e *extractor) extractK8sContainerEnv(container *yamlv3.Node) {
+	if container.Kind != yamlv3.MappingNode {
+		return
+	}
+	name := ""
+	if n := mappingValue(container, "name"); n != nil {
+		name = n.Value
+	}
+	env := mappingValue(container, "env")
+	if env == nil || env.Kind != yamlv3.SequenceNode {
+		return
+	}
+	for _, item := range env.Content {
+		if v := mappingValue(item, "name"); v != nil && v.Kind == yamlv3.ScalarNode {
+			value := ""
+			if val := mappingValue(item, "value"); val != nil {
+				value = val.Value
+			}
+			e.addEnvProducer(v.Value, value, v.Line, name, "", "kubernetes")
+		}
+	}
+}
+
+func (e *extractor) addEnvProducer(varName, value string, line int, service, buildContext, source string) {
+	varName = strings.TrimSpace(varName)
+	if varName == "" {
+		return
+	}
+	props := map[string]string{
+		"kind":   parser.ConfigKindEnvVar,
+		"role":   parser.ConfigRoleProducer,
+		"source": source,
+	}
+	if service != "" {
+		props["service"] = service
+	}
+	if buildContext != "" {
+		props["build_context"] = buildContext
+	}
+	if host, urlPath, ok := parser.SplitURL(value); ok {
+		props[parser.EnvPropURLHost] = host
+		if urlPath != "" && urlPath != "/" {
+			props[parser.EnvPropURLPath] = urlPath
+		}
+	}
+	cfgID := graph.NewNodeID(string(graph.NodeConfig), e.filePath,
+		parser.ConfigKindEnvVar+":"+service+":"+varName+":"+fmt.Sprint(line))
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:         cfgID,
+		Type:       graph.NodeConfig,
+		Name:       varName,
+		FilePath:   e.filePath,
+		Line:       line,
+		Language:   string(parser.LangYAML),
+		Properties: props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.fileNodeID, cfgID, string(graph.EdgeConfigures)),
+		Type:     graph.EdgeConfigures,
+		SourceID: e.fileNodeID,
+		TargetID: cfgID,
+	})
+}
+
+// addComposeHosts records the names other containers on the compose network
+// reach a service by: the service name, container_name, hostname and
+// network aliases.
+func (e *extractor) addComposeHosts(service string, svc *yamlv3.Node, buildContext string) {
+	names := []string{service}
+	for _, key := range []string{"container_name", "hostname"} {
+		if v := mappingValue(svc, key); v != nil && v.Kind == yamlv3.ScalarNode {
+			names = append(names, v.Value)
+		}
+	}
+	if networks := mappingValue(svc, "networks"); networks != nil && networks.Kind == yamlv3.MappingNode {
+		for i := 1; i < len(networks.Content); i += 2 {
+			if aliases := mappingValue(networks.Content[i], "aliases"); aliases != nil && aliases.Kind == yamlv3.SequenceNode {
+				for _, a := range aliases.Content {
+					names = append(names, a.Value)
+				}
+			}
+		}
+	}
+	seen := make(map[string]bool, len(names))
+	for _, host := range names {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" || seen[host] || strings.ContainsAny(host, "${}") {
+			continue
+		}
+		seen[host] = true
+		cfgID := graph.NewNodeID(string(graph.NodeConfig), e.filePath,
+			parser.ConfigKindHost+":"+service+":"+host)
+		e.nodes = append(e.nodes, &graph.Node{
+			ID:       cfgID,
+			Type:     graph.NodeConfig,
+			Name:     host,
+			FilePath: e.filePath,
+			Line:     svc.Line,
+			Language: string(parser.LangYAML),
+			Properties: map[string]string{
+				"kind":          parser.ConfigKindHost,
+				"source":        "docker-compose",
+				"service":       service,
+				"build_context": buildContext,
+			},
+		})
+		e.edges = append(e.edges, &graph.Edge{
+			ID:       edgeID(e.fileNodeID, cfgID, string(graph.EdgeConfigures)),
+			Type:     graph.EdgeConfigures,
+			SourceID: e.fileNodeID,
+			TargetID: cfgID,
+		})
+	}
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // nodeScalarValue extracts the scalar value from a YAML node.
 // For sequences, it joins values with comma. For mappings, it returns empty.
 func nodeScalarValue(node *yamlv3.Node) string {
@@ -716,5 +965,5 @@ func nodeScalarValue(node *yamlv3.Node) string {
 }
 
 func edgeID(sourceID, targetID, edgeType string) string {
-	return graph.NewNodeID(edgeType, sourceID, targetID)
+	return graph.NewEdgeID(graph.EdgeType(edgeType), sourceID, targetID)
 }
@@ -0,0 +1,233 @@
+package yaml
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// --- CI pipelines ---
+
+// gitlabReservedKeys are the top-level keys of a GitLab CI configuration
+// that are not jobs.
+var gitlabReservedKeys = map[string]bool{
+	"stages": true, "variables": true, "default": true, "include": true, "workflow": true,
+	"image": true, "services": true, "cache": true, "before_script": true, "after_script": true,
+	"spec": true,
+}
+
+// isGitLabCI reports whether filePath is a GitLab CI configuration: a
+// .gitlab-ci.yml or a file under .gitlab/ci/ included from one.
+func isGitLabCI(filePath string) bool {
+	p := filepath.ToSlash(filePath)
+	base := path.Base(p)
+	return base == ".gitlab-ci.yml" || base == ".gitlab-ci.yaml" || strings.Contains("/"+p, "/.gitlab/ci/")
+}
+
+// ciRoot returns the repository directory of a CI configuration file: the
+// parent of .github or .gitlab, else the file's directory.
+func ciRoot(filePath string) string {
+	p := "/" + filepath.ToSlash(filePath)
+	for _, marker := range []string{"/.github/", "/.gitlab/"} {
+		if i := strings.Index(p, marker); i >= 0 {
+			return strings.TrimPrefix(p[:i], "/")
+		}
+	}
+	if dir := path.Dir(strings.TrimPrefix(p, "/")); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+// joinCIPath resolves a working directory named in a CI configuration
+// against the repository directory root.
+func joinCIPath(root, dir string) string {
+	if p := parser.CleanPipelinePath(root, dir+"/"); p != "" {
+		return p
+	}
+	return root
+}
+
+// setPathList stores the path globs, relative to the repository directory
+// root, as a comma-separated property.
+func setPathList(props map[string]string, key, root string, globs []string) {
+	if len(globs) == 0 {
+		return
+	}
+	joined := make([]string, 0, len(globs))
+	for _, g := range globs {
+		joined = append(joined, path.Join(root, strings.TrimPrefix(g, "/")))
+	}
+	props[key] = strings.Join(joined, ",")
+}
+
+// nodeStrings returns the scalar or scalar items of node; sequence items
+// that are mappings contribute their "job" key (GitLab needs).
+func nodeStrings(node *yamlv3.Node) []string {
+	if node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case yamlv3.ScalarNode:
+		return []string{node.Value}
+	case yamlv3.SequenceNode:
+		var out []string
+		for _, item := range node.Content {
+			if item.Kind == yamlv3.ScalarNode {
+				out = append(out, item.Value)
+			} else if job := scalarAt(item, "job"); job != "" {
+				out = append(out, job)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// addPipeline adds the Pipeline node of the file and returns its ID.
+func (e *extractor) addPipeline(name string, props map[string]string) string {
+	id := graph.NewNodeID(string(graph.NodePipeline), e.filePath, name)
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:         id,
+		Type:       graph.NodePipeline,
+		Name:       name,
+		FilePath:   e.filePath,
+		Line:       1,
+		Language:   string(parser.LangYAML),
+		Properties: props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.fileNodeID, id, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: e.fileNodeID,
+		TargetID: id,
+	})
+	return id
+}
+
+// addPipelineJob adds a PipelineJob node contained in the pipeline and
+// returns its ID. Its actions come from texts (name, commands, used
+// actions) and its paths from paths, with the repository root dropped: a
+// job working on the whole repository names no particular directory.
+func (e *extractor) addPipelineJob(pipelineID, name string, line int, props map[string]string, texts, paths []string) string {
+	props[parser.PipelinePropCI] = e.dialect
+	if actions := parser.PipelineActions(texts...); len(actions) > 0 {
+		props[parser.PipelinePropActions] = strings.Join(actions, ",")
+	}
+	seen := map[string]bool{ciRoot(e.filePath): true}
+	var dirs []string
+	for _, p := range paths {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			dirs = append(dirs, p)
+		}
+	}
+	if len(dirs) > 0 {
+		props[parser.PipelinePropPaths] = strings.Join(dirs, ",")
+	}
+
+	id := graph.NewNodeID(string(graph.NodePipelineJob), e.filePath, "job:"+name)
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:         id,
+		Type:       graph.NodePipelineJob,
+		Name:       name,
+		FilePath:   e.filePath,
+		Line:       line,
+		Language:   string(parser.LangYAML),
+		Exported:   true,
+		Properties: props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(pipelineID, id, string(graph.EdgeContains)),
+		Type:     graph.EdgeContains,
+		SourceID: pipelineID,
+		TargetID: id,
+	})
+	return id
+}
+
+// linkJobNeeds adds DependsOn edges from each job to the jobs of the same
+// file it needs, given by name.
+func (e *extractor) linkJobNeeds(needs map[string][]string) {
+	for jobID, names := range needs {
+		for _, name := range names {
+			target := graph.NewNodeID(string(graph.NodePipelineJob), e.filePath, "job:"+name)
+			if _, ok := needs[target]; !ok {
+				continue
+			}
+			e.edges = append(e.edges, &graph.Edge{
+				ID:       edgeID(jobID, target, string(graph.EdgeDependsOn)),
+				Type:     graph.EdgeDependsOn,
+				SourceID: jobID,
+				TargetID: target,
+			})
+		}
+	}
+}
+
+// extractGitLabCI records a GitLab CI configuration as a Pipeline with a
+// PipelineJob per job. Hidden jobs (".name") are templates and skipped. A
+// job's paths come from the cd targets and paths of its scripts; its
+// rules:changes and only:changes globs are its trigger paths.
+func (e *extractor) extractGitLabCI(root *yamlv3.Node) {
+	if root == nil || len(root.Content) == 0 || root.Content[0].Kind != yamlv3.MappingNode {
+		return
+	}
+	doc := root.Content[0]
+	repoDir := ciRoot(e.filePath)
+
+	name := scalarAt(doc, "workflow", "name")
+	if name == "" {
+		name = e.filePath
+	}
+	pipelineID := e.addPipeline(name, map[string]string{parser.PipelinePropCI: DialectGitLabCI})
+
+	needs := make(map[string][]string)
+	for i := 0; i < len(doc.Content)-1; i += 2 {
+		key, job := doc.Content[i].Value, doc.Content[i+1]
+		if gitlabReservedKeys[key] || strings.HasPrefix(key, ".") || job.Kind != yamlv3.MappingNode {
+			continue
+		}
+		if mappingValue(job, "script") == nil && mappingValue(job, "trigger") == nil && mappingValue(job, "extends") == nil {
+			continue
+		}
+
+		texts := []string{key}
+		var paths []string
+		for _, section := range []string{"before_script", "script", "after_script"} {
+			script := strings.Join(nodeStrings(mappingValue(job, section)), "\n")
+			texts = append(texts, script)
+			paths = append(paths, parser.PipelineCommandPaths(repoDir, script)...)
+		}
+		if mappingValue(job, "environment") != nil {
+			// Jobs bound to an environment deploy to it.
+			texts = append(texts, "deploy")
+		}
+
+		props := map[string]string{}
+		if stage := scalarAt(job, "stage"); stage != "" {
+			props[parser.PipelinePropStage] = stage
+			texts = append(texts, stage)
+		}
+		if image := scalarAt(job, "image"); image != "" {
+			props["image"] = image
+		}
+		changes := nodeStrings(valueAt(job, "only", "changes"))
+		if rules := mappingValue(job, "rules"); rules != nil && rules.Kind == yamlv3.SequenceNode {
+			for _, rule := range rules.Content {
+				changes = append(changes, nodeStrings(mappingValue(rule, "changes"))...)
+				changes = append(changes, nodeStrings(valueAt(rule, "changes", "paths"))...)
+			}
+		}
+		setPathList(props, parser.PipelinePropTriggerPaths, repoDir, changes)
+
+		jobID := e.addPipelineJob(pipelineID, key, doc.Content[i].Line, props, texts, paths)
+		needs[jobID] = nodeStrings(mappingValue(job, "needs"))
+	}
+	e.linkJobNeeds(needs)
+}
@@ -0,0 +1,429 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/parser"
+)
+
+// --- Reverse proxy routes ---
+
+// pathRulePattern matches the PathPrefix and Path matchers of a Traefik
+// rule; the submatch holds their quoted arguments.
+var pathRulePattern = regexp.MustCompile(`\bPath(?:Prefix)?\(([^)]*)\)`)
+
+// quotedPattern matches a backquoted, double- or single-quoted string.
+var quotedPattern = regexp.MustCompile("[`\"']([^`\"']+)[`\"']")
+
+// traefikMiddleware is the path rewriting part of a Traefik middleware.
+type traefikMiddleware struct {
+	stripPrefixes []string
+	addPrefix     string
+}
+
+// apply returns the forwarded prefix for a route prefix after the
+// middleware rewrites it.
+func (m traefikMiddleware) apply(prefix string) string {
+	for _, strip := range m.stripPrefixes {
+		strip = parser.RoutePrefix(strip)
+		if strip != "/" && (prefix == strip || strings.HasPrefix(prefix, strip+"/")) {
+			prefix = parser.RoutePrefix(prefix[len(strip):])
+			break
+		}
+	}
+	if m.addPrefix != "" {
+		prefix = parser.RoutePrefix(strings.TrimRight(m.addPrefix, "/") + prefix)
+	}
+	return prefix
+}
+
+// extractRoutes records the path routes of Traefik (docker-compose labels
+// and IngressRoute resources), Istio VirtualServices and Envoy static
+// configuration as route Config nodes, with Configures edges from the
+// file. Every document of a multi-document file is inspected, and Traefik
+// Middleware resources apply to IngressRoutes in the same file.
+func (e *extractor) extractRoutes() {
+	var docs []*yamlv3.Node
+	dec := yamlv3.NewDecoder(bytes.NewReader(e.content))
+	for {
+		var doc yamlv3.Node
+		if err := dec.Decode(&doc); err != nil {
+			break // io.EOF, or a malformed later document
+		}
+		if len(doc.Content) > 0 && doc.Content[0].Kind == yamlv3.MappingNode {
+			docs = append(docs, doc.Content[0])
+		}
+	}
+
+	middlewares := make(map[string]traefikMiddleware)
+	for _, root := range docs {
+		if scalarAt(root, "kind") == "Middleware" {
+			name := scalarAt(root, "metadata", "name")
+			spec := mappingValue(root, "spec")
+			middlewares[name] = traefikMiddleware{
+				stripPrefixes: scalarList(valueAt(spec, "stripPrefix", "prefixes")),
+				addPrefix:     scalarAt(spec, "addPrefix", "prefix"),
+			}
+		}
+	}
+
+	for _, root := range docs {
+		keys := mappingKeys(root)
+		switch {
+		case keys["apiVersion"] && keys["kind"]:
+			switch scalarAt(root, "kind") {
+			case "VirtualService":
+				e.extractIstioRoutes(mappingValue(root, "spec"))
+			case "IngressRoute":
+				e.extractIngressRoutes(mappingValue(root, "spec"), middlewares)
+			}
+		case keys["static_resources"]:
+			e.extractEnvoyRoutes(mappingValue(root, "static_resources"))
+		case keys["services"]:
+			e.extractComposeTraefikRoutes(mappingValue(root, "services"))
+		}
+	}
+}
+
+// extractIstioRoutes handles spec.http[] of an Istio VirtualService: each
+// uri prefix or exact match forwards to the first destination, with the
+// rewrite.uri replacing the matched prefix.
+func (e *extractor) extractIstioRoutes(spec *yamlv3.Node) {
+	httpRoutes := mappingValue(spec, "http")
+	if httpRoutes == nil || httpRoutes.Kind != yamlv3.SequenceNode {
+		return
+	}
+	for _, route := range httpRoutes.Content {
+		host := ""
+		if dests := mappingValue(route, "route"); dests != nil && dests.Kind == yamlv3.SequenceNode && len(dests.Content) > 0 {
+			host = scalarAt(dests.Content[0], "destination", "host")
+		}
+		if host == "" {
+			continue
+		}
+		rewrite := scalarAt(route, "rewrite", "uri")
+		matches := mappingValue(route, "match")
+		if matches == nil || matches.Kind != yamlv3.SequenceNode {
+			continue
+		}
+		for _, m := range matches.Content {
+			uri := mappingValue(m, "uri")
+			prefix := scalarAt(uri, "prefix")
+			if prefix == "" {
+				prefix = scalarAt(uri, "exact")
+			}
+			if prefix == "" {
+				continue
+			}
+			to := prefix
+			if rewrite != "" {
+				to = rewrite
+			}
+			e.addRoute(prefix, to, host, "istio", m.Line, "", "")
+		}
+	}
+}
+
+// extractIngressRoutes handles spec.routes[] of a Traefik IngressRoute.
+func (e *extractor) extractIngressRoutes(spec *yamlv3.Node, middlewares map[string]traefikMiddleware) {
+	routes := mappingValue(spec, "routes")
+	if routes == nil || routes.Kind != yamlv3.SequenceNode {
+		return
+	}
+	for _, route := range routes.Content {
+		host := ""
+		if svcs := mappingValue(route, "services"); svcs != nil && svcs.Kind == yamlv3.SequenceNode && len(svcs.Content) > 0 {
+			host = scalarAt(svcs.Content[0], "name")
+		}
+		if host == "" {
+			continue
+		}
+		var chain []traefikMiddleware
+		if mws := mappingValue(route, "middlewares"); mws != nil && mws.Kind == yamlv3.SequenceNode {
+			for _, mw := range mws.Content {
+				if m, ok := middlewares[scalarAt(mw, "name")]; ok {
+					chain = append(chain, m)
+				}
+			}
+		}
+		for _, prefix := range rulePaths(scalarAt(route, "match")) {
+			to := parser.RoutePrefix(prefix)
+			for _, m := range chain {
+				to = m.apply(to)
+			}
+			e.addRoute(prefix, to, host, "traefik", route.Line, "", "")
+		}
+	}
+}
+
+// extractComposeTraefikRoutes reads Traefik docker provider labels from
+// docker-compose services. Routers forward to the service carrying their
+// labels; middlewares may be declared on any service in the file.
+func (e *extractor) extractComposeTraefikRoutes(services *yamlv3.Node) {
+	if services == nil || services.Kind != yamlv3.MappingNode {
+		return
+	}
+	type router struct {
+		rule        string
+		middlewares []string
+		line        int
+	}
+	type svcRouters struct {
+		name, buildContext string
+		routers            map[string]*router
+	}
+	var all []svcRouters
+	middlewares := make(map[string]traefikMiddleware)
+
+	for i := 0; i < len(services.Content)-1; i += 2 {
+		name, svc := services.Content[i].Value, services.Content[i+1]
+		labels := composeLabels(mappingValue(svc, "labels"))
+		if len(labels) == 0 {
+			continue
+		}
+		sr := svcRouters{name: name, buildContext: composeBuildContext(e.filePath, svc), routers: make(map[string]*router)}
+		for _, l := range labels {
+			parts := strings.Split(l.key, ".")
+			if len(parts) < 5 || parts[0] != "traefik" || parts[1] != "http" {
+				continue
+			}
+			obj, attr := parts[3], strings.ToLower(strings.Join(parts[4:], "."))
+			switch parts[2] {
+			case "routers":
+				r := sr.routers[obj]
+				if r == nil {
+					r = &router{line: l.line}
+					sr.routers[obj] = r
+				}
+				switch attr {
+				case "rule":
+					r.rule, r.line = l.value, l.line
+				case "middlewares":
+					for _, m := range strings.Split(l.value, ",") {
+						m, _, _ = strings.Cut(strings.TrimSpace(m), "@")
+						r.middlewares = append(r.middlewares, m)
+					}
+				}
+			case "middlewares":
+				m := middlewares[obj]
+				switch attr {
+				case "stripprefix.prefixes":
+					m.stripPrefixes = strings.Split(l.value, ",")
+				case "addprefix.prefix":
+					m.addPrefix = l.value
+				}
+				middlewares[obj] = m
+			}
+		}
+		all = append(all, sr)
+	}
+
+	for _, sr := range all {
+		names := make([]string, 0, len(sr.routers))
+		for name := range sr.routers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			r := sr.routers[name]
+			for _, prefix := range rulePaths(r.rule) {
+				to := parser.RoutePrefix(prefix)
+				for _, mw := range r.middlewares {
+					to = middlewares[mw].apply(to)
+				}
+				e.addRoute(prefix, to, sr.name, "traefik", r.line, sr.name, sr.buildContext)
+			}
+		}
+	}
+}
+
+// extractEnvoyRoutes finds route_config virtual host routes anywhere in an
+// Envoy bootstrap's static_resources and resolves each route's cluster to
+// the address of its first endpoint.
+func (e *extractor) extractEnvoyRoutes(resources *yamlv3.Node) {
+	clusters := make(map[string]string)
+	if cs := mappingValue(resources, "clusters"); cs != nil && cs.Kind == yamlv3.SequenceNode {
+		for _, c := range cs.Content {
+			name := scalarAt(c, "name")
+			addr := ""
+			if eps := valueAt(c, "load_assignment", "endpoints"); eps != nil && eps.Kind == yamlv3.SequenceNode && len(eps.Content) > 0 {
+				if lbs := mappingValue(eps.Content[0], "lb_endpoints"); lbs != nil && lbs.Kind == yamlv3.SequenceNode && len(lbs.Content) > 0 {
+					addr = scalarAt(lbs.Content[0], "endpoint", "address", "socket_address", "address")
+				}
+			}
+			if addr == "" {
+				addr = name
+			}
+			clusters[name] = addr
+		}
+	}
+
+	var walk func(n *yamlv3.Node)
+	walk = func(n *yamlv3.Node) {
+		switch n.Kind {
+		case yamlv3.MappingNode:
+			for i := 0; i < len(n.Content)-1; i += 2 {
+				if n.Content[i].Value == "routes" && n.Content[i+1].Kind == yamlv3.SequenceNode {
+					for _, r := range n.Content[i+1].Content {
+						e.addEnvoyRoute(r, clusters)
+					}
+					continue
+				}
+				walk(n.Content[i+1])
+			}
+		case yamlv3.SequenceNode:
+			for _, item := range n.Content {
+				walk(item)
+			}
+		}
+	}
+	walk(resources)
+}
+
+func (e *extractor) addEnvoyRoute(r *yamlv3.Node, clusters map[string]string) {
+	prefix := scalarAt(r, "match", "prefix")
+	if prefix == "" {
+		prefix = scalarAt(r, "match", "path")
+	}
+	cluster := scalarAt(r, "route", "cluster")
+	if prefix == "" || cluster == "" {
+		return
+	}
+	host := cluster
+	if addr, ok := clusters[cluster]; ok {
+		host = addr
+	}
+	to := scalarAt(r, "route", "prefix_rewrite")
+	if to == "" {
+		to = prefix
+	}
+	e.addRoute(prefix, to, host, "envoy", r.Line, "", "")
+}
+
+func (e *extractor) addRoute(prefix, rewrite, upstream, source string, line int, service, buildContext string) {
+	upstream = parser.UpstreamHost(upstream)
+	if upstream == "" {
+		return
+	}
+	prefix = parser.RoutePrefix(prefix)
+	props := map[string]string{
+		"kind":                   parser.ConfigKindRoute,
+		"source":                 source,
+		parser.RoutePropPrefix:   prefix,
+		parser.RoutePropRewrite:  parser.RoutePrefix(rewrite),
+		parser.RoutePropUpstream: upstream,
+	}
+	if service != "" {
+		props["service"] = service
+	}
+	if buildContext != "" {
+		props["build_context"] = buildContext
+	}
+	cfgID := graph.NewNodeID(string(graph.NodeConfig), e.filePath,
+		parser.ConfigKindRoute+":"+prefix+":"+fmt.Sprint(line))
+	e.nodes = append(e.nodes, &graph.Node{
+		ID:         cfgID,
+		Type:       graph.NodeConfig,
+		Name:       prefix,
+		FilePath:   e.filePath,
+		Line:       line,
+		Language:   string(parser.LangYAML),
+		Properties: props,
+	})
+	e.edges = append(e.edges, &graph.Edge{
+		ID:       edgeID(e.fileNodeID, cfgID, string(graph.EdgeConfigures)),
+		Type:     graph.EdgeConfigures,
+		SourceID: e.fileNodeID,
+		TargetID: cfgID,
+	})
+}
+
+// rulePaths returns the paths of the PathPrefix and Path matchers in a
+// Traefik rule such as "Host(`a.com`) && PathPrefix(`/api`, `/v1`)".
+func rulePaths(rule string) []string {
+	var paths []string
+	for _, m := range pathRulePattern.FindAllStringSubmatch(rule, -1) {
+		for _, q := range quotedPattern.FindAllStringSubmatch(m[1], -1) {
+			paths = append(paths, q[1])
+		}
+	}
+	return paths
+}
+
+type composeLabel struct {
+	key, value string
+	line       int
+}
+
+// composeLabels returns docker-compose labels given in mapping or
+// "key=value" list form.
+func composeLabels(labels *yamlv3.Node) []composeLabel {
+	if labels == nil {
+		return nil
+	}
+	var out []composeLabel
+	switch labels.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i < len(labels.Content)-1; i += 2 {
+			out = append(out, composeLabel{labels.Content[i].Value, labels.Content[i+1].Value, labels.Content[i].Line})
+		}
+	case yamlv3.SequenceNode:
+		for _, item := range labels.Content {
+			key, value, _ := strings.Cut(item.Value, "=")
+			out = append(out, composeLabel{strings.TrimSpace(key), strings.TrimSpace(value), item.Line})
+		}
+	}
+	return out
+}
+
+// composeBuildContext returns a compose service's build context relative to
+// the repository, or "".
+func composeBuildContext(filePath string, svc *yamlv3.Node) string {
+	build := mappingValue(svc, "build")
+	if build != nil && build.Kind == yamlv3.MappingNode {
+		build = mappingValue(build, "context")
+	}
+	if build == nil || build.Kind != yamlv3.ScalarNode {
+		return ""
+	}
+	return path.Join(path.Dir(filePath), build.Value)
+}
+
+// valueAt follows a path of mapping keys from node, returning nil when any
+// step is missing.
+func valueAt(node *yamlv3.Node, keys ...string) *yamlv3.Node {
+	for _, k := range keys {
+		node = mappingValue(node, k)
+	}
+	return node
+}
+
+// scalarAt returns the scalar at a path of mapping keys, or "".
+func scalarAt(node *yamlv3.Node, keys ...string) string {
+	if v := valueAt(node, keys...); v != nil && v.Kind == yamlv3.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
+
+// scalarList returns the scalar items of a sequence node.
+func scalarList(node *yamlv3.Node) []string {
+	if node == nil || node.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+	var out []string
+	for _, item := range node.Content {
+		if item.Kind == yamlv3.ScalarNode {
+			out = append(out, item.Value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,201 @@
+package savedquery
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Result is the outcome of running a saved query: a table of strings.
+type Result struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Columns     []string   `json:"columns"`
+	Rows        [][]string `json:"rows"`
+}
+
+// Records returns the rows as column-keyed maps.
+func (r *Result) Records() []map[string]string {
+	records := make([]map[string]string, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		rec := make(map[string]string, len(row))
+		for i, c := range r.Columns {
+			rec[c] = row[i]
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// match is a node reached from a matched node.
+type match struct {
+	from *graph.Node
+	node *graph.Node
+}
+
+// Run executes a saved query against the store.
+func Run(ctx context.Context, store graph.Store, q *Query) (*Result, error) {
+	nodes, err := store.QueryNodes(ctx, q.Match.Filter())
+	if err != nil {
+		return nil, fmt.Errorf("query nodes: %w", err)
+	}
+	matches := make([]match, 0, len(nodes))
+	for _, n := range nodes {
+		matches = append(matches, match{from: n, node: n})
+	}
+	for _, s := range q.Traverse {
+		if matches, err = traverse(ctx, store, matches, s); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{Name: q.Name, Description: q.Description}
+	if q.Output.GroupBy != "" {
+		counts := make(map[string]int)
+		for _, m := range matches {
+			counts[column(m, q.Output.GroupBy)]++
+		}
+		res.Columns = []string{q.Output.GroupBy, "count"}
+		for v, n := range counts {
+			res.Rows = append(res.Rows, []string{v, strconv.Itoa(n)})
+		}
+		// Largest groups first unless a sort is given.
+		sortRows(res.Rows, 0, false)
+		sortRows(res.Rows, 1, true)
+	} else {
+		res.Columns = q.Output.Columns
+		if len(res.Columns) == 0 {
+			res.Columns = []string{"type", "name", "file", "line"}
+			if len(q.Traverse) > 0 {
+				res.Columns = append([]string{"from"}, res.Columns...)
+			}
+		}
+		for _, m := range matches {
+			row := make([]string, len(res.Columns))
+			for i, c := range res.Columns {
+				row[i] = column(m, c)
+			}
+			res.Rows = append(res.Rows, row)
+		}
+		for i := len(res.Columns) - 1; i >= 0; i-- {
+			sortRows(res.Rows, i, false)
+		}
+	}
+
+	if s := q.Output.Sort; s != "" {
+		desc := strings.HasPrefix(s, "-")
+		s = strings.TrimPrefix(s, "-")
+		for i, c := range res.Columns {
+			if c == s {
+				sortRows(res.Rows, i, desc)
+			}
+		}
+	}
+	if q.Output.Limit > 0 && len(res.Rows) > q.Output.Limit {
+		res.Rows = res.Rows[:q.Output.Limit]
+	}
+	if res.Rows == nil {
+		res.Rows = [][]string{}
+	}
+	return res, nil
+}
+
+// traverse applies one step to the matches.
+func traverse(ctx context.Context, store graph.Store, matches []match, s Step) ([]match, error) {
+	dir, err := direction(s.Direction)
+	if err != nil {
+		return nil, err
+	}
+	var out []match
+	seen := make(map[[2]string]bool)
+	for _, m := range matches {
+		neighbors, err := store.GetNeighbors(ctx, m.node.ID, graph.EdgeType(s.Edge), dir)
+		if err != nil {
+			return nil, fmt.Errorf("neighbors of %s: %w", m.node.Name, err)
+		}
+		found := false
+		for _, n := range neighbors {
+			if s.Type != "" && string(n.Type) != s.Type {
+				continue
+			}
+			if s.Name != "" {
+				if ok, _ := filepath.Match(s.Name, n.Name); !ok {
+					continue
+				}
+			}
+			found = true
+			if s.Without {
+				break
+			}
+			key := [2]string{m.from.ID, n.ID}
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, match{from: m.from, node: n})
+			}
+		}
+		if s.Without && !found {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// column returns the value of a column for a match.
+func column(m match, c string) string {
+	n := m.node
+	switch c {
+	case "id":
+		return n.ID
+	case "type":
+		return string(n.Type)
+	case "name":
+		return n.Name
+	case "qualified_name":
+		return n.QualifiedName
+	case "package":
+		return n.Package
+	case "file":
+		return n.FilePath
+	case "line":
+		if n.Line > 0 {
+			return strconv.Itoa(n.Line)
+		}
+		return ""
+	case "language":
+		return n.Language
+	case "exported":
+		return strconv.FormatBool(n.Exported)
+	case "from":
+		return m.from.Name
+	case "from_type":
+		return string(m.from.Type)
+	case "from_file":
+		return m.from.FilePath
+	}
+	if key, ok := strings.CutPrefix(c, "prop."); ok {
+		return n.Properties[key]
+	}
+	return ""
+}
+
+// sortRows stably sorts rows by column i, numerically when both values
+// are integers.
+func sortRows(rows [][]string, i int, desc bool) {
+	sort.SliceStable(rows, func(a, b int) bool {
+		x, y := rows[a][i], rows[b][i]
+		if desc {
+			x, y = y, x
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		if xerr == nil && yerr == nil {
+			return xn < yn
+		}
+		return x < y
+	})
+}
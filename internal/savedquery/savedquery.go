@@ -0,0 +1,240 @@
+// Package savedquery loads named queries from .CodeEagle/queries/*.yaml and
+// runs them against the knowledge graph, so recurring architecture reports
+// can be kept in the repository instead of scripted against the store.
+//
+// A saved query selects nodes, optionally walks edges from them, and
+// describes how to present the result:
+//
+//	description: Endpoints without tests
+//	match:
+//	  type: APIEndpoint
+//	traverse:
+//	  - edge: Tests
+//	    direction: in
+//	    without: true
+//	output:
+//	  columns: [name, file, line]
+//	  sort: file
+package savedquery
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// DirName is the saved query directory inside the .CodeEagle dir.
+const DirName = "queries"
+
+// Output formats.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+// Query is a saved query. Its name is the file name without extension.
+type Query struct {
+	Name        string `yaml:"-"`
+	Description string `yaml:"description"`
+	// Match selects the starting nodes.
+	Match Match `yaml:"match"`
+	// Traverse walks edges from the matched nodes, one step after another.
+	Traverse []Step `yaml:"traverse"`
+	Output   Output `yaml:"output"`
+}
+
+// Match is a node filter. Name is a glob pattern; all set fields must match.
+type Match struct {
+	Type       string            `yaml:"type"`
+	Name       string            `yaml:"name"`
+	Package    string            `yaml:"package"`
+	File       string            `yaml:"file"`
+	Language   string            `yaml:"language"`
+	Exported   *bool             `yaml:"exported"`
+	Properties map[string]string `yaml:"properties"`
+}
+
+// Filter converts the match to a graph node filter.
+func (m Match) Filter() graph.NodeFilter {
+	return graph.NodeFilter{
+		Type:        graph.NodeType(m.Type),
+		NamePattern: m.Name,
+		Package:     m.Package,
+		FilePath:    m.File,
+		Language:    m.Language,
+		Exported:    m.Exported,
+		Properties:  m.Properties,
+	}
+}
+
+// Step is one traversal step. It replaces each node with its neighbours
+// over Edge (any edge type when empty) in Direction ("out", the default,
+// "in" or "both"), keeping neighbours of the given Type and Name glob.
+// With Without set, the step instead keeps only the nodes that have no
+// such neighbour.
+type Step struct {
+	Edge      string `yaml:"edge"`
+	Direction string `yaml:"direction"`
+	Type      string `yaml:"type"`
+	Name      string `yaml:"name"`
+	Without   bool   `yaml:"without"`
+}
+
+// Output describes how results are presented.
+type Output struct {
+	// Format is "table" (the default), "json" or "csv".
+	Format string `yaml:"format"`
+	// Columns lists the columns to show; see Columns for the choices.
+	Columns []string `yaml:"columns"`
+	// GroupBy counts the results per value of a column instead of listing
+	// them.
+	GroupBy string `yaml:"group_by"`
+	// Sort orders the results by a column, descending with a "-" prefix.
+	Sort  string `yaml:"sort"`
+	Limit int    `yaml:"limit"`
+}
+
+// Columns are the column names a query can show. "from" is the matched
+// node a result was reached from; "prop.<key>" shows a node property.
+var Columns = []string{"id", "type", "name", "qualified_name", "package", "file", "line", "language", "exported", "from", "from_type", "from_file"}
+
+// Parse decodes a saved query. Unknown fields are rejected so typos are
+// reported instead of silently widening the query.
+func Parse(name string, data []byte) (*Query, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	q := &Query{}
+	if err := dec.Decode(q); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("query %s: %w", name, err)
+	}
+	q.Name = name
+	if err := q.validate(); err != nil {
+		return nil, fmt.Errorf("query %s: %w", name, err)
+	}
+	return q, nil
+}
+
+func (q *Query) validate() error {
+	for i, s := range q.Traverse {
+		if _, err := direction(s.Direction); err != nil {
+			return fmt.Errorf("traverse step %d: %w", i+1, err)
+		}
+	}
+	switch q.Output.Format {
+	case "", FormatTable, FormatJSON, FormatCSV:
+	default:
+		return fmt.Errorf("unknown output format %q (want table, json or csv)", q.Output.Format)
+	}
+	if q.Output.Limit < 0 {
+		return fmt.Errorf("output limit must not be negative")
+	}
+	for _, c := range q.Output.Columns {
+		if !validColumn(c) {
+			return fmt.Errorf("unknown column %q", c)
+		}
+	}
+	if q.Output.GroupBy != "" && !validColumn(q.Output.GroupBy) {
+		return fmt.Errorf("unknown group_by column %q", q.Output.GroupBy)
+	}
+	if sortCol := strings.TrimPrefix(q.Output.Sort, "-"); sortCol != "" {
+		if q.Output.GroupBy != "" {
+			if sortCol != q.Output.GroupBy && sortCol != "count" {
+				return fmt.Errorf("sort column %q must be %q or count when grouping", sortCol, q.Output.GroupBy)
+			}
+		} else if !validColumn(sortCol) {
+			return fmt.Errorf("unknown sort column %q", sortCol)
+		}
+	}
+	return nil
+}
+
+func validColumn(c string) bool {
+	if key, ok := strings.CutPrefix(c, "prop."); ok {
+		return key != ""
+	}
+	for _, known := range Columns {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+func direction(s string) (graph.Direction, error) {
+	switch s {
+	case "", "out":
+		return graph.Outgoing, nil
+	case "in":
+		return graph.Incoming, nil
+	case "both":
+		return graph.Both, nil
+	}
+	return 0, fmt.Errorf("unknown direction %q (want out, in or both)", s)
+}
+
+// Load reads the saved queries in dir, sorted by name. A missing directory
+// has none.
+func Load(dir string) ([]*Query, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read queries: %w", err)
+	}
+	var queries []*Query
+	for _, e := range entries {
+		name, ok := queryName(e.Name())
+		if e.IsDir() || !ok {
+			continue
+		}
+		q, err := loadFile(filepath.Join(dir, e.Name()), name)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	return queries, nil
+}
+
+// Find reads the saved query with the given name from dir.
+func Find(dir, name string) (*Query, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid query name %q", name)
+	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return loadFile(path, name)
+		}
+	}
+	return nil, fmt.Errorf("no saved query %q in %s", name, dir)
+}
+
+func queryName(file string) (string, bool) {
+	for _, ext := range []string{".yaml", ".yml"} {
+		if name, ok := strings.CutSuffix(file, ext); ok && name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func loadFile(path, name string) (*Query, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read query %s: %w", name, err)
+	}
+	return Parse(name, data)
+}
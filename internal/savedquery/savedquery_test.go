@@ -0,0 +1,156 @@
+package savedquery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"valid", "match: {type: Function}\ntraverse: [{edge: Calls, direction: in}]\noutput: {columns: [from, prop.kind], sort: -line}\n", ""},
+		{"empty", "", ""},
+		{"unknown field", "match: {kind: Function}\n", "field kind not found"},
+		{"bad direction", "traverse: [{edge: Calls, direction: up}]\n", "unknown direction"},
+		{"bad column", "output: {columns: [nme]}\n", `unknown column "nme"`},
+		{"bad format", "output: {format: xml}\n", "unknown output format"},
+		{"sort while grouping", "output: {group_by: package, sort: name}\n", "when grouping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse("q", []byte(tt.yaml))
+			if tt.wantErr == "" {
+				if err != nil || q.Name != "q" {
+					t.Fatalf("Parse = %+v, %v", q, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadAndFind(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"untested.yaml": "description: Endpoints without tests\n",
+		"by-lang.yml":   "output: {group_by: language}\n",
+		"notes.txt":     "not a query",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	queries, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var names []string
+	for _, q := range queries {
+		names = append(names, q.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"by-lang", "untested"}) {
+		t.Errorf("Load names = %v", names)
+	}
+	if q, err := Find(dir, "by-lang"); err != nil || q.Output.GroupBy != "language" {
+		t.Errorf("Find(by-lang) = %+v, %v", q, err)
+	}
+	if _, err := Find(dir, "missing"); err == nil {
+		t.Error("Find(missing) succeeded")
+	}
+	if _, err := Find(dir, "../untested"); err == nil {
+		t.Error("Find accepted a path")
+	}
+	if queries, err := Load(filepath.Join(dir, "none")); err != nil || queries != nil {
+		t.Errorf("Load(missing dir) = %v, %v", queries, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+	for _, n := range []*graph.Node{
+		{ID: "ep-users", Type: graph.NodeAPIEndpoint, Name: "GET /users", FilePath: "api/users.go", Line: 10, Language: "go"},
+		{ID: "ep-orders", Type: graph.NodeAPIEndpoint, Name: "GET /orders", FilePath: "api/orders.go", Line: 4, Language: "go"},
+		{ID: "h-users", Type: graph.NodeFunction, Name: "ListUsers", FilePath: "api/users.go", Line: 12, Language: "go"},
+		{ID: "h-orders", Type: graph.NodeFunction, Name: "ListOrders", FilePath: "api/orders.go", Line: 6, Language: "go"},
+		{ID: "t-users", Type: graph.NodeTestFunction, Name: "TestListUsers", FilePath: "api/users_test.go", Line: 3, Language: "go"},
+		{ID: "py", Type: graph.NodeFunction, Name: "report", FilePath: "jobs/report.py", Line: 1, Language: "python"},
+	} {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	for _, e := range []*graph.Edge{
+		{ID: "e1", Type: graph.EdgeCalls, SourceID: "ep-users", TargetID: "h-users"},
+		{ID: "e2", Type: graph.EdgeCalls, SourceID: "ep-orders", TargetID: "h-orders"},
+		{ID: "e3", Type: graph.EdgeTests, SourceID: "t-users", TargetID: "h-users"},
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		yaml        string
+		wantColumns []string
+		wantRows    [][]string
+	}{
+		{
+			name:        "match only",
+			yaml:        "match: {type: Function, language: go}\n",
+			wantColumns: []string{"type", "name", "file", "line"},
+			wantRows:    [][]string{{"Function", "ListOrders", "api/orders.go", "6"}, {"Function", "ListUsers", "api/users.go", "12"}},
+		},
+		{
+			name:        "traverse without",
+			yaml:        "match: {type: APIEndpoint}\ntraverse:\n  - {edge: Calls}\n  - {edge: Tests, direction: in, without: true}\noutput: {columns: [from, name]}\n",
+			wantColumns: []string{"from", "name"},
+			wantRows:    [][]string{{"GET /orders", "ListOrders"}},
+		},
+		{
+			name:        "traverse filtered by type",
+			yaml:        "match: {name: \"List*\"}\ntraverse: [{direction: both, type: APIEndpoint}]\noutput: {columns: [from, name], sort: -from, limit: 1}\n",
+			wantColumns: []string{"from", "name"},
+			wantRows:    [][]string{{"ListUsers", "GET /users"}},
+		},
+		{
+			name:        "group by",
+			yaml:        "output: {group_by: language}\n",
+			wantColumns: []string{"language", "count"},
+			wantRows:    [][]string{{"go", "5"}, {"python", "1"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.name, []byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			res, err := Run(ctx, store, q)
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if !reflect.DeepEqual(res.Columns, tt.wantColumns) || !reflect.DeepEqual(res.Rows, tt.wantRows) {
+				t.Errorf("Run = %v %v, want %v %v", res.Columns, res.Rows, tt.wantColumns, tt.wantRows)
+			}
+		})
+	}
+}
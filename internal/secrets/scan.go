@@ -0,0 +1,182 @@
+// Package secrets detects hard-coded credentials (API keys, tokens, private
+// key blocks) in file content.
+package secrets
+
+import (
+	"bytes"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Rule is a credential pattern. When the regexp has a capture group, the
+// first group is the secret value; otherwise the whole match is.
+type Rule struct {
+	ID          string
+	Description string
+	Severity    string // "error" or "warning"
+	re          *regexp.Regexp
+	// minEntropy, if set, rejects values with lower Shannon entropy
+	// (bits per character) to skip placeholders like "changeme".
+	minEntropy float64
+}
+
+// Rules lists the built-in credential patterns.
+var Rules = []Rule{
+	{ID: "private-key", Description: "private key block", Severity: "error",
+		re: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{ID: "aws-access-key-id", Description: "AWS access key ID", Severity: "error",
+		re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{ID: "aws-secret-access-key", Description: "AWS secret access key", Severity: "error",
+		re: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`)},
+	{ID: "github-token", Description: "GitHub token", Severity: "error",
+		re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{ID: "slack-token", Description: "Slack token", Severity: "error",
+		re: regexp.MustCompile(`\bxox[baprs]-[A-Za-z0-9-]{10,}`)},
+	{ID: "stripe-secret-key", Description: "Stripe live secret key", Severity: "error",
+		re: regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}\b`)},
+	{ID: "google-api-key", Description: "Google API key", Severity: "error",
+		re: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+	{ID: "jwt", Description: "JSON Web Token", Severity: "warning",
+		re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{ID: "generic-secret", Description: "hard-coded secret assignment", Severity: "warning", minEntropy: 3.5,
+		re: regexp.MustCompile(`(?i)(?:api[_-]?key|secret|token|passw(?:or)?d|access[_-]?key)\w*["']?\s*[:=]\s*["']([^"'\s]{12,})["']`)},
+}
+
+// placeholderMarkers are substrings of values that are templates or examples
+// rather than real credentials.
+var placeholderMarkers = []string{"${", "{{", "<", "xxxx", "****", "changeme", "example", "placeholder", "dummy", "your_", "your-"}
+
+// Match is a detected credential. The secret itself is never retained.
+type Match struct {
+	Rule     *Rule
+	Line     int
+	Column   int
+	Redacted string
+}
+
+// Scan returns the credentials found in content. Binary content (containing
+// a NUL byte in the first 8 KiB) is skipped. Lines containing
+// "codeeagle:allow-secret" are ignored, for intentional test fixtures.
+func Scan(content []byte) []Match {
+	head := content
+	if len(head) > 8192 {
+		head = head[:8192]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	var matches []Match
+	seen := make(map[[2]int]bool) // line, column
+	for i := range Rules {
+		rule := &Rules[i]
+		for _, loc := range rule.re.FindAllSubmatchIndex(content, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			value := string(content[start:end])
+			if rule.minEntropy > 0 && (isPlaceholder(value) || entropy(value) < rule.minEntropy) {
+				continue
+			}
+
+			lineStart := bytes.LastIndexByte(content[:start], '\n') + 1
+			lineEnd := bytes.IndexByte(content[start:], '\n')
+			if lineEnd < 0 {
+				lineEnd = len(content)
+			} else {
+				lineEnd += start
+			}
+			if bytes.Contains(content[lineStart:lineEnd], []byte("codeeagle:allow-secret")) {
+				continue
+			}
+
+			pos := [2]int{bytes.Count(content[:start], []byte("\n")) + 1, start - lineStart + 1}
+			if seen[pos] {
+				continue // an earlier, more specific rule already reported it
+			}
+			seen[pos] = true
+			matches = append(matches, Match{Rule: rule, Line: pos[0], Column: pos[1], Redacted: Redact(value)})
+		}
+	}
+	return matches
+}
+
+// Redact keeps a short prefix of a secret for identification and masks the rest.
+func Redact(value string) string {
+	keep := 4
+	if len(value) <= 8 {
+		keep = 0
+	}
+	return value[:keep] + strings.Repeat("*", min(len(value)-keep, 8))
+}
+
+// Excluded reports whether the slash-separated path p matches any of the
+// globs. Globs without a slash match the base name; "**" matches any number
+// of directories.
+func Excluded(globs []string, p string) bool {
+	for _, g := range globs {
+		if !strings.Contains(g, "/") {
+			if ok, _ := path.Match(g, path.Base(p)); ok {
+				return true
+			}
+			continue
+		}
+		if matchParts(strings.Split(strings.Trim(g, "/"), "/"), strings.Split(p, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchParts(glob, parts []string) bool {
+	if len(glob) == 0 {
+		return len(parts) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchParts(glob[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(glob[0], parts[0]); !ok {
+		return false
+	}
+	return matchParts(glob[1:], parts[1:])
+}
+
+func isPlaceholder(value string) bool {
+	lower := strings.ToLower(value)
+	for _, m := range placeholderMarkers {
+		if strings.Contains(lower, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,79 @@
+package secrets
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test secrets are assembled at runtime so this file doesn't trip scanners.
+func fake(parts ...string) string { return strings.Join(parts, "") }
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // rule IDs in order
+	}{
+		{"aws key id", `key := "` + fake("AKIA", "IOSFODNN7QWERTY1") + `"`, []string{"aws-access-key-id"}},
+		{"aws secret", fake("aws_secret_access_key", " = ", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYzq9RtV8a2B"), []string{"aws-secret-access-key"}},
+		{"github token", fake("token: gh", "p_", strings.Repeat("a1B2", 9)), []string{"github-token"}},
+		{"private key", fake("-----BEGIN RSA ", "PRIVATE KEY-----\nMIIE...\n"), []string{"private-key"}},
+		{"stripe", fake("sk_", "live_", "4eC39HqLyjWDarjtT1zdp7dc"), []string{"stripe-secret-key"}},
+		{"generic high entropy", `DB_PASSWORD = "` + fake("Zx9#qL2!", "vR7$wK4p") + `"`, []string{"generic-secret"}},
+		{"generic placeholder", `api_key: "${API_KEY_FROM_VAULT}"`, nil},
+		{"generic low entropy", `password = "aaaaaaaaaaaaaaaa"`, nil},
+		{"allow comment", `key = "` + fake("AKIA", "IOSFODNN7QWERTY1") + `" // codeeagle:allow-secret`, nil},
+		{"binary", "\x00\x01" + fake("AKIA", "IOSFODNN7QWERTY1"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := Scan([]byte(tt.content))
+			var got []string
+			for _, m := range matches {
+				got = append(got, m.Rule.ID)
+				if strings.Contains(tt.content, m.Redacted) && !strings.HasSuffix(m.Redacted, "*") {
+					t.Errorf("redacted value %q leaks the secret", m.Redacted)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Scan rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanPosition(t *testing.T) {
+	content := "line one\n  token = \"" + fake("xox", "b-1234567890-abcdefghij") + "\"\n"
+	matches := Scan([]byte(content))
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	m := matches[0]
+	if m.Line != 2 || m.Column != 12 || m.Rule.ID != "slack-token" {
+		t.Errorf("match = %s at %d:%d, want slack-token at 2:12", m.Rule.ID, m.Line, m.Column)
+	}
+	if m.Redacted != "xoxb********" {
+		t.Errorf("Redacted = %q", m.Redacted)
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	globs := []string{"*.lock", "**/testdata/**", "config/dev/*.yaml"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"go.lock", true},
+		{"web/yarn.lock", true},
+		{"internal/secrets/testdata/keys.pem", true},
+		{"testdata/a.txt", true},
+		{"config/dev/app.yaml", true},
+		{"config/prod/app.yaml", false},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		if got := Excluded(globs, tt.path); got != tt.want {
+			t.Errorf("Excluded(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
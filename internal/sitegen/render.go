@@ -0,0 +1,264 @@
+package sitegen
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Format is a site output format.
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// Write renders the site into dir: an index page plus one page per service.
+// It returns the paths written.
+func Write(site *Site, dir string, format Format) ([]string, error) {
+	var (
+		ext    string
+		render func(*Site, *Service) ([]byte, error)
+		index  func(*Site) ([]byte, error)
+	)
+	switch format {
+	case FormatMarkdown:
+		ext, render, index = ".md", renderMarkdownPage, renderMarkdownIndex
+	case FormatHTML:
+		ext, render, index = ".html", renderHTMLPage, renderHTMLIndex
+	default:
+		return nil, fmt.Errorf("unknown site format %q (want markdown or html)", format)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create site dir: %w", err)
+	}
+	var written []string
+	write := func(name string, content []byte) error {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+		written = append(written, path)
+		return nil
+	}
+
+	content, err := index(site)
+	if err != nil {
+		return nil, err
+	}
+	if err := write("index"+ext, content); err != nil {
+		return nil, err
+	}
+	for _, svc := range site.Services {
+		content, err := render(site, svc)
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", svc.Name, err)
+		}
+		if err := write(PageName(svc.Name)+ext, content); err != nil {
+			return nil, err
+		}
+	}
+	return written, nil
+}
+
+var unsafePageChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// PageName returns the file name (without extension) of a service's page.
+func PageName(service string) string {
+	name := strings.Trim(unsafePageChars.ReplaceAllString(service, "-"), "-.")
+	switch name {
+	case "":
+		return "service"
+	case "index": // reserved for the site index
+		return "service-index"
+	}
+	return name
+}
+
+// firstParagraph returns the first paragraph of a description, for the index.
+func firstParagraph(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n\n"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// --- markdown ---
+
+// mdCell escapes text for a markdown table cell.
+func mdCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+func renderMarkdownIndex(site *Site) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", site.Title)
+	fmt.Fprintln(&b, "Generated by CodeEagle from the knowledge graph.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "| Service | Endpoints | Depends on | Description |")
+	fmt.Fprintln(&b, "|---|---|---|---|")
+	for _, svc := range site.Services {
+		fmt.Fprintf(&b, "| [%s](%s.md) | %d | %s | %s |\n", mdCell(svc.Name), PageName(svc.Name),
+			len(svc.Exposed), mdCell(strings.Join(svc.DependsOn, ", ")), mdCell(firstParagraph(svc.Description)))
+	}
+	return b.Bytes(), nil
+}
+
+func renderMarkdownPage(site *Site, svc *Service) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", svc.Name)
+	fmt.Fprintf(&b, "[← %s](index.md)\n\n", site.Title)
+	if svc.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(svc.Description))
+	}
+
+	if len(svc.Manifests) > 0 {
+		fmt.Fprintln(&b, "## Manifests")
+		fmt.Fprintln(&b)
+		for _, m := range svc.Manifests {
+			version := ""
+			if m.Version != "" {
+				version = " " + m.Version
+			}
+			fmt.Fprintf(&b, "- `%s`%s (%s) — `%s`\n", m.Name, version, m.Ecosystem, m.File)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	writeEndpoints := func(title, servicesHeader string, eps []Endpoint) {
+		if len(eps) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "## %s (%d)\n\n", title, len(eps))
+		fmt.Fprintf(&b, "| Endpoint | Handler | Location | %s |\n", servicesHeader)
+		fmt.Fprintln(&b, "|---|---|---|---|")
+		for _, ep := range eps {
+			var links []string
+			for _, s := range ep.Services {
+				links = append(links, fmt.Sprintf("[%s](%s.md)", mdCell(s), PageName(s)))
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | `%s:%d` | %s |\n", mdCell(ep.Name), mdCell(ep.Handler), ep.File, ep.Line, strings.Join(links, ", "))
+		}
+		fmt.Fprintln(&b)
+	}
+	writeEndpoints("Exposed endpoints", "Consumers", svc.Exposed)
+	writeEndpoints("Consumed endpoints", "Provider", svc.Consumed)
+
+	if len(svc.KeyTypes) > 0 {
+		fmt.Fprintln(&b, "## Key types")
+		fmt.Fprintln(&b)
+		for _, t := range svc.KeyTypes {
+			fmt.Fprintf(&b, "### %s\n\n", t.Name)
+			fmt.Fprintf(&b, "%s in `%s:%d`\n\n", t.Kind, t.File, t.Line)
+			if t.DocComment != "" {
+				fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(t.DocComment))
+			}
+		}
+	}
+
+	if len(svc.DependsOn)+len(svc.UsedBy)+len(svc.Packages) > 0 {
+		fmt.Fprintln(&b, "## Dependencies")
+		fmt.Fprintln(&b)
+		serviceList := func(label string, names []string) {
+			if len(names) == 0 {
+				return
+			}
+			var links []string
+			for _, s := range names {
+				links = append(links, fmt.Sprintf("[%s](%s.md)", s, PageName(s)))
+			}
+			fmt.Fprintf(&b, "**%s:** %s\n\n", label, strings.Join(links, ", "))
+		}
+		serviceList("Depends on", svc.DependsOn)
+		serviceList("Used by", svc.UsedBy)
+		if len(svc.Packages) > 0 {
+			fmt.Fprintln(&b, "| Package | Version | Ecosystem | Scope |")
+			fmt.Fprintln(&b, "|---|---|---|---|")
+			for _, p := range svc.Packages {
+				fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", mdCell(p.Name), mdCell(p.Version), p.Ecosystem, p.Scope)
+			}
+			fmt.Fprintln(&b)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// --- HTML ---
+
+const htmlStyle = `body{font-family:system-ui,sans-serif;max-width:60rem;margin:2rem auto;padding:0 1rem;color:#222}
+table{border-collapse:collapse;width:100%;margin:1rem 0}th,td{border:1px solid #ddd;padding:.3rem .5rem;text-align:left;vertical-align:top}
+th{background:#f5f5f5}code{background:#f5f5f5;padding:0 .2rem}pre{white-space:pre-wrap}.muted{color:#666}`
+
+var htmlFuncs = template.FuncMap{
+	"page":           func(s string) string { return PageName(s) + ".html" },
+	"firstParagraph": firstParagraph,
+	"join":           strings.Join,
+}
+
+var htmlIndexTmpl = template.Must(template.New("index").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}}</title><style>` + htmlStyle + `</style></head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="muted">Generated by CodeEagle from the knowledge graph.</p>
+<table>
+<tr><th>Service</th><th>Endpoints</th><th>Depends on</th><th>Description</th></tr>
+{{range .Services}}<tr><td><a href="{{page .Name}}">{{.Name}}</a></td><td>{{len .Exposed}}</td><td>{{join .DependsOn ", "}}</td><td>{{firstParagraph .Description}}</td></tr>
+{{end}}</table>
+</body></html>
+`))
+
+var htmlPageTmpl = template.Must(template.New("page").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Service.Name}} — {{.Site.Title}}</title><style>` + htmlStyle + `</style></head>
+<body>
+{{with .Service}}<p><a href="index.html">← {{$.Site.Title}}</a></p>
+<h1>{{.Name}}</h1>
+{{if .Description}}<pre>{{.Description}}</pre>{{end}}
+{{if .Manifests}}<h2>Manifests</h2>
+<ul>{{range .Manifests}}<li><code>{{.Name}}</code>{{if .Version}} {{.Version}}{{end}} ({{.Ecosystem}}) — <code>{{.File}}</code></li>{{end}}</ul>{{end}}
+{{if .Exposed}}<h2>Exposed endpoints ({{len .Exposed}})</h2>
+<table><tr><th>Endpoint</th><th>Handler</th><th>Location</th><th>Consumers</th></tr>
+{{range .Exposed}}<tr><td><code>{{.Name}}</code></td><td>{{.Handler}}</td><td><code>{{.File}}:{{.Line}}</code></td><td>{{range $i, $s := .Services}}{{if $i}}, {{end}}<a href="{{page $s}}">{{$s}}</a>{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Consumed}}<h2>Consumed endpoints ({{len .Consumed}})</h2>
+<table><tr><th>Endpoint</th><th>Handler</th><th>Location</th><th>Provider</th></tr>
+{{range .Consumed}}<tr><td><code>{{.Name}}</code></td><td>{{.Handler}}</td><td><code>{{.File}}:{{.Line}}</code></td><td>{{range $i, $s := .Services}}{{if $i}}, {{end}}<a href="{{page $s}}">{{$s}}</a>{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .KeyTypes}}<h2>Key types</h2>
+{{range .KeyTypes}}<h3>{{.Name}}</h3>
+<p class="muted">{{.Kind}} in <code>{{.File}}:{{.Line}}</code></p>
+{{if .DocComment}}<pre>{{.DocComment}}</pre>{{end}}
+{{end}}{{end}}
+{{if or .DependsOn .UsedBy .Packages}}<h2>Dependencies</h2>
+{{if .DependsOn}}<p><strong>Depends on:</strong> {{range $i, $s := .DependsOn}}{{if $i}}, {{end}}<a href="{{page $s}}">{{$s}}</a>{{end}}</p>{{end}}
+{{if .UsedBy}}<p><strong>Used by:</strong> {{range $i, $s := .UsedBy}}{{if $i}}, {{end}}<a href="{{page $s}}">{{$s}}</a>{{end}}</p>{{end}}
+{{if .Packages}}<table><tr><th>Package</th><th>Version</th><th>Ecosystem</th><th>Scope</th></tr>
+{{range .Packages}}<tr><td><code>{{.Name}}</code></td><td>{{.Version}}</td><td>{{.Ecosystem}}</td><td>{{.Scope}}</td></tr>
+{{end}}</table>{{end}}{{end}}{{end}}
+</body></html>
+`))
+
+func renderHTMLIndex(site *Site) ([]byte, error) {
+	var b bytes.Buffer
+	if err := htmlIndexTmpl.Execute(&b, site); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func renderHTMLPage(site *Site, svc *Service) ([]byte, error) {
+	var b bytes.Buffer
+	if err := htmlPageTmpl.Execute(&b, struct {
+		Site    *Site
+		Service *Service
+	}{site, svc}); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,367 @@
+// Package sitegen assembles per-service documentation pages from the
+// knowledge graph and renders them as a static markdown or HTML site.
+package sitegen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// Site is the generated documentation: one page per service.
+type Site struct {
+	Title    string
+	Services []*Service
+}
+
+// Service is the documentation page of one service (a service group: the
+// top-level directory its manifests and sources live in).
+type Service struct {
+	Name        string
+	Description string // LLM summary from indexing, if any
+	Manifests   []Manifest
+	Exposed     []Endpoint // endpoints this service serves
+	Consumed    []Endpoint // endpoints of other services it calls
+	KeyTypes    []Type
+	DependsOn   []string // other services
+	UsedBy      []string // services depending on this one
+	Packages    []Package
+}
+
+// Manifest is a build manifest declaring the service.
+type Manifest struct {
+	Name      string
+	Version   string
+	Ecosystem string
+	File      string
+}
+
+// Endpoint is an API endpoint. Services lists its consumers for exposed
+// endpoints and its provider for consumed ones.
+type Endpoint struct {
+	Name     string
+	Handler  string
+	File     string
+	Line     int
+	Services []string
+}
+
+// Type is a class, struct, or interface with its doc comment.
+type Type struct {
+	Name       string
+	Kind       string
+	File       string
+	Line       int
+	DocComment string
+	References int // incoming edges, used for ranking
+}
+
+// Package is a third-party manifest dependency.
+type Package struct {
+	Name      string
+	Version   string
+	Ecosystem string
+	Scope     string
+}
+
+// Options controls site generation.
+type Options struct {
+	Title string
+	// MaxTypes caps the key types listed per service (0 for the default of 15).
+	MaxTypes int
+	// Services restricts the site to these services; empty means all.
+	Services []string
+}
+
+const defaultMaxTypes = 15
+
+// Build assembles the site from the graph.
+func Build(ctx context.Context, store graph.Store, opts Options) (*Site, error) {
+	if opts.MaxTypes <= 0 {
+		opts.MaxTypes = defaultMaxTypes
+	}
+	if opts.Title == "" {
+		opts.Title = "Services"
+	}
+
+	b := &siteBuilder{ctx: ctx, store: store, byName: make(map[string]*Service)}
+	if err := b.services(); err != nil {
+		return nil, err
+	}
+	if err := b.descriptions(); err != nil {
+		return nil, err
+	}
+	if err := b.endpoints(); err != nil {
+		return nil, err
+	}
+	if err := b.dependencies(); err != nil {
+		return nil, err
+	}
+	if err := b.keyTypes(opts.MaxTypes); err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]bool)
+	for _, s := range opts.Services {
+		want[s] = true
+	}
+	site := &Site{Title: opts.Title}
+	for _, svc := range b.byName {
+		if len(want) == 0 || want[svc.Name] {
+			site.Services = append(site.Services, svc)
+		}
+	}
+	for name := range want {
+		if b.byName[name] == nil {
+			return nil, fmt.Errorf("service %q not found", name)
+		}
+	}
+	sort.Slice(site.Services, func(i, j int) bool { return site.Services[i].Name < site.Services[j].Name })
+	return site, nil
+}
+
+type siteBuilder struct {
+	ctx    context.Context
+	store  graph.Store
+	byName map[string]*Service
+	group  map[string]string // Service node ID -> service name
+}
+
+// service returns the page for a group, creating it.
+func (b *siteBuilder) service(name string) *Service {
+	svc := b.byName[name]
+	if svc == nil {
+		svc = &Service{Name: name}
+		b.byName[name] = svc
+	}
+	return svc
+}
+
+// services creates a page per service group from the Service nodes, and
+// lists their manifests and third-party dependencies.
+func (b *siteBuilder) services() error {
+	nodes, err := b.store.QueryNodes(b.ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return fmt.Errorf("query services: %w", err)
+	}
+	b.group = make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		name := n.Name
+		if n.FilePath != "" {
+			name = linker.ServiceGroup(n.FilePath)
+		}
+		b.group[n.ID] = name
+		svc := b.service(name)
+		if n.FilePath != "" {
+			svc.Manifests = append(svc.Manifests, Manifest{
+				Name:      n.Name,
+				Version:   n.Properties["version"],
+				Ecosystem: n.Properties["ecosystem"],
+				File:      n.FilePath,
+			})
+		}
+	}
+
+	deps, err := b.store.QueryNodes(b.ctx, graph.NodeFilter{
+		Type:       graph.NodeDependency,
+		Properties: map[string]string{"kind": "manifest_dep"},
+	})
+	if err != nil {
+		return fmt.Errorf("query dependencies: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, d := range deps {
+		if d.Properties["internal"] == "true" {
+			continue
+		}
+		name := linker.ServiceGroup(d.FilePath)
+		svc := b.byName[name]
+		if svc == nil || seen[name+"\x00"+d.Name] {
+			continue
+		}
+		seen[name+"\x00"+d.Name] = true
+		svc.Packages = append(svc.Packages, Package{
+			Name:      d.Name,
+			Version:   d.Properties["version"],
+			Ecosystem: d.Properties["ecosystem"],
+			Scope:     d.Properties["scope"],
+		})
+	}
+	for _, svc := range b.byName {
+		sort.Slice(svc.Manifests, func(i, j int) bool { return svc.Manifests[i].File < svc.Manifests[j].File })
+		sort.Slice(svc.Packages, func(i, j int) bool { return svc.Packages[i].Name < svc.Packages[j].Name })
+	}
+	return nil
+}
+
+// descriptions attaches the service summaries generated during indexing.
+func (b *siteBuilder) descriptions() error {
+	docs, err := b.store.QueryNodes(b.ctx, graph.NodeFilter{
+		Type:       graph.NodeDocument,
+		Properties: map[string]string{"kind": "summary"},
+	})
+	if err != nil {
+		return fmt.Errorf("query summaries: %w", err)
+	}
+	for _, d := range docs {
+		if svc := b.byName[d.Properties["service"]]; svc != nil {
+			svc.Description = d.DocComment
+		}
+	}
+	return nil
+}
+
+// endpoints lists each service's endpoints with their consumers, and the
+// endpoints of other services it consumes.
+func (b *siteBuilder) endpoints() error {
+	nodes, err := b.store.QueryNodes(b.ctx, graph.NodeFilter{Type: graph.NodeAPIEndpoint})
+	if err != nil {
+		return fmt.Errorf("query endpoints: %w", err)
+	}
+	for _, ep := range nodes {
+		owner := linker.ServiceGroup(ep.FilePath)
+		edges, err := b.store.GetEdges(b.ctx, ep.ID, graph.EdgeConsumes)
+		if err != nil {
+			return fmt.Errorf("get consumers of %s: %w", ep.Name, err)
+		}
+		consumers := make(map[string]bool)
+		for _, e := range edges {
+			if e.TargetID != ep.ID {
+				continue
+			}
+			call, err := b.store.GetNode(b.ctx, e.SourceID)
+			if err != nil {
+				continue
+			}
+			if consumer := linker.ServiceGroup(call.FilePath); consumer != owner {
+				consumers[consumer] = true
+			}
+		}
+
+		if svc := b.byName[owner]; svc != nil {
+			svc.Exposed = append(svc.Exposed, Endpoint{
+				Name: ep.Name, Handler: ep.Properties["handler"], File: ep.FilePath, Line: ep.Line,
+				Services: sortedKeys(consumers),
+			})
+		}
+		for consumer := range consumers {
+			if svc := b.byName[consumer]; svc != nil {
+				svc.Consumed = append(svc.Consumed, Endpoint{
+					Name: ep.Name, Handler: ep.Properties["handler"], File: ep.FilePath, Line: ep.Line,
+					Services: []string{owner},
+				})
+			}
+		}
+	}
+	for _, svc := range b.byName {
+		sortEndpoints(svc.Exposed)
+		sortEndpoints(svc.Consumed)
+	}
+	return nil
+}
+
+// dependencies lists service-to-service dependencies in both directions.
+func (b *siteBuilder) dependencies() error {
+	dependsOn := make(map[string]map[string]bool)
+	usedBy := make(map[string]map[string]bool)
+	add := func(m map[string]map[string]bool, k, v string) {
+		if m[k] == nil {
+			m[k] = make(map[string]bool)
+		}
+		m[k][v] = true
+	}
+	for id, from := range b.group {
+		edges, err := b.store.GetEdges(b.ctx, id, graph.EdgeDependsOn)
+		if err != nil {
+			return fmt.Errorf("get dependencies of %s: %w", from, err)
+		}
+		for _, e := range edges {
+			to, ok := b.group[e.TargetID]
+			if e.SourceID != id || !ok || to == from {
+				continue
+			}
+			add(dependsOn, from, to)
+			add(usedBy, to, from)
+		}
+	}
+	for name, svc := range b.byName {
+		svc.DependsOn = sortedKeys(dependsOn[name])
+		svc.UsedBy = sortedKeys(usedBy[name])
+	}
+	return nil
+}
+
+// keyTypes lists each service's most referenced exported types.
+func (b *siteBuilder) keyTypes(limit int) error {
+	for _, t := range []graph.NodeType{graph.NodeClass, graph.NodeStruct, graph.NodeInterface} {
+		nodes, err := b.store.QueryNodes(b.ctx, graph.NodeFilter{Type: t})
+		if err != nil {
+			return fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		for _, n := range nodes {
+			svc := b.byName[linker.ServiceGroup(n.FilePath)]
+			if svc == nil || !n.Exported {
+				continue
+			}
+			edges, err := b.store.GetEdges(b.ctx, n.ID, "")
+			if err != nil {
+				return fmt.Errorf("get edges of %s: %w", n.Name, err)
+			}
+			refs := 0
+			for _, e := range edges {
+				if e.TargetID == n.ID && e.Type != graph.EdgeContains {
+					refs++
+				}
+			}
+			svc.KeyTypes = append(svc.KeyTypes, Type{
+				Name: n.Name, Kind: string(n.Type), File: n.FilePath, Line: n.Line,
+				DocComment: n.DocComment, References: refs,
+			})
+		}
+	}
+	for _, svc := range b.byName {
+		types := svc.KeyTypes
+		// Documented types first, then by how often they are referenced.
+		sort.Slice(types, func(i, j int) bool {
+			if (types[i].DocComment != "") != (types[j].DocComment != "") {
+				return types[i].DocComment != ""
+			}
+			if types[i].References != types[j].References {
+				return types[i].References > types[j].References
+			}
+			return types[i].Name < types[j].Name
+		})
+		if len(types) > limit {
+			types = types[:limit]
+		}
+		svc.KeyTypes = types
+	}
+	return nil
+}
+
+func sortEndpoints(eps []Endpoint) {
+	sort.Slice(eps, func(i, j int) bool {
+		if eps[i].File != eps[j].File {
+			return eps[i].File < eps[j].File
+		}
+		if eps[i].Line != eps[j].Line {
+			return eps[i].Line < eps[j].Line
+		}
+		return eps[i].Name < eps[j].Name
+	})
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package sitegen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func newTestStore(t *testing.T) graph.Store {
+	t.Helper()
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	ctx := context.Background()
+	nodes := []*graph.Node{
+		{ID: "svc-web", Type: graph.NodeService, Name: "web", FilePath: "web/package.json", Properties: map[string]string{"ecosystem": "npm", "version": "1.0.0"}},
+		{ID: "svc-orders", Type: graph.NodeService, Name: "example.com/orders", FilePath: "orders/go.mod", Properties: map[string]string{"ecosystem": "go"}},
+		{ID: "sum-orders", Type: graph.NodeDocument, Name: "orders summary", DocComment: "Manages | orders.\n\nDetails here.", Properties: map[string]string{"kind": "summary", "service": "orders"}},
+		{ID: "dep-chi", Type: graph.NodeDependency, Name: "github.com/go-chi/chi", FilePath: "orders/go.mod", Properties: map[string]string{"kind": "manifest_dep", "version": "v5.0.0", "ecosystem": "go", "scope": "runtime"}},
+		{ID: "dep-internal", Type: graph.NodeDependency, Name: "example.com/shared", FilePath: "orders/go.mod", Properties: map[string]string{"kind": "manifest_dep", "internal": "true"}},
+		{ID: "ep-list", Type: graph.NodeAPIEndpoint, Name: "GET /orders", FilePath: "orders/api.go", Line: 10, Properties: map[string]string{"handler": "listOrders"}},
+		{ID: "call-list", Type: graph.NodeDependency, Name: "GET /orders", FilePath: "web/client.ts"},
+		{ID: "call-self", Type: graph.NodeDependency, Name: "GET /orders", FilePath: "orders/worker.go"},
+		{ID: "t-order", Type: graph.NodeStruct, Name: "Order", FilePath: "orders/model.go", Line: 5, Exported: true},
+		{ID: "t-svc", Type: graph.NodeStruct, Name: "OrderService", FilePath: "orders/service.go", Line: 8, Exported: true, DocComment: "OrderService handles orders."},
+		{ID: "t-private", Type: graph.NodeStruct, Name: "cache", FilePath: "orders/cache.go"},
+		{ID: "f-a", Type: graph.NodeFunction, Name: "a", FilePath: "orders/a.go"},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	edges := []*graph.Edge{
+		{ID: "d1", Type: graph.EdgeDependsOn, SourceID: "svc-web", TargetID: "svc-orders", Properties: map[string]string{"kind": "api_dependency"}},
+		{ID: "c1", Type: graph.EdgeConsumes, SourceID: "call-list", TargetID: "ep-list"},
+		{ID: "c2", Type: graph.EdgeConsumes, SourceID: "call-self", TargetID: "ep-list"},
+		{ID: "r1", Type: graph.EdgeCalls, SourceID: "f-a", TargetID: "t-order"},
+	}
+	for _, e := range edges {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestBuild(t *testing.T) {
+	store := newTestStore(t)
+	site, err := Build(context.Background(), store, Options{Title: "Shop"})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(site.Services) != 2 || site.Services[0].Name != "orders" || site.Services[1].Name != "web" {
+		t.Fatalf("services = %+v", site.Services)
+	}
+	orders, web := site.Services[0], site.Services[1]
+
+	if !strings.HasPrefix(orders.Description, "Manages") {
+		t.Errorf("description = %q", orders.Description)
+	}
+	if len(orders.Packages) != 1 || orders.Packages[0].Name != "github.com/go-chi/chi" {
+		t.Errorf("packages = %+v", orders.Packages)
+	}
+	if len(orders.Exposed) != 1 || !reflect.DeepEqual(orders.Exposed[0].Services, []string{"web"}) {
+		t.Errorf("exposed = %+v", orders.Exposed)
+	}
+	if len(web.Consumed) != 1 || !reflect.DeepEqual(web.Consumed[0].Services, []string{"orders"}) {
+		t.Errorf("consumed = %+v", web.Consumed)
+	}
+	if !reflect.DeepEqual(web.DependsOn, []string{"orders"}) || !reflect.DeepEqual(orders.UsedBy, []string{"web"}) {
+		t.Errorf("dependsOn = %v, usedBy = %v", web.DependsOn, orders.UsedBy)
+	}
+	var types []string
+	for _, typ := range orders.KeyTypes {
+		types = append(types, typ.Name)
+	}
+	if want := []string{"OrderService", "Order"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("key types = %v, want %v", types, want)
+	}
+
+	if _, err := Build(context.Background(), store, Options{Services: []string{"missing"}}); err == nil {
+		t.Error("expected error for unknown service")
+	}
+	only, err := Build(context.Background(), store, Options{Services: []string{"web"}, MaxTypes: 1})
+	if err != nil || len(only.Services) != 1 {
+		t.Fatalf("Build(web) = %+v, %v", only, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	site, err := Build(context.Background(), newTestStore(t), Options{Title: "Shop"})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	tests := []struct {
+		format Format
+		page   string
+		want   []string
+	}{
+		{FormatMarkdown, "orders.md", []string{"# orders", "Manages | orders.", "`GET /orders`", "[web](web.md)", "### OrderService", "github.com/go-chi/chi"}},
+		{FormatHTML, "orders.html", []string{"<h1>orders</h1>", `<a href="web.html">web</a>`, "<h3>OrderService</h3>", "Manages | orders."}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			dir := t.TempDir()
+			written, err := Write(site, dir, tt.format)
+			if err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if len(written) != 3 {
+				t.Errorf("wrote %d files, want 3", len(written))
+			}
+			data, err := os.ReadFile(filepath.Join(dir, tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(data), w) {
+					t.Errorf("%s missing %q:\n%s", tt.page, w, data)
+				}
+			}
+		})
+	}
+
+	if _, err := Write(site, t.TempDir(), "pdf"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestPageName(t *testing.T) {
+	tests := map[string]string{
+		"orders":        "orders",
+		"api/v2 gw":     "api-v2-gw",
+		"index":         "service-index",
+		"":              "service",
+		"..":            "service",
+		"billing.svc":   "billing.svc",
+		"@scope/web-ui": "scope-web-ui",
+	}
+	for in, want := range tests {
+		if got := PageName(in); got != want {
+			t.Errorf("PageName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package testresults
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+)
+
+// Property keys recorded on TestFunction nodes. The counters accumulate
+// across ingestions; the rest are derived from them.
+const (
+	PropRuns     = "test_runs"
+	PropPasses   = "test_passes"
+	PropFailures = "test_failures"
+	PropTotalMs  = "test_total_ms"
+	PropPassRate = "test_pass_rate"
+	PropAvgMs    = "test_avg_ms"
+	PropFlaky    = "test_flaky"
+)
+
+// Stats is the recorded run history of a test.
+type Stats struct {
+	Runs     int
+	Passes   int
+	Failures int
+	TotalMs  int64
+}
+
+// PassRate returns the fraction of runs that passed.
+func (s Stats) PassRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Passes) / float64(s.Runs)
+}
+
+// AvgDuration returns the mean run duration.
+func (s Stats) AvgDuration() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return time.Duration(s.TotalMs/int64(s.Runs)) * time.Millisecond
+}
+
+// Flaky reports whether the test has both passed and failed.
+func (s Stats) Flaky() bool {
+	return s.Passes > 0 && s.Failures > 0
+}
+
+// String formats the stats for display, e.g. "pass rate 95% over 20 runs, avg 120ms, flaky".
+func (s Stats) String() string {
+	out := fmt.Sprintf("pass rate %.0f%% over %d run(s), avg %s",
+		s.PassRate()*100, s.Runs, s.AvgDuration().Round(time.Millisecond))
+	if s.Flaky() {
+		out += ", flaky"
+	}
+	return out
+}
+
+// StatsFromNode reads the recorded history from a node. The second return
+// value is false when no runs have been recorded.
+func StatsFromNode(n *graph.Node) (Stats, bool) {
+	if n == nil || n.Properties == nil {
+		return Stats{}, false
+	}
+	runs, _ := strconv.Atoi(n.Properties[PropRuns])
+	if runs <= 0 {
+		return Stats{}, false
+	}
+	passes, _ := strconv.Atoi(n.Properties[PropPasses])
+	failures, _ := strconv.Atoi(n.Properties[PropFailures])
+	total, _ := strconv.ParseInt(n.Properties[PropTotalMs], 10, 64)
+	return Stats{Runs: runs, Passes: passes, Failures: failures, TotalMs: total}, true
+}
+
+// apply writes the stats and their derived values onto the node.
+func (s Stats) apply(n *graph.Node) {
+	if n.Properties == nil {
+		n.Properties = make(map[string]string)
+	}
+	n.Properties[PropRuns] = strconv.Itoa(s.Runs)
+	n.Properties[PropPasses] = strconv.Itoa(s.Passes)
+	n.Properties[PropFailures] = strconv.Itoa(s.Failures)
+	n.Properties[PropTotalMs] = strconv.FormatInt(s.TotalMs, 10)
+	n.Properties[PropPassRate] = strconv.FormatFloat(s.PassRate(), 'f', 3, 64)
+	n.Properties[PropAvgMs] = strconv.FormatInt(s.AvgDuration().Milliseconds(), 10)
+	if s.Flaky() {
+		n.Properties[PropFlaky] = "true"
+	} else {
+		delete(n.Properties, PropFlaky)
+	}
+}
+
+// SortForPlan orders test nodes for a test plan: tests with recorded history
+// first, by descending pass rate and then ascending average duration. Tests
+// without history keep their relative order at the end.
+func SortForPlan(tests []*graph.Node) {
+	sort.SliceStable(tests, func(i, j int) bool {
+		si, oki := StatsFromNode(tests[i])
+		sj, okj := StatsFromNode(tests[j])
+		if oki != okj {
+			return oki
+		}
+		if !oki {
+			return false
+		}
+		if pi, pj := si.PassRate(), sj.PassRate(); pi != pj {
+			return pi > pj
+		}
+		return si.AvgDuration() < sj.AvgDuration()
+	})
+}
+
+// Result summarizes an ingestion run.
+type Result struct {
+	// Runs is the number of pass/fail runs in the report (skips excluded).
+	Runs int `json:"runs"`
+	// Tests is the number of TestFunction nodes updated.
+	Tests int `json:"tests"`
+	// UnresolvedTests lists report tests that matched no TestFunction node.
+	UnresolvedTests []string `json:"unresolved_tests,omitempty"`
+}
+
+// Ingest adds the runs in rep to the history of the matching TestFunction
+// nodes. Skipped runs are ignored.
+func Ingest(ctx context.Context, store graph.Store, rep *Report) (*Result, error) {
+	nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeTestFunction})
+	if err != nil {
+		return nil, fmt.Errorf("query test function nodes: %w", err)
+	}
+	byName := make(map[string][]*graph.Node)
+	for _, n := range nodes {
+		byName[n.Name] = append(byName[n.Name], n)
+	}
+
+	res := &Result{}
+	updated := make(map[string]*graph.Node)
+	stats := make(map[string]Stats)
+	unresolved := make(map[string]bool)
+	for _, run := range rep.Runs {
+		if run.Status == StatusSkip {
+			continue
+		}
+		res.Runs++
+		matches := resolve(byName, run)
+		if len(matches) == 0 {
+			key := run.Name
+			if run.Suite != "" {
+				key = run.Suite + "." + run.Name
+			}
+			unresolved[key] = true
+			continue
+		}
+		for _, n := range matches {
+			s, ok := stats[n.ID]
+			if !ok {
+				s, _ = StatsFromNode(n)
+			}
+			s.Runs++
+			if run.Status == StatusPass {
+				s.Passes++
+			} else {
+				s.Failures++
+			}
+			s.TotalMs += run.Duration.Milliseconds()
+			stats[n.ID] = s
+			updated[n.ID] = n
+		}
+	}
+
+	ids := make([]string, 0, len(updated))
+	for id := range updated {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		n := updated[id]
+		stats[id].apply(n)
+		delete(n.Properties, graph.PropGraphSource)
+		if err := store.UpdateNode(ctx, n); err != nil {
+			// The node may come from a read-only branch; copy it to the write branch.
+			if err := store.AddNode(ctx, n); err != nil {
+				return nil, fmt.Errorf("update test %s: %w", n.Name, err)
+			}
+		}
+		res.Tests++
+	}
+
+	for name := range unresolved {
+		res.UnresolvedTests = append(res.UnresolvedTests, name)
+	}
+	sort.Strings(res.UnresolvedTests)
+	return res, nil
+}
+
+// resolve finds the TestFunction nodes for a run. The test name is tried as
+// is and with parameter and qualifier decorations removed (e.g. "testFoo()",
+// "test_bar[1]", "Suite.test"). When several nodes share the name, the suite
+// (Go package, JUnit classname) narrows them down by file path.
+func resolve(byName map[string][]*graph.Node, run Run) []*graph.Node {
+	name := run.Name
+	candidates := []string{name}
+	if i := strings.IndexAny(name, "[("); i > 0 {
+		name = name[:i]
+		candidates = append(candidates, name)
+	}
+	if i := strings.LastIndexAny(name, ".#:"); i >= 0 && i < len(name)-1 {
+		candidates = append(candidates, name[i+1:])
+	}
+
+	for _, c := range candidates {
+		nodes := byName[c]
+		if len(nodes) <= 1 || run.Suite == "" {
+			if len(nodes) > 0 {
+				return nodes
+			}
+			continue
+		}
+		best, bestScore := []*graph.Node(nil), 0
+		for _, n := range nodes {
+			score := suiteScore(run.Suite, n.FilePath)
+			switch {
+			case score > bestScore:
+				best, bestScore = []*graph.Node{n}, score
+			case score == bestScore && score > 0:
+				best = append(best, n)
+			}
+		}
+		if bestScore > 0 {
+			return best
+		}
+		return nodes
+	}
+	return nil
+}
+
+// suiteScore counts how many trailing segments of a suite name (split on
+// "." and "/") match the trailing segments of a file path, comparing either
+// the file's directory (Go packages) or its path without extension (classes
+// and Python modules).
+func suiteScore(suite, filePath string) int {
+	suiteParts := strings.FieldsFunc(suite, func(r rune) bool { return r == '.' || r == '/' })
+	trailing := func(parts []string) int {
+		n := 0
+		for i, j := len(suiteParts)-1, len(parts)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+			if !strings.EqualFold(suiteParts[i], parts[j]) {
+				break
+			}
+			n++
+		}
+		return n
+	}
+	noExt := strings.TrimSuffix(filePath, path.Ext(filePath))
+	return max(trailing(strings.Split(noExt, "/")), trailing(strings.Split(path.Dir(filePath), "/")))
+}
@@ -0,0 +1,219 @@
+// Package testresults parses test result reports (JUnit XML, go test -json)
+// and records per-test pass-rate and duration history on TestFunction nodes
+// so impact analysis can prefer fast, reliable tests.
+package testresults
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format identifies a test result report format.
+type Format string
+
+const (
+	FormatAuto   Format = "auto"
+	FormatJUnit  Format = "junit"
+	FormatGoTest Format = "gotest"
+)
+
+// Status is the outcome of a single test run.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Run is one execution of one test.
+type Run struct {
+	// Suite is the JUnit classname or Go package the test belongs to.
+	Suite    string
+	Name     string
+	Status   Status
+	Duration time.Duration
+}
+
+// Report is a parsed test result report.
+type Report struct {
+	Format Format
+	Runs   []Run
+}
+
+// ParseFile reads and parses a report file. With FormatAuto the format is
+// detected from the content.
+func ParseFile(path string, format Format) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open report: %w", err)
+	}
+	defer f.Close()
+	return Parse(f, format)
+}
+
+// Parse parses a report in the given format.
+func Parse(r io.Reader, format Format) (*Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read report: %w", err)
+	}
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(data)
+		if format == "" {
+			return nil, fmt.Errorf("unrecognized test report format")
+		}
+	}
+	var runs []Run
+	switch format {
+	case FormatJUnit:
+		runs, err = parseJUnit(data)
+	case FormatGoTest:
+		runs, err = parseGoTest(data)
+	default:
+		return nil, fmt.Errorf("unsupported test report format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Format: format, Runs: runs}, nil
+}
+
+// DetectFormat guesses the report format from its content, returning "" if
+// the content is not recognized.
+func DetectFormat(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		if bytes.Contains(trimmed, []byte("<testsuite")) || bytes.Contains(trimmed, []byte("<testcase")) {
+			return FormatJUnit
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		if bytes.Contains(trimmed, []byte(`"Action"`)) {
+			return FormatGoTest
+		}
+	}
+	return ""
+}
+
+// junitSuite matches both <testsuites> and <testsuite> elements; suites may
+// nest arbitrarily.
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string    `xml:"classname,attr"`
+	Name      string    `xml:"name,attr"`
+	Time      string    `xml:"time,attr"`
+	Failure   *struct{} `xml:"failure"`
+	Error     *struct{} `xml:"error"`
+	Skipped   *struct{} `xml:"skipped"`
+}
+
+func parseJUnit(data []byte) ([]Run, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse junit xml: %w", err)
+	}
+	var runs []Run
+	var walk func(s junitSuite)
+	walk = func(s junitSuite) {
+		for _, c := range s.Cases {
+			if c.Name == "" {
+				continue
+			}
+			suite := c.ClassName
+			if suite == "" {
+				suite = s.Name
+			}
+			status := StatusPass
+			switch {
+			case c.Failure != nil || c.Error != nil:
+				status = StatusFail
+			case c.Skipped != nil:
+				status = StatusSkip
+			}
+			runs = append(runs, Run{
+				Suite:    suite,
+				Name:     c.Name,
+				Status:   status,
+				Duration: parseSeconds(c.Time),
+			})
+		}
+		for _, child := range s.Suites {
+			walk(child)
+		}
+	}
+	walk(root)
+	return runs, nil
+}
+
+// parseSeconds parses a JUnit time attribute ("1.234", "1,234.5").
+func parseSeconds(s string) time.Duration {
+	secs, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs * float64(time.Second))
+}
+
+// goTestEvent is one line of `go test -json` output.
+type goTestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+}
+
+// parseGoTest parses `go test -json` output. Subtests are ignored; their
+// outcome is reflected in the parent test.
+func parseGoTest(data []byte) ([]Run, error) {
+	var runs []Run
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			return nil, fmt.Errorf("parse go test json: %w", err)
+		}
+		if ev.Test == "" || strings.Contains(ev.Test, "/") {
+			continue
+		}
+		var status Status
+		switch ev.Action {
+		case "pass":
+			status = StatusPass
+		case "fail":
+			status = StatusFail
+		case "skip":
+			status = StatusSkip
+		default:
+			continue
+		}
+		runs = append(runs, Run{
+			Suite:    ev.Package,
+			Name:     ev.Test,
+			Status:   status,
+			Duration: time.Duration(ev.Elapsed * float64(time.Second)),
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read go test json: %w", err)
+	}
+	return runs, nil
+}
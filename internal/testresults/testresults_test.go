@@ -0,0 +1,166 @@
+package testresults
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+const junitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="com.acme.UserServiceTest">
+    <testcase classname="com.acme.UserServiceTest" name="testCreate()" time="0.120"/>
+    <testcase classname="com.acme.UserServiceTest" name="testDelete" time="1.5">
+      <failure message="boom"/>
+    </testcase>
+    <testcase classname="com.acme.UserServiceTest" name="testIgnored" time="0">
+      <skipped/>
+    </testcase>
+  </testsuite>
+  <testsuite name="pytest">
+    <testcase classname="tests.test_api" name="test_list[page-2]" time="0.01"/>
+  </testsuite>
+</testsuites>
+`
+
+const goTestJSON = `{"Action":"run","Package":"github.com/acme/app/store","Test":"TestGet"}
+{"Action":"pass","Package":"github.com/acme/app/store","Test":"TestGet/sub","Elapsed":0.01}
+{"Action":"pass","Package":"github.com/acme/app/store","Test":"TestGet","Elapsed":0.25}
+{"Action":"fail","Package":"github.com/acme/app/cache","Test":"TestGet","Elapsed":2}
+{"Action":"pass","Package":"github.com/acme/app/store","Elapsed":0.3}
+`
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format Format
+		want   []Run
+	}{
+		{
+			name:   "junit",
+			input:  junitReport,
+			format: FormatJUnit,
+			want: []Run{
+				{Suite: "com.acme.UserServiceTest", Name: "testCreate()", Status: StatusPass, Duration: 120 * time.Millisecond},
+				{Suite: "com.acme.UserServiceTest", Name: "testDelete", Status: StatusFail, Duration: 1500 * time.Millisecond},
+				{Suite: "com.acme.UserServiceTest", Name: "testIgnored", Status: StatusSkip},
+				{Suite: "tests.test_api", Name: "test_list[page-2]", Status: StatusPass, Duration: 10 * time.Millisecond},
+			},
+		},
+		{
+			name:   "go test json",
+			input:  goTestJSON,
+			format: FormatGoTest,
+			want: []Run{
+				{Suite: "github.com/acme/app/store", Name: "TestGet", Status: StatusPass, Duration: 250 * time.Millisecond},
+				{Suite: "github.com/acme/app/cache", Name: "TestGet", Status: StatusFail, Duration: 2 * time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rep, err := Parse(strings.NewReader(tt.input), FormatAuto)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if rep.Format != tt.format {
+				t.Errorf("format = %s, want %s", rep.Format, tt.format)
+			}
+			if len(rep.Runs) != len(tt.want) {
+				t.Fatalf("runs = %+v, want %+v", rep.Runs, tt.want)
+			}
+			for i, want := range tt.want {
+				if rep.Runs[i] != want {
+					t.Errorf("run[%d] = %+v, want %+v", i, rep.Runs[i], want)
+				}
+			}
+		})
+	}
+
+	if _, err := Parse(strings.NewReader("not a report"), FormatAuto); err == nil {
+		t.Error("expected error for unrecognized report")
+	}
+}
+
+func TestIngest(t *testing.T) {
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+
+	nodes := []*graph.Node{
+		{ID: "store-get", Type: graph.NodeTestFunction, Name: "TestGet", FilePath: "store/store_test.go"},
+		{ID: "cache-get", Type: graph.NodeTestFunction, Name: "TestGet", FilePath: "cache/cache_test.go"},
+		{ID: "create", Type: graph.NodeTestFunction, Name: "testCreate", FilePath: "src/test/java/com/acme/UserServiceTest.java"},
+		{ID: "delete", Type: graph.NodeTestFunction, Name: "testDelete", FilePath: "src/test/java/com/acme/UserServiceTest.java"},
+		{ID: "list", Type: graph.NodeTestFunction, Name: "test_list", FilePath: "tests/test_api.py"},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+
+	for _, input := range []string{goTestJSON, goTestJSON, junitReport} {
+		rep, err := Parse(strings.NewReader(input), FormatAuto)
+		if err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		res, err := Ingest(ctx, store, rep)
+		if err != nil {
+			t.Fatalf("Ingest: %v", err)
+		}
+		if len(res.UnresolvedTests) > 0 {
+			t.Errorf("unresolved tests: %v", res.UnresolvedTests)
+		}
+	}
+
+	// Flip one store run to a failure to make it flaky.
+	flaky := `{"Action":"fail","Package":"github.com/acme/app/store","Test":"TestGet","Elapsed":0.25}`
+	rep, _ := Parse(strings.NewReader(flaky), FormatGoTest)
+	if _, err := Ingest(ctx, store, rep); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+
+	want := map[string]Stats{
+		"store-get": {Runs: 3, Passes: 2, Failures: 1, TotalMs: 750},
+		"cache-get": {Runs: 2, Passes: 0, Failures: 2, TotalMs: 4000},
+		"create":    {Runs: 1, Passes: 1, Failures: 0, TotalMs: 120},
+		"delete":    {Runs: 1, Passes: 0, Failures: 1, TotalMs: 1500},
+		"list":      {Runs: 1, Passes: 1, Failures: 0, TotalMs: 10},
+	}
+	var got []*graph.Node
+	for id, ws := range want {
+		n, err := store.GetNode(ctx, id)
+		if err != nil {
+			t.Fatalf("GetNode(%s): %v", id, err)
+		}
+		s, ok := StatsFromNode(n)
+		if !ok || s != ws {
+			t.Errorf("%s stats = %+v, want %+v", id, s, ws)
+		}
+		got = append(got, n)
+	}
+	if n, _ := store.GetNode(ctx, "store-get"); n.Properties[PropFlaky] != "true" || n.Properties[PropPassRate] != "0.667" {
+		t.Errorf("store-get properties = %v", n.Properties)
+	}
+
+	untested := &graph.Node{ID: "new", Type: graph.NodeTestFunction, Name: "TestNew"}
+	got = append(got, untested)
+	SortForPlan(got)
+	var order []string
+	for _, n := range got {
+		order = append(order, n.ID)
+	}
+	wantOrder := "list,create,store-get,delete,cache-get,new"
+	if strings.Join(order, ",") != wantOrder {
+		t.Errorf("plan order = %s, want %s", strings.Join(order, ","), wantOrder)
+	}
+}
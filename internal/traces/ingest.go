@@ -0,0 +1,542 @@
+package traces
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/linker"
+)
+
+// KindRuntimeDependency is the kind of service DependsOn edges created from
+// traces alone, with no statically inferred counterpart.
+const KindRuntimeDependency = "runtime_dependency"
+
+// Options tunes an ingestion.
+type Options struct {
+	// ServiceMap maps service.name values to Service node names, for
+	// services deployed under a different name than the graph knows.
+	ServiceMap map[string]string
+}
+
+// Dependency is a dependency of one service on another, named by the
+// Service node when the service is in the graph and as reported in the
+// traces otherwise.
+type Dependency struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Spans is the number of spans that observed the dependency.
+	Spans int `json:"spans,omitempty"`
+}
+
+// Result summarizes an ingestion and compares the observed service
+// dependencies with the statically inferred ones.
+type Result struct {
+	Spans  int `json:"spans"`
+	Traces int `json:"traces"`
+	// Calls and Consumes count the edges marked or created as observed.
+	Calls    int `json:"calls"`
+	Consumes int `json:"consumes"`
+	// Confirmed dependencies were both inferred and observed.
+	Confirmed []Dependency `json:"confirmed"`
+	// ObservedOnly dependencies were observed but not inferred statically:
+	// calls through dynamic URLs, service discovery or unindexed clients.
+	ObservedOnly []Dependency `json:"observed_only"`
+	// StaticOnly dependencies of traced services were inferred but never
+	// observed: dead code paths, or paths the traces did not exercise.
+	StaticOnly []Dependency `json:"static_only"`
+	// UnresolvedServices lists service.name values matching no Service node.
+	UnresolvedServices []string `json:"unresolved_services,omitempty"`
+}
+
+// resolvedSpan is what a span maps to in the graph.
+type resolvedSpan struct {
+	fn *graph.Node // Function or Method from the code.* attributes
+	ep *graph.Node // APIEndpoint served, for server spans
+}
+
+// observation is an edge seen in the traces.
+type observation struct {
+	typ      graph.EdgeType
+	src, tgt string
+	count    int
+}
+
+// ingester holds the graph lookups of one ingestion.
+type ingester struct {
+	store graph.Store
+	opts  Options
+
+	services    []*graph.Node
+	serviceByID map[string]*graph.Node
+	svcCache    map[string]*graph.Node
+	funcs       map[string][]*graph.Node
+	endpoints   map[string][]*graph.Node   // service ID -> exposed endpoints
+	files       map[string]map[string]bool // service ID -> contained file paths
+}
+
+// Ingest records the calls and endpoint requests observed in spans as
+// Calls and Consumes edges marked observed=true, and the service-to-service
+// dependencies as DependsOn edges, and compares those with the statically
+// inferred dependencies.
+//
+// Spans are mapped to functions and methods through the code.function,
+// code.namespace and code.filepath attributes, and server spans to the
+// endpoints their service exposes through http.route (or url.path) and the
+// request method. Calls edges link each function to the nearest mapped
+// ancestor span in the same service; Consumes edges link the nearest mapped
+// function in the calling service (or the calling Service) to the endpoint.
+// Existing edges are marked observed rather than duplicated.
+func Ingest(ctx context.Context, store graph.Store, spans []*Span, opts Options) (*Result, error) {
+	in := &ingester{
+		store:       store,
+		opts:        opts,
+		serviceByID: make(map[string]*graph.Node),
+		svcCache:    make(map[string]*graph.Node),
+		funcs:       make(map[string][]*graph.Node),
+		endpoints:   make(map[string][]*graph.Node),
+		files:       make(map[string]map[string]bool),
+	}
+	var err error
+	in.services, err = store.QueryNodes(ctx, graph.NodeFilter{Type: graph.NodeService})
+	if err != nil {
+		return nil, fmt.Errorf("query service nodes: %w", err)
+	}
+	for _, svc := range in.services {
+		in.serviceByID[svc.ID] = svc
+	}
+	for _, t := range []graph.NodeType{graph.NodeFunction, graph.NodeMethod} {
+		nodes, err := store.QueryNodes(ctx, graph.NodeFilter{Type: t})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", t, err)
+		}
+		for _, n := range nodes {
+			in.funcs[n.Name] = append(in.funcs[n.Name], n)
+		}
+	}
+
+	res := &Result{
+		Spans:        len(spans),
+		Confirmed:    []Dependency{},
+		ObservedOnly: []Dependency{},
+		StaticOnly:   []Dependency{},
+	}
+	byKey := make(map[string]*Span, len(spans))
+	traceIDs := make(map[string]bool)
+	for _, s := range spans {
+		byKey[s.TraceID+"/"+s.SpanID] = s
+		traceIDs[s.TraceID] = true
+	}
+	res.Traces = len(traceIDs)
+	parentOf := func(s *Span) *Span {
+		if s.ParentSpanID == "" {
+			return nil
+		}
+		return byKey[s.TraceID+"/"+s.ParentSpanID]
+	}
+
+	resolved := make(map[*Span]resolvedSpan, len(spans))
+	for _, s := range spans {
+		svc := in.service(s.Service)
+		r := resolvedSpan{}
+		if r.fn, err = in.function(ctx, s, svc); err != nil {
+			return nil, err
+		}
+		if r.ep, err = in.endpoint(ctx, s, svc); err != nil {
+			return nil, err
+		}
+		resolved[s] = r
+	}
+
+	// nearest returns the nearest ancestor of s in service that maps to a
+	// function (or, with endpoints, to an endpoint).
+	nearest := func(s *Span, service string, endpoints bool) *graph.Node {
+		for p, hops := s, 0; p != nil && p.Service == service && hops < len(spans); p, hops = parentOf(p), hops+1 {
+			if r := resolved[p]; r.fn != nil {
+				return r.fn
+			} else if endpoints && r.ep != nil {
+				return r.ep
+			}
+		}
+		return nil
+	}
+
+	observed := make(map[string]*observation)
+	observe := func(typ graph.EdgeType, src, tgt string) {
+		if src == tgt {
+			return
+		}
+		key := string(typ) + "|" + src + "|" + tgt
+		if o, ok := observed[key]; ok {
+			o.count++
+			return
+		}
+		observed[key] = &observation{typ: typ, src: src, tgt: tgt, count: 1}
+	}
+
+	type servicePair struct{ from, to string }
+	deps := make(map[servicePair]int)
+	crossServiceChild := make(map[*Span]bool)
+	for _, s := range spans {
+		if p := parentOf(s); p != nil && p.Service != s.Service {
+			crossServiceChild[p] = true
+		}
+	}
+	for _, s := range spans {
+		p := parentOf(s)
+		r := resolved[s]
+		if p != nil && p.Service != s.Service && s.Service != "" && p.Service != "" {
+			deps[servicePair{p.Service, s.Service}]++
+		} else if peer := s.Attr("peer.service"); peer != "" && (s.Kind == KindClient || s.Kind == KindProducer) && !crossServiceChild[s] {
+			deps[servicePair{s.Service, peer}]++
+		}
+
+		if r.fn != nil {
+			if r.ep != nil {
+				observe(graph.EdgeCalls, r.ep.ID, r.fn.ID)
+			} else if p != nil && p.Service == s.Service {
+				if caller := nearest(p, s.Service, true); caller != nil {
+					observe(graph.EdgeCalls, caller.ID, r.fn.ID)
+				}
+			}
+		}
+		if r.ep != nil && p != nil && p.Service != s.Service {
+			caller := nearest(p, p.Service, false)
+			if caller == nil {
+				caller = in.service(p.Service)
+			}
+			if caller != nil {
+				observe(graph.EdgeConsumes, caller.ID, r.ep.ID)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(observed))
+	for k := range observed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		o := observed[k]
+		if _, err := in.record(ctx, o, nil); err != nil {
+			return nil, err
+		}
+		if o.typ == graph.EdgeCalls {
+			res.Calls++
+		} else {
+			res.Consumes++
+		}
+	}
+
+	// Service dependencies, compared with the static ones.
+	type depKey struct{ from, to string }
+	merged := make(map[depKey]*Dependency)
+	static := make(map[depKey]bool)
+	unresolved := make(map[string]bool)
+	traced := make(map[string]*graph.Node)
+	for _, s := range spans {
+		if svc := in.service(s.Service); svc != nil {
+			traced[svc.ID] = svc
+		} else if s.Service != "" {
+			unresolved[s.Service] = true
+		}
+	}
+	for pair, count := range deps {
+		from, to := in.service(pair.from), in.service(pair.to)
+		fromName, toName := pair.from, pair.to
+		if from != nil {
+			fromName = from.Name
+		}
+		if to != nil {
+			toName = to.Name
+		} else {
+			unresolved[pair.to] = true
+		}
+		if from != nil && to != nil && from.ID == to.ID {
+			continue
+		}
+		key := depKey{fromName, toName}
+		if d, ok := merged[key]; ok {
+			d.Spans += count
+		} else {
+			merged[key] = &Dependency{From: fromName, To: toName, Spans: count}
+		}
+		if from != nil && to != nil {
+			wasStatic, err := in.record(ctx, &observation{typ: graph.EdgeDependsOn, src: from.ID, tgt: to.ID, count: count},
+				map[string]string{"kind": KindRuntimeDependency})
+			if err != nil {
+				return nil, err
+			}
+			static[key] = static[key] || wasStatic
+		}
+	}
+	for key, d := range merged {
+		if static[key] {
+			res.Confirmed = append(res.Confirmed, *d)
+		} else {
+			res.ObservedOnly = append(res.ObservedOnly, *d)
+		}
+	}
+	for _, svc := range traced {
+		edges, err := store.GetEdges(ctx, svc.ID, graph.EdgeDependsOn)
+		if err != nil {
+			return nil, fmt.Errorf("get dependencies of %s: %w", svc.Name, err)
+		}
+		for _, e := range edges {
+			to := in.serviceByID[e.TargetID]
+			if e.SourceID != svc.ID || to == nil || e.Properties["kind"] == KindRuntimeDependency {
+				continue
+			}
+			if _, seen := merged[depKey{svc.Name, to.Name}]; !seen {
+				res.StaticOnly = append(res.StaticOnly, Dependency{From: svc.Name, To: to.Name})
+			}
+		}
+	}
+	for name := range unresolved {
+		res.UnresolvedServices = append(res.UnresolvedServices, name)
+	}
+
+	for _, list := range [][]Dependency{res.Confirmed, res.ObservedOnly, res.StaticOnly} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].From != list[j].From {
+				return list[i].From < list[j].From
+			}
+			return list[i].To < list[j].To
+		})
+	}
+	sort.Strings(res.UnresolvedServices)
+	return res, nil
+}
+
+// record marks the edge of an observation as observed, adding the
+// observation's count, or creates it with props when the graph has no such
+// edge. It reports whether the edge existed and was not itself created from
+// traces.
+func (in *ingester) record(ctx context.Context, o *observation, props map[string]string) (bool, error) {
+	edges, err := in.store.GetEdges(ctx, o.src, o.typ)
+	if err != nil {
+		return false, fmt.Errorf("get %s edges: %w", o.typ, err)
+	}
+	var edge *graph.Edge
+	for _, e := range edges {
+		if e.SourceID == o.src && e.TargetID == o.tgt {
+			edge = e
+			break
+		}
+	}
+	existed := edge != nil
+	if edge == nil {
+		edge = &graph.Edge{
+			ID:       graph.NewEdgeID(o.typ, o.src, o.tgt),
+			Type:     o.typ,
+			SourceID: o.src,
+			TargetID: o.tgt,
+			Properties: map[string]string{
+				graph.PropConfidence:      graph.ConfidenceExact,
+				graph.PropConfidenceScore: "1.00",
+			},
+		}
+		for k, v := range props {
+			edge.Properties[k] = v
+		}
+	}
+	if edge.Properties == nil {
+		edge.Properties = make(map[string]string)
+	}
+	wasStatic := existed && edge.Properties["kind"] != KindRuntimeDependency
+	count, _ := strconv.Atoi(edge.Properties[graph.PropObservedCount])
+	edge.Properties[graph.PropObserved] = "true"
+	edge.Properties[graph.PropObservedCount] = strconv.Itoa(count + o.count)
+	delete(edge.Properties, graph.PropGraphSource)
+	if err := in.store.AddEdge(ctx, edge); err != nil {
+		return false, fmt.Errorf("record observed %s edge: %w", o.typ, err)
+	}
+	return wasStatic, nil
+}
+
+// service returns the Service node a service.name refers to: the one
+// named by Options.ServiceMap, else one with the same name, ignoring case
+// and punctuation, and then common suffixes (payments-service, payments-api).
+func (in *ingester) service(name string) *graph.Node {
+	if name == "" {
+		return nil
+	}
+	if svc, ok := in.svcCache[name]; ok {
+		return svc
+	}
+	var found *graph.Node
+	if mapped, ok := in.opts.ServiceMap[name]; ok {
+		for _, svc := range in.services {
+			if svc.Name == mapped {
+				found = svc
+				break
+			}
+		}
+	} else {
+		for _, norm := range []func(string) string{
+			func(s string) string { return s },
+			normalizeServiceName,
+			func(s string) string { return trimServiceSuffix(normalizeServiceName(s)) },
+		} {
+			for _, svc := range in.services {
+				if norm(svc.Name) == norm(name) {
+					found = svc
+					break
+				}
+			}
+			if found != nil {
+				break
+			}
+		}
+	}
+	in.svcCache[name] = found
+	return found
+}
+
+// normalizeServiceName lowercases a service name and drops everything but
+// letters and digits.
+func normalizeServiceName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// trimServiceSuffix drops a service, svc, server, api or app suffix from a
+// normalized service name.
+func trimServiceSuffix(name string) string {
+	for _, suffix := range []string{"service", "svc", "server", "api", "app"} {
+		if len(name) > len(suffix) && strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix)
+		}
+	}
+	return name
+}
+
+// function returns the function or method a span's code.* attributes name,
+// or nil when they name none or several.
+func (in *ingester) function(ctx context.Context, s *Span, svc *graph.Node) (*graph.Node, error) {
+	name := s.Attr("code.function.name", "code.function")
+	if name == "" {
+		return nil, nil
+	}
+	namespace := s.Attr("code.namespace")
+	if i := strings.LastIndexAny(name, ".:"); i >= 0 {
+		if namespace == "" {
+			namespace = strings.TrimRight(name[:i], ".:")
+		}
+		name = name[i+1:]
+	}
+	candidates := in.funcs[name]
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	if namespace != "" {
+		owner := namespace[strings.LastIndexAny(namespace, ".:/")+1:]
+		qualified := strings.ReplaceAll(namespace, "::", ".") + "." + name
+		candidates = narrow(candidates, func(n *graph.Node) bool {
+			return n.Properties["class"] == owner || n.Properties["receiver"] == owner || n.Package == owner ||
+				strings.HasSuffix(strings.ReplaceAll(n.QualifiedName, "::", "."), qualified)
+		})
+	}
+	if file := s.Attr("code.file.path", "code.filepath"); file != "" {
+		file = strings.ReplaceAll(file, "\\", "/")
+		candidates = narrow(candidates, func(n *graph.Node) bool {
+			return file == n.FilePath || strings.HasSuffix(file, "/"+n.FilePath)
+		})
+	}
+	if svc != nil && len(candidates) > 1 {
+		files, err := in.serviceFiles(ctx, svc)
+		if err != nil {
+			return nil, err
+		}
+		candidates = narrow(candidates, func(n *graph.Node) bool { return files[n.FilePath] })
+	}
+	if len(candidates) != 1 {
+		return nil, nil
+	}
+	return candidates[0], nil
+}
+
+// endpoint returns the endpoint of svc a server span handled, matched by
+// route (or path) and request method.
+func (in *ingester) endpoint(ctx context.Context, s *Span, svc *graph.Node) (*graph.Node, error) {
+	if svc == nil || s.Kind != KindServer {
+		return nil, nil
+	}
+	path := s.Attr("http.route", "url.path", "http.target")
+	if path == "" {
+		return nil, nil
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	endpoints, ok := in.endpoints[svc.ID]
+	if !ok {
+		edges, err := in.store.GetEdges(ctx, svc.ID, graph.EdgeExposes)
+		if err != nil {
+			return nil, fmt.Errorf("get endpoints of %s: %w", svc.Name, err)
+		}
+		for _, e := range edges {
+			if e.SourceID != svc.ID {
+				continue
+			}
+			if ep, err := in.store.GetNode(ctx, e.TargetID); err == nil && ep != nil {
+				endpoints = append(endpoints, ep)
+			}
+		}
+		in.endpoints[svc.ID] = endpoints
+	}
+	if method := s.Attr("http.request.method", "http.method"); method != "" {
+		endpoints = narrow(endpoints, func(n *graph.Node) bool {
+			return strings.EqualFold(n.Properties["http_method"], method)
+		})
+	}
+	return linker.NewEndpointIndex(endpoints).Match(&graph.Node{
+		Properties: map[string]string{"path": path},
+	}), nil
+}
+
+// serviceFiles returns the paths of the files a service contains.
+func (in *ingester) serviceFiles(ctx context.Context, svc *graph.Node) (map[string]bool, error) {
+	if files, ok := in.files[svc.ID]; ok {
+		return files, nil
+	}
+	edges, err := in.store.GetEdges(ctx, svc.ID, graph.EdgeContains)
+	if err != nil {
+		return nil, fmt.Errorf("get files of %s: %w", svc.Name, err)
+	}
+	files := make(map[string]bool)
+	for _, e := range edges {
+		if e.SourceID != svc.ID {
+			continue
+		}
+		if f, err := in.store.GetNode(ctx, e.TargetID); err == nil && f != nil {
+			files[f.FilePath] = true
+		}
+	}
+	in.files[svc.ID] = files
+	return files, nil
+}
+
+// narrow returns the nodes matching keep, or all nodes when none do.
+func narrow(nodes []*graph.Node, keep func(*graph.Node) bool) []*graph.Node {
+	var kept []*graph.Node
+	for _, n := range nodes {
+		if keep(n) {
+			kept = append(kept, n)
+		}
+	}
+	if len(kept) == 0 {
+		return nodes
+	}
+	return kept
+}
@@ -0,0 +1,179 @@
+// Package traces ingests OpenTelemetry trace exports (OTLP JSON), records
+// the calls and endpoint requests observed at runtime as edges marked
+// observed=true, and compares the service dependencies seen in traces with
+// those inferred statically.
+package traces
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SpanKind is the role of a span in a trace.
+type SpanKind string
+
+const (
+	KindUnspecified SpanKind = ""
+	KindInternal    SpanKind = "internal"
+	KindServer      SpanKind = "server"
+	KindClient      SpanKind = "client"
+	KindProducer    SpanKind = "producer"
+	KindConsumer    SpanKind = "consumer"
+)
+
+// Span is one span of a trace, flattened with its resource's service name.
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	// Service is the service.name resource attribute.
+	Service    string
+	Name       string
+	Kind       SpanKind
+	Attributes map[string]string
+}
+
+// Attr returns the first non-empty attribute among keys, so old and new
+// semantic convention names can be tried in turn.
+func (s *Span) Attr(keys ...string) string {
+	for _, k := range keys {
+		if v := s.Attributes[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseFile reads an OTLP JSON trace export: a single TracesData /
+// ExportTraceServiceRequest document, or one document per line as written
+// by the collector's file exporter.
+func ParseFile(path string) ([]*Span, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open trace export: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse parses an OTLP JSON trace export. Consecutive documents, one per
+// line or not, are all read.
+func Parse(r io.Reader) ([]*Span, error) {
+	var spans []*Span
+	dec := json.NewDecoder(r)
+	for {
+		var doc otlpTracesData
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("parse OTLP JSON: %w", err)
+		}
+		spans = append(spans, doc.spans()...)
+	}
+	return spans, nil
+}
+
+// otlpTracesData is the OTLP JSON encoding of TracesData (and of
+// ExportTraceServiceRequest, which has the same shape).
+type otlpTracesData struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []otlpKeyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+		// InstrumentationLibrarySpans is the pre-1.0 name of ScopeSpans.
+		InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"`
+	} `json:"resourceSpans"`
+}
+
+type otlpScopeSpans struct {
+	Spans []struct {
+		TraceID      string          `json:"traceId"`
+		SpanID       string          `json:"spanId"`
+		ParentSpanID string          `json:"parentSpanId"`
+		Name         string          `json:"name"`
+		Kind         json.RawMessage `json:"kind"`
+		Attributes   []otlpKeyValue  `json:"attributes"`
+	} `json:"spans"`
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string         `json:"stringValue"`
+		IntValue    json.RawMessage `json:"intValue"`
+		BoolValue   *bool           `json:"boolValue"`
+		DoubleValue *float64        `json:"doubleValue"`
+	} `json:"value"`
+}
+
+// value returns the attribute's scalar value as a string; arrays and maps
+// are ignored.
+func (kv otlpKeyValue) value() string {
+	v := kv.Value
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case len(v.IntValue) > 0:
+		// int64 values are JSON strings in OTLP, but numbers are accepted.
+		return strings.Trim(string(v.IntValue), `"`)
+	case v.BoolValue != nil:
+		return fmt.Sprint(*v.BoolValue)
+	case v.DoubleValue != nil:
+		return fmt.Sprint(*v.DoubleValue)
+	}
+	return ""
+}
+
+func attributes(kvs []otlpKeyValue) map[string]string {
+	attrs := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		if v := kv.value(); v != "" {
+			attrs[kv.Key] = v
+		}
+	}
+	return attrs
+}
+
+func (d *otlpTracesData) spans() []*Span {
+	var spans []*Span
+	for _, rs := range d.ResourceSpans {
+		service := attributes(rs.Resource.Attributes)["service.name"]
+		for _, ss := range append(rs.ScopeSpans, rs.InstrumentationLibrarySpans...) {
+			for _, s := range ss.Spans {
+				spans = append(spans, &Span{
+					TraceID:      s.TraceID,
+					SpanID:       s.SpanID,
+					ParentSpanID: s.ParentSpanID,
+					Service:      service,
+					Name:         s.Name,
+					Kind:         parseKind(s.Kind),
+					Attributes:   attributes(s.Attributes),
+				})
+			}
+		}
+	}
+	return spans
+}
+
+// parseKind decodes a span kind written as an enum number (2) or name
+// ("SPAN_KIND_SERVER").
+func parseKind(raw json.RawMessage) SpanKind {
+	k := strings.Trim(string(raw), `"`)
+	switch strings.TrimPrefix(strings.ToUpper(k), "SPAN_KIND_") {
+	case "1", "INTERNAL":
+		return KindInternal
+	case "2", "SERVER":
+		return KindServer
+	case "3", "CLIENT":
+		return KindClient
+	case "4", "PRODUCER":
+		return KindProducer
+	case "5", "CONSUMER":
+		return KindConsumer
+	}
+	return KindUnspecified
+}
@@ -0,0 +1,208 @@
+package traces
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/imyousuf/CodeEagle/internal/graph"
+	"github.com/imyousuf/CodeEagle/internal/graph/embedded"
+)
+
+func TestParse(t *testing.T) {
+	doc := `{"resourceSpans":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"orders"}}]},
+"scopeSpans":[{"spans":[
+  {"traceId":"t1","spanId":"a","name":"POST /orders","kind":2,"attributes":[
+    {"key":"http.route","value":{"stringValue":"/orders"}},
+    {"key":"http.response.status_code","value":{"intValue":"201"}}]},
+  {"traceId":"t1","spanId":"b","parentSpanId":"a","name":"reserve","kind":"SPAN_KIND_CLIENT","attributes":[
+    {"key":"retry","value":{"boolValue":true}}]}]}]}]}
+{"resourceSpans":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"billing"}}]},
+"instrumentationLibrarySpans":[{"spans":[{"traceId":"t1","spanId":"c","parentSpanId":"b","name":"charge","kind":1}]}]}]}
+`
+	spans, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	type got struct {
+		id, parent, service string
+		kind                SpanKind
+	}
+	var all []got
+	for _, s := range spans {
+		all = append(all, got{s.SpanID, s.ParentSpanID, s.Service, s.Kind})
+	}
+	want := []got{
+		{"a", "", "orders", KindServer},
+		{"b", "a", "orders", KindClient},
+		{"c", "b", "billing", KindInternal},
+	}
+	if !reflect.DeepEqual(all, want) {
+		t.Errorf("spans = %+v, want %+v", all, want)
+	}
+	if a := spans[0].Attributes; a["http.route"] != "/orders" || a["http.response.status_code"] != "201" {
+		t.Errorf("attributes = %v", a)
+	}
+	if spans[1].Attr("missing", "retry") != "true" {
+		t.Errorf("bool attribute = %q, want true", spans[1].Attributes["retry"])
+	}
+
+	if _, err := Parse(strings.NewReader(`{"resourceSpans": [`)); err == nil {
+		t.Error("expected an error for truncated JSON")
+	}
+}
+
+func TestIngest(t *testing.T) {
+	store, err := embedded.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	ctx := context.Background()
+
+	nodes := []*graph.Node{
+		{ID: "svc-web", Type: graph.NodeService, Name: "web"},
+		{ID: "svc-orders", Type: graph.NodeService, Name: "orders"},
+		{ID: "svc-billing", Type: graph.NodeService, Name: "billing"},
+		{ID: "svc-inventory", Type: graph.NodeService, Name: "inventory"},
+		{ID: "f-orders-api", Type: graph.NodeFile, Name: "api.go", FilePath: "orders/api.go"},
+		{ID: "f-orders-reserve", Type: graph.NodeFile, Name: "reserve.go", FilePath: "orders/reserve.go"},
+		{ID: "f-billing-reserve", Type: graph.NodeFile, Name: "reserve.go", FilePath: "billing/reserve.go"},
+		{ID: "ep-create", Type: graph.NodeAPIEndpoint, Name: "POST /orders", FilePath: "orders/api.go",
+			Properties: map[string]string{"http_method": "POST", "path": "/orders"}},
+		{ID: "ep-list", Type: graph.NodeAPIEndpoint, Name: "GET /orders", FilePath: "orders/api.go",
+			Properties: map[string]string{"http_method": "GET", "path": "/orders"}},
+		{ID: "submit", Type: graph.NodeFunction, Name: "submit", FilePath: "web/client.ts"},
+		{ID: "create", Type: graph.NodeFunction, Name: "CreateOrder", FilePath: "orders/api.go"},
+		{ID: "reserve", Type: graph.NodeFunction, Name: "Reserve", FilePath: "orders/reserve.go"},
+		{ID: "billing-reserve", Type: graph.NodeFunction, Name: "Reserve", FilePath: "billing/reserve.go"},
+	}
+	for _, n := range nodes {
+		if err := store.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	edge := func(typ graph.EdgeType, src, tgt string, props map[string]string) *graph.Edge {
+		return &graph.Edge{ID: graph.NewEdgeID(typ, src, tgt), Type: typ, SourceID: src, TargetID: tgt, Properties: props}
+	}
+	for _, e := range []*graph.Edge{
+		edge(graph.EdgeDependsOn, "svc-web", "svc-orders", map[string]string{"kind": "api_dependency"}),
+		edge(graph.EdgeDependsOn, "svc-orders", "svc-inventory", map[string]string{"kind": "api_dependency"}),
+		edge(graph.EdgeExposes, "svc-orders", "ep-create", nil),
+		edge(graph.EdgeExposes, "svc-orders", "ep-list", nil),
+		edge(graph.EdgeContains, "svc-orders", "f-orders-api", nil),
+		edge(graph.EdgeContains, "svc-orders", "f-orders-reserve", nil),
+		edge(graph.EdgeContains, "svc-billing", "f-billing-reserve", nil),
+		edge(graph.EdgeCalls, "create", "reserve", map[string]string{graph.PropConfidence: graph.ConfidenceExact}),
+	} {
+		if err := store.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+
+	span := func(id, parent, service string, kind SpanKind, attrs ...string) *Span {
+		s := &Span{TraceID: "t1", SpanID: id, ParentSpanID: parent, Service: service, Kind: kind, Attributes: map[string]string{}}
+		for i := 0; i+1 < len(attrs); i += 2 {
+			s.Attributes[attrs[i]] = attrs[i+1]
+		}
+		return s
+	}
+	spans := []*Span{
+		span("a", "", "web", KindInternal, "code.function", "submit"),
+		span("b", "a", "web", KindClient),
+		span("c", "b", "orders-service", KindServer,
+			"http.route", "/orders", "http.request.method", "POST", "code.function", "CreateOrder"),
+		span("d", "c", "orders-service", KindInternal, "code.function", "Reserve"),
+		span("e", "d", "orders-service", KindClient, "peer.service", "billing-api"),
+		span("f", "d", "orders-service", KindClient, "peer.service", "stripe"),
+	}
+
+	res, err := Ingest(ctx, store, spans, Options{})
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if res.Spans != 6 || res.Traces != 1 || res.Calls != 2 || res.Consumes != 1 {
+		t.Errorf("result = %+v, want 6 spans, 1 trace, 2 calls, 1 consumes", res)
+	}
+	if want := []Dependency{{"web", "orders", 1}}; !reflect.DeepEqual(res.Confirmed, want) {
+		t.Errorf("Confirmed = %+v, want %+v", res.Confirmed, want)
+	}
+	if want := []Dependency{{"orders", "billing", 1}, {"orders", "stripe", 1}}; !reflect.DeepEqual(res.ObservedOnly, want) {
+		t.Errorf("ObservedOnly = %+v, want %+v", res.ObservedOnly, want)
+	}
+	if want := []Dependency{{From: "orders", To: "inventory"}}; !reflect.DeepEqual(res.StaticOnly, want) {
+		t.Errorf("StaticOnly = %+v, want %+v", res.StaticOnly, want)
+	}
+	if want := []string{"stripe"}; !reflect.DeepEqual(res.UnresolvedServices, want) {
+		t.Errorf("UnresolvedServices = %v, want %v", res.UnresolvedServices, want)
+	}
+
+	observedEdge := func(typ graph.EdgeType, src, tgt string) *graph.Edge {
+		t.Helper()
+		edges, err := store.GetEdges(ctx, src, typ)
+		if err != nil {
+			t.Fatalf("GetEdges: %v", err)
+		}
+		for _, e := range edges {
+			if e.SourceID == src && e.TargetID == tgt {
+				if e.Properties[graph.PropObserved] != "true" {
+					t.Errorf("%s %s -> %s not marked observed: %v", typ, src, tgt, e.Properties)
+				}
+				return e
+			}
+		}
+		t.Errorf("no %s edge %s -> %s", typ, src, tgt)
+		return nil
+	}
+	observedEdge(graph.EdgeCalls, "ep-create", "create")
+	observedEdge(graph.EdgeConsumes, "submit", "ep-create")
+	if e := observedEdge(graph.EdgeDependsOn, "svc-orders", "svc-billing"); e != nil && e.Properties["kind"] != KindRuntimeDependency {
+		t.Errorf("new dependency kind = %q, want %q", e.Properties["kind"], KindRuntimeDependency)
+	}
+
+	// A second ingestion accumulates counts and still tells static from
+	// observed-only dependencies.
+	res, err = Ingest(ctx, store, spans, Options{})
+	if err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if len(res.Confirmed) != 1 || len(res.ObservedOnly) != 2 {
+		t.Errorf("second ingestion: confirmed %+v, observed only %+v", res.Confirmed, res.ObservedOnly)
+	}
+	if e := observedEdge(graph.EdgeCalls, "create", "reserve"); e != nil {
+		if e.Properties[graph.PropObservedCount] != "2" || e.Properties[graph.PropConfidence] != graph.ConfidenceExact {
+			t.Errorf("static call edge properties = %v, want observed_count 2 and its confidence kept", e.Properties)
+		}
+	}
+}
+
+func TestServiceResolution(t *testing.T) {
+	in := &ingester{
+		services: []*graph.Node{
+			{ID: "1", Name: "payments"},
+			{ID: "2", Name: "Order_Service"},
+			{ID: "3", Name: "checkout"},
+		},
+		svcCache: make(map[string]*graph.Node),
+		opts:     Options{ServiceMap: map[string]string{"checkout-prod-eu": "checkout"}},
+	}
+	tests := map[string]string{
+		"payments":         "1",
+		"Payments-API":     "1",
+		"payments-service": "1",
+		"order-service":    "2",
+		"orders":           "",
+		"checkout-prod-eu": "3",
+		"":                 "",
+	}
+	for name, want := range tests {
+		got := ""
+		if svc := in.service(name); svc != nil {
+			got = svc.ID
+		}
+		if got != want {
+			t.Errorf("service(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -5,7 +5,7 @@ import "time"
 // VectorIndexMeta stores metadata about the vector index.
 // Persisted in BadgerDB at key "vec:meta:<branch>".
 type VectorIndexMeta struct {
-	Provider   string    `json:"provider"`   // "ollama" or "vertex-ai"
+	Provider   string    `json:"provider"`   // embedding provider name, e.g. "ollama"
 	Model      string    `json:"model"`      // embedding model name
 	Dimensions int       `json:"dimensions"` // vector dimensionality
 	ChunkSize  int       `json:"chunk_size"` // chars per chunk
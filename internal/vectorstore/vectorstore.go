@@ -108,6 +108,9 @@ func (vs *VectorStore) Search(ctx context.Context, query string, topK int) ([]Se
 	if err != nil {
 		return nil, fmt.Errorf("embed query: %w", err)
 	}
+	if dims := vs.idx.Dims(); len(queryVec) != dims {
+		return nil, fmt.Errorf("query embedding has %d dimensions but the index has %d; rebuild the vector index", len(queryVec), dims)
+	}
 
 	neighbors := vs.idx.Search(queryVec, topK)
 
@@ -165,6 +168,15 @@ func (vs *VectorStore) IndexNode(ctx context.Context, node *graph.Node) error {
 		return fmt.Errorf("embed node %s: %w", node.ID, err)
 	}
 
+	// Vectors of a different size cannot share the HNSW graph.
+	if dims := vs.idx.Dims(); dims > 0 {
+		for _, vec := range embeddings {
+			if len(vec) != dims {
+				return fmt.Errorf("embed node %s: got %d dimensions, index has %d; rebuild the vector index", node.ID, len(vec), dims)
+			}
+		}
+	}
+
 	// Store each chunk.
 	for i, vec := range embeddings {
 		key := chunkKey(node.ID, i)
@@ -248,6 +260,9 @@ func (vs *VectorStore) Rebuild(ctx context.Context) error {
 	vs.meta.Provider = vs.embedder.Name()
 	vs.meta.Model = vs.embedder.ModelName()
 	vs.meta.Dimensions = vs.embedder.Dimensions()
+	if vs.meta.Dimensions == 0 {
+		vs.meta.Dimensions = vs.idx.Dims()
+	}
 	vs.meta.ChunkSize = vs.chunk.ChunkSize
 	vs.meta.Overlap = vs.chunk.Overlap
 	vs.meta.UpdatedAt = now
@@ -357,14 +372,20 @@ func (vs *VectorStore) Len() int {
 	return vs.idx.Len()
 }
 
-// NeedsReindex checks if the current index was built with a different provider/model.
+// NeedsReindex checks if the current index was built with a different
+// provider, model or vector size. Sizes are compared only when both are
+// known, since local providers learn theirs from the first embedding.
 func (vs *VectorStore) NeedsReindex() bool {
 	vs.mu.RLock()
 	defer vs.mu.RUnlock()
 	if vs.meta == nil {
 		return true
 	}
-	return vs.meta.Provider != vs.embedder.Name() || vs.meta.Model != vs.embedder.ModelName()
+	if vs.meta.Provider != vs.embedder.Name() || vs.meta.Model != vs.embedder.ModelName() {
+		return true
+	}
+	dims := vs.embedder.Dimensions()
+	return vs.meta.Dimensions > 0 && dims > 0 && vs.meta.Dimensions != dims
 }
 
 // --- internal helpers ---
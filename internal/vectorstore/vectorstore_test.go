@@ -96,11 +96,27 @@ func (s *mockGraphStore) QueryNodes(_ context.Context, filter graph.NodeFilter)
 	return results, nil
 }
 
+func (s *mockGraphStore) ScanNodes(ctx context.Context, filter graph.NodeFilter, fn func(*graph.Node) error) error {
+	nodes, _ := s.QueryNodes(ctx, filter)
+	for _, n := range nodes {
+		if err := fn(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *mockGraphStore) AddEdge(_ context.Context, _ *graph.Edge) error { return nil }
 func (s *mockGraphStore) DeleteEdge(_ context.Context, _ string) error   { return nil }
 func (s *mockGraphStore) GetEdges(_ context.Context, _ string, _ graph.EdgeType) ([]*graph.Edge, error) {
 	return nil, nil
 }
+func (s *mockGraphStore) GetIncomingEdges(_ context.Context, _ string, _ graph.EdgeType) ([]*graph.Edge, error) {
+	return nil, nil
+}
+func (s *mockGraphStore) QueryEdges(_ context.Context, _ graph.EdgeFilter) ([]*graph.Edge, error) {
+	return nil, nil
+}
 func (s *mockGraphStore) GetNeighbors(_ context.Context, _ string, _ graph.EdgeType, _ graph.Direction) ([]*graph.Node, error) {
 	return nil, nil
 }
@@ -303,13 +319,46 @@ func TestVectorStoreNeedsReindex(t *testing.T) {
 		t.Error("NeedsReindex should be false when provider/model match")
 	}
 
+	// Unknown dimensions in meta are not a mismatch.
+	vs.meta.Dimensions = 0
+	if vs.NeedsReindex() {
+		t.Error("NeedsReindex should be false when meta dimensions are unknown")
+	}
+
+	// Same model served at a different size.
+	vs.meta.Dimensions = 64
+	if !vs.NeedsReindex() {
+		t.Error("NeedsReindex should be true when dimensions differ")
+	}
+
 	// Change provider in meta.
+	vs.meta.Dimensions = 32
 	vs.meta.Provider = "other"
 	if !vs.NeedsReindex() {
 		t.Error("NeedsReindex should be true when provider differs")
 	}
 }
 
+func TestVectorStoreDimensionMismatch(t *testing.T) {
+	vs, _, embedder := setupTestVectorStore(t)
+	ctx := context.Background()
+
+	node := &graph.Node{ID: "n1", Type: graph.NodeFunction, Name: "HandleAuth", DocComment: "Handles user authentication"}
+	if err := vs.IndexNode(ctx, node); err != nil {
+		t.Fatalf("IndexNode: %v", err)
+	}
+
+	// The embedding server now serves a model with a different size.
+	embedder.dims = 16
+	if _, err := vs.Search(ctx, "authentication", 5); err == nil {
+		t.Error("Search should fail when the query size differs from the index")
+	}
+	other := &graph.Node{ID: "n2", Type: graph.NodeFunction, Name: "Login", DocComment: "Logs a user in"}
+	if err := vs.IndexNode(ctx, other); err == nil {
+		t.Error("IndexNode should fail when the vector size differs from the index")
+	}
+}
+
 func TestVectorStoreNotAvailable(t *testing.T) {
 	var vs *VectorStore
 	if vs.Available() {
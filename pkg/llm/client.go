@@ -35,6 +35,31 @@ func SupportsTools(c Client) bool {
 	return ok
 }
 
+// StreamingClient extends Client with streamed responses.
+// Providers that can deliver partial output should implement this interface.
+type StreamingClient interface {
+	Client
+	// ChatStream is like Chat but calls onDelta with each piece of text as
+	// it arrives. The returned Response holds the full content and usage.
+	ChatStream(ctx context.Context, systemPrompt string, messages []Message, onDelta func(string)) (*Response, error)
+}
+
+// ChatStream streams the response when c implements StreamingClient, and
+// otherwise calls Chat and passes the whole content to onDelta at once.
+func ChatStream(ctx context.Context, c Client, systemPrompt string, messages []Message, onDelta func(string)) (*Response, error) {
+	if sc, ok := c.(StreamingClient); ok {
+		return sc.ChatStream(ctx, systemPrompt, messages, onDelta)
+	}
+	resp, err := c.Chat(ctx, systemPrompt, messages)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Content != "" {
+		onDelta(resp.Content)
+	}
+	return resp, nil
+}
+
 // Config holds configuration for creating an LLM client.
 type Config struct {
 	// Provider specifies which LLM provider to use.
@@ -47,10 +72,13 @@ type Config struct {
 	BaseURL string
 	// Project is the GCP project ID (for Vertex AI).
 	Project string
-	// Location is the GCP region (for Vertex AI, e.g. "us-central1").
+	// Location is the GCP region (for Vertex AI, e.g. "us-central1") or the
+	// AWS region (for Bedrock, e.g. "us-east-1").
 	Location string
 	// CredentialsFile is the path to a GCP service account credentials JSON file (for Vertex AI).
 	CredentialsFile string
+	// APIVersion is the API version to request (for Azure OpenAI).
+	APIVersion string
 }
 
 // ProviderFactory is a function type for creating LLM clients.
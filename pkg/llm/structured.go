@@ -0,0 +1,256 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidOutput is returned by ChatJSON when the model's reply still does
+// not match the schema after all retries.
+var ErrInvalidOutput = errors.New("LLM response does not match the requested schema")
+
+// DefaultJSONRetries is how many times ChatJSON asks the model again after
+// an invalid reply.
+const DefaultJSONRetries = 2
+
+// Schema describes the JSON object a structured request must return.
+type Schema struct {
+	// Name identifies the output (e.g. "endpoint_matches"). It is used as
+	// the schema or tool name, so it should be a short identifier.
+	Name string
+	// Description explains what the output holds.
+	Description string
+	// Schema is the JSON Schema of the output. Its root must be an object.
+	Schema map[string]any
+}
+
+// StructuredClient extends Client with provider-native structured output.
+// Providers that can constrain replies to a JSON schema should implement
+// this interface.
+type StructuredClient interface {
+	Client
+	// ChatJSON sends messages and returns a response whose Content is a
+	// JSON document constrained to schema.
+	ChatJSON(ctx context.Context, systemPrompt string, messages []Message, schema Schema) (*Response, error)
+}
+
+// ChatJSON asks c for a JSON reply matching schema and returns the
+// response with Content set to the validated JSON. It uses the provider's
+// native structured output when c implements StructuredClient, a forced
+// tool call when c implements ToolCapableClient, and otherwise adds the
+// schema to the system prompt. Invalid replies are sent back to the model
+// with the validation error, up to retries times.
+//
+// When the reply is still invalid, the error wraps ErrInvalidOutput and the
+// returned Response holds the last reply and the usage of all attempts.
+func ChatJSON(ctx context.Context, c Client, systemPrompt string, messages []Message, schema Schema, retries int) (*Response, error) {
+	msgs := append([]Message(nil), messages...)
+	var usage TokenUsage
+	var last *Response
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		resp, err := chatJSONOnce(ctx, c, systemPrompt, msgs, schema)
+		if err != nil {
+			return nil, err
+		}
+		usage.InputTokens += resp.Usage.InputTokens
+		usage.OutputTokens += resp.Usage.OutputTokens
+		resp.Usage = usage
+		last = resp
+
+		content, err := normalizeJSON(resp.Content, schema.Schema)
+		if err == nil {
+			resp.Content = content
+			return resp, nil
+		}
+		lastErr = err
+		msgs = append(msgs,
+			Message{Role: RoleAssistant, Content: resp.Content},
+			Message{Role: RoleUser, Content: fmt.Sprintf(
+				"That response is invalid: %v. Reply again with only a JSON object matching the %s schema.", err, schema.Name)},
+		)
+	}
+	return last, fmt.Errorf("%w: %v", ErrInvalidOutput, lastErr)
+}
+
+// chatJSONOnce sends one structured request through the best mechanism c
+// supports. The returned Content is the raw JSON text.
+func chatJSONOnce(ctx context.Context, c Client, systemPrompt string, messages []Message, schema Schema) (*Response, error) {
+	if sc, ok := c.(StructuredClient); ok {
+		return sc.ChatJSON(ctx, systemPrompt, messages, schema)
+	}
+
+	if tc, ok := c.(ToolCapableClient); ok {
+		tool := Tool{Name: schema.Name, Description: schema.Description, Parameters: schema.Schema}
+		prompt := systemPrompt + fmt.Sprintf("\n\nReturn your answer by calling the %s tool exactly once.", schema.Name)
+		resp, err := tc.ChatWithTools(ctx, prompt, messages, []Tool{tool})
+		if err != nil {
+			return nil, err
+		}
+		for _, call := range resp.ToolCalls {
+			if call.Name == schema.Name {
+				data, err := json.Marshal(call.Arguments)
+				if err != nil {
+					return nil, fmt.Errorf("marshal %s arguments: %w", schema.Name, err)
+				}
+				resp.Content = string(data)
+				resp.ToolCalls = nil
+				return resp, nil
+			}
+		}
+		// No tool call: validate whatever text came back.
+		return resp, nil
+	}
+
+	schemaJSON, err := json.Marshal(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("marshal %s schema: %w", schema.Name, err)
+	}
+	prompt := systemPrompt + fmt.Sprintf(
+		"\n\nRespond with only a JSON object (no prose, no code fences) matching this JSON Schema:\n%s", schemaJSON)
+	return c.Chat(ctx, prompt, messages)
+}
+
+// normalizeJSON parses content as a JSON object, tolerating surrounding
+// code fences or prose, validates it against schema, and returns it
+// re-encoded compactly.
+func normalizeJSON(content string, schema map[string]any) (string, error) {
+	s := strings.TrimSpace(content)
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		start, end := strings.Index(s, "{"), strings.LastIndex(s, "}")
+		if start < 0 || end < start {
+			return "", fmt.Errorf("no JSON object found")
+		}
+		if err := json.Unmarshal([]byte(s[start:end+1]), &v); err != nil {
+			return "", fmt.Errorf("malformed JSON: %v", err)
+		}
+	}
+	if err := ValidateJSON(v, schema); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ValidateJSON checks a decoded JSON value against the subset of JSON
+// Schema used for structured output: type, properties, required, items
+// and enum.
+func ValidateJSON(v any, schema map[string]any) error {
+	return validateJSON(v, schema, "$")
+}
+
+func validateJSON(v any, schema map[string]any, path string) error {
+	if schema == nil {
+		return nil
+	}
+	if typ, ok := schema["type"].(string); ok && !jsonTypeMatches(v, typ) {
+		return fmt.Errorf("%s: expected %s", path, typ)
+	}
+	if enum := schemaEnum(schema["enum"]); enum != nil {
+		found := false
+		for _, e := range enum {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, v, enum)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, ok := val[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name, sub := range props {
+			subSchema, _ := sub.(map[string]any)
+			if pv, ok := val[name]; ok {
+				if err := validateJSON(pv, subSchema, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range val {
+			if err := validateJSON(item, items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// jsonTypeMatches reports whether v, as decoded by encoding/json, has the
+// JSON Schema type typ.
+func jsonTypeMatches(v any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+// schemaStrings returns a schema keyword's value as strings, whether it was
+// built in Go ([]string) or decoded from JSON ([]any).
+func schemaStrings(v any) []string {
+	switch s := v.(type) {
+	case []string:
+		return s
+	case []any:
+		out := make([]string, 0, len(s))
+		for _, e := range s {
+			if str, ok := e.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaEnum returns the enum keyword's values, converting a Go []string to
+// the []any form values decode to.
+func schemaEnum(v any) []any {
+	switch e := v.(type) {
+	case []any:
+		return e
+	case []string:
+		out := make([]any, len(e))
+		for i, s := range e {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
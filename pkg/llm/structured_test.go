@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testSchema = Schema{
+	Name: "answer",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"items": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":  map[string]any{"type": "string"},
+						"level": map[string]any{"type": "string", "enum": []string{"high", "low"}},
+					},
+					"required": []string{"name"},
+				},
+			},
+		},
+		"required": []string{"items"},
+	},
+}
+
+// scriptedClient answers Chat with its replies in order and records the
+// system prompts and messages it was sent.
+type scriptedClient struct {
+	replies  []string
+	calls    int
+	systems  []string
+	messages [][]Message
+}
+
+func (c *scriptedClient) Chat(_ context.Context, system string, msgs []Message) (*Response, error) {
+	c.systems = append(c.systems, system)
+	c.messages = append(c.messages, msgs)
+	reply := c.replies[c.calls]
+	c.calls++
+	return &Response{Content: reply, Usage: TokenUsage{InputTokens: 3, OutputTokens: 1}}, nil
+}
+
+func (c *scriptedClient) Model() string    { return "scripted" }
+func (c *scriptedClient) Provider() string { return "test" }
+func (c *scriptedClient) Close() error     { return nil }
+
+// toolClient answers ChatWithTools with a call to the first tool.
+type toolClient struct {
+	scriptedClient
+	args map[string]any
+}
+
+func (c *toolClient) ChatWithTools(_ context.Context, _ string, _ []Message, tools []Tool) (*Response, error) {
+	c.calls++
+	return &Response{ToolCalls: []ToolCall{{ID: "t1", Name: tools[0].Name, Arguments: c.args}}}, nil
+}
+
+// nativeClient answers ChatJSON with a fixed document.
+type nativeClient struct {
+	scriptedClient
+	doc string
+}
+
+func (c *nativeClient) ChatJSON(_ context.Context, _ string, _ []Message, _ Schema) (*Response, error) {
+	c.calls++
+	return &Response{Content: c.doc}, nil
+}
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"valid", map[string]any{"items": []any{map[string]any{"name": "a", "level": "high"}}}, ""},
+		{"not an object", []any{}, "$: expected object"},
+		{"missing required", map[string]any{}, `$: missing required property "items"`},
+		{"wrong item type", map[string]any{"items": []any{"a"}}, "$.items[0]: expected object"},
+		{"not in enum", map[string]any{"items": []any{map[string]any{"name": "a", "level": "mid"}}}, "$.items[0].level: mid is not one of [high low]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJSON(tt.value, testSchema.Schema)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChatJSONRetriesInvalidReplies(t *testing.T) {
+	c := &scriptedClient{replies: []string{
+		"Sure! Here you go: [not json",
+		"```json\n{\"items\": [{\"name\": \"a\"}]}\n```",
+	}}
+	resp, err := ChatJSON(context.Background(), c, "system", []Message{{Role: RoleUser, Content: "q"}}, testSchema, 2)
+	if err != nil {
+		t.Fatalf("ChatJSON: %v", err)
+	}
+	if resp.Content != `{"items":[{"name":"a"}]}` {
+		t.Errorf("content = %q", resp.Content)
+	}
+	if resp.Usage.InputTokens != 6 || resp.Usage.OutputTokens != 2 {
+		t.Errorf("usage = %+v, want both attempts counted", resp.Usage)
+	}
+	if !strings.Contains(c.systems[0], "JSON Schema") {
+		t.Error("expected the schema in the system prompt")
+	}
+	// The retry carries the bad reply and the validation error.
+	if got := c.messages[1]; len(got) != 3 || got[1].Content != c.replies[0] || !strings.Contains(got[2].Content, "invalid") {
+		t.Errorf("retry messages = %+v", got)
+	}
+}
+
+func TestChatJSONGivesUp(t *testing.T) {
+	c := &scriptedClient{replies: []string{`{"other": 1}`, `{"other": 2}`}}
+	resp, err := ChatJSON(context.Background(), c, "", nil, testSchema, 1)
+	if !errors.Is(err, ErrInvalidOutput) {
+		t.Fatalf("error = %v, want ErrInvalidOutput", err)
+	}
+	if resp == nil || resp.Usage.InputTokens != 6 {
+		t.Errorf("response = %+v, want the last reply with usage of both attempts", resp)
+	}
+}
+
+func TestChatJSONUsesToolsAndNativeOutput(t *testing.T) {
+	tc := &toolClient{args: map[string]any{"items": []any{map[string]any{"name": "t"}}}}
+	resp, err := ChatJSON(context.Background(), tc, "", nil, testSchema, 0)
+	if err != nil || resp.Content != `{"items":[{"name":"t"}]}` || len(resp.ToolCalls) != 0 {
+		t.Errorf("tool path: response = %+v, err = %v", resp, err)
+	}
+
+	nc := &nativeClient{doc: `{"items": []}`}
+	resp, err = ChatJSON(context.Background(), nc, "", nil, testSchema, 0)
+	if err != nil || resp.Content != `{"items":[]}` {
+		t.Errorf("native path: response = %+v, err = %v", resp, err)
+	}
+	if nc.calls != 1 {
+		t.Errorf("native client calls = %d, want 1", nc.calls)
+	}
+}